@@ -9,6 +9,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/api"
 	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/scraper"
 
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -21,6 +22,9 @@ func main() {
 	// stop any profiling at exit
 	defer pprof.StopCPUProfile()
 	blockForever()
+
+	// stop the browser used by scrapers, removing its temporary files
+	scraper.CloseBrowser()
 }
 
 func blockForever() {
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// idle session limiters are discarded after this duration
+const sessionLimiterExpiry = 1 * time.Hour
+
+type sessionLimiter struct {
+	limiter  *utils.RateLimiter
+	lastUsed time.Time
+}
+
+type bandwidthLimiter struct {
+	global   *utils.RateLimiter
+	sessions map[string]*sessionLimiter
+	mutex    sync.Mutex
+}
+
+var bandwidth = &bandwidthLimiter{
+	global:   utils.NewRateLimiter(0),
+	sessions: make(map[string]*sessionLimiter),
+}
+
+// sessionKey returns the key used to identify the session of the request.
+// Authenticated requests are keyed by user, otherwise by client address.
+func sessionKey(r *http.Request) string {
+	if userID, ok := r.Context().Value(ContextUser).(string); ok && userID != "" {
+		return "user:" + userID
+	}
+
+	return "addr:" + clientAddress(r)
+}
+
+// clientAddress returns the host of the client which made the request.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func (b *bandwidthLimiter) getSessionLimiter(key string, rate int64) *utils.RateLimiter {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+
+	// remove expired limiters
+	for k, s := range b.sessions {
+		if now.Sub(s.lastUsed) > sessionLimiterExpiry {
+			delete(b.sessions, k)
+		}
+	}
+
+	s := b.sessions[key]
+	if s == nil {
+		s = &sessionLimiter{
+			limiter: utils.NewRateLimiter(rate),
+		}
+		b.sessions[key] = s
+	}
+
+	s.lastUsed = now
+	s.limiter.SetRate(rate)
+
+	return s.limiter
+}
+
+// bandwidthLimitHandler limits the rate at which response bodies are
+// written, according to the configured per-session and global bandwidth
+// limits.
+func bandwidthLimitHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := config.GetInstance()
+		globalRate := c.GetMaxGlobalBandwidth()
+		sessionRate := c.GetMaxSessionBandwidth()
+
+		bandwidth.global.SetRate(globalRate)
+
+		if globalRate <= 0 && sessionRate <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var limiters []*utils.RateLimiter
+		if globalRate > 0 {
+			limiters = append(limiters, bandwidth.global)
+		}
+		if sessionRate > 0 {
+			limiters = append(limiters, bandwidth.getSessionLimiter(sessionKey(r), sessionRate))
+		}
+
+		tw := &throttledResponseWriter{
+			ResponseWriter: w,
+			writer:         utils.NewThrottledWriter(r.Context(), w, limiters...),
+		}
+
+		next.ServeHTTP(tw, r)
+	})
+}
+
+type throttledResponseWriter struct {
+	http.ResponseWriter
+	writer *utils.ThrottledWriter
+}
+
+func (w *throttledResponseWriter) Write(p []byte) (int, error) {
+	return w.writer.Write(p)
+}
+
+func (w *throttledResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack is required so that running streams can be closed.
+func (w *throttledResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+
+	return hj.Hijack()
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/stashapp/stash/pkg/manager/config"
+)
+
+// indexBaseHref is the start of the base element in the UI index page,
+// which is rewritten to point to the URL base path.
+const indexBaseHref = `<base href="/"`
+
+// withBasePath returns the provided absolute path prefixed with the
+// configured URL base path.
+func withBasePath(p string) string {
+	return config.GetInstance().GetURLBasePath() + p
+}
+
+// basePathHandler serves next under basePath, stripping the base path from
+// request paths so that routes match as if served from the root. Requests
+// for the root or the base path itself are redirected to the base path.
+func basePathHandler(basePath string, next http.Handler) http.Handler {
+	stripped := http.StripPrefix(basePath, next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" || r.URL.Path == basePath {
+			http.Redirect(w, r, basePath+"/", http.StatusFound)
+			return
+		}
+
+		stripped.ServeHTTP(w, r)
+	})
+}
+
+// setIndexBasePath points the base element of the UI index page to the
+// base path, so that the UI resolves its assets and routes under it.
+func setIndexBasePath(index []byte, basePath string) []byte {
+	if basePath == "" {
+		return index
+	}
+
+	return bytes.Replace(index, []byte(indexBaseHref), []byte(`<base href="`+basePath+`/"`), 1)
+}
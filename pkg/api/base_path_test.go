@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasePathHandler(t *testing.T) {
+	var gotPath string
+	h := basePathHandler("/stash", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		gotPath = ""
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := serve("/stash/graphql")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/graphql", gotPath)
+
+	w = serve("/stash/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/", gotPath)
+
+	for _, p := range []string{"/", "/stash"} {
+		w = serve(p)
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "/stash/", w.Header().Get("Location"))
+	}
+
+	w = serve("/graphql")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "", gotPath)
+}
+
+func TestSetIndexBasePath(t *testing.T) {
+	index := []byte(`<head><base href="/"/><link href="./static/main.css"></head>`)
+
+	assert.Equal(t, string(index), string(setIndexBasePath(index, "")))
+	assert.Equal(t, `<head><base href="/stash/"/><link href="./static/main.css"></head>`, string(setIndexBasePath(index, "/stash")))
+}
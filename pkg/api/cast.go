@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/chromecast"
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// castBaseURL returns the base URL through which the cast device at host
+// reaches the server. Devices cannot reach the server at a loopback address,
+// so it is replaced with the address of the interface on the network of the
+// device.
+func castBaseURL(baseURL string, host string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	hostname := u.Hostname()
+	if ip := net.ParseIP(hostname); hostname != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return baseURL, nil
+	}
+
+	localIP, err := chromecast.LocalIP(host)
+	if err != nil {
+		return "", err
+	}
+
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(localIP.String(), port)
+	} else {
+		u.Host = localIP.String()
+	}
+
+	return u.String(), nil
+}
+
+// withAPIKey returns u with the API key parameter added, since cast devices
+// do not have the session cookie of the user.
+func withAPIKey(u string, apiKey string) string {
+	if apiKey == "" {
+		return u
+	}
+
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+
+	return u + sep + ApiKeyParameter + "=" + url.QueryEscape(apiKey)
+}
+
+// getSceneCastMedia returns the media of the scene to load on cast devices,
+// with its captions as subtitle tracks.
+func getSceneCastMedia(scene *models.Scene, captions []*models.SceneCaption, baseURL string, apiKey string) chromecast.Media {
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, scene.ID)
+	streamURL, mimeType := manager.GetSceneCastStream(scene, builder.GetStreamURL())
+
+	ret := chromecast.Media{
+		URL:         withAPIKey(streamURL, apiKey),
+		ContentType: mimeType,
+		Title:       scene.GetTitle(),
+		ImageURL:    withAPIKey(builder.GetScreenshotURL(scene.UpdatedAt.Timestamp), apiKey),
+	}
+
+	builder.APIKey = apiKey
+	for _, c := range captions {
+		name := c.Title
+		if name == "" {
+			name = c.LanguageCode
+		}
+
+		ret.Tracks = append(ret.Tracks, chromecast.Track{
+			ID:       c.ID,
+			URL:      builder.GetCaptionURL(c.ID),
+			Name:     name,
+			Language: c.LanguageCode,
+		})
+	}
+
+	return ret
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCastBaseURL(t *testing.T) {
+	// the local address used to reach a loopback device is itself a
+	// loopback address
+	for _, baseURL := range []string{"http://localhost:9999/stash", "http://127.0.0.1:9999/stash"} {
+		got, err := castBaseURL(baseURL, "127.0.0.1")
+		assert.Nil(t, err)
+		assert.Equal(t, "http://127.0.0.1:9999/stash", got)
+	}
+
+	got, err := castBaseURL("https://stash.example.com", "127.0.0.1")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://stash.example.com", got)
+}
+
+func TestWithAPIKey(t *testing.T) {
+	assert.Equal(t, "/scene/1/stream", withAPIKey("/scene/1/stream", ""))
+	assert.Equal(t, "/scene/1/stream?apikey=a%2Bb", withAPIKey("/scene/1/stream", "a+b"))
+	assert.Equal(t, "/scene/1/screenshot?123&apikey=key", withAPIKey("/scene/1/screenshot?123", "key"))
+}
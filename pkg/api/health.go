@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stashapp/stash/pkg/manager"
+)
+
+const healthEndPoint = "/readyz"
+
+type healthResponse struct {
+	Status string                `json:"status"`
+	Checks []manager.HealthCheck `json:"checks"`
+}
+
+// healthHandler responds to health check requests with the result of each
+// check, before authentication, so that container orchestrators can use
+// it. The response status is 503 if any check fails.
+func healthHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method != http.MethodGet && r.Method != http.MethodHead) || r.URL.Path != healthEndPoint {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		checks, healthy := manager.GetInstance().CheckHealth()
+
+		resp := healthResponse{
+			Status: "ok",
+			Checks: checks,
+		}
+		status := http.StatusOK
+		if !healthy {
+			resp.Status = "error"
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(resp)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/manager/config"
+)
+
+// syncTokenParameter is the query parameter with the sync token of a scene,
+// which allows interactive devices to download the funscript of the scene
+// without the credentials of the user.
+const syncTokenParameter = "token"
+
+// syncTokenLifetime is how long sync tokens are valid after they are issued.
+const syncTokenLifetime = 12 * time.Hour
+
+var funscriptPathRE = regexp.MustCompile(`^/scene/(\d+)/funscript$`)
+
+func syncTokenSignature(key []byte, payload string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// makeSyncToken returns a token of the scene which is valid until expires,
+// signed with key.
+func makeSyncToken(key []byte, sceneID int, expires time.Time) string {
+	payload := strconv.Itoa(sceneID) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + syncTokenSignature(key, payload)
+}
+
+// validSyncToken returns true if token is a sync token of the scene signed
+// with key, which has not expired at now.
+func validSyncToken(key []byte, token string, sceneID int, now time.Time) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != strconv.Itoa(sceneID) {
+		return false
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+
+	signature := syncTokenSignature(key, parts[0]+"."+parts[1])
+	return hmac.Equal([]byte(parts[2]), []byte(signature))
+}
+
+// allowSyncToken returns true if the request is for the funscript of a
+// scene, with a valid sync token of that scene.
+func allowSyncToken(r *http.Request) bool {
+	m := funscriptPathRE.FindStringSubmatch(r.URL.Path)
+	token := r.URL.Query().Get(syncTokenParameter)
+	if m == nil || token == "" {
+		return false
+	}
+
+	sceneID, err := strconv.Atoi(m[1])
+	if err != nil {
+		return false
+	}
+
+	return validSyncToken(config.GetInstance().GetJWTSignKey(), token, sceneID, time.Now())
+}
+
+// interactiveSync is what interactive devices, such as the Handy or devices
+// connected to Intiface, need to play the funscript of a scene in sync with
+// its video.
+type interactiveSync struct {
+	SceneID int `json:"scene_id"`
+	// FunscriptURL can be downloaded without credentials until ExpiresAt,
+	// so that it can be passed on to device services
+	FunscriptURL string    `json:"funscript_url"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	// Offset is added to the times of the funscript actions, in milliseconds
+	Offset int `json:"offset"`
+	// ServerTime is the time of the server in milliseconds since the epoch,
+	// to estimate the difference between the clocks of the client and server
+	ServerTime int64 `json:"server_time"`
+}
+
+func makeInteractiveSync(sceneID int, baseURL string, key []byte, offset int, now time.Time) interactiveSync {
+	expires := now.Add(syncTokenLifetime)
+	token := makeSyncToken(key, sceneID, expires)
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, sceneID)
+
+	return interactiveSync{
+		SceneID:      sceneID,
+		FunscriptURL: builder.GetFunscriptURL() + "?" + syncTokenParameter + "=" + token,
+		Token:        token,
+		ExpiresAt:    expires.UTC(),
+		Offset:       offset,
+		ServerTime:   now.UnixNano() / int64(time.Millisecond),
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncToken(t *testing.T) {
+	key := []byte("key")
+	now := time.Unix(1600000000, 0)
+	token := makeSyncToken(key, 12, now.Add(time.Hour))
+
+	assert.True(t, validSyncToken(key, token, 12, now))
+	assert.True(t, validSyncToken(key, token, 12, now.Add(time.Hour)))
+
+	// expired
+	assert.False(t, validSyncToken(key, token, 12, now.Add(time.Hour+time.Second)))
+	// other scene
+	assert.False(t, validSyncToken(key, token, 1, now))
+	// other key
+	assert.False(t, validSyncToken([]byte("other"), token, 12, now))
+	// extended expiry
+	assert.False(t, validSyncToken(key, "12.1700000000"+token[len("12.1600003600"):], 12, now))
+	assert.False(t, validSyncToken(key, "", 12, now))
+}
+
+func TestAllowSyncToken(t *testing.T) {
+	token := makeSyncToken(nil, 3, time.Now().Add(time.Minute))
+
+	assert.True(t, allowSyncToken(httptest.NewRequest("GET", "/scene/3/funscript?token="+token, nil)))
+	assert.False(t, allowSyncToken(httptest.NewRequest("GET", "/scene/3/funscript", nil)))
+	assert.False(t, allowSyncToken(httptest.NewRequest("GET", "/scene/3/stream?token="+token, nil)))
+	assert.False(t, allowSyncToken(httptest.NewRequest("GET", "/scene/4/funscript?token="+token, nil)))
+}
+
+func TestMakeInteractiveSync(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	sync := makeInteractiveSync(3, "http://localhost:9999", []byte("key"), -50, now)
+
+	assert.Equal(t, "http://localhost:9999/scene/3/funscript?token="+sync.Token, sync.FunscriptURL)
+	assert.True(t, validSyncToken([]byte("key"), sync.Token, 3, now))
+	assert.Equal(t, now.Add(syncTokenLifetime).UTC(), sync.ExpiresAt)
+	assert.Equal(t, -50, sync.Offset)
+	assert.Equal(t, int64(1600000000000), sync.ServerTime)
+}
@@ -0,0 +1,233 @@
+//go:generate go run -mod=vendor github.com/vektah/dataloaden SceneLoader int *github.com/stashapp/stash/pkg/models.Scene
+//go:generate go run -mod=vendor github.com/vektah/dataloaden PerformerLoader int *github.com/stashapp/stash/pkg/models.Performer
+//go:generate go run -mod=vendor github.com/vektah/dataloaden StudioLoader int *github.com/stashapp/stash/pkg/models.Studio
+//go:generate go run -mod=vendor github.com/vektah/dataloaden TagLoader int *github.com/stashapp/stash/pkg/models.Tag
+//go:generate go run -mod=vendor github.com/vektah/dataloaden IDsLoader int []int
+
+// Package loaders provides dataloaders, which batch and cache the objects
+// looked up while resolving a GraphQL request, so that listing objects
+// does not query the database once per related object.
+package loaders
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type contextKey struct{ name string }
+
+var loadersCtxKey = &contextKey{"loaders"}
+
+const (
+	// how long to wait for further keys before fetching a batch
+	wait = 1 * time.Millisecond
+
+	// maximum number of keys fetched in one batch
+	maxBatch = 1000
+)
+
+// Loaders are the dataloaders for a single request. Loaded objects are
+// cached for the lifetime of the request.
+type Loaders struct {
+	SceneByID     *SceneLoader
+	PerformerByID *PerformerLoader
+	StudioByID    *StudioLoader
+	TagByID       *TagLoader
+
+	ScenePerformerIDs *IDsLoader
+	SceneTagIDs       *IDsLoader
+}
+
+// Middleware adds a new set of Loaders to the context of each request.
+type Middleware struct {
+	TxnManager models.TransactionManager
+}
+
+func (m Middleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ldrs := m.newLoaders(ctx)
+
+		ctx = context.WithValue(ctx, loadersCtxKey, ldrs)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (m Middleware) newLoaders(ctx context.Context) Loaders {
+	return Loaders{
+		SceneByID: NewSceneLoader(SceneLoaderConfig{
+			Wait:     wait,
+			MaxBatch: maxBatch,
+			Fetch:    m.fetchScenes(ctx),
+		}),
+		PerformerByID: NewPerformerLoader(PerformerLoaderConfig{
+			Wait:     wait,
+			MaxBatch: maxBatch,
+			Fetch:    m.fetchPerformers(ctx),
+		}),
+		StudioByID: NewStudioLoader(StudioLoaderConfig{
+			Wait:     wait,
+			MaxBatch: maxBatch,
+			Fetch:    m.fetchStudios(ctx),
+		}),
+		TagByID: NewTagLoader(TagLoaderConfig{
+			Wait:     wait,
+			MaxBatch: maxBatch,
+			Fetch:    m.fetchTags(ctx),
+		}),
+		ScenePerformerIDs: NewIDsLoader(IDsLoaderConfig{
+			Wait:     wait,
+			MaxBatch: maxBatch,
+			Fetch:    m.fetchScenePerformerIDs(ctx),
+		}),
+		SceneTagIDs: NewIDsLoader(IDsLoaderConfig{
+			Wait:     wait,
+			MaxBatch: maxBatch,
+			Fetch:    m.fetchSceneTagIDs(ctx),
+		}),
+	}
+}
+
+// From returns the Loaders of the request. Middleware must have been
+// applied to the request.
+func From(ctx context.Context) Loaders {
+	return ctx.Value(loadersCtxKey).(Loaders)
+}
+
+// toErrorSlice returns the errors for a fetch. The loaders apply a single
+// error to all keys.
+func toErrorSlice(err error) []error {
+	if err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
+
+// findEach calls find with the index of each key, returning the error of
+// each key, or nil if there are no errors. It is used when FindMany fails,
+// since FindMany fails for every key if any object is missing, while find
+// returns nil for a missing object.
+func findEach(keys []int, find func(i int) error) []error {
+	errs := make([]error, len(keys))
+	failed := false
+	for i := range keys {
+		if errs[i] = find(i); errs[i] != nil {
+			failed = true
+		}
+	}
+
+	if !failed {
+		return nil
+	}
+	return errs
+}
+
+func (m Middleware) fetchScenes(ctx context.Context) func(keys []int) ([]*models.Scene, []error) {
+	return func(keys []int) (ret []*models.Scene, errs []error) {
+		err := m.TxnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			ret, err = repo.Scene().FindMany(keys)
+			if err != nil {
+				ret = make([]*models.Scene, len(keys))
+				errs = findEach(keys, func(i int) (err error) {
+					ret[i], err = repo.Scene().Find(keys[i])
+					return
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, toErrorSlice(err)
+		}
+		return ret, errs
+	}
+}
+
+func (m Middleware) fetchPerformers(ctx context.Context) func(keys []int) ([]*models.Performer, []error) {
+	return func(keys []int) (ret []*models.Performer, errs []error) {
+		err := m.TxnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			ret, err = repo.Performer().FindMany(keys)
+			if err != nil {
+				ret = make([]*models.Performer, len(keys))
+				errs = findEach(keys, func(i int) (err error) {
+					ret[i], err = repo.Performer().Find(keys[i])
+					return
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, toErrorSlice(err)
+		}
+		return ret, errs
+	}
+}
+
+func (m Middleware) fetchStudios(ctx context.Context) func(keys []int) ([]*models.Studio, []error) {
+	return func(keys []int) (ret []*models.Studio, errs []error) {
+		err := m.TxnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			ret, err = repo.Studio().FindMany(keys)
+			if err != nil {
+				ret = make([]*models.Studio, len(keys))
+				errs = findEach(keys, func(i int) (err error) {
+					ret[i], err = repo.Studio().Find(keys[i])
+					return
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, toErrorSlice(err)
+		}
+		return ret, errs
+	}
+}
+
+func (m Middleware) fetchTags(ctx context.Context) func(keys []int) ([]*models.Tag, []error) {
+	return func(keys []int) (ret []*models.Tag, errs []error) {
+		err := m.TxnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			ret, err = repo.Tag().FindMany(keys)
+			if err != nil {
+				ret = make([]*models.Tag, len(keys))
+				errs = findEach(keys, func(i int) (err error) {
+					ret[i], err = repo.Tag().Find(keys[i])
+					return
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, toErrorSlice(err)
+		}
+		return ret, errs
+	}
+}
+
+func (m Middleware) fetchScenePerformerIDs(ctx context.Context) func(keys []int) ([][]int, []error) {
+	return func(keys []int) (ret [][]int, errs []error) {
+		err := m.TxnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			ret, err = repo.Scene().GetManyPerformerIDs(keys)
+			return err
+		})
+		return ret, toErrorSlice(err)
+	}
+}
+
+func (m Middleware) fetchSceneTagIDs(ctx context.Context) func(keys []int) ([][]int, []error) {
+	return func(keys []int) (ret [][]int, errs []error) {
+		err := m.TxnManager.WithReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			ret, err = repo.Scene().GetManyTagIDs(keys)
+			return err
+		})
+		return ret, toErrorSlice(err)
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// usesAPIKey returns true if the request was authenticated using the API
+// key.
+func usesAPIKey(ctx context.Context) bool {
+	v, _ := ctx.Value(apiAuthKey).(bool)
+	return v
+}
+
+// queryLimit returns the limit which applies to the request, using the API
+// key limit if set and the request was authenticated using the API key.
+func queryLimit(ctx context.Context, general int, apiKey *int) int {
+	if apiKey != nil && usesAPIKey(ctx) {
+		return *apiKey
+	}
+
+	return general
+}
+
+func queryComplexityLimit(ctx context.Context) int {
+	c := config.GetInstance()
+	limit := queryLimit(ctx, c.GetMaxQueryComplexity(), c.GetAPIKeyMaxQueryComplexity())
+
+	// the complexity extension has no way to disable the limit
+	if limit == 0 {
+		return math.MaxInt32
+	}
+
+	return limit
+}
+
+// selectionDepth returns the depth of the deepest field in the selection
+// set. Fragments do not add to the depth. Validation ensures that
+// fragments do not form cycles.
+func selectionDepth(set ast.SelectionSet) int {
+	ret := 0
+	for _, s := range set {
+		var depth int
+		switch s := s.(type) {
+		case *ast.Field:
+			depth = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+
+		if depth > ret {
+			ret = depth
+		}
+	}
+
+	return ret
+}
+
+// queryDepthMiddleware rejects root fields with selections deeper than the
+// configured limit, before they are resolved.
+func queryDepthMiddleware(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Parent != nil {
+		return next(ctx)
+	}
+
+	c := config.GetInstance()
+	limit := queryLimit(ctx, c.GetMaxQueryDepth(), c.GetAPIKeyMaxQueryDepth())
+	if limit == 0 {
+		return next(ctx)
+	}
+
+	depth := 1 + selectionDepth(fc.Field.SelectionSet)
+	if depth > limit {
+		return nil, fmt.Errorf("%s has depth %d, which exceeds the limit of %d", fc.Field.Name, depth, limit)
+	}
+
+	return next(ctx)
+}
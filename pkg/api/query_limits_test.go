@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const nestedQuery = `
+query {
+  findScenes {
+    scenes {
+      ...SceneData
+      performers {
+        scenes {
+          performers {
+            name
+          }
+        }
+      }
+    }
+  }
+}
+
+fragment SceneData on Scene {
+  id
+  ... on Scene {
+    studio {
+      parent_studio {
+        name
+      }
+    }
+  }
+}
+`
+
+func TestSelectionDepth(t *testing.T) {
+	es := models.NewExecutableSchema(models.Config{Resolvers: &Resolver{}})
+	doc, err := gqlparser.LoadQuery(es.Schema(), nestedQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// findScenes.scenes.performers.scenes.performers.name
+	assert.Equal(t, 6, selectionDepth(doc.Operations[0].SelectionSet))
+}
+
+func TestQueryLimit(t *testing.T) {
+	apiKeyLimit := 10
+	apiKeyCtx := context.WithValue(context.Background(), apiAuthKey, true)
+
+	assert.Equal(t, 5, queryLimit(context.Background(), 5, nil))
+	assert.Equal(t, 5, queryLimit(context.Background(), 5, &apiKeyLimit))
+	assert.Equal(t, 5, queryLimit(apiKeyCtx, 5, nil))
+	assert.Equal(t, apiKeyLimit, queryLimit(apiKeyCtx, 5, &apiKeyLimit))
+}
+
+// TestDocumentsWithinDefaultLimits ensures that the default limits do not
+// reject the operations used by the UI.
+func TestDocumentsWithinDefaultLimits(t *testing.T) {
+	var sources []string
+	err := filepath.Walk(filepath.Join("..", "..", "graphql", "documents"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".graphql" {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		sources = append(sources, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	es := models.NewExecutableSchema(models.Config{Resolvers: &Resolver{}})
+	doc, gqlErr := gqlparser.LoadQuery(es.Schema(), strings.Join(sources, "\n"))
+	if gqlErr != nil {
+		t.Fatal(gqlErr)
+	}
+
+	c := config.GetInstance()
+	for _, op := range doc.Operations {
+		assert.LessOrEqualf(t, complexity.Calculate(es, op, nil), c.GetMaxQueryComplexity(), "complexity of %s", op.Name)
+
+		for _, s := range op.SelectionSet {
+			assert.LessOrEqualf(t, selectionDepth(ast.SelectionSet{s}), c.GetMaxQueryDepth(), "depth of %s", op.Name)
+		}
+	}
+}
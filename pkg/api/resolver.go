@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strconv"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
@@ -31,6 +32,9 @@ func (r *Resolver) Scene() models.SceneResolver {
 func (r *Resolver) Image() models.ImageResolver {
 	return &imageResolver{r}
 }
+func (r *Resolver) SceneCaption() models.SceneCaptionResolver {
+	return &sceneCaptionResolver{r}
+}
 func (r *Resolver) SceneMarker() models.SceneMarkerResolver {
 	return &sceneMarkerResolver{r}
 }
@@ -46,6 +50,25 @@ func (r *Resolver) Subscription() models.SubscriptionResolver {
 func (r *Resolver) Tag() models.TagResolver {
 	return &tagResolver{r}
 }
+func (r *Resolver) PerformerRelationship() models.PerformerRelationshipResolver {
+	return &performerRelationshipResolver{r}
+}
+func (r *Resolver) Group() models.GroupResolver {
+	return &groupResolver{r}
+}
+func (r *Resolver) Playlist() models.PlaylistResolver {
+	return &playlistResolver{r}
+}
+func (r *Resolver) ScheduledTask() models.ScheduledTaskResolver {
+	return &scheduledTaskResolver{r}
+}
+func (r *Resolver) JobHistoryEntry() models.JobHistoryEntryResolver {
+	return &jobHistoryEntryResolver{r}
+}
+
+func (r *Resolver) StatsResultType() models.StatsResultTypeResolver {
+	return &statsResultTypeResolver{r}
+}
 
 func (r *Resolver) ScrapedSceneTag() models.ScrapedSceneTagResolver {
 	return &scrapedSceneTagResolver{r}
@@ -69,12 +92,19 @@ type subscriptionResolver struct{ *Resolver }
 
 type galleryResolver struct{ *Resolver }
 type performerResolver struct{ *Resolver }
+type performerRelationshipResolver struct{ *Resolver }
 type sceneResolver struct{ *Resolver }
+type sceneCaptionResolver struct{ *Resolver }
 type sceneMarkerResolver struct{ *Resolver }
 type imageResolver struct{ *Resolver }
 type studioResolver struct{ *Resolver }
 type movieResolver struct{ *Resolver }
 type tagResolver struct{ *Resolver }
+type groupResolver struct{ *Resolver }
+type playlistResolver struct{ *Resolver }
+type scheduledTaskResolver struct{ *Resolver }
+type jobHistoryEntryResolver struct{ *Resolver }
+type statsResultTypeResolver struct{ *Resolver }
 type scrapedSceneTagResolver struct{ *Resolver }
 type scrapedSceneMovieResolver struct{ *Resolver }
 type scrapedScenePerformerResolver struct{ *Resolver }
@@ -88,6 +118,69 @@ func (r *Resolver) withReadTxn(ctx context.Context, fn func(r models.ReaderRepos
 	return r.txnManager.WithReadTxn(ctx, fn)
 }
 
+// firstError returns the first non-nil error of the errors returned by
+// loading many objects from a dataloader.
+func firstError(errs []error) error {
+	for _, e := range errs {
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// countOnly returns true if the only field selected from the result of the
+// current query is its count, in which case the matching objects need not be
+// loaded.
+func countOnly(ctx context.Context) bool {
+	if graphql.GetFieldContext(ctx) == nil {
+		return false
+	}
+
+	for _, f := range graphql.CollectFieldsCtx(ctx, nil) {
+		if f.Name != "count" && f.Name != "__typename" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// The dataloaders load nil for objects which do not exist, such as objects
+// deleted since their IDs were read. These functions remove them from lists
+// of loaded objects.
+
+func foundScenes(scenes []*models.Scene) []*models.Scene {
+	var ret []*models.Scene
+	for _, s := range scenes {
+		if s != nil {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func foundPerformers(performers []*models.Performer) []*models.Performer {
+	var ret []*models.Performer
+	for _, p := range performers {
+		if p != nil {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+func foundTags(tags []*models.Tag) []*models.Tag {
+	var ret []*models.Tag
+	for _, t := range tags {
+		if t != nil {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
 func (r *queryResolver) MarkerWall(ctx context.Context, q *string) (ret []*models.SceneMarker, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
 		ret, err = repo.SceneMarker().Wall(q)
@@ -132,6 +225,7 @@ func (r *queryResolver) Stats(ctx context.Context) (*models.StatsResultType, err
 		tagsQB := repo.Tag()
 		scenesCount, _ := scenesQB.Count()
 		scenesSize, _ := scenesQB.Size()
+		scenesDuration, _ := scenesQB.Duration()
 		imageCount, _ := imageQB.Count()
 		imageSize, _ := imageQB.Size()
 		galleryCount, _ := galleryQB.Count()
@@ -143,6 +237,7 @@ func (r *queryResolver) Stats(ctx context.Context) (*models.StatsResultType, err
 		ret = models.StatsResultType{
 			SceneCount:     scenesCount,
 			ScenesSize:     scenesSize,
+			ScenesDuration: scenesDuration,
 			ImageCount:     imageCount,
 			ImagesSize:     imageSize,
 			GalleryCount:   galleryCount,
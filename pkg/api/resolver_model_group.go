@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *groupResolver) Description(ctx context.Context, obj *models.Group) (*string, error) {
+	if obj.Description.Valid {
+		return &obj.Description.String, nil
+	}
+	return nil, nil
+}
+
+func (r *groupResolver) Parent(ctx context.Context, obj *models.Group) (ret *models.Group, err error) {
+	if !obj.ParentID.Valid {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Group().Find(int(obj.ParentID.Int64))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *groupResolver) Children(ctx context.Context, obj *models.Group) (ret []*models.Group, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Group().FindChildren(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *groupResolver) sceneIDs(ctx context.Context, obj *models.Group) (ret []int, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Group().GetSceneIDs(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *groupResolver) Scenes(ctx context.Context, obj *models.Group) (ret []*models.Scene, err error) {
+	ids, err := r.sceneIDs(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).SceneByID.LoadAll(ids)
+	return foundScenes(ret), firstError(errs)
+}
+
+func (r *groupResolver) SceneCount(ctx context.Context, obj *models.Group) (int, error) {
+	ids, err := r.sceneIDs(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+func (r *groupResolver) CreatedAt(ctx context.Context, obj *models.Group) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *groupResolver) UpdatedAt(ctx context.Context, obj *models.Group) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"time"
 
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
@@ -60,14 +62,7 @@ func (r *imageResolver) Studio(ctx context.Context, obj *models.Image) (ret *mod
 		return nil, nil
 	}
 
-	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Studio().Find(int(obj.StudioID.Int64))
-		return err
-	}); err != nil {
-		return nil, err
-	}
-
-	return ret, nil
+	return loaders.From(ctx).StudioByID.Load(int(obj.StudioID.Int64))
 }
 
 func (r *imageResolver) Tags(ctx context.Context, obj *models.Image) (ret []*models.Tag, err error) {
@@ -91,3 +86,45 @@ func (r *imageResolver) Performers(ctx context.Context, obj *models.Image) (ret
 
 	return ret, nil
 }
+
+func (r *imageResolver) Location(ctx context.Context, obj *models.Image) (*string, error) {
+	if obj.Location.Valid {
+		return &obj.Location.String, nil
+	}
+	return nil, nil
+}
+
+func (r *imageResolver) Latitude(ctx context.Context, obj *models.Image) (*float64, error) {
+	if obj.Latitude.Valid {
+		return &obj.Latitude.Float64, nil
+	}
+	return nil, nil
+}
+
+func (r *imageResolver) Longitude(ctx context.Context, obj *models.Image) (*float64, error) {
+	if obj.Longitude.Valid {
+		return &obj.Longitude.Float64, nil
+	}
+	return nil, nil
+}
+
+func (r *imageResolver) TakenAt(ctx context.Context, obj *models.Image) (*time.Time, error) {
+	if obj.TakenAt.Valid {
+		return &obj.TakenAt.Timestamp, nil
+	}
+	return nil, nil
+}
+
+func (r *imageResolver) CameraMake(ctx context.Context, obj *models.Image) (*string, error) {
+	if obj.CameraMake.Valid {
+		return &obj.CameraMake.String, nil
+	}
+	return nil, nil
+}
+
+func (r *imageResolver) CameraModel(ctx context.Context, obj *models.Image) (*string, error) {
+	if obj.CameraModel.Valid {
+		return &obj.CameraModel.String, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *jobHistoryEntryResolver) Error(ctx context.Context, obj *models.JobHistoryEntry) (*string, error) {
+	if obj.Error.Valid {
+		return &obj.Error.String, nil
+	}
+	return nil, nil
+}
+
+func (r *jobHistoryEntryResolver) AddedAt(ctx context.Context, obj *models.JobHistoryEntry) (*time.Time, error) {
+	return &obj.AddedAt.Timestamp, nil
+}
+
+func (r *jobHistoryEntryResolver) StartedAt(ctx context.Context, obj *models.JobHistoryEntry) (*time.Time, error) {
+	if obj.StartedAt.Valid {
+		return &obj.StartedAt.Timestamp, nil
+	}
+	return nil, nil
+}
+
+func (r *jobHistoryEntryResolver) FinishedAt(ctx context.Context, obj *models.JobHistoryEntry) (*time.Time, error) {
+	return &obj.FinishedAt.Timestamp, nil
+}
+
+func (r *jobHistoryEntryResolver) Duration(ctx context.Context, obj *models.JobHistoryEntry) (*float64, error) {
+	if !obj.StartedAt.Valid {
+		return nil, nil
+	}
+
+	ret := obj.FinishedAt.Timestamp.Sub(obj.StartedAt.Timestamp).Seconds()
+	return &ret, nil
+}
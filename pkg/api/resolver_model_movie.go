@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -55,14 +56,7 @@ func (r *movieResolver) Rating(ctx context.Context, obj *models.Movie) (*int, er
 
 func (r *movieResolver) Studio(ctx context.Context, obj *models.Movie) (ret *models.Studio, err error) {
 	if obj.StudioID.Valid {
-		if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-			ret, err = repo.Studio().Find(int(obj.StudioID.Int64))
-			return err
-		}); err != nil {
-			return nil, err
-		}
-
-		return ret, nil
+		return loaders.From(ctx).StudioByID.Load(int(obj.StudioID.Int64))
 	}
 
 	return nil, nil
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
@@ -80,7 +82,16 @@ func (r *performerResolver) EyeColor(ctx context.Context, obj *models.Performer)
 
 func (r *performerResolver) Height(ctx context.Context, obj *models.Performer) (*string, error) {
 	if obj.Height.Valid {
-		return &obj.Height.String, nil
+		height := strconv.FormatInt(obj.Height.Int64, 10)
+		return &height, nil
+	}
+	return nil, nil
+}
+
+func (r *performerResolver) HeightCm(ctx context.Context, obj *models.Performer) (*int, error) {
+	if obj.Height.Valid {
+		height := int(obj.Height.Int64)
+		return &height, nil
 	}
 	return nil, nil
 }
@@ -245,3 +256,33 @@ func (r *performerResolver) Weight(ctx context.Context, obj *models.Performer) (
 	}
 	return nil, nil
 }
+
+func (r *performerResolver) AutoTagRegex(ctx context.Context, obj *models.Performer) (*string, error) {
+	if obj.AutoTagRegex.Valid {
+		return &obj.AutoTagRegex.String, nil
+	}
+	return nil, nil
+}
+
+func (r *performerResolver) Relationships(ctx context.Context, obj *models.Performer) (ret []*models.PerformerRelationship, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Performer().GetRelationships(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *performerRelationshipResolver) Type(ctx context.Context, obj *models.PerformerRelationship) (models.PerformerRelationshipType, error) {
+	return models.PerformerRelationshipType(obj.Type), nil
+}
+
+func (r *performerRelationshipResolver) Performer(ctx context.Context, obj *models.PerformerRelationship) (*models.Performer, error) {
+	return loaders.From(ctx).PerformerByID.Load(obj.PerformerID)
+}
+
+func (r *performerRelationshipResolver) Related(ctx context.Context, obj *models.PerformerRelationship) (*models.Performer, error) {
+	return loaders.From(ctx).PerformerByID.Load(obj.RelatedID)
+}
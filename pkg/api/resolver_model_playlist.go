@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *playlistResolver) sceneIDs(ctx context.Context, obj *models.Playlist) (ret []int, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Playlist().GetSceneIDs(obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *playlistResolver) Scenes(ctx context.Context, obj *models.Playlist) (ret []*models.Scene, err error) {
+	ids, err := r.sceneIDs(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).SceneByID.LoadAll(ids)
+	return foundScenes(ret), firstError(errs)
+}
+
+func (r *playlistResolver) SceneCount(ctx context.Context, obj *models.Playlist) (int, error) {
+	ids, err := r.sceneIDs(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+func (r *playlistResolver) CreatedAt(ctx context.Context, obj *models.Playlist) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *playlistResolver) UpdatedAt(ctx context.Context, obj *models.Playlist) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"sort"
+	"strings"
 
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
@@ -60,6 +63,14 @@ func (r *sceneResolver) Rating(ctx context.Context, obj *models.Scene) (*int, er
 	return nil, nil
 }
 
+func (r *sceneResolver) InteractiveSpeed(ctx context.Context, obj *models.Scene) (*int, error) {
+	if obj.InteractiveSpeed.Valid {
+		speed := int(obj.InteractiveSpeed.Int64)
+		return &speed, nil
+	}
+	return nil, nil
+}
+
 func (r *sceneResolver) File(ctx context.Context, obj *models.Scene) (*models.SceneFileType, error) {
 	width := int(obj.Width.Int64)
 	height := int(obj.Height.Int64)
@@ -87,14 +98,30 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*models.S
 	vttPath := builder.GetSpriteVTTURL()
 	spritePath := builder.GetSpriteURL()
 	chaptersVttPath := builder.GetChaptersVTTURL()
+
+	var funscriptPath *string
+	if obj.Interactive {
+		p := builder.GetFunscriptURL()
+		funscriptPath = &p
+	}
+
+	// the heatmap is generated with the interactive speed
+	var heatmapPath *string
+	if obj.Interactive && obj.InteractiveSpeed.Valid {
+		p := builder.GetInteractiveHeatmapURL()
+		heatmapPath = &p
+	}
+
 	return &models.ScenePathsType{
-		Screenshot:  &screenshotPath,
-		Preview:     &previewPath,
-		Stream:      &streamPath,
-		Webp:        &webpPath,
-		Vtt:         &vttPath,
-		ChaptersVtt: &chaptersVttPath,
-		Sprite:      &spritePath,
+		Screenshot:         &screenshotPath,
+		Preview:            &previewPath,
+		Stream:             &streamPath,
+		Webp:               &webpPath,
+		Vtt:                &vttPath,
+		ChaptersVtt:        &chaptersVttPath,
+		Sprite:             &spritePath,
+		Funscript:          funscriptPath,
+		InteractiveHeatmap: heatmapPath,
 	}, nil
 }
 
@@ -125,14 +152,7 @@ func (r *sceneResolver) Studio(ctx context.Context, obj *models.Scene) (ret *mod
 		return nil, nil
 	}
 
-	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Studio().Find(int(obj.StudioID.Int64))
-		return err
-	}); err != nil {
-		return nil, err
-	}
-
-	return ret, nil
+	return loaders.From(ctx).StudioByID.Load(int(obj.StudioID.Int64))
 }
 
 func (r *sceneResolver) Movies(ctx context.Context, obj *models.Scene) (ret []*models.SceneMovie, err error) {
@@ -172,9 +192,9 @@ func (r *sceneResolver) Movies(ctx context.Context, obj *models.Scene) (ret []*m
 	return ret, nil
 }
 
-func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene) (ret []*models.Tag, err error) {
+func (r *sceneResolver) Groups(ctx context.Context, obj *models.Scene) (ret []*models.Group, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Tag().FindBySceneID(obj.ID)
+		ret, err = repo.Group().FindBySceneID(obj.ID)
 		return err
 	}); err != nil {
 		return nil, err
@@ -183,9 +203,45 @@ func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene) (ret []*mod
 	return ret, nil
 }
 
+func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene) (ret []*models.Tag, err error) {
+	ids, err := loaders.From(ctx).SceneTagIDs.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).TagByID.LoadAll(ids)
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	ret = foundTags(ret)
+	sortTagsByName(ret)
+	return ret, nil
+}
+
+// sortTagsByName sorts the tags by name ignoring case, as tags are sorted
+// by default when queried.
+func sortTagsByName(tags []*models.Tag) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name)
+	})
+}
+
 func (r *sceneResolver) Performers(ctx context.Context, obj *models.Scene) (ret []*models.Performer, err error) {
+	ids, err := loaders.From(ctx).ScenePerformerIDs.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).PerformerByID.LoadAll(ids)
+	return foundPerformers(ret), firstError(errs)
+}
+
+func (r *sceneResolver) StashIds(ctx context.Context, obj *models.Scene) (ret []*models.StashID, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Performer().FindBySceneID(obj.ID)
+		ret, err = repo.Scene().GetStashIDs(obj.ID)
 		return err
 	}); err != nil {
 		return nil, err
@@ -194,9 +250,9 @@ func (r *sceneResolver) Performers(ctx context.Context, obj *models.Scene) (ret
 	return ret, nil
 }
 
-func (r *sceneResolver) StashIds(ctx context.Context, obj *models.Scene) (ret []*models.StashID, err error) {
+func (r *sceneResolver) Captions(ctx context.Context, obj *models.Scene) (ret []*models.SceneCaption, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Scene().GetStashIDs(obj.ID)
+		ret, err = repo.Scene().GetCaptions(obj.ID)
 		return err
 	}); err != nil {
 		return nil, err
@@ -212,3 +268,38 @@ func (r *sceneResolver) Phash(ctx context.Context, obj *models.Scene) (*string,
 	}
 	return nil, nil
 }
+
+func (r *sceneResolver) Location(ctx context.Context, obj *models.Scene) (*string, error) {
+	if obj.Location.Valid {
+		return &obj.Location.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneResolver) DecodeError(ctx context.Context, obj *models.Scene) (*string, error) {
+	if obj.DecodeError.Valid {
+		return &obj.DecodeError.String, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneResolver) Latitude(ctx context.Context, obj *models.Scene) (*float64, error) {
+	if obj.Latitude.Valid {
+		return &obj.Latitude.Float64, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneResolver) Longitude(ctx context.Context, obj *models.Scene) (*float64, error) {
+	if obj.Longitude.Valid {
+		return &obj.Longitude.Float64, nil
+	}
+	return nil, nil
+}
+
+func (r *sceneCaptionResolver) URL(ctx context.Context, obj *models.SceneCaption) (string, error) {
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, obj.SceneID)
+	builder.APIKey = config.GetInstance().GetAPIKey()
+	return builder.GetCaptionURL(obj.ID), nil
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/models"
 )
@@ -12,26 +13,11 @@ func (r *sceneMarkerResolver) Scene(ctx context.Context, obj *models.SceneMarker
 		panic("Invalid scene id")
 	}
 
-	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		sceneID := int(obj.SceneID.Int64)
-		ret, err = repo.Scene().Find(sceneID)
-		return err
-	}); err != nil {
-		return nil, err
-	}
-
-	return ret, nil
+	return loaders.From(ctx).SceneByID.Load(int(obj.SceneID.Int64))
 }
 
 func (r *sceneMarkerResolver) PrimaryTag(ctx context.Context, obj *models.SceneMarker) (ret *models.Tag, err error) {
-	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Tag().Find(obj.PrimaryTagID)
-		return err
-	}); err != nil {
-		return nil, err
-	}
-
-	return ret, err
+	return loaders.From(ctx).TagByID.Load(obj.PrimaryTagID)
 }
 
 func (r *sceneMarkerResolver) Tags(ctx context.Context, obj *models.SceneMarker) (ret []*models.Tag, err error) {
@@ -56,3 +42,9 @@ func (r *sceneMarkerResolver) Preview(ctx context.Context, obj *models.SceneMark
 	sceneID := int(obj.SceneID.Int64)
 	return urlbuilders.NewSceneURLBuilder(baseURL, sceneID).GetSceneMarkerStreamPreviewURL(obj.ID), nil
 }
+
+func (r *sceneMarkerResolver) Clip(ctx context.Context, obj *models.SceneMarker) (string, error) {
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	sceneID := int(obj.SceneID.Int64)
+	return urlbuilders.NewSceneURLBuilder(baseURL, sceneID).GetSceneMarkerClipURL(obj.ID), nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneTagsSortedByName(t *testing.T) {
+	r := newResolver()
+
+	const sceneID = 1
+	txnManager := r.txnManager.(*mocks.TransactionManager)
+	txnManager.Scene().(*mocks.SceneReaderWriter).On("GetManyTagIDs", []int{sceneID}).Return([][]int{{1, 2, 3}}, nil).Once()
+	txnManager.Tag().(*mocks.TagReaderWriter).On("FindMany", []int{1, 2, 3}).Return([]*models.Tag{
+		{ID: 1, Name: "charlie"},
+		{ID: 2, Name: "Alpha"},
+		{ID: 3, Name: "bravo"},
+	}, nil).Once()
+
+	var tags []*models.Tag
+	var err error
+	handler := loaders.Middleware{TxnManager: txnManager}.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tags, err = r.Scene().Tags(req.Context(), &models.Scene{ID: sceneID})
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Nil(t, err)
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"Alpha", "bravo", "charlie"}, names)
+}
+
+func TestScenePerformersMissing(t *testing.T) {
+	r := newResolver()
+
+	const sceneID = 1
+	txnManager := r.txnManager.(*mocks.TransactionManager)
+	txnManager.Scene().(*mocks.SceneReaderWriter).On("GetManyPerformerIDs", []int{sceneID}).Return([][]int{{1, 2}}, nil).Once()
+
+	// one deleted performer does not fail the other performers
+	performerReaderWriter := txnManager.Performer().(*mocks.PerformerReaderWriter)
+	performerReaderWriter.On("FindMany", []int{1, 2}).Return(nil, errors.New("performer with id 2 not found")).Once()
+	performerReaderWriter.On("Find", 1).Return(&models.Performer{ID: 1}, nil).Once()
+	performerReaderWriter.On("Find", 2).Return(nil, nil).Once()
+
+	var performers []*models.Performer
+	var err error
+	handler := loaders.Middleware{TxnManager: txnManager}.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		performers, err = r.Scene().Performers(req.Context(), &models.Scene{ID: sceneID})
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Nil(t, err)
+	if assert.Len(t, performers, 1) {
+		assert.Equal(t, 1, performers[0].ID)
+	}
+	performerReaderWriter.AssertExpectations(t)
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *scheduledTaskResolver) LastRunAt(ctx context.Context, obj *models.ScheduledTask) (*time.Time, error) {
+	if obj.LastRunAt.Valid {
+		return &obj.LastRunAt.Timestamp, nil
+	}
+	return nil, nil
+}
+
+func (r *scheduledTaskResolver) NextRunAt(ctx context.Context, obj *models.ScheduledTask) (*time.Time, error) {
+	return manager.NextScheduledRun(obj), nil
+}
+
+func (r *scheduledTaskResolver) CreatedAt(ctx context.Context, obj *models.ScheduledTask) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *scheduledTaskResolver) UpdatedAt(ctx context.Context, obj *models.ScheduledTask) (*time.Time, error) {
+	return &obj.UpdatedAt.Timestamp, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const defaultStatsLimit = 10
+
+func getStatsLimit(limit *int) int {
+	if limit == nil {
+		return defaultStatsLimit
+	}
+
+	return *limit
+}
+
+func (r *statsResultTypeResolver) StudioStats(ctx context.Context, obj *models.StatsResultType, limit *int) (ret []*models.StatsAggregate, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Studio().SceneStats(getStatsLimit(limit))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *statsResultTypeResolver) TagStats(ctx context.Context, obj *models.StatsResultType, limit *int) (ret []*models.StatsAggregate, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Tag().SceneStats(getStatsLimit(limit))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *statsResultTypeResolver) PerformerStats(ctx context.Context, obj *models.StatsResultType, limit *int) (ret []*models.StatsAggregate, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Performer().SceneStats(getStatsLimit(limit))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
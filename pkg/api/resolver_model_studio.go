@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
@@ -85,14 +86,7 @@ func (r *studioResolver) ParentStudio(ctx context.Context, obj *models.Studio) (
 		return nil, nil
 	}
 
-	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		ret, err = repo.Studio().Find(int(obj.ParentID.Int64))
-		return err
-	}); err != nil {
-		return nil, err
-	}
-
-	return ret, nil
+	return loaders.From(ctx).StudioByID.Load(int(obj.ParentID.Int64))
 }
 
 func (r *studioResolver) ChildStudios(ctx context.Context, obj *models.Studio) (ret []*models.Studio, err error) {
@@ -131,3 +125,10 @@ func (r *studioResolver) Details(ctx context.Context, obj *models.Studio) (*stri
 	}
 	return nil, nil
 }
+
+func (r *studioResolver) Country(ctx context.Context, obj *models.Studio) (*string, error) {
+	if obj.Country.Valid {
+		return &obj.Country.String, nil
+	}
+	return nil, nil
+}
@@ -9,6 +9,13 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 )
 
+func (r *tagResolver) AutoTagRegex(ctx context.Context, obj *models.Tag) (*string, error) {
+	if obj.AutoTagRegex.Valid {
+		return &obj.AutoTagRegex.String, nil
+	}
+	return nil, nil
+}
+
 func (r *tagResolver) SceneCount(ctx context.Context, obj *models.Tag) (ret *int, err error) {
 	var count int
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
@@ -10,6 +10,8 @@ import (
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/scraper/packages"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -70,6 +72,19 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 		c.Set(config.Cache, input.CachePath)
 	}
 
+	if input.BlobsStorage != nil {
+		c.Set(config.BlobsStorage, input.BlobsStorage.String())
+	}
+
+	if input.BlobsPath != nil {
+		if *input.BlobsPath != "" {
+			if err := utils.EnsureDir(*input.BlobsPath); err != nil {
+				return makeConfigGeneralResult(), err
+			}
+		}
+		c.Set(config.BlobsPath, input.BlobsPath)
+	}
+
 	if !input.CalculateMd5 && input.VideoFileNamingAlgorithm == models.HashAlgorithmMd5 {
 		return makeConfigGeneralResult(), errors.New("calculateMD5 must be true if using MD5")
 	}
@@ -88,6 +103,82 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 	if input.ParallelTasks != nil {
 		c.Set(config.ParallelTasks, *input.ParallelTasks)
 	}
+	if input.HashWorkers != nil {
+		if *input.HashWorkers < 0 {
+			return makeConfigGeneralResult(), errors.New("hash workers must not be negative")
+		}
+
+		c.Set(config.HashWorkers, *input.HashWorkers)
+	}
+	if input.ProbeWorkers != nil {
+		if *input.ProbeWorkers < 0 {
+			return makeConfigGeneralResult(), errors.New("probe workers must not be negative")
+		}
+
+		c.Set(config.ProbeWorkers, *input.ProbeWorkers)
+	}
+	if input.GenerateWorkers != nil {
+		if *input.GenerateWorkers < 0 {
+			return makeConfigGeneralResult(), errors.New("generate workers must not be negative")
+		}
+
+		c.Set(config.GenerateWorkers, *input.GenerateWorkers)
+	}
+	if input.ThumbnailWorkers != nil {
+		if *input.ThumbnailWorkers < 0 {
+			return makeConfigGeneralResult(), errors.New("thumbnail workers must not be negative")
+		}
+
+		c.Set(config.ThumbnailWorkers, *input.ThumbnailWorkers)
+	}
+	if input.ThumbnailSizes != nil {
+		for _, s := range input.ThumbnailSizes {
+			if s <= 0 {
+				return makeConfigGeneralResult(), errors.New("thumbnail sizes must be positive")
+			}
+		}
+
+		c.Set(config.ThumbnailSizes, input.ThumbnailSizes)
+	}
+	if input.ThumbnailCacheSize != nil {
+		if *input.ThumbnailCacheSize < 0 {
+			return makeConfigGeneralResult(), errors.New("thumbnail cache size must not be negative")
+		}
+
+		c.Set(config.ThumbnailCacheSize, *input.ThumbnailCacheSize)
+	}
+	if input.ImportWorkers != nil {
+		if *input.ImportWorkers < 0 {
+			return makeConfigGeneralResult(), errors.New("import workers must not be negative")
+		}
+
+		c.Set(config.ImportWorkers, *input.ImportWorkers)
+	}
+	if input.BackupInterval != nil {
+		if *input.BackupInterval < 0 {
+			return makeConfigGeneralResult(), errors.New("backup interval must not be negative")
+		}
+
+		c.Set(config.BackupInterval, *input.BackupInterval)
+	}
+	if input.BackupPath != nil {
+		if *input.BackupPath != "" {
+			if err := utils.EnsureDir(*input.BackupPath); err != nil {
+				return makeConfigGeneralResult(), err
+			}
+		}
+		c.Set(config.BackupPath, *input.BackupPath)
+	}
+	if input.BackupCount != nil {
+		if *input.BackupCount < 1 {
+			return makeConfigGeneralResult(), errors.New("backup count must be at least 1")
+		}
+
+		c.Set(config.BackupCount, *input.BackupCount)
+	}
+	if input.BackupIncludeExport != nil {
+		c.Set(config.BackupIncludeExport, *input.BackupIncludeExport)
+	}
 	if input.PreviewSegments != nil {
 		c.Set(config.PreviewSegments, *input.PreviewSegments)
 	}
@@ -103,6 +194,43 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 	if input.PreviewPreset != nil {
 		c.Set(config.PreviewPreset, input.PreviewPreset.String())
 	}
+	if input.PreviewImageQuality != nil {
+		if *input.PreviewImageQuality < 0 || *input.PreviewImageQuality > 100 {
+			return makeConfigGeneralResult(), fmt.Errorf("preview image quality must be between 0 and 100")
+		}
+		c.Set(config.PreviewImageQuality, *input.PreviewImageQuality)
+	}
+	if input.PreviewImageFps != nil {
+		if *input.PreviewImageFps <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("preview image frame rate must be positive")
+		}
+		c.Set(config.PreviewImageFps, *input.PreviewImageFps)
+	}
+
+	if input.SpriteRows != nil {
+		if *input.SpriteRows <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("sprite rows must be positive")
+		}
+		c.Set(config.SpriteRows, *input.SpriteRows)
+	}
+	if input.SpriteColumns != nil {
+		if *input.SpriteColumns <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("sprite columns must be positive")
+		}
+		c.Set(config.SpriteColumns, *input.SpriteColumns)
+	}
+	if input.SpriteWidth != nil {
+		if *input.SpriteWidth <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("sprite width must be positive")
+		}
+		c.Set(config.SpriteWidth, *input.SpriteWidth)
+	}
+	if input.SpriteInterval != nil {
+		if *input.SpriteInterval < 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("sprite interval cannot be negative")
+		}
+		c.Set(config.SpriteInterval, *input.SpriteInterval)
+	}
 
 	if input.MaxTranscodeSize != nil {
 		c.Set(config.MaxTranscodeSize, input.MaxTranscodeSize.String())
@@ -112,6 +240,45 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 		c.Set(config.MaxStreamingTranscodeSize, input.MaxStreamingTranscodeSize.String())
 	}
 
+	if input.HardwareAcceleration != nil {
+		c.Set(config.HardwareAcceleration, input.HardwareAcceleration.String())
+	}
+
+	if input.MaxTranscodeProcesses != nil {
+		if *input.MaxTranscodeProcesses < 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("max transcode processes must not be negative")
+		}
+		c.Set(config.MaxTranscodeProcesses, *input.MaxTranscodeProcesses)
+	}
+
+	if input.MaxGenerateProcesses != nil {
+		if *input.MaxGenerateProcesses < 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("max generate processes must not be negative")
+		}
+		c.Set(config.MaxGenerateProcesses, *input.MaxGenerateProcesses)
+	}
+
+	if input.TranscodeProfiles != nil {
+		if err := c.ValidateTranscodeProfiles(input.TranscodeProfiles); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.TranscodeProfiles, input.TranscodeProfiles)
+	}
+
+	if input.LiveTranscodeProfile != nil {
+		if *input.LiveTranscodeProfile != "" && c.GetTranscodeProfile(*input.LiveTranscodeProfile) == nil {
+			return makeConfigGeneralResult(), fmt.Errorf("transcode profile not found: %s", *input.LiveTranscodeProfile)
+		}
+		c.Set(config.LiveTranscodeProfile, *input.LiveTranscodeProfile)
+	}
+
+	if input.PreviewTranscodeProfile != nil {
+		if *input.PreviewTranscodeProfile != "" && c.GetTranscodeProfile(*input.PreviewTranscodeProfile) == nil {
+			return makeConfigGeneralResult(), fmt.Errorf("transcode profile not found: %s", *input.PreviewTranscodeProfile)
+		}
+		c.Set(config.PreviewTranscodeProfile, *input.PreviewTranscodeProfile)
+	}
+
 	if input.Username != nil {
 		c.Set(config.Username, input.Username)
 	}
@@ -142,6 +309,74 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 		logger.SetLogLevel(input.LogLevel)
 	}
 
+	if input.MaxSessionBandwidth != nil {
+		if *input.MaxSessionBandwidth < 0 {
+			return makeConfigGeneralResult(), errors.New("maxSessionBandwidth must not be negative")
+		}
+		c.Set(config.MaxSessionBandwidth, *input.MaxSessionBandwidth)
+	}
+
+	if input.MaxGlobalBandwidth != nil {
+		if *input.MaxGlobalBandwidth < 0 {
+			return makeConfigGeneralResult(), errors.New("maxGlobalBandwidth must not be negative")
+		}
+		c.Set(config.MaxGlobalBandwidth, *input.MaxGlobalBandwidth)
+	}
+
+	if input.MaxRequestsPerMinute != nil {
+		if *input.MaxRequestsPerMinute < 0 {
+			return makeConfigGeneralResult(), errors.New("maxRequestsPerMinute must not be negative")
+		}
+		c.Set(config.MaxRequestsPerMinute, *input.MaxRequestsPerMinute)
+	}
+
+	if input.LoginMaxAttempts != nil {
+		if *input.LoginMaxAttempts < 0 {
+			return makeConfigGeneralResult(), errors.New("loginMaxAttempts must not be negative")
+		}
+		c.Set(config.LoginMaxAttempts, *input.LoginMaxAttempts)
+	}
+
+	if input.LoginLockoutDuration != nil {
+		if *input.LoginLockoutDuration < 0 {
+			return makeConfigGeneralResult(), errors.New("loginLockoutDuration must not be negative")
+		}
+		c.Set(config.LoginLockoutDuration, *input.LoginLockoutDuration)
+	}
+
+	if input.MaxQueryComplexity != nil {
+		if *input.MaxQueryComplexity < 0 {
+			return makeConfigGeneralResult(), errors.New("maxQueryComplexity must not be negative")
+		}
+		c.Set(config.MaxQueryComplexity, *input.MaxQueryComplexity)
+	}
+
+	if input.MaxQueryDepth != nil {
+		if *input.MaxQueryDepth < 0 {
+			return makeConfigGeneralResult(), errors.New("maxQueryDepth must not be negative")
+		}
+		c.Set(config.MaxQueryDepth, *input.MaxQueryDepth)
+	}
+
+	if input.APIKeyMaxQueryComplexity != nil {
+		c.Set(config.APIKeyMaxQueryComplexity, *input.APIKeyMaxQueryComplexity)
+	}
+
+	if input.APIKeyMaxQueryDepth != nil {
+		c.Set(config.APIKeyMaxQueryDepth, *input.APIKeyMaxQueryDepth)
+	}
+
+	if input.QueryProfiling != nil {
+		c.Set(config.QueryProfiling, *input.QueryProfiling)
+	}
+
+	if input.SlowQueryThreshold != nil {
+		if *input.SlowQueryThreshold < 0 {
+			return makeConfigGeneralResult(), errors.New("slowQueryThreshold must not be negative")
+		}
+		c.Set(config.SlowQueryThreshold, *input.SlowQueryThreshold)
+	}
+
 	if input.Excludes != nil {
 		c.Set(config.Exclude, input.Excludes)
 	}
@@ -154,6 +389,10 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 		c.Set(config.VideoExtensions, input.VideoExtensions)
 	}
 
+	if input.AudioExtensions != nil {
+		c.Set(config.AudioExtensions, input.AudioExtensions)
+	}
+
 	if input.ImageExtensions != nil {
 		c.Set(config.ImageExtensions, input.ImageExtensions)
 	}
@@ -177,6 +416,48 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 
 	c.Set(config.ScraperCertCheck, input.ScraperCertCheck)
 
+	if input.ScraperCacheTTL != nil {
+		if *input.ScraperCacheTTL < 0 {
+			return makeConfigGeneralResult(), errors.New("scraper cache TTL must not be negative")
+		}
+		c.Set(config.ScraperCacheTTL, *input.ScraperCacheTTL)
+	}
+
+	if input.ScraperRequestInterval != nil {
+		if *input.ScraperRequestInterval < 0 {
+			return makeConfigGeneralResult(), errors.New("scraper request interval must not be negative")
+		}
+		c.Set(config.ScraperRequestInterval, *input.ScraperRequestInterval)
+	}
+
+	if input.ScraperRequestJitter != nil {
+		if *input.ScraperRequestJitter < 0 {
+			return makeConfigGeneralResult(), errors.New("scraper request jitter must not be negative")
+		}
+		c.Set(config.ScraperRequestJitter, *input.ScraperRequestJitter)
+	}
+
+	if input.ScraperMaxConcurrency != nil {
+		if *input.ScraperMaxConcurrency < 0 {
+			return makeConfigGeneralResult(), errors.New("maximum concurrent scraper requests must not be negative")
+		}
+		c.Set(config.ScraperMaxConcurrency, *input.ScraperMaxConcurrency)
+	}
+
+	if input.ScraperSources != nil {
+		if err := scraper.ValidateSources(input.ScraperSources); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.ScraperSources, input.ScraperSources)
+	}
+
+	if input.ScraperPackageSources != nil {
+		if err := packages.ValidateSources(input.ScraperPackageSources); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.ScraperPackageSources, input.ScraperPackageSources)
+	}
+
 	if input.StashBoxes != nil {
 		if err := c.ValidateStashBoxes(input.StashBoxes); err != nil {
 			return nil, err
@@ -184,6 +465,45 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input models.Co
 		c.Set(config.StashBoxes, input.StashBoxes)
 	}
 
+	if input.Webhooks != nil {
+		if err := manager.ValidateWebhooks(input.Webhooks); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.Webhooks, input.Webhooks)
+	}
+
+	if input.NotificationSinks != nil {
+		if err := manager.ValidateNotificationSinks(input.NotificationSinks); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.NotificationSinks, input.NotificationSinks)
+	}
+
+	if input.ZipPasswords != nil {
+		if err := manager.ValidateZipPasswords(input.ZipPasswords); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.ZipPasswords, input.ZipPasswords)
+	}
+
+	if input.ObjectStorage != nil {
+		if err := manager.ValidateObjectStorage(input.ObjectStorage); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.ObjectStorage, input.ObjectStorage)
+	}
+
+	if input.DuplicateNamePolicy != nil {
+		if !input.DuplicateNamePolicy.IsValid() {
+			return makeConfigGeneralResult(), fmt.Errorf("invalid duplicate name policy: %s", *input.DuplicateNamePolicy)
+		}
+		c.Set(config.DuplicateNamePolicy, input.DuplicateNamePolicy.String())
+	}
+
+	if input.AutoTagSeparators != nil {
+		c.Set(config.AutoTagSeparators, *input.AutoTagSeparators)
+	}
+
 	if err := c.Write(); err != nil {
 		return makeConfigGeneralResult(), err
 	}
@@ -234,6 +554,14 @@ func (r *mutationResolver) ConfigureInterface(ctx context.Context, input models.
 		c.Set(config.SlideshowDelay, *input.SlideshowDelay)
 	}
 
+	if input.FunscriptOffset != nil {
+		c.Set(config.FunscriptOffset, *input.FunscriptOffset)
+	}
+
+	if input.HandyKey != nil {
+		c.Set(config.HandyKey, *input.HandyKey)
+	}
+
 	css := ""
 
 	if input.CSS != nil {
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func validateGroup(g models.Group, qb models.GroupReader) error {
+	if strings.TrimSpace(g.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+
+	return manager.ValidateModifyGroup(g, qb)
+}
+
+func findGroup(qb models.GroupReader, id int) (*models.Group, error) {
+	ret, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, fmt.Errorf("group with id %d not found", id)
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) GroupCreate(ctx context.Context, input models.GroupCreateInput) (*models.Group, error) {
+	currentTime := time.Now()
+	newGroup := models.Group{
+		Name:      input.Name,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	if input.Description != nil {
+		newGroup.Description = sql.NullString{String: *input.Description, Valid: true}
+	}
+	if input.ParentID != nil {
+		parentID, err := strconv.ParseInt(*input.ParentID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		newGroup.ParentID = sql.NullInt64{Int64: parentID, Valid: true}
+	}
+
+	var ret *models.Group
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Group()
+		if err := validateGroup(newGroup, qb); err != nil {
+			return err
+		}
+
+		sceneIDs, err := existingSceneIDs(repo, input.SceneIds)
+		if err != nil {
+			return err
+		}
+
+		ret, err = qb.Create(newGroup)
+		if err != nil {
+			return err
+		}
+
+		return qb.UpdateScenes(ret.ID, sceneIDs)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) GroupUpdate(ctx context.Context, input models.GroupUpdateInput) (*models.Group, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	var ret *models.Group
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Group()
+		existing, err := findGroup(qb, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *existing
+		if input.Name != nil {
+			updated.Name = *input.Name
+		}
+		if description := translator.nullString(input.Description, "description"); description != nil {
+			updated.Description = *description
+		}
+		if parentID := translator.nullInt64FromString(input.ParentID, "parent_id"); parentID != nil {
+			updated.ParentID = *parentID
+		}
+
+		if err := validateGroup(updated, qb); err != nil {
+			return err
+		}
+
+		if input.SceneIds != nil {
+			sceneIDs, err := existingSceneIDs(repo, input.SceneIds)
+			if err != nil {
+				return err
+			}
+
+			if err := qb.UpdateScenes(id, sceneIDs); err != nil {
+				return err
+			}
+		}
+
+		updated.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+		ret, err = qb.Update(updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) GroupAddScenes(ctx context.Context, input models.GroupAddScenesInput) (*models.Group, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.Group
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Group()
+		existing, err := findGroup(qb, id)
+		if err != nil {
+			return err
+		}
+
+		toAdd, err := existingSceneIDs(repo, input.SceneIds)
+		if err != nil {
+			return err
+		}
+
+		sceneIDs, err := qb.GetSceneIDs(id)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.UpdateScenes(id, utils.IntAppendUniques(sceneIDs, toAdd)); err != nil {
+			return err
+		}
+
+		updated := *existing
+		updated.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+		ret, err = qb.Update(updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) GroupDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		return repo.Group().Destroy(idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
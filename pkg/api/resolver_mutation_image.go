@@ -68,6 +68,9 @@ func (r *mutationResolver) imageUpdate(input models.ImageUpdateInput, translator
 
 	updatedImage.Title = translator.nullString(input.Title, "title")
 	updatedImage.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedImage.Location = translator.nullString(input.Location, "location")
+	updatedImage.Latitude = translator.nullFloat64(input.Latitude, "latitude")
+	updatedImage.Longitude = translator.nullFloat64(input.Longitude, "longitude")
 	updatedImage.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedImage.Organized = input.Organized
 
@@ -143,6 +146,9 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input models.Bul
 
 	updatedImage.Title = translator.nullString(input.Title, "title")
 	updatedImage.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedImage.Location = translator.nullString(input.Location, "location")
+	updatedImage.Latitude = translator.nullFloat64(input.Latitude, "latitude")
+	updatedImage.Longitude = translator.nullFloat64(input.Longitude, "longitude")
 	updatedImage.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedImage.Organized = input.Organized
 
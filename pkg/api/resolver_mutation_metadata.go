@@ -2,8 +2,10 @@ package api
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/stashapp/stash/pkg/database"
@@ -15,18 +17,20 @@ import (
 )
 
 func (r *mutationResolver) MetadataScan(ctx context.Context, input models.ScanMetadataInput) (string, error) {
-	if err := manager.GetInstance().Scan(input); err != nil {
+	j, err := manager.GetInstance().Scan(input)
+	if err != nil {
 		return "", err
 	}
-	return "todo", nil
+	return jobID(j), nil
 }
 
 func (r *mutationResolver) MetadataImport(ctx context.Context) (string, error) {
-	if err := manager.GetInstance().Import(); err != nil {
+	j, err := manager.GetInstance().Import()
+	if err != nil {
 		return "", err
 	}
 
-	return "todo", nil
+	return jobID(j), nil
 }
 
 func (r *mutationResolver) ImportObjects(ctx context.Context, input models.ImportObjectsInput) (string, error) {
@@ -35,37 +39,65 @@ func (r *mutationResolver) ImportObjects(ctx context.Context, input models.Impor
 		return "", err
 	}
 
-	_, err = manager.GetInstance().RunSingleTask(t)
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) ImportFromStash(ctx context.Context, input models.RemoteImportInput) (string, error) {
+	t, err := manager.CreateRemoteImportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
 	if err != nil {
 		return "", err
 	}
 
-	return "todo", nil
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) ImportObjectsDryRun(ctx context.Context, input models.ImportObjectsInput) (*models.ImportReport, error) {
+	t, err := manager.CreateImportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
+	if err != nil {
+		return nil, err
+	}
+	t.DryRun = true
+
+	// the user waits for the report
+	manager.GetInstance().RunTask(t, models.JobPriorityHigh).Wait()
+
+	return t.Report()
 }
 
 func (r *mutationResolver) MetadataExport(ctx context.Context) (string, error) {
-	if err := manager.GetInstance().Export(); err != nil {
+	j, err := manager.GetInstance().Export()
+	if err != nil {
 		return "", err
 	}
 
-	return "todo", nil
+	return jobID(j), nil
 }
 
-func (r *mutationResolver) ExportObjects(ctx context.Context, input models.ExportObjectsInput) (*string, error) {
-	t := manager.CreateExportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
-	wg, err := manager.GetInstance().RunSingleTask(t)
+func (r *mutationResolver) ExportNfo(ctx context.Context, input models.ExportNfoInput) (string, error) {
+	t, err := manager.CreateExportNfoTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	wg.Wait()
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) ExportObjects(ctx context.Context, input models.ExportObjectsInput) (*string, error) {
+	t := manager.CreateExportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
+	if t.Streamed() {
+		// the export is run when the link is downloaded
+		t.RegisterStream()
+	} else {
+		// the link is returned once the export has finished
+		manager.GetInstance().RunTask(t, models.JobPriorityHigh).Wait()
+	}
 
 	if t.DownloadHash != "" {
 		baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 
 		// generate timestamp
 		suffix := time.Now().Format("20060102-150405")
-		ret := baseURL + "/downloads/" + t.DownloadHash + "/export" + suffix + ".zip"
+		ret := baseURL + "/downloads/" + t.DownloadHash + "/export" + suffix + t.DownloadExtension
 		return &ret, nil
 	}
 
@@ -73,40 +105,96 @@ func (r *mutationResolver) ExportObjects(ctx context.Context, input models.Expor
 }
 
 func (r *mutationResolver) MetadataGenerate(ctx context.Context, input models.GenerateMetadataInput) (string, error) {
-	if err := manager.GetInstance().Generate(input); err != nil {
+	j, err := manager.GetInstance().Generate(input)
+	if err != nil {
 		return "", err
 	}
-	return "todo", nil
+	return jobID(j), nil
 }
 
 func (r *mutationResolver) MetadataAutoTag(ctx context.Context, input models.AutoTagMetadataInput) (string, error) {
-	manager.GetInstance().AutoTag(input)
-	return "todo", nil
+	return jobID(manager.GetInstance().AutoTag(input)), nil
 }
 
 func (r *mutationResolver) MetadataClean(ctx context.Context, input models.CleanMetadataInput) (string, error) {
-	manager.GetInstance().Clean(input)
-	return "todo", nil
+	return jobID(manager.GetInstance().Clean(input)), nil
 }
 
 func (r *mutationResolver) MigrateHashNaming(ctx context.Context) (string, error) {
-	manager.GetInstance().MigrateHash()
-	return "todo", nil
+	return jobID(manager.GetInstance().MigrateHash()), nil
 }
 
-func (r *mutationResolver) JobStatus(ctx context.Context) (*models.MetadataUpdateStatus, error) {
-	status := manager.GetInstance().Status
-	ret := models.MetadataUpdateStatus{
-		Progress: status.Progress,
-		Status:   status.Status.String(),
-		Message:  "",
+func (r *mutationResolver) MigrateBlobs(ctx context.Context) (string, error) {
+	return jobID(manager.GetInstance().MigrateBlobs()), nil
+}
+
+func (r *mutationResolver) UploadGeneratedFiles(ctx context.Context) (string, error) {
+	if !manager.GetInstance().GeneratedStore.HasObjectStorage() {
+		return "", errors.New("object storage is not configured")
 	}
 
-	return &ret, nil
+	return jobID(manager.GetInstance().UploadGenerated()), nil
+}
+
+func (r *mutationResolver) MetadataRepackageGalleries(ctx context.Context, input models.RepackageGalleriesInput) (string, error) {
+	return jobID(manager.GetInstance().RepackageGalleries(input)), nil
+}
+
+func (r *mutationResolver) MetadataRecalculate(ctx context.Context) (string, error) {
+	return jobID(manager.GetInstance().Recalculate()), nil
 }
 
-func (r *mutationResolver) StopJob(ctx context.Context) (bool, error) {
-	return manager.GetInstance().Status.Stop(), nil
+func (r *mutationResolver) SetZipPassword(ctx context.Context, path string, password string) (bool, error) {
+	if err := manager.GetInstance().SetZipPassword(path, password); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) MetadataCheckMedia(ctx context.Context, input models.CheckMediaInput) (string, error) {
+	t, err := manager.CreateCheckMediaTask(input)
+	if err != nil {
+		return "", err
+	}
+
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) MetadataOrganize(ctx context.Context, input models.OrganizeFilesInput) (string, error) {
+	t, err := manager.CreateOrganizeTask(input)
+	if err != nil {
+		return "", err
+	}
+
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) MetadataIdentify(ctx context.Context, input models.IdentifyMetadataInput) (string, error) {
+	t, err := manager.CreateIdentifyTask(input)
+	if err != nil {
+		return "", err
+	}
+
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) JobStatus(ctx context.Context) (*models.MetadataUpdateStatus, error) {
+	return makeMetadataUpdateStatus(manager.GetInstance().CurrentStatus()), nil
+}
+
+func (r *mutationResolver) StopJob(ctx context.Context, jobID *string) (bool, error) {
+	if jobID == nil {
+		manager.GetInstance().StopAllJobs()
+		return true, nil
+	}
+
+	id, err := strconv.Atoi(*jobID)
+	if err != nil {
+		return false, err
+	}
+
+	return manager.GetInstance().StopJob(id), nil
 }
 
 func (r *mutationResolver) BackupDatabase(ctx context.Context, input models.BackupDatabaseInput) (*string, error) {
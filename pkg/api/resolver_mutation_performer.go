@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/stashapp/stash/pkg/autotag"
+	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/performer"
 	"github.com/stashapp/stash/pkg/utils"
@@ -48,13 +50,14 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 		newPerformer.Ethnicity = sql.NullString{String: *input.Ethnicity, Valid: true}
 	}
 	if input.Country != nil {
-		newPerformer.Country = sql.NullString{String: *input.Country, Valid: true}
+		newPerformer.Country = sql.NullString{String: utils.NormaliseCountry(*input.Country), Valid: true}
 	}
 	if input.EyeColor != nil {
 		newPerformer.EyeColor = sql.NullString{String: *input.EyeColor, Valid: true}
 	}
-	if input.Height != nil {
-		newPerformer.Height = sql.NullString{String: *input.Height, Valid: true}
+	newPerformer.Height, err = translateHeight(input.Height)
+	if err != nil {
+		return nil, err
 	}
 	if input.Measurements != nil {
 		newPerformer.Measurements = sql.NullString{String: *input.Measurements, Valid: true}
@@ -103,6 +106,9 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 		weight := int64(*input.Weight)
 		newPerformer.Weight = sql.NullInt64{Int64: weight, Valid: true}
 	}
+	if input.AutoTagRegex != nil {
+		newPerformer.AutoTagRegex = sql.NullString{String: *input.AutoTagRegex, Valid: true}
+	}
 
 	if err := performer.ValidateDeathDate(nil, input.Birthdate, input.DeathDate); err != nil {
 		if err != nil {
@@ -110,11 +116,19 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 		}
 	}
 
+	if err := autotag.ValidateRegex(input.AutoTagRegex); err != nil {
+		return nil, err
+	}
+
 	// Start the transaction and save the performer
 	var performer *models.Performer
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Performer()
 
+		if err := manager.ValidatePerformerName(qb, input.Name); err != nil {
+			return err
+		}
+
 		performer, err = qb.Create(newPerformer)
 		if err != nil {
 			return err
@@ -190,10 +204,16 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 	}
 
 	updatedPerformer.Birthdate = translator.sqliteDate(input.Birthdate, "birthdate")
-	updatedPerformer.Country = translator.nullString(input.Country, "country")
+	updatedPerformer.Country = translator.nullString(translateCountry(input.Country), "country")
 	updatedPerformer.EyeColor = translator.nullString(input.EyeColor, "eye_color")
 	updatedPerformer.Measurements = translator.nullString(input.Measurements, "measurements")
-	updatedPerformer.Height = translator.nullString(input.Height, "height")
+	if translator.hasField("height") {
+		height, err := translateHeight(input.Height)
+		if err != nil {
+			return nil, err
+		}
+		updatedPerformer.Height = &height
+	}
 	updatedPerformer.Ethnicity = translator.nullString(input.Ethnicity, "ethnicity")
 	updatedPerformer.FakeTits = translator.nullString(input.FakeTits, "fake_tits")
 	updatedPerformer.CareerLength = translator.nullString(input.CareerLength, "career_length")
@@ -208,6 +228,11 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 	updatedPerformer.DeathDate = translator.sqliteDate(input.DeathDate, "death_date")
 	updatedPerformer.HairColor = translator.nullString(input.HairColor, "hair_color")
 	updatedPerformer.Weight = translator.nullInt64(input.Weight, "weight")
+	updatedPerformer.AutoTagRegex = translator.nullString(input.AutoTagRegex, "auto_tag_regex")
+
+	if err := autotag.ValidateRegex(input.AutoTagRegex); err != nil {
+		return nil, err
+	}
 
 	// Start the transaction and save the p
 	var p *models.Performer
@@ -298,9 +323,15 @@ func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input models
 	updatedPerformer.URL = translator.nullString(input.URL, "url")
 	updatedPerformer.Birthdate = translator.sqliteDate(input.Birthdate, "birthdate")
 	updatedPerformer.Ethnicity = translator.nullString(input.Ethnicity, "ethnicity")
-	updatedPerformer.Country = translator.nullString(input.Country, "country")
+	updatedPerformer.Country = translator.nullString(translateCountry(input.Country), "country")
 	updatedPerformer.EyeColor = translator.nullString(input.EyeColor, "eye_color")
-	updatedPerformer.Height = translator.nullString(input.Height, "height")
+	if translator.hasField("height") {
+		height, err := translateHeight(input.Height)
+		if err != nil {
+			return nil, err
+		}
+		updatedPerformer.Height = &height
+	}
 	updatedPerformer.Measurements = translator.nullString(input.Measurements, "measurements")
 	updatedPerformer.FakeTits = translator.nullString(input.FakeTits, "fake_tits")
 	updatedPerformer.CareerLength = translator.nullString(input.CareerLength, "career_length")
@@ -389,6 +420,57 @@ func (r *mutationResolver) PerformerDestroy(ctx context.Context, input models.Pe
 	return true, nil
 }
 
+func performerRelationshipFromInput(input models.PerformerRelationshipInput) (*models.PerformerRelationship, error) {
+	performerID, err := strconv.Atoi(input.PerformerID)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedID, err := strconv.Atoi(input.RelatedID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PerformerRelationship{
+		PerformerID: performerID,
+		RelatedID:   relatedID,
+		Type:        input.Type.String(),
+	}, nil
+}
+
+func (r *mutationResolver) PerformerRelationshipAdd(ctx context.Context, input models.PerformerRelationshipInput) (bool, error) {
+	relationship, err := performerRelationshipFromInput(input)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Performer()
+		if err := performer.ValidateRelationship(*relationship, qb); err != nil {
+			return err
+		}
+
+		return qb.AddRelationship(*relationship)
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) PerformerRelationshipRemove(ctx context.Context, input models.PerformerRelationshipInput) (bool, error) {
+	relationship, err := performerRelationshipFromInput(input)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		return repo.Performer().RemoveRelationship(*relationship)
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (r *mutationResolver) PerformersDestroy(ctx context.Context, performerIDs []string) (bool, error) {
 	ids, err := utils.StringSliceToIntSlice(performerIDs)
 	if err != nil {
@@ -409,3 +491,30 @@ func (r *mutationResolver) PerformersDestroy(ctx context.Context, performerIDs [
 	}
 	return true, nil
 }
+
+// translateHeight parses the height input into centimetres. A nil or empty
+// height is returned as null. Returns an error if the height cannot be
+// parsed, rather than clearing the existing height.
+func translateHeight(value *string) (sql.NullInt64, error) {
+	if value == nil || *value == "" {
+		return sql.NullInt64{}, nil
+	}
+
+	height, err := utils.ParseHeight(*value)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+
+	return sql.NullInt64{Int64: int64(height), Valid: true}, nil
+}
+
+// translateCountry normalises the country input to an ISO 3166-1 alpha-2
+// code where it is recognised.
+func translateCountry(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	ret := utils.NormaliseCountry(*value)
+	return &ret
+}
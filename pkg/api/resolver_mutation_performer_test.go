@@ -0,0 +1,32 @@
+package api
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateHeight(t *testing.T) {
+	str := func(s string) *string {
+		return &s
+	}
+
+	tests := []struct {
+		input   *string
+		want    sql.NullInt64
+		wantErr bool
+	}{
+		{nil, sql.NullInt64{}, false},
+		{str(""), sql.NullInt64{}, false},
+		{str("180"), sql.NullInt64{Int64: 180, Valid: true}, false},
+		{str("5'11\""), sql.NullInt64{Int64: 180, Valid: true}, false},
+		{str("tall"), sql.NullInt64{}, true},
+	}
+
+	for _, tc := range tests {
+		got, err := translateHeight(tc.input)
+		assert.Equal(t, tc.wantErr, err != nil)
+		assert.Equal(t, tc.want, got)
+	}
+}
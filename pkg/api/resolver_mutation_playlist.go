@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func validatePlaylist(p models.Playlist) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+
+	return nil
+}
+
+func findPlaylist(qb models.PlaylistReader, id int) (*models.Playlist, error) {
+	ret, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, fmt.Errorf("playlist with id %d not found", id)
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlaylistCreate(ctx context.Context, input models.PlaylistCreateInput) (*models.Playlist, error) {
+	currentTime := time.Now()
+	newPlaylist := models.Playlist{
+		Name:      input.Name,
+		Shuffle:   input.Shuffle != nil && *input.Shuffle,
+		Repeat:    input.Repeat != nil && *input.Repeat,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	if err := validatePlaylist(newPlaylist); err != nil {
+		return nil, err
+	}
+
+	var ret *models.Playlist
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		sceneIDs, err := existingSceneIDs(repo, input.SceneIds)
+		if err != nil {
+			return err
+		}
+
+		qb := repo.Playlist()
+		ret, err = qb.Create(newPlaylist)
+		if err != nil {
+			return err
+		}
+
+		return qb.UpdateScenes(ret.ID, sceneIDs)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlaylistUpdate(ctx context.Context, input models.PlaylistUpdateInput) (*models.Playlist, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.Playlist
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Playlist()
+		existing, err := findPlaylist(qb, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *existing
+		if input.Name != nil {
+			updated.Name = *input.Name
+		}
+		if input.Shuffle != nil {
+			updated.Shuffle = *input.Shuffle
+		}
+		if input.Repeat != nil {
+			updated.Repeat = *input.Repeat
+		}
+
+		if err := validatePlaylist(updated); err != nil {
+			return err
+		}
+
+		if input.SceneIds != nil {
+			sceneIDs, err := existingSceneIDs(repo, input.SceneIds)
+			if err != nil {
+				return err
+			}
+
+			if err := qb.UpdateScenes(id, sceneIDs); err != nil {
+				return err
+			}
+		}
+
+		updated.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+		ret, err = qb.Update(updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlaylistAddScenes(ctx context.Context, input models.PlaylistAddScenesInput) (*models.Playlist, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.Playlist
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Playlist()
+		existing, err := findPlaylist(qb, id)
+		if err != nil {
+			return err
+		}
+
+		toAdd, err := existingSceneIDs(repo, input.SceneIds)
+		if err != nil {
+			return err
+		}
+
+		sceneIDs, err := qb.GetSceneIDs(id)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.UpdateScenes(id, utils.IntAppendUniques(sceneIDs, toAdd)); err != nil {
+			return err
+		}
+
+		updated := *existing
+		updated.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+		ret, err = qb.Update(updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlaylistDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		return repo.Playlist().Destroy(idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
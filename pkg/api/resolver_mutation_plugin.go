@@ -35,8 +35,7 @@ func (r *mutationResolver) RunPluginTask(ctx context.Context, pluginID string, t
 		serverConnection.Scheme = "https"
 	}
 
-	manager.GetInstance().RunPluginTask(pluginID, taskName, args, serverConnection)
-	return "todo", nil
+	return jobID(manager.GetInstance().RunPluginTask(pluginID, taskName, args, serverConnection)), nil
 }
 
 func (r *mutationResolver) ReloadPlugins(ctx context.Context) (bool, error) {
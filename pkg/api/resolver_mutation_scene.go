@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/stashapp/stash/pkg/chromecast"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
@@ -75,6 +76,9 @@ func (r *mutationResolver) sceneUpdate(input models.SceneUpdateInput, translator
 	updatedScene.URL = translator.nullString(input.URL, "url")
 	updatedScene.Date = translator.sqliteDate(input.Date, "date")
 	updatedScene.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedScene.Location = translator.nullString(input.Location, "location")
+	updatedScene.Latitude = translator.nullFloat64(input.Latitude, "latitude")
+	updatedScene.Longitude = translator.nullFloat64(input.Longitude, "longitude")
 	updatedScene.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedScene.Organized = input.Organized
 
@@ -220,6 +224,9 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input models.Bul
 	updatedScene.URL = translator.nullString(input.URL, "url")
 	updatedScene.Date = translator.sqliteDate(input.Date, "date")
 	updatedScene.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedScene.Location = translator.nullString(input.Location, "location")
+	updatedScene.Latitude = translator.nullFloat64(input.Latitude, "latitude")
+	updatedScene.Longitude = translator.nullFloat64(input.Longitude, "longitude")
 	updatedScene.StudioID = translator.nullInt64FromString(input.StudioID, "studio_id")
 	updatedScene.Organized = input.Organized
 
@@ -352,6 +359,22 @@ func adjustSceneGalleryIDs(qb models.SceneReader, sceneID int, ids models.BulkUp
 	return adjustIDs(ret, ids), nil
 }
 
+// existingSceneIDs converts the provided scene ids, returning an error if
+// any scene does not exist. Duplicate scenes are removed, keeping the first.
+func existingSceneIDs(repo models.Repository, ids []string) ([]int, error) {
+	sceneIDs, err := utils.StringSliceToIntSlice(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	sceneIDs = utils.IntAppendUniques(nil, sceneIDs)
+	if _, err := repo.Scene().FindMany(sceneIDs); err != nil {
+		return nil, err
+	}
+
+	return sceneIDs, nil
+}
+
 func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneDestroyInput) (bool, error) {
 	sceneID, err := strconv.Atoi(input.ID)
 	if err != nil {
@@ -648,10 +671,103 @@ func (r *mutationResolver) SceneResetO(ctx context.Context, id string) (ret int,
 
 func (r *mutationResolver) SceneGenerateScreenshot(ctx context.Context, id string, at *float64) (string, error) {
 	if at != nil {
-		manager.GetInstance().GenerateScreenshot(id, *at)
-	} else {
-		manager.GetInstance().GenerateDefaultScreenshot(id)
+		return jobID(manager.GetInstance().GenerateScreenshot(id, *at)), nil
 	}
 
-	return "todo", nil
+	return jobID(manager.GetInstance().GenerateDefaultScreenshot(id)), nil
+}
+
+func (r *mutationResolver) SceneSetCoverFromFrame(ctx context.Context, id string, at float64) (*models.Scene, error) {
+	sceneID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var scene *models.Scene
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		scene, err = repo.Scene().Find(sceneID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if scene == nil {
+		return nil, fmt.Errorf("scene with id %d not found", sceneID)
+	}
+
+	coverImageData, err := manager.CaptureSceneFrame(scene, at, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.Scene
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.Scene()
+		if err := qb.UpdateCover(sceneID, coverImageData); err != nil {
+			return err
+		}
+
+		// update the scene with the update date, so that the new screenshot
+		// isn't cached
+		ret, err = qb.Update(models.ScenePartial{
+			ID:        sceneID,
+			UpdatedAt: &models.SQLiteTimestamp{Timestamp: time.Now()},
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := manager.SetSceneScreenshot(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()), coverImageData); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) CastScene(ctx context.Context, input models.CastSceneInput) (bool, error) {
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return false, err
+	}
+
+	var scene *models.Scene
+	var captions []*models.SceneCaption
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		scene, err = repo.Scene().Find(sceneID)
+		if err != nil || scene == nil {
+			return err
+		}
+
+		captions, err = repo.Scene().GetCaptions(sceneID)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	if scene == nil {
+		return false, fmt.Errorf("scene with id %d not found", sceneID)
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	baseURL, err = castBaseURL(baseURL, input.Host)
+	if err != nil {
+		return false, err
+	}
+
+	media := getSceneCastMedia(scene, captions, baseURL, config.GetInstance().GetAPIKey())
+	if input.StartTime != nil {
+		media.StartTime = *input.StartTime
+	}
+
+	port := 0
+	if input.Port != nil {
+		port = *input.Port
+	}
+
+	if err := chromecast.NewClient(input.Host, port).Load(ctx, media); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
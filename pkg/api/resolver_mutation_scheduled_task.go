@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func validateScheduledTask(t models.ScheduledTask) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+
+	if !t.Task.IsValid() {
+		return fmt.Errorf("invalid task type %s", t.Task)
+	}
+
+	return manager.ValidateSchedule(t.Schedule)
+}
+
+func (r *mutationResolver) ScheduledTaskCreate(ctx context.Context, input models.ScheduledTaskCreateInput) (*models.ScheduledTask, error) {
+	currentTime := time.Now()
+	newTask := models.ScheduledTask{
+		Name:      input.Name,
+		Task:      input.Task,
+		Schedule:  input.Schedule,
+		Enabled:   input.Enabled == nil || *input.Enabled,
+		CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	if err := validateScheduledTask(newTask); err != nil {
+		return nil, err
+	}
+
+	var ret *models.ScheduledTask
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		var err error
+		ret, err = repo.ScheduledTask().Create(newTask)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) ScheduledTaskUpdate(ctx context.Context, input models.ScheduledTaskUpdateInput) (*models.ScheduledTask, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.ScheduledTask
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		qb := repo.ScheduledTask()
+		existing, err := qb.Find(id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("scheduled task with id %d not found", id)
+		}
+
+		updated := *existing
+		if input.Name != nil {
+			updated.Name = *input.Name
+		}
+		if input.Task != nil {
+			updated.Task = *input.Task
+		}
+		if input.Schedule != nil {
+			updated.Schedule = *input.Schedule
+		}
+		if input.Enabled != nil {
+			updated.Enabled = *input.Enabled
+		}
+
+		if updated == *existing {
+			ret = existing
+			return nil
+		}
+
+		if err := validateScheduledTask(updated); err != nil {
+			return err
+		}
+
+		// the schedule applies from now, so that changing the task doesn't
+		// run it immediately for missed runs. See manager.NextScheduledRun.
+		updated.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		ret, err = qb.Update(updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) ScheduledTaskDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(repo models.Repository) error {
+		return repo.ScheduledTask().Destroy(idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
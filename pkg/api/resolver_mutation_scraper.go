@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 func (r *mutationResolver) ReloadScrapers(ctx context.Context) (bool, error) {
@@ -15,3 +16,43 @@ func (r *mutationResolver) ReloadScrapers(ctx context.Context) (bool, error) {
 
 	return true, nil
 }
+
+func (r *mutationResolver) ValidateScrapers(ctx context.Context) ([]*models.ScraperLoadError, error) {
+	cache := manager.GetInstance().ScraperCache
+	if err := cache.ReloadScrapers(); err != nil {
+		return nil, err
+	}
+
+	ret := cache.LoadErrors()
+	if ret == nil {
+		ret = []*models.ScraperLoadError{}
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) InstallScraperPackages(ctx context.Context, input models.InstallScraperPackagesInput) (string, error) {
+	t, err := manager.CreateInstallScraperPackagesTask(input)
+	if err != nil {
+		return "", err
+	}
+
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) UpdateScraperPackages(ctx context.Context, ids []string) (string, error) {
+	t := manager.CreateUpdateScraperPackagesTask(ids)
+	return jobID(manager.GetInstance().RunSingleTask(t)), nil
+}
+
+func (r *mutationResolver) UninstallScraperPackages(ctx context.Context, ids []string) (bool, error) {
+	if err := manager.ScraperPackageManager().Uninstall(ids); err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().ScraperCache.ReloadScrapers(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
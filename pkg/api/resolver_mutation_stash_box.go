@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
@@ -22,7 +23,44 @@ func (r *mutationResolver) SubmitStashBoxFingerprints(ctx context.Context, input
 	return client.SubmitStashBoxFingerprints(input.SceneIds, boxes[input.StashBoxIndex].Endpoint)
 }
 
+func (r *mutationResolver) SubmitStashBoxSceneDraft(ctx context.Context, input models.StashBoxDraftSubmissionInput) (*string, error) {
+	client, err := draftClient(input, r.txnManager)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SubmitSceneDraft(id)
+}
+
+func (r *mutationResolver) SubmitStashBoxPerformerDraft(ctx context.Context, input models.StashBoxDraftSubmissionInput) (*string, error) {
+	client, err := draftClient(input, r.txnManager)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SubmitPerformerDraft(id)
+}
+
+func draftClient(input models.StashBoxDraftSubmissionInput, txnManager models.TransactionManager) (*stashbox.Client, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return nil, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	return stashbox.NewClient(*boxes[input.StashBoxIndex], txnManager), nil
+}
+
 func (r *mutationResolver) StashBoxBatchPerformerTag(ctx context.Context, input models.StashBoxBatchPerformerTagInput) (string, error) {
-	manager.GetInstance().StashBoxBatchPerformerTag(input)
-	return "todo", nil
+	return jobID(manager.GetInstance().StashBoxBatchPerformerTag(input)), nil
 }
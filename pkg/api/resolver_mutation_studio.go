@@ -50,12 +50,19 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 	if input.Details != nil {
 		newStudio.Details = sql.NullString{String: *input.Details, Valid: true}
 	}
+	if input.Country != nil {
+		newStudio.Country = sql.NullString{String: utils.NormaliseCountry(*input.Country), Valid: true}
+	}
 
 	// Start the transaction and save the studio
 	var studio *models.Studio
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
 		qb := repo.Studio()
 
+		if err := manager.ValidateStudioName(qb, input.Name); err != nil {
+			return err
+		}
+
 		var err error
 		studio, err = qb.Create(newStudio)
 		if err != nil {
@@ -121,6 +128,7 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 	updatedStudio.Details = translator.nullString(input.Details, "details")
 	updatedStudio.ParentID = translator.nullInt64FromString(input.ParentID, "parent_id")
 	updatedStudio.Rating = translator.nullInt64(input.Rating, "rating")
+	updatedStudio.Country = translator.nullString(translateCountry(input.Country), "country")
 
 	// Start the transaction and save the studio
 	var studio *models.Studio
@@ -2,10 +2,12 @@ package api
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/stashapp/stash/pkg/autotag"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -20,6 +22,14 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input models.TagCreate
 		UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
 	}
 
+	if input.AutoTagRegex != nil {
+		newTag.AutoTagRegex = sql.NullString{String: *input.AutoTagRegex, Valid: true}
+	}
+
+	if err := autotag.ValidateRegex(input.AutoTagRegex); err != nil {
+		return nil, err
+	}
+
 	var imageData []byte
 	var err error
 
@@ -89,6 +99,10 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input models.TagUpdate
 		}
 	}
 
+	if err := autotag.ValidateRegex(input.AutoTagRegex); err != nil {
+		return nil, err
+	}
+
 	// Start the transaction and save the tag
 	var tag *models.Tag
 	if err := r.withTxn(ctx, func(repo models.Repository) error {
@@ -110,6 +124,13 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input models.TagUpdate
 			}
 		}
 
+		// tags are updated in full, so unset fields keep their existing value
+		if autoTagRegex := translator.nullString(input.AutoTagRegex, "auto_tag_regex"); autoTagRegex != nil {
+			updatedTag.AutoTagRegex = *autoTagRegex
+		} else {
+			updatedTag.AutoTagRegex = existing.AutoTagRegex
+		}
+
 		tag, err = qb.Update(updatedTag)
 		if err != nil {
 			return err
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"time"
 
+	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -43,6 +45,14 @@ func makeConfigGeneralResult() *models.ConfigGeneralResult {
 	scraperUserAgent := config.GetScraperUserAgent()
 	scraperCDPPath := config.GetScraperCDPPath()
 
+	var liveTranscodeProfile, previewTranscodeProfile *string
+	if p := config.GetLiveTranscodeProfile(); p != nil {
+		liveTranscodeProfile = &p.Name
+	}
+	if p := config.GetPreviewTranscodeProfile(); p != nil {
+		previewTranscodeProfile = &p.Name
+	}
+
 	return &models.ConfigGeneralResult{
 		Stashes:                    config.GetStashPaths(),
 		DatabasePath:               config.GetDatabasePath(),
@@ -50,14 +60,33 @@ func makeConfigGeneralResult() *models.ConfigGeneralResult {
 		ConfigFilePath:             config.GetConfigFilePath(),
 		ScrapersPath:               config.GetScrapersPath(),
 		CachePath:                  config.GetCachePath(),
+		BlobsStorage:               config.GetBlobsStorage(),
+		BlobsPath:                  config.GetBlobsPath(),
 		CalculateMd5:               config.IsCalculateMD5(),
 		VideoFileNamingAlgorithm:   config.GetVideoFileNamingAlgorithm(),
 		ParallelTasks:              config.GetParallelTasks(),
+		HashWorkers:                config.GetHashWorkers(),
+		ProbeWorkers:               config.GetProbeWorkers(),
+		GenerateWorkers:            config.GetGenerateWorkers(),
+		ThumbnailWorkers:           config.GetThumbnailWorkers(),
+		ThumbnailSizes:             config.GetThumbnailSizes(),
+		ThumbnailCacheSize:         int(config.GetThumbnailCacheSize() / (1024 * 1024)),
+		ImportWorkers:              config.GetImportWorkers(),
+		BackupInterval:             int(config.GetBackupInterval() / time.Hour),
+		BackupPath:                 config.GetBackupPath(),
+		BackupCount:                config.GetBackupCount(),
+		BackupIncludeExport:        config.GetBackupIncludeExport(),
 		PreviewSegments:            config.GetPreviewSegments(),
 		PreviewSegmentDuration:     config.GetPreviewSegmentDuration(),
 		PreviewExcludeStart:        config.GetPreviewExcludeStart(),
 		PreviewExcludeEnd:          config.GetPreviewExcludeEnd(),
 		PreviewPreset:              config.GetPreviewPreset(),
+		PreviewImageQuality:        config.GetPreviewImageQuality(),
+		PreviewImageFps:            config.GetPreviewImageFps(),
+		SpriteRows:                 config.GetSpriteRows(),
+		SpriteColumns:              config.GetSpriteColumns(),
+		SpriteWidth:                config.GetSpriteWidth(),
+		SpriteInterval:             config.GetSpriteInterval(),
 		MaxTranscodeSize:           &maxTranscodeSize,
 		MaxStreamingTranscodeSize:  &maxStreamingTranscodeSize,
 		APIKey:                     config.GetAPIKey(),
@@ -68,7 +97,19 @@ func makeConfigGeneralResult() *models.ConfigGeneralResult {
 		LogOut:                     config.GetLogOut(),
 		LogLevel:                   config.GetLogLevel(),
 		LogAccess:                  config.GetLogAccess(),
+		MaxSessionBandwidth:        int(config.GetMaxSessionBandwidth() >> 10),
+		MaxGlobalBandwidth:         int(config.GetMaxGlobalBandwidth() >> 10),
+		MaxRequestsPerMinute:       config.GetMaxRequestsPerMinute(),
+		LoginMaxAttempts:           config.GetLoginMaxAttempts(),
+		LoginLockoutDuration:       int(config.GetLoginLockoutDuration() / time.Second),
+		MaxQueryComplexity:         config.GetMaxQueryComplexity(),
+		MaxQueryDepth:              config.GetMaxQueryDepth(),
+		APIKeyMaxQueryComplexity:   config.GetAPIKeyMaxQueryComplexity(),
+		APIKeyMaxQueryDepth:        config.GetAPIKeyMaxQueryDepth(),
+		QueryProfiling:             config.GetQueryProfiling(),
+		SlowQueryThreshold:         config.GetSlowQueryThreshold(),
 		VideoExtensions:            config.GetVideoExtensions(),
+		AudioExtensions:            config.GetAudioExtensions(),
 		ImageExtensions:            config.GetImageExtensions(),
 		GalleryExtensions:          config.GetGalleryExtensions(),
 		CreateGalleriesFromFolders: config.GetCreateGalleriesFromFolders(),
@@ -77,7 +118,29 @@ func makeConfigGeneralResult() *models.ConfigGeneralResult {
 		ScraperUserAgent:           &scraperUserAgent,
 		ScraperCertCheck:           config.GetScraperCertCheck(),
 		ScraperCDPPath:             &scraperCDPPath,
+		ScraperCacheTTL:            int(config.GetScraperCacheTTL() / time.Minute),
+		ScraperRequestInterval:     int(config.GetScraperRequestInterval() / time.Millisecond),
+		ScraperRequestJitter:       int(config.GetScraperRequestJitter() / time.Millisecond),
+		ScraperMaxConcurrency:      config.GetScraperMaxConcurrency(),
+		ScraperSources:             config.GetScraperSources(),
+		ScraperPackageSources:      config.GetScraperPackageSources(),
 		StashBoxes:                 config.GetStashBoxes(),
+		Webhooks:                   config.GetWebhooks(),
+		NotificationSinks:          config.GetNotificationSinks(),
+		ZipPasswords:               config.GetZipPasswords(),
+		ObjectStorage:              config.GetObjectStorage(),
+		DuplicateNamePolicy:        config.GetDuplicateNamePolicy(),
+		AutoTagSeparators:          config.GetAutoTagSeparators(),
+		ReadOnly:                   config.IsReadOnly(),
+
+		HardwareAcceleration:          config.GetHardwareAcceleration(),
+		AvailableHardwareAcceleration: manager.GetInstance().HWAccels,
+		MaxTranscodeProcesses:         config.GetMaxTranscodeProcesses(),
+		MaxGenerateProcesses:          config.GetMaxGenerateProcesses(),
+
+		TranscodeProfiles:       config.GetTranscodeProfiles(),
+		LiveTranscodeProfile:    liveTranscodeProfile,
+		PreviewTranscodeProfile: previewTranscodeProfile,
 	}
 }
 
@@ -94,6 +157,8 @@ func makeConfigInterfaceResult() *models.ConfigInterfaceResult {
 	cssEnabled := config.GetCSSEnabled()
 	language := config.GetLanguage()
 	slideshowDelay := config.GetSlideshowDelay()
+	funscriptOffset := config.GetFunscriptOffset()
+	handyKey := config.GetHandyKey()
 
 	return &models.ConfigInterfaceResult{
 		MenuItems:           menuItems,
@@ -107,5 +172,7 @@ func makeConfigInterfaceResult() *models.ConfigInterfaceResult {
 		CSSEnabled:          &cssEnabled,
 		Language:            &language,
 		SlideshowDelay:      &slideshowDelay,
+		FunscriptOffset:     &funscriptOffset,
+		HandyKey:            &handyKey,
 	}
 }
@@ -25,7 +25,16 @@ func (r *queryResolver) FindGallery(ctx context.Context, id string) (ret *models
 
 func (r *queryResolver) FindGalleries(ctx context.Context, galleryFilter *models.GalleryFilterType, filter *models.FindFilterType) (ret *models.FindGalleriesResultType, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
-		galleries, total, err := repo.Gallery().Query(galleryFilter, filter)
+		var galleries []*models.Gallery
+		var total int
+		var err error
+
+		if countOnly(ctx) {
+			_, total, err = repo.Gallery().QueryIDs(galleryFilter, filter)
+		} else {
+			galleries, total, err = repo.Gallery().Query(galleryFilter, filter)
+		}
+
 		if err != nil {
 			return err
 		}
@@ -36,7 +36,16 @@ func (r *queryResolver) FindImage(ctx context.Context, id *string, checksum *str
 func (r *queryResolver) FindImages(ctx context.Context, imageFilter *models.ImageFilterType, imageIds []int, filter *models.FindFilterType) (ret *models.FindImagesResultType, err error) {
 	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
 		qb := repo.Image()
-		images, total, err := qb.Query(imageFilter, filter)
+		var images []*models.Image
+		var total int
+		var err error
+
+		if countOnly(ctx) {
+			_, total, err = qb.QueryIDs(imageFilter, filter)
+		} else {
+			images, total, err = qb.Query(imageFilter, filter)
+		}
+
 		if err != nil {
 			return err
 		}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/stashapp/stash/pkg/models"
@@ -52,3 +53,31 @@ func (r *queryResolver) AllPerformers(ctx context.Context) (ret []*models.Perfor
 
 	return ret, nil
 }
+
+// maxRelatedPerformersDepth is the maximum number of relationships followed
+// when finding related performers.
+const maxRelatedPerformersDepth = 5
+
+func (r *queryResolver) FindRelatedPerformers(ctx context.Context, performerID string, types []models.PerformerRelationshipType, depth *int) (ret []*models.Performer, err error) {
+	idInt, err := strconv.Atoi(performerID)
+	if err != nil {
+		return nil, err
+	}
+
+	depthInt := 1
+	if depth != nil {
+		depthInt = *depth
+	}
+	if depthInt < 1 || depthInt > maxRelatedPerformersDepth {
+		return nil, fmt.Errorf("depth must be between 1 and %d", maxRelatedPerformersDepth)
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Performer().FindRelated(idInt, types, depthInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
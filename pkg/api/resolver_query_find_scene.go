@@ -70,6 +70,8 @@ func (r *queryResolver) FindScenes(ctx context.Context, sceneFilter *models.Scen
 			if err == nil {
 				total = len(scenes)
 			}
+		} else if countOnly(ctx) {
+			_, total, err = repo.Scene().QueryIDs(sceneFilter, filter)
 		} else {
 			scenes, total, err = repo.Scene().Query(sceneFilter, filter)
 		}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// queryContext returns a context for resolving the first root field of query.
+func queryContext(t *testing.T, query string) context.Context {
+	es := models.NewExecutableSchema(models.Config{Resolvers: &Resolver{}})
+	doc, err := gqlparser.LoadQuery(es.Schema(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := doc.Operations[0].SelectionSet[0].(*ast.Field)
+	ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{Doc: doc})
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Field: graphql.CollectedField{Field: field, Selections: field.SelectionSet},
+	})
+}
+
+func TestFindScenesCountOnly(t *testing.T) {
+	r := newResolver()
+	qb := r.txnManager.(*mocks.TransactionManager).Scene().(*mocks.SceneReaderWriter)
+	qb.On("QueryIDs", (*models.SceneFilterType)(nil), (*models.FindFilterType)(nil)).Return([]int{1, 2}, 2, nil).Once()
+
+	ctx := queryContext(t, `query { findScenes { count } }`)
+	ret, err := r.Query().FindScenes(ctx, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, ret.Count)
+	assert.Empty(t, ret.Scenes)
+
+	scenes := []*models.Scene{{ID: 1}, {ID: 2}}
+	qb.On("Query", (*models.SceneFilterType)(nil), (*models.FindFilterType)(nil)).Return(scenes, 2, nil).Once()
+
+	ctx = queryContext(t, `query { findScenes { count scenes { id } } }`)
+	ret, err = r.Query().FindScenes(ctx, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, ret.Count)
+	assert.Equal(t, scenes, ret.Scenes)
+
+	qb.AssertExpectations(t)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// default number of finished jobs returned by the job history query
+const defaultJobHistoryLimit = 50
+
+func jobID(j *manager.Job) string {
+	return strconv.Itoa(j.ID)
+}
+
+func makeJob(j manager.Job) *models.Job {
+	status := j.Status
+	ret := &models.Job{
+		ID:       jobID(&j),
+		Type:     status.Status.String(),
+		State:    j.State,
+		Priority: j.Priority,
+		Progress: status.Progress,
+		Message:  status.Message,
+		AddedAt:  j.AddedAt,
+	}
+
+	if ret.State == models.JobStateRunning && status.IsStopping() {
+		ret.State = models.JobStateStopping
+	}
+
+	if !j.StartedAt.IsZero() {
+		ret.StartedAt = &j.StartedAt
+	}
+
+	if status.Phase != "" {
+		ret.Phase = &status.Phase
+	}
+
+	if status.ObjectsTotal > 0 {
+		ret.ObjectsDone = &status.ObjectsDone
+		ret.ObjectsTotal = &status.ObjectsTotal
+	}
+
+	if status.ETA > 0 {
+		eta := int(math.Ceil(status.ETA.Seconds()))
+		ret.Eta = &eta
+	}
+
+	return ret
+}
+
+func makeJobs(jobs []manager.Job) []*models.Job {
+	ret := []*models.Job{}
+	for _, j := range jobs {
+		ret = append(ret, makeJob(j))
+	}
+
+	return ret
+}
+
+func (r *queryResolver) JobQueue(ctx context.Context) ([]*models.Job, error) {
+	return makeJobs(manager.GetInstance().Jobs()), nil
+}
+
+func (r *queryResolver) JobHistory(ctx context.Context, limit *int) (ret []*models.JobHistoryEntry, err error) {
+	l := defaultJobHistoryLimit
+	if limit != nil {
+		l = *limit
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.JobHistory().Recent(l)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
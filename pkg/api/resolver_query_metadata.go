@@ -2,20 +2,50 @@ package api
 
 import (
 	"context"
+	"math"
 
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 )
 
 func (r *queryResolver) JobStatus(ctx context.Context) (*models.MetadataUpdateStatus, error) {
-	status := manager.GetInstance().Status
-	ret := models.MetadataUpdateStatus{
+	return makeMetadataUpdateStatus(manager.GetInstance().CurrentStatus()), nil
+}
+
+func makeMetadataUpdateStatus(status manager.TaskStatus) *models.MetadataUpdateStatus {
+	ret := &models.MetadataUpdateStatus{
 		Progress: status.Progress,
 		Status:   status.Status.String(),
-		Message:  "",
+		Message:  status.Message,
+	}
+
+	if status.Phase != "" {
+		ret.Phase = &status.Phase
+	}
+
+	if status.ObjectsTotal > 0 {
+		ret.ObjectsDone = &status.ObjectsDone
+		ret.ObjectsTotal = &status.ObjectsTotal
+	}
+
+	if status.ETA > 0 {
+		eta := int(math.Ceil(status.ETA.Seconds()))
+		ret.Eta = &eta
 	}
 
-	return &ret, nil
+	return ret
+}
+
+func (r *queryResolver) LastImportReport(ctx context.Context) (*models.ImportReport, error) {
+	return manager.GetInstance().LastImportReport()
+}
+
+func (r *queryResolver) LastCleanReport(ctx context.Context) (*models.CleanReport, error) {
+	return manager.GetInstance().LastCleanReport()
+}
+
+func (r *queryResolver) LockedZipFiles(ctx context.Context) ([]string, error) {
+	return manager.LockedZipFiles(), nil
 }
 
 func (r *queryResolver) SystemStatus(ctx context.Context) (*models.SystemStatus, error) {
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/playlist"
+)
+
+func (r *queryResolver) FindPlaylist(ctx context.Context, id string) (ret *models.Playlist, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Playlist().Find(idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) AllPlaylists(ctx context.Context) (ret []*models.Playlist, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.Playlist().All()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) PlaylistNext(ctx context.Context, input models.PlaylistNextInput) (ret *models.Scene, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentID *int
+	if input.SceneID != nil {
+		sceneID, err := strconv.Atoi(*input.SceneID)
+		if err != nil {
+			return nil, err
+		}
+		currentID = &sceneID
+	}
+
+	var seed int64
+	if input.Seed != nil {
+		seed = int64(*input.Seed)
+	}
+
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		p, err := findPlaylist(repo.Playlist(), id)
+		if err != nil {
+			return err
+		}
+
+		sceneIDs, err := repo.Playlist().GetSceneIDs(id)
+		if err != nil {
+			return err
+		}
+
+		nextID, found := playlist.Next(p, sceneIDs, currentID, seed)
+		if !found {
+			return nil
+		}
+
+		ret, err = repo.Scene().Find(nextID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
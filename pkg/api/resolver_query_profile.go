@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (r *queryResolver) QueryProfile(ctx context.Context) ([]*models.QueryProfileEntry, error) {
+	profile := sqlite.GetQueryProfile()
+	ret := make([]*models.QueryProfileEntry, len(profile))
+
+	for i, s := range profile {
+		var mean time.Duration
+		if s.Count > 0 {
+			mean = s.TotalDuration / time.Duration(s.Count)
+		}
+
+		ret[i] = &models.QueryProfileEntry{
+			Query:     s.Query,
+			Count:     s.Count,
+			SlowCount: s.SlowCount,
+			TotalMs:   durationToMs(s.TotalDuration),
+			MeanMs:    durationToMs(mean),
+			MaxMs:     durationToMs(s.MaxDuration),
+		}
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) ResetQueryProfile(ctx context.Context) (bool, error) {
+	sqlite.ResetQueryProfile()
+	return true, nil
+}
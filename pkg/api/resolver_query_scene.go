@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/pkg/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/chromecast"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
@@ -32,3 +33,24 @@ func (r *queryResolver) SceneStreams(ctx context.Context, id *string) ([]*models
 
 	return manager.GetSceneStreamPaths(scene, builder.GetStreamURL(), config.GetInstance().GetMaxStreamingTranscodeSize())
 }
+
+func (r *queryResolver) CastDevices(ctx context.Context) ([]*models.CastDevice, error) {
+	devices, err := chromecast.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*models.CastDevice{}
+	for _, d := range devices {
+		model := d.Model
+		ret = append(ret, &models.CastDevice{
+			ID:    d.ID,
+			Name:  d.Name,
+			Model: &model,
+			Host:  d.Host,
+			Port:  d.Port,
+		})
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) AllScheduledTasks(ctx context.Context) (ret []*models.ScheduledTask, err error) {
+	if err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+		ret, err = repo.ScheduledTask().All()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/scraper/packages"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
 )
 
@@ -52,40 +53,132 @@ func (r *queryResolver) ListMovieScrapers(ctx context.Context) ([]*models.Scrape
 	return manager.GetInstance().ScraperCache.ListMovieScrapers(), nil
 }
 
-func (r *queryResolver) ScrapePerformerList(ctx context.Context, scraperID string, query string) ([]*models.ScrapedPerformer, error) {
+func (r *queryResolver) ScraperTraces(ctx context.Context, scraperID string) ([]*models.ScraperTrace, error) {
+	return manager.GetInstance().ScraperCache.Traces(scraperID), nil
+}
+
+func (r *queryResolver) AvailableScraperPackages(ctx context.Context, sourceURL string) ([]*models.ScraperPackage, error) {
+	if packages.FindSource(config.GetInstance().GetScraperPackageSources(), sourceURL) == nil {
+		return nil, fmt.Errorf("scraper package source %s is not configured", sourceURL)
+	}
+
+	available, err := manager.ScraperPackageManager().ListAvailable(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*models.ScraperPackage{}
+	for _, p := range available {
+		requires := p.Requires
+		if requires == nil {
+			requires = []string{}
+		}
+
+		ret = append(ret, &models.ScraperPackage{
+			ID:       p.ID,
+			Name:     p.Name,
+			Version:  optionalString(p.Version),
+			Date:     optionalString(p.Date),
+			Requires: requires,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) InstalledScraperPackages(ctx context.Context, checkUpdates *bool) ([]*models.InstalledScraperPackage, error) {
+	m := manager.ScraperPackageManager()
+	installed, err := m.ListInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	availableVersions := make(map[string]string)
+	if checkUpdates != nil && *checkUpdates {
+		updates, err := m.Updates(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range updates {
+			availableVersions[u.Installed.ID] = u.Available.Version
+		}
+	}
+
+	ret := []*models.InstalledScraperPackage{}
+	for _, p := range installed {
+		i := &models.InstalledScraperPackage{
+			ID:        p.ID,
+			Name:      p.Name,
+			Version:   optionalString(p.Version),
+			Date:      optionalString(p.Date),
+			SourceURL: p.SourceURL,
+		}
+
+		if v, found := availableVersions[p.ID]; found {
+			i.AvailableVersion = &v
+		}
+
+		ret = append(ret, i)
+	}
+
+	return ret, nil
+}
+
+// optionalString returns nil if s is empty.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// scraperCache returns the scraper cache, which does not use cached
+// responses if bypassCache is true.
+func scraperCache(bypassCache *bool) *scraper.Cache {
+	ret := manager.GetInstance().ScraperCache
+	if bypassCache != nil && *bypassCache {
+		return ret.Uncached()
+	}
+
+	return ret
+}
+
+func (r *queryResolver) ScrapePerformerList(ctx context.Context, scraperID string, query string, bypassCache *bool) ([]*models.ScrapedPerformer, error) {
 	if query == "" {
 		return nil, nil
 	}
 
-	return manager.GetInstance().ScraperCache.ScrapePerformerList(scraperID, query)
+	return scraperCache(bypassCache).ScrapePerformerList(scraperID, query)
 }
 
-func (r *queryResolver) ScrapePerformer(ctx context.Context, scraperID string, scrapedPerformer models.ScrapedPerformerInput) (*models.ScrapedPerformer, error) {
-	return manager.GetInstance().ScraperCache.ScrapePerformer(scraperID, scrapedPerformer)
+func (r *queryResolver) ScrapePerformer(ctx context.Context, scraperID string, scrapedPerformer models.ScrapedPerformerInput, bypassCache *bool) (*models.ScrapedPerformer, error) {
+	return scraperCache(bypassCache).ScrapePerformer(scraperID, scrapedPerformer)
 }
 
-func (r *queryResolver) ScrapePerformerURL(ctx context.Context, url string) (*models.ScrapedPerformer, error) {
-	return manager.GetInstance().ScraperCache.ScrapePerformerURL(url)
+func (r *queryResolver) ScrapePerformerURL(ctx context.Context, url string, bypassCache *bool) (*models.ScrapedPerformer, error) {
+	return scraperCache(bypassCache).ScrapePerformerURL(url)
 }
 
-func (r *queryResolver) ScrapeScene(ctx context.Context, scraperID string, scene models.SceneUpdateInput) (*models.ScrapedScene, error) {
-	return manager.GetInstance().ScraperCache.ScrapeScene(scraperID, scene)
+func (r *queryResolver) ScrapeScene(ctx context.Context, scraperID string, scene models.SceneUpdateInput, bypassCache *bool) (*models.ScrapedScene, error) {
+	return scraperCache(bypassCache).ScrapeScene(scraperID, scene)
 }
 
-func (r *queryResolver) ScrapeSceneURL(ctx context.Context, url string) (*models.ScrapedScene, error) {
-	return manager.GetInstance().ScraperCache.ScrapeSceneURL(url)
+func (r *queryResolver) ScrapeSceneURL(ctx context.Context, url string, bypassCache *bool) (*models.ScrapedScene, error) {
+	return scraperCache(bypassCache).ScrapeSceneURL(url)
 }
 
-func (r *queryResolver) ScrapeGallery(ctx context.Context, scraperID string, gallery models.GalleryUpdateInput) (*models.ScrapedGallery, error) {
-	return manager.GetInstance().ScraperCache.ScrapeGallery(scraperID, gallery)
+func (r *queryResolver) ScrapeGallery(ctx context.Context, scraperID string, gallery models.GalleryUpdateInput, bypassCache *bool) (*models.ScrapedGallery, error) {
+	return scraperCache(bypassCache).ScrapeGallery(scraperID, gallery)
 }
 
-func (r *queryResolver) ScrapeGalleryURL(ctx context.Context, url string) (*models.ScrapedGallery, error) {
-	return manager.GetInstance().ScraperCache.ScrapeGalleryURL(url)
+func (r *queryResolver) ScrapeGalleryURL(ctx context.Context, url string, bypassCache *bool) (*models.ScrapedGallery, error) {
+	return scraperCache(bypassCache).ScrapeGalleryURL(url)
 }
 
-func (r *queryResolver) ScrapeMovieURL(ctx context.Context, url string) (*models.ScrapedMovie, error) {
-	return manager.GetInstance().ScraperCache.ScrapeMovieURL(url)
+func (r *queryResolver) ScrapeMovieURL(ctx context.Context, url string, bypassCache *bool) (*models.ScrapedMovie, error) {
+	return scraperCache(bypassCache).ScrapeMovieURL(url)
 }
 
 func (r *queryResolver) QueryStashBoxScene(ctx context.Context, input models.StashBoxSceneQueryInput) ([]*models.ScrapedScene, error) {
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	tagUsageCacheDuration = 5 * time.Minute
+
+	defaultTrendingTagDays  = 7
+	defaultTrendingTagLimit = 10
+)
+
+type tagUsageCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// tagUsageCache caches the results of the tag usage queries, since they
+// aggregate over all scenes and the results are not expected to change
+// quickly.
+type tagUsageCache struct {
+	mutex   sync.Mutex
+	entries map[string]tagUsageCacheEntry
+}
+
+var tagUsage = &tagUsageCache{
+	entries: make(map[string]tagUsageCacheEntry),
+}
+
+// get returns the cached value for the key if it has not expired.
+// Otherwise, it calls fn and caches the result if fn was successful.
+func (c *tagUsageCache) get(key string, fn func() (interface{}, error)) (interface{}, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if e, found := c.entries[key]; found && now.Before(e.expires) {
+		return e.value, nil
+	}
+
+	value, err := fn()
+	if err != nil {
+		return nil, err
+	}
+
+	// remove expired entries so that the cache does not grow indefinitely
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = tagUsageCacheEntry{
+		value:   value,
+		expires: now.Add(tagUsageCacheDuration),
+	}
+
+	return value, nil
+}
+
+func (r *queryResolver) TagUsageTimeline(ctx context.Context, input models.TagUsageTimelineInput) ([]*models.TagUsagePoint, error) {
+	tagID, err := strconv.Atoi(input.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := models.TagUsageIntervalMonth
+	if input.Interval != nil {
+		interval = *input.Interval
+	}
+
+	dateField := models.TagUsageDateFieldDate
+	if input.DateField != nil {
+		dateField = *input.DateField
+	}
+
+	key := fmt.Sprintf("timeline:%d:%s:%s", tagID, interval, dateField)
+	ret, err := tagUsage.get(key, func() (interface{}, error) {
+		var points []*models.TagUsagePoint
+		err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			points, err = repo.Tag().UsageTimeline(tagID, interval, dateField)
+			return err
+		})
+		return points, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.([]*models.TagUsagePoint), nil
+}
+
+func (r *queryResolver) TrendingTags(ctx context.Context, days *int, limit *int) ([]*models.TrendingTag, error) {
+	d := defaultTrendingTagDays
+	if days != nil {
+		d = *days
+	}
+
+	l := defaultTrendingTagLimit
+	if limit != nil {
+		l = *limit
+	}
+
+	if d <= 0 {
+		return nil, errors.New("days must be greater than zero")
+	}
+
+	key := fmt.Sprintf("trending:%d:%d", d, l)
+	ret, err := tagUsage.get(key, func() (interface{}, error) {
+		var trending []*models.TrendingTag
+		err := r.withReadTxn(ctx, func(repo models.ReaderRepository) error {
+			var err error
+			trending, err = repo.Tag().Trending(d, l)
+			return err
+		})
+		return trending, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret.([]*models.TrendingTag), nil
+}
@@ -38,7 +38,21 @@ func logEntriesFromLogItems(logItems []logger.LogItem) []*models.LogEntry {
 	return ret
 }
 
-func (r *subscriptionResolver) LoggingSubscribe(ctx context.Context) (<-chan []*models.LogEntry, error) {
+// logLevelTypes maps the graphql log levels to logger item types.
+var logLevelTypes = map[models.LogLevel]string{
+	models.LogLevelDebug:    "debug",
+	models.LogLevelInfo:     "info",
+	models.LogLevelProgress: "progress",
+	models.LogLevelWarning:  "warn",
+	models.LogLevelError:    "error",
+}
+
+func (r *subscriptionResolver) LoggingSubscribe(ctx context.Context, minLevel *models.LogLevel) (<-chan []*models.LogEntry, error) {
+	minType := "trace"
+	if minLevel != nil {
+		minType = logLevelTypes[*minLevel]
+	}
+
 	ret := make(chan []*models.LogEntry, 100)
 	stop := make(chan int, 1)
 	logSub := logger.SubscribeToLog(stop)
@@ -47,7 +61,9 @@ func (r *subscriptionResolver) LoggingSubscribe(ctx context.Context) (<-chan []*
 		for {
 			select {
 			case logEntries := <-logSub:
-				ret <- logEntriesFromLogItems(logEntries)
+				if filtered := filterLogItems(logEntries, minType); len(filtered) > 0 {
+					ret <- logEntriesFromLogItems(filtered)
+				}
 			case <-ctx.Done():
 				stop <- 0
 				close(ret)
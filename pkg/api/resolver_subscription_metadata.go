@@ -2,35 +2,104 @@ package api
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
 )
 
+// minimum interval between status updates sent to a single client
+const statusUpdateInterval = 500 * time.Millisecond
+
 func (r *subscriptionResolver) MetadataUpdate(ctx context.Context) (<-chan *models.MetadataUpdateStatus, error) {
 	msg := make(chan *models.MetadataUpdateStatus, 1)
 
-	ticker := time.NewTicker(5 * time.Second)
+	updates := manager.SubscribeToStatus(ctx)
 
 	go func() {
+		defer close(msg)
+
 		lastStatus := manager.TaskStatus{}
 		for {
+			thisStatus := manager.GetInstance().CurrentStatus()
+			if thisStatus != lastStatus {
+				select {
+				case msg <- makeMetadataUpdateStatus(thisStatus):
+				case <-ctx.Done():
+					return
+				}
+			}
+			lastStatus = thisStatus
+
+			// further updates are coalesced while waiting
 			select {
-			case _ = <-ticker.C:
-				thisStatus := manager.GetInstance().Status
-				if thisStatus != lastStatus {
-					ret := models.MetadataUpdateStatus{
-						Progress: thisStatus.Progress,
-						Status:   thisStatus.Status.String(),
-						Message:  "",
-					}
-					msg <- &ret
+			case <-time.After(statusUpdateInterval):
+			case <-ctx.Done():
+				return
+			}
+
+			if _, ok := <-updates; !ok {
+				return
+			}
+		}
+	}()
+
+	return msg, nil
+}
+
+func (r *subscriptionResolver) JobsUpdate(ctx context.Context) (<-chan []*models.Job, error) {
+	msg := make(chan []*models.Job, 1)
+
+	updates := manager.SubscribeToStatus(ctx)
+
+	go func() {
+		defer close(msg)
+
+		var lastJobs []*models.Job
+		for {
+			jobs := makeJobs(manager.GetInstance().Jobs())
+			if lastJobs == nil || !reflect.DeepEqual(jobs, lastJobs) {
+				select {
+				case msg <- jobs:
+				case <-ctx.Done():
+					return
 				}
-				lastStatus = thisStatus
+			}
+			lastJobs = jobs
+
+			// further updates are coalesced while waiting
+			select {
+			case <-time.After(statusUpdateInterval):
+			case <-ctx.Done():
+				return
+			}
+
+			if _, ok := <-updates; !ok {
+				return
+			}
+		}
+	}()
+
+	return msg, nil
+}
+
+func (r *subscriptionResolver) EntityChanged(ctx context.Context, types []models.EntityType) (<-chan *models.EntityChangedEvent, error) {
+	msg := make(chan *models.EntityChangedEvent, 100)
+
+	changes := manager.SubscribeToEntityChanges(ctx)
+
+	go func() {
+		defer close(msg)
+
+		for e := range changes {
+			if !includesEntityType(types, e.Type) {
+				continue
+			}
+
+			select {
+			case msg <- e:
 			case <-ctx.Done():
-				ticker.Stop()
-				close(msg)
 				return
 			}
 		}
@@ -38,3 +107,18 @@ func (r *subscriptionResolver) MetadataUpdate(ctx context.Context) (<-chan *mode
 
 	return msg, nil
 }
+
+// includesEntityType returns true if types is empty or contains t.
+func includesEntityType(types []models.EntityType, t models.EntityType) bool {
+	if len(types) == 0 {
+		return true
+	}
+
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+
+	return false
+}
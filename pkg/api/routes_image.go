@@ -7,9 +7,9 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/models"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 type imageRoutes struct {
@@ -31,13 +31,25 @@ func (rs imageRoutes) Routes() chi.Router {
 
 // region Handlers
 
+// Thumbnail serves the thumbnail of the image of at least the size query
+// parameter, which defaults to the size shown in the interface. The
+// thumbnail is made if it does not exist.
 func (rs imageRoutes) Thumbnail(w http.ResponseWriter, r *http.Request) {
 	image := r.Context().Value(imageKey).(*models.Image)
-	filepath := manager.GetInstance().Paths.Generated.GetThumbnailPath(image.Checksum, models.DefaultGthumbWidth)
 
-	// if the thumbnail doesn't exist, fall back to the original file
-	exists, _ := utils.FileExists(filepath)
-	if exists {
+	size := models.DefaultGthumbWidth
+	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 {
+		size = s
+	}
+
+	filepath, err := manager.GetInstance().ThumbnailCache.Get(image, size)
+	if err != nil {
+		logger.Warnf("error getting thumbnail for image %s: %s", image.Path, err.Error())
+	}
+
+	// fall back to the original file if the image is no larger than the
+	// thumbnail, or the thumbnail could not be made
+	if filepath != "" {
 		http.ServeFile(w, r, filepath)
 	} else {
 		rs.Image(w, r)
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// interval at which comments are sent to keep idle log streams open
+const logStreamKeepAlive = 30 * time.Second
+
+// logTypeSeverity orders the logger item types. Progress is treated as info.
+var logTypeSeverity = map[string]int{
+	"trace":    0,
+	"debug":    1,
+	"info":     2,
+	"progress": 2,
+	"warn":     3,
+	"error":    4,
+}
+
+// logLevelType returns the logger item type corresponding to the provided
+// level name, as used in the log level configuration. Returns false if the
+// level is not recognised.
+func logLevelType(level string) (string, bool) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return "trace", true
+	case "debug":
+		return "debug", true
+	case "info":
+		return "info", true
+	case "progress":
+		return "progress", true
+	case "warn", "warning":
+		return "warn", true
+	case "error":
+		return "error", true
+	}
+
+	return "", false
+}
+
+// filterLogItems returns the items with at least the severity of minType.
+func filterLogItems(items []logger.LogItem, minType string) []logger.LogItem {
+	min := logTypeSeverity[minType]
+
+	var ret []logger.LogItem
+	for _, item := range items {
+		if logTypeSeverity[item.Type] >= min {
+			ret = append(ret, item)
+		}
+	}
+
+	return ret
+}
+
+type logsRoutes struct{}
+
+func (rs logsRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/stream", rs.stream)
+
+	return r
+}
+
+// stream sends log entries as server-sent events, starting with the recent
+// entries in the log cache. The level query parameter sets the minimum level
+// of the entries sent, and defaults to Info.
+func (rs logsRoutes) stream(w http.ResponseWriter, r *http.Request) {
+	minType := "info"
+	if level := r.URL.Query().Get("level"); level != "" {
+		var ok bool
+		minType, ok = logLevelType(level)
+		if !ok {
+			http.Error(w, fmt.Sprintf("invalid log level: %s", level), http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	send := func(items []logger.LogItem) error {
+		for _, entry := range logEntriesFromLogItems(filterLogItems(items, minType)) {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(w, "event: log\ndata: %s\n\n", data); err != nil {
+				return err
+			}
+		}
+
+		flusher.Flush()
+		return nil
+	}
+
+	stop := make(chan int, 1)
+	logSub := logger.SubscribeToLog(stop)
+	defer func() {
+		stop <- 0
+	}()
+
+	// the cache is ordered from newest to oldest
+	cache := logger.GetLogCache()
+	for i, j := 0, len(cache)-1; i < j; i, j = i+1, j-1 {
+		cache[i], cache[j] = cache[j], cache[i]
+	}
+	if err := send(cache); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(logStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case items, ok := <-logSub:
+			if !ok {
+				return
+			}
+			if err := send(items); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
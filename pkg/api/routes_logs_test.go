@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterLogItems(t *testing.T) {
+	items := []logger.LogItem{
+		{Type: "trace", Message: "trace"},
+		{Type: "debug", Message: "debug"},
+		{Type: "info", Message: "info"},
+		{Type: "progress", Message: "progress"},
+		{Type: "warn", Message: "warn"},
+		{Type: "error", Message: "error"},
+	}
+
+	messages := func(items []logger.LogItem) []string {
+		var ret []string
+		for _, i := range items {
+			ret = append(ret, i.Message)
+		}
+		return ret
+	}
+
+	testCases := []struct {
+		level string
+		want  []string
+	}{
+		{"Trace", []string{"trace", "debug", "info", "progress", "warn", "error"}},
+		{"info", []string{"info", "progress", "warn", "error"}},
+		{"Warning", []string{"warn", "error"}},
+		{"error", []string{"error"}},
+	}
+
+	for _, tc := range testCases {
+		minType, ok := logLevelType(tc.level)
+		assert.True(t, ok)
+		assert.Equal(t, tc.want, messages(filterLogItems(items, minType)), tc.level)
+	}
+
+	_, ok := logLevelType("verbose")
+	assert.False(t, ok)
+}
+
+func TestLogStreamInvalidLevel(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/stream?level=verbose", nil)
+	w := httptest.NewRecorder()
+
+	logsRoutes{}.Routes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -2,9 +2,13 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/stashapp/stash/pkg/ffmpeg"
@@ -12,9 +16,14 @@ import (
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
+// markerClipDuration is the default length in seconds of scene marker clips,
+// which is the length of the marker preview videos.
+const markerClipDuration = 20.0
+
 type sceneRoutes struct {
 	txnManager models.TransactionManager
 }
@@ -32,14 +41,21 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Get("/stream.m3u8", rs.StreamHLS)
 		r.Get("/stream.ts", rs.StreamTS)
 		r.Get("/stream.mp4", rs.StreamMp4)
+		r.Get("/clip.mp4", rs.Clip)
 
 		r.Get("/screenshot", rs.Screenshot)
+		r.Get("/frame", rs.Frame)
 		r.Get("/preview", rs.Preview)
 		r.Get("/webp", rs.Webp)
 		r.Get("/vtt/chapter", rs.ChapterVtt)
+		r.Get("/caption/{captionId}", rs.Caption)
+		r.Get("/funscript", rs.Funscript)
+		r.Get("/interactive", rs.Interactive)
+		r.Get("/interactive_heatmap", rs.InteractiveHeatmap)
 
 		r.Get("/scene_marker/{sceneMarkerId}/stream", rs.SceneMarkerStream)
 		r.Get("/scene_marker/{sceneMarkerId}/preview", rs.SceneMarkerPreview)
+		r.Get("/scene_marker/{sceneMarkerId}/clip.mp4", rs.SceneMarkerClip)
 	})
 	r.With(SceneCtx).Get("/{sceneId}_thumbs.vtt", rs.VttThumbs)
 	r.With(SceneCtx).Get("/{sceneId}_sprite.jpg", rs.VttSprite)
@@ -71,6 +87,13 @@ func (rs sceneRoutes) StreamDirect(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
 
+	// serve transcodes which are only in object storage from it
+	transcodePath := manager.GetInstance().Paths.Scene.GetTranscodePath(scene.GetHash(fileNamingAlgo))
+	if manager.GetInstance().GeneratedStore.IsRemote(transcodePath) {
+		manager.GetInstance().GeneratedStore.Serve(w, r, transcodePath)
+		return
+	}
+
 	filepath := manager.GetInstance().Paths.Scene.GetStreamPath(scene.Path, scene.GetHash(fileNamingAlgo))
 	manager.RegisterStream(filepath, &w)
 	http.ServeFile(w, r, filepath)
@@ -99,6 +122,67 @@ func (rs sceneRoutes) StreamMp4(w http.ResponseWriter, r *http.Request) {
 	rs.streamTranscode(w, r, ffmpeg.CodecH264)
 }
 
+// Clip downloads the part of the scene between the start and end times in
+// seconds as an MP4 file. The streams are transcoded if they can't be copied
+// or if the transcode parameter is true.
+func (rs sceneRoutes) Clip(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	start, err := ffmpeg.ParseStartTime(r.URL.Query().Get("start"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	end, err := ffmpeg.ParseStartTime(r.URL.Query().Get("end"))
+	if err != nil {
+		http.Error(w, "invalid end time: "+r.URL.Query().Get("end"), http.StatusBadRequest)
+		return
+	}
+
+	rs.serveClip(w, r, scene, start, end)
+}
+
+func (rs sceneRoutes) serveClip(w http.ResponseWriter, r *http.Request, scene *models.Scene, start float64, end float64) {
+	videoFile, err := ffmpeg.NewVideoFile(manager.GetInstance().FFProbePath, scene.Path, false)
+	if err != nil {
+		logger.Errorf("[clip] error reading video file: %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	duration, err := ffmpeg.ClipDuration(*videoFile, start, end)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transcode, _ := strconv.ParseBool(r.URL.Query().Get("transcode"))
+	options := ffmpeg.ClipOptions{
+		ProbeResult: *videoFile,
+		Start:       start,
+		Duration:    duration,
+		Transcode:   transcode,
+	}
+
+	encoder := ffmpeg.NewEncoder(manager.GetInstance().FFMPEGPath)
+	stream, err := encoder.GetClipStream(options)
+	if err != nil {
+		logger.Errorf("[clip] error exporting clip of %s: %s", scene.Path, err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s-%s.mp4", scene.GetTitle(), clipTime(start), clipTime(start+duration))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	stream.Serve(w, r)
+}
+
+// clipTime returns the time in seconds formatted for clip file names.
+func clipTime(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', -1, 64)
+}
+
 func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 
@@ -108,13 +192,25 @@ func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logger.Debug("Returning HLS playlist")
-
 	// getting the playlist manifest only
 	w.Header().Set("Content-Type", ffmpeg.MimeHLS)
 	var str strings.Builder
 
-	ffmpeg.WriteHLSPlaylist(*videoFile, r.URL.String(), &str)
+	// return the master playlist of the quality levels unless the playlist
+	// of a level was requested
+	r.ParseForm()
+	resolution := models.StreamingResolutionEnum(r.Form.Get("resolution"))
+	if !resolution.IsValid() {
+		resolution = ""
+	}
+	variants := ffmpeg.GetHLSVariants(*videoFile, config.GetInstance().GetMaxStreamingTranscodeSize())
+	if resolution == "" && len(variants) > 0 {
+		logger.Debug("Returning HLS master playlist")
+		ffmpeg.WriteHLSMasterPlaylist(variants, r.URL.String(), &str)
+	} else {
+		logger.Debug("Returning HLS playlist")
+		ffmpeg.WriteHLSPlaylist(*videoFile, r.URL.String(), resolution, &str)
+	}
 
 	requestByteRange := utils.CreateByteRange(r.Header.Get("Range"))
 	if requestByteRange.RawString != "" {
@@ -149,6 +245,14 @@ func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, vi
 	startTime := r.Form.Get("start")
 	requestedSize := r.Form.Get("resolution")
 
+	if startTime != "" {
+		if _, err := ffmpeg.ParseStartTime(startTime); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+	}
+
 	var stream *ffmpeg.Stream
 
 	audioCodec := ffmpeg.MissingUnsupported
@@ -159,11 +263,13 @@ func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, vi
 	options := ffmpeg.GetTranscodeStreamOptions(*videoFile, videoCodec, audioCodec)
 	options.StartTime = startTime
 	options.MaxTranscodeSize = config.GetInstance().GetMaxStreamingTranscodeSize()
+	options.ApplyProfile(config.GetInstance().GetLiveTranscodeProfile())
 	if requestedSize != "" {
 		options.MaxTranscodeSize = models.StreamingResolutionEnum(requestedSize)
 	}
 
 	encoder := ffmpeg.NewEncoder(manager.GetInstance().FFMPEGPath)
+	encoder.HWAccel = manager.GetInstance().HWAccel()
 	stream, err = encoder.GetTranscodeStream(options)
 
 	if err != nil {
@@ -181,9 +287,8 @@ func (rs sceneRoutes) Screenshot(w http.ResponseWriter, r *http.Request) {
 	filepath := manager.GetInstance().Paths.Scene.GetScreenshotPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
 
 	// fall back to the scene image blob if the file isn't present
-	screenshotExists, _ := utils.FileExists(filepath)
-	if screenshotExists {
-		http.ServeFile(w, r, filepath)
+	if manager.GetInstance().GeneratedStore.Exists(filepath) {
+		manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
 	} else {
 		var cover []byte
 		rs.txnManager.WithReadTxn(r.Context(), func(repo models.ReaderRepository) error {
@@ -194,16 +299,47 @@ func (rs sceneRoutes) Screenshot(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Frame returns the frame of the scene file at the time in seconds set by
+// the t parameter. The frame is scaled to the width parameter, if set.
+func (rs sceneRoutes) Frame(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	at, err := ffmpeg.ParseStartTime(r.URL.Query().Get("t"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	width := 0
+	if widthParam := r.URL.Query().Get("width"); widthParam != "" {
+		width, err = strconv.Atoi(widthParam)
+		if err != nil || width <= 0 {
+			http.Error(w, "invalid width: "+widthParam, http.StatusBadRequest)
+			return
+		}
+	}
+
+	frame, err := manager.CaptureSceneFrame(scene, at, width)
+	if err != nil {
+		logger.Errorf("[frame] error capturing frame of %s: %s", scene.Path, err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.ServeImage(frame, w, r)
+}
+
 func (rs sceneRoutes) Preview(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	filepath := manager.GetInstance().Paths.Scene.GetStreamPreviewPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
-	utils.ServeFileNoCache(w, r, filepath)
+	w.Header().Add("Cache-Control", "no-cache")
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
 }
 
 func (rs sceneRoutes) Webp(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	filepath := manager.GetInstance().Paths.Scene.GetStreamPreviewImagePath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
-	http.ServeFile(w, r, filepath)
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
 }
 
 func (rs sceneRoutes) getChapterVttTitle(ctx context.Context, marker *models.SceneMarker) string {
@@ -264,18 +400,109 @@ func (rs sceneRoutes) ChapterVtt(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(vtt))
 }
 
+// Caption serves the caption as WebVTT. WebVTT files are served as is, other
+// captions are converted with ffmpeg.
+func (rs sceneRoutes) Caption(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	captionID, _ := strconv.Atoi(chi.URLParam(r, "captionId"))
+
+	var captions []*models.SceneCaption
+	if err := rs.txnManager.WithReadTxn(r.Context(), func(repo models.ReaderRepository) error {
+		var err error
+		captions, err = repo.Scene().GetCaptions(scene.ID)
+		return err
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var caption *models.SceneCaption
+	for _, c := range captions {
+		if c.ID == captionID {
+			caption = c
+			break
+		}
+	}
+	if caption == nil {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vtt")
+
+	if !caption.Embedded() && caption.Codec == "webvtt" {
+		http.ServeFile(w, r, caption.Path)
+		return
+	}
+
+	path := caption.Path
+	streamIndex := -1
+	if caption.Embedded() {
+		path = scene.Path
+		streamIndex = int(caption.StreamIndex.Int64)
+	}
+
+	encoder := ffmpeg.NewEncoder(manager.GetInstance().FFMPEGPath)
+	vtt, err := encoder.CaptionToWebVTT(path, streamIndex)
+	if err != nil {
+		logger.Errorf("error converting caption %d of %s: %s", captionID, scene.Path, err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write([]byte(vtt))
+}
+
+func (rs sceneRoutes) Funscript(w http.ResponseWriter, r *http.Request) {
+	s := r.Context().Value(sceneKey).(*models.Scene)
+	if !s.Interactive {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	utils.ServeFileNoCache(w, r, scene.GetFunscriptPath(s.Path))
+}
+
+// Interactive returns the funscript URL with a sync token, the funscript
+// offset and the server time, for clients of interactive devices.
+func (rs sceneRoutes) Interactive(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	if !scene.Interactive {
+		http.Error(w, "scene is not interactive", http.StatusNotFound)
+		return
+	}
+
+	c := config.GetInstance()
+	baseURL, _ := r.Context().Value(BaseURLCtxKey).(string)
+	sync := makeInteractiveSync(scene.ID, baseURL, c.GetJWTSignKey(), c.GetFunscriptOffset(), time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(sync); err != nil {
+		logger.Errorf("error writing interactive sync of scene %d: %s", scene.ID, err.Error())
+	}
+}
+
+func (rs sceneRoutes) InteractiveHeatmap(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	filepath := manager.GetInstance().Paths.Scene.GetInteractiveHeatmapPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
+	w.Header().Add("Cache-Control", "no-cache")
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
+}
+
 func (rs sceneRoutes) VttThumbs(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	w.Header().Set("Content-Type", "text/vtt")
 	filepath := manager.GetInstance().Paths.Scene.GetSpriteVttFilePath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
-	http.ServeFile(w, r, filepath)
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
 }
 
 func (rs sceneRoutes) VttSprite(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	w.Header().Set("Content-Type", "image/jpeg")
 	filepath := manager.GetInstance().Paths.Scene.GetSpriteImageFilePath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
-	http.ServeFile(w, r, filepath)
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
 }
 
 func (rs sceneRoutes) SceneMarkerStream(w http.ResponseWriter, r *http.Request) {
@@ -292,7 +519,42 @@ func (rs sceneRoutes) SceneMarkerStream(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	filepath := manager.GetInstance().Paths.SceneMarkers.GetStreamPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()), int(sceneMarker.Seconds))
-	http.ServeFile(w, r, filepath)
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
+}
+
+// SceneMarkerClip downloads the part of the scene starting at the scene
+// marker as an MP4 file. The length of the clip in seconds is set by the
+// duration parameter, defaulting to the length of marker previews.
+func (rs sceneRoutes) SceneMarkerClip(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	sceneMarkerID, _ := strconv.Atoi(chi.URLParam(r, "sceneMarkerId"))
+	var sceneMarker *models.SceneMarker
+	if err := rs.txnManager.WithReadTxn(r.Context(), func(repo models.ReaderRepository) error {
+		var err error
+		sceneMarker, err = repo.SceneMarker().Find(sceneMarkerID)
+		return err
+	}); err != nil {
+		logger.Warnf("Error when getting scene marker for clip: %s", err.Error())
+		http.Error(w, http.StatusText(500), 500)
+		return
+	}
+
+	if sceneMarker == nil || sceneMarker.SceneID.Int64 != int64(scene.ID) {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	duration := markerClipDuration
+	if durationParam := r.URL.Query().Get("duration"); durationParam != "" {
+		var err error
+		duration, err = ffmpeg.ParseStartTime(durationParam)
+		if err != nil || duration == 0 {
+			http.Error(w, "invalid duration: "+durationParam, http.StatusBadRequest)
+			return
+		}
+	}
+
+	rs.serveClip(w, r, scene, sceneMarker.Seconds, sceneMarker.Seconds+duration)
 }
 
 func (rs sceneRoutes) SceneMarkerPreview(w http.ResponseWriter, r *http.Request) {
@@ -311,15 +573,14 @@ func (rs sceneRoutes) SceneMarkerPreview(w http.ResponseWriter, r *http.Request)
 	filepath := manager.GetInstance().Paths.SceneMarkers.GetStreamPreviewImagePath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()), int(sceneMarker.Seconds))
 
 	// If the image doesn't exist, send the placeholder
-	exists, _ := utils.FileExists(filepath)
-	if !exists {
+	if !manager.GetInstance().GeneratedStore.Exists(filepath) {
 		w.Header().Set("Content-Type", "image/png")
 		w.Header().Set("Cache-Control", "no-store")
 		_, _ = w.Write(utils.PendingGenerateResource)
 		return
 	}
 
-	http.ServeFile(w, r, filepath)
+	manager.GetInstance().GeneratedStore.Serve(w, r, filepath)
 }
 
 // endregion
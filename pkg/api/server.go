@@ -2,10 +2,8 @@ package api
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
@@ -14,16 +12,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/handler"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/gobuffalo/packr/v2"
 	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
+	"github.com/stashapp/stash/pkg/api/loaders"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager"
 	"github.com/stashapp/stash/pkg/manager/config"
-	"github.com/stashapp/stash/pkg/manager/paths"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -43,7 +42,7 @@ const (
 )
 
 func allowUnauthenticated(r *http.Request) bool {
-	return strings.HasPrefix(r.URL.Path, "/login") || r.URL.Path == "/css"
+	return strings.HasPrefix(r.URL.Path, "/login") || r.URL.Path == "/css" || allowSyncToken(r)
 }
 
 func authenticateHandler() func(http.Handler) http.Handler {
@@ -73,6 +72,7 @@ func authenticateHandler() func(http.Handler) http.Handler {
 				}
 
 				userID = c.GetUsername()
+				ctx = context.WithValue(ctx, apiAuthKey, true)
 			} else {
 				// handle session
 				userID, err = getSessionUserID(w, r)
@@ -96,10 +96,10 @@ func authenticateHandler() func(http.Handler) http.Handler {
 
 				// otherwise redirect to the login page
 				u := url.URL{
-					Path: "/login",
+					Path: withBasePath(loginEndPoint),
 				}
 				q := u.Query()
-				q.Set(returnURLParam, r.URL.Path)
+				q.Set(returnURLParam, withBasePath(r.URL.Path))
 				u.RawQuery = q.Encode()
 				http.Redirect(w, r, u.String(), http.StatusFound)
 				return
@@ -126,7 +126,9 @@ func Start() {
 
 	r := chi.NewRouter()
 
+	// liveness only, without checking dependencies
 	r.Use(middleware.Heartbeat("/healthz"))
+	r.Use(healthHandler)
 	r.Use(authenticateHandler())
 	r.Use(middleware.Recoverer)
 
@@ -136,7 +138,14 @@ func Start() {
 	}
 	r.Use(middleware.DefaultCompress)
 	r.Use(middleware.StripSlashes)
-	r.Use(cors.AllowAll().Handler)
+	// the same as cors.AllowAll, but with the range headers exposed so that
+	// cast devices can seek in the streams
+	r.Use(cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"HEAD", "GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders: []string{"*"},
+		ExposedHeaders: []string{"Content-Range", "Accept-Ranges", "Content-Length"},
+	}).Handler)
 	r.Use(BaseURLMiddleware)
 
 	recoverFunc := handler.RecoverFunc(func(ctx context.Context, err interface{}) error {
@@ -154,15 +163,35 @@ func Start() {
 	maxUploadSize := handler.UploadMaxSize(c.GetMaxUploadSize())
 	websocketKeepAliveDuration := handler.WebsocketKeepAliveDuration(10 * time.Second)
 
+	// reject all mutations in read-only mode, including those which do not
+	// write to the database, such as configuration changes and tasks
+	readOnly := c.IsReadOnly()
+	readOnlyMiddleware := handler.ResolverMiddleware(func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+		if readOnly && graphql.GetFieldContext(ctx).Object == "Mutation" {
+			return nil, models.ErrReadOnly
+		}
+
+		return next(ctx)
+	})
+
+	// limit the complexity and depth of operations, so that deeply nested
+	// queries cannot exhaust the server
+	complexityLimit := handler.ComplexityLimitFunc(queryComplexityLimit)
+	depthLimitMiddleware := handler.ResolverMiddleware(queryDepthMiddleware)
+
 	txnManager := manager.GetInstance().TxnManager
 	resolver := &Resolver{
 		txnManager: txnManager,
 	}
 
-	gqlHandler := handler.GraphQL(models.NewExecutableSchema(models.Config{Resolvers: resolver}), recoverFunc, websocketUpgrader, websocketKeepAliveDuration, maxUploadSize)
+	gqlHandler := handler.GraphQL(models.NewExecutableSchema(models.Config{Resolvers: resolver}), recoverFunc, websocketUpgrader, websocketKeepAliveDuration, maxUploadSize, readOnlyMiddleware, complexityLimit, depthLimitMiddleware)
+
+	dataloaders := loaders.Middleware{
+		TxnManager: txnManager,
+	}
 
-	r.Handle("/graphql", gqlHandler)
-	r.Handle("/playground", handler.Playground("GraphQL playground", "/graphql"))
+	r.With(requestRateLimitHandler, dataloaders.Middleware).Handle("/graphql", gqlHandler)
+	r.Handle("/playground", handler.Playground("GraphQL playground", withBasePath("/graphql")))
 
 	// session handlers
 	r.Post(loginEndPoint, handleLogin)
@@ -173,10 +202,10 @@ func Start() {
 	r.Mount("/performer", performerRoutes{
 		txnManager: txnManager,
 	}.Routes())
-	r.Mount("/scene", sceneRoutes{
+	r.With(bandwidthLimitHandler).Mount("/scene", sceneRoutes{
 		txnManager: txnManager,
 	}.Routes())
-	r.Mount("/image", imageRoutes{
+	r.With(bandwidthLimitHandler).Mount("/image", imageRoutes{
 		txnManager: txnManager,
 	}.Routes())
 	r.Mount("/studio", studioRoutes{
@@ -188,7 +217,8 @@ func Start() {
 	r.Mount("/tag", tagRoutes{
 		txnManager: txnManager,
 	}.Routes())
-	r.Mount("/downloads", downloadsRoutes{}.Routes())
+	r.With(bandwidthLimitHandler).Mount("/downloads", downloadsRoutes{}.Routes())
+	r.Mount("/logs", logsRoutes{}.Routes())
 
 	r.HandleFunc("/css", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/css")
@@ -235,6 +265,7 @@ func Start() {
 	}
 
 	customUILocation := c.GetCustomUILocation()
+	basePath := c.GetURLBasePath()
 
 	// Serve the web app
 	r.HandleFunc("/*", func(w http.ResponseWriter, r *http.Request) {
@@ -251,7 +282,7 @@ func Start() {
 
 		if ext == ".html" || ext == "" {
 			data, _ := uiBox.Find("index.html")
-			_, _ = w.Write(data)
+			_, _ = w.Write(setIndexBasePath(data, basePath))
 		} else {
 			isStatic, _ := path.Match("/static/*/*", r.URL.Path)
 			if isStatic {
@@ -265,13 +296,23 @@ func Start() {
 	if displayHost == "0.0.0.0" {
 		displayHost = "localhost"
 	}
-	displayAddress := displayHost + ":" + strconv.Itoa(c.GetPort())
+	displayAddress := displayHost + ":" + strconv.Itoa(c.GetPort()) + basePath
+
+	var serverHandler http.Handler = r
+	if basePath != "" {
+		serverHandler = basePathHandler(basePath, r)
+	}
 
 	address := c.GetHost() + ":" + strconv.Itoa(c.GetPort())
-	if tlsConfig := makeTLSConfig(); tlsConfig != nil {
+	tlsConfig, err := makeTLSConfig()
+	if err != nil {
+		logger.Errorf("Error loading SSL certificate, serving over HTTP: %s", err.Error())
+	}
+
+	if tlsConfig != nil {
 		httpsServer := &http.Server{
 			Addr:      address,
-			Handler:   r,
+			Handler:   serverHandler,
 			TLSConfig: tlsConfig,
 		}
 
@@ -285,7 +326,7 @@ func Start() {
 	} else {
 		server := &http.Server{
 			Addr:    address,
-			Handler: r,
+			Handler: serverHandler,
 		}
 
 		go func() {
@@ -310,38 +351,6 @@ func GetVersion() (string, string, string) {
 	return version, githash, buildstamp
 }
 
-func makeTLSConfig() *tls.Config {
-	cert, err := ioutil.ReadFile(paths.GetSSLCert())
-	if err != nil {
-		return nil
-	}
-
-	key, err := ioutil.ReadFile(paths.GetSSLKey())
-	if err != nil {
-		return nil
-	}
-
-	certs := make([]tls.Certificate, 1)
-	certs[0], err = tls.X509KeyPair(cert, key)
-	if err != nil {
-		return nil
-	}
-	tlsConfig := &tls.Config{
-		Certificates: certs,
-	}
-
-	return tlsConfig
-}
-
-func HasTLSConfig() bool {
-	ret, _ := utils.FileExists(paths.GetSSLCert())
-	if ret {
-		ret, _ = utils.FileExists(paths.GetSSLKey())
-	}
-
-	return ret
-}
-
 type contextKey struct {
 	name string
 }
@@ -366,6 +375,7 @@ func BaseURLMiddleware(next http.Handler) http.Handler {
 		if externalHost != "" {
 			baseURL = externalHost
 		}
+		baseURL = strings.TrimSuffix(baseURL, "/") + config.GetInstance().GetURLBasePath()
 
 		r = r.WithContext(context.WithValue(ctx, BaseURLCtxKey, baseURL))
 
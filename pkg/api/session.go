@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"time"
 
 	"github.com/stashapp/stash/pkg/manager/config"
 
@@ -22,8 +23,9 @@ const returnURLParam = "returnURL"
 var sessionStore = sessions.NewCookieStore(config.GetInstance().GetSessionStoreKey())
 
 type loginTemplateData struct {
-	URL   string
-	Error string
+	URL      string
+	Error    string
+	BasePath string
 }
 
 func initSessionStore() {
@@ -38,7 +40,7 @@ func redirectToLogin(w http.ResponseWriter, returnURL string, loginError string)
 		return
 	}
 
-	err = templ.Execute(w, loginTemplateData{URL: returnURL, Error: loginError})
+	err = templ.Execute(w, loginTemplateData{URL: returnURL, Error: loginError, BasePath: config.GetInstance().GetURLBasePath()})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("error: %s", err), http.StatusInternalServerError)
 	}
@@ -46,7 +48,7 @@ func redirectToLogin(w http.ResponseWriter, returnURL string, loginError string)
 
 func getLoginHandler(w http.ResponseWriter, r *http.Request) {
 	if !config.GetInstance().HasCredentials() {
-		http.Redirect(w, r, "/", http.StatusFound)
+		http.Redirect(w, r, withBasePath("/"), http.StatusFound)
 		return
 	}
 
@@ -56,7 +58,13 @@ func getLoginHandler(w http.ResponseWriter, r *http.Request) {
 func handleLogin(w http.ResponseWriter, r *http.Request) {
 	url := r.FormValue(returnURLParam)
 	if url == "" {
-		url = "/"
+		url = withBasePath("/")
+	}
+
+	// reject locked out clients without checking the credentials
+	if d := logins.lockedOut(clientAddress(r), time.Now()); d > 0 {
+		redirectToLogin(w, url, fmt.Sprintf("Too many failed login attempts. Try again in %s", d.Round(time.Second)))
+		return
 	}
 
 	// ignore error - we want a new session regardless
@@ -67,11 +75,15 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 
 	// authenticate the user
 	if !config.GetInstance().ValidateCredentials(username, password) {
+		loginFailed(r)
+
 		// redirect back to the login page with an error
 		redirectToLogin(w, url, "Username or password is invalid")
 		return
 	}
 
+	logins.succeeded(clientAddress(r))
+
 	newSession.Values[userIDKey] = username
 
 	err := newSession.Save(r, w)
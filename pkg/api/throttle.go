@@ -0,0 +1,177 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+)
+
+// window over which requests are counted for rate limiting
+const requestWindowDuration = 1 * time.Minute
+
+// clients without failed logins for this duration are forgotten
+const loginAttemptsExpiry = 24 * time.Hour
+
+// maximum duration of a single login lockout
+const maxLoginLockout = 24 * time.Hour
+
+type requestWindow struct {
+	start time.Time
+	count int
+}
+
+// requestLimiter counts requests per client within fixed windows.
+type requestLimiter struct {
+	windows map[string]*requestWindow
+	mutex   sync.Mutex
+}
+
+var requests = &requestLimiter{
+	windows: make(map[string]*requestWindow),
+}
+
+// allow records a request from the client identified by key. It returns
+// false if the client has exceeded limit requests in the current window,
+// along with the time remaining until the window ends.
+func (l *requestLimiter) allow(key string, limit int, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// remove expired windows
+	for k, w := range l.windows {
+		if now.Sub(w.start) >= requestWindowDuration {
+			delete(l.windows, k)
+		}
+	}
+
+	w := l.windows[key]
+	if w == nil {
+		w = &requestWindow{
+			start: now,
+		}
+		l.windows[key] = w
+	}
+
+	w.count++
+	if w.count > limit {
+		return false, w.start.Add(requestWindowDuration).Sub(now)
+	}
+
+	return true, 0
+}
+
+// requestRateLimitHandler rejects requests from clients which have exceeded
+// the configured number of requests per minute.
+func requestRateLimitHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := config.GetInstance().GetMaxRequestsPerMinute()
+		if limit > 0 {
+			if ok, retry := requests.allow(sessionKey(r), limit, time.Now()); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+type loginAttempts struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// loginThrottle tracks consecutive failed logins per client, locking out
+// clients with too many failures.
+type loginThrottle struct {
+	attempts map[string]*loginAttempts
+	mutex    sync.Mutex
+}
+
+var logins = &loginThrottle{
+	attempts: make(map[string]*loginAttempts),
+}
+
+// lockedOut returns the remaining lockout duration for the client, or zero
+// if the client may attempt to log in.
+func (t *loginThrottle) lockedOut(key string, now time.Time) time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	a := t.attempts[key]
+	if a == nil || !now.Before(a.lockedUntil) {
+		return 0
+	}
+
+	return a.lockedUntil.Sub(now)
+}
+
+// failed records a failed login from the client. Once the client reaches
+// maxAttempts consecutive failures, it is locked out for the lockout
+// duration, which doubles with each further failure. It returns the
+// duration of the lockout, or zero if the client is not locked out.
+func (t *loginThrottle) failed(key string, maxAttempts int, lockout time.Duration, now time.Time) time.Duration {
+	if maxAttempts <= 0 {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// remove clients that have not failed recently
+	for k, a := range t.attempts {
+		if now.Sub(a.lastFailure) > loginAttemptsExpiry && !now.Before(a.lockedUntil) {
+			delete(t.attempts, k)
+		}
+	}
+
+	a := t.attempts[key]
+	if a == nil {
+		a = &loginAttempts{}
+		t.attempts[key] = a
+	}
+
+	a.failures++
+	a.lastFailure = now
+
+	if a.failures < maxAttempts {
+		return 0
+	}
+
+	d := lockout
+	for i := maxAttempts; i < a.failures && d < maxLoginLockout; i++ {
+		d *= 2
+	}
+	if d > maxLoginLockout {
+		d = maxLoginLockout
+	}
+
+	a.lockedUntil = now.Add(d)
+	return d
+}
+
+// succeeded clears the failed logins of the client.
+func (t *loginThrottle) succeeded(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.attempts, key)
+}
+
+// loginFailed records a failed login for the request, logging a warning if
+// the client is locked out as a result.
+func loginFailed(r *http.Request) {
+	c := config.GetInstance()
+	key := clientAddress(r)
+
+	if d := logins.failed(key, c.GetLoginMaxAttempts(), c.GetLoginLockoutDuration(), time.Now()); d > 0 {
+		logger.Warnf("Too many failed logins from %s. Locked out for %s", key, d)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLimiterAllow(t *testing.T) {
+	assert := assert.New(t)
+
+	l := &requestLimiter{
+		windows: make(map[string]*requestWindow),
+	}
+
+	const limit = 2
+	now := time.Now()
+
+	ok, _ := l.allow("a", limit, now)
+	assert.True(ok)
+	ok, _ = l.allow("a", limit, now)
+	assert.True(ok)
+
+	ok, retry := l.allow("a", limit, now.Add(10*time.Second))
+	assert.False(ok)
+	assert.Equal(50*time.Second, retry)
+
+	// other clients are not affected
+	ok, _ = l.allow("b", limit, now)
+	assert.True(ok)
+
+	// requests are allowed again in the next window
+	ok, _ = l.allow("a", limit, now.Add(requestWindowDuration))
+	assert.True(ok)
+}
+
+func TestLoginThrottle(t *testing.T) {
+	assert := assert.New(t)
+
+	l := &loginThrottle{
+		attempts: make(map[string]*loginAttempts),
+	}
+
+	const (
+		maxAttempts = 3
+		lockout     = time.Minute
+	)
+	now := time.Now()
+
+	assert.Equal(time.Duration(0), l.failed("a", maxAttempts, lockout, now))
+	assert.Equal(time.Duration(0), l.failed("a", maxAttempts, lockout, now))
+	assert.Equal(time.Duration(0), l.lockedOut("a", now))
+
+	// locked out on reaching the maximum attempts
+	assert.Equal(lockout, l.failed("a", maxAttempts, lockout, now))
+	assert.Equal(lockout, l.lockedOut("a", now))
+	assert.Equal(time.Duration(0), l.lockedOut("b", now))
+
+	// lockout doubles with each further failure
+	now = now.Add(lockout)
+	assert.Equal(time.Duration(0), l.lockedOut("a", now))
+	assert.Equal(2*lockout, l.failed("a", maxAttempts, lockout, now))
+	assert.Equal(4*lockout, l.failed("a", maxAttempts, lockout, now))
+
+	// lockout is capped
+	for i := 0; i < 20; i++ {
+		l.failed("a", maxAttempts, lockout, now)
+	}
+	assert.Equal(maxLoginLockout, l.lockedOut("a", now))
+
+	// success clears the failures
+	l.succeeded("a")
+	assert.Equal(time.Duration(0), l.lockedOut("a", now))
+	assert.Equal(time.Duration(0), l.failed("a", maxAttempts, lockout, now))
+
+	// zero attempts disables throttling
+	for i := 0; i < 10; i++ {
+		assert.Equal(time.Duration(0), l.failed("c", 0, lockout, now))
+	}
+}
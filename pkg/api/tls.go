@@ -0,0 +1,188 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/manager/paths"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// interval at which the SSL certificate files are checked for changes
+const certReloadInterval = 30 * time.Second
+
+// certReloader serves the certificate in the SSL certificate and key files,
+// reloading them when they change, so that renewed certificates are used
+// without restarting.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+	mutex   sync.Mutex
+}
+
+func newCertReloader(certFile string, keyFile string) (*certReloader, error) {
+	ret := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		checked:  time.Now(),
+	}
+
+	modTime, err := ret.modified()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ret.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// modified returns the latest modification time of the files.
+func (r *certReloader) modified() (time.Time, error) {
+	var ret time.Time
+	for _, fn := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(fn)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(ret) {
+			ret = info.ModTime()
+		}
+	}
+
+	return ret, nil
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+func (r *certReloader) reloadIfModified() error {
+	modTime, err := r.modified()
+	if err != nil {
+		return err
+	}
+
+	if !modTime.After(r.modTime) {
+		return nil
+	}
+
+	if err := r.load(modTime); err != nil {
+		return err
+	}
+
+	logger.Info("Reloaded SSL certificate")
+	return nil
+}
+
+// GetCertificate returns the current certificate, for use as
+// tls.Config.GetCertificate. If the files cannot be reloaded, the error is
+// logged and the previous certificate is used.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.checked) >= certReloadInterval {
+		r.checked = now
+		if err := r.reloadIfModified(); err != nil {
+			logger.Warnf("Error reloading SSL certificate: %s", err.Error())
+		}
+	}
+
+	return r.cert, nil
+}
+
+func makeACMEManager(c *config.Instance) *autocert.Manager {
+	ret := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(c.GetACMEDomains()...),
+		Cache:      autocert.DirCache(c.GetACMECachePath()),
+		Email:      c.GetACMEEmail(),
+	}
+
+	if directoryURL := c.GetACMEDirectoryURL(); directoryURL != "" {
+		ret.Client = &acme.Client{
+			DirectoryURL: directoryURL,
+		}
+	}
+
+	return ret
+}
+
+// serveACMEChallenges answers ACME HTTP challenges on the configured port,
+// redirecting all other requests to HTTPS.
+func serveACMEChallenges(m *autocert.Manager, host string, port int) {
+	address := host + ":" + strconv.Itoa(port)
+	logger.Infof("answering ACME HTTP challenges on " + address)
+	if err := http.ListenAndServe(address, m.HTTPHandler(nil)); err != nil {
+		logger.Errorf("Error answering ACME HTTP challenges: %s", err.Error())
+	}
+}
+
+// makeTLSConfig returns the TLS configuration of the server. If ACME
+// domains are configured, certificates are obtained using ACME. Otherwise,
+// the SSL certificate files are used if present. Returns nil if the server
+// should not use TLS.
+func makeTLSConfig() (*tls.Config, error) {
+	c := config.GetInstance()
+
+	if len(c.GetACMEDomains()) > 0 {
+		m := makeACMEManager(c)
+		if port := c.GetACMEHTTPPort(); port != 0 {
+			go serveACMEChallenges(m, c.GetHost(), port)
+		}
+
+		return m.TLSConfig(), nil
+	}
+
+	if !hasSSLFiles() {
+		return nil, nil
+	}
+
+	reloader, err := newCertReloader(paths.GetSSLCert(), paths.GetSSLKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+	}, nil
+}
+
+func hasSSLFiles() bool {
+	ret, _ := utils.FileExists(paths.GetSSLCert())
+	if ret {
+		ret, _ = utils.FileExists(paths.GetSSLKey())
+	}
+
+	return ret
+}
+
+// HasTLSConfig returns true if the server uses TLS.
+func HasTLSConfig() bool {
+	return len(config.GetInstance().GetACMEDomains()) > 0 || hasSSLFiles()
+}
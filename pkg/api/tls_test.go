@@ -0,0 +1,103 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestCert(t *testing.T, certFile string, keyFile string, serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func certSerial(t *testing.T, cert *tls.Certificate) int64 {
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.SerialNumber.Int64()
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "stash.crt")
+	keyFile := filepath.Join(dir, "stash.key")
+	writeTestCert(t, certFile, keyFile, 1)
+
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, _ := r.GetCertificate(nil)
+	assert.Equal(t, int64(1), certSerial(t, cert))
+
+	// replace the certificate
+	writeTestCert(t, certFile, keyFile, 2)
+	later := time.Now().Add(time.Minute)
+	_ = os.Chtimes(certFile, later, later)
+
+	// not reloaded until the reload interval has passed
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, int64(1), certSerial(t, cert))
+
+	r.checked = r.checked.Add(-certReloadInterval)
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, int64(2), certSerial(t, cert))
+
+	// invalid files keep the previous certificate
+	_ = ioutil.WriteFile(keyFile, []byte("invalid"), 0600)
+	later = later.Add(time.Minute)
+	_ = os.Chtimes(keyFile, later, later)
+	r.checked = r.checked.Add(-certReloadInterval)
+	cert, _ = r.GetCertificate(nil)
+	assert.Equal(t, int64(2), certSerial(t, cert))
+
+	_, err = newCertReloader(certFile, keyFile)
+	assert.NotNil(t, err)
+}
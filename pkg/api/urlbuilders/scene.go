@@ -51,10 +51,34 @@ func (b SceneURLBuilder) GetChaptersVTTURL() string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/vtt/chapter"
 }
 
+func (b SceneURLBuilder) GetCaptionURL(captionID int) string {
+	var apiKeyParam string
+	if b.APIKey != "" {
+		apiKeyParam = fmt.Sprintf("?apikey=%s", b.APIKey)
+	}
+	return fmt.Sprintf("%s/scene/%s/caption/%d%s", b.BaseURL, b.SceneID, captionID, apiKeyParam)
+}
+
+func (b SceneURLBuilder) GetFunscriptURL() string {
+	var apiKeyParam string
+	if b.APIKey != "" {
+		apiKeyParam = fmt.Sprintf("?apikey=%s", b.APIKey)
+	}
+	return fmt.Sprintf("%s/scene/%s/funscript%s", b.BaseURL, b.SceneID, apiKeyParam)
+}
+
+func (b SceneURLBuilder) GetInteractiveHeatmapURL() string {
+	return b.BaseURL + "/scene/" + b.SceneID + "/interactive_heatmap"
+}
+
 func (b SceneURLBuilder) GetSceneMarkerStreamURL(sceneMarkerID int) string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/scene_marker/" + strconv.Itoa(sceneMarkerID) + "/stream"
 }
 
+func (b SceneURLBuilder) GetSceneMarkerClipURL(sceneMarkerID int) string {
+	return b.BaseURL + "/scene/" + b.SceneID + "/scene_marker/" + strconv.Itoa(sceneMarkerID) + "/clip.mp4"
+}
+
 func (b SceneURLBuilder) GetSceneMarkerStreamPreviewURL(sceneMarkerID int) string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/scene_marker/" + strconv.Itoa(sceneMarkerID) + "/preview"
 }
@@ -41,8 +41,8 @@ func galleryPathsFilter(paths []string) *models.GalleryFilterType {
 	return ret
 }
 
-func getMatchingGalleries(name string, paths []string, galleryReader models.GalleryReader) ([]*models.Gallery, error) {
-	regex := getPathQueryRegex(name)
+func getMatchingGalleries(matcher pathMatcher, paths []string, galleryReader models.GalleryReader) ([]*models.Gallery, error) {
+	regex := matcher.queryRegex()
 	organized := false
 	filter := models.GalleryFilterType{
 		Path: &models.StringCriterionInput{
@@ -65,7 +65,7 @@ func getMatchingGalleries(name string, paths []string, galleryReader models.Gall
 
 	var ret []*models.Gallery
 	for _, p := range gallerys {
-		if nameMatchesPath(name, p.Path.String) {
+		if matcher.matches(p.Path.String) {
 			ret = append(ret, p)
 		}
 	}
@@ -41,8 +41,8 @@ func imagePathsFilter(paths []string) *models.ImageFilterType {
 	return ret
 }
 
-func getMatchingImages(name string, paths []string, imageReader models.ImageReader) ([]*models.Image, error) {
-	regex := getPathQueryRegex(name)
+func getMatchingImages(matcher pathMatcher, paths []string, imageReader models.ImageReader) ([]*models.Image, error) {
+	regex := matcher.queryRegex()
 	organized := false
 	filter := models.ImageFilterType{
 		Path: &models.StringCriterionInput{
@@ -65,7 +65,7 @@ func getMatchingImages(name string, paths []string, imageReader models.ImageRead
 
 	var ret []*models.Image
 	for _, p := range images {
-		if nameMatchesPath(name, p.Path) {
+		if matcher.matches(p.Path) {
 			ret = append(ret, p)
 		}
 	}
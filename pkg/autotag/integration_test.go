@@ -296,7 +296,7 @@ func createGallery(sqb models.GalleryWriter, gallery *models.Gallery) error {
 }
 
 func withTxn(f func(r models.Repository) error) error {
-	t := sqlite.NewTransactionManager()
+	t := sqlite.NewTransactionManager(false)
 	return t.WithTxn(context.TODO(), f)
 }
 
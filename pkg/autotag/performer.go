@@ -1,6 +1,9 @@
 package autotag
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
@@ -17,8 +20,7 @@ func getMatchingPerformers(path string, performerReader models.PerformerReader)
 
 	var ret []*models.Performer
 	for _, p := range performers {
-		// TODO - commenting out alias handling until both sides work correctly
-		if nameMatchesPath(p.Name.String, path) { // || nameMatchesPath(p.Aliases.String, path) {
+		if getPerformerMatcher(p).matches(path) {
 			ret = append(ret, p)
 		}
 	}
@@ -26,14 +28,36 @@ func getMatchingPerformers(path string, performerReader models.PerformerReader)
 	return ret, nil
 }
 
+// getPerformerAliases returns the comma-separated aliases of the performer.
+// Single character aliases are ignored, since they would match too many
+// paths.
+func getPerformerAliases(p *models.Performer) []string {
+	var ret []string
+	for _, alias := range strings.Split(p.Aliases.String, ",") {
+		alias = strings.TrimSpace(alias)
+		if utf8.RuneCountInString(alias) > 1 {
+			ret = append(ret, alias)
+		}
+	}
+
+	return ret
+}
+
 func getPerformerTagger(p *models.Performer) tagger {
 	return tagger{
-		ID:   p.ID,
-		Type: "performer",
-		Name: p.Name.String,
+		ID:      p.ID,
+		Type:    "performer",
+		Name:    p.Name.String,
+		Aliases: getPerformerAliases(p),
+		Regex:   getCustomRegex(p.AutoTagRegex, "performer", p.Name.String),
 	}
 }
 
+func getPerformerMatcher(p *models.Performer) pathMatcher {
+	t := getPerformerTagger(p)
+	return t.matcher()
+}
+
 // PerformerScenes searches for scenes whose path matches the provided performer name and tags the scene with the performer.
 func PerformerScenes(p *models.Performer, paths []string, rw models.SceneReaderWriter) error {
 	t := getPerformerTagger(p)
@@ -17,11 +17,11 @@ func TestPerformerScenes(t *testing.T) {
 	performerNames := []test{
 		{
 			"performer name",
-			`(?i)(?:^|_|[^\w\d])performer[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"performer + name",
-			`(?i)(?:^|_|[^\w\d])performer[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -80,6 +80,52 @@ func testPerformerScenes(t *testing.T, performerName, expectedRegex string) {
 	mockSceneReader.AssertExpectations(t)
 }
 
+func TestPerformerScenesAliases(t *testing.T) {
+	mockSceneReader := &mocks.SceneReaderWriter{}
+
+	const performerID = 2
+	performer := models.Performer{
+		ID:      performerID,
+		Name:    models.NullString("performer name"),
+		Aliases: models.NullString("alias, x, other alias"),
+	}
+
+	scenes := []*models.Scene{
+		{ID: 1, Path: "performer name.mp4"},
+		{ID: 2, Path: "dir/alias.mp4"},
+		{ID: 3, Path: "other.alias.mp4"},
+		{ID: 4, Path: "x.mp4"},
+		{ID: 5, Path: "aliases.mp4"},
+	}
+
+	organized := false
+	perPage := models.PerPageAll
+
+	expectedSceneFilter := &models.SceneFilterType{
+		Organized: &organized,
+		Path: &models.StringCriterionInput{
+			Value:    `(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*name(?:$|[^\p{L}\p{N}])|(?:^|[^\p{L}\p{N}])alias(?:$|[^\p{L}\p{N}])|(?:^|[^\p{L}\p{N}])other[.\-_ ]*alias(?:$|[^\p{L}\p{N}])`,
+			Modifier: models.CriterionModifierMatchesRegex,
+		},
+	}
+
+	expectedFindFilter := &models.FindFilterType{
+		PerPage: &perPage,
+	}
+
+	mockSceneReader.On("Query", expectedSceneFilter, expectedFindFilter).Return(scenes, len(scenes), nil).Once()
+
+	for _, sceneID := range []int{1, 2, 3} {
+		mockSceneReader.On("GetPerformerIDs", sceneID).Return(nil, nil).Once()
+		mockSceneReader.On("UpdatePerformers", sceneID, []int{performerID}).Return(nil).Once()
+	}
+
+	err := PerformerScenes(&performer, nil, mockSceneReader)
+
+	assert.Nil(t, err)
+	mockSceneReader.AssertExpectations(t)
+}
+
 func TestPerformerImages(t *testing.T) {
 	type test struct {
 		performerName string
@@ -89,11 +135,11 @@ func TestPerformerImages(t *testing.T) {
 	performerNames := []test{
 		{
 			"performer name",
-			`(?i)(?:^|_|[^\w\d])performer[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"performer + name",
-			`(?i)(?:^|_|[^\w\d])performer[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -161,11 +207,11 @@ func TestPerformerGalleries(t *testing.T) {
 	performerNames := []test{
 		{
 			"performer name",
-			`(?i)(?:^|_|[^\w\d])performer[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"performer + name",
-			`(?i)(?:^|_|[^\w\d])performer[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])performer[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
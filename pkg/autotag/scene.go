@@ -41,8 +41,8 @@ func scenePathsFilter(paths []string) *models.SceneFilterType {
 	return ret
 }
 
-func getMatchingScenes(name string, paths []string, sceneReader models.SceneReader) ([]*models.Scene, error) {
-	regex := getPathQueryRegex(name)
+func getMatchingScenes(matcher pathMatcher, paths []string, sceneReader models.SceneReader) ([]*models.Scene, error) {
+	regex := matcher.queryRegex()
 	organized := false
 	filter := models.SceneFilterType{
 		Path: &models.StringCriterionInput{
@@ -65,7 +65,7 @@ func getMatchingScenes(name string, paths []string, sceneReader models.SceneRead
 
 	var ret []*models.Scene
 	for _, p := range scenes {
-		if nameMatchesPath(name, p.Path) {
+		if matcher.matches(p.Path) {
 			ret = append(ret, p)
 		}
 	}
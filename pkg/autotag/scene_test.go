@@ -187,6 +187,43 @@ func TestScenePerformers(t *testing.T) {
 	}
 }
 
+func TestScenePerformersAliases(t *testing.T) {
+	const sceneID = 1
+	const performerID = 2
+	performer := models.Performer{
+		ID:      performerID,
+		Name:    models.NullString("performer name"),
+		Aliases: models.NullString("alias"),
+	}
+
+	tests := []pathTestTable{
+		{"aaa.alias.bbb.mp4", true},
+		{"aaa.aliases.mp4", false},
+	}
+
+	for _, test := range tests {
+		mockPerformerReader := &mocks.PerformerReaderWriter{}
+		mockSceneReader := &mocks.SceneReaderWriter{}
+
+		mockPerformerReader.On("QueryForAutoTag", mock.Anything).Return([]*models.Performer{&performer}, nil).Once()
+
+		if test.Matches {
+			mockSceneReader.On("GetPerformerIDs", sceneID).Return(nil, nil).Once()
+			mockSceneReader.On("UpdatePerformers", sceneID, []int{performerID}).Return(nil).Once()
+		}
+
+		scene := models.Scene{
+			ID:   sceneID,
+			Path: test.Path,
+		}
+		err := ScenePerformers(&scene, mockSceneReader, mockPerformerReader)
+
+		assert.Nil(t, err)
+		mockPerformerReader.AssertExpectations(t)
+		mockSceneReader.AssertExpectations(t)
+	}
+}
+
 func TestSceneStudios(t *testing.T) {
 	const sceneID = 1
 	const studioName = "studio name"
@@ -276,3 +313,41 @@ func TestSceneTags(t *testing.T) {
 		mockSceneReader.AssertExpectations(t)
 	}
 }
+
+func TestSceneTagsRegex(t *testing.T) {
+	const sceneID = 1
+	const tagID = 2
+	tag := models.Tag{
+		ID:           tagID,
+		Name:         "double penetration",
+		AutoTagRegex: models.NullString(`\bdp\b`),
+	}
+
+	tests := []pathTestTable{
+		{"Studio.21.03.15.Performer.DP.XXX.mp4", true},
+		{"double-penetration.mp4", true},
+		{"dpi.mp4", false},
+	}
+
+	for _, test := range tests {
+		mockTagReader := &mocks.TagReaderWriter{}
+		mockSceneReader := &mocks.SceneReaderWriter{}
+
+		mockTagReader.On("QueryForAutoTag", mock.Anything).Return([]*models.Tag{&tag}, nil).Once()
+
+		if test.Matches {
+			mockSceneReader.On("GetTagIDs", sceneID).Return(nil, nil).Once()
+			mockSceneReader.On("UpdateTags", sceneID, []int{tagID}).Return(nil).Once()
+		}
+
+		scene := models.Scene{
+			ID:   sceneID,
+			Path: test.Path,
+		}
+		err := SceneTags(&scene, mockSceneReader, mockTagReader)
+
+		assert.Nil(t, err)
+		mockTagReader.AssertExpectations(t)
+		mockSceneReader.AssertExpectations(t)
+	}
+}
@@ -17,11 +17,11 @@ func TestStudioScenes(t *testing.T) {
 	studioNames := []test{
 		{
 			"studio name",
-			`(?i)(?:^|_|[^\w\d])studio[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])studio[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"studio + name",
-			`(?i)(?:^|_|[^\w\d])studio[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])studio[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -93,11 +93,11 @@ func TestStudioImages(t *testing.T) {
 	studioNames := []test{
 		{
 			"studio name",
-			`(?i)(?:^|_|[^\w\d])studio[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])studio[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"studio + name",
-			`(?i)(?:^|_|[^\w\d])studio[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])studio[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -169,11 +169,11 @@ func TestStudioGalleries(t *testing.T) {
 	studioNames := []test{
 		{
 			"studio name",
-			`(?i)(?:^|_|[^\w\d])studio[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])studio[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"studio + name",
-			`(?i)(?:^|_|[^\w\d])studio[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])studio[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
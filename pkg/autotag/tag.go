@@ -17,7 +17,7 @@ func getMatchingTags(path string, tagReader models.TagReader) ([]*models.Tag, er
 
 	var ret []*models.Tag
 	for _, p := range tags {
-		if nameMatchesPath(p.Name, path) {
+		if getTagMatcher(p).matches(path) {
 			ret = append(ret, p)
 		}
 	}
@@ -27,12 +27,18 @@ func getMatchingTags(path string, tagReader models.TagReader) ([]*models.Tag, er
 
 func getTagTagger(p *models.Tag) tagger {
 	return tagger{
-		ID:   p.ID,
-		Type: "tag",
-		Name: p.Name,
+		ID:    p.ID,
+		Type:  "tag",
+		Name:  p.Name,
+		Regex: getCustomRegex(p.AutoTagRegex, "tag", p.Name),
 	}
 }
 
+func getTagMatcher(p *models.Tag) pathMatcher {
+	t := getTagTagger(p)
+	return t.matcher()
+}
+
 // TagScenes searches for scenes whose path matches the provided tag name and tags the scene with the tag.
 func TagScenes(p *models.Tag, paths []string, rw models.SceneReaderWriter) error {
 	t := getTagTagger(p)
@@ -17,11 +17,11 @@ func TestTagScenes(t *testing.T) {
 	tagNames := []test{
 		{
 			"tag name",
-			`(?i)(?:^|_|[^\w\d])tag[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])tag[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"tag + name",
-			`(?i)(?:^|_|[^\w\d])tag[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])tag[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -89,11 +89,11 @@ func TestTagImages(t *testing.T) {
 	tagNames := []test{
 		{
 			"tag name",
-			`(?i)(?:^|_|[^\w\d])tag[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])tag[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"tag + name",
-			`(?i)(?:^|_|[^\w\d])tag[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])tag[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -161,11 +161,11 @@ func TestTagGalleries(t *testing.T) {
 	tagNames := []test{
 		{
 			"tag name",
-			`(?i)(?:^|_|[^\w\d])tag[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])tag[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 		{
 			"tag + name",
-			`(?i)(?:^|_|[^\w\d])tag[.\-_ ]*\+[.\-_ ]*name(?:$|_|[^\w\d])`,
+			`(?i)(?:^|[^\p{L}\p{N}])tag[.\-_ ]*\+[.\-_ ]*name(?:$|[^\p{L}\p{N}])`,
 		},
 	}
 
@@ -4,26 +4,90 @@
 // The autotag engine tags scenes with performers/studios/tags if the scene's
 // path matches the performer/studio/tag name. A scene's path is considered
 // a match if it contains the performer/studio/tag's full name, ignoring any
-// '.', '-', '_' characters in the path.
+// separator characters in the path. The separators default to '.', '-', '_'
+// and space, and are set with ConfigureSeparators.
 //
 // For example, for a performer "foo bar", the following paths would be
 // considered a match: "foo bar.mp4", "foobar.mp4", "foo.bar.mp4",
 // "foo-bar.mp4", "aaa.foo bar.bbb.mp4".
 // The following would not be considered a match:
 // "aafoo bar.mp4", "foo barbb.mp4", "foo/bar.mp4"
+//
+// Names only match whole words, so that short names such as "max" don't
+// match paths such as "climax.mp4". Letters and digits of any script are part
+// of words. Performers also match their aliases.
+//
+// Performers and tags may also have a custom regular expression, which is
+// matched against paths case-insensitively in addition to their names.
 package autotag
 
 import (
+	"database/sql"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
 
-const separatorChars = `.\-_ `
+// DefaultSeparators are the characters which may separate, or be omitted
+// between, the words of a name in a path.
+const DefaultSeparators = ".-_ "
+
+var (
+	separatorMutex sync.RWMutex
+	separatorClass = getSeparatorClass(DefaultSeparators)
+)
+
+// ConfigureSeparators sets the characters which may separate the words of a
+// name in a path. Space is always a separator. The default separators are
+// used if chars is empty.
+func ConfigureSeparators(chars string) {
+	if chars == "" {
+		chars = DefaultSeparators
+	}
+
+	separatorMutex.Lock()
+	defer separatorMutex.Unlock()
+	separatorClass = getSeparatorClass(chars)
+}
+
+// getSeparatorClass returns the regex character class matching any of chars
+// or a space.
+func getSeparatorClass(chars string) string {
+	if !strings.ContainsRune(chars, ' ') {
+		chars += " "
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	for _, c := range chars {
+		if strings.ContainsRune(`\-[]^`, c) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteString("]")
+
+	return b.String()
+}
+
+func getSeparatorClassValue() string {
+	separatorMutex.RLock()
+	defer separatorMutex.RUnlock()
+	return separatorClass
+}
+
+// nameStart and nameEnd match the start and end of a name in a path: anything
+// but a letter or digit.
+const (
+	nameStart = `(?:^|[^\p{L}\p{N}])`
+	nameEnd   = `(?:$|[^\p{L}\p{N}])`
+)
 
 // fixes #1292
 func escapePathRegex(name string) string {
@@ -43,28 +107,97 @@ func getPathQueryRegex(name string) string {
 	name = escapePathRegex(name)
 
 	// handle path separators
-	const separator = `[` + separatorChars + `]`
+	separator := getSeparatorClassValue()
 
 	ret := strings.Replace(name, " ", separator+"*", -1)
-	ret = `(?:^|_|[^\w\d])` + ret + `(?:$|_|[^\w\d])`
+	ret = nameStart + ret + nameEnd
 	return ret
 }
 
+// getNamesQueryRegex returns the regex matching paths which match any of the
+// names.
+func getNamesQueryRegex(names []string) string {
+	var ret []string
+	for _, name := range names {
+		ret = append(ret, getPathQueryRegex(name))
+	}
+
+	return strings.Join(ret, "|")
+}
+
 func nameMatchesPath(name, path string) bool {
-	// escape specific regex characters
-	name = escapePathRegex(name)
+	re := regexp.MustCompile("(?i)" + getPathQueryRegex(name))
+	return re.MatchString(path)
+}
 
-	name = strings.ToLower(name)
-	path = strings.ToLower(path)
+func anyNameMatchesPath(names []string, path string) bool {
+	for _, name := range names {
+		if nameMatchesPath(name, path) {
+			return true
+		}
+	}
 
-	// handle path separators
-	const separator = `[` + separatorChars + `]`
+	return false
+}
 
-	reStr := strings.Replace(name, " ", separator+"*", -1)
-	reStr = `(?:^|_|[^\w\d])` + reStr + `(?:$|_|[^\w\d])`
+// ValidateRegex returns an error if regex is not a valid custom auto-tag
+// regular expression.
+func ValidateRegex(regex *string) error {
+	if regex == nil || *regex == "" {
+		return nil
+	}
 
-	re := regexp.MustCompile(reStr)
-	return re.MatchString(path)
+	if _, err := regexp.Compile("(?i)" + *regex); err != nil {
+		return fmt.Errorf("invalid auto-tag regex: %s", err.Error())
+	}
+
+	return nil
+}
+
+// getCustomRegex returns the custom auto-tag regex of an object, or an empty
+// string if it is unset or invalid.
+func getCustomRegex(regex sql.NullString, objectType string, name string) string {
+	if !regex.Valid || regex.String == "" {
+		return ""
+	}
+
+	if err := ValidateRegex(&regex.String); err != nil {
+		logger.Warnf("Ignoring auto-tag regex of %s '%s': %s", objectType, name, err.Error())
+		return ""
+	}
+
+	return regex.String
+}
+
+// pathMatcher matches paths containing any of its names, or matching its
+// custom regex if set.
+type pathMatcher struct {
+	names []string
+	regex string
+}
+
+// queryRegex returns the regex matching paths which match the pathMatcher,
+// without the case-insensitive flag.
+func (m pathMatcher) queryRegex() string {
+	ret := getNamesQueryRegex(m.names)
+	if m.regex != "" {
+		ret += "|(?:" + m.regex + ")"
+	}
+
+	return ret
+}
+
+func (m pathMatcher) matches(path string) bool {
+	if anyNameMatchesPath(m.names, path) {
+		return true
+	}
+
+	if m.regex == "" {
+		return false
+	}
+
+	re, err := regexp.Compile("(?i)" + m.regex)
+	return err == nil && re.MatchString(path)
 }
 
 func getPathWords(path string) []string {
@@ -76,8 +209,8 @@ func getPathWords(path string) []string {
 		retStr = strings.TrimSuffix(retStr, ext)
 	}
 
-	// handle path separators
-	const separator = `(?:_|[^\w\d])+`
+	// split on anything but letters and digits
+	const separator = `[^\p{L}\p{N}]+`
 	re := regexp.MustCompile(separator)
 	retStr = re.ReplaceAllString(retStr, " ")
 
@@ -86,7 +219,7 @@ func getPathWords(path string) []string {
 	// remove any single letter words
 	var ret []string
 	for _, w := range words {
-		if len(w) > 1 {
+		if utf8.RuneCountInString(w) > 1 {
 			ret = append(ret, w)
 		}
 	}
@@ -98,7 +231,18 @@ type tagger struct {
 	ID   int
 	Type string
 	Name string
-	Path string
+	// Aliases are matched in addition to the name
+	Aliases []string
+	// Regex is a custom regex matched in addition to the name
+	Regex string
+	Path  string
+}
+
+func (t *tagger) matcher() pathMatcher {
+	return pathMatcher{
+		names: append([]string{t.Name}, t.Aliases...),
+		regex: t.Regex,
+	}
 }
 
 type addLinkFunc func(subjectID, otherID int) (bool, error)
@@ -177,7 +321,7 @@ func (t *tagger) tagTags(tagReader models.TagReader, addFunc addLinkFunc) error
 }
 
 func (t *tagger) tagScenes(paths []string, sceneReader models.SceneReader, addFunc addLinkFunc) error {
-	others, err := getMatchingScenes(t.Name, paths, sceneReader)
+	others, err := getMatchingScenes(t.matcher(), paths, sceneReader)
 	if err != nil {
 		return err
 	}
@@ -198,7 +342,7 @@ func (t *tagger) tagScenes(paths []string, sceneReader models.SceneReader, addFu
 }
 
 func (t *tagger) tagImages(paths []string, imageReader models.ImageReader, addFunc addLinkFunc) error {
-	others, err := getMatchingImages(t.Name, paths, imageReader)
+	others, err := getMatchingImages(t.matcher(), paths, imageReader)
 	if err != nil {
 		return err
 	}
@@ -219,7 +363,7 @@ func (t *tagger) tagImages(paths []string, imageReader models.ImageReader, addFu
 }
 
 func (t *tagger) tagGalleries(paths []string, galleryReader models.GalleryReader, addFunc addLinkFunc) error {
-	others, err := getMatchingGalleries(t.Name, paths, galleryReader)
+	others, err := getMatchingGalleries(t.matcher(), paths, galleryReader)
 	if err != nil {
 		return err
 	}
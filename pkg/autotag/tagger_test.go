@@ -0,0 +1,90 @@
+package autotag
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameMatchesPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		matches bool
+	}{
+		{"max", "max.mp4", true},
+		{"max", "climax.mp4", false},
+		{"max", "maximum.mp4", false},
+		{"max", "dir/Max_2.mp4", true},
+		{"max", "max2.mp4", false},
+		// letters of other scripts are part of words
+		{"ana", "mañana.mp4", false},
+		{"ana", "ñ ana.mp4", true},
+		{"zoë", "Zoë.mp4", true},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.matches, nameMatchesPath(tc.name, tc.path), "%s in %s", tc.name, tc.path)
+	}
+}
+
+func TestGetSeparatorClass(t *testing.T) {
+	assert.Equal(t, `[.\-_ ]`, getSeparatorClass(DefaultSeparators))
+	// space is always a separator
+	assert.Equal(t, `[+ ]`, getSeparatorClass("+"))
+	assert.Equal(t, `[\[\]\^\\ ]`, getSeparatorClass(`[]^\ `))
+}
+
+func TestConfigureSeparators(t *testing.T) {
+	defer ConfigureSeparators("")
+
+	assert.False(t, nameMatchesPath("foo bar", "foo+bar.mp4"))
+
+	ConfigureSeparators("+")
+	assert.True(t, nameMatchesPath("foo bar", "foo+bar.mp4"))
+	assert.True(t, nameMatchesPath("foo bar", "foobar.mp4"))
+	assert.False(t, nameMatchesPath("foo bar", "foo.bar.mp4"))
+
+	ConfigureSeparators("")
+	assert.True(t, nameMatchesPath("foo bar", "foo.bar.mp4"))
+}
+
+func TestValidateRegex(t *testing.T) {
+	valid := `\bdp\b`
+	invalid := `(dp`
+	empty := ""
+
+	assert.Nil(t, ValidateRegex(nil))
+	assert.Nil(t, ValidateRegex(&empty))
+	assert.Nil(t, ValidateRegex(&valid))
+	assert.NotNil(t, ValidateRegex(&invalid))
+}
+
+func TestPathMatcher(t *testing.T) {
+	m := pathMatcher{
+		names: []string{"double penetration"},
+		regex: `\bdp\b`,
+	}
+
+	assert.Equal(t, `(?:^|[^\p{L}\p{N}])double[.\-_ ]*penetration(?:$|[^\p{L}\p{N}])|(?:\bdp\b)`, m.queryRegex())
+	assert.True(t, m.matches("Double.Penetration.mp4"))
+	assert.True(t, m.matches("Studio.21.03.15.DP.XXX.mp4"))
+	assert.False(t, m.matches("dpi.mp4"))
+
+	// an empty regex matches names only
+	m.regex = ""
+	assert.False(t, m.matches("dp.mp4"))
+}
+
+func TestGetCustomRegex(t *testing.T) {
+	assert.Equal(t, "", getCustomRegex(sql.NullString{}, "tag", "name"))
+	assert.Equal(t, "", getCustomRegex(models.NullString("(dp"), "tag", "name"))
+	assert.Equal(t, "dp", getCustomRegex(models.NullString("dp"), "tag", "name"))
+}
+
+func TestGetPathWords(t *testing.T) {
+	assert.Equal(t, []string{"Studio", "21", "03", "15", "Zoë", "Name", "XXX", "1080p"}, getPathWords("Studio.21.03.15.Zoë_Name.a.XXX.1080p.mp4"))
+	assert.Equal(t, []string{"mañana"}, getPathWords("[mañana].mp4"))
+}
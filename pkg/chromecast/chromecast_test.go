@@ -0,0 +1,237 @@
+package chromecast
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	m := castMessage{
+		SourceID:      senderID,
+		DestinationID: receiverID,
+		Namespace:     namespaceReceiver,
+		Payload:       `{"type":"LAUNCH","appId":"` + strings.Repeat("a", 200) + `"}`,
+	}
+
+	var buf bytes.Buffer
+	if !assert.Nil(t, writeMessage(&buf, m)) {
+		return
+	}
+
+	read, err := readMessage(&buf)
+	if assert.Nil(t, err) {
+		assert.Equal(t, m, *read)
+	}
+
+	_, err = unmarshalMessage([]byte{0x12, 0x05, 'a'})
+	assert.NotNil(t, err)
+}
+
+// dnsName encodes a name without compression.
+func dnsName(name string) []byte {
+	return makeQuery(name)[12 : len(makeQuery(name))-4]
+}
+
+func dnsRecord(name []byte, recordType uint16, data []byte) []byte {
+	b := append([]byte{}, name...)
+	header := make([]byte, 10)
+	binary.BigEndian.PutUint16(header, recordType)
+	binary.BigEndian.PutUint16(header[2:], dnsClassIN)
+	binary.BigEndian.PutUint32(header[4:], 120)
+	binary.BigEndian.PutUint16(header[8:], uint16(len(data)))
+	b = append(b, header...)
+	return append(b, data...)
+}
+
+func TestParseResponse(t *testing.T) {
+	const instance = "Chromecast-abc._googlecast._tcp.local"
+	const target = "abc.local"
+
+	msg := []byte{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 3}
+
+	// the PTR record of the service, with the instance name compressed as a
+	// pointer to the service name
+	serviceOffset := len(msg)
+	ptrData := append([]byte{14}, "Chromecast-abc"...)
+	ptrData = append(ptrData, 0xc0, byte(serviceOffset))
+	msg = append(msg, dnsRecord(dnsName(castService), dnsTypePTR, ptrData)...)
+
+	txt := []byte{}
+	for _, kv := range []string{"id=abc", "fn=Living Room", "md=Chromecast Ultra", "invalid"} {
+		txt = append(txt, byte(len(kv)))
+		txt = append(txt, kv...)
+	}
+	msg = append(msg, dnsRecord(dnsName(instance), dnsTypeTXT, txt)...)
+
+	srv := []byte{0, 0, 0, 0, 0x1f, 0x49}
+	srv = append(srv, dnsName(target)...)
+	msg = append(msg, dnsRecord(dnsName(instance), dnsTypeSRV, srv)...)
+
+	msg = append(msg, dnsRecord(dnsName(target), dnsTypeA, []byte{192, 168, 1, 20})...)
+
+	devices, err := parseResponse(msg)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []Device{
+			{
+				ID:    "abc",
+				Name:  "Living Room",
+				Model: "Chromecast Ultra",
+				Host:  "192.168.1.20",
+				Port:  8009,
+			},
+		}, devices)
+	}
+
+	_, err = parseResponse(msg[:len(msg)-2])
+	assert.NotNil(t, err)
+}
+
+// fakeDevice answers the requests of a client as the default media
+// receiver would, sending a heartbeat ping before each response, until the
+// client disconnects.
+func fakeDevice(t *testing.T, conn net.Conn, loadResponse string, received *[]map[string]interface{}) {
+	defer conn.Close()
+
+	// pipes are unbuffered, so responses are written while the pong from
+	// the client is read
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	respond := func(destination string, namespace string, requestID float64, payload map[string]interface{}) {
+		payload["requestId"] = requestID
+		data, _ := json.Marshal(payload)
+
+		wg.Wait()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = writeMessage(conn, castMessage{
+				SourceID:      receiverID,
+				DestinationID: senderID,
+				Namespace:     namespaceHeartbeat,
+				Payload:       `{"type":"PING"}`,
+			})
+			_ = writeMessage(conn, castMessage{
+				SourceID:      destination,
+				DestinationID: senderID,
+				Namespace:     namespace,
+				Payload:       string(data),
+			})
+		}()
+	}
+
+	for {
+		msg, err := readMessage(conn)
+		if err != nil {
+			return
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(msg.Payload), &payload); err != nil {
+			t.Errorf("Error decoding payload: %s", err.Error())
+			return
+		}
+		*received = append(*received, payload)
+
+		requestID, _ := payload["requestId"].(float64)
+		switch payload["type"] {
+		case "LAUNCH":
+			respond(msg.DestinationID, msg.Namespace, requestID, map[string]interface{}{
+				"type": "RECEIVER_STATUS",
+				"status": map[string]interface{}{
+					"applications": []map[string]interface{}{
+						{"appId": defaultMediaReceiverAppID, "transportId": "transport-1"},
+					},
+				},
+			})
+		case "LOAD":
+			if msg.DestinationID != "transport-1" {
+				t.Errorf("LOAD sent to %s", msg.DestinationID)
+			}
+			respond(msg.DestinationID, msg.Namespace, requestID, map[string]interface{}{
+				"type": loadResponse,
+			})
+		}
+	}
+}
+
+func testLoad(t *testing.T, loadResponse string, media Media) ([]map[string]interface{}, error) {
+	var received []map[string]interface{}
+	done := make(chan struct{})
+
+	client := &Client{
+		Addr: "device:8009",
+		dial: func(ctx context.Context, addr string) (net.Conn, error) {
+			clientConn, deviceConn := net.Pipe()
+			go func() {
+				fakeDevice(t, deviceConn, loadResponse, &received)
+				close(done)
+			}()
+			return clientConn, nil
+		},
+	}
+
+	err := client.Load(context.Background(), media)
+	<-done
+	return received, err
+}
+
+func TestLoad(t *testing.T) {
+	received, err := testLoad(t, "MEDIA_STATUS", Media{
+		URL:         "http://192.168.1.10:9999/scene/1/stream",
+		ContentType: "video/mp4",
+		Title:       "title",
+		StartTime:   10,
+		Tracks: []Track{
+			{ID: 1, URL: "http://192.168.1.10:9999/scene/1/caption/1", Name: "English", Language: "en"},
+		},
+	})
+	assert.Nil(t, err)
+
+	var types []interface{}
+	var load map[string]interface{}
+	pongs := 0
+	for _, r := range received {
+		switch r["type"] {
+		case "PONG":
+			pongs++
+			continue
+		case "LOAD":
+			load = r
+		}
+		types = append(types, r["type"])
+	}
+	assert.Equal(t, []interface{}{"CONNECT", "LAUNCH", "CONNECT", "LOAD"}, types)
+	// the heartbeat pings are answered
+	assert.Equal(t, 2, pongs)
+	if load == nil {
+		return
+	}
+
+	assert.Equal(t, float64(10), load["currentTime"])
+	media := load["media"].(map[string]interface{})
+	assert.Equal(t, "http://192.168.1.10:9999/scene/1/stream", media["contentId"])
+	assert.Equal(t, "video/mp4", media["contentType"])
+	tracks := media["tracks"].([]interface{})
+	if assert.Len(t, tracks, 1) {
+		track := tracks[0].(map[string]interface{})
+		assert.Equal(t, "text/vtt", track["trackContentType"])
+		assert.Equal(t, "en", track["language"])
+	}
+}
+
+func TestLoadFailed(t *testing.T) {
+	_, err := testLoad(t, "LOAD_FAILED", Media{
+		URL:         "http://192.168.1.10:9999/scene/1/stream",
+		ContentType: "video/mp4",
+	})
+	assert.NotNil(t, err)
+}
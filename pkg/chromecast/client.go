@@ -0,0 +1,284 @@
+// Package chromecast discovers cast devices on the local network and loads
+// media on them with the default media receiver.
+package chromecast
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	namespaceConnection = "urn:x-cast:com.google.cast.tp.connection"
+	namespaceHeartbeat  = "urn:x-cast:com.google.cast.tp.heartbeat"
+	namespaceReceiver   = "urn:x-cast:com.google.cast.receiver"
+	namespaceMedia      = "urn:x-cast:com.google.cast.media"
+
+	senderID   = "sender-0"
+	receiverID = "receiver-0"
+
+	// defaultMediaReceiverAppID is the application which plays media loaded
+	// by senders.
+	defaultMediaReceiverAppID = "CC1AD845"
+
+	// timeout for the device to launch the receiver and load the media if
+	// the context has no deadline
+	defaultLoadTimeout = 30 * time.Second
+)
+
+// Media is the media to load on a device.
+type Media struct {
+	URL         string
+	ContentType string
+	Title       string
+	ImageURL    string
+	// StartTime is the position in seconds from which playback starts.
+	StartTime float64
+	Tracks    []Track
+}
+
+// Track is a WebVTT subtitle track of the media.
+type Track struct {
+	ID       int
+	URL      string
+	Name     string
+	Language string
+}
+
+// Client connects to a cast device.
+type Client struct {
+	Addr string
+
+	dial func(ctx context.Context, addr string) (net.Conn, error)
+}
+
+// NewClient returns a client of the device at host and port.
+func NewClient(host string, port int) *Client {
+	if port == 0 {
+		port = defaultCastPort
+	}
+
+	return &Client{
+		Addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		dial: dialTLS,
+	}
+}
+
+// LocalIP returns the IP address of the local interface through which the
+// host is reached.
+func LocalIP(host string) (net.IP, error) {
+	// no packets are sent to connect a UDP socket
+	conn, err := net.Dial("udp", net.JoinHostPort(host, strconv.Itoa(defaultCastPort)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// dialTLS connects to the device. Devices have self-signed certificates, so
+// the certificate is not verified.
+func dialTLS(ctx context.Context, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		InsecureSkipVerify: true, // nolint:gosec
+	})
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+// session is a connection to a device, on which requests are made and their
+// responses awaited.
+type session struct {
+	conn      net.Conn
+	requestID int
+}
+
+func (s *session) send(destinationID string, namespace string, payload map[string]interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return writeMessage(s.conn, castMessage{
+		SourceID:      senderID,
+		DestinationID: destinationID,
+		Namespace:     namespace,
+		Payload:       string(data),
+	})
+}
+
+// request sends the payload with a new request ID and returns the response
+// with the same ID. Heartbeat pings received in the meantime are answered.
+func (s *session) request(destinationID string, namespace string, payload map[string]interface{}) (map[string]interface{}, error) {
+	s.requestID++
+	payload["requestId"] = s.requestID
+	if err := s.send(destinationID, namespace, payload); err != nil {
+		return nil, err
+	}
+
+	for {
+		msg, err := readMessage(s.conn)
+		if err != nil {
+			return nil, err
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal([]byte(msg.Payload), &response); err != nil {
+			continue
+		}
+
+		if msg.Namespace == namespaceHeartbeat && response["type"] == "PING" {
+			if err := s.send(msg.SourceID, namespaceHeartbeat, map[string]interface{}{"type": "PONG"}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if id, ok := response["requestId"].(float64); ok && int(id) == s.requestID {
+			return response, nil
+		}
+	}
+}
+
+// Load launches the default media receiver on the device and loads the
+// media, returning once the device has started loading it. Playback
+// continues after the client disconnects.
+func (c *Client) Load(ctx context.Context, media Media) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultLoadTimeout)
+		defer cancel()
+	}
+
+	conn, err := c.dial(ctx, c.Addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s: %s", c.Addr, err.Error())
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	s := &session{conn: conn}
+	if err := s.send(receiverID, namespaceConnection, map[string]interface{}{"type": "CONNECT"}); err != nil {
+		return err
+	}
+
+	status, err := s.request(receiverID, namespaceReceiver, map[string]interface{}{
+		"type":  "LAUNCH",
+		"appId": defaultMediaReceiverAppID,
+	})
+	if err != nil {
+		return fmt.Errorf("error launching media receiver: %s", err.Error())
+	}
+
+	transportID := receiverTransportID(status)
+	if transportID == "" {
+		return fmt.Errorf("media receiver was not launched: %v", status["type"])
+	}
+
+	if err := s.send(transportID, namespaceConnection, map[string]interface{}{"type": "CONNECT"}); err != nil {
+		return err
+	}
+
+	response, err := s.request(transportID, namespaceMedia, loadRequest(media))
+	if err != nil {
+		return fmt.Errorf("error loading media: %s", err.Error())
+	}
+
+	if response["type"] != "MEDIA_STATUS" {
+		return fmt.Errorf("device could not load media: %v", response["type"])
+	}
+
+	return nil
+}
+
+// receiverTransportID returns the transport ID of the default media
+// receiver from the receiver status, or the empty string if it is not
+// running.
+func receiverTransportID(status map[string]interface{}) string {
+	if status["type"] != "RECEIVER_STATUS" {
+		return ""
+	}
+
+	s, _ := status["status"].(map[string]interface{})
+	apps, _ := s["applications"].([]interface{})
+	for _, a := range apps {
+		app, _ := a.(map[string]interface{})
+		if app["appId"] == defaultMediaReceiverAppID {
+			id, _ := app["transportId"].(string)
+			return id
+		}
+	}
+
+	return ""
+}
+
+func loadRequest(media Media) map[string]interface{} {
+	metadata := map[string]interface{}{
+		// generic media
+		"metadataType": 0,
+		"title":        media.Title,
+	}
+	if media.ImageURL != "" {
+		metadata["images"] = []map[string]interface{}{
+			{"url": media.ImageURL},
+		}
+	}
+
+	info := map[string]interface{}{
+		"contentId":   media.URL,
+		"contentType": media.ContentType,
+		"streamType":  "BUFFERED",
+		"metadata":    metadata,
+	}
+
+	if len(media.Tracks) > 0 {
+		var tracks []map[string]interface{}
+		for _, t := range media.Tracks {
+			track := map[string]interface{}{
+				"trackId":          t.ID,
+				"type":             "TEXT",
+				"subtype":          "SUBTITLES",
+				"trackContentId":   t.URL,
+				"trackContentType": "text/vtt",
+				"name":             t.Name,
+			}
+			if t.Language != "" {
+				track["language"] = t.Language
+			}
+			tracks = append(tracks, track)
+		}
+		info["tracks"] = tracks
+	}
+
+	return map[string]interface{}{
+		"type":        "LOAD",
+		"media":       info,
+		"autoplay":    true,
+		"currentTime": media.StartTime,
+	}
+}
@@ -0,0 +1,276 @@
+package chromecast
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	castService = "_googlecast._tcp.local"
+
+	// DefaultDiscoveryTimeout is how long Discover waits for responses if
+	// the context has no deadline.
+	DefaultDiscoveryTimeout = 3 * time.Second
+
+	defaultCastPort = 8009
+)
+
+var mdnsAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+const (
+	dnsTypeA   = 1
+	dnsTypePTR = 12
+	dnsTypeTXT = 16
+	dnsTypeSRV = 33
+	dnsClassIN = 1
+)
+
+// Device is a cast device found on the local network.
+type Device struct {
+	ID    string
+	Name  string
+	Model string
+	Host  string
+	Port  int
+}
+
+// Discover queries the local network for cast devices with multicast DNS,
+// and returns the devices which respond before the context is done, or
+// before DefaultDiscoveryTimeout if the context has no deadline. The query
+// is sent from an ephemeral port, so that devices respond directly to it.
+func Discover(ctx context.Context) ([]Device, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultDiscoveryTimeout)
+		defer cancel()
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.WriteToUDP(makeQuery(castService), mdnsAddr); err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]Device)
+	buf := make([]byte, 9000)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, err
+		}
+
+		found, err := parseResponse(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, d := range found {
+			if d.Host == "" {
+				d.Host = addr.IP.String()
+			}
+			devices[d.ID] = d
+		}
+	}
+
+	var ret []Device
+	for _, d := range devices {
+		ret = append(ret, d)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret, nil
+}
+
+// makeQuery returns a DNS query for the PTR records of the service.
+func makeQuery(service string) []byte {
+	// id, flags, one question and no records
+	b := []byte{0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0}
+	for _, label := range strings.Split(service, ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	b = append(b, 0)
+	b = append(b, 0, dnsTypePTR, 0, dnsClassIN)
+	return b
+}
+
+var errInvalidResponse = errors.New("invalid dns response")
+
+// readName reads the possibly compressed name at offset of the message,
+// returning the name and the offset following it.
+func readName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if offset >= len(msg) {
+			return "", 0, errInvalidResponse
+		}
+
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) || jumps > 10 {
+				return "", 0, errInvalidResponse
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:]) & 0x3fff)
+			jumps++
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, errInvalidResponse
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
+
+type srvRecord struct {
+	port   int
+	target string
+}
+
+// parseResponse returns the cast devices described by the records of the
+// mDNS response.
+func parseResponse(msg []byte) ([]Device, error) {
+	if len(msg) < 12 {
+		return nil, errInvalidResponse
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:]))
+	records := int(binary.BigEndian.Uint16(msg[6:])) + int(binary.BigEndian.Uint16(msg[8:])) + int(binary.BigEndian.Uint16(msg[10:]))
+
+	offset := 12
+	for i := 0; i < questions; i++ {
+		_, next, err := readName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = next + 4
+	}
+
+	var instances []string
+	srv := make(map[string]srvRecord)
+	txt := make(map[string]map[string]string)
+	hosts := make(map[string]string)
+
+	for i := 0; i < records; i++ {
+		name, next, err := readName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(msg) {
+			return nil, errInvalidResponse
+		}
+
+		recordType := binary.BigEndian.Uint16(msg[next:])
+		length := int(binary.BigEndian.Uint16(msg[next+8:]))
+		start := next + 10
+		end := start + length
+		if end > len(msg) {
+			return nil, errInvalidResponse
+		}
+		data := msg[start:end]
+
+		switch recordType {
+		case dnsTypePTR:
+			if strings.EqualFold(name, castService) {
+				instance, _, err := readName(msg, start)
+				if err != nil {
+					return nil, err
+				}
+				instances = append(instances, instance)
+			}
+		case dnsTypeSRV:
+			if len(data) < 7 {
+				return nil, errInvalidResponse
+			}
+			target, _, err := readName(msg, start+6)
+			if err != nil {
+				return nil, err
+			}
+			srv[name] = srvRecord{
+				port:   int(binary.BigEndian.Uint16(data[4:])),
+				target: target,
+			}
+		case dnsTypeTXT:
+			txt[name] = parseTXT(data)
+		case dnsTypeA:
+			if len(data) == 4 {
+				hosts[name] = net.IP(data).String()
+			}
+		}
+
+		offset = end
+	}
+
+	var ret []Device
+	for _, instance := range instances {
+		values := txt[instance]
+		d := Device{
+			ID:    values["id"],
+			Name:  values["fn"],
+			Model: values["md"],
+			Port:  defaultCastPort,
+		}
+		if d.ID == "" {
+			d.ID = instance
+		}
+		if d.Name == "" {
+			d.Name = strings.TrimSuffix(instance, "."+castService)
+		}
+		if s, found := srv[instance]; found {
+			d.Port = s.port
+			d.Host = hosts[s.target]
+		}
+
+		ret = append(ret, d)
+	}
+
+	return ret, nil
+}
+
+// parseTXT returns the key=value strings of a TXT record.
+func parseTXT(data []byte) map[string]string {
+	ret := make(map[string]string)
+	for len(data) > 0 {
+		length := int(data[0])
+		if 1+length > len(data) {
+			break
+		}
+
+		kv := string(data[1 : 1+length])
+		if i := strings.Index(kv, "="); i > 0 {
+			ret[kv[:i]] = kv[i+1:]
+		}
+		data = data[1+length:]
+	}
+
+	return ret
+}
@@ -0,0 +1,156 @@
+package chromecast
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxMessageSize is the largest message accepted from a device.
+const maxMessageSize = 64 * 1024
+
+// castMessage is the CastMessage protocol buffer exchanged with devices.
+// Only string payloads are used, so the message is encoded by hand rather
+// than with generated code:
+//
+//	message CastMessage {
+//	  required ProtocolVersion protocol_version = 1; // CASTV2_1_0 = 0
+//	  required string source_id = 2;
+//	  required string destination_id = 3;
+//	  required string namespace = 4;
+//	  required PayloadType payload_type = 5; // STRING = 0
+//	  optional string payload_utf8 = 6;
+//	}
+type castMessage struct {
+	SourceID      string
+	DestinationID string
+	Namespace     string
+	Payload       string
+}
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendString(b []byte, field int, s string) []byte {
+	b = appendVarint(b, uint64(field<<3|wireBytes))
+	b = appendVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func (m castMessage) marshal() []byte {
+	var b []byte
+	// protocol_version = CASTV2_1_0
+	b = appendVarint(b, 1<<3|wireVarint)
+	b = appendVarint(b, 0)
+	b = appendString(b, 2, m.SourceID)
+	b = appendString(b, 3, m.DestinationID)
+	b = appendString(b, 4, m.Namespace)
+	// payload_type = STRING
+	b = appendVarint(b, 5<<3|wireVarint)
+	b = appendVarint(b, 0)
+	return appendString(b, 6, m.Payload)
+}
+
+var errInvalidMessage = errors.New("invalid cast message")
+
+func readVarint(b []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * uint(i))
+		if b[i] < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+
+	return 0, 0, errInvalidMessage
+}
+
+func unmarshalMessage(b []byte) (*castMessage, error) {
+	ret := &castMessage{}
+	for len(b) > 0 {
+		tag, n, err := readVarint(b)
+		if err != nil {
+			return nil, err
+		}
+		b = b[n:]
+
+		field := int(tag >> 3)
+		switch tag & 7 {
+		case wireVarint:
+			_, n, err = readVarint(b)
+			if err != nil {
+				return nil, err
+			}
+		case wireFixed64:
+			n = 8
+		case wireFixed32:
+			n = 4
+		case wireBytes:
+			length, ln, err := readVarint(b)
+			if err != nil || uint64(len(b)-ln) < length {
+				return nil, errInvalidMessage
+			}
+			value := string(b[ln : ln+int(length)])
+			n = ln + int(length)
+
+			switch field {
+			case 2:
+				ret.SourceID = value
+			case 3:
+				ret.DestinationID = value
+			case 4:
+				ret.Namespace = value
+			case 6:
+				ret.Payload = value
+			}
+		default:
+			return nil, errInvalidMessage
+		}
+
+		if n > len(b) {
+			return nil, errInvalidMessage
+		}
+		b = b[n:]
+	}
+
+	return ret, nil
+}
+
+// writeMessage writes the message prefixed with its big-endian length.
+func writeMessage(w io.Writer, m castMessage) error {
+	data := m.marshal()
+	buf := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	_, err := w.Write(append(buf, data...))
+	return err
+}
+
+func readMessage(r io.Reader) (*castMessage, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length > maxMessageSize {
+		return nil, fmt.Errorf("cast message of %d bytes is too large", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	return unmarshalMessage(data)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -23,7 +24,7 @@ import (
 var DB *sqlx.DB
 var WriteMu *sync.Mutex
 var dbPath string
-var appSchemaVersion uint = 22
+var appSchemaVersion uint = 42
 var databaseSchemaVersion uint
 
 var (
@@ -155,6 +156,94 @@ func Backup(db *sqlx.DB, backupPath string) error {
 	return nil
 }
 
+// Optimize updates the statistics used by the query planner, and rebuilds
+// the database file to reclaim unused space. Writes wait until it has
+// finished.
+func Optimize() error {
+	if DB == nil {
+		return ErrDatabaseNotInitialized
+	}
+
+	WriteMu.Lock()
+	defer WriteMu.Unlock()
+
+	logger.Info("Analyzing database")
+	if _, err := DB.Exec("ANALYZE"); err != nil {
+		return fmt.Errorf("error analyzing database: %s", err.Error())
+	}
+
+	logger.Info("Performing vacuum on database")
+	if _, err := DB.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("error performing vacuum on database: %s", err.Error())
+	}
+
+	return nil
+}
+
+// time to wait before retrying a backup step when the database is locked
+const onlineBackupRetryInterval = 100 * time.Millisecond
+
+// OnlineBackup copies the database into backupPath using the SQLite online
+// backup API. Unlike Backup, the database may continue to be written while
+// the backup is made.
+func OnlineBackup(db *sqlx.DB, backupPath string) error {
+	if db == nil {
+		return ErrDatabaseNotInitialized
+	}
+
+	logger.Infof("Backing up database into: %s", backupPath)
+
+	ctx := context.Background()
+	srcConn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	destDB, err := sql.Open(sqlite3Driver, "file:"+backupPath)
+	if err != nil {
+		return fmt.Errorf("Open database %s failed:%s", backupPath, err)
+	}
+	defer destDB.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriverConn interface{}) error {
+		return srcConn.Raw(func(srcDriverConn interface{}) error {
+			dest := destDriverConn.(*sqlite3.SQLiteConn)
+			src := srcDriverConn.(*sqlite3.SQLiteConn)
+
+			b, err := dest.Backup("main", src, "main")
+			if err != nil {
+				return err
+			}
+
+			// copy all pages in a single step, so that the backup is not
+			// restarted by writes made during it. Readers do not block
+			// writers in WAL mode.
+			for {
+				done, err := b.Step(-1)
+				if err != nil {
+					b.Close()
+					return err
+				}
+				if done {
+					break
+				}
+
+				// the database is locked
+				time.Sleep(onlineBackupRetryInterval)
+			}
+
+			return b.Finish()
+		})
+	})
+}
+
 func RestoreFromBackup(backupPath string) error {
 	logger.Infof("Restoring backup database %s into %s", backupPath, dbPath)
 	return os.Rename(backupPath, dbPath)
@@ -261,6 +350,11 @@ func registerCustomDriver() {
 				funcs := map[string]interface{}{
 					"regexp":            regexFn,
 					"durationToTinyInt": durationToTinyIntFn,
+					"distanceKm":        distanceKmFn,
+					"heightToCm":        heightToCmFn,
+					"measurement":       measurementFn,
+					"normaliseCountry":  normaliseCountryFn,
+					"md5":               md5Fn,
 				}
 
 				for name, fn := range funcs {
@@ -269,6 +363,10 @@ func registerCustomDriver() {
 					}
 				}
 
+				if err := conn.RegisterFunc("generatedFileExists", generatedFileExistsFn, false); err != nil {
+					return fmt.Errorf("Error registering function generatedFileExists: %s", err.Error())
+				}
+
 				// COLLATE NATURAL_CS - Case sensitive natural sort
 				err := conn.RegisterCollation("NATURAL_CS", func(s string, s2 string) int {
 					if sortorder.NaturalLess(s, s2) {
@@ -1,15 +1,66 @@
 package database
 
 import (
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 func regexFn(re, s string) (bool, error) {
 	return regexp.MatchString(re, s)
 }
 
+// heightToCmFn returns the height in centimetres, or 0 if it cannot parse
+// the string.
+func heightToCmFn(str string) (int64, error) {
+	height, err := utils.ParseHeight(str)
+	if err != nil {
+		return 0, nil
+	}
+
+	return int64(height), nil
+}
+
+// measurementFn returns the measurement at index of the bust, waist and hips
+// measurements in the string, or 0 if it cannot parse the string.
+func measurementFn(str string, index int64) (int64, error) {
+	bust, waist, hips, err := utils.ParseMeasurements(str)
+	if err != nil || index < 0 || index > 2 {
+		return 0, nil
+	}
+
+	return int64([]int{bust, waist, hips}[index]), nil
+}
+
+// normaliseCountryFn returns the ISO 3166-1 alpha-2 code for the country,
+// or the original value if it is not recognised.
+func normaliseCountryFn(str string) (string, error) {
+	return utils.NormaliseCountry(str), nil
+}
+
+// md5Fn returns the hex encoded MD5 checksum of the data.
+func md5Fn(data []byte) (string, error) {
+	return utils.MD5FromBytes(data), nil
+}
+
+// GeneratedFileExistsFunc returns true if a generated file of the provided
+// type exists for the scene with the provided hashes. It is set by the
+// manager, since the generated file paths depend on the configuration.
+var GeneratedFileExistsFunc func(fileType string, checksum string, oshash string) bool
+
+// generatedFileExistsFn is not pure, since its result depends on the
+// filesystem.
+func generatedFileExistsFn(fileType, checksum, oshash string) (bool, error) {
+	if GeneratedFileExistsFunc == nil {
+		return false, nil
+	}
+
+	return GeneratedFileExistsFunc(fileType, checksum, oshash), nil
+}
+
 func durationToTinyIntFn(str string) (int64, error) {
 	splits := strings.Split(str, ":")
 
@@ -35,3 +86,22 @@ func durationToTinyIntFn(str string) (int64, error) {
 
 	return int64(seconds), nil
 }
+
+// mean radius of the earth in kilometres
+const earthRadiusKm = 6371.0
+
+// distanceKmFn returns the great-circle distance in kilometres between two
+// points given in decimal degrees, using the haversine formula.
+func distanceKmFn(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 {
+		return deg * math.Pi / 180
+	}
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
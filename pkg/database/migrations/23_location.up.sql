@@ -0,0 +1,6 @@
+ALTER TABLE `scenes` ADD COLUMN `location` varchar(255);
+ALTER TABLE `scenes` ADD COLUMN `latitude` real;
+ALTER TABLE `scenes` ADD COLUMN `longitude` real;
+ALTER TABLE `images` ADD COLUMN `location` varchar(255);
+ALTER TABLE `images` ADD COLUMN `latitude` real;
+ALTER TABLE `images` ADD COLUMN `longitude` real;
@@ -0,0 +1,101 @@
+-- convert performer height to an integer number of centimetres
+CREATE TABLE `performers_new` (
+  `id` integer not null primary key autoincrement,
+  `checksum` varchar(255) not null,
+  `name` varchar(255),
+  `gender` varchar(20),
+  `url` varchar(255),
+  `twitter` varchar(255),
+  `instagram` varchar(255),
+  `birthdate` date,
+  `ethnicity` varchar(255),
+  `country` varchar(255),
+  `eye_color` varchar(255),
+  `height` integer,
+  `measurements` varchar(255),
+  `fake_tits` varchar(255),
+  `career_length` varchar(255),
+  `tattoos` varchar(255),
+  `piercings` varchar(255),
+  `aliases` varchar(255),
+  `favorite` boolean not null default '0',
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  `details` text,
+  `death_date` date,
+  `hair_color` varchar(255),
+  `weight` integer,
+  `rating` tinyint
+);
+
+INSERT INTO `performers_new`
+  (
+    `id`,
+    `checksum`,
+    `name`,
+    `gender`,
+    `url`,
+    `twitter`,
+    `instagram`,
+    `birthdate`,
+    `ethnicity`,
+    `country`,
+    `eye_color`,
+    `height`,
+    `measurements`,
+    `fake_tits`,
+    `career_length`,
+    `tattoos`,
+    `piercings`,
+    `aliases`,
+    `favorite`,
+    `created_at`,
+    `updated_at`,
+    `details`,
+    `death_date`,
+    `hair_color`,
+    `weight`,
+    `rating`
+  )
+  SELECT
+    `id`,
+    `checksum`,
+    `name`,
+    `gender`,
+    `url`,
+    `twitter`,
+    `instagram`,
+    `birthdate`,
+    `ethnicity`,
+    `country`,
+    `eye_color`,
+    CASE WHEN `height` IS NOT NULL THEN heightToCm(`height`) END,
+    `measurements`,
+    `fake_tits`,
+    `career_length`,
+    `tattoos`,
+    `piercings`,
+    `aliases`,
+    `favorite`,
+    `created_at`,
+    `updated_at`,
+    `details`,
+    `death_date`,
+    `hair_color`,
+    `weight`,
+    `rating`
+  FROM `performers`;
+
+-- heightToCm returns 0 if it cannot parse the string
+-- set these values to null instead
+UPDATE `performers_new` SET `height` = NULL WHERE `height` = 0;
+
+-- drop the old table before renaming so that foreign keys referencing
+-- performers are not rewritten
+DROP INDEX `performers_checksum_unique`;
+DROP INDEX `index_performers_on_name`;
+DROP TABLE `performers`;
+ALTER TABLE `performers_new` RENAME TO `performers`;
+
+CREATE UNIQUE INDEX `performers_checksum_unique` on `performers` (`checksum`);
+CREATE INDEX `index_performers_on_name` on `performers` (`name`);
@@ -0,0 +1,5 @@
+ALTER TABLE `studios` ADD COLUMN `country` varchar(255);
+
+-- normalise existing performer countries to ISO codes
+UPDATE `performers` SET `country` = normaliseCountry(`country`) WHERE `country` IS NOT NULL;
+UPDATE `performers` SET `country` = NULL WHERE `country` = '';
@@ -0,0 +1,54 @@
+CREATE TABLE `deleted_objects` (
+  `id` integer not null primary key autoincrement,
+  `type` varchar(255) not null,
+  -- nullable
+  `checksum` varchar(255),
+  `oshash` varchar(255),
+  `name` varchar(255),
+  `deleted_at` datetime not null
+);
+
+CREATE INDEX `index_deleted_objects_on_deleted_at` on `deleted_objects` (`deleted_at`);
+
+-- record deleted objects so that incremental exports can include them
+CREATE TRIGGER `scenes_deleted` AFTER DELETE ON `scenes`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `checksum`, `oshash`, `deleted_at`)
+  VALUES ('scene', OLD.`checksum`, OLD.`oshash`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `images_deleted` AFTER DELETE ON `images`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `checksum`, `deleted_at`)
+  VALUES ('image', OLD.`checksum`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `galleries_deleted` AFTER DELETE ON `galleries`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `checksum`, `deleted_at`)
+  VALUES ('gallery', OLD.`checksum`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `performers_deleted` AFTER DELETE ON `performers`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `checksum`, `name`, `deleted_at`)
+  VALUES ('performer', OLD.`checksum`, OLD.`name`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `studios_deleted` AFTER DELETE ON `studios`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `checksum`, `name`, `deleted_at`)
+  VALUES ('studio', OLD.`checksum`, OLD.`name`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `movies_deleted` AFTER DELETE ON `movies`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `checksum`, `name`, `deleted_at`)
+  VALUES ('movie', OLD.`checksum`, OLD.`name`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `tags_deleted` AFTER DELETE ON `tags`
+BEGIN
+  INSERT INTO `deleted_objects` (`type`, `name`, `deleted_at`)
+  VALUES ('tag', OLD.`name`, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
@@ -0,0 +1,14 @@
+CREATE TABLE `scene_captions` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer not null,
+  -- the index of the subtitle stream, for embedded captions
+  `stream_index` integer,
+  -- the path of the caption file, for external captions
+  `path` varchar(255) not null default '',
+  `language_code` varchar(255) not null default '',
+  `title` varchar(255) not null default '',
+  `codec` varchar(255) not null default '',
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_scene_captions_on_scene_id` on `scene_captions` (`scene_id`);
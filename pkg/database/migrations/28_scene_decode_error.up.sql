@@ -0,0 +1,3 @@
+-- the errors reported when decoding the scene file, or null if the file
+-- decoded cleanly or has not been checked
+ALTER TABLE `scenes` ADD COLUMN `decode_error` text;
@@ -0,0 +1,2 @@
+-- true for scenes of audio files, which have no video stream
+ALTER TABLE `scenes` ADD COLUMN `audio_only` boolean not null default '0';
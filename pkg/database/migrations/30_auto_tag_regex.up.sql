@@ -0,0 +1,3 @@
+-- custom regular expressions matched against paths when auto-tagging
+ALTER TABLE `performers` ADD COLUMN `auto_tag_regex` varchar(255);
+ALTER TABLE `tags` ADD COLUMN `auto_tag_regex` varchar(255);
@@ -0,0 +1,12 @@
+-- recurring tasks run by the scheduler
+CREATE TABLE `scheduled_tasks` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `task` varchar(255) not null,
+  -- cron expression
+  `schedule` varchar(255) not null,
+  `enabled` boolean not null default '1',
+  `last_run_at` datetime,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
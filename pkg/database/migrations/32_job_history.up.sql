@@ -0,0 +1,12 @@
+-- jobs which have finished, been stopped or failed
+CREATE TABLE `job_history` (
+  `id` integer not null primary key autoincrement,
+  `type` varchar(255) not null,
+  `priority` varchar(255) not null,
+  `state` varchar(255) not null,
+  `error` text,
+  `added_at` datetime not null,
+  -- null if the job was cancelled before it started
+  `started_at` datetime,
+  `finished_at` datetime not null
+);
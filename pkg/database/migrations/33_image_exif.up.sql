@@ -0,0 +1,4 @@
+ALTER TABLE `images` ADD COLUMN `taken_at` datetime;
+ALTER TABLE `images` ADD COLUMN `camera_make` varchar(255);
+ALTER TABLE `images` ADD COLUMN `camera_model` varchar(255);
+CREATE INDEX `index_images_on_taken_at` on `images` (`taken_at`);
@@ -0,0 +1,104 @@
+-- store each distinct image once, by checksum. The blob is null if the image
+-- is stored in the filesystem.
+CREATE TABLE `blobs` (
+  `checksum` varchar(255) NOT NULL PRIMARY KEY,
+  `blob` blob
+);
+
+INSERT OR IGNORE INTO `blobs` (`checksum`, `blob`)
+  SELECT md5(`image`), `image` FROM `performers_image`;
+
+INSERT OR IGNORE INTO `blobs` (`checksum`, `blob`)
+  SELECT md5(`image`), `image` FROM `studios_image`;
+
+INSERT OR IGNORE INTO `blobs` (`checksum`, `blob`)
+  SELECT md5(`image`), `image` FROM `tags_image`;
+
+INSERT OR IGNORE INTO `blobs` (`checksum`, `blob`)
+  SELECT md5(`front_image`), `front_image` FROM `movies_images`;
+
+INSERT OR IGNORE INTO `blobs` (`checksum`, `blob`)
+  SELECT md5(`back_image`), `back_image` FROM `movies_images` WHERE `back_image` IS NOT NULL;
+
+-- replace the images with references to the blobs
+ALTER TABLE `performers_image` RENAME TO `_performers_image_old`;
+ALTER TABLE `studios_image` RENAME TO `_studios_image_old`;
+ALTER TABLE `tags_image` RENAME TO `_tags_image_old`;
+ALTER TABLE `movies_images` RENAME TO `_movies_images_old`;
+
+CREATE TABLE `performers_image` (
+  `performer_id` integer,
+  `image_blob` varchar(255) not null,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  foreign key(`image_blob`) references `blobs`(`checksum`)
+);
+
+INSERT INTO `performers_image`
+  (
+    `performer_id`,
+    `image_blob`
+  )
+  SELECT `performer_id`, md5(`image`) from `_performers_image_old`;
+
+CREATE TABLE `studios_image` (
+  `studio_id` integer,
+  `image_blob` varchar(255) not null,
+  foreign key(`studio_id`) references `studios`(`id`) on delete CASCADE,
+  foreign key(`image_blob`) references `blobs`(`checksum`)
+);
+
+INSERT INTO `studios_image`
+  (
+    `studio_id`,
+    `image_blob`
+  )
+  SELECT `studio_id`, md5(`image`) from `_studios_image_old`;
+
+CREATE TABLE `tags_image` (
+  `tag_id` integer,
+  `image_blob` varchar(255) not null,
+  foreign key(`tag_id`) references `tags`(`id`) on delete CASCADE,
+  foreign key(`image_blob`) references `blobs`(`checksum`)
+);
+
+INSERT INTO `tags_image`
+  (
+    `tag_id`,
+    `image_blob`
+  )
+  SELECT `tag_id`, md5(`image`) from `_tags_image_old`;
+
+CREATE TABLE `movies_images` (
+  `movie_id` integer,
+  `front_image_blob` varchar(255) not null,
+  `back_image_blob` varchar(255),
+  foreign key(`movie_id`) references `movies`(`id`) on delete CASCADE,
+  foreign key(`front_image_blob`) references `blobs`(`checksum`),
+  foreign key(`back_image_blob`) references `blobs`(`checksum`)
+);
+
+INSERT INTO `movies_images`
+  (
+    `movie_id`,
+    `front_image_blob`,
+    `back_image_blob`
+  )
+  SELECT `movie_id`, md5(`front_image`), CASE WHEN `back_image` IS NULL THEN NULL ELSE md5(`back_image`) END from `_movies_images_old`;
+
+-- drop old tables
+DROP TABLE `_performers_image_old`;
+DROP TABLE `_studios_image_old`;
+DROP TABLE `_tags_image_old`;
+DROP TABLE `_movies_images_old`;
+
+CREATE UNIQUE INDEX `index_performer_image_on_performer_id` on `performers_image` (`performer_id`);
+CREATE UNIQUE INDEX `index_studio_image_on_studio_id` on `studios_image` (`studio_id`);
+CREATE UNIQUE INDEX `index_tag_image_on_tag_id` on `tags_image` (`tag_id`);
+CREATE UNIQUE INDEX `index_movie_images_on_movie_id` on `movies_images` (`movie_id`);
+
+-- blobs are deleted once they are no longer referenced
+CREATE INDEX `index_performers_image_on_image_blob` on `performers_image` (`image_blob`);
+CREATE INDEX `index_studios_image_on_image_blob` on `studios_image` (`image_blob`);
+CREATE INDEX `index_tags_image_on_image_blob` on `tags_image` (`image_blob`);
+CREATE INDEX `index_movies_images_on_front_image_blob` on `movies_images` (`front_image_blob`);
+CREATE INDEX `index_movies_images_on_back_image_blob` on `movies_images` (`back_image_blob`);
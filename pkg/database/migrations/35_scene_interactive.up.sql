@@ -0,0 +1,2 @@
+-- true for scenes with a funscript file next to the scene file
+ALTER TABLE `scenes` ADD COLUMN `interactive` boolean not null default '0';
@@ -0,0 +1,2 @@
+-- median speed of the moves of the funscript, in positions per second
+ALTER TABLE `scenes` ADD COLUMN `interactive_speed` int;
@@ -0,0 +1,21 @@
+-- ordered lists of scenes to play one after another
+CREATE TABLE `playlists` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `shuffle` boolean not null default '0',
+  `repeat` boolean not null default '0',
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE TABLE `playlists_scenes` (
+  `playlist_id` integer not null,
+  `scene_id` integer not null,
+  -- order of the scene in the playlist, starting from 0
+  `position` integer not null,
+  foreign key(`playlist_id`) references `playlists`(`id`) on delete CASCADE,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`playlist_id`, `scene_id`)
+);
+
+CREATE INDEX `index_playlists_scenes_on_scene_id` on `playlists_scenes` (`scene_id`);
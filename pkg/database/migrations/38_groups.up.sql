@@ -0,0 +1,25 @@
+-- groups of scenes, such as series or sets, which may contain other groups
+CREATE TABLE `groups` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `description` text,
+  `parent_id` integer,
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  foreign key(`parent_id`) references `groups`(`id`) on delete SET NULL
+);
+
+CREATE INDEX `index_groups_on_name` on `groups` (`name`);
+CREATE INDEX `index_groups_on_parent_id` on `groups` (`parent_id`);
+
+CREATE TABLE `groups_scenes` (
+  `group_id` integer not null,
+  `scene_id` integer not null,
+  -- order of the scene in the group, starting from 0
+  `scene_index` integer not null,
+  foreign key(`group_id`) references `groups`(`id`) on delete CASCADE,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`group_id`, `scene_id`)
+);
+
+CREATE INDEX `index_groups_scenes_on_scene_id` on `groups_scenes` (`scene_id`);
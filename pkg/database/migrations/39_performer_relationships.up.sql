@@ -0,0 +1,12 @@
+-- typed relationships between performers. Relationships of symmetric
+-- types are stored once, with the lower performer id first.
+CREATE TABLE `performers_relationships` (
+  `performer_id` integer not null,
+  `related_id` integer not null,
+  `type` varchar(255) not null,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  foreign key(`related_id`) references `performers`(`id`) on delete CASCADE,
+  PRIMARY KEY(`performer_id`, `related_id`, `type`)
+);
+
+CREATE INDEX `index_performers_relationships_on_related_id` on `performers_relationships` (`related_id`);
@@ -0,0 +1,7 @@
+-- performers and studios may share a name depending on the duplicate name
+-- policy, so the checksums of their names are no longer unique. studios
+-- already have a non-unique index on checksum.
+DROP INDEX `performers_checksum_unique`;
+DROP INDEX `studios_checksum_unique`;
+
+CREATE INDEX `index_performers_on_checksum` on `performers` (`checksum`);
@@ -0,0 +1,3 @@
+-- true once the subtitle streams of the scene file have been detected, so
+-- that scans only probe existing scenes for embedded captions once
+ALTER TABLE `scenes` ADD COLUMN `captions_probed` boolean not null default '0';
@@ -0,0 +1,15 @@
+-- the bust, waist and hips measurements parsed from the measurements string,
+-- so that performers can be filtered by them
+ALTER TABLE `performers` ADD COLUMN `bust` integer;
+ALTER TABLE `performers` ADD COLUMN `waist` integer;
+ALTER TABLE `performers` ADD COLUMN `hips` integer;
+
+UPDATE `performers` SET
+  `bust` = measurement(`measurements`, 0),
+  `waist` = measurement(`measurements`, 1),
+  `hips` = measurement(`measurements`, 2)
+WHERE `measurements` IS NOT NULL;
+
+-- measurement returns 0 if it cannot parse the string
+-- set these values to null instead
+UPDATE `performers` SET `bust` = NULL, `waist` = NULL, `hips` = NULL WHERE `bust` = 0;
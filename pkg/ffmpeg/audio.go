@@ -0,0 +1,33 @@
+package ffmpeg
+
+const (
+	Flac     AudioCodec = "flac"
+	PcmS16le AudioCodec = "pcm_s16le"
+)
+
+// validAudioForDirectStream are the codecs of audio files which are played
+// by browsers without transcoding.
+var validAudioForDirectStream = []AudioCodec{Aac, Mp3, Flac, Opus, Vorbis, PcmS16le}
+
+// IsAudioOnly returns true if the file has an audio stream but no video
+// stream. Cover art is not considered a video stream.
+func (v *VideoFile) IsAudioOnly() bool {
+	return v.VideoStream == nil && v.AudioStream != nil
+}
+
+// GetArtworkStream returns the cover art stream of the file, or nil if the
+// file has no cover art.
+func (v *VideoFile) GetArtworkStream() *FFProbeStream {
+	for i, stream := range v.JSON.Streams {
+		if stream.CodecType == "video" && stream.Disposition.AttachedPic != 0 {
+			return &v.JSON.Streams[i]
+		}
+	}
+	return nil
+}
+
+// IsStreamableAudio returns true if audio files with the codec may be
+// streamed directly.
+func IsStreamableAudio(audio AudioCodec) bool {
+	return IsValidAudio(audio, validAudioForDirectStream)
+}
@@ -0,0 +1,42 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioOnly(t *testing.T) {
+	artwork := FFProbeStream{Index: 1, CodecType: "video", CodecName: "mjpeg"}
+	artwork.Disposition.AttachedPic = 1
+
+	v := VideoFile{
+		JSON: FFProbeJSON{
+			Streams: []FFProbeStream{
+				{Index: 0, CodecType: "audio", CodecName: "mp3"},
+				artwork,
+			},
+		},
+	}
+	v.AudioStream = v.GetAudioStream()
+	v.VideoStream = v.GetVideoStream()
+
+	assert.Nil(t, v.VideoStream)
+	assert.True(t, v.IsAudioOnly())
+	if assert.NotNil(t, v.GetArtworkStream()) {
+		assert.Equal(t, 1, v.GetArtworkStream().Index)
+	}
+
+	args := GetTranscodeStreamOptions(v, CodecH264, Mp3).getStreamArgs()
+	assert.Contains(t, args, "-vn")
+	assert.NotContains(t, args, "-c:v")
+	assert.NotContains(t, args, "-vf")
+
+	v.JSON.Streams = append(v.JSON.Streams, FFProbeStream{Index: 2, CodecType: "video", CodecName: "h264"})
+	v.VideoStream = v.GetVideoStream()
+	if assert.NotNil(t, v.VideoStream) {
+		assert.Equal(t, 2, v.VideoStream.Index)
+	}
+	assert.False(t, v.IsAudioOnly())
+	assert.NotContains(t, GetTranscodeStreamOptions(v, CodecH264, Aac).getStreamArgs(), "-vn")
+}
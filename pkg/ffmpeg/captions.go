@@ -0,0 +1,70 @@
+package ffmpeg
+
+import (
+	"strconv"
+)
+
+// textCaptionCodecs are the subtitle codecs which ffmpeg can convert to
+// WebVTT. Bitmap subtitles, such as PGS and DVD subtitles, are ignored.
+var textCaptionCodecs = map[string]bool{
+	"subrip":   true,
+	"ass":      true,
+	"ssa":      true,
+	"mov_text": true,
+	"webvtt":   true,
+	"text":     true,
+}
+
+// undeterminedLanguage is the language code set by muxers for streams
+// without a language.
+const undeterminedLanguage = "und"
+
+// CaptionStream is a text subtitle stream of a video file.
+type CaptionStream struct {
+	Index    int
+	Codec    string
+	Language string
+	Title    string
+}
+
+// GetCaptionStreams returns the subtitle streams of the video file which can
+// be converted to WebVTT.
+func (v *VideoFile) GetCaptionStreams() []CaptionStream {
+	var ret []CaptionStream
+	for _, stream := range v.JSON.Streams {
+		if stream.CodecType != "subtitle" || !textCaptionCodecs[stream.CodecName] {
+			continue
+		}
+
+		language := stream.Tags.Language
+		if language == undeterminedLanguage {
+			language = ""
+		}
+
+		ret = append(ret, CaptionStream{
+			Index:    stream.Index,
+			Codec:    stream.CodecName,
+			Language: language,
+			Title:    stream.Tags.Title,
+		})
+	}
+
+	return ret
+}
+
+// CaptionToWebVTT returns the captions at path converted to WebVTT. If
+// streamIndex is not negative, then path is a video file and the subtitle
+// stream with the index is converted. Otherwise path is a caption file,
+// such as an .srt file.
+func (e *Encoder) CaptionToWebVTT(path string, streamIndex int) (string, error) {
+	args := []string{
+		"-v", "error",
+		"-i", path,
+	}
+	if streamIndex >= 0 {
+		args = append(args, "-map", "0:"+strconv.Itoa(streamIndex))
+	}
+	args = append(args, "-f", "webvtt", "-")
+
+	return e.runWithPriority(VideoFile{Path: path}, args, PriorityInteractive)
+}
@@ -0,0 +1,42 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCaptionStreams(t *testing.T) {
+	stream := func(index int, codecType, codec, language, title string) FFProbeStream {
+		s := FFProbeStream{
+			Index:     index,
+			CodecType: codecType,
+			CodecName: codec,
+		}
+		s.Tags.Language = language
+		s.Tags.Title = title
+		return s
+	}
+
+	v := &VideoFile{}
+	v.JSON.Streams = []FFProbeStream{
+		stream(0, "video", "h264", "und", ""),
+		stream(1, "audio", "aac", "eng", ""),
+		stream(2, "subtitle", "subrip", "eng", "English"),
+		stream(3, "subtitle", "hdmv_pgs_subtitle", "eng", ""),
+		stream(4, "subtitle", "ass", "und", ""),
+	}
+
+	assert.Equal(t, []CaptionStream{
+		{
+			Index:    2,
+			Codec:    "subrip",
+			Language: "eng",
+			Title:    "English",
+		},
+		{
+			Index: 4,
+			Codec: "ass",
+		},
+	}, v.GetCaptionStreams())
+}
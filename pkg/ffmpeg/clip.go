@@ -0,0 +1,128 @@
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// clipCopyVideoCodecs are the video codecs which are copied into MP4 clips.
+var clipCopyVideoCodecs = []string{H264, Hevc}
+
+type ClipOptions struct {
+	ProbeResult VideoFile
+	// Start is the start time of the clip in seconds
+	Start float64
+	// Duration is the length of the clip in seconds
+	Duration float64
+	// Transcode encodes the clip even if the streams of the file may be
+	// copied
+	Transcode bool
+}
+
+// ClipDuration returns the duration of the clip between start and end, in
+// seconds. The end is clamped to the duration of the file. Returns an error
+// if the clip is empty.
+func ClipDuration(probeResult VideoFile, start float64, end float64) (float64, error) {
+	if probeResult.Duration > 0 && end > probeResult.Duration {
+		end = probeResult.Duration
+	}
+
+	if end <= start {
+		return 0, errors.New("clip end must be after the start")
+	}
+
+	return end - start, nil
+}
+
+// copyStreams returns true if the streams of the file are copied into the
+// clip. Copied clips start at the key frame before the start time.
+func (o ClipOptions) copyStreams() bool {
+	if o.Transcode {
+		return false
+	}
+
+	if !o.ProbeResult.IsAudioOnly() {
+		if o.ProbeResult.VideoStream == nil || !IsValidCodec(o.ProbeResult.VideoCodec, clipCopyVideoCodecs) {
+			return false
+		}
+	}
+
+	if o.ProbeResult.AudioStream == nil {
+		return true
+	}
+
+	return IsValidAudio(AudioCodec(o.ProbeResult.AudioCodec), validAudioForMp4)
+}
+
+func (e *Encoder) getClipArgs(o ClipOptions) []string {
+	args := []string{
+		"-hide_banner",
+		"-v", "error",
+		"-ss", strconv.FormatFloat(o.Start, 'f', -1, 64),
+		"-i", o.ProbeResult.Path,
+		"-t", strconv.FormatFloat(o.Duration, 'f', -1, 64),
+	}
+
+	if o.ProbeResult.IsAudioOnly() {
+		// ignore the cover art stream
+		args = append(args, "-map", "0:a:0")
+	} else {
+		args = append(args, "-map", "0:v:0", "-map", "0:a:0?")
+	}
+
+	if o.copyStreams() {
+		args = append(args,
+			"-c", CopyStreamCodec,
+			"-avoid_negative_ts", "make_zero",
+		)
+		if o.ProbeResult.VideoCodec == Hevc && !o.ProbeResult.IsAudioOnly() {
+			// needed to play HEVC in MP4 on Apple devices
+			args = append(args, "-tag:v", "hvc1")
+		}
+	} else {
+		if !o.ProbeResult.IsAudioOnly() {
+			args = append(args,
+				"-c:v", "libx264",
+				"-pix_fmt", "yuv420p",
+				"-preset", "veryfast",
+				"-crf", "23",
+				"-vf", e.videoFilter(o.ProbeResult, fmt.Sprintf("scale=%v:-2", o.ProbeResult.Width)),
+			)
+		}
+		args = append(args,
+			"-c:a", "aac",
+			"-ac", "2",
+		)
+	}
+
+	args = append(args,
+		"-movflags", "frag_keyframe+empty_moov",
+		"-f", "mp4",
+		"pipe:",
+	)
+
+	return args
+}
+
+// GetClipStream starts exporting the clip of the file as an MP4 stream.
+// The streams of the file are copied if the codecs are supported by MP4,
+// and transcoded otherwise.
+func (e *Encoder) GetClipStream(options ClipOptions) (*Stream, error) {
+	if options.ProbeResult.VideoStream == nil && options.ProbeResult.AudioStream == nil {
+		return nil, errors.New("file has no video or audio stream")
+	}
+
+	stream, err := e.startStream(options.ProbeResult, e.getClipArgs(options))
+	if err != nil {
+		return nil, err
+	}
+
+	stream.mimeType = MimeMp4
+	// sets the duration header of the stream
+	stream.options = TranscodeStreamOptions{
+		ProbeResult: VideoFile{Duration: options.Duration},
+	}
+
+	return stream, nil
+}
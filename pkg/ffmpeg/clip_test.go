@@ -0,0 +1,78 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipDuration(t *testing.T) {
+	probe := VideoFile{Duration: 100}
+
+	duration, err := ClipDuration(probe, 10, 25.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 15.5, duration)
+
+	// clamped to the end of the file
+	duration, err = ClipDuration(probe, 90, 120)
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, duration)
+
+	_, err = ClipDuration(probe, 30, 30)
+	assert.NotNil(t, err)
+
+	_, err = ClipDuration(probe, 110, 120)
+	assert.NotNil(t, err)
+}
+
+func TestClipCopyStreams(t *testing.T) {
+	video := &FFProbeStream{}
+	audio := &FFProbeStream{}
+
+	tests := []struct {
+		name  string
+		probe VideoFile
+		copy  bool
+	}{
+		{"h264 aac", VideoFile{VideoStream: video, VideoCodec: H264, AudioStream: audio, AudioCodec: "aac"}, true},
+		{"hevc no audio", VideoFile{VideoStream: video, VideoCodec: Hevc}, true},
+		{"h264 opus", VideoFile{VideoStream: video, VideoCodec: H264, AudioStream: audio, AudioCodec: "opus"}, false},
+		{"vp9 aac", VideoFile{VideoStream: video, VideoCodec: "vp9", AudioStream: audio, AudioCodec: "aac"}, false},
+		{"mp3 audio", VideoFile{AudioStream: audio, AudioCodec: "mp3"}, true},
+		{"flac audio", VideoFile{AudioStream: audio, AudioCodec: "flac"}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.copy, ClipOptions{ProbeResult: tt.probe}.copyStreams(), tt.name)
+	}
+
+	transcode := ClipOptions{ProbeResult: tests[0].probe, Transcode: true}
+	assert.False(t, transcode.copyStreams())
+}
+
+func TestGetClipArgs(t *testing.T) {
+	e := &Encoder{}
+	options := ClipOptions{
+		ProbeResult: VideoFile{Path: "in.mkv", VideoStream: &FFProbeStream{}, VideoCodec: "vp9", Width: 1280},
+		Start:       12.5,
+		Duration:    30,
+	}
+
+	args := strings.Join(e.getClipArgs(options), " ")
+	assert.Contains(t, args, "-ss 12.5 -i in.mkv -t 30 -map 0:v:0 -map 0:a:0?")
+	assert.Contains(t, args, "-c:v libx264")
+	assert.Contains(t, args, "-vf scale=1280:-2")
+	assert.True(t, strings.HasSuffix(args, "-f mp4 pipe:"))
+
+	options.ProbeResult.VideoCodec = Hevc
+	args = strings.Join(e.getClipArgs(options), " ")
+	assert.Contains(t, args, "-c copy")
+	assert.Contains(t, args, "-tag:v hvc1")
+	assert.NotContains(t, args, "-vf")
+
+	options.ProbeResult = VideoFile{Path: "in.flac", AudioStream: &FFProbeStream{}, AudioCodec: "flac"}
+	args = strings.Join(e.getClipArgs(options), " ")
+	assert.Contains(t, args, "-map 0:a:0 -c:a aac")
+	assert.NotContains(t, args, "-c:v")
+}
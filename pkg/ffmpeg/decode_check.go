@@ -0,0 +1,90 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// maxDecodeErrorLines is the maximum number of lines of ffmpeg output kept
+// for a file which failed to decode.
+const maxDecodeErrorLines = 10
+
+// CheckDecode decodes the file without writing any output, and returns the
+// errors reported by ffmpeg, or an empty string if the file decoded
+// cleanly. The fast check only decodes the key frames of the first video
+// stream, which finds truncated files and corrupted containers. The full
+// check decodes every frame of the video and audio streams. The audio of
+// audio files is always fully decoded.
+//
+// The returned error is only set if ffmpeg could not be run, or if ctx was
+// cancelled.
+func (e *Encoder) CheckDecode(ctx context.Context, probeResult VideoFile, full bool) (string, error) {
+	args := []string{"-nostdin", "-hide_banner", "-v", "error"}
+	switch {
+	case full:
+		args = append(args, "-i", probeResult.Path, "-map", "0:v?", "-map", "0:a?")
+	case probeResult.IsAudioOnly():
+		// audio frames are all key frames
+		args = append(args, "-i", probeResult.Path, "-map", "0:a:0")
+	default:
+		args = append(args, "-skip_frame", "nokey", "-i", probeResult.Path, "-map", "0:v:0", "-an")
+	}
+	args = append(args, "-f", "null", "-")
+
+	cmd := exec.CommandContext(ctx, e.Path, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	processScheduler.acquire(PriorityBackground)
+	defer processScheduler.release(PriorityBackground)
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	registerRunningEncoder(probeResult.Path, cmd.Process)
+	err := waitAndDeregister(probeResult.Path, cmd)
+
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	ret := decodeErrors(stderr.String())
+
+	var exitErr *exec.ExitError
+	if err != nil {
+		if !errors.As(err, &exitErr) {
+			return "", err
+		}
+
+		if ret == "" {
+			ret = fmt.Sprintf("ffmpeg exited with status %d", exitErr.ExitCode())
+		}
+	}
+
+	return ret, nil
+}
+
+// decodeErrors returns the first lines of the error output of ffmpeg, with
+// the number of lines omitted.
+func decodeErrors(output string) string {
+	var lines []string
+	for _, l := range strings.Split(output, "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	if len(lines) > maxDecodeErrorLines {
+		omitted := len(lines) - maxDecodeErrorLines
+		lines = append(lines[:maxDecodeErrorLines], fmt.Sprintf("... %d more lines", omitted))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,24 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeErrors(t *testing.T) {
+	assert.Equal(t, "", decodeErrors(""))
+	assert.Equal(t, "", decodeErrors("\n  \n"))
+
+	const output = `[h264 @ 0x1] error while decoding MB 45 30, bytestream -5
+
+[h264 @ 0x1] concealing 1200 DC, 1200 AC, 1200 MV errors in P frame
+`
+	assert.Equal(t, "[h264 @ 0x1] error while decoding MB 45 30, bytestream -5\n[h264 @ 0x1] concealing 1200 DC, 1200 AC, 1200 MV errors in P frame", decodeErrors(output))
+
+	long := strings.Repeat("error\n", maxDecodeErrorLines+3)
+	lines := strings.Split(decodeErrors(long), "\n")
+	assert.Len(t, lines, maxDecodeErrorLines+1)
+	assert.Equal(t, "... 3 more lines", lines[maxDecodeErrorLines])
+}
@@ -1,9 +1,15 @@
 package ffmpeg
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"os/exec"
@@ -47,9 +53,21 @@ func GetPaths(paths []string) (string, string) {
 	return ffmpegPath, ffprobePath
 }
 
+// downloadAttempts is the number of times an interrupted download is
+// resumed before giving up.
+const downloadAttempts = 5
+
+// archiveDownload is an ffmpeg archive and the checksum published for it.
+// The archive is not verified if checksumURL is empty.
+type archiveDownload struct {
+	url         string
+	checksumURL string
+	newHash     func() hash.Hash
+}
+
 func Download(configDirectory string) error {
-	for _, url := range getFFMPEGURL() {
-		err := DownloadSingle(configDirectory, url)
+	for _, archive := range getFFMPEGDownloads() {
+		err := downloadSingle(configDirectory, archive)
 		if err != nil {
 			return err
 		}
@@ -80,52 +98,61 @@ func (r *progressReader) Read(p []byte) (int, error) {
 	return read, err
 }
 
-func DownloadSingle(configDirectory, url string) error {
+func downloadSingle(configDirectory string, archive archiveDownload) error {
+	url := archive.url
 	if url == "" {
 		return fmt.Errorf("no ffmpeg url for this platform")
 	}
 
-	// Configure where we want to download the archive
-	urlExt := path.Ext(url)
+	var checksum string
+	if archive.checksumURL != "" {
+		var err error
+		checksum, err = getChecksum(archive.checksumURL)
+		if err != nil {
+			return fmt.Errorf("error getting checksum of %s: %s", url, err.Error())
+		}
+	}
+
+	// Configure where we want to download the archive. The archive is
+	// downloaded to a partial file, which is resumed by the next attempt if
+	// the download is interrupted.
 	urlBase := path.Base(url)
 	archivePath := filepath.Join(configDirectory, urlBase)
-	_ = os.Remove(archivePath) // remove archive if it already exists
-	out, err := os.Create(archivePath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
+	partPath := archivePath + ".part"
 
 	logger.Infof("Downloading %s...", url)
 
-	// Make the HTTP request
-	resp, err := http.Get(url)
+	var err error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if err = downloadPart(url, partPath); err == nil {
+			break
+		}
+
+		logger.Warnf("Error downloading %s (attempt %d of %d): %s", url, attempt, downloadAttempts, err.Error())
+	}
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
+	logger.Info("Downloading complete")
 
-	reader := &progressReader{
-		Reader: resp.Body,
-		total:  resp.ContentLength,
+	if checksum != "" {
+		if err := utils.VerifyChecksum(partPath, checksum, archive.newHash()); err != nil {
+			// remove the archive so that the next download starts again
+			_ = os.Remove(partPath)
+			return err
+		}
+		logger.Info("Checksum verified")
 	}
 
-	// Write the response to the archive file location
-	_, err = io.Copy(out, reader)
-	if err != nil {
+	_ = os.Remove(archivePath) // remove archive if it already exists
+	if err := os.Rename(partPath, archivePath); err != nil {
 		return err
 	}
 
-	logger.Info("Downloading complete")
-
-	if urlExt == ".zip" {
-		logger.Infof("Unzipping %s...", archivePath)
-		if err := unzip(archivePath, configDirectory); err != nil {
+	if extract := getExtractor(urlBase); extract != nil {
+		logger.Infof("Extracting %s...", archivePath)
+		if err := extract(archivePath, configDirectory); err != nil {
 			return err
 		}
 
@@ -153,21 +180,169 @@ func DownloadSingle(configDirectory, url string) error {
 	return nil
 }
 
-func getFFMPEGURL() []string {
-	var urls []string
+// downloadPart downloads url to partPath. If partPath already exists, the
+// rest of the file is requested with a Range request and appended to it.
+// The file is downloaded from the start if the server doesn't support
+// Range requests.
+func downloadPart(url, partPath string) error {
+	out, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	// Make the HTTP request
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Check server response
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		logger.Infof("Resuming download from %d bytes", offset)
+	case http.StatusOK:
+		// the whole file was sent
+		offset = 0
+		if _, err := out.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := out.Truncate(0); err != nil {
+			return err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the file was already completely downloaded
+		if offset > 0 {
+			return nil
+		}
+		return fmt.Errorf("bad status: %s", resp.Status)
+	default:
+		return fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	reader := &progressReader{
+		Reader:    resp.Body,
+		bytesRead: offset,
+	}
+	if resp.ContentLength > 0 {
+		reader.total = offset + resp.ContentLength
+	}
+
+	// Write the response to the archive file location
+	_, err = io.Copy(out, reader)
+	return err
+}
+
+// getChecksum returns the checksum in the checksum file at url. Checksum
+// files contain the hex encoded checksum, optionally followed by the name
+// of the file.
+func getChecksum(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	// checksum files are small, so limit the amount read
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	return parseChecksum(string(data))
+}
+
+func parseChecksum(data string) (string, error) {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file")
+	}
+
+	checksum := strings.ToLower(fields[0])
+	if _, err := hex.DecodeString(checksum); err != nil {
+		return "", fmt.Errorf("invalid checksum %q", fields[0])
+	}
+
+	return checksum, nil
+}
+
+func getFFMPEGDownloads() []archiveDownload {
+	var downloads []archiveDownload
 	switch runtime.GOOS {
 	case "darwin":
-		urls = []string{"https://evermeet.cx/ffmpeg/ffmpeg-4.3.1.zip", "https://evermeet.cx/ffmpeg/ffprobe-4.3.1.zip"}
+		// evermeet.cx only publishes signatures of the archives
+		downloads = []archiveDownload{
+			{url: "https://evermeet.cx/ffmpeg/ffmpeg-4.3.1.zip"},
+			{url: "https://evermeet.cx/ffmpeg/ffprobe-4.3.1.zip"},
+		}
 	case "linux":
-		// TODO: get appropriate arch (arm,arm64,amd64) and xz untar from https://johnvansickle.com/ffmpeg/
-		//       or get the ffmpeg,ffprobe zip repackaged ones from  https://ffbinaries.com/downloads
-		urls = []string{""}
+		downloads = []archiveDownload{getLinuxFFMPEGDownload(runtime.GOARCH)}
 	case "windows":
-		urls = []string{"https://www.gyan.dev/ffmpeg/builds/ffmpeg-release-essentials.zip"}
+		downloads = []archiveDownload{{
+			url:         "https://www.gyan.dev/ffmpeg/builds/ffmpeg-release-essentials.zip",
+			checksumURL: "https://www.gyan.dev/ffmpeg/builds/ffmpeg-release-essentials.zip.sha256",
+			newHash:     sha256.New,
+		}}
 	default:
-		urls = []string{""}
+		downloads = []archiveDownload{{}}
+	}
+	return downloads
+}
+
+// linuxFFMPEGArchs maps GOARCH to the architecture names of the static
+// builds at https://johnvansickle.com/ffmpeg/
+var linuxFFMPEGArchs = map[string]string{
+	"amd64": "amd64",
+	"386":   "i686",
+	"arm64": "arm64",
+	"arm":   "armhf",
+}
+
+// getLinuxFFMPEGDownload returns the static build for arch. Only MD5 sums
+// are published for these builds.
+func getLinuxFFMPEGDownload(arch string) archiveDownload {
+	buildArch, found := linuxFFMPEGArchs[arch]
+	if !found {
+		return archiveDownload{}
+	}
+
+	url := fmt.Sprintf("https://johnvansickle.com/ffmpeg/releases/ffmpeg-release-%s-static.tar.xz", buildArch)
+	return archiveDownload{
+		url:         url,
+		checksumURL: url + ".md5",
+		newHash:     md5.New,
+	}
+}
+
+// getExtractor returns the function which extracts ffmpeg and ffprobe from
+// the downloaded archive, or nil if the archive type is not supported.
+func getExtractor(archiveName string) func(src, configDirectory string) error {
+	switch {
+	case strings.HasSuffix(archiveName, ".zip"):
+		return unzip
+	case strings.HasSuffix(archiveName, ".tar.xz"):
+		return untarXZ
+	default:
+		return nil
 	}
-	return urls
 }
 
 func getFFMPEGFilename() string {
@@ -212,7 +387,7 @@ func unzip(src, configDirectory string) error {
 			continue
 		}
 		filename := f.FileInfo().Name()
-		if filename != "ffprobe" && filename != "ffmpeg" && filename != "ffprobe.exe" && filename != "ffmpeg.exe" {
+		if !isFFMPEGBinary(filename) {
 			continue
 		}
 
@@ -236,3 +411,80 @@ func unzip(src, configDirectory string) error {
 
 	return nil
 }
+
+func isFFMPEGBinary(filename string) bool {
+	return filename == "ffprobe" || filename == "ffmpeg" || filename == "ffprobe.exe" || filename == "ffmpeg.exe"
+}
+
+// untarXZ extracts ffmpeg and ffprobe from a tar.xz archive. The archive is
+// decompressed with the xz binary, which is installed on most Linux
+// distributions, since the standard library has no xz support.
+func untarXZ(src, configDirectory string) error {
+	xzPath, err := exec.LookPath("xz")
+	if err != nil {
+		return fmt.Errorf("xz is required to extract %s: %s", src, err.Error())
+	}
+
+	cmd := exec.Command(xzPath, "-dc", src)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if err := untar(stdout, configDirectory); err != nil {
+		// drain output so that xz exits
+		_, _ = io.Copy(ioutil.Discard, stdout)
+		_ = cmd.Wait()
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("error decompressing %s: %s", src, err.Error())
+	}
+
+	return nil
+}
+
+func untar(r io.Reader, configDirectory string) error {
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		filename := path.Base(header.Name)
+		if !isFFMPEGBinary(filename) {
+			continue
+		}
+
+		untarredPath := filepath.Join(configDirectory, filename)
+		untarredOutput, err := os.Create(untarredPath)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(untarredOutput, tarReader)
+		if err != nil {
+			untarredOutput.Close()
+			return err
+		}
+
+		if err := untarredOutput.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package ffmpeg
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func TestGetLinuxFFMPEGDownload(t *testing.T) {
+	arm64 := getLinuxFFMPEGDownload("arm64")
+	assert.Equal(t, "https://johnvansickle.com/ffmpeg/releases/ffmpeg-release-arm64-static.tar.xz", arm64.url)
+	assert.Equal(t, "https://johnvansickle.com/ffmpeg/releases/ffmpeg-release-arm64-static.tar.xz.md5", arm64.checksumURL)
+	assert.Equal(t, "https://johnvansickle.com/ffmpeg/releases/ffmpeg-release-armhf-static.tar.xz", getLinuxFFMPEGDownload("arm").url)
+	assert.Equal(t, "", getLinuxFFMPEGDownload("mips").url)
+}
+
+func TestParseChecksum(t *testing.T) {
+	checksum, err := parseChecksum("D41D8CD98F00B204E9800998ECF8427E  ffmpeg-release-amd64-static.tar.xz\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", checksum)
+
+	_, err = parseChecksum("")
+	assert.NotNil(t, err)
+
+	_, err = parseChecksum("<html>")
+	assert.NotNil(t, err)
+}
+
+func TestDownloadPartResume(t *testing.T) {
+	const content = "ffmpeg archive content"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "archive.zip", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "stash-ffmpeg-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	partPath := filepath.Join(dir, "archive.zip.part")
+	if err := ioutil.WriteFile(partPath, []byte(content[:6]), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := downloadPart(server.URL+"/archive.zip", partPath); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(partPath)
+	assert.Nil(t, err)
+	assert.Equal(t, content, string(data))
+
+	// already complete
+	assert.Nil(t, downloadPart(server.URL+"/archive.zip", partPath))
+
+	sum := sha256.Sum256([]byte(content))
+	assert.Nil(t, utils.VerifyChecksum(partPath, hex.EncodeToString(sum[:]), sha256.New()))
+	assert.NotNil(t, utils.VerifyChecksum(partPath, "00", sha256.New()))
+}
+
+func TestUntar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-ffmpeg-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	files := map[string]string{
+		"ffmpeg-4.4-arm64-static/ffmpeg":     "ffmpeg",
+		"ffmpeg-4.4-arm64-static/ffprobe":    "ffprobe",
+		"ffmpeg-4.4-arm64-static/readme.txt": "readme",
+	}
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := untar(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "ffmpeg"))
+	assert.Nil(t, err)
+	assert.Equal(t, "ffmpeg", string(data))
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "ffprobe"))
+	assert.Nil(t, err)
+	assert.Equal(t, "ffprobe", string(data))
+
+	_, err = os.Stat(filepath.Join(dir, "readme.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -10,10 +10,14 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 type Encoder struct {
 	Path string
+
+	// HWAccel is the hardware encoder used for H.264 streams and previews
+	HWAccel models.HardwareAcceleration
 }
 
 var (
@@ -96,6 +100,9 @@ func (e *Encoder) runTranscode(probeResult VideoFile, args []string) (string, er
 		logger.Error("FFMPEG stdout not available: " + err.Error())
 	}
 
+	processScheduler.acquire(PriorityBackground)
+	defer processScheduler.release(PriorityBackground)
+
 	if err = cmd.Start(); err != nil {
 		return "", err
 	}
@@ -140,12 +147,21 @@ func (e *Encoder) runTranscode(probeResult VideoFile, args []string) (string, er
 }
 
 func (e *Encoder) run(probeResult VideoFile, args []string) (string, error) {
+	return e.runWithPriority(probeResult, args, PriorityBackground)
+}
+
+// runWithPriority runs ffmpeg once the scheduler allows a process with the
+// priority to start.
+func (e *Encoder) runWithPriority(probeResult VideoFile, args []string, priority ProcessPriority) (string, error) {
 	cmd := exec.Command(e.Path, args...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	processScheduler.acquire(priority)
+	defer processScheduler.release(priority)
+
 	if err := cmd.Start(); err != nil {
 		return "", err
 	}
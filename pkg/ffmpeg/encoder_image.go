@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ImageToWebP encodes the image as a WebP image of the quality, from 0 to
+// 100. The pixels are piped to ffmpeg, so that images in archives are
+// encoded without being extracted.
+func (e *Encoder) ImageToWebP(img *image.NRGBA, quality int, priority ProcessPriority) ([]byte, error) {
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, errors.New("image is empty")
+	}
+
+	// copy the rows if the image is a part of a larger one
+	pix := img.Pix
+	if img.Stride != width*4 || len(pix) != width*height*4 {
+		pix = make([]byte, 0, width*height*4)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			offset := img.PixOffset(bounds.Min.X, y)
+			pix = append(pix, img.Pix[offset:offset+width*4]...)
+		}
+	}
+
+	args := []string{
+		"-v", "error",
+		"-f", "rawvideo",
+		"-pix_fmt", "rgba",
+		"-s", fmt.Sprintf("%dx%d", width, height),
+		"-i", "-",
+		"-frames:v", "1",
+		"-c:v", "libwebp",
+		"-quality", strconv.Itoa(quality),
+		"-f", "image2",
+		"-",
+	}
+
+	cmd := exec.Command(e.Path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(pix)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	processScheduler.acquire(priority)
+	defer processScheduler.release(priority)
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error encoding webp image: %s: %s", err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	if stdout.Len() == 0 {
+		return nil, errors.New("error encoding webp image: no output")
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -27,7 +27,7 @@ func (e *Encoder) SceneMarkerVideo(probeResult VideoFile, options SceneMarkerOpt
 		"-crf", "24",
 		"-movflags", "+faststart",
 		"-threads", "4",
-		"-vf", fmt.Sprintf("scale=%v:-2", options.Width),
+		"-vf", e.videoFilter(probeResult, fmt.Sprintf("scale=%v:-2", options.Width)),
 		"-sws_flags", "lanczos",
 		"-c:a", "aac",
 		"-b:a", "64k",
@@ -51,7 +51,7 @@ func (e *Encoder) SceneMarkerImage(probeResult VideoFile, options SceneMarkerOpt
 		"-preset", "default",
 		"-loop", "0",
 		"-threads", "4",
-		"-vf", fmt.Sprintf("scale=%v:-2,fps=12", options.Width),
+		"-vf", e.videoFilter(probeResult, fmt.Sprintf("scale=%v:-2", options.Width), "fps=12"),
 		"-an",
 		options.OutputPath,
 	}
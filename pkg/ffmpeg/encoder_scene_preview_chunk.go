@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -12,9 +14,28 @@ type ScenePreviewChunkOptions struct {
 	Duration   float64
 	Width      int
 	OutputPath string
+
+	// Profile replaces the default encoder settings if set
+	Profile *models.TranscodeProfile
 }
 
+// ScenePreviewVideoChunk encodes a chunk of a scene preview, using the
+// hardware encoder if set and there is no transcode profile. The chunk is
+// encoded in software if the hardware encoder fails.
 func (e *Encoder) ScenePreviewVideoChunk(probeResult VideoFile, options ScenePreviewChunkOptions, preset string, fallback bool) error {
+	if hw, found := hwEncoders[e.HWAccel]; found && options.Profile == nil {
+		err := e.scenePreviewVideoChunk(probeResult, options, preset, fallback, &hw)
+		if err == nil {
+			return nil
+		}
+
+		logger.Warnf("[generator] hardware encoder %s failed. Falling back to software encoding", hw.codec)
+	}
+
+	return e.scenePreviewVideoChunk(probeResult, options, preset, fallback, nil)
+}
+
+func (e *Encoder) scenePreviewVideoChunk(probeResult VideoFile, options ScenePreviewChunkOptions, preset string, fallback bool, hw *hwEncoder) error {
 	var fastSeek float64
 	var slowSeek float64
 	fallbackMinSlowSeek := 20.0
@@ -23,6 +44,10 @@ func (e *Encoder) ScenePreviewVideoChunk(probeResult VideoFile, options ScenePre
 		"-v", "error",
 	}
 
+	if hw != nil {
+		args = append(args, hw.inputArgs...)
+	}
+
 	// Non-fallback: enable xerror.
 	// "-xerror" causes ffmpeg to fail on warnings, often the preview is fine but could be broken.
 	if !fallback {
@@ -58,23 +83,46 @@ func (e *Encoder) ScenePreviewVideoChunk(probeResult VideoFile, options ScenePre
 		args = append(args, strconv.FormatFloat(slowSeek, 'f', 2, 64))
 	}
 
-	args2 := []string{
-		"-t", strconv.FormatFloat(options.Duration, 'f', 2, 64),
-		"-max_muxing_queue_size", "1024", // https://trac.ffmpeg.org/ticket/6375
-		"-y",
+	videoArgs := []string{
 		"-c:v", "libx264",
 		"-pix_fmt", "yuv420p",
 		"-profile:v", "high",
 		"-level", "4.2",
 		"-preset", preset,
 		"-crf", "21",
-		"-threads", "4",
-		"-vf", fmt.Sprintf("scale=%v:-2", options.Width),
+	}
+	audioArgs := []string{
 		"-c:a", "aac",
 		"-b:a", "128k",
+	}
+	filters := []string{fmt.Sprintf("scale=%v:-2", options.Width)}
+
+	if hw != nil {
+		videoArgs = append([]string{"-c:v", hw.codec}, hw.args...)
+		filters = append(filters, hw.filters...)
+	}
+
+	encoderArgs := append(videoArgs, audioArgs...)
+	if p := options.Profile; p != nil {
+		encoderArgs = profileArgs(encoderArgs, p)
+		if p.MaxResolution != nil && p.MaxResolution.IsValid() {
+			filters[0] = "scale=" + calculateTranscodeScale(probeResult, *p.MaxResolution)
+		}
+	}
+
+	args = append(args,
+		"-t", strconv.FormatFloat(options.Duration, 'f', 2, 64),
+		"-max_muxing_queue_size", "1024", // https://trac.ffmpeg.org/ticket/6375
+		"-y",
+	)
+
+	args2 := []string{
+		"-threads", "4",
+		"-vf", e.videoFilter(probeResult, filters...),
 		"-strict", "-2",
-		options.OutputPath,
 	}
+	args2 = append(args2, encoderArgs...)
+	args2 = append(args2, options.OutputPath)
 
 	finalArgs := append(args, args2...)
 
@@ -95,22 +143,52 @@ func (e *Encoder) ScenePreviewVideoChunkCombine(probeResult VideoFile, concatFil
 	return err
 }
 
-func (e *Encoder) ScenePreviewVideoToImage(probeResult VideoFile, width int, videoPreviewPath string, outputPath string) error {
+// ScenePreviewImageOptions are the options of an animated WebP preview
+// image.
+type ScenePreviewImageOptions struct {
+	Width int
+	// Quality is from 0 to 100. The image is encoded losslessly if it is 100.
+	Quality int
+	Fps     int
+}
+
+// webpQualityArgs returns the encoder arguments for the quality. The quality
+// sets the compression effort in lossless mode.
+func webpQualityArgs(quality int) []string {
+	if quality >= 100 {
+		return []string{
+			"-lossless", "1",
+			"-q:v", "70",
+		}
+	}
+
+	if quality < 0 {
+		quality = 0
+	}
+
+	return []string{
+		"-lossless", "0",
+		"-q:v", strconv.Itoa(quality),
+	}
+}
+
+func (e *Encoder) ScenePreviewVideoToImage(probeResult VideoFile, options ScenePreviewImageOptions, videoPreviewPath string, outputPath string) error {
 	args := []string{
 		"-v", "error",
 		"-i", videoPreviewPath,
 		"-y",
 		"-c:v", "libwebp",
-		"-lossless", "1",
-		"-q:v", "70",
+	}
+	args = append(args, webpQualityArgs(options.Quality)...)
+	args = append(args,
 		"-compression_level", "6",
 		"-preset", "default",
 		"-loop", "0",
 		"-threads", "4",
-		"-vf", fmt.Sprintf("scale=%v:-2,fps=12", width),
+		"-vf", fmt.Sprintf("scale=%v:-2,fps=%d", options.Width, options.Fps),
 		"-an",
 		outputPath,
-	}
+	)
 	_, err := e.run(probeResult, args)
 	return err
 }
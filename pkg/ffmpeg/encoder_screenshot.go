@@ -1,6 +1,10 @@
 package ffmpeg
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
 type ScreenshotOptions struct {
 	OutputPath string
@@ -24,7 +28,7 @@ func (e *Encoder) Screenshot(probeResult VideoFile, options ScreenshotOptions) e
 		"-i", probeResult.Path,
 		"-vframes", "1",
 		"-q:v", fmt.Sprintf("%v", options.Quality),
-		"-vf", fmt.Sprintf("scale=%v:-1", options.Width),
+		"-vf", e.videoFilter(probeResult, fmt.Sprintf("scale=%v:-1", options.Width)),
 		"-f", "image2",
 		options.OutputPath,
 	}
@@ -32,3 +36,56 @@ func (e *Encoder) Screenshot(probeResult VideoFile, options ScreenshotOptions) e
 
 	return err
 }
+
+// Frame returns the frame of the video at the time, in seconds, as a JPEG
+// image. The frame is scaled to width, if it is positive and less than the
+// width of the video.
+func (e *Encoder) Frame(probeResult VideoFile, at float64, width int) ([]byte, error) {
+	if probeResult.VideoStream == nil {
+		return nil, errors.New("file has no video stream")
+	}
+
+	if width <= 0 || width > probeResult.Width {
+		width = probeResult.Width
+	}
+
+	args := []string{
+		"-v", "error",
+		"-ss", fmt.Sprintf("%v", frameTime(probeResult, at)),
+		"-i", probeResult.Path,
+		"-frames:v", "1",
+		"-q:v", "2",
+		"-vf", e.videoFilter(probeResult, fmt.Sprintf("scale=%v:-2", width)),
+		"-f", "image2",
+		"-c:v", "mjpeg",
+		"-",
+	}
+
+	// the user is waiting for the frame
+	out, err := e.runWithPriority(probeResult, args, PriorityInteractive)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no frame at %v", at)
+	}
+
+	return []byte(out), nil
+}
+
+// frameTime returns the time of the frame to capture, which is before the
+// end of the video. Seeking to the end of the video returns no frame.
+func frameTime(probeResult VideoFile, at float64) float64 {
+	if at < 0 {
+		return 0
+	}
+
+	// the last frame may be shorter than a second
+	last := probeResult.Duration - 1
+	if probeResult.Duration > 0 && at > last {
+		return math.Max(last, 0)
+	}
+
+	return at
+}
@@ -0,0 +1,29 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration float64
+		at       float64
+		want     float64
+	}{
+		{"negative", 60, -5, 0},
+		{"start", 60, 0, 0},
+		{"middle", 60, 30.5, 30.5},
+		{"end", 60, 60, 59},
+		{"past end", 60, 120, 59},
+		{"short video", 0.5, 10, 0},
+		{"unknown duration", 0, 10, 10},
+	}
+
+	for _, tt := range tests {
+		v := VideoFile{Duration: tt.duration}
+		assert.Equal(t, tt.want, frameTime(v, tt.at), tt.name)
+	}
+}
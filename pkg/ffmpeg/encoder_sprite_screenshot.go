@@ -17,7 +17,7 @@ func (e *Encoder) SpriteScreenshot(probeResult VideoFile, options SpriteScreensh
 		"-ss", fmt.Sprintf("%v", options.Time),
 		"-i", probeResult.Path,
 		"-vframes", "1",
-		"-vf", fmt.Sprintf("scale=%v:-1", options.Width),
+		"-vf", e.videoFilter(probeResult, fmt.Sprintf("scale=%v:-1", options.Width)),
 		"-c:v", "bmp",
 		"-f", "rawvideo",
 		"-",
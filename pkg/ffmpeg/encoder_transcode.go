@@ -59,7 +59,7 @@ func (e *Encoder) Transcode(probeResult VideoFile, options TranscodeOptions) {
 		"-level", "4.2",
 		"-preset", "superfast",
 		"-crf", "23",
-		"-vf", "scale=" + scale,
+		"-vf", e.videoFilter(probeResult, "scale="+scale),
 		"-c:a", "aac",
 		"-strict", "-2",
 		options.OutputPath,
@@ -81,7 +81,7 @@ func (e *Encoder) TranscodeVideo(probeResult VideoFile, options TranscodeOptions
 		"-level", "4.2",
 		"-preset", "superfast",
 		"-crf", "23",
-		"-vf", "scale=" + scale,
+		"-vf", e.videoFilter(probeResult, "scale="+scale),
 		options.OutputPath,
 	}
 	_, _ = e.runTranscode(probeResult, args)
@@ -68,6 +68,14 @@ var validForVp9 = []Container{Webm}
 var validForHevcMkv = []Container{Mp4, Matroska}
 var validForHevc = []Container{Mp4}
 
+// validForCast are the containers of each video codec which cast devices can
+// play directly
+var validForCast = map[string][]Container{
+	H264: {Mp4},
+	Vp8:  {Webm},
+	Vp9:  {Webm},
+}
+
 var validAudioForMkv = []AudioCodec{Aac, Mp3, Vorbis, Opus}
 var validAudioForWebm = []AudioCodec{Vorbis, Opus}
 var validAudioForMp4 = []AudioCodec{Aac, Mp3}
@@ -196,6 +204,12 @@ func IsStreamable(videoCodec string, audioCodec AudioCodec, container Container)
 	return IsValidCodec(videoCodec, supportedVideoCodecs) && IsValidCombo(videoCodec, container, supportedVideoCodecs) && IsValidAudioForContainer(audioCodec, container)
 }
 
+// IsCastable returns whether cast devices can play the file directly, without
+// it being transcoded.
+func IsCastable(videoCodec string, audioCodec AudioCodec, container Container) bool {
+	return IsValidForContainer(container, validForCast[videoCodec]) && IsValidAudioForContainer(audioCodec, container)
+}
+
 type VideoFile struct {
 	JSON        FFProbeJSON
 	AudioStream *FFProbeStream
@@ -221,8 +235,18 @@ type VideoFile struct {
 	AudioCodec string
 }
 
-// Execute exec command and bind result to struct.
+// Execute exec command and bind result to struct. The output of ffprobe is
+// cached, so ffprobe is only run if the file has not been probed or has
+// changed since.
 func NewVideoFile(ffprobePath string, videoPath string, stripExt bool) (*VideoFile, error) {
+	info, err := os.Stat(videoPath)
+	cache := err == nil
+	if cache {
+		if probeJSON := defaultProbeCache.get(videoPath, info); probeJSON != nil {
+			return parse(videoPath, probeJSON, stripExt)
+		}
+	}
+
 	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_error", videoPath}
 	//// Extremely slow on windows for some reason
 	//if runtime.GOOS != "windows" {
@@ -239,6 +263,10 @@ func NewVideoFile(ffprobePath string, videoPath string, stripExt bool) (*VideoFi
 		return nil, fmt.Errorf("Error unmarshalling video data for <%s>: %s", videoPath, err.Error())
 	}
 
+	if cache && probeJSON.Error.Code == 0 {
+		defaultProbeCache.put(videoPath, info, probeJSON)
+	}
+
 	return parse(videoPath, probeJSON, stripExt)
 }
 
@@ -321,10 +349,14 @@ func (v *VideoFile) GetAudioStream() *FFProbeStream {
 	return nil
 }
 
+// GetVideoStream returns the first video stream of the file. The cover art
+// of audio files is stored as a video stream with a single frame, which is
+// not returned.
 func (v *VideoFile) GetVideoStream() *FFProbeStream {
-	index := v.getStreamIndex("video", v.JSON)
-	if index != -1 {
-		return &v.JSON.Streams[index]
+	for i, stream := range v.JSON.Streams {
+		if stream.CodecType == "video" && stream.Disposition.AttachedPic == 0 {
+			return &v.JSON.Streams[i]
+		}
 	}
 	return nil
 }
@@ -3,12 +3,117 @@ package ffmpeg
 import (
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
 )
 
 const hlsSegmentLength = 10.0
 
-func WriteHLSPlaylist(probeResult VideoFile, baseUrl string, w io.Writer) {
+// HLSVariant is a quality level of an adaptive HLS stream.
+type HLSVariant struct {
+	Resolution models.StreamingResolutionEnum
+	Width      int
+	Height     int
+	// Bandwidth is the approximate peak bitrate of the variant, in bits per
+	// second
+	Bandwidth int
+}
+
+// hlsLevels are the resolutions of the HLS variants, from lowest to
+// highest, with the size of the smaller dimension and the bandwidth of each.
+var hlsLevels = []struct {
+	resolution models.StreamingResolutionEnum
+	size       int
+	bandwidth  int
+}{
+	{models.StreamingResolutionEnumLow, 240, 500000},
+	{models.StreamingResolutionEnumStandard, 480, 1500000},
+	{models.StreamingResolutionEnumStandardHd, 720, 3000000},
+	{models.StreamingResolutionEnumFullHd, 1080, 6000000},
+	{models.StreamingResolutionEnumFourK, 2160, 16000000},
+}
+
+// GetHLSVariants returns the HLS variants of the video, which are the
+// resolutions no larger than the video and maxSize. Returns nil if the video
+// is smaller than the lowest resolution.
+func GetHLSVariants(probeResult VideoFile, maxSize models.StreamingResolutionEnum) []HLSVariant {
+	videoSize := probeResult.Height
+	if probeResult.Width < videoSize {
+		videoSize = probeResult.Width
+	}
+
+	maxLevelSize := 0
+	for _, l := range hlsLevels {
+		if l.resolution == maxSize {
+			maxLevelSize = l.size
+		}
+	}
+
+	var ret []HLSVariant
+	for _, l := range hlsLevels {
+		if l.size > videoSize || (maxLevelSize != 0 && l.size > maxLevelSize) {
+			break
+		}
+
+		width, height := scaledSize(probeResult, l.size)
+		ret = append(ret, HLSVariant{
+			Resolution: l.resolution,
+			Width:      width,
+			Height:     height,
+			Bandwidth:  l.bandwidth,
+		})
+	}
+
+	return ret
+}
+
+// scaledSize returns the size of the video with the smaller dimension
+// scaled to size, rounding the other dimension to an even number as the
+// -2 scale option does.
+func scaledSize(probeResult VideoFile, size int) (int, int) {
+	if probeResult.Width == 0 || probeResult.Height == 0 {
+		return 0, 0
+	}
+
+	if probeResult.Width > probeResult.Height {
+		width := size * probeResult.Width / probeResult.Height
+		return width - width%2, size
+	}
+
+	height := size * probeResult.Height / probeResult.Width
+	return size, height - height%2
+}
+
+// WriteHLSMasterPlaylist writes a master playlist of the variants. The
+// playlist of each variant is playlistURL with the resolution of the
+// variant.
+func WriteHLSMasterPlaylist(variants []HLSVariant, playlistURL string, w io.Writer) {
+	fmt.Fprint(w, "#EXTM3U\n")
+	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
+
+	for _, v := range variants {
+		fmt.Fprintf(w, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n", v.Bandwidth, v.Width, v.Height, v.Resolution.String())
+		fmt.Fprintf(w, "%s\n", withQuery(playlistURL, "resolution", v.Resolution.String()))
+	}
+}
+
+// withQuery returns u with the query parameter added.
+func withQuery(u string, key string, value string) string {
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+
+	return u + sep + key + "=" + url.QueryEscape(value)
+}
+
+// WriteHLSPlaylist writes the media playlist of the video. The segments are
+// transcoded at resolution if it is not empty. The API key of baseUrl is kept
+// in the segment URLs, since clients such as cast devices authenticate each
+// request with it.
+func WriteHLSPlaylist(probeResult VideoFile, baseUrl string, resolution models.StreamingResolutionEnum, w io.Writer) {
 	fmt.Fprint(w, "#EXTM3U\n")
 	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
 	fmt.Fprint(w, "#EXT-X-MEDIA-SEQUENCE:0\n")
@@ -25,14 +130,27 @@ func WriteHLSPlaylist(probeResult VideoFile, baseUrl string, w io.Writer) {
 	i := strings.LastIndex(baseUrl, ".m3u8")
 	tsURL = baseUrl[0:i] + ".ts"
 
+	var apiKey string
+	if u, err := url.Parse(baseUrl); err == nil {
+		apiKey = u.Query().Get("apikey")
+	}
+
 	for leftover > 0 {
 		thisLength := hlsSegmentLength
 		if leftover < thisLength {
 			thisLength = leftover
 		}
 
+		segmentURL := fmt.Sprintf("%s?start=%f", tsURL, upTo)
+		if resolution != "" {
+			segmentURL = withQuery(segmentURL, "resolution", resolution.String())
+		}
+		if apiKey != "" {
+			segmentURL = withQuery(segmentURL, "apikey", apiKey)
+		}
+
 		fmt.Fprintf(w, "#EXTINF: %f,\n", thisLength)
-		fmt.Fprintf(w, "%s?start=%f\n", tsURL, upTo)
+		fmt.Fprintf(w, "%s\n", segmentURL)
 
 		leftover -= thisLength
 		upTo += thisLength
@@ -0,0 +1,54 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHLSVariants(t *testing.T) {
+	video := VideoFile{Width: 1920, Height: 1080}
+
+	variants := GetHLSVariants(video, models.StreamingResolutionEnumOriginal)
+	assert.Equal(t, []HLSVariant{
+		{Resolution: models.StreamingResolutionEnumLow, Width: 426, Height: 240, Bandwidth: 500000},
+		{Resolution: models.StreamingResolutionEnumStandard, Width: 852, Height: 480, Bandwidth: 1500000},
+		{Resolution: models.StreamingResolutionEnumStandardHd, Width: 1280, Height: 720, Bandwidth: 3000000},
+		{Resolution: models.StreamingResolutionEnumFullHd, Width: 1920, Height: 1080, Bandwidth: 6000000},
+	}, variants)
+
+	assert.Len(t, GetHLSVariants(video, models.StreamingResolutionEnumStandard), 2)
+
+	portrait := GetHLSVariants(VideoFile{Width: 720, Height: 1280}, models.StreamingResolutionEnumOriginal)
+	if assert.Len(t, portrait, 3) {
+		assert.Equal(t, 720, portrait[2].Width)
+		assert.Equal(t, 1280, portrait[2].Height)
+	}
+
+	assert.Nil(t, GetHLSVariants(VideoFile{Width: 320, Height: 180}, models.StreamingResolutionEnumOriginal))
+}
+
+func TestWriteHLSPlaylists(t *testing.T) {
+	video := VideoFile{Width: 1280, Height: 720, Duration: 15}
+
+	var master strings.Builder
+	WriteHLSMasterPlaylist(GetHLSVariants(video, models.StreamingResolutionEnumStandard), "/scene/1/stream.m3u8", &master)
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-STREAM-INF:BANDWIDTH=500000,RESOLUTION=426x240,NAME="LOW"
+/scene/1/stream.m3u8?resolution=LOW
+#EXT-X-STREAM-INF:BANDWIDTH=1500000,RESOLUTION=852x480,NAME="STANDARD"
+/scene/1/stream.m3u8?resolution=STANDARD
+`, master.String())
+
+	var media strings.Builder
+	WriteHLSPlaylist(video, "/scene/1/stream.m3u8?resolution=LOW", models.StreamingResolutionEnumLow, &media)
+	assert.Contains(t, media.String(), "/scene/1/stream.ts?start=0.000000&resolution=LOW\n")
+	assert.Contains(t, media.String(), "/scene/1/stream.ts?start=10.000000&resolution=LOW\n")
+
+	media.Reset()
+	WriteHLSPlaylist(video, "/scene/1/stream.m3u8?apikey=key&resolution=LOW", models.StreamingResolutionEnumLow, &media)
+	assert.Contains(t, media.String(), "/scene/1/stream.ts?start=0.000000&resolution=LOW&apikey=key\n")
+}
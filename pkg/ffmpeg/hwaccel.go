@@ -0,0 +1,155 @@
+package ffmpeg
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// vaapiDevice is the render device used for VAAPI encoding.
+const vaapiDevice = "/dev/dri/renderD128"
+
+// hwAccelTestTimeout is the maximum time taken to test a hardware encoder.
+const hwAccelTestTimeout = 10 * time.Second
+
+// hwEncoder is a hardware H.264 encoder of ffmpeg.
+type hwEncoder struct {
+	codec string
+	// inputArgs are set before the input file
+	inputArgs []string
+	// filters are appended to the video filter chain, to convert frames to a
+	// format accepted by the encoder
+	filters []string
+	// args set the quality of the encoder, in place of the preset and crf of
+	// libx264
+	args []string
+}
+
+var hwEncoders = map[models.HardwareAcceleration]hwEncoder{
+	models.HardwareAccelerationNvenc: {
+		codec: "h264_nvenc",
+		args:  []string{"-preset", "fast", "-rc", "vbr", "-cq", "25"},
+	},
+	models.HardwareAccelerationQsv: {
+		codec:   "h264_qsv",
+		filters: []string{"format=nv12"},
+		args:    []string{"-preset", "veryfast", "-global_quality", "25"},
+	},
+	models.HardwareAccelerationVaapi: {
+		codec:     "h264_vaapi",
+		inputArgs: []string{"-vaapi_device", vaapiDevice},
+		filters:   []string{"format=nv12", "hwupload"},
+		args:      []string{"-qp", "25"},
+	},
+	models.HardwareAccelerationVideotoolbox: {
+		codec: "h264_videotoolbox",
+		args:  []string{"-b:v", "6M"},
+	},
+}
+
+// hwAccelPreference is the order in which hardware encoders are chosen if
+// the hardware acceleration is AUTO.
+var hwAccelPreference = []models.HardwareAcceleration{
+	models.HardwareAccelerationNvenc,
+	models.HardwareAccelerationQsv,
+	models.HardwareAccelerationVaapi,
+	models.HardwareAccelerationVideotoolbox,
+}
+
+// DetectHWAccels returns the hardware encoders which work with the ffmpeg
+// binary. Each encoder is tested by encoding a single frame, since ffmpeg
+// builds include encoders for which there is no hardware.
+func DetectHWAccels(ffmpegPath string) []models.HardwareAcceleration {
+	var ret []models.HardwareAcceleration
+	for _, hw := range hwAccelPreference {
+		if err := testHWEncoder(ffmpegPath, hwEncoders[hw]); err != nil {
+			logger.Debugf("hardware encoder %s is not available: %s", hwEncoders[hw].codec, err.Error())
+			continue
+		}
+
+		ret = append(ret, hw)
+	}
+
+	return ret
+}
+
+func testHWEncoder(ffmpegPath string, e hwEncoder) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hwAccelTestTimeout)
+	defer cancel()
+
+	args := []string{"-hide_banner", "-v", "error"}
+	args = append(args, e.inputArgs...)
+	args = append(args, "-f", "lavfi", "-i", "color=black:s=320x240:d=0.1")
+	if len(e.filters) > 0 {
+		args = append(args, "-vf", strings.Join(e.filters, ","))
+	}
+	args = append(args, "-c:v", e.codec, "-frames:v", "1", "-f", "null", "-")
+
+	return exec.CommandContext(ctx, ffmpegPath, args...).Run()
+}
+
+// SelectHWAccel returns the hardware acceleration to use, given the
+// configured value and the available hardware encoders. Returns NONE if the
+// configured encoder is not available.
+func SelectHWAccel(configured models.HardwareAcceleration, available []models.HardwareAcceleration) models.HardwareAcceleration {
+	if configured == models.HardwareAccelerationAuto {
+		if len(available) > 0 {
+			return available[0]
+		}
+		return models.HardwareAccelerationNone
+	}
+
+	for _, hw := range available {
+		if hw == configured {
+			return hw
+		}
+	}
+
+	return models.HardwareAccelerationNone
+}
+
+// withoutArgs returns args without the named options and their values.
+func withoutArgs(args []string, names ...string) []string {
+	var ret []string
+	for i := 0; i < len(args); i++ {
+		skip := false
+		for _, n := range names {
+			if args[i] == n {
+				skip = true
+				break
+			}
+		}
+
+		if skip {
+			// skip the value as well
+			i++
+			continue
+		}
+
+		ret = append(ret, args[i])
+	}
+
+	return ret
+}
+
+// hwCodec returns the codec using the hardware encoder in place of libx264.
+// Returns false if the codec is not encoded with libx264, or if the encoder
+// settings were set by a transcode profile.
+func (c Codec) hwCodec(hw models.HardwareAcceleration) (Codec, bool) {
+	e, found := hwEncoders[hw]
+	if !found || c.Codec != CodecH264.Codec || c.profile {
+		return c, false
+	}
+
+	ret := c
+	ret.Codec = e.codec
+	ret.inputArgs = e.inputArgs
+	ret.filters = e.filters
+	ret.extraArgs = append(withoutArgs(c.extraArgs, "-pix_fmt", "-preset", "-crf"), e.args...)
+
+	return ret, true
+}
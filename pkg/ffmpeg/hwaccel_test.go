@@ -0,0 +1,37 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectHWAccel(t *testing.T) {
+	available := []models.HardwareAcceleration{models.HardwareAccelerationQsv, models.HardwareAccelerationVaapi}
+
+	assert.Equal(t, models.HardwareAccelerationQsv, SelectHWAccel(models.HardwareAccelerationAuto, available))
+	assert.Equal(t, models.HardwareAccelerationVaapi, SelectHWAccel(models.HardwareAccelerationVaapi, available))
+	assert.Equal(t, models.HardwareAccelerationNone, SelectHWAccel(models.HardwareAccelerationNvenc, available))
+	assert.Equal(t, models.HardwareAccelerationNone, SelectHWAccel(models.HardwareAccelerationAuto, nil))
+}
+
+func TestCodecHWCodec(t *testing.T) {
+	codec, ok := CodecH264.hwCodec(models.HardwareAccelerationVaapi)
+	if assert.True(t, ok) {
+		assert.Equal(t, "h264_vaapi", codec.Codec)
+		assert.Equal(t, []string{"-movflags", "frag_keyframe+empty_moov", "-qp", "25"}, codec.extraArgs)
+
+		args := TranscodeStreamOptions{
+			ProbeResult: VideoFile{Path: "video.mp4", Width: 1920, Height: 1080},
+			Codec:       codec,
+		}.getStreamArgs()
+		assert.Subset(t, args, []string{"-vaapi_device", vaapiDevice, "scale=iw:-2,format=nv12,hwupload"})
+	}
+
+	_, ok = CodecVP9.hwCodec(models.HardwareAccelerationVaapi)
+	assert.False(t, ok)
+
+	_, ok = CodecH264.hwCodec(models.HardwareAccelerationNone)
+	assert.False(t, ok)
+}
@@ -0,0 +1,117 @@
+package ffmpeg
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"time"
+)
+
+// probeCacheSize is the maximum number of ffprobe results kept in memory,
+// until configured otherwise.
+const probeCacheSize = 5000
+
+type probeCacheEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+	json    *FFProbeJSON
+}
+
+// probeCache holds the ffprobe output of files, so that scans and generate
+// tasks don't run ffprobe for unchanged files. Results are keyed by path,
+// and are only used while the size and modification time of the file are
+// unchanged. The least recently used result is removed when the cache is
+// full.
+type probeCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// the values are the entries, most recently used first
+	order *list.List
+}
+
+func newProbeCache(size int) *probeCache {
+	return &probeCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+var defaultProbeCache = newProbeCache(probeCacheSize)
+
+// ConfigureProbeCache sets the maximum number of ffprobe results kept in
+// memory. Results are not cached if size is zero or less.
+func ConfigureProbeCache(size int) {
+	defaultProbeCache.setSize(size)
+}
+
+func (c *probeCache) setSize(size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.size = size
+	c.evict()
+}
+
+// evict removes the least recently used entries until the cache is within
+// its size. Must be called with the mutex held.
+func (c *probeCache) evict() {
+	for c.order.Len() > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*probeCacheEntry).path)
+	}
+}
+
+// get returns a copy of the cached output for the file at path, or nil if
+// there is no output for it or the file was modified since it was probed.
+func (c *probeCache) get(path string, info os.FileInfo) *FFProbeJSON {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, found := c.entries[path]
+	if !found {
+		return nil
+	}
+
+	entry := e.Value.(*probeCacheEntry)
+	if entry.size != info.Size() || !entry.modTime.Equal(info.ModTime()) {
+		return nil
+	}
+
+	c.order.MoveToFront(e)
+
+	// copy the streams, since callers hold pointers to them
+	ret := *entry.json
+	ret.Streams = append([]FFProbeStream(nil), entry.json.Streams...)
+	return &ret
+}
+
+func (c *probeCache) put(path string, info os.FileInfo, probeJSON *FFProbeJSON) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	stored := *probeJSON
+	stored.Streams = append([]FFProbeStream(nil), probeJSON.Streams...)
+	entry := &probeCacheEntry{
+		path:    path,
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		json:    &stored,
+	}
+
+	if e, found := c.entries[path]; found {
+		e.Value = entry
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[path] = c.order.PushFront(entry)
+	c.evict()
+}
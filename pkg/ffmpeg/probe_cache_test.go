@@ -0,0 +1,83 @@
+package ffmpeg
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type probeCacheTestFile struct {
+	size    int64
+	modTime time.Time
+}
+
+func (f probeCacheTestFile) Name() string       { return "" }
+func (f probeCacheTestFile) Size() int64        { return f.size }
+func (f probeCacheTestFile) Mode() os.FileMode  { return 0644 }
+func (f probeCacheTestFile) ModTime() time.Time { return f.modTime }
+func (f probeCacheTestFile) IsDir() bool        { return false }
+func (f probeCacheTestFile) Sys() interface{}   { return nil }
+
+func TestProbeCache(t *testing.T) {
+	c := newProbeCache(2)
+	info := probeCacheTestFile{size: 1, modTime: time.Now()}
+
+	probeJSON := func(duration string) *FFProbeJSON {
+		ret := &FFProbeJSON{
+			Streams: []FFProbeStream{{CodecName: "h264"}},
+		}
+		ret.Format.Duration = duration
+		return ret
+	}
+
+	assert.Nil(t, c.get("a", info))
+
+	c.put("a", info, probeJSON("1"))
+	ret := c.get("a", info)
+	if assert.NotNil(t, ret) {
+		assert.Equal(t, "1", ret.Format.Duration)
+
+		// modifying the result must not modify the cache
+		ret.Streams[0].CodecName = "hevc"
+		assert.Equal(t, "h264", c.get("a", info).Streams[0].CodecName)
+	}
+
+	// the file was modified
+	assert.Nil(t, c.get("a", probeCacheTestFile{size: 1, modTime: info.modTime.Add(time.Second)}))
+	assert.Nil(t, c.get("a", probeCacheTestFile{size: 2, modTime: info.modTime}))
+
+	// the least recently used entry is removed
+	c.put("b", info, probeJSON("2"))
+	c.get("a", info)
+	c.put("c", info, probeJSON("3"))
+	assert.Nil(t, c.get("b", info))
+	assert.Equal(t, "1", c.get("a", info).Format.Duration)
+	assert.Equal(t, "3", c.get("c", info).Format.Duration)
+
+	// replacing an entry does not remove another
+	c.put("c", info, probeJSON("4"))
+	assert.Equal(t, "1", c.get("a", info).Format.Duration)
+	assert.Equal(t, "4", c.get("c", info).Format.Duration)
+}
+
+func TestProbeCacheSetSize(t *testing.T) {
+	c := newProbeCache(3)
+	info := probeCacheTestFile{size: 1, modTime: time.Now()}
+
+	for _, path := range []string{"a", "b", "c"} {
+		c.put(path, info, &FFProbeJSON{})
+	}
+
+	c.setSize(1)
+	assert.Nil(t, c.get("a", info))
+	assert.Nil(t, c.get("b", info))
+	assert.NotNil(t, c.get("c", info))
+
+	// nothing is cached if the size is zero
+	c.setSize(0)
+	c.put("d", info, &FFProbeJSON{})
+	assert.Nil(t, c.get("c", info))
+	assert.Nil(t, c.get("d", info))
+}
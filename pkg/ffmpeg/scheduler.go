@@ -0,0 +1,111 @@
+package ffmpeg
+
+import (
+	"sync"
+)
+
+// ProcessPriority is the priority of an ffmpeg process.
+type ProcessPriority int
+
+const (
+	// PriorityBackground is the priority of processes run by tasks, such as
+	// generating previews and sprites.
+	PriorityBackground ProcessPriority = iota
+	// PriorityInteractive is the priority of processes which a user is
+	// waiting for, such as live transcodes.
+	PriorityInteractive
+)
+
+// scheduler limits the number of concurrent ffmpeg processes. Interactive
+// processes only wait for other interactive processes. Background processes
+// wait while interactive processes are waiting, and running interactive
+// processes count towards the background limit, so that fewer background
+// processes run while scenes are being played. A limit of zero is
+// unlimited.
+type scheduler struct {
+	mutex sync.Mutex
+	cond  *sync.Cond
+
+	maxInteractive int
+	maxBackground  int
+
+	interactive        int
+	background         int
+	interactiveWaiting int
+}
+
+func newScheduler() *scheduler {
+	ret := &scheduler{}
+	ret.cond = sync.NewCond(&ret.mutex)
+	return ret
+}
+
+var processScheduler = newScheduler()
+
+// ConfigureProcessLimits sets the maximum number of concurrent live
+// transcodes and of concurrent ffmpeg processes run by tasks. A limit of
+// zero is unlimited.
+func ConfigureProcessLimits(maxTranscodes int, maxBackground int) {
+	processScheduler.setLimits(maxTranscodes, maxBackground)
+}
+
+func (s *scheduler) setLimits(maxInteractive int, maxBackground int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.maxInteractive = maxInteractive
+	s.maxBackground = maxBackground
+
+	// waiting processes may be able to start
+	s.cond.Broadcast()
+}
+
+func (s *scheduler) canStart(p ProcessPriority) bool {
+	if p == PriorityInteractive {
+		return s.maxInteractive <= 0 || s.interactive < s.maxInteractive
+	}
+
+	if s.interactiveWaiting > 0 {
+		return false
+	}
+
+	return s.maxBackground <= 0 || s.background+s.interactive < s.maxBackground
+}
+
+// acquire waits until a process with the priority can start. release must
+// be called when the process exits.
+func (s *scheduler) acquire(p ProcessPriority) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if p == PriorityInteractive {
+		s.interactiveWaiting++
+	}
+
+	for !s.canStart(p) {
+		s.cond.Wait()
+	}
+
+	if p == PriorityInteractive {
+		s.interactiveWaiting--
+		s.interactive++
+
+		// background processes may have been waiting for this process
+		s.cond.Broadcast()
+	} else {
+		s.background++
+	}
+}
+
+func (s *scheduler) release(p ProcessPriority) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if p == PriorityInteractive {
+		s.interactive--
+	} else {
+		s.background--
+	}
+
+	s.cond.Broadcast()
+}
@@ -0,0 +1,75 @@
+package ffmpeg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// acquireAsync acquires a process in a goroutine, returning a channel which
+// is closed once the process may start.
+func acquireAsync(s *scheduler, p ProcessPriority) chan struct{} {
+	ret := make(chan struct{})
+	go func() {
+		s.acquire(p)
+		close(ret)
+	}()
+	return ret
+}
+
+func isStarted(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+func TestSchedulerUnlimited(t *testing.T) {
+	s := newScheduler()
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, isStarted(acquireAsync(s, PriorityBackground)))
+		assert.True(t, isStarted(acquireAsync(s, PriorityInteractive)))
+	}
+}
+
+func TestSchedulerLimits(t *testing.T) {
+	s := newScheduler()
+	s.setLimits(1, 2)
+
+	assert.True(t, isStarted(acquireAsync(s, PriorityBackground)))
+
+	// interactive processes don't wait for background processes
+	assert.True(t, isStarted(acquireAsync(s, PriorityInteractive)))
+
+	// the running transcode counts towards the background limit
+	background := acquireAsync(s, PriorityBackground)
+	assert.False(t, isStarted(background))
+
+	interactive := acquireAsync(s, PriorityInteractive)
+	assert.False(t, isStarted(interactive))
+
+	// the waiting transcode starts before the waiting background process
+	s.release(PriorityInteractive)
+	assert.True(t, isStarted(interactive))
+	assert.False(t, isStarted(background))
+
+	s.release(PriorityInteractive)
+	assert.True(t, isStarted(background))
+}
+
+func TestSchedulerSetLimits(t *testing.T) {
+	s := newScheduler()
+	s.setLimits(0, 1)
+
+	assert.True(t, isStarted(acquireAsync(s, PriorityBackground)))
+	background := acquireAsync(s, PriorityBackground)
+	assert.False(t, isStarted(background))
+
+	// raising the limit starts waiting processes
+	s.setLimits(0, 2)
+	assert.True(t, isStarted(background))
+}
@@ -1,8 +1,11 @@
 package ffmpeg
 
 import (
+	"bufio"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
@@ -24,6 +27,11 @@ type Stream struct {
 
 func (s *Stream) Serve(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", s.mimeType)
+	// the stream is seeked by requesting a new stream with a start time
+	w.Header().Set("Accept-Ranges", "none")
+	if duration := s.options.duration(); duration > 0 {
+		w.Header().Set("X-Content-Duration", strconv.FormatFloat(duration, 'f', 3, 64))
+	}
 	w.WriteHeader(http.StatusOK)
 
 	logger.Infof("[stream] transcoding video file to %s", s.mimeType)
@@ -47,6 +55,14 @@ type Codec struct {
 	MimeType  string
 	extraArgs []string
 	hls       bool
+
+	// inputArgs and filters are set for hardware encoders
+	inputArgs []string
+	filters   []string
+
+	// profile is true if the encoder settings were set by a transcode
+	// profile
+	profile bool
 }
 
 var CodecHLS = Codec{
@@ -133,6 +149,45 @@ type TranscodeStreamOptions struct {
 	// in some videos where the audio codec is not supported by ffmpeg
 	// ffmpeg fails if you try to transcode the audio
 	VideoOnly bool
+	// AudioOnly transcodes the audio of files without a video stream
+	AudioOnly bool
+
+	// hdrFilters tone map HDR video to SDR
+	hdrFilters []string
+}
+
+// ParseStartTime returns the start time of a stream in seconds. Returns an
+// error if start is not a non-negative number.
+func ParseStartTime(start string) (float64, error) {
+	ret, err := strconv.ParseFloat(start, 64)
+	if err != nil || ret < 0 || math.IsInf(ret, 0) {
+		return 0, fmt.Errorf("invalid start time: %s", start)
+	}
+
+	return ret, nil
+}
+
+// duration returns the duration of the transcoded stream, which starts at
+// StartTime. Returns zero if the duration of the video is unknown.
+func (o TranscodeStreamOptions) duration() float64 {
+	if o.ProbeResult.Duration <= 0 {
+		return 0
+	}
+
+	start := 0.0
+	if o.StartTime != "" {
+		start, _ = ParseStartTime(o.StartTime)
+	}
+
+	ret := o.ProbeResult.Duration - start
+	if o.Codec.hls && ret > hlsSegmentLength {
+		ret = hlsSegmentLength
+	}
+	if ret < 0 {
+		return 0
+	}
+
+	return ret
 }
 
 func GetTranscodeStreamOptions(probeResult VideoFile, videoCodec Codec, audioCodec AudioCodec) TranscodeStreamOptions {
@@ -146,6 +201,8 @@ func GetTranscodeStreamOptions(probeResult VideoFile, videoCodec Codec, audioCod
 		options.VideoOnly = true
 	}
 
+	options.AudioOnly = probeResult.IsAudioOnly()
+
 	return options
 }
 
@@ -164,6 +221,8 @@ func (o TranscodeStreamOptions) getStreamArgs() []string {
 		args = append(args, "-t", strconv.Itoa(int(hlsSegmentLength)))
 	}
 
+	args = append(args, o.Codec.inputArgs...)
+
 	args = append(args,
 		"-i", o.ProbeResult.Path,
 	)
@@ -172,15 +231,22 @@ func (o TranscodeStreamOptions) getStreamArgs() []string {
 		args = append(args, "-an")
 	}
 
-	args = append(args,
-		"-c:v", o.Codec.Codec,
-	)
+	if o.AudioOnly {
+		// ignore the cover art stream
+		args = append(args, "-vn")
+	} else {
+		args = append(args,
+			"-c:v", o.Codec.Codec,
+		)
+	}
 
 	// don't set scale when copying video stream
-	if o.Codec.Codec != CopyStreamCodec {
+	if o.Codec.Codec != CopyStreamCodec && !o.AudioOnly {
 		scale := calculateTranscodeScale(o.ProbeResult, o.MaxTranscodeSize)
+		filters := append([]string{"scale=" + scale}, o.hdrFilters...)
+		filters = append(filters, o.Codec.filters...)
 		args = append(args,
-			"-vf", "scale="+scale,
+			"-vf", strings.Join(filters, ","),
 		)
 	}
 
@@ -188,6 +254,12 @@ func (o TranscodeStreamOptions) getStreamArgs() []string {
 		args = append(args, o.Codec.extraArgs...)
 	}
 
+	if o.Codec.hls && o.StartTime != "" {
+		// keep the timestamps of the segments continuous, so that players
+		// can switch between quality levels
+		args = append(args, "-output_ts_offset", o.StartTime)
+	}
+
 	args = append(args,
 		// this is needed for 5-channel ac3 files
 		"-ac", "2",
@@ -198,12 +270,65 @@ func (o TranscodeStreamOptions) getStreamArgs() []string {
 	return args
 }
 
+// GetTranscodeStream starts transcoding the video. H.264 streams are
+// encoded using the hardware encoder if set, falling back to software
+// encoding if the hardware encoder exits without output.
 func (e *Encoder) GetTranscodeStream(options TranscodeStreamOptions) (*Stream, error) {
+	if codec, ok := options.Codec.hwCodec(e.HWAccel); ok && !options.AudioOnly {
+		hwOptions := options
+		hwOptions.Codec = codec
+
+		stream, err := e.stream(options.ProbeResult, hwOptions)
+		if err == nil {
+			if stream.started() {
+				return stream, nil
+			}
+
+			// ffmpeg may still be running, holding a transcode slot
+			stream.close()
+		}
+
+		logger.Warnf("[stream] hardware encoder %s failed. Falling back to software encoding", codec.Codec)
+	}
+
 	return e.stream(options.ProbeResult, options)
 }
 
+type bufferedReadCloser struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// started waits for the first output of the stream. Returns false if
+// ffmpeg exited without output.
+func (s *Stream) started() bool {
+	r := bufio.NewReader(s.Stdout)
+	_, err := r.Peek(1)
+	s.Stdout = bufferedReadCloser{Reader: r, Closer: s.Stdout}
+
+	return err == nil
+}
+
+// close closes the output of the stream and kills ffmpeg.
+func (s *Stream) close() {
+	s.Stdout.Close()
+	_ = s.Process.Kill()
+}
+
 func (e *Encoder) stream(probeResult VideoFile, options TranscodeStreamOptions) (*Stream, error) {
-	args := options.getStreamArgs()
+	options.hdrFilters = e.hdrFilters(probeResult)
+	ret, err := e.startStream(probeResult, options.getStreamArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	ret.options = options
+	ret.mimeType = options.Codec.MimeType
+	return ret, nil
+}
+
+// startStream starts ffmpeg with the output written to stdout.
+func (e *Encoder) startStream(probeResult VideoFile, args []string) (*Stream, error) {
 	cmd := exec.Command(e.Path, args...)
 	logger.Debugf("Streaming via: %s", strings.Join(cmd.Args, " "))
 
@@ -219,12 +344,17 @@ func (e *Encoder) stream(probeResult VideoFile, options TranscodeStreamOptions)
 		return nil, err
 	}
 
+	processScheduler.acquire(PriorityInteractive)
 	if err = cmd.Start(); err != nil {
+		processScheduler.release(PriorityInteractive)
 		return nil, err
 	}
 
 	registerRunningEncoder(probeResult.Path, cmd.Process)
-	go waitAndDeregister(probeResult.Path, cmd)
+	go func() {
+		_ = waitAndDeregister(probeResult.Path, cmd)
+		processScheduler.release(PriorityInteractive)
+	}()
 
 	// stderr must be consumed or the process deadlocks
 	go func() {
@@ -236,10 +366,8 @@ func (e *Encoder) stream(probeResult VideoFile, options TranscodeStreamOptions)
 	}()
 
 	ret := &Stream{
-		Stdout:   stdout,
-		Process:  cmd.Process,
-		options:  options,
-		mimeType: options.Codec.MimeType,
+		Stdout:  stdout,
+		Process: cmd.Process,
 	}
 	return ret, nil
 }
@@ -0,0 +1,109 @@
+package ffmpeg
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStartTime(t *testing.T) {
+	start, err := ParseStartTime("12.5")
+	assert.Nil(t, err)
+	assert.Equal(t, 12.5, start)
+
+	for _, s := range []string{"", "-1", "abc", "Inf", "10; rm"} {
+		_, err := ParseStartTime(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestTranscodeStreamOptionsDuration(t *testing.T) {
+	options := TranscodeStreamOptions{
+		ProbeResult: VideoFile{Duration: 100},
+		Codec:       CodecH264,
+		StartTime:   "40",
+	}
+	assert.Equal(t, 60.0, options.duration())
+
+	options.StartTime = ""
+	assert.Equal(t, 100.0, options.duration())
+
+	options.StartTime = "120"
+	assert.Equal(t, 0.0, options.duration())
+
+	hls := TranscodeStreamOptions{
+		ProbeResult: VideoFile{Duration: 100},
+		Codec:       CodecHLS,
+		StartTime:   "95",
+	}
+	assert.Equal(t, 5.0, hls.duration())
+	hls.StartTime = "10"
+	assert.Equal(t, hlsSegmentLength, hls.duration())
+}
+
+// fakeStreamFFMpeg stands in for ffmpeg. The hardware encoder closes its
+// output without exiting, and the software encoder writes its output.
+const fakeStreamFFMpeg = `#!/bin/sh
+case "$*" in
+*h264_nvenc*) exec >&-; exec sleep 60 ;;
+*) echo output; exec sleep 60 ;;
+esac
+`
+
+func interactiveProcesses() int {
+	processScheduler.mutex.Lock()
+	defer processScheduler.mutex.Unlock()
+	return processScheduler.interactive
+}
+
+func TestGetTranscodeStreamHWFallback(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test ffmpeg requires sh")
+	}
+
+	dir, err := ioutil.TempDir("", "stash-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ffmpegPath := filepath.Join(dir, "ffmpeg")
+	if err := ioutil.WriteFile(ffmpegPath, []byte(fakeStreamFFMpeg), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	encoder := &Encoder{
+		Path:    ffmpegPath,
+		HWAccel: models.HardwareAccelerationNvenc,
+	}
+	options := TranscodeStreamOptions{
+		ProbeResult: VideoFile{Path: filepath.Join(dir, "video.mp4")},
+		Codec:       CodecH264,
+	}
+
+	stream, err := encoder.GetTranscodeStream(options)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, CodecH264.Codec, stream.options.Codec.Codec)
+
+	data := make([]byte, 6)
+	_, err = io.ReadFull(stream.Stdout, data)
+	assert.Nil(t, err)
+	assert.Equal(t, "output", string(data))
+	stream.close()
+
+	// the failed hardware encoder releases its transcode slot
+	deadline := time.Now().Add(5 * time.Second)
+	for interactiveProcesses() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, interactiveProcesses())
+}
@@ -0,0 +1,105 @@
+package ffmpeg
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// hdrTransfers are the transfer characteristics of HDR video: PQ, used by
+// HDR10 and Dolby Vision, and HLG.
+var hdrTransfers = map[string]bool{
+	"smpte2084":    true,
+	"arib-std-b67": true,
+}
+
+// tonemapFilters convert HDR video to 8-bit BT.709 video. Without them, HDR
+// video encoded as SDR looks washed out.
+var tonemapFilters = []string{
+	"zscale=t=linear:npl=100",
+	"format=gbrpf32le",
+	"zscale=p=bt709",
+	"tonemap=tonemap=hable:desat=0",
+	"zscale=t=bt709:m=bt709:r=tv",
+	"format=yuv420p",
+}
+
+// IsHDR returns true if the video stream is HDR. Streams without a transfer
+// characteristic are HDR if they are 10-bit BT.2020 video.
+func (v *VideoFile) IsHDR() bool {
+	s := v.VideoStream
+	if s == nil {
+		return false
+	}
+
+	if s.ColorTransfer != "" {
+		return hdrTransfers[s.ColorTransfer]
+	}
+
+	return s.ColorPrimaries == "bt2020" && strings.Contains(s.PixFmt, "10")
+}
+
+var (
+	tonemapSupport      = make(map[string]bool)
+	tonemapSupportMutex sync.Mutex
+)
+
+// supportsTonemap returns true if the ffmpeg binary has the zscale and
+// tonemap filters. zscale requires ffmpeg to be built with zimg, which is
+// missing from some distribution packages. The result is cached.
+func supportsTonemap(ffmpegPath string) bool {
+	tonemapSupportMutex.Lock()
+	defer tonemapSupportMutex.Unlock()
+
+	if ret, found := tonemapSupport[ffmpegPath]; found {
+		return ret
+	}
+
+	out, err := exec.Command(ffmpegPath, "-hide_banner", "-filters").Output()
+	ret := err == nil && hasFilter(string(out), "zscale") && hasFilter(string(out), "tonemap")
+	if !ret {
+		logger.Warnf("ffmpeg does not support the zscale and tonemap filters. HDR video will not be tone mapped")
+	}
+
+	tonemapSupport[ffmpegPath] = ret
+	return ret
+}
+
+// hasFilter returns true if the output of ffmpeg -filters lists the filter.
+// Each filter is listed on a line of flags, name, type and description.
+func hasFilter(filters string, name string) bool {
+	for _, line := range strings.Split(filters, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[1] == name && strings.Contains(fields[2], "->") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hdrFilters returns the filters which tone map the video to SDR, or nil if
+// the video is not HDR or ffmpeg cannot tone map.
+func (e *Encoder) hdrFilters(probeResult VideoFile) []string {
+	if !probeResult.IsHDR() || !supportsTonemap(e.Path) {
+		return nil
+	}
+
+	return tonemapFilters
+}
+
+// videoFilter returns the -vf value of the filters, with the tone mapping
+// filters inserted after the first filter, which scales the video, if the
+// video is HDR.
+func (e *Encoder) videoFilter(probeResult VideoFile, filters ...string) string {
+	hdr := e.hdrFilters(probeResult)
+	if len(hdr) == 0 || len(filters) == 0 {
+		return strings.Join(filters, ",")
+	}
+
+	ret := append([]string{filters[0]}, hdr...)
+	ret = append(ret, filters[1:]...)
+	return strings.Join(ret, ",")
+}
@@ -0,0 +1,70 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHDR(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream *FFProbeStream
+		hdr    bool
+	}{
+		{"no video", nil, false},
+		{"sdr", &FFProbeStream{ColorTransfer: "bt709", PixFmt: "yuv420p"}, false},
+		{"10-bit sdr", &FFProbeStream{ColorTransfer: "bt709", PixFmt: "yuv420p10le"}, false},
+		{"hdr10", &FFProbeStream{ColorTransfer: "smpte2084", ColorPrimaries: "bt2020", PixFmt: "yuv420p10le"}, true},
+		{"hlg", &FFProbeStream{ColorTransfer: "arib-std-b67", PixFmt: "yuv420p10le"}, true},
+		{"untagged bt2020", &FFProbeStream{ColorPrimaries: "bt2020", PixFmt: "yuv420p10le"}, true},
+		{"untagged 8-bit bt2020", &FFProbeStream{ColorPrimaries: "bt2020", PixFmt: "yuv420p"}, false},
+	}
+
+	for _, tt := range tests {
+		v := VideoFile{VideoStream: tt.stream}
+		assert.Equal(t, tt.hdr, v.IsHDR(), tt.name)
+	}
+}
+
+func TestHasFilter(t *testing.T) {
+	const filters = `Filters:
+  T.. = Timeline support
+ ... scale             V->V       Scale the input video size and/or convert the image format.
+ ..C tonemap           V->V       Conversion to/from different dynamic ranges.
+`
+
+	assert.True(t, hasFilter(filters, "scale"))
+	assert.True(t, hasFilter(filters, "tonemap"))
+	assert.False(t, hasFilter(filters, "zscale"))
+	assert.False(t, hasFilter(filters, "="))
+}
+
+func TestVideoFilter(t *testing.T) {
+	const ffmpegPath = "test-ffmpeg-tonemap"
+	tonemapSupportMutex.Lock()
+	tonemapSupport[ffmpegPath] = true
+	tonemapSupportMutex.Unlock()
+
+	e := NewEncoder(ffmpegPath)
+	sdr := VideoFile{VideoStream: &FFProbeStream{ColorTransfer: "bt709"}}
+	hdr := VideoFile{VideoStream: &FFProbeStream{ColorTransfer: "smpte2084"}}
+
+	assert.Equal(t, "scale=320:-2,fps=12", e.videoFilter(sdr, "scale=320:-2", "fps=12"))
+	assert.Equal(t, "scale=320:-2,"+strings.Join(tonemapFilters, ",")+",fps=12", e.videoFilter(hdr, "scale=320:-2", "fps=12"))
+
+	// the filters are only added if ffmpeg supports them
+	unsupported := NewEncoder("test-ffmpeg-no-tonemap")
+	tonemapSupportMutex.Lock()
+	tonemapSupport[unsupported.Path] = false
+	tonemapSupportMutex.Unlock()
+	assert.Equal(t, "scale=320:-2", unsupported.videoFilter(hdr, "scale=320:-2"))
+
+	args := TranscodeStreamOptions{
+		ProbeResult: hdr,
+		Codec:       CodecVP9,
+		hdrFilters:  e.hdrFilters(hdr),
+	}.getStreamArgs()
+	assert.Contains(t, args, "scale=iw:-2,"+strings.Join(tonemapFilters, ","))
+}
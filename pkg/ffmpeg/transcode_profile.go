@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// profileArgs returns args with the encoder settings replaced by those set
+// in the profile. The extra arguments of the profile are appended.
+func profileArgs(args []string, p *models.TranscodeProfile) []string {
+	var remove []string
+	var add []string
+
+	if p.VideoCodec != nil && *p.VideoCodec != "" {
+		// the profile and level are specific to libx264
+		remove = append(remove, "-c:v", "-vcodec", "-profile:v", "-level")
+		add = append(add, "-c:v", *p.VideoCodec)
+	}
+	if p.Preset != nil && *p.Preset != "" {
+		remove = append(remove, "-preset")
+		add = append(add, "-preset", *p.Preset)
+	}
+	if p.Crf != nil {
+		remove = append(remove, "-crf")
+		add = append(add, "-crf", strconv.Itoa(*p.Crf))
+	}
+	if p.AudioCodec != nil && *p.AudioCodec != "" {
+		remove = append(remove, "-c:a", "-acodec")
+		add = append(add, "-c:a", *p.AudioCodec)
+	}
+	if p.AudioBitrate != nil && *p.AudioBitrate != "" {
+		remove = append(remove, "-b:a")
+		add = append(add, "-b:a", *p.AudioBitrate)
+	}
+
+	ret := append(withoutArgs(args, remove...), add...)
+	return append(ret, p.ExtraArgs...)
+}
+
+// withProfile returns the codec with the encoder settings of the profile.
+func (c Codec) withProfile(p *models.TranscodeProfile) Codec {
+	ret := c
+	if p.VideoCodec != nil && *p.VideoCodec != "" {
+		ret.Codec = *p.VideoCodec
+	}
+	ret.extraArgs = profileArgs(c.extraArgs, p)
+	ret.profile = true
+
+	return ret
+}
+
+// ApplyProfile sets the encoder settings and maximum resolution of the
+// profile. The profile is only applied to H.264 transcodes, since the other
+// streams use codecs specific to their container.
+func (o *TranscodeStreamOptions) ApplyProfile(p *models.TranscodeProfile) {
+	if p == nil || o.Codec.Codec != CodecH264.Codec {
+		return
+	}
+
+	o.Codec = o.Codec.withProfile(p)
+	if p.MaxResolution != nil && p.MaxResolution.IsValid() {
+		o.MaxTranscodeSize = *p.MaxResolution
+	}
+}
@@ -0,0 +1,67 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileArgs(t *testing.T) {
+	videoCodec := "libx265"
+	crf := 28
+	audioCodec := "copy"
+
+	args := []string{
+		"-c:v", "libx264",
+		"-profile:v", "high",
+		"-preset", "veryfast",
+		"-crf", "21",
+		"-c:a", "aac",
+		"-b:a", "128k",
+	}
+
+	assert.Equal(t, []string{
+		"-preset", "veryfast",
+		"-b:a", "128k",
+		"-c:v", "libx265",
+		"-crf", "28",
+		"-c:a", "copy",
+		"-tag:v", "hvc1",
+	}, profileArgs(args, &models.TranscodeProfile{
+		VideoCodec: &videoCodec,
+		Crf:        &crf,
+		AudioCodec: &audioCodec,
+		ExtraArgs:  []string{"-tag:v", "hvc1"},
+	}))
+
+	assert.Equal(t, args, profileArgs(args, &models.TranscodeProfile{}))
+}
+
+func TestTranscodeStreamOptionsApplyProfile(t *testing.T) {
+	preset := "medium"
+	maxResolution := models.StreamingResolutionEnumStandardHd
+	profile := &models.TranscodeProfile{
+		Preset:        &preset,
+		MaxResolution: &maxResolution,
+	}
+
+	options := TranscodeStreamOptions{
+		Codec:            CodecH264,
+		MaxTranscodeSize: models.StreamingResolutionEnumOriginal,
+	}
+	options.ApplyProfile(profile)
+
+	assert.Equal(t, models.StreamingResolutionEnumStandardHd, options.MaxTranscodeSize)
+	assert.Contains(t, options.Codec.extraArgs, "medium")
+	assert.NotContains(t, options.Codec.extraArgs, "veryfast")
+
+	// hardware encoders are not used with a profile
+	_, ok := options.Codec.hwCodec(models.HardwareAccelerationNvenc)
+	assert.False(t, ok)
+
+	// profiles only apply to H.264 transcodes
+	vp9 := TranscodeStreamOptions{Codec: CodecVP9}
+	vp9.ApplyProfile(profile)
+	assert.Equal(t, CodecVP9, vp9.Codec)
+}
@@ -24,6 +24,14 @@ type FFProbeJSON struct {
 			MinorVersion     string          `json:"minor_version"`
 			Title            string          `json:"title"`
 			Comment          string          `json:"comment"`
+			Description      string          `json:"description"`
+			Synopsis         string          `json:"synopsis"`
+			Artist           string          `json:"artist"`
+			AlbumArtist      string          `json:"album_artist"`
+			Album            string          `json:"album"`
+			Genre            string          `json:"genre"`
+			Date             string          `json:"date"`
+			Track            string          `json:"track"`
 		} `json:"tags"`
 	} `json:"format"`
 	Streams []FFProbeStream `json:"streams"`
@@ -46,6 +54,9 @@ type FFProbeStream struct {
 	CodecType          string `json:"codec_type"`
 	CodedHeight        int    `json:"coded_height,omitempty"`
 	CodedWidth         int    `json:"coded_width,omitempty"`
+	ColorPrimaries     string `json:"color_primaries,omitempty"`
+	ColorSpace         string `json:"color_space,omitempty"`
+	ColorTransfer      string `json:"color_transfer,omitempty"`
 	DisplayAspectRatio string `json:"display_aspect_ratio,omitempty"`
 	Disposition        struct {
 		AttachedPic     int `json:"attached_pic"`
@@ -82,6 +93,7 @@ type FFProbeStream struct {
 		HandlerName  string          `json:"handler_name"`
 		Language     string          `json:"language"`
 		Rotate       string          `json:"rotate"`
+		Title        string          `json:"title"`
 	} `json:"tags"`
 	TimeBase      string `json:"time_base"`
 	Width         int    `json:"width,omitempty"`
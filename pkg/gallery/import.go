@@ -21,6 +21,10 @@ type Importer struct {
 	gallery    models.Gallery
 	performers []*models.Performer
 	tags       []*models.Tag
+
+	// relationships of the existing gallery when merging
+	existingPerformerIDs []int
+	existingTagIDs       []int
 }
 
 func (i *Importer) PreImport() error {
@@ -240,6 +244,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, performer := range i.performers {
 			performerIDs = append(performerIDs, performer.ID)
 		}
+		performerIDs = utils.IntAppendUniques(i.existingPerformerIDs, performerIDs)
 
 		if err := i.ReaderWriter.UpdatePerformers(id, performerIDs); err != nil {
 			return fmt.Errorf("failed to associate performers: %s", err.Error())
@@ -251,6 +256,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, t := range i.tags {
 			tagIDs = append(tagIDs, t.ID)
 		}
+		tagIDs = utils.IntAppendUniques(i.existingTagIDs, tagIDs)
 		if err := i.ReaderWriter.UpdateTags(id, tagIDs); err != nil {
 			return fmt.Errorf("failed to associate tags: %s", err.Error())
 		}
@@ -297,3 +303,40 @@ func (i *Importer) Update(id int) error {
 
 	return nil
 }
+
+// Merge sets the missing fields of the existing gallery. The organized flag
+// of the existing gallery is kept.
+func (i *Importer) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing gallery: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing gallery with id %d not found", id)
+	}
+
+	gallery := *existing
+	models.FillNullString(&gallery.Path, i.gallery.Path)
+	models.FillNullString(&gallery.Title, i.gallery.Title)
+	models.FillNullString(&gallery.URL, i.gallery.URL)
+	models.FillSQLiteDate(&gallery.Date, i.gallery.Date)
+	models.FillNullString(&gallery.Details, i.gallery.Details)
+	models.FillNullInt64(&gallery.Rating, i.gallery.Rating)
+	models.FillNullInt64(&gallery.StudioID, i.gallery.StudioID)
+
+	if _, err := i.ReaderWriter.Update(gallery); err != nil {
+		return fmt.Errorf("error updating existing gallery: %s", err.Error())
+	}
+
+	i.existingPerformerIDs, err = i.ReaderWriter.GetPerformerIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing gallery performers: %s", err.Error())
+	}
+
+	i.existingTagIDs, err = i.ReaderWriter.GetTagIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing gallery tags: %s", err.Error())
+	}
+
+	return nil
+}
@@ -22,6 +22,16 @@ func AddImage(qb models.GalleryReaderWriter, galleryID int, imageID int) error {
 	return qb.UpdateImages(galleryID, imageIDs)
 }
 
+func RemoveImage(qb models.GalleryReaderWriter, galleryID int, imageID int) error {
+	imageIDs, err := qb.GetImageIDs(galleryID)
+	if err != nil {
+		return err
+	}
+
+	imageIDs = utils.IntExclude(imageIDs, []int{imageID})
+	return qb.UpdateImages(galleryID, imageIDs)
+}
+
 func AddPerformer(qb models.GalleryReaderWriter, id int, performerID int) (bool, error) {
 	performerIDs, err := qb.GetPerformerIDs(id)
 	if err != nil {
@@ -0,0 +1,64 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// archive formats of galleries
+const (
+	archiveZip        = "zip"
+	archiveRar        = "rar"
+	archiveSevenZip   = "7z"
+	archiveHeaderSize = 8
+)
+
+var archiveSignatures = []struct {
+	format    string
+	signature []byte
+}{
+	{archiveZip, []byte("PK\x03\x04")},
+	// empty zip file
+	{archiveZip, []byte("PK\x05\x06")},
+	{archiveRar, []byte("Rar!\x1a\x07")},
+	{archiveSevenZip, []byte("7z\xbc\xaf\x27\x1c")},
+}
+
+// detectArchiveFormat returns the format of the archive at path from its
+// contents, since comic book archives are often named for another format.
+func detectArchiveFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, archiveHeaderSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	for _, s := range archiveSignatures {
+		if bytes.HasPrefix(header[:n], s.signature) {
+			return s.format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s is not a zip, rar or 7z archive", path)
+}
+
+// IsZipArchive returns true if the archive at path is a zip file, rather
+// than another archive format.
+func IsZipArchive(path string) bool {
+	format, err := detectArchiveFormat(path)
+	return err == nil && format == archiveZip
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
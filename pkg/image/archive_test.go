@@ -0,0 +1,265 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testToolListing = `
+7-Zip [64] 16.02 : Copyright (c) 1999-2016 Igor Pavlov : 2016-05-21
+
+Scanning the drive for archives:
+1 file, 1234 bytes (2 KiB)
+
+Listing archive: gallery.cbr
+
+--
+Path = gallery.cbr
+Type = Rar
+Physical Size = 1234
+Solid = -
+
+----------
+Path = pages
+Folder = +
+Size = 0
+Modified = 2021-02-03 04:05:06
+Attributes = D...
+Encrypted = -
+
+Path = pages/001.jpg
+Folder = -
+Size = 11
+Packed Size = 11
+Modified = 2021-02-03 04:05:06.1234567
+Attributes = A
+Encrypted = %s
+
+Path = pages/inner.zip
+Folder = -
+Size = %d
+Modified = 2021-02-03 04:05:06
+Attributes = A
+Encrypted = %s
+
+Path = __MACOSX/pages/._001.jpg
+Folder = -
+Size = 8
+Modified = 2021-02-03 04:05:06
+Attributes = A
+Encrypted = -
+`
+
+// fakeArchiveTool replaces 7-Zip in tests, extracting files from memory.
+type fakeArchiveTool struct {
+	files    map[string][]byte
+	password string
+	listings int
+}
+
+func newFakeArchiveTool(password string) *fakeArchiveTool {
+	return &fakeArchiveTool{
+		files: map[string][]byte{
+			"pages/001.jpg": []byte("outer image"),
+			"pages/inner.zip": writeZip(map[string][]byte{
+				"b.jpg": []byte("inner image"),
+			}, 0),
+			"__MACOSX/pages/._001.jpg": []byte("metadata"),
+		},
+		password: password,
+	}
+}
+
+func (t *fakeArchiveTool) listing() string {
+	encrypted := "-"
+	if t.password != "" {
+		encrypted = "+"
+	}
+
+	return fmt.Sprintf(testToolListing, encrypted, len(t.files["pages/inner.zip"]), encrypted)
+}
+
+func (t *fakeArchiveTool) run(password string, args ...string) ([]byte, error) {
+	// command, switches, --, archive, file names
+	i := 1
+	for ; args[i] != "--"; i++ {
+		if strings.HasPrefix(args[i], "-p") {
+			return nil, errors.New("password passed as an argument")
+		}
+	}
+	names := args[i+2:]
+
+	if args[0] == "l" {
+		t.listings++
+		return []byte(t.listing()), nil
+	}
+
+	if t.password != "" && password != t.password {
+		return nil, ErrZipPassword
+	}
+
+	data, ok := t.files[names[0]]
+	if !ok {
+		return nil, errors.New("no files to process")
+	}
+
+	if args[0] == "t" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+func withFakeArchiveTool(t *fakeArchiveTool) func() {
+	old := runArchiveTool
+	runArchiveTool = t.run
+	return func() {
+		runArchiveTool = old
+	}
+}
+
+func writeArchive(t *testing.T, name string, data []byte) (string, func()) {
+	dir, err := ioutil.TempDir("", "stash-archive-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{"gallery.zip", writeZip(map[string][]byte{"a.jpg": nil}, 0), archiveZip, false},
+		{"empty.cbz", writeZip(nil, 0), archiveZip, false},
+		// comic archives named for another format
+		{"rar4.cbz", []byte("Rar!\x1a\x07\x00rest"), archiveRar, false},
+		{"rar5.cbr", []byte("Rar!\x1a\x07\x01\x00rest"), archiveRar, false},
+		{"gallery.cb7", []byte("7z\xbc\xaf\x27\x1c\x00\x04"), archiveSevenZip, false},
+		{"short.zip", []byte("PK"), "", true},
+		{"text.zip", []byte("not an archive"), "", true},
+	}
+
+	for _, tt := range tests {
+		path, cleanup := writeArchive(t, tt.name, tt.data)
+		got, err := detectArchiveFormat(path)
+		cleanup()
+
+		assert.Equal(t, tt.want, got, tt.name)
+		assert.Equal(t, tt.wantErr, err != nil, tt.name)
+	}
+}
+
+func TestParseToolListing(t *testing.T) {
+	files := parseToolListing([]byte(strings.Replace(newFakeArchiveTool("x").listing(), "\n", "\r\n", -1)))
+
+	if !assert.Len(t, files, 4) {
+		return
+	}
+
+	modified := time.Date(2021, 2, 3, 4, 5, 6, 0, time.Local)
+	assert.Equal(t, &toolArchiveFile{name: "pages", modified: modified, dir: true}, files[0])
+	assert.Equal(t, &toolArchiveFile{name: "pages/001.jpg", size: 11, modified: modified, encrypted: true}, files[1])
+	assert.Equal(t, "pages/inner.zip", files[2].name)
+	assert.Equal(t, "__MACOSX/pages/._001.jpg", files[3].name)
+}
+
+func TestToolArchive(t *testing.T) {
+	path, cleanup := writeArchive(t, "gallery.cbr", []byte("Rar!\x1a\x07\x00"))
+	defer cleanup()
+
+	tool := newFakeArchiveTool("")
+	defer withFakeArchiveTool(tool)()
+
+	innerName := ZipFilename("pages/inner.zip", "b.jpg")
+	names, err := walkNames(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pages/001.jpg", innerName}, names)
+
+	got, err := readZipPath(ZipFilename(path, "pages/001.jpg"))
+	assert.Nil(t, err)
+	assert.Equal(t, "outer image", got)
+
+	got, err = readZipPath(ZipFilename(path, innerName))
+	assert.Nil(t, err)
+	assert.Equal(t, "inner image", got)
+
+	info, err := stat(ZipFilename(path, "pages/001.jpg"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "001.jpg", info.Name())
+		assert.Equal(t, int64(11), info.Size())
+		assert.Equal(t, time.Date(2021, 2, 3, 4, 5, 6, 0, time.Local), info.ModTime())
+	}
+
+	_, err = readZipPath(ZipFilename(path, "pages/missing.jpg"))
+	assert.NotNil(t, err)
+
+	// the archive is only listed again once it changes
+	assert.Equal(t, 1, tool.listings)
+	modified := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	_, err = readZipPath(ZipFilename(path, "pages/001.jpg"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, tool.listings)
+
+	assert.False(t, IsZipArchive(path))
+}
+
+func TestEncryptedToolArchive(t *testing.T) {
+	path, cleanup := writeArchive(t, "gallery.7z", []byte("7z\xbc\xaf\x27\x1c\x00\x04"))
+	defer cleanup()
+
+	defer withFakeArchiveTool(newFakeArchiveTool(testZipPassword))()
+
+	_, err := walkNames(path)
+	assert.True(t, errors.Is(err, ErrZipPassword))
+
+	_, err = readZipPath(ZipFilename(path, "pages/001.jpg"))
+	assert.True(t, errors.Is(err, ErrZipPassword))
+
+	assert.True(t, errors.Is(CheckZipPassword(path, "wrong"), ErrZipPassword))
+	assert.Nil(t, CheckZipPassword(path, testZipPassword))
+
+	defer withZipPasswords([]string{"wrong", testZipPassword})()
+	names, err := walkNames(path)
+	assert.Nil(t, err)
+	assert.Len(t, names, 2)
+
+	got, err := readZipPath(ZipFilename(path, "pages/001.jpg"))
+	assert.Nil(t, err)
+	assert.Equal(t, "outer image", got)
+}
+
+func TestNoArchiveTool(t *testing.T) {
+	path, cleanup := writeArchive(t, "gallery.cbr", []byte("Rar!\x1a\x07\x00"))
+	defer cleanup()
+
+	old := ArchiveToolPath
+	ArchiveToolPath = ""
+	defer func() {
+		ArchiveToolPath = old
+	}()
+
+	_, err := walkNames(path)
+	assert.True(t, errors.Is(err, ErrNoArchiveTool))
+}
@@ -0,0 +1,428 @@
+package image
+
+import (
+	"archive/zip"
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// ArchiveToolPath is the path of the 7-Zip executable, which reads rar and 7z
+// archives. Set by the manager.
+var ArchiveToolPath string
+
+// ErrNoArchiveTool is returned when reading a rar or 7z archive if 7-Zip was
+// not found.
+var ErrNoArchiveTool = errors.New("7-Zip is required to read rar and 7z archives")
+
+// the names of the 7-Zip executable, in order of preference. 7za does not read
+// rar archives.
+var archiveToolNames = []string{"7zz", "7z", "7za"}
+
+const (
+	archiveToolTimeLayout = "2006-01-02 15:04:05"
+
+	// the time allowed for 7-Zip to list, test or extract from an archive
+	archiveToolTimeout = 2 * time.Minute
+
+	// the number of archive listings kept in memory
+	toolArchiveCacheSize = 100
+)
+
+// FindArchiveTool returns the path of the 7-Zip executable in the PATH, or in
+// one of paths. Returns an empty string if it is not found.
+func FindArchiveTool(paths []string) string {
+	for _, name := range archiveToolNames {
+		if p, err := exec.LookPath(name); err == nil {
+			return p
+		}
+	}
+
+	for _, dir := range paths {
+		for _, name := range archiveToolNames {
+			if runtime.GOOS == "windows" {
+				name += ".exe"
+			}
+
+			p := filepath.Join(dir, name)
+			if exists, _ := utils.FileExists(p); exists {
+				return p
+			}
+		}
+	}
+
+	return ""
+}
+
+// runArchiveTool runs 7-Zip with args, returning its output. The password
+// is entered on the standard input of 7-Zip if it asks for one, rather than
+// passed as an argument, so that it is not visible in the process list. An
+// empty password is entered for archives without one, so that 7-Zip does
+// not wait for input. Replaced in tests.
+var runArchiveTool = func(password string, args ...string) ([]byte, error) {
+	if ArchiveToolPath == "" {
+		return nil, ErrNoArchiveTool
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), archiveToolTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ArchiveToolPath, args...)
+	cmd.Stdin = strings.NewReader(password + "\n")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	setArchiveToolProcAttr(cmd)
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("7-Zip did not finish within %s", archiveToolTimeout)
+		}
+
+		// older versions write errors to stdout
+		if isWrongPassword(stderr.Bytes()) || isWrongPassword(stdout.Bytes()) {
+			return nil, ErrZipPassword
+		}
+
+		return nil, fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func isWrongPassword(output []byte) bool {
+	return bytes.Contains(bytes.ToLower(output), []byte("wrong password"))
+}
+
+// toolArchiveFile is a file in an archive read by 7-Zip.
+type toolArchiveFile struct {
+	name      string
+	size      int64
+	modified  time.Time
+	dir       bool
+	encrypted bool
+}
+
+// parseToolListing parses the technical listing of an archive, as output by
+// 7-Zip with the -slt switch. The properties of the archive come before a
+// line of dashes, followed by those of each file, separated by blank lines.
+func parseToolListing(out []byte) []*toolArchiveFile {
+	var ret []*toolArchiveFile
+	var f *toolArchiveFile
+	files := false
+
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !files {
+			files = line == "----------"
+			continue
+		}
+
+		if line == "" {
+			f = nil
+			continue
+		}
+
+		kv := strings.SplitN(line, " = ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, value := kv[0], kv[1]
+
+		if key == "Path" {
+			f = &toolArchiveFile{name: filepath.ToSlash(value)}
+			ret = append(ret, f)
+			continue
+		}
+
+		if f == nil {
+			continue
+		}
+
+		switch key {
+		case "Folder":
+			f.dir = value == "+"
+		case "Attributes":
+			f.dir = f.dir || strings.HasPrefix(value, "D")
+		case "Size":
+			f.size, _ = strconv.ParseInt(value, 10, 64)
+		case "Modified":
+			// may have fractions of seconds
+			if len(value) >= len(archiveToolTimeLayout) {
+				f.modified, _ = time.ParseInLocation(archiveToolTimeLayout, value[:len(archiveToolTimeLayout)], time.Local)
+			}
+		case "Encrypted":
+			f.encrypted = value == "+"
+		}
+	}
+
+	return ret
+}
+
+func listToolArchive(path string, password string) ([]*toolArchiveFile, error) {
+	out, err := runArchiveTool(password, "l", "-slt", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseToolListing(out), nil
+}
+
+// toolArchiveListing is the result of opening an archive with 7-Zip.
+type toolArchiveListing struct {
+	modTime   time.Time
+	size      int64
+	passwords []string
+
+	password string
+	files    []*toolArchiveFile
+}
+
+// toolArchiveCache holds the listings of the archives most recently opened,
+// so that reading each image of an archive does not list it again. A
+// listing is only used while the archive is unchanged, and for the same
+// passwords.
+type toolArchiveCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// the values are the paths, most recently used first
+	order *list.List
+}
+
+func newToolArchiveCache(size int) *toolArchiveCache {
+	return &toolArchiveCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+var toolArchives = newToolArchiveCache(toolArchiveCacheSize)
+
+type toolArchiveCacheEntry struct {
+	path    string
+	listing *toolArchiveListing
+}
+
+func (c *toolArchiveCache) get(path string, info os.FileInfo, passwords []string) *toolArchiveListing {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, found := c.entries[path]
+	if !found {
+		return nil
+	}
+
+	l := e.Value.(*toolArchiveCacheEntry).listing
+	if !l.modTime.Equal(info.ModTime()) || l.size != info.Size() || !utils.StrSliceEquals(l.passwords, passwords) {
+		return nil
+	}
+
+	c.order.MoveToFront(e)
+	return l
+}
+
+func (c *toolArchiveCache) put(path string, l *toolArchiveListing) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, found := c.entries[path]; found {
+		e.Value.(*toolArchiveCacheEntry).listing = l
+		c.order.MoveToFront(e)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*toolArchiveCacheEntry).path)
+	}
+
+	c.entries[path] = c.order.PushFront(&toolArchiveCacheEntry{
+		path:    path,
+		listing: l,
+	})
+}
+
+// openToolArchive lists the files of the archive at path, returning the
+// first of the passwords which decrypts it, or an error wrapping
+// ErrZipPassword if none do. The listing is cached until the archive
+// changes.
+func openToolArchive(path string, passwords []string) (string, []*toolArchiveFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if l := toolArchives.get(path, info, passwords); l != nil {
+		return l.password, l.files, nil
+	}
+
+	password, files, err := readToolArchive(path, passwords)
+	if err != nil {
+		return "", nil, err
+	}
+
+	toolArchives.put(path, &toolArchiveListing{
+		modTime:   info.ModTime(),
+		size:      info.Size(),
+		passwords: append([]string(nil), passwords...),
+		password:  password,
+		files:     files,
+	})
+
+	return password, files, nil
+}
+
+func readToolArchive(path string, passwords []string) (string, []*toolArchiveFile, error) {
+	if len(passwords) == 0 {
+		passwords = []string{""}
+	}
+
+	for _, password := range passwords {
+		// the file names are encrypted in some archives
+		files, err := listToolArchive(path, password)
+		if errors.Is(err, ErrZipPassword) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		var encrypted *toolArchiveFile
+		for _, f := range files {
+			if f.encrypted && !f.dir {
+				encrypted = f
+				break
+			}
+		}
+
+		if encrypted == nil {
+			return password, files, nil
+		}
+
+		_, err = runArchiveTool(password, "t", "-spd", "--", path, encrypted.name)
+		if errors.Is(err, ErrZipPassword) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		return password, files, nil
+	}
+
+	return "", nil, fmt.Errorf("%s: %w", path, ErrZipPassword)
+}
+
+func newToolArchiveEntry(path string, password string, f *toolArchiveFile) *ZipEntry {
+	return &ZipEntry{
+		Name:     f.name,
+		Size:     f.size,
+		Modified: f.modified,
+		open: func() (io.ReadCloser, error) {
+			out, err := runArchiveTool(password, "e", "-so", "-spd", "--", path, f.name)
+			if err != nil {
+				return nil, err
+			}
+
+			return ioutil.NopCloser(bytes.NewReader(out)), nil
+		},
+	}
+}
+
+// walkToolArchive walks the archive at path as WalkZip, using 7-Zip.
+func walkToolArchive(path string, passwords []string, walkFunc func(e *ZipEntry) error) error {
+	password, files, err := openToolArchive(path, passwords)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.dir || skipZipFile(f.name) {
+			continue
+		}
+
+		e := newToolArchiveEntry(path, password, f)
+		if !isZipName(f.name) {
+			if err := walkFunc(e); err != nil {
+				return err
+			}
+			continue
+		}
+
+		nestedName := ZipFilename(path, f.name)
+		nested, err := readNestedZip(nestedName, e, passwords)
+		if errors.Is(err, zip.ErrFormat) {
+			// not a zip file after all
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", PathDisplayName(nestedName), err)
+		}
+
+		if err := walkZipArchive(nested, f.name, walkFunc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findToolArchivePath finds the file of the names within the archive at
+// path, where each name but the last is a zip file within the previous one.
+func findToolArchivePath(path string, names []string) (*ZipEntry, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no file name in zip path")
+	}
+
+	passwords := ZipPasswords(path)
+	password, files, err := openToolArchive(path, passwords)
+	if err != nil {
+		return nil, err
+	}
+
+	var e *ZipEntry
+	for _, f := range files {
+		if f.name == names[0] && !f.dir {
+			e = newToolArchiveEntry(path, password, f)
+			break
+		}
+	}
+
+	if e == nil {
+		return nil, fmt.Errorf("file with name '%s' not found in archive '%s'", names[0], path)
+	}
+
+	if len(names) == 1 {
+		return e, nil
+	}
+
+	nested, err := readNestedZip(ZipFilename(path, names[0]), e, passwords)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := findZipPath(nested, names[1:])
+	if err != nil {
+		return nil, err
+	}
+	ret.Name = strings.Join(names, zipSeparator)
+
+	return ret, nil
+}
@@ -0,0 +1,15 @@
+// +build !windows
+
+package image
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setArchiveToolProcAttr starts 7-Zip in a new session, without a
+// controlling terminal, so that it reads passwords from its standard input
+// rather than from the terminal.
+func setArchiveToolProcAttr(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
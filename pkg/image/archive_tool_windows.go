@@ -0,0 +1,7 @@
+package image
+
+import "os/exec"
+
+// setArchiveToolProcAttr does nothing on Windows, where 7-Zip reads
+// passwords from its standard input when it is redirected.
+func setArchiveToolProcAttr(cmd *exec.Cmd) {}
@@ -0,0 +1,363 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const (
+	exifTagMake     = 0x010F
+	exifTagModel    = 0x0110
+	exifTagDateTime = 0x0132
+	exifTagExifIFD  = 0x8769
+	exifTagGPSIFD   = 0x8825
+
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagOffsetTimeOriginal = 0x9011
+	exifTagPixelXDimension    = 0xA002
+	exifTagPixelYDimension    = 0xA003
+
+	gpsTagLatitudeRef  = 0x0001
+	gpsTagLatitude     = 0x0002
+	gpsTagLongitudeRef = 0x0003
+	gpsTagLongitude    = 0x0004
+)
+
+// TIFF field types
+const (
+	exifTypeByte      = 1
+	exifTypeASCII     = 2
+	exifTypeShort     = 3
+	exifTypeLong      = 4
+	exifTypeRational  = 5
+	exifTypeUndefined = 7
+	exifTypeSLong     = 9
+	exifTypeSRational = 10
+)
+
+var errNoExif = errors.New("no exif data")
+
+const (
+	exifTimeLayout   = "2006:01:02 15:04:05"
+	exifOffsetLayout = "-07:00"
+)
+
+type exifEntry struct {
+	fieldType uint16
+	count     uint32
+	data      []byte
+}
+
+// exifData contains the raw entries of the primary, exif and GPS IFDs of
+// an image.
+type exifData struct {
+	order   binary.ByteOrder
+	primary map[uint16]exifEntry
+	exif    map[uint16]exifEntry
+	gps     map[uint16]exifEntry
+}
+
+// readExif reads the exif data from a JPEG image. Returns errNoExif if the
+// image does not contain exif data.
+func readExif(r io.Reader) (*exifData, error) {
+	br := bufio.NewReader(r)
+
+	var marker [2]byte
+	if _, err := io.ReadFull(br, marker[:]); err != nil {
+		return nil, err
+	}
+	if marker[0] != 0xFF || marker[1] != 0xD8 {
+		// not a JPEG
+		return nil, errNoExif
+	}
+
+	for {
+		if _, err := io.ReadFull(br, marker[:]); err != nil {
+			return nil, err
+		}
+		if marker[0] != 0xFF {
+			return nil, errNoExif
+		}
+
+		// start of scan or end of image - no more metadata
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, errNoExif
+		}
+
+		var length uint16
+		if err := binary.Read(br, binary.BigEndian, &length); err != nil {
+			return nil, err
+		}
+		if length < 2 {
+			return nil, errNoExif
+		}
+
+		segment := io.LimitReader(br, int64(length-2))
+
+		if marker[1] == 0xE1 {
+			data, err := ioutil.ReadAll(segment)
+			if err != nil {
+				return nil, err
+			}
+
+			if bytes.HasPrefix(data, []byte("Exif\x00\x00")) {
+				return parseTIFF(data[6:])
+			}
+
+			continue
+		}
+
+		if _, err := io.Copy(ioutil.Discard, segment); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func parseTIFF(data []byte) (*exifData, error) {
+	if len(data) < 8 {
+		return nil, errNoExif
+	}
+
+	ret := &exifData{}
+	switch string(data[0:2]) {
+	case "II":
+		ret.order = binary.LittleEndian
+	case "MM":
+		ret.order = binary.BigEndian
+	default:
+		return nil, errNoExif
+	}
+
+	offset := ret.order.Uint32(data[4:8])
+
+	var err error
+	ret.primary, err = ret.parseIFD(data, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if off, ok := ret.pointer(ret.primary, exifTagExifIFD); ok {
+		// ignore errors in sub-IFDs
+		ret.exif, _ = ret.parseIFD(data, off)
+	}
+
+	if off, ok := ret.pointer(ret.primary, exifTagGPSIFD); ok {
+		ret.gps, _ = ret.parseIFD(data, off)
+	}
+
+	return ret, nil
+}
+
+func exifTypeSize(fieldType uint16) int {
+	switch fieldType {
+	case exifTypeByte, exifTypeASCII, exifTypeUndefined:
+		return 1
+	case exifTypeShort:
+		return 2
+	case exifTypeLong, exifTypeSLong:
+		return 4
+	case exifTypeRational, exifTypeSRational:
+		return 8
+	}
+
+	return 0
+}
+
+func (e *exifData) parseIFD(data []byte, offset uint32) (map[uint16]exifEntry, error) {
+	if int(offset)+2 > len(data) {
+		return nil, errors.New("invalid IFD offset")
+	}
+
+	n := int(e.order.Uint16(data[offset:]))
+	pos := int(offset) + 2
+
+	ret := make(map[uint16]exifEntry)
+	for i := 0; i < n; i++ {
+		if pos+12 > len(data) {
+			return ret, errors.New("truncated IFD")
+		}
+
+		entry := data[pos : pos+12]
+		pos += 12
+
+		tag := e.order.Uint16(entry[0:2])
+		fieldType := e.order.Uint16(entry[2:4])
+		count := e.order.Uint32(entry[4:8])
+
+		size := exifTypeSize(fieldType) * int(count)
+		if size == 0 {
+			continue
+		}
+
+		var value []byte
+		if size <= 4 {
+			value = entry[8 : 8+size]
+		} else {
+			valueOffset := int(e.order.Uint32(entry[8:12]))
+			if valueOffset < 0 || valueOffset+size > len(data) {
+				continue
+			}
+			value = data[valueOffset : valueOffset+size]
+		}
+
+		ret[tag] = exifEntry{
+			fieldType: fieldType,
+			count:     count,
+			data:      value,
+		}
+	}
+
+	return ret, nil
+}
+
+func (e *exifData) pointer(ifd map[uint16]exifEntry, tag uint16) (uint32, bool) {
+	v, ok := ifd[tag]
+	if !ok || v.fieldType != exifTypeLong || len(v.data) < 4 {
+		return 0, false
+	}
+
+	return e.order.Uint32(v.data), true
+}
+
+func (e *exifData) getString(ifd map[uint16]exifEntry, tag uint16) (string, bool) {
+	v, ok := ifd[tag]
+	if !ok || v.fieldType != exifTypeASCII {
+		return "", false
+	}
+
+	return string(bytes.TrimRight(v.data, "\x00 ")), true
+}
+
+func (e *exifData) getInt(ifd map[uint16]exifEntry, tag uint16) (int, bool) {
+	v, ok := ifd[tag]
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case v.fieldType == exifTypeShort && len(v.data) >= 2:
+		return int(e.order.Uint16(v.data)), true
+	case v.fieldType == exifTypeLong && len(v.data) >= 4:
+		return int(e.order.Uint32(v.data)), true
+	}
+
+	return 0, false
+}
+
+func (e *exifData) getRationals(ifd map[uint16]exifEntry, tag uint16) ([]float64, bool) {
+	v, ok := ifd[tag]
+	if !ok || (v.fieldType != exifTypeRational && v.fieldType != exifTypeSRational) {
+		return nil, false
+	}
+
+	var ret []float64
+	for i := 0; i+8 <= len(v.data); i += 8 {
+		num := e.order.Uint32(v.data[i:])
+		den := e.order.Uint32(v.data[i+4:])
+		if den == 0 {
+			return nil, false
+		}
+
+		if v.fieldType == exifTypeSRational {
+			ret = append(ret, float64(int32(num))/float64(int32(den)))
+		} else {
+			ret = append(ret, float64(num)/float64(den))
+		}
+	}
+
+	return ret, true
+}
+
+func (e *exifData) getGPSCoordinate(valueTag, refTag uint16, negativeRef string) (float64, bool) {
+	dms, ok := e.getRationals(e.gps, valueTag)
+	if !ok || len(dms) != 3 {
+		return 0, false
+	}
+
+	ret := dms[0] + dms[1]/60 + dms[2]/3600
+
+	if ref, _ := e.getString(e.gps, refTag); ref == negativeRef {
+		ret = -ret
+	}
+
+	return ret, true
+}
+
+// GPSCoordinates returns the latitude and longitude in decimal degrees
+// from the GPS IFD. Returns false if either is not present or invalid.
+func (e *exifData) GPSCoordinates() (latitude float64, longitude float64, ok bool) {
+	if e.gps == nil {
+		return 0, 0, false
+	}
+
+	latitude, latOK := e.getGPSCoordinate(gpsTagLatitude, gpsTagLatitudeRef, "S")
+	longitude, lonOK := e.getGPSCoordinate(gpsTagLongitude, gpsTagLongitudeRef, "W")
+
+	if !latOK || !lonOK || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return 0, 0, false
+	}
+
+	return latitude, longitude, true
+}
+
+// DateTaken returns the time the image was captured, from the original date
+// and time of the exif IFD, or the modification date and time of the primary
+// IFD if it is not present. The time is in the offset recorded with the
+// original time, or the local time zone if there is none. Returns false if
+// neither is present or valid.
+func (e *exifData) DateTaken() (time.Time, bool) {
+	loc := time.Local
+	if offset, ok := e.getString(e.exif, exifTagOffsetTimeOriginal); ok {
+		if t, err := time.Parse(exifOffsetLayout, offset); err == nil {
+			loc = t.Location()
+		}
+	}
+
+	for _, v := range []struct {
+		ifd map[uint16]exifEntry
+		tag uint16
+	}{
+		{e.exif, exifTagDateTimeOriginal},
+		{e.primary, exifTagDateTime},
+	} {
+		s, ok := e.getString(v.ifd, v.tag)
+		if !ok {
+			continue
+		}
+
+		// unknown values are filled with spaces or zeros
+		if t, err := time.ParseInLocation(exifTimeLayout, s, loc); err == nil && t.Year() > 1 {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Camera returns the make and model of the camera that captured the image.
+// Either may be empty.
+func (e *exifData) Camera() (cameraMake string, cameraModel string) {
+	cameraMake, _ = e.getString(e.primary, exifTagMake)
+	cameraModel, _ = e.getString(e.primary, exifTagModel)
+
+	return strings.TrimSpace(cameraMake), strings.TrimSpace(cameraModel)
+}
+
+// Dimensions returns the width and height of the image from the exif IFD.
+// Returns false if either is not present.
+func (e *exifData) Dimensions() (width int, height int, ok bool) {
+	width, wOK := e.getInt(e.exif, exifTagPixelXDimension)
+	height, hOK := e.getInt(e.exif, exifTagPixelYDimension)
+
+	if !wOK || !hOK || width == 0 || height == 0 {
+		return 0, 0, false
+	}
+
+	return width, height, true
+}
@@ -0,0 +1,247 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testIFDEntry is an entry of an IFD written by writeTestExif. Pointers to
+// the exif and GPS IFDs are added automatically.
+type testIFDEntry struct {
+	tag       uint16
+	fieldType uint16
+	count     uint32
+	data      []byte
+}
+
+func asciiEntry(tag uint16, s string) testIFDEntry {
+	data := append([]byte(s), 0)
+	return testIFDEntry{tag, exifTypeASCII, uint32(len(data)), data}
+}
+
+func shortEntry(tag uint16, v uint16) testIFDEntry {
+	data := make([]byte, 2)
+	binary.LittleEndian.PutUint16(data, v)
+	return testIFDEntry{tag, exifTypeShort, 1, data}
+}
+
+func longEntry(tag uint16, v uint32) testIFDEntry {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, v)
+	return testIFDEntry{tag, exifTypeLong, 1, data}
+}
+
+// writeTestExif returns a little-endian TIFF structure with the primary,
+// exif and GPS IFDs. The exif and GPS IFDs are omitted if empty.
+func writeTestExif(primary, exif, gps []testIFDEntry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("II")
+	binary.Write(&buf, binary.LittleEndian, uint16(42))
+	binary.Write(&buf, binary.LittleEndian, uint32(8))
+
+	ifdSize := func(entries []testIFDEntry) int {
+		ret := 2 + 12*len(entries) + 4
+		for _, e := range entries {
+			if len(e.data) > 4 {
+				ret += len(e.data)
+			}
+		}
+		return ret
+	}
+
+	// the primary IFD is written first, followed by the sub-IFDs
+	primary = append([]testIFDEntry(nil), primary...)
+	nSubIFDs := 0
+	if len(exif) > 0 {
+		nSubIFDs++
+	}
+	if len(gps) > 0 {
+		nSubIFDs++
+	}
+	offset := uint32(8 + ifdSize(primary) + 12*nSubIFDs)
+	if len(exif) > 0 {
+		primary = append(primary, longEntry(exifTagExifIFD, offset))
+		offset += uint32(ifdSize(exif))
+	}
+	if len(gps) > 0 {
+		primary = append(primary, longEntry(exifTagGPSIFD, offset))
+	}
+
+	writeIFD := func(entries []testIFDEntry) {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].tag < entries[j].tag
+		})
+
+		// values larger than 4 bytes follow the entries
+		valueOffset := uint32(buf.Len() + 2 + 12*len(entries) + 4)
+		var values []byte
+
+		binary.Write(&buf, binary.LittleEndian, uint16(len(entries)))
+		for _, e := range entries {
+			binary.Write(&buf, binary.LittleEndian, e.tag)
+			binary.Write(&buf, binary.LittleEndian, e.fieldType)
+			binary.Write(&buf, binary.LittleEndian, e.count)
+			if len(e.data) > 4 {
+				binary.Write(&buf, binary.LittleEndian, valueOffset+uint32(len(values)))
+				values = append(values, e.data...)
+			} else {
+				value := make([]byte, 4)
+				copy(value, e.data)
+				buf.Write(value)
+			}
+		}
+		// no next IFD
+		binary.Write(&buf, binary.LittleEndian, uint32(0))
+		buf.Write(values)
+	}
+
+	writeIFD(primary)
+	if len(exif) > 0 {
+		writeIFD(exif)
+	}
+	if len(gps) > 0 {
+		writeIFD(gps)
+	}
+
+	return buf.Bytes()
+}
+
+// writeTestJPEG returns the start of a JPEG file containing the exif data.
+func writeTestJPEG(tiff []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8})
+
+	// an unrelated segment before the exif data
+	buf.Write([]byte{0xFF, 0xE0, 0x00, 0x04, 'J', 'F'})
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	buf.Write([]byte{0xFF, 0xE1})
+	binary.Write(&buf, binary.BigEndian, uint16(len(segment)+2))
+	buf.Write(segment)
+
+	// start of scan
+	buf.Write([]byte{0xFF, 0xDA})
+	return buf.Bytes()
+}
+
+func TestReadExif(t *testing.T) {
+	tiff := writeTestExif([]testIFDEntry{
+		asciiEntry(exifTagMake, "Canon"),
+		asciiEntry(exifTagModel, "Canon EOS 5D "),
+		asciiEntry(exifTagDateTime, "2021:05:06 07:08:09"),
+	}, []testIFDEntry{
+		asciiEntry(exifTagDateTimeOriginal, "2021:02:03 04:05:06"),
+		asciiEntry(exifTagOffsetTimeOriginal, "+10:00"),
+		shortEntry(exifTagPixelXDimension, 640),
+		longEntry(exifTagPixelYDimension, 480),
+	}, nil)
+
+	exif, err := readExif(bytes.NewReader(writeTestJPEG(tiff)))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	taken, ok := exif.DateTaken()
+	assert.True(t, ok)
+	assert.True(t, time.Date(2021, 2, 3, 4, 5, 6, 0, time.FixedZone("", 10*60*60)).Equal(taken), taken)
+
+	cameraMake, cameraModel := exif.Camera()
+	assert.Equal(t, "Canon", cameraMake)
+	assert.Equal(t, "Canon EOS 5D", cameraModel)
+
+	width, height, ok := exif.Dimensions()
+	assert.True(t, ok)
+	assert.Equal(t, 640, width)
+	assert.Equal(t, 480, height)
+
+	_, _, ok = exif.GPSCoordinates()
+	assert.False(t, ok)
+}
+
+func TestExifDateTaken(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary []testIFDEntry
+		exif    []testIFDEntry
+		want    time.Time
+		wantOK  bool
+	}{
+		{
+			"modification time",
+			[]testIFDEntry{asciiEntry(exifTagDateTime, "2021:05:06 07:08:09")},
+			nil,
+			time.Date(2021, 5, 6, 7, 8, 9, 0, time.Local),
+			true,
+		},
+		{
+			"unknown original time",
+			[]testIFDEntry{asciiEntry(exifTagDateTime, "2021:05:06 07:08:09")},
+			[]testIFDEntry{asciiEntry(exifTagDateTimeOriginal, "    :  :     :  :  ")},
+			time.Date(2021, 5, 6, 7, 8, 9, 0, time.Local),
+			true,
+		},
+		{
+			"zero time",
+			nil,
+			[]testIFDEntry{asciiEntry(exifTagDateTimeOriginal, "0000:00:00 00:00:00")},
+			time.Time{},
+			false,
+		},
+		{
+			"invalid offset",
+			nil,
+			[]testIFDEntry{
+				asciiEntry(exifTagDateTimeOriginal, "2021:02:03 04:05:06"),
+				asciiEntry(exifTagOffsetTimeOriginal, "   :  "),
+			},
+			time.Date(2021, 2, 3, 4, 5, 6, 0, time.Local),
+			true,
+		},
+		{
+			"none",
+			[]testIFDEntry{asciiEntry(exifTagMake, "Canon")},
+			nil,
+			time.Time{},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		exif, err := parseTIFF(writeTestExif(tt.primary, tt.exif, nil))
+		if !assert.Nil(t, err, tt.name) {
+			continue
+		}
+
+		got, ok := exif.DateTaken()
+		assert.Equal(t, tt.wantOK, ok, tt.name)
+		assert.True(t, tt.want.Equal(got), "%s: %v", tt.name, got)
+	}
+}
+
+func TestExifNoDimensions(t *testing.T) {
+	exif, err := parseTIFF(writeTestExif([]testIFDEntry{
+		asciiEntry(exifTagMake, "Canon"),
+	}, []testIFDEntry{
+		shortEntry(exifTagPixelXDimension, 640),
+	}, nil))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, _, ok := exif.Dimensions()
+	assert.False(t, ok)
+
+	cameraMake, cameraModel := exif.Camera()
+	assert.Equal(t, "Canon", cameraMake)
+	assert.Equal(t, "", cameraModel)
+}
+
+func TestReadExifNotJPEG(t *testing.T) {
+	_, err := readExif(bytes.NewReader([]byte("\x89PNG\r\n\x1a\n")))
+	assert.Equal(t, errNoExif, err)
+}
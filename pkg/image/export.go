@@ -26,6 +26,15 @@ func ToBasicJSON(image *models.Image) *jsonschema.Image {
 	newImageJSON.Organized = image.Organized
 	newImageJSON.OCounter = image.OCounter
 
+	if image.Location.Valid {
+		newImageJSON.Location = image.Location.String
+	}
+
+	if image.Latitude.Valid && image.Longitude.Valid {
+		newImageJSON.Latitude = &image.Latitude.Float64
+		newImageJSON.Longitude = &image.Longitude.Float64
+	}
+
 	newImageJSON.File = getImageFileJSON(image)
 
 	return &newImageJSON
@@ -50,6 +59,18 @@ func getImageFileJSON(image *models.Image) *jsonschema.ImageFile {
 		ret.Height = int(image.Height.Int64)
 	}
 
+	if image.TakenAt.Valid {
+		ret.TakenAt = models.JSONTime{Time: image.TakenAt.Timestamp}
+	}
+
+	if image.CameraMake.Valid {
+		ret.CameraMake = image.CameraMake.String
+	}
+
+	if image.CameraModel.Valid {
+		ret.CameraModel = image.CameraModel.String
+	}
+
 	return ret
 }
 
@@ -46,6 +46,9 @@ const (
 	size      = 123
 	width     = 100
 	height    = 100
+
+	cameraMake  = "cameraMake"
+	cameraModel = "cameraModel"
 )
 
 const (
@@ -60,6 +63,7 @@ var names = []string{
 
 var createTime time.Time = time.Date(2001, 01, 01, 0, 0, 0, 0, time.UTC)
 var updateTime time.Time = time.Date(2002, 01, 01, 0, 0, 0, 0, time.UTC)
+var takenTime time.Time = time.Date(2000, 01, 01, 0, 0, 0, 0, time.UTC)
 
 func createFullImage(id int) models.Image {
 	return models.Image{
@@ -72,6 +76,12 @@ func createFullImage(id int) models.Image {
 		Size:      models.NullInt64(int64(size)),
 		Organized: organized,
 		Width:     models.NullInt64(width),
+		TakenAt: models.NullSQLiteTimestamp{
+			Timestamp: takenTime,
+			Valid:     true,
+		},
+		CameraMake:  models.NullString(cameraMake),
+		CameraModel: models.NullString(cameraModel),
 		CreatedAt: models.SQLiteTimestamp{
 			Timestamp: createTime,
 		},
@@ -104,6 +114,11 @@ func createFullJSONImage() *jsonschema.Image {
 			Height: height,
 			Size:   size,
 			Width:  width,
+			TakenAt: models.JSONTime{
+				Time: takenTime,
+			},
+			CameraMake:  cameraMake,
+			CameraModel: cameraModel,
 		},
 		CreatedAt: models.JSONTime{
 			Time: createTime,
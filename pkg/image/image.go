@@ -1,7 +1,6 @@
 package image
 
 import (
-	"archive/zip"
 	"database/sql"
 	"fmt"
 	"image"
@@ -59,6 +58,13 @@ func ZipFilename(zipFilename, filenameInZip string) string {
 	return zipFilename + zipSeparator + filenameInZip
 }
 
+// SplitZipFilename returns the zip file path and the filename within the zip
+// of an image path. If the path is not within a zip file, zipFilename is
+// empty.
+func SplitZipFilename(path string) (zipFilename, filenameInZip string) {
+	return getFilePath(path)
+}
+
 // IsZipPath returns true if the path includes the zip separator byte,
 // indicating it is within a zip file.
 // TODO - this should be moved to utils
@@ -68,7 +74,7 @@ func IsZipPath(p string) bool {
 
 type imageReadCloser struct {
 	src io.ReadCloser
-	zrc *zip.ReadCloser
+	zrc io.Closer
 }
 
 func (i *imageReadCloser) Read(p []byte) (n int, err error) {
@@ -89,37 +95,12 @@ func (i *imageReadCloser) Close() error {
 }
 
 func openSourceImage(path string) (io.ReadCloser, error) {
-	// may need to read from a zip file
-	zipFilename, filename := getFilePath(path)
-	if zipFilename != "" {
-		r, err := zip.OpenReader(zipFilename)
-		if err != nil {
-			return nil, err
-		}
-
-		// defer closing of zip to the calling function, unless an error
-		// is returned, in which case it should be closed immediately
-
-		// find the file matching the filename
-		for _, f := range r.File {
-			if f.Name == filename {
-				src, err := f.Open()
-				if err != nil {
-					r.Close()
-					return nil, err
-				}
-				return &imageReadCloser{
-					src: src,
-					zrc: r,
-				}, nil
-			}
-		}
-
-		r.Close()
-		return nil, fmt.Errorf("file with name '%s' not found in zip file '%s'", filename, zipFilename)
+	// may need to read from a zip file, which may be within other zip files
+	if IsZipPath(path) {
+		return openZipPathFile(path)
 	}
 
-	return os.Open(filename)
+	return os.Open(path)
 }
 
 func getFilePath(path string) (zipFilename, filename string) {
@@ -172,9 +153,51 @@ func SetFileDetails(i *models.Image) error {
 		Valid: true,
 	}
 
+	setExifDetails(i)
+
 	return nil
 }
 
+// setExifDetails sets the GPS coordinates, capture time and camera of the
+// image from its exif data, if present. The dimensions are also set if they
+// could not be decoded from the image.
+func setExifDetails(i *models.Image) {
+	f, err := openSourceImage(i.Path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	exif, err := readExif(f)
+	if err != nil {
+		return
+	}
+
+	if lat, lon, ok := exif.GPSCoordinates(); ok {
+		i.Latitude = sql.NullFloat64{Float64: lat, Valid: true}
+		i.Longitude = sql.NullFloat64{Float64: lon, Valid: true}
+	}
+
+	if t, ok := exif.DateTaken(); ok {
+		i.TakenAt = models.NullSQLiteTimestamp{Timestamp: t, Valid: true}
+	}
+
+	cameraMake, cameraModel := exif.Camera()
+	if cameraMake != "" {
+		i.CameraMake = sql.NullString{String: cameraMake, Valid: true}
+	}
+	if cameraModel != "" {
+		i.CameraModel = sql.NullString{String: cameraModel, Valid: true}
+	}
+
+	if !i.Width.Valid || !i.Height.Valid {
+		if width, height, ok := exif.Dimensions(); ok {
+			i.Width = models.NullInt64(int64(width))
+			i.Height = models.NullInt64(int64(height))
+		}
+	}
+}
+
 // GetFileModTime gets the file modification time, handling files in zip files.
 func GetFileModTime(path string) (time.Time, error) {
 	fi, err := stat(path)
@@ -190,26 +213,18 @@ func GetFileModTime(path string) (time.Time, error) {
 }
 
 func stat(path string) (os.FileInfo, error) {
-	// may need to read from a zip file
-	zipFilename, filename := getFilePath(path)
-	if zipFilename != "" {
-		r, err := zip.OpenReader(zipFilename)
+	// may need to read from a zip file, which may be within other zip files
+	if IsZipPath(path) {
+		e, closer, err := openZipPath(path)
 		if err != nil {
 			return nil, err
 		}
-		defer r.Close()
-
-		// find the file matching the filename
-		for _, f := range r.File {
-			if f.Name == filename {
-				return f.FileInfo(), nil
-			}
-		}
+		defer closer.Close()
 
-		return nil, fmt.Errorf("file with name '%s' not found in zip file '%s'", filename, zipFilename)
+		return e.fileInfo(), nil
 	}
 
-	return os.Stat(filename)
+	return os.Stat(path)
 }
 
 // PathDisplayName converts an image path for display. It translates the zip
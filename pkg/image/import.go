@@ -25,6 +25,11 @@ type Importer struct {
 	galleries  []*models.Gallery
 	performers []*models.Performer
 	tags       []*models.Tag
+
+	// relationships of the existing image when merging
+	existingGalleryIDs   []int
+	existingPerformerIDs []int
+	existingTagIDs       []int
 }
 
 func (i *Importer) PreImport() error {
@@ -61,6 +66,13 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 	if imageJSON.Rating != 0 {
 		newImage.Rating = sql.NullInt64{Int64: int64(imageJSON.Rating), Valid: true}
 	}
+	if imageJSON.Location != "" {
+		newImage.Location = sql.NullString{String: imageJSON.Location, Valid: true}
+	}
+	if imageJSON.Latitude != nil && imageJSON.Longitude != nil {
+		newImage.Latitude = sql.NullFloat64{Float64: *imageJSON.Latitude, Valid: true}
+		newImage.Longitude = sql.NullFloat64{Float64: *imageJSON.Longitude, Valid: true}
+	}
 
 	newImage.Organized = imageJSON.Organized
 	newImage.OCounter = imageJSON.OCounter
@@ -77,6 +89,15 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 		if imageJSON.File.Height != 0 {
 			newImage.Height = sql.NullInt64{Int64: int64(imageJSON.File.Height), Valid: true}
 		}
+		if !imageJSON.File.TakenAt.IsZero() {
+			newImage.TakenAt = models.NullSQLiteTimestamp{Timestamp: imageJSON.File.TakenAt.GetTime(), Valid: true}
+		}
+		if imageJSON.File.CameraMake != "" {
+			newImage.CameraMake = sql.NullString{String: imageJSON.File.CameraMake, Valid: true}
+		}
+		if imageJSON.File.CameraModel != "" {
+			newImage.CameraModel = sql.NullString{String: imageJSON.File.CameraModel, Valid: true}
+		}
 	}
 
 	return newImage
@@ -230,6 +251,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, g := range i.galleries {
 			galleryIDs = append(galleryIDs, g.ID)
 		}
+		galleryIDs = utils.IntAppendUniques(i.existingGalleryIDs, galleryIDs)
 
 		if err := i.ReaderWriter.UpdateGalleries(id, galleryIDs); err != nil {
 			return fmt.Errorf("failed to associate galleries: %s", err.Error())
@@ -241,6 +263,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, performer := range i.performers {
 			performerIDs = append(performerIDs, performer.ID)
 		}
+		performerIDs = utils.IntAppendUniques(i.existingPerformerIDs, performerIDs)
 
 		if err := i.ReaderWriter.UpdatePerformers(id, performerIDs); err != nil {
 			return fmt.Errorf("failed to associate performers: %s", err.Error())
@@ -252,6 +275,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, t := range i.tags {
 			tagIDs = append(tagIDs, t.ID)
 		}
+		tagIDs = utils.IntAppendUniques(i.existingTagIDs, tagIDs)
 		if err := i.ReaderWriter.UpdateTags(id, tagIDs); err != nil {
 			return fmt.Errorf("failed to associate tags: %s", err.Error())
 		}
@@ -304,6 +328,58 @@ func (i *Importer) Update(id int) error {
 	return nil
 }
 
+// Merge sets the missing fields of the existing image. The organized flag
+// and o-counter of the existing image are kept.
+func (i *Importer) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing image: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing image with id %d not found", id)
+	}
+
+	image := *existing
+	models.FillNullString(&image.Title, i.image.Title)
+	models.FillNullInt64(&image.Rating, i.image.Rating)
+	models.FillNullInt64(&image.Size, i.image.Size)
+	models.FillNullInt64(&image.Width, i.image.Width)
+	models.FillNullInt64(&image.Height, i.image.Height)
+	models.FillNullInt64(&image.StudioID, i.image.StudioID)
+	models.FillNullString(&image.Location, i.image.Location)
+	models.FillNullString(&image.CameraMake, i.image.CameraMake)
+	models.FillNullString(&image.CameraModel, i.image.CameraModel)
+	if !image.TakenAt.Valid {
+		image.TakenAt = i.image.TakenAt
+	}
+	if !image.Latitude.Valid || !image.Longitude.Valid {
+		image.Latitude = i.image.Latitude
+		image.Longitude = i.image.Longitude
+	}
+
+	i.ID = id
+	if _, err := i.ReaderWriter.UpdateFull(image); err != nil {
+		return fmt.Errorf("error updating existing image: %s", err.Error())
+	}
+
+	i.existingGalleryIDs, err = i.ReaderWriter.GetGalleryIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing image galleries: %s", err.Error())
+	}
+
+	i.existingPerformerIDs, err = i.ReaderWriter.GetPerformerIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing image performers: %s", err.Error())
+	}
+
+	i.existingTagIDs, err = i.ReaderWriter.GetTagIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing image tags: %s", err.Error())
+	}
+
+	return nil
+}
+
 func importTags(tagWriter models.TagReaderWriter, names []string, missingRefBehaviour models.ImportMissingRefEnum) ([]*models.Tag, error) {
 	tags, err := tagWriter.FindByNames(names, false)
 	if err != nil {
@@ -16,20 +16,24 @@ func ThumbnailNeeded(srcImage image.Image, maxSize int) bool {
 	return w > maxSize || h > maxSize
 }
 
+// ResizeImage returns the image resized so that its largest dimension is
+// maxSize.
+func ResizeImage(srcImage image.Image, maxSize int) *image.NRGBA {
+	// if height is longer then resize by height instead of width
+	dim := srcImage.Bounds().Max
+	if dim.Y > dim.X {
+		return imaging.Resize(srcImage, 0, maxSize, imaging.Box)
+	}
+
+	return imaging.Resize(srcImage, maxSize, 0, imaging.Box)
+}
+
 // GetThumbnail returns the thumbnail image of the provided image resized to
 // the provided max size. It resizes based on the largest X/Y direction.
 // It returns nil and an error if an error occurs reading, decoding or encoding
 // the image.
 func GetThumbnail(srcImage image.Image, maxSize int) ([]byte, error) {
-	var resizedImage image.Image
-
-	// if height is longer then resize by height instead of width
-	dim := srcImage.Bounds().Max
-	if dim.Y > dim.X {
-		resizedImage = imaging.Resize(srcImage, 0, maxSize, imaging.Box)
-	} else {
-		resizedImage = imaging.Resize(srcImage, maxSize, 0, imaging.Box)
-	}
+	resizedImage := ResizeImage(srcImage, maxSize)
 
 	buf := new(bytes.Buffer)
 	err := jpeg.Encode(buf, resizedImage, nil)
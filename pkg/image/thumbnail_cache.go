@@ -0,0 +1,379 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// thumbnailExts are the file extensions of the formats of thumbnails, in
+// order of preference. Thumbnails are encoded as WebP, or as JPEG if they
+// cannot be encoded as WebP. Thumbnails made by earlier versions are JPEG.
+var thumbnailExts = []string{"webp", "jpg"}
+
+// touchInterval is how often the modification time of a thumbnail, which is
+// when it was last served, is updated as it is served.
+const touchInterval = time.Hour
+
+// evictRatio is the proportion of the maximum size to which the cache is
+// reduced when it is full, so that thumbnails are not removed as each new
+// one is written.
+const evictRatio = 0.9
+
+const tmpSuffix = ".tmp"
+
+// ThumbnailPathFunc returns the path of the thumbnail of the image with the
+// checksum, with the size and file extension.
+type ThumbnailPathFunc func(checksum string, size int, ext string) string
+
+// WebPEncoder encodes the thumbnail as a WebP image. ffmpeg processes are
+// run with the priority.
+type WebPEncoder func(img *image.NRGBA, priority ffmpeg.ProcessPriority) ([]byte, error)
+
+// ThumbnailCache makes thumbnails of images when they are first requested,
+// and keeps them in a directory. The largest dimension of a thumbnail is one
+// of Sizes. If MaxSize is positive, the least recently served thumbnails are
+// removed once the total size of the directory exceeds it.
+type ThumbnailCache struct {
+	Dir  string
+	Path ThumbnailPathFunc
+
+	// Sizes are the sizes of the thumbnails in ascending order.
+	Sizes   []int
+	MaxSize int64
+
+	// EncodeWebP encodes the thumbnails. Thumbnails are encoded as JPEG if it
+	// is nil or returns an error.
+	EncodeWebP WebPEncoder
+
+	// workers limits the number of thumbnails which are made concurrently
+	// as they are requested
+	workers chan struct{}
+
+	mutex   sync.Mutex
+	pending map[string]*pendingThumbnail
+	// used is the total size of the directory, or -1 if it is not known
+	used     int64
+	evicting bool
+
+	webpWarning sync.Once
+}
+
+type pendingThumbnail struct {
+	done chan struct{}
+	path string
+	err  error
+}
+
+// NewThumbnailCache returns a cache of the thumbnails in the directory, of
+// which workers are made concurrently as they are requested.
+func NewThumbnailCache(dir string, path ThumbnailPathFunc, workers int) *ThumbnailCache {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &ThumbnailCache{
+		Dir:     dir,
+		Path:    path,
+		Sizes:   []int{models.DefaultGthumbWidth},
+		workers: make(chan struct{}, workers),
+		pending: make(map[string]*pendingThumbnail),
+		used:    -1,
+	}
+}
+
+// thumbnailSize returns the smallest size which is at least the requested
+// size, or the largest size if there is none.
+func (c *ThumbnailCache) thumbnailSize(requested int) int {
+	for _, s := range c.Sizes {
+		if s >= requested {
+			return s
+		}
+	}
+
+	return c.Sizes[len(c.Sizes)-1]
+}
+
+// thumbnailNeeded returns false if the image is known to be no larger than
+// the thumbnail.
+func thumbnailNeeded(i *models.Image, size int) bool {
+	if !i.Width.Valid || !i.Height.Valid {
+		return true
+	}
+
+	return i.Width.Int64 > int64(size) || i.Height.Int64 > int64(size)
+}
+
+// Get returns the path of the thumbnail of the image of the smallest size
+// which is at least the requested size, making the thumbnail if it does not
+// exist. Returns the empty string if the image is no larger than the
+// thumbnail, in which case the image should be served.
+func (c *ThumbnailCache) Get(i *models.Image, size int) (string, error) {
+	size = c.thumbnailSize(size)
+	if !thumbnailNeeded(i, size) {
+		return "", nil
+	}
+
+	if path := c.find(i.Checksum, size); path != "" {
+		return path, nil
+	}
+
+	// the user is waiting for the thumbnail
+	c.workers <- struct{}{}
+	defer func() {
+		<-c.workers
+	}()
+
+	return c.make(i, size, ffmpeg.PriorityInteractive)
+}
+
+// Generate makes the thumbnail of the image of the size shown in the
+// interface, if it does not exist.
+func (c *ThumbnailCache) Generate(i *models.Image) error {
+	size := c.thumbnailSize(models.DefaultGthumbWidth)
+	if !thumbnailNeeded(i, size) || c.find(i.Checksum, size) != "" {
+		return nil
+	}
+
+	_, err := c.make(i, size, ffmpeg.PriorityBackground)
+	return err
+}
+
+// Remove removes the thumbnails of all sizes of the image with the checksum.
+func (c *ThumbnailCache) Remove(checksum string) {
+	dir := filepath.Dir(c.Path(checksum, 0, ""))
+	matches, _ := filepath.Glob(filepath.Join(dir, checksum+"_*"))
+
+	var removed int64
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+
+		if err := os.Remove(m); err != nil {
+			logger.Warnf("Could not delete thumbnail %s: %s", m, err.Error())
+			continue
+		}
+		removed += info.Size()
+	}
+
+	c.mutex.Lock()
+	if c.used >= 0 {
+		c.used -= removed
+	}
+	c.mutex.Unlock()
+}
+
+// find returns the path of the existing thumbnail of the image with the
+// checksum and size, or the empty string if there is none.
+func (c *ThumbnailCache) find(checksum string, size int) string {
+	for _, ext := range thumbnailExts {
+		path := c.Path(checksum, size, ext)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		// the least recently served thumbnails are removed first
+		if c.MaxSize > 0 && time.Since(info.ModTime()) > touchInterval {
+			now := time.Now()
+			_ = os.Chtimes(path, now, now)
+		}
+
+		return path
+	}
+
+	return ""
+}
+
+// make makes the thumbnail, waiting for the thumbnail to be made if it is
+// already being made.
+func (c *ThumbnailCache) make(i *models.Image, size int, priority ffmpeg.ProcessPriority) (string, error) {
+	key := fmt.Sprintf("%s_%d", i.Checksum, size)
+
+	c.mutex.Lock()
+	if p, found := c.pending[key]; found {
+		c.mutex.Unlock()
+		<-p.done
+		return p.path, p.err
+	}
+
+	p := &pendingThumbnail{done: make(chan struct{})}
+	c.pending[key] = p
+	c.mutex.Unlock()
+
+	p.path, p.err = c.write(i, size, priority)
+
+	c.mutex.Lock()
+	delete(c.pending, key)
+	c.mutex.Unlock()
+	close(p.done)
+
+	return p.path, p.err
+}
+
+func (c *ThumbnailCache) write(i *models.Image, size int, priority ffmpeg.ProcessPriority) (string, error) {
+	// the thumbnail may have been made while waiting for a worker
+	if path := c.find(i.Checksum, size); path != "" {
+		return path, nil
+	}
+
+	srcImage, err := GetSourceImage(i)
+	if err != nil {
+		return "", fmt.Errorf("error reading image %s: %s", i.Path, err.Error())
+	}
+
+	if !ThumbnailNeeded(srcImage, size) {
+		return "", nil
+	}
+
+	data, ext, err := c.encode(ResizeImage(srcImage, size), priority)
+	if err != nil {
+		return "", fmt.Errorf("error encoding thumbnail of image %s: %s", i.Path, err.Error())
+	}
+
+	// write then rename, so that partially written thumbnails aren't served
+	path := c.Path(i.Checksum, size, ext)
+	if err := utils.WriteFile(path+tmpSuffix, data); err != nil {
+		return "", err
+	}
+	if err := os.Rename(path+tmpSuffix, path); err != nil {
+		return "", err
+	}
+
+	c.added(int64(len(data)))
+
+	return path, nil
+}
+
+func (c *ThumbnailCache) encode(img *image.NRGBA, priority ffmpeg.ProcessPriority) ([]byte, string, error) {
+	if c.EncodeWebP != nil {
+		data, err := c.EncodeWebP(img, priority)
+		if err == nil {
+			return data, "webp", nil
+		}
+
+		c.webpWarning.Do(func() {
+			logger.Warnf("Could not encode thumbnails as WebP, encoding them as JPEG: %s", err.Error())
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, nil); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), "jpg", nil
+}
+
+// added adds the size of a new thumbnail to the size of the cache, removing
+// thumbnails in the background if the cache is full.
+func (c *ThumbnailCache) added(size int64) {
+	if c.MaxSize <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	if c.used >= 0 {
+		c.used += size
+	}
+	// the size of the directory is counted when evicting
+	evict := !c.evicting && (c.used < 0 || c.used > c.MaxSize)
+	if evict {
+		c.evicting = true
+	}
+	c.mutex.Unlock()
+
+	if evict {
+		go func() {
+			if err := c.evict(); err != nil {
+				logger.Warnf("Error removing thumbnails: %s", err.Error())
+			}
+		}()
+	}
+}
+
+type cachedThumbnail struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict counts the size of the directory, and removes the least recently
+// served thumbnails if it exceeds the maximum size.
+func (c *ThumbnailCache) evict() error {
+	defer func() {
+		c.mutex.Lock()
+		c.evicting = false
+		c.mutex.Unlock()
+	}()
+
+	var files []cachedThumbnail
+	var total int64
+	err := filepath.Walk(c.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the file may have been removed since the directory was read
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if info.IsDir() || strings.HasSuffix(path, tmpSuffix) {
+			return nil
+		}
+
+		files = append(files, cachedThumbnail{
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if total > c.MaxSize {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].modTime.Before(files[j].modTime)
+		})
+
+		target := int64(float64(c.MaxSize) * evictRatio)
+		removed := 0
+		for _, f := range files {
+			if total <= target {
+				break
+			}
+
+			if err := os.Remove(f.path); err != nil {
+				logger.Warnf("Could not delete thumbnail %s: %s", f.path, err.Error())
+				continue
+			}
+			total -= f.size
+			removed++
+		}
+
+		logger.Debugf("Removed %d least recently used thumbnails", removed)
+	}
+
+	c.mutex.Lock()
+	c.used = total
+	c.mutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestThumbnailCache returns a cache in a temporary directory, which the
+// caller removes, and an image to make thumbnails of.
+func newTestThumbnailCache(t *testing.T) (*ThumbnailCache, *models.Image, string) {
+	dir, err := ioutil.TempDir("", "thumbnails")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imagePath := filepath.Join(dir, "image.png")
+	f, err := os.Create(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewNRGBA(image.Rect(0, 0, 100, 50))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	thumbnailDir := filepath.Join(dir, "thumbnails")
+	c := NewThumbnailCache(thumbnailDir, func(checksum string, size int, ext string) string {
+		return filepath.Join(thumbnailDir, fmt.Sprintf("%s_%d.%s", checksum, size, ext))
+	}, 2)
+	c.Sizes = []int{20, 40}
+
+	i := &models.Image{
+		Path:     imagePath,
+		Checksum: "checksum",
+		Width:    models.NullInt64(100),
+		Height:   models.NullInt64(50),
+	}
+
+	return c, i, dir
+}
+
+func TestThumbnailCacheGet(t *testing.T) {
+	c, i, dir := newTestThumbnailCache(t)
+	defer os.RemoveAll(dir)
+
+	// the smallest size of at least the requested size is used
+	path, err := c.Get(i, 30)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, c.Path("checksum", 40, "jpg"), path)
+
+	f, err := os.Open(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	thumbnail, err := jpeg.Decode(f)
+	f.Close()
+	if assert.Nil(t, err) {
+		assert.Equal(t, image.Rect(0, 0, 40, 20), thumbnail.Bounds())
+	}
+
+	// the largest size is used for larger requests
+	path, err = c.Get(i, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Path("checksum", 40, "jpg"), path)
+
+	// images no larger than the thumbnail are served as they are
+	c.Sizes = []int{100}
+	path, err = c.Get(i, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, "", path)
+}
+
+func TestThumbnailCacheWebP(t *testing.T) {
+	c, i, dir := newTestThumbnailCache(t)
+	defer os.RemoveAll(dir)
+
+	var mutex sync.Mutex
+	encoded := 0
+	release := make(chan struct{})
+	c.EncodeWebP = func(img *image.NRGBA, priority ffmpeg.ProcessPriority) ([]byte, error) {
+		<-release
+		mutex.Lock()
+		defer mutex.Unlock()
+		encoded++
+		return []byte("webp"), nil
+	}
+
+	// concurrent requests for the same thumbnail make it once
+	var wg sync.WaitGroup
+	paths := make([]string, 4)
+	for n := range paths {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			paths[n], _ = c.Get(i, 20)
+		}(n)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, encoded)
+	for _, p := range paths {
+		assert.Equal(t, c.Path("checksum", 20, "webp"), p)
+	}
+
+	data, _ := ioutil.ReadFile(paths[0])
+	assert.Equal(t, "webp", string(data))
+
+	// JPEG is used if the thumbnail cannot be encoded as WebP
+	c.EncodeWebP = func(img *image.NRGBA, priority ffmpeg.ProcessPriority) ([]byte, error) {
+		return nil, fmt.Errorf("no webp encoder")
+	}
+	path, err := c.Get(i, 40)
+	assert.Nil(t, err)
+	assert.Equal(t, c.Path("checksum", 40, "jpg"), path)
+
+	c.Remove("checksum")
+	files, _ := ioutil.ReadDir(c.Dir)
+	assert.Len(t, files, 0)
+}
+
+func TestThumbnailCacheEvict(t *testing.T) {
+	c, _, dir := newTestThumbnailCache(t)
+	defer os.RemoveAll(dir)
+	c.MaxSize = 250
+
+	now := time.Now()
+	for n := 0; n < 5; n++ {
+		path := c.Path(fmt.Sprintf("image%d", n), 20, "webp")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// image0 was served most recently
+		modTime := now.Add(-time.Duration(n) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.Nil(t, c.evict())
+	assert.Equal(t, int64(200), c.used)
+
+	for n := 0; n < 5; n++ {
+		_, err := os.Stat(c.Path(fmt.Sprintf("image%d", n), 20, "webp"))
+		assert.Equal(t, n >= 2, os.IsNotExist(err), "image%d", n)
+	}
+}
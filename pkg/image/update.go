@@ -12,6 +12,36 @@ func UpdateFileModTime(qb models.ImageWriter, id int, modTime models.NullSQLiteT
 	})
 }
 
+// UpdateZipFilename updates the paths of the images in the zip file of the
+// gallery, after the zip file was moved to zipFilename.
+func UpdateZipFilename(qb models.ImageReaderWriter, galleryID int, zipFilename string) error {
+	images, err := qb.FindByGalleryID(galleryID)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range images {
+		if !IsZipPath(i.Path) {
+			continue
+		}
+
+		_, filenameInZip := SplitZipFilename(i.Path)
+		newPath := ZipFilename(zipFilename, filenameInZip)
+		if newPath == i.Path {
+			continue
+		}
+
+		if _, err := qb.Update(models.ImagePartial{
+			ID:   i.ID,
+			Path: &newPath,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func AddPerformer(qb models.ImageReaderWriter, id int, performerID int) (bool, error) {
 	performerIDs, err := qb.GetPerformerIDs(id)
 	if err != nil {
@@ -0,0 +1,37 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateZipFilename(t *testing.T) {
+	const (
+		galleryID = 1
+		oldZip    = "old/gallery.zip"
+		newZip    = "new/gallery.zip"
+	)
+
+	mockImageReader := &mocks.ImageReaderWriter{}
+
+	mockImageReader.On("FindByGalleryID", galleryID).Return([]*models.Image{
+		{ID: 1, Path: ZipFilename(oldZip, "a.jpg")},
+		{ID: 2, Path: ZipFilename(newZip, "b.jpg")},
+		{ID: 3, Path: "old/c.jpg"},
+	}, nil).Once()
+
+	newPath := ZipFilename(newZip, "a.jpg")
+	mockImageReader.On("Update", models.ImagePartial{
+		ID:   1,
+		Path: &newPath,
+	}).Return(nil, nil).Once()
+
+	err := UpdateZipFilename(mockImageReader, galleryID, newZip)
+	assert.Nil(t, err)
+
+	mockImageReader.AssertExpectations(t)
+	mockImageReader.AssertNumberOfCalls(t, "Update", 1)
+}
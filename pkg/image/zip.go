@@ -0,0 +1,375 @@
+package image
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ZipPasswords returns the passwords to try when reading the encrypted files
+// of the zip file at path, including those of the zip files within it. Set by
+// the manager from the configuration.
+var ZipPasswords = func(path string) []string {
+	return nil
+}
+
+// zipArchive is an open zip file, which may be within another zip file.
+type zipArchive struct {
+	*zip.Reader
+	name      string
+	ra        io.ReaderAt
+	passwords []string
+
+	// closer is the zip file on disk, if the archive is not within another
+	// zip file
+	closer io.Closer
+}
+
+func (z *zipArchive) Close() error {
+	if z.closer != nil {
+		return z.closer.Close()
+	}
+	return nil
+}
+
+func openZipArchive(path string, passwords []string) (*zipArchive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	z, err := newZipArchive(path, f, info.Size(), passwords)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	z.closer = f
+
+	return z, nil
+}
+
+func newZipArchive(name string, ra io.ReaderAt, size int64, passwords []string) (*zipArchive, error) {
+	r, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zipArchive{
+		Reader:    r,
+		name:      name,
+		ra:        ra,
+		passwords: passwords,
+	}, nil
+}
+
+func (z *zipArchive) find(name string) *zip.File {
+	for _, f := range z.File {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func (z *zipArchive) open(f *zip.File) (io.ReadCloser, error) {
+	return openZipEntry(z.ra, f, z.passwords)
+}
+
+// openNested opens the zip file f within z, using the passwords of z. Zip
+// files which are stored without compression or encryption are read in
+// place, others are read into memory.
+func (z *zipArchive) openNested(name string, f *zip.File) (*zipArchive, error) {
+	if f.Method == zip.Store && !isEncrypted(f) {
+		offset, err := f.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+		size := int64(f.UncompressedSize64)
+		return newZipArchive(name, io.NewSectionReader(z.ra, offset, size), size, z.passwords)
+	}
+
+	return readNestedZip(name, newZipEntry(name, z, f), z.passwords)
+}
+
+// readNestedZip reads the zip file of the entry e, which is within another
+// archive, into memory.
+func readNestedZip(name string, e *ZipEntry, passwords []string) (*zipArchive, error) {
+	rc, err := e.Open()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return newZipArchive(name, bytes.NewReader(data), int64(len(data)), passwords)
+}
+
+// checkPassword returns an error wrapping ErrZipPassword if z has encrypted
+// files which none of its passwords decrypt.
+func (z *zipArchive) checkPassword() error {
+	for _, f := range z.File {
+		if !isEncrypted(f) || f.FileInfo().IsDir() {
+			continue
+		}
+
+		if err := checkZipEntryPassword(z.ra, f, z.passwords); err != nil {
+			return fmt.Errorf("%s: %w", PathDisplayName(z.name), err)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func isZipName(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".zip")
+}
+
+// openZipPath finds the file of a path within an archive, where the file may
+// be within zip files within the archive. The returned closer must be closed
+// once the file has been read.
+func openZipPath(path string) (*ZipEntry, io.Closer, error) {
+	parts := strings.Split(path, zipSeparator)
+	format, err := detectArchiveFormat(parts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if format != archiveZip {
+		e, err := findToolArchivePath(parts[0], parts[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		return e, nopCloser{}, nil
+	}
+
+	root, err := openZipArchive(parts[0], ZipPasswords(parts[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e, err := findZipPath(root, parts[1:])
+	if err != nil {
+		root.Close()
+		return nil, nil, err
+	}
+
+	return e, root, nil
+}
+
+// findZipPath finds the file of the names within z, where each name but the
+// last is a zip file within the previous one.
+func findZipPath(z *zipArchive, names []string) (*ZipEntry, error) {
+	for i, name := range names {
+		f := z.find(name)
+		if f == nil {
+			return nil, fmt.Errorf("file with name '%s' not found in zip file '%s'", name, PathDisplayName(z.name))
+		}
+
+		// the last name is the file
+		if i == len(names)-1 {
+			return newZipEntry(strings.Join(names, zipSeparator), z, f), nil
+		}
+
+		var err error
+		z, err = z.openNested(ZipFilename(z.name, name), f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("no file name in zip path")
+}
+
+// openZipPathFile opens the file of a path within an archive. See
+// openZipPath.
+func openZipPathFile(path string) (io.ReadCloser, error) {
+	e, closer, err := openZipPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := e.Open()
+	if err != nil {
+		closer.Close()
+		return nil, err
+	}
+
+	return &imageReadCloser{
+		src: src,
+		zrc: closer,
+	}, nil
+}
+
+// ZipEntry is a file within a gallery archive.
+type ZipEntry struct {
+	// Name is the path of the file within the archive. Files within zip files
+	// within the archive are named as returned by ZipFilename, such as
+	// set/part1.zip\x00image.jpg.
+	Name     string
+	Size     int64
+	Modified time.Time
+
+	open func() (io.ReadCloser, error)
+}
+
+func newZipEntry(name string, z *zipArchive, f *zip.File) *ZipEntry {
+	return &ZipEntry{
+		Name:     name,
+		Size:     int64(f.UncompressedSize64),
+		Modified: f.FileInfo().ModTime(),
+		open: func() (io.ReadCloser, error) {
+			return z.open(f)
+		},
+	}
+}
+
+// Open opens the file for reading, decrypting it if required.
+func (e *ZipEntry) Open() (io.ReadCloser, error) {
+	return e.open()
+}
+
+func (e *ZipEntry) fileInfo() os.FileInfo {
+	return zipEntryInfo{e}
+}
+
+type zipEntryInfo struct {
+	e *ZipEntry
+}
+
+func (i zipEntryInfo) Name() string {
+	return filepath.Base(PathDisplayName(i.e.Name))
+}
+
+func (i zipEntryInfo) Size() int64 {
+	return i.e.Size
+}
+
+func (i zipEntryInfo) Mode() os.FileMode {
+	return 0444
+}
+
+func (i zipEntryInfo) ModTime() time.Time {
+	return i.e.Modified
+}
+
+func (i zipEntryInfo) IsDir() bool {
+	return false
+}
+
+func (i zipEntryInfo) Sys() interface{} {
+	return nil
+}
+
+// skipZipFile returns true for files in archives which are not walked.
+func skipZipFile(name string) bool {
+	return strings.Contains(name, "__MACOSX")
+}
+
+// WalkZip calls walkFunc for each file in the archive at path, and for the
+// files of the zip files within it, which are not passed to walkFunc
+// themselves. Directories and macOS metadata are skipped. Returns an error
+// wrapping ErrZipPassword if the encrypted files of the archive, or of a
+// zip file within it, cannot be decrypted. No files of that archive are
+// passed to walkFunc.
+func WalkZip(path string, walkFunc func(e *ZipEntry) error) error {
+	format, err := detectArchiveFormat(path)
+	if err != nil {
+		return err
+	}
+
+	if format != archiveZip {
+		return walkToolArchive(path, ZipPasswords(path), walkFunc)
+	}
+
+	z, err := openZipArchive(path, ZipPasswords(path))
+	if err != nil {
+		return err
+	}
+	defer z.Close()
+
+	return walkZipArchive(z, "", walkFunc)
+}
+
+func walkZipArchive(z *zipArchive, prefix string, walkFunc func(e *ZipEntry) error) error {
+	if err := z.checkPassword(); err != nil {
+		return err
+	}
+
+	for _, f := range z.File {
+		if f.FileInfo().IsDir() || skipZipFile(f.Name) {
+			continue
+		}
+
+		name := f.Name
+		if prefix != "" {
+			name = ZipFilename(prefix, f.Name)
+		}
+
+		if !isZipName(f.Name) {
+			if err := walkFunc(newZipEntry(name, z, f)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		nestedName := ZipFilename(z.name, f.Name)
+		nested, err := z.openNested(nestedName, f)
+		if errors.Is(err, zip.ErrFormat) {
+			// not a zip file after all
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", PathDisplayName(nestedName), err)
+		}
+
+		if err := walkZipArchive(nested, name, walkFunc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckZipPassword returns an error wrapping ErrZipPassword if password does
+// not decrypt the encrypted files of the archive at path, or of the zip files
+// within it.
+func CheckZipPassword(path string, password string) error {
+	format, err := detectArchiveFormat(path)
+	if err != nil {
+		return err
+	}
+
+	if format != archiveZip {
+		return walkToolArchive(path, []string{password}, func(e *ZipEntry) error {
+			return nil
+		})
+	}
+
+	z, err := openZipArchive(path, []string{password})
+	if err != nil {
+		return err
+	}
+	defer z.Close()
+
+	return walkZipArchive(z, "", func(e *ZipEntry) error {
+		return nil
+	})
+}
@@ -0,0 +1,361 @@
+package image
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	zipFlagEncrypted      = 0x1
+	zipFlagDataDescriptor = 0x8
+
+	// WinZip AES encryption
+	zipMethodAES      = 99
+	zipExtraAES       = 0x9901
+	aesVerifierLen    = 2
+	aesAuthCodeLen    = 10
+	aesKeyIterations  = 1000
+	aesVendorVersion2 = 2
+
+	// traditional PKWARE encryption
+	zipCryptoHeaderLen = 12
+)
+
+// ErrZipPassword is returned when reading an encrypted file in a zip file,
+// if none of the passwords of the zip file are correct.
+var ErrZipPassword = errors.New("incorrect or missing zip file password")
+
+var errZipAuthentication = errors.New("zip: authentication failed")
+
+func isEncrypted(f *zip.File) bool {
+	return f.Flags&zipFlagEncrypted != 0
+}
+
+// openZipEntry opens the file f in the zip file read from ra, decrypting it
+// with the first matching password if it is encrypted.
+func openZipEntry(ra io.ReaderAt, f *zip.File, passwords []string) (io.ReadCloser, error) {
+	if !isEncrypted(f) {
+		return f.Open()
+	}
+
+	offset, err := f.DataOffset()
+	if err != nil {
+		return nil, err
+	}
+	raw := io.NewSectionReader(ra, offset, int64(f.CompressedSize64))
+
+	if e, ok := readAESExtra(f.Extra); ok {
+		return openAESEntry(raw, f, e, passwords)
+	}
+
+	return openZipCryptoEntry(raw, f, passwords)
+}
+
+// checkZipEntryPassword returns ErrZipPassword if none of the passwords
+// decrypt the file f, without reading its contents.
+func checkZipEntryPassword(ra io.ReaderAt, f *zip.File, passwords []string) error {
+	rc, err := openZipEntry(ra, f, passwords)
+	if err != nil {
+		return err
+	}
+
+	return rc.Close()
+}
+
+func decompressZipEntry(method uint16, r io.Reader) (io.ReadCloser, error) {
+	switch method {
+	case zip.Store:
+		return ioutil.NopCloser(r), nil
+	case zip.Deflate:
+		return flate.NewReader(r), nil
+	}
+
+	return nil, zip.ErrAlgorithm
+}
+
+// zipChecksumReader returns zip.ErrChecksum at the end of the file if its
+// CRC-32 does not match.
+type zipChecksumReader struct {
+	rc   io.ReadCloser
+	hash hash.Hash32
+	want uint32
+}
+
+func (r *zipChecksumReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF && r.hash.Sum32() != r.want {
+		return n, zip.ErrChecksum
+	}
+	return n, err
+}
+
+func (r *zipChecksumReader) Close() error {
+	return r.rc.Close()
+}
+
+// zipCryptoKeys is the state of the traditional PKWARE stream cipher.
+type zipCryptoKeys [3]uint32
+
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	k := &zipCryptoKeys{0x12345678, 0x23456789, 0x34567890}
+	for i := 0; i < len(password); i++ {
+		k.update(password[i])
+	}
+	return k
+}
+
+func crc32Update(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ (crc >> 8)
+}
+
+func (k *zipCryptoKeys) update(b byte) {
+	k[0] = crc32Update(k[0], b)
+	k[1] = (k[1]+(k[0]&0xff))*134775813 + 1
+	k[2] = crc32Update(k[2], byte(k[1]>>24))
+}
+
+func (k *zipCryptoKeys) decrypt(buf []byte) {
+	for i, c := range buf {
+		t := k[2] | 2
+		p := c ^ byte((t*(t^1))>>8)
+		k.update(p)
+		buf[i] = p
+	}
+}
+
+type zipCryptoReader struct {
+	r    io.Reader
+	keys *zipCryptoKeys
+}
+
+func (r *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.keys.decrypt(p[:n])
+	return n, err
+}
+
+func openZipCryptoEntry(raw *io.SectionReader, f *zip.File, passwords []string) (io.ReadCloser, error) {
+	header := make([]byte, zipCryptoHeaderLen)
+	if _, err := raw.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+
+	// the last byte of the header is a check byte of the password
+	check := byte(f.CRC32 >> 24)
+	if f.Flags&zipFlagDataDescriptor != 0 {
+		check = byte(f.ModifiedTime >> 8)
+	}
+
+	for _, password := range passwords {
+		keys := newZipCryptoKeys(password)
+		h := make([]byte, zipCryptoHeaderLen)
+		copy(h, header)
+		keys.decrypt(h)
+		if h[zipCryptoHeaderLen-1] != check {
+			continue
+		}
+
+		data := io.NewSectionReader(raw, zipCryptoHeaderLen, raw.Size()-zipCryptoHeaderLen)
+		rc, err := decompressZipEntry(f.Method, &zipCryptoReader{r: data, keys: keys})
+		if err != nil {
+			return nil, err
+		}
+
+		return &zipChecksumReader{rc: rc, hash: crc32.NewIEEE(), want: f.CRC32}, nil
+	}
+
+	return nil, ErrZipPassword
+}
+
+// aesExtra is the extra field of a file encrypted with WinZip AES.
+type aesExtra struct {
+	vendorVersion uint16
+	strength      byte
+	method        uint16
+}
+
+func (e *aesExtra) keyLen() int {
+	return 8 + 8*int(e.strength)
+}
+
+func (e *aesExtra) saltLen() int {
+	return e.keyLen() / 2
+}
+
+func readAESExtra(extra []byte) (*aesExtra, bool) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			break
+		}
+
+		if id == zipExtraAES && size >= 7 {
+			e := &aesExtra{
+				vendorVersion: binary.LittleEndian.Uint16(extra),
+				strength:      extra[4],
+				method:        binary.LittleEndian.Uint16(extra[5:]),
+			}
+			if e.strength < 1 || e.strength > 3 {
+				return nil, false
+			}
+			return e, true
+		}
+
+		extra = extra[size:]
+	}
+
+	return nil, false
+}
+
+func pbkdf2SHA1(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha1.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var ret []byte
+	var counter [4]byte
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		ret = prf.Sum(ret)
+
+		t := ret[len(ret)-hashLen:]
+		copy(u, t)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range u {
+				t[j] ^= u[j]
+			}
+		}
+	}
+
+	return ret[:keyLen]
+}
+
+// winZipCTR is AES in counter mode, with the little endian counter starting
+// at 1 used by WinZip.
+type winZipCTR struct {
+	block   cipher.Block
+	counter [aes.BlockSize]byte
+	stream  [aes.BlockSize]byte
+	pos     int
+}
+
+func newWinZipCTR(block cipher.Block) *winZipCTR {
+	return &winZipCTR{block: block, pos: aes.BlockSize}
+}
+
+func (c *winZipCTR) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if c.pos == aes.BlockSize {
+			for j := range c.counter {
+				c.counter[j]++
+				if c.counter[j] != 0 {
+					break
+				}
+			}
+			c.block.Encrypt(c.stream[:], c.counter[:])
+			c.pos = 0
+		}
+
+		dst[i] = src[i] ^ c.stream[c.pos]
+		c.pos++
+	}
+}
+
+// aesReader decrypts the data of a file encrypted with WinZip AES, and
+// returns errZipAuthentication at the end of the data if the authentication
+// code does not match.
+type aesReader struct {
+	r        io.Reader
+	ctr      *winZipCTR
+	mac      hash.Hash
+	authCode io.Reader
+}
+
+func (r *aesReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.mac.Write(p[:n])
+	r.ctr.XORKeyStream(p[:n], p[:n])
+
+	if err == io.EOF {
+		code := make([]byte, aesAuthCodeLen)
+		if _, err := io.ReadFull(r.authCode, code); err != nil {
+			return n, err
+		}
+		if !hmac.Equal(r.mac.Sum(nil)[:aesAuthCodeLen], code) {
+			return n, errZipAuthentication
+		}
+	}
+
+	return n, err
+}
+
+func openAESEntry(raw *io.SectionReader, f *zip.File, e *aesExtra, passwords []string) (io.ReadCloser, error) {
+	keyLen := e.keyLen()
+	saltLen := e.saltLen()
+	dataLen := raw.Size() - int64(saltLen) - aesVerifierLen - aesAuthCodeLen
+	if dataLen < 0 {
+		return nil, zip.ErrFormat
+	}
+
+	header := make([]byte, saltLen+aesVerifierLen)
+	if _, err := raw.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	salt := header[:saltLen]
+	verifier := header[saltLen:]
+
+	for _, password := range passwords {
+		keys := pbkdf2SHA1([]byte(password), salt, aesKeyIterations, 2*keyLen+aesVerifierLen)
+		if !hmac.Equal(keys[2*keyLen:], verifier) {
+			continue
+		}
+
+		block, err := aes.NewCipher(keys[:keyLen])
+		if err != nil {
+			return nil, err
+		}
+
+		dataOffset := int64(len(header))
+		r := &aesReader{
+			r:        io.NewSectionReader(raw, dataOffset, dataLen),
+			ctr:      newWinZipCTR(block),
+			mac:      hmac.New(sha1.New, keys[keyLen:2*keyLen]),
+			authCode: io.NewSectionReader(raw, dataOffset+dataLen, aesAuthCodeLen),
+		}
+
+		rc, err := decompressZipEntry(e.method, r)
+		if err != nil {
+			return nil, err
+		}
+
+		// version 2 does not store the checksum, relying on the
+		// authentication code instead
+		if e.vendorVersion == aesVendorVersion2 {
+			return rc, nil
+		}
+
+		return &zipChecksumReader{rc: rc, hash: crc32.NewIEEE(), want: f.CRC32}, nil
+	}
+
+	return nil, ErrZipPassword
+}
@@ -0,0 +1,348 @@
+package image
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testZipPassword = "secret"
+
+// rawZipEntry is a file written to a zip file as is, so that encrypted files
+// can be written.
+type rawZipEntry struct {
+	name             string
+	method           uint16
+	flags            uint16
+	crc              uint32
+	modTime          uint16
+	extra            []byte
+	data             []byte
+	uncompressedSize int
+}
+
+func writeRawZip(entries []rawZipEntry) []byte {
+	var b bytes.Buffer
+	var central bytes.Buffer
+	le := binary.LittleEndian
+
+	for _, e := range entries {
+		offset := b.Len()
+
+		header := func(w *bytes.Buffer, sig uint32, central bool) {
+			binary.Write(w, le, sig)
+			if central {
+				binary.Write(w, le, uint16(20)) // version made by
+			}
+			binary.Write(w, le, uint16(20)) // version needed
+			binary.Write(w, le, e.flags)
+			binary.Write(w, le, e.method)
+			binary.Write(w, le, e.modTime)
+			binary.Write(w, le, uint16(0x5221)) // date
+			binary.Write(w, le, e.crc)
+			binary.Write(w, le, uint32(len(e.data)))
+			binary.Write(w, le, uint32(e.uncompressedSize))
+			binary.Write(w, le, uint16(len(e.name)))
+			binary.Write(w, le, uint16(len(e.extra)))
+			if central {
+				binary.Write(w, le, uint16(0)) // comment length
+				binary.Write(w, le, uint16(0)) // disk number
+				binary.Write(w, le, uint16(0)) // internal attributes
+				binary.Write(w, le, uint32(0)) // external attributes
+				binary.Write(w, le, uint32(offset))
+			}
+			w.WriteString(e.name)
+			w.Write(e.extra)
+		}
+
+		header(&b, 0x04034b50, false)
+		b.Write(e.data)
+		header(&central, 0x02014b50, true)
+	}
+
+	centralOffset := b.Len()
+	b.Write(central.Bytes())
+
+	binary.Write(&b, le, uint32(0x06054b50))
+	binary.Write(&b, le, uint16(0))
+	binary.Write(&b, le, uint16(0))
+	binary.Write(&b, le, uint16(len(entries)))
+	binary.Write(&b, le, uint16(len(entries)))
+	binary.Write(&b, le, uint32(central.Len()))
+	binary.Write(&b, le, uint32(centralOffset))
+	binary.Write(&b, le, uint16(0))
+
+	return b.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	w, _ := flate.NewWriter(&b, flate.DefaultCompression)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+func zipCryptoEntry(name string, content []byte, password string) rawZipEntry {
+	crc := crc32.ChecksumIEEE(content)
+	plain := append(make([]byte, zipCryptoHeaderLen), content...)
+	plain[zipCryptoHeaderLen-1] = byte(crc >> 24)
+
+	keys := newZipCryptoKeys(password)
+	data := make([]byte, len(plain))
+	for i, p := range plain {
+		t := keys[2] | 2
+		data[i] = p ^ byte((t*(t^1))>>8)
+		keys.update(p)
+	}
+
+	return rawZipEntry{
+		name:             name,
+		method:           zip.Store,
+		flags:            zipFlagEncrypted,
+		crc:              crc,
+		data:             data,
+		uncompressedSize: len(content),
+	}
+}
+
+func aesEntry(name string, content []byte, password string, vendorVersion uint16, method uint16) rawZipEntry {
+	const strength = 3
+	e := &aesExtra{strength: strength}
+	keyLen := e.keyLen()
+	salt := bytes.Repeat([]byte{7}, e.saltLen())
+	keys := pbkdf2SHA1([]byte(password), salt, aesKeyIterations, 2*keyLen+aesVerifierLen)
+
+	compressed := content
+	if method == zip.Deflate {
+		compressed = deflate(content)
+	}
+
+	block, _ := aes.NewCipher(keys[:keyLen])
+	encrypted := make([]byte, len(compressed))
+	newWinZipCTR(block).XORKeyStream(encrypted, compressed)
+
+	mac := hmac.New(sha1.New, keys[keyLen:2*keyLen])
+	mac.Write(encrypted)
+
+	var data []byte
+	data = append(data, salt...)
+	data = append(data, keys[2*keyLen:]...)
+	data = append(data, encrypted...)
+	data = append(data, mac.Sum(nil)[:aesAuthCodeLen]...)
+
+	extra := make([]byte, 11)
+	binary.LittleEndian.PutUint16(extra, zipExtraAES)
+	binary.LittleEndian.PutUint16(extra[2:], 7)
+	binary.LittleEndian.PutUint16(extra[4:], vendorVersion)
+	copy(extra[6:], "AE")
+	extra[8] = strength
+	binary.LittleEndian.PutUint16(extra[9:], method)
+
+	var crc uint32
+	if vendorVersion != aesVendorVersion2 {
+		crc = crc32.ChecksumIEEE(content)
+	}
+
+	return rawZipEntry{
+		name:             name,
+		method:           zipMethodAES,
+		flags:            zipFlagEncrypted,
+		crc:              crc,
+		extra:            extra,
+		data:             data,
+		uncompressedSize: len(content),
+	}
+}
+
+func writeZip(files map[string][]byte, method uint16) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for _, name := range []string{"a.jpg", "inner.zip", "b.jpg", "__MACOSX/._inner.zip"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		f, _ := w.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		f.Write(content)
+	}
+	w.Close()
+	return b.Bytes()
+}
+
+func withZipPasswords(passwords []string) func() {
+	old := ZipPasswords
+	ZipPasswords = func(path string) []string {
+		return passwords
+	}
+	return func() {
+		ZipPasswords = old
+	}
+}
+
+func tempZip(t *testing.T, data []byte) (string, func()) {
+	dir, err := ioutil.TempDir("", "stash-zip-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "gallery.zip")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func readZipPath(path string) (string, error) {
+	rc, err := openSourceImage(path)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(rc)
+	return string(data), err
+}
+
+func walkNames(path string) ([]string, error) {
+	var ret []string
+	err := WalkZip(path, func(e *ZipEntry) error {
+		ret = append(ret, e.Name)
+		return nil
+	})
+	return ret, err
+}
+
+func TestPBKDF2SHA1(t *testing.T) {
+	// RFC 6070 test vectors
+	assert.Equal(t, "0c60c80f961f0e71f3a9b524af6012062fe037a6", hex.EncodeToString(pbkdf2SHA1([]byte("password"), []byte("salt"), 1, 20)))
+	assert.Equal(t, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957", hex.EncodeToString(pbkdf2SHA1([]byte("password"), []byte("salt"), 2, 20)))
+	assert.Equal(t, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038", hex.EncodeToString(pbkdf2SHA1([]byte("passwordPASSWORDpassword"), []byte("saltSALTsaltSALTsaltSALTsaltSALTsalt"), 4096, 25)))
+}
+
+func TestEncryptedZip(t *testing.T) {
+	content := []byte("image data")
+	path, cleanup := tempZip(t, writeRawZip([]rawZipEntry{
+		zipCryptoEntry("zipcrypto.jpg", content, testZipPassword),
+		aesEntry("aes1.jpg", content, testZipPassword, 1, zip.Deflate),
+		aesEntry("aes2.jpg", content, testZipPassword, aesVendorVersion2, zip.Store),
+	}))
+	defer cleanup()
+
+	names := []string{"zipcrypto.jpg", "aes1.jpg", "aes2.jpg"}
+
+	// no password
+	for _, name := range names {
+		_, err := readZipPath(ZipFilename(path, name))
+		assert.True(t, errors.Is(err, ErrZipPassword), name)
+	}
+	_, err := walkNames(path)
+	assert.True(t, errors.Is(err, ErrZipPassword))
+
+	// wrong password, then the correct one
+	defer withZipPasswords([]string{"wrong", testZipPassword})()
+	for _, name := range names {
+		got, err := readZipPath(ZipFilename(path, name))
+		assert.Nil(t, err, name)
+		assert.Equal(t, string(content), got, name)
+	}
+
+	got, err := walkNames(path)
+	assert.Nil(t, err)
+	assert.Equal(t, names, got)
+
+	assert.Nil(t, CheckZipPassword(path, testZipPassword))
+	assert.True(t, errors.Is(CheckZipPassword(path, "wrong"), ErrZipPassword))
+}
+
+func TestEncryptedZipCorrupt(t *testing.T) {
+	content := []byte("image data")
+	e := aesEntry("aes2.jpg", content, testZipPassword, aesVendorVersion2, zip.Store)
+
+	// corrupt the encrypted data after the password verifier
+	e.data[len(e.data)-aesAuthCodeLen-1] ^= 0xff
+
+	path, cleanup := tempZip(t, writeRawZip([]rawZipEntry{e}))
+	defer cleanup()
+
+	defer withZipPasswords([]string{testZipPassword})()
+	_, err := readZipPath(ZipFilename(path, "aes2.jpg"))
+	assert.Equal(t, errZipAuthentication, err)
+}
+
+func TestNestedZip(t *testing.T) {
+	for _, method := range []uint16{zip.Store, zip.Deflate} {
+		inner := writeZip(map[string][]byte{
+			"b.jpg": []byte("inner image"),
+		}, method)
+
+		path, cleanup := tempZip(t, writeZip(map[string][]byte{
+			"a.jpg":                []byte("outer image"),
+			"inner.zip":            inner,
+			"__MACOSX/._inner.zip": []byte("metadata"),
+		}, method))
+		defer cleanup()
+
+		innerName := ZipFilename("inner.zip", "b.jpg")
+		names, err := walkNames(path)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a.jpg", innerName}, names)
+
+		got, err := readZipPath(ZipFilename(path, innerName))
+		assert.Nil(t, err)
+		assert.Equal(t, "inner image", got)
+
+		info, err := stat(ZipFilename(path, innerName))
+		if assert.Nil(t, err) {
+			assert.Equal(t, int64(len("inner image")), info.Size())
+		}
+
+		_, err = readZipPath(ZipFilename(path, ZipFilename("inner.zip", "c.jpg")))
+		assert.NotNil(t, err)
+
+		zipFilename, filenameInZip := SplitZipFilename(ZipFilename(path, innerName))
+		assert.Equal(t, path, zipFilename)
+		assert.Equal(t, innerName, filenameInZip)
+	}
+}
+
+func TestNestedEncryptedZip(t *testing.T) {
+	inner := writeRawZip([]rawZipEntry{
+		zipCryptoEntry("b.jpg", []byte("inner image"), testZipPassword),
+	})
+
+	path, cleanup := tempZip(t, writeRawZip([]rawZipEntry{
+		zipCryptoEntry("inner.zip", inner, testZipPassword),
+	}))
+	defer cleanup()
+
+	_, err := walkNames(path)
+	assert.True(t, errors.Is(err, ErrZipPassword))
+
+	defer withZipPasswords([]string{testZipPassword})()
+	innerName := ZipFilename("inner.zip", "b.jpg")
+	names, err := walkNames(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{innerName}, names)
+
+	got, err := readZipPath(ZipFilename(path, innerName))
+	assert.Nil(t, err)
+	assert.Equal(t, "inner image", got)
+}
@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// captionFileCache holds the caption file names of each folder read during a
+// scan, so that a folder is only read once for all of its scene files.
+type captionFileCache struct {
+	mutex sync.Mutex
+	dirs  map[string][]string
+}
+
+func newCaptionFileCache() *captionFileCache {
+	return &captionFileCache{
+		dirs: make(map[string][]string),
+	}
+}
+
+// getExternalCaptions returns the caption files next to the scene file at
+// path. The folder is read if it is not cached.
+func (c *captionFileCache) getExternalCaptions(path string) ([]*models.SceneCaption, error) {
+	if c == nil {
+		return scene.GetExternalCaptions(path)
+	}
+
+	dir := filepath.Dir(path)
+
+	c.mutex.Lock()
+	names, found := c.dirs[dir]
+	c.mutex.Unlock()
+
+	if !found {
+		var err error
+		names, err = scene.ListCaptionFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mutex.Lock()
+		c.dirs[dir] = names
+		c.mutex.Unlock()
+	}
+
+	return scene.MatchExternalCaptions(path, names), nil
+}
@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptionFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-captions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, fn := range []string{"a.mp4", "a.srt", "a.en.vtt", "b.mp4", "b.srt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, fn), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := newCaptionFileCache()
+
+	captions, err := c.getExternalCaptions(filepath.Join(dir, "a.mp4"))
+	assert.Nil(t, err)
+	assert.Len(t, captions, 2)
+
+	// caption files added after the folder is read are not seen until the
+	// next scan
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.de.srt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	captions, err = c.getExternalCaptions(filepath.Join(dir, "b.mp4"))
+	assert.Nil(t, err)
+	if assert.Len(t, captions, 1) {
+		assert.Equal(t, filepath.Join(dir, "b.srt"), captions[0].Path)
+	}
+
+	// a nil cache reads the folder every time
+	var nilCache *captionFileCache
+	captions, err = nilCache.getExternalCaptions(filepath.Join(dir, "b.mp4"))
+	assert.Nil(t, err)
+	assert.Len(t, captions, 2)
+}
@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// cleanReport accumulates the objects removed by the clean task. It is safe
+// for concurrent use.
+type cleanReport struct {
+	models.CleanReport
+	mutex sync.Mutex
+}
+
+func (r *cleanReport) add(objectType string, id int, path string, reason models.CleanReason) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Objects = append(r.Objects, &models.CleanObjectReport{
+		Type:   objectType,
+		ID:     strconv.Itoa(id),
+		Path:   path,
+		Reason: reason,
+	})
+}
+
+func (r *cleanReport) setError(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	errStr := err.Error()
+	r.Error = &errStr
+}
+
+// summary returns the number of objects removed for each reason.
+func (r *cleanReport) summary() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	counts := make(map[models.CleanReason]int)
+	for _, o := range r.Objects {
+		counts[o.Reason]++
+	}
+
+	var ret []string
+	for _, reason := range models.AllCleanReason {
+		if counts[reason] > 0 {
+			ret = append(ret, fmt.Sprintf("%d %s", counts[reason], strings.ToLower(strings.Replace(reason.String(), "_", " ", -1))))
+		}
+	}
+
+	if len(ret) == 0 {
+		return "no objects"
+	}
+
+	return strings.Join(ret, ", ")
+}
+
+// finish completes the report once the clean has finished, and writes it to
+// fn so that it can be retrieved afterwards.
+func (r *cleanReport) finish(fn string) {
+	now := time.Now()
+	r.mutex.Lock()
+	r.FinishedAt = &now
+	r.mutex.Unlock()
+
+	verb := "removed"
+	if r.DryRun {
+		verb = "would remove"
+	}
+	logger.Infof("Clean %s: %s", verb, r.summary())
+
+	if err := saveCleanReport(fn, &r.CleanReport); err != nil {
+		logger.Errorf("error writing clean report to %s: %s", fn, err.Error())
+	}
+}
+
+func saveCleanReport(fn string, report *models.CleanReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fn, data, 0644)
+}
+
+// loadCleanReport returns the clean report saved to the file, or nil if the
+// file does not exist.
+func loadCleanReport(fn string) (*models.CleanReport, error) {
+	data, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret models.CleanReport
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanReportSummary(t *testing.T) {
+	var r cleanReport
+	assert.Equal(t, "no objects", r.summary())
+
+	r.add("scene", 1, "a.mp4", models.CleanReasonFileMissing)
+	r.add("image", 2, "b.jpg", models.CleanReasonExcludedPattern)
+	r.add("scene", 3, "c.mp4", models.CleanReasonFileMissing)
+
+	assert.Equal(t, "2 file missing, 1 excluded pattern", r.summary())
+
+	// tasks without a report don't report what they removed
+	var nilReport *cleanReport
+	nilReport.add("scene", 1, "a.mp4", models.CleanReasonFileMissing)
+}
+
+func TestCleanReportFinish(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-clean-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "clean_report.json")
+
+	report, err := loadCleanReport(fn)
+	assert.Nil(t, err)
+	assert.Nil(t, report)
+
+	r := &cleanReport{
+		CleanReport: models.CleanReport{
+			DryRun: true,
+		},
+	}
+	r.add("gallery", 1, "a.zip", models.CleanReasonEmptyGallery)
+	r.setError(errors.New("failed"))
+	r.finish(fn)
+
+	report, err = loadCleanReport(fn)
+	if !assert.Nil(t, err) || !assert.NotNil(t, report) {
+		return
+	}
+
+	assert.True(t, report.DryRun)
+	assert.NotNil(t, report.FinishedAt)
+	assert.Equal(t, "failed", *report.Error)
+	if assert.Len(t, report.Objects, 1) {
+		assert.Equal(t, "gallery", report.Objects[0].Type)
+		assert.Equal(t, "1", report.Objects[0].ID)
+		assert.Equal(t, "a.zip", report.Objects[0].Path)
+		assert.Equal(t, models.CleanReasonEmptyGallery, report.Objects[0].Reason)
+	}
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -32,6 +34,11 @@ const DefaultMaxSessionAge = 60 * 60 * 1 // 1 hours
 
 const Database = "database"
 
+// BlobsStorage is where new performer, studio, tag and movie images are
+// stored. Images stored in the filesystem are stored in BlobsPath.
+const BlobsStorage = "blobs_storage"
+const BlobsPath = "blobs_path"
+
 const Exclude = "exclude"
 const ImageExclude = "image_exclude"
 
@@ -39,13 +46,19 @@ const VideoExtensions = "video_extensions"
 
 var defaultVideoExtensions = []string{"m4v", "mp4", "mov", "wmv", "avi", "mpg", "mpeg", "rmvb", "rm", "flv", "asf", "mkv", "webm"}
 
+// AudioExtensions are the extensions of audio files, which are scanned as
+// scenes without a video stream
+const AudioExtensions = "audio_extensions"
+
+var defaultAudioExtensions = []string{"mp3", "flac", "m4a", "ogg", "opus", "wav"}
+
 const ImageExtensions = "image_extensions"
 
 var defaultImageExtensions = []string{"png", "jpg", "jpeg", "gif", "webp"}
 
 const GalleryExtensions = "gallery_extensions"
 
-var defaultGalleryExtensions = []string{"zip", "cbz"}
+var defaultGalleryExtensions = []string{"zip", "cbz", "rar", "cbr", "7z", "cb7"}
 
 const CreateGalleriesFromFolders = "create_galleries_from_folders"
 
@@ -62,9 +75,62 @@ const PreviewPreset = "preview_preset"
 const MaxTranscodeSize = "max_transcode_size"
 const MaxStreamingTranscodeSize = "max_streaming_transcode_size"
 
+// HardwareAcceleration is the hardware encoder used for live transcodes and
+// preview generation.
+const HardwareAcceleration = "hardware_acceleration"
+
+// MaxTranscodeProcesses is the maximum number of concurrent live transcodes.
+// MaxGenerateProcesses is the maximum number of concurrent ffmpeg processes
+// run by tasks, including the running live transcodes. Zero is unlimited.
+const MaxTranscodeProcesses = "max_transcode_processes"
+const MaxGenerateProcesses = "max_generate_processes"
+
+// ProbeCacheSize is the maximum number of ffprobe results kept in memory.
+// this should be manually configured only
+const ProbeCacheSize = "probe_cache_size"
+const probeCacheSizeDefault = 5000
+
+// TranscodeProfiles are custom encoder settings. LiveTranscodeProfile and
+// PreviewTranscodeProfile are the names of the profiles used for live
+// transcodes and preview generation.
+const TranscodeProfiles = "transcode_profiles"
+const LiveTranscodeProfile = "live_transcode_profile"
+const PreviewTranscodeProfile = "preview_transcode_profile"
+
 const ParallelTasks = "parallel_tasks"
 const parallelTasksDefault = 1
 
+// The number of workers of each kind of job of the scan and generate tasks.
+// HashWorkers calculate checksums, ProbeWorkers run ffprobe, GenerateWorkers
+// make screenshots, previews, sprites, phashes, markers and transcodes, and
+// ThumbnailWorkers make image thumbnails. Zero uses the parallel tasks.
+const HashWorkers = "hash_workers"
+const ProbeWorkers = "probe_workers"
+const GenerateWorkers = "generate_workers"
+const ThumbnailWorkers = "thumbnail_workers"
+
+// ImportWorkers is the number of objects imported concurrently. Zero uses
+// the number of CPUs.
+const ImportWorkers = "import_workers"
+
+// ThumbnailSizes are the sizes of the largest dimension of the image
+// thumbnails which can be requested. ThumbnailCacheSize is the maximum total
+// size of the thumbnails in MB, above which the least recently used
+// thumbnails are removed. Thumbnails are not removed if it is zero.
+const ThumbnailSizes = "thumbnail_sizes"
+const ThumbnailCacheSize = "thumbnail_cache_size"
+
+// Scheduled backup options. BackupInterval is the number of hours between
+// backups, which are disabled if it is zero. BackupCount is the number of
+// backups kept in BackupPath.
+const BackupInterval = "backup_interval"
+const BackupPath = "backup_path"
+const BackupCount = "backup_count"
+const backupCountDefault = 7
+
+// BackupIncludeExport includes a metadata export in scheduled backups.
+const BackupIncludeExport = "backup_include_export"
+
 const PreviewSegmentDuration = "preview_segment_duration"
 const previewSegmentDurationDefault = 0.75
 
@@ -77,10 +143,46 @@ const previewExcludeStartDefault = "0"
 const PreviewExcludeEnd = "preview_exclude_end"
 const previewExcludeEndDefault = "0"
 
+// PreviewImageQuality is the quality of animated WebP preview images, from 0
+// to 100. Images are encoded losslessly if it is 100.
+const PreviewImageQuality = "preview_image_quality"
+const previewImageQualityDefault = 100
+
+const PreviewImageFps = "preview_image_fps"
+const previewImageFpsDefault = 12
+
+// Sprite layout. If SpriteInterval is positive, a sprite thumbnail is taken
+// every SpriteInterval seconds and SpriteRows is ignored.
+const SpriteRows = "sprite_rows"
+const spriteRowsDefault = 9
+
+const SpriteColumns = "sprite_columns"
+const spriteColumnsDefault = 9
+
+const SpriteWidth = "sprite_width"
+const spriteWidthDefault = 160
+
+const SpriteInterval = "sprite_interval"
+
 const Host = "host"
 const Port = "port"
 const ExternalHost = "external_host"
 
+// URLBasePath is the path prefix under which stash is served, such as
+// /stash, for use behind a reverse proxy.
+const URLBasePath = "url_base_path"
+
+// ACME options. If domains are set, certificates for them are obtained and
+// renewed using ACME, such as from Let's Encrypt, rather than using the
+// SSL certificate files.
+const ACMEDomains = "acme_domains"
+const ACMEEmail = "acme_email"
+const ACMEDirectoryURL = "acme_directory_url"
+
+// ACMEHTTPPort is the port on which to answer ACME HTTP challenges, usually
+// 80. If not set, only TLS challenges on the server port are answered.
+const ACMEHTTPPort = "acme_http_port"
+
 // key used to sign JWT tokens
 const JWTSignKey = "jwt_secret_key"
 
@@ -93,9 +195,42 @@ const ScraperUserAgent = "scraper_user_agent"
 const ScraperCertCheck = "scraper_cert_check"
 const ScraperCDPPath = "scraper_cdp_path"
 
+// ScraperCacheTTL is the number of minutes scraper responses are cached for
+const ScraperCacheTTL = "scraper_cache_ttl"
+const DefaultScraperCacheTTL = 60
+
+// Milliseconds between the start of scraper requests to the same domain,
+// and the maximum random delay added to it
+const ScraperRequestInterval = "scraper_request_interval"
+const DefaultScraperRequestInterval = 1000
+const ScraperRequestJitter = "scraper_request_jitter"
+const DefaultScraperRequestJitter = 500
+
+// Maximum simultaneous scraper requests to the same domain
+const ScraperMaxConcurrency = "scraper_max_concurrency"
+const DefaultScraperMaxConcurrency = 2
+
+// Proxies, headers and cookies of scrapers
+const ScraperSources = "scraper_sources"
+
+// Sources of installable scraper packages
+const ScraperPackageSources = "scraper_package_sources"
+
 // stash-box options
 const StashBoxes = "stash_boxes"
 
+// Webhooks notified of library events
+const Webhooks = "webhooks"
+
+// Sinks notified when jobs finish
+const NotificationSinks = "notification_sinks"
+
+// Passwords of encrypted zip galleries
+const ZipPasswords = "zip_passwords"
+
+// Object storage of generated files
+const ObjectStorage = "object_storage"
+
 // plugin options
 const PluginsPath = "plugins_path"
 
@@ -124,15 +259,60 @@ const CSSEnabled = "cssEnabled"
 const WallPlayback = "wall_playback"
 const SlideshowDelay = "slideshow_delay"
 
+// Interactive options
+const FunscriptOffset = "funscript_offset"
+const HandyKey = "handy_key"
+
 // Logging options
 const LogFile = "logFile"
 const LogOut = "logOut"
 const LogLevel = "logLevel"
 const LogAccess = "logAccess"
 
+// Database profiling options
+const QueryProfiling = "query_profiling"
+const SlowQueryThreshold = "slow_query_threshold"
+const slowQueryThresholdDefault = 100
+
 // File upload options
 const MaxUploadSize = "max_upload_size"
 
+// Bandwidth options, in kilobytes per second
+const MaxSessionBandwidth = "max_session_bandwidth"
+const MaxGlobalBandwidth = "max_global_bandwidth"
+
+// Request throttling options
+const MaxRequestsPerMinute = "max_requests_per_minute"
+const LoginMaxAttempts = "login_max_attempts"
+const loginMaxAttemptsDefault = 5
+
+// LoginLockoutDuration is the duration, in seconds, of the first lockout
+// after too many failed logins. It doubles with each further failure.
+const LoginLockoutDuration = "login_lockout_duration"
+const loginLockoutDurationDefault = 60
+
+// GraphQL query limits. The API key options override the general limits for
+// requests authenticated using the API key.
+const MaxQueryComplexity = "max_query_complexity"
+const maxQueryComplexityDefault = 1000
+const MaxQueryDepth = "max_query_depth"
+const maxQueryDepthDefault = 15
+const APIKeyMaxQueryComplexity = "api_key_max_query_complexity"
+const APIKeyMaxQueryDepth = "api_key_max_query_depth"
+
+// DuplicateNamePolicy is the config key used to determine the behaviour
+// when creating a performer or studio with the name of an existing one.
+const DuplicateNamePolicy = "duplicate_name_policy"
+
+// AutoTagSeparators is the config key for the characters which may separate
+// the words of a name in a path when auto-tagging.
+const AutoTagSeparators = "autotag_separators"
+const autoTagSeparatorsDefault = ".-_ "
+
+// ReadOnly is the config key used to reject all changes to the database.
+// It may only be set in the config file, and requires a restart to apply.
+const ReadOnly = "read_only"
+
 type MissingConfigError struct {
 	missingFields []string
 }
@@ -241,6 +421,27 @@ func (i *Instance) GetDatabasePath() string {
 	return viper.GetString(Database)
 }
 
+// GetBlobsStorage returns where new performer, studio, tag and movie images
+// are stored. Defaults to the database.
+func (i *Instance) GetBlobsStorage() models.BlobsStorageType {
+	ret := models.BlobsStorageType(strings.ToUpper(viper.GetString(BlobsStorage)))
+	if !ret.IsValid() {
+		return models.BlobsStorageTypeDatabase
+	}
+
+	return ret
+}
+
+// GetBlobsPath returns the directory of the images stored in the
+// filesystem. Defaults to the blobs directory alongside the database.
+func (i *Instance) GetBlobsPath() string {
+	if ret := viper.GetString(BlobsPath); ret != "" {
+		return ret
+	}
+
+	return filepath.Join(filepath.Dir(i.GetDatabasePath()), "blobs")
+}
+
 func (i *Instance) GetJWTSignKey() []byte {
 	return []byte(viper.GetString(JWTSignKey))
 }
@@ -273,6 +474,14 @@ func (i *Instance) GetVideoExtensions() []string {
 	return ret
 }
 
+func (i *Instance) GetAudioExtensions() []string {
+	ret := viper.GetStringSlice(AudioExtensions)
+	if ret == nil {
+		ret = defaultAudioExtensions
+	}
+	return ret
+}
+
 func (i *Instance) GetImageExtensions() []string {
 	ret := viper.GetStringSlice(ImageExtensions)
 	if ret == nil {
@@ -348,12 +557,91 @@ func (i *Instance) GetScraperCertCheck() bool {
 	return ret
 }
 
+// GetScraperCacheTTL returns how long scraper responses are cached for.
+// Responses are not cached if it is zero.
+func (i *Instance) GetScraperCacheTTL() time.Duration {
+	viper.SetDefault(ScraperCacheTTL, DefaultScraperCacheTTL)
+	return time.Duration(viper.GetInt(ScraperCacheTTL)) * time.Minute
+}
+
+// GetScraperRequestInterval returns the minimum time between the start of
+// scraper requests to the same domain.
+func (i *Instance) GetScraperRequestInterval() time.Duration {
+	viper.SetDefault(ScraperRequestInterval, DefaultScraperRequestInterval)
+	return time.Duration(viper.GetInt(ScraperRequestInterval)) * time.Millisecond
+}
+
+// GetScraperRequestJitter returns the maximum random delay added to the
+// scraper request interval.
+func (i *Instance) GetScraperRequestJitter() time.Duration {
+	viper.SetDefault(ScraperRequestJitter, DefaultScraperRequestJitter)
+	return time.Duration(viper.GetInt(ScraperRequestJitter)) * time.Millisecond
+}
+
+// GetScraperMaxConcurrency returns the maximum number of simultaneous
+// scraper requests to the same domain. Returns 0 if it is not limited.
+func (i *Instance) GetScraperMaxConcurrency() int {
+	viper.SetDefault(ScraperMaxConcurrency, DefaultScraperMaxConcurrency)
+	return viper.GetInt(ScraperMaxConcurrency)
+}
+
+func (i *Instance) GetScraperSources() []*models.ScraperSource {
+	var sources []*models.ScraperSource
+	viper.UnmarshalKey(ScraperSources, &sources)
+	return sources
+}
+
+func (i *Instance) GetScraperPackageSources() []*models.ScraperPackageSource {
+	var sources []*models.ScraperPackageSource
+	viper.UnmarshalKey(ScraperPackageSources, &sources)
+	return sources
+}
+
 func (i *Instance) GetStashBoxes() []*models.StashBox {
 	var boxes []*models.StashBox
 	viper.UnmarshalKey(StashBoxes, &boxes)
 	return boxes
 }
 
+func (i *Instance) GetWebhooks() []*models.Webhook {
+	var webhooks []*models.Webhook
+	viper.UnmarshalKey(Webhooks, &webhooks)
+	return webhooks
+}
+
+func (i *Instance) GetNotificationSinks() []*models.NotificationSink {
+	var sinks []*models.NotificationSink
+	viper.UnmarshalKey(NotificationSinks, &sinks)
+	return sinks
+}
+
+func (i *Instance) GetZipPasswords() []*models.ZipPassword {
+	var passwords []*models.ZipPassword
+	viper.UnmarshalKey(ZipPasswords, &passwords)
+	return passwords
+}
+
+// GetZipPasswordsForPath returns the passwords of the zip file at path, and
+// of the directories containing it.
+func (i *Instance) GetZipPasswordsForPath(path string) []string {
+	var ret []string
+	for _, p := range i.GetZipPasswords() {
+		if utils.IsPathInDir(p.Path, path) {
+			ret = append(ret, p.Password)
+		}
+	}
+
+	return ret
+}
+
+// GetObjectStorage returns the object storage of generated files. Object
+// storage is disabled if the endpoint is empty.
+func (i *Instance) GetObjectStorage() *models.ObjectStorage {
+	var ret models.ObjectStorage
+	viper.UnmarshalKey(ObjectStorage, &ret)
+	return &ret
+}
+
 func (i *Instance) GetDefaultPluginsPath() string {
 	// default to the same directory as the config file
 	fn := filepath.Join(i.GetConfigPath(), "plugins")
@@ -377,6 +665,46 @@ func (i *Instance) GetExternalHost() string {
 	return viper.GetString(ExternalHost)
 }
 
+// GetACMEDomains returns the domains for which certificates are obtained
+// using ACME. ACME is disabled if empty.
+func (i *Instance) GetACMEDomains() []string {
+	return viper.GetStringSlice(ACMEDomains)
+}
+
+// GetACMEEmail returns the contact email address given to the ACME
+// certificate authority. May be empty.
+func (i *Instance) GetACMEEmail() string {
+	return viper.GetString(ACMEEmail)
+}
+
+// GetACMEDirectoryURL returns the directory URL of the ACME certificate
+// authority. Let's Encrypt is used if empty.
+func (i *Instance) GetACMEDirectoryURL() string {
+	return viper.GetString(ACMEDirectoryURL)
+}
+
+func (i *Instance) GetACMEHTTPPort() int {
+	return viper.GetInt(ACMEHTTPPort)
+}
+
+// GetACMECachePath returns the directory in which ACME account keys and
+// certificates are stored.
+func (i *Instance) GetACMECachePath() string {
+	return filepath.Join(i.GetConfigPath(), "acme")
+}
+
+// GetURLBasePath returns the path prefix under which stash is served. The
+// returned path starts with a slash and has no trailing slash, or is empty
+// if stash is served from the root.
+func (i *Instance) GetURLBasePath() string {
+	ret := strings.Trim(strings.TrimSpace(viper.GetString(URLBasePath)), "/")
+	if ret == "" {
+		return ""
+	}
+
+	return "/" + ret
+}
+
 // GetPreviewSegmentDuration returns the duration of a single segment in a
 // scene preview file, in seconds.
 func (i *Instance) GetPreviewSegmentDuration() float64 {
@@ -397,6 +725,123 @@ func (i *Instance) GetParallelTasksWithAutoDetection() int {
 	return parallelTasks
 }
 
+// GetHashWorkers returns the number of files hashed concurrently, as set in
+// the configuration. Zero means the number of parallel tasks.
+func (i *Instance) GetHashWorkers() int {
+	return viper.GetInt(HashWorkers)
+}
+
+// GetProbeWorkers returns the number of files probed by ffprobe
+// concurrently, as set in the configuration. Zero means the number of
+// parallel tasks.
+func (i *Instance) GetProbeWorkers() int {
+	return viper.GetInt(ProbeWorkers)
+}
+
+// GetGenerateWorkers returns the number of generated files made
+// concurrently, as set in the configuration. Zero means the number of
+// parallel tasks.
+func (i *Instance) GetGenerateWorkers() int {
+	return viper.GetInt(GenerateWorkers)
+}
+
+// GetThumbnailWorkers returns the number of image thumbnails made
+// concurrently, as set in the configuration. Zero means the number of
+// parallel tasks.
+func (i *Instance) GetThumbnailWorkers() int {
+	return viper.GetInt(ThumbnailWorkers)
+}
+
+func (i *Instance) GetHashWorkersWithAutoDetection() int {
+	return i.workersWithAutoDetection(HashWorkers)
+}
+
+func (i *Instance) GetProbeWorkersWithAutoDetection() int {
+	return i.workersWithAutoDetection(ProbeWorkers)
+}
+
+func (i *Instance) GetGenerateWorkersWithAutoDetection() int {
+	return i.workersWithAutoDetection(GenerateWorkers)
+}
+
+func (i *Instance) GetThumbnailWorkersWithAutoDetection() int {
+	return i.workersWithAutoDetection(ThumbnailWorkers)
+}
+
+func (i *Instance) workersWithAutoDetection(key string) int {
+	workers := viper.GetInt(key)
+	if workers <= 0 {
+		workers = i.GetParallelTasksWithAutoDetection()
+	}
+	return workers
+}
+
+// GetImportWorkers returns the number of objects of the same type that
+// should be imported concurrently, as set in the configuration. Zero means
+// the number of CPUs.
+func (i *Instance) GetImportWorkers() int {
+	return viper.GetInt(ImportWorkers)
+}
+
+func (i *Instance) GetImportWorkersWithAutoDetection() int {
+	workers := viper.GetInt(ImportWorkers)
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return workers
+}
+
+// GetThumbnailSizes returns the sizes of the image thumbnails which can be
+// requested, in ascending order. Defaults to the size of the thumbnails shown
+// in the interface.
+func (i *Instance) GetThumbnailSizes() []int {
+	var ret []int
+	for _, s := range viper.GetIntSlice(ThumbnailSizes) {
+		if s > 0 {
+			ret = append(ret, s)
+		}
+	}
+
+	if len(ret) == 0 {
+		return []int{models.DefaultGthumbWidth}
+	}
+
+	sort.Ints(ret)
+	return ret
+}
+
+// GetThumbnailCacheSize returns the maximum total size of the image
+// thumbnails in bytes. Returns zero if the size is not limited.
+func (i *Instance) GetThumbnailCacheSize() int64 {
+	return int64(viper.GetInt(ThumbnailCacheSize)) * 1024 * 1024
+}
+
+// GetBackupInterval returns the time between scheduled backups. Returns
+// zero if scheduled backups are disabled.
+func (i *Instance) GetBackupInterval() time.Duration {
+	return time.Duration(viper.GetInt(BackupInterval)) * time.Hour
+}
+
+// GetBackupPath returns the directory in which scheduled backups are
+// stored. Defaults to the backups directory alongside the database.
+func (i *Instance) GetBackupPath() string {
+	if ret := viper.GetString(BackupPath); ret != "" {
+		return ret
+	}
+
+	return filepath.Join(filepath.Dir(i.GetDatabasePath()), "backups")
+}
+
+// GetBackupCount returns the number of scheduled backups to keep.
+func (i *Instance) GetBackupCount() int {
+	viper.SetDefault(BackupCount, backupCountDefault)
+	return viper.GetInt(BackupCount)
+}
+
+func (i *Instance) GetBackupIncludeExport() bool {
+	return viper.GetBool(BackupIncludeExport)
+}
+
 // GetPreviewSegments returns the amount of segments in a scene preview file.
 func (i *Instance) GetPreviewSegments() int {
 	return viper.GetInt(PreviewSegments)
@@ -434,6 +879,39 @@ func (i *Instance) GetPreviewPreset() models.PreviewPreset {
 	return models.PreviewPreset(ret)
 }
 
+// GetPreviewImageQuality returns the quality of animated WebP preview images.
+func (i *Instance) GetPreviewImageQuality() int {
+	return viper.GetInt(PreviewImageQuality)
+}
+
+// GetPreviewImageFps returns the frame rate of animated WebP preview images.
+func (i *Instance) GetPreviewImageFps() int {
+	return viper.GetInt(PreviewImageFps)
+}
+
+// GetSpriteRows returns the number of rows of sprite images, if the sprite
+// interval is not set.
+func (i *Instance) GetSpriteRows() int {
+	return viper.GetInt(SpriteRows)
+}
+
+// GetSpriteColumns returns the number of columns of sprite images.
+func (i *Instance) GetSpriteColumns() int {
+	return viper.GetInt(SpriteColumns)
+}
+
+// GetSpriteWidth returns the width of sprite thumbnails.
+func (i *Instance) GetSpriteWidth() int {
+	return viper.GetInt(SpriteWidth)
+}
+
+// GetSpriteInterval returns the number of seconds between sprite
+// thumbnails. Zero spreads rows by columns thumbnails evenly through the
+// video.
+func (i *Instance) GetSpriteInterval() float64 {
+	return viper.GetFloat64(SpriteInterval)
+}
+
 func (i *Instance) GetMaxTranscodeSize() models.StreamingResolutionEnum {
 	ret := viper.GetString(MaxTranscodeSize)
 
@@ -445,6 +923,92 @@ func (i *Instance) GetMaxTranscodeSize() models.StreamingResolutionEnum {
 	return models.StreamingResolutionEnum(ret)
 }
 
+// GetMaxTranscodeProcesses returns the maximum number of concurrent live
+// transcodes. Zero is unlimited.
+func (i *Instance) GetMaxTranscodeProcesses() int {
+	return viper.GetInt(MaxTranscodeProcesses)
+}
+
+// GetMaxGenerateProcesses returns the maximum number of concurrent ffmpeg
+// processes run by tasks, such as generating previews. Running live
+// transcodes count towards the limit, so that tasks yield to playback. Zero
+// is unlimited.
+func (i *Instance) GetMaxGenerateProcesses() int {
+	return viper.GetInt(MaxGenerateProcesses)
+}
+
+// GetProbeCacheSize returns the maximum number of ffprobe results kept in
+// memory. Results are not cached if it is zero.
+func (i *Instance) GetProbeCacheSize() int {
+	viper.SetDefault(ProbeCacheSize, probeCacheSizeDefault)
+	return viper.GetInt(ProbeCacheSize)
+}
+
+// GetHardwareAcceleration returns the hardware encoder used for live
+// transcodes and preview generation. Defaults to none.
+func (i *Instance) GetHardwareAcceleration() models.HardwareAcceleration {
+	ret := models.HardwareAcceleration(viper.GetString(HardwareAcceleration))
+	if !ret.IsValid() {
+		return models.HardwareAccelerationNone
+	}
+
+	return ret
+}
+
+func (i *Instance) GetTranscodeProfiles() []*models.TranscodeProfile {
+	var profiles []*models.TranscodeProfile
+	viper.UnmarshalKey(TranscodeProfiles, &profiles)
+	return profiles
+}
+
+// GetTranscodeProfile returns the transcode profile with the name, or nil
+// if there is no such profile.
+func (i *Instance) GetTranscodeProfile(name string) *models.TranscodeProfile {
+	if name == "" {
+		return nil
+	}
+
+	for _, p := range i.GetTranscodeProfiles() {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// GetLiveTranscodeProfile returns the transcode profile used for live
+// transcodes, or nil if the default settings are used.
+func (i *Instance) GetLiveTranscodeProfile() *models.TranscodeProfile {
+	return i.GetTranscodeProfile(viper.GetString(LiveTranscodeProfile))
+}
+
+// GetPreviewTranscodeProfile returns the transcode profile used for preview
+// generation, or nil if the default settings are used.
+func (i *Instance) GetPreviewTranscodeProfile() *models.TranscodeProfile {
+	return i.GetTranscodeProfile(viper.GetString(PreviewTranscodeProfile))
+}
+
+// ValidateTranscodeProfiles returns an error if a profile has no name or
+// the same name as another profile.
+func (i *Instance) ValidateTranscodeProfiles(profiles []*models.TranscodeProfileInput) error {
+	names := make(map[string]bool)
+	for _, p := range profiles {
+		if p.Name == "" {
+			return errors.New("Transcode profile name cannot be blank")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("Duplicate transcode profile name: %s", p.Name)
+		}
+		if p.MaxResolution != nil && !p.MaxResolution.IsValid() {
+			return fmt.Errorf("Invalid maximum resolution for transcode profile %s: %s", p.Name, *p.MaxResolution)
+		}
+		names[p.Name] = true
+	}
+
+	return nil
+}
+
 func (i *Instance) GetMaxStreamingTranscodeSize() models.StreamingResolutionEnum {
 	ret := viper.GetString(MaxStreamingTranscodeSize)
 
@@ -514,6 +1078,8 @@ func (i *Instance) ValidateStashBoxes(boxes []*models.StashBoxInput) error {
 		return errors.New("Failure to generate regular expression")
 	}
 
+	endpoints := make(map[string]bool)
+	names := make(map[string]bool)
 	for _, box := range boxes {
 		if box.APIKey == "" {
 			return errors.New("Stash-box API Key cannot be blank")
@@ -523,7 +1089,14 @@ func (i *Instance) ValidateStashBoxes(boxes []*models.StashBoxInput) error {
 			return errors.New("Stash-box Endpoint is invalid")
 		} else if isMulti && box.Name == "" {
 			return errors.New("Stash-box Name cannot be blank")
+		} else if endpoints[box.Endpoint] {
+			return errors.New("Stash-box Endpoint is already configured")
+		} else if box.Name != "" && names[box.Name] {
+			return errors.New("Stash-box Name must be unique")
 		}
+
+		endpoints[box.Endpoint] = true
+		names[box.Name] = true
 	}
 	return nil
 }
@@ -588,6 +1161,18 @@ func (i *Instance) GetSlideshowDelay() int {
 	return viper.GetInt(SlideshowDelay)
 }
 
+// GetFunscriptOffset returns the offset in milliseconds which is added to the
+// times of funscript actions, to correct the latency of interactive devices.
+func (i *Instance) GetFunscriptOffset() int {
+	return viper.GetInt(FunscriptOffset)
+}
+
+// GetHandyKey returns the connection key of the Handy device which plays
+// the funscripts of interactive scenes.
+func (i *Instance) GetHandyKey() string {
+	return viper.GetString(HandyKey)
+}
+
 func (i *Instance) GetCSSPath() string {
 	// use custom.css in the same directory as the config file
 	configFileUsed := viper.ConfigFileUsed()
@@ -669,6 +1254,20 @@ func (i *Instance) GetLogAccess() bool {
 	return ret
 }
 
+// GetQueryProfiling returns true if aggregate statistics should be collected
+// for database queries. Defaults to false.
+func (i *Instance) GetQueryProfiling() bool {
+	return viper.GetBool(QueryProfiling)
+}
+
+// GetSlowQueryThreshold returns the duration, in milliseconds, after which a
+// profiled database query is logged as slow. Zero disables slow query
+// logging.
+func (i *Instance) GetSlowQueryThreshold() int {
+	viper.SetDefault(SlowQueryThreshold, slowQueryThresholdDefault)
+	return viper.GetInt(SlowQueryThreshold)
+}
+
 // Max allowed graphql upload size in megabytes
 func (i *Instance) GetMaxUploadSize() int64 {
 	ret := int64(1024)
@@ -678,6 +1277,110 @@ func (i *Instance) GetMaxUploadSize() int64 {
 	return ret << 20
 }
 
+// GetMaxSessionBandwidth returns the maximum rate, in bytes per second, at
+// which streams, images and downloads are served to a single session.
+// Zero means unlimited.
+func (i *Instance) GetMaxSessionBandwidth() int64 {
+	return viper.GetInt64(MaxSessionBandwidth) << 10
+}
+
+// GetMaxGlobalBandwidth returns the maximum combined rate, in bytes per
+// second, at which streams, images and downloads are served to all sessions.
+// Zero means unlimited.
+func (i *Instance) GetMaxGlobalBandwidth() int64 {
+	return viper.GetInt64(MaxGlobalBandwidth) << 10
+}
+
+// GetMaxRequestsPerMinute returns the maximum number of API requests
+// accepted from a single client per minute. Zero means unlimited.
+func (i *Instance) GetMaxRequestsPerMinute() int {
+	return viper.GetInt(MaxRequestsPerMinute)
+}
+
+// GetLoginMaxAttempts returns the number of consecutive failed logins
+// allowed from a single client before it is locked out. Zero disables
+// login throttling.
+func (i *Instance) GetLoginMaxAttempts() int {
+	viper.SetDefault(LoginMaxAttempts, loginMaxAttemptsDefault)
+	return viper.GetInt(LoginMaxAttempts)
+}
+
+// GetLoginLockoutDuration returns the duration of the first lockout after
+// too many failed logins.
+func (i *Instance) GetLoginLockoutDuration() time.Duration {
+	viper.SetDefault(LoginLockoutDuration, loginLockoutDurationDefault)
+	return time.Duration(viper.GetInt(LoginLockoutDuration)) * time.Second
+}
+
+// GetMaxQueryComplexity returns the maximum complexity of GraphQL
+// operations. Zero means unlimited.
+func (i *Instance) GetMaxQueryComplexity() int {
+	viper.SetDefault(MaxQueryComplexity, maxQueryComplexityDefault)
+	return viper.GetInt(MaxQueryComplexity)
+}
+
+// GetMaxQueryDepth returns the maximum selection depth of GraphQL
+// operations. Zero means unlimited.
+func (i *Instance) GetMaxQueryDepth() int {
+	viper.SetDefault(MaxQueryDepth, maxQueryDepthDefault)
+	return viper.GetInt(MaxQueryDepth)
+}
+
+// GetAPIKeyMaxQueryComplexity returns the maximum complexity of GraphQL
+// operations made using the API key. Returns nil if the general limit
+// applies, which is the case if it is not set or is negative.
+func (i *Instance) GetAPIKeyMaxQueryComplexity() *int {
+	return getOptionalLimit(APIKeyMaxQueryComplexity)
+}
+
+// GetAPIKeyMaxQueryDepth returns the maximum selection depth of GraphQL
+// operations made using the API key. Returns nil if the general limit
+// applies, which is the case if it is not set or is negative.
+func (i *Instance) GetAPIKeyMaxQueryDepth() *int {
+	return getOptionalLimit(APIKeyMaxQueryDepth)
+}
+
+func getOptionalLimit(key string) *int {
+	if !viper.IsSet(key) {
+		return nil
+	}
+
+	ret := viper.GetInt(key)
+	if ret < 0 {
+		return nil
+	}
+
+	return &ret
+}
+
+// GetDuplicateNamePolicy returns the behaviour when creating a performer or
+// studio with the same name as an existing one. Defaults to warn.
+func (i *Instance) GetDuplicateNamePolicy() models.DuplicateNamePolicy {
+	ret := models.DuplicateNamePolicy(viper.GetString(DuplicateNamePolicy))
+	if !ret.IsValid() {
+		return models.DuplicateNamePolicyWarn
+	}
+
+	return ret
+}
+
+// GetAutoTagSeparators returns the characters which may separate the words
+// of a name in a path when auto-tagging. Defaults to '.', '-', '_' and space.
+func (i *Instance) GetAutoTagSeparators() string {
+	ret := viper.GetString(AutoTagSeparators)
+	if ret == "" {
+		return autoTagSeparatorsDefault
+	}
+
+	return ret
+}
+
+// IsReadOnly returns true if the server should reject all mutations and
+// changes to the database.
+func (i *Instance) IsReadOnly() bool {
+	return viper.GetBool(ReadOnly)
+}
+
 func (i *Instance) Validate() error {
 	mandatoryPaths := []string{
 		Database,
@@ -707,6 +1410,11 @@ func (i *Instance) setDefaultValues() error {
 	viper.SetDefault(PreviewSegments, previewSegmentsDefault)
 	viper.SetDefault(PreviewExcludeStart, previewExcludeStartDefault)
 	viper.SetDefault(PreviewExcludeEnd, previewExcludeEndDefault)
+	viper.SetDefault(PreviewImageQuality, previewImageQualityDefault)
+	viper.SetDefault(PreviewImageFps, previewImageFpsDefault)
+	viper.SetDefault(SpriteRows, spriteRowsDefault)
+	viper.SetDefault(SpriteColumns, spriteColumnsDefault)
+	viper.SetDefault(SpriteWidth, spriteWidthDefault)
 
 	viper.SetDefault(Database, i.GetDefaultDatabaseFilePath())
 
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStashBoxes(t *testing.T) {
+	const (
+		endpoint      = "https://stashdb.org/graphql"
+		otherEndpoint = "https://other.org/graphql"
+		apiKey        = "apikey"
+	)
+
+	tests := []struct {
+		name  string
+		boxes []*models.StashBoxInput
+		valid bool
+	}{
+		{
+			"single unnamed",
+			[]*models.StashBoxInput{
+				{Endpoint: endpoint, APIKey: apiKey},
+			},
+			true,
+		},
+		{
+			"multiple named",
+			[]*models.StashBoxInput{
+				{Endpoint: endpoint, APIKey: apiKey, Name: "stashdb"},
+				{Endpoint: otherEndpoint, APIKey: apiKey, Name: "other"},
+			},
+			true,
+		},
+		{
+			"blank api key",
+			[]*models.StashBoxInput{
+				{Endpoint: endpoint},
+			},
+			false,
+		},
+		{
+			"invalid endpoint",
+			[]*models.StashBoxInput{
+				{Endpoint: "stashdb.org", APIKey: apiKey},
+			},
+			false,
+		},
+		{
+			"multiple unnamed",
+			[]*models.StashBoxInput{
+				{Endpoint: endpoint, APIKey: apiKey, Name: "stashdb"},
+				{Endpoint: otherEndpoint, APIKey: apiKey},
+			},
+			false,
+		},
+		{
+			"duplicate endpoint",
+			[]*models.StashBoxInput{
+				{Endpoint: endpoint, APIKey: apiKey, Name: "stashdb"},
+				{Endpoint: endpoint, APIKey: apiKey, Name: "other"},
+			},
+			false,
+		},
+		{
+			"duplicate name",
+			[]*models.StashBoxInput{
+				{Endpoint: endpoint, APIKey: apiKey, Name: "stashdb"},
+				{Endpoint: otherEndpoint, APIKey: apiKey, Name: "stashdb"},
+			},
+			false,
+		},
+	}
+
+	i := GetInstance()
+	for _, tt := range tests {
+		err := i.ValidateStashBoxes(tt.boxes)
+		assert.Equal(t, tt.valid, err == nil, tt.name)
+	}
+}
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"io"
 	"net/http"
 	"os"
 	"sync"
@@ -17,7 +18,10 @@ type DownloadStore struct {
 }
 
 type storeFile struct {
-	path        string
+	path string
+	// stream, if set, generates the download as it is served in place of
+	// the file at path
+	stream      func(w io.Writer) error
 	contentType string
 	keep        bool
 	wg          sync.WaitGroup
@@ -31,6 +35,23 @@ func NewDownloadStore() *DownloadStore {
 }
 
 func (s *DownloadStore) RegisterFile(fp string, contentType string, keep bool) string {
+	return s.register(&storeFile{
+		path:        fp,
+		contentType: contentType,
+		keep:        keep,
+	})
+}
+
+// RegisterStream registers a download which is generated by fn as it is
+// served. Streams may only be downloaded once.
+func (s *DownloadStore) RegisterStream(fn func(w io.Writer) error, contentType string) string {
+	return s.register(&storeFile{
+		stream:      fn,
+		contentType: contentType,
+	})
+}
+
+func (s *DownloadStore) register(f *storeFile) string {
 	const keyLength = 4
 	const attempts = 100
 
@@ -47,11 +68,7 @@ func (s *DownloadStore) RegisterFile(fp string, contentType string, keep bool) s
 		a = a + 1
 	}
 
-	s.m[hash] = &storeFile{
-		path:        fp,
-		contentType: contentType,
-		keep:        keep,
-	}
+	s.m[hash] = f
 	s.mutex.Unlock()
 
 	return hash
@@ -67,6 +84,14 @@ func (s *DownloadStore) Serve(hash string, w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if f.stream != nil {
+		delete(s.m, hash)
+		s.mutex.Unlock()
+
+		s.serveStream(f, w, r)
+		return
+	}
+
 	if !f.keep {
 		s.waitAndRemoveFile(hash, &w, r)
 	}
@@ -79,6 +104,34 @@ func (s *DownloadStore) Serve(hash string, w http.ResponseWriter, r *http.Reques
 	http.ServeFile(w, r, f.path)
 }
 
+func (s *DownloadStore) serveStream(f *storeFile, w http.ResponseWriter, r *http.Request) {
+	if f.contentType != "" {
+		w.Header().Add("Content-Type", f.contentType)
+	}
+
+	cw := &countingWriter{w: w}
+	if err := f.stream(cw); err != nil {
+		logger.Errorf("error streaming download: %s", err.Error())
+
+		// the status can only be set if nothing has been written
+		if cw.n == 0 {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func (s *DownloadStore) waitAndRemoveFile(hash string, w *http.ResponseWriter, r *http.Request) {
 	f := s.m[hash]
 	notify := r.Context().Done()
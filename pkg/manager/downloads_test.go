@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadStoreServeStream(t *testing.T) {
+	s := NewDownloadStore()
+
+	calls := 0
+	hash := s.RegisterStream(func(w io.Writer) error {
+		calls++
+		_, err := w.Write([]byte("streamed"))
+		return err
+	}, "application/zip")
+
+	rec := httptest.NewRecorder()
+	s.Serve(hash, rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "streamed", rec.Body.String())
+
+	// streams may only be downloaded once
+	rec = httptest.NewRecorder()
+	s.Serve(hash, rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDownloadStoreServeStreamError(t *testing.T) {
+	s := NewDownloadStore()
+
+	hash := s.RegisterStream(func(w io.Writer) error {
+		return errors.New("task already running")
+	}, "")
+
+	rec := httptest.NewRecorder()
+	s.Serve(hash, rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
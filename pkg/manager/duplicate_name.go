@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/studio"
+)
+
+// DuplicateNameError is returned when creating an object with the same name
+// as existing objects while the duplicate name policy is set to reject.
+type DuplicateNameError struct {
+	ObjectType string
+	Name       string
+	IDs        []int
+}
+
+func (e DuplicateNameError) Error() string {
+	return fmt.Sprintf("%s with name %q already exists: %v", e.ObjectType, e.Name, e.IDs)
+}
+
+// Extensions returns the conflicting IDs to graphql clients, so that they
+// may offer to merge with the existing objects instead.
+func (e DuplicateNameError) Extensions() map[string]interface{} {
+	var ids []string
+	for _, id := range e.IDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	return map[string]interface{}{
+		"code":            "DUPLICATE_NAME",
+		"conflicting_ids": ids,
+	}
+}
+
+func applyDuplicateNamePolicy(objectType string, name string, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	switch config.GetInstance().GetDuplicateNamePolicy() {
+	case models.DuplicateNamePolicyReject:
+		return DuplicateNameError{
+			ObjectType: objectType,
+			Name:       name,
+			IDs:        ids,
+		}
+	case models.DuplicateNamePolicyWarn:
+		logger.Warnf("Creating %s %q with the same name as existing %ss: %v", objectType, name, objectType, ids)
+	}
+
+	return nil
+}
+
+// ValidatePerformerName applies the duplicate name policy to a performer
+// about to be created with the provided name.
+func ValidatePerformerName(r models.PerformerReader, name string) error {
+	if config.GetInstance().GetDuplicateNamePolicy() == models.DuplicateNamePolicyAllow {
+		return nil
+	}
+
+	ids, err := performer.FindDuplicates(r, name)
+	if err != nil {
+		return err
+	}
+
+	return applyDuplicateNamePolicy("performer", name, ids)
+}
+
+// ValidateStudioName applies the duplicate name policy to a studio about to
+// be created with the provided name.
+func ValidateStudioName(r models.StudioReader, name string) error {
+	if config.GetInstance().GetDuplicateNamePolicy() == models.DuplicateNamePolicyAllow {
+		return nil
+	}
+
+	ids, err := studio.FindDuplicates(r, name)
+	if err != nil {
+		return err
+	}
+
+	return applyDuplicateNamePolicy("studio", name, ids)
+}
@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// maximum number of entity change events buffered per subscriber. Further
+// events are dropped until the subscriber catches up.
+const entityChangeBufferSize = 100
+
+type subscribers struct {
+	status        map[chan struct{}]bool
+	entityChanges map[chan *models.EntityChangedEvent]bool
+	mutex         sync.Mutex
+}
+
+var subs = &subscribers{
+	status:        make(map[chan struct{}]bool),
+	entityChanges: make(map[chan *models.EntityChangedEvent]bool),
+}
+
+// SubscribeToStatus returns a channel which receives a value whenever the
+// task status changes, until ctx is done. Notifications are coalesced if the
+// subscriber has not yet received the previous one, so subscribers should
+// read the current status on each notification.
+func SubscribeToStatus(ctx context.Context) <-chan struct{} {
+	ret := make(chan struct{}, 1)
+
+	subs.mutex.Lock()
+	subs.status[ret] = true
+	subs.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		subs.mutex.Lock()
+		delete(subs.status, ret)
+		close(ret)
+		subs.mutex.Unlock()
+	}()
+
+	return ret
+}
+
+func notifyStatusSubscribers() {
+	subs.mutex.Lock()
+	defer subs.mutex.Unlock()
+
+	for c := range subs.status {
+		// don't block if a notification is already pending
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SubscribeToEntityChanges returns a channel which receives the changes
+// made to the library, until ctx is done.
+func SubscribeToEntityChanges(ctx context.Context) <-chan *models.EntityChangedEvent {
+	ret := make(chan *models.EntityChangedEvent, entityChangeBufferSize)
+
+	subs.mutex.Lock()
+	subs.entityChanges[ret] = true
+	subs.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		subs.mutex.Lock()
+		delete(subs.entityChanges, ret)
+		close(ret)
+		subs.mutex.Unlock()
+	}()
+
+	return ret
+}
+
+func publishEntityChanges(events []*models.EntityChangedEvent) {
+	subs.mutex.Lock()
+	defer subs.mutex.Unlock()
+
+	for c := range subs.entityChanges {
+		for _, e := range events {
+			// don't block waiting for slow subscribers
+			select {
+			case c <- e:
+			default:
+			}
+		}
+	}
+}
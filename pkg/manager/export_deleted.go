@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// deletedObjectChecksum returns the checksum used as the JSON filename of
+// the deleted object.
+func (t *ExportTask) deletedObjectChecksum(o *models.DeletedObject) string {
+	switch o.Type {
+	case models.DeletedObjectTypeScene:
+		if t.fileNamingAlgorithm == models.HashAlgorithmOshash {
+			return o.OSHash.String
+		}
+	case models.DeletedObjectTypeTag:
+		// tag checksums are generated from the name
+		return utils.MD5FromString(o.Name.String)
+	}
+
+	return o.Checksum.String
+}
+
+// ExportDeleted adds the objects deleted since the time of an incremental
+// export to the mappings. Only the object types selected by all are
+// included. Objects which have been recreated since they were deleted are
+// included in the export, and so are omitted.
+func (t *ExportTask) ExportDeleted(repo models.ReaderRepository) {
+	deleted, err := repo.DeletedObject().FindSince(*t.since)
+	if err != nil {
+		logger.Errorf("[deleted] failed to fetch deleted objects: %s", err.Error())
+		return
+	}
+
+	types := map[string]struct {
+		spec     *exportSpec
+		mappings []jsonschema.PathNameMapping
+	}{
+		models.DeletedObjectTypeScene:     {t.scenes, t.Mappings.Scenes},
+		models.DeletedObjectTypeImage:     {t.images, t.Mappings.Images},
+		models.DeletedObjectTypeGallery:   {t.galleries, t.Mappings.Galleries},
+		models.DeletedObjectTypePerformer: {t.performers, t.Mappings.Performers},
+		models.DeletedObjectTypeStudio:    {t.studios, t.Mappings.Studios},
+		models.DeletedObjectTypeMovie:     {t.movies, t.Mappings.Movies},
+		models.DeletedObjectTypeTag:       {t.tags, t.Mappings.Tags},
+	}
+
+	exported := make(map[string]bool)
+	for objectType, v := range types {
+		for _, m := range v.mappings {
+			exported[objectType+"/"+m.Checksum] = true
+		}
+	}
+
+	// objects may be deleted more than once if they were recreated in the
+	// meantime. Only the latest deletion is kept.
+	indexes := make(map[string]int)
+	for _, o := range deleted {
+		v, found := types[o.Type]
+		if !found || !(t.full || (v.spec != nil && v.spec.all)) {
+			continue
+		}
+
+		checksum := t.deletedObjectChecksum(o)
+		key := o.Type + "/" + checksum
+		if checksum == "" || exported[key] {
+			continue
+		}
+
+		deletedJSON := jsonschema.DeletedObject{
+			Type:      o.Type,
+			Checksum:  checksum,
+			Name:      o.Name.String,
+			DeletedAt: models.JSONTime{Time: o.DeletedAt.Timestamp},
+		}
+
+		if i, found := indexes[key]; found {
+			t.Mappings.Deleted[i] = deletedJSON
+			continue
+		}
+
+		indexes[key] = len(t.Mappings.Deleted)
+		t.Mappings.Deleted = append(t.Mappings.Deleted, deletedJSON)
+	}
+
+	logger.Infof("[deleted] %d deleted objects exported", len(t.Mappings.Deleted))
+}
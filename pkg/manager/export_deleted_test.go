@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTaskExportDeleted(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	deletedAt := models.SQLiteTimestamp{Timestamp: since.Add(time.Hour)}
+	laterDeletedAt := models.SQLiteTimestamp{Timestamp: since.Add(2 * time.Hour)}
+
+	validString := func(s string) sql.NullString {
+		return sql.NullString{String: s, Valid: true}
+	}
+
+	deleted := []*models.DeletedObject{
+		{Type: models.DeletedObjectTypeScene, Checksum: validString("sceneMD5"), OSHash: validString("sceneOSHash"), DeletedAt: deletedAt},
+		// scenes without an oshash are omitted
+		{Type: models.DeletedObjectTypeScene, Checksum: validString("noOSHash"), DeletedAt: deletedAt},
+		// recreated scene
+		{Type: models.DeletedObjectTypeScene, OSHash: validString("recreated"), DeletedAt: deletedAt},
+		{Type: models.DeletedObjectTypeTag, Name: validString("tag"), DeletedAt: deletedAt},
+		{Type: models.DeletedObjectTypeTag, Name: validString("tag"), DeletedAt: laterDeletedAt},
+		// performers are not selected
+		{Type: models.DeletedObjectTypePerformer, Checksum: validString("performer"), Name: validString("performer"), DeletedAt: deletedAt},
+	}
+
+	txnManager := mocks.NewTransactionManager()
+	txnManager.DeletedObject().(*mocks.DeletedObjectReader).On("FindSince", since).Return(deleted, nil).Once()
+
+	task := &ExportTask{
+		fileNamingAlgorithm: models.HashAlgorithmOshash,
+		scenes:              &exportSpec{all: true},
+		tags:                &exportSpec{all: true},
+		performers:          &exportSpec{IDs: []int{1}},
+		since:               &since,
+		Mappings: &jsonschema.Mappings{
+			Scenes: []jsonschema.PathNameMapping{{Path: "path", Checksum: "recreated"}},
+		},
+	}
+
+	txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		task.ExportDeleted(r)
+		return nil
+	})
+
+	assert.Equal(t, []jsonschema.DeletedObject{
+		{Type: models.DeletedObjectTypeScene, Checksum: "sceneOSHash", DeletedAt: models.JSONTime{Time: deletedAt.Timestamp}},
+		{Type: models.DeletedObjectTypeTag, Checksum: utils.MD5FromString("tag"), Name: "tag", DeletedAt: models.JSONTime{Time: laterDeletedAt.Timestamp}},
+	}, task.Mappings.Deleted)
+}
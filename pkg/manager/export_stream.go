@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/manager/paths"
+)
+
+// zipObjectWriter writes objects to a zip archive as they are exported,
+// using the same layout as the object files. It is safe for concurrent use.
+// Once a write fails, all further writes return the same error.
+type zipObjectWriter struct {
+	z     *zip.Writer
+	json  paths.JSONPaths
+	err   error
+	mutex sync.Mutex
+}
+
+func newZipObjectWriter(w io.Writer) *zipObjectWriter {
+	return &zipObjectWriter{
+		z:    zip.NewWriter(w),
+		json: *paths.GetJSONPaths(""),
+	}
+}
+
+func (w *zipObjectWriter) objectPath(objectType string, checksum string) (string, error) {
+	switch objectType {
+	case jsonschema.LineTypeMappings:
+		return w.json.MappingsFile, nil
+	case jsonschema.LineTypeScraped:
+		return w.json.ScrapedFile, nil
+	case jsonschema.LineTypePerformer:
+		return w.json.PerformerJSONPath(checksum), nil
+	case jsonschema.LineTypeStudio:
+		return w.json.StudioJSONPath(checksum), nil
+	case jsonschema.LineTypeTag:
+		return w.json.TagJSONPath(checksum), nil
+	case jsonschema.LineTypeMovie:
+		return w.json.MovieJSONPath(checksum), nil
+	case jsonschema.LineTypeScene:
+		return w.json.SceneJSONPath(checksum), nil
+	case jsonschema.LineTypeImage:
+		return w.json.ImageJSONPath(checksum), nil
+	case jsonschema.LineTypeGallery:
+		return w.json.GalleryJSONPath(checksum), nil
+	}
+
+	return "", fmt.Errorf("unknown object type %s", objectType)
+}
+
+func (w *zipObjectWriter) Write(objectType string, checksum string, object interface{}) error {
+	fn, err := w.objectPath(objectType, checksum)
+	if err != nil {
+		return err
+	}
+
+	data, err := jsonschema.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	return w.writeFile(fn, data)
+}
+
+// WriteBlob writes an image exported as a separate file.
+func (w *zipObjectWriter) WriteBlob(dir string, name string, data []byte) error {
+	return w.writeFile(w.json.BlobPath(dir, name), data)
+}
+
+func (w *zipObjectWriter) writeFile(fn string, data []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	f, err := w.z.Create(filepath.ToSlash(fn))
+	if err == nil {
+		_, err = f.Write(data)
+	}
+
+	w.err = err
+	return err
+}
+
+func (w *zipObjectWriter) Err() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.err
+}
+
+// Close writes the end of the archive. The archive is left incomplete if a
+// write failed, so that it cannot be mistaken for a complete export.
+func (w *zipObjectWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.z.Close()
+}
@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZipObjectWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newZipObjectWriter(&buf)
+
+	assert.Nil(t, w.Write(jsonschema.LineTypeScene, "checksum", &jsonschema.Scene{Title: "scene"}))
+	assert.Nil(t, w.Write(jsonschema.LineTypeMappings, "", &jsonschema.Mappings{}))
+	assert.NotNil(t, w.Write("invalid", "checksum", &jsonschema.Scene{}))
+	assert.Nil(t, w.WriteBlob(blobStudios, "checksum.jpg", []byte("image")))
+	assert.Nil(t, w.Err())
+	assert.Nil(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"scenes/checksum.json", "mappings.json", "blobs/studios/checksum.jpg"}, names)
+
+	f, err := r.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	data, _ := ioutil.ReadAll(f)
+	want, _ := jsonschema.Marshal(&jsonschema.Scene{Title: "scene"})
+	assert.Equal(t, want, data)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestZipObjectWriterError(t *testing.T) {
+	w := newZipObjectWriter(failingWriter{})
+
+	// zip entries are buffered, so write enough to force a flush
+	for err := error(nil); err == nil; {
+		err = w.Write(jsonschema.LineTypeScene, "checksum", &jsonschema.Scene{Details: string(make([]byte, 4096))})
+	}
+
+	assert.NotNil(t, w.Err())
+	assert.Equal(t, w.Err(), w.Write(jsonschema.LineTypeTag, "checksum", &jsonschema.Tag{}))
+	assert.Equal(t, w.Err(), w.Close())
+}
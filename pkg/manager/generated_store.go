@@ -0,0 +1,496 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/s3"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// generatedContentTypes are the content types of the uploaded generated
+// files, by file extension.
+var generatedContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".mp4":  "video/mp4",
+	".vtt":  "text/vtt",
+	".webp": "image/webp",
+}
+
+// generatedResponseHeaders are the headers of object storage responses
+// passed on when serving uploaded files.
+var generatedResponseHeaders = []string{"Accept-Ranges", "Content-Length", "Content-Range", "Content-Type", "ETag", "Last-Modified"}
+
+// GeneratedStore stores the generated screenshots, previews, sprites,
+// markers and transcodes of scenes. Files are generated in the generated
+// directory. If object storage is configured, they are then uploaded to it
+// and the local copies are removed unless they are kept. Files are served
+// from the local copy if it exists.
+type GeneratedStore struct {
+	dir string
+
+	client    *s3.Client
+	options   models.ObjectStorage
+	prefix    string
+	keepLocal bool
+
+	mutex sync.RWMutex
+	// remote are the keys of the uploaded files
+	remote map[string]bool
+}
+
+// NewGeneratedStore returns a store of the generated files in dir. Files are
+// uploaded to the object storage if its endpoint is set.
+func NewGeneratedStore(dir string, options models.ObjectStorage) (*GeneratedStore, error) {
+	ret := &GeneratedStore{
+		dir:     dir,
+		options: options,
+		remote:  make(map[string]bool),
+	}
+
+	if options.Endpoint == "" {
+		return ret, nil
+	}
+
+	c := s3.Config{
+		Endpoint:  options.Endpoint,
+		Bucket:    options.Bucket,
+		AccessKey: options.AccessKey,
+		SecretKey: options.SecretKey,
+	}
+	if options.Region != nil {
+		c.Region = *options.Region
+	}
+	if options.PathStyle != nil {
+		c.PathStyle = *options.PathStyle
+	}
+
+	client, err := s3.New(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.client = client
+	if options.Prefix != nil {
+		ret.prefix = strings.Trim(*options.Prefix, "/")
+		if ret.prefix != "" {
+			ret.prefix += "/"
+		}
+	}
+	if options.KeepLocal != nil {
+		ret.keepLocal = *options.KeepLocal
+	}
+
+	return ret, nil
+}
+
+// ValidateObjectStorage returns an error if the object storage options are
+// invalid.
+func ValidateObjectStorage(options *models.ObjectStorageInput) error {
+	if options.Endpoint == "" {
+		return nil
+	}
+
+	_, err := NewGeneratedStore("", models.ObjectStorage(*options))
+	if err != nil {
+		return fmt.Errorf("invalid object storage: %s", err.Error())
+	}
+
+	return nil
+}
+
+// configured returns true if the store is of the generated files in dir,
+// with the object storage options.
+func (s *GeneratedStore) configured(dir string, options models.ObjectStorage) bool {
+	if s.dir != dir {
+		return false
+	}
+
+	equal := func(a, b *string) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+	equalBool := func(a, b *bool) bool {
+		return (a != nil && *a) == (b != nil && *b)
+	}
+
+	o := s.options
+	return o.Endpoint == options.Endpoint && o.Bucket == options.Bucket &&
+		o.AccessKey == options.AccessKey && o.SecretKey == options.SecretKey &&
+		equal(o.Region, options.Region) && equal(o.Prefix, options.Prefix) &&
+		equalBool(o.PathStyle, options.PathStyle) && equalBool(o.KeepLocal, options.KeepLocal)
+}
+
+// HasObjectStorage returns true if files are uploaded to object storage.
+func (s *GeneratedStore) HasObjectStorage() bool {
+	return s.client != nil
+}
+
+// key returns the object storage key of the file at path, or false if it is
+// not in the generated directory.
+func (s *GeneratedStore) key(path string) (string, bool) {
+	rel, err := filepath.Rel(s.dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return s.prefix + filepath.ToSlash(rel), true
+}
+
+// remoteKey returns the key of the file at path if it has been uploaded.
+func (s *GeneratedStore) remoteKey(path string) (string, bool) {
+	if s.client == nil {
+		return "", false
+	}
+
+	key, ok := s.key(path)
+	if !ok {
+		return "", false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return key, s.remote[key]
+}
+
+// remoteKeys returns the keys of the uploaded files in the directory at
+// path, or of the file at path.
+func (s *GeneratedStore) remoteKeys(path string) []string {
+	if s.client == nil {
+		return nil
+	}
+
+	key, ok := s.key(path)
+	if !ok {
+		return nil
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var ret []string
+	for k := range s.remote {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			ret = append(ret, k)
+		}
+	}
+	return ret
+}
+
+func (s *GeneratedStore) setRemote(key string, uploaded bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if uploaded {
+		s.remote[key] = true
+	} else {
+		delete(s.remote, key)
+	}
+}
+
+// Refresh loads the keys of the files uploaded to object storage.
+func (s *GeneratedStore) Refresh(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+
+	remote := make(map[string]bool)
+	if err := s.client.List(ctx, s.prefix, func(o s3.Object) error {
+		remote[o.Key] = true
+		return nil
+	}); err != nil {
+		return fmt.Errorf("error listing object storage: %s", err.Error())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.remote = remote
+
+	return nil
+}
+
+// IsRemote returns true if the file at path is only in object storage.
+func (s *GeneratedStore) IsRemote(path string) bool {
+	if _, ok := s.remoteKey(path); !ok {
+		return false
+	}
+
+	exists, _ := utils.FileExists(path)
+	return !exists
+}
+
+// Exists returns true if the file at path exists locally or in object
+// storage.
+func (s *GeneratedStore) Exists(path string) bool {
+	if _, ok := s.remoteKey(path); ok {
+		return true
+	}
+
+	exists, _ := utils.FileExists(path)
+	return exists
+}
+
+// Store uploads the generated file at path to object storage, and removes
+// the local copy unless local copies are kept. It does nothing if object
+// storage is not configured.
+func (s *GeneratedStore) Store(path string) error {
+	if s.client == nil {
+		return nil
+	}
+
+	key, ok := s.key(path)
+	if !ok {
+		return fmt.Errorf("%s is not in the generated directory", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	contentType := generatedContentTypes[strings.ToLower(filepath.Ext(path))]
+	err = s.client.Put(context.TODO(), key, f, info.Size(), contentType)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("error uploading %s: %s", path, err.Error())
+	}
+
+	s.setRemote(key, true)
+
+	if !s.keepLocal {
+		if err := os.Remove(path); err != nil {
+			logger.Warnf("Could not delete file %s: %s", path, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// storeGenerated uploads the generated files, logging any errors. Files
+// which were not generated are skipped.
+func storeGenerated(paths ...string) {
+	for _, path := range paths {
+		if exists, _ := utils.FileExists(path); !exists {
+			continue
+		}
+
+		if err := instance.GeneratedStore.Store(path); err != nil {
+			logger.Errorf("[generator] %s", err.Error())
+		}
+	}
+}
+
+// Remove deletes the generated file at path, locally and from object
+// storage. Errors are logged.
+func (s *GeneratedStore) Remove(path string) {
+	s.remove(path, os.Remove)
+}
+
+// RemoveAll deletes the directory at path and the files in it, locally and
+// from object storage. Errors are logged.
+func (s *GeneratedStore) RemoveAll(path string) {
+	s.remove(path, os.RemoveAll)
+}
+
+func (s *GeneratedStore) remove(path string, removeLocal func(string) error) {
+	exists, _ := utils.FileExists(path)
+	if exists {
+		if err := removeLocal(path); err != nil {
+			logger.Warnf("Could not delete file %s: %s", path, err.Error())
+		}
+	}
+
+	for _, key := range s.remoteKeys(path) {
+		if err := s.client.Delete(context.TODO(), key); err != nil {
+			logger.Warnf("Could not delete %s from object storage: %s", key, err.Error())
+			continue
+		}
+		s.setRemote(key, false)
+	}
+}
+
+// Rename moves the file or directory at oldPath to newPath, locally and in
+// object storage. It does nothing if oldPath does not exist.
+func (s *GeneratedStore) Rename(oldPath string, newPath string) error {
+	exists, err := utils.FileExists(oldPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	keys := s.remoteKeys(oldPath)
+	if exists || len(keys) > 0 {
+		logger.Infof("renaming %s to %s", oldPath, newPath)
+	}
+
+	if exists {
+		if err := os.Rename(oldPath, newPath); err != nil {
+			return err
+		}
+	}
+
+	oldKey, _ := s.key(oldPath)
+	newKey, ok := s.key(newPath)
+	if len(keys) > 0 && !ok {
+		return fmt.Errorf("%s is not in the generated directory", newPath)
+	}
+
+	for _, key := range keys {
+		dest := newKey + strings.TrimPrefix(key, oldKey)
+		if err := s.client.Copy(context.TODO(), key, dest); err != nil {
+			return err
+		}
+		s.setRemote(dest, true)
+
+		if err := s.client.Delete(context.TODO(), key); err != nil {
+			return err
+		}
+		s.setRemote(key, false)
+	}
+
+	return nil
+}
+
+// Open returns the contents of the generated file at path, from the local
+// copy if it exists.
+func (s *GeneratedStore) Open(path string) (io.ReadCloser, error) {
+	key, ok := s.remoteKey(path)
+	if !ok {
+		return os.Open(path)
+	}
+
+	f, err := os.Open(path)
+	if err == nil || !os.IsNotExist(err) {
+		return f, err
+	}
+
+	resp, err := s.client.Get(context.TODO(), key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Serve serves the generated file at path. Files which are only in object
+// storage are proxied from it, passing on range requests.
+func (s *GeneratedStore) Serve(w http.ResponseWriter, r *http.Request, path string) {
+	if !s.IsRemote(path) {
+		http.ServeFile(w, r, path)
+		return
+	}
+
+	key, _ := s.key(path)
+	resp, err := s.client.Get(r.Context(), key, r.Header)
+	if errors.Is(err, s3.ErrNotFound) {
+		s.setRemote(key, false)
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		logger.Warnf("error getting %s from object storage: %s", key, err.Error())
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, h := range generatedResponseHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	if r.Method != http.MethodHead {
+		_, _ = io.Copy(w, resp.Body)
+	}
+}
+
+// generatedStoreDirs returns the directories of the generated files which
+// are uploaded to object storage.
+func generatedStoreDirs() []string {
+	p := instance.Paths.Generated
+	return []string{p.Screenshots, p.Vtt, p.Markers, p.Transcodes, p.InteractiveHeatmaps}
+}
+
+// Upload uploads the generated files which are only stored locally.
+// progress is called with the number of files uploaded and the total number
+// to upload. Files which cannot be uploaded are logged and skipped.
+func (s *GeneratedStore) Upload(ctx context.Context, dirs []string, progress func(done int, total int)) error {
+	if s.client == nil {
+		return errors.New("object storage is not configured")
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return err
+	}
+
+	var files []string
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+
+			key, ok := s.key(path)
+			if !ok {
+				return nil
+			}
+
+			s.mutex.RLock()
+			uploaded := s.remote[key]
+			s.mutex.RUnlock()
+
+			// the local copies of uploaded files are kept or removed as
+			// configured
+			if uploaded {
+				if !s.keepLocal {
+					if err := os.Remove(path); err != nil {
+						logger.Warnf("Could not delete file %s: %s", path, err.Error())
+					}
+				}
+				return nil
+			}
+
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	total := len(files)
+	for i, path := range files {
+		progress(i, total)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := s.Store(path); err != nil {
+			logger.Warnf("%s", err.Error())
+		}
+	}
+	progress(total, total)
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package manager
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// fakeBucket is an in-memory bucket named bucket, with path style
+// addressing.
+type fakeBucket struct {
+	mutex   sync.Mutex
+	objects map[string]string
+}
+
+type fakeBucketObject struct {
+	Key string `xml:"Key"`
+}
+
+type fakeBucketList struct {
+	XMLName  xml.Name           `xml:"ListBucketResult"`
+	Contents []fakeBucketObject `xml:"Contents"`
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/bucket"), "/")
+	if key == "" {
+		var list fakeBucketList
+		for k := range b.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				list.Contents = append(list.Contents, fakeBucketObject{Key: k})
+			}
+		}
+		sort.Slice(list.Contents, func(i, j int) bool {
+			return list.Contents[i].Key < list.Contents[j].Key
+		})
+		_ = xml.NewEncoder(w).Encode(list)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if src := r.Header.Get("X-Amz-Copy-Source"); src != "" {
+			b.objects[key] = b.objects[strings.TrimPrefix(src, "/bucket/")]
+			return
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		b.objects[key] = string(data)
+	case http.MethodGet, http.MethodHead:
+		data, found := b.objects[key]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, key, time.Time{}, strings.NewReader(data))
+	case http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (b *fakeBucket) keys() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var ret []string
+	for k := range b.objects {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func newTestGeneratedStore(t *testing.T, keepLocal bool) (*GeneratedStore, *fakeBucket, string, func()) {
+	dir, err := ioutil.TempDir("", "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket := &fakeBucket{objects: make(map[string]string)}
+	server := httptest.NewServer(bucket)
+
+	prefix := "/stash/"
+	pathStyle := true
+	store, err := NewGeneratedStore(dir, models.ObjectStorage{
+		Endpoint:  server.URL,
+		Bucket:    "bucket",
+		AccessKey: "key",
+		SecretKey: "secret",
+		Prefix:    &prefix,
+		PathStyle: &pathStyle,
+		KeepLocal: &keepLocal,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store, bucket, dir, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func writeGeneratedFile(t *testing.T, path string, data string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGeneratedStoreStore(t *testing.T) {
+	store, bucket, dir, cleanup := newTestGeneratedStore(t, false)
+	defer cleanup()
+
+	path := filepath.Join(dir, "screenshots", "abc.mp4")
+	writeGeneratedFile(t, path, "preview video")
+
+	assert.Nil(t, store.Store(path))
+	assert.Equal(t, []string{"stash/screenshots/abc.mp4"}, bucket.keys())
+
+	// the local copy is removed
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.True(t, store.Exists(path))
+	assert.True(t, store.IsRemote(path))
+
+	// files are served from object storage, passing on range requests
+	r := httptest.NewRequest(http.MethodGet, "/scene/1/preview", nil)
+	r.Header.Set("Range", "bytes=8-12")
+	w := httptest.NewRecorder()
+	store.Serve(w, r, path)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "video", w.Body.String())
+
+	f, err := store.Open(path)
+	if assert.Nil(t, err) {
+		data, _ := ioutil.ReadAll(f)
+		f.Close()
+		assert.Equal(t, "preview video", string(data))
+	}
+
+	// files outside the generated directory are not uploaded
+	assert.NotNil(t, store.Store(filepath.Join(filepath.Dir(dir), "other.mp4")))
+
+	// uploaded files are found when the store is created again
+	reloaded, err := NewGeneratedStore(dir, store.options)
+	if assert.Nil(t, err) && assert.Nil(t, reloaded.Refresh(context.Background())) {
+		assert.True(t, reloaded.Exists(path))
+	}
+
+	store.Remove(path)
+	assert.Empty(t, bucket.keys())
+	assert.False(t, store.Exists(path))
+
+	w = httptest.NewRecorder()
+	store.Serve(w, httptest.NewRequest(http.MethodGet, "/scene/1/preview", nil), path)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGeneratedStoreRename(t *testing.T) {
+	store, bucket, dir, cleanup := newTestGeneratedStore(t, true)
+	defer cleanup()
+
+	oldDir := filepath.Join(dir, "markers", "old")
+	newDir := filepath.Join(dir, "markers", "new")
+	for _, name := range []string{"10.mp4", "10.webp"} {
+		path := filepath.Join(oldDir, name)
+		writeGeneratedFile(t, path, name)
+		assert.Nil(t, store.Store(path))
+	}
+
+	// local copies are kept
+	assert.True(t, store.Exists(filepath.Join(oldDir, "10.mp4")))
+	assert.False(t, store.IsRemote(filepath.Join(oldDir, "10.mp4")))
+
+	assert.Nil(t, store.Rename(oldDir, newDir))
+	assert.Equal(t, []string{"stash/markers/new/10.mp4", "stash/markers/new/10.webp"}, bucket.keys())
+
+	data, err := ioutil.ReadFile(filepath.Join(newDir, "10.webp"))
+	assert.Nil(t, err)
+	assert.Equal(t, "10.webp", string(data))
+
+	// renaming a missing file does nothing
+	assert.Nil(t, store.Rename(oldDir, newDir))
+
+	store.RemoveAll(newDir)
+	assert.Empty(t, bucket.keys())
+	_, err = os.Stat(newDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGeneratedStoreUpload(t *testing.T) {
+	store, bucket, dir, cleanup := newTestGeneratedStore(t, false)
+	defer cleanup()
+
+	screenshots := filepath.Join(dir, "screenshots")
+	vtt := filepath.Join(dir, "vtt")
+	writeGeneratedFile(t, filepath.Join(screenshots, "abc.jpg"), "screenshot")
+	writeGeneratedFile(t, filepath.Join(vtt, "abc_thumbs.vtt"), "vtt")
+	writeGeneratedFile(t, filepath.Join(dir, "tmp", "partial.mp4"), "partial")
+
+	var progress []int
+	err := store.Upload(context.Background(), []string{screenshots, vtt}, func(done int, total int) {
+		progress = append(progress, done)
+		assert.Equal(t, 2, total)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 2}, progress)
+	assert.Equal(t, []string{"stash/screenshots/abc.jpg", "stash/vtt/abc_thumbs.vtt"}, bucket.keys())
+
+	_, err = os.Stat(filepath.Join(screenshots, "abc.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	local, err := NewGeneratedStore(dir, models.ObjectStorage{})
+	if assert.Nil(t, err) {
+		assert.False(t, local.HasObjectStorage())
+		assert.NotNil(t, local.Upload(context.Background(), []string{screenshots}, func(int, int) {}))
+	}
+}
+
+func TestValidateObjectStorage(t *testing.T) {
+	assert.Nil(t, ValidateObjectStorage(&models.ObjectStorageInput{}))
+	assert.NotNil(t, ValidateObjectStorage(&models.ObjectStorageInput{Endpoint: "http://localhost:9000"}))
+	assert.NotNil(t, ValidateObjectStorage(&models.ObjectStorageInput{Endpoint: "localhost:9000", Bucket: "bucket"}))
+	assert.Nil(t, ValidateObjectStorage(&models.ObjectStorageInput{Endpoint: "http://localhost:9000", Bucket: "bucket"}))
+}
@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// the size of interactive heatmaps, in pixels
+const (
+	heatmapWidth  = 1280
+	heatmapHeight = 60
+)
+
+type heatmapStop struct {
+	speed float64
+	color color.RGBA
+}
+
+// heatmapStops are the colours of speeds in positions per second, from slow
+// moves in blue to fast moves in red. Speeds between the stops are
+// interpolated.
+var heatmapStops = []heatmapStop{
+	{0, color.RGBA{0x1e, 0x90, 0xff, 0xff}},
+	{100, color.RGBA{0x34, 0xc7, 0x59, 0xff}},
+	{200, color.RGBA{0xff, 0xd6, 0x0a, 0xff}},
+	{300, color.RGBA{0xff, 0x95, 0x00, 0xff}},
+	{400, color.RGBA{0xff, 0x3b, 0x30, 0xff}},
+}
+
+func heatmapColor(speed float64) color.RGBA {
+	for i := 1; i < len(heatmapStops); i++ {
+		prev, next := heatmapStops[i-1], heatmapStops[i]
+		if speed >= next.speed {
+			continue
+		}
+
+		f := (speed - prev.speed) / (next.speed - prev.speed)
+		mix := func(a, b uint8) uint8 {
+			return uint8(math.Round(float64(a) + f*(float64(b)-float64(a))))
+		}
+		return color.RGBA{mix(prev.color.R, next.color.R), mix(prev.color.G, next.color.G), mix(prev.color.B, next.color.B), 0xff}
+	}
+
+	return heatmapStops[len(heatmapStops)-1].color
+}
+
+// renderHeatmap returns an image of the speed of the funscript over the
+// duration of the scene in seconds, from left to right. Each column is
+// coloured by the average speed of the moves during its time, and is
+// transparent where the funscript has no actions. The duration of the
+// funscript is used if duration is not set.
+func renderHeatmap(f *scene.Funscript, duration float64, width int, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	durationMs := duration * 1000
+	if durationMs <= 0 && len(f.Actions) > 0 {
+		durationMs = f.Actions[len(f.Actions)-1].At
+	}
+	if durationMs <= 0 || width <= 0 {
+		return img
+	}
+
+	// the speed of each column is the average of the speeds of the moves,
+	// weighted by their time in the column
+	span := durationMs / float64(width)
+	sums := make([]float64, width)
+	weights := make([]float64, width)
+	for _, m := range f.Moves() {
+		first := int(math.Max(0, math.Floor(m.Start/span)))
+		last := int(math.Min(float64(width-1), math.Floor(m.End/span)))
+		for x := first; x <= last; x++ {
+			overlap := math.Min(m.End, float64(x+1)*span) - math.Max(m.Start, float64(x)*span)
+			if overlap > 0 {
+				sums[x] += m.Speed * overlap
+				weights[x] += overlap
+			}
+		}
+	}
+
+	for x := 0; x < width; x++ {
+		if weights[x] == 0 {
+			continue
+		}
+
+		c := heatmapColor(sums[x] / weights[x])
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+// writeHeatmap renders the heatmap of the funscript to the PNG file at path.
+func writeHeatmap(f *scene.Funscript, duration float64, path string) error {
+	if err := utils.EnsureDirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(out, renderHeatmap(f, duration, heatmapWidth, heatmapHeight)); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
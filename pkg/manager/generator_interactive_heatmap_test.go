@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+func TestHeatmapColor(t *testing.T) {
+	assert.Equal(t, heatmapStops[0].color, heatmapColor(0))
+	assert.Equal(t, heatmapStops[1].color, heatmapColor(100))
+	assert.Equal(t, heatmapStops[4].color, heatmapColor(400))
+	assert.Equal(t, heatmapStops[4].color, heatmapColor(1000))
+
+	// half way between yellow and orange
+	assert.Equal(t, color.RGBA{0xff, 0xb6, 0x05, 0xff}, heatmapColor(250))
+}
+
+func TestRenderHeatmap(t *testing.T) {
+	// 100 positions per second for the first second, then 400 for half a
+	// second, with no actions for the rest of the 4 seconds
+	f := &scene.Funscript{Actions: []scene.FunscriptAction{
+		{At: 0, Pos: 0},
+		{At: 1000, Pos: 100},
+		{At: 1500, Pos: 300},
+	}}
+	img := renderHeatmap(f, 4, 8, 2)
+
+	assert.Equal(t, 8, img.Bounds().Dx())
+	assert.Equal(t, 2, img.Bounds().Dy())
+	assert.Equal(t, heatmapStops[1].color, img.RGBAAt(0, 0))
+	assert.Equal(t, heatmapStops[1].color, img.RGBAAt(1, 1))
+	assert.Equal(t, heatmapStops[4].color, img.RGBAAt(2, 0))
+	assert.Equal(t, color.RGBA{}, img.RGBAAt(3, 0))
+	assert.Equal(t, color.RGBA{}, img.RGBAAt(7, 1))
+
+	// the duration of the funscript is used if the scene has no duration
+	img = renderHeatmap(f, 0, 3, 1)
+	assert.Equal(t, heatmapStops[1].color, img.RGBAAt(0, 0))
+	assert.Equal(t, heatmapStops[4].color, img.RGBAAt(2, 0))
+
+	img = renderHeatmap(&scene.Funscript{}, 0, 3, 1)
+	assert.Equal(t, color.RGBA{}, img.RGBAAt(0, 0))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -23,6 +24,12 @@ type PreviewGenerator struct {
 	GenerateImage bool
 
 	PreviewPreset string
+	// ImageQuality and ImageFps are the quality and frame rate of the
+	// preview image
+	ImageQuality int
+	ImageFps     int
+	// Profile replaces the default encoder settings if set
+	Profile *models.TranscodeProfile
 
 	Overwrite bool
 }
@@ -47,6 +54,8 @@ func NewPreviewGenerator(videoFile ffmpeg.VideoFile, videoChecksum string, video
 		GenerateVideo:   generateVideo,
 		GenerateImage:   generateImage,
 		PreviewPreset:   previewPreset,
+		ImageQuality:    100,
+		ImageFps:        12,
 	}, nil
 }
 
@@ -58,6 +67,7 @@ func (g *PreviewGenerator) Generate() error {
 	}
 
 	encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+	encoder.HWAccel = instance.HWAccel()
 	if g.GenerateVideo {
 		if err := g.generateVideo(&encoder, false); err != nil {
 			logger.Warnf("[generator] failed generating scene preview, trying fallback")
@@ -124,6 +134,7 @@ func (g *PreviewGenerator) generateVideo(encoder *ffmpeg.Encoder, fallback bool)
 			Duration:   durationSegment,
 			Width:      640,
 			OutputPath: chunkOutputPath,
+			Profile:    g.Profile,
 		}
 		if err := encoder.ScenePreviewVideoChunk(g.Info.VideoFile, options, g.PreviewPreset, fallback); err != nil {
 			return err
@@ -147,7 +158,12 @@ func (g *PreviewGenerator) generateImage(encoder *ffmpeg.Encoder) error {
 
 	videoPreviewPath := filepath.Join(g.OutputDirectory, g.VideoFilename)
 	tmpOutputPath := instance.Paths.Generated.GetTmpPath(g.ImageFilename)
-	if err := encoder.ScenePreviewVideoToImage(g.Info.VideoFile, 640, videoPreviewPath, tmpOutputPath); err != nil {
+	options := ffmpeg.ScenePreviewImageOptions{
+		Width:   640,
+		Quality: g.ImageQuality,
+		Fps:     g.ImageFps,
+	}
+	if err := encoder.ScenePreviewVideoToImage(g.Info.VideoFile, options, videoPreviewPath, tmpOutputPath); err != nil {
 		return err
 	}
 	if err := utils.SafeMove(tmpOutputPath, outputPath); err != nil {
@@ -17,6 +17,21 @@ import (
 	"github.com/stashapp/stash/pkg/utils"
 )
 
+// maxSpriteChunks is the maximum number of thumbnails in a sprite, which
+// keeps the sprite image within the size limits of JPEG images.
+const maxSpriteChunks = 1000
+
+// SpriteOptions are the layout of a sprite image. If Interval is positive,
+// a thumbnail is taken every Interval seconds, and the number of rows
+// depends on the duration of the video. Otherwise the sprite is a Rows by
+// Columns grid of thumbnails spread evenly through the video.
+type SpriteOptions struct {
+	Rows     int
+	Columns  int
+	Width    int
+	Interval float64
+}
+
 type SpriteGenerator struct {
 	Info *GeneratorInfo
 
@@ -25,11 +40,31 @@ type SpriteGenerator struct {
 	VTTOutputPath   string
 	Rows            int
 	Columns         int
+	Width           int
 
 	Overwrite bool
 }
 
-func NewSpriteGenerator(videoFile ffmpeg.VideoFile, videoChecksum string, imageOutputPath string, vttOutputPath string, rows int, cols int) (*SpriteGenerator, error) {
+// spriteGrid returns the number of thumbnails and rows of a sprite of a
+// video with the duration.
+func spriteGrid(duration float64, options SpriteOptions) (chunks int, rows int) {
+	if options.Interval <= 0 || duration <= 0 {
+		return options.Rows * options.Columns, options.Rows
+	}
+
+	chunks = int(math.Ceil(duration / options.Interval))
+	if chunks > maxSpriteChunks {
+		chunks = maxSpriteChunks
+	}
+	if chunks < 1 {
+		chunks = 1
+	}
+
+	rows = (chunks + options.Columns - 1) / options.Columns
+	return chunks, rows
+}
+
+func NewSpriteGenerator(videoFile ffmpeg.VideoFile, videoChecksum string, imageOutputPath string, vttOutputPath string, options SpriteOptions) (*SpriteGenerator, error) {
 	exists, err := utils.FileExists(videoFile.Path)
 	if !exists {
 		return nil, err
@@ -38,7 +73,12 @@ func NewSpriteGenerator(videoFile ffmpeg.VideoFile, videoChecksum string, imageO
 	if err != nil {
 		return nil, err
 	}
-	generator.ChunkCount = rows * cols
+	if options.Columns <= 0 || options.Rows <= 0 || options.Width <= 0 {
+		return nil, fmt.Errorf("invalid sprite options: %d rows, %d columns, width %d", options.Rows, options.Columns, options.Width)
+	}
+
+	chunks, rows := spriteGrid(videoFile.Duration, options)
+	generator.ChunkCount = chunks
 	if err := generator.configure(); err != nil {
 		return nil, err
 	}
@@ -49,7 +89,8 @@ func NewSpriteGenerator(videoFile ffmpeg.VideoFile, videoChecksum string, imageO
 		ImageOutputPath: imageOutputPath,
 		VTTOutputPath:   vttOutputPath,
 		Rows:            rows,
-		Columns:         cols,
+		Columns:         options.Columns,
+		Width:           options.Width,
 	}, nil
 }
 
@@ -72,14 +113,14 @@ func (g *SpriteGenerator) generateSpriteImage(encoder *ffmpeg.Encoder) error {
 	logger.Infof("[generator] generating sprite image for %s", g.Info.VideoFile.Path)
 
 	// Create `this.chunkCount` thumbnails in the tmp directory
-	stepSize := g.Info.VideoFile.Duration / float64(g.Info.ChunkCount)
+	stepSize := g.stepSize()
 	var images []image.Image
 	for i := 0; i < g.Info.ChunkCount; i++ {
 		time := float64(i) * stepSize
 
 		options := ffmpeg.SpriteScreenshotOptions{
 			Time:  time,
-			Width: 160,
+			Width: g.Width,
 		}
 		img, err := encoder.SpriteScreenshot(g.Info.VideoFile, options)
 		if err != nil {
@@ -99,7 +140,7 @@ func (g *SpriteGenerator) generateSpriteImage(encoder *ffmpeg.Encoder) error {
 	montage := imaging.New(canvasWidth, canvasHeight, color.NRGBA{})
 	for index := 0; index < len(images); index++ {
 		x := width * (index % g.Columns)
-		y := height * int(math.Floor(float64(index)/float64(g.Rows)))
+		y := height * (index / g.Columns)
 		img := images[index]
 		montage = imaging.Paste(montage, img, image.Pt(x, y))
 	}
@@ -123,14 +164,14 @@ func (g *SpriteGenerator) generateSpriteVTT(encoder *ffmpeg.Encoder) error {
 	width := image.Width / g.Columns
 	height := image.Height / g.Rows
 
-	stepSize := float64(g.Info.NthFrame) / g.Info.FrameRate
+	stepSize := g.stepSize()
 
 	vttLines := []string{"WEBVTT", ""}
 	for index := 0; index < g.Info.ChunkCount; index++ {
 		x := width * (index % g.Columns)
-		y := height * int(math.Floor(float64(index)/float64(g.Rows)))
+		y := height * (index / g.Columns)
 		startTime := utils.GetVTTTime(float64(index) * stepSize)
-		endTime := utils.GetVTTTime(float64(index+1) * stepSize)
+		endTime := utils.GetVTTTime(math.Min(float64(index+1)*stepSize, g.Info.VideoFile.Duration))
 
 		vttLines = append(vttLines, startTime+" --> "+endTime)
 		vttLines = append(vttLines, fmt.Sprintf("%s#xywh=%d,%d,%d,%d", spriteImageName, x, y, width, height))
@@ -141,6 +182,12 @@ func (g *SpriteGenerator) generateSpriteVTT(encoder *ffmpeg.Encoder) error {
 	return ioutil.WriteFile(g.VTTOutputPath, []byte(vtt), 0644)
 }
 
+// stepSize returns the time between thumbnails, which is the same for the
+// sprite image and VTT file.
+func (g *SpriteGenerator) stepSize() float64 {
+	return g.Info.VideoFile.Duration / float64(g.Info.ChunkCount)
+}
+
 func (g *SpriteGenerator) imageExists() bool {
 	exists, _ := utils.FileExists(g.ImageOutputPath)
 	return exists
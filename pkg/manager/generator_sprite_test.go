@@ -0,0 +1,34 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpriteGrid(t *testing.T) {
+	grid := SpriteOptions{Rows: 9, Columns: 9, Width: 160}
+
+	chunks, rows := spriteGrid(3600, grid)
+	assert.Equal(t, 81, chunks)
+	assert.Equal(t, 9, rows)
+
+	interval := SpriteOptions{Rows: 9, Columns: 10, Width: 160, Interval: 10}
+
+	chunks, rows = spriteGrid(3600, interval)
+	assert.Equal(t, 360, chunks)
+	assert.Equal(t, 36, rows)
+
+	chunks, rows = spriteGrid(95, interval)
+	assert.Equal(t, 10, chunks)
+	assert.Equal(t, 1, rows)
+
+	chunks, rows = spriteGrid(1000000, interval)
+	assert.Equal(t, maxSpriteChunks, chunks)
+	assert.Equal(t, 100, rows)
+
+	// unknown duration
+	chunks, rows = spriteGrid(0, interval)
+	assert.Equal(t, 90, chunks)
+	assert.Equal(t, 9, rows)
+}
@@ -0,0 +1,33 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ValidateModifyGroup returns an error if the parent of the group does not
+// exist, or is the group itself or one of the groups nested in it.
+func ValidateModifyGroup(group models.Group, qb models.GroupReader) error {
+	// ensure there is no cyclic dependency
+	currentParentID := group.ParentID
+
+	for currentParentID.Valid {
+		if currentParentID.Int64 == int64(group.ID) {
+			return errors.New("group cannot be an ancestor of itself")
+		}
+
+		currentGroup, err := qb.Find(int(currentParentID.Int64))
+		if err != nil {
+			return fmt.Errorf("error finding parent group: %s", err.Error())
+		}
+		if currentGroup == nil {
+			return fmt.Errorf("parent group with id %d not found", currentParentID.Int64)
+		}
+
+		currentParentID = currentGroup.ParentID
+	}
+
+	return nil
+}
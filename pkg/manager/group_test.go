@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+func TestValidateModifyGroup(t *testing.T) {
+	const (
+		rootID = iota + 1
+		childID
+		grandchildID
+		missingID
+		errID
+	)
+
+	qb := &mocks.GroupReaderWriter{}
+	qb.On("Find", rootID).Return(&models.Group{ID: rootID}, nil)
+	qb.On("Find", childID).Return(&models.Group{ID: childID, ParentID: models.NullInt64(rootID)}, nil)
+	qb.On("Find", grandchildID).Return(&models.Group{ID: grandchildID, ParentID: models.NullInt64(childID)}, nil)
+	qb.On("Find", missingID).Return(nil, nil)
+	qb.On("Find", errID).Return(nil, errors.New("error"))
+
+	tests := []struct {
+		name     string
+		id       int
+		parentID int
+		wantErr  bool
+	}{
+		{"no parent", rootID, 0, false},
+		{"new group", 0, grandchildID, false},
+		{"move to other branch", childID, rootID, false},
+		{"own parent", rootID, rootID, true},
+		{"nested parent", rootID, grandchildID, true},
+		{"missing parent", rootID, missingID, true},
+		{"error", rootID, errID, true},
+	}
+
+	for _, tt := range tests {
+		g := models.Group{ID: tt.id}
+		if tt.parentID != 0 {
+			g.ParentID = models.NullInt64(int64(tt.parentID))
+		}
+
+		err := ValidateModifyGroup(g, qb)
+		assert.Equal(t, tt.wantErr, err != nil, tt.name)
+	}
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// HealthCheck is the result of a single health check.
+type HealthCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type healthCheckFunc struct {
+	name string
+	fn   func() error
+}
+
+func (s *singleton) healthChecks() []healthCheckFunc {
+	ret := []healthCheckFunc{
+		{"database", checkDatabase},
+		{"migration", checkMigration},
+		{"ffmpeg", func() error {
+			return checkExecutable(s.FFMPEGPath)
+		}},
+		{"ffprobe", func() error {
+			return checkExecutable(s.FFProbePath)
+		}},
+		{"generated_path", func() error {
+			return checkWritable(s.Config.GetGeneratedPath())
+		}},
+	}
+
+	// the cache path is optional, so it is only checked if set
+	if cachePath := s.Config.GetCachePath(); cachePath != "" {
+		ret = append(ret, healthCheckFunc{"cache_path", func() error {
+			return checkWritable(cachePath)
+		}})
+	}
+
+	return ret
+}
+
+// CheckHealth runs the health checks, returning the result of each check
+// and whether all checks passed.
+func (s *singleton) CheckHealth() ([]HealthCheck, bool) {
+	var ret []HealthCheck
+	healthy := true
+	for _, c := range s.healthChecks() {
+		result := HealthCheck{
+			Name: c.name,
+			OK:   true,
+		}
+
+		if err := c.fn(); err != nil {
+			result.OK = false
+			result.Error = err.Error()
+			healthy = false
+		}
+
+		ret = append(ret, result)
+	}
+
+	return ret, healthy
+}
+
+func checkDatabase() error {
+	if err := database.Ready(); err != nil {
+		return err
+	}
+
+	return database.DB.Ping()
+}
+
+func checkMigration() error {
+	if err := database.Ready(); err != nil {
+		return err
+	}
+
+	if database.NeedsMigration() {
+		return fmt.Errorf("database schema version %d does not match required version %d", database.Version(), database.AppSchemaVersion())
+	}
+
+	return nil
+}
+
+func checkExecutable(path string) error {
+	if path == "" {
+		return errors.New("not found")
+	}
+
+	_, err := utils.FileExists(path)
+	return err
+}
+
+// checkWritable returns an error if a file cannot be created in the
+// directory.
+func checkWritable(dir string) error {
+	if dir == "" {
+		return errors.New("not set")
+	}
+
+	f, err := ioutil.TempFile(dir, ".healthcheck")
+	if err != nil {
+		return err
+	}
+
+	f.Close()
+	return os.Remove(f.Name())
+}
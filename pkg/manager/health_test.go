@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/manager/config"
+)
+
+func TestCheckWritable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, checkWritable(dir))
+	assert.NotNil(t, checkWritable(""))
+	assert.NotNil(t, checkWritable(filepath.Join(dir, "missing")))
+
+	// the check leaves no files behind
+	files, _ := ioutil.ReadDir(dir)
+	assert.Len(t, files, 0)
+}
+
+func TestCheckExecutable(t *testing.T) {
+	f, err := ioutil.TempFile("", "stash-health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	assert.Nil(t, checkExecutable(f.Name()))
+	assert.NotNil(t, checkExecutable(""))
+	assert.NotNil(t, checkExecutable(f.Name()+".missing"))
+}
+
+func TestHealthChecksCachePath(t *testing.T) {
+	c := config.GetInstance()
+	s := &singleton{Config: c}
+
+	checkNames := func() []string {
+		var ret []string
+		for _, check := range s.healthChecks() {
+			ret = append(ret, check.name)
+		}
+		return ret
+	}
+
+	// an unset cache path is not checked
+	c.Set(config.Cache, "")
+	assert.NotContains(t, checkNames(), "cache_path")
+
+	c.Set(config.Cache, os.TempDir())
+	defer c.Set(config.Cache, "")
+	assert.Contains(t, checkNames(), "cache_path")
+}
@@ -1,25 +1,16 @@
 package manager
 
 import (
-	"archive/zip"
 	"os"
-	"strings"
 
+	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 // DeleteGeneratedImageFiles deletes generated files for the provided image.
 func DeleteGeneratedImageFiles(image *models.Image) {
-	thumbPath := GetInstance().Paths.Generated.GetThumbnailPath(image.Checksum, models.DefaultGthumbWidth)
-	exists, _ := utils.FileExists(thumbPath)
-	if exists {
-		err := os.Remove(thumbPath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", thumbPath, err.Error())
-		}
-	}
+	GetInstance().ThumbnailCache.Remove(image.Checksum)
 }
 
 // DeleteImageFile deletes the image file from the filesystem.
@@ -30,41 +21,27 @@ func DeleteImageFile(image *models.Image) {
 	}
 }
 
-func walkGalleryZip(path string, walkFunc func(file *zip.File) error) error {
-	readCloser, err := zip.OpenReader(path)
-	if err != nil {
-		return err
-	}
-	defer readCloser.Close()
-
-	for _, file := range readCloser.File {
-		if file.FileInfo().IsDir() {
-			continue
-		}
-
-		if strings.Contains(file.Name, "__MACOSX") {
-			continue
+// walkGalleryZip calls walkFunc for each image in the gallery archive,
+// including the images of the zip files within it.
+func walkGalleryZip(path string, walkFunc func(e *image.ZipEntry) error) error {
+	return image.WalkZip(path, func(e *image.ZipEntry) error {
+		if !isImage(e.Name) {
+			return nil
 		}
 
-		if !isImage(file.Name) {
-			continue
-		}
-
-		err := walkFunc(file)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+		return walkFunc(e)
+	})
 }
 
-func countImagesInZip(path string) int {
+// countImagesInZip returns the number of images in the gallery archive. The
+// error wraps image.ErrZipPassword if the archive is encrypted with an unknown
+// password.
+func countImagesInZip(path string) (int, error) {
 	ret := 0
-	walkGalleryZip(path, func(file *zip.File) error {
+	err := walkGalleryZip(path, func(e *image.ZipEntry) error {
 		ret++
 		return nil
 	})
 
-	return ret
+	return ret, err
 }
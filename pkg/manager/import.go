@@ -1,7 +1,12 @@
 package manager
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -14,48 +19,156 @@ type importer interface {
 	FindExistingID() (*int, error)
 	Create() (*int, error)
 	Update(id int) error
+	// Merge sets the missing fields of the existing object from the input.
+	// Relationships in the input are added to those of the existing object
+	// by the subsequent call to PostImport.
+	Merge(id int) error
 }
 
-func performImport(i importer, duplicateBehaviour models.ImportDuplicateEnum) error {
+// performImport imports the object, returning whether it was created,
+// updated or skipped. If the object exists and duplicateBehaviour is Fail,
+// then it returns ImportObjectResultConflict with the error. Merging into an
+// existing object is reported as an update.
+func performImport(i importer, duplicateBehaviour models.ImportDuplicateEnum) (models.ImportObjectResult, error) {
 	if err := i.PreImport(); err != nil {
-		return err
+		return models.ImportObjectResultFail, err
 	}
 
 	// try to find an existing object with the same name
 	name := i.Name()
 	existing, err := i.FindExistingID()
 	if err != nil {
-		return fmt.Errorf("error finding existing objects: %s", err.Error())
+		return models.ImportObjectResultFail, fmt.Errorf("error finding existing objects: %s", err.Error())
 	}
 
 	var id int
+	result := models.ImportObjectResultCreate
 
 	if existing != nil {
 		if duplicateBehaviour == models.ImportDuplicateEnumFail {
-			return fmt.Errorf("existing object with name '%s'", name)
+			return models.ImportObjectResultConflict, fmt.Errorf("existing object with name '%s'", name)
 		} else if duplicateBehaviour == models.ImportDuplicateEnumIgnore {
 			logger.Info("Skipping existing object")
-			return nil
+			return models.ImportObjectResultSkip, nil
 		}
 
-		// must be overwriting
 		id = *existing
-		if err := i.Update(id); err != nil {
-			return fmt.Errorf("error updating existing object: %s", err.Error())
+		if duplicateBehaviour == models.ImportDuplicateEnumMerge {
+			if err := i.Merge(id); err != nil {
+				return models.ImportObjectResultFail, fmt.Errorf("error merging into existing object: %s", err.Error())
+			}
+		} else {
+			// must be overwriting
+			if err := i.Update(id); err != nil {
+				return models.ImportObjectResultFail, fmt.Errorf("error updating existing object: %s", err.Error())
+			}
 		}
+
+		result = models.ImportObjectResultUpdate
 	} else {
 		// creating
 		createdID, err := i.Create()
 		if err != nil {
-			return fmt.Errorf("error creating object: %s", err.Error())
+			return models.ImportObjectResultFail, fmt.Errorf("error creating object: %s", err.Error())
 		}
 
 		id = *createdID
 	}
 
 	if err := i.PostImport(id); err != nil {
+		return models.ImportObjectResultFail, err
+	}
+
+	return result, nil
+}
+
+// importReport accumulates the result of importing each object. It is safe
+// for concurrent use.
+type importReport struct {
+	models.ImportReport
+	mutex sync.Mutex
+}
+
+// add adds the result of importing an object. The result is Fail if err is
+// set, unless the object conflicted with an existing object.
+func (r *importReport) add(objectType string, key string, name string, result models.ImportObjectResult, err error) {
+	o := &models.ImportObjectReport{
+		Type:   objectType,
+		Key:    key,
+		Result: result,
+	}
+
+	if name != "" {
+		o.Name = &name
+	}
+
+	if err != nil {
+		if result != models.ImportObjectResultConflict {
+			o.Result = models.ImportObjectResultFail
+		}
+
+		errStr := err.Error()
+		o.Error = &errStr
+	}
+
+	r.addResults([]*models.ImportObjectReport{o})
+}
+
+func (r *importReport) addResults(results []*models.ImportObjectReport) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Objects = append(r.Objects, results...)
+}
+
+// summary returns the number of objects with each result.
+func (r *importReport) summary() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	counts := make(map[models.ImportObjectResult]int)
+	for _, o := range r.Objects {
+		counts[o.Result]++
+	}
+
+	var ret []string
+	for _, result := range models.AllImportObjectResult {
+		if counts[result] > 0 {
+			ret = append(ret, fmt.Sprintf("%d %s", counts[result], strings.ToLower(result.String())))
+		}
+	}
+
+	if len(ret) == 0 {
+		return "no objects"
+	}
+
+	return strings.Join(ret, ", ")
+}
+
+func saveImportReport(fn string, report *models.ImportReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return ioutil.WriteFile(fn, data, 0644)
+}
+
+// loadImportReport returns the import report saved to the file, or nil if
+// the file does not exist.
+func loadImportReport(fn string) (*models.ImportReport, error) {
+	data, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret models.ImportReport
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
 }
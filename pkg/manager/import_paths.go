@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// remapPath replaces the prefix of p using the first of the path mappings
+// which matches it. Prefixes match whole path elements, and either path
+// separator matches the other, so that paths exported on Windows may be
+// remapped to Linux paths and vice versa. The separators in the rest of the
+// path are changed to match the replacement prefix. p is returned
+// unchanged if no mapping matches.
+func remapPath(mappings []*models.ImportPathMappingInput, p string) string {
+	normalised := strings.ReplaceAll(p, `\`, "/")
+
+	for _, m := range mappings {
+		from := strings.TrimRight(strings.ReplaceAll(m.From, `\`, "/"), "/")
+		if from == "" || !strings.HasPrefix(normalised, from) {
+			continue
+		}
+
+		rest := normalised[len(from):]
+		if rest != "" && !strings.HasPrefix(rest, "/") {
+			// only part of the last path element matches
+			continue
+		}
+
+		to := strings.TrimRight(m.To, `/\`)
+		if strings.Contains(to, `\`) && !strings.Contains(to, "/") {
+			rest = strings.ReplaceAll(rest, "/", `\`)
+		}
+
+		return to + rest
+	}
+
+	return p
+}
+
+// remapImagePath remaps the path of an image. Only the path of the zip
+// file is remapped for images within zip files.
+func remapImagePath(mappings []*models.ImportPathMappingInput, p string) string {
+	zipFilename, filenameInZip := image.SplitZipFilename(p)
+	if zipFilename == "" {
+		return remapPath(mappings, p)
+	}
+
+	return image.ZipFilename(remapPath(mappings, zipFilename), filenameInZip)
+}
+
+// remapPaths applies the path mappings to the scene and image paths in the
+// mappings. Gallery paths are remapped as each gallery is imported.
+func (t *ImportTask) remapPaths() {
+	if len(t.PathMappings) == 0 {
+		return
+	}
+
+	for i := range t.mappings.Scenes {
+		m := &t.mappings.Scenes[i]
+		m.Path = remapPath(t.PathMappings, m.Path)
+	}
+
+	for i := range t.mappings.Images {
+		m := &t.mappings.Images[i]
+		m.Path = remapImagePath(t.PathMappings, m.Path)
+	}
+}
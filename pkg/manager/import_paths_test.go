@@ -0,0 +1,37 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemapPath(t *testing.T) {
+	mappings := []*models.ImportPathMappingInput{
+		{From: `C:\Videos\`, To: "/data/videos"},
+		{From: "/mnt/old", To: `D:\New`},
+		{From: "/mnt", To: "/media"},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`C:\Videos\a\b.mp4`, "/data/videos/a/b.mp4"},
+		{`C:\Videos`, "/data/videos"},
+		{`C:\Videos2\b.mp4`, `C:\Videos2\b.mp4`},
+		{"/mnt/old/a/b.mp4", `D:\New\a\b.mp4`},
+		{"/mnt/other/b.mp4", "/media/other/b.mp4"},
+		{"/other/b.mp4", "/other/b.mp4"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, remapPath(mappings, tt.path), tt.path)
+	}
+
+	// only the zip file path is remapped
+	assert.Equal(t, image.ZipFilename("/data/videos/a.zip", `b\c.jpg`), remapImagePath(mappings, image.ZipFilename(`C:\Videos\a.zip`, `b\c.jpg`)))
+	assert.Equal(t, "/data/videos/c.jpg", remapImagePath(mappings, `C:\Videos\c.jpg`))
+}
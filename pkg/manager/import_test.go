@@ -0,0 +1,230 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testImporter struct {
+	existing  *int
+	createErr error
+
+	created bool
+	updated bool
+	merged  bool
+}
+
+func (i *testImporter) PreImport() error {
+	return nil
+}
+
+func (i *testImporter) PostImport(id int) error {
+	return nil
+}
+
+func (i *testImporter) Name() string {
+	return "name"
+}
+
+func (i *testImporter) FindExistingID() (*int, error) {
+	return i.existing, nil
+}
+
+func (i *testImporter) Create() (*int, error) {
+	if i.createErr != nil {
+		return nil, i.createErr
+	}
+
+	i.created = true
+	id := 1
+	return &id, nil
+}
+
+func (i *testImporter) Update(id int) error {
+	i.updated = true
+	return nil
+}
+
+func (i *testImporter) Merge(id int) error {
+	i.merged = true
+	return nil
+}
+
+func TestPerformImport(t *testing.T) {
+	existingID := 2
+
+	tests := []struct {
+		name               string
+		importer           *testImporter
+		duplicateBehaviour models.ImportDuplicateEnum
+		want               models.ImportObjectResult
+		wantErr            bool
+	}{
+		{
+			"create",
+			&testImporter{},
+			models.ImportDuplicateEnumFail,
+			models.ImportObjectResultCreate,
+			false,
+		},
+		{
+			"create error",
+			&testImporter{createErr: errors.New("create error")},
+			models.ImportDuplicateEnumFail,
+			models.ImportObjectResultFail,
+			true,
+		},
+		{
+			"conflict",
+			&testImporter{existing: &existingID},
+			models.ImportDuplicateEnumFail,
+			models.ImportObjectResultConflict,
+			true,
+		},
+		{
+			"skip",
+			&testImporter{existing: &existingID},
+			models.ImportDuplicateEnumIgnore,
+			models.ImportObjectResultSkip,
+			false,
+		},
+		{
+			"update",
+			&testImporter{existing: &existingID},
+			models.ImportDuplicateEnumOverwrite,
+			models.ImportObjectResultUpdate,
+			false,
+		},
+		{
+			"merge",
+			&testImporter{existing: &existingID},
+			models.ImportDuplicateEnumMerge,
+			models.ImportObjectResultUpdate,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := performImport(tt.importer, tt.duplicateBehaviour)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want == models.ImportObjectResultCreate, tt.importer.created)
+			assert.Equal(t, tt.want == models.ImportObjectResultUpdate && tt.duplicateBehaviour == models.ImportDuplicateEnumOverwrite, tt.importer.updated)
+			assert.Equal(t, tt.duplicateBehaviour == models.ImportDuplicateEnumMerge, tt.importer.merged)
+		})
+	}
+}
+
+func TestImportReportAdd(t *testing.T) {
+	var r importReport
+
+	r.add("tag", "a", "", models.ImportObjectResultCreate, nil)
+	r.add("tag", "b", "b", models.ImportObjectResultConflict, errors.New("conflict"))
+	r.add("tag", "c", "c", "", errors.New("failed"))
+
+	assert.Len(t, r.Objects, 3)
+	assert.Nil(t, r.Objects[0].Name)
+	assert.Nil(t, r.Objects[0].Error)
+	assert.Equal(t, models.ImportObjectResultConflict, r.Objects[1].Result)
+	assert.Equal(t, "conflict", *r.Objects[1].Error)
+	assert.Equal(t, models.ImportObjectResultFail, r.Objects[2].Result)
+	assert.Equal(t, "failed", *r.Objects[2].Error)
+}
+
+func TestImportReportSummary(t *testing.T) {
+	var r importReport
+	assert.Equal(t, "no objects", r.summary())
+
+	r.add("tag", "a", "", models.ImportObjectResultCreate, nil)
+	r.add("tag", "b", "", models.ImportObjectResultCreate, nil)
+	r.add("tag", "c", "", "", errors.New("failed"))
+
+	assert.Equal(t, "2 create, 1 fail", r.summary())
+}
+
+func TestImportTaskFinishReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-import-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "import_report.json")
+
+	report, err := loadImportReport(fn)
+	assert.Nil(t, err)
+	assert.Nil(t, report)
+
+	task := &ImportTask{
+		ReportFile: fn,
+		err:        errors.New("failed"),
+	}
+	task.report.add("tag", "a", "a", models.ImportObjectResultCreate, nil)
+	task.finishReport()
+
+	report, err = loadImportReport(fn)
+	if !assert.Nil(t, err) || !assert.NotNil(t, report) {
+		return
+	}
+
+	assert.False(t, report.DryRun)
+	assert.NotNil(t, report.FinishedAt)
+	assert.Equal(t, "failed", *report.Error)
+	if assert.Len(t, report.Objects, 1) {
+		assert.Equal(t, "a", report.Objects[0].Key)
+		assert.Equal(t, models.ImportObjectResultCreate, report.Objects[0].Result)
+	}
+
+	// dry run reports are returned directly instead
+	os.Remove(fn)
+	dryRun := &ImportTask{
+		ReportFile: fn,
+		DryRun:     true,
+	}
+	dryRun.finishReport()
+
+	report, err = loadImportReport(fn)
+	assert.Nil(t, err)
+	assert.Nil(t, report)
+}
+
+func TestImportConcurrently(t *testing.T) {
+	task := &ImportTask{
+		Workers: 4,
+	}
+
+	var mappings []jsonschema.PathNameMapping
+	for i := 0; i < 20; i++ {
+		mappings = append(mappings, jsonschema.PathNameMapping{Checksum: strconv.Itoa(i)})
+	}
+
+	var mutex sync.Mutex
+	imported := make(map[string]bool)
+	importFn := func(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		imported[mappingJSON.Checksum] = true
+	}
+
+	task.importConcurrently(context.Background(), "objects", mappings, importFn)
+	assert.Len(t, imported, len(mappings))
+
+	// nothing is imported once stopped
+	imported = make(map[string]bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	task.importConcurrently(ctx, "objects", mappings, importFn)
+	assert.Len(t, imported, 0)
+}
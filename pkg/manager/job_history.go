@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// jobHistoryKeep is the number of finished jobs kept in the job history.
+const jobHistoryKeep = 1000
+
+// newJobHistoryEntry returns the history entry of a job which has finished,
+// or been removed from the queue. err is the error which caused the job to
+// fail.
+func newJobHistoryEntry(j *Job, err error) models.JobHistoryEntry {
+	ret := models.JobHistoryEntry{
+		Type:       j.Status.Status.String(),
+		Priority:   j.Priority,
+		State:      j.State,
+		AddedAt:    models.SQLiteTimestamp{Timestamp: j.AddedAt},
+		FinishedAt: models.SQLiteTimestamp{Timestamp: j.FinishedAt},
+	}
+
+	if !j.StartedAt.IsZero() {
+		ret.StartedAt = models.NullSQLiteTimestamp{Timestamp: j.StartedAt, Valid: true}
+	}
+
+	if err != nil {
+		ret.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+
+	return ret
+}
+
+// recordJob adds the finished job to the job history. The history is not
+// recorded when the database is read-only or not yet ready.
+func (s *singleton) recordJob(j *Job, err error) {
+	if database.Ready() != nil || s.Config.IsReadOnly() {
+		return
+	}
+
+	entry := newJobHistoryEntry(j, err)
+	if err := s.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		qb := r.JobHistory()
+		if _, err := qb.Create(entry); err != nil {
+			return err
+		}
+
+		return qb.Trim(jobHistoryKeep)
+	}); err != nil {
+		logger.Warnf("error recording %s job in the job history: %s", entry.Type, err.Error())
+	}
+}
@@ -0,0 +1,357 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// maxRunningJobs is the number of jobs which may run at the same time.
+const maxRunningJobs = 3
+
+// Job is a task added to the job queue.
+type Job struct {
+	ID       int
+	Priority models.JobPriority
+	State    models.JobState
+
+	AddedAt    time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	// Status is the progress of the job. Status.Status is the type of job.
+	Status TaskStatus
+
+	exec func(status *TaskStatus)
+	done chan struct{}
+}
+
+// Wait blocks until the job has finished, or has been removed from the
+// queue before it started.
+func (j *Job) Wait() {
+	<-j.done
+}
+
+// exclusive returns true if jobs of type s must not run alongside any other
+// job, because they change or remove many objects or files, or lock the
+// database.
+func (s JobStatus) exclusive() bool {
+	switch s {
+	case Import, Clean, Migrate, RepackageGalleries, Recalculate, Organize, Optimize, MigrateBlobs, UploadGenerated:
+		return true
+	}
+
+	return false
+}
+
+// jobsConflict returns true if jobs of types a and b must not run at the
+// same time. Jobs of the same type never run at the same time. Scans and
+// generate jobs share the temporary directory of the generated files.
+func jobsConflict(a JobStatus, b JobStatus) bool {
+	if a.exclusive() || b.exclusive() {
+		return true
+	}
+
+	group := func(s JobStatus) JobStatus {
+		if s == Scan {
+			return Generate
+		}
+		return s
+	}
+
+	return group(a) == group(b)
+}
+
+func jobPriorityRank(p models.JobPriority) int {
+	switch p {
+	case models.JobPriorityLow:
+		return 0
+	case models.JobPriorityHigh:
+		return 2
+	}
+
+	return 1
+}
+
+// jobQueue runs the queued jobs in order of priority, then in the order
+// they were added. Jobs which do not conflict run at the same time.
+type jobQueue struct {
+	mutex   sync.Mutex
+	lastID  int
+	queued  []*Job
+	running []*Job
+
+	// onFinished is called once a job has finished, or has been removed
+	// from the queue before it started. err is the error which caused the
+	// job to fail.
+	onFinished func(j *Job, err error)
+}
+
+func newJobQueue(onFinished func(j *Job, err error)) *jobQueue {
+	return &jobQueue{
+		onFinished: onFinished,
+	}
+}
+
+// add queues a job of type jobType which runs exec, reporting its progress
+// to status.
+func (q *jobQueue) add(jobType JobStatus, priority models.JobPriority, exec func(status *TaskStatus)) *Job {
+	q.mutex.Lock()
+	q.lastID++
+	j := &Job{
+		ID:       q.lastID,
+		Priority: priority,
+		State:    models.JobStateQueued,
+		AddedAt:  time.Now(),
+		exec:     exec,
+		done:     make(chan struct{}),
+	}
+	j.Status.Status = jobType
+	j.Status.Progress = -1
+	j.Status.mutex = &sync.Mutex{}
+
+	q.queued = append(q.queued, j)
+	q.startJobs()
+	q.mutex.Unlock()
+
+	notifyStatusSubscribers()
+	return j
+}
+
+// startJobs starts the queued jobs which do not conflict with the running
+// jobs. An exclusive job which must wait prevents any jobs after it from
+// starting, so that it is not delayed indefinitely. Must be called with the
+// mutex held.
+func (q *jobQueue) startJobs() {
+	sort.SliceStable(q.queued, func(i, j int) bool {
+		return jobPriorityRank(q.queued[i].Priority) > jobPriorityRank(q.queued[j].Priority)
+	})
+
+	var waiting []*Job
+	blocked := false
+	for _, j := range q.queued {
+		if blocked || len(q.running) >= maxRunningJobs || q.conflicts(j.Status.Status) {
+			waiting = append(waiting, j)
+			if j.Status.Status.exclusive() {
+				blocked = true
+			}
+			continue
+		}
+
+		j.State = models.JobStateRunning
+		j.StartedAt = time.Now()
+		q.running = append(q.running, j)
+		go q.run(j)
+	}
+
+	q.queued = waiting
+}
+
+func (q *jobQueue) conflicts(jobType JobStatus) bool {
+	for _, r := range q.running {
+		if jobsConflict(jobType, r.Status.Status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (q *jobQueue) run(j *Job) {
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorf("recovered from panic in %s job: %v", j.Status.Status, r)
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+
+		j.exec(&j.Status)
+	}()
+
+	if err == nil && j.Status.err != "" {
+		err = errors.New(j.Status.err)
+	}
+
+	q.mutex.Lock()
+	for i, r := range q.running {
+		if r == j {
+			q.running = append(q.running[:i], q.running[i+1:]...)
+			break
+		}
+	}
+
+	j.FinishedAt = time.Now()
+	switch {
+	case err != nil:
+		j.State = models.JobStateFailed
+	case j.Status.IsStopping():
+		j.State = models.JobStateCancelled
+	default:
+		j.State = models.JobStateFinished
+	}
+
+	q.startJobs()
+	q.mutex.Unlock()
+
+	q.finished(j, err)
+}
+
+func (q *jobQueue) finished(j *Job, err error) {
+	close(j.done)
+	notifyStatusSubscribers()
+
+	if q.onFinished != nil {
+		q.onFinished(j, err)
+	}
+}
+
+// stop stops the running job with the id, or removes it from the queue if
+// it has not started. Returns false if there is no such job.
+func (q *jobQueue) stop(id int) bool {
+	q.mutex.Lock()
+
+	for _, j := range q.running {
+		if j.ID == id {
+			q.mutex.Unlock()
+			return j.Status.Stop()
+		}
+	}
+
+	for i, j := range q.queued {
+		if j.ID == id {
+			q.queued = append(q.queued[:i], q.queued[i+1:]...)
+			j.State = models.JobStateCancelled
+			j.FinishedAt = time.Now()
+			q.mutex.Unlock()
+
+			q.finished(j, nil)
+			return true
+		}
+	}
+
+	q.mutex.Unlock()
+	return false
+}
+
+// stopAll removes all jobs from the queue and stops the running jobs.
+func (q *jobQueue) stopAll() {
+	for _, j := range q.jobs() {
+		q.stop(j.ID)
+	}
+}
+
+// jobs returns copies of the running jobs, followed by the queued jobs in
+// the order they will start.
+func (q *jobQueue) jobs() []Job {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var ret []Job
+	for _, j := range q.running {
+		ret = append(ret, j.copy())
+	}
+	for _, j := range q.queued {
+		ret = append(ret, j.copy())
+	}
+
+	return ret
+}
+
+// copy returns a copy of the job with a snapshot of its status. Must be
+// called with the queue mutex held.
+func (j *Job) copy() Job {
+	return Job{
+		ID:         j.ID,
+		Priority:   j.Priority,
+		State:      j.State,
+		AddedAt:    j.AddedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+		Status:     j.Status.snapshot(),
+		exec:       j.exec,
+		done:       j.done,
+	}
+}
+
+// isQueued returns true if a job of type jobType is queued or running.
+func (q *jobQueue) isQueued(jobType JobStatus) bool {
+	for _, j := range q.jobs() {
+		if j.Status.Status == jobType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// queueJob adds a job to the queue. See jobQueue.add.
+func (s *singleton) queueJob(jobType JobStatus, priority models.JobPriority, exec func(status *TaskStatus)) *Job {
+	return s.jobs.add(jobType, priority, exec)
+}
+
+// RunSingleTask queues the task with normal priority.
+func (s *singleton) RunSingleTask(t Task) *Job {
+	return s.RunTask(t, models.JobPriorityNormal)
+}
+
+// RunTask queues the task with the given priority.
+func (s *singleton) RunTask(t Task, priority models.JobPriority) *Job {
+	return s.queueJob(t.GetStatus(), priority, func(status *TaskStatus) {
+		t.setStatus(status)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		t.Start(&wg)
+		wg.Wait()
+	})
+}
+
+// Jobs returns the running jobs, followed by the queued jobs in the order
+// they will start.
+func (s *singleton) Jobs() []Job {
+	return s.jobs.jobs()
+}
+
+// StopJob stops the running job with the id, or removes it from the queue
+// if it has not started. Returns false if there is no such job.
+func (s *singleton) StopJob(id int) bool {
+	return s.jobs.stop(id)
+}
+
+// StopAllJobs removes all jobs from the queue and stops the running jobs.
+func (s *singleton) StopAllJobs() {
+	s.jobs.stopAll()
+}
+
+// CurrentStatus returns the status of the first running job, or an idle
+// status if no job is running.
+func (s *singleton) CurrentStatus() TaskStatus {
+	for _, j := range s.Jobs() {
+		if j.State == models.JobStateRunning {
+			return j.Status
+		}
+	}
+
+	return TaskStatus{Status: Idle, Progress: -1}
+}
+
+func (s *singleton) jobFinished(j *Job, err error) {
+	// jobs removed from the queue did not run
+	if !j.StartedAt.IsZero() {
+		if j.Status.Status == Generate {
+			instance.Paths.Generated.RemoveTmpDir()
+		}
+
+		queueJobFinished(j.Status.Status, j.State == models.JobStateCancelled, err)
+		queueJobNotification(j, err)
+	}
+
+	s.recordJob(j, err)
+}
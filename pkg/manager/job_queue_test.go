@@ -0,0 +1,206 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingJob returns an exec func which records that it started on
+// started, then waits for release to be closed.
+func blockingJob(started chan<- JobStatus, jobType JobStatus, release <-chan struct{}) func(status *TaskStatus) {
+	return func(status *TaskStatus) {
+		started <- jobType
+		<-release
+	}
+}
+
+func jobStates(q *jobQueue) map[int]models.JobState {
+	ret := make(map[int]models.JobState)
+	for _, j := range q.jobs() {
+		ret[j.ID] = j.State
+	}
+	return ret
+}
+
+func TestJobsConflict(t *testing.T) {
+	assert.True(t, jobsConflict(Scan, Scan))
+	assert.True(t, jobsConflict(Scan, Generate))
+	assert.True(t, jobsConflict(Clean, AutoTag))
+	assert.True(t, jobsConflict(AutoTag, Import))
+	assert.False(t, jobsConflict(Scan, AutoTag))
+	assert.False(t, jobsConflict(Generate, Backup))
+}
+
+func TestJobQueuePriority(t *testing.T) {
+	q := newJobQueue(nil)
+	started := make(chan JobStatus, 10)
+	release := make(chan struct{})
+
+	// the clean job runs alone, so the others wait behind it
+	clean := q.add(Clean, models.JobPriorityNormal, blockingJob(started, Clean, release))
+	assert.Equal(t, Clean, <-started)
+
+	q.add(Generate, models.JobPriorityLow, blockingJob(started, Generate, release))
+	q.add(Scan, models.JobPriorityNormal, blockingJob(started, Scan, release))
+	q.add(AutoTag, models.JobPriorityHigh, blockingJob(started, AutoTag, release))
+
+	var order []JobStatus
+	for _, j := range q.jobs() {
+		order = append(order, j.Status.Status)
+	}
+	assert.Equal(t, []JobStatus{Clean, AutoTag, Scan, Generate}, order)
+
+	close(release)
+	clean.Wait()
+
+	var got []JobStatus
+	for i := 0; i < 3; i++ {
+		got = append(got, <-started)
+	}
+
+	// the auto tag and scan jobs run together, then the generate job once
+	// the scan has finished
+	assert.ElementsMatch(t, []JobStatus{AutoTag, Scan}, got[:2])
+	assert.Equal(t, Generate, got[2])
+}
+
+func TestJobQueueExclusiveBlocks(t *testing.T) {
+	q := newJobQueue(nil)
+	started := make(chan JobStatus, 10)
+	release := make(chan struct{})
+
+	scan := q.add(Scan, models.JobPriorityNormal, blockingJob(started, Scan, release))
+	<-started
+
+	// the optimize job must wait for the scan, and the auto tag job must
+	// wait for the optimize job
+	optimize := q.add(Optimize, models.JobPriorityNormal, blockingJob(started, Optimize, release))
+	autoTag := q.add(AutoTag, models.JobPriorityNormal, blockingJob(started, AutoTag, release))
+
+	states := jobStates(q)
+	assert.Equal(t, models.JobStateRunning, states[scan.ID])
+	assert.Equal(t, models.JobStateQueued, states[optimize.ID])
+	assert.Equal(t, models.JobStateQueued, states[autoTag.ID])
+
+	close(release)
+	assert.Equal(t, Optimize, <-started)
+	assert.Equal(t, AutoTag, <-started)
+	autoTag.Wait()
+}
+
+func TestJobQueueMaxRunning(t *testing.T) {
+	q := newJobQueue(nil)
+	started := make(chan JobStatus, 10)
+	release := make(chan struct{})
+
+	types := []JobStatus{Scan, AutoTag, Backup, Export}
+	for _, jobType := range types {
+		q.add(jobType, models.JobPriorityNormal, blockingJob(started, jobType, release))
+	}
+
+	for i := 0; i < maxRunningJobs; i++ {
+		<-started
+	}
+
+	running := 0
+	for _, j := range q.jobs() {
+		if j.State == models.JobStateRunning {
+			running++
+		}
+	}
+	assert.Equal(t, maxRunningJobs, running)
+
+	close(release)
+	assert.Equal(t, Export, <-started)
+}
+
+func TestJobQueueStop(t *testing.T) {
+	var finished []*Job
+	done := make(chan struct{}, 10)
+	q := newJobQueue(func(j *Job, err error) {
+		finished = append(finished, j)
+		done <- struct{}{}
+	})
+
+	started := make(chan JobStatus, 10)
+	release := make(chan struct{})
+
+	running := q.add(Scan, models.JobPriorityNormal, func(status *TaskStatus) {
+		started <- Scan
+		<-release
+	})
+	<-started
+	queued := q.add(Generate, models.JobPriorityNormal, blockingJob(started, Generate, release))
+
+	// a queued job is removed without running
+	assert.True(t, q.stop(queued.ID))
+	queued.Wait()
+	<-done
+	assert.Equal(t, models.JobStateCancelled, queued.State)
+	assert.True(t, queued.StartedAt.IsZero())
+
+	// a running job is asked to stop
+	assert.True(t, q.stop(running.ID))
+	close(release)
+	running.Wait()
+	<-done
+	assert.Equal(t, models.JobStateCancelled, running.State)
+
+	assert.False(t, q.stop(running.ID))
+	assert.Len(t, finished, 2)
+	assert.Len(t, q.jobs(), 0)
+}
+
+func TestJobQueueFinishedStates(t *testing.T) {
+	q := newJobQueue(nil)
+
+	ok := q.add(Scan, models.JobPriorityNormal, func(status *TaskStatus) {})
+	ok.Wait()
+	assert.Equal(t, models.JobStateFinished, ok.State)
+
+	failed := q.add(Scan, models.JobPriorityNormal, func(status *TaskStatus) {
+		status.err = "failed"
+	})
+	failed.Wait()
+	assert.Equal(t, models.JobStateFailed, failed.State)
+
+	panicked := q.add(Scan, models.JobPriorityNormal, func(status *TaskStatus) {
+		panic("oops")
+	})
+	panicked.Wait()
+	assert.Equal(t, models.JobStateFailed, panicked.State)
+}
+
+func TestJobQueueJobsWhileRunning(t *testing.T) {
+	q := newJobQueue(nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	j := q.add(Scan, models.JobPriorityNormal, func(status *TaskStatus) {
+		close(started)
+		for i := 0; ; i++ {
+			select {
+			case <-release:
+				return
+			default:
+			}
+
+			status.setStepProgress("step", i, 1000)
+			status.objectDone()
+		}
+	})
+	<-started
+
+	// the status of the running job is copied while it is updated, which
+	// is reported by the race detector if the status is not locked
+	for i := 0; i < 1000; i++ {
+		for _, copied := range q.jobs() {
+			assert.Equal(t, Scan, copied.Status.Status)
+		}
+	}
+
+	close(release)
+	j.Wait()
+}
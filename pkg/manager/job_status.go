@@ -14,6 +14,17 @@ const (
 	Migrate                JobStatus = 8
 	PluginOperation        JobStatus = 9
 	StashBoxBatchPerformer JobStatus = 10
+	RepackageGalleries     JobStatus = 11
+	Recalculate            JobStatus = 12
+	Backup                 JobStatus = 13
+	ExportNfo              JobStatus = 14
+	CheckMedia             JobStatus = 15
+	Organize               JobStatus = 16
+	Optimize               JobStatus = 17
+	Identify               JobStatus = 18
+	InstallScrapers        JobStatus = 19
+	MigrateBlobs           JobStatus = 20
+	UploadGenerated        JobStatus = 21
 )
 
 func (s JobStatus) String() string {
@@ -40,6 +51,28 @@ func (s JobStatus) String() string {
 		statusMessage = "Plugin Operation"
 	case StashBoxBatchPerformer:
 		statusMessage = "Stash-Box Performer Batch Operation"
+	case RepackageGalleries:
+		statusMessage = "Repackage Galleries"
+	case Recalculate:
+		statusMessage = "Recalculate"
+	case Backup:
+		statusMessage = "Backup"
+	case ExportNfo:
+		statusMessage = "Export NFO"
+	case CheckMedia:
+		statusMessage = "Check Media"
+	case Organize:
+		statusMessage = "Organize Files"
+	case Optimize:
+		statusMessage = "Optimize Database"
+	case Identify:
+		statusMessage = "Identify"
+	case InstallScrapers:
+		statusMessage = "Install Scrapers"
+	case MigrateBlobs:
+		statusMessage = "Move Images"
+	case UploadGenerated:
+		statusMessage = "Upload Generated Files"
 	}
 
 	return statusMessage
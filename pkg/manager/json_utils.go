@@ -1,82 +1,304 @@
 package manager
 
 import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/manager/paths"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// objectWriter writes saved objects in place of the object files.
+type objectWriter interface {
+	Write(objectType string, checksum string, object interface{}) error
+	// Err returns the error of the first failed write, if any.
+	Err() error
+	Close() error
+}
+
+// blobWriter is implemented by object writers which can write images
+// exported as separate files.
+type blobWriter interface {
+	WriteBlob(dir string, name string, data []byte) error
+}
+
+// directories of the images exported as separate files
+const (
+	blobPerformers = "performers"
+	blobStudios    = "studios"
+	blobMovies     = "movies"
 )
 
+var blobDirs = []string{blobPerformers, blobStudios, blobMovies}
+
 type jsonUtils struct {
 	json paths.JSONPaths
+
+	// lines, if set, holds the objects read from a JSON lines file, which
+	// are read instead of the object files
+	lines *jsonschema.LinesFile
+
+	// writer, if set, writes the saved objects instead of the object files
+	writer objectWriter
+
+	// version is the schema version of the objects read, which is set from
+	// the mappings. Objects are not upgraded if it is not set.
+	version int
+}
+
+// readObject returns the JSON of the object of the type, from the JSON
+// lines file if loaded, otherwise from the object file.
+func (jp *jsonUtils) readObject(objectType string, checksum string, fn string) ([]byte, error) {
+	if jp.lines != nil {
+		return jp.lines.GetRaw(objectType, checksum)
+	}
+
+	return ioutil.ReadFile(fn)
+}
+
+// decodeObject decodes the JSON of an object of the type into out, after
+// upgrading it from the schema version of the import.
+func (jp *jsonUtils) decodeObject(objectType string, data []byte, out interface{}) error {
+	if jp.version != 0 {
+		var err error
+		data, err = jsonschema.Upgrade(objectType, jp.version, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return jsonschema.Unmarshal(data, out)
+}
+
+func (jp *jsonUtils) getObject(objectType string, checksum string, fn string, out interface{}) error {
+	data, err := jp.readObject(objectType, checksum, fn)
+	if err != nil {
+		return err
+	}
+
+	return jp.decodeObject(objectType, data, out)
+}
+
+// loadLines loads the JSON lines file in the directory if there is no
+// mappings file. Does nothing if neither exists.
+func (jp *jsonUtils) loadLines() error {
+	if exists, _ := utils.FileExists(jp.json.MappingsFile); exists {
+		return nil
+	}
+
+	for _, fn := range []string{jp.json.ObjectsFile, jp.json.CompressedObjectsFile} {
+		if exists, _ := utils.FileExists(fn); exists {
+			var err error
+			jp.lines, err = jsonschema.LoadLinesFile(fn)
+			return err
+		}
+	}
+
+	return nil
 }
 
+// getMappings returns the mappings, and sets the schema version from which
+// the objects read afterwards are upgraded. Returns an error if the export
+// was made by a newer version of stash.
 func (jp *jsonUtils) getMappings() (*jsonschema.Mappings, error) {
-	return jsonschema.LoadMappingsFile(jp.json.MappingsFile)
+	data, err := jp.readObject(jsonschema.LineTypeMappings, "", jp.json.MappingsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	jp.version, err = jsonschema.GetSchemaVersion(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret jsonschema.Mappings
+	if err := jp.decodeObject(jsonschema.LineTypeMappings, data, &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveMappings(mappings *jsonschema.Mappings) error {
+	mappings.SchemaVersion = jsonschema.SchemaVersion
+
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeMappings, "", mappings)
+	}
+
 	return jsonschema.SaveMappingsFile(jp.json.MappingsFile, mappings)
 }
 
 func (jp *jsonUtils) getScraped() ([]jsonschema.ScrapedItem, error) {
-	return jsonschema.LoadScrapedFile(jp.json.ScrapedFile)
+	var ret []jsonschema.ScrapedItem
+	if err := jp.getObject(jsonschema.LineTypeScraped, "", jp.json.ScrapedFile, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
 
 func (jp *jsonUtils) saveScaped(scraped []jsonschema.ScrapedItem) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeScraped, "", scraped)
+	}
+
 	return jsonschema.SaveScrapedFile(jp.json.ScrapedFile, scraped)
 }
 
 func (jp *jsonUtils) getPerformer(checksum string) (*jsonschema.Performer, error) {
-	return jsonschema.LoadPerformerFile(jp.json.PerformerJSONPath(checksum))
+	var ret jsonschema.Performer
+	if err := jp.getObject(jsonschema.LineTypePerformer, checksum, jp.json.PerformerJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) savePerformer(checksum string, performer *jsonschema.Performer) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypePerformer, checksum, performer)
+	}
+
 	return jsonschema.SavePerformerFile(jp.json.PerformerJSONPath(checksum), performer)
 }
 
 func (jp *jsonUtils) getStudio(checksum string) (*jsonschema.Studio, error) {
-	return jsonschema.LoadStudioFile(jp.json.StudioJSONPath(checksum))
+	var ret jsonschema.Studio
+	if err := jp.getObject(jsonschema.LineTypeStudio, checksum, jp.json.StudioJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveStudio(checksum string, studio *jsonschema.Studio) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeStudio, checksum, studio)
+	}
+
 	return jsonschema.SaveStudioFile(jp.json.StudioJSONPath(checksum), studio)
 }
 
 func (jp *jsonUtils) getTag(checksum string) (*jsonschema.Tag, error) {
-	return jsonschema.LoadTagFile(jp.json.TagJSONPath(checksum))
+	var ret jsonschema.Tag
+	if err := jp.getObject(jsonschema.LineTypeTag, checksum, jp.json.TagJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveTag(checksum string, tag *jsonschema.Tag) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeTag, checksum, tag)
+	}
+
 	return jsonschema.SaveTagFile(jp.json.TagJSONPath(checksum), tag)
 }
 
 func (jp *jsonUtils) getMovie(checksum string) (*jsonschema.Movie, error) {
-	return jsonschema.LoadMovieFile(jp.json.MovieJSONPath(checksum))
+	var ret jsonschema.Movie
+	if err := jp.getObject(jsonschema.LineTypeMovie, checksum, jp.json.MovieJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveMovie(checksum string, movie *jsonschema.Movie) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeMovie, checksum, movie)
+	}
+
 	return jsonschema.SaveMovieFile(jp.json.MovieJSONPath(checksum), movie)
 }
 
 func (jp *jsonUtils) getScene(checksum string) (*jsonschema.Scene, error) {
-	return jsonschema.LoadSceneFile(jp.json.SceneJSONPath(checksum))
+	var ret jsonschema.Scene
+	if err := jp.getObject(jsonschema.LineTypeScene, checksum, jp.json.SceneJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveScene(checksum string, scene *jsonschema.Scene) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeScene, checksum, scene)
+	}
+
 	return jsonschema.SaveSceneFile(jp.json.SceneJSONPath(checksum), scene)
 }
 
 func (jp *jsonUtils) getImage(checksum string) (*jsonschema.Image, error) {
-	return jsonschema.LoadImageFile(jp.json.ImageJSONPath(checksum))
+	var ret jsonschema.Image
+	if err := jp.getObject(jsonschema.LineTypeImage, checksum, jp.json.ImageJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveImage(checksum string, image *jsonschema.Image) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeImage, checksum, image)
+	}
+
 	return jsonschema.SaveImageFile(jp.json.ImageJSONPath(checksum), image)
 }
 
 func (jp *jsonUtils) getGallery(checksum string) (*jsonschema.Gallery, error) {
-	return jsonschema.LoadGalleryFile(jp.json.GalleryJSONPath(checksum))
+	var ret jsonschema.Gallery
+	if err := jp.getObject(jsonschema.LineTypeGallery, checksum, jp.json.GalleryJSONPath(checksum), &ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
 }
 
 func (jp *jsonUtils) saveGallery(checksum string, gallery *jsonschema.Gallery) error {
+	if jp.writer != nil {
+		return jp.writer.Write(jsonschema.LineTypeGallery, checksum, gallery)
+	}
+
 	return jsonschema.SaveGalleryFile(jp.json.GalleryJSONPath(checksum), gallery)
 }
+
+// saveBlob saves an image as a separate file in the blobs directory. The
+// extension of the image type is added to the name.
+func (jp *jsonUtils) saveBlob(dir string, name string, data []byte) error {
+	name += imageExtension(data)
+
+	if jp.writer != nil {
+		w, ok := jp.writer.(blobWriter)
+		if !ok {
+			return errors.New("images cannot be exported as files in this format")
+		}
+		return w.WriteBlob(dir, name, data)
+	}
+
+	fn := jp.json.BlobPath(dir, name)
+	if err := utils.EnsureDirAll(filepath.Dir(fn)); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fn, data, 0644)
+}
+
+// getBlob returns the base64 encoded image with the name in the blobs
+// directory, regardless of its extension. Returns an empty string if there
+// is no such image.
+func (jp *jsonUtils) getBlob(dir string, name string) (string, error) {
+	return findBlob(jp.json.Blobs, dir, name)
+}
+
+// findBlob returns the base64 encoded image with the name in the directory
+// of the object type within blobsDir, regardless of its extension. Returns
+// an empty string if there is no such image.
+func findBlob(blobsDir string, dir string, name string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(blobsDir, dir, name+".*"))
+	if err != nil || len(matches) == 0 {
+		return "", err
+	}
+
+	data, err := ioutil.ReadFile(matches[0])
+	if err != nil {
+		return "", err
+	}
+
+	return utils.GetBase64StringFromData(data), nil
+}
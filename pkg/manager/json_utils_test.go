@@ -0,0 +1,212 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/manager/paths"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONUtilsLines(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		dir, err := ioutil.TempDir("", "stash-json-lines")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		jsonPaths := paths.GetJSONPaths(dir)
+		fn := jsonPaths.ObjectsFile
+		if compress {
+			fn = jsonPaths.CompressedObjectsFile
+		}
+
+		w, err := jsonschema.CreateLinesFile(fn, compress)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mappings := &jsonschema.Mappings{
+			Scenes: []jsonschema.PathNameMapping{{Path: "scene.mp4", Checksum: "scene"}},
+			Tags:   []jsonschema.PathNameMapping{{Name: "tag", Checksum: "tag"}},
+		}
+		scraped := []jsonschema.ScrapedItem{{Title: "scraped"}}
+
+		writer := jsonUtils{json: *jsonPaths, writer: w}
+		assert.Nil(t, writer.saveScene("scene", &jsonschema.Scene{Title: "scene"}))
+		assert.Nil(t, writer.saveTag("tag", &jsonschema.Tag{Name: "tag"}))
+		assert.Nil(t, writer.saveScaped(scraped))
+		assert.Nil(t, writer.saveMappings(mappings))
+		assert.Nil(t, w.Close())
+
+		reader := jsonUtils{json: *jsonPaths}
+		if err := reader.loadLines(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !assert.NotNil(t, reader.lines, "compress: %v", compress) {
+			continue
+		}
+
+		gotMappings, err := reader.getMappings()
+		assert.Nil(t, err)
+		assert.Equal(t, mappings, gotMappings)
+
+		gotScraped, err := reader.getScraped()
+		assert.Nil(t, err)
+		assert.Equal(t, scraped, gotScraped)
+
+		scene, err := reader.getScene("scene")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "scene", scene.Title)
+		}
+
+		tag, err := reader.getTag("tag")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "tag", tag.Name)
+		}
+
+		_, err = reader.getScene("missing")
+		assert.NotNil(t, err)
+	}
+}
+
+func TestJSONUtilsLoadLinesPrefersMappings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-json-lines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	jsonPaths := paths.GetJSONPaths(dir)
+	if err := jsonschema.SaveMappingsFile(jsonPaths.MappingsFile, &jsonschema.Mappings{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(jsonPaths.ObjectsFile, []byte("invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u := jsonUtils{json: *jsonPaths}
+	assert.Nil(t, u.loadLines())
+	assert.Nil(t, u.lines)
+}
+
+func TestJSONUtilsBlobs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-json-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	png := []byte("\x89PNG\r\n\x1a\nimage")
+
+	u := jsonUtils{json: *paths.GetJSONPaths(dir)}
+	assert.Nil(t, u.saveBlob(blobPerformers, "checksum", png))
+
+	exists, _ := utils.FileExists(filepath.Join(dir, "blobs", "performers", "checksum.png"))
+	assert.True(t, exists)
+
+	got, err := u.getBlob(blobPerformers, "checksum")
+	assert.Nil(t, err)
+	assert.Equal(t, utils.GetBase64StringFromData(png), got)
+
+	got, err = u.getBlob(blobStudios, "checksum")
+	assert.Nil(t, err)
+	assert.Equal(t, "", got)
+
+	w, err := jsonschema.CreateLinesFile(u.json.ObjectsFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	lines := jsonUtils{json: u.json, writer: w}
+	assert.NotNil(t, lines.saveBlob(blobPerformers, "checksum", png))
+}
+
+func TestExportedImageImport(t *testing.T) {
+	exportDir, err := ioutil.TempDir("", "stash-json-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(exportDir)
+
+	imagesDir, err := ioutil.TempDir("", "stash-json-blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(imagesDir)
+
+	image := utils.GetBase64StringFromData([]byte("image"))
+	u := jsonUtils{json: *paths.GetJSONPaths(exportDir)}
+
+	inline := &ExportTask{json: u, imageMode: models.ExportImageModeInline}
+	assert.Equal(t, image, inline.exportedImage(blobMovies, "checksum-front", image))
+
+	none := &ExportTask{json: u, imageMode: models.ExportImageModeNone}
+	assert.Equal(t, "", none.exportedImage(blobMovies, "checksum-front", image))
+
+	files := &ExportTask{json: u, imageMode: models.ExportImageModeFiles}
+	assert.Equal(t, "", files.exportedImage(blobMovies, "checksum-front", image))
+	assert.Equal(t, "", files.exportedImage(blobMovies, "checksum-back", ""))
+
+	other := jsonUtils{json: *paths.GetJSONPaths(imagesDir)}
+	assert.Nil(t, other.saveBlob(blobStudios, "checksum", []byte("studio")))
+
+	importTask := &ImportTask{json: u, ImagesPath: other.json.Blobs}
+	assert.Equal(t, image, importTask.blobImage(blobMovies, "checksum-front", ""))
+	assert.Equal(t, "", importTask.blobImage(blobMovies, "checksum-back", ""))
+	assert.Equal(t, "existing", importTask.blobImage(blobMovies, "checksum-front", "existing"))
+	assert.Equal(t, utils.GetBase64StringFromData([]byte("studio")), importTask.blobImage(blobStudios, "checksum", ""))
+}
+
+func TestJSONUtilsUpgrade(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-json-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	paths.EnsureJSONDirs(dir)
+	u := jsonUtils{json: *paths.GetJSONPaths(dir)}
+
+	writeFile := func(fn string, data string) {
+		if err := ioutil.WriteFile(fn, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// exports made before the format was versioned
+	writeFile(u.json.MappingsFile, `{"scenes": [{"path": "scene.mp4", "checksum": "scene"}]}`)
+	writeFile(u.json.SceneJSONPath("scene"), `{"title": "scene", "gallery": "gallery", "file": {"size": "1000000"}}`)
+
+	mappings, err := u.getMappings()
+	if assert.Nil(t, err) {
+		assert.Equal(t, 1, u.version)
+		assert.Len(t, mappings.Scenes, 1)
+	}
+
+	scene, err := u.getScene("scene")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "scene", scene.Title)
+		assert.Equal(t, []string{"gallery"}, scene.Galleries)
+		assert.Equal(t, "1000000", scene.File.Size)
+	}
+
+	writeFile(u.json.MappingsFile, fmt.Sprintf(`{"schema_version": %d}`, jsonschema.SchemaVersion+1))
+	_, err = u.getMappings()
+	assert.NotNil(t, err)
+
+	current := jsonUtils{json: u.json}
+	assert.Nil(t, current.saveMappings(&jsonschema.Mappings{}))
+	if _, err = current.getMappings(); assert.Nil(t, err) {
+		assert.Equal(t, jsonschema.SchemaVersion, current.version)
+	}
+}
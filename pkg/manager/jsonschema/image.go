@@ -9,10 +9,13 @@ import (
 )
 
 type ImageFile struct {
-	ModTime models.JSONTime `json:"mod_time,omitempty"`
-	Size    int             `json:"size"`
-	Width   int             `json:"width"`
-	Height  int             `json:"height"`
+	ModTime     models.JSONTime `json:"mod_time,omitempty"`
+	Size        int             `json:"size"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	TakenAt     models.JSONTime `json:"taken_at,omitempty"`
+	CameraMake  string          `json:"camera_make,omitempty"`
+	CameraModel string          `json:"camera_model,omitempty"`
 }
 
 type Image struct {
@@ -22,6 +25,9 @@ type Image struct {
 	Rating     int             `json:"rating,omitempty"`
 	Organized  bool            `json:"organized,omitempty"`
 	OCounter   int             `json:"o_counter,omitempty"`
+	Location   string          `json:"location,omitempty"`
+	Latitude   *float64        `json:"latitude,omitempty"`
+	Longitude  *float64        `json:"longitude,omitempty"`
 	Galleries  []string        `json:"galleries,omitempty"`
 	Performers []string        `json:"performers,omitempty"`
 	Tags       []string        `json:"tags,omitempty"`
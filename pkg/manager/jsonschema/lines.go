@@ -0,0 +1,230 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// The object types of the lines in a JSON lines file.
+const (
+	LineTypeMappings  = "mappings"
+	LineTypeScraped   = "scraped"
+	LineTypePerformer = "performer"
+	LineTypeStudio    = "studio"
+	LineTypeTag       = "tag"
+	LineTypeMovie     = "movie"
+	LineTypeScene     = "scene"
+	LineTypeImage     = "image"
+	LineTypeGallery   = "gallery"
+)
+
+// Line is a single line of a JSON lines file. Each line holds one object,
+// which is identified by its type and the checksum which is otherwise used
+// as its filename.
+type Line struct {
+	Type     string              `json:"type"`
+	Checksum string              `json:"checksum,omitempty"`
+	Object   jsoniter.RawMessage `json:"object"`
+}
+
+func lineKey(objectType string, checksum string) string {
+	return objectType + "/" + checksum
+}
+
+// LinesWriter writes objects in the JSON lines format. It is safe for
+// concurrent use. Once a write fails, all further writes return the same
+// error.
+type LinesWriter struct {
+	file io.Closer
+	gz   *gzip.Writer
+	w    *bufio.Writer
+	err  error
+
+	mutex sync.Mutex
+}
+
+// NewLinesWriter returns a writer which writes JSON lines to w, gzipped if
+// compress is true. The returned writer must be closed to flush the output.
+// Closing the writer does not close w.
+func NewLinesWriter(w io.Writer, compress bool) *LinesWriter {
+	ret := &LinesWriter{}
+
+	if compress {
+		ret.gz = gzip.NewWriter(w)
+		w = ret.gz
+	}
+	ret.w = bufio.NewWriter(w)
+
+	return ret
+}
+
+// CreateLinesFile creates a JSON lines file, which is gzipped if compress
+// is true. The returned writer must be closed to flush the file.
+func CreateLinesFile(filePath string, compress bool) (*LinesWriter, error) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := NewLinesWriter(f, compress)
+	ret.file = f
+
+	return ret, nil
+}
+
+// Write writes an object to the file.
+func (w *LinesWriter) Write(objectType string, checksum string, object interface{}) error {
+	if object == nil {
+		return fmt.Errorf("%s must not be nil", objectType)
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(Line{
+		Type:     objectType,
+		Checksum: checksum,
+		Object:   data,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	if _, err := w.w.Write(line); err != nil {
+		w.err = err
+		return err
+	}
+
+	w.err = w.w.WriteByte('\n')
+	return w.err
+}
+
+// Err returns the error of the first failed write, if any.
+func (w *LinesWriter) Err() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.err
+}
+
+// Close flushes the output, and closes the file if the writer was created
+// using CreateLinesFile.
+func (w *LinesWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	err := w.err
+	if err == nil {
+		err = w.w.Flush()
+	}
+	if w.gz != nil {
+		if gzErr := w.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	if w.file != nil {
+		if closeErr := w.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// LinesFile holds the objects read from a JSON lines file.
+type LinesFile struct {
+	objects map[string]jsoniter.RawMessage
+}
+
+// LoadLinesFile reads all of the objects in a JSON lines file into memory.
+// Gzipped files are detected from their contents.
+func LoadLinesFile(filePath string) (*LinesFile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readLines(file)
+}
+
+func readLines(r io.Reader) (*LinesFile, error) {
+	br := bufio.NewReader(r)
+
+	// gzip files start with 0x1f 0x8b
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		br = bufio.NewReader(gz)
+	}
+
+	ret := &LinesFile{
+		objects: make(map[string]jsoniter.RawMessage),
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	for {
+		// lines may be large, since objects include their images
+		data, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			var line Line
+			if err := json.Unmarshal(data, &line); err != nil {
+				return nil, err
+			}
+
+			ret.objects[lineKey(line.Type, line.Checksum)] = line.Object
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return ret, nil
+}
+
+// Get decodes the object with the provided type and checksum into out.
+func (f *LinesFile) Get(objectType string, checksum string, out interface{}) error {
+	data, err := f.GetRaw(objectType, checksum)
+	if err != nil {
+		return err
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.Unmarshal(data, out)
+}
+
+// GetRaw returns the JSON of the object with the provided type and checksum.
+func (f *LinesFile) GetRaw(objectType string, checksum string) ([]byte, error) {
+	data, found := f.objects[lineKey(objectType, checksum)]
+	if !found {
+		return nil, fmt.Errorf("%s <%s> not found", objectType, checksum)
+	}
+
+	return data, nil
+}
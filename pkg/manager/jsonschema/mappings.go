@@ -5,6 +5,7 @@ import (
 	"os"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 type PathNameMapping struct {
@@ -13,7 +14,21 @@ type PathNameMapping struct {
 	Checksum string `json:"checksum"`
 }
 
+// DeletedObject identifies an object deleted since the time of an
+// incremental export. Checksum is the checksum used as the filename of the
+// object's JSON file.
+type DeletedObject struct {
+	Type      string          `json:"type"`
+	Checksum  string          `json:"checksum"`
+	Name      string          `json:"name,omitempty"`
+	DeletedAt models.JSONTime `json:"deleted_at"`
+}
+
 type Mappings struct {
+	// SchemaVersion is the version of the export format. It is not set in
+	// exports made before the format was versioned.
+	SchemaVersion int `json:"schema_version,omitempty"`
+
 	Tags       []PathNameMapping `json:"tags"`
 	Performers []PathNameMapping `json:"performers"`
 	Studios    []PathNameMapping `json:"studios"`
@@ -21,6 +36,7 @@ type Mappings struct {
 	Galleries  []PathNameMapping `json:"galleries"`
 	Scenes     []PathNameMapping `json:"scenes"`
 	Images     []PathNameMapping `json:"images"`
+	Deleted    []DeletedObject   `json:"deleted,omitempty"`
 }
 
 func LoadMappingsFile(filePath string) (*Mappings, error) {
@@ -18,16 +18,18 @@ type SceneMarker struct {
 }
 
 type SceneFile struct {
-	ModTime    models.JSONTime `json:"mod_time,omitempty"`
-	Size       string          `json:"size"`
-	Duration   string          `json:"duration"`
-	VideoCodec string          `json:"video_codec"`
-	AudioCodec string          `json:"audio_codec"`
-	Format     string          `json:"format"`
-	Width      int             `json:"width"`
-	Height     int             `json:"height"`
-	Framerate  string          `json:"framerate"`
-	Bitrate    int             `json:"bitrate"`
+	ModTime     models.JSONTime `json:"mod_time,omitempty"`
+	Size        string          `json:"size"`
+	Duration    string          `json:"duration"`
+	VideoCodec  string          `json:"video_codec"`
+	AudioCodec  string          `json:"audio_codec"`
+	Format      string          `json:"format"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Framerate   string          `json:"framerate"`
+	Bitrate     int             `json:"bitrate"`
+	AudioOnly   bool            `json:"audio_only,omitempty"`
+	Interactive bool            `json:"interactive,omitempty"`
 }
 
 type SceneMovie struct {
@@ -36,26 +38,30 @@ type SceneMovie struct {
 }
 
 type Scene struct {
-	Title      string          `json:"title,omitempty"`
-	Checksum   string          `json:"checksum,omitempty"`
-	OSHash     string          `json:"oshash,omitempty"`
-	Phash      string          `json:"phash,omitempty"`
-	Studio     string          `json:"studio,omitempty"`
-	URL        string          `json:"url,omitempty"`
-	Date       string          `json:"date,omitempty"`
-	Rating     int             `json:"rating,omitempty"`
-	Organized  bool            `json:"organized,omitempty"`
-	OCounter   int             `json:"o_counter,omitempty"`
-	Details    string          `json:"details,omitempty"`
-	Galleries  []string        `json:"galleries,omitempty"`
-	Performers []string        `json:"performers,omitempty"`
-	Movies     []SceneMovie    `json:"movies,omitempty"`
-	Tags       []string        `json:"tags,omitempty"`
-	Markers    []SceneMarker   `json:"markers,omitempty"`
-	File       *SceneFile      `json:"file,omitempty"`
-	Cover      string          `json:"cover,omitempty"`
-	CreatedAt  models.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt  models.JSONTime `json:"updated_at,omitempty"`
+	Title      string           `json:"title,omitempty"`
+	Checksum   string           `json:"checksum,omitempty"`
+	OSHash     string           `json:"oshash,omitempty"`
+	Phash      string           `json:"phash,omitempty"`
+	Studio     string           `json:"studio,omitempty"`
+	URL        string           `json:"url,omitempty"`
+	Date       string           `json:"date,omitempty"`
+	Rating     int              `json:"rating,omitempty"`
+	Organized  bool             `json:"organized,omitempty"`
+	OCounter   int              `json:"o_counter,omitempty"`
+	Details    string           `json:"details,omitempty"`
+	Location   string           `json:"location,omitempty"`
+	Latitude   *float64         `json:"latitude,omitempty"`
+	Longitude  *float64         `json:"longitude,omitempty"`
+	Galleries  []string         `json:"galleries,omitempty"`
+	Performers []string         `json:"performers,omitempty"`
+	Movies     []SceneMovie     `json:"movies,omitempty"`
+	Tags       []string         `json:"tags,omitempty"`
+	Markers    []SceneMarker    `json:"markers,omitempty"`
+	File       *SceneFile       `json:"file,omitempty"`
+	Cover      string           `json:"cover,omitempty"`
+	StashIDs   []models.StashID `json:"stash_ids,omitempty"`
+	CreatedAt  models.JSONTime  `json:"created_at,omitempty"`
+	UpdatedAt  models.JSONTime  `json:"updated_at,omitempty"`
 }
 
 func LoadSceneFile(filePath string) (*Scene, error) {
@@ -9,14 +9,16 @@ import (
 )
 
 type Studio struct {
-	Name         string          `json:"name,omitempty"`
-	URL          string          `json:"url,omitempty"`
-	ParentStudio string          `json:"parent_studio,omitempty"`
-	Image        string          `json:"image,omitempty"`
-	CreatedAt    models.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt    models.JSONTime `json:"updated_at,omitempty"`
-	Rating       int             `json:"rating,omitempty"`
-	Details      string          `json:"details,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	URL          string           `json:"url,omitempty"`
+	ParentStudio string           `json:"parent_studio,omitempty"`
+	Image        string           `json:"image,omitempty"`
+	CreatedAt    models.JSONTime  `json:"created_at,omitempty"`
+	UpdatedAt    models.JSONTime  `json:"updated_at,omitempty"`
+	Rating       int              `json:"rating,omitempty"`
+	Details      string           `json:"details,omitempty"`
+	Country      string           `json:"country,omitempty"`
+	StashIDs     []models.StashID `json:"stash_ids,omitempty"`
 }
 
 func LoadStudioFile(filePath string) (*Studio, error) {
@@ -9,10 +9,11 @@ import (
 )
 
 type Tag struct {
-	Name      string          `json:"name,omitempty"`
-	Image     string          `json:"image,omitempty"`
-	CreatedAt models.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt models.JSONTime `json:"updated_at,omitempty"`
+	Name         string          `json:"name,omitempty"`
+	AutoTagRegex string          `json:"auto_tag_regex,omitempty"`
+	Image        string          `json:"image,omitempty"`
+	CreatedAt    models.JSONTime `json:"created_at,omitempty"`
+	UpdatedAt    models.JSONTime `json:"updated_at,omitempty"`
 }
 
 func LoadTagFile(filePath string) (*Tag, error) {
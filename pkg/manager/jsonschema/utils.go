@@ -25,6 +25,17 @@ func marshalToFile(filePath string, j interface{}) error {
 	return ioutil.WriteFile(filePath, data, 0644)
 }
 
+// Marshal encodes j in the format of the object files.
+func Marshal(j interface{}) ([]byte, error) {
+	return encode(j)
+}
+
+// Unmarshal decodes data in the format of the object files into j.
+func Unmarshal(data []byte, j interface{}) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.Unmarshal(data, j)
+}
+
 func encode(j interface{}) ([]byte, error) {
 	buffer := &bytes.Buffer{}
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
@@ -0,0 +1,102 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// SchemaVersion is the version of the export format written by this version
+// of stash. It must be incremented, and an adapter added, whenever a change
+// to the format means that older exports can no longer be read as they are.
+// Exports without a version are version 1.
+const SchemaVersion = 2
+
+// adapter upgrades an object from the previous schema version.
+type adapter func(object map[string]interface{})
+
+// adapters holds the adapters which upgrade objects to each schema version
+// from the previous version, by object type.
+var adapters = map[int]map[string]adapter{
+	2: {
+		LineTypeScene: adaptSceneGallery,
+	},
+}
+
+// adaptSceneGallery replaces the single gallery of scenes exported before
+// scenes could have multiple galleries.
+func adaptSceneGallery(object map[string]interface{}) {
+	gallery, ok := object["gallery"].(string)
+	delete(object, "gallery")
+
+	if !ok || gallery == "" {
+		return
+	}
+
+	galleries, _ := object["galleries"].([]interface{})
+	for _, g := range galleries {
+		if g == gallery {
+			return
+		}
+	}
+
+	object["galleries"] = append(galleries, gallery)
+}
+
+// GetSchemaVersion returns the schema version of the export with the
+// mappings.
+func GetSchemaVersion(mappings []byte) (int, error) {
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	if err := json.Unmarshal(mappings, &v); err != nil {
+		return 0, err
+	}
+
+	if v.SchemaVersion == 0 {
+		return 1, nil
+	}
+
+	if v.SchemaVersion > SchemaVersion {
+		return 0, fmt.Errorf("export schema version %d is newer than the supported version %d", v.SchemaVersion, SchemaVersion)
+	}
+
+	return v.SchemaVersion, nil
+}
+
+// Upgrade returns the JSON of an object of the type, read from an export
+// with the schema version, upgraded to the current schema version. The JSON
+// is returned unchanged if there are no adapters for the object type.
+func Upgrade(objectType string, version int, data []byte) ([]byte, error) {
+	var toApply []adapter
+	for v := version + 1; v <= SchemaVersion; v++ {
+		if a := adapters[v][objectType]; a != nil {
+			toApply = append(toApply, a)
+		}
+	}
+
+	if len(toApply) == 0 {
+		return data, nil
+	}
+
+	// encoding/json is used, since the map support of the vendored jsoniter
+	// is broken on recent Go releases. Numbers are kept as they are, rather
+	// than converted to floats.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	for _, a := range toApply {
+		a(object)
+	}
+
+	return json.Marshal(object)
+}
@@ -1,16 +1,20 @@
 package manager
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	goimage "image"
 	"os"
 	"path/filepath"
 	"runtime/pprof"
 	"sync"
 	"time"
 
+	"github.com/stashapp/stash/pkg/autotag"
 	"github.com/stashapp/stash/pkg/database"
 	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/manager/paths"
@@ -24,20 +28,30 @@ import (
 type singleton struct {
 	Config *config.Instance
 
-	Status TaskStatus
-	Paths  *paths.Paths
+	Paths *paths.Paths
 
 	FFMPEGPath  string
 	FFProbePath string
 
-	PluginCache  *plugin.Cache
-	ScraperCache *scraper.Cache
+	// HWAccels are the hardware encoders which worked with ffmpeg when it
+	// was initialised
+	HWAccels []models.HardwareAcceleration
+
+	PluginCache    *plugin.Cache
+	ScraperCache   *scraper.Cache
+	ThumbnailCache *image.ThumbnailCache
+	GeneratedStore *GeneratedStore
 
 	DownloadStore *DownloadStore
 
 	TxnManager models.TransactionManager
+
+	jobs *jobQueue
 }
 
+// thumbnailQuality is the quality of WebP image thumbnails.
+const thumbnailQuality = 80
+
 var instance *singleton
 var once sync.Once
 
@@ -58,13 +72,24 @@ func Initialize() *singleton {
 		initLog()
 		initProfiling(cfg.GetCPUProfilePath())
 
+		txnManager := sqlite.NewTransactionManager(cfg.IsReadOnly())
+		txnManager.OnCommit = publishEntityChanges
+
+		go runWebhooks(context.Background())
+		go runNotifications(context.Background())
+
+		image.ZipPasswords = cfg.GetZipPasswordsForPath
+		sqlite.BlobStorage = blobStoreOptions
+		go runBackups(context.Background())
+		go runScheduler(context.Background())
+
 		instance = &singleton{
 			Config:        cfg,
-			Status:        TaskStatus{Status: Idle, Progress: -1},
 			DownloadStore: NewDownloadStore(),
 
-			TxnManager: sqlite.NewTransactionManager(),
+			TxnManager: txnManager,
 		}
+		instance.jobs = newJobQueue(instance.jobFinished)
 
 		if !cfg.IsNewSystem() {
 			logger.Infof("using config file: %s", cfg.GetConfigFile())
@@ -89,6 +114,7 @@ func Initialize() *singleton {
 		}
 
 		initFFMPEG()
+		initArchiveTool()
 	})
 
 	return instance
@@ -141,11 +167,29 @@ func initFFMPEG() error {
 
 		instance.FFMPEGPath = ffmpegPath
 		instance.FFProbePath = ffprobePath
+
+		instance.HWAccels = ffmpeg.DetectHWAccels(ffmpegPath)
+		if len(instance.HWAccels) > 0 {
+			logger.Infof("Available hardware encoders: %v", instance.HWAccels)
+		}
 	}
 
 	return nil
 }
 
+// initArchiveTool finds 7-Zip, which reads rar and 7z galleries.
+func initArchiveTool() {
+	paths := []string{
+		instance.Config.GetConfigPath(),
+		paths.GetStashHomeDirectory(),
+	}
+
+	image.ArchiveToolPath = image.FindArchiveTool(paths)
+	if image.ArchiveToolPath == "" {
+		logger.Info("couldn't find 7-Zip. Rar and 7z galleries will not be scanned")
+	}
+}
+
 func initLog() {
 	config := config.GetInstance()
 	logger.Init(config.GetLogFile(), config.GetLogOut(), config.GetLogLevel())
@@ -189,6 +233,8 @@ func (s *singleton) PostInit() error {
 		})
 	}
 
+	database.GeneratedFileExistsFunc = generatedFileExists
+
 	if err := database.Initialize(s.Config.GetDatabasePath()); err != nil {
 		return err
 	}
@@ -219,8 +265,68 @@ func (s *singleton) RefreshConfig() {
 		utils.EnsureDir(s.Paths.Generated.Vtt)
 		utils.EnsureDir(s.Paths.Generated.Markers)
 		utils.EnsureDir(s.Paths.Generated.Transcodes)
+		utils.EnsureDir(s.Paths.Generated.InteractiveHeatmaps)
 		utils.EnsureDir(s.Paths.Generated.Downloads)
 	}
+
+	slowQueryThreshold := time.Duration(config.GetSlowQueryThreshold()) * time.Millisecond
+	sqlite.ConfigureQueryProfiler(config.GetQueryProfiling(), slowQueryThreshold)
+
+	ffmpeg.ConfigureProcessLimits(config.GetMaxTranscodeProcesses(), config.GetMaxGenerateProcesses())
+	ffmpeg.ConfigureProbeCache(config.GetProbeCacheSize())
+
+	autotag.ConfigureSeparators(config.GetAutoTagSeparators())
+
+	s.ThumbnailCache = s.initThumbnailCache()
+	s.GeneratedStore = s.initGeneratedStore()
+
+	watcher.refresh(config.GetStashPaths())
+}
+
+// initThumbnailCache returns a cache of the image thumbnails in the generated
+// directory, which are encoded as WebP images with ffmpeg.
+func (s *singleton) initThumbnailCache() *image.ThumbnailCache {
+	config := s.Config
+	ret := image.NewThumbnailCache(s.Paths.Generated.Thumbnails, s.Paths.Generated.GetThumbnailPath, config.GetThumbnailWorkersWithAutoDetection())
+	ret.Sizes = config.GetThumbnailSizes()
+	ret.MaxSize = config.GetThumbnailCacheSize()
+	ret.EncodeWebP = func(img *goimage.NRGBA, priority ffmpeg.ProcessPriority) ([]byte, error) {
+		if s.FFMPEGPath == "" {
+			return nil, errors.New("ffmpeg not found")
+		}
+
+		encoder := ffmpeg.NewEncoder(s.FFMPEGPath)
+		return encoder.ImageToWebP(img, thumbnailQuality, priority)
+	}
+
+	return ret
+}
+
+// initGeneratedStore returns the store of the generated files. The existing
+// store is kept if its configuration is unchanged, so that object storage is
+// not listed again. The uploaded files are listed in the background.
+func (s *singleton) initGeneratedStore() *GeneratedStore {
+	dir := s.Config.GetGeneratedPath()
+	options := *s.Config.GetObjectStorage()
+	if s.GeneratedStore != nil && s.GeneratedStore.configured(dir, options) {
+		return s.GeneratedStore
+	}
+
+	ret, err := NewGeneratedStore(dir, options)
+	if err != nil {
+		logger.Errorf("error configuring object storage: %s", err.Error())
+		ret, _ = NewGeneratedStore(dir, models.ObjectStorage{})
+	}
+
+	if ret.HasObjectStorage() {
+		go func() {
+			if err := ret.Refresh(context.Background()); err != nil {
+				logger.Errorf("%s", err.Error())
+			}
+		}()
+	}
+
+	return ret
 }
 
 // RefreshScraperCache refreshes the scraper cache. Call this when scraper
@@ -328,6 +434,12 @@ func (s *singleton) Migrate(input models.MigrateInput) error {
 	return nil
 }
 
+// HWAccel returns the configured hardware encoder, or NONE if it is not
+// available.
+func (s *singleton) HWAccel() models.HardwareAcceleration {
+	return ffmpeg.SelectHWAccel(s.Config.GetHardwareAcceleration(), s.HWAccels)
+}
+
 func (s *singleton) GetSystemStatus() *models.SystemStatus {
 	status := models.SystemStatusEnumOk
 	dbSchema := int(database.Version())
@@ -347,5 +459,15 @@ func (s *singleton) GetSystemStatus() *models.SystemStatus {
 		AppSchema:      appSchema,
 		Status:         status,
 		ConfigPath:     &configFile,
+		ScraperBrowser: scraper.BrowserStatus(),
+	}
+}
+
+// blobStoreOptions returns the blob store options of the configuration.
+func blobStoreOptions() sqlite.BlobStoreOptions {
+	c := config.GetInstance()
+	return sqlite.BlobStoreOptions{
+		UseFilesystem: c.GetBlobsStorage() == models.BlobsStorageTypeFilesystem,
+		Path:          c.GetBlobsPath(),
 	}
 }
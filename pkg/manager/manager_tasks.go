@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
@@ -28,6 +29,11 @@ func isVideo(pathname string) bool {
 	return matchExtension(pathname, vidExt)
 }
 
+func isAudio(pathname string) bool {
+	audioExt := config.GetInstance().GetAudioExtensions()
+	return matchExtension(pathname, audioExt)
+}
+
 func isImage(pathname string) bool {
 	imgExt := config.GetInstance().GetImageExtensions()
 	return matchExtension(pathname, imgExt)
@@ -36,24 +42,95 @@ func isImage(pathname string) bool {
 type TaskStatus struct {
 	Status     JobStatus
 	Progress   float64
+	Message    string
 	LastUpdate time.Time
-	stopping   bool
-	upTo       int
-	total      int
+
+	// Phase is the current phase of a task made up of several steps, such
+	// as the type of object being imported.
+	Phase string
+
+	// ObjectsDone and ObjectsTotal count the objects processed by the task
+	// over all of its phases. ObjectsTotal is zero if the task does not
+	// count its objects.
+	ObjectsDone  int
+	ObjectsTotal int
+
+	// ETA is the estimated time remaining until the task completes. It is
+	// zero if it is not known.
+	ETA time.Duration
+
+	stopping     int32
+	upTo         int
+	total        int
+	err          string
+	objectsStart time.Time
+
+	// mutex is locked while the status is changed or copied, since the
+	// status of a job is read by other goroutines while the job runs. It is
+	// nil for statuses which are not of a job.
+	mutex *sync.Mutex
+}
+
+// lock locks the status, returning the function which unlocks it.
+func (t *TaskStatus) lock() func() {
+	if t.mutex == nil {
+		return func() {}
+	}
+
+	t.mutex.Lock()
+	return t.mutex.Unlock
+}
+
+// snapshot returns a copy of the status, which may be read while the task
+// continues to update the status.
+func (t *TaskStatus) snapshot() TaskStatus {
+	defer t.lock()()
+
+	return TaskStatus{
+		Status:       t.Status,
+		Progress:     t.Progress,
+		Message:      t.Message,
+		LastUpdate:   t.LastUpdate,
+		Phase:        t.Phase,
+		ObjectsDone:  t.ObjectsDone,
+		ObjectsTotal: t.ObjectsTotal,
+		ETA:          t.ETA,
+		stopping:     atomic.LoadInt32(&t.stopping),
+		upTo:         t.upTo,
+		total:        t.total,
+		err:          t.err,
+		objectsStart: t.objectsStart,
+	}
 }
 
 func (t *TaskStatus) Stop() bool {
-	t.stopping = true
+	atomic.StoreInt32(&t.stopping, 1)
+
+	unlock := t.lock()
 	t.updated()
+	unlock()
 	return true
 }
 
+// IsStopping returns true if the task has been asked to stop.
+func (t *TaskStatus) IsStopping() bool {
+	return atomic.LoadInt32(&t.stopping) != 0
+}
+
 func (t *TaskStatus) SetStatus(s JobStatus) {
+	defer t.lock()()
+
 	t.Status = s
 	t.updated()
 }
 
 func (t *TaskStatus) setProgress(upTo int, total int) {
+	defer t.lock()()
+	t.progressTo(upTo, total)
+}
+
+// progressTo sets the progress. Must be called with the status locked.
+func (t *TaskStatus) progressTo(upTo int, total int) {
 	if total == 0 {
 		t.Progress = 1
 	}
@@ -64,23 +141,144 @@ func (t *TaskStatus) setProgress(upTo int, total int) {
 }
 
 func (t *TaskStatus) setProgressPercent(progress float64) {
+	defer t.lock()()
+
 	if progress != t.Progress {
 		t.Progress = progress
 		t.updated()
 	}
 }
 
+func (t *TaskStatus) setMessage(message string) {
+	defer t.lock()()
+
+	if message != t.Message {
+		t.Message = message
+		t.updated()
+	}
+}
+
+// setStepProgress reports the progress through a single step of a task,
+// such as one object type during an import.
+func (t *TaskStatus) setStepProgress(step string, upTo int, total int) {
+	t.setMessage(step)
+	t.setProgress(upTo, total)
+}
+
+// setError records the error which caused the task to fail. It may be
+// called on a nil TaskStatus, in which case it does nothing.
+func (t *TaskStatus) setError(err error) {
+	if t == nil {
+		return
+	}
+
+	defer t.lock()()
+
+	t.err = err.Error()
+	t.updated()
+}
+
+// setPhase sets the current phase of the task. It may be called on a nil
+// TaskStatus, in which case it does nothing.
+func (t *TaskStatus) setPhase(phase string) {
+	if t == nil {
+		return
+	}
+
+	defer t.lock()()
+
+	t.Phase = phase
+	t.updated()
+}
+
+// setObjectsTotal sets the number of objects to be processed by the task,
+// and starts estimating the time remaining. It may be called on a nil
+// TaskStatus, in which case it does nothing.
+func (t *TaskStatus) setObjectsTotal(total int) {
+	if t == nil {
+		return
+	}
+
+	defer t.lock()()
+
+	t.ObjectsDone = 0
+	t.ObjectsTotal = total
+	t.ETA = 0
+	t.objectsStart = time.Now()
+	t.updated()
+}
+
+// objectDone counts an object as processed, estimating the time remaining
+// from the average time taken for each object so far. It may be called on a
+// nil TaskStatus, in which case it does nothing.
+func (t *TaskStatus) objectDone() {
+	if t == nil {
+		return
+	}
+
+	defer t.lock()()
+
+	t.ObjectsDone++
+	t.ETA = estimateRemaining(time.Since(t.objectsStart), t.ObjectsDone, t.ObjectsTotal)
+	t.updated()
+}
+
+func estimateRemaining(elapsed time.Duration, done int, total int) time.Duration {
+	if done <= 0 || done >= total {
+		return 0
+	}
+
+	return time.Duration(float64(elapsed) / float64(done) * float64(total-done))
+}
+
+// stopContext returns a context derived from ctx which is cancelled when
+// the task is stopped, so that tasks may stop between objects. The returned
+// cancel function must be called once the task is complete.
+func (t *TaskStatus) stopContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if t == nil {
+		return ctx, cancel
+	}
+
+	updates := SubscribeToStatus(ctx)
+	go func() {
+		if t.IsStopping() {
+			cancel()
+			return
+		}
+
+		for range updates {
+			if t.IsStopping() {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
 func (t *TaskStatus) incrementProgress() {
-	t.setProgress(t.upTo+1, t.total)
+	defer t.lock()()
+	t.progressTo(t.upTo+1, t.total)
+}
+
+// setTotal sets the total used by incrementProgress.
+func (t *TaskStatus) setTotal(total int) {
+	defer t.lock()()
+	t.total = total
 }
 
 func (t *TaskStatus) indefiniteProgress() {
+	defer t.lock()()
+
 	t.Progress = -1
 	t.updated()
 }
 
 func (t *TaskStatus) updated() {
 	t.LastUpdate = time.Now()
+	notifyStatusSubscribers()
 }
 
 func getScanPaths(inputPaths []string) []*models.StashConfig {
@@ -105,7 +303,17 @@ func getScanPaths(inputPaths []string) []*models.StashConfig {
 	return ret
 }
 
-func (s *singleton) neededScan(paths []*models.StashConfig) (total *int, newFiles *int) {
+// stashOption returns the setting of the stash path if set, otherwise the
+// option of the task.
+func stashOption(setting *bool, option *bool) bool {
+	if setting != nil {
+		return *setting
+	}
+
+	return utils.IsTrue(option)
+}
+
+func (s *singleton) neededScan(status *TaskStatus, paths []*models.StashConfig) (total *int, newFiles *int) {
 	const timeout = 90 * time.Second
 
 	// create a control channel through which to signal the counting loop when the timeout is reached
@@ -134,7 +342,7 @@ func (s *singleton) neededScan(paths []*models.StashConfig) (total *int, newFile
 			}
 
 			// check stop
-			if s.Status.stopping {
+			if status.IsStopping() {
 				return timeoutErr
 			}
 
@@ -155,25 +363,17 @@ func (s *singleton) neededScan(paths []*models.StashConfig) (total *int, newFile
 	return &t, &n
 }
 
-func (s *singleton) Scan(input models.ScanMetadataInput) error {
+func (s *singleton) Scan(input models.ScanMetadataInput) (*Job, error) {
 	if err := s.validateFFMPEG(); err != nil {
-		return err
+		return nil, err
 	}
 
-	if s.Status.Status != Idle {
-		return nil
-	}
-	s.Status.SetStatus(Scan)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
-
+	return s.queueJob(Scan, models.JobPriorityNormal, func(status *TaskStatus) {
 		paths := getScanPaths(input.Paths)
 
-		total, newFiles := s.neededScan(paths)
+		total, newFiles := s.neededScan(status, paths)
 
-		if s.Status.stopping {
+		if status.IsStopping() {
 			logger.Info("Stopping due to user request")
 			return
 		}
@@ -187,14 +387,20 @@ func (s *singleton) Scan(input models.ScanMetadataInput) error {
 
 		start := time.Now()
 		config := config.GetInstance()
-		parallelTasks := config.GetParallelTasksWithAutoDetection()
-		logger.Infof("Scan started with %d parallel tasks", parallelTasks)
-		wg := sizedwaitgroup.New(parallelTasks)
+		pools := newScanPools(config)
+		captionFiles := newCaptionFileCache()
+		logger.Infof("Scan started with %d hash, %d probe, %d generate and %d thumbnail workers", pools.hash.size(), pools.probe.size(), pools.generate.size(), pools.thumbnail.size())
+		wg := sizedwaitgroup.New(pools.files())
 
-		s.Status.Progress = 0
+		status.setProgressPercent(0)
 		fileNamingAlgo := config.GetVideoFileNamingAlgorithm()
 		calculateMD5 := config.IsCalculateMD5()
 
+		sidecarMissingRefBehaviour := models.ImportMissingRefEnumCreate
+		if input.SidecarMissingRefBehaviour != nil {
+			sidecarMissingRefBehaviour = *input.SidecarMissingRefBehaviour
+		}
+
 		i := 0
 		stoppingErr := errors.New("stopping")
 		var err error
@@ -204,11 +410,11 @@ func (s *singleton) Scan(input models.ScanMetadataInput) error {
 		for _, sp := range paths {
 			err = walkFilesToScan(sp, func(path string, info os.FileInfo, err error) error {
 				if total != nil {
-					s.Status.setProgress(i, *total)
+					status.setProgress(i, *total)
 					i++
 				}
 
-				if s.Status.stopping {
+				if status.IsStopping() {
 					return stoppingErr
 				}
 
@@ -226,10 +432,16 @@ func (s *singleton) Scan(input models.ScanMetadataInput) error {
 					StripFileExtension:   utils.IsTrue(input.StripFileExtension),
 					fileNamingAlgorithm:  fileNamingAlgo,
 					calculateMD5:         calculateMD5,
-					GeneratePreview:      utils.IsTrue(input.ScanGeneratePreviews),
+					GeneratePreview:      stashOption(sp.GeneratePreviews, input.ScanGeneratePreviews),
 					GenerateImagePreview: utils.IsTrue(input.ScanGenerateImagePreviews),
 					GenerateSprite:       utils.IsTrue(input.ScanGenerateSprites),
-					GeneratePhash:        utils.IsTrue(input.ScanGeneratePhashes),
+					GeneratePhash:        stashOption(sp.GeneratePhashes, input.ScanGeneratePhashes),
+
+					UseSidecarMetadata:         utils.IsTrue(input.UseSidecarMetadata),
+					SidecarMissingRefBehaviour: sidecarMissingRefBehaviour,
+
+					pools:        pools,
+					captionFiles: captionFiles,
 				}
 				go task.Start(&wg)
 
@@ -252,7 +464,7 @@ func (s *singleton) Scan(input models.ScanMetadataInput) error {
 		elapsed := time.Since(start)
 		logger.Info(fmt.Sprintf("Scan finished (%s)", elapsed))
 
-		if s.Status.stopping || err != nil {
+		if status.IsStopping() || err != nil {
 			return
 		}
 
@@ -267,93 +479,61 @@ func (s *singleton) Scan(input models.ScanMetadataInput) error {
 			wg.Wait()
 		}
 		logger.Info("Finished gallery association")
-	}()
-
-	return nil
+	}), nil
 }
 
-func (s *singleton) Import() error {
+func (s *singleton) Import() (*Job, error) {
 	config := config.GetInstance()
 	metadataPath := config.GetMetadataPath()
 	if metadataPath == "" {
-		return errors.New("metadata path must be set in config")
-	}
-
-	if s.Status.Status != Idle {
-		return nil
+		return nil, errors.New("metadata path must be set in config")
 	}
-	s.Status.SetStatus(Import)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
 
+	return s.queueJob(Import, models.JobPriorityNormal, func(status *TaskStatus) {
 		var wg sync.WaitGroup
 		wg.Add(1)
 
 		task := ImportTask{
 			txnManager:          s.TxnManager,
+			status:              status,
 			BaseDir:             metadataPath,
 			Reset:               true,
 			DuplicateBehaviour:  models.ImportDuplicateEnumFail,
 			MissingRefBehaviour: models.ImportMissingRefEnumFail,
+			Workers:             config.GetImportWorkersWithAutoDetection(),
+			ReportFile:          s.Paths.Generated.ImportReport,
 			fileNamingAlgorithm: config.GetVideoFileNamingAlgorithm(),
 		}
 		go task.Start(&wg)
 		wg.Wait()
-	}()
+	}), nil
+}
 
-	return nil
+// LastImportReport returns the report of the last finished import, or nil if
+// no import has finished.
+func (s *singleton) LastImportReport() (*models.ImportReport, error) {
+	return loadImportReport(s.Paths.Generated.ImportReport)
 }
 
-func (s *singleton) Export() error {
+func (s *singleton) Export() (*Job, error) {
 	config := config.GetInstance()
 	metadataPath := config.GetMetadataPath()
 	if metadataPath == "" {
-		return errors.New("metadata path must be set in config")
-	}
-
-	if s.Status.Status != Idle {
-		return nil
+		return nil, errors.New("metadata path must be set in config")
 	}
-	s.Status.SetStatus(Export)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
 
+	return s.queueJob(Export, models.JobPriorityNormal, func(status *TaskStatus) {
 		var wg sync.WaitGroup
 		wg.Add(1)
 		task := ExportTask{
 			txnManager:          s.TxnManager,
+			status:              status,
 			full:                true,
 			fileNamingAlgorithm: config.GetVideoFileNamingAlgorithm(),
 		}
 		go task.Start(&wg)
 		wg.Wait()
-	}()
-
-	return nil
-}
-
-func (s *singleton) RunSingleTask(t Task) (*sync.WaitGroup, error) {
-	if s.Status.Status != Idle {
-		return nil, errors.New("task already running")
-	}
-
-	s.Status.SetStatus(t.GetStatus())
-	s.Status.indefiniteProgress()
-	var wg sync.WaitGroup
-	wg.Add(1)
-
-	go func() {
-		defer s.returnToIdleState()
-
-		go t.Start(&wg)
-		wg.Wait()
-	}()
-
-	return &wg, nil
+	}), nil
 }
 
 func setGeneratePreviewOptionsInput(optionsInput *models.GeneratePreviewOptionsInput) {
@@ -382,21 +562,55 @@ func setGeneratePreviewOptionsInput(optionsInput *models.GeneratePreviewOptionsI
 		val := config.GetPreviewPreset()
 		optionsInput.PreviewPreset = &val
 	}
+
+	if optionsInput.PreviewImageQuality == nil {
+		val := config.GetPreviewImageQuality()
+		optionsInput.PreviewImageQuality = &val
+	}
+
+	if optionsInput.PreviewImageFps == nil {
+		val := config.GetPreviewImageFps()
+		optionsInput.PreviewImageFps = &val
+	}
 }
 
-func (s *singleton) Generate(input models.GenerateMetadataInput) error {
-	if err := s.validateFFMPEG(); err != nil {
-		return err
+// generateOverwrite is whether a generate task overwrites the existing files
+// of each type.
+type generateOverwrite struct {
+	sprites    bool
+	previews   bool
+	markers    bool
+	transcodes bool
+	phashes    bool
+	heatmaps   bool
+}
+
+func newGenerateOverwrite(input models.GenerateMetadataInput) generateOverwrite {
+	overwrite := input.Overwrite != nil && *input.Overwrite
+
+	flag := func(v *bool, def bool) bool {
+		if v != nil {
+			return *v
+		}
+		return def
 	}
 
-	if s.Status.Status != Idle {
-		return nil
+	// phashes of unchanged files do not change, so are only regenerated
+	// when requested
+	return generateOverwrite{
+		sprites:    flag(input.OverwriteSprites, overwrite),
+		previews:   flag(input.OverwritePreviews, overwrite),
+		markers:    flag(input.OverwriteMarkers, overwrite),
+		transcodes: flag(input.OverwriteTranscodes, overwrite),
+		phashes:    flag(input.OverwritePhashes, false),
+		heatmaps:   flag(input.OverwriteInteractiveHeatmapsSpeeds, overwrite),
 	}
-	s.Status.SetStatus(Generate)
-	s.Status.indefiniteProgress()
+}
 
-	//this.job.total = await ObjectionUtils.getCount(Scene);
-	instance.Paths.Generated.EnsureTmpDir()
+func (s *singleton) Generate(input models.GenerateMetadataInput) (*Job, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return nil, err
+	}
 
 	sceneIDs, err := utils.StringSliceToIntSlice(input.SceneIDs)
 	if err != nil {
@@ -407,8 +621,10 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 		logger.Error(err.Error())
 	}
 
-	go func() {
-		defer s.returnToIdleState()
+	// generate jobs are run at low priority, so that they don't delay other
+	// tasks
+	return s.queueJob(Generate, models.JobPriorityLow, func(status *TaskStatus) {
+		instance.Paths.Generated.EnsureTmpDir()
 
 		var scenes []*models.Scene
 		var err error
@@ -416,9 +632,12 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 
 		if err := s.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
 			qb := r.Scene()
-			if len(sceneIDs) > 0 {
+			switch {
+			case input.SceneFilter != nil:
+				scenes, err = findGenerateScenes(qb, input.SceneFilter, sceneIDs)
+			case len(sceneIDs) > 0:
 				scenes, err = qb.FindMany(sceneIDs)
-			} else {
+			default:
 				scenes, err = qb.All()
 			}
 
@@ -440,16 +659,16 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 		}
 
 		config := config.GetInstance()
-		parallelTasks := config.GetParallelTasksWithAutoDetection()
+		parallelTasks := config.GetGenerateWorkersWithAutoDetection()
 
 		logger.Infof("Generate started with %d parallel tasks", parallelTasks)
 		wg := sizedwaitgroup.New(parallelTasks)
 
-		s.Status.Progress = 0
+		status.setProgressPercent(0)
 		lenScenes := len(scenes)
 		total := lenScenes + len(markers)
 
-		if s.Status.stopping {
+		if status.IsStopping() {
 			logger.Info("Stopping due to user request")
 			return
 		}
@@ -459,15 +678,12 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 			logger.Infof("Taking too long to count content. Skipping...")
 			logger.Infof("Generating content")
 		} else {
-			logger.Infof("Generating %d sprites %d previews %d image previews %d markers %d transcodes %d phashes", totalsNeeded.sprites, totalsNeeded.previews, totalsNeeded.imagePreviews, totalsNeeded.markers, totalsNeeded.transcodes, totalsNeeded.phashes)
+			logger.Infof("Generating %d sprites %d previews %d image previews %d markers %d transcodes %d phashes %d interactive heatmaps", totalsNeeded.sprites, totalsNeeded.previews, totalsNeeded.imagePreviews, totalsNeeded.markers, totalsNeeded.transcodes, totalsNeeded.phashes, totalsNeeded.heatmaps)
 		}
 
 		fileNamingAlgo := config.GetVideoFileNamingAlgorithm()
 
-		overwrite := false
-		if input.Overwrite != nil {
-			overwrite = *input.Overwrite
-		}
+		overwrite := newGenerateOverwrite(input)
 
 		generatePreviewOptions := input.PreviewOptions
 		if generatePreviewOptions == nil {
@@ -480,8 +696,8 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 		instance.Paths.Generated.EnsureTmpDir()
 
 		for i, scene := range scenes {
-			s.Status.setProgress(i, total)
-			if s.Status.stopping {
+			status.setProgress(i, total)
+			if status.IsStopping() {
 				logger.Info("Stopping due to user request")
 				wg.Wait()
 				instance.Paths.Generated.EmptyTmpDir()
@@ -493,10 +709,28 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 				continue
 			}
 
+			// heatmaps are generated from the funscript, so audio files
+			// may have them
+			if input.InteractiveHeatmapsSpeeds != nil && *input.InteractiveHeatmapsSpeeds {
+				task := GenerateInteractiveHeatmapSpeedTask{
+					Scene:               *scene,
+					Overwrite:           overwrite.heatmaps,
+					fileNamingAlgorithm: fileNamingAlgo,
+					txnManager:          s.TxnManager,
+				}
+				wg.Add()
+				go task.Start(&wg)
+			}
+
+			// audio files have no video to generate from
+			if scene.AudioOnly {
+				continue
+			}
+
 			if input.Sprites {
 				task := GenerateSpriteTask{
 					Scene:               *scene,
-					Overwrite:           overwrite,
+					Overwrite:           overwrite.sprites,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				wg.Add()
@@ -508,7 +742,7 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 					Scene:               *scene,
 					ImagePreview:        input.ImagePreviews,
 					Options:             *generatePreviewOptions,
-					Overwrite:           overwrite,
+					Overwrite:           overwrite.previews,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				wg.Add()
@@ -520,7 +754,7 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 				task := GenerateMarkersTask{
 					TxnManager:          s.TxnManager,
 					Scene:               scene,
-					Overwrite:           overwrite,
+					Overwrite:           overwrite.markers,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				go task.Start(&wg)
@@ -530,7 +764,7 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 				wg.Add()
 				task := GenerateTranscodeTask{
 					Scene:               *scene,
-					Overwrite:           overwrite,
+					Overwrite:           overwrite.transcodes,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				go task.Start(&wg)
@@ -539,6 +773,7 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 			if input.Phashes {
 				task := GeneratePhashTask{
 					Scene:               *scene,
+					Overwrite:           overwrite.phashes,
 					fileNamingAlgorithm: fileNamingAlgo,
 					txnManager:          s.TxnManager,
 				}
@@ -550,8 +785,8 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 		wg.Wait()
 
 		for i, marker := range markers {
-			s.Status.setProgress(lenScenes+i, total)
-			if s.Status.stopping {
+			status.setProgress(lenScenes+i, total)
+			if status.IsStopping() {
 				logger.Info("Stopping due to user request")
 				wg.Wait()
 				instance.Paths.Generated.EmptyTmpDir()
@@ -569,7 +804,7 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 			task := GenerateMarkersTask{
 				TxnManager:          s.TxnManager,
 				Marker:              marker,
-				Overwrite:           overwrite,
+				Overwrite:           overwrite.markers,
 				fileNamingAlgorithm: fileNamingAlgo,
 			}
 			go task.Start(&wg)
@@ -580,31 +815,46 @@ func (s *singleton) Generate(input models.GenerateMetadataInput) error {
 		instance.Paths.Generated.EmptyTmpDir()
 		elapsed := time.Since(start)
 		logger.Info(fmt.Sprintf("Generate finished (%s)", elapsed))
-	}()
+	}), nil
+}
+
+// findGenerateScenes returns the scenes matching sceneFilter. If sceneIDs is
+// not empty, only the matching scenes with those ids are returned.
+func findGenerateScenes(qb models.SceneReader, sceneFilter *models.SceneFilterType, sceneIDs []int) ([]*models.Scene, error) {
+	perPage := models.PerPageAll
+	ids, _, err := qb.QueryIDs(sceneFilter, &models.FindFilterType{
+		PerPage: &perPage,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	if len(sceneIDs) > 0 {
+		var included []int
+		for _, id := range ids {
+			if utils.IntInclude(sceneIDs, id) {
+				included = append(included, id)
+			}
+		}
+		ids = included
+	}
+
+	return qb.FindMany(ids)
 }
 
-func (s *singleton) GenerateDefaultScreenshot(sceneId string) {
-	s.generateScreenshot(sceneId, nil)
+func (s *singleton) GenerateDefaultScreenshot(sceneId string) *Job {
+	return s.generateScreenshot(sceneId, nil)
 }
 
-func (s *singleton) GenerateScreenshot(sceneId string, at float64) {
-	s.generateScreenshot(sceneId, &at)
+func (s *singleton) GenerateScreenshot(sceneId string, at float64) *Job {
+	return s.generateScreenshot(sceneId, &at)
 }
 
 // generate default screenshot if at is nil
-func (s *singleton) generateScreenshot(sceneId string, at *float64) {
-	if s.Status.Status != Idle {
-		return
-	}
-	s.Status.SetStatus(Generate)
-	s.Status.indefiniteProgress()
-
-	instance.Paths.Generated.EnsureTmpDir()
-
-	go func() {
-		defer s.returnToIdleState()
+func (s *singleton) generateScreenshot(sceneId string, at *float64) *Job {
+	// the user is waiting for the screenshot
+	return s.queueJob(Generate, models.JobPriorityHigh, func(status *TaskStatus) {
+		instance.Paths.Generated.EnsureTmpDir()
 
 		sceneIdInt, err := strconv.Atoi(sceneId)
 		if err != nil {
@@ -636,7 +886,7 @@ func (s *singleton) generateScreenshot(sceneId string, at *float64) {
 		wg.Wait()
 
 		logger.Infof("Generate screenshot finished")
-	}()
+	})
 }
 
 func (s *singleton) isFileBasedAutoTag(input models.AutoTagMetadataInput) bool {
@@ -648,40 +898,32 @@ func (s *singleton) isFileBasedAutoTag(input models.AutoTagMetadataInput) bool {
 	return (len(performerIds) == 0 || performerIds[0] == wildcard) && (len(studioIds) == 0 || studioIds[0] == wildcard) && (len(tagIds) == 0 || tagIds[0] == wildcard)
 }
 
-func (s *singleton) AutoTag(input models.AutoTagMetadataInput) {
-	if s.Status.Status != Idle {
-		return
-	}
-	s.Status.SetStatus(AutoTag)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
-
+func (s *singleton) AutoTag(input models.AutoTagMetadataInput) *Job {
+	return s.queueJob(AutoTag, models.JobPriorityNormal, func(status *TaskStatus) {
 		if s.isFileBasedAutoTag(input) {
 			// doing file-based auto-tag
-			s.autoTagFiles(input.Paths, len(input.Performers) > 0, len(input.Studios) > 0, len(input.Tags) > 0)
+			s.autoTagFiles(status, input.Paths, len(input.Performers) > 0, len(input.Studios) > 0, len(input.Tags) > 0)
 		} else {
 			// doing specific performer/studio/tag auto-tag
-			s.autoTagSpecific(input)
+			s.autoTagSpecific(status, input)
 		}
-	}()
+	})
 }
 
-func (s *singleton) autoTagFiles(paths []string, performers, studios, tags bool) {
+func (s *singleton) autoTagFiles(status *TaskStatus, paths []string, performers, studios, tags bool) {
 	t := autoTagFilesTask{
 		paths:      paths,
 		performers: performers,
 		studios:    studios,
 		tags:       tags,
 		txnManager: s.TxnManager,
-		status:     &s.Status,
+		status:     status,
 	}
 
 	t.process()
 }
 
-func (s *singleton) autoTagSpecific(input models.AutoTagMetadataInput) {
+func (s *singleton) autoTagSpecific(status *TaskStatus, input models.AutoTagMetadataInput) {
 	performerIds := input.Performers
 	studioIds := input.Studios
 	tagIds := input.Tags
@@ -723,19 +965,19 @@ func (s *singleton) autoTagSpecific(input models.AutoTagMetadataInput) {
 	}
 
 	total := performerCount + studioCount + tagCount
-	s.Status.setProgress(0, total)
+	status.setProgress(0, total)
 
 	logger.Infof("Starting autotag of %d performers, %d studios, %d tags", performerCount, studioCount, tagCount)
 
-	s.autoTagPerformers(input.Paths, performerIds)
-	s.autoTagStudios(input.Paths, studioIds)
-	s.autoTagTags(input.Paths, tagIds)
+	s.autoTagPerformers(status, input.Paths, performerIds)
+	s.autoTagStudios(status, input.Paths, studioIds)
+	s.autoTagTags(status, input.Paths, tagIds)
 
 	logger.Info("Finished autotag")
 }
 
-func (s *singleton) autoTagPerformers(paths []string, performerIds []string) {
-	if s.Status.stopping {
+func (s *singleton) autoTagPerformers(status *TaskStatus, paths []string, performerIds []string) {
+	if status.IsStopping() {
 		return
 	}
 
@@ -769,7 +1011,7 @@ func (s *singleton) autoTagPerformers(paths []string, performerIds []string) {
 			}
 
 			for _, performer := range performers {
-				if s.Status.stopping {
+				if status.IsStopping() {
 					logger.Info("Stopping due to user request")
 					return nil
 				}
@@ -790,7 +1032,7 @@ func (s *singleton) autoTagPerformers(paths []string, performerIds []string) {
 					return fmt.Errorf("error auto-tagging performer '%s': %s", performer.Name.String, err.Error())
 				}
 
-				s.Status.incrementProgress()
+				status.incrementProgress()
 			}
 
 			return nil
@@ -801,8 +1043,8 @@ func (s *singleton) autoTagPerformers(paths []string, performerIds []string) {
 	}
 }
 
-func (s *singleton) autoTagStudios(paths []string, studioIds []string) {
-	if s.Status.stopping {
+func (s *singleton) autoTagStudios(status *TaskStatus, paths []string, studioIds []string) {
+	if status.IsStopping() {
 		return
 	}
 
@@ -836,7 +1078,7 @@ func (s *singleton) autoTagStudios(paths []string, studioIds []string) {
 			}
 
 			for _, studio := range studios {
-				if s.Status.stopping {
+				if status.IsStopping() {
 					logger.Info("Stopping due to user request")
 					return nil
 				}
@@ -857,7 +1099,7 @@ func (s *singleton) autoTagStudios(paths []string, studioIds []string) {
 					return fmt.Errorf("error auto-tagging studio '%s': %s", studio.Name.String, err.Error())
 				}
 
-				s.Status.incrementProgress()
+				status.incrementProgress()
 			}
 
 			return nil
@@ -868,8 +1110,8 @@ func (s *singleton) autoTagStudios(paths []string, studioIds []string) {
 	}
 }
 
-func (s *singleton) autoTagTags(paths []string, tagIds []string) {
-	if s.Status.stopping {
+func (s *singleton) autoTagTags(status *TaskStatus, paths []string, tagIds []string) {
+	if status.IsStopping() {
 		return
 	}
 
@@ -897,7 +1139,7 @@ func (s *singleton) autoTagTags(paths []string, tagIds []string) {
 			}
 
 			for _, tag := range tags {
-				if s.Status.stopping {
+				if status.IsStopping() {
 					logger.Info("Stopping due to user request")
 					return nil
 				}
@@ -918,7 +1160,7 @@ func (s *singleton) autoTagTags(paths []string, tagIds []string) {
 					return fmt.Errorf("error auto-tagging tag '%s': %s", tag.Name, err.Error())
 				}
 
-				s.Status.incrementProgress()
+				status.incrementProgress()
 			}
 
 			return nil
@@ -929,15 +1171,15 @@ func (s *singleton) autoTagTags(paths []string, tagIds []string) {
 	}
 }
 
-func (s *singleton) Clean(input models.CleanMetadataInput) {
-	if s.Status.Status != Idle {
-		return
-	}
-	s.Status.SetStatus(Clean)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
+func (s *singleton) Clean(input models.CleanMetadataInput) *Job {
+	return s.queueJob(Clean, models.JobPriorityNormal, func(status *TaskStatus) {
+		report := &cleanReport{
+			CleanReport: models.CleanReport{
+				DryRun:  input.DryRun,
+				Objects: []*models.CleanObjectReport{},
+			},
+		}
+		defer report.finish(s.Paths.Generated.CleanReport)
 
 		var scenes []*models.Scene
 		var images []*models.Image
@@ -973,22 +1215,25 @@ func (s *singleton) Clean(input models.CleanMetadataInput) {
 			return nil
 		}); err != nil {
 			logger.Error(err.Error())
+			report.setError(err)
 			return
 		}
 
-		if s.Status.stopping {
+		if status.IsStopping() {
 			logger.Info("Stopping due to user request")
+			report.Stopped = true
 			return
 		}
 
 		var wg sync.WaitGroup
-		s.Status.Progress = 0
+		status.setProgressPercent(0)
 		total := len(scenes) + len(images) + len(galleries)
 		fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
 		for i, scene := range scenes {
-			s.Status.setProgress(i, total)
-			if s.Status.stopping {
+			status.setProgress(i, total)
+			if status.IsStopping() {
 				logger.Info("Stopping due to user request")
+				report.Stopped = true
 				return
 			}
 
@@ -1003,15 +1248,17 @@ func (s *singleton) Clean(input models.CleanMetadataInput) {
 				TxnManager:          s.TxnManager,
 				Scene:               scene,
 				fileNamingAlgorithm: fileNamingAlgo,
+				report:              report,
 			}
 			go task.Start(&wg, input.DryRun)
 			wg.Wait()
 		}
 
 		for i, img := range images {
-			s.Status.setProgress(len(scenes)+i, total)
-			if s.Status.stopping {
+			status.setProgress(len(scenes)+i, total)
+			if status.IsStopping() {
 				logger.Info("Stopping due to user request")
+				report.Stopped = true
 				return
 			}
 
@@ -1025,15 +1272,17 @@ func (s *singleton) Clean(input models.CleanMetadataInput) {
 			task := CleanTask{
 				TxnManager: s.TxnManager,
 				Image:      img,
+				report:     report,
 			}
 			go task.Start(&wg, input.DryRun)
 			wg.Wait()
 		}
 
 		for i, gallery := range galleries {
-			s.Status.setProgress(len(scenes)+len(galleries)+i, total)
-			if s.Status.stopping {
+			status.setProgress(len(scenes)+len(galleries)+i, total)
+			if status.IsStopping() {
 				logger.Info("Stopping due to user request")
+				report.Stopped = true
 				return
 			}
 
@@ -1047,25 +1296,24 @@ func (s *singleton) Clean(input models.CleanMetadataInput) {
 			task := CleanTask{
 				TxnManager: s.TxnManager,
 				Gallery:    gallery,
+				report:     report,
 			}
 			go task.Start(&wg, input.DryRun)
 			wg.Wait()
 		}
 
 		logger.Info("Finished Cleaning")
-	}()
+	})
 }
 
-func (s *singleton) MigrateHash() {
-	if s.Status.Status != Idle {
-		return
-	}
-	s.Status.SetStatus(Migrate)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
+// LastCleanReport returns the report of the last finished clean, or nil if
+// no clean has finished.
+func (s *singleton) LastCleanReport() (*models.CleanReport, error) {
+	return loadCleanReport(s.Paths.Generated.CleanReport)
+}
 
+func (s *singleton) MigrateHash() *Job {
+	return s.queueJob(Migrate, models.JobPriorityNormal, func(status *TaskStatus) {
 		fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
 		logger.Infof("Migrating generated files for %s naming hash", fileNamingAlgo.String())
 
@@ -1080,12 +1328,12 @@ func (s *singleton) MigrateHash() {
 		}
 
 		var wg sync.WaitGroup
-		s.Status.Progress = 0
+		status.setProgressPercent(0)
 		total := len(scenes)
 
 		for i, scene := range scenes {
-			s.Status.setProgress(i, total)
-			if s.Status.stopping {
+			status.setProgress(i, total)
+			if status.IsStopping() {
 				logger.Info("Stopping due to user request")
 				return
 			}
@@ -1103,20 +1351,81 @@ func (s *singleton) MigrateHash() {
 		}
 
 		logger.Info("Finished migrating")
-	}()
+	})
 }
 
-func (s *singleton) returnToIdleState() {
-	if r := recover(); r != nil {
-		logger.Info("recovered from ", r)
-	}
+// MigrateBlobs moves the performer, studio, tag and movie images to the
+// configured storage.
+func (s *singleton) MigrateBlobs() *Job {
+	return s.queueJob(MigrateBlobs, models.JobPriorityNormal, func(status *TaskStatus) {
+		task := MigrateBlobsTask{Status: status}
+		task.Start()
+	})
+}
 
-	if s.Status.Status == Generate {
-		instance.Paths.Generated.RemoveTmpDir()
-	}
-	s.Status.SetStatus(Idle)
-	s.Status.indefiniteProgress()
-	s.Status.stopping = false
+// UploadGenerated uploads the generated files to the configured object
+// storage.
+func (s *singleton) UploadGenerated() *Job {
+	return s.queueJob(UploadGenerated, models.JobPriorityNormal, func(status *TaskStatus) {
+		task := UploadGeneratedTask{Status: status, Store: s.GeneratedStore}
+		task.Start()
+	})
+}
+
+func (s *singleton) RepackageGalleries(input models.RepackageGalleriesInput) *Job {
+	return s.queueJob(RepackageGalleries, models.JobPriorityNormal, func(status *TaskStatus) {
+		ids, err := utils.StringSliceToIntSlice(input.Ids)
+		if err != nil {
+			logger.Errorf("invalid gallery ids: %s", err.Error())
+			return
+		}
+
+		var galleries []*models.Gallery
+		if err := s.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+			var err error
+			galleries, err = r.Gallery().FindMany(ids)
+			return err
+		}); err != nil {
+			logger.Errorf("failed to fetch galleries for repackaging: %s", err.Error())
+			return
+		}
+
+		var wg sync.WaitGroup
+		status.setProgressPercent(0)
+		total := len(galleries)
+
+		for i, g := range galleries {
+			status.setProgress(i, total)
+			if status.IsStopping() {
+				logger.Info("Stopping due to user request")
+				return
+			}
+
+			wg.Add(1)
+
+			task := RepackageGalleryTask{
+				TxnManager: s.TxnManager,
+				Gallery:    g,
+				Format:     input.Format,
+			}
+			go task.Start(&wg)
+			wg.Wait()
+		}
+
+		logger.Info("Finished repackaging galleries")
+	})
+}
+
+func (s *singleton) Recalculate() *Job {
+	return s.queueJob(Recalculate, models.JobPriorityNormal, func(status *TaskStatus) {
+		task := RecalculateTask{
+			TxnManager: s.TxnManager,
+			Status:     status,
+		}
+		task.Start()
+
+		logger.Info("Finished recalculating")
+	})
 }
 
 type totalsGenerate struct {
@@ -1126,6 +1435,7 @@ type totalsGenerate struct {
 	markers       int64
 	transcodes    int64
 	phashes       int64
+	heatmaps      int64
 }
 
 func (s *singleton) neededGenerate(scenes []*models.Scene, input models.GenerateMetadataInput) *totalsGenerate {
@@ -1143,21 +1453,30 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 	}()
 
 	fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
-	overwrite := false
-	if input.Overwrite != nil {
-		overwrite = *input.Overwrite
-	}
+	overwrite := newGenerateOverwrite(input)
 
 	logger.Infof("Counting content to generate...")
 	for _, scene := range scenes {
-		if scene != nil {
+		if scene != nil && input.InteractiveHeatmapsSpeeds != nil && *input.InteractiveHeatmapsSpeeds {
+			task := GenerateInteractiveHeatmapSpeedTask{
+				Scene:               *scene,
+				Overwrite:           overwrite.heatmaps,
+				fileNamingAlgorithm: fileNamingAlgo,
+			}
+
+			if task.shouldGenerate() {
+				totals.heatmaps++
+			}
+		}
+
+		if scene != nil && !scene.AudioOnly {
 			if input.Sprites {
 				task := GenerateSpriteTask{
 					Scene:               *scene,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 
-				if overwrite || task.required() {
+				if overwrite.sprites || task.required() {
 					totals.sprites++
 				}
 			}
@@ -1170,11 +1489,11 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 				}
 
 				sceneHash := scene.GetHash(task.fileNamingAlgorithm)
-				if overwrite || !task.doesVideoPreviewExist(sceneHash) {
+				if overwrite.previews || !task.doesVideoPreviewExist(sceneHash) {
 					totals.previews++
 				}
 
-				if input.ImagePreviews && (overwrite || !task.doesImagePreviewExist(sceneHash)) {
+				if input.ImagePreviews && (overwrite.previews || !task.doesImagePreviewExist(sceneHash)) {
 					totals.imagePreviews++
 				}
 			}
@@ -1183,7 +1502,7 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 				task := GenerateMarkersTask{
 					TxnManager:          s.TxnManager,
 					Scene:               scene,
-					Overwrite:           overwrite,
+					Overwrite:           overwrite.markers,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				totals.markers += int64(task.isMarkerNeeded())
@@ -1192,7 +1511,7 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 			if input.Transcodes {
 				task := GenerateTranscodeTask{
 					Scene:               *scene,
-					Overwrite:           overwrite,
+					Overwrite:           overwrite.transcodes,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 				if task.isTranscodeNeeded() {
@@ -1203,6 +1522,7 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 			if input.Phashes {
 				task := GeneratePhashTask{
 					Scene:               *scene,
+					Overwrite:           overwrite.phashes,
 					fileNamingAlgorithm: fileNamingAlgo,
 				}
 
@@ -1222,15 +1542,8 @@ func (s *singleton) neededGenerate(scenes []*models.Scene, input models.Generate
 	return &totals
 }
 
-func (s *singleton) StashBoxBatchPerformerTag(input models.StashBoxBatchPerformerTagInput) {
-	if s.Status.Status != Idle {
-		return
-	}
-	s.Status.SetStatus(StashBoxBatchPerformer)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
+func (s *singleton) StashBoxBatchPerformerTag(input models.StashBoxBatchPerformerTagInput) *Job {
+	return s.queueJob(StashBoxBatchPerformer, models.JobPriorityNormal, func(status *TaskStatus) {
 		logger.Infof("Initiating stash-box batch performer tag")
 
 		boxes := config.GetInstance().GetStashBoxes()
@@ -1309,11 +1622,10 @@ func (s *singleton) StashBoxBatchPerformerTag(input models.StashBoxBatchPerforme
 		}
 
 		if len(tasks) == 0 {
-			s.returnToIdleState()
 			return
 		}
 
-		s.Status.setProgress(0, len(tasks))
+		status.setProgress(0, len(tasks))
 
 		logger.Infof("Starting stash-box batch operation for %d performers", len(tasks))
 
@@ -1323,7 +1635,7 @@ func (s *singleton) StashBoxBatchPerformerTag(input models.StashBoxBatchPerforme
 			go task.Start(&wg)
 			wg.Wait()
 
-			s.Status.incrementProgress()
+			status.incrementProgress()
 		}
-	}()
+	})
 }
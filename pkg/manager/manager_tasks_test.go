@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEstimateRemaining(t *testing.T) {
+	assert.Equal(t, time.Duration(0), estimateRemaining(time.Minute, 0, 10))
+	assert.Equal(t, time.Duration(0), estimateRemaining(time.Minute, 10, 10))
+	assert.Equal(t, 3*time.Minute, estimateRemaining(time.Minute, 1, 4))
+	assert.Equal(t, 30*time.Second, estimateRemaining(time.Minute, 2, 3))
+}
+
+func TestTaskStatusObjects(t *testing.T) {
+	s := &TaskStatus{}
+
+	s.setObjectsTotal(2)
+	assert.Equal(t, 0, s.ObjectsDone)
+	assert.Equal(t, 2, s.ObjectsTotal)
+
+	s.objectDone()
+	assert.Equal(t, 1, s.ObjectsDone)
+
+	s.objectDone()
+	assert.Equal(t, 2, s.ObjectsDone)
+	assert.Equal(t, time.Duration(0), s.ETA)
+
+	// nil status is ignored
+	var nilStatus *TaskStatus
+	nilStatus.setObjectsTotal(1)
+	nilStatus.objectDone()
+	nilStatus.setPhase("phase")
+}
+
+func TestTaskStatusStopContext(t *testing.T) {
+	s := &TaskStatus{}
+
+	ctx, cancel := s.stopContext(context.Background())
+	defer cancel()
+
+	assert.Nil(t, ctx.Err())
+
+	s.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("context not cancelled after stopping")
+	}
+
+	// already stopped
+	ctx, cancel = s.stopContext(context.Background())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("context not cancelled for stopped task")
+	}
+}
+
+func TestStashOption(t *testing.T) {
+	yes := true
+	no := false
+
+	assert.True(t, stashOption(nil, &yes))
+	assert.False(t, stashOption(nil, nil))
+	assert.False(t, stashOption(&no, &yes))
+	assert.True(t, stashOption(&yes, &no))
+}
+
+func TestFindGenerateScenes(t *testing.T) {
+	studioID := "1"
+	sceneFilter := &models.SceneFilterType{
+		Studios: &models.MultiCriterionInput{
+			Value:    []string{studioID},
+			Modifier: models.CriterionModifierIncludes,
+		},
+	}
+	matching := []*models.Scene{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	qb := &mocks.SceneReaderWriter{}
+	qb.On("QueryIDs", sceneFilter, mock.MatchedBy(func(f *models.FindFilterType) bool {
+		return f.PerPage != nil && *f.PerPage == models.PerPageAll
+	})).Return([]int{1, 2, 3}, len(matching), nil)
+	qb.On("FindMany", []int{1, 2, 3}).Return(matching, nil)
+	qb.On("FindMany", []int{1, 3}).Return([]*models.Scene{matching[0], matching[2]}, nil)
+
+	scenes, err := findGenerateScenes(qb, sceneFilter, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, matching, scenes)
+
+	// restricted to the ids
+	scenes, err = findGenerateScenes(qb, sceneFilter, []int{3, 1, 4})
+	assert.Nil(t, err)
+	assert.Equal(t, []*models.Scene{matching[0], matching[2]}, scenes)
+
+	qb.AssertExpectations(t)
+}
+
+func TestNewGenerateOverwrite(t *testing.T) {
+	yes := true
+	no := false
+
+	assert.Equal(t, generateOverwrite{}, newGenerateOverwrite(models.GenerateMetadataInput{}))
+
+	// phashes are not regenerated by the overall flag
+	assert.Equal(t, generateOverwrite{
+		sprites:    true,
+		previews:   true,
+		markers:    true,
+		transcodes: true,
+		heatmaps:   true,
+	}, newGenerateOverwrite(models.GenerateMetadataInput{Overwrite: &yes}))
+
+	assert.Equal(t, generateOverwrite{
+		sprites:  true,
+		previews: false,
+		markers:  true,
+		phashes:  true,
+	}, newGenerateOverwrite(models.GenerateMetadataInput{
+		Overwrite:                          &yes,
+		OverwritePreviews:                  &no,
+		OverwriteTranscodes:                &no,
+		OverwritePhashes:                   &yes,
+		OverwriteInteractiveHeatmapsSpeeds: &no,
+	}))
+
+	assert.Equal(t, generateOverwrite{sprites: true}, newGenerateOverwrite(models.GenerateMetadataInput{
+		OverwriteSprites: &yes,
+	}))
+}
@@ -1,11 +1,9 @@
 package manager
 
 import (
-	"os"
 	"path/filepath"
 
 	"github.com/stashapp/stash/pkg/logger"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 func MigrateHash(oldHash string, newHash string) {
@@ -41,19 +39,14 @@ func MigrateHash(oldHash string, newHash string) {
 	oldPath = scenePaths.GetSpriteImageFilePath(oldHash)
 	newPath = scenePaths.GetSpriteImageFilePath(newHash)
 	migrate(oldPath, newPath)
+
+	oldPath = scenePaths.GetInteractiveHeatmapPath(oldHash)
+	newPath = scenePaths.GetInteractiveHeatmapPath(newHash)
+	migrate(oldPath, newPath)
 }
 
 func migrate(oldName, newName string) {
-	oldExists, err := utils.FileExists(oldName)
-	if err != nil && !os.IsNotExist(err) {
-		logger.Errorf("Error checking existence of %s: %s", oldName, err.Error())
-		return
-	}
-
-	if oldExists {
-		logger.Infof("renaming %s to %s", oldName, newName)
-		if err := os.Rename(oldName, newName); err != nil {
-			logger.Errorf("error renaming %s to %s: %s", oldName, newName, err.Error())
-		}
+	if err := instance.GeneratedStore.Rename(oldName, newName); err != nil {
+		logger.Errorf("error renaming %s to %s: %s", oldName, newName, err.Error())
 	}
 }
@@ -0,0 +1,466 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	notificationTimeout = 10 * time.Second
+
+	// maximum number of notifications waiting to be sent
+	notificationQueueSize = 100
+
+	defaultSMTPPort  = "587"
+	defaultSMTPSPort = "465"
+
+	gotifyKeyHeader = "X-Gotify-Key"
+
+	gotifyPriorityNormal = 5
+	gotifyPriorityFailed = 8
+)
+
+var notificationClient = &http.Client{
+	Timeout: notificationTimeout,
+}
+
+// notification reports a finished job.
+type notification struct {
+	Title   string
+	Message string
+	Job     string
+	State   string
+	Error   string
+
+	// event is the last webhook event of the job, which is posted to
+	// webhook sinks
+	event *webhookEvent
+
+	failed bool
+}
+
+var notifications = make(chan *notification, notificationQueueSize)
+
+func newJobNotification(j *Job, err error) *notification {
+	var verb string
+	switch j.State {
+	case models.JobStateFailed:
+		verb = "failed"
+	case models.JobStateCancelled:
+		verb = "stopped"
+	default:
+		verb = "finished"
+	}
+
+	job := j.Status.Status.String()
+	duration := j.FinishedAt.Sub(j.StartedAt).Round(time.Second)
+
+	n := &notification{
+		Title:   fmt.Sprintf("%s %s", job, verb),
+		Message: fmt.Sprintf("%s %s after %s.", job, verb, duration),
+		Job:     jobEventName(j.Status.Status),
+		State:   j.State.String(),
+		failed:  j.State == models.JobStateFailed,
+	}
+
+	if err != nil {
+		n.Error = err.Error()
+		n.Message = fmt.Sprintf("%s %s after %s: %s", job, verb, duration, err.Error())
+	}
+
+	events := jobFinishedEvents(j.Status.Status, j.State == models.JobStateCancelled, err)
+	n.event = events[len(events)-1]
+
+	return n
+}
+
+// queueJobNotification queues the notification of a finished job. Jobs with
+// high priority are not notified, since they are run for requests which wait
+// for them to finish.
+func queueJobNotification(j *Job, err error) {
+	if j.Priority == models.JobPriorityHigh {
+		return
+	}
+
+	n := newJobNotification(j, err)
+
+	// don't block the job queue waiting for notifications
+	select {
+	case notifications <- n:
+	default:
+		logger.Warnf("Notification queue is full. Dropping %s notification", n.Job)
+	}
+}
+
+// runNotifications sends the queued notifications to the configured sinks
+// until ctx is done.
+func runNotifications(ctx context.Context) {
+	for {
+		select {
+		case n := <-notifications:
+			sendNotification(config.GetInstance().GetNotificationSinks(), n)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sendNotification(sinks []*models.NotificationSink, n *notification) {
+	for _, s := range sinks {
+		if !notificationSinkMatches(s, n) {
+			continue
+		}
+
+		if err := notify(s, n); err != nil {
+			logger.Warnf("Error sending %s notification to %s: %s", n.Job, redactURL(s.URL), err.Error())
+		}
+	}
+}
+
+func notificationSinkMatches(s *models.NotificationSink, n *notification) bool {
+	if s.FailedOnly != nil && *s.FailedOnly && !n.failed {
+		return false
+	}
+
+	if len(s.Jobs) == 0 {
+		return true
+	}
+
+	for _, j := range s.Jobs {
+		if j == n.Job {
+			return true
+		}
+	}
+
+	return false
+}
+
+func notify(s *models.NotificationSink, n *notification) error {
+	switch s.Type {
+	case models.NotificationSinkTypeWebhook:
+		return notifyWebhook(s, n)
+	case models.NotificationSinkTypeEmail:
+		return notifyEmail(s, n)
+	case models.NotificationSinkTypeGotify:
+		return notifyGotify(s, n)
+	case models.NotificationSinkTypeNtfy:
+		return notifyNtfy(s, n)
+	}
+
+	return fmt.Errorf("unknown notification sink type %s", s.Type)
+}
+
+func postNotification(req *http.Request) error {
+	resp, err := notificationClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// notifyWebhook posts the job event to the sink, in the same format as sent
+// to webhooks.
+func notifyWebhook(s *models.NotificationSink, n *notification) error {
+	body, err := json.Marshal(n.event)
+	if err != nil {
+		return err
+	}
+
+	return postWebhook(&models.Webhook{URL: s.URL}, body)
+}
+
+func notifyGotify(s *models.NotificationSink, n *notification) error {
+	priority := gotifyPriorityNormal
+	if n.failed {
+		priority = gotifyPriorityFailed
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":    n.Title,
+		"message":  n.Message,
+		"priority": priority,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(s.URL, "/")+"/message", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != nil {
+		req.Header.Set(gotifyKeyHeader, *s.Token)
+	}
+
+	return postNotification(req)
+}
+
+func notifyNtfy(s *models.NotificationSink, n *notification) error {
+	req, err := http.NewRequest(http.MethodPost, s.URL, strings.NewReader(n.Message))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Title", n.Title)
+	if n.failed {
+		req.Header.Set("Priority", "high")
+		req.Header.Set("Tags", "warning")
+	}
+	if s.Token != nil && *s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+*s.Token)
+	}
+
+	return postNotification(req)
+}
+
+// emailFrom returns the sender address of emails sent to the sink.
+func emailFrom(s *models.NotificationSink, u *url.URL) string {
+	if s.From != nil && *s.From != "" {
+		return *s.From
+	}
+
+	return u.User.Username()
+}
+
+func buildEmail(from string, to []string, n *notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", n.Title)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(n.Message)
+	b.WriteString("\r\n")
+
+	return b.Bytes()
+}
+
+func notifyEmail(s *models.NotificationSink, n *notification) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return err
+	}
+
+	from := emailFrom(s, u)
+	msg := buildEmail(from, s.To, n)
+
+	// the envelope takes the addresses without names
+	envelopeFrom := envelopeAddress(from)
+	var envelopeTo []string
+	for _, a := range s.To {
+		envelopeTo = append(envelopeTo, envelopeAddress(a))
+	}
+
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = defaultSMTPPort
+		if u.Scheme == "smtps" {
+			port = defaultSMTPSPort
+		}
+	}
+	addr := net.JoinHostPort(host, port)
+
+	var auth smtp.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = smtp.PlainAuth("", u.User.Username(), password, host)
+	}
+
+	server := &smtpServer{
+		addr:        addr,
+		host:        host,
+		implicitTLS: u.Scheme == "smtps",
+		auth:        auth,
+		timeout:     notificationTimeout,
+	}
+
+	return server.sendMail(envelopeFrom, envelopeTo, msg)
+}
+
+func envelopeAddress(a string) string {
+	if parsed, err := mail.ParseAddress(a); err == nil {
+		return parsed.Address
+	}
+
+	return a
+}
+
+type smtpServer struct {
+	addr string
+	host string
+	// implicitTLS is true if the server expects TLS from the start of the
+	// connection. Otherwise STARTTLS is used if the server supports it.
+	implicitTLS bool
+	auth        smtp.Auth
+	// timeout is the time allowed to send the email, including the dial
+	timeout time.Duration
+}
+
+// sendMail sends an email to the server. Unlike smtp.SendMail, the whole
+// exchange must finish within the timeout of the server, so that an
+// unresponsive server does not block the notifications queue.
+func (s *smtpServer) sendMail(from string, to []string, msg []byte) error {
+	deadline := time.Now().Add(s.timeout)
+	dialer := &net.Dialer{Deadline: deadline}
+
+	var conn net.Conn
+	var err error
+	if s.implicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.addr, &tls.Config{ServerName: s.host})
+	} else {
+		conn, err = dialer.Dial("tcp", s.addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if !s.implicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err := c.Auth(s.auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// redactURL returns the URL without its password, for logging.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+
+	return u.String()
+}
+
+func isValidNotificationJob(job string) bool {
+	for _, j := range webhookJobs {
+		if job == jobEventName(j) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateNotificationSinks returns an error if any of the sinks has an
+// invalid URL, addresses or job.
+func ValidateNotificationSinks(sinks []*models.NotificationSinkInput) error {
+	for _, s := range sinks {
+		if !s.Type.IsValid() {
+			return fmt.Errorf("invalid notification sink type: %s", s.Type)
+		}
+
+		u, err := url.Parse(s.URL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid notification sink URL: %s", redactURL(s.URL))
+		}
+
+		if s.Type == models.NotificationSinkTypeEmail {
+			if u.Scheme != "smtp" && u.Scheme != "smtps" {
+				return fmt.Errorf("invalid email notification URL: %s", redactURL(s.URL))
+			}
+
+			if len(s.To) == 0 {
+				return fmt.Errorf("email notification sink %s has no recipients", u.Host)
+			}
+
+			addresses := s.To
+			if s.From != nil && *s.From != "" {
+				addresses = append([]string{*s.From}, addresses...)
+			} else if u.User.Username() == "" {
+				return fmt.Errorf("email notification sink %s has no sender address", u.Host)
+			}
+
+			for _, a := range addresses {
+				if _, err := mail.ParseAddress(a); err != nil {
+					return fmt.Errorf("invalid email address: %s", a)
+				}
+			}
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid notification sink URL: %s", redactURL(s.URL))
+		}
+
+		if s.Type == models.NotificationSinkTypeGotify && (s.Token == nil || *s.Token == "") {
+			return fmt.Errorf("gotify notification sink %s has no token", u.Host)
+		}
+
+		for _, j := range s.Jobs {
+			if !isValidNotificationJob(j) {
+				return fmt.Errorf("invalid notification job: %s", j)
+			}
+		}
+	}
+
+	return nil
+}
@@ -12,13 +12,19 @@ const thumbDirDepth int = 2
 const thumbDirLength int = 2 // thumbDirDepth * thumbDirLength must be smaller than the length of checksum
 
 type generatedPaths struct {
-	Screenshots string
-	Thumbnails  string
-	Vtt         string
-	Markers     string
-	Transcodes  string
-	Downloads   string
-	Tmp         string
+	Screenshots         string
+	Thumbnails          string
+	Vtt                 string
+	Markers             string
+	Transcodes          string
+	InteractiveHeatmaps string
+	Downloads           string
+	Tmp                 string
+
+	// ImportReport holds the report of the last finished import
+	ImportReport string
+	// CleanReport holds the report of the last finished clean
+	CleanReport string
 }
 
 func newGeneratedPaths(path string) *generatedPaths {
@@ -28,8 +34,11 @@ func newGeneratedPaths(path string) *generatedPaths {
 	gp.Vtt = filepath.Join(path, "vtt")
 	gp.Markers = filepath.Join(path, "markers")
 	gp.Transcodes = filepath.Join(path, "transcodes")
+	gp.InteractiveHeatmaps = filepath.Join(path, "interactive_heatmaps")
 	gp.Downloads = filepath.Join(path, "download_stage")
 	gp.Tmp = filepath.Join(path, "tmp")
+	gp.ImportReport = filepath.Join(path, "import_report.json")
+	gp.CleanReport = filepath.Join(path, "clean_report.json")
 	return &gp
 }
 
@@ -61,7 +70,15 @@ func (gp *generatedPaths) TempDir(pattern string) (string, error) {
 	return ret, nil
 }
 
-func (gp *generatedPaths) GetThumbnailPath(checksum string, width int) string {
-	fname := fmt.Sprintf("%s_%d.jpg", checksum, width)
-	return filepath.Join(gp.Thumbnails, utils.GetIntraDir(checksum, thumbDirDepth, thumbDirLength), fname)
+// GetThumbnailDir returns the directory of the thumbnails of the image with
+// the checksum.
+func (gp *generatedPaths) GetThumbnailDir(checksum string) string {
+	return filepath.Join(gp.Thumbnails, utils.GetIntraDir(checksum, thumbDirDepth, thumbDirLength))
+}
+
+// GetThumbnailPath returns the path of the thumbnail of the image with the
+// checksum, with the size and file extension.
+func (gp *generatedPaths) GetThumbnailPath(checksum string, size int, ext string) string {
+	fname := fmt.Sprintf("%s_%d.%s", checksum, size, ext)
+	return filepath.Join(gp.GetThumbnailDir(checksum), fname)
 }
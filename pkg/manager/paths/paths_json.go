@@ -12,6 +12,11 @@ type JSONPaths struct {
 	MappingsFile string
 	ScrapedFile  string
 
+	// ObjectsFile and CompressedObjectsFile hold all objects in a single
+	// JSON lines file, as an alternative to the mappings and object files
+	ObjectsFile           string
+	CompressedObjectsFile string
+
 	Performers string
 	Scenes     string
 	Images     string
@@ -19,6 +24,10 @@ type JSONPaths struct {
 	Studios    string
 	Tags       string
 	Movies     string
+
+	// Blobs holds the images of performers, studios and movies which are
+	// exported as separate files, in a directory for each object type
+	Blobs string
 }
 
 func newJSONPaths(baseDir string) *JSONPaths {
@@ -26,6 +35,8 @@ func newJSONPaths(baseDir string) *JSONPaths {
 	jp.Metadata = baseDir
 	jp.MappingsFile = filepath.Join(baseDir, "mappings.json")
 	jp.ScrapedFile = filepath.Join(baseDir, "scraped.json")
+	jp.ObjectsFile = filepath.Join(baseDir, "objects.jsonl")
+	jp.CompressedObjectsFile = filepath.Join(baseDir, "objects.jsonl.gz")
 	jp.Performers = filepath.Join(baseDir, "performers")
 	jp.Scenes = filepath.Join(baseDir, "scenes")
 	jp.Images = filepath.Join(baseDir, "images")
@@ -33,6 +44,7 @@ func newJSONPaths(baseDir string) *JSONPaths {
 	jp.Studios = filepath.Join(baseDir, "studios")
 	jp.Movies = filepath.Join(baseDir, "movies")
 	jp.Tags = filepath.Join(baseDir, "tags")
+	jp.Blobs = filepath.Join(baseDir, "blobs")
 	return &jp
 }
 
@@ -80,3 +92,9 @@ func (jp *JSONPaths) TagJSONPath(checksum string) string {
 func (jp *JSONPaths) MovieJSONPath(checksum string) string {
 	return filepath.Join(jp.Movies, checksum+".json")
 }
+
+// BlobPath returns the path of an image exported as a separate file. The
+// directory is the directory of the object type, such as performers.
+func (jp *JSONPaths) BlobPath(dir string, name string) string {
+	return filepath.Join(jp.Blobs, dir, name)
+}
@@ -27,6 +27,10 @@ func (sp *scenePaths) GetTranscodePath(checksum string) string {
 	return filepath.Join(sp.generated.Transcodes, checksum+".mp4")
 }
 
+func (sp *scenePaths) GetInteractiveHeatmapPath(checksum string) string {
+	return filepath.Join(sp.generated.InteractiveHeatmaps, checksum+".png")
+}
+
 func (sp *scenePaths) GetStreamPath(scenePath string, checksum string) string {
 	transcodePath := sp.GetTranscodePath(checksum)
 	transcodeExists, _ := utils.FileExists(transcodePath)
@@ -0,0 +1,381 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/graphql"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	// timeout of each request to the remote stash. Queries for all objects
+	// of a type may take some time for large libraries.
+	remoteStashTimeout = 5 * time.Minute
+
+	remoteStashAPIKeyHeader = "ApiKey"
+)
+
+// remoteStash queries the GraphQL API of another stash instance.
+type remoteStash struct {
+	url    string
+	http   *http.Client
+	client *graphql.Client
+}
+
+func newRemoteStash(stashURL string, apiKey string) *remoteStash {
+	stashURL = strings.TrimRight(stashURL, "/")
+
+	httpClient := &http.Client{
+		Transport: &apiKeyTransport{apiKey: apiKey},
+		Timeout:   remoteStashTimeout,
+	}
+
+	return &remoteStash{
+		url:    stashURL,
+		http:   httpClient,
+		client: graphql.NewClient(stashURL+"/graphql", httpClient),
+	}
+}
+
+// apiKeyTransport sets the API key header on each request.
+type apiKeyTransport struct {
+	apiKey string
+}
+
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.apiKey != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(remoteStashAPIKeyHeader, t.apiKey)
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// getImage returns the base64 encoded image at the path of the remote stash.
+func (s *remoteStash) getImage(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("http error %d getting %s", resp.StatusCode, path)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.GetBase64StringFromData(body), nil
+}
+
+// missingImage returns the is_missing filter value for objects without
+// images. The remote stash serves a default image for objects without an
+// image, so these objects are excluded when fetching images.
+func missingImage() *string {
+	ret := "image"
+	return &ret
+}
+
+func allPages() models.FindFilterType {
+	perPage := models.PerPageAll
+	return models.FindFilterType{
+		PerPage: &perPage,
+	}
+}
+
+type remoteID struct {
+	ID string `graphql:"id"`
+}
+
+type remoteName struct {
+	Name string `graphql:"name"`
+}
+
+func remoteNames(objects []remoteName) []string {
+	var ret []string
+	for _, o := range objects {
+		ret = append(ret, o.Name)
+	}
+
+	return ret
+}
+
+func remoteIDSet(objects []remoteID) map[string]bool {
+	ret := make(map[string]bool)
+	for _, o := range objects {
+		ret[o.ID] = true
+	}
+
+	return ret
+}
+
+type remoteStashID struct {
+	Endpoint string `graphql:"endpoint"`
+	StashID  string `graphql:"stash_id"`
+}
+
+func remoteStashIDs(ids []remoteStashID) []models.StashID {
+	var ret []models.StashID
+	for _, id := range ids {
+		ret = append(ret, models.StashID{
+			Endpoint: id.Endpoint,
+			StashID:  id.StashID,
+		})
+	}
+
+	return ret
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+
+	return *i
+}
+
+type remoteTag struct {
+	ID   string `graphql:"id"`
+	Name string `graphql:"name"`
+}
+
+func (t remoteTag) toJSON() *jsonschema.Tag {
+	return &jsonschema.Tag{
+		Name: t.Name,
+	}
+}
+
+func (s *remoteStash) getTags(ctx context.Context) ([]remoteTag, map[string]bool, error) {
+	var q struct {
+		AllTags     []remoteTag `graphql:"allTags"`
+		MissingTags struct {
+			Tags []remoteID `graphql:"tags"`
+		} `graphql:"findTags(tag_filter: $tf, filter: $f)"`
+	}
+
+	vars := map[string]interface{}{
+		"tf": models.TagFilterType{IsMissing: missingImage()},
+		"f":  allPages(),
+	}
+
+	if err := s.client.Query(ctx, &q, vars); err != nil {
+		return nil, nil, err
+	}
+
+	return q.AllTags, remoteIDSet(q.MissingTags.Tags), nil
+}
+
+type remotePerformer struct {
+	ID           string          `graphql:"id"`
+	Name         *string         `graphql:"name"`
+	Gender       *string         `graphql:"gender"`
+	URL          *string         `graphql:"url"`
+	Twitter      *string         `graphql:"twitter"`
+	Instagram    *string         `graphql:"instagram"`
+	Birthdate    *string         `graphql:"birthdate"`
+	Ethnicity    *string         `graphql:"ethnicity"`
+	Country      *string         `graphql:"country"`
+	EyeColor     *string         `graphql:"eye_color"`
+	Height       *string         `graphql:"height"`
+	Measurements *string         `graphql:"measurements"`
+	FakeTits     *string         `graphql:"fake_tits"`
+	CareerLength *string         `graphql:"career_length"`
+	Tattoos      *string         `graphql:"tattoos"`
+	Piercings    *string         `graphql:"piercings"`
+	Aliases      *string         `graphql:"aliases"`
+	Favorite     bool            `graphql:"favorite"`
+	Tags         []remoteName    `graphql:"tags"`
+	Rating       *int            `graphql:"rating"`
+	Details      *string         `graphql:"details"`
+	DeathDate    *string         `graphql:"death_date"`
+	HairColor    *string         `graphql:"hair_color"`
+	Weight       *int            `graphql:"weight"`
+	StashIDs     []remoteStashID `graphql:"stash_ids"`
+}
+
+func (p remotePerformer) toJSON() *jsonschema.Performer {
+	return &jsonschema.Performer{
+		Name:         stringValue(p.Name),
+		Gender:       stringValue(p.Gender),
+		URL:          stringValue(p.URL),
+		Twitter:      stringValue(p.Twitter),
+		Instagram:    stringValue(p.Instagram),
+		Birthdate:    stringValue(p.Birthdate),
+		Ethnicity:    stringValue(p.Ethnicity),
+		Country:      stringValue(p.Country),
+		EyeColor:     stringValue(p.EyeColor),
+		Height:       stringValue(p.Height),
+		Measurements: stringValue(p.Measurements),
+		FakeTits:     stringValue(p.FakeTits),
+		CareerLength: stringValue(p.CareerLength),
+		Tattoos:      stringValue(p.Tattoos),
+		Piercings:    stringValue(p.Piercings),
+		Aliases:      stringValue(p.Aliases),
+		Favorite:     p.Favorite,
+		Tags:         remoteNames(p.Tags),
+		Rating:       intValue(p.Rating),
+		Details:      stringValue(p.Details),
+		DeathDate:    stringValue(p.DeathDate),
+		HairColor:    stringValue(p.HairColor),
+		Weight:       intValue(p.Weight),
+		StashIDs:     remoteStashIDs(p.StashIDs),
+	}
+}
+
+func (s *remoteStash) getPerformers(ctx context.Context) ([]remotePerformer, map[string]bool, error) {
+	var q struct {
+		AllPerformers     []remotePerformer `graphql:"allPerformers"`
+		MissingPerformers struct {
+			Performers []remoteID `graphql:"performers"`
+		} `graphql:"findPerformers(performer_filter: $pf, filter: $f)"`
+	}
+
+	vars := map[string]interface{}{
+		"pf": models.PerformerFilterType{IsMissing: missingImage()},
+		"f":  allPages(),
+	}
+
+	if err := s.client.Query(ctx, &q, vars); err != nil {
+		return nil, nil, err
+	}
+
+	return q.AllPerformers, remoteIDSet(q.MissingPerformers.Performers), nil
+}
+
+type remoteStudio struct {
+	ID           string          `graphql:"id"`
+	Name         string          `graphql:"name"`
+	URL          *string         `graphql:"url"`
+	ParentStudio *remoteName     `graphql:"parent_studio"`
+	Rating       *int            `graphql:"rating"`
+	Details      *string         `graphql:"details"`
+	Country      *string         `graphql:"country"`
+	StashIDs     []remoteStashID `graphql:"stash_ids"`
+}
+
+func (s remoteStudio) toJSON() *jsonschema.Studio {
+	ret := &jsonschema.Studio{
+		Name:     s.Name,
+		URL:      stringValue(s.URL),
+		Rating:   intValue(s.Rating),
+		Details:  stringValue(s.Details),
+		Country:  stringValue(s.Country),
+		StashIDs: remoteStashIDs(s.StashIDs),
+	}
+
+	if s.ParentStudio != nil {
+		ret.ParentStudio = s.ParentStudio.Name
+	}
+
+	return ret
+}
+
+func (s *remoteStash) getStudios(ctx context.Context) ([]remoteStudio, map[string]bool, error) {
+	var q struct {
+		AllStudios     []remoteStudio `graphql:"allStudios"`
+		MissingStudios struct {
+			Studios []remoteID `graphql:"studios"`
+		} `graphql:"findStudios(studio_filter: $sf, filter: $f)"`
+	}
+
+	vars := map[string]interface{}{
+		"sf": models.StudioFilterType{IsMissing: missingImage()},
+		"f":  allPages(),
+	}
+
+	if err := s.client.Query(ctx, &q, vars); err != nil {
+		return nil, nil, err
+	}
+
+	return q.AllStudios, remoteIDSet(q.MissingStudios.Studios), nil
+}
+
+type remoteScene struct {
+	Title      *string         `graphql:"title"`
+	Details    *string         `graphql:"details"`
+	URL        *string         `graphql:"url"`
+	Date       *string         `graphql:"date"`
+	Studio     *remoteName     `graphql:"studio"`
+	Performers []remoteName    `graphql:"performers"`
+	Tags       []remoteName    `graphql:"tags"`
+	StashIDs   []remoteStashID `graphql:"stash_ids"`
+}
+
+// mergeInto sets the metadata of the remote scene in the JSON of the local
+// scene. Metadata which is not set on the remote scene is left unchanged.
+// Performers and tags are added to those of the local scene.
+func (s remoteScene) mergeInto(sceneJSON *jsonschema.Scene) {
+	if v := stringValue(s.Title); v != "" {
+		sceneJSON.Title = v
+	}
+	if v := stringValue(s.Details); v != "" {
+		sceneJSON.Details = v
+	}
+	if v := stringValue(s.URL); v != "" {
+		sceneJSON.URL = v
+	}
+	if v := stringValue(s.Date); v != "" {
+		sceneJSON.Date = v
+	}
+	if s.Studio != nil {
+		sceneJSON.Studio = s.Studio.Name
+	}
+
+	sceneJSON.Performers = utils.StrAppendUniques(sceneJSON.Performers, remoteNames(s.Performers))
+	sceneJSON.Tags = utils.StrAppendUniques(sceneJSON.Tags, remoteNames(s.Tags))
+
+	// the importer merges the stash IDs with those of the existing scene
+	if len(s.StashIDs) > 0 {
+		sceneJSON.StashIDs = remoteStashIDs(s.StashIDs)
+	}
+}
+
+// findScene returns the remote scene with either of the hashes, or nil if
+// there is no such scene.
+func (s *remoteStash) findScene(ctx context.Context, checksum string, oshash string) (*remoteScene, error) {
+	var q struct {
+		FindSceneByHash *remoteScene `graphql:"findSceneByHash(input: $i)"`
+	}
+
+	input := models.SceneHashInput{}
+	if checksum != "" {
+		input.Checksum = &checksum
+	}
+	if oshash != "" {
+		input.Oshash = &oshash
+	}
+
+	vars := map[string]interface{}{
+		"i": input,
+	}
+
+	if err := s.client.Query(ctx, &q, vars); err != nil {
+		return nil, err
+	}
+
+	return q.FindSceneByHash, nil
+}
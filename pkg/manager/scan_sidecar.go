@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// mergeSidecarMetadata sets the metadata fields which are set in the sidecar
+// in the scene JSON.
+func mergeSidecarMetadata(sceneJSON *jsonschema.Scene, sidecar *jsonschema.Scene) {
+	if sidecar.Title != "" {
+		sceneJSON.Title = sidecar.Title
+	}
+	if sidecar.Details != "" {
+		sceneJSON.Details = sidecar.Details
+	}
+	if sidecar.URL != "" {
+		sceneJSON.URL = sidecar.URL
+	}
+	if sidecar.Date != "" {
+		sceneJSON.Date = sidecar.Date
+	}
+	if sidecar.Rating != 0 {
+		sceneJSON.Rating = sidecar.Rating
+	}
+	if sidecar.Studio != "" {
+		sceneJSON.Studio = sidecar.Studio
+	}
+	if len(sidecar.Performers) > 0 {
+		sceneJSON.Performers = sidecar.Performers
+	}
+	if len(sidecar.Tags) > 0 {
+		sceneJSON.Tags = sidecar.Tags
+	}
+	if len(sidecar.Movies) > 0 {
+		sceneJSON.Movies = sidecar.Movies
+	}
+}
+
+// applySidecarMetadata sets the metadata of a newly created scene from its
+// sidecar file, if it has one. Errors are logged, and leave the scene
+// without the sidecar metadata.
+func (t *ScanTask) applySidecarMetadata(r models.Repository, s *models.Scene) {
+	sidecar, err := scene.ReadSidecar(s.Path)
+	if err != nil {
+		logger.Warnf("error reading sidecar file of %s: %s", s.Path, err.Error())
+		return
+	}
+	if sidecar == nil {
+		return
+	}
+
+	t.applyMetadata(r, s, sidecar, "sidecar file")
+}
+
+// applyMetadata sets the metadata fields which are set in metadata on a
+// newly created scene. The metadata is applied using the scene importer,
+// so that missing studios, performers, tags and movies are handled
+// according to SidecarMissingRefBehaviour. Errors are logged, and leave
+// the scene without the metadata.
+func (t *ScanTask) applyMetadata(r models.Repository, s *models.Scene, metadata *jsonschema.Scene, source string) {
+	// the scene may have been updated since it was created
+	current, err := r.Scene().Find(s.ID)
+	if err != nil || current == nil {
+		logger.Warnf("error getting scene %s: %v", s.Path, err)
+		return
+	}
+
+	sceneJSON, err := scene.ToBasicJSON(r.Scene(), current)
+	if err != nil {
+		logger.Warnf("error getting JSON of %s: %s", s.Path, err.Error())
+		return
+	}
+
+	mergeSidecarMetadata(sceneJSON, metadata)
+
+	missingRefBehaviour := t.SidecarMissingRefBehaviour
+	if !missingRefBehaviour.IsValid() {
+		missingRefBehaviour = models.ImportMissingRefEnumCreate
+	}
+
+	importer := &scene.Importer{
+		ReaderWriter:        r.Scene(),
+		StudioWriter:        r.Studio(),
+		GalleryWriter:       r.Gallery(),
+		PerformerWriter:     r.Performer(),
+		MovieWriter:         r.Movie(),
+		TagWriter:           r.Tag(),
+		Input:               *sceneJSON,
+		Path:                s.Path,
+		MissingRefBehaviour: missingRefBehaviour,
+		FileNamingAlgorithm: t.fileNamingAlgorithm,
+	}
+
+	if err := importer.PreImport(); err != nil {
+		logger.Warnf("error applying metadata from %s to %s: %s", source, s.Path, err.Error())
+		return
+	}
+
+	if err := importer.Update(s.ID); err != nil {
+		logger.Warnf("error applying metadata from %s to %s: %s", source, s.Path, err.Error())
+		return
+	}
+
+	if err := importer.PostImport(s.ID); err != nil {
+		logger.Warnf("error applying metadata from %s to %s: %s", source, s.Path, err.Error())
+		return
+	}
+
+	logger.Infof("Set metadata of %s from %s", s.Path, source)
+}
@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSidecarMetadata(t *testing.T) {
+	sceneJSON := &jsonschema.Scene{
+		Title:    "filename",
+		Checksum: "checksum",
+		File: &jsonschema.SceneFile{
+			Size: "100",
+		},
+	}
+
+	mergeSidecarMetadata(sceneJSON, &jsonschema.Scene{
+		Date:       "2001-01-01",
+		Performers: []string{"performer"},
+	})
+
+	assert.Equal(t, &jsonschema.Scene{
+		Title:      "filename",
+		Checksum:   "checksum",
+		Date:       "2001-01-01",
+		Performers: []string{"performer"},
+		File: &jsonschema.SceneFile{
+			Size: "100",
+		},
+	}, sceneJSON)
+
+	mergeSidecarMetadata(sceneJSON, &jsonschema.Scene{
+		Title:  "title",
+		Studio: "studio",
+		Tags:   []string{"tag"},
+	})
+
+	assert.Equal(t, "title", sceneJSON.Title)
+	assert.Equal(t, "studio", sceneJSON.Studio)
+	assert.Equal(t, []string{"tag"}, sceneJSON.Tags)
+	assert.Equal(t, []string{"performer"}, sceneJSON.Performers)
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 // DestroyScene deletes a scene and its associated relationships from the
@@ -66,80 +65,27 @@ func DeleteGeneratedSceneFiles(scene *models.Scene, fileNamingAlgo models.HashAl
 		return
 	}
 
-	markersFolder := filepath.Join(GetInstance().Paths.Generated.Markers, sceneHash)
+	store := GetInstance().GeneratedStore
+	store.RemoveAll(filepath.Join(GetInstance().Paths.Generated.Markers, sceneHash))
 
-	exists, _ := utils.FileExists(markersFolder)
-	if exists {
-		err := os.RemoveAll(markersFolder)
-		if err != nil {
-			logger.Warnf("Could not delete folder %s: %s", markersFolder, err.Error())
-		}
-	}
-
-	thumbPath := GetInstance().Paths.Scene.GetThumbnailScreenshotPath(sceneHash)
-	exists, _ = utils.FileExists(thumbPath)
-	if exists {
-		err := os.Remove(thumbPath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", thumbPath, err.Error())
-		}
-	}
-
-	normalPath := GetInstance().Paths.Scene.GetScreenshotPath(sceneHash)
-	exists, _ = utils.FileExists(normalPath)
-	if exists {
-		err := os.Remove(normalPath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", normalPath, err.Error())
-		}
-	}
-
-	streamPreviewPath := GetInstance().Paths.Scene.GetStreamPreviewPath(sceneHash)
-	exists, _ = utils.FileExists(streamPreviewPath)
-	if exists {
-		err := os.Remove(streamPreviewPath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", streamPreviewPath, err.Error())
-		}
-	}
-
-	streamPreviewImagePath := GetInstance().Paths.Scene.GetStreamPreviewImagePath(sceneHash)
-	exists, _ = utils.FileExists(streamPreviewImagePath)
-	if exists {
-		err := os.Remove(streamPreviewImagePath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", streamPreviewImagePath, err.Error())
-		}
-	}
-
-	transcodePath := GetInstance().Paths.Scene.GetTranscodePath(sceneHash)
-	exists, _ = utils.FileExists(transcodePath)
-	if exists {
+	scenePaths := GetInstance().Paths.Scene
+	transcodePath := scenePaths.GetTranscodePath(sceneHash)
+	if store.Exists(transcodePath) {
 		// kill any running streams
 		KillRunningStreams(transcodePath)
-
-		err := os.Remove(transcodePath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", transcodePath, err.Error())
-		}
-	}
-
-	spritePath := GetInstance().Paths.Scene.GetSpriteImageFilePath(sceneHash)
-	exists, _ = utils.FileExists(spritePath)
-	if exists {
-		err := os.Remove(spritePath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", spritePath, err.Error())
-		}
 	}
 
-	vttPath := GetInstance().Paths.Scene.GetSpriteVttFilePath(sceneHash)
-	exists, _ = utils.FileExists(vttPath)
-	if exists {
-		err := os.Remove(vttPath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", vttPath, err.Error())
-		}
+	for _, path := range []string{
+		scenePaths.GetThumbnailScreenshotPath(sceneHash),
+		scenePaths.GetScreenshotPath(sceneHash),
+		scenePaths.GetStreamPreviewPath(sceneHash),
+		scenePaths.GetStreamPreviewImagePath(sceneHash),
+		transcodePath,
+		scenePaths.GetSpriteImageFilePath(sceneHash),
+		scenePaths.GetSpriteVttFilePath(sceneHash),
+		scenePaths.GetInteractiveHeatmapPath(sceneHash),
+	} {
+		store.Remove(path)
 	}
 }
 
@@ -149,21 +95,8 @@ func DeleteSceneMarkerFiles(scene *models.Scene, seconds int, fileNamingAlgo mod
 	videoPath := GetInstance().Paths.SceneMarkers.GetStreamPath(scene.GetHash(fileNamingAlgo), seconds)
 	imagePath := GetInstance().Paths.SceneMarkers.GetStreamPreviewImagePath(scene.GetHash(fileNamingAlgo), seconds)
 
-	exists, _ := utils.FileExists(videoPath)
-	if exists {
-		err := os.Remove(videoPath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", videoPath, err.Error())
-		}
-	}
-
-	exists, _ = utils.FileExists(imagePath)
-	if exists {
-		err := os.Remove(imagePath)
-		if err != nil {
-			logger.Warnf("Could not delete file %s: %s", videoPath, err.Error())
-		}
-	}
+	GetInstance().GeneratedStore.Remove(videoPath)
+	GetInstance().GeneratedStore.Remove(imagePath)
 }
 
 // DeleteSceneFile deletes the scene video file from the filesystem.
@@ -230,6 +163,10 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL string, maxStreami
 		return nil, fmt.Errorf("nil scene")
 	}
 
+	if scene.AudioOnly {
+		return getAudioStreamPaths(scene, directStreamURL), nil
+	}
+
 	var ret []*models.SceneStreamEndpoint
 	mimeWebm := ffmpeg.MimeWebm
 	mimeHLS := ffmpeg.MimeHLS
@@ -336,6 +273,92 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL string, maxStreami
 	return ret, nil
 }
 
+// GetSceneCastStream returns the URL and MIME type of the stream of the scene
+// to load on cast devices. The file is streamed directly if it has been
+// transcoded or cast devices can play it, otherwise it is transcoded to HLS,
+// which unlike the other transcoded streams can be seeked by the devices.
+// Audio files are always transcoded to MP4.
+func GetSceneCastStream(scene *models.Scene, directStreamURL string) (string, string) {
+	if scene.AudioOnly {
+		return directStreamURL + ".mp4", ffmpeg.MimeMp4
+	}
+
+	if HasTranscode(scene, config.GetInstance().GetVideoFileNamingAlgorithm()) {
+		return directStreamURL, ffmpeg.MimeMp4
+	}
+
+	audioCodec := ffmpeg.MissingUnsupported
+	if scene.AudioCodec.Valid {
+		audioCodec = ffmpeg.AudioCodec(scene.AudioCodec.String)
+	}
+
+	container, _ := GetSceneFileContainer(scene)
+	if ffmpeg.IsCastable(scene.VideoCodec.String, audioCodec, container) {
+		if container == ffmpeg.Webm {
+			return directStreamURL, ffmpeg.MimeWebm
+		}
+		return directStreamURL, ffmpeg.MimeMp4
+	}
+
+	return directStreamURL + ".m3u8", ffmpeg.MimeHLS
+}
+
+// getAudioStreamPaths returns the streams of an audio file. The file is
+// streamed directly if browsers support its codec, otherwise the audio is
+// transcoded to AAC in an MP4 container. There are no quality levels to
+// choose between.
+func getAudioStreamPaths(scene *models.Scene, directStreamURL string) []*models.SceneStreamEndpoint {
+	var ret []*models.SceneStreamEndpoint
+	// the player only distinguishes between video mime types
+	mimeMp4 := ffmpeg.MimeMp4
+
+	if ffmpeg.IsStreamableAudio(ffmpeg.AudioCodec(scene.AudioCodec.String)) {
+		label := "Direct stream"
+		ret = append(ret, &models.SceneStreamEndpoint{
+			URL:      directStreamURL,
+			MimeType: &mimeMp4,
+			Label:    &label,
+		})
+	}
+
+	label := "MP4 Audio"
+	ret = append(ret, &models.SceneStreamEndpoint{
+		URL:      directStreamURL + ".mp4",
+		MimeType: &mimeMp4,
+		Label:    &label,
+	})
+
+	return ret
+}
+
+// generatedFileExists returns true if the generated file of the provided
+// type exists for the scene with the provided hashes. It is used by the
+// database to filter scenes by missing generated files.
+func generatedFileExists(fileType string, checksum string, oshash string) bool {
+	sceneHash := oshash
+	if config.GetInstance().GetVideoFileNamingAlgorithm() == models.HashAlgorithmMd5 {
+		sceneHash = checksum
+	}
+
+	if sceneHash == "" {
+		return false
+	}
+
+	var path string
+	switch fileType {
+	case "preview":
+		path = instance.Paths.Scene.GetStreamPreviewPath(sceneHash)
+	case "sprite":
+		path = instance.Paths.Scene.GetSpriteImageFilePath(sceneHash)
+	case "heatmap":
+		path = instance.Paths.Scene.GetInteractiveHeatmapPath(sceneHash)
+	default:
+		return false
+	}
+
+	return instance.GeneratedStore.Exists(path)
+}
+
 // HasTranscode returns true if a transcoded video exists for the provided
 // scene. It will check using the OSHash of the scene first, then fall back
 // to the checksum.
@@ -350,6 +373,5 @@ func HasTranscode(scene *models.Scene, fileNamingAlgo models.HashAlgorithm) bool
 	}
 
 	transcodePath := instance.Paths.Scene.GetTranscodePath(sceneHash)
-	ret, _ := utils.FileExists(transcodePath)
-	return ret
+	return instance.GeneratedStore.Exists(transcodePath)
 }
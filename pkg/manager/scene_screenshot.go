@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"github.com/disintegration/imaging"
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
 
 	// needed to decode other image formats
 	_ "image/gif"
@@ -55,7 +57,22 @@ func SetSceneScreenshot(checksum string, imageData []byte) error {
 		return err
 	}
 
-	err = writeImage(normalPath, imageData)
+	if err := writeImage(normalPath, imageData); err != nil {
+		return err
+	}
 
-	return err
+	storeGenerated(thumbPath, normalPath)
+	return nil
+}
+
+// CaptureSceneFrame returns the frame of the scene file at the time, in
+// seconds, as a JPEG image. The frame is scaled to width if it is positive.
+func CaptureSceneFrame(scene *models.Scene, at float64, width int) ([]byte, error) {
+	probeResult, err := ffmpeg.NewVideoFile(instance.FFProbePath, scene.Path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+	return encoder.Frame(*probeResult, at, width)
 }
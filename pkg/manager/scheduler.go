@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// how often to check whether a scheduled task is due
+const schedulerCheckInterval = time.Minute
+
+// runScheduler adds the scheduled tasks to the job queue when they are due,
+// until ctx is done.
+func runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			GetInstance().runScheduledTasks(time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ValidateSchedule returns an error if schedule is not a valid cron
+// expression.
+func ValidateSchedule(schedule string) error {
+	_, err := utils.ParseCron(schedule)
+	return err
+}
+
+// NextScheduledRun returns the next time the task is due, in local time.
+// The schedule applies from the last run of the task, or from when it was
+// last changed if later, so a task which missed runs while stash was not
+// running is run once. Returns nil if the task is disabled or its schedule
+// never matches.
+func NextScheduledRun(t *models.ScheduledTask) *time.Time {
+	if !t.Enabled {
+		return nil
+	}
+
+	schedule, err := utils.ParseCron(t.Schedule)
+	if err != nil {
+		return nil
+	}
+
+	from := t.UpdatedAt.Timestamp
+	if t.LastRunAt.Valid && t.LastRunAt.Timestamp.After(from) {
+		from = t.LastRunAt.Timestamp
+	}
+
+	next := schedule.Next(from.Local())
+	if next.IsZero() {
+		return nil
+	}
+
+	return &next
+}
+
+// dueScheduledTasks returns the tasks which are due at now, in the order
+// they became due.
+func dueScheduledTasks(tasks []*models.ScheduledTask, now time.Time) []*models.ScheduledTask {
+	var ret []*models.ScheduledTask
+	next := make(map[*models.ScheduledTask]time.Time)
+	for _, t := range tasks {
+		n := NextScheduledRun(t)
+		if n == nil || n.After(now) {
+			continue
+		}
+
+		ret = append(ret, t)
+		next[t] = *n
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return next[ret[i]].Before(next[ret[j]])
+	})
+
+	return ret
+}
+
+func (s *singleton) runScheduledTasks(now time.Time) {
+	if database.Ready() != nil {
+		return
+	}
+
+	var tasks []*models.ScheduledTask
+	if err := s.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		tasks, err = r.ScheduledTask().All()
+		return err
+	}); err != nil {
+		logger.Errorf("error reading scheduled tasks: %s", err.Error())
+		return
+	}
+
+	for _, t := range dueScheduledTasks(tasks, now) {
+		// the run is recorded first so that a task which fails to start is
+		// not retried at every check
+		if err := s.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			return r.ScheduledTask().UpdateLastRunAt(t.ID, now)
+		}); err != nil {
+			logger.Errorf("error updating scheduled task %s: %s", t.Name, err.Error())
+			continue
+		}
+
+		logger.Infof("Queueing scheduled task %s", t.Name)
+		if err := s.queueScheduledTask(t.Task); err != nil {
+			logger.Errorf("error queueing scheduled task %s: %s", t.Name, err.Error())
+		}
+	}
+}
+
+func (s *singleton) queueScheduledTask(task models.ScheduledTaskType) error {
+	switch task {
+	case models.ScheduledTaskTypeScan:
+		_, err := s.Scan(models.ScanMetadataInput{})
+		return err
+	case models.ScheduledTaskTypeAutoTag:
+		all := []string{"*"}
+		s.AutoTag(models.AutoTagMetadataInput{
+			Performers: all,
+			Studios:    all,
+			Tags:       all,
+		})
+	case models.ScheduledTaskTypeClean:
+		s.Clean(models.CleanMetadataInput{})
+	case models.ScheduledTaskTypeBackup:
+		s.RunSingleTask(s.newBackupTask())
+	case models.ScheduledTaskTypeOptimize:
+		s.RunSingleTask(&OptimizeTask{})
+	default:
+		return fmt.Errorf("unknown task type %s", task)
+	}
+
+	return nil
+}
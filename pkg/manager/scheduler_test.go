@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextScheduledRun(t *testing.T) {
+	updatedAt := time.Date(2021, time.March, 17, 10, 30, 0, 0, time.Local)
+	task := &models.ScheduledTask{
+		Schedule:  "0 3 * * *",
+		Enabled:   true,
+		UpdatedAt: models.SQLiteTimestamp{Timestamp: updatedAt},
+	}
+
+	// never run: from when the task changed
+	want := time.Date(2021, time.March, 18, 3, 0, 0, 0, time.Local)
+	assert.Equal(t, &want, NextScheduledRun(task))
+
+	// from the last run
+	task.LastRunAt = models.NullSQLiteTimestamp{Timestamp: want, Valid: true}
+	want = time.Date(2021, time.March, 19, 3, 0, 0, 0, time.Local)
+	assert.Equal(t, &want, NextScheduledRun(task))
+
+	// runs before the last change are ignored
+	task.LastRunAt.Timestamp = updatedAt.Add(-time.Hour)
+	want = time.Date(2021, time.March, 18, 3, 0, 0, 0, time.Local)
+	assert.Equal(t, &want, NextScheduledRun(task))
+
+	task.Enabled = false
+	assert.Nil(t, NextScheduledRun(task))
+
+	task.Enabled = true
+	task.Schedule = "invalid"
+	assert.Nil(t, NextScheduledRun(task))
+
+	task.Schedule = "0 0 30 2 *"
+	assert.Nil(t, NextScheduledRun(task))
+}
+
+func TestDueScheduledTasks(t *testing.T) {
+	updatedAt := models.SQLiteTimestamp{Timestamp: time.Date(2021, time.March, 17, 0, 0, 0, 0, time.Local)}
+	newTask := func(schedule string, enabled bool) *models.ScheduledTask {
+		return &models.ScheduledTask{
+			Schedule:  schedule,
+			Enabled:   enabled,
+			UpdatedAt: updatedAt,
+		}
+	}
+
+	hourly := newTask("@hourly", true)
+	daily := newTask("0 12 * * *", true)
+	disabled := newTask("* * * * *", false)
+	tasks := []*models.ScheduledTask{daily, disabled, hourly}
+
+	// nothing is due
+	assert.Len(t, dueScheduledTasks(tasks, updatedAt.Timestamp.Add(30*time.Minute)), 0)
+
+	// the hourly task has been due for longer
+	now := time.Date(2021, time.March, 17, 13, 0, 0, 0, time.Local)
+	assert.Equal(t, []*models.ScheduledTask{hourly, daily}, dueScheduledTasks(tasks, now))
+
+	hourly.LastRunAt = models.NullSQLiteTimestamp{Timestamp: now, Valid: true}
+	assert.Equal(t, []*models.ScheduledTask{daily}, dueScheduledTasks(tasks, now))
+}
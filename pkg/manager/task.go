@@ -5,4 +5,8 @@ import "sync"
 type Task interface {
 	Start(wg *sync.WaitGroup)
 	GetStatus() JobStatus
+
+	// setStatus sets the status to which the task reports its progress. It
+	// is called once the job running the task starts.
+	setStatus(status *TaskStatus)
 }
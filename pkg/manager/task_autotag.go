@@ -117,17 +117,17 @@ func (t *autoTagFilesTask) getCount(r models.ReaderRepository) (int, error) {
 		PerPage: &pp,
 	}
 
-	_, sceneCount, err := r.Scene().Query(t.makeSceneFilter(), findFilter)
+	_, sceneCount, err := r.Scene().QueryIDs(t.makeSceneFilter(), findFilter)
 	if err != nil {
 		return 0, err
 	}
 
-	_, imageCount, err := r.Image().Query(t.makeImageFilter(), findFilter)
+	_, imageCount, err := r.Image().QueryIDs(t.makeImageFilter(), findFilter)
 	if err != nil {
 		return 0, err
 	}
 
-	_, galleryCount, err := r.Gallery().Query(t.makeGalleryFilter(), findFilter)
+	_, galleryCount, err := r.Gallery().QueryIDs(t.makeGalleryFilter(), findFilter)
 	if err != nil {
 		return 0, err
 	}
@@ -144,7 +144,7 @@ func (t *autoTagFilesTask) batchFindFilter(batchSize int) *models.FindFilterType
 }
 
 func (t *autoTagFilesTask) processScenes(r models.ReaderRepository) error {
-	if t.status.stopping {
+	if t.status.IsStopping() {
 		return nil
 	}
 
@@ -161,7 +161,7 @@ func (t *autoTagFilesTask) processScenes(r models.ReaderRepository) error {
 		}
 
 		for _, ss := range scenes {
-			if t.status.stopping {
+			if t.status.IsStopping() {
 				return nil
 			}
 
@@ -192,7 +192,7 @@ func (t *autoTagFilesTask) processScenes(r models.ReaderRepository) error {
 }
 
 func (t *autoTagFilesTask) processImages(r models.ReaderRepository) error {
-	if t.status.stopping {
+	if t.status.IsStopping() {
 		return nil
 	}
 
@@ -209,7 +209,7 @@ func (t *autoTagFilesTask) processImages(r models.ReaderRepository) error {
 		}
 
 		for _, ss := range images {
-			if t.status.stopping {
+			if t.status.IsStopping() {
 				return nil
 			}
 
@@ -240,7 +240,7 @@ func (t *autoTagFilesTask) processImages(r models.ReaderRepository) error {
 }
 
 func (t *autoTagFilesTask) processGalleries(r models.ReaderRepository) error {
-	if t.status.stopping {
+	if t.status.IsStopping() {
 		return nil
 	}
 
@@ -257,7 +257,7 @@ func (t *autoTagFilesTask) processGalleries(r models.ReaderRepository) error {
 		}
 
 		for _, ss := range galleries {
-			if t.status.stopping {
+			if t.status.IsStopping() {
 				return nil
 			}
 
@@ -294,7 +294,7 @@ func (t *autoTagFilesTask) process() {
 			return err
 		}
 
-		t.status.total = total
+		t.status.setTotal(total)
 
 		logger.Infof("Starting autotag of %d files", total)
 
@@ -310,7 +310,7 @@ func (t *autoTagFilesTask) process() {
 			return err
 		}
 
-		if t.status.stopping {
+		if t.status.IsStopping() {
 			logger.Info("Stopping due to user request")
 		}
 
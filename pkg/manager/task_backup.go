@@ -0,0 +1,252 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// how often to check whether a scheduled backup is due
+const backupCheckInterval = time.Minute
+
+const (
+	backupPrefix     = "stash-backup-"
+	backupTimeFormat = "20060102_150405"
+)
+
+// runBackups adds the scheduled backups to the job queue when they are due,
+// until ctx is done.
+func runBackups(ctx context.Context) {
+	ticker := time.NewTicker(backupCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			GetInstance().scheduleBackup(time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *singleton) scheduleBackup(now time.Time) {
+	c := config.GetInstance()
+	interval := c.GetBackupInterval()
+	if interval <= 0 || database.Ready() != nil || s.jobs.isQueued(Backup) {
+		return
+	}
+
+	dir := c.GetBackupPath()
+	backups, err := listBackups(dir)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Errorf("error reading backups in %s: %s", dir, err.Error())
+		return
+	}
+
+	if len(backups) > 0 && now.Sub(backups[len(backups)-1].time) < interval {
+		return
+	}
+
+	s.RunSingleTask(s.newBackupTask())
+}
+
+// newBackupTask returns a task which backs up into the configured backup
+// path.
+func (s *singleton) newBackupTask() *BackupTask {
+	c := config.GetInstance()
+	return &BackupTask{
+		txnManager:          s.TxnManager,
+		Dir:                 c.GetBackupPath(),
+		Keep:                c.GetBackupCount(),
+		IncludeExport:       c.GetBackupIncludeExport(),
+		fileNamingAlgorithm: c.GetVideoFileNamingAlgorithm(),
+	}
+}
+
+// BackupTask backs up the database, and optionally makes a metadata export,
+// into Dir. Only the Keep most recent backups in Dir are kept.
+type BackupTask struct {
+	txnManager models.TransactionManager
+	status     *TaskStatus
+
+	Dir           string
+	Keep          int
+	IncludeExport bool
+
+	fileNamingAlgorithm models.HashAlgorithm
+}
+
+func (t *BackupTask) GetStatus() JobStatus {
+	return Backup
+}
+
+func (t *BackupTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *BackupTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if err := utils.EnsureDir(t.Dir); err != nil {
+		logger.Errorf("error creating backup directory %s: %s", t.Dir, err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	name := filepath.Join(t.Dir, backupPrefix+time.Now().Format(backupTimeFormat))
+
+	t.status.setPhase("database")
+	dbFile := name + ".sqlite"
+	if err := database.OnlineBackup(database.DB, dbFile); err != nil {
+		logger.Errorf("error backing up database: %s", err.Error())
+		os.Remove(dbFile)
+		t.status.setError(err)
+		return
+	}
+
+	if t.IncludeExport {
+		t.status.setPhase("export")
+		exportFile := name + ".jsonl.gz"
+		if err := t.export(exportFile); err != nil {
+			logger.Errorf("error exporting metadata for backup: %s", err.Error())
+			os.Remove(exportFile)
+			t.status.setError(err)
+		}
+	}
+
+	t.rotate()
+
+	logger.Infof("Backup complete: %s", name)
+}
+
+// export exports all objects into a gzipped JSON lines file.
+func (t *BackupTask) export(fn string) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	all := true
+	allObjects := &models.ExportObjectTypeInput{All: &all}
+	format := models.ExportFormatJSONLinesGzip
+
+	task := CreateExportTask(t.fileNamingAlgorithm, models.ExportObjectsInput{
+		Scenes:     allObjects,
+		Images:     allObjects,
+		Studios:    allObjects,
+		Performers: allObjects,
+		Tags:       allObjects,
+		Movies:     allObjects,
+		Galleries:  allObjects,
+		Format:     &format,
+	})
+	task.txnManager = t.txnManager
+	task.status = t.status
+	task.stream = f
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	task.Start(&wg)
+
+	if task.json.writer == nil {
+		return errors.New("export failed")
+	}
+	if err := task.json.writer.Err(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+type backup struct {
+	// name is the filename of the backup, without the extension
+	name string
+	time time.Time
+	// files are the paths of the files which make up the backup
+	files []string
+}
+
+// listBackups returns the backups in dir, ordered from oldest to newest.
+func listBackups(dir string) ([]*backup, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make(map[string]*backup)
+	for _, e := range entries {
+		fn := e.Name()
+		if e.IsDir() || !strings.HasPrefix(fn, backupPrefix) {
+			continue
+		}
+
+		name := fn
+		if i := strings.Index(fn, "."); i != -1 {
+			name = fn[:i]
+		}
+
+		b := backups[name]
+		if b == nil {
+			t, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(name, backupPrefix), time.Local)
+			if err != nil {
+				continue
+			}
+
+			b = &backup{name: name, time: t}
+			backups[name] = b
+		}
+
+		b.files = append(b.files, filepath.Join(dir, fn))
+	}
+
+	var ret []*backup
+	for _, b := range backups {
+		ret = append(ret, b)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].time.Before(ret[j].time)
+	})
+
+	return ret, nil
+}
+
+// rotate removes the oldest backups so that only Keep remain. The newest
+// backup is always kept.
+func (t *BackupTask) rotate() {
+	backups, err := listBackups(t.Dir)
+	if err != nil {
+		logger.Errorf("error reading backups in %s: %s", t.Dir, err.Error())
+		return
+	}
+
+	keep := t.Keep
+	if keep < 1 {
+		keep = 1
+	}
+
+	for len(backups) > keep {
+		for _, fn := range backups[0].files {
+			logger.Infof("Removing old backup %s", fn)
+			if err := os.Remove(fn); err != nil {
+				logger.Errorf("error removing old backup %s: %s", fn, err.Error())
+			}
+		}
+
+		backups = backups[1:]
+	}
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupTaskRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-backups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{
+		"stash-backup-20210103_000000.sqlite",
+		"stash-backup-20210101_000000.sqlite",
+		"stash-backup-20210101_000000.jsonl.gz",
+		"stash-backup-20210102_000000.sqlite",
+		"stash-backup-invalid.sqlite",
+		"other.sqlite",
+	}
+	for _, fn := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, fn), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := listBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, backups, 3) {
+		assert.Equal(t, "stash-backup-20210101_000000", backups[0].name)
+		assert.Len(t, backups[0].files, 2)
+		assert.Equal(t, time.Date(2021, 1, 3, 0, 0, 0, 0, time.Local), backups[2].time)
+	}
+
+	task := &BackupTask{Dir: dir, Keep: 2}
+	task.rotate()
+
+	var remaining []string
+	entries, _ := ioutil.ReadDir(dir)
+	for _, e := range entries {
+		remaining = append(remaining, e.Name())
+	}
+
+	assert.Equal(t, []string{
+		"other.sqlite",
+		"stash-backup-20210102_000000.sqlite",
+		"stash-backup-20210103_000000.sqlite",
+		"stash-backup-invalid.sqlite",
+	}, remaining)
+}
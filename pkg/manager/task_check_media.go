@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// CheckMediaTask decodes the file of each scene, and sets the decode error
+// of the scenes which failed to decode. The decode error of the scenes
+// which decoded cleanly is cleared. Missing files are skipped, since they
+// are removed by the clean task.
+type CheckMediaTask struct {
+	txnManager models.TransactionManager
+	status     *TaskStatus
+
+	// SceneIDs are the scenes to check. All scenes are checked if empty.
+	SceneIDs []int
+
+	// FullDecode decodes every frame of the video and audio streams, rather
+	// than only the key frames of the video.
+	FullDecode bool
+}
+
+func CreateCheckMediaTask(input models.CheckMediaInput) (*CheckMediaTask, error) {
+	sceneIDs, err := utils.StringSliceToIntSlice(input.SceneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckMediaTask{
+		txnManager: GetInstance().TxnManager,
+		SceneIDs:   sceneIDs,
+		FullDecode: input.FullDecode != nil && *input.FullDecode,
+	}, nil
+}
+
+func (t *CheckMediaTask) GetStatus() JobStatus {
+	return CheckMedia
+}
+
+func (t *CheckMediaTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *CheckMediaTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	var scenes []*models.Scene
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		if len(t.SceneIDs) > 0 {
+			scenes, err = r.Scene().FindMany(t.SceneIDs)
+		} else {
+			scenes, err = r.Scene().All()
+		}
+		return err
+	}); err != nil {
+		logger.Errorf("error getting scenes to check: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	t.status.setObjectsTotal(len(scenes))
+
+	encoder := ffmpeg.NewEncoder(instance.FFMPEGPath)
+	failed := 0
+	for i, s := range scenes {
+		if ctx.Err() != nil {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		t.status.setProgress(i, len(scenes))
+		decodeError, err := t.checkScene(ctx, &encoder, s)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("[check] <%s> error checking file: %s", s.Path, err.Error())
+			}
+		} else if decodeError.Valid {
+			failed++
+		}
+		t.status.objectDone()
+	}
+
+	logger.Infof("Media check complete. %d of %d scenes have decode errors", failed, len(scenes))
+}
+
+// checkScene decodes the scene file and updates the decode error of the
+// scene. Returns the new decode error.
+func (t *CheckMediaTask) checkScene(ctx context.Context, encoder *ffmpeg.Encoder, s *models.Scene) (sql.NullString, error) {
+	if exists, _ := utils.FileExists(s.Path); !exists {
+		logger.Warnf("[check] <%s> skipping missing scene file", s.Path)
+		return s.DecodeError, nil
+	}
+
+	var decodeError sql.NullString
+	probeResult, err := ffmpeg.NewVideoFile(instance.FFProbePath, s.Path, false)
+	if err != nil {
+		// the file couldn't be read at all
+		decodeError = sql.NullString{String: err.Error(), Valid: true}
+	} else {
+		output, err := encoder.CheckDecode(ctx, *probeResult, t.FullDecode)
+		if err != nil {
+			return s.DecodeError, err
+		}
+		decodeError = sql.NullString{String: output, Valid: output != ""}
+	}
+
+	if decodeError.Valid {
+		logger.Warnf("[check] <%s> decode errors: %s", s.Path, decodeError.String)
+	}
+
+	if decodeError == s.DecodeError {
+		return decodeError, nil
+	}
+
+	return decodeError, t.txnManager.WithTxn(ctx, func(r models.Repository) error {
+		_, err := r.Scene().Update(models.ScenePartial{
+			ID:          s.ID,
+			DecodeError: &decodeError,
+		})
+		return err
+	})
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
@@ -19,122 +20,206 @@ type CleanTask struct {
 	Gallery             *models.Gallery
 	Image               *models.Image
 	fileNamingAlgorithm models.HashAlgorithm
+	report              *cleanReport
 }
 
 func (t *CleanTask) Start(wg *sync.WaitGroup, dryRun bool) {
 	defer wg.Done()
 
-	if t.Scene != nil && t.shouldCleanScene(t.Scene) && !dryRun {
-		t.deleteScene(t.Scene.ID)
+	if t.Scene != nil {
+		if reason, clean := t.shouldCleanScene(t.Scene); clean {
+			t.report.add("scene", t.Scene.ID, t.Scene.Path, reason)
+			if !dryRun {
+				t.deleteScene(t.Scene.ID)
+			}
+		}
 	}
 
-	if t.Gallery != nil && t.shouldCleanGallery(t.Gallery) && !dryRun {
-		t.deleteGallery(t.Gallery.ID)
+	if t.Gallery != nil {
+		if reason, clean := t.shouldCleanGallery(t.Gallery); clean {
+			t.report.add("gallery", t.Gallery.ID, t.Gallery.Path.String, reason)
+			if !dryRun {
+				t.deleteGallery(t.Gallery.ID)
+			}
+		}
 	}
 
-	if t.Image != nil && t.shouldCleanImage(t.Image) && !dryRun {
-		t.deleteImage(t.Image.ID)
+	if t.Image != nil {
+		if reason, clean := t.shouldCleanImage(t.Image); clean {
+			t.report.add("image", t.Image.ID, t.Image.Path, reason)
+			if !dryRun {
+				t.deleteImage(t.Image.ID)
+			}
+		}
 	}
 }
 
-func (t *CleanTask) shouldClean(path string) bool {
+func (t *CleanTask) shouldClean(path string) (models.CleanReason, bool) {
 	// use image.FileExists for zip file checking
 	fileExists := image.FileExists(path)
 
+	stash := getStashFromPath(path)
+	if !fileExists && stash != nil && stash.Removable && !isStashAvailable(stash.Path) {
+		logger.Infof("File in unavailable removable library. Not cleaning: \"%s\"", path)
+		return "", false
+	}
+
+	if !fileExists {
+		logger.Infof("File not found. Cleaning: \"%s\"", path)
+		return models.CleanReasonFileMissing, true
+	}
+
 	// #1102 - clean anything in generated path
 	generatedPath := config.GetInstance().GetGeneratedPath()
-	if !fileExists || getStashFromPath(path) == nil || utils.IsPathInDir(generatedPath, path) {
-		logger.Infof("File not found. Cleaning: \"%s\"", path)
-		return true
+	if stash == nil || utils.IsPathInDir(generatedPath, path) {
+		logger.Infof("File not in library. Cleaning: \"%s\"", path)
+		return models.CleanReasonNotInLibrary, true
 	}
 
-	return false
+	if isEmptyFile(path) {
+		logger.Infof("File is empty. Cleaning: \"%s\"", path)
+		return models.CleanReasonZeroSize, true
+	}
+
+	return "", false
 }
 
-func (t *CleanTask) shouldCleanScene(s *models.Scene) bool {
-	if t.shouldClean(s.Path) {
-		return true
+func (t *CleanTask) shouldCleanScene(s *models.Scene) (models.CleanReason, bool) {
+	if reason, clean := t.shouldClean(s.Path); clean {
+		return reason, true
 	}
 
 	stash := getStashFromPath(s.Path)
 	if stash.ExcludeVideo {
 		logger.Infof("File in stash library that excludes video. Cleaning: \"%s\"", s.Path)
-		return true
+		return models.CleanReasonExcludedLibrary, true
 	}
 
 	config := config.GetInstance()
-	if !matchExtension(s.Path, config.GetVideoExtensions()) {
-		logger.Infof("File extension does not match video extensions. Cleaning: \"%s\"", s.Path)
-		return true
+	if !matchExtension(s.Path, config.GetVideoExtensions()) && !matchExtension(s.Path, config.GetAudioExtensions()) {
+		logger.Infof("File extension does not match video or audio extensions. Cleaning: \"%s\"", s.Path)
+		return models.CleanReasonExtension, true
 	}
 
-	if matchFile(s.Path, config.GetExcludes()) {
+	if matchFile(s.Path, config.GetExcludes()) || matchFile(s.Path, stash.Excludes) {
 		logger.Infof("File matched regex. Cleaning: \"%s\"", s.Path)
-		return true
+		return models.CleanReasonExcludedPattern, true
 	}
 
-	return false
+	return "", false
 }
 
-func (t *CleanTask) shouldCleanGallery(g *models.Gallery) bool {
+func (t *CleanTask) shouldCleanGallery(g *models.Gallery) (models.CleanReason, bool) {
 	// never clean manually created galleries
+	if !g.Path.Valid {
+		return "", false
+	}
+
 	if !g.Zip {
-		return false
+		return t.shouldCleanFolderGallery(g)
 	}
 
 	path := g.Path.String
-	if t.shouldClean(path) {
-		return true
+	if reason, clean := t.shouldClean(path); clean {
+		return reason, true
 	}
 
 	stash := getStashFromPath(path)
 	if stash.ExcludeImage {
 		logger.Infof("File in stash library that excludes images. Cleaning: \"%s\"", path)
-		return true
+		return models.CleanReasonExcludedLibrary, true
 	}
 
 	config := config.GetInstance()
 	if !matchExtension(path, config.GetGalleryExtensions()) {
 		logger.Infof("File extension does not match gallery extensions. Cleaning: \"%s\"", path)
-		return true
+		return models.CleanReasonExtension, true
 	}
 
-	if matchFile(path, config.GetImageExcludes()) {
+	if matchFile(path, config.GetImageExcludes()) || matchFile(path, stash.ImageExcludes) {
 		logger.Infof("File matched regex. Cleaning: \"%s\"", path)
-		return true
+		return models.CleanReasonExcludedPattern, true
 	}
 
-	if countImagesInZip(path) == 0 {
+	// galleries which are encrypted with an unknown password, or which need
+	// 7-Zip to be read, are kept
+	if n, err := countImagesInZip(path); n == 0 && !errors.Is(err, image.ErrZipPassword) && !errors.Is(err, image.ErrNoArchiveTool) {
 		logger.Infof("Gallery has 0 images. Cleaning: \"%s\"", path)
-		return true
+		return models.CleanReasonEmptyGallery, true
 	}
 
-	return false
+	return "", false
 }
 
-func (t *CleanTask) shouldCleanImage(s *models.Image) bool {
-	if t.shouldClean(s.Path) {
-		return true
+func (t *CleanTask) shouldCleanFolderGallery(g *models.Gallery) (models.CleanReason, bool) {
+	path := g.Path.String
+	exists, _ := utils.DirExists(path)
+
+	stash := getStashFromDirPath(path)
+	if !exists && stash != nil && stash.Removable && !isStashAvailable(stash.Path) {
+		logger.Infof("Folder in unavailable removable library. Not cleaning: \"%s\"", path)
+		return "", false
+	}
+
+	if !exists {
+		logger.Infof("Folder not found. Cleaning: \"%s\"", path)
+		return models.CleanReasonFileMissing, true
+	}
+
+	generatedPath := config.GetInstance().GetGeneratedPath()
+	if stash == nil || utils.IsPathInDir(generatedPath, path) {
+		logger.Infof("Folder not in library. Cleaning: \"%s\"", path)
+		return models.CleanReasonNotInLibrary, true
+	}
+
+	if stash.ExcludeImage {
+		logger.Infof("Folder in stash library that excludes images. Cleaning: \"%s\"", path)
+		return models.CleanReasonExcludedLibrary, true
+	}
+
+	// images are cleaned before galleries, so the galleries of folders whose
+	// images were all cleaned are empty. Not so for dry runs.
+	var imageIDs []int
+	if err := t.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		imageIDs, err = r.Gallery().GetImageIDs(g.ID)
+		return err
+	}); err != nil {
+		logger.Errorf("Error reading gallery images: %s", err.Error())
+		return "", false
+	}
+
+	if len(imageIDs) == 0 {
+		logger.Infof("Gallery has 0 images. Cleaning: \"%s\"", path)
+		return models.CleanReasonEmptyGallery, true
+	}
+
+	return "", false
+}
+
+func (t *CleanTask) shouldCleanImage(s *models.Image) (models.CleanReason, bool) {
+	if reason, clean := t.shouldClean(s.Path); clean {
+		return reason, true
 	}
 
 	stash := getStashFromPath(s.Path)
 	if stash.ExcludeImage {
 		logger.Infof("File in stash library that excludes images. Cleaning: \"%s\"", s.Path)
-		return true
+		return models.CleanReasonExcludedLibrary, true
 	}
 
 	config := config.GetInstance()
 	if !matchExtension(s.Path, config.GetImageExtensions()) {
 		logger.Infof("File extension does not match image extensions. Cleaning: \"%s\"", s.Path)
-		return true
+		return models.CleanReasonExtension, true
 	}
 
-	if matchFile(s.Path, config.GetImageExcludes()) {
+	if matchFile(s.Path, config.GetImageExcludes()) || matchFile(s.Path, stash.ImageExcludes) {
 		logger.Infof("File matched regex. Cleaning: \"%s\"", s.Path)
-		return true
+		return models.CleanReasonExcludedPattern, true
 	}
 
-	return false
+	return "", false
 }
 
 func (t *CleanTask) deleteScene(sceneID int) {
@@ -181,10 +266,7 @@ func (t *CleanTask) deleteImage(imageID int) {
 		return
 	}
 
-	pathErr := os.Remove(GetInstance().Paths.Generated.GetThumbnailPath(t.Image.Checksum, models.DefaultGthumbWidth)) // remove cache dir of gallery
-	if pathErr != nil {
-		logger.Errorf("Error deleting thumbnail image from cache: %s", pathErr)
-	}
+	GetInstance().ThumbnailCache.Remove(t.Image.Checksum)
 }
 
 func (t *CleanTask) fileExists(filename string) (bool, error) {
@@ -201,6 +283,30 @@ func (t *CleanTask) fileExists(filename string) (bool, error) {
 	return !info.IsDir(), nil
 }
 
+// isStashAvailable returns false if the stash path is missing or empty,
+// which is the case for removable storage which is not mounted.
+func isStashAvailable(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	names, _ := f.Readdirnames(1)
+	return len(names) > 0
+}
+
+// isEmptyFile returns true if the file is empty. Files within zip files are
+// not checked.
+func isEmptyFile(path string) bool {
+	if image.IsZipPath(path) {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Size() == 0
+}
+
 func getStashFromPath(pathToCheck string) *models.StashConfig {
 	for _, s := range config.GetInstance().GetStashPaths() {
 		if utils.IsPathInDir(s.Path, filepath.Dir(pathToCheck)) {
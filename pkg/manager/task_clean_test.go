@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStashAvailable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-clean")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// empty directories are unmounted mount points
+	assert.False(t, isStashAvailable(dir))
+	assert.False(t, isStashAvailable(filepath.Join(dir, "missing")))
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "scene.mp4"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, isStashAvailable(dir))
+}
+
+func TestIsEmptyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-clean")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	empty := filepath.Join(dir, "empty.mp4")
+	if err := ioutil.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	nonEmpty := filepath.Join(dir, "scene.mp4")
+	if err := ioutil.WriteFile(nonEmpty, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, isEmptyFile(empty))
+	assert.False(t, isEmptyFile(nonEmpty))
+	assert.False(t, isEmptyFile(dir))
+	assert.False(t, isEmptyFile(filepath.Join(dir, "missing.mp4")))
+	assert.False(t, isEmptyFile(image.ZipFilename(empty, "a.jpg")))
+}
@@ -3,6 +3,7 @@ package manager
 import (
 	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -29,6 +30,7 @@ import (
 
 type ExportTask struct {
 	txnManager models.TransactionManager
+	status     *TaskStatus
 	full       bool
 
 	baseDir string
@@ -47,7 +49,25 @@ type ExportTask struct {
 
 	includeDependencies bool
 
+	// since, if set, limits the objects selected by all to those updated
+	// after the time, and adds the objects deleted after it to the mappings
+	since *time.Time
+
+	format    models.ExportFormat
+	linesFile string
+
+	// streamed exports are written to stream as they are generated, rather
+	// than to a file which is then downloaded
+	streamed bool
+	stream   io.Writer
+
+	// imageMode is how the images of performers, studios and movies are
+	// exported. Images are included in the objects if it is not set.
+	imageMode models.ExportImageMode
+
 	DownloadHash string
+	// DownloadExtension is the file extension of the download
+	DownloadExtension string
 }
 
 type exportSpec struct {
@@ -79,7 +99,17 @@ func CreateExportTask(a models.HashAlgorithm, input models.ExportObjectsInput) *
 		includeDeps = *input.IncludeDependencies
 	}
 
-	return &ExportTask{
+	format := models.ExportFormatFiles
+	if input.Format != nil && input.Format.IsValid() {
+		format = *input.Format
+	}
+
+	imageMode := models.ExportImageModeInline
+	if input.ImageMode != nil && input.ImageMode.IsValid() {
+		imageMode = *input.ImageMode
+	}
+
+	ret := &ExportTask{
 		txnManager:          GetInstance().TxnManager,
 		fileNamingAlgorithm: a,
 		scenes:              newExportSpec(input.Scenes),
@@ -90,13 +120,65 @@ func CreateExportTask(a models.HashAlgorithm, input models.ExportObjectsInput) *
 		studios:             newExportSpec(input.Studios),
 		galleries:           newExportSpec(input.Galleries),
 		includeDependencies: includeDeps,
+		since:               input.Since,
+		format:              format,
+		streamed:            input.Stream != nil && *input.Stream,
+		imageMode:           imageMode,
+	}
+
+	if imageMode == models.ExportImageModeFiles && ret.jsonLinesFormat() {
+		logger.Warnf("Images cannot be exported as files in the %s format. Including images in the exported objects", format)
+		ret.imageMode = models.ExportImageModeInline
+	}
+
+	return ret
+}
+
+// progress reports the progress through the objects of a single type.
+func (t *ExportTask) progress(objectType string, upTo int, total int) {
+	if t.status == nil {
+		logger.Progressf("[%s] %d of %d", objectType, upTo, total)
+		return
 	}
+
+	t.status.setStepProgress("Exporting "+objectType, upTo, total)
+}
+
+// exportedImage returns the base64 encoded image to include in an exported
+// object, according to the image mode. Images exported as files are saved
+// to the blobs directory with the name, and are omitted from the object.
+func (t *ExportTask) exportedImage(dir string, name string, image string) string {
+	if image == "" || (t.imageMode != models.ExportImageModeFiles && t.imageMode != models.ExportImageModeNone) {
+		return image
+	}
+
+	if t.imageMode == models.ExportImageModeFiles {
+		data, err := utils.GetDataFromBase64String(image)
+		if err == nil {
+			err = t.json.saveBlob(dir, name, data)
+		}
+		if err != nil {
+			logger.Errorf("[%s] <%s> failed to save image: %s", dir, name, err.Error())
+		}
+	}
+
+	return ""
+}
+
+// updatedSince returns true if the object should be included in an
+// incremental export.
+func (t *ExportTask) updatedSince(updatedAt models.SQLiteTimestamp) bool {
+	return t.since == nil || updatedAt.Timestamp.After(*t.since)
 }
 
 func (t *ExportTask) GetStatus() JobStatus {
 	return Export
 }
 
+func (t *ExportTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
 func (t *ExportTask) Start(wg *sync.WaitGroup) {
 	defer wg.Done()
 	// @manager.total = Scene.count + Gallery.count + Performer.count + Studio.count + Movie.count
@@ -113,6 +195,7 @@ func (t *ExportTask) Start(wg *sync.WaitGroup) {
 		t.baseDir, err = instance.Paths.Generated.TempDir("export")
 		if err != nil {
 			logger.Errorf("error creating temporary directory for export: %s", err.Error())
+			t.status.setError(err)
 			return
 		}
 
@@ -128,7 +211,17 @@ func (t *ExportTask) Start(wg *sync.WaitGroup) {
 		json: *paths.GetJSONPaths(t.baseDir),
 	}
 
-	paths.EnsureJSONDirs(t.baseDir)
+	if t.stream != nil {
+		t.createStreamWriter()
+	} else if t.jsonLinesFormat() {
+		if err := t.createLinesFile(); err != nil {
+			logger.Errorf("error creating export file: %s", err.Error())
+			t.status.setError(err)
+			return
+		}
+	} else {
+		paths.EnsureJSONDirs(t.baseDir)
+	}
 
 	t.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
 		// include movie scenes and gallery images
@@ -156,14 +249,27 @@ func (t *ExportTask) Start(wg *sync.WaitGroup) {
 			t.ExportScrapedItems(r)
 		}
 
+		if t.since != nil {
+			t.ExportDeleted(r)
+		}
+
 		return nil
 	})
 
 	if err := t.json.saveMappings(t.Mappings); err != nil {
 		logger.Errorf("[mappings] failed to save json: %s", err.Error())
+		t.status.setError(err)
 	}
 
-	if !t.full {
+	if t.json.writer != nil {
+		if err := t.json.writer.Close(); err != nil {
+			logger.Errorf("error writing export file: %s", err.Error())
+			t.status.setError(err)
+			return
+		}
+	}
+
+	if !t.full && t.stream == nil {
 		err := t.generateDownload()
 		if err != nil {
 			logger.Errorf("error generating download link: %s", err.Error())
@@ -173,7 +279,103 @@ func (t *ExportTask) Start(wg *sync.WaitGroup) {
 	logger.Infof("Export complete in %s.", time.Since(startTime))
 }
 
+// jsonLinesFormat returns true if all objects are exported to a single JSON
+// lines file.
+func (t *ExportTask) jsonLinesFormat() bool {
+	return !t.full && (t.format == models.ExportFormatJSONLines || t.format == models.ExportFormatJSONLinesGzip)
+}
+
+// downloadExtension returns the file extension of the export format.
+func (t *ExportTask) downloadExtension() string {
+	switch t.format {
+	case models.ExportFormatJSONLines:
+		return ".jsonl"
+	case models.ExportFormatJSONLinesGzip:
+		return ".jsonl.gz"
+	}
+
+	return ".zip"
+}
+
+func (t *ExportTask) downloadContentType() string {
+	switch t.format {
+	case models.ExportFormatJSONLines:
+		return "application/x-ndjson"
+	case models.ExportFormatJSONLinesGzip:
+		return "application/gzip"
+	}
+
+	return "application/zip"
+}
+
+// createLinesFile creates the JSON lines file in the downloads directory,
+// so that it does not need to be copied once the export is complete.
+func (t *ExportTask) createLinesFile() error {
+	ext := t.downloadExtension()
+
+	utils.EnsureDir(instance.Paths.Generated.Downloads)
+	f, err := ioutil.TempFile(instance.Paths.Generated.Downloads, "export*"+ext)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	t.linesFile = f.Name()
+	t.DownloadExtension = ext
+	lines, err := jsonschema.CreateLinesFile(t.linesFile, t.format == models.ExportFormatJSONLinesGzip)
+	if err != nil {
+		return err
+	}
+
+	t.json.writer = lines
+	return nil
+}
+
+func (t *ExportTask) createStreamWriter() {
+	if t.jsonLinesFormat() {
+		t.json.writer = jsonschema.NewLinesWriter(t.stream, t.format == models.ExportFormatJSONLinesGzip)
+		return
+	}
+
+	t.json.writer = newZipObjectWriter(t.stream)
+}
+
+// Streamed returns true if the export should be streamed to the download
+// using RegisterStream, rather than run before the download is available.
+func (t *ExportTask) Streamed() bool {
+	return t.streamed
+}
+
+// RegisterStream registers a download which runs the export as it is
+// downloaded, and sets DownloadHash to the hash of the download.
+func (t *ExportTask) RegisterStream() {
+	t.DownloadExtension = t.downloadExtension()
+	t.DownloadHash = instance.DownloadStore.RegisterStream(func(w io.Writer) error {
+		t.stream = w
+
+		// the download waits for the export
+		instance.RunTask(t, models.JobPriorityHigh).Wait()
+
+		if t.json.writer == nil {
+			return errors.New("export failed")
+		}
+		return t.json.writer.Err()
+	}, t.downloadContentType())
+}
+
+// aborted returns true if the export can no longer be written, such as when
+// the client of a streamed export has disconnected.
+func (t *ExportTask) aborted() bool {
+	return t.json.writer != nil && t.json.writer.Err() != nil
+}
+
 func (t *ExportTask) generateDownload() error {
+	if t.linesFile != "" {
+		t.DownloadHash = instance.DownloadStore.RegisterFile(t.linesFile, "", false)
+		logger.Debugf("Generated export file %s with hash %s", t.linesFile, t.DownloadHash)
+		return nil
+	}
+
 	// zip the files and register a download link
 	utils.EnsureDir(instance.Paths.Generated.Downloads)
 	z, err := ioutil.TempFile(instance.Paths.Generated.Downloads, "export*.zip")
@@ -188,6 +390,7 @@ func (t *ExportTask) generateDownload() error {
 	}
 
 	t.DownloadHash = instance.DownloadStore.RegisterFile(z.Name(), "", false)
+	t.DownloadExtension = ".zip"
 	logger.Debugf("Generated zip file %s with hash %s", z.Name(), t.DownloadHash)
 	return nil
 }
@@ -214,6 +417,10 @@ func (t *ExportTask) zipFiles(w io.Writer) error {
 	filepath.Walk(t.json.json.Scenes, t.zipWalkFunc(u.json.Scenes, z))
 	filepath.Walk(t.json.json.Images, t.zipWalkFunc(u.json.Images, z))
 
+	for _, dir := range blobDirs {
+		filepath.Walk(filepath.Join(t.json.json.Blobs, dir), t.zipWalkFunc(filepath.Join(u.json.Blobs, dir), z))
+	}
+
 	return nil
 }
 
@@ -345,8 +552,16 @@ func (t *ExportTask) ExportScenes(workers int, repo models.ReaderRepository) {
 		index := i + 1
 
 		if (i % 100) == 0 { // make progress easier to read
-			logger.Progressf("[scenes] %d of %d", index, len(scenes))
+			t.progress("scenes", index, len(scenes))
+		}
+		if t.aborted() {
+			break
 		}
+
+		if all && !t.updatedSince(scene.UpdatedAt) {
+			continue
+		}
+
 		t.Mappings.Scenes = append(t.Mappings.Scenes, jsonschema.PathNameMapping{Path: scene.Path, Checksum: scene.GetHash(t.fileNamingAlgorithm)})
 		jobCh <- scene // feed workers
 	}
@@ -483,8 +698,16 @@ func (t *ExportTask) ExportImages(workers int, repo models.ReaderRepository) {
 		index := i + 1
 
 		if (i % 100) == 0 { // make progress easier to read
-			logger.Progressf("[images] %d of %d", index, len(images))
+			t.progress("images", index, len(images))
+		}
+		if t.aborted() {
+			break
+		}
+
+		if all && !t.updatedSince(image.UpdatedAt) {
+			continue
 		}
+
 		t.Mappings.Images = append(t.Mappings.Images, jsonschema.PathNameMapping{Path: image.Path, Checksum: image.Checksum})
 		jobCh <- image // feed workers
 	}
@@ -598,7 +821,15 @@ func (t *ExportTask) ExportGalleries(workers int, repo models.ReaderRepository)
 		index := i + 1
 
 		if (i % 100) == 0 { // make progress easier to read
-			logger.Progressf("[galleries] %d of %d", index, len(galleries))
+			t.progress("galleries", index, len(galleries))
+		}
+
+		if t.aborted() {
+			break
+		}
+
+		if all && !t.updatedSince(gallery.UpdatedAt) {
+			continue
 		}
 
 		t.Mappings.Galleries = append(t.Mappings.Galleries, jsonschema.PathNameMapping{
@@ -700,7 +931,15 @@ func (t *ExportTask) ExportPerformers(workers int, repo models.ReaderRepository)
 
 	for i, performer := range performers {
 		index := i + 1
-		logger.Progressf("[performers] %d of %d", index, len(performers))
+		t.progress("performers", index, len(performers))
+
+		if t.aborted() {
+			break
+		}
+
+		if all && !t.updatedSince(performer.UpdatedAt) {
+			continue
+		}
 
 		t.Mappings.Performers = append(t.Mappings.Performers, jsonschema.PathNameMapping{Name: performer.Name.String, Checksum: performer.Checksum})
 		jobCh <- performer // feed workers
@@ -732,6 +971,7 @@ func (t *ExportTask) exportPerformer(wg *sync.WaitGroup, jobChan <-chan *models.
 		}
 
 		newPerformerJSON.Tags = tag.GetNames(tags)
+		newPerformerJSON.Image = t.exportedImage(blobPerformers, p.Checksum, newPerformerJSON.Image)
 
 		if t.includeDependencies {
 			t.tags.IDs = utils.IntAppendUniques(t.tags.IDs, tag.GetIDs(tags))
@@ -779,7 +1019,15 @@ func (t *ExportTask) ExportStudios(workers int, repo models.ReaderRepository) {
 
 	for i, studio := range studios {
 		index := i + 1
-		logger.Progressf("[studios] %d of %d", index, len(studios))
+		t.progress("studios", index, len(studios))
+
+		if t.aborted() {
+			break
+		}
+
+		if all && !t.updatedSince(studio.UpdatedAt) {
+			continue
+		}
 
 		t.Mappings.Studios = append(t.Mappings.Studios, jsonschema.PathNameMapping{Name: studio.Name.String, Checksum: studio.Checksum})
 		jobCh <- studio // feed workers
@@ -804,6 +1052,8 @@ func (t *ExportTask) exportStudio(wg *sync.WaitGroup, jobChan <-chan *models.Stu
 			continue
 		}
 
+		newStudioJSON.Image = t.exportedImage(blobStudios, s.Checksum, newStudioJSON.Image)
+
 		studioJSON, err := t.json.getStudio(s.Checksum)
 		if err == nil && jsonschema.CompareJSON(*studioJSON, *newStudioJSON) {
 			continue
@@ -844,7 +1094,15 @@ func (t *ExportTask) ExportTags(workers int, repo models.ReaderRepository) {
 
 	for i, tag := range tags {
 		index := i + 1
-		logger.Progressf("[tags] %d of %d", index, len(tags))
+		t.progress("tags", index, len(tags))
+
+		if t.aborted() {
+			break
+		}
+
+		if all && !t.updatedSince(tag.UpdatedAt) {
+			continue
+		}
 
 		// generate checksum on the fly by name, since we don't store it
 		checksum := utils.MD5FromString(tag.Name)
@@ -915,7 +1173,15 @@ func (t *ExportTask) ExportMovies(workers int, repo models.ReaderRepository) {
 
 	for i, movie := range movies {
 		index := i + 1
-		logger.Progressf("[movies] %d of %d", index, len(movies))
+		t.progress("movies", index, len(movies))
+
+		if t.aborted() {
+			break
+		}
+
+		if all && !t.updatedSince(movie.UpdatedAt) {
+			continue
+		}
 
 		t.Mappings.Movies = append(t.Mappings.Movies, jsonschema.PathNameMapping{Name: movie.Name.String, Checksum: movie.Checksum})
 		jobCh <- movie // feed workers
@@ -941,6 +1207,9 @@ func (t *ExportTask) exportMovie(wg *sync.WaitGroup, jobChan <-chan *models.Movi
 			continue
 		}
 
+		newMovieJSON.FrontImage = t.exportedImage(blobMovies, m.Checksum+"-front", newMovieJSON.FrontImage)
+		newMovieJSON.BackImage = t.exportedImage(blobMovies, m.Checksum+"-back", newMovieJSON.BackImage)
+
 		if t.includeDependencies {
 			if m.StudioID.Valid {
 				t.studios.IDs = utils.IntAppendUnique(t.studios.IDs, int(m.StudioID.Int64))
@@ -974,7 +1243,7 @@ func (t *ExportTask) ExportScrapedItems(repo models.ReaderRepository) {
 
 	for i, scrapedItem := range scrapedItems {
 		index := i + 1
-		logger.Progressf("[scraped sites] %d of %d", index, len(scrapedItems))
+		t.progress("scraped sites", index, len(scrapedItems))
 
 		var studioName string
 		if scrapedItem.StudioID.Valid {
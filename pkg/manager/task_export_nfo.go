@@ -0,0 +1,251 @@
+package manager
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// ExportNfoTask writes an NFO file next to the file of each scene, so that
+// the scenes may be added to Kodi or Jellyfin libraries. The scene
+// screenshot is written as the fanart image, and the front image of the
+// scene's first movie, or otherwise the screenshot, as the poster image.
+type ExportNfoTask struct {
+	txnManager models.TransactionManager
+	status     *TaskStatus
+
+	// SceneIDs are the scenes to export. All scenes are exported if empty.
+	SceneIDs []int
+
+	// Images writes the poster and fanart images alongside the NFO files.
+	Images bool
+
+	// Overwrite replaces existing NFO and image files. Existing files are
+	// left unchanged otherwise.
+	Overwrite bool
+
+	fileNamingAlgorithm models.HashAlgorithm
+}
+
+// nfoFiles are the files written for a scene.
+type nfoFiles struct {
+	nfo    []byte
+	poster []byte
+	fanart []byte
+}
+
+func CreateExportNfoTask(a models.HashAlgorithm, input models.ExportNfoInput) (*ExportNfoTask, error) {
+	sceneIDs, err := utils.StringSliceToIntSlice(input.SceneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportNfoTask{
+		txnManager:          GetInstance().TxnManager,
+		SceneIDs:            sceneIDs,
+		Images:              input.Images != nil && *input.Images,
+		Overwrite:           input.Overwrite != nil && *input.Overwrite,
+		fileNamingAlgorithm: a,
+	}, nil
+}
+
+func (t *ExportNfoTask) GetStatus() JobStatus {
+	return ExportNfo
+}
+
+func (t *ExportNfoTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *ExportNfoTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	var scenes []*models.Scene
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		if len(t.SceneIDs) > 0 {
+			scenes, err = r.Scene().FindMany(t.SceneIDs)
+		} else {
+			scenes, err = r.Scene().All()
+		}
+		return err
+	}); err != nil {
+		logger.Errorf("error getting scenes for NFO export: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	t.status.setObjectsTotal(len(scenes))
+
+	for i, s := range scenes {
+		if ctx.Err() != nil {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		t.status.setProgress(i, len(scenes))
+		if err := t.exportScene(ctx, s); err != nil {
+			logger.Errorf("[nfo] <%s> error exporting NFO: %s", s.Path, err.Error())
+		}
+		t.status.objectDone()
+	}
+
+	logger.Infof("NFO export complete. Exported %d scenes", len(scenes))
+}
+
+func (t *ExportNfoTask) exportScene(ctx context.Context, s *models.Scene) error {
+	// the files are written next to the scene file
+	if exists, _ := utils.FileExists(s.Path); !exists {
+		logger.Warnf("[nfo] <%s> skipping missing scene file", s.Path)
+		return nil
+	}
+
+	var files *nfoFiles
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		files, err = t.getFiles(r, s)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(s.Path, filepath.Ext(s.Path))
+	if err := t.writeFile(base+".nfo", files.nfo); err != nil {
+		return err
+	}
+
+	if len(files.poster) > 0 {
+		if err := t.writeFile(base+"-poster"+imageExtension(files.poster), files.poster); err != nil {
+			return err
+		}
+	}
+
+	if len(files.fanart) > 0 {
+		if err := t.writeFile(base+"-fanart"+imageExtension(files.fanart), files.fanart); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *ExportNfoTask) getFiles(r models.ReaderRepository, s *models.Scene) (*nfoFiles, error) {
+	studio, err := scene.GetStudioName(r.Studio(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	performers, err := r.Performer().FindBySceneID(s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := scene.GetTagNames(r.Tag(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	sceneMovies, err := r.Scene().GetMovies(s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var movies []*models.Movie
+	for _, sm := range sceneMovies {
+		movie, err := r.Movie().Find(sm.MovieID)
+		if err != nil {
+			return nil, err
+		}
+		if movie != nil && movie.Name.Valid {
+			movies = append(movies, movie)
+		}
+	}
+
+	var movieNames []string
+	for _, m := range movies {
+		movieNames = append(movieNames, m.Name.String)
+	}
+
+	nfo, err := scene.ToNFO(s, studio, performer.GetNames(performers), tags, movieNames).Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &nfoFiles{
+		nfo: nfo,
+	}
+
+	if !t.Images {
+		return ret, nil
+	}
+
+	ret.fanart, err = t.getScreenshot(r, s)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.poster = ret.fanart
+	if len(movies) > 0 {
+		front, err := r.Movie().GetFrontImage(movies[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(front) > 0 {
+			ret.poster = front
+		}
+	}
+
+	return ret, nil
+}
+
+// getScreenshot returns the generated screenshot of the scene, or the cover
+// image if there is no generated screenshot.
+func (t *ExportNfoTask) getScreenshot(r models.ReaderRepository, s *models.Scene) ([]byte, error) {
+	fn := instance.Paths.Scene.GetScreenshotPath(s.GetHash(t.fileNamingAlgorithm))
+	if instance.GeneratedStore.Exists(fn) {
+		f, err := instance.GeneratedStore.Open(fn)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ioutil.ReadAll(f)
+	}
+
+	return r.Scene().GetCover(s.ID)
+}
+
+func (t *ExportNfoTask) writeFile(fn string, data []byte) error {
+	if !t.Overwrite {
+		if exists, _ := utils.FileExists(fn); exists {
+			return nil
+		}
+	}
+
+	return ioutil.WriteFile(fn, data, 0644)
+}
+
+// imageExtension returns the file extension for the type of the image.
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	}
+
+	return ".jpg"
+}
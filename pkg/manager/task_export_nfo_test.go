@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageExtension(t *testing.T) {
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A")
+	jpeg := []byte("\xFF\xD8\xFF")
+
+	assert.Equal(t, ".png", imageExtension(png))
+	assert.Equal(t, ".jpg", imageExtension(jpeg))
+	assert.Equal(t, ".jpg", imageExtension([]byte("unknown")))
+}
+
+func TestExportNfoTaskWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-nfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "scene.nfo")
+	if err := ioutil.WriteFile(fn, []byte("existing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func() string {
+		data, _ := ioutil.ReadFile(fn)
+		return string(data)
+	}
+
+	task := &ExportNfoTask{}
+	assert.Nil(t, task.writeFile(fn, []byte("new")))
+	assert.Equal(t, "existing", read())
+
+	task.Overwrite = true
+	assert.Nil(t, task.writeFile(fn, []byte("new")))
+	assert.Equal(t, "new", read())
+}
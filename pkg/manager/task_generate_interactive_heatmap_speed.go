@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/remeh/sizedwaitgroup"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// GenerateInteractiveHeatmapSpeedTask renders the heatmap of the funscript
+// of an interactive scene, and sets the interactive speed of the scene to
+// the median speed of the funscript.
+type GenerateInteractiveHeatmapSpeedTask struct {
+	Scene               models.Scene
+	Overwrite           bool
+	fileNamingAlgorithm models.HashAlgorithm
+	txnManager          models.TransactionManager
+}
+
+func (t *GenerateInteractiveHeatmapSpeedTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
+	defer wg.Done()
+
+	if !t.shouldGenerate() {
+		return
+	}
+
+	funscript, err := scene.ParseFunscript(scene.GetFunscriptPath(t.Scene.Path))
+	if err != nil {
+		logger.Errorf("error reading funscript of %s: %s", t.Scene.Path, err.Error())
+		return
+	}
+
+	heatmapPath := instance.Paths.Scene.GetInteractiveHeatmapPath(t.Scene.GetHash(t.fileNamingAlgorithm))
+	if err := writeHeatmap(funscript, t.Scene.Duration.Float64, heatmapPath); err != nil {
+		logger.Errorf("error generating heatmap of %s: %s", t.Scene.Path, err.Error())
+		return
+	}
+	storeGenerated(heatmapPath)
+
+	if err := t.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		speed := sql.NullInt64{Int64: int64(funscript.MedianSpeed()), Valid: true}
+		_, err := r.Scene().Update(models.ScenePartial{
+			ID:               t.Scene.ID,
+			InteractiveSpeed: &speed,
+		})
+		return err
+	}); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+func (t *GenerateInteractiveHeatmapSpeedTask) shouldGenerate() bool {
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if !t.Scene.Interactive || sceneHash == "" {
+		return false
+	}
+
+	if t.Overwrite || !t.Scene.InteractiveSpeed.Valid {
+		return true
+	}
+
+	return !instance.GeneratedStore.Exists(instance.Paths.Scene.GetInteractiveHeatmapPath(sceneHash))
+}
@@ -116,6 +116,7 @@ func (t *GenerateMarkersTask) generateMarker(videoFile *ffmpeg.VideoFile, scene
 		} else {
 			_ = utils.SafeMove(options.OutputPath, videoPath)
 			logger.Debug("created marker video: ", videoPath)
+			storeGenerated(videoPath)
 		}
 	}
 
@@ -129,6 +130,7 @@ func (t *GenerateMarkersTask) generateMarker(videoFile *ffmpeg.VideoFile, scene
 		} else {
 			_ = utils.SafeMove(options.OutputPath, imagePath)
 			logger.Debug("created marker image: ", imagePath)
+			storeGenerated(imagePath)
 		}
 	}
 }
@@ -168,10 +170,7 @@ func (t *GenerateMarkersTask) markerExists(sceneChecksum string, seconds int) bo
 
 	videoPath := instance.Paths.SceneMarkers.GetStreamPath(sceneChecksum, seconds)
 	imagePath := instance.Paths.SceneMarkers.GetStreamPreviewImagePath(sceneChecksum, seconds)
-	videoExists, _ := utils.FileExists(videoPath)
-	imageExists, _ := utils.FileExists(imagePath)
-
-	return videoExists && imageExists
+	return instance.GeneratedStore.Exists(videoPath) && instance.GeneratedStore.Exists(imagePath)
 }
 
 func (t *GenerateMarkersTask) videoExists(sceneChecksum string, seconds int) bool {
@@ -180,9 +179,7 @@ func (t *GenerateMarkersTask) videoExists(sceneChecksum string, seconds int) boo
 	}
 
 	videoPath := instance.Paths.SceneMarkers.GetStreamPath(sceneChecksum, seconds)
-	videoExists, _ := utils.FileExists(videoPath)
-
-	return videoExists
+	return instance.GeneratedStore.Exists(videoPath)
 }
 
 func (t *GenerateMarkersTask) imageExists(sceneChecksum string, seconds int) bool {
@@ -191,7 +188,5 @@ func (t *GenerateMarkersTask) imageExists(sceneChecksum string, seconds int) boo
 	}
 
 	imagePath := instance.Paths.SceneMarkers.GetStreamPreviewImagePath(sceneChecksum, seconds)
-	imageExists, _ := utils.FileExists(imagePath)
-
-	return imageExists
+	return instance.GeneratedStore.Exists(imagePath)
 }
@@ -5,8 +5,8 @@ import (
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 type GeneratePreviewTask struct {
@@ -44,17 +44,29 @@ func (t *GeneratePreviewTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 		return
 	}
 	generator.Overwrite = t.Overwrite
+	generator.Profile = config.GetInstance().GetPreviewTranscodeProfile()
 
 	// set the preview generation configuration from the global config
 	generator.Info.ChunkCount = *t.Options.PreviewSegments
 	generator.Info.ChunkDuration = *t.Options.PreviewSegmentDuration
 	generator.Info.ExcludeStart = *t.Options.PreviewExcludeStart
 	generator.Info.ExcludeEnd = *t.Options.PreviewExcludeEnd
+	if t.Options.PreviewImageQuality != nil {
+		generator.ImageQuality = *t.Options.PreviewImageQuality
+	}
+	if t.Options.PreviewImageFps != nil && *t.Options.PreviewImageFps > 0 {
+		generator.ImageFps = *t.Options.PreviewImageFps
+	}
 
 	if err := generator.Generate(); err != nil {
 		logger.Errorf("error generating preview: %s", err.Error())
 		return
 	}
+
+	storeGenerated(instance.Paths.Scene.GetStreamPreviewPath(videoChecksum))
+	if t.ImagePreview {
+		storeGenerated(instance.Paths.Scene.GetStreamPreviewImagePath(videoChecksum))
+	}
 }
 
 func (t GeneratePreviewTask) required() bool {
@@ -69,8 +81,7 @@ func (t *GeneratePreviewTask) doesVideoPreviewExist(sceneChecksum string) bool {
 		return false
 	}
 
-	videoExists, _ := utils.FileExists(instance.Paths.Scene.GetStreamPreviewPath(sceneChecksum))
-	return videoExists
+	return instance.GeneratedStore.Exists(instance.Paths.Scene.GetStreamPreviewPath(sceneChecksum))
 }
 
 func (t *GeneratePreviewTask) doesImagePreviewExist(sceneChecksum string) bool {
@@ -78,8 +89,7 @@ func (t *GeneratePreviewTask) doesImagePreviewExist(sceneChecksum string) bool {
 		return false
 	}
 
-	imageExists, _ := utils.FileExists(instance.Paths.Scene.GetStreamPreviewImagePath(sceneChecksum))
-	return imageExists
+	return instance.GeneratedStore.Exists(instance.Paths.Scene.GetStreamPreviewImagePath(sceneChecksum))
 }
 
 func (t *GeneratePreviewTask) videoFilename() string {
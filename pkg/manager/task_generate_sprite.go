@@ -5,8 +5,8 @@ import (
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 type GenerateSpriteTask struct {
@@ -31,7 +31,14 @@ func (t *GenerateSpriteTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
 	imagePath := instance.Paths.Scene.GetSpriteImageFilePath(sceneHash)
 	vttPath := instance.Paths.Scene.GetSpriteVttFilePath(sceneHash)
-	generator, err := NewSpriteGenerator(*videoFile, sceneHash, imagePath, vttPath, 9, 9)
+	c := config.GetInstance()
+	options := SpriteOptions{
+		Rows:     c.GetSpriteRows(),
+		Columns:  c.GetSpriteColumns(),
+		Width:    c.GetSpriteWidth(),
+		Interval: c.GetSpriteInterval(),
+	}
+	generator, err := NewSpriteGenerator(*videoFile, sceneHash, imagePath, vttPath, options)
 
 	if err != nil {
 		logger.Errorf("error creating sprite generator: %s", err.Error())
@@ -43,6 +50,8 @@ func (t *GenerateSpriteTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 		logger.Errorf("error generating sprite: %s", err.Error())
 		return
 	}
+
+	storeGenerated(imagePath, vttPath)
 }
 
 // required returns true if the sprite needs to be generated
@@ -56,7 +65,6 @@ func (t *GenerateSpriteTask) doesSpriteExist(sceneChecksum string) bool {
 		return false
 	}
 
-	imageExists, _ := utils.FileExists(instance.Paths.Scene.GetSpriteImageFilePath(sceneChecksum))
-	vttExists, _ := utils.FileExists(instance.Paths.Scene.GetSpriteVttFilePath(sceneChecksum))
-	return imageExists && vttExists
+	store := instance.GeneratedStore
+	return store.Exists(instance.Paths.Scene.GetSpriteImageFilePath(sceneChecksum)) && store.Exists(instance.Paths.Scene.GetSpriteVttFilePath(sceneChecksum))
 }
@@ -0,0 +1,249 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// identifyBatchSize is the number of scenes queried from stash-box at once.
+const identifyBatchSize = 20
+
+// IdentifyTask queries stash-box endpoints for scenes with the fingerprints
+// of each scene, and sets the metadata of the scenes from the scene they
+// match. The endpoints are queried in order of preference, so that only
+// the scenes not identified by an endpoint are queried from the next.
+// Organized scenes are not identified.
+type IdentifyTask struct {
+	txnManager models.TransactionManager
+	status     *TaskStatus
+
+	// StashBoxes are the endpoints to query, in order of preference.
+	StashBoxes []*models.StashBox
+
+	// SceneIDs are the scenes to identify. All scenes are identified if
+	// empty and SceneFilter is not set.
+	SceneIDs    []int
+	SceneFilter *models.SceneFilterType
+
+	FieldOptions  []*models.IdentifyFieldOptionsInput
+	CreateMissing bool
+	SetOrganized  bool
+
+	// SubmitFingerprints submits the fingerprints of the identified scenes
+	// to the endpoint which identified them.
+	SubmitFingerprints bool
+}
+
+func CreateIdentifyTask(input models.IdentifyMetadataInput) (*IdentifyTask, error) {
+	sceneIDs, err := utils.StringSliceToIntSlice(input.SceneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	boxes := config.GetInstance().GetStashBoxes()
+	if len(boxes) == 0 {
+		return nil, errors.New("no stash-box endpoints are configured")
+	}
+
+	if input.StashBoxIndexes != nil {
+		var selected []*models.StashBox
+		for _, index := range input.StashBoxIndexes {
+			if index < 0 || index >= len(boxes) {
+				return nil, fmt.Errorf("invalid stash_box_index %d", index)
+			}
+			selected = append(selected, boxes[index])
+		}
+		boxes = selected
+	}
+
+	return &IdentifyTask{
+		txnManager:    GetInstance().TxnManager,
+		StashBoxes:    boxes,
+		SceneIDs:      sceneIDs,
+		SceneFilter:   input.SceneFilter,
+		FieldOptions:  input.FieldOptions,
+		CreateMissing: input.CreateMissing != nil && *input.CreateMissing,
+		SetOrganized:  input.SetOrganized != nil && *input.SetOrganized,
+
+		SubmitFingerprints: input.SubmitFingerprints != nil && *input.SubmitFingerprints,
+	}, nil
+}
+
+func (t *IdentifyTask) GetStatus() JobStatus {
+	return Identify
+}
+
+func (t *IdentifyTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *IdentifyTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	scenes, err := t.findScenes(ctx)
+	if err != nil {
+		logger.Errorf("error getting scenes to identify: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	total := len(scenes)
+	t.status.setObjectsTotal(total)
+
+	done := 0
+	identified := 0
+	pending := scenes
+	for boxIndex, box := range t.StashBoxes {
+		last := boxIndex == len(t.StashBoxes)-1
+		client := stashbox.NewClient(*box, t.txnManager)
+
+		var unidentified []*models.Scene
+		for start := 0; start < len(pending); start += identifyBatchSize {
+			if ctx.Err() != nil {
+				logger.Info("Stopping due to user request")
+				return
+			}
+
+			end := start + identifyBatchSize
+			if end > len(pending) {
+				end = len(pending)
+			}
+			batch := pending[start:end]
+
+			ids, err := t.identifyBatch(ctx, client, box, batch)
+			if err != nil {
+				logger.Errorf("[identify] error querying %s: %s", box.Endpoint, err.Error())
+			}
+
+			if t.SubmitFingerprints && len(ids) > 0 {
+				t.submitFingerprints(client, box, ids)
+			}
+
+			// scenes not identified are queried from the next endpoint
+			for _, s := range batch {
+				found := utils.IntInclude(ids, s.ID)
+				if found {
+					identified++
+				} else {
+					unidentified = append(unidentified, s)
+				}
+
+				if found || last {
+					done++
+					t.status.objectDone()
+				}
+			}
+			t.status.setProgress(done, total)
+		}
+
+		pending = unidentified
+	}
+
+	logger.Infof("Identify complete. Identified %d of %d scenes", identified, total)
+}
+
+// findScenes returns the scenes to identify which are not organized and
+// have a fingerprint.
+func (t *IdentifyTask) findScenes(ctx context.Context) ([]*models.Scene, error) {
+	var scenes []*models.Scene
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		switch {
+		case t.SceneFilter != nil:
+			scenes, err = findGenerateScenes(r.Scene(), t.SceneFilter, t.SceneIDs)
+		case len(t.SceneIDs) > 0:
+			scenes, err = r.Scene().FindMany(t.SceneIDs)
+		default:
+			scenes, err = r.Scene().All()
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var ret []*models.Scene
+	for _, s := range scenes {
+		if !s.Organized && (s.Checksum.Valid || s.OSHash.Valid || s.Phash.Valid) {
+			ret = append(ret, s)
+		}
+	}
+
+	return ret, nil
+}
+
+// submitFingerprints submits the fingerprints of the scenes to the endpoint.
+// Errors are logged, since the scenes have already been identified.
+func (t *IdentifyTask) submitFingerprints(client *stashbox.Client, box *models.StashBox, sceneIDs []int) {
+	var ids []string
+	for _, id := range sceneIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+
+	if _, err := client.SubmitStashBoxFingerprints(ids, box.Endpoint); err != nil {
+		logger.Errorf("[identify] error submitting fingerprints to %s: %s", box.Endpoint, err.Error())
+	}
+}
+
+// identifyBatch queries the endpoint with the fingerprints of the scenes,
+// and sets the metadata of the scenes which match a result. Returns the IDs
+// of the identified scenes.
+func (t *IdentifyTask) identifyBatch(ctx context.Context, client *stashbox.Client, box *models.StashBox, scenes []*models.Scene) ([]int, error) {
+	var sceneIDs []string
+	for _, s := range scenes {
+		sceneIDs = append(sceneIDs, strconv.Itoa(s.ID))
+	}
+
+	results, err := client.FindStashBoxScenesByFingerprints(sceneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	for _, s := range scenes {
+		result := scene.MatchIdentified(s, results)
+		if result == nil {
+			continue
+		}
+
+		var changed bool
+		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
+			identifier := scene.Identifier{
+				ReaderWriter:    r.Scene(),
+				StudioWriter:    r.Studio(),
+				PerformerWriter: r.Performer(),
+				TagWriter:       r.Tag(),
+				Endpoint:        box.Endpoint,
+				FieldOptions:    t.FieldOptions,
+				CreateMissing:   t.CreateMissing,
+				SetOrganized:    t.SetOrganized,
+			}
+
+			var err error
+			changed, err = identifier.Identify(s, result)
+			return err
+		}); err != nil {
+			logger.Errorf("[identify] <%s> error setting metadata: %s", s.Path, err.Error())
+			continue
+		}
+
+		if changed {
+			logger.Infof("[identify] <%s> identified by %s", s.Path, box.Endpoint)
+		}
+		ret = append(ret, s.ID)
+	}
+
+	return ret, nil
+}
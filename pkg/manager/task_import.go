@@ -29,6 +29,7 @@ import (
 
 type ImportTask struct {
 	txnManager models.TransactionManager
+	status     *TaskStatus
 	json       jsonUtils
 
 	BaseDir             string
@@ -37,9 +38,46 @@ type ImportTask struct {
 	DuplicateBehaviour  models.ImportDuplicateEnum
 	MissingRefBehaviour models.ImportMissingRefEnum
 
+	// DryRun walks the import without saving any changes. The database is
+	// not reset.
+	DryRun bool
+
+	// ObjectTypes are the types of object to import. All types are
+	// imported if empty.
+	ObjectTypes []models.ImportObjectType
+
+	// Checksums are the mapping checksums of the objects to import. All
+	// objects of the imported types are imported if empty. Scraped items
+	// have no checksum, so are not imported if set.
+	Checksums []string
+
+	// PathMappings replace the path prefixes of the imported scenes, images
+	// and galleries.
+	PathMappings []*models.ImportPathMappingInput
+
+	// ReportFile, if set, is the file to which the report is written when
+	// the import finishes. The reports of dry runs are not written.
+	ReportFile string
+
+	// ImagesPath, if set, is a directory with the layout of the blobs
+	// directory of an export. Performers, studios and movies without images
+	// which are not in the blobs directory of the import are read from it.
+	ImagesPath string
+
+	// Workers is the number of objects of the same type imported
+	// concurrently. Studios are always imported one at a time, so that
+	// parent studios are created first.
+	Workers int
+
 	mappings            *jsonschema.Mappings
 	scraped             []jsonschema.ScrapedItem
 	fileNamingAlgorithm models.HashAlgorithm
+
+	report importReport
+	err    error
+
+	// guards the status updates made by the import workers
+	statusMutex sync.Mutex
 }
 
 func CreateImportTask(a models.HashAlgorithm, input models.ImportObjectsInput) (*ImportTask, error) {
@@ -64,6 +102,11 @@ func CreateImportTask(a models.HashAlgorithm, input models.ImportObjectsInput) (
 		}
 	}
 
+	imagesPath := ""
+	if input.ImagesPath != nil {
+		imagesPath = *input.ImagesPath
+	}
+
 	return &ImportTask{
 		txnManager:          GetInstance().TxnManager,
 		BaseDir:             baseDir,
@@ -71,16 +114,111 @@ func CreateImportTask(a models.HashAlgorithm, input models.ImportObjectsInput) (
 		Reset:               false,
 		DuplicateBehaviour:  input.DuplicateBehaviour,
 		MissingRefBehaviour: input.MissingRefBehaviour,
+		ObjectTypes:         input.Types,
+		Checksums:           input.Checksums,
+		PathMappings:        input.PathMappings,
+		ImagesPath:          imagesPath,
+		ReportFile:          GetInstance().Paths.Generated.ImportReport,
+		Workers:             config.GetInstance().GetImportWorkersWithAutoDetection(),
 		fileNamingAlgorithm: a,
 	}, nil
 }
 
+// Report returns what the import did, or would do in a dry run, to each
+// object. Returns an error if the import failed before importing objects.
+func (t *ImportTask) Report() (*models.ImportReport, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	return &t.report.ImportReport, nil
+}
+
+func (t *ImportTask) setError(err error) {
+	t.err = err
+	t.status.setError(err)
+}
+
+// progress reports the progress through the objects of a single type.
+func (t *ImportTask) progress(objectType string, upTo int, total int) {
+	if t.status == nil {
+		logger.Progressf("[%s] %d of %d", objectType, upTo, total)
+		return
+	}
+
+	if t.status.Phase != objectType {
+		t.status.setPhase(objectType)
+	}
+	t.status.setStepProgress("Importing "+objectType, upTo, total)
+}
+
+// filterObjects removes the objects which are not to be imported from the
+// mappings.
+func (t *ImportTask) filterObjects() {
+	if len(t.ObjectTypes) == 0 && len(t.Checksums) == 0 {
+		return
+	}
+
+	types := make(map[models.ImportObjectType]bool)
+	for _, objectType := range t.ObjectTypes {
+		types[objectType] = true
+	}
+
+	checksums := make(map[string]bool)
+	for _, checksum := range t.Checksums {
+		checksums[checksum] = true
+	}
+
+	filter := func(objectType models.ImportObjectType, mappings []jsonschema.PathNameMapping) []jsonschema.PathNameMapping {
+		if len(types) > 0 && !types[objectType] {
+			return nil
+		}
+
+		if len(checksums) == 0 {
+			return mappings
+		}
+
+		var ret []jsonschema.PathNameMapping
+		for _, m := range mappings {
+			if checksums[m.Checksum] {
+				ret = append(ret, m)
+			}
+		}
+
+		return ret
+	}
+
+	m := t.mappings
+	m.Tags = filter(models.ImportObjectTypeTag, m.Tags)
+	m.Performers = filter(models.ImportObjectTypePerformer, m.Performers)
+	m.Studios = filter(models.ImportObjectTypeStudio, m.Studios)
+	m.Movies = filter(models.ImportObjectTypeMovie, m.Movies)
+	m.Galleries = filter(models.ImportObjectTypeGallery, m.Galleries)
+	m.Scenes = filter(models.ImportObjectTypeScene, m.Scenes)
+	m.Images = filter(models.ImportObjectTypeImage, m.Images)
+
+	if len(checksums) > 0 || (len(types) > 0 && !types[models.ImportObjectTypeScrapedItem]) {
+		t.scraped = nil
+	}
+}
+
+// objectCount returns the number of objects to be imported.
+func (t *ImportTask) objectCount() int {
+	m := t.mappings
+	return len(m.Tags) + len(m.Performers) + len(m.Studios) + len(m.Movies) + len(m.Galleries) + len(t.scraped) + len(m.Scenes) + len(m.Images)
+}
+
 func (t *ImportTask) GetStatus() JobStatus {
 	return Import
 }
 
+func (t *ImportTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
 func (t *ImportTask) Start(wg *sync.WaitGroup) {
 	defer wg.Done()
+	defer t.finishReport()
 
 	if t.TmpZip != "" {
 		defer func() {
@@ -92,6 +230,7 @@ func (t *ImportTask) Start(wg *sync.WaitGroup) {
 
 		if err := t.unzipFile(); err != nil {
 			logger.Errorf("error unzipping provided file for import: %s", err.Error())
+			t.setError(err)
 			return
 		}
 	}
@@ -100,6 +239,12 @@ func (t *ImportTask) Start(wg *sync.WaitGroup) {
 		json: *paths.GetJSONPaths(t.BaseDir),
 	}
 
+	if err := t.json.loadLines(); err != nil {
+		logger.Errorf("error reading objects file: %s", err.Error())
+		t.setError(err)
+		return
+	}
+
 	// set default behaviour if not provided
 	if !t.DuplicateBehaviour.IsValid() {
 		t.DuplicateBehaviour = models.ImportDuplicateEnumFail
@@ -107,29 +252,73 @@ func (t *ImportTask) Start(wg *sync.WaitGroup) {
 	if !t.MissingRefBehaviour.IsValid() {
 		t.MissingRefBehaviour = models.ImportMissingRefEnumFail
 	}
+	if t.Workers <= 0 {
+		t.Workers = 1
+	}
 
-	t.mappings, _ = t.json.getMappings()
-	if t.mappings == nil {
-		logger.Error("missing mappings json")
+	var err error
+	t.mappings, err = t.json.getMappings()
+	if err != nil {
+		logger.Errorf("error reading mappings json: %s", err.Error())
+		t.setError(fmt.Errorf("error reading mappings json: %s", err.Error()))
 		return
 	}
+	if t.json.version < jsonschema.SchemaVersion {
+		logger.Infof("Upgrading objects from export schema version %d", t.json.version)
+	}
 	scraped, _ := t.json.getScraped()
 	if scraped == nil {
 		logger.Warn("missing scraped json")
 	}
 	t.scraped = scraped
 
-	if t.Reset {
+	t.filterObjects()
+	t.remapPaths()
+
+	if t.Reset && !t.DryRun {
 		err := database.Reset(config.GetInstance().GetDatabasePath())
 
 		if err != nil {
 			logger.Errorf("Error resetting database: %s", err.Error())
+			t.setError(err)
 			return
 		}
 	}
 
-	ctx := context.TODO()
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	t.status.setObjectsTotal(t.objectCount())
+	t.report = importReport{
+		ImportReport: models.ImportReport{
+			DryRun: t.DryRun,
+		},
+	}
+
+	if !t.DryRun {
+		t.importObjects(ctx)
+		return
+	}
+
+	logger.Info("Performing import dry run. No changes will be saved")
 
+	// import using the dry run transactions, which are rolled back afterwards
+	txnManager := t.txnManager
+	defer func() {
+		t.txnManager = txnManager
+	}()
+
+	if err := txnManager.DryRun(ctx, func(dryRunManager models.TransactionManager) error {
+		t.txnManager = dryRunManager
+		t.importObjects(ctx)
+		return nil
+	}); err != nil {
+		logger.Errorf("Error performing import dry run: %s", err.Error())
+		t.setError(err)
+	}
+}
+
+func (t *ImportTask) importObjects(ctx context.Context) {
 	t.ImportTags(ctx)
 	t.ImportPerformers(ctx)
 	t.ImportStudios(ctx)
@@ -139,18 +328,49 @@ func (t *ImportTask) Start(wg *sync.WaitGroup) {
 	t.ImportScrapedItems(ctx)
 	t.ImportScenes(ctx)
 	t.ImportImages(ctx)
+
+	if ctx.Err() != nil {
+		logger.Info("Stopping due to user request")
+		t.report.Stopped = true
+	}
+}
+
+// finishReport completes the report once the import has finished, and
+// writes it to ReportFile so that it can be retrieved afterwards.
+func (t *ImportTask) finishReport() {
+	now := time.Now()
+	t.report.DryRun = t.DryRun
+	t.report.FinishedAt = &now
+	if t.err != nil {
+		errStr := t.err.Error()
+		t.report.Error = &errStr
+	}
+
+	logger.Infof("Import finished: %s", t.report.summary())
+
+	if t.DryRun || t.ReportFile == "" {
+		return
+	}
+
+	if err := saveImportReport(t.ReportFile, &t.report.ImportReport); err != nil {
+		logger.Errorf("error writing import report to %s: %s", t.ReportFile, err.Error())
+	}
 }
 
 func (t *ImportTask) unzipFile() error {
 	defer func() {
 		err := os.Remove(t.TmpZip)
-		if err != nil {
+		if err != nil && !os.IsNotExist(err) {
 			logger.Errorf("error removing temporary zip file %s: %s", t.TmpZip, err.Error())
 		}
 	}()
 
 	// now we can read the zip file
 	r, err := zip.OpenReader(t.TmpZip)
+	if err == zip.ErrFormat {
+		// not a zip file, so import it as a JSON lines file
+		return os.Rename(t.TmpZip, paths.GetJSONPaths(t.BaseDir).ObjectsFile)
+	}
 	if err != nil {
 		return err
 	}
@@ -193,33 +413,51 @@ func (t *ImportTask) unzipFile() error {
 }
 
 func (t *ImportTask) ImportPerformers(ctx context.Context) {
-	logger.Info("[performers] importing")
+	t.importConcurrently(ctx, "performers", t.mappings.Performers, t.importPerformer)
+}
 
-	for i, mappingJSON := range t.mappings.Performers {
-		index := i + 1
-		performerJSON, err := t.json.getPerformer(mappingJSON.Checksum)
-		if err != nil {
-			logger.Errorf("[performers] failed to read json: %s", err.Error())
-			continue
+func (t *ImportTask) importPerformer(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+	performerJSON, err := t.json.getPerformer(mappingJSON.Checksum)
+	if err != nil {
+		logger.Errorf("[performers] failed to read json: %s", err.Error())
+		t.report.add("performer", mappingJSON.Checksum, mappingJSON.Name, models.ImportObjectResultFail, err)
+		return
+	}
+
+	performerJSON.Image = t.blobImage(blobPerformers, mappingJSON.Checksum, performerJSON.Image)
+
+	if err := t.importObject(ctx, "performer", mappingJSON.Checksum, performerJSON.Name, func(r models.Repository) (models.ImportObjectResult, error) {
+		readerWriter := r.Performer()
+		importer := &performer.Importer{
+			ReaderWriter: readerWriter,
+			TagWriter:    r.Tag(),
+			Input:        *performerJSON,
 		}
 
-		logger.Progressf("[performers] %d of %d", index, len(t.mappings.Performers))
+		return performImport(importer, t.DuplicateBehaviour)
+	}); err != nil {
+		logger.Errorf("[performers] <%s> import failed: %s", mappingJSON.Checksum, err.Error())
+	}
+}
+
+// blobImage returns the image of an imported object. If the object has no
+// image, the image exported as a separate file with the name is read from
+// the blobs directory of the import, or from ImagesPath.
+func (t *ImportTask) blobImage(dir string, name string, image string) string {
+	if image != "" {
+		return image
+	}
 
-		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			readerWriter := r.Performer()
-			importer := &performer.Importer{
-				ReaderWriter: readerWriter,
-				TagWriter:    r.Tag(),
-				Input:        *performerJSON,
-			}
+	ret, err := t.json.getBlob(dir, name)
+	if ret == "" && err == nil && t.ImagesPath != "" {
+		ret, err = findBlob(t.ImagesPath, dir, name)
+	}
 
-			return performImport(importer, t.DuplicateBehaviour)
-		}); err != nil {
-			logger.Errorf("[performers] <%s> import failed: %s", mappingJSON.Checksum, err.Error())
-		}
+	if err != nil {
+		logger.Warnf("[%s] <%s> error reading image: %s", dir, name, err.Error())
 	}
 
-	logger.Info("[performers] import complete")
+	return ret
 }
 
 func (t *ImportTask) ImportStudios(ctx context.Context) {
@@ -228,17 +466,30 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 	logger.Info("[studios] importing")
 
 	for i, mappingJSON := range t.mappings.Studios {
-		index := i + 1
+		if ctx.Err() != nil {
+			return
+		}
+
+		t.progress("studios", i+1, len(t.mappings.Studios))
+
+		// studios pending their parent are counted here, rather than when
+		// they are imported
+		t.status.objectDone()
+
 		studioJSON, err := t.json.getStudio(mappingJSON.Checksum)
 		if err != nil {
 			logger.Errorf("[studios] failed to read json: %s", err.Error())
+			t.report.add("studio", mappingJSON.Checksum, mappingJSON.Name, models.ImportObjectResultFail, err)
 			continue
 		}
 
-		logger.Progressf("[studios] %d of %d", index, len(t.mappings.Studios))
+		studioJSON.Image = t.blobImage(blobStudios, mappingJSON.Checksum, studioJSON.Image)
 
+		var results []*models.ImportObjectReport
 		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			return t.ImportStudio(studioJSON, pendingParent, r.Studio())
+			var err error
+			results, err = t.ImportStudio(studioJSON, pendingParent, r.Studio())
+			return err
 		}); err != nil {
 			if err == studio.ErrParentStudioNotExist {
 				// add to the pending parent list so that it is created after the parent
@@ -249,8 +500,11 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 			}
 
 			logger.Errorf("[studios] <%s> failed to create: %s", mappingJSON.Checksum, err.Error())
+			t.report.add("studio", mappingJSON.Checksum, studioJSON.Name, models.ImportObjectResultFail, err)
 			continue
 		}
+
+		t.report.addResults(results)
 	}
 
 	// create the leftover studios, warning for missing parents
@@ -259,12 +513,22 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 
 		for _, s := range pendingParent {
 			for _, orphanStudioJSON := range s {
+				if ctx.Err() != nil {
+					return
+				}
+
+				var results []*models.ImportObjectReport
 				if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-					return t.ImportStudio(orphanStudioJSON, nil, r.Studio())
+					var err error
+					results, err = t.ImportStudio(orphanStudioJSON, nil, r.Studio())
+					return err
 				}); err != nil {
 					logger.Errorf("[studios] <%s> failed to create: %s", orphanStudioJSON.Name, err.Error())
+					t.report.add("studio", utils.MD5FromString(orphanStudioJSON.Name), orphanStudioJSON.Name, models.ImportObjectResultFail, err)
 					continue
 				}
+
+				t.report.addResults(results)
 			}
 		}
 	}
@@ -272,7 +536,9 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 	logger.Info("[studios] import complete")
 }
 
-func (t *ImportTask) ImportStudio(studioJSON *jsonschema.Studio, pendingParent map[string][]*jsonschema.Studio, readerWriter models.StudioReaderWriter) error {
+// ImportStudio imports the studio, followed by the studios pending its
+// creation. Returns the results of importing each studio.
+func (t *ImportTask) ImportStudio(studioJSON *jsonschema.Studio, pendingParent map[string][]*jsonschema.Studio, readerWriter models.StudioReaderWriter) ([]*models.ImportObjectReport, error) {
 	importer := &studio.Importer{
 		ReaderWriter:        readerWriter,
 		Input:               *studioJSON,
@@ -284,129 +550,127 @@ func (t *ImportTask) ImportStudio(studioJSON *jsonschema.Studio, pendingParent m
 		importer.MissingRefBehaviour = models.ImportMissingRefEnumFail
 	}
 
-	if err := performImport(importer, t.DuplicateBehaviour); err != nil {
-		return err
+	result, err := performImport(importer, t.DuplicateBehaviour)
+	if err != nil {
+		return nil, err
 	}
 
+	var report importReport
+	report.add("studio", utils.MD5FromString(studioJSON.Name), studioJSON.Name, result, nil)
+
 	// now create the studios pending this studios creation
 	s := pendingParent[studioJSON.Name]
 	for _, childStudioJSON := range s {
 		// map is nil since we're not checking parent studios at this point
-		if err := t.ImportStudio(childStudioJSON, nil, readerWriter); err != nil {
-			return fmt.Errorf("failed to create child studio <%s>: %s", childStudioJSON.Name, err.Error())
+		results, err := t.ImportStudio(childStudioJSON, nil, readerWriter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create child studio <%s>: %s", childStudioJSON.Name, err.Error())
 		}
+
+		report.addResults(results)
 	}
 
 	// delete the entry from the map so that we know its not left over
 	delete(pendingParent, studioJSON.Name)
 
-	return nil
+	return report.Objects, nil
 }
 
 func (t *ImportTask) ImportMovies(ctx context.Context) {
-	logger.Info("[movies] importing")
+	t.importConcurrently(ctx, "movies", t.mappings.Movies, t.importMovie)
+}
 
-	for i, mappingJSON := range t.mappings.Movies {
-		index := i + 1
-		movieJSON, err := t.json.getMovie(mappingJSON.Checksum)
-		if err != nil {
-			logger.Errorf("[movies] failed to read json: %s", err.Error())
-			continue
-		}
+func (t *ImportTask) importMovie(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+	movieJSON, err := t.json.getMovie(mappingJSON.Checksum)
+	if err != nil {
+		logger.Errorf("[movies] failed to read json: %s", err.Error())
+		t.report.add("movie", mappingJSON.Checksum, mappingJSON.Name, models.ImportObjectResultFail, err)
+		return
+	}
 
-		logger.Progressf("[movies] %d of %d", index, len(t.mappings.Movies))
+	movieJSON.FrontImage = t.blobImage(blobMovies, mappingJSON.Checksum+"-front", movieJSON.FrontImage)
+	movieJSON.BackImage = t.blobImage(blobMovies, mappingJSON.Checksum+"-back", movieJSON.BackImage)
 
-		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			readerWriter := r.Movie()
-			studioReaderWriter := r.Studio()
+	if err := t.importObject(ctx, "movie", mappingJSON.Checksum, movieJSON.Name, func(r models.Repository) (models.ImportObjectResult, error) {
+		readerWriter := r.Movie()
+		studioReaderWriter := r.Studio()
 
-			movieImporter := &movie.Importer{
-				ReaderWriter:        readerWriter,
-				StudioWriter:        studioReaderWriter,
-				Input:               *movieJSON,
-				MissingRefBehaviour: t.MissingRefBehaviour,
-			}
-
-			return performImport(movieImporter, t.DuplicateBehaviour)
-		}); err != nil {
-			logger.Errorf("[movies] <%s> import failed: %s", mappingJSON.Checksum, err.Error())
-			continue
+		movieImporter := &movie.Importer{
+			ReaderWriter:        readerWriter,
+			StudioWriter:        studioReaderWriter,
+			Input:               *movieJSON,
+			MissingRefBehaviour: t.MissingRefBehaviour,
 		}
-	}
 
-	logger.Info("[movies] import complete")
+		return performImport(movieImporter, t.DuplicateBehaviour)
+	}); err != nil {
+		logger.Errorf("[movies] <%s> import failed: %s", mappingJSON.Checksum, err.Error())
+	}
 }
 
 func (t *ImportTask) ImportGalleries(ctx context.Context) {
-	logger.Info("[galleries] importing")
+	t.importConcurrently(ctx, "galleries", t.mappings.Galleries, t.importGallery)
+}
 
-	for i, mappingJSON := range t.mappings.Galleries {
-		index := i + 1
-		galleryJSON, err := t.json.getGallery(mappingJSON.Checksum)
-		if err != nil {
-			logger.Errorf("[galleries] failed to read json: %s", err.Error())
-			continue
-		}
+func (t *ImportTask) importGallery(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+	galleryJSON, err := t.json.getGallery(mappingJSON.Checksum)
+	if err != nil {
+		logger.Errorf("[galleries] failed to read json: %s", err.Error())
+		t.report.add("gallery", mappingJSON.Checksum, mappingJSON.Name, models.ImportObjectResultFail, err)
+		return
+	}
 
-		logger.Progressf("[galleries] %d of %d", index, len(t.mappings.Galleries))
+	galleryJSON.Path = remapPath(t.PathMappings, galleryJSON.Path)
 
-		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			readerWriter := r.Gallery()
-			tagWriter := r.Tag()
-			performerWriter := r.Performer()
-			studioWriter := r.Studio()
-
-			galleryImporter := &gallery.Importer{
-				ReaderWriter:        readerWriter,
-				PerformerWriter:     performerWriter,
-				StudioWriter:        studioWriter,
-				TagWriter:           tagWriter,
-				Input:               *galleryJSON,
-				MissingRefBehaviour: t.MissingRefBehaviour,
-			}
+	if err := t.importObject(ctx, "gallery", mappingJSON.Checksum, galleryJSON.Title, func(r models.Repository) (models.ImportObjectResult, error) {
+		readerWriter := r.Gallery()
+		tagWriter := r.Tag()
+		performerWriter := r.Performer()
+		studioWriter := r.Studio()
 
-			return performImport(galleryImporter, t.DuplicateBehaviour)
-		}); err != nil {
-			logger.Errorf("[galleries] <%s> import failed to commit: %s", mappingJSON.Checksum, err.Error())
-			continue
+		galleryImporter := &gallery.Importer{
+			ReaderWriter:        readerWriter,
+			PerformerWriter:     performerWriter,
+			StudioWriter:        studioWriter,
+			TagWriter:           tagWriter,
+			Input:               *galleryJSON,
+			MissingRefBehaviour: t.MissingRefBehaviour,
 		}
-	}
 
-	logger.Info("[galleries] import complete")
+		return performImport(galleryImporter, t.DuplicateBehaviour)
+	}); err != nil {
+		logger.Errorf("[galleries] <%s> import failed to commit: %s", mappingJSON.Checksum, err.Error())
+	}
 }
 
 func (t *ImportTask) ImportTags(ctx context.Context) {
-	logger.Info("[tags] importing")
-
-	for i, mappingJSON := range t.mappings.Tags {
-		index := i + 1
-		tagJSON, err := t.json.getTag(mappingJSON.Checksum)
-		if err != nil {
-			logger.Errorf("[tags] failed to read json: %s", err.Error())
-			continue
-		}
-
-		logger.Progressf("[tags] %d of %d", index, len(t.mappings.Tags))
+	t.importConcurrently(ctx, "tags", t.mappings.Tags, t.importTag)
+}
 
-		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			readerWriter := r.Tag()
+func (t *ImportTask) importTag(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+	tagJSON, err := t.json.getTag(mappingJSON.Checksum)
+	if err != nil {
+		logger.Errorf("[tags] failed to read json: %s", err.Error())
+		t.report.add("tag", mappingJSON.Checksum, mappingJSON.Name, models.ImportObjectResultFail, err)
+		return
+	}
 
-			tagImporter := &tag.Importer{
-				ReaderWriter: readerWriter,
-				Input:        *tagJSON,
-			}
+	if err := t.importObject(ctx, "tag", mappingJSON.Checksum, tagJSON.Name, func(r models.Repository) (models.ImportObjectResult, error) {
+		readerWriter := r.Tag()
 
-			return performImport(tagImporter, t.DuplicateBehaviour)
-		}); err != nil {
-			logger.Errorf("[tags] <%s> failed to import: %s", mappingJSON.Checksum, err.Error())
-			continue
+		tagImporter := &tag.Importer{
+			ReaderWriter: readerWriter,
+			Input:        *tagJSON,
 		}
-	}
 
-	logger.Info("[tags] import complete")
+		return performImport(tagImporter, t.DuplicateBehaviour)
+	}); err != nil {
+		logger.Errorf("[tags] <%s> failed to import: %s", mappingJSON.Checksum, err.Error())
+	}
 }
 
 func (t *ImportTask) ImportScrapedItems(ctx context.Context) {
+	var report importReport
 	if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
 		logger.Info("[scraped sites] importing")
 		qb := r.ScrapedItem()
@@ -414,8 +678,13 @@ func (t *ImportTask) ImportScrapedItems(ctx context.Context) {
 		currentTime := time.Now()
 
 		for i, mappingJSON := range t.scraped {
+			if ctx.Err() != nil {
+				break
+			}
+
 			index := i + 1
-			logger.Progressf("[scraped sites] %d of %d", index, len(t.mappings.Scenes))
+			t.progress("scraped sites", index, len(t.scraped))
+			t.status.objectDone()
 
 			newScrapedItem := models.ScrapedItem{
 				Title:           sql.NullString{String: mappingJSON.Title, Valid: true},
@@ -446,127 +715,196 @@ func (t *ImportTask) ImportScrapedItems(ctx context.Context) {
 			if err != nil {
 				logger.Errorf("[scraped sites] <%s> failed to create: %s", newScrapedItem.Title.String, err.Error())
 			}
+			report.add("scraped item", mappingJSON.URL, mappingJSON.Title, models.ImportObjectResultCreate, err)
 		}
 
 		return nil
 	}); err != nil {
 		logger.Errorf("[scraped sites] import failed to commit: %s", err.Error())
+
+		if ctx.Err() != nil {
+			// the items were rolled back by stopping the task
+			report.Objects = nil
+		}
+
+		errStr := err.Error()
+		for _, o := range report.Objects {
+			o.Result = models.ImportObjectResultFail
+			o.Error = &errStr
+		}
 	}
 
+	t.report.addResults(report.Objects)
+
 	logger.Info("[scraped sites] import complete")
 }
 
 func (t *ImportTask) ImportScenes(ctx context.Context) {
-	logger.Info("[scenes] importing")
-
-	for i, mappingJSON := range t.mappings.Scenes {
-		index := i + 1
+	t.importConcurrently(ctx, "scenes", t.mappings.Scenes, t.importScene)
+}
 
-		logger.Progressf("[scenes] %d of %d", index, len(t.mappings.Scenes))
+func (t *ImportTask) importScene(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+	sceneJSON, err := t.json.getScene(mappingJSON.Checksum)
+	if err != nil {
+		logger.Infof("[scenes] <%s> json parse failure: %s", mappingJSON.Checksum, err.Error())
+		t.report.add("scene", mappingJSON.Checksum, mappingJSON.Path, models.ImportObjectResultFail, err)
+		return
+	}
 
-		sceneJSON, err := t.json.getScene(mappingJSON.Checksum)
-		if err != nil {
-			logger.Infof("[scenes] <%s> json parse failure: %s", mappingJSON.Checksum, err.Error())
-			continue
-		}
+	sceneHash := mappingJSON.Checksum
 
-		sceneHash := mappingJSON.Checksum
+	if err := t.importObject(ctx, "scene", sceneHash, mappingJSON.Path, func(r models.Repository) (models.ImportObjectResult, error) {
+		readerWriter := r.Scene()
+		tagWriter := r.Tag()
+		galleryWriter := r.Gallery()
+		movieWriter := r.Movie()
+		performerWriter := r.Performer()
+		studioWriter := r.Studio()
+		markerWriter := r.SceneMarker()
 
-		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			readerWriter := r.Scene()
-			tagWriter := r.Tag()
-			galleryWriter := r.Gallery()
-			movieWriter := r.Movie()
-			performerWriter := r.Performer()
-			studioWriter := r.Studio()
-			markerWriter := r.SceneMarker()
-
-			sceneImporter := &scene.Importer{
-				ReaderWriter: readerWriter,
-				Input:        *sceneJSON,
-				Path:         mappingJSON.Path,
-
-				FileNamingAlgorithm: t.fileNamingAlgorithm,
-				MissingRefBehaviour: t.MissingRefBehaviour,
+		sceneImporter := &scene.Importer{
+			ReaderWriter: readerWriter,
+			Input:        *sceneJSON,
+			Path:         mappingJSON.Path,
 
-				GalleryWriter:   galleryWriter,
-				MovieWriter:     movieWriter,
-				PerformerWriter: performerWriter,
-				StudioWriter:    studioWriter,
-				TagWriter:       tagWriter,
-			}
+			FileNamingAlgorithm: t.fileNamingAlgorithm,
+			MissingRefBehaviour: t.MissingRefBehaviour,
 
-			if err := performImport(sceneImporter, t.DuplicateBehaviour); err != nil {
-				return err
-			}
+			GalleryWriter:   galleryWriter,
+			MovieWriter:     movieWriter,
+			PerformerWriter: performerWriter,
+			StudioWriter:    studioWriter,
+			TagWriter:       tagWriter,
+		}
 
-			// import the scene markers
-			for _, m := range sceneJSON.Markers {
-				markerImporter := &scene.MarkerImporter{
-					SceneID:             sceneImporter.ID,
-					Input:               m,
-					MissingRefBehaviour: t.MissingRefBehaviour,
-					ReaderWriter:        markerWriter,
-					TagWriter:           tagWriter,
-				}
+		result, err := performImport(sceneImporter, t.DuplicateBehaviour)
+		if err != nil {
+			return result, err
+		}
 
-				if err := performImport(markerImporter, t.DuplicateBehaviour); err != nil {
-					return err
-				}
+		// import the scene markers
+		for _, m := range sceneJSON.Markers {
+			markerImporter := &scene.MarkerImporter{
+				SceneID:             sceneImporter.ID,
+				Input:               m,
+				MissingRefBehaviour: t.MissingRefBehaviour,
+				ReaderWriter:        markerWriter,
+				TagWriter:           tagWriter,
 			}
 
-			return nil
-		}); err != nil {
-			logger.Errorf("[scenes] <%s> import failed: %s", sceneHash, err.Error())
+			if _, err := performImport(markerImporter, t.DuplicateBehaviour); err != nil {
+				return models.ImportObjectResultFail, fmt.Errorf("error importing scene marker: %s", err.Error())
+			}
 		}
-	}
 
-	logger.Info("[scenes] import complete")
+		return result, nil
+	}); err != nil {
+		logger.Errorf("[scenes] <%s> import failed: %s", sceneHash, err.Error())
+	}
 }
 
 func (t *ImportTask) ImportImages(ctx context.Context) {
-	logger.Info("[images] importing")
+	t.importConcurrently(ctx, "images", t.mappings.Images, t.importImage)
+}
 
-	for i, mappingJSON := range t.mappings.Images {
-		index := i + 1
+func (t *ImportTask) importImage(ctx context.Context, mappingJSON jsonschema.PathNameMapping) {
+	imageJSON, err := t.json.getImage(mappingJSON.Checksum)
+	if err != nil {
+		logger.Infof("[images] <%s> json parse failure: %s", mappingJSON.Checksum, err.Error())
+		t.report.add("image", mappingJSON.Checksum, mappingJSON.Path, models.ImportObjectResultFail, err)
+		return
+	}
 
-		logger.Progressf("[images] %d of %d", index, len(t.mappings.Images))
+	imageHash := mappingJSON.Checksum
 
-		imageJSON, err := t.json.getImage(mappingJSON.Checksum)
-		if err != nil {
-			logger.Infof("[images] <%s> json parse failure: %s", mappingJSON.Checksum, err.Error())
-			continue
-		}
+	if err := t.importObject(ctx, "image", imageHash, mappingJSON.Path, func(r models.Repository) (models.ImportObjectResult, error) {
+		readerWriter := r.Image()
+		tagWriter := r.Tag()
+		galleryWriter := r.Gallery()
+		performerWriter := r.Performer()
+		studioWriter := r.Studio()
 
-		imageHash := mappingJSON.Checksum
+		imageImporter := &image.Importer{
+			ReaderWriter: readerWriter,
+			Input:        *imageJSON,
+			Path:         mappingJSON.Path,
 
-		if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
-			readerWriter := r.Image()
-			tagWriter := r.Tag()
-			galleryWriter := r.Gallery()
-			performerWriter := r.Performer()
-			studioWriter := r.Studio()
+			MissingRefBehaviour: t.MissingRefBehaviour,
 
-			imageImporter := &image.Importer{
-				ReaderWriter: readerWriter,
-				Input:        *imageJSON,
-				Path:         mappingJSON.Path,
+			GalleryWriter:   galleryWriter,
+			PerformerWriter: performerWriter,
+			StudioWriter:    studioWriter,
+			TagWriter:       tagWriter,
+		}
 
-				MissingRefBehaviour: t.MissingRefBehaviour,
+		return performImport(imageImporter, t.DuplicateBehaviour)
+	}); err != nil {
+		logger.Errorf("[images] <%s> import failed: %s", imageHash, err.Error())
+	}
+}
 
-				GalleryWriter:   galleryWriter,
-				PerformerWriter: performerWriter,
-				StudioWriter:    studioWriter,
-				TagWriter:       tagWriter,
+// importConcurrently imports each of the objects using importFn, with up
+// to t.Workers objects being imported at once. It returns once all of the
+// objects have been imported, or the import is stopped. Transactions are
+// still made one at a time, so workers mostly save time reading and parsing
+// the json files.
+func (t *ImportTask) importConcurrently(ctx context.Context, objectType string, mappings []jsonschema.PathNameMapping, importFn func(ctx context.Context, mappingJSON jsonschema.PathNameMapping)) {
+	logger.Infof("[%s] importing", objectType)
+
+	var wg sync.WaitGroup
+	jobCh := make(chan jsonschema.PathNameMapping, t.Workers*2) // make a buffered channel to feed workers
+
+	done := 0
+	for w := 0; w < t.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for mappingJSON := range jobCh {
+				importFn(ctx, mappingJSON)
+
+				t.statusMutex.Lock()
+				done++
+				t.progress(objectType, done, len(mappings))
+				t.status.objectDone()
+				t.statusMutex.Unlock()
 			}
+		}()
+	}
 
-			return performImport(imageImporter, t.DuplicateBehaviour)
-		}); err != nil {
-			logger.Errorf("[images] <%s> import failed: %s", imageHash, err.Error())
+	for _, mappingJSON := range mappings {
+		if ctx.Err() != nil {
+			break
 		}
+
+		jobCh <- mappingJSON // feed workers
+	}
+
+	close(jobCh) // close channel so that workers will know no more jobs are available
+	wg.Wait()
+
+	if ctx.Err() == nil {
+		logger.Infof("[%s] import complete", objectType)
+	}
+}
+
+// importObject imports a single object in its own transaction, adding the
+// result to the report. Objects interrupted by stopping the task are rolled
+// back and omitted from the report.
+func (t *ImportTask) importObject(ctx context.Context, objectType string, key string, name string, fn func(r models.Repository) (models.ImportObjectResult, error)) error {
+	var result models.ImportObjectResult
+	err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
+		var err error
+		result, err = fn(r)
+		return err
+	})
+
+	if err != nil && ctx.Err() != nil {
+		return err
 	}
 
-	logger.Info("[images] import complete")
+	t.report.add(objectType, key, name, result, err)
+	return err
 }
 
 func (t *ImportTask) getPerformers(names []string, qb models.PerformerReader) ([]*models.Performer, error) {
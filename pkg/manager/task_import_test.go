@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestMappings() *jsonschema.Mappings {
+	return &jsonschema.Mappings{
+		Tags:       []jsonschema.PathNameMapping{{Checksum: "tag1"}, {Checksum: "tag2"}},
+		Performers: []jsonschema.PathNameMapping{{Checksum: "performer1"}},
+		Studios:    []jsonschema.PathNameMapping{{Checksum: "studio1"}},
+		Scenes:     []jsonschema.PathNameMapping{{Checksum: "scene1"}},
+	}
+}
+
+func TestImportTaskFilterObjects(t *testing.T) {
+	scraped := []jsonschema.ScrapedItem{{Title: "scraped"}}
+
+	// everything is imported by default
+	task := &ImportTask{
+		mappings: makeTestMappings(),
+		scraped:  scraped,
+	}
+	task.filterObjects()
+	assert.Equal(t, makeTestMappings(), task.mappings)
+	assert.Len(t, task.scraped, 1)
+
+	task = &ImportTask{
+		mappings:    makeTestMappings(),
+		scraped:     scraped,
+		ObjectTypes: []models.ImportObjectType{models.ImportObjectTypeTag, models.ImportObjectTypePerformer},
+	}
+	task.filterObjects()
+	assert.Len(t, task.mappings.Tags, 2)
+	assert.Len(t, task.mappings.Performers, 1)
+	assert.Len(t, task.mappings.Studios, 0)
+	assert.Len(t, task.mappings.Scenes, 0)
+	assert.Len(t, task.scraped, 0)
+
+	task = &ImportTask{
+		mappings:    makeTestMappings(),
+		scraped:     scraped,
+		ObjectTypes: []models.ImportObjectType{models.ImportObjectTypeTag, models.ImportObjectTypeScene},
+		Checksums:   []string{"tag2", "scene1", "performer1"},
+	}
+	task.filterObjects()
+	assert.Equal(t, []jsonschema.PathNameMapping{{Checksum: "tag2"}}, task.mappings.Tags)
+	assert.Len(t, task.mappings.Performers, 0)
+	assert.Len(t, task.mappings.Scenes, 1)
+	assert.Len(t, task.scraped, 0)
+}
@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+// MigrateBlobsTask moves the stored performer, studio, tag and movie images
+// into the filesystem or the database, according to the configured storage.
+// Space freed in the database is reclaimed by optimizing it.
+type MigrateBlobsTask struct {
+	Status *TaskStatus
+}
+
+func (t *MigrateBlobsTask) Start() {
+	storage := config.GetInstance().GetBlobsStorage()
+	logger.Infof("Moving images to %s storage", storage.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := sqlite.MigrateBlobs(ctx, func(done int, total int) {
+		t.Status.setProgress(done, total)
+		if t.Status.IsStopping() {
+			cancel()
+		}
+	})
+
+	switch {
+	case err == context.Canceled:
+		logger.Info("Stopping due to user request")
+	case err != nil:
+		logger.Errorf("Error moving images: %s", err.Error())
+		t.Status.setError(err)
+	default:
+		logger.Info("Finished moving images")
+		if storage == models.BlobsStorageTypeFilesystem {
+			logger.Info("Optimize the database to reclaim the space used by the moved images")
+		}
+	}
+}
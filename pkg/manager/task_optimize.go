@@ -0,0 +1,33 @@
+package manager
+
+import (
+	"sync"
+
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// OptimizeTask optimizes the database. See database.Optimize.
+type OptimizeTask struct {
+	status *TaskStatus
+}
+
+func (t *OptimizeTask) GetStatus() JobStatus {
+	return Optimize
+}
+
+func (t *OptimizeTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *OptimizeTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if err := database.Optimize(); err != nil {
+		logger.Errorf("error optimizing database: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	logger.Info("Finished optimizing database")
+}
@@ -0,0 +1,315 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// maxOrganizeCollisions is the number of suffixed names tried for a file
+// whose organized path is taken by another file.
+const maxOrganizeCollisions = 100
+
+// OrganizeTask renames and moves the file of each scene to the path built
+// from the template and the scene metadata, within the library path of the
+// file. The external caption files of the scene are moved with it. A
+// number is appended to the name of files whose path is taken.
+type OrganizeTask struct {
+	txnManager models.TransactionManager
+	status     *TaskStatus
+
+	// SceneIDs are the scenes to organize. All scenes are organized if empty.
+	SceneIDs []int
+
+	// Template is the path of the files relative to the library path,
+	// without the extension. See scene.OrganizePath.
+	Template string
+
+	// DryRun logs where the files would be moved without moving them.
+	DryRun bool
+}
+
+func CreateOrganizeTask(input models.OrganizeFilesInput) (*OrganizeTask, error) {
+	sceneIDs, err := utils.StringSliceToIntSlice(input.SceneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scene.ValidateOrganizeTemplate(input.Template); err != nil {
+		return nil, err
+	}
+
+	return &OrganizeTask{
+		txnManager: GetInstance().TxnManager,
+		SceneIDs:   sceneIDs,
+		Template:   input.Template,
+		DryRun:     input.DryRun != nil && *input.DryRun,
+	}, nil
+}
+
+func (t *OrganizeTask) GetStatus() JobStatus {
+	return Organize
+}
+
+func (t *OrganizeTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *OrganizeTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	var scenes []*models.Scene
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		if len(t.SceneIDs) > 0 {
+			scenes, err = r.Scene().FindMany(t.SceneIDs)
+		} else {
+			scenes, err = r.Scene().All()
+		}
+		return err
+	}); err != nil {
+		logger.Errorf("error getting scenes to organize: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	if t.DryRun {
+		logger.Info("Performing organize dry run. No files will be moved")
+	}
+
+	t.status.setObjectsTotal(len(scenes))
+
+	moved := 0
+	for i, s := range scenes {
+		if ctx.Err() != nil {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		t.status.setProgress(i, len(scenes))
+		ok, err := t.organizeScene(ctx, s)
+		if err != nil {
+			logger.Errorf("[organize] <%s> error moving file: %s", s.Path, err.Error())
+		} else if ok {
+			moved++
+		}
+		t.status.objectDone()
+	}
+
+	verb := "Moved"
+	if t.DryRun {
+		verb = "Would move"
+	}
+	logger.Infof("Organize complete. %s %d of %d scene files", verb, moved, len(scenes))
+}
+
+// organizeScene moves the scene file to its organized path. Returns true if
+// the file was moved, or would be moved by a dry run.
+func (t *OrganizeTask) organizeScene(ctx context.Context, s *models.Scene) (bool, error) {
+	if exists, _ := utils.FileExists(s.Path); !exists {
+		logger.Warnf("[organize] <%s> skipping missing scene file", s.Path)
+		return false, nil
+	}
+
+	stash := getStashFromPath(s.Path)
+	if stash == nil {
+		logger.Warnf("[organize] <%s> skipping file outside of the library paths", s.Path)
+		return false, nil
+	}
+
+	var newPath string
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		fields, err := t.getFields(r, s)
+		if err != nil {
+			return err
+		}
+
+		rel, err := scene.OrganizePath(t.Template, *fields)
+		if err != nil {
+			return err
+		}
+
+		newPath, err = t.uniquePath(r.Scene(), s.Path, filepath.Join(stash.Path, rel), filepath.Ext(s.Path))
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	if newPath == s.Path {
+		return false, nil
+	}
+
+	if t.DryRun {
+		logger.Infof("[organize] <%s> would be moved to %s", s.Path, newPath)
+		return true, nil
+	}
+
+	captions, err := scene.GetExternalCaptions(s.Path)
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return false, err
+	}
+
+	// the file is moved once the path is updated, and moved back if the
+	// transaction fails to commit
+	moved := false
+	if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
+		if _, err := r.Scene().Update(models.ScenePartial{
+			ID:        s.ID,
+			Path:      &newPath,
+			UpdatedAt: &models.SQLiteTimestamp{Timestamp: time.Now()},
+		}); err != nil {
+			return err
+		}
+
+		if err := os.Rename(s.Path, newPath); err != nil {
+			return err
+		}
+		moved = true
+
+		return nil
+	}); err != nil {
+		if moved {
+			if renameErr := os.Rename(newPath, s.Path); renameErr != nil {
+				logger.Errorf("[organize] <%s> error moving file back from %s: %s", s.Path, newPath, renameErr.Error())
+			}
+		}
+		return false, err
+	}
+
+	logger.Infof("[organize] <%s> moved to %s", s.Path, newPath)
+
+	t.moveCaptions(ctx, s, captions, newPath)
+
+	// remove the old directory if it is now empty. Fails if it isn't.
+	if oldDir := filepath.Dir(s.Path); oldDir != filepath.Clean(stash.Path) {
+		_ = os.Remove(oldDir)
+	}
+
+	return true, nil
+}
+
+func (t *OrganizeTask) getFields(r models.ReaderRepository, s *models.Scene) (*scene.OrganizeFields, error) {
+	studio, err := scene.GetStudioName(r.Studio(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	performers, err := r.Performer().FindBySceneID(s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := s.Title.String
+	if strings.TrimSpace(title) == "" {
+		title = utils.GetNameFromPath(s.Path, true)
+	}
+
+	return &scene.OrganizeFields{
+		Title:      title,
+		Studio:     studio,
+		Date:       s.Date.String,
+		Performers: performer.GetNames(performers),
+	}, nil
+}
+
+// uniquePath returns base followed by ext, or with a number appended to
+// base if the path is taken by another file or scene.
+func (t *OrganizeTask) uniquePath(qb models.SceneReader, oldPath string, base string, ext string) (string, error) {
+	for i := 0; i <= maxOrganizeCollisions; i++ {
+		p := base + ext
+		if i > 0 {
+			p = fmt.Sprintf("%s (%d)%s", base, i, ext)
+		}
+
+		if p == oldPath {
+			return p, nil
+		}
+
+		taken, err := isPathTaken(qb, oldPath, p)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return p, nil
+		}
+	}
+
+	return "", fmt.Errorf("more than %d files are organized to %s", maxOrganizeCollisions, base+ext)
+}
+
+// isPathTaken returns true if a file other than the file at oldPath exists
+// at p, or if another scene has the path p.
+func isPathTaken(qb models.SceneReader, oldPath string, p string) (bool, error) {
+	if info, err := os.Stat(p); err == nil {
+		// the name of the file may differ only in case on case-insensitive
+		// filesystems
+		oldInfo, err := os.Stat(oldPath)
+		if err != nil || !os.SameFile(info, oldInfo) {
+			return true, nil
+		}
+	}
+
+	existing, err := qb.FindByPath(p)
+	if err != nil {
+		return false, err
+	}
+
+	return existing != nil, nil
+}
+
+// moveCaptions moves the external caption files of the scene to the new path
+// of the scene file, and updates the captions of the scene.
+func (t *OrganizeTask) moveCaptions(ctx context.Context, s *models.Scene, captions []*models.SceneCaption, newPath string) {
+	if len(captions) == 0 {
+		return
+	}
+
+	oldBase := strings.TrimSuffix(filepath.Base(s.Path), filepath.Ext(s.Path))
+	newBase := strings.TrimSuffix(newPath, filepath.Ext(newPath))
+	for _, c := range captions {
+		suffix := strings.TrimPrefix(filepath.Base(c.Path), oldBase)
+		if err := os.Rename(c.Path, newBase+suffix); err != nil {
+			logger.Warnf("[organize] <%s> error moving caption file %s: %s", newPath, c.Path, err.Error())
+		}
+	}
+
+	external, err := scene.GetExternalCaptions(newPath)
+	if err != nil {
+		logger.Warnf("[organize] <%s> error reading caption files: %s", newPath, err.Error())
+		return
+	}
+
+	if err := t.txnManager.WithTxn(ctx, func(r models.Repository) error {
+		qb := r.Scene()
+		existing, err := qb.GetCaptions(s.ID)
+		if err != nil {
+			return err
+		}
+
+		merged := scene.MergeCaptions(existing, external)
+		if !scene.CaptionsChanged(existing, merged) {
+			return nil
+		}
+
+		return qb.UpdateCaptions(s.ID, merged)
+	}); err != nil {
+		logger.Warnf("[organize] <%s> error updating captions: %s", newPath, err.Error())
+	}
+}
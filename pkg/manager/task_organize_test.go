@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizeUniquePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-organize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldPath := filepath.Join(dir, "old.mp4")
+	takenPath := filepath.Join(dir, "new.mp4")
+	for _, fn := range []string{oldPath, takenPath} {
+		if err := ioutil.WriteFile(fn, []byte(fn), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := filepath.Join(dir, "new")
+	missingScenePath := base + " (1).mp4"
+	freePath := base + " (2).mp4"
+
+	mockSceneReader := &mocks.SceneReaderWriter{}
+	mockSceneReader.On("FindByPath", missingScenePath).Return(&models.Scene{ID: 2}, nil).Once()
+	mockSceneReader.On("FindByPath", freePath).Return(nil, nil).Once()
+
+	task := &OrganizeTask{}
+
+	// the file is taken, then a scene with a missing file
+	p, err := task.uniquePath(mockSceneReader, oldPath, base, ".mp4")
+	assert.Nil(t, err)
+	assert.Equal(t, freePath, p)
+
+	// files already at their path are not moved
+	p, err = task.uniquePath(mockSceneReader, oldPath, filepath.Join(dir, "old"), ".mp4")
+	assert.Nil(t, err)
+	assert.Equal(t, oldPath, p)
+
+	mockSceneReader.AssertExpectations(t)
+}
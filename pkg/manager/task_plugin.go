@@ -8,16 +8,8 @@ import (
 	"github.com/stashapp/stash/pkg/plugin/common"
 )
 
-func (s *singleton) RunPluginTask(pluginID string, taskName string, args []*models.PluginArgInput, serverConnection common.StashServerConnection) {
-	if s.Status.Status != Idle {
-		return
-	}
-	s.Status.SetStatus(PluginOperation)
-	s.Status.indefiniteProgress()
-
-	go func() {
-		defer s.returnToIdleState()
-
+func (s *singleton) RunPluginTask(pluginID string, taskName string, args []*models.PluginArgInput, serverConnection common.StashServerConnection) *Job {
+	return s.queueJob(PluginOperation, models.JobPriorityNormal, func(status *TaskStatus) {
 		progress := make(chan float64)
 		task, err := s.PluginCache.CreateTask(pluginID, taskName, serverConnection, args, progress)
 		if err != nil {
@@ -57,9 +49,9 @@ func (s *singleton) RunPluginTask(pluginID string, taskName string, args []*mode
 			case <-done:
 				return
 			case p := <-progress:
-				s.Status.setProgressPercent(p)
+				status.setProgressPercent(p)
 			case <-stopPoller:
-				if s.Status.stopping {
+				if status.IsStopping() {
 					if err := task.Stop(); err != nil {
 						logger.Errorf("Error stopping plugin operation: %s", err.Error())
 					}
@@ -67,5 +59,5 @@ func (s *singleton) RunPluginTask(pluginID string, taskName string, args []*mode
 				}
 			}
 		}
-	}()
+	})
 }
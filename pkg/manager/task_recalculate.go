@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// recalculateStep rebuilds the counts of a single table.
+// It returns the number of objects that were updated.
+type recalculateStep struct {
+	table string
+	fn    func(r models.Repository) (int, error)
+}
+
+// RecalculateTask rebuilds the image counts of galleries and the scene
+// counts of performers and studios after manual changes to the database or
+// a failed import. The counts are calculated from the joins between objects
+// when queried, so they are rebuilt by removing the joins to objects which
+// no longer exist.
+type RecalculateTask struct {
+	TxnManager models.TransactionManager
+	Status     *TaskStatus
+}
+
+func (t *RecalculateTask) steps() []recalculateStep {
+	return []recalculateStep{
+		{"galleries", func(r models.Repository) (int, error) {
+			return r.Gallery().RemoveMissingImages()
+		}},
+		{"performers", func(r models.Repository) (int, error) {
+			return r.Performer().RemoveMissingScenes()
+		}},
+		{"studios", func(r models.Repository) (int, error) {
+			return r.Scene().ClearMissingStudios()
+		}},
+	}
+}
+
+// Start runs each step in its own transaction, reporting progress per table.
+func (t *RecalculateTask) Start() {
+	steps := t.steps()
+	total := len(steps)
+
+	for i, step := range steps {
+		t.Status.setProgress(i, total)
+		t.Status.setMessage("Recalculating " + step.table)
+
+		if t.Status.IsStopping() {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		var updated int
+		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+			var err error
+			updated, err = step.fn(r)
+			return err
+		}); err != nil {
+			logger.Errorf("Error recalculating %s: %s", step.table, err.Error())
+			continue
+		}
+
+		logger.Infof("Recalculated %s: %d updated", step.table, updated)
+	}
+
+	t.Status.setProgress(total, total)
+}
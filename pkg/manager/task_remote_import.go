@@ -0,0 +1,353 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/manager/paths"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// RemoteImportTask imports the tags, performers and studios of another stash
+// instance, and optionally the metadata of the remote scenes which match
+// local scenes by checksum or oshash. The remote objects are written to a
+// JSON lines file in a temporary directory, which is then imported in the
+// same way as an uploaded import file.
+//
+// The matched scenes already exist, so scene metadata is only imported if
+// DuplicateBehaviour is OVERWRITE or MERGE.
+type RemoteImportTask struct {
+	txnManager models.TransactionManager
+	status     *TaskStatus
+	remote     *remoteStash
+
+	IncludeScenes       bool
+	DuplicateBehaviour  models.ImportDuplicateEnum
+	MissingRefBehaviour models.ImportMissingRefEnum
+	Workers             int
+
+	fileNamingAlgorithm models.HashAlgorithm
+}
+
+func CreateRemoteImportTask(a models.HashAlgorithm, input models.RemoteImportInput) (*RemoteImportTask, error) {
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid stash URL: %s", input.URL)
+	}
+
+	apiKey := ""
+	if input.APIKey != nil {
+		apiKey = *input.APIKey
+	}
+
+	return &RemoteImportTask{
+		txnManager:          GetInstance().TxnManager,
+		remote:              newRemoteStash(input.URL, apiKey),
+		IncludeScenes:       input.IncludeScenes != nil && *input.IncludeScenes,
+		DuplicateBehaviour:  input.DuplicateBehaviour,
+		MissingRefBehaviour: input.MissingRefBehaviour,
+		Workers:             config.GetInstance().GetImportWorkersWithAutoDetection(),
+		fileNamingAlgorithm: a,
+	}, nil
+}
+
+func (t *RemoteImportTask) GetStatus() JobStatus {
+	return Import
+}
+
+func (t *RemoteImportTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *RemoteImportTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	baseDir, err := instance.Paths.Generated.TempDir("remote_import")
+	if err != nil {
+		logger.Errorf("error creating temporary directory for remote import: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+	defer func() {
+		if err := utils.RemoveDir(baseDir); err != nil {
+			logger.Errorf("error removing directory %s: %s", baseDir, err.Error())
+		}
+	}()
+
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	logger.Infof("Fetching objects from %s", t.remote.url)
+	if err := t.writeObjects(ctx, paths.GetJSONPaths(baseDir).ObjectsFile); err != nil {
+		logger.Errorf("error fetching objects from %s: %s", t.remote.url, err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	if ctx.Err() != nil {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	importTask := &ImportTask{
+		txnManager:          t.txnManager,
+		status:              t.status,
+		BaseDir:             baseDir,
+		DuplicateBehaviour:  t.DuplicateBehaviour,
+		MissingRefBehaviour: t.MissingRefBehaviour,
+		Workers:             t.Workers,
+		ReportFile:          instance.Paths.Generated.ImportReport,
+		fileNamingAlgorithm: t.fileNamingAlgorithm,
+	}
+
+	var importWg sync.WaitGroup
+	importWg.Add(1)
+	importTask.Start(&importWg)
+
+	logger.Info("Remote import complete")
+}
+
+// writeObjects fetches the remote objects and writes them, along with their
+// mappings, to a JSON lines file.
+func (t *RemoteImportTask) writeObjects(ctx context.Context, fn string) error {
+	w, err := jsonschema.CreateLinesFile(fn, false)
+	if err != nil {
+		return err
+	}
+
+	mappings := &jsonschema.Mappings{
+		SchemaVersion: jsonschema.SchemaVersion,
+	}
+	if err := t.fetchObjects(ctx, w, mappings); err != nil {
+		w.Close()
+		return err
+	}
+
+	if err := w.Write(jsonschema.LineTypeMappings, "", mappings); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+func (t *RemoteImportTask) fetchObjects(ctx context.Context, w *jsonschema.LinesWriter, mappings *jsonschema.Mappings) error {
+	t.status.setPhase("tags")
+	if err := t.fetchTags(ctx, w, mappings); err != nil {
+		return fmt.Errorf("error fetching tags: %s", err.Error())
+	}
+
+	t.status.setPhase("performers")
+	if err := t.fetchPerformers(ctx, w, mappings); err != nil {
+		return fmt.Errorf("error fetching performers: %s", err.Error())
+	}
+
+	t.status.setPhase("studios")
+	if err := t.fetchStudios(ctx, w, mappings); err != nil {
+		return fmt.Errorf("error fetching studios: %s", err.Error())
+	}
+
+	if t.IncludeScenes {
+		t.status.setPhase("scenes")
+		if err := t.fetchScenes(ctx, w, mappings); err != nil {
+			return fmt.Errorf("error fetching scenes: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// fetchImage returns the image of the remote object at the path, or an empty
+// string if the object has no image. Errors are logged rather than
+// returned, so that the object is imported without its image.
+func (t *RemoteImportTask) fetchImage(ctx context.Context, path string, hasImage bool) string {
+	if !hasImage || ctx.Err() != nil {
+		return ""
+	}
+
+	image, err := t.remote.getImage(ctx, path)
+	if err != nil {
+		logger.Warnf("error fetching image %s: %s", path, err.Error())
+		return ""
+	}
+
+	return image
+}
+
+func (t *RemoteImportTask) fetchTags(ctx context.Context, w *jsonschema.LinesWriter, mappings *jsonschema.Mappings) error {
+	tags, missingImages, err := t.remote.getTags(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, tag := range tags {
+		if ctx.Err() != nil {
+			return nil
+		}
+		t.status.setStepProgress("tags", i, len(tags))
+
+		tagJSON := tag.toJSON()
+		tagJSON.Image = t.fetchImage(ctx, "/tag/"+tag.ID+"/image", !missingImages[tag.ID])
+
+		checksum := utils.MD5FromString(tag.Name)
+		if err := w.Write(jsonschema.LineTypeTag, checksum, tagJSON); err != nil {
+			return err
+		}
+
+		mappings.Tags = append(mappings.Tags, jsonschema.PathNameMapping{Name: tag.Name, Checksum: checksum})
+	}
+
+	return nil
+}
+
+func (t *RemoteImportTask) fetchPerformers(ctx context.Context, w *jsonschema.LinesWriter, mappings *jsonschema.Mappings) error {
+	performers, missingImages, err := t.remote.getPerformers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, p := range performers {
+		if ctx.Err() != nil {
+			return nil
+		}
+		t.status.setStepProgress("performers", i, len(performers))
+
+		performerJSON := p.toJSON()
+		if performerJSON.Name == "" {
+			logger.Warnf("skipping remote performer %s without a name", p.ID)
+			continue
+		}
+		performerJSON.Image = t.fetchImage(ctx, "/performer/"+p.ID+"/image", !missingImages[p.ID])
+
+		checksum := utils.MD5FromString(performerJSON.Name)
+		if err := w.Write(jsonschema.LineTypePerformer, checksum, performerJSON); err != nil {
+			return err
+		}
+
+		mappings.Performers = append(mappings.Performers, jsonschema.PathNameMapping{Name: performerJSON.Name, Checksum: checksum})
+	}
+
+	return nil
+}
+
+func (t *RemoteImportTask) fetchStudios(ctx context.Context, w *jsonschema.LinesWriter, mappings *jsonschema.Mappings) error {
+	studios, missingImages, err := t.remote.getStudios(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, s := range studios {
+		if ctx.Err() != nil {
+			return nil
+		}
+		t.status.setStepProgress("studios", i, len(studios))
+
+		studioJSON := s.toJSON()
+		studioJSON.Image = t.fetchImage(ctx, "/studio/"+s.ID+"/image", !missingImages[s.ID])
+
+		checksum := utils.MD5FromString(s.Name)
+		if err := w.Write(jsonschema.LineTypeStudio, checksum, studioJSON); err != nil {
+			return err
+		}
+
+		mappings.Studios = append(mappings.Studios, jsonschema.PathNameMapping{Name: s.Name, Checksum: checksum})
+	}
+
+	return nil
+}
+
+// fetchScenes writes the local scenes which match a remote scene, with the
+// metadata of the remote scene merged in.
+func (t *RemoteImportTask) fetchScenes(ctx context.Context, w *jsonschema.LinesWriter, mappings *jsonschema.Mappings) error {
+	var scenes []*models.Scene
+	if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+		var err error
+		scenes, err = r.Scene().All()
+		return err
+	}); err != nil {
+		return err
+	}
+
+	matched := 0
+	for i, s := range scenes {
+		if ctx.Err() != nil {
+			return nil
+		}
+		t.status.setStepProgress("scenes", i, len(scenes))
+
+		sceneHash := s.GetHash(t.fileNamingAlgorithm)
+		if sceneHash == "" {
+			continue
+		}
+
+		remoteScene, err := t.remote.findScene(ctx, s.Checksum.String, s.OSHash.String)
+		if err != nil {
+			return err
+		}
+		if remoteScene == nil {
+			continue
+		}
+
+		var sceneJSON *jsonschema.Scene
+		if err := t.txnManager.WithReadTxn(ctx, func(r models.ReaderRepository) error {
+			var err error
+			sceneJSON, err = localSceneJSON(r, s)
+			return err
+		}); err != nil {
+			return fmt.Errorf("error getting JSON of scene %s: %s", s.Path, err.Error())
+		}
+
+		remoteScene.mergeInto(sceneJSON)
+
+		if err := w.Write(jsonschema.LineTypeScene, sceneHash, sceneJSON); err != nil {
+			return err
+		}
+
+		mappings.Scenes = append(mappings.Scenes, jsonschema.PathNameMapping{Path: s.Path, Checksum: sceneHash})
+		matched++
+	}
+
+	logger.Infof("Matched %d of %d scenes", matched, len(scenes))
+
+	if matched > 0 && t.DuplicateBehaviour != models.ImportDuplicateEnumOverwrite && t.DuplicateBehaviour != models.ImportDuplicateEnumMerge {
+		logger.Warn("Scene metadata is only imported when overwriting or merging duplicates")
+	}
+
+	return nil
+}
+
+// localSceneJSON returns the JSON of the scene with the relationships which
+// are replaced when the scene is overwritten. Galleries, movies and markers
+// are left unchanged by the importer, so are omitted.
+func localSceneJSON(r models.ReaderRepository, s *models.Scene) (*jsonschema.Scene, error) {
+	ret, err := scene.ToBasicJSON(r.Scene(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Studio, err = scene.GetStudioName(r.Studio(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	performers, err := r.Performer().FindBySceneID(s.ID)
+	if err != nil {
+		return nil, err
+	}
+	ret.Performers = performer.GetNames(performers)
+
+	ret.Tags, err = scene.GetTagNames(r.Tag(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,198 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	remoteTestAPIKey = "secret"
+	remoteTestImage  = "image data"
+)
+
+var remoteTestResponses = map[string]string{
+	"allTags": `{"data": {
+		"allTags": [{"id": "1", "name": "tag"}, {"id": "2", "name": "imageless tag"}],
+		"findTags": {"tags": [{"id": "2"}]}
+	}}`,
+	"allPerformers": `{"data": {
+		"allPerformers": [{
+			"id": "1",
+			"name": "performer",
+			"gender": "FEMALE",
+			"favorite": true,
+			"tags": [{"name": "tag"}],
+			"rating": 4,
+			"stash_ids": [{"endpoint": "endpoint", "stash_id": "id"}]
+		}],
+		"findPerformers": {"performers": []}
+	}}`,
+	"allStudios": `{"data": {
+		"allStudios": [{"id": "1", "name": "child", "parent_studio": {"name": "parent"}}],
+		"findStudios": {"studios": [{"id": "1"}]}
+	}}`,
+}
+
+func newRemoteTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(remoteStashAPIKeyHeader) != remoteTestAPIKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/graphql" {
+			w.Write([]byte(remoteTestImage))
+			return
+		}
+
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("error decoding request: %s", err.Error())
+			return
+		}
+
+		for field, response := range remoteTestResponses {
+			if strings.Contains(body.Query, field) {
+				w.Write([]byte(response))
+				return
+			}
+		}
+
+		t.Errorf("unexpected query: %s", body.Query)
+	}))
+}
+
+func TestRemoteImportTaskWriteObjects(t *testing.T) {
+	server := newRemoteTestServer(t)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "stash-remote-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	task := &RemoteImportTask{
+		status: &TaskStatus{},
+		remote: newRemoteStash(server.URL+"/", remoteTestAPIKey),
+	}
+
+	fn := filepath.Join(dir, "objects.jsonl")
+	if err := task.writeObjects(context.Background(), fn); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := jsonschema.LoadLinesFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mappings jsonschema.Mappings
+	if err := lines.Get(jsonschema.LineTypeMappings, "", &mappings); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, mappings.Tags, 2)
+	assert.Len(t, mappings.Performers, 1)
+	assert.Len(t, mappings.Studios, 1)
+	assert.Len(t, mappings.Scenes, 0)
+
+	image := utils.GetBase64StringFromData([]byte(remoteTestImage))
+
+	var tag jsonschema.Tag
+	if assert.Nil(t, lines.Get(jsonschema.LineTypeTag, utils.MD5FromString("tag"), &tag)) {
+		assert.Equal(t, image, tag.Image)
+	}
+
+	var imagelessTag jsonschema.Tag
+	if assert.Nil(t, lines.Get(jsonschema.LineTypeTag, utils.MD5FromString("imageless tag"), &imagelessTag)) {
+		assert.Equal(t, "", imagelessTag.Image)
+	}
+
+	var performer jsonschema.Performer
+	if assert.Nil(t, lines.Get(jsonschema.LineTypePerformer, utils.MD5FromString("performer"), &performer)) {
+		assert.Equal(t, jsonschema.Performer{
+			Name:     "performer",
+			Gender:   "FEMALE",
+			Favorite: true,
+			Tags:     []string{"tag"},
+			Rating:   4,
+			Image:    image,
+			StashIDs: []models.StashID{{Endpoint: "endpoint", StashID: "id"}},
+		}, performer)
+	}
+
+	var studio jsonschema.Studio
+	if assert.Nil(t, lines.Get(jsonschema.LineTypeStudio, utils.MD5FromString("child"), &studio)) {
+		assert.Equal(t, "parent", studio.ParentStudio)
+		assert.Equal(t, "", studio.Image)
+	}
+}
+
+func TestRemoteImportTaskUnauthorized(t *testing.T) {
+	server := newRemoteTestServer(t)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "stash-remote-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	task := &RemoteImportTask{
+		status: &TaskStatus{},
+		remote: newRemoteStash(server.URL, "wrong"),
+	}
+
+	assert.NotNil(t, task.writeObjects(context.Background(), filepath.Join(dir, "objects.jsonl")))
+}
+
+func TestRemoteSceneMergeInto(t *testing.T) {
+	title := "remote title"
+	empty := ""
+
+	remote := remoteScene{
+		Title:      &title,
+		Details:    &empty,
+		Studio:     &remoteName{Name: "remote studio"},
+		Performers: []remoteName{{Name: "local"}, {Name: "remote"}},
+		Tags:       []remoteName{{Name: "remote"}},
+		StashIDs:   []remoteStashID{{Endpoint: "endpoint", StashID: "id"}},
+	}
+
+	sceneJSON := &jsonschema.Scene{
+		Title:      "local title",
+		Details:    "local details",
+		URL:        "local url",
+		Rating:     5,
+		Studio:     "local studio",
+		Performers: []string{"local"},
+	}
+
+	remote.mergeInto(sceneJSON)
+
+	assert.Equal(t, &jsonschema.Scene{
+		Title:      "remote title",
+		Details:    "local details",
+		URL:        "local url",
+		Rating:     5,
+		Studio:     "remote studio",
+		Performers: []string{"local", "remote"},
+		Tags:       []string{"remote"},
+		StashIDs:   []models.StashID{{Endpoint: "endpoint", StashID: "id"}},
+	}, sceneJSON)
+}
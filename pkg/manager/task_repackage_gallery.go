@@ -0,0 +1,338 @@
+package manager
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// RepackageGalleryTask converts a gallery between folder and zip storage.
+// The database is only updated once the new files have been written, and
+// the original files are only removed once the database has been updated.
+type RepackageGalleryTask struct {
+	TxnManager models.TransactionManager
+	Gallery    *models.Gallery
+	Format     models.GalleryStorageFormat
+}
+
+// Start starts the task.
+func (t *RepackageGalleryTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if !t.Gallery.Path.Valid {
+		logger.Warnf("Gallery %d has no path, skipping", t.Gallery.ID)
+		return
+	}
+
+	var err error
+	switch {
+	case t.Format == models.GalleryStorageFormatZip && !t.Gallery.Zip:
+		err = t.folderToZip()
+	case t.Format == models.GalleryStorageFormatFolder && t.Gallery.Zip:
+		err = t.zipToFolder()
+	default:
+		logger.Infof("Gallery %s is already stored as %s", t.Gallery.Path.String, strings.ToLower(t.Format.String()))
+		return
+	}
+
+	if err != nil {
+		logger.Errorf("Error repackaging gallery %s: %s", t.Gallery.Path.String, err.Error())
+	}
+}
+
+func (t *RepackageGalleryTask) getImages() ([]*models.Image, error) {
+	var ret []*models.Image
+	err := t.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		ret, err = r.Image().FindByGalleryID(t.Gallery.ID)
+		return err
+	})
+
+	return ret, err
+}
+
+func (t *RepackageGalleryTask) folderToZip() error {
+	folder := t.Gallery.Path.String
+	zipPath := folder + ".zip"
+
+	if exists, _ := utils.FileExists(zipPath); exists {
+		return fmt.Errorf("%s already exists", zipPath)
+	}
+
+	images, err := t.getImages()
+	if err != nil {
+		return err
+	}
+
+	// only images directly within the folder are moved into the zip
+	var toMove []*models.Image
+	for _, i := range images {
+		if !image.IsZipPath(i.Path) && filepath.Dir(i.Path) == folder {
+			toMove = append(toMove, i)
+		}
+	}
+
+	if len(toMove) == 0 {
+		return errors.New("no images in gallery folder")
+	}
+
+	logger.Infof("Writing %d images to %s", len(toMove), zipPath)
+	if err := writeGalleryZip(zipPath, toMove); err != nil {
+		os.Remove(zipPath)
+		return err
+	}
+
+	checksum, err := utils.MD5FromFilePath(zipPath)
+	if err != nil {
+		os.Remove(zipPath)
+		return err
+	}
+
+	modTime, err := getRepackagedFileModTime(zipPath)
+	if err != nil {
+		os.Remove(zipPath)
+		return err
+	}
+
+	if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		updatedTime := models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		g := *t.Gallery
+		g.Path = models.NullString(zipPath)
+		g.Zip = true
+		g.Checksum = checksum
+		g.FileModTime = modTime
+		g.UpdatedAt = updatedTime
+
+		if _, err := r.Gallery().Update(g); err != nil {
+			return err
+		}
+
+		iqb := r.Image()
+		for _, i := range toMove {
+			path := image.ZipFilename(zipPath, filepath.Base(i.Path))
+			if _, err := iqb.Update(models.ImagePartial{
+				ID:          i.ID,
+				Path:        &path,
+				FileModTime: &modTime,
+				UpdatedAt:   &updatedTime,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		os.Remove(zipPath)
+		return err
+	}
+
+	// the database now refers to the zip, so remove the original files
+	for _, i := range toMove {
+		if err := os.Remove(i.Path); err != nil {
+			logger.Warnf("Could not delete file %s: %s", i.Path, err.Error())
+		}
+	}
+
+	// remove the folder if it is now empty
+	os.Remove(folder)
+
+	logger.Infof("Repackaged gallery %s into %s", folder, zipPath)
+	return nil
+}
+
+func writeGalleryZip(zipPath string, images []*models.Image) error {
+	f, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, i := range images {
+		if err := addFileToZip(w, i.Path); err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+func addFileToZip(w *zip.Writer, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	// images are stored uncompressed, since compressing them gains little
+	// and makes reading them from the zip slower
+	dest, err := w.CreateHeader(&zip.FileHeader{
+		Name:     filepath.Base(path),
+		Method:   zip.Store,
+		Modified: info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dest, src)
+	return err
+}
+
+func (t *RepackageGalleryTask) zipToFolder() error {
+	zipPath := t.Gallery.Path.String
+	folder := strings.TrimSuffix(zipPath, filepath.Ext(zipPath))
+
+	if exists, _ := utils.FileExists(folder); exists {
+		return fmt.Errorf("%s already exists", folder)
+	}
+
+	images, err := t.getImages()
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Extracting %s to %s", zipPath, folder)
+	if err := extractGalleryZip(zipPath, folder); err != nil {
+		os.RemoveAll(folder)
+		return err
+	}
+
+	if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		updatedTime := models.SQLiteTimestamp{Timestamp: time.Now()}
+
+		// folder galleries are identified by the checksum of their path
+		g := *t.Gallery
+		g.Path = models.NullString(folder)
+		g.Zip = false
+		g.Checksum = utils.MD5FromString(folder)
+		g.FileModTime = models.NullSQLiteTimestamp{}
+		g.UpdatedAt = updatedTime
+
+		if _, err := r.Gallery().Update(g); err != nil {
+			return err
+		}
+
+		iqb := r.Image()
+		for _, i := range images {
+			zipFilename, filename := image.SplitZipFilename(i.Path)
+			if zipFilename != zipPath {
+				continue
+			}
+
+			path := extractedFilePath(folder, filename)
+			modTime, err := getRepackagedFileModTime(path)
+			if err != nil {
+				return err
+			}
+
+			if _, err := iqb.Update(models.ImagePartial{
+				ID:          i.ID,
+				Path:        &path,
+				FileModTime: &modTime,
+				UpdatedAt:   &updatedTime,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		os.RemoveAll(folder)
+		return err
+	}
+
+	if err := os.Remove(zipPath); err != nil {
+		logger.Warnf("Could not delete file %s: %s", zipPath, err.Error())
+	}
+
+	logger.Infof("Extracted gallery %s into %s", zipPath, folder)
+	return nil
+}
+
+// extractGalleryZip extracts all files of the zip file into folder, not just
+// the images, since the zip file is removed once extracted. Only macOS
+// metadata is left out.
+func extractGalleryZip(zipPath string, folder string) error {
+	if err := os.Mkdir(folder, 0755); err != nil {
+		return err
+	}
+
+	return image.WalkZip(zipPath, func(e *image.ZipEntry) error {
+		dest := extractedFilePath(folder, e.Name)
+
+		// guard against entries escaping the destination folder
+		if !strings.HasPrefix(dest, filepath.Clean(folder)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file path in zip: %s", image.PathDisplayName(e.Name))
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+
+		src, err := e.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		out, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(out, src); err != nil {
+			out.Close()
+			return err
+		}
+
+		if err := out.Close(); err != nil {
+			return err
+		}
+
+		return os.Chtimes(dest, e.Modified, e.Modified)
+	})
+}
+
+// extractedFilePath returns the path of a file extracted from a zip file into
+// folder. The files of zip files within the zip file are extracted into
+// folders named after them, without the extension.
+func extractedFilePath(folder string, name string) string {
+	parts := strings.Split(name, "\x00")
+	for i := range parts[:len(parts)-1] {
+		parts[i] = strings.TrimSuffix(parts[i], filepath.Ext(parts[i]))
+	}
+
+	return filepath.Join(folder, filepath.FromSlash(strings.Join(parts, "/")))
+}
+
+func getRepackagedFileModTime(path string) (models.NullSQLiteTimestamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.NullSQLiteTimestamp{}, err
+	}
+
+	return models.NullSQLiteTimestamp{
+		// truncate to seconds, since we don't store beyond that in the database
+		Timestamp: info.ModTime().Truncate(time.Second),
+		Valid:     true,
+	}, nil
+}
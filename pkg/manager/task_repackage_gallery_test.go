@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+func writeRepackageTestZip(t *testing.T, path string, files map[string][]byte) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractGalleryZip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-repackage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	nested := filepath.Join(dir, "nested.zip")
+	writeRepackageTestZip(t, nested, map[string][]byte{
+		"c.jpg":     []byte("c"),
+		"notes.txt": []byte("nested notes"),
+	})
+	nestedData, err := ioutil.ReadFile(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath := filepath.Join(dir, "gallery.zip")
+	writeRepackageTestZip(t, zipPath, map[string][]byte{
+		"a.jpg":                []byte("a"),
+		"sub/b.png":            []byte("b"),
+		"info.txt":             []byte("info"),
+		"sub/ComicInfo.xml":    []byte("<ComicInfo/>"),
+		"part.zip":             nestedData,
+		"__MACOSX/._a.jpg":     []byte("metadata"),
+		"sub/deeper/empty.nfo": nil,
+	})
+
+	folder := filepath.Join(dir, "gallery")
+	if err := extractGalleryZip(zipPath, folder); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"a.jpg":                "a",
+		"sub/b.png":            "b",
+		"info.txt":             "info",
+		"sub/ComicInfo.xml":    "<ComicInfo/>",
+		"part/c.jpg":           "c",
+		"part/notes.txt":       "nested notes",
+		"sub/deeper/empty.nfo": "",
+	}
+	for name, data := range expected {
+		got, err := ioutil.ReadFile(filepath.Join(folder, filepath.FromSlash(name)))
+		assert.Nil(t, err, name)
+		assert.Equal(t, data, string(got), name)
+	}
+
+	_, err = os.Stat(filepath.Join(folder, "__MACOSX"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRepackageGalleryZipToFolder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-repackage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	zipPath := filepath.Join(dir, "gallery.zip")
+	writeRepackageTestZip(t, zipPath, map[string][]byte{
+		"a.jpg":    []byte("a"),
+		"info.txt": []byte("info"),
+	})
+
+	const galleryID = 1
+	const imageID = 2
+	folder := filepath.Join(dir, "gallery")
+	extractedImage := filepath.Join(folder, "a.jpg")
+
+	txnManager := mocks.NewTransactionManager()
+	txnManager.Image().(*mocks.ImageReaderWriter).On("FindByGalleryID", galleryID).Return([]*models.Image{
+		{ID: imageID, Path: image.ZipFilename(zipPath, "a.jpg")},
+	}, nil).Once()
+	txnManager.Gallery().(*mocks.GalleryReaderWriter).On("Update", mock.MatchedBy(func(g models.Gallery) bool {
+		return g.ID == galleryID && g.Path.String == folder && !g.Zip
+	})).Return(nil, nil).Once()
+	txnManager.Image().(*mocks.ImageReaderWriter).On("Update", mock.MatchedBy(func(i models.ImagePartial) bool {
+		return i.ID == imageID && *i.Path == extractedImage
+	})).Return(nil, nil).Once()
+
+	task := &RepackageGalleryTask{
+		TxnManager: txnManager,
+		Gallery: &models.Gallery{
+			ID:   galleryID,
+			Path: sql.NullString{String: zipPath, Valid: true},
+			Zip:  true,
+		},
+		Format: models.GalleryStorageFormatFolder,
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	task.Start(&wg)
+
+	txnManager.Gallery().(*mocks.GalleryReaderWriter).AssertExpectations(t)
+	txnManager.Image().(*mocks.ImageReaderWriter).AssertExpectations(t)
+
+	// the zip is removed once all of its files are extracted
+	_, err = os.Stat(zipPath)
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := ioutil.ReadFile(filepath.Join(folder, "info.txt"))
+	assert.Nil(t, err)
+	assert.Equal(t, "info", string(data))
+}
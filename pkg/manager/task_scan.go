@@ -1,9 +1,9 @@
 package manager
 
 import (
-	"archive/zip"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -35,15 +35,29 @@ type ScanTask struct {
 	GeneratePreview      bool
 	GenerateImagePreview bool
 	zipGallery           *models.Gallery
+
+	// UseSidecarMetadata sets the metadata of new scenes from their .nfo or
+	// .json sidecar files.
+	UseSidecarMetadata         bool
+	SidecarMissingRefBehaviour models.ImportMissingRefEnum
+
+	// pools limit the concurrent jobs of each kind. Jobs are not limited
+	// if not set.
+	pools scanPools
+
+	// captionFiles caches the caption files of each folder during the scan.
+	// Folders are read for each file if not set.
+	captionFiles *captionFileCache
 }
 
 func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 	if isGallery(t.FilePath) {
 		t.scanGallery()
-	} else if isVideo(t.FilePath) {
+	} else if isVideo(t.FilePath) || isAudio(t.FilePath) {
 		s := t.scanScene()
 
-		if s != nil {
+		// audio files have no video to generate from
+		if s != nil && !s.AudioOnly {
 			iwg := sizedwaitgroup.New(2)
 
 			if t.GenerateSprite {
@@ -53,7 +67,7 @@ func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 					Overwrite:           false,
 					fileNamingAlgorithm: t.fileNamingAlgorithm,
 				}
-				go taskSprite.Start(&iwg)
+				go t.pools.generate.do(func() { taskSprite.Start(&iwg) })
 			}
 
 			if t.GeneratePhash {
@@ -63,7 +77,7 @@ func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 					fileNamingAlgorithm: t.fileNamingAlgorithm,
 					txnManager:          t.TxnManager,
 				}
-				go taskPhash.Start(&iwg)
+				go t.pools.generate.do(func() { taskPhash.Start(&iwg) })
 			}
 
 			if t.GeneratePreview {
@@ -75,6 +89,8 @@ func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 				var previewExcludeStart = config.GetPreviewExcludeStart()
 				var previewExcludeEnd = config.GetPreviewExcludeEnd()
 				var previewPresent = config.GetPreviewPreset()
+				var previewImageQuality = config.GetPreviewImageQuality()
+				var previewImageFps = config.GetPreviewImageFps()
 
 				// NOTE: the reuse of this model like this is painful.
 				previewOptions := models.GeneratePreviewOptionsInput{
@@ -83,6 +99,8 @@ func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 					PreviewExcludeStart:    &previewExcludeStart,
 					PreviewExcludeEnd:      &previewExcludeEnd,
 					PreviewPreset:          &previewPresent,
+					PreviewImageQuality:    &previewImageQuality,
+					PreviewImageFps:        &previewImageFps,
 				}
 
 				taskPreview := GeneratePreviewTask{
@@ -92,7 +110,7 @@ func (t *ScanTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 					Overwrite:           false,
 					fileNamingAlgorithm: t.fileNamingAlgorithm,
 				}
-				go taskPreview.Start(&iwg)
+				go t.pools.generate.do(func() { taskPreview.Start(&iwg) })
 			}
 
 			iwg.Wait()
@@ -213,18 +231,28 @@ func (t *ScanTask) scanGallery() {
 			g, _ = qb.FindByChecksum(checksum)
 			if g != nil {
 				exists, _ := utils.FileExists(g.Path.String)
-				if exists {
+				if !isMoved(g.Path.String, exists) {
 					logger.Infof("%s already exists.  Duplicate of %s ", t.FilePath, g.Path.String)
 				} else {
-					logger.Infof("%s already exists.  Updating path...", t.FilePath)
+					logger.Infof("%s has been moved from %s.  Updating path...", t.FilePath, g.Path.String)
 					g.Path = sql.NullString{
 						String: t.FilePath,
 						Valid:  true,
 					}
+					g.FileModTime = models.NullSQLiteTimestamp{
+						Timestamp: fileModTime,
+						Valid:     true,
+					}
+					g.UpdatedAt = models.SQLiteTimestamp{Timestamp: time.Now()}
 					g, err = qb.Update(*g)
 					if err != nil {
 						return err
 					}
+
+					// the images of the zip file are within the old path
+					if err := image.UpdateZipFilename(r.Image(), g.ID, t.FilePath); err != nil {
+						return err
+					}
 				}
 			} else {
 				currentTime := time.Now()
@@ -248,12 +276,19 @@ func (t *ScanTask) scanGallery() {
 					UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
 				}
 
-				// don't create gallery if it has no images
-				if countImagesInZip(t.FilePath) > 0 {
+				// don't create gallery if it has no images. Encrypted
+				// galleries are created so that their password can be set
+				n, err := countImagesInZip(t.FilePath)
+				if err != nil && !errors.Is(err, image.ErrZipPassword) {
+					logger.Warnf("failed to read gallery %s: %s", t.FilePath, err.Error())
+				}
+				if n > 0 || errors.Is(err, image.ErrZipPassword) {
 					// only warn when creating the gallery
-					ok, err := utils.IsZipFileUncompressed(t.FilePath)
-					if err == nil && !ok {
-						logger.Warnf("%s is using above store (0) level compression.", t.FilePath)
+					if image.IsZipArchive(t.FilePath) {
+						ok, err := utils.IsZipFileUncompressed(t.FilePath)
+						if err == nil && !ok {
+							logger.Warnf("%s is using above store (0) level compression.", t.FilePath)
+						}
 					}
 
 					logger.Infof("%s doesn't exist.  Creating new item...", t.FilePath)
@@ -402,9 +437,9 @@ func (t *ScanTask) scanScene() *models.Scene {
 
 		// if the mod time of the file is different than that of the associated
 		// scene, then recalculate the checksum and regenerate the thumbnail
-		modified := t.isFileModified(fileModTime, s.FileModTime)
+		modified := t.isFileModified(fileModTime, s.FileModTime) || !s.Size.Valid
 		config := config.GetInstance()
-		if modified || !s.Size.Valid {
+		if modified {
 			oldHash := s.GetHash(config.GetVideoFileNamingAlgorithm())
 			s, err = t.rescanScene(s, fileModTime)
 			if err != nil {
@@ -416,6 +451,14 @@ func (t *ScanTask) scanScene() *models.Scene {
 			if newHash != oldHash {
 				MigrateHash(oldHash, newHash)
 			}
+		} else {
+			// caption and funscript files may have been added or removed
+			if s.CaptionsProbed {
+				t.updateCaptions(s.ID, nil)
+			} else if err := t.probeCaptions(s.ID); err != nil {
+				return logError(err)
+			}
+			t.updateInteractive(s)
 		}
 
 		// We already have this item in the database
@@ -424,7 +467,7 @@ func (t *ScanTask) scanScene() *models.Scene {
 
 		// check for container
 		if !s.Format.Valid {
-			videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
+			videoFile, err := t.probeFile()
 			if err != nil {
 				return logError(err)
 			}
@@ -439,10 +482,32 @@ func (t *ScanTask) scanScene() *models.Scene {
 			}
 		}
 
+		// fill in missing metadata from the file, if it has any. This is only
+		// done when the file has changed, so that unchanged files are not
+		// probed on every scan, and fields cleared by the user stay empty.
+		if t.UseFileMetadata && modified && scene.IsMissingMetadata(s) {
+			videoFile, err := t.probeFile()
+			if err != nil {
+				return logError(err)
+			}
+
+			if partial := scene.FillEmptyMetadata(s, scene.EmbeddedMetadataToJSON(videoFile)); partial != nil {
+				logger.Infof("Setting metadata of %s from file metadata", t.FilePath)
+
+				if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+					var err error
+					s, err = r.Scene().Update(*partial)
+					return err
+				}); err != nil {
+					return logError(err)
+				}
+			}
+		}
+
 		// check if oshash is set
 		if !s.OSHash.Valid {
 			logger.Infof("Calculating oshash for existing file %s ...", t.FilePath)
-			oshash, err := utils.OSHashFromFilePath(t.FilePath)
+			oshash, err := t.calculateOSHash()
 			if err != nil {
 				return nil
 			}
@@ -492,7 +557,7 @@ func (t *ScanTask) scanScene() *models.Scene {
 		return nil
 	}
 
-	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
+	videoFile, err := t.probeFile()
 	if err != nil {
 		logger.Error(err.Error())
 		return nil
@@ -507,7 +572,7 @@ func (t *ScanTask) scanScene() *models.Scene {
 	var checksum string
 
 	logger.Infof("%s not found. Calculating oshash...", t.FilePath)
-	oshash, err := utils.OSHashFromFilePath(t.FilePath)
+	oshash, err := t.calculateOSHash()
 	if err != nil {
 		return logError(err)
 	}
@@ -544,20 +609,32 @@ func (t *ScanTask) scanScene() *models.Scene {
 
 	if s != nil {
 		exists, _ := utils.FileExists(s.Path)
-		if exists {
+		if !isMoved(s.Path, exists) {
 			logger.Infof("%s already exists. Duplicate of %s", t.FilePath, s.Path)
 		} else {
-			logger.Infof("%s already exists. Updating path...", t.FilePath)
+			logger.Infof("%s has been moved from %s. Updating path...", t.FilePath, s.Path)
+			interactive := scene.IsInteractive(t.FilePath)
+			captionsProbed := true
 			scenePartial := models.ScenePartial{
-				ID:   s.ID,
-				Path: &t.FilePath,
+				ID:             s.ID,
+				Path:           &t.FilePath,
+				Interactive:    &interactive,
+				CaptionsProbed: &captionsProbed,
+				FileModTime: &models.NullSQLiteTimestamp{
+					Timestamp: fileModTime,
+					Valid:     true,
+				},
+				UpdatedAt: &models.SQLiteTimestamp{Timestamp: time.Now()},
 			}
 			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				_, err := r.Scene().Update(scenePartial)
+				var err error
+				retScene, err = r.Scene().Update(scenePartial)
 				return err
 			}); err != nil {
 				return logError(err)
 			}
+
+			t.updateCaptions(s.ID, videoFile)
 		}
 	} else {
 		logger.Infof("%s doesn't exist. Creating new item...", t.FilePath)
@@ -580,22 +657,40 @@ func (t *ScanTask) scanScene() *models.Scene {
 				Timestamp: fileModTime,
 				Valid:     true,
 			},
-			CreatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
-			UpdatedAt: models.SQLiteTimestamp{Timestamp: currentTime},
+			AudioOnly:      videoFile.IsAudioOnly(),
+			Interactive:    scene.IsInteractive(t.FilePath),
+			CaptionsProbed: true,
+			CreatedAt:      models.SQLiteTimestamp{Timestamp: currentTime},
+			UpdatedAt:      models.SQLiteTimestamp{Timestamp: currentTime},
 		}
 
 		if t.UseFileMetadata {
-			newScene.Details = sql.NullString{String: videoFile.Comment, Valid: true}
-			newScene.Date = models.SQLiteDate{String: videoFile.CreationTime.Format("2006-01-02")}
+			metadata := scene.EmbeddedMetadataToJSON(videoFile)
+			newScene.Details = sql.NullString{String: metadata.Details, Valid: metadata.Details != ""}
+			newScene.Date = models.SQLiteDate{String: metadata.Date, Valid: metadata.Date != ""}
 		}
 
 		if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
 			var err error
 			retScene, err = r.Scene().Create(newScene)
-			return err
+			if err != nil {
+				return err
+			}
+
+			if t.UseFileMetadata && newScene.AudioOnly {
+				t.applyMetadata(r, retScene, scene.AudioTagsToJSON(videoFile), "audio tags")
+			}
+
+			if t.UseSidecarMetadata {
+				t.applySidecarMetadata(r, retScene)
+			}
+
+			return nil
 		}); err != nil {
 			return logError(err)
 		}
+
+		t.updateCaptions(retScene.ID, videoFile)
 	}
 
 	return retScene
@@ -606,7 +701,7 @@ func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.
 
 	// update the oshash/checksum and the modification time
 	logger.Infof("Calculating oshash for existing file %s ...", t.FilePath)
-	oshash, err := utils.OSHashFromFilePath(t.FilePath)
+	oshash, err := t.calculateOSHash()
 	if err != nil {
 		return nil, err
 	}
@@ -625,11 +720,14 @@ func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.
 	}
 
 	// regenerate the file details as well
-	videoFile, err := ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
+	videoFile, err := t.probeFile()
 	if err != nil {
 		return nil, err
 	}
 	container := ffmpeg.MatchContainer(videoFile.Container, t.FilePath)
+	audioOnly := videoFile.IsAudioOnly()
+	interactive := scene.IsInteractive(t.FilePath)
+	captionsProbed := true
 
 	currentTime := time.Now()
 	scenePartial := models.ScenePartial{
@@ -639,20 +737,25 @@ func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.
 			String: oshash,
 			Valid:  true,
 		},
-		Duration:   &sql.NullFloat64{Float64: videoFile.Duration, Valid: true},
-		VideoCodec: &sql.NullString{String: videoFile.VideoCodec, Valid: true},
-		AudioCodec: &sql.NullString{String: videoFile.AudioCodec, Valid: true},
-		Format:     &sql.NullString{String: string(container), Valid: true},
-		Width:      &sql.NullInt64{Int64: int64(videoFile.Width), Valid: true},
-		Height:     &sql.NullInt64{Int64: int64(videoFile.Height), Valid: true},
-		Framerate:  &sql.NullFloat64{Float64: videoFile.FrameRate, Valid: true},
-		Bitrate:    &sql.NullInt64{Int64: videoFile.Bitrate, Valid: true},
-		Size:       &sql.NullString{String: strconv.FormatInt(videoFile.Size, 10), Valid: true},
+		Duration:    &sql.NullFloat64{Float64: videoFile.Duration, Valid: true},
+		VideoCodec:  &sql.NullString{String: videoFile.VideoCodec, Valid: true},
+		AudioCodec:  &sql.NullString{String: videoFile.AudioCodec, Valid: true},
+		Format:      &sql.NullString{String: string(container), Valid: true},
+		Width:       &sql.NullInt64{Int64: int64(videoFile.Width), Valid: true},
+		Height:      &sql.NullInt64{Int64: int64(videoFile.Height), Valid: true},
+		Framerate:   &sql.NullFloat64{Float64: videoFile.FrameRate, Valid: true},
+		Bitrate:     &sql.NullInt64{Int64: videoFile.Bitrate, Valid: true},
+		Size:        &sql.NullString{String: strconv.FormatInt(videoFile.Size, 10), Valid: true},
+		AudioOnly:   &audioOnly,
+		Interactive: &interactive,
 		FileModTime: &models.NullSQLiteTimestamp{
 			Timestamp: fileModTime,
 			Valid:     true,
 		},
-		UpdatedAt: &models.SQLiteTimestamp{Timestamp: currentTime},
+		// the file has changed since it was last checked
+		DecodeError:    &sql.NullString{},
+		CaptionsProbed: &captionsProbed,
+		UpdatedAt:      &models.SQLiteTimestamp{Timestamp: currentTime},
 	}
 
 	var ret *models.Scene
@@ -665,17 +768,115 @@ func (t *ScanTask) rescanScene(s *models.Scene, fileModTime time.Time) (*models.
 		return nil, err
 	}
 
+	t.updateCaptions(ret.ID, videoFile)
+
 	// leave the generated files as is - the scene file may have been moved
 	// elsewhere
 
 	return ret, nil
 }
+
+// probeCaptions sets the embedded and external captions of a scene whose
+// file has not been probed for embedded captions yet, and marks it as probed
+// so that later scans only check the caption files next to it.
+func (t *ScanTask) probeCaptions(sceneID int) error {
+	videoFile, err := t.probeFile()
+	if err != nil {
+		return err
+	}
+
+	t.updateCaptions(sceneID, videoFile)
+
+	captionsProbed := true
+	return t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		_, err := r.Scene().Update(models.ScenePartial{
+			ID:             sceneID,
+			CaptionsProbed: &captionsProbed,
+		})
+		return err
+	})
+}
+
+// updateCaptions sets the embedded captions of the probed scene file and the
+// caption files next to it. If probeResult is nil, the existing embedded
+// captions are kept. Errors are logged, since the captions are not required
+// for the scene.
+func (t *ScanTask) updateCaptions(sceneID int, probeResult *ffmpeg.VideoFile) {
+	external, err := t.captionFiles.getExternalCaptions(t.FilePath)
+	if err != nil {
+		logger.Warnf("error reading caption files of %s: %s", t.FilePath, err.Error())
+		return
+	}
+
+	var existing []*models.SceneCaption
+	if err := t.TxnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		var err error
+		existing, err = r.Scene().GetCaptions(sceneID)
+		return err
+	}); err != nil {
+		logger.Warnf("error getting captions of %s: %s", t.FilePath, err.Error())
+		return
+	}
+
+	var captions []*models.SceneCaption
+	if probeResult != nil {
+		captions = append(scene.GetEmbeddedCaptions(probeResult), external...)
+	} else {
+		captions = scene.MergeCaptions(existing, external)
+	}
+
+	if !scene.CaptionsChanged(existing, captions) {
+		return
+	}
+
+	logger.Infof("Updating captions of %s", t.FilePath)
+	if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		return r.Scene().UpdateCaptions(sceneID, captions)
+	}); err != nil {
+		logger.Warnf("error updating captions of %s: %s", t.FilePath, err.Error())
+	}
+}
+
+// updateInteractive sets whether the scene is interactive, if its funscript
+// file has been added or removed since the scene was scanned.
+func (t *ScanTask) updateInteractive(s *models.Scene) {
+	interactive := scene.IsInteractive(t.FilePath)
+	if interactive == s.Interactive {
+		return
+	}
+
+	logger.Infof("Updating interactive flag of %s", t.FilePath)
+	if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		partial := models.ScenePartial{
+			ID:          s.ID,
+			Interactive: &interactive,
+			UpdatedAt:   &models.SQLiteTimestamp{Timestamp: time.Now()},
+		}
+
+		// the speed of a removed funscript no longer applies
+		if !interactive {
+			partial.InteractiveSpeed = &sql.NullInt64{}
+		}
+
+		_, err := r.Scene().Update(partial)
+		return err
+	}); err != nil {
+		logger.Warnf("error updating interactive flag of %s: %s", t.FilePath, err.Error())
+		return
+	}
+
+	s.Interactive = interactive
+	if !interactive {
+		s.InteractiveSpeed = sql.NullInt64{}
+	}
+}
+
 func (t *ScanTask) makeScreenshots(probeResult *ffmpeg.VideoFile, checksum string) {
 	thumbPath := instance.Paths.Scene.GetThumbnailScreenshotPath(checksum)
 	normalPath := instance.Paths.Scene.GetScreenshotPath(checksum)
 
-	thumbExists, _ := utils.FileExists(thumbPath)
-	normalExists, _ := utils.FileExists(normalPath)
+	thumbExists := instance.GeneratedStore.Exists(thumbPath)
+	normalExists := instance.GeneratedStore.Exists(normalPath)
 
 	if thumbExists && normalExists {
 		return
@@ -683,7 +884,7 @@ func (t *ScanTask) makeScreenshots(probeResult *ffmpeg.VideoFile, checksum strin
 
 	if probeResult == nil {
 		var err error
-		probeResult, err = ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
+		probeResult, err = t.probeFile()
 
 		if err != nil {
 			logger.Error(err.Error())
@@ -694,24 +895,36 @@ func (t *ScanTask) makeScreenshots(probeResult *ffmpeg.VideoFile, checksum strin
 
 	at := float64(probeResult.Duration) * 0.2
 
-	if !thumbExists {
-		logger.Debugf("Creating thumbnail for %s", t.FilePath)
-		makeScreenshot(*probeResult, thumbPath, 5, 320, at)
+	if probeResult.IsAudioOnly() {
+		// use the cover art of audio files, which is a single frame
+		if probeResult.GetArtworkStream() == nil {
+			return
+		}
+		at = 0
 	}
 
-	if !normalExists {
-		logger.Debugf("Creating screenshot for %s", t.FilePath)
-		makeScreenshot(*probeResult, normalPath, 2, probeResult.Width, at)
-	}
+	t.pools.generate.do(func() {
+		if !thumbExists {
+			logger.Debugf("Creating thumbnail for %s", t.FilePath)
+			makeScreenshot(*probeResult, thumbPath, 5, 320, at)
+		}
+
+		if !normalExists {
+			logger.Debugf("Creating screenshot for %s", t.FilePath)
+			makeScreenshot(*probeResult, normalPath, 2, probeResult.Width, at)
+		}
+
+		storeGenerated(thumbPath, normalPath)
+	})
 }
 
 func (t *ScanTask) scanZipImages(zipGallery *models.Gallery) {
-	err := walkGalleryZip(zipGallery.Path.String, func(file *zip.File) error {
+	err := walkGalleryZip(zipGallery.Path.String, func(e *image.ZipEntry) error {
 		// copy this task and change the filename
 		subTask := *t
 
 		// filepath is the zip file and the internal file name, separated by a null byte
-		subTask.FilePath = image.ZipFilename(zipGallery.Path.String, file.Name)
+		subTask.FilePath = image.ZipFilename(zipGallery.Path.String, e.Name)
 		subTask.zipGallery = zipGallery
 
 		// run the subtask and wait for it to complete
@@ -720,9 +933,17 @@ func (t *ScanTask) scanZipImages(zipGallery *models.Gallery) {
 		subTask.Start(&iwg)
 		return nil
 	})
+	if errors.Is(err, image.ErrZipPassword) {
+		addLockedZip(zipGallery.Path.String)
+		logger.Warnf("Cannot scan zip file images for %s: %s. Set its password to scan it.", zipGallery.Path.String, err.Error())
+		return
+	}
 	if err != nil {
 		logger.Warnf("failed to scan zip file images for %s: %s", zipGallery.Path.String, err.Error())
+		return
 	}
+
+	removeLockedZip(zipGallery.Path.String)
 }
 
 func (t *ScanTask) regenerateZipImages(zipGallery *models.Gallery) {
@@ -827,22 +1048,38 @@ func (t *ScanTask) scanImage() {
 
 		if i != nil {
 			exists := image.FileExists(i.Path)
-			if exists {
+			if !isMoved(i.Path, exists) {
 				logger.Infof("%s already exists.  Duplicate of %s ", image.PathDisplayName(t.FilePath), image.PathDisplayName(i.Path))
 			} else {
-				logger.Infof("%s already exists.  Updating path...", image.PathDisplayName(t.FilePath))
+				logger.Infof("%s has been moved from %s.  Updating path...", image.PathDisplayName(t.FilePath), image.PathDisplayName(i.Path))
+				oldPath := i.Path
 				imagePartial := models.ImagePartial{
 					ID:   i.ID,
 					Path: &t.FilePath,
+					FileModTime: &models.NullSQLiteTimestamp{
+						Timestamp: fileModTime,
+						Valid:     true,
+					},
+					UpdatedAt: &models.SQLiteTimestamp{Timestamp: time.Now()},
 				}
 
 				if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-					_, err := r.Image().Update(imagePartial)
-					return err
+					var err error
+					i, err = r.Image().Update(imagePartial)
+					if err != nil {
+						return err
+					}
+
+					if filepath.Dir(oldPath) == filepath.Dir(t.FilePath) {
+						return nil
+					}
+					return removeImageFromFolderGallery(r.Gallery(), i.ID, oldPath)
 				}); err != nil {
 					logger.Error(err.Error())
 					return
 				}
+
+				t.ensureFolderGallery(i)
 			}
 		} else {
 			logger.Infof("%s doesn't exist.  Creating new item...", image.PathDisplayName(t.FilePath))
@@ -883,15 +1120,8 @@ func (t *ScanTask) scanImage() {
 				logger.Error(err.Error())
 				return
 			}
-		} else if config.GetInstance().GetCreateGalleriesFromFolders() {
-			// create gallery from folder or associate with existing gallery
-			logger.Infof("Associating image %s with folder gallery", i.Path)
-			if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
-				return t.associateImageWithFolderGallery(i.ID, r.Gallery())
-			}); err != nil {
-				logger.Error(err.Error())
-				return
-			}
+		} else {
+			t.ensureFolderGallery(i)
 		}
 	}
 
@@ -928,7 +1158,16 @@ func (t *ScanTask) rescanImage(i *models.Image, fileModTime time.Time) (*models.
 			Timestamp: fileModTime,
 			Valid:     true,
 		},
-		UpdatedAt: &models.SQLiteTimestamp{Timestamp: currentTime},
+		TakenAt:     &fileDetails.TakenAt,
+		CameraMake:  &fileDetails.CameraMake,
+		CameraModel: &fileDetails.CameraModel,
+		UpdatedAt:   &models.SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	// only overwrite the coordinates if the file contains them
+	if fileDetails.Latitude.Valid && fileDetails.Longitude.Valid {
+		imagePartial.Latitude = &fileDetails.Latitude
+		imagePartial.Longitude = &fileDetails.Longitude
 	}
 
 	var ret *models.Image
@@ -940,20 +1179,45 @@ func (t *ScanTask) rescanImage(i *models.Image, fileModTime time.Time) (*models.
 		return nil, err
 	}
 
-	// remove the old thumbnail if the checksum changed - we'll regenerate it
+	// remove the old thumbnails if the checksum changed - we'll regenerate them
 	if oldChecksum != checksum {
-		err = os.Remove(GetInstance().Paths.Generated.GetThumbnailPath(oldChecksum, models.DefaultGthumbWidth)) // remove cache dir of gallery
-		if err != nil {
-			logger.Errorf("Error deleting thumbnail image: %s", err)
-		}
+		GetInstance().ThumbnailCache.Remove(oldChecksum)
 	}
 
 	return ret, nil
 }
 
+// ensureFolderGallery associates the image with the gallery of its folder,
+// creating the gallery if required, if galleries are created from folders.
+// Only called for new and moved images, so that images removed from their
+// folder gallery are not added again by later scans.
+func (t *ScanTask) ensureFolderGallery(i *models.Image) {
+	if t.zipGallery != nil || image.IsZipPath(i.Path) || !config.GetInstance().GetCreateGalleriesFromFolders() {
+		return
+	}
+
+	if err := t.TxnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		return t.associateImageWithFolderGallery(i.ID, r.Gallery())
+	}); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
 func (t *ScanTask) associateImageWithFolderGallery(imageID int, qb models.GalleryReaderWriter) error {
-	// find a gallery with the path specified
 	path := filepath.Dir(t.FilePath)
+
+	// the image may already be in the gallery
+	galleries, err := qb.FindByImageID(imageID)
+	if err != nil {
+		return err
+	}
+	for _, g := range galleries {
+		if isFolderGallery(g, path) {
+			return nil
+		}
+	}
+
+	// find a gallery with the path specified
 	g, err := qb.FindByPath(path)
 	if err != nil {
 		return err
@@ -987,40 +1251,69 @@ func (t *ScanTask) associateImageWithFolderGallery(imageID int, qb models.Galler
 	}
 
 	// associate image with gallery
+	logger.Infof("Associating image %s with folder gallery", t.FilePath)
 	err = gallery.AddImage(qb, g.ID, imageID)
 	return err
 }
 
-func (t *ScanTask) generateThumbnail(i *models.Image) {
-	thumbPath := GetInstance().Paths.Generated.GetThumbnailPath(i.Checksum, models.DefaultGthumbWidth)
-	exists, _ := utils.FileExists(thumbPath)
-	if exists {
-		return
+// isFolderGallery returns true if g is the gallery of the folder at path.
+func isFolderGallery(g *models.Gallery, path string) bool {
+	return !g.Zip && g.Path.Valid && g.Path.String == path
+}
+
+// removeImageFromFolderGallery removes the image from the gallery of the
+// folder it was in at oldPath.
+func removeImageFromFolderGallery(qb models.GalleryReaderWriter, imageID int, oldPath string) error {
+	if image.IsZipPath(oldPath) {
+		return nil
 	}
 
-	srcImage, err := image.GetSourceImage(i)
+	galleries, err := qb.FindByImageID(imageID)
 	if err != nil {
-		logger.Errorf("error reading image %s: %s", i.Path, err.Error())
-		return
+		return err
 	}
 
-	if image.ThumbnailNeeded(srcImage, models.DefaultGthumbWidth) {
-		data, err := image.GetThumbnail(srcImage, models.DefaultGthumbWidth)
-		if err != nil {
-			logger.Errorf("error getting thumbnail for image %s: %s", i.Path, err.Error())
-			return
+	for _, g := range galleries {
+		if isFolderGallery(g, filepath.Dir(oldPath)) {
+			logger.Infof("Removing image %s from folder gallery %s", oldPath, g.Path.String)
+			if err := gallery.RemoveImage(qb, g.ID, imageID); err != nil {
+				return err
+			}
 		}
+	}
 
-		err = utils.WriteFile(thumbPath, data)
-		if err != nil {
-			logger.Errorf("error writing thumbnail for image %s: %s", i.Path, err)
+	return nil
+}
+
+func (t *ScanTask) generateThumbnail(i *models.Image) {
+	t.pools.thumbnail.do(func() {
+		if err := GetInstance().ThumbnailCache.Generate(i); err != nil {
+			logger.Errorf("error generating thumbnail for image %s: %s", i.Path, err.Error())
 		}
-	}
+	})
+}
+
+func (t *ScanTask) probeFile() (videoFile *ffmpeg.VideoFile, err error) {
+	t.pools.probe.do(func() {
+		videoFile, err = ffmpeg.NewVideoFile(instance.FFProbePath, t.FilePath, t.StripFileExtension)
+	})
+	return
+}
+
+func (t *ScanTask) calculateOSHash() (oshash string, err error) {
+	t.pools.hash.do(func() {
+		oshash, err = utils.OSHashFromFilePath(t.FilePath)
+	})
+	return
 }
 
 func (t *ScanTask) calculateChecksum() (string, error) {
 	logger.Infof("Calculating checksum for %s...", t.FilePath)
-	checksum, err := utils.MD5FromFilePath(t.FilePath)
+	var checksum string
+	var err error
+	t.pools.hash.do(func() {
+		checksum, err = utils.MD5FromFilePath(t.FilePath)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -1031,7 +1324,11 @@ func (t *ScanTask) calculateChecksum() (string, error) {
 func (t *ScanTask) calculateImageChecksum() (string, error) {
 	logger.Infof("Calculating checksum for %s...", image.PathDisplayName(t.FilePath))
 	// uses image.CalculateMD5 to read files in zips
-	checksum, err := image.CalculateMD5(t.FilePath)
+	var checksum string
+	var err error
+	t.pools.hash.do(func() {
+		checksum, err = image.CalculateMD5(t.FilePath)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -1039,9 +1336,26 @@ func (t *ScanTask) calculateImageChecksum() (string, error) {
 	return checksum, nil
 }
 
+// isMoved returns true if the file of an existing item with the same hash as
+// the scanned file was moved, rather than the scanned file being a duplicate.
+// Files of removable stashes which are not available are not moved.
+func isMoved(oldPath string, exists bool) bool {
+	if exists {
+		return false
+	}
+
+	if zipFilename, _ := image.SplitZipFilename(oldPath); zipFilename != "" {
+		oldPath = zipFilename
+	}
+
+	stash := getStashFromPath(oldPath)
+	return stash == nil || !stash.Removable || isStashAvailable(stash.Path)
+}
+
 func (t *ScanTask) doesPathExist() bool {
 	config := config.GetInstance()
 	vidExt := config.GetVideoExtensions()
+	audioExt := config.GetAudioExtensions()
 	imgExt := config.GetImageExtensions()
 	gExt := config.GetGalleryExtensions()
 
@@ -1052,7 +1366,7 @@ func (t *ScanTask) doesPathExist() bool {
 			if gallery != nil {
 				ret = true
 			}
-		} else if matchExtension(t.FilePath, vidExt) {
+		} else if matchExtension(t.FilePath, vidExt) || matchExtension(t.FilePath, audioExt) {
 			s, _ := r.Scene().FindByPath(t.FilePath)
 			if s != nil {
 				ret = true
@@ -1073,10 +1387,16 @@ func (t *ScanTask) doesPathExist() bool {
 func walkFilesToScan(s *models.StashConfig, f filepath.WalkFunc) error {
 	config := config.GetInstance()
 	vidExt := config.GetVideoExtensions()
+	audioExt := config.GetAudioExtensions()
 	imgExt := config.GetImageExtensions()
 	gExt := config.GetGalleryExtensions()
-	excludeVidRegex := generateRegexps(config.GetExcludes())
-	excludeImgRegex := generateRegexps(config.GetImageExcludes())
+	excludeVidRegex := append(generateRegexps(config.GetExcludes()), generateRegexps(s.Excludes)...)
+	excludeImgRegex := append(generateRegexps(config.GetImageExcludes()), generateRegexps(s.ImageExcludes)...)
+
+	if s.Removable && !isStashAvailable(s.Path) {
+		logger.Infof("Skipping unavailable removable path %s", s.Path)
+		return nil
+	}
 
 	// don't scan zip images directly
 	if image.IsZipPath(s.Path) {
@@ -1108,7 +1428,8 @@ func walkFilesToScan(s *models.StashConfig, f filepath.WalkFunc) error {
 			return nil
 		}
 
-		if !s.ExcludeVideo && matchExtension(path, vidExt) && !matchFileRegex(path, excludeVidRegex) {
+		// audio files are scanned as scenes, so are excluded with the videos
+		if !s.ExcludeVideo && (matchExtension(path, vidExt) || matchExtension(path, audioExt)) && !matchFileRegex(path, excludeVidRegex) {
 			return f(path, info, err)
 		}
 
@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	folderGalleryID = iota + 1
+	otherGalleryID
+	zipGalleryID
+	newGalleryID
+)
+
+var (
+	galleryFolder = filepath.Join("images", "set")
+	otherFolder   = filepath.Join("images", "other")
+	folderImageID = 10
+)
+
+func folderGallery(id int, path string, zip bool) *models.Gallery {
+	return &models.Gallery{
+		ID:   id,
+		Path: models.NullString(path),
+		Zip:  zip,
+	}
+}
+
+func TestAssociateImageWithFolderGallery(t *testing.T) {
+	imagePath := filepath.Join(galleryFolder, "a.jpg")
+	task := ScanTask{FilePath: imagePath}
+
+	// already in the gallery of its folder
+	qb := &mocks.GalleryReaderWriter{}
+	qb.On("FindByImageID", folderImageID).Return([]*models.Gallery{
+		folderGallery(otherGalleryID, otherFolder, false),
+		folderGallery(folderGalleryID, galleryFolder, false),
+	}, nil).Once()
+
+	assert.Nil(t, task.associateImageWithFolderGallery(folderImageID, qb))
+	qb.AssertExpectations(t)
+
+	// gallery exists, in a zip gallery of the same name
+	qb = &mocks.GalleryReaderWriter{}
+	qb.On("FindByImageID", folderImageID).Return([]*models.Gallery{
+		folderGallery(zipGalleryID, galleryFolder, true),
+	}, nil).Once()
+	qb.On("FindByPath", galleryFolder).Return(folderGallery(folderGalleryID, galleryFolder, false), nil).Once()
+	qb.On("GetImageIDs", folderGalleryID).Return([]int{1, 2}, nil).Once()
+	qb.On("UpdateImages", folderGalleryID, []int{1, 2, folderImageID}).Return(nil).Once()
+
+	assert.Nil(t, task.associateImageWithFolderGallery(folderImageID, qb))
+	qb.AssertExpectations(t)
+
+	// gallery is created
+	qb = &mocks.GalleryReaderWriter{}
+	qb.On("FindByImageID", folderImageID).Return(nil, nil).Once()
+	qb.On("FindByPath", galleryFolder).Return(nil, nil).Once()
+	qb.On("Create", mock.MatchedBy(func(g models.Gallery) bool {
+		return g.Path.String == galleryFolder && !g.Zip && g.Title.String == "set"
+	})).Return(folderGallery(newGalleryID, galleryFolder, false), nil).Once()
+	qb.On("GetImageIDs", newGalleryID).Return(nil, nil).Once()
+	qb.On("UpdateImages", newGalleryID, []int{folderImageID}).Return(nil).Once()
+
+	assert.Nil(t, task.associateImageWithFolderGallery(folderImageID, qb))
+	qb.AssertExpectations(t)
+}
+
+func TestRemoveImageFromFolderGallery(t *testing.T) {
+	oldPath := filepath.Join(galleryFolder, "a.jpg")
+
+	qb := &mocks.GalleryReaderWriter{}
+	qb.On("FindByImageID", folderImageID).Return([]*models.Gallery{
+		folderGallery(zipGalleryID, galleryFolder, true),
+		folderGallery(otherGalleryID, otherFolder, false),
+		folderGallery(folderGalleryID, galleryFolder, false),
+		// manually created
+		{ID: newGalleryID},
+	}, nil).Once()
+	qb.On("GetImageIDs", folderGalleryID).Return([]int{1, folderImageID, 2}, nil).Once()
+	qb.On("UpdateImages", folderGalleryID, []int{1, 2}).Return(nil).Once()
+
+	assert.Nil(t, removeImageFromFolderGallery(qb, folderImageID, oldPath))
+	qb.AssertExpectations(t)
+
+	// images in zip files are not in folder galleries
+	qb = &mocks.GalleryReaderWriter{}
+	assert.Nil(t, removeImageFromFolderGallery(qb, folderImageID, image.ZipFilename(galleryFolder+".zip", "a.jpg")))
+	qb.AssertExpectations(t)
+}
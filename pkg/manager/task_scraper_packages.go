@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/packages"
+)
+
+// ScraperPackagesTask installs scraper packages from a package source, or
+// updates the installed packages to the version listed by their sources.
+// The scrapers are reloaded once the packages are installed.
+type ScraperPackagesTask struct {
+	status  *TaskStatus
+	manager *packages.Manager
+
+	// SourceURL is the index of the source to install IDs from. If empty,
+	// the installed packages with IDs, or all installed packages if IDs is
+	// empty, are updated.
+	SourceURL string
+	IDs       []string
+}
+
+// ScraperPackageManager returns the manager of the packages installed into
+// the scrapers path.
+func ScraperPackageManager() *packages.Manager {
+	return packages.NewManager(config.GetInstance().GetScrapersPath())
+}
+
+func CreateInstallScraperPackagesTask(input models.InstallScraperPackagesInput) (*ScraperPackagesTask, error) {
+	if packages.FindSource(config.GetInstance().GetScraperPackageSources(), input.SourceURL) == nil {
+		return nil, fmt.Errorf("scraper package source %s is not configured", input.SourceURL)
+	}
+
+	if len(input.Ids) == 0 {
+		return nil, fmt.Errorf("no scraper packages to install")
+	}
+
+	return &ScraperPackagesTask{
+		manager:   ScraperPackageManager(),
+		SourceURL: input.SourceURL,
+		IDs:       input.Ids,
+	}, nil
+}
+
+func CreateUpdateScraperPackagesTask(ids []string) *ScraperPackagesTask {
+	return &ScraperPackagesTask{
+		manager: ScraperPackageManager(),
+		IDs:     ids,
+	}
+}
+
+func (t *ScraperPackagesTask) GetStatus() JobStatus {
+	return InstallScrapers
+}
+
+func (t *ScraperPackagesTask) setStatus(status *TaskStatus) {
+	t.status = status
+}
+
+func (t *ScraperPackagesTask) Start(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ctx, cancel := t.status.stopContext(context.TODO())
+	defer cancel()
+
+	t.status.indefiniteProgress()
+
+	var installed []string
+	var err error
+	if t.SourceURL != "" {
+		installed, err = t.manager.Install(ctx, t.SourceURL, t.IDs)
+	} else {
+		installed, err = t.manager.Update(ctx, t.IDs)
+	}
+
+	// reload the scrapers of the packages installed before any error
+	if len(installed) > 0 {
+		if reloadErr := GetInstance().ScraperCache.ReloadScrapers(); reloadErr != nil {
+			logger.Errorf("error reloading scrapers: %s", reloadErr.Error())
+		}
+	}
+
+	if err != nil {
+		logger.Errorf("error installing scraper packages: %s", err.Error())
+		t.status.setError(err)
+		return
+	}
+
+	logger.Infof("Finished installing %d scraper packages", len(installed))
+}
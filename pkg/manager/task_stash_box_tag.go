@@ -89,7 +89,7 @@ func (t *StashBoxPerformerTagTask) stashBoxPerformerTag() {
 				partial.CareerLength = &value
 			}
 			if performer.Country != nil && !excluded["country"] {
-				value := getNullString(performer.Country)
+				value := getNullCountry(performer.Country)
 				partial.Country = &value
 			}
 			if performer.Ethnicity != nil && !excluded["ethnicity"] {
@@ -109,7 +109,7 @@ func (t *StashBoxPerformerTagTask) stashBoxPerformerTag() {
 				partial.Gender = &value
 			}
 			if performer.Height != nil && !excluded["height"] {
-				value := getNullString(performer.Height)
+				value := getNullHeight(performer.Height)
 				partial.Height = &value
 			}
 			if performer.Instagram != nil && !excluded["instagram"] {
@@ -176,14 +176,14 @@ func (t *StashBoxPerformerTagTask) stashBoxPerformerTag() {
 				Birthdate:    getDate(performer.Birthdate),
 				CareerLength: getNullString(performer.CareerLength),
 				Checksum:     utils.MD5FromString(performer.Name),
-				Country:      getNullString(performer.Country),
+				Country:      getNullCountry(performer.Country),
 				CreatedAt:    models.SQLiteTimestamp{Timestamp: currentTime},
 				Ethnicity:    getNullString(performer.Ethnicity),
 				EyeColor:     getNullString(performer.EyeColor),
 				FakeTits:     getNullString(performer.FakeTits),
 				Favorite:     sql.NullBool{Bool: false, Valid: true},
 				Gender:       getNullString(performer.Gender),
-				Height:       getNullString(performer.Height),
+				Height:       getNullHeight(performer.Height),
 				Instagram:    getNullString(performer.Instagram),
 				Measurements: getNullString(performer.Measurements),
 				Name:         sql.NullString{String: performer.Name, Valid: true},
@@ -194,6 +194,10 @@ func (t *StashBoxPerformerTagTask) stashBoxPerformerTag() {
 				UpdatedAt:    models.SQLiteTimestamp{Timestamp: currentTime},
 			}
 			err := t.txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+				if err := ValidatePerformerName(r.Performer(), performer.Name); err != nil {
+					return err
+				}
+
 				createdPerformer, err := r.Performer().Create(newPerformer)
 				if err != nil {
 					return err
@@ -243,6 +247,27 @@ func getDate(val *string) models.SQLiteDate {
 	}
 }
 
+func getNullHeight(val *string) sql.NullInt64 {
+	if val == nil {
+		return sql.NullInt64{Valid: false}
+	}
+
+	height, err := utils.ParseHeight(*val)
+	if err != nil {
+		return sql.NullInt64{Valid: false}
+	}
+
+	return sql.NullInt64{Int64: int64(height), Valid: true}
+}
+
+func getNullCountry(val *string) sql.NullString {
+	if val == nil {
+		return sql.NullString{Valid: false}
+	}
+
+	return sql.NullString{String: utils.NormaliseCountry(*val), Valid: true}
+}
+
 func getNullString(val *string) sql.NullString {
 	if val == nil {
 		return sql.NullString{Valid: false}
@@ -79,10 +79,12 @@ func (t *GenerateTranscodeTask) Start(wg *sizedwaitgroup.SizedWaitGroup) {
 		}
 	}
 
-	if err := utils.SafeMove(outputPath, instance.Paths.Scene.GetTranscodePath(sceneHash)); err != nil {
+	transcodePath := instance.Paths.Scene.GetTranscodePath(sceneHash)
+	if err := utils.SafeMove(outputPath, transcodePath); err != nil {
 		logger.Errorf("[transcode] error generating transcode: %s", err.Error())
 		return
 	}
+	storeGenerated(transcodePath)
 
 	logger.Debugf("[transcode] <%s> created transcode: %s", sceneHash, outputPath)
 	return
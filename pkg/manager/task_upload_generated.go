@@ -0,0 +1,39 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// UploadGeneratedTask uploads the generated files which are only stored
+// locally to object storage, removing the local copies unless they are
+// kept.
+type UploadGeneratedTask struct {
+	Status *TaskStatus
+	Store  *GeneratedStore
+}
+
+func (t *UploadGeneratedTask) Start() {
+	logger.Info("Uploading generated files")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := t.Store.Upload(ctx, generatedStoreDirs(), func(done int, total int) {
+		t.Status.setProgress(done, total)
+		if t.Status.IsStopping() {
+			cancel()
+		}
+	})
+
+	switch {
+	case err == context.Canceled:
+		logger.Info("Stopping due to user request")
+	case err != nil:
+		logger.Errorf("Error uploading generated files: %s", err.Error())
+		t.Status.setError(err)
+	default:
+		logger.Info("Finished uploading generated files")
+	}
+}
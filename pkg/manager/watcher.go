@@ -0,0 +1,214 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// watchDebounce is the time without changes after which a changed directory
+// is scanned. Files being copied are written to continuously, so they are
+// only scanned once complete.
+const watchDebounce = 10 * time.Second
+
+// how often to check for changed directories to scan
+const watchCheckInterval = time.Second
+
+// libraryWatcher watches the stash paths with watching enabled, and scans
+// the directories in which files are created or changed. Removed files are
+// left for the clean task.
+type libraryWatcher struct {
+	mutex   sync.Mutex
+	watcher *fsnotify.Watcher
+
+	// changed are the directories with changes which have not been scanned,
+	// with the time of the last change
+	changed map[string]time.Time
+}
+
+var watcher = &libraryWatcher{
+	changed: make(map[string]time.Time),
+}
+
+// refresh stops watching, and starts watching the stash paths with watching
+// enabled. Changes which have not been scanned are kept.
+func (w *libraryWatcher) refresh(stashes []*models.StashConfig) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.watcher != nil {
+		w.watcher.Close()
+		w.watcher = nil
+	}
+
+	var paths []string
+	for _, s := range stashes {
+		if s.Watch {
+			paths = append(paths, s.Path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return
+	}
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Errorf("[watch] error creating file watcher: %s", err.Error())
+		return
+	}
+
+	for _, p := range paths {
+		logger.Infof("[watch] watching %s for changes", p)
+		addWatchDirs(fw, p)
+	}
+
+	w.watcher = fw
+	go w.run(fw)
+}
+
+// addWatchDirs watches dir and its subdirectories, since fsnotify does not
+// watch directories recursively.
+func addWatchDirs(fw *fsnotify.Watcher, dir string) {
+	generatedPath := config.GetInstance().GetGeneratedPath()
+
+	_ = utils.SymWalk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			logger.Warnf("[watch] error reading %s: %s", path, err.Error())
+			return nil
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		if generatedPath != "" && utils.IsPathInDir(generatedPath, path) {
+			return filepath.SkipDir
+		}
+
+		if err := fw.Add(path); err != nil {
+			logger.Warnf("[watch] error watching %s: %s", path, err.Error())
+		}
+
+		return nil
+	})
+}
+
+// run handles the events of fw until it is closed.
+func (w *libraryWatcher) run(fw *fsnotify.Watcher) {
+	ticker := time.NewTicker(watchCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-fw.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(fw, event)
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return
+			}
+			logger.Warnf("[watch] %s", err.Error())
+		case now := <-ticker.C:
+			w.scanChanged(now)
+		}
+	}
+}
+
+func (w *libraryWatcher) handleEvent(fw *fsnotify.Watcher, event fsnotify.Event) {
+	// renamed files are created with the new name
+	if event.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+		return
+	}
+
+	info, err := os.Stat(event.Name)
+	if err != nil {
+		// removed before it could be read
+		return
+	}
+
+	dir := filepath.Dir(event.Name)
+	if info.IsDir() {
+		if event.Op&fsnotify.Create == 0 {
+			return
+		}
+
+		// directories may be moved into the library with files in them
+		addWatchDirs(fw, event.Name)
+		dir = event.Name
+	} else if !isVideo(event.Name) && !isAudio(event.Name) && !isImage(event.Name) && !isGallery(event.Name) {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.changed[dir] = time.Now()
+}
+
+// scanChanged scans the changed directories without changes in the last
+// watchDebounce. Directories are kept until the database is ready and no
+// scan is queued or running.
+func (w *libraryWatcher) scanChanged(now time.Time) {
+	w.mutex.Lock()
+	var dirs []string
+	for dir, t := range w.changed {
+		if now.Sub(t) >= watchDebounce {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if len(dirs) == 0 || database.Ready() != nil || GetInstance().jobs.isQueued(Scan) {
+		w.mutex.Unlock()
+		return
+	}
+
+	for _, dir := range dirs {
+		delete(w.changed, dir)
+	}
+	w.mutex.Unlock()
+
+	dirs = topLevelDirs(dirs)
+	logger.Infof("[watch] scanning changed directories: %s", strings.Join(dirs, ", "))
+	if _, err := GetInstance().Scan(models.ScanMetadataInput{Paths: dirs}); err != nil {
+		logger.Errorf("[watch] error starting scan: %s", err.Error())
+	}
+}
+
+// topLevelDirs returns the sorted directories which are not within another
+// of the directories, since directories are scanned recursively.
+func topLevelDirs(dirs []string) []string {
+	sorted := append([]string(nil), dirs...)
+	sort.Strings(sorted)
+
+	var ret []string
+	for _, dir := range sorted {
+		if !isInAnyDir(ret, dir) {
+			ret = append(ret, dir)
+		}
+	}
+
+	return ret
+}
+
+func isInAnyDir(dirs []string, path string) bool {
+	for _, dir := range dirs {
+		if utils.IsPathInDir(dir, path) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,22 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopLevelDirs(t *testing.T) {
+	a := filepath.Join("stash", "a")
+	ab := filepath.Join(a, "b")
+	aSpace := filepath.Join("stash", "a b")
+	aSpaceC := filepath.Join(aSpace, "c")
+	abc := filepath.Join(ab, "c")
+	other := filepath.Join("other", "x")
+
+	dirs := []string{abc, other, aSpaceC, ab, aSpace}
+	assert.Equal(t, []string{other, aSpace, ab}, topLevelDirs(dirs))
+	assert.Equal(t, []string{a, aSpace}, topLevelDirs([]string{aSpace, abc, a}))
+	assert.Nil(t, topLevelDirs(nil))
+}
@@ -0,0 +1,263 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+
+	// maximum number of job events waiting to be sent
+	webhookQueueSize = 100
+
+	webhookSignatureHeader = "X-Stash-Signature"
+
+	webhookAllEvents    = "*"
+	webhookJobFailed    = "job.failed"
+	webhookFinishSuffix = ".finished"
+)
+
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+}
+
+// webhookJobs are the jobs for which <job>.finished events are sent.
+var webhookJobs = []JobStatus{
+	Import,
+	Export,
+	Scan,
+	Generate,
+	Clean,
+	AutoTag,
+	Migrate,
+	PluginOperation,
+	StashBoxBatchPerformer,
+	RepackageGalleries,
+	Recalculate,
+	Backup,
+	ExportNfo,
+	CheckMedia,
+	Organize,
+	Optimize,
+	InstallScrapers,
+	MigrateBlobs,
+	UploadGenerated,
+}
+
+// webhookEvent is the payload posted to webhooks. Content is a human
+// readable description of the event, which allows chat services such as
+// Discord to display the event without further processing.
+type webhookEvent struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Content string    `json:"content"`
+	IDs     []string  `json:"ids,omitempty"`
+	Job     string    `json:"job,omitempty"`
+	Stopped bool      `json:"stopped,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+var webhookJobEvents = make(chan *webhookEvent, webhookQueueSize)
+
+// jobEventName returns the prefix of the events sent for the job, such as
+// auto_tag for the auto tag job.
+func jobEventName(job JobStatus) string {
+	s := strings.ToLower(job.String())
+	s = strings.ReplaceAll(s, "-", "")
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+func entityEventName(e *models.EntityChangedEvent) string {
+	return strings.ToLower(e.Type.String()) + "." + strings.ToLower(e.Change.String())
+}
+
+func isValidWebhookEvent(event string) bool {
+	if event == webhookAllEvents || event == webhookJobFailed {
+		return true
+	}
+
+	for _, t := range models.AllEntityType {
+		for _, c := range models.AllEntityChangeType {
+			if event == entityEventName(&models.EntityChangedEvent{Type: t, Change: c}) {
+				return true
+			}
+		}
+	}
+
+	for _, j := range webhookJobs {
+		if event == jobEventName(j)+webhookFinishSuffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateWebhooks returns an error if any of the webhooks has an invalid
+// URL or event.
+func ValidateWebhooks(webhooks []*models.WebhookInput) error {
+	for _, w := range webhooks {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL: %s", w.URL)
+		}
+
+		if len(w.Events) == 0 {
+			return fmt.Errorf("webhook %s has no events", w.URL)
+		}
+
+		for _, e := range w.Events {
+			if !isValidWebhookEvent(e) {
+				return fmt.Errorf("invalid webhook event: %s", e)
+			}
+		}
+	}
+
+	return nil
+}
+
+func webhookMatches(w *models.Webhook, event string) bool {
+	for _, e := range w.Events {
+		if e == webhookAllEvents || e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jobFinishedEvents returns the events for a finished job: the
+// <job>.finished event, followed by the job.failed event if the job failed.
+func jobFinishedEvents(job JobStatus, stopped bool, err error) []*webhookEvent {
+	name := jobEventName(job)
+
+	e := &webhookEvent{
+		Event:   name + webhookFinishSuffix,
+		Time:    time.Now(),
+		Content: fmt.Sprintf("%s finished", job),
+		Job:     name,
+		Stopped: stopped,
+	}
+	if stopped {
+		e.Content = fmt.Sprintf("%s stopped", job)
+	}
+
+	events := []*webhookEvent{e}
+	if err != nil {
+		failed := *e
+		failed.Event = webhookJobFailed
+		failed.Content = fmt.Sprintf("%s failed: %s", job, err.Error())
+		failed.Error = err.Error()
+		events = append(events, &failed)
+	}
+
+	return events
+}
+
+// queueJobFinished queues the events for a finished job. A job fails if it
+// panicked or reported an error.
+func queueJobFinished(job JobStatus, stopped bool, err error) {
+	for _, e := range jobFinishedEvents(job, stopped, err) {
+		// don't block the task waiting for webhooks
+		select {
+		case webhookJobEvents <- e:
+		default:
+			logger.Warnf("Webhook queue is full. Dropping %s event", e.Event)
+		}
+	}
+}
+
+// runWebhooks sends library and job events to the configured webhooks until
+// ctx is done. Events are sent one at a time, so library changes may be
+// dropped if the webhooks are slow to respond.
+func runWebhooks(ctx context.Context) {
+	changes := SubscribeToEntityChanges(ctx)
+
+	for {
+		select {
+		case c, ok := <-changes:
+			if !ok {
+				return
+			}
+
+			sendWebhooks(&webhookEvent{
+				Event:   entityEventName(c),
+				Time:    time.Now(),
+				Content: fmt.Sprintf("%s %s: %s", strings.ToLower(c.Type.String()), strings.ToLower(c.Change.String()), strings.Join(c.Ids, ", ")),
+				IDs:     c.Ids,
+			})
+		case e := <-webhookJobEvents:
+			sendWebhooks(e)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func sendWebhooks(e *webhookEvent) {
+	webhooks := config.GetInstance().GetWebhooks()
+
+	var body []byte
+	for _, w := range webhooks {
+		if !webhookMatches(w, e.Event) {
+			continue
+		}
+
+		if body == nil {
+			var err error
+			body, err = json.Marshal(e)
+			if err != nil {
+				logger.Errorf("Error encoding webhook event: %s", err.Error())
+				return
+			}
+		}
+
+		if err := postWebhook(w, body); err != nil {
+			logger.Warnf("Error sending %s event to webhook %s: %s", e.Event, w.URL, err.Error())
+		}
+	}
+}
+
+func postWebhook(w *models.Webhook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != nil && *w.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+webhookSignature(*w.Secret, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return nil
+}
+
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobEventName(t *testing.T) {
+	assert.Equal(t, "scan", jobEventName(Scan))
+	assert.Equal(t, "auto_tag", jobEventName(AutoTag))
+	assert.Equal(t, "stashbox_performer_batch_operation", jobEventName(StashBoxBatchPerformer))
+}
+
+func TestValidateWebhooks(t *testing.T) {
+	valid := []string{"*", "scene.created", "scene_marker.destroyed", "scan.finished", "import.finished", "job.failed"}
+	assert.Nil(t, ValidateWebhooks([]*models.WebhookInput{
+		{
+			URL:    "http://localhost:8123/api/webhook/stash",
+			Events: valid,
+		},
+	}))
+
+	invalid := []*models.WebhookInput{
+		{URL: "localhost", Events: []string{"*"}},
+		{URL: "ftp://localhost", Events: []string{"*"}},
+		{URL: "https://localhost", Events: nil},
+		{URL: "https://localhost", Events: []string{"scene.played"}},
+		{URL: "https://localhost", Events: []string{"idle.finished"}},
+	}
+	for _, w := range invalid {
+		assert.NotNil(t, ValidateWebhooks([]*models.WebhookInput{w}), w.URL, w.Events)
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	body := []byte(`{"event":"scan.finished"}`)
+	secret := "secret"
+
+	var gotBody []byte
+	var gotSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		gotSignature = r.Header.Get(webhookSignatureHeader)
+	}))
+	defer server.Close()
+
+	err := postWebhook(&models.Webhook{
+		URL:    server.URL,
+		Events: []string{"*"},
+		Secret: &secret,
+	}, body)
+
+	assert.Nil(t, err)
+	assert.Equal(t, body, gotBody)
+	assert.Equal(t, "sha256="+webhookSignature(secret, body), gotSignature)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	assert.NotNil(t, postWebhook(&models.Webhook{URL: failing.URL}, body))
+}
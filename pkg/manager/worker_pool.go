@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"github.com/stashapp/stash/pkg/manager/config"
+)
+
+// workerPool limits the number of concurrent jobs of one kind. A nil pool
+// does not limit the jobs.
+type workerPool struct {
+	workers chan struct{}
+}
+
+func newWorkerPool(workers int) *workerPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &workerPool{
+		workers: make(chan struct{}, workers),
+	}
+}
+
+// size returns the number of workers of the pool.
+func (p *workerPool) size() int {
+	if p == nil {
+		return 0
+	}
+
+	return cap(p.workers)
+}
+
+// do runs fn once a worker is free, and returns once fn returns.
+func (p *workerPool) do(fn func()) {
+	if p == nil {
+		fn()
+		return
+	}
+
+	p.workers <- struct{}{}
+	defer func() {
+		<-p.workers
+	}()
+
+	fn()
+}
+
+// scanPools are the worker pools of the kinds of jobs run while scanning
+// files, so that each kind can be throttled separately. Files are hashed,
+// then probed, then their generated files are made, so a file waiting for
+// one kind of worker doesn't hold a worker of another kind.
+type scanPools struct {
+	hash      *workerPool
+	probe     *workerPool
+	generate  *workerPool
+	thumbnail *workerPool
+}
+
+func newScanPools(c *config.Instance) scanPools {
+	return scanPools{
+		hash:      newWorkerPool(c.GetHashWorkersWithAutoDetection()),
+		probe:     newWorkerPool(c.GetProbeWorkersWithAutoDetection()),
+		generate:  newWorkerPool(c.GetGenerateWorkersWithAutoDetection()),
+		thumbnail: newWorkerPool(c.GetThumbnailWorkersWithAutoDetection()),
+	}
+}
+
+// files returns the number of files scanned concurrently, which is enough
+// to keep the workers of the largest pool busy.
+func (p scanPools) files() int {
+	ret := 1
+	for _, pool := range []*workerPool{p.hash, p.probe, p.generate, p.thumbnail} {
+		if pool.size() > ret {
+			ret = pool.size()
+		}
+	}
+
+	return ret
+}
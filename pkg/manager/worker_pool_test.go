@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPoolLimit(t *testing.T) {
+	const workers = 2
+	p := newWorkerPool(workers)
+
+	var mutex sync.Mutex
+	running := 0
+	maxRunning := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.do(func() {
+				mutex.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mutex.Unlock()
+
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+			})
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxRunning, workers)
+	assert.Equal(t, 0, len(p.workers))
+}
+
+func TestNilWorkerPool(t *testing.T) {
+	var p *workerPool
+	ran := false
+	p.do(func() {
+		ran = true
+	})
+
+	assert.True(t, ran)
+	assert.Equal(t, 0, p.size())
+}
+
+func TestScanPoolsFiles(t *testing.T) {
+	assert.Equal(t, 1, scanPools{}.files())
+
+	pools := scanPools{
+		hash:      newWorkerPool(2),
+		probe:     newWorkerPool(4),
+		generate:  newWorkerPool(1),
+		thumbnail: newWorkerPool(0),
+	}
+	assert.Equal(t, 4, pools.files())
+	assert.Equal(t, 1, pools.thumbnail.size())
+}
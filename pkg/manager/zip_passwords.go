@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// lockedZips are the zip galleries found by scans which could not be read,
+// since their password is not known.
+var lockedZips = struct {
+	sync.Mutex
+	paths map[string]bool
+}{
+	paths: make(map[string]bool),
+}
+
+func addLockedZip(path string) {
+	lockedZips.Lock()
+	defer lockedZips.Unlock()
+	lockedZips.paths[path] = true
+}
+
+func removeLockedZip(path string) {
+	lockedZips.Lock()
+	defer lockedZips.Unlock()
+	delete(lockedZips.paths, path)
+}
+
+// LockedZipFiles returns the paths of the zip galleries which could not be
+// scanned, since their password is not known.
+func LockedZipFiles() []string {
+	lockedZips.Lock()
+	defer lockedZips.Unlock()
+
+	ret := []string{}
+	for p := range lockedZips.paths {
+		ret = append(ret, p)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+// ValidateZipPasswords returns an error if any of the passwords has no path.
+func ValidateZipPasswords(passwords []*models.ZipPasswordInput) error {
+	for _, p := range passwords {
+		if p.Path == "" {
+			return fmt.Errorf("zip password has no path")
+		}
+	}
+
+	return nil
+}
+
+// setZipPassword returns the passwords with the password of the zip file at
+// path replaced, or added if it has none.
+func setZipPassword(passwords []*models.ZipPassword, path string, password string) []*models.ZipPassword {
+	for _, p := range passwords {
+		if p.Path == path {
+			p.Password = password
+			return passwords
+		}
+	}
+
+	return append(passwords, &models.ZipPassword{
+		Path:     path,
+		Password: password,
+	})
+}
+
+// SetZipPassword checks the password of the zip gallery at path, stores it in
+// the configuration and queues a scan of the gallery. Returns an error
+// wrapping image.ErrZipPassword if the password is incorrect.
+func (s *singleton) SetZipPassword(path string, password string) error {
+	if err := image.CheckZipPassword(path, password); err != nil {
+		return err
+	}
+
+	c := config.GetInstance()
+	c.Set(config.ZipPasswords, setZipPassword(c.GetZipPasswords(), path, password))
+	if err := c.Write(); err != nil {
+		return err
+	}
+
+	removeLockedZip(path)
+
+	// the images of galleries without images are scanned, even if the zip
+	// file has not changed
+	_, err := s.Scan(models.ScanMetadataInput{
+		Paths: []string{path},
+	})
+	return err
+}
@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetZipPassword(t *testing.T) {
+	passwords := setZipPassword(nil, "/galleries/a.zip", "a")
+	passwords = setZipPassword(passwords, "/galleries/b.zip", "b")
+	passwords = setZipPassword(passwords, "/galleries/a.zip", "c")
+
+	assert.Equal(t, []*models.ZipPassword{
+		{Path: "/galleries/a.zip", Password: "c"},
+		{Path: "/galleries/b.zip", Password: "b"},
+	}, passwords)
+}
+
+func TestLockedZipFiles(t *testing.T) {
+	assert.Equal(t, []string{}, LockedZipFiles())
+
+	addLockedZip("/galleries/b.zip")
+	addLockedZip("/galleries/a.zip")
+	addLockedZip("/galleries/b.zip")
+	assert.Equal(t, []string{"/galleries/a.zip", "/galleries/b.zip"}, LockedZipFiles())
+
+	removeLockedZip("/galleries/a.zip")
+	removeLockedZip("/galleries/b.zip")
+	assert.Equal(t, []string{}, LockedZipFiles())
+}
+
+func TestExtractedFilePath(t *testing.T) {
+	folder := filepath.Join("galleries", "set")
+	assert.Equal(t, filepath.Join(folder, "a.jpg"), extractedFilePath(folder, "a.jpg"))
+	assert.Equal(t, filepath.Join(folder, "dir", "a.jpg"), extractedFilePath(folder, "dir/a.jpg"))
+	assert.Equal(t, filepath.Join(folder, "dir", "part1", "a.jpg"), extractedFilePath(folder, image.ZipFilename("dir/part1.zip", "a.jpg")))
+}
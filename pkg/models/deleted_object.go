@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type DeletedObjectReader interface {
+	// FindSince returns the objects deleted after the provided time, in the
+	// order in which they were deleted.
+	FindSince(since time.Time) ([]*DeletedObject, error)
+}
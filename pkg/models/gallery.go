@@ -11,6 +11,7 @@ type GalleryReader interface {
 	Count() (int, error)
 	All() ([]*Gallery, error)
 	Query(galleryFilter *GalleryFilterType, findFilter *FindFilterType) ([]*Gallery, int, error)
+	QueryIDs(galleryFilter *GalleryFilterType, findFilter *FindFilterType) ([]int, int, error)
 	QueryCount(galleryFilter *GalleryFilterType, findFilter *FindFilterType) (int, error)
 	GetPerformerIDs(galleryID int) ([]int, error)
 	GetTagIDs(galleryID int) ([]int, error)
@@ -28,6 +29,7 @@ type GalleryWriter interface {
 	UpdateTags(galleryID int, tagIDs []int) error
 	UpdateScenes(galleryID int, sceneIDs []int) error
 	UpdateImages(galleryID int, imageIDs []int) error
+	RemoveMissingImages() (int, error)
 }
 
 type GalleryReaderWriter interface {
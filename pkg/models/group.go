@@ -0,0 +1,28 @@
+package models
+
+type GroupReader interface {
+	Find(id int) (*Group, error)
+	FindMany(ids []int) ([]*Group, error)
+	// FindChildren returns the groups directly nested in the group.
+	FindChildren(id int) ([]*Group, error)
+	FindBySceneID(sceneID int) ([]*Group, error)
+	All() ([]*Group, error)
+	Count() (int, error)
+	// GetSceneIDs returns the ids of the scenes of the group, in the order
+	// of the group.
+	GetSceneIDs(groupID int) ([]int, error)
+}
+
+type GroupWriter interface {
+	Create(newObject Group) (*Group, error)
+	Update(updatedObject Group) (*Group, error)
+	Destroy(id int) error
+	// UpdateScenes replaces the scenes of the group with sceneIDs, in that
+	// order.
+	UpdateScenes(groupID int, sceneIDs []int) error
+}
+
+type GroupReaderWriter interface {
+	GroupReader
+	GroupWriter
+}
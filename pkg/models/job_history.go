@@ -0,0 +1,17 @@
+package models
+
+type JobHistoryReader interface {
+	// Recent returns the limit most recently finished jobs, newest first.
+	Recent(limit int) ([]*JobHistoryEntry, error)
+}
+
+type JobHistoryWriter interface {
+	Create(newObject JobHistoryEntry) (*JobHistoryEntry, error)
+	// Trim removes all but the keep most recently finished jobs.
+	Trim(keep int) error
+}
+
+type JobHistoryReaderWriter interface {
+	JobHistoryReader
+	JobHistoryWriter
+}
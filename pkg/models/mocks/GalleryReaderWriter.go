@@ -397,6 +397,57 @@ func (_m *GalleryReaderWriter) QueryCount(galleryFilter *models.GalleryFilterTyp
 	return r0, r1
 }
 
+// QueryIDs provides a mock function with given fields: galleryFilter, findFilter
+func (_m *GalleryReaderWriter) QueryIDs(galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType) ([]int, int, error) {
+	ret := _m.Called(galleryFilter, findFilter)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(*models.GalleryFilterType, *models.FindFilterType) []int); ok {
+		r0 = rf(galleryFilter, findFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(*models.GalleryFilterType, *models.FindFilterType) int); ok {
+		r1 = rf(galleryFilter, findFilter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*models.GalleryFilterType, *models.FindFilterType) error); ok {
+		r2 = rf(galleryFilter, findFilter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RemoveMissingImages provides a mock function with given fields:
+func (_m *GalleryReaderWriter) RemoveMissingImages() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: updatedGallery
 func (_m *GalleryReaderWriter) Update(updatedGallery models.Gallery) (*models.Gallery, error) {
 	ret := _m.Called(updatedGallery)
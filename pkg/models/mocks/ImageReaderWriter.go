@@ -391,6 +391,36 @@ func (_m *ImageReaderWriter) QueryCount(imageFilter *models.ImageFilterType, fin
 	return r0, r1
 }
 
+// QueryIDs provides a mock function with given fields: imageFilter, findFilter
+func (_m *ImageReaderWriter) QueryIDs(imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) ([]int, int, error) {
+	ret := _m.Called(imageFilter, findFilter)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(*models.ImageFilterType, *models.FindFilterType) []int); ok {
+		r0 = rf(imageFilter, findFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(*models.ImageFilterType, *models.FindFilterType) int); ok {
+		r1 = rf(imageFilter, findFilter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*models.ImageFilterType, *models.FindFilterType) error); ok {
+		r2 = rf(imageFilter, findFilter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ResetOCounter provides a mock function with given fields: id
 func (_m *ImageReaderWriter) ResetOCounter(id int) (int, error) {
 	ret := _m.Called(id)
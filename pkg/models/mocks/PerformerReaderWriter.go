@@ -12,6 +12,20 @@ type PerformerReaderWriter struct {
 	mock.Mock
 }
 
+// AddRelationship provides a mock function with given fields: relationship
+func (_m *PerformerReaderWriter) AddRelationship(relationship models.PerformerRelationship) error {
+	ret := _m.Called(relationship)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(models.PerformerRelationship) error); ok {
+		r0 = rf(relationship)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // All provides a mock function with given fields:
 func (_m *PerformerReaderWriter) All() ([]*models.Performer, error) {
 	ret := _m.Called()
@@ -289,6 +303,29 @@ func (_m *PerformerReaderWriter) FindNamesBySceneID(sceneID int) ([]*models.Perf
 	return r0, r1
 }
 
+// FindRelated provides a mock function with given fields: performerID, types, depth
+func (_m *PerformerReaderWriter) FindRelated(performerID int, types []models.PerformerRelationshipType, depth int) ([]*models.Performer, error) {
+	ret := _m.Called(performerID, types, depth)
+
+	var r0 []*models.Performer
+	if rf, ok := ret.Get(0).(func(int, []models.PerformerRelationshipType, int) []*models.Performer); ok {
+		r0 = rf(performerID, types, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Performer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, []models.PerformerRelationshipType, int) error); ok {
+		r1 = rf(performerID, types, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetImage provides a mock function with given fields: performerID
 func (_m *PerformerReaderWriter) GetImage(performerID int) ([]byte, error) {
 	ret := _m.Called(performerID)
@@ -312,6 +349,29 @@ func (_m *PerformerReaderWriter) GetImage(performerID int) ([]byte, error) {
 	return r0, r1
 }
 
+// GetRelationships provides a mock function with given fields: performerID
+func (_m *PerformerReaderWriter) GetRelationships(performerID int) ([]*models.PerformerRelationship, error) {
+	ret := _m.Called(performerID)
+
+	var r0 []*models.PerformerRelationship
+	if rf, ok := ret.Get(0).(func(int) []*models.PerformerRelationship); ok {
+		r0 = rf(performerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PerformerRelationship)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(performerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStashIDs provides a mock function with given fields: performerID
 func (_m *PerformerReaderWriter) GetStashIDs(performerID int) ([]*models.StashID, error) {
 	ret := _m.Called(performerID)
@@ -411,6 +471,64 @@ func (_m *PerformerReaderWriter) QueryForAutoTag(words []string) ([]*models.Perf
 	return r0, r1
 }
 
+// RemoveMissingScenes provides a mock function with given fields:
+func (_m *PerformerReaderWriter) RemoveMissingScenes() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveRelationship provides a mock function with given fields: relationship
+func (_m *PerformerReaderWriter) RemoveRelationship(relationship models.PerformerRelationship) error {
+	ret := _m.Called(relationship)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(models.PerformerRelationship) error); ok {
+		r0 = rf(relationship)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SceneStats provides a mock function with given fields: limit
+func (_m *PerformerReaderWriter) SceneStats(limit int) ([]*models.StatsAggregate, error) {
+	ret := _m.Called(limit)
+
+	var r0 []*models.StatsAggregate
+	if rf, ok := ret.Get(0).(func(int) []*models.StatsAggregate); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StatsAggregate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: updatedPerformer
 func (_m *PerformerReaderWriter) Update(updatedPerformer models.PerformerPartial) (*models.Performer, error) {
 	ret := _m.Called(updatedPerformer)
@@ -499,6 +617,29 @@ func (_m *PerformerReaderWriter) UpdateTags(sceneID int, tagIDs []int) error {
 	return r0
 }
 
+// FindByStashID provides a mock function with given fields: stashID
+func (_m *PerformerReaderWriter) FindByStashID(stashID models.StashID) ([]*models.Performer, error) {
+	ret := _m.Called(stashID)
+
+	var r0 []*models.Performer
+	if rf, ok := ret.Get(0).(func(models.StashID) []*models.Performer); ok {
+		r0 = rf(stashID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Performer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.StashID) error); ok {
+		r1 = rf(stashID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByStashIDStatus provides a mock function with given fields: hasStashID, stashboxEndpoint
 func (_m *PerformerReaderWriter) FindByStashIDStatus(hasStashID bool, stashboxEndpoint string) ([]*models.Performer, error) {
 	ret := _m.Called(hasStashID, stashboxEndpoint)
@@ -35,6 +35,27 @@ func (_m *SceneReaderWriter) All() ([]*models.Scene, error) {
 	return r0, r1
 }
 
+// ClearMissingStudios provides a mock function with given fields:
+func (_m *SceneReaderWriter) ClearMissingStudios() (int, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Count provides a mock function with given fields:
 func (_m *SceneReaderWriter) Count() (int, error) {
 	ret := _m.Called()
@@ -254,6 +275,27 @@ func (_m *SceneReaderWriter) DestroyCover(sceneID int) error {
 	return r0
 }
 
+// Duration provides a mock function with given fields:
+func (_m *SceneReaderWriter) Duration() (float64, error) {
+	ret := _m.Called()
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func() float64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: id
 func (_m *SceneReaderWriter) Find(id int) (*models.Scene, error) {
 	ret := _m.Called(id)
@@ -461,6 +503,29 @@ func (_m *SceneReaderWriter) FindMany(ids []int) ([]*models.Scene, error) {
 	return r0, r1
 }
 
+// GetCaptions provides a mock function with given fields: sceneID
+func (_m *SceneReaderWriter) GetCaptions(sceneID int) ([]*models.SceneCaption, error) {
+	ret := _m.Called(sceneID)
+
+	var r0 []*models.SceneCaption
+	if rf, ok := ret.Get(0).(func(int) []*models.SceneCaption); ok {
+		r0 = rf(sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneCaption)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCover provides a mock function with given fields: sceneID
 func (_m *SceneReaderWriter) GetCover(sceneID int) ([]byte, error) {
 	ret := _m.Called(sceneID)
@@ -507,6 +572,52 @@ func (_m *SceneReaderWriter) GetGalleryIDs(sceneID int) ([]int, error) {
 	return r0, r1
 }
 
+// GetManyPerformerIDs provides a mock function with given fields: sceneIDs
+func (_m *SceneReaderWriter) GetManyPerformerIDs(sceneIDs []int) ([][]int, error) {
+	ret := _m.Called(sceneIDs)
+
+	var r0 [][]int
+	if rf, ok := ret.Get(0).(func([]int) [][]int); ok {
+		r0 = rf(sceneIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(sceneIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetManyTagIDs provides a mock function with given fields: sceneIDs
+func (_m *SceneReaderWriter) GetManyTagIDs(sceneIDs []int) ([][]int, error) {
+	ret := _m.Called(sceneIDs)
+
+	var r0 [][]int
+	if rf, ok := ret.Get(0).(func([]int) [][]int); ok {
+		r0 = rf(sceneIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = rf(sceneIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMovies provides a mock function with given fields: sceneID
 func (_m *SceneReaderWriter) GetMovies(sceneID int) ([]models.MoviesScenes, error) {
 	ret := _m.Called(sceneID)
@@ -650,6 +761,36 @@ func (_m *SceneReaderWriter) Query(sceneFilter *models.SceneFilterType, findFilt
 	return r0, r1, r2
 }
 
+// QueryIDs provides a mock function with given fields: sceneFilter, findFilter
+func (_m *SceneReaderWriter) QueryIDs(sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType) ([]int, int, error) {
+	ret := _m.Called(sceneFilter, findFilter)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(*models.SceneFilterType, *models.FindFilterType) []int); ok {
+		r0 = rf(sceneFilter, findFilter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(*models.SceneFilterType, *models.FindFilterType) int); ok {
+		r1 = rf(sceneFilter, findFilter)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*models.SceneFilterType, *models.FindFilterType) error); ok {
+		r2 = rf(sceneFilter, findFilter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ResetOCounter provides a mock function with given fields: id
 func (_m *SceneReaderWriter) ResetOCounter(id int) (int, error) {
 	ret := _m.Called(id)
@@ -715,6 +856,20 @@ func (_m *SceneReaderWriter) Update(updatedScene models.ScenePartial) (*models.S
 	return r0, r1
 }
 
+// UpdateCaptions provides a mock function with given fields: sceneID, captions
+func (_m *SceneReaderWriter) UpdateCaptions(sceneID int, captions []*models.SceneCaption) error {
+	ret := _m.Called(sceneID, captions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []*models.SceneCaption) error); ok {
+		r0 = rf(sceneID, captions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateCover provides a mock function with given fields: sceneID, cover
 func (_m *SceneReaderWriter) UpdateCover(sceneID int, cover []byte) error {
 	ret := _m.Called(sceneID, cover)
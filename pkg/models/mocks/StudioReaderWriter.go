@@ -153,6 +153,52 @@ func (_m *StudioReaderWriter) FindByName(name string, nocase bool) (*models.Stud
 	return r0, r1
 }
 
+// FindByNames provides a mock function with given fields: names, nocase
+func (_m *StudioReaderWriter) FindByNames(names []string, nocase bool) ([]*models.Studio, error) {
+	ret := _m.Called(names, nocase)
+
+	var r0 []*models.Studio
+	if rf, ok := ret.Get(0).(func([]string, bool) []*models.Studio); ok {
+		r0 = rf(names, nocase)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Studio)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, bool) error); ok {
+		r1 = rf(names, nocase)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByStashID provides a mock function with given fields: stashID
+func (_m *StudioReaderWriter) FindByStashID(stashID models.StashID) ([]*models.Studio, error) {
+	ret := _m.Called(stashID)
+
+	var r0 []*models.Studio
+	if rf, ok := ret.Get(0).(func(models.StashID) []*models.Studio); ok {
+		r0 = rf(stashID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Studio)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(models.StashID) error); ok {
+		r1 = rf(stashID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindChildren provides a mock function with given fields: id
 func (_m *StudioReaderWriter) FindChildren(id int) ([]*models.Studio, error) {
 	ret := _m.Called(id)
@@ -319,6 +365,29 @@ func (_m *StudioReaderWriter) QueryForAutoTag(words []string) ([]*models.Studio,
 	return r0, r1
 }
 
+// SceneStats provides a mock function with given fields: limit
+func (_m *StudioReaderWriter) SceneStats(limit int) ([]*models.StatsAggregate, error) {
+	ret := _m.Called(limit)
+
+	var r0 []*models.StatsAggregate
+	if rf, ok := ret.Get(0).(func(int) []*models.StatsAggregate); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StatsAggregate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: updatedStudio
 func (_m *StudioReaderWriter) Update(updatedStudio models.StudioPartial) (*models.Studio, error) {
 	ret := _m.Called(updatedStudio)
@@ -390,6 +390,52 @@ func (_m *TagReaderWriter) QueryForAutoTag(words []string) ([]*models.Tag, error
 	return r0, r1
 }
 
+// SceneStats provides a mock function with given fields: limit
+func (_m *TagReaderWriter) SceneStats(limit int) ([]*models.StatsAggregate, error) {
+	ret := _m.Called(limit)
+
+	var r0 []*models.StatsAggregate
+	if rf, ok := ret.Get(0).(func(int) []*models.StatsAggregate); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.StatsAggregate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Trending provides a mock function with given fields: days, limit
+func (_m *TagReaderWriter) Trending(days int, limit int) ([]*models.TrendingTag, error) {
+	ret := _m.Called(days, limit)
+
+	var r0 []*models.TrendingTag
+	if rf, ok := ret.Get(0).(func(int, int) []*models.TrendingTag); ok {
+		r0 = rf(days, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TrendingTag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(days, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: updatedTag
 func (_m *TagReaderWriter) Update(updatedTag models.Tag) (*models.Tag, error) {
 	ret := _m.Called(updatedTag)
@@ -426,3 +472,26 @@ func (_m *TagReaderWriter) UpdateImage(tagID int, image []byte) error {
 
 	return r0
 }
+
+// UsageTimeline provides a mock function with given fields: tagID, interval, dateField
+func (_m *TagReaderWriter) UsageTimeline(tagID int, interval models.TagUsageInterval, dateField models.TagUsageDateField) ([]*models.TagUsagePoint, error) {
+	ret := _m.Called(tagID, interval, dateField)
+
+	var r0 []*models.TagUsagePoint
+	if rf, ok := ret.Get(0).(func(int, models.TagUsageInterval, models.TagUsageDateField) []*models.TagUsagePoint); ok {
+		r0 = rf(tagID, interval, dateField)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TagUsagePoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, models.TagUsageInterval, models.TagUsageDateField) error); ok {
+		r1 = rf(tagID, interval, dateField)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -7,28 +7,38 @@ import (
 )
 
 type TransactionManager struct {
-	gallery     models.GalleryReaderWriter
-	image       models.ImageReaderWriter
-	movie       models.MovieReaderWriter
-	performer   models.PerformerReaderWriter
-	scene       models.SceneReaderWriter
-	sceneMarker models.SceneMarkerReaderWriter
-	scrapedItem models.ScrapedItemReaderWriter
-	studio      models.StudioReaderWriter
-	tag         models.TagReaderWriter
+	deletedObject models.DeletedObjectReader
+	gallery       models.GalleryReaderWriter
+	group         models.GroupReaderWriter
+	image         models.ImageReaderWriter
+	jobHistory    models.JobHistoryReaderWriter
+	movie         models.MovieReaderWriter
+	performer     models.PerformerReaderWriter
+	playlist      models.PlaylistReaderWriter
+	scene         models.SceneReaderWriter
+	sceneMarker   models.SceneMarkerReaderWriter
+	scheduledTask models.ScheduledTaskReaderWriter
+	scrapedItem   models.ScrapedItemReaderWriter
+	studio        models.StudioReaderWriter
+	tag           models.TagReaderWriter
 }
 
 func NewTransactionManager() *TransactionManager {
 	return &TransactionManager{
-		gallery:     &GalleryReaderWriter{},
-		image:       &ImageReaderWriter{},
-		movie:       &MovieReaderWriter{},
-		performer:   &PerformerReaderWriter{},
-		scene:       &SceneReaderWriter{},
-		sceneMarker: &SceneMarkerReaderWriter{},
-		scrapedItem: &ScrapedItemReaderWriter{},
-		studio:      &StudioReaderWriter{},
-		tag:         &TagReaderWriter{},
+		deletedObject: &DeletedObjectReader{},
+		gallery:       &GalleryReaderWriter{},
+		group:         &GroupReaderWriter{},
+		image:         &ImageReaderWriter{},
+		jobHistory:    &JobHistoryReaderWriter{},
+		movie:         &MovieReaderWriter{},
+		performer:     &PerformerReaderWriter{},
+		playlist:      &PlaylistReaderWriter{},
+		scene:         &SceneReaderWriter{},
+		sceneMarker:   &SceneMarkerReaderWriter{},
+		scheduledTask: &ScheduledTaskReaderWriter{},
+		scrapedItem:   &ScrapedItemReaderWriter{},
+		studio:        &StudioReaderWriter{},
+		tag:           &TagReaderWriter{},
 	}
 }
 
@@ -36,14 +46,30 @@ func (t *TransactionManager) WithTxn(ctx context.Context, fn func(r models.Repos
 	return fn(t)
 }
 
+func (t *TransactionManager) DryRun(ctx context.Context, fn func(txnManager models.TransactionManager) error) error {
+	return fn(t)
+}
+
+func (t *TransactionManager) DeletedObject() models.DeletedObjectReader {
+	return t.deletedObject
+}
+
 func (t *TransactionManager) Gallery() models.GalleryReaderWriter {
 	return t.gallery
 }
 
+func (t *TransactionManager) Group() models.GroupReaderWriter {
+	return t.group
+}
+
 func (t *TransactionManager) Image() models.ImageReaderWriter {
 	return t.image
 }
 
+func (t *TransactionManager) JobHistory() models.JobHistoryReaderWriter {
+	return t.jobHistory
+}
+
 func (t *TransactionManager) Movie() models.MovieReaderWriter {
 	return t.movie
 }
@@ -52,6 +78,10 @@ func (t *TransactionManager) Performer() models.PerformerReaderWriter {
 	return t.performer
 }
 
+func (t *TransactionManager) Playlist() models.PlaylistReaderWriter {
+	return t.playlist
+}
+
 func (t *TransactionManager) SceneMarker() models.SceneMarkerReaderWriter {
 	return t.sceneMarker
 }
@@ -60,6 +90,10 @@ func (t *TransactionManager) Scene() models.SceneReaderWriter {
 	return t.scene
 }
 
+func (t *TransactionManager) ScheduledTask() models.ScheduledTaskReaderWriter {
+	return t.scheduledTask
+}
+
 func (t *TransactionManager) ScrapedItem() models.ScrapedItemReaderWriter {
 	return t.scrapedItem
 }
@@ -80,14 +114,26 @@ func (t *TransactionManager) WithReadTxn(ctx context.Context, fn func(r models.R
 	return fn(&ReadTransaction{t: t})
 }
 
+func (r *ReadTransaction) DeletedObject() models.DeletedObjectReader {
+	return r.t.deletedObject
+}
+
 func (r *ReadTransaction) Gallery() models.GalleryReader {
 	return r.t.gallery
 }
 
+func (r *ReadTransaction) Group() models.GroupReader {
+	return r.t.group
+}
+
 func (r *ReadTransaction) Image() models.ImageReader {
 	return r.t.image
 }
 
+func (r *ReadTransaction) JobHistory() models.JobHistoryReader {
+	return r.t.jobHistory
+}
+
 func (r *ReadTransaction) Movie() models.MovieReader {
 	return r.t.movie
 }
@@ -96,6 +142,10 @@ func (r *ReadTransaction) Performer() models.PerformerReader {
 	return r.t.performer
 }
 
+func (r *ReadTransaction) Playlist() models.PlaylistReader {
+	return r.t.playlist
+}
+
 func (r *ReadTransaction) SceneMarker() models.SceneMarkerReader {
 	return r.t.sceneMarker
 }
@@ -104,6 +154,10 @@ func (r *ReadTransaction) Scene() models.SceneReader {
 	return r.t.scene
 }
 
+func (r *ReadTransaction) ScheduledTask() models.ScheduledTaskReader {
+	return r.t.scheduledTask
+}
+
 func (r *ReadTransaction) ScrapedItem() models.ScrapedItemReader {
 	return r.t.scrapedItem
 }
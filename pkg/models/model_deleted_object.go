@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// DeletedObject records the identifying fields of a deleted object, so that
+// incremental exports may report which objects have been deleted since an
+// earlier export.
+type DeletedObject struct {
+	ID        int             `db:"id" json:"id"`
+	Type      string          `db:"type" json:"type"`
+	Checksum  sql.NullString  `db:"checksum" json:"checksum"`
+	OSHash    sql.NullString  `db:"oshash" json:"oshash"`
+	Name      sql.NullString  `db:"name" json:"name"`
+	DeletedAt SQLiteTimestamp `db:"deleted_at" json:"deleted_at"`
+}
+
+type DeletedObjects []*DeletedObject
+
+func (o *DeletedObjects) Append(v interface{}) {
+	*o = append(*o, v.(*DeletedObject))
+}
+
+func (o *DeletedObjects) New() interface{} {
+	return &DeletedObject{}
+}
+
+// Deleted object types, as recorded by the database triggers.
+const (
+	DeletedObjectTypeScene     = "scene"
+	DeletedObjectTypeImage     = "image"
+	DeletedObjectTypeGallery   = "gallery"
+	DeletedObjectTypePerformer = "performer"
+	DeletedObjectTypeStudio    = "studio"
+	DeletedObjectTypeMovie     = "movie"
+	DeletedObjectTypeTag       = "tag"
+)
@@ -0,0 +1,24 @@
+package models
+
+import "database/sql"
+
+// Group is a set of scenes, such as a series, which doesn't fit the movie
+// model. Groups may be nested in a parent group.
+type Group struct {
+	ID          int             `db:"id" json:"id"`
+	Name        string          `db:"name" json:"name"`
+	Description sql.NullString  `db:"description" json:"description"`
+	ParentID    sql.NullInt64   `db:"parent_id,omitempty" json:"parent_id"`
+	CreatedAt   SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt   SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+type Groups []*Group
+
+func (g *Groups) Append(o interface{}) {
+	*g = append(*g, o.(*Group))
+}
+
+func (g *Groups) New() interface{} {
+	return &Group{}
+}
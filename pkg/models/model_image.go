@@ -19,6 +19,12 @@ type Image struct {
 	Height      sql.NullInt64       `db:"height" json:"height"`
 	StudioID    sql.NullInt64       `db:"studio_id,omitempty" json:"studio_id"`
 	FileModTime NullSQLiteTimestamp `db:"file_mod_time" json:"file_mod_time"`
+	Location    sql.NullString      `db:"location" json:"location"`
+	Latitude    sql.NullFloat64     `db:"latitude" json:"latitude"`
+	Longitude   sql.NullFloat64     `db:"longitude" json:"longitude"`
+	TakenAt     NullSQLiteTimestamp `db:"taken_at" json:"taken_at"`
+	CameraMake  sql.NullString      `db:"camera_make" json:"camera_make"`
+	CameraModel sql.NullString      `db:"camera_model" json:"camera_model"`
 	CreatedAt   SQLiteTimestamp     `db:"created_at" json:"created_at"`
 	UpdatedAt   SQLiteTimestamp     `db:"updated_at" json:"updated_at"`
 }
@@ -37,6 +43,12 @@ type ImagePartial struct {
 	Height      *sql.NullInt64       `db:"height" json:"height"`
 	StudioID    *sql.NullInt64       `db:"studio_id,omitempty" json:"studio_id"`
 	FileModTime *NullSQLiteTimestamp `db:"file_mod_time" json:"file_mod_time"`
+	Location    *sql.NullString      `db:"location" json:"location"`
+	Latitude    *sql.NullFloat64     `db:"latitude" json:"latitude"`
+	Longitude   *sql.NullFloat64     `db:"longitude" json:"longitude"`
+	TakenAt     *NullSQLiteTimestamp `db:"taken_at" json:"taken_at"`
+	CameraMake  *sql.NullString      `db:"camera_make" json:"camera_make"`
+	CameraModel *sql.NullString      `db:"camera_model" json:"camera_model"`
 	CreatedAt   *SQLiteTimestamp     `db:"created_at" json:"created_at"`
 	UpdatedAt   *SQLiteTimestamp     `db:"updated_at" json:"updated_at"`
 }
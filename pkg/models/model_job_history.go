@@ -0,0 +1,25 @@
+package models
+
+import "database/sql"
+
+// JobHistoryEntry is a job which has finished, been stopped or failed.
+type JobHistoryEntry struct {
+	ID         int                 `db:"id" json:"id"`
+	Type       string              `db:"type" json:"type"`
+	Priority   JobPriority         `db:"priority" json:"priority"`
+	State      JobState            `db:"state" json:"state"`
+	Error      sql.NullString      `db:"error" json:"error"`
+	AddedAt    SQLiteTimestamp     `db:"added_at" json:"added_at"`
+	StartedAt  NullSQLiteTimestamp `db:"started_at" json:"started_at"`
+	FinishedAt SQLiteTimestamp     `db:"finished_at" json:"finished_at"`
+}
+
+type JobHistoryEntries []*JobHistoryEntry
+
+func (e *JobHistoryEntries) Append(o interface{}) {
+	*e = append(*e, o.(*JobHistoryEntry))
+}
+
+func (e *JobHistoryEntries) New() interface{} {
+	return &JobHistoryEntry{}
+}
@@ -19,8 +19,11 @@ type Performer struct {
 	Ethnicity    sql.NullString  `db:"ethnicity" json:"ethnicity"`
 	Country      sql.NullString  `db:"country" json:"country"`
 	EyeColor     sql.NullString  `db:"eye_color" json:"eye_color"`
-	Height       sql.NullString  `db:"height" json:"height"`
+	Height       sql.NullInt64   `db:"height" json:"height"`
 	Measurements sql.NullString  `db:"measurements" json:"measurements"`
+	Bust         sql.NullInt64   `db:"bust" json:"bust"`
+	Waist        sql.NullInt64   `db:"waist" json:"waist"`
+	Hips         sql.NullInt64   `db:"hips" json:"hips"`
 	FakeTits     sql.NullString  `db:"fake_tits" json:"fake_tits"`
 	CareerLength sql.NullString  `db:"career_length" json:"career_length"`
 	Tattoos      sql.NullString  `db:"tattoos" json:"tattoos"`
@@ -34,6 +37,7 @@ type Performer struct {
 	DeathDate    SQLiteDate      `db:"death_date" json:"death_date"`
 	HairColor    sql.NullString  `db:"hair_color" json:"hair_color"`
 	Weight       sql.NullInt64   `db:"weight" json:"weight"`
+	AutoTagRegex sql.NullString  `db:"auto_tag_regex" json:"auto_tag_regex"`
 }
 
 type PerformerPartial struct {
@@ -48,8 +52,11 @@ type PerformerPartial struct {
 	Ethnicity    *sql.NullString  `db:"ethnicity" json:"ethnicity"`
 	Country      *sql.NullString  `db:"country" json:"country"`
 	EyeColor     *sql.NullString  `db:"eye_color" json:"eye_color"`
-	Height       *sql.NullString  `db:"height" json:"height"`
+	Height       *sql.NullInt64   `db:"height" json:"height"`
 	Measurements *sql.NullString  `db:"measurements" json:"measurements"`
+	Bust         *sql.NullInt64   `db:"bust" json:"bust"`
+	Waist        *sql.NullInt64   `db:"waist" json:"waist"`
+	Hips         *sql.NullInt64   `db:"hips" json:"hips"`
 	FakeTits     *sql.NullString  `db:"fake_tits" json:"fake_tits"`
 	CareerLength *sql.NullString  `db:"career_length" json:"career_length"`
 	Tattoos      *sql.NullString  `db:"tattoos" json:"tattoos"`
@@ -63,6 +70,7 @@ type PerformerPartial struct {
 	DeathDate    *SQLiteDate      `db:"death_date" json:"death_date"`
 	HairColor    *sql.NullString  `db:"hair_color" json:"hair_color"`
 	Weight       *sql.NullInt64   `db:"weight" json:"weight"`
+	AutoTagRegex *sql.NullString  `db:"auto_tag_regex" json:"auto_tag_regex"`
 }
 
 func NewPerformer(name string) *Performer {
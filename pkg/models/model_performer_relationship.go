@@ -0,0 +1,27 @@
+package models
+
+// PerformerRelationship links a performer to a related performer. For
+// types which are not symmetric, the relationship reads from the performer
+// to the related performer, e.g. the performer is an alias of the related
+// performer.
+type PerformerRelationship struct {
+	PerformerID int    `db:"performer_id" json:"performer_id"`
+	RelatedID   int    `db:"related_id" json:"related_id"`
+	Type        string `db:"type" json:"type"`
+}
+
+// IsSymmetric returns true if the relationship type reads the same in both
+// directions, such as siblings.
+func (e PerformerRelationshipType) IsSymmetric() bool {
+	return e != PerformerRelationshipTypeAliasOf
+}
+
+type PerformerRelationships []*PerformerRelationship
+
+func (p *PerformerRelationships) Append(o interface{}) {
+	*p = append(*p, o.(*PerformerRelationship))
+}
+
+func (p *PerformerRelationships) New() interface{} {
+	return &PerformerRelationship{}
+}
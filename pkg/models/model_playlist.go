@@ -0,0 +1,24 @@
+package models
+
+// Playlist is an ordered list of scenes, played one after another.
+type Playlist struct {
+	ID   int    `db:"id" json:"id"`
+	Name string `db:"name" json:"name"`
+	// Shuffle plays the scenes in a random order rather than in the order of
+	// the playlist.
+	Shuffle bool `db:"shuffle" json:"shuffle"`
+	// Repeat starts the playlist again after its last scene.
+	Repeat    bool            `db:"repeat" json:"repeat"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+type Playlists []*Playlist
+
+func (p *Playlists) Append(o interface{}) {
+	*p = append(*p, o.(*Playlist))
+}
+
+func (p *Playlists) New() interface{} {
+	return &Playlist{}
+}
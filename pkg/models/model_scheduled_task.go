@@ -0,0 +1,24 @@
+package models
+
+// ScheduledTask is a task run by the scheduler whenever its schedule, a cron
+// expression, matches.
+type ScheduledTask struct {
+	ID        int                 `db:"id" json:"id"`
+	Name      string              `db:"name" json:"name"`
+	Task      ScheduledTaskType   `db:"task" json:"task"`
+	Schedule  string              `db:"schedule" json:"schedule"`
+	Enabled   bool                `db:"enabled" json:"enabled"`
+	LastRunAt NullSQLiteTimestamp `db:"last_run_at" json:"last_run_at"`
+	CreatedAt SQLiteTimestamp     `db:"created_at" json:"created_at"`
+	UpdatedAt SQLiteTimestamp     `db:"updated_at" json:"updated_at"`
+}
+
+type ScheduledTasks []*ScheduledTask
+
+func (t *ScheduledTasks) Append(o interface{}) {
+	*t = append(*t, o.(*ScheduledTask))
+}
+
+func (t *ScheduledTasks) New() interface{} {
+	return &ScheduledTask{}
+}
@@ -17,6 +17,7 @@ type Studio struct {
 	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 	Rating    sql.NullInt64   `db:"rating" json:"rating"`
 	Details   sql.NullString  `db:"details" json:"details"`
+	Country   sql.NullString  `db:"country" json:"country"`
 }
 
 type StudioPartial struct {
@@ -29,6 +30,7 @@ type StudioPartial struct {
 	UpdatedAt *SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 	Rating    *sql.NullInt64   `db:"rating" json:"rating"`
 	Details   *sql.NullString  `db:"details" json:"details"`
+	Country   *sql.NullString  `db:"country" json:"country"`
 }
 
 var DefaultStudioImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAGQAAABkCAYAAABw4pVUAAAABmJLR0QA/wD/AP+gvaeTAAAACXBIWXMAAA3XAAAN1wFCKJt4AAAAB3RJTUUH4wgVBQsJl1CMZAAAASJJREFUeNrt3N0JwyAYhlEj3cj9R3Cm5rbkqtAP+qrnGaCYHPwJpLlaa++mmLpbAERAgAgIEAEBIiBABERAgAgIEAEBIiBABERAgAgIEAHZuVflj40x4i94zhk9vqsVvEq6AsQqMP1EjORx20OACAgQRRx7T+zzcFBxcjNDfoB4ntQqTm5Awo7MlqywZxcgYQ+RlqywJ3ozJAQCSBiEJSsQA0gYBpDAgAARECACAkRAgAgIEAERECACAmSjUv6eAOSB8m8YIGGzBUjYbAESBgMkbBkDEjZbgITBAClcxiqQvEoatreYIWEBASIgJ4Gkf11ntXH3nS9uxfGWfJ5J9hAgAgJEQAQEiIAAERAgAgJEQAQEiIAAERAgAgJEQAQEiL7qBuc6RKLHxr0CAAAAAElFTkSuQmCC"
@@ -1,12 +1,16 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Tag struct {
-	ID        int             `db:"id" json:"id"`
-	Name      string          `db:"name" json:"name"` // TODO make schema not null
-	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
-	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	ID           int             `db:"id" json:"id"`
+	Name         string          `db:"name" json:"name"` // TODO make schema not null
+	AutoTagRegex sql.NullString  `db:"auto_tag_regex" json:"auto_tag_regex"`
+	CreatedAt    SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt    SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 }
 
 func NewTag(name string) *Tag {
@@ -8,9 +8,11 @@ type PerformerReader interface {
 	FindByImageID(imageID int) ([]*Performer, error)
 	FindByGalleryID(galleryID int) ([]*Performer, error)
 	FindByNames(names []string, nocase bool) ([]*Performer, error)
+	FindByStashID(stashID StashID) ([]*Performer, error)
 	FindByStashIDStatus(hasStashID bool, stashboxEndpoint string) ([]*Performer, error)
 	CountByTagID(tagID int) (int, error)
 	Count() (int, error)
+	SceneStats(limit int) ([]*StatsAggregate, error)
 	All() ([]*Performer, error)
 	// TODO - this interface is temporary until the filter schema can fully
 	// support the query needed
@@ -19,6 +21,12 @@ type PerformerReader interface {
 	GetImage(performerID int) ([]byte, error)
 	GetStashIDs(performerID int) ([]*StashID, error)
 	GetTagIDs(sceneID int) ([]int, error)
+	// GetRelationships returns the relationships from and to the performer.
+	GetRelationships(performerID int) ([]*PerformerRelationship, error)
+	// FindRelated returns the performers linked to the performer by up to
+	// depth relationships of the provided types, or of any type if types is
+	// empty.
+	FindRelated(performerID int, types []PerformerRelationshipType, depth int) ([]*Performer, error)
 }
 
 type PerformerWriter interface {
@@ -30,6 +38,9 @@ type PerformerWriter interface {
 	DestroyImage(performerID int) error
 	UpdateStashIDs(performerID int, stashIDs []StashID) error
 	UpdateTags(sceneID int, tagIDs []int) error
+	AddRelationship(relationship PerformerRelationship) error
+	RemoveRelationship(relationship PerformerRelationship) error
+	RemoveMissingScenes() (int, error)
 }
 
 type PerformerReaderWriter interface {
@@ -0,0 +1,24 @@
+package models
+
+type PlaylistReader interface {
+	Find(id int) (*Playlist, error)
+	All() ([]*Playlist, error)
+	Count() (int, error)
+	// GetSceneIDs returns the ids of the scenes of the playlist, in the
+	// order of the playlist.
+	GetSceneIDs(playlistID int) ([]int, error)
+}
+
+type PlaylistWriter interface {
+	Create(newObject Playlist) (*Playlist, error)
+	Update(updatedObject Playlist) (*Playlist, error)
+	Destroy(id int) error
+	// UpdateScenes replaces the scenes of the playlist with sceneIDs, in
+	// that order.
+	UpdateScenes(playlistID int, sceneIDs []int) error
+}
+
+type PlaylistReaderWriter interface {
+	PlaylistReader
+	PlaylistWriter
+}
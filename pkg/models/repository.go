@@ -1,24 +1,34 @@
 package models
 
 type Repository interface {
+	DeletedObject() DeletedObjectReader
 	Gallery() GalleryReaderWriter
+	Group() GroupReaderWriter
 	Image() ImageReaderWriter
+	JobHistory() JobHistoryReaderWriter
 	Movie() MovieReaderWriter
 	Performer() PerformerReaderWriter
+	Playlist() PlaylistReaderWriter
 	Scene() SceneReaderWriter
 	SceneMarker() SceneMarkerReaderWriter
+	ScheduledTask() ScheduledTaskReaderWriter
 	ScrapedItem() ScrapedItemReaderWriter
 	Studio() StudioReaderWriter
 	Tag() TagReaderWriter
 }
 
 type ReaderRepository interface {
+	DeletedObject() DeletedObjectReader
 	Gallery() GalleryReader
+	Group() GroupReader
 	Image() ImageReader
+	JobHistory() JobHistoryReader
 	Movie() MovieReader
 	Performer() PerformerReader
+	Playlist() PlaylistReader
 	Scene() SceneReader
 	SceneMarker() SceneMarkerReader
+	ScheduledTask() ScheduledTaskReader
 	ScrapedItem() ScrapedItemReader
 	Studio() StudioReader
 	Tag() TagReader
@@ -15,6 +15,7 @@ type SceneReader interface {
 	CountByMovieID(movieID int) (int, error)
 	Count() (int, error)
 	Size() (float64, error)
+	Duration() (float64, error)
 	// SizeCount() (string, error)
 	CountByStudioID(studioID int) (int, error)
 	CountByTagID(tagID int) (int, error)
@@ -23,12 +24,16 @@ type SceneReader interface {
 	Wall(q *string) ([]*Scene, error)
 	All() ([]*Scene, error)
 	Query(sceneFilter *SceneFilterType, findFilter *FindFilterType) ([]*Scene, int, error)
+	QueryIDs(sceneFilter *SceneFilterType, findFilter *FindFilterType) ([]int, int, error)
 	GetCover(sceneID int) ([]byte, error)
 	GetMovies(sceneID int) ([]MoviesScenes, error)
 	GetTagIDs(sceneID int) ([]int, error)
+	GetManyTagIDs(sceneIDs []int) ([][]int, error)
 	GetGalleryIDs(sceneID int) ([]int, error)
 	GetPerformerIDs(sceneID int) ([]int, error)
+	GetManyPerformerIDs(sceneIDs []int) ([][]int, error)
 	GetStashIDs(sceneID int) ([]*StashID, error)
+	GetCaptions(sceneID int) ([]*SceneCaption, error)
 }
 
 type SceneWriter interface {
@@ -47,6 +52,8 @@ type SceneWriter interface {
 	UpdateGalleries(sceneID int, galleryIDs []int) error
 	UpdateMovies(sceneID int, movies []MoviesScenes) error
 	UpdateStashIDs(sceneID int, stashIDs []StashID) error
+	UpdateCaptions(sceneID int, captions []*SceneCaption) error
+	ClearMissingStudios() (int, error)
 }
 
 type SceneReaderWriter interface {
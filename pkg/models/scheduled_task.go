@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type ScheduledTaskReader interface {
+	Find(id int) (*ScheduledTask, error)
+	All() ([]*ScheduledTask, error)
+}
+
+type ScheduledTaskWriter interface {
+	Create(newObject ScheduledTask) (*ScheduledTask, error)
+	Update(updatedObject ScheduledTask) (*ScheduledTask, error)
+	// UpdateLastRunAt sets the time the task was last run, without changing
+	// its updated time.
+	UpdateLastRunAt(id int, lastRunAt time.Time) error
+	Destroy(id int) error
+}
+
+type ScheduledTaskReaderWriter interface {
+	ScheduledTaskReader
+	ScheduledTaskWriter
+}
@@ -15,3 +15,31 @@ func NullInt64(v int64) sql.NullInt64 {
 		Valid: true,
 	}
 }
+
+// FillNullString sets dst to src if dst is null or empty.
+func FillNullString(dst *sql.NullString, src sql.NullString) {
+	if !dst.Valid || dst.String == "" {
+		*dst = src
+	}
+}
+
+// FillNullInt64 sets dst to src if dst is null or zero.
+func FillNullInt64(dst *sql.NullInt64, src sql.NullInt64) {
+	if !dst.Valid || dst.Int64 == 0 {
+		*dst = src
+	}
+}
+
+// FillNullFloat64 sets dst to src if dst is null or zero.
+func FillNullFloat64(dst *sql.NullFloat64, src sql.NullFloat64) {
+	if !dst.Valid || dst.Float64 == 0 {
+		*dst = src
+	}
+}
+
+// FillSQLiteDate sets dst to src if dst is null or empty.
+func FillSQLiteDate(dst *SQLiteDate, src SQLiteDate) {
+	if !dst.Valid || dst.String == "" {
+		*dst = src
+	}
+}
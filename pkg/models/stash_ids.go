@@ -12,3 +12,24 @@ func StashIDsFromInput(i []*StashIDInput) []StashID {
 
 	return ret
 }
+
+// MergeStashIDs returns the existing stash IDs combined with the new stash
+// IDs. A new stash ID replaces an existing stash ID for the same endpoint.
+func MergeStashIDs(existing []*StashID, newIDs []StashID) []StashID {
+	var ret []StashID
+	for _, e := range existing {
+		replaced := false
+		for _, n := range newIDs {
+			if n.Endpoint == e.Endpoint {
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			ret = append(ret, *e)
+		}
+	}
+
+	return append(ret, newIDs...)
+}
@@ -5,7 +5,10 @@ type StudioReader interface {
 	FindMany(ids []int) ([]*Studio, error)
 	FindChildren(id int) ([]*Studio, error)
 	FindByName(name string, nocase bool) (*Studio, error)
+	FindByNames(names []string, nocase bool) ([]*Studio, error)
+	FindByStashID(stashID StashID) ([]*Studio, error)
 	Count() (int, error)
+	SceneStats(limit int) ([]*StatsAggregate, error)
 	All() ([]*Studio, error)
 	// TODO - this interface is temporary until the filter schema can fully
 	// support the query needed
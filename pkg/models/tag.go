@@ -11,12 +11,15 @@ type TagReader interface {
 	FindByName(name string, nocase bool) (*Tag, error)
 	FindByNames(names []string, nocase bool) ([]*Tag, error)
 	Count() (int, error)
+	SceneStats(limit int) ([]*StatsAggregate, error)
 	All() ([]*Tag, error)
 	// TODO - this interface is temporary until the filter schema can fully
 	// support the query needed
 	QueryForAutoTag(words []string) ([]*Tag, error)
 	Query(tagFilter *TagFilterType, findFilter *FindFilterType) ([]*Tag, int, error)
 	GetImage(tagID int) ([]byte, error)
+	UsageTimeline(tagID int, interval TagUsageInterval, dateField TagUsageDateField) ([]*TagUsagePoint, error)
+	Trending(days int, limit int) ([]*TrendingTag, error)
 }
 
 type TagWriter interface {
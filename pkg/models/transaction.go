@@ -1,6 +1,13 @@
 package models
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrReadOnly is returned when attempting to write to the database while
+// the server is in read-only mode.
+var ErrReadOnly = errors.New("server is in read-only mode")
 
 type Transaction interface {
 	Begin() error
@@ -19,6 +26,10 @@ type ReadTransaction interface {
 type TransactionManager interface {
 	WithTxn(ctx context.Context, fn func(r Repository) error) error
 	WithReadTxn(ctx context.Context, fn func(r ReaderRepository) error) error
+
+	// DryRun calls fn with a TransactionManager whose changes are discarded
+	// once fn returns.
+	DryRun(ctx context.Context, fn func(txnManager TransactionManager) error) error
 }
 
 func WithTxn(txn Transaction, fn func(r Repository) error) error {
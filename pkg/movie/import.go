@@ -154,6 +154,55 @@ func (i *Importer) Create() (*int, error) {
 	return &id, nil
 }
 
+// Merge sets the missing fields and images of the existing movie.
+func (i *Importer) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing movie: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing movie with id %d not found", id)
+	}
+
+	movie := *existing
+	models.FillNullString(&movie.Aliases, i.movie.Aliases)
+	models.FillNullInt64(&movie.Duration, i.movie.Duration)
+	models.FillSQLiteDate(&movie.Date, i.movie.Date)
+	models.FillNullInt64(&movie.Rating, i.movie.Rating)
+	models.FillNullInt64(&movie.StudioID, i.movie.StudioID)
+	models.FillNullString(&movie.Director, i.movie.Director)
+	models.FillNullString(&movie.Synopsis, i.movie.Synopsis)
+	models.FillNullString(&movie.URL, i.movie.URL)
+
+	if _, err := i.ReaderWriter.UpdateFull(movie); err != nil {
+		return fmt.Errorf("error updating existing movie: %s", err.Error())
+	}
+
+	if len(i.frontImageData) == 0 && len(i.backImageData) == 0 {
+		return nil
+	}
+
+	// images are set together, so keep the existing images in place of the
+	// imported ones
+	existingFront, err := i.ReaderWriter.GetFrontImage(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing movie front image: %s", err.Error())
+	}
+	if len(existingFront) > 0 {
+		i.frontImageData = existingFront
+	}
+
+	existingBack, err := i.ReaderWriter.GetBackImage(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing movie back image: %s", err.Error())
+	}
+	if len(existingBack) > 0 {
+		i.backImageData = existingBack
+	}
+
+	return nil
+}
+
 func (i *Importer) Update(id int) error {
 	movie := i.movie
 	movie.ID = id
@@ -275,3 +275,46 @@ func TestUpdate(t *testing.T) {
 
 	readerWriter.AssertExpectations(t)
 }
+
+func TestMerge(t *testing.T) {
+	readerWriter := &mocks.MovieReaderWriter{}
+
+	existing := models.Movie{
+		ID:       movieID,
+		Name:     models.NullString(movieName),
+		Director: models.NullString("existing director"),
+	}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		movie: models.Movie{
+			Name:     models.NullString(movieName),
+			Director: models.NullString("imported director"),
+			Duration: models.NullInt64(60),
+		},
+		frontImageData: []byte("importedFront"),
+		backImageData:  []byte("importedBack"),
+	}
+
+	merged := existing
+	merged.Duration = models.NullInt64(60)
+
+	readerWriter.On("Find", movieID).Return(&existing, nil).Once()
+	readerWriter.On("UpdateFull", merged).Return(nil, nil).Once()
+	readerWriter.On("GetFrontImage", movieID).Return(frontImageBytes, nil).Once()
+	readerWriter.On("GetBackImage", movieID).Return(nil, nil).Once()
+
+	err := i.Merge(movieID)
+	assert.Nil(t, err)
+
+	// the existing front image is kept and the missing back image is set
+	assert.Equal(t, frontImageBytes, i.frontImageData)
+	assert.Equal(t, []byte("importedBack"), i.backImageData)
+
+	readerWriter.On("Find", errImageID).Return(nil, errors.New("Find error")).Once()
+
+	err = i.Merge(errImageID)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
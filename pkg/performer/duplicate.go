@@ -0,0 +1,77 @@
+package performer
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// normaliseName returns the name in lowercase, with surrounding whitespace
+// removed and internal whitespace collapsed.
+func normaliseName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func hasAlias(p *models.Performer, name string) bool {
+	if !p.Aliases.Valid {
+		return false
+	}
+
+	for _, alias := range strings.Split(p.Aliases.String, ",") {
+		if normaliseName(alias) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindDuplicates returns the IDs of the performers with a name or alias
+// matching the provided name, ignoring case and whitespace differences.
+func FindDuplicates(r models.PerformerReader, name string) ([]int, error) {
+	name = normaliseName(name)
+	if name == "" {
+		return nil, nil
+	}
+
+	var ret []int
+	found := make(map[int]bool)
+	add := func(id int) {
+		if !found[id] {
+			found[id] = true
+			ret = append(ret, id)
+		}
+	}
+
+	byName, err := r.FindByNames([]string{name}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range byName {
+		add(p.ID)
+	}
+
+	// the aliases are stored as a single string, so find candidates and
+	// check each alias individually
+	perPage := -1
+	candidates, _, err := r.Query(&models.PerformerFilterType{
+		Aliases: &models.StringCriterionInput{
+			Value:    name,
+			Modifier: models.CriterionModifierIncludes,
+		},
+	}, &models.FindFilterType{
+		PerPage: &perPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range candidates {
+		if hasAlias(p, name) {
+			add(p.ID)
+		}
+	}
+
+	return ret, nil
+}
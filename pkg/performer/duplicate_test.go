@@ -0,0 +1,60 @@
+package performer
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	duplicateName        = "Jane Doe"
+	duplicateNameID      = 1
+	duplicateAliasID     = 2
+	partialAliasID       = 3
+	duplicateNameErrName = "error"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	mockPerformerReader := &mocks.PerformerReaderWriter{}
+
+	mockPerformerReader.On("FindByNames", []string{"jane doe"}, true).Return([]*models.Performer{
+		{
+			ID: duplicateNameID,
+		},
+	}, nil).Once()
+	mockPerformerReader.On("Query", mock.Anything, mock.Anything).Return([]*models.Performer{
+		{
+			ID:      duplicateNameID,
+			Aliases: sql.NullString{String: "jane doe", Valid: true},
+		},
+		{
+			ID:      duplicateAliasID,
+			Aliases: sql.NullString{String: "JD, JANE  DOE ", Valid: true},
+		},
+		{
+			ID:      partialAliasID,
+			Aliases: sql.NullString{String: "Jane Doe Smith", Valid: true},
+		},
+	}, 3, nil).Once()
+
+	ids, err := FindDuplicates(mockPerformerReader, " "+duplicateName+" ")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{duplicateNameID, duplicateAliasID}, ids)
+
+	// empty names never match
+	ids, err = FindDuplicates(mockPerformerReader, " ")
+	assert.Nil(t, err)
+	assert.Len(t, ids, 0)
+
+	mockPerformerReader.On("FindByNames", []string{duplicateNameErrName}, true).Return(nil, errors.New("FindByNames error")).Once()
+
+	_, err = FindDuplicates(mockPerformerReader, duplicateNameErrName)
+	assert.NotNil(t, err)
+
+	mockPerformerReader.AssertExpectations(t)
+}
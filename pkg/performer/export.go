@@ -2,6 +2,7 @@ package performer
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
@@ -37,7 +38,7 @@ func ToJSON(reader models.PerformerReader, performer *models.Performer) (*jsonsc
 		newPerformerJSON.EyeColor = performer.EyeColor.String
 	}
 	if performer.Height.Valid {
-		newPerformerJSON.Height = performer.Height.String
+		newPerformerJSON.Height = strconv.FormatInt(performer.Height.Int64, 10)
 	}
 	if performer.Measurements.Valid {
 		newPerformerJSON.Measurements = performer.Measurements.String
@@ -81,6 +82,9 @@ func ToJSON(reader models.PerformerReader, performer *models.Performer) (*jsonsc
 	if performer.Weight.Valid {
 		newPerformerJSON.Weight = int(performer.Weight.Int64)
 	}
+	if performer.AutoTagRegex.Valid {
+		newPerformerJSON.AutoTagRegex = performer.AutoTagRegex.String
+	}
 
 	image, err := reader.GetImage(performer.ID)
 	if err != nil {
@@ -91,6 +95,15 @@ func ToJSON(reader models.PerformerReader, performer *models.Performer) (*jsonsc
 		newPerformerJSON.Image = utils.GetBase64StringFromData(image)
 	}
 
+	stashIDs, err := reader.GetStashIDs(performer.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting performer stash ids: %s", err.Error())
+	}
+
+	for _, stashID := range stashIDs {
+		newPerformerJSON.StashIDs = append(newPerformerJSON.StashIDs, *stashID)
+	}
+
 	return &newPerformerJSON, nil
 }
 
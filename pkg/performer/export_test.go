@@ -3,6 +3,7 @@ package performer
 import (
 	"database/sql"
 	"errors"
+	"strconv"
 
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
@@ -15,9 +16,10 @@ import (
 )
 
 const (
-	performerID = 1
-	noImageID   = 2
-	errImageID  = 3
+	performerID   = 1
+	noImageID     = 2
+	errImageID    = 3
+	errStashIDsID = 4
 )
 
 const (
@@ -30,7 +32,7 @@ const (
 	eyeColor      = "eyeColor"
 	fakeTits      = "fakeTits"
 	gender        = "gender"
-	height        = "height"
+	height        = 170
 	instagram     = "instagram"
 	measurements  = "measurements"
 	piercings     = "piercings"
@@ -44,6 +46,11 @@ const (
 
 var imageBytes = []byte("imageBytes")
 
+var stashID = models.StashID{
+	StashID:  "StashID",
+	Endpoint: "Endpoint",
+}
+
 const image = "aW1hZ2VCeXRlcw=="
 
 var birthDate = models.SQLiteDate{
@@ -75,7 +82,7 @@ func createFullPerformer(id int, name string) *models.Performer {
 			Valid: true,
 		},
 		Gender:       models.NullString(gender),
-		Height:       models.NullString(height),
+		Height:       models.NullInt64(height),
 		Instagram:    models.NullString(instagram),
 		Measurements: models.NullString(measurements),
 		Piercings:    models.NullString(piercings),
@@ -123,7 +130,7 @@ func createFullJSONPerformer(name string, image string) *jsonschema.Performer {
 		FakeTits:     fakeTits,
 		Favorite:     true,
 		Gender:       gender,
-		Height:       height,
+		Height:       strconv.Itoa(height),
 		Instagram:    instagram,
 		Measurements: measurements,
 		Piercings:    piercings,
@@ -141,6 +148,9 @@ func createFullJSONPerformer(name string, image string) *jsonschema.Performer {
 		DeathDate: deathDate.String,
 		HairColor: hairColor,
 		Weight:    weight,
+		StashIDs: []models.StashID{
+			stashID,
+		},
 	}
 }
 
@@ -180,6 +190,11 @@ func initTestTable() {
 			nil,
 			true,
 		},
+		testScenario{
+			*createFullPerformer(errStashIDsID, performerName),
+			nil,
+			true,
+		},
 	}
 }
 
@@ -189,10 +204,16 @@ func TestToJSON(t *testing.T) {
 	mockPerformerReader := &mocks.PerformerReaderWriter{}
 
 	imageErr := errors.New("error getting image")
+	stashIDsErr := errors.New("error getting stash ids")
 
 	mockPerformerReader.On("GetImage", performerID).Return(imageBytes, nil).Once()
 	mockPerformerReader.On("GetImage", noImageID).Return(nil, nil).Once()
 	mockPerformerReader.On("GetImage", errImageID).Return(nil, imageErr).Once()
+	mockPerformerReader.On("GetImage", errStashIDsID).Return(imageBytes, nil).Once()
+
+	mockPerformerReader.On("GetStashIDs", performerID).Return([]*models.StashID{&stashID}, nil).Once()
+	mockPerformerReader.On("GetStashIDs", noImageID).Return(nil, nil).Once()
+	mockPerformerReader.On("GetStashIDs", errStashIDsID).Return(nil, stashIDsErr).Once()
 
 	for i, s := range scenarios {
 		tag := s.input
@@ -21,6 +21,9 @@ type Importer struct {
 	imageData []byte
 
 	tags []*models.Tag
+
+	// tags of the existing performer when merging
+	existingTagIDs []int
 }
 
 func (i *Importer) PreImport() error {
@@ -112,6 +115,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, t := range i.tags {
 			tagIDs = append(tagIDs, t.ID)
 		}
+		tagIDs = utils.IntAppendUniques(i.existingTagIDs, tagIDs)
 		if err := i.ReaderWriter.UpdateTags(id, tagIDs); err != nil {
 			return fmt.Errorf("failed to associate tags: %s", err.Error())
 		}
@@ -123,6 +127,28 @@ func (i *Importer) PostImport(id int) error {
 		}
 	}
 
+	if len(i.Input.StashIDs) > 0 {
+		if err := i.updateStashIDs(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateStashIDs merges the stash IDs from the input with the existing stash
+// IDs of the performer, so that stash IDs for other endpoints are retained when
+// overwriting.
+func (i *Importer) updateStashIDs(id int) error {
+	existing, err := i.ReaderWriter.GetStashIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting performer stash ids: %s", err.Error())
+	}
+
+	if err := i.ReaderWriter.UpdateStashIDs(id, models.MergeStashIDs(existing, i.Input.StashIDs)); err != nil {
+		return fmt.Errorf("error setting performer stash ids: %s", err.Error())
+	}
+
 	return nil
 }
 
@@ -142,6 +168,25 @@ func (i *Importer) FindExistingID() (*int, error) {
 		return &id, nil
 	}
 
+	// fall back to stash IDs, in case the performer has a different name
+	return i.findExistingByStashIDs()
+}
+
+// findExistingByStashIDs returns the ID of the first existing performer with
+// any of the stash IDs in the input.
+func (i *Importer) findExistingByStashIDs() (*int, error) {
+	for _, stashID := range i.Input.StashIDs {
+		existing, err := i.ReaderWriter.FindByStashID(stashID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(existing) > 0 {
+			id := existing[0].ID
+			return &id, nil
+		}
+	}
+
 	return nil, nil
 }
 
@@ -166,6 +211,63 @@ func (i *Importer) Update(id int) error {
 	return nil
 }
 
+// Merge sets the missing fields and image of the existing performer. The
+// favorite flag of the existing performer is kept.
+func (i *Importer) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing performer: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing performer with id %d not found", id)
+	}
+
+	performer := *existing
+	models.FillNullString(&performer.Gender, i.performer.Gender)
+	models.FillNullString(&performer.URL, i.performer.URL)
+	models.FillNullString(&performer.Twitter, i.performer.Twitter)
+	models.FillNullString(&performer.Instagram, i.performer.Instagram)
+	models.FillSQLiteDate(&performer.Birthdate, i.performer.Birthdate)
+	models.FillNullString(&performer.Ethnicity, i.performer.Ethnicity)
+	models.FillNullString(&performer.Country, i.performer.Country)
+	models.FillNullString(&performer.EyeColor, i.performer.EyeColor)
+	models.FillNullInt64(&performer.Height, i.performer.Height)
+	models.FillNullString(&performer.Measurements, i.performer.Measurements)
+	models.FillNullString(&performer.FakeTits, i.performer.FakeTits)
+	models.FillNullString(&performer.CareerLength, i.performer.CareerLength)
+	models.FillNullString(&performer.Tattoos, i.performer.Tattoos)
+	models.FillNullString(&performer.Piercings, i.performer.Piercings)
+	models.FillNullString(&performer.Aliases, i.performer.Aliases)
+	models.FillNullInt64(&performer.Rating, i.performer.Rating)
+	models.FillNullString(&performer.Details, i.performer.Details)
+	models.FillSQLiteDate(&performer.DeathDate, i.performer.DeathDate)
+	models.FillNullString(&performer.HairColor, i.performer.HairColor)
+	models.FillNullInt64(&performer.Weight, i.performer.Weight)
+	models.FillNullString(&performer.AutoTagRegex, i.performer.AutoTagRegex)
+
+	if _, err := i.ReaderWriter.UpdateFull(performer); err != nil {
+		return fmt.Errorf("error updating existing performer: %s", err.Error())
+	}
+
+	i.existingTagIDs, err = i.ReaderWriter.GetTagIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing performer tags: %s", err.Error())
+	}
+
+	if len(i.imageData) > 0 {
+		existingImage, err := i.ReaderWriter.GetImage(id)
+		if err != nil {
+			return fmt.Errorf("error getting existing performer image: %s", err.Error())
+		}
+
+		if len(existingImage) > 0 {
+			i.imageData = nil
+		}
+	}
+
+	return nil
+}
+
 func performerJSONToPerformer(performerJSON jsonschema.Performer) models.Performer {
 	checksum := utils.MD5FromString(performerJSON.Name)
 
@@ -192,13 +294,17 @@ func performerJSONToPerformer(performerJSON jsonschema.Performer) models.Perform
 		newPerformer.Ethnicity = sql.NullString{String: performerJSON.Ethnicity, Valid: true}
 	}
 	if performerJSON.Country != "" {
-		newPerformer.Country = sql.NullString{String: performerJSON.Country, Valid: true}
+		newPerformer.Country = sql.NullString{String: utils.NormaliseCountry(performerJSON.Country), Valid: true}
 	}
 	if performerJSON.EyeColor != "" {
 		newPerformer.EyeColor = sql.NullString{String: performerJSON.EyeColor, Valid: true}
 	}
 	if performerJSON.Height != "" {
-		newPerformer.Height = sql.NullString{String: performerJSON.Height, Valid: true}
+		// older exports may contain heights in other units
+		height, err := utils.ParseHeight(performerJSON.Height)
+		if err == nil {
+			newPerformer.Height = sql.NullInt64{Int64: int64(height), Valid: true}
+		}
 	}
 	if performerJSON.Measurements != "" {
 		newPerformer.Measurements = sql.NullString{String: performerJSON.Measurements, Valid: true}
@@ -239,6 +345,9 @@ func performerJSONToPerformer(performerJSON jsonschema.Performer) models.Perform
 	if performerJSON.Weight != 0 {
 		newPerformer.Weight = sql.NullInt64{Int64: int64(performerJSON.Weight), Valid: true}
 	}
+	if performerJSON.AutoTagRegex != "" {
+		newPerformer.AutoTagRegex = sql.NullString{String: performerJSON.AutoTagRegex, Valid: true}
+	}
 
 	return newPerformer
 }
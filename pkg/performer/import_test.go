@@ -1,6 +1,7 @@
 package performer
 
 import (
+	"database/sql"
 	"errors"
 
 	"github.com/stretchr/testify/mock"
@@ -204,6 +205,85 @@ func TestImporterFindExistingID(t *testing.T) {
 	readerWriter.AssertExpectations(t)
 }
 
+func TestImporterFindExistingIDByStashID(t *testing.T) {
+	readerWriter := &mocks.PerformerReaderWriter{}
+
+	missingStashID := models.StashID{
+		StashID:  "missingStashID",
+		Endpoint: stashID.Endpoint,
+	}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		Input: jsonschema.Performer{
+			Name: performerName,
+			StashIDs: []models.StashID{
+				missingStashID,
+				stashID,
+			},
+		},
+	}
+
+	errFindByStashID := errors.New("FindByStashID error")
+	readerWriter.On("FindByNames", []string{performerName}, false).Return(nil, nil).Twice()
+	readerWriter.On("FindByStashID", missingStashID).Return(nil, nil).Once()
+	readerWriter.On("FindByStashID", stashID).Return([]*models.Performer{
+		{
+			ID: existingPerformerID,
+		},
+	}, nil).Once()
+
+	id, err := i.FindExistingID()
+	assert.Equal(t, existingPerformerID, *id)
+	assert.Nil(t, err)
+
+	readerWriter.On("FindByStashID", missingStashID).Return(nil, errFindByStashID).Once()
+
+	id, err = i.FindExistingID()
+	assert.Nil(t, id)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
+func TestImporterPostImportUpdateStashIDs(t *testing.T) {
+	readerWriter := &mocks.PerformerReaderWriter{}
+
+	otherStashID := models.StashID{
+		StashID:  "otherStashID",
+		Endpoint: "otherEndpoint",
+	}
+	oldStashID := models.StashID{
+		StashID:  "oldStashID",
+		Endpoint: stashID.Endpoint,
+	}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		Input: jsonschema.Performer{
+			StashIDs: []models.StashID{
+				stashID,
+			},
+		},
+	}
+
+	updateErr := errors.New("UpdateStashIDs error")
+
+	// stash IDs for other endpoints are retained
+	readerWriter.On("GetStashIDs", performerID).Return([]*models.StashID{&otherStashID, &oldStashID}, nil).Once()
+	readerWriter.On("UpdateStashIDs", performerID, []models.StashID{otherStashID, stashID}).Return(nil).Once()
+	readerWriter.On("GetStashIDs", errStashIDsID).Return(nil, nil).Once()
+	readerWriter.On("UpdateStashIDs", errStashIDsID, []models.StashID{stashID}).Return(updateErr).Once()
+
+	err := i.PostImport(performerID)
+	assert.Nil(t, err)
+
+	err = i.PostImport(errStashIDsID)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
 func TestImporterPostImportUpdateTags(t *testing.T) {
 	readerWriter := &mocks.PerformerReaderWriter{}
 
@@ -300,3 +380,70 @@ func TestUpdate(t *testing.T) {
 
 	readerWriter.AssertExpectations(t)
 }
+
+func TestImporterPostImportMergeTags(t *testing.T) {
+	readerWriter := &mocks.PerformerReaderWriter{}
+
+	const otherTagID = 107
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		tags: []*models.Tag{
+			{
+				ID: existingTagID,
+			},
+		},
+		existingTagIDs: []int{otherTagID, existingTagID},
+	}
+
+	readerWriter.On("UpdateTags", performerID, []int{otherTagID, existingTagID}).Return(nil).Once()
+
+	err := i.PostImport(performerID)
+	assert.Nil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
+func TestMerge(t *testing.T) {
+	readerWriter := &mocks.PerformerReaderWriter{}
+
+	existing := models.Performer{
+		ID:       performerID,
+		Name:     models.NullString(performerName),
+		Details:  models.NullString("existing details"),
+		Favorite: sql.NullBool{Bool: false, Valid: true},
+	}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		performer: models.Performer{
+			Name:     models.NullString(performerName),
+			Details:  models.NullString("imported details"),
+			URL:      models.NullString(url),
+			Favorite: sql.NullBool{Bool: true, Valid: true},
+		},
+		imageData: imageBytes,
+	}
+
+	merged := existing
+	merged.URL = models.NullString(url)
+
+	readerWriter.On("Find", performerID).Return(&existing, nil).Once()
+	readerWriter.On("UpdateFull", merged).Return(nil, nil).Once()
+	readerWriter.On("GetTagIDs", performerID).Return([]int{existingTagID}, nil).Once()
+	readerWriter.On("GetImage", performerID).Return(imageBytes, nil).Once()
+
+	err := i.Merge(performerID)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{existingTagID}, i.existingTagIDs)
+
+	// the existing image is kept
+	assert.Nil(t, i.imageData)
+
+	readerWriter.On("Find", errImageID).Return(nil, nil).Once()
+
+	err = i.Merge(errImageID)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
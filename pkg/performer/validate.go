@@ -2,6 +2,7 @@ package performer
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
@@ -35,3 +36,39 @@ func ValidateDeathDate(performer *models.Performer, birthdate *string, deathDate
 
 	return nil
 }
+
+// ValidateRelationship returns an error if the relationship links a
+// performer to itself or to a missing performer, or if it would make two
+// performers aliases of each other.
+func ValidateRelationship(relationship models.PerformerRelationship, qb models.PerformerReader) error {
+	if relationship.PerformerID == relationship.RelatedID {
+		return errors.New("a performer cannot be related to itself")
+	}
+
+	for _, id := range []int{relationship.PerformerID, relationship.RelatedID} {
+		p, err := qb.Find(id)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("performer with id %d not found", id)
+		}
+	}
+
+	if models.PerformerRelationshipType(relationship.Type).IsSymmetric() {
+		return nil
+	}
+
+	existing, err := qb.GetRelationships(relationship.PerformerID)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range existing {
+		if r.Type == relationship.Type && r.PerformerID == relationship.RelatedID && r.RelatedID == relationship.PerformerID {
+			return fmt.Errorf("performer %d is already an alias of performer %d", relationship.RelatedID, relationship.PerformerID)
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,3 +69,44 @@ func TestValidateDeathDate(t *testing.T) {
 	assert.Nil(ValidateDeathDate(&validPerformer, nil, &date4))
 	assert.Nil(ValidateDeathDate(&validPerformer, &date1, nil))
 }
+
+func TestValidateRelationship(t *testing.T) {
+	assert := assert.New(t)
+
+	const (
+		performerID = iota + 1
+		aliasID
+		missingID
+	)
+
+	alias := models.PerformerRelationship{
+		PerformerID: aliasID,
+		RelatedID:   performerID,
+		Type:        models.PerformerRelationshipTypeAliasOf.String(),
+	}
+
+	qb := &mocks.PerformerReaderWriter{}
+	qb.On("Find", performerID).Return(&models.Performer{ID: performerID}, nil)
+	qb.On("Find", aliasID).Return(&models.Performer{ID: aliasID}, nil)
+	qb.On("Find", missingID).Return(nil, nil)
+	qb.On("GetRelationships", performerID).Return([]*models.PerformerRelationship{&alias}, nil)
+	qb.On("GetRelationships", aliasID).Return([]*models.PerformerRelationship{&alias}, nil)
+
+	relationship := func(performerID int, relatedID int, relationshipType models.PerformerRelationshipType) models.PerformerRelationship {
+		return models.PerformerRelationship{
+			PerformerID: performerID,
+			RelatedID:   relatedID,
+			Type:        relationshipType.String(),
+		}
+	}
+
+	assert.Nil(ValidateRelationship(alias, qb))
+	assert.Nil(ValidateRelationship(relationship(performerID, aliasID, models.PerformerRelationshipTypeSibling), qb))
+
+	// performers cannot be related to themselves or to missing performers
+	assert.NotNil(ValidateRelationship(relationship(performerID, performerID, models.PerformerRelationshipTypeSibling), qb))
+	assert.NotNil(ValidateRelationship(relationship(performerID, missingID, models.PerformerRelationshipTypePairedWith), qb))
+
+	// performers cannot be aliases of each other
+	assert.NotNil(ValidateRelationship(relationship(performerID, aliasID, models.PerformerRelationshipTypeAliasOf), qb))
+}
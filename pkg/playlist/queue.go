@@ -0,0 +1,58 @@
+// Package playlist provides the play queue of playlists.
+package playlist
+
+import (
+	"math/rand"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Order returns the ids of the scenes of the playlist in the order they are
+// played. The scenes of shuffled playlists are played in a random order
+// determined by seed, so that the same seed always gives the same order.
+func Order(p *models.Playlist, sceneIDs []int, seed int64) []int {
+	ret := make([]int, len(sceneIDs))
+	copy(ret, sceneIDs)
+
+	if p.Shuffle {
+		r := rand.New(rand.NewSource(seed))
+		r.Shuffle(len(ret), func(i, j int) {
+			ret[i], ret[j] = ret[j], ret[i]
+		})
+	}
+
+	return ret
+}
+
+// Next returns the id of the scene played after the scene with currentID,
+// or the first scene if currentID is nil or not in the playlist. It returns
+// false if the playlist is empty, or if currentID is the last scene and the
+// playlist does not repeat.
+func Next(p *models.Playlist, sceneIDs []int, currentID *int, seed int64) (int, bool) {
+	order := Order(p, sceneIDs, seed)
+	if len(order) == 0 {
+		return 0, false
+	}
+
+	if currentID == nil {
+		return order[0], true
+	}
+
+	for i, id := range order {
+		if id != *currentID {
+			continue
+		}
+
+		if i+1 < len(order) {
+			return order[i+1], true
+		}
+
+		if p.Repeat {
+			return order[0], true
+		}
+
+		return 0, false
+	}
+
+	return order[0], true
+}
@@ -0,0 +1,65 @@
+package playlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestNext(t *testing.T) {
+	sceneIDs := []int{3, 1, 2}
+
+	tests := []struct {
+		name      string
+		repeat    bool
+		currentID *int
+		want      int
+		wantFound bool
+	}{
+		{"start", false, nil, 3, true},
+		{"middle", false, intPtr(1), 2, true},
+		{"last", false, intPtr(2), 0, false},
+		{"last repeat", true, intPtr(2), 3, true},
+		{"not in playlist", false, intPtr(4), 3, true},
+	}
+
+	for _, tt := range tests {
+		p := &models.Playlist{Repeat: tt.repeat}
+		got, found := Next(p, sceneIDs, tt.currentID, 0)
+		assert.Equal(t, tt.wantFound, found, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+
+	_, found := Next(&models.Playlist{Repeat: true}, nil, nil, 0)
+	assert.False(t, found, "empty")
+}
+
+func TestNextShuffle(t *testing.T) {
+	sceneIDs := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	p := &models.Playlist{Shuffle: true}
+	const seed = 42
+
+	order := Order(p, sceneIDs, seed)
+	assert.ElementsMatch(t, sceneIDs, order)
+	assert.Equal(t, order, Order(p, sceneIDs, seed))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, sceneIDs, "scene ids are not changed")
+
+	// following the queue plays each scene once
+	var played []int
+	var currentID *int
+	for {
+		next, found := Next(p, sceneIDs, currentID, seed)
+		if !found {
+			break
+		}
+		played = append(played, next)
+		currentID = intPtr(next)
+	}
+	assert.Equal(t, order, played)
+}
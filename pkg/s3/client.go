@@ -0,0 +1,310 @@
+// Package s3 provides a minimal client of S3-compatible object storage,
+// such as Amazon S3 and MinIO.
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRegion is the region used to sign requests if none is set.
+const DefaultRegion = "us-east-1"
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Config configures the connection to a bucket.
+type Config struct {
+	// Endpoint is the URL of the storage service, such as
+	// https://s3.us-east-1.amazonaws.com or http://localhost:9000.
+	Endpoint string
+	Bucket   string
+	Region   string
+
+	AccessKey string
+	SecretKey string
+
+	// PathStyle addresses the bucket in the URL path rather than in the
+	// host name. It is usually required by MinIO.
+	PathStyle bool
+}
+
+// Client reads and writes the objects of a bucket.
+type Client struct {
+	config   Config
+	endpoint *url.URL
+	client   *http.Client
+
+	// now returns the time used to sign requests
+	now func() time.Time
+}
+
+// New returns a client of the bucket configured by c.
+func New(c Config) (*Client, error) {
+	if c.Endpoint == "" {
+		return nil, errors.New("endpoint is not set")
+	}
+	if c.Bucket == "" {
+		return nil, errors.New("bucket is not set")
+	}
+
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %s: %s", c.Endpoint, err.Error())
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %s: scheme must be http or https", c.Endpoint)
+	}
+
+	if c.Region == "" {
+		c.Region = DefaultRegion
+	}
+
+	return &Client{
+		config:   c,
+		endpoint: endpoint,
+		client:   &http.Client{},
+		now:      time.Now,
+	}, nil
+}
+
+// objectURL returns the URL of the object with the key, or of the bucket if
+// key is empty.
+func (c *Client) objectURL(key string, query url.Values) *url.URL {
+	u := *c.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+
+	switch {
+	case c.config.PathStyle && key == "":
+		u.Path = base + "/" + c.config.Bucket
+	case c.config.PathStyle:
+		u.Path = base + "/" + c.config.Bucket + "/" + key
+	default:
+		u.Host = c.config.Bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = query.Encode()
+
+	return &u
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.objectURL(key, query).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	return req.WithContext(ctx), nil
+}
+
+// do signs and sends the request, returning an error if the response status
+// is not successful.
+func (c *Client) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	sign(req, c.config.AccessKey, c.config.SecretKey, c.config.Region, payloadHash, c.now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotModified {
+		defer resp.Body.Close()
+		return nil, responseError(req, resp)
+	}
+
+	return resp, nil
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func responseError(req *http.Request, resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	var e errorResponse
+	if err := xml.Unmarshal(body, &e); err != nil || e.Code == "" {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+
+	return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, e.Code, e.Message)
+}
+
+// Put uploads the object with the key, reading size bytes from r.
+func (c *Client) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := c.newRequest(ctx, http.MethodPut, key, nil, r)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.do(req, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// forwardedHeaders are the request headers passed on by Get.
+var forwardedHeaders = []string{"Range", "If-Range", "If-None-Match", "If-Modified-Since"}
+
+// Get downloads the object with the key. The range and conditional headers
+// in header are passed on to the storage service, so the status of the
+// response may be partial content or not modified. The caller must close
+// the body of the response.
+func (c *Client) Get(ctx context.Context, key string, header http.Header) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range forwardedHeaders {
+		if v := header.Get(h); v != "" {
+			req.Header.Set(h, v)
+		}
+	}
+
+	return c.do(req, emptyPayload)
+}
+
+// Exists returns true if the object with the key exists.
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	req, err := c.newRequest(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.do(req, emptyPayload)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// Delete deletes the object with the key. It does not return an error if
+// the object does not exist.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, emptyPayload)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Copy copies the object with the key src to the key dest in the bucket.
+func (c *Client) Copy(ctx context.Context, src string, dest string) error {
+	req, err := c.newRequest(ctx, http.MethodPut, dest, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Amz-Copy-Source", uriEncode("/"+c.config.Bucket+"/"+src, false))
+
+	resp, err := c.do(req, emptyPayload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// errors during the copy are reported in the body of a successful
+	// response
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var e errorResponse
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("copying %s to %s: %s: %s", src, dest, e.Code, e.Message)
+	}
+
+	return nil
+}
+
+// Object is an object listed by List.
+type Object struct {
+	Key  string `xml:"Key"`
+	Size int64  `xml:"Size"`
+}
+
+type listResponse struct {
+	Contents              []Object `xml:"Contents"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+// List calls fn with each object of which the key starts with prefix, in
+// the order of their keys.
+func (c *Client) List(ctx context.Context, prefix string, fn func(o Object) error) error {
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		req, err := c.newRequest(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.do(req, emptyPayload)
+		if err != nil {
+			return err
+		}
+
+		var list listResponse
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error decoding object list: %s", err.Error())
+		}
+
+		for _, o := range list.Contents {
+			if err := fn(o); err != nil {
+				return err
+			}
+		}
+
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			return nil
+		}
+		token = list.NextContinuationToken
+	}
+}
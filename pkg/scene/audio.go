@@ -0,0 +1,75 @@
+package scene
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// audioTagSeparator separates multiple values of a tag. ffprobe joins
+// repeated Vorbis comments with it, and it is commonly used in ID3 tags.
+const audioTagSeparator = ";"
+
+// AudioTagsToJSON returns the metadata set in the tags of the probed audio
+// file. The artists are returned as performers, the genres as tags and the
+// album as a movie, with the track number as the scene index. The date is
+// only returned if it is a full date.
+func AudioTagsToJSON(probe *ffmpeg.VideoFile) *jsonschema.Scene {
+	tags := probe.JSON.Format.Tags
+
+	ret := &jsonschema.Scene{
+		Title:      strings.TrimSpace(tags.Title),
+		Details:    strings.TrimSpace(tags.Comment),
+		Date:       audioTagDate(tags.Date),
+		Performers: splitAudioTag(tags.Artist),
+		Tags:       splitAudioTag(tags.Genre),
+	}
+
+	if len(ret.Performers) == 0 {
+		ret.Performers = splitAudioTag(tags.AlbumArtist)
+	}
+
+	if album := strings.TrimSpace(tags.Album); album != "" {
+		ret.Movies = []jsonschema.SceneMovie{{
+			MovieName:  album,
+			SceneIndex: audioTagTrack(tags.Track),
+		}}
+	}
+
+	return ret
+}
+
+func splitAudioTag(v string) []string {
+	return utils.StrAppendUniques(nil, trimAll(strings.Split(v, audioTagSeparator)))
+}
+
+// audioTagDate returns the date at the start of the date tag, or an empty
+// string if the tag has no full date, such as if it only has the year.
+func audioTagDate(v string) string {
+	const dateLen = len("2006-01-02")
+	v = strings.TrimSpace(v)
+	if len(v) < dateLen {
+		return ""
+	}
+
+	if _, err := time.Parse("2006-01-02", v[:dateLen]); err != nil {
+		return ""
+	}
+
+	return v[:dateLen]
+}
+
+// audioTagTrack returns the track number of a track tag, which may include
+// the number of tracks, such as 3/12. Returns zero if the tag is not set.
+func audioTagTrack(v string) int {
+	v = strings.TrimSpace(strings.SplitN(v, "/", 2)[0])
+	ret, _ := strconv.Atoi(v)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
@@ -0,0 +1,46 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioTagsToJSON(t *testing.T) {
+	probe := &ffmpeg.VideoFile{}
+	tags := &probe.JSON.Format.Tags
+	tags.Title = " title "
+	tags.Comment = "comment"
+	tags.Artist = "artist1; artist2;artist1"
+	tags.AlbumArtist = "album artist"
+	tags.Album = "album"
+	tags.Genre = "genre"
+	tags.Date = "2001-02-03T00:00:00"
+	tags.Track = "3/12"
+
+	assert.Equal(t, &jsonschema.Scene{
+		Title:      "title",
+		Details:    "comment",
+		Date:       "2001-02-03",
+		Performers: []string{"artist1", "artist2"},
+		Tags:       []string{"genre"},
+		Movies:     []jsonschema.SceneMovie{{MovieName: "album", SceneIndex: 3}},
+	}, AudioTagsToJSON(probe))
+
+	tags.Artist = ""
+	tags.Album = ""
+	tags.Date = "2001"
+	ret := AudioTagsToJSON(probe)
+	assert.Equal(t, []string{"album artist"}, ret.Performers)
+	assert.Empty(t, ret.Movies)
+	assert.Equal(t, "", ret.Date)
+}
+
+func TestAudioTagTrack(t *testing.T) {
+	assert.Equal(t, 0, audioTagTrack(""))
+	assert.Equal(t, 4, audioTagTrack("4"))
+	assert.Equal(t, 4, audioTagTrack(" 04/10"))
+	assert.Equal(t, 0, audioTagTrack("A1"))
+}
@@ -0,0 +1,149 @@
+package scene
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// captionCodecs are the codecs of external caption files, by extension.
+// The codec names are those reported by ffprobe.
+var captionCodecs = map[string]string{
+	".srt": "subrip",
+	".vtt": "webvtt",
+}
+
+// IsCaptionFile returns true if the file is a caption file which is read by
+// the scan.
+func IsCaptionFile(path string) bool {
+	_, found := captionCodecs[strings.ToLower(filepath.Ext(path))]
+	return found
+}
+
+// GetEmbeddedCaptions returns the captions of the text subtitle streams of
+// the probed scene file.
+func GetEmbeddedCaptions(probe *ffmpeg.VideoFile) []*models.SceneCaption {
+	var ret []*models.SceneCaption
+	for _, s := range probe.GetCaptionStreams() {
+		ret = append(ret, &models.SceneCaption{
+			StreamIndex:  sql.NullInt64{Int64: int64(s.Index), Valid: true},
+			LanguageCode: s.Language,
+			Title:        s.Title,
+			Codec:        s.Codec,
+		})
+	}
+
+	return ret
+}
+
+// GetExternalCaptions returns the caption files next to the scene file at
+// path. Caption files have the same name as the scene file, optionally
+// followed by a language code, such as scene.srt or scene.en.vtt.
+func GetExternalCaptions(path string) ([]*models.SceneCaption, error) {
+	names, err := ListCaptionFiles(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return MatchExternalCaptions(path, names), nil
+}
+
+// ListCaptionFiles returns the names of the caption files in dir, so that
+// the captions of each scene file in dir can be matched without reading dir
+// again.
+func ListCaptionFiles(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	for _, f := range files {
+		if !f.IsDir() && IsCaptionFile(f.Name()) {
+			ret = append(ret, f.Name())
+		}
+	}
+
+	return ret, nil
+}
+
+// MatchExternalCaptions returns the captions of the scene file at path from
+// the names of the caption files in its directory.
+func MatchExternalCaptions(path string, names []string) []*models.SceneCaption {
+	dir := filepath.Dir(path)
+
+	var ret []*models.SceneCaption
+	for _, fn := range names {
+		language, ok := matchCaptionFile(path, fn)
+		if !ok {
+			continue
+		}
+
+		ret = append(ret, &models.SceneCaption{
+			Path:         filepath.Join(dir, fn),
+			LanguageCode: language,
+			Codec:        captionCodecs[strings.ToLower(filepath.Ext(fn))],
+		})
+	}
+
+	return ret
+}
+
+// matchCaptionFile returns the language code of the caption file with the
+// name fn, and whether it is a caption file of the scene file at path.
+func matchCaptionFile(path string, fn string) (string, bool) {
+	if !IsCaptionFile(fn) {
+		return "", false
+	}
+
+	sceneBase := filepath.Base(path)
+	sceneBase = strings.TrimSuffix(sceneBase, filepath.Ext(sceneBase))
+	base := strings.TrimSuffix(fn, filepath.Ext(fn))
+
+	if base == sceneBase {
+		return "", true
+	}
+
+	language := strings.TrimPrefix(base, sceneBase+".")
+	if language == base || language == "" || strings.Contains(language, ".") {
+		return "", false
+	}
+
+	return language, true
+}
+
+// MergeCaptions returns the embedded captions of existing followed by the
+// external captions. This allows the external captions to be updated
+// without probing the scene file.
+func MergeCaptions(existing []*models.SceneCaption, external []*models.SceneCaption) []*models.SceneCaption {
+	var ret []*models.SceneCaption
+	for _, c := range existing {
+		if c.Embedded() {
+			ret = append(ret, c)
+		}
+	}
+
+	return append(ret, external...)
+}
+
+// CaptionsChanged returns true if the captions differ, ignoring the IDs.
+func CaptionsChanged(existing []*models.SceneCaption, captions []*models.SceneCaption) bool {
+	if len(existing) != len(captions) {
+		return true
+	}
+
+	for i, c := range existing {
+		o := *captions[i]
+		o.ID = c.ID
+		o.SceneID = c.SceneID
+		if *c != o {
+			return true
+		}
+	}
+
+	return false
+}
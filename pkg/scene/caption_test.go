@@ -0,0 +1,99 @@
+package scene
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchCaptionFile(t *testing.T) {
+	const scenePath = "/videos/scene.name.mp4"
+
+	tests := []struct {
+		fn       string
+		language string
+		match    bool
+	}{
+		{"scene.name.srt", "", true},
+		{"scene.name.VTT", "", true},
+		{"scene.name.en.srt", "en", true},
+		{"scene.name.pt-BR.vtt", "pt-BR", true},
+		{"scene.name.en.forced.srt", "", false},
+		{"scene.name..srt", "", false},
+		{"scene.name.nfo", "", false},
+		{"scene.srt", "", false},
+		{"other.srt", "", false},
+		{"scene.namex.srt", "", false},
+	}
+
+	for _, tt := range tests {
+		language, match := matchCaptionFile(scenePath, tt.fn)
+		assert.Equal(t, tt.match, match, tt.fn)
+		assert.Equal(t, tt.language, language, tt.fn)
+	}
+}
+
+func TestGetExternalCaptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-caption")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, fn := range []string{"scene.mp4", "scene.en.srt", "scene.vtt", "scene.nfo", "other.srt"} {
+		writeSidecarTestFile(t, filepath.Join(dir, fn), "")
+	}
+
+	ret, err := GetExternalCaptions(filepath.Join(dir, "scene.mp4"))
+	assert.Nil(t, err)
+	assert.Equal(t, []*models.SceneCaption{
+		{
+			Path:         filepath.Join(dir, "scene.en.srt"),
+			LanguageCode: "en",
+			Codec:        "subrip",
+		},
+		{
+			Path:  filepath.Join(dir, "scene.vtt"),
+			Codec: "webvtt",
+		},
+	}, ret)
+}
+
+func TestMergeCaptions(t *testing.T) {
+	embedded := &models.SceneCaption{
+		ID:          1,
+		SceneID:     1,
+		StreamIndex: sql.NullInt64{Int64: 2, Valid: true},
+		Codec:       "subrip",
+	}
+	oldExternal := &models.SceneCaption{
+		ID:      2,
+		SceneID: 1,
+		Path:    "scene.srt",
+		Codec:   "subrip",
+	}
+	existing := []*models.SceneCaption{embedded, oldExternal}
+
+	external := []*models.SceneCaption{
+		{
+			Path:  "scene.srt",
+			Codec: "subrip",
+		},
+	}
+
+	merged := MergeCaptions(existing, external)
+	assert.Equal(t, []*models.SceneCaption{embedded, external[0]}, merged)
+	assert.False(t, CaptionsChanged(existing, merged))
+
+	external = append(external, &models.SceneCaption{
+		Path:  "scene.vtt",
+		Codec: "webvtt",
+	})
+	assert.True(t, CaptionsChanged(existing, MergeCaptions(existing, external)))
+	assert.True(t, CaptionsChanged(existing, MergeCaptions(existing, nil)))
+}
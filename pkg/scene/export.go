@@ -54,6 +54,15 @@ func ToBasicJSON(reader models.SceneReader, scene *models.Scene) (*jsonschema.Sc
 		newSceneJSON.Details = scene.Details.String
 	}
 
+	if scene.Location.Valid {
+		newSceneJSON.Location = scene.Location.String
+	}
+
+	if scene.Latitude.Valid && scene.Longitude.Valid {
+		newSceneJSON.Latitude = &scene.Latitude.Float64
+		newSceneJSON.Longitude = &scene.Longitude.Float64
+	}
+
 	newSceneJSON.File = getSceneFileJSON(scene)
 
 	cover, err := reader.GetCover(scene.ID)
@@ -65,6 +74,15 @@ func ToBasicJSON(reader models.SceneReader, scene *models.Scene) (*jsonschema.Sc
 		newSceneJSON.Cover = utils.GetBase64StringFromData(cover)
 	}
 
+	stashIDs, err := reader.GetStashIDs(scene.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scene stash ids: %s", err.Error())
+	}
+
+	for _, stashID := range stashIDs {
+		newSceneJSON.StashIDs = append(newSceneJSON.StashIDs, *stashID)
+	}
+
 	return &newSceneJSON, nil
 }
 
@@ -111,6 +129,9 @@ func getSceneFileJSON(scene *models.Scene) *jsonschema.SceneFile {
 		ret.Bitrate = int(scene.Bitrate.Int64)
 	}
 
+	ret.AudioOnly = scene.AudioOnly
+	ret.Interactive = scene.Interactive
+
 	return ret
 }
 
@@ -85,6 +85,11 @@ var names = []string{
 
 var imageBytes = []byte("imageBytes")
 
+var stashID = models.StashID{
+	StashID:  "StashID",
+	Endpoint: "Endpoint",
+}
+
 const image = "aW1hZ2VCeXRlcw=="
 
 var createTime time.Time = time.Date(2001, 01, 01, 0, 0, 0, 0, time.UTC)
@@ -172,6 +177,9 @@ func createFullJSONScene(image string) *jsonschema.Scene {
 			Time: updateTime,
 		},
 		Cover: image,
+		StashIDs: []models.StashID{
+			stashID,
+		},
 	}
 }
 
@@ -220,6 +228,9 @@ func TestToJSON(t *testing.T) {
 	mockSceneReader.On("GetCover", noImageID).Return(nil, nil).Once()
 	mockSceneReader.On("GetCover", errImageID).Return(nil, imageErr).Once()
 
+	mockSceneReader.On("GetStashIDs", sceneID).Return([]*models.StashID{&stashID}, nil).Once()
+	mockSceneReader.On("GetStashIDs", noImageID).Return(nil, nil).Once()
+
 	for i, s := range scenarios {
 		scene := s.input
 		json, err := ToBasicJSON(mockSceneReader, &scene)
@@ -0,0 +1,116 @@
+package scene
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// Funscript is the script of the moves of an interactive device during a
+// scene.
+type Funscript struct {
+	Version  string            `json:"version"`
+	Inverted bool              `json:"inverted"`
+	Range    int               `json:"range"`
+	Actions  []FunscriptAction `json:"actions"`
+}
+
+// FunscriptAction moves the device to the position Pos, from 0 to 100, at
+// the time At in milliseconds.
+type FunscriptAction struct {
+	At  float64 `json:"at"`
+	Pos float64 `json:"pos"`
+}
+
+// GetFunscriptPath returns the path of the funscript file of the scene file
+// at path. The funscript file has the same name as the scene file, with the
+// .funscript extension.
+func GetFunscriptPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".funscript"
+}
+
+// IsInteractive returns true if there is a funscript file next to the scene
+// file at path.
+func IsInteractive(path string) bool {
+	exists, _ := utils.FileExists(GetFunscriptPath(path))
+	return exists
+}
+
+// ParseFunscript reads the funscript file at path. The actions are sorted
+// by time.
+func ParseFunscript(path string) (*Funscript, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret Funscript
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("error parsing funscript %s: %s", path, err.Error())
+	}
+
+	sort.SliceStable(ret.Actions, func(i, j int) bool {
+		return ret.Actions[i].At < ret.Actions[j].At
+	})
+
+	return &ret, nil
+}
+
+// FunscriptMove is the move of the device between two consecutive actions,
+// from the time Start to End in milliseconds. Speed is in positions per
+// second, and is 0 for pauses.
+type FunscriptMove struct {
+	Start float64
+	End   float64
+	Speed float64
+}
+
+// Moves returns the moves between the actions of the funscript. Actions at
+// the same time as the previous action are skipped.
+func (f *Funscript) Moves() []FunscriptMove {
+	var ret []FunscriptMove
+	for i := 1; i < len(f.Actions); i++ {
+		a, b := f.Actions[i-1], f.Actions[i]
+		duration := b.At - a.At
+		if duration <= 0 {
+			continue
+		}
+
+		ret = append(ret, FunscriptMove{
+			Start: a.At,
+			End:   b.At,
+			Speed: math.Abs(b.Pos-a.Pos) * 1000 / duration,
+		})
+	}
+
+	return ret
+}
+
+// MedianSpeed returns the median speed of the moves of the funscript, in
+// positions per second. Pauses are not counted. Returns 0 if the funscript
+// has no moves.
+func (f *Funscript) MedianSpeed() int {
+	var speeds []float64
+	for _, m := range f.Moves() {
+		if m.Speed > 0 {
+			speeds = append(speeds, m.Speed)
+		}
+	}
+
+	if len(speeds) == 0 {
+		return 0
+	}
+
+	sort.Float64s(speeds)
+	mid := len(speeds) / 2
+	if len(speeds)%2 == 0 {
+		return int(math.Round((speeds[mid-1] + speeds[mid]) / 2))
+	}
+	return int(math.Round(speeds[mid]))
+}
@@ -0,0 +1,86 @@
+package scene
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFunscriptPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("dir", "scene.funscript"), GetFunscriptPath(filepath.Join("dir", "scene.mp4")))
+	assert.Equal(t, filepath.Join("dir", "scene.1.funscript"), GetFunscriptPath(filepath.Join("dir", "scene.1.mkv")))
+}
+
+func TestIsInteractive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "funscript")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "scene.mp4")
+	assert.False(t, IsInteractive(path))
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "scene.funscript"), []byte(`{"actions":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, IsInteractive(path))
+	assert.False(t, IsInteractive(filepath.Join(dir, "other.mp4")))
+}
+
+func TestParseFunscript(t *testing.T) {
+	dir, err := ioutil.TempDir("", "funscript")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "scene.funscript")
+	data := `{"version":"1.0","inverted":false,"range":90,"actions":[{"at":500,"pos":100},{"at":0,"pos":0},{"at":1000.5,"pos":50}]}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	script, err := ParseFunscript(path)
+	if assert.Nil(t, err) {
+		assert.Equal(t, 90, script.Range)
+		assert.Equal(t, []FunscriptAction{{0, 0}, {500, 100}, {1000.5, 50}}, script.Actions)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseFunscript(path)
+	assert.NotNil(t, err)
+
+	_, err = ParseFunscript(filepath.Join(dir, "missing.funscript"))
+	assert.NotNil(t, err)
+}
+
+func TestFunscriptMedianSpeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []FunscriptAction
+		want    int
+	}{
+		{"no actions", nil, 0},
+		{"one action", []FunscriptAction{{0, 50}}, 0},
+		// 200, 100 and 50 positions per second
+		{"odd", []FunscriptAction{{0, 0}, {500, 100}, {1500, 0}, {2500, 50}}, 100},
+		// the pause is not a move
+		{"even", []FunscriptAction{{0, 0}, {500, 100}, {1000, 100}, {2000, 0}}, 150},
+		// actions at the same time are skipped
+		{"same time", []FunscriptAction{{0, 0}, {0, 100}, {1000, 0}}, 100},
+	}
+
+	for _, tt := range tests {
+		f := &Funscript{Actions: tt.actions}
+		assert.Equal(t, tt.want, f.MedianSpeed(), tt.name)
+	}
+
+	f := &Funscript{Actions: []FunscriptAction{{0, 0}, {0, 100}, {1000, 100}, {1250, 50}}}
+	assert.Equal(t, []FunscriptMove{{0, 1000, 0}, {1000, 1250, 200}}, f.Moves())
+}
@@ -0,0 +1,407 @@
+package scene
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// Identifier sets the metadata of scenes from the scenes they were
+// identified as by a stash-box endpoint. Each field is set according to its
+// strategy in FieldOptions, and is merged if it has none. The studio,
+// performers and tags of the identified scene are found by name as when
+// importing, and are created if CreateMissing is set.
+type Identifier struct {
+	ReaderWriter    models.SceneReaderWriter
+	StudioWriter    models.StudioReaderWriter
+	PerformerWriter models.PerformerReaderWriter
+	TagWriter       models.TagReaderWriter
+
+	// Endpoint is the stash-box endpoint which identified the scenes.
+	Endpoint string
+
+	FieldOptions  []*models.IdentifyFieldOptionsInput
+	CreateMissing bool
+	SetOrganized  bool
+}
+
+func (i *Identifier) strategy(field models.IdentifyField) models.IdentifyFieldStrategy {
+	for _, o := range i.FieldOptions {
+		if o.Field == field {
+			return o.Strategy
+		}
+	}
+
+	return models.IdentifyFieldStrategyMerge
+}
+
+// Identify sets the metadata of the scene from the identified scene.
+// Returns true if the scene was changed.
+func (i *Identifier) Identify(s *models.Scene, identified *models.ScrapedScene) (bool, error) {
+	importer := i.importer(identified)
+	if err := importer.PreImport(); err != nil {
+		return false, err
+	}
+
+	// the studio is matched by name when scraped
+	studioID := importer.scene.StudioID
+	if identified.Studio != nil && identified.Studio.ID != nil {
+		id, err := strconv.Atoi(*identified.Studio.ID)
+		if err != nil {
+			return false, fmt.Errorf("invalid studio id %s: %s", *identified.Studio.ID, err.Error())
+		}
+		studioID = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	partial := models.ScenePartial{
+		ID:       s.ID,
+		Title:    identifyNullString(i.strategy(models.IdentifyFieldTitle), s.Title, importer.scene.Title),
+		Details:  identifyNullString(i.strategy(models.IdentifyFieldDetails), s.Details, importer.scene.Details),
+		URL:      identifyNullString(i.strategy(models.IdentifyFieldURL), s.URL, importer.scene.URL),
+		Date:     identifySQLiteDate(i.strategy(models.IdentifyFieldDate), s.Date, importer.scene.Date),
+		StudioID: identifyNullInt64(i.strategy(models.IdentifyFieldStudio), s.StudioID, studioID),
+	}
+
+	if i.SetOrganized && !s.Organized {
+		organized := true
+		partial.Organized = &organized
+	}
+
+	changed := false
+	if partial != (models.ScenePartial{ID: s.ID}) {
+		partial.UpdatedAt = &models.SQLiteTimestamp{Timestamp: time.Now()}
+		if _, err := i.ReaderWriter.Update(partial); err != nil {
+			return false, fmt.Errorf("error updating scene: %s", err.Error())
+		}
+		changed = true
+	}
+
+	var matched []*string
+	for _, p := range identified.Performers {
+		matched = append(matched, p.ID)
+	}
+	performerIDs, err := matchedIDs(matched)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range importer.performers {
+		performerIDs = utils.IntAppendUnique(performerIDs, p.ID)
+	}
+
+	updated, err := i.updateIDs(i.strategy(models.IdentifyFieldPerformers), s.ID, performerIDs, i.ReaderWriter.GetPerformerIDs, i.ReaderWriter.UpdatePerformers)
+	if err != nil {
+		return false, fmt.Errorf("error updating scene performers: %s", err.Error())
+	}
+	changed = changed || updated
+
+	matched = nil
+	for _, t := range identified.Tags {
+		matched = append(matched, t.ID)
+	}
+	tagIDs, err := matchedIDs(matched)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range importer.tags {
+		tagIDs = utils.IntAppendUnique(tagIDs, t.ID)
+	}
+
+	updated, err = i.updateIDs(i.strategy(models.IdentifyFieldTags), s.ID, tagIDs, i.ReaderWriter.GetTagIDs, i.ReaderWriter.UpdateTags)
+	if err != nil {
+		return false, fmt.Errorf("error updating scene tags: %s", err.Error())
+	}
+	changed = changed || updated
+
+	updated, err = i.updateCover(s.ID, importer.coverImageData)
+	if err != nil {
+		return false, err
+	}
+	changed = changed || updated
+
+	updated, err = i.updateStashID(s.ID, identified.RemoteSiteID)
+	if err != nil {
+		return false, err
+	}
+
+	return changed || updated, nil
+}
+
+// importer returns an importer resolving the studio, performers and tags of
+// the identified scene which were not matched when scraping, and decoding
+// its cover image. Fields which are ignored are not resolved, so that their
+// missing objects are not created.
+func (i *Identifier) importer(identified *models.ScrapedScene) *Importer {
+	input := jsonschema.Scene{}
+	if identified.Title != nil {
+		input.Title = *identified.Title
+	}
+	if identified.Details != nil {
+		input.Details = *identified.Details
+	}
+	if identified.URL != nil {
+		input.URL = *identified.URL
+	}
+	if identified.Date != nil {
+		input.Date = *identified.Date
+	}
+
+	if identified.Studio != nil && identified.Studio.ID == nil && i.strategy(models.IdentifyFieldStudio) != models.IdentifyFieldStrategyIgnore {
+		input.Studio = identified.Studio.Name
+	}
+
+	if i.strategy(models.IdentifyFieldPerformers) != models.IdentifyFieldStrategyIgnore {
+		for _, p := range identified.Performers {
+			if p.ID == nil {
+				input.Performers = append(input.Performers, p.Name)
+			}
+		}
+	}
+
+	if i.strategy(models.IdentifyFieldTags) != models.IdentifyFieldStrategyIgnore {
+		for _, t := range identified.Tags {
+			if t.ID == nil {
+				input.Tags = append(input.Tags, t.Name)
+			}
+		}
+	}
+
+	if identified.Image != nil && i.strategy(models.IdentifyFieldCoverImage) != models.IdentifyFieldStrategyIgnore {
+		input.Cover = *identified.Image
+	}
+
+	missingRefBehaviour := models.ImportMissingRefEnumIgnore
+	if i.CreateMissing {
+		missingRefBehaviour = models.ImportMissingRefEnumCreate
+	}
+
+	return &Importer{
+		ReaderWriter:        i.ReaderWriter,
+		StudioWriter:        i.StudioWriter,
+		PerformerWriter:     i.PerformerWriter,
+		TagWriter:           i.TagWriter,
+		Input:               input,
+		MissingRefBehaviour: missingRefBehaviour,
+	}
+}
+
+// updateIDs sets the related objects of the scene, such as its performers,
+// according to the strategy. The objects are left unchanged if ids is
+// empty. Returns true if they were changed.
+func (i *Identifier) updateIDs(strategy models.IdentifyFieldStrategy, sceneID int, ids []int, get func(int) ([]int, error), update func(int, []int) error) (bool, error) {
+	if len(ids) == 0 || strategy == models.IdentifyFieldStrategyIgnore {
+		return false, nil
+	}
+
+	existing, err := get(sceneID)
+	if err != nil {
+		return false, err
+	}
+
+	if strategy == models.IdentifyFieldStrategyMerge {
+		ids = utils.IntAppendUniques(existing, ids)
+	}
+
+	if sameIDs(existing, ids) {
+		return false, nil
+	}
+
+	if err := update(sceneID, ids); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (i *Identifier) updateCover(sceneID int, cover []byte) (bool, error) {
+	if len(cover) == 0 {
+		return false, nil
+	}
+
+	if i.strategy(models.IdentifyFieldCoverImage) == models.IdentifyFieldStrategyMerge {
+		existing, err := i.ReaderWriter.GetCover(sceneID)
+		if err != nil {
+			return false, fmt.Errorf("error getting scene cover: %s", err.Error())
+		}
+
+		if len(existing) > 0 {
+			return false, nil
+		}
+	}
+
+	if err := i.ReaderWriter.UpdateCover(sceneID, cover); err != nil {
+		return false, fmt.Errorf("error setting scene cover: %s", err.Error())
+	}
+
+	return true, nil
+}
+
+// updateStashID sets the stash ID of the scene for the endpoint, replacing
+// any existing stash ID for the endpoint.
+func (i *Identifier) updateStashID(sceneID int, remoteSiteID *string) (bool, error) {
+	if remoteSiteID == nil || i.strategy(models.IdentifyFieldStashIDS) == models.IdentifyFieldStrategyIgnore {
+		return false, nil
+	}
+
+	existing, err := i.ReaderWriter.GetStashIDs(sceneID)
+	if err != nil {
+		return false, fmt.Errorf("error getting scene stash ids: %s", err.Error())
+	}
+
+	for _, e := range existing {
+		if e.Endpoint == i.Endpoint && e.StashID == *remoteSiteID {
+			return false, nil
+		}
+	}
+
+	stashIDs := models.MergeStashIDs(existing, []models.StashID{{
+		Endpoint: i.Endpoint,
+		StashID:  *remoteSiteID,
+	}})
+	if err := i.ReaderWriter.UpdateStashIDs(sceneID, stashIDs); err != nil {
+		return false, fmt.Errorf("error setting scene stash ids: %s", err.Error())
+	}
+
+	return true, nil
+}
+
+// MatchIdentified returns the scene of results which shares the most
+// fingerprints with the scene, or nil if none share a fingerprint or more
+// than one share the most.
+func MatchIdentified(s *models.Scene, results []*models.ScrapedScene) *models.ScrapedScene {
+	var hashes []string
+	if s.Checksum.Valid {
+		hashes = append(hashes, s.Checksum.String)
+	}
+	if s.OSHash.Valid {
+		hashes = append(hashes, s.OSHash.String)
+	}
+	if s.Phash.Valid {
+		hashes = append(hashes, utils.PhashToString(s.Phash.Int64))
+	}
+
+	var ret *models.ScrapedScene
+	most := 0
+	tied := false
+	for _, r := range results {
+		matches := 0
+		for _, fp := range r.Fingerprints {
+			if utils.StrInclude(hashes, fp.Hash) {
+				matches++
+			}
+		}
+
+		switch {
+		case matches == 0:
+		case matches > most:
+			ret = r
+			most = matches
+			tied = false
+		case matches == most:
+			tied = true
+		}
+	}
+
+	if tied {
+		return nil
+	}
+
+	return ret
+}
+
+func identifyNullString(strategy models.IdentifyFieldStrategy, existing sql.NullString, value sql.NullString) *sql.NullString {
+	if !value.Valid {
+		return nil
+	}
+
+	ret := existing
+	switch strategy {
+	case models.IdentifyFieldStrategyMerge:
+		models.FillNullString(&ret, value)
+	case models.IdentifyFieldStrategyOverwrite:
+		ret = value
+	}
+
+	if ret == existing {
+		return nil
+	}
+
+	return &ret
+}
+
+func identifyNullInt64(strategy models.IdentifyFieldStrategy, existing sql.NullInt64, value sql.NullInt64) *sql.NullInt64 {
+	if !value.Valid {
+		return nil
+	}
+
+	ret := existing
+	switch strategy {
+	case models.IdentifyFieldStrategyMerge:
+		models.FillNullInt64(&ret, value)
+	case models.IdentifyFieldStrategyOverwrite:
+		ret = value
+	}
+
+	if ret == existing {
+		return nil
+	}
+
+	return &ret
+}
+
+func identifySQLiteDate(strategy models.IdentifyFieldStrategy, existing models.SQLiteDate, value models.SQLiteDate) *models.SQLiteDate {
+	if !value.Valid {
+		return nil
+	}
+
+	ret := existing
+	switch strategy {
+	case models.IdentifyFieldStrategyMerge:
+		models.FillSQLiteDate(&ret, value)
+	case models.IdentifyFieldStrategyOverwrite:
+		ret = value
+	}
+
+	if ret == existing {
+		return nil
+	}
+
+	return &ret
+}
+
+// matchedIDs returns the IDs of the scraped objects which were matched to
+// existing objects. The IDs of unmatched objects are nil.
+func matchedIDs(ids []*string) ([]int, error) {
+	var ret []int
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+
+		v, err := strconv.Atoi(*id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %s: %s", *id, err.Error())
+		}
+		ret = utils.IntAppendUnique(ret, v)
+	}
+
+	return ret, nil
+}
+
+func sameIDs(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, v := range a {
+		if !utils.IntInclude(b, v) {
+			return false
+		}
+	}
+
+	return true
+}
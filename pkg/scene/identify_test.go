@@ -0,0 +1,196 @@
+package scene
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	identifySceneID  = 1
+	identifyEndpoint = "https://stashdb.org/graphql"
+	identifyRemoteID = "remoteID"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestMatchIdentified(t *testing.T) {
+	s := &models.Scene{
+		Checksum: sql.NullString{String: "checksum", Valid: true},
+		OSHash:   sql.NullString{String: "oshash", Valid: true},
+		Phash:    sql.NullInt64{Int64: 0x1234, Valid: true},
+	}
+
+	fingerprints := func(hashes ...string) []*models.StashBoxFingerprint {
+		var ret []*models.StashBoxFingerprint
+		for _, h := range hashes {
+			ret = append(ret, &models.StashBoxFingerprint{Hash: h})
+		}
+		return ret
+	}
+
+	none := &models.ScrapedScene{Fingerprints: fingerprints("other")}
+	one := &models.ScrapedScene{Fingerprints: fingerprints("oshash")}
+	two := &models.ScrapedScene{Fingerprints: fingerprints("oshash", "1234")}
+	otherTwo := &models.ScrapedScene{Fingerprints: fingerprints("checksum", "oshash")}
+
+	assert.Nil(t, MatchIdentified(s, nil))
+	assert.Nil(t, MatchIdentified(s, []*models.ScrapedScene{none}))
+	assert.Equal(t, one, MatchIdentified(s, []*models.ScrapedScene{none, one}))
+	assert.Equal(t, two, MatchIdentified(s, []*models.ScrapedScene{one, two}))
+
+	// ambiguous matches are not used
+	assert.Nil(t, MatchIdentified(s, []*models.ScrapedScene{two, otherTwo}))
+	assert.Nil(t, MatchIdentified(s, []*models.ScrapedScene{one, one}))
+}
+
+func TestIdentifyMerge(t *testing.T) {
+	sceneReaderWriter := &mocks.SceneReaderWriter{}
+	performerReaderWriter := &mocks.PerformerReaderWriter{}
+	tagReaderWriter := &mocks.TagReaderWriter{}
+
+	i := Identifier{
+		ReaderWriter:    sceneReaderWriter,
+		PerformerWriter: performerReaderWriter,
+		TagWriter:       tagReaderWriter,
+		Endpoint:        identifyEndpoint,
+	}
+
+	s := &models.Scene{
+		ID:    identifySceneID,
+		Title: sql.NullString{String: "existing", Valid: true},
+	}
+
+	identified := &models.ScrapedScene{
+		Title:   strPtr("title"),
+		Details: strPtr("details"),
+		Date:    strPtr("2001-02-03"),
+		Studio: &models.ScrapedSceneStudio{
+			ID:   strPtr("5"),
+			Name: "studio",
+		},
+		Performers: []*models.ScrapedScenePerformer{
+			{ID: strPtr("10"), Name: "matched"},
+			{Name: "missing"},
+		},
+		Tags: []*models.ScrapedSceneTag{
+			{Name: "tag"},
+		},
+		RemoteSiteID: strPtr(identifyRemoteID),
+	}
+
+	sceneReaderWriter.On("Update", mock.MatchedBy(func(p models.ScenePartial) bool {
+		return p.ID == identifySceneID && p.Title == nil && p.Organized == nil &&
+			*p.Details == sql.NullString{String: "details", Valid: true} &&
+			*p.Date == models.SQLiteDate{String: "2001-02-03", Valid: true} &&
+			*p.StudioID == sql.NullInt64{Int64: 5, Valid: true}
+	})).Return(nil, nil).Once()
+
+	// missing performers are ignored
+	performerReaderWriter.On("FindByNames", []string{"missing"}, false).Return(nil, nil).Once()
+	sceneReaderWriter.On("GetPerformerIDs", identifySceneID).Return([]int{11}, nil).Once()
+	sceneReaderWriter.On("UpdatePerformers", identifySceneID, []int{11, 10}).Return(nil).Once()
+
+	// the tag is already set
+	tagReaderWriter.On("FindByNames", []string{"tag"}, false).Return([]*models.Tag{
+		{ID: 20, Name: "tag"},
+	}, nil).Once()
+	sceneReaderWriter.On("GetTagIDs", identifySceneID).Return([]int{20}, nil).Once()
+
+	sceneReaderWriter.On("GetStashIDs", identifySceneID).Return([]*models.StashID{
+		{Endpoint: "other", StashID: "otherID"},
+	}, nil).Once()
+	sceneReaderWriter.On("UpdateStashIDs", identifySceneID, []models.StashID{
+		{Endpoint: "other", StashID: "otherID"},
+		{Endpoint: identifyEndpoint, StashID: identifyRemoteID},
+	}).Return(nil).Once()
+
+	changed, err := i.Identify(s, identified)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	sceneReaderWriter.AssertExpectations(t)
+	performerReaderWriter.AssertExpectations(t)
+	tagReaderWriter.AssertExpectations(t)
+}
+
+func TestIdentifyStrategies(t *testing.T) {
+	sceneReaderWriter := &mocks.SceneReaderWriter{}
+
+	i := Identifier{
+		ReaderWriter: sceneReaderWriter,
+		Endpoint:     identifyEndpoint,
+		FieldOptions: []*models.IdentifyFieldOptionsInput{
+			{Field: models.IdentifyFieldTitle, Strategy: models.IdentifyFieldStrategyOverwrite},
+			{Field: models.IdentifyFieldTags, Strategy: models.IdentifyFieldStrategyOverwrite},
+			{Field: models.IdentifyFieldPerformers, Strategy: models.IdentifyFieldStrategyIgnore},
+			{Field: models.IdentifyFieldStashIDS, Strategy: models.IdentifyFieldStrategyIgnore},
+		},
+		SetOrganized: true,
+	}
+
+	s := &models.Scene{
+		ID:    identifySceneID,
+		Title: sql.NullString{String: "existing", Valid: true},
+	}
+
+	identified := &models.ScrapedScene{
+		Title: strPtr("title"),
+		Performers: []*models.ScrapedScenePerformer{
+			{ID: strPtr("10"), Name: "performer"},
+		},
+		Tags: []*models.ScrapedSceneTag{
+			{ID: strPtr("20"), Name: "tag"},
+		},
+		RemoteSiteID: strPtr(identifyRemoteID),
+	}
+
+	sceneReaderWriter.On("Update", mock.MatchedBy(func(p models.ScenePartial) bool {
+		return p.ID == identifySceneID && *p.Organized &&
+			*p.Title == sql.NullString{String: "title", Valid: true}
+	})).Return(nil, nil).Once()
+
+	sceneReaderWriter.On("GetTagIDs", identifySceneID).Return([]int{21}, nil).Once()
+	sceneReaderWriter.On("UpdateTags", identifySceneID, []int{20}).Return(nil).Once()
+
+	changed, err := i.Identify(s, identified)
+	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	sceneReaderWriter.AssertExpectations(t)
+}
+
+func TestIdentifyUnchanged(t *testing.T) {
+	sceneReaderWriter := &mocks.SceneReaderWriter{}
+
+	i := Identifier{
+		ReaderWriter: sceneReaderWriter,
+		Endpoint:     identifyEndpoint,
+	}
+
+	s := &models.Scene{
+		ID:    identifySceneID,
+		Title: sql.NullString{String: "existing", Valid: true},
+	}
+
+	identified := &models.ScrapedScene{
+		Title:        strPtr("title"),
+		RemoteSiteID: strPtr(identifyRemoteID),
+	}
+
+	sceneReaderWriter.On("GetStashIDs", identifySceneID).Return([]*models.StashID{
+		{Endpoint: identifyEndpoint, StashID: identifyRemoteID},
+	}, nil).Once()
+
+	changed, err := i.Identify(s, identified)
+	assert.Nil(t, err)
+	assert.False(t, changed)
+
+	sceneReaderWriter.AssertExpectations(t)
+}
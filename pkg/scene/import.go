@@ -30,6 +30,12 @@ type Importer struct {
 	movies         []models.MoviesScenes
 	tags           []*models.Tag
 	coverImageData []byte
+
+	// relationships of the existing scene when merging
+	existingGalleryIDs   []int
+	existingPerformerIDs []int
+	existingMovies       []models.MoviesScenes
+	existingTagIDs       []int
 }
 
 func (i *Importer) PreImport() error {
@@ -93,6 +99,13 @@ func (i *Importer) sceneJSONToScene(sceneJSON jsonschema.Scene) models.Scene {
 	if sceneJSON.Rating != 0 {
 		newScene.Rating = sql.NullInt64{Int64: int64(sceneJSON.Rating), Valid: true}
 	}
+	if sceneJSON.Location != "" {
+		newScene.Location = sql.NullString{String: sceneJSON.Location, Valid: true}
+	}
+	if sceneJSON.Latitude != nil && sceneJSON.Longitude != nil {
+		newScene.Latitude = sql.NullFloat64{Float64: *sceneJSON.Latitude, Valid: true}
+		newScene.Longitude = sql.NullFloat64{Float64: *sceneJSON.Longitude, Valid: true}
+	}
 
 	newScene.Organized = sceneJSON.Organized
 	newScene.OCounter = sceneJSON.OCounter
@@ -129,6 +142,8 @@ func (i *Importer) sceneJSONToScene(sceneJSON jsonschema.Scene) models.Scene {
 		if sceneJSON.File.Bitrate != 0 {
 			newScene.Bitrate = sql.NullInt64{Int64: int64(sceneJSON.File.Bitrate), Valid: true}
 		}
+		newScene.AudioOnly = sceneJSON.File.AudioOnly
+		newScene.Interactive = sceneJSON.File.Interactive
 	}
 
 	return newScene
@@ -350,6 +365,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, gallery := range i.galleries {
 			galleryIDs = append(galleryIDs, gallery.ID)
 		}
+		galleryIDs = utils.IntAppendUniques(i.existingGalleryIDs, galleryIDs)
 
 		if err := i.ReaderWriter.UpdateGalleries(id, galleryIDs); err != nil {
 			return fmt.Errorf("failed to associate galleries: %s", err.Error())
@@ -361,6 +377,7 @@ func (i *Importer) PostImport(id int) error {
 		for _, performer := range i.performers {
 			performerIDs = append(performerIDs, performer.ID)
 		}
+		performerIDs = utils.IntAppendUniques(i.existingPerformerIDs, performerIDs)
 
 		if err := i.ReaderWriter.UpdatePerformers(id, performerIDs); err != nil {
 			return fmt.Errorf("failed to associate performers: %s", err.Error())
@@ -371,7 +388,7 @@ func (i *Importer) PostImport(id int) error {
 		for index := range i.movies {
 			i.movies[index].SceneID = id
 		}
-		if err := i.ReaderWriter.UpdateMovies(id, i.movies); err != nil {
+		if err := i.ReaderWriter.UpdateMovies(id, mergeSceneMovies(i.existingMovies, i.movies)); err != nil {
 			return fmt.Errorf("failed to associate movies: %s", err.Error())
 		}
 	}
@@ -381,11 +398,34 @@ func (i *Importer) PostImport(id int) error {
 		for _, t := range i.tags {
 			tagIDs = append(tagIDs, t.ID)
 		}
+		tagIDs = utils.IntAppendUniques(i.existingTagIDs, tagIDs)
 		if err := i.ReaderWriter.UpdateTags(id, tagIDs); err != nil {
 			return fmt.Errorf("failed to associate tags: %s", err.Error())
 		}
 	}
 
+	if len(i.Input.StashIDs) > 0 {
+		if err := i.updateStashIDs(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateStashIDs merges the stash IDs from the input with the existing stash
+// IDs of the scene, so that stash IDs for other endpoints are retained when
+// overwriting.
+func (i *Importer) updateStashIDs(id int) error {
+	existing, err := i.ReaderWriter.GetStashIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting scene stash ids: %s", err.Error())
+	}
+
+	if err := i.ReaderWriter.UpdateStashIDs(id, models.MergeStashIDs(existing, i.Input.StashIDs)); err != nil {
+		return fmt.Errorf("error setting scene stash ids: %s", err.Error())
+	}
+
 	return nil
 }
 
@@ -439,6 +479,114 @@ func (i *Importer) Update(id int) error {
 	return nil
 }
 
+// Merge sets the missing fields and cover of the existing scene. The
+// organized flag and o-counter of the existing scene are kept.
+func (i *Importer) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing scene: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing scene with id %d not found", id)
+	}
+
+	scene := *existing
+	models.FillNullString(&scene.Checksum, i.scene.Checksum)
+	models.FillNullString(&scene.OSHash, i.scene.OSHash)
+	models.FillNullString(&scene.Title, i.scene.Title)
+	models.FillNullString(&scene.Details, i.scene.Details)
+	models.FillNullString(&scene.URL, i.scene.URL)
+	models.FillSQLiteDate(&scene.Date, i.scene.Date)
+	models.FillNullInt64(&scene.Rating, i.scene.Rating)
+	models.FillNullString(&scene.Size, i.scene.Size)
+	models.FillNullFloat64(&scene.Duration, i.scene.Duration)
+	models.FillNullString(&scene.VideoCodec, i.scene.VideoCodec)
+	models.FillNullString(&scene.Format, i.scene.Format)
+	models.FillNullString(&scene.AudioCodec, i.scene.AudioCodec)
+	models.FillNullInt64(&scene.Width, i.scene.Width)
+	models.FillNullInt64(&scene.Height, i.scene.Height)
+	models.FillNullFloat64(&scene.Framerate, i.scene.Framerate)
+	models.FillNullInt64(&scene.Bitrate, i.scene.Bitrate)
+	models.FillNullInt64(&scene.StudioID, i.scene.StudioID)
+	models.FillNullInt64(&scene.Phash, i.scene.Phash)
+	models.FillNullString(&scene.Location, i.scene.Location)
+	if !scene.Latitude.Valid || !scene.Longitude.Valid {
+		scene.Latitude = i.scene.Latitude
+		scene.Longitude = i.scene.Longitude
+	}
+
+	i.ID = id
+	if _, err := i.ReaderWriter.UpdateFull(scene); err != nil {
+		return fmt.Errorf("error updating existing scene: %s", err.Error())
+	}
+
+	if err := i.getExistingRelationships(id); err != nil {
+		return err
+	}
+
+	if len(i.coverImageData) > 0 {
+		existingCover, err := i.ReaderWriter.GetCover(id)
+		if err != nil {
+			return fmt.Errorf("error getting existing scene cover: %s", err.Error())
+		}
+
+		if len(existingCover) > 0 {
+			i.coverImageData = nil
+		}
+	}
+
+	return nil
+}
+
+func (i *Importer) getExistingRelationships(id int) error {
+	var err error
+	i.existingGalleryIDs, err = i.ReaderWriter.GetGalleryIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing scene galleries: %s", err.Error())
+	}
+
+	i.existingPerformerIDs, err = i.ReaderWriter.GetPerformerIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing scene performers: %s", err.Error())
+	}
+
+	i.existingMovies, err = i.ReaderWriter.GetMovies(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing scene movies: %s", err.Error())
+	}
+
+	i.existingTagIDs, err = i.ReaderWriter.GetTagIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing scene tags: %s", err.Error())
+	}
+
+	return nil
+}
+
+// mergeSceneMovies returns the existing movies of a scene with the movies
+// which are not already set. The scene index of an existing movie is set if
+// it has none.
+func mergeSceneMovies(existing []models.MoviesScenes, movies []models.MoviesScenes) []models.MoviesScenes {
+	ret := append([]models.MoviesScenes(nil), existing...)
+
+	for _, m := range movies {
+		found := false
+		for index := range ret {
+			if ret[index].MovieID == m.MovieID {
+				models.FillNullInt64(&ret[index].SceneIndex, m.SceneIndex)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			ret = append(ret, m)
+		}
+	}
+
+	return ret
+}
+
 func importTags(tagWriter models.TagReaderWriter, names []string, missingRefBehaviour models.ImportMissingRefEnum) ([]*models.Tag, error) {
 	tags, err := tagWriter.FindByNames(names, false)
 	if err != nil {
@@ -1,6 +1,7 @@
 package scene
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -753,3 +754,141 @@ func TestUpdate(t *testing.T) {
 
 	readerWriter.AssertExpectations(t)
 }
+
+func TestImporterPostImportMergeMovies(t *testing.T) {
+	sceneReaderWriter := &mocks.SceneReaderWriter{}
+
+	const otherMovieID = 106
+
+	i := Importer{
+		ReaderWriter: sceneReaderWriter,
+		movies: []models.MoviesScenes{
+			{
+				MovieID:    existingMovieID,
+				SceneIndex: models.NullInt64(2),
+			},
+			{
+				MovieID:    otherMovieID,
+				SceneIndex: models.NullInt64(3),
+			},
+		},
+		existingMovies: []models.MoviesScenes{
+			{
+				MovieID:    existingMovieID,
+				SceneID:    sceneID,
+				SceneIndex: models.NullInt64(1),
+			},
+		},
+	}
+
+	sceneReaderWriter.On("UpdateMovies", sceneID, []models.MoviesScenes{
+		{
+			MovieID:    existingMovieID,
+			SceneID:    sceneID,
+			SceneIndex: models.NullInt64(1),
+		},
+		{
+			MovieID:    otherMovieID,
+			SceneID:    sceneID,
+			SceneIndex: models.NullInt64(3),
+		},
+	}).Return(nil).Once()
+
+	err := i.PostImport(sceneID)
+	assert.Nil(t, err)
+
+	sceneReaderWriter.AssertExpectations(t)
+}
+
+func TestImporterPostImportMergeTags(t *testing.T) {
+	sceneReaderWriter := &mocks.SceneReaderWriter{}
+
+	const otherTagID = 106
+
+	i := Importer{
+		ReaderWriter: sceneReaderWriter,
+		tags: []*models.Tag{
+			{
+				ID: existingTagID,
+			},
+		},
+		existingTagIDs: []int{otherTagID, existingTagID},
+	}
+
+	sceneReaderWriter.On("UpdateTags", sceneID, []int{otherTagID, existingTagID}).Return(nil).Once()
+
+	err := i.PostImport(sceneID)
+	assert.Nil(t, err)
+
+	sceneReaderWriter.AssertExpectations(t)
+}
+
+func TestMerge(t *testing.T) {
+	readerWriter := &mocks.SceneReaderWriter{}
+
+	existing := models.Scene{
+		ID:        sceneID,
+		Title:     models.NullString(title),
+		Organized: false,
+		OCounter:  2,
+	}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		scene: models.Scene{
+			Title:     models.NullString(existingSceneName),
+			Details:   models.NullString(details),
+			StudioID:  models.NullInt64(existingStudioID),
+			Latitude:  sql.NullFloat64{Float64: 1, Valid: true},
+			Longitude: sql.NullFloat64{Float64: 2, Valid: true},
+			Organized: true,
+			OCounter:  1,
+		},
+		coverImageData: imageBytes,
+	}
+
+	merged := existing
+	merged.Details = models.NullString(details)
+	merged.StudioID = models.NullInt64(existingStudioID)
+	merged.Latitude = sql.NullFloat64{Float64: 1, Valid: true}
+	merged.Longitude = sql.NullFloat64{Float64: 2, Valid: true}
+
+	existingMovies := []models.MoviesScenes{
+		{
+			MovieID: existingMovieID,
+			SceneID: sceneID,
+		},
+	}
+
+	readerWriter.On("Find", sceneID).Return(&existing, nil).Once()
+	readerWriter.On("UpdateFull", merged).Return(nil, nil).Once()
+	readerWriter.On("GetGalleryIDs", sceneID).Return([]int{existingGalleryID}, nil).Once()
+	readerWriter.On("GetPerformerIDs", sceneID).Return([]int{existingPerformerID}, nil).Once()
+	readerWriter.On("GetMovies", sceneID).Return(existingMovies, nil).Once()
+	readerWriter.On("GetTagIDs", sceneID).Return([]int{existingTagID}, nil).Once()
+	readerWriter.On("GetCover", sceneID).Return(nil, nil).Once()
+
+	err := i.Merge(sceneID)
+	assert.Nil(t, err)
+	assert.Equal(t, sceneID, i.ID)
+	assert.Equal(t, []int{existingGalleryID}, i.existingGalleryIDs)
+	assert.Equal(t, []int{existingPerformerID}, i.existingPerformerIDs)
+	assert.Equal(t, existingMovies, i.existingMovies)
+	assert.Equal(t, []int{existingTagID}, i.existingTagIDs)
+
+	// the existing scene has no cover, so the imported cover is set
+	assert.Equal(t, imageBytes, i.coverImageData)
+
+	errUpdate := errors.New("Update error")
+	existingErr := models.Scene{
+		ID: errImageID,
+	}
+
+	readerWriter.On("Find", errImageID).Return(&existingErr, nil).Once()
+	readerWriter.On("UpdateFull", mock.AnythingOfType("models.Scene")).Return(nil, errUpdate).Once()
+
+	err = i.Merge(errImageID)
+	assert.NotNil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 type MarkerImporter struct {
@@ -18,6 +19,9 @@ type MarkerImporter struct {
 
 	tags   []*models.Tag
 	marker models.SceneMarker
+
+	// tags of the existing marker when merging
+	existingTagIDs []int
 }
 
 func (i *MarkerImporter) PreImport() error {
@@ -69,6 +73,7 @@ func (i *MarkerImporter) PostImport(id int) error {
 		for _, t := range i.tags {
 			tagIDs = append(tagIDs, t.ID)
 		}
+		tagIDs = utils.IntAppendUniques(i.existingTagIDs, tagIDs)
 		if err := i.ReaderWriter.UpdateTags(id, tagIDs); err != nil {
 			return fmt.Errorf("failed to associate tags: %s", err.Error())
 		}
@@ -118,3 +123,30 @@ func (i *MarkerImporter) Update(id int) error {
 
 	return nil
 }
+
+// Merge sets the title of the existing marker if it has no title. The
+// primary tag of the existing marker is kept.
+func (i *MarkerImporter) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing marker: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing marker with id %d not found", id)
+	}
+
+	if existing.Title == "" && i.marker.Title != "" {
+		marker := *existing
+		marker.Title = i.marker.Title
+		if _, err := i.ReaderWriter.Update(marker); err != nil {
+			return fmt.Errorf("error updating existing marker: %s", err.Error())
+		}
+	}
+
+	i.existingTagIDs, err = i.ReaderWriter.GetTagIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing marker tags: %s", err.Error())
+	}
+
+	return nil
+}
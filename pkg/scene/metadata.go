@@ -0,0 +1,93 @@
+package scene
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// creation times in or before this year are the zero times of the mp4 and
+// unix epochs, written by tools which do not set the creation time
+const minCreationYear = 1970
+
+// EmbeddedMetadataToJSON returns the title, date and details set in the tags
+// of the container of the probed file. The date is taken from the date tag
+// if it is a full date, otherwise from the creation time in the local time
+// zone. The details are taken from the comment tag, or the description or
+// synopsis tags if it is not set.
+func EmbeddedMetadataToJSON(probe *ffmpeg.VideoFile) *jsonschema.Scene {
+	tags := probe.JSON.Format.Tags
+
+	ret := &jsonschema.Scene{
+		Title: strings.TrimSpace(tags.Title),
+		Date:  audioTagDate(tags.Date),
+	}
+
+	if ret.Date == "" {
+		if t := tags.CreationTime.Time; t.Year() > minCreationYear {
+			ret.Date = t.Local().Format("2006-01-02")
+		}
+	}
+
+	for _, v := range []string{tags.Comment, tags.Description, tags.Synopsis} {
+		if v = strings.TrimSpace(v); v != "" {
+			ret.Details = v
+			break
+		}
+	}
+
+	return ret
+}
+
+// isFilenameTitle returns true if title is empty, or is the name of the file
+// at path, with or without its extension, as scanned scenes are titled when
+// their file has no title tag.
+func isFilenameTitle(title string, path string) bool {
+	name := filepath.Base(path)
+	return title == "" || title == name || title == strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// IsMissingMetadata returns true if the scene is not organized, and its title
+// is the file name or its date or details are not set, so that
+// FillEmptyMetadata may set them.
+func IsMissingMetadata(s *models.Scene) bool {
+	return !s.Organized && (isFilenameTitle(s.Title.String, s.Path) || !s.Date.Valid || s.Details.String == "")
+}
+
+// FillEmptyMetadata returns a partial updating the title, date and details of
+// the scene from metadata, where they are not set on the scene. A title the
+// same as the file name is replaced. Returns nil if the scene is organized,
+// or there are no fields to set.
+func FillEmptyMetadata(s *models.Scene, metadata *jsonschema.Scene) *models.ScenePartial {
+	if s.Organized {
+		return nil
+	}
+
+	ret := &models.ScenePartial{ID: s.ID}
+	set := false
+
+	if metadata.Title != "" && metadata.Title != s.Title.String && isFilenameTitle(s.Title.String, s.Path) {
+		ret.Title = &sql.NullString{String: metadata.Title, Valid: true}
+		set = true
+	}
+
+	if metadata.Date != "" && !s.Date.Valid {
+		ret.Date = &models.SQLiteDate{String: metadata.Date, Valid: true}
+		set = true
+	}
+
+	if metadata.Details != "" && s.Details.String == "" {
+		ret.Details = &sql.NullString{String: metadata.Details, Valid: true}
+		set = true
+	}
+
+	if !set {
+		return nil
+	}
+
+	return ret
+}
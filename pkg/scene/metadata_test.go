@@ -0,0 +1,102 @@
+package scene
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedMetadataToJSON(t *testing.T) {
+	probe := &ffmpeg.VideoFile{}
+	tags := &probe.JSON.Format.Tags
+	tags.Title = " title "
+	tags.Description = "description"
+	tags.Synopsis = "synopsis"
+	tags.Date = "2001-02-03"
+	tags.CreationTime = models.JSONTime{Time: time.Date(2005, 6, 7, 12, 0, 0, 0, time.Local)}
+
+	assert.Equal(t, &jsonschema.Scene{
+		Title:   "title",
+		Details: "description",
+		Date:    "2001-02-03",
+	}, EmbeddedMetadataToJSON(probe))
+
+	// the creation time is used without a full date
+	tags.Date = "2001"
+	tags.Comment = " comment "
+	ret := EmbeddedMetadataToJSON(probe)
+	assert.Equal(t, "2005-06-07", ret.Date)
+	assert.Equal(t, "comment", ret.Details)
+
+	// placeholder creation times are ignored
+	tags.CreationTime = models.JSONTime{Time: time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, "", EmbeddedMetadataToJSON(probe).Date)
+
+	tags.CreationTime = models.JSONTime{}
+	assert.Equal(t, "", EmbeddedMetadataToJSON(probe).Date)
+}
+
+func TestFillEmptyMetadata(t *testing.T) {
+	const (
+		sceneID  = 1
+		path     = "/videos/holiday.mp4"
+		title    = "Holiday"
+		date     = "2001-02-03"
+		details  = "details"
+		newTitle = "new title"
+	)
+
+	metadata := &jsonschema.Scene{
+		Title:   title,
+		Date:    date,
+		Details: details,
+	}
+
+	// empty scene, titled after the file
+	for _, sceneTitle := range []string{"", "holiday", "holiday.mp4"} {
+		s := &models.Scene{
+			ID:    sceneID,
+			Path:  path,
+			Title: models.NullString(sceneTitle),
+		}
+
+		assert.True(t, IsMissingMetadata(s), sceneTitle)
+		assert.Equal(t, &models.ScenePartial{
+			ID:      sceneID,
+			Title:   &sql.NullString{String: title, Valid: true},
+			Date:    &models.SQLiteDate{String: date, Valid: true},
+			Details: &sql.NullString{String: details, Valid: true},
+		}, FillEmptyMetadata(s, metadata), sceneTitle)
+	}
+
+	// set fields are kept
+	s := &models.Scene{
+		ID:    sceneID,
+		Path:  path,
+		Title: models.NullString(newTitle),
+		Date:  models.SQLiteDate{String: "2010-01-01", Valid: true},
+	}
+	assert.True(t, IsMissingMetadata(s))
+	assert.Equal(t, &models.ScenePartial{
+		ID:      sceneID,
+		Details: &sql.NullString{String: details, Valid: true},
+	}, FillEmptyMetadata(s, metadata))
+
+	s.Details = models.NullString("existing")
+	assert.False(t, IsMissingMetadata(s))
+	assert.Nil(t, FillEmptyMetadata(s, metadata))
+
+	// no metadata
+	s = &models.Scene{ID: sceneID, Path: path}
+	assert.Nil(t, FillEmptyMetadata(s, &jsonschema.Scene{}))
+
+	// organized scenes are not changed
+	s.Organized = true
+	assert.False(t, IsMissingMetadata(s))
+	assert.Nil(t, FillEmptyMetadata(s, metadata))
+}
@@ -0,0 +1,130 @@
+package scene
+
+import (
+	"encoding/xml"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// NFO is the metadata of a scene in the Kodi movie NFO format, which is
+// also read by Jellyfin and Emby. Rating and Genres are only read from
+// NFO files written by other applications.
+type NFO struct {
+	XMLName    xml.Name     `xml:"movie"`
+	Title      string       `xml:"title"`
+	Plot       string       `xml:"plot,omitempty"`
+	Premiered  string       `xml:"premiered,omitempty"`
+	Year       string       `xml:"year,omitempty"`
+	Rating     float64      `xml:"rating,omitempty"`
+	UserRating int          `xml:"userrating,omitempty"`
+	Runtime    int          `xml:"runtime,omitempty"`
+	Studio     string       `xml:"studio,omitempty"`
+	Set        *NFOSet      `xml:"set,omitempty"`
+	Genres     []string     `xml:"genre"`
+	Tags       []string     `xml:"tag"`
+	Actors     []NFOActor   `xml:"actor"`
+	DateAdded  string       `xml:"dateadded,omitempty"`
+	FileInfo   *NFOFileInfo `xml:"fileinfo,omitempty"`
+}
+
+// NFOSet is the set of a movie. Older NFO files have the name of the set as
+// the text of the set element, which is read into Text.
+type NFOSet struct {
+	Name string `xml:"name"`
+	Text string `xml:",chardata"`
+}
+
+type NFOActor struct {
+	Name  string `xml:"name"`
+	Order int    `xml:"order"`
+}
+
+type NFOFileInfo struct {
+	Video NFOVideoStream `xml:"streamdetails>video"`
+	Audio NFOAudioStream `xml:"streamdetails>audio"`
+}
+
+type NFOVideoStream struct {
+	Codec             string `xml:"codec,omitempty"`
+	Width             int    `xml:"width,omitempty"`
+	Height            int    `xml:"height,omitempty"`
+	DurationInSeconds int    `xml:"durationinseconds,omitempty"`
+}
+
+type NFOAudioStream struct {
+	Codec string `xml:"codec,omitempty"`
+}
+
+// nfoDateAddedFormat is the format of the dateadded element.
+const nfoDateAddedFormat = "2006-01-02 15:04:05"
+
+// ToNFO returns the NFO of the scene with the provided related objects. The
+// scene is added to a set named after the first of its movies, since Kodi
+// supports a single set for each movie. The filename is used as the title
+// if the scene has no title.
+func ToNFO(scene *models.Scene, studio string, performers []string, tags []string, movies []string) *NFO {
+	ret := &NFO{
+		Title:     scene.Title.String,
+		Plot:      scene.Details.String,
+		Studio:    studio,
+		Tags:      tags,
+		DateAdded: scene.CreatedAt.Timestamp.Format(nfoDateAddedFormat),
+	}
+
+	if ret.Title == "" {
+		base := filepath.Base(scene.Path)
+		ret.Title = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	if scene.Date.Valid {
+		ret.Premiered = utils.GetYMDFromDatabaseDate(scene.Date.String)
+		if len(ret.Premiered) >= 4 {
+			ret.Year = ret.Premiered[:4]
+		}
+	}
+
+	// ratings are out of 10 in Kodi
+	if scene.Rating.Valid {
+		ret.UserRating = int(scene.Rating.Int64) * 2
+	}
+
+	if len(movies) > 0 {
+		ret.Set = &NFOSet{Name: movies[0]}
+	}
+
+	for i, p := range performers {
+		ret.Actors = append(ret.Actors, NFOActor{Name: p, Order: i})
+	}
+
+	if scene.Duration.Valid {
+		ret.Runtime = int(math.Round(scene.Duration.Float64 / 60))
+	}
+
+	ret.FileInfo = &NFOFileInfo{
+		Video: NFOVideoStream{
+			Codec:             scene.VideoCodec.String,
+			Width:             int(scene.Width.Int64),
+			Height:            int(scene.Height.Int64),
+			DurationInSeconds: int(math.Round(scene.Duration.Float64)),
+		},
+		Audio: NFOAudioStream{
+			Codec: scene.AudioCodec.String,
+		},
+	}
+
+	return ret
+}
+
+// Marshal returns the NFO as an XML document.
+func (n *NFO) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
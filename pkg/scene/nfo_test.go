@@ -0,0 +1,86 @@
+package scene
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToNFO(t *testing.T) {
+	createdAt := time.Date(2001, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	s := &models.Scene{
+		Title:      sql.NullString{String: title, Valid: true},
+		Details:    sql.NullString{String: details, Valid: true},
+		Date:       models.SQLiteDate{String: date, Valid: true},
+		Rating:     sql.NullInt64{Int64: rating, Valid: true},
+		Path:       "/videos/scene.mp4",
+		Duration:   sql.NullFloat64{Float64: 150.4, Valid: true},
+		VideoCodec: sql.NullString{String: videoCodec, Valid: true},
+		AudioCodec: sql.NullString{String: audioCodec, Valid: true},
+		Width:      sql.NullInt64{Int64: width, Valid: true},
+		Height:     sql.NullInt64{Int64: height, Valid: true},
+		CreatedAt:  models.SQLiteTimestamp{Timestamp: createdAt},
+	}
+
+	nfo := ToNFO(s, "studio", []string{"performer 1", "performer 2"}, []string{"tag"}, []string{"movie 1", "movie 2"})
+
+	data, err := nfo.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+  <title>title</title>
+  <plot>details</plot>
+  <premiered>2001-01-01</premiered>
+  <year>2001</year>
+  <userrating>10</userrating>
+  <runtime>3</runtime>
+  <studio>studio</studio>
+  <set>
+    <name>movie 1</name>
+  </set>
+  <tag>tag</tag>
+  <actor>
+    <name>performer 1</name>
+    <order>0</order>
+  </actor>
+  <actor>
+    <name>performer 2</name>
+    <order>1</order>
+  </actor>
+  <dateadded>2001-01-01 12:30:00</dateadded>
+  <fileinfo>
+    <streamdetails>
+      <video>
+        <codec>videoCodec</codec>
+        <width>100</width>
+        <height>100</height>
+        <durationinseconds>150</durationinseconds>
+      </video>
+      <audio>
+        <codec>audioCodec</codec>
+      </audio>
+    </streamdetails>
+  </fileinfo>
+</movie>
+`, string(data))
+}
+
+func TestToNFOFilenameTitle(t *testing.T) {
+	s := &models.Scene{
+		Path: "/videos/scene name.mp4",
+	}
+
+	nfo := ToNFO(s, "", nil, nil, nil)
+
+	assert.Equal(t, "scene name", nfo.Title)
+	assert.Equal(t, "", nfo.Premiered)
+	assert.Nil(t, nfo.Set)
+	assert.Nil(t, nfo.Actors)
+}
@@ -0,0 +1,156 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxOrganizeNameLength is the maximum length in bytes of each file or
+// directory name of an organized path. Most filesystems limit names to 255
+// bytes, which leaves room for the extension and a collision suffix.
+const maxOrganizeNameLength = 200
+
+var organizeFieldRE = regexp.MustCompile(`\{(\w+)\}`)
+
+// emptyBracketsRE matches brackets left empty by fields without a value.
+var emptyBracketsRE = regexp.MustCompile(`\(\s*\)|\[\s*\]`)
+
+var multiSpaceRE = regexp.MustCompile(`\s{2,}`)
+
+// OrganizeFields are the values of the fields of an organize template.
+type OrganizeFields struct {
+	Title  string
+	Studio string
+	// Date is in the format YYYY-MM-DD
+	Date       string
+	Performers []string
+}
+
+func (f OrganizeFields) value(field string) (string, bool) {
+	var year, month, day string
+	if parts := strings.Split(f.Date, "-"); len(parts) == 3 {
+		year, month, day = parts[0], parts[1], parts[2]
+	}
+
+	switch field {
+	case "title":
+		return f.Title, true
+	case "studio":
+		return f.Studio, true
+	case "date":
+		return f.Date, true
+	case "yyyy":
+		return year, true
+	case "mm":
+		return month, true
+	case "dd":
+		return day, true
+	case "performer":
+		performers := f.sortedPerformers()
+		if len(performers) == 0 {
+			return "", true
+		}
+		return performers[0], true
+	case "performers":
+		return strings.Join(f.sortedPerformers(), ", "), true
+	}
+
+	return "", false
+}
+
+func (f OrganizeFields) sortedPerformers() []string {
+	ret := append([]string(nil), f.Performers...)
+	sort.Strings(ret)
+	return ret
+}
+
+// ValidateOrganizeTemplate returns an error if the template is empty or has
+// unknown fields.
+func ValidateOrganizeTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return errors.New("organize template must be set")
+	}
+
+	for _, m := range organizeFieldRE.FindAllStringSubmatch(template, -1) {
+		if _, ok := (OrganizeFields{}).value(m[1]); !ok {
+			return fmt.Errorf("unknown organize template field %s", m[0])
+		}
+	}
+
+	return nil
+}
+
+// OrganizePath returns the path of a scene file, relative to its library
+// path and without the extension, from the template. Directories of the
+// template are separated by /. The fields of the template are replaced with
+// their values, with the characters which are invalid in file names
+// removed. Directories which are empty once the fields are replaced are
+// omitted.
+func OrganizePath(template string, fields OrganizeFields) (string, error) {
+	if err := ValidateOrganizeTemplate(template); err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, segment := range strings.Split(template, "/") {
+		name := organizeFieldRE.ReplaceAllStringFunc(segment, func(m string) string {
+			v, _ := fields.value(m[1 : len(m)-1])
+			return sanitizeFileName(v)
+		})
+
+		name = emptyBracketsRE.ReplaceAllString(name, "")
+		name = multiSpaceRE.ReplaceAllString(name, " ")
+		name = truncateName(strings.TrimSpace(name), maxOrganizeNameLength)
+		// trailing dots and spaces are not allowed on Windows. This also
+		// removes . and .. names.
+		name = strings.TrimRight(name, ". ")
+
+		if name == "" {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return "", errors.New("organize template produced an empty path")
+	}
+
+	return filepath.Join(names...), nil
+}
+
+// sanitizeFileName returns v without the characters which are invalid in
+// file names on common filesystems.
+func sanitizeFileName(v string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20:
+			return -1
+		case r == '/' || r == '\\':
+			return '-'
+		case strings.ContainsRune(`<>:"|?*`, r):
+			return -1
+		}
+		return r
+	}, v)
+}
+
+// truncateName returns name truncated to at most n bytes, without splitting
+// a multi-byte character.
+func truncateName(name string, n int) string {
+	if len(name) <= n {
+		return name
+	}
+
+	name = name[:n]
+	for len(name) > 0 && !utf8.ValidString(name) {
+		name = name[:len(name)-1]
+	}
+
+	return strings.TrimSpace(name)
+}
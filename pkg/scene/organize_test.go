@@ -0,0 +1,54 @@
+package scene
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizePath(t *testing.T) {
+	fields := OrganizeFields{
+		Title:      "Title: Part 1/2",
+		Studio:     "Studio",
+		Date:       "2001-02-03",
+		Performers: []string{"Performer B", "Performer A"},
+	}
+
+	tests := []struct {
+		template string
+		fields   OrganizeFields
+		want     string
+	}{
+		{"{studio}/{date} {title}", fields, filepath.Join("Studio", "2001-02-03 Title Part 1-2")},
+		{"{yyyy}/{mm}/{dd} {performer}", fields, filepath.Join("2001", "02", "03 Performer A")},
+		{"{performers} - {title}", fields, "Performer A, Performer B - Title Part 1-2"},
+		// empty fields remove their directory and brackets
+		{"{studio}/{title} ({yyyy})", OrganizeFields{Title: "Title"}, "Title"},
+		{"{studio}/{title} [{performers}]", OrganizeFields{Title: "Title"}, "Title"},
+		// double dots don't leave the library path
+		{"../{title}...", OrganizeFields{Title: "Title"}, "Title"},
+	}
+
+	for _, tc := range tests {
+		got, err := OrganizePath(tc.template, tc.fields)
+		if assert.Nil(t, err, tc.template) {
+			assert.Equal(t, tc.want, got, tc.template)
+		}
+	}
+
+	long := OrganizeFields{Title: strings.Repeat("é", 150)}
+	got, err := OrganizePath("{title}", long)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Repeat("é", maxOrganizeNameLength/2), got)
+
+	_, err = OrganizePath("{studio}", OrganizeFields{Title: "Title"})
+	assert.NotNil(t, err)
+}
+
+func TestValidateOrganizeTemplate(t *testing.T) {
+	assert.Nil(t, ValidateOrganizeTemplate("{studio}/{date} {title}"))
+	assert.NotNil(t, ValidateOrganizeTemplate(" "))
+	assert.NotNil(t, ValidateOrganizeTemplate("{unknown}"))
+}
@@ -0,0 +1,120 @@
+package scene
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// sidecarExtensions are the extensions of the sidecar files read for a
+// scene, in order of preference.
+var sidecarExtensions = []string{".nfo", ".json"}
+
+// ReadSidecar returns the metadata in the sidecar file next to the scene
+// file at path. Sidecar files have the same name as the scene file, with
+// either the .nfo extension for Kodi NFO files or the .json extension for
+// files in the scene export format. Only the metadata fields are returned.
+// Returns nil if there is no sidecar file.
+func ReadSidecar(path string) (*jsonschema.Scene, error) {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+
+	for _, ext := range sidecarExtensions {
+		fn := base + ext
+		if exists, _ := utils.FileExists(fn); !exists {
+			continue
+		}
+
+		if ext == ".nfo" {
+			return readNFOSidecar(fn)
+		}
+
+		return readJSONSidecar(fn)
+	}
+
+	return nil, nil
+}
+
+func readNFOSidecar(fn string) (*jsonschema.Scene, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var nfo NFO
+	if err := xml.Unmarshal(data, &nfo); err != nil {
+		return nil, err
+	}
+
+	return nfo.toJSON(), nil
+}
+
+func readJSONSidecar(fn string) (*jsonschema.Scene, error) {
+	sceneJSON, err := jsonschema.LoadSceneFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonschema.Scene{
+		Title:      sceneJSON.Title,
+		Details:    sceneJSON.Details,
+		URL:        sceneJSON.URL,
+		Date:       sceneJSON.Date,
+		Rating:     sceneJSON.Rating,
+		Studio:     sceneJSON.Studio,
+		Performers: sceneJSON.Performers,
+		Tags:       sceneJSON.Tags,
+		Movies:     sceneJSON.Movies,
+	}, nil
+}
+
+// toJSON returns the metadata of the NFO file. Genres are treated as tags.
+func (n *NFO) toJSON() *jsonschema.Scene {
+	ret := &jsonschema.Scene{
+		Title:   strings.TrimSpace(n.Title),
+		Details: strings.TrimSpace(n.Plot),
+		Date:    strings.TrimSpace(n.Premiered),
+		Studio:  strings.TrimSpace(n.Studio),
+		Tags:    utils.StrAppendUniques(nil, trimAll(append(n.Genres, n.Tags...))),
+	}
+
+	// ratings are out of 10 in Kodi
+	if n.UserRating > 0 {
+		ret.Rating = int(math.Round(float64(n.UserRating) / 2))
+	} else if n.Rating > 0 {
+		ret.Rating = int(math.Round(n.Rating / 2))
+	}
+
+	for _, a := range n.Actors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			ret.Performers = append(ret.Performers, name)
+		}
+	}
+
+	if n.Set != nil {
+		name := strings.TrimSpace(n.Set.Name)
+		if name == "" {
+			name = strings.TrimSpace(n.Set.Text)
+		}
+		if name != "" {
+			ret.Movies = []jsonschema.SceneMovie{{MovieName: name}}
+		}
+	}
+
+	return ret
+}
+
+func trimAll(s []string) []string {
+	var ret []string
+	for _, v := range s {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret
+}
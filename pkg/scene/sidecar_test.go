@@ -0,0 +1,123 @@
+package scene
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/manager/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+const testNFO = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<movie>
+  <title> title </title>
+  <plot>details</plot>
+  <premiered>2001-01-01</premiered>
+  <rating>7.8</rating>
+  <studio>studio</studio>
+  <set>movie</set>
+  <genre>genre</genre>
+  <tag>tag</tag>
+  <tag>genre</tag>
+  <actor>
+    <name>performer</name>
+    <role>role</role>
+  </actor>
+  <actor>
+    <name></name>
+  </actor>
+</movie>
+`
+
+const testSidecarJSON = `{
+  "title": "json title",
+  "checksum": "checksum",
+  "performers": ["performer"],
+  "rating": 3
+}`
+
+func writeSidecarTestFile(t *testing.T, fn string, content string) {
+	if err := ioutil.WriteFile(fn, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadSidecar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-sidecar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	scenePath := filepath.Join(dir, "scene.mp4")
+
+	ret, err := ReadSidecar(scenePath)
+	assert.Nil(t, err)
+	assert.Nil(t, ret)
+
+	writeSidecarTestFile(t, filepath.Join(dir, "scene.json"), testSidecarJSON)
+
+	ret, err = ReadSidecar(scenePath)
+	assert.Nil(t, err)
+	assert.Equal(t, &jsonschema.Scene{
+		Title:      "json title",
+		Performers: []string{"performer"},
+		Rating:     3,
+	}, ret)
+
+	// NFO files are preferred
+	writeSidecarTestFile(t, filepath.Join(dir, "scene.nfo"), testNFO)
+
+	ret, err = ReadSidecar(scenePath)
+	assert.Nil(t, err)
+	assert.Equal(t, &jsonschema.Scene{
+		Title:      "title",
+		Details:    "details",
+		Date:       "2001-01-01",
+		Rating:     4,
+		Studio:     "studio",
+		Performers: []string{"performer"},
+		Tags:       []string{"genre", "tag"},
+		Movies:     []jsonschema.SceneMovie{{MovieName: "movie"}},
+	}, ret)
+
+	writeSidecarTestFile(t, filepath.Join(dir, "scene.nfo"), "<movie>")
+
+	_, err = ReadSidecar(scenePath)
+	assert.NotNil(t, err)
+}
+
+func TestNFOSidecarRoundTrip(t *testing.T) {
+	nfo := &NFO{
+		Title:      "title",
+		UserRating: 6,
+		Set:        &NFOSet{Name: "movie"},
+		Tags:       []string{"tag"},
+		Actors:     []NFOActor{{Name: "performer"}},
+	}
+
+	data, err := nfo.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "stash-sidecar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeSidecarTestFile(t, filepath.Join(dir, "scene.nfo"), string(data))
+
+	ret, err := ReadSidecar(filepath.Join(dir, "scene.mp4"))
+	assert.Nil(t, err)
+	assert.Equal(t, &jsonschema.Scene{
+		Title:      "title",
+		Rating:     3,
+		Performers: []string{"performer"},
+		Tags:       []string{"tag"},
+		Movies:     []jsonschema.SceneMovie{{MovieName: "movie"}},
+	}, ret)
+}
@@ -0,0 +1,465 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	// the number of open pages kept for reuse
+	browserIdlePages = 2
+
+	// pages whose javascript heap grows beyond this are closed rather than
+	// reused
+	browserPageMaxHeap = 256 * 1024 * 1024
+
+	// the browser is restarted once this many pages have been opened, to
+	// release the memory it accumulates
+	browserMaxPages = 100
+
+	// the browser is closed when it has not been used for this long
+	browserIdleTimeout = 10 * time.Minute
+
+	// timeout for resetting and closing pages
+	browserPageTimeout = 5 * time.Second
+)
+
+// browsers is the browser pool used by all CDP scrapers.
+var browsers = &browserPool{}
+
+// browserPool manages the browser used by CDP scrapers. The browser is
+// started, or connected to if remote, when first needed, and is restarted
+// if it crashes or the CDP path changes. Pages are kept open between
+// scrapes, each in its own browser context so that cookies are not shared.
+type browserPool struct {
+	mutex sync.Mutex
+
+	// the CDP path the browser was started with
+	cdpPath  string
+	execPath string
+	remote   bool
+
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+	started       time.Time
+	pagesOpened   int
+	restarts      int
+
+	active    int
+	idle      []*browserPage
+	idleTimer *time.Timer
+	lastError string
+
+	// closed once the browser being started has started or failed to
+	starting chan struct{}
+}
+
+type browserPage struct {
+	ctx              context.Context
+	cancel           context.CancelFunc
+	browserContextID cdp.BrowserContextID
+
+	// the browser context of the pool when the page was opened
+	browserCtx context.Context
+}
+
+// running returns true if the browser is running and connected.
+func (p *browserPool) running() bool {
+	return p.browserCtx != nil && p.browserCtx.Err() == nil
+}
+
+// get returns a page of the browser, starting the browser if needed. The
+// page must be returned with put.
+func (p *browserPool) get(globalConfig GlobalConfig) (*browserPage, error) {
+	if err := p.ensureBrowser(globalConfig); err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.running() {
+		err := errors.New("browser stopped before a page was opened")
+		p.lastError = err.Error()
+		return nil, err
+	}
+
+	for len(p.idle) > 0 {
+		ret := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if ret.ctx.Err() == nil {
+			p.active++
+			return ret, nil
+		}
+
+		ret.close()
+	}
+
+	ret, err := p.newPage()
+	if err != nil {
+		p.lastError = err.Error()
+		return nil, err
+	}
+
+	p.active++
+	return ret, nil
+}
+
+// put returns a page obtained by get. The page is kept for reuse if the
+// pool has room for it and its memory use has not grown too much. err is
+// the error of the page load, if any.
+func (p *browserPool) put(pg *browserPage, err error) {
+	keep := err == nil && pg.reset() == nil && pg.heapSize() < browserPageMaxHeap
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err != nil {
+		p.lastError = err.Error()
+	}
+
+	p.active--
+	if keep && pg.browserCtx == p.browserCtx && p.running() && len(p.idle) < browserIdlePages {
+		p.idle = append(p.idle, pg)
+	} else {
+		go pg.close()
+	}
+
+	if p.active == 0 {
+		p.idleTimer = time.AfterFunc(browserIdleTimeout, p.closeIdle)
+	}
+}
+
+// ensureBrowser starts the browser if it is not running, or was started
+// with a different CDP path. The browser is also restarted when it has
+// opened too many pages and none are in use. The browser is downloaded and
+// started without holding the mutex, so that the status of the pool can be
+// read meanwhile. Other callers wait for the browser being started.
+func (p *browserPool) ensureBrowser(globalConfig GlobalConfig) error {
+	cdpPath := globalConfig.GetScraperCDPPath()
+
+	for {
+		p.mutex.Lock()
+
+		if p.idleTimer != nil {
+			p.idleTimer.Stop()
+			p.idleTimer = nil
+		}
+
+		if p.starting != nil {
+			starting := p.starting
+			p.mutex.Unlock()
+			<-starting
+			continue
+		}
+
+		if p.running() && p.cdpPath == cdpPath && (p.pagesOpened < browserMaxPages || p.active > 0) {
+			p.mutex.Unlock()
+			return nil
+		}
+
+		restart := !p.started.IsZero()
+		if p.running() && p.cdpPath == cdpPath {
+			logger.Debugf("[scraper] recycling browser after %d pages", p.pagesOpened)
+		} else if restart && !p.running() && p.cdpPath == cdpPath {
+			logger.Warnf("[scraper] browser is no longer running, restarting")
+		}
+
+		p.stop()
+
+		starting := make(chan struct{})
+		p.starting = starting
+		p.mutex.Unlock()
+
+		b, err := startBrowser(globalConfig)
+
+		p.mutex.Lock()
+		p.starting = nil
+		close(starting)
+
+		if err != nil {
+			p.lastError = err.Error()
+			p.mutex.Unlock()
+			return err
+		}
+
+		p.cdpPath = cdpPath
+		p.execPath = b.execPath
+		p.remote = b.remote
+		p.allocCancel = b.allocCancel
+		p.browserCtx = b.ctx
+		p.browserCancel = b.cancel
+		p.started = time.Now()
+		p.pagesOpened = 0
+		if restart {
+			p.restarts++
+		}
+
+		p.mutex.Unlock()
+		return nil
+	}
+}
+
+// startedBrowser is a browser started, or connected to, by startBrowser.
+type startedBrowser struct {
+	execPath    string
+	remote      bool
+	allocCancel context.CancelFunc
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// startBrowser starts the browser, downloading Chromium if no browser is
+// configured or installed, or connects to the remote browser.
+func startBrowser(globalConfig GlobalConfig) (*startedBrowser, error) {
+	cdpPath := globalConfig.GetScraperCDPPath()
+	remote := isCDPPathHTTP(globalConfig) || isCDPPathWS(globalConfig)
+
+	var allocCtx context.Context
+	var allocCancel context.CancelFunc
+	var execPath string
+
+	if remote {
+		wsURL := cdpPath
+
+		// if CDPPath is http(s) then we need to get the websocket URL
+		if isCDPPathHTTP(globalConfig) {
+			var err error
+			wsURL, err = getRemoteCDPWSAddress(cdpPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		allocCtx, allocCancel = chromedp.NewRemoteAllocator(context.Background(), wsURL)
+	} else {
+		execPath = cdpPath
+		if execPath == "" {
+			execPath = findChrome()
+		}
+		if execPath == "" {
+			var err error
+			execPath, err = downloadChromium(globalConfig.GetConfigPath())
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// chrome uses a temporary user directory, removed when it stops
+		opts := append(chromedp.DefaultExecAllocatorOptions[:],
+			chromedp.ExecPath(execPath),
+		)
+		allocCtx, allocCancel = chromedp.NewExecAllocator(context.Background(), opts...)
+	}
+
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+
+	// the first run starts or connects to the browser
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return nil, err
+	}
+
+	if remote {
+		logger.Infof("[scraper] connected to browser at %s", cdpPath)
+	} else {
+		logger.Infof("[scraper] started browser %s", execPath)
+	}
+
+	return &startedBrowser{
+		execPath:    execPath,
+		remote:      remote,
+		allocCancel: allocCancel,
+		ctx:         browserCtx,
+		cancel:      browserCancel,
+	}, nil
+}
+
+// stop closes the idle pages and stops the browser, or disconnects from it
+// if remote. Pages in use are closed along with the browser.
+func (p *browserPool) stop() {
+	for _, pg := range p.idle {
+		pg.close()
+	}
+	p.idle = nil
+
+	if p.browserCancel != nil {
+		p.browserCancel()
+		p.allocCancel()
+	}
+
+	p.browserCtx = nil
+	p.browserCancel = nil
+	p.allocCancel = nil
+}
+
+// closeIdle stops the browser if no pages are in use.
+func (p *browserPool) closeIdle() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.active == 0 && p.browserCtx != nil {
+		logger.Debug("[scraper] closing unused browser")
+		p.stop()
+	}
+}
+
+// newPage opens a page in a new browser context.
+func (p *browserPool) newPage() (*browserPage, error) {
+	var browserContextID cdp.BrowserContextID
+	var targetID target.ID
+
+	err := chromedp.Run(p.browserCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		// browser contexts are created by the browser rather than a page
+		browserExec := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+
+		var err error
+		browserContextID, err = target.CreateBrowserContext().Do(browserExec)
+		if err != nil {
+			return err
+		}
+
+		targetID, err = target.CreateTarget("about:blank").WithBrowserContextID(browserContextID).Do(browserExec)
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := chromedp.NewContext(p.browserCtx, chromedp.WithTargetID(targetID))
+	ret := &browserPage{
+		ctx:              ctx,
+		cancel:           cancel,
+		browserContextID: browserContextID,
+		browserCtx:       p.browserCtx,
+	}
+
+	if err := chromedp.Run(ctx, performance.Enable()); err != nil {
+		ret.close()
+		return nil, err
+	}
+
+	p.pagesOpened++
+	return ret, nil
+}
+
+// reset clears the cookies of the page and navigates away from the scraped
+// site, so that the page can be reused.
+func (pg *browserPage) reset() error {
+	ctx, cancel := context.WithTimeout(pg.ctx, browserPageTimeout)
+	defer cancel()
+
+	return chromedp.Run(ctx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, _, _, err := page.Navigate("about:blank").Do(ctx)
+			return err
+		}),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			browserExec := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+			return storage.ClearCookies().WithBrowserContextID(pg.browserContextID).Do(browserExec)
+		}),
+	)
+}
+
+// heapSize returns the size of the javascript heap of the page, or the
+// maximum size if it cannot be read.
+func (pg *browserPage) heapSize() float64 {
+	ctx, cancel := context.WithTimeout(pg.ctx, browserPageTimeout)
+	defer cancel()
+
+	var metrics []*performance.Metric
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		metrics, err = performance.GetMetrics().Do(ctx)
+		return err
+	})); err != nil {
+		return browserPageMaxHeap
+	}
+
+	for _, m := range metrics {
+		if m.Name == "JSHeapTotalSize" {
+			return m.Value
+		}
+	}
+
+	return 0
+}
+
+// close closes the page and disposes of its browser context.
+func (pg *browserPage) close() {
+	pg.cancel()
+
+	if pg.browserCtx.Err() != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(pg.browserCtx, browserPageTimeout)
+	defer cancel()
+
+	if err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		browserExec := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+		return target.DisposeBrowserContext(pg.browserContextID).Do(browserExec)
+	})); err != nil {
+		logger.Debugf("[scraper] error disposing browser context: %s", err.Error())
+	}
+}
+
+func (p *browserPool) status() *models.ScraperBrowserStatus {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ret := &models.ScraperBrowserStatus{
+		Running:     p.running(),
+		Remote:      p.remote,
+		ActivePages: p.active,
+		IdlePages:   len(p.idle),
+		Restarts:    p.restarts,
+	}
+
+	if !p.started.IsZero() {
+		path := p.execPath
+		if p.remote {
+			path = p.cdpPath
+		}
+		ret.Path = &path
+	}
+
+	if ret.Running {
+		started := p.started
+		ret.StartedAt = &started
+	}
+
+	if p.lastError != "" {
+		lastError := p.lastError
+		ret.LastError = &lastError
+	}
+
+	return ret
+}
+
+// BrowserStatus returns the status of the browser used by CDP scrapers.
+func BrowserStatus() *models.ScraperBrowserStatus {
+	return browsers.status()
+}
+
+// CloseBrowser stops the browser used by CDP scrapers.
+func CloseBrowser() {
+	browsers.mutex.Lock()
+	defer browsers.mutex.Unlock()
+
+	browsers.stop()
+}
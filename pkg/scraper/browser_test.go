@@ -0,0 +1,17 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrowserStatusStopped(t *testing.T) {
+	p := &browserPool{}
+	status := p.status()
+	assert.False(t, status.Running)
+	assert.Nil(t, status.Path)
+	assert.Nil(t, status.StartedAt)
+	assert.Nil(t, status.LastError)
+	assert.Equal(t, 0, status.ActivePages)
+}
@@ -0,0 +1,308 @@
+package scraper
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// chromiumRevision is the Chromium snapshot downloaded when Chrome is not
+// installed. It supports the version of the DevTools protocol used by the
+// CDP scrapers.
+const chromiumRevision = "768783"
+
+var chromiumSnapshotURL = "https://storage.googleapis.com/chromium-browser-snapshots"
+
+// chromiumMetadataURL is the storage API endpoint of the snapshot objects.
+// The metadata of each object includes the MD5 checksum of the archive,
+// which is used to verify the download.
+var chromiumMetadataURL = "https://www.googleapis.com/storage/v1/b/chromium-browser-snapshots/o"
+
+// chromiumDownloadTimeout is the time allowed to download the snapshot.
+const chromiumDownloadTimeout = 10 * time.Minute
+
+// chromiumChecksumTimeout is the time allowed to get the snapshot checksum.
+const chromiumChecksumTimeout = 30 * time.Second
+
+// the subdirectory of the config directory Chromium is downloaded to
+const chromiumDir = "chromium"
+
+// chromeExecutables are the names and paths searched for an installed
+// Chrome, in order of preference.
+var chromeExecutables = []string{
+	// Unix-like
+	"headless_shell",
+	"headless-shell",
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"google-chrome-beta",
+	"google-chrome-unstable",
+	"/usr/bin/google-chrome",
+
+	// Windows
+	"chrome",
+	"chrome.exe",
+	`C:\Program Files (x86)\Google\Chrome\Application\chrome.exe`,
+	`C:\Program Files\Google\Chrome\Application\chrome.exe`,
+
+	// Mac
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+	"/Applications/Chromium.app/Contents/MacOS/Chromium",
+}
+
+// findChrome returns the path of an installed Chrome, or an empty string if
+// none is found.
+func findChrome() string {
+	for _, p := range chromeExecutables {
+		if found, err := exec.LookPath(p); err == nil {
+			return found
+		}
+	}
+
+	return ""
+}
+
+// chromiumArchive returns the name of the snapshot directory and archive
+// for the platform, and the path of the executable within the archive.
+// Returns empty strings if there is no snapshot for the platform.
+func chromiumArchive(goos, goarch string) (platform, archive, executable string) {
+	switch {
+	case goos == "linux" && goarch == "amd64":
+		return "Linux_x64", "chrome-linux", "chrome"
+	case goos == "darwin" && goarch == "amd64":
+		return "Mac", "chrome-mac", "Chromium.app/Contents/MacOS/Chromium"
+	case goos == "windows" && goarch == "amd64":
+		return "Win_x64", "chrome-win", "chrome.exe"
+	case goos == "windows" && goarch == "386":
+		return "Win", "chrome-win", "chrome.exe"
+	}
+
+	return "", "", ""
+}
+
+// downloadChromium downloads the Chromium snapshot to a directory within
+// dir, unless it was already downloaded, and returns the path of its
+// executable.
+func downloadChromium(dir string) (string, error) {
+	platform, archive, executable := chromiumArchive(runtime.GOOS, runtime.GOARCH)
+	if platform == "" {
+		return "", fmt.Errorf("chrome was not found, and chromium cannot be downloaded for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	revisionDir := filepath.Join(dir, chromiumDir, chromiumRevision)
+	execPath := filepath.Join(revisionDir, archive, filepath.FromSlash(executable))
+	if exists, _ := utils.FileExists(execPath); exists {
+		return execPath, nil
+	}
+
+	object := fmt.Sprintf("%s/%s/%s.zip", platform, chromiumRevision, archive)
+	checksum, err := chromiumChecksum(object)
+	if err != nil {
+		return "", fmt.Errorf("error getting chromium checksum: %s", err.Error())
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, chromiumDir), 0755); err != nil {
+		return "", err
+	}
+
+	url := chromiumSnapshotURL + "/" + object
+	logger.Infof("[scraper] chrome was not found, downloading chromium from %s...", url)
+
+	archivePath := revisionDir + ".zip.part"
+	defer os.Remove(archivePath)
+	if err := downloadFile(url, archivePath); err != nil {
+		return "", fmt.Errorf("error downloading chromium: %s", err.Error())
+	}
+
+	if err := utils.VerifyChecksum(archivePath, checksum, md5.New()); err != nil {
+		return "", fmt.Errorf("error verifying chromium download: %s", err.Error())
+	}
+
+	// extract to a temporary directory first, so that a partly extracted
+	// archive is not used
+	tmpDir := revisionDir + ".tmp"
+	os.RemoveAll(tmpDir)
+	if err := extractZip(archivePath, tmpDir); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", fmt.Errorf("error extracting chromium: %s", err.Error())
+	}
+
+	os.RemoveAll(revisionDir)
+	if err := os.Rename(tmpDir, revisionDir); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", err
+	}
+
+	logger.Infof("[scraper] chromium installed in %s", revisionDir)
+	return execPath, nil
+}
+
+// chromiumChecksum returns the hex encoded MD5 checksum published in the
+// metadata of the snapshot object.
+func chromiumChecksum(object string) (string, error) {
+	client := &http.Client{
+		Timeout: chromiumChecksumTimeout,
+	}
+
+	resp, err := client.Get(chromiumMetadataURL + "/" + url.PathEscape(object))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var metadata struct {
+		MD5Hash string `json:"md5Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return "", err
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(metadata.MD5Hash)
+	if err != nil {
+		return "", err
+	}
+	if len(sum) != md5.Size {
+		return "", fmt.Errorf("no checksum published for %s", object)
+	}
+
+	return hex.EncodeToString(sum), nil
+}
+
+func downloadFile(url, path string) error {
+	client := &http.Client{
+		Timeout: chromiumDownloadTimeout,
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// extractZip extracts the zip file at src to dest, keeping the permissions
+// of the files, so that executables can be run.
+func extractZip(src, dest string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	destPrefix := filepath.Clean(dest) + string(os.PathSeparator)
+	for _, f := range r.File {
+		path := filepath.Join(dest, filepath.FromSlash(f.Name))
+		if !strings.HasPrefix(path, destPrefix) {
+			return fmt.Errorf("invalid file path in archive: %s", f.Name)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		if f.Mode()&os.ModeSymlink != 0 {
+			// the mac archive contains symlinks within Chromium.app
+			if err := extractSymlink(f, path, destPrefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := extractZipFile(f, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractZipFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	mode := f.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func extractSymlink(f *zip.File, path, destPrefix string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	target, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	// links must stay within the archive
+	linkPath := filepath.Join(filepath.Dir(path), string(target))
+	if filepath.IsAbs(string(target)) || !strings.HasPrefix(linkPath, destPrefix) {
+		return fmt.Errorf("invalid link in archive: %s", f.Name)
+	}
+
+	return os.Symlink(string(target), path)
+}
@@ -0,0 +1,267 @@
+package scraper
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testZipEntry struct {
+	name string
+	mode os.FileMode
+	data string
+}
+
+func writeTestZip(t *testing.T, path string, entries []testZipEntry) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		header.SetMode(e.mode)
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-chromium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "chrome.zip")
+	writeTestZip(t, archive, []testZipEntry{
+		{"chrome-linux/", os.ModeDir | 0755, ""},
+		{"chrome-linux/chrome", 0755, "binary"},
+		{"chrome-linux/locales/en-US.pak", 0644, "locale"},
+		{"chrome-linux/current", os.ModeSymlink | 0777, "locales"},
+	})
+
+	dest := filepath.Join(dir, "extracted")
+	if !assert.Nil(t, extractZip(archive, dest)) {
+		return
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dest, "chrome-linux", "locales", "en-US.pak"))
+	assert.Nil(t, err)
+	assert.Equal(t, "locale", string(data))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dest, "chrome-linux", "chrome"))
+		if assert.Nil(t, err) {
+			assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		}
+
+		target, err := os.Readlink(filepath.Join(dest, "chrome-linux", "current"))
+		assert.Nil(t, err)
+		assert.Equal(t, "locales", target)
+	}
+}
+
+func TestExtractZipInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-chromium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name  string
+		entry testZipEntry
+	}{
+		{"path outside", testZipEntry{"../outside", 0644, "data"}},
+		{"absolute link", testZipEntry{"link", os.ModeSymlink | 0777, "/etc/passwd"}},
+		{"link outside", testZipEntry{"dir/link", os.ModeSymlink | 0777, "../../outside"}},
+	}
+
+	for _, tt := range tests {
+		archive := filepath.Join(dir, "invalid.zip")
+		writeTestZip(t, archive, []testZipEntry{tt.entry})
+
+		dest := filepath.Join(dir, "extracted")
+		assert.NotNil(t, extractZip(archive, dest), tt.name)
+		os.RemoveAll(dest)
+	}
+}
+
+func TestChromiumArchive(t *testing.T) {
+	platform, archive, executable := chromiumArchive("linux", "amd64")
+	assert.Equal(t, "Linux_x64", platform)
+	assert.Equal(t, "chrome-linux", archive)
+	assert.Equal(t, "chrome", executable)
+
+	platform, _, executable = chromiumArchive("darwin", "amd64")
+	assert.Equal(t, "Mac", platform)
+	assert.Equal(t, "Chromium.app/Contents/MacOS/Chromium", executable)
+
+	platform, _, _ = chromiumArchive("linux", "arm64")
+	assert.Equal(t, "", platform)
+}
+
+func TestDownloadChromiumExisting(t *testing.T) {
+	platform, archive, executable := chromiumArchive(runtime.GOOS, runtime.GOARCH)
+	if platform == "" {
+		t.Skip("no chromium snapshot for this platform")
+	}
+
+	dir, err := ioutil.TempDir("", "stash-chromium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a previously downloaded snapshot is used without downloading
+	execPath := filepath.Join(dir, chromiumDir, chromiumRevision, archive, filepath.FromSlash(executable))
+	if err := os.MkdirAll(filepath.Dir(execPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(execPath, []byte("binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := downloadChromium(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, execPath, got)
+}
+
+// chromiumPlatforms are the platforms with a chromium snapshot.
+var chromiumPlatforms = [][2]string{
+	{"linux", "amd64"},
+	{"darwin", "amd64"},
+	{"windows", "amd64"},
+	{"windows", "386"},
+}
+
+// testSnapshotServer serves the snapshot archives and their metadata. The
+// published checksum of objects in badChecksum does not match the archive.
+type testSnapshotServer struct {
+	archives    map[string][]byte
+	badChecksum map[string]bool
+}
+
+func (s *testSnapshotServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/metadata/") {
+		object := strings.TrimPrefix(r.URL.Path, "/metadata/")
+		data, found := s.archives[object]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if s.badChecksum[object] {
+			data = []byte("other")
+		}
+		sum := md5.Sum(data)
+		fmt.Fprintf(w, `{"name": %q, "md5Hash": %q}`, object, base64.StdEncoding.EncodeToString(sum[:]))
+		return
+	}
+
+	data, found := s.archives[strings.TrimPrefix(r.URL.Path, "/snapshots/")]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write(data)
+}
+
+func useTestSnapshotServer(s *testSnapshotServer) func() {
+	ts := httptest.NewServer(s)
+	oldSnapshot, oldMetadata := chromiumSnapshotURL, chromiumMetadataURL
+	chromiumSnapshotURL = ts.URL + "/snapshots"
+	chromiumMetadataURL = ts.URL + "/metadata"
+
+	return func() {
+		chromiumSnapshotURL, chromiumMetadataURL = oldSnapshot, oldMetadata
+		ts.Close()
+	}
+}
+
+func TestChromiumChecksum(t *testing.T) {
+	s := &testSnapshotServer{archives: map[string][]byte{}}
+	defer useTestSnapshotServer(s)()
+
+	for _, p := range chromiumPlatforms {
+		platform, archive, _ := chromiumArchive(p[0], p[1])
+		object := platform + "/" + chromiumRevision + "/" + archive + ".zip"
+		s.archives[object] = []byte(object)
+
+		sum := md5.Sum([]byte(object))
+		checksum, err := chromiumChecksum(object)
+		assert.Nil(t, err, object)
+		assert.NotEmpty(t, checksum, object)
+		assert.Equal(t, hex.EncodeToString(sum[:]), checksum, object)
+	}
+
+	_, err := chromiumChecksum("Linux_x64/1/chrome-linux.zip")
+	assert.NotNil(t, err)
+}
+
+func TestDownloadChromium(t *testing.T) {
+	platform, archive, executable := chromiumArchive(runtime.GOOS, runtime.GOARCH)
+	if platform == "" {
+		t.Skip("no chromium snapshot for this platform")
+	}
+
+	dir, err := ioutil.TempDir("", "stash-chromium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	zipPath := filepath.Join(dir, "snapshot.zip")
+	writeTestZip(t, zipPath, []testZipEntry{
+		{archive + "/" + executable, 0755, "binary"},
+	})
+	data, err := ioutil.ReadFile(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object := platform + "/" + chromiumRevision + "/" + archive + ".zip"
+	s := &testSnapshotServer{
+		archives:    map[string][]byte{object: data},
+		badChecksum: map[string]bool{object: true},
+	}
+	defer useTestSnapshotServer(s)()
+
+	// snapshots which do not match the published checksum are not used
+	_, err = downloadChromium(dir)
+	assert.NotNil(t, err)
+	_, err = os.Stat(filepath.Join(dir, chromiumDir, chromiumRevision))
+	assert.True(t, os.IsNotExist(err))
+
+	s.badChecksum = nil
+	execPath, err := downloadChromium(dir)
+	if assert.Nil(t, err) {
+		assert.Equal(t, filepath.Join(dir, chromiumDir, chromiumRevision, archive, filepath.FromSlash(executable)), execPath)
+		got, err := ioutil.ReadFile(execPath)
+		assert.Nil(t, err)
+		assert.Equal(t, "binary", string(got))
+	}
+}
@@ -17,6 +17,9 @@ type config struct {
 	ID   string
 	path string
 
+	// trace records the current scrape, if tracing is enabled
+	trace *scrapeTrace
+
 	// The name of the scraper. This is displayed in the UI.
 	Name string `yaml:"name"`
 
@@ -83,6 +86,12 @@ func (c config) validate() error {
 		}
 	}
 
+	if c.GalleryByFragment != nil {
+		if err := c.GalleryByFragment.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, s := range c.PerformerByURL {
 		if err := s.validate(); err != nil {
 			return err
@@ -95,12 +104,61 @@ func (c config) validate() error {
 		}
 	}
 
+	for _, s := range c.GalleryByURL {
+		if err := s.validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, s := range c.MovieByURL {
 		if err := s.validate(); err != nil {
 			return err
 		}
 	}
 
+	if err := c.validateScraperRefs(); err != nil {
+		return err
+	}
+
+	if err := c.XPathScrapers.validate("xPathScrapers", validateXPathSelector); err != nil {
+		return err
+	}
+
+	// json selectors are not validated, since gjson accepts any path
+	return c.JsonScrapers.validate("jsonScrapers", nil)
+}
+
+// validateScraperRefs returns an error if a scraper type configuration
+// refers to a xpath or json scraper which does not exist.
+func (c config) validateScraperRefs() error {
+	types := []*scraperTypeConfig{c.PerformerByName, c.PerformerByFragment, c.SceneByFragment, c.GalleryByFragment}
+	for _, urlConfigs := range [][]*scrapeByURLConfig{c.PerformerByURL, c.SceneByURL, c.GalleryByURL, c.MovieByURL} {
+		for _, s := range urlConfigs {
+			types = append(types, &s.scraperTypeConfig)
+		}
+	}
+
+	for _, t := range types {
+		if t == nil {
+			continue
+		}
+
+		var scrapers mappedScrapers
+		var name string
+		switch t.Action {
+		case scraperActionXPath:
+			scrapers, name = c.XPathScrapers, "xPathScrapers"
+		case scraperActionJson:
+			scrapers, name = c.JsonScrapers, "jsonScrapers"
+		default:
+			continue
+		}
+
+		if _, found := scrapers[t.Scraper]; !found {
+			return fmt.Errorf("scraper '%s' not found in %s", t.Scraper, name)
+		}
+	}
+
 	return nil
 }
 
@@ -116,6 +174,10 @@ type scraperTypeConfig struct {
 	// for xpath name scraper only
 	QueryURL             string               `yaml:"queryURL"`
 	QueryURLReplacements queryURLReplacements `yaml:"queryURLReplace"`
+
+	// for script scraper only. The number of seconds the script may run
+	// before it is killed. Defaults to scriptTimeout if zero.
+	Timeout int `yaml:"timeout"`
 }
 
 func (c scraperTypeConfig) validate() error {
@@ -127,6 +189,10 @@ func (c scraperTypeConfig) validate() error {
 		return errors.New("script is mandatory for script scraper action")
 	}
 
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+
 	return nil
 }
 
@@ -155,6 +221,9 @@ func (c scrapeByURLConfig) matchesURL(url string) bool {
 
 type scraperDebugOptions struct {
 	PrintHTML bool `yaml:"printHTML"`
+	// Trace records the requests made and the selectors run by each scrape,
+	// which are returned by the scraperTraces query.
+	Trace bool `yaml:"trace"`
 }
 
 type scraperCookies struct {
@@ -0,0 +1,114 @@
+package scraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validScraperYAML = `name: Test
+sceneByURL:
+  - action: scrapeXPath
+    url:
+      - example.com
+    scraper: sceneScraper
+xPathScrapers:
+  sceneScraper:
+    common:
+      $title: //div[@class="title"]
+    scene:
+      Title: $title/h1
+      Tags:
+        Name:
+          selector: //a[@class="tag"]
+          postProcess:
+            - replace:
+                - regex: \s+
+                  with: " "
+`
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{
+			"valid",
+			validScraperYAML,
+			"",
+		},
+		{
+			"unknown field",
+			validScraperYAML + "unknown: true\n",
+			"field unknown not found",
+		},
+		{
+			"invalid xpath",
+			strings.Replace(validScraperYAML, "$title/h1", "$title/h1[", 1),
+			"xPathScrapers.sceneScraper: scene.Title: invalid selector",
+		},
+		{
+			"invalid regex",
+			strings.Replace(validScraperYAML, `\s+`, `\s+(`, 1),
+			"xPathScrapers.sceneScraper: scene.Tags.Name: invalid regex",
+		},
+		{
+			"missing scraper",
+			strings.Replace(validScraperYAML, "scraper: sceneScraper", "scraper: other", 1),
+			"scraper 'other' not found in xPathScrapers",
+		},
+		{
+			"invalid gallery scraper",
+			validScraperYAML + "galleryByURL:\n  - action: scrapeXPath\n    scraper: sceneScraper\n",
+			"url is mandatory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadScraperFromYAML("test", strings.NewReader(tt.yaml))
+			if tt.err == "" {
+				assert.Nil(t, err)
+			} else if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadScrapersErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scrapers")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	validPath := filepath.Join(dir, "valid.yml")
+	invalidPath := filepath.Join(dir, "invalid.yml")
+	if err := ioutil.WriteFile(validPath, []byte(validScraperYAML), 0644); err != nil {
+		t.Fatalf("Error writing scraper: %s", err.Error())
+	}
+	if err := ioutil.WriteFile(invalidPath, []byte("name: [Test\n"), 0644); err != nil {
+		t.Fatalf("Error writing scraper: %s", err.Error())
+	}
+
+	scrapers, loadErrors, err := loadScrapers(dir)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// the built-in scraper is always loaded
+	if assert.Len(t, scrapers, 2) {
+		assert.Equal(t, "valid", scrapers[1].ID)
+	}
+
+	if assert.Len(t, loadErrors, 1) {
+		assert.Equal(t, invalidPath, loadErrors[0].Path)
+		assert.NotEmpty(t, loadErrors[0].Error)
+	}
+}
@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const galleryHTML = `
+<div class="gallery">
+	<h1>Gallery Title</h1>
+	<span class="date">2001-02-03</span>
+	<a class="performer">Performer 1</a>
+	<a class="performer">Performer 2</a>
+	<a class="tag">Tag</a>
+</div>
+`
+
+const galleryXPathScraper = `xPathScrapers:
+  galleryScraper:
+    gallery:
+      Title: //h1
+      Date: //span[@class="date"]
+      Performers:
+        Name: //a[@class="performer"]
+      Tags:
+        Name: //a[@class="tag"]
+`
+
+// expectGalleryMatches sets the expected matching of the scraped performers
+// and tags. The first performer and the tag exist.
+func expectGalleryMatches(txnManager *mocks.TransactionManager) {
+	performerReaderWriter := txnManager.Performer().(*mocks.PerformerReaderWriter)
+	performerReaderWriter.On("FindByNames", []string{"Performer 1"}, true).Return([]*models.Performer{{ID: 1}}, nil).Once()
+	performerReaderWriter.On("FindByNames", []string{"Performer 2"}, true).Return(nil, nil).Once()
+
+	tagReaderWriter := txnManager.Tag().(*mocks.TagReaderWriter)
+	tagReaderWriter.On("FindByName", "Tag", true).Return(&models.Tag{ID: 2}, nil).Once()
+}
+
+func verifyScrapedGallery(t *testing.T, gallery *models.ScrapedGallery) {
+	verifyField(t, "Gallery Title", gallery.Title, "Title")
+	verifyField(t, "2001-02-03", gallery.Date, "Date")
+
+	if assert.Len(t, gallery.Performers, 2) {
+		assert.Equal(t, "Performer 1", gallery.Performers[0].Name)
+		verifyField(t, "1", gallery.Performers[0].ID, "Performers[0].ID")
+		assert.Nil(t, gallery.Performers[1].ID)
+	}
+
+	if assert.Len(t, gallery.Tags, 1) {
+		verifyField(t, "2", gallery.Tags[0].ID, "Tags[0].ID")
+	}
+}
+
+func TestScrapeGalleryURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, galleryHTML)
+	}))
+	defer ts.Close()
+
+	cache, txnManager := makeScraperCache(t, `name: Test
+galleryByURL:
+  - action: scrapeXPath
+    url:
+      - `+ts.URL+`
+    scraper: galleryScraper
+`+galleryXPathScraper)
+
+	expectGalleryMatches(txnManager)
+
+	gallery, err := cache.ScrapeGalleryURL(ts.URL + "/gallery")
+	if !assert.Nil(t, err) || !assert.NotNil(t, gallery) {
+		return
+	}
+
+	verifyScrapedGallery(t, gallery)
+	txnManager.Performer().(*mocks.PerformerReaderWriter).AssertExpectations(t)
+	txnManager.Tag().(*mocks.TagReaderWriter).AssertExpectations(t)
+}
+
+func TestScrapeGalleryURLNoGallery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, galleryHTML)
+	}))
+	defer ts.Close()
+
+	// the scraper has no gallery configuration
+	cache, _ := makeScraperCache(t, `name: Test
+galleryByURL:
+  - action: scrapeXPath
+    url:
+      - `+ts.URL+`
+    scraper: sceneScraper
+xPathScrapers:
+  sceneScraper:
+    scene:
+      Title: //h1
+`)
+
+	gallery, err := cache.ScrapeGalleryURL(ts.URL + "/gallery")
+	assert.Nil(t, err)
+	assert.Nil(t, gallery)
+}
+
+func TestScrapeGalleryFragment(t *testing.T) {
+	const (
+		galleryID = 10
+		checksum  = "checksum"
+	)
+
+	var requested string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		fmt.Fprint(w, galleryHTML)
+	}))
+	defer ts.Close()
+
+	cache, txnManager := makeScraperCache(t, `name: Test
+galleryByFragment:
+  action: scrapeXPath
+  queryURL: `+ts.URL+`/search?checksum={checksum}
+  scraper: galleryScraper
+`+galleryXPathScraper)
+
+	galleryReaderWriter := txnManager.Gallery().(*mocks.GalleryReaderWriter)
+	galleryReaderWriter.On("Find", galleryID).Return(&models.Gallery{
+		ID:       galleryID,
+		Checksum: checksum,
+	}, nil).Once()
+	galleryReaderWriter.On("Find", galleryID+1).Return(nil, nil).Once()
+	expectGalleryMatches(txnManager)
+
+	gallery, err := cache.ScrapeGallery("test", models.GalleryUpdateInput{ID: strconv.Itoa(galleryID)})
+	if !assert.Nil(t, err) || !assert.NotNil(t, gallery) {
+		return
+	}
+
+	assert.Equal(t, "/search?checksum="+checksum, requested)
+	verifyScrapedGallery(t, gallery)
+
+	// galleries which do not exist are not scraped
+	_, err = cache.ScrapeGallery("test", models.GalleryUpdateInput{ID: strconv.Itoa(galleryID + 1)})
+	assert.NotNil(t, err)
+
+	galleryReaderWriter.AssertExpectations(t)
+}
@@ -183,7 +183,7 @@ func (s *jsonScraper) scrapeGalleryByFragment(gallery models.GalleryUpdateInput)
 	}
 
 	if storedGallery == nil {
-		return nil, errors.New("no scene found")
+		return nil, errors.New("no gallery found")
 	}
 
 	// construct the URL
@@ -221,11 +221,21 @@ type jsonQuery struct {
 	scraper *jsonScraper
 }
 
+// trace returns the trace of the current scrape, or nil if it is not traced.
+func (q *jsonQuery) trace() *scrapeTrace {
+	if q.scraper == nil {
+		return nil
+	}
+
+	return q.scraper.config.trace
+}
+
 func (q *jsonQuery) runQuery(selector string) []string {
 	value := gjson.Get(q.doc, selector)
 
 	if !value.Exists() {
 		logger.Warnf("Could not find json path '%s' in json object", selector)
+		q.trace().selector(selector, 0, nil)
 		return nil
 	}
 
@@ -239,6 +249,7 @@ func (q *jsonQuery) runQuery(selector string) []string {
 		ret = append(ret, value.String())
 	}
 
+	q.trace().selector(selector, len(ret), nil)
 	return ret
 }
 
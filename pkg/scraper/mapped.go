@@ -6,6 +6,7 @@ import (
 	"math"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -674,8 +675,57 @@ func (c mappedScraperAttrConfig) postProcess(value string, q mappedQuery) string
 	return value
 }
 
+// validate returns an error if the selector is rejected by validSelector,
+// or a replace regex cannot be compiled. Sub-scraper selectors are also
+// validated.
+func (c mappedScraperAttrConfig) validate(common commonMappedConfig, validSelector func(string) error) error {
+	if c.Fixed == "" && c.Selector != "" && validSelector != nil {
+		selector := mappedConfig{}.applyCommon(common, c.Selector)
+		if err := validSelector(selector); err != nil {
+			return fmt.Errorf("invalid selector '%s': %s", selector, err.Error())
+		}
+	}
+
+	for _, action := range c.postProcessActions {
+		switch a := action.(type) {
+		case *postProcessReplace:
+			for _, r := range *a {
+				if _, err := regexp.Compile(r.Regex); err != nil {
+					return fmt.Errorf("invalid regex '%s': %s", r.Regex, err.Error())
+				}
+			}
+		case *postProcessSubScraper:
+			// sub-scraper selectors are run without the common fields
+			if err := mappedScraperAttrConfig(*a).validate(nil, validSelector); err != nil {
+				return fmt.Errorf("subScraper: %s", err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
 type mappedScrapers map[string]*mappedScraper
 
+// validate returns an error for the first invalid scraper, sorted by name.
+// The name of the scrapers' configuration section is used as the error
+// prefix.
+func (s mappedScrapers) validate(section string, validSelector func(string) error) error {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := s[name].validate(validSelector); err != nil {
+			return fmt.Errorf("%s.%s: %s", section, name, err.Error())
+		}
+	}
+
+	return nil
+}
+
 type mappedScraper struct {
 	Common    commonMappedConfig            `yaml:"common"`
 	Scene     *mappedSceneScraperConfig     `yaml:"scene"`
@@ -684,6 +734,57 @@ type mappedScraper struct {
 	Movie     *mappedMovieScraperConfig     `yaml:"movie"`
 }
 
+// validate returns an error if a field of the scraper is invalid. Selectors
+// are checked with validSelector, if not nil.
+func (s *mappedScraper) validate(validSelector func(string) error) error {
+	if s == nil {
+		return errors.New("scraper is empty")
+	}
+
+	configs := make(map[string]mappedConfig)
+	if s.Scene != nil {
+		configs["scene"] = s.Scene.mappedConfig
+		configs["scene.Tags"] = s.Scene.Tags
+		configs["scene.Performers"] = s.Scene.Performers.mappedConfig
+		configs["scene.Performers.Tags"] = s.Scene.Performers.Tags
+		configs["scene.Studio"] = s.Scene.Studio
+		configs["scene.Movies"] = s.Scene.Movies
+	}
+	if s.Gallery != nil {
+		configs["gallery"] = s.Gallery.mappedConfig
+		configs["gallery.Tags"] = s.Gallery.Tags
+		configs["gallery.Performers"] = s.Gallery.Performers
+		configs["gallery.Studio"] = s.Gallery.Studio
+	}
+	if s.Performer != nil {
+		configs["performer"] = s.Performer.mappedConfig
+		configs["performer.Tags"] = s.Performer.Tags
+	}
+	if s.Movie != nil {
+		configs["movie"] = s.Movie.mappedConfig
+		configs["movie.Studio"] = s.Movie.Studio
+	}
+
+	// report the first error in a consistent order
+	var names []string
+	for name, c := range configs {
+		for field := range c {
+			names = append(names, name+"."+field)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		i := strings.LastIndex(name, ".")
+		attrConfig := configs[name[:i]][name[i+1:]]
+		if err := attrConfig.validate(s.Common, validSelector); err != nil {
+			return fmt.Errorf("%s: %s", name, err.Error())
+		}
+	}
+
+	return nil
+}
+
 type mappedResult map[string]string
 type mappedResults []mappedResult
 
@@ -860,10 +961,10 @@ func (s mappedScraper) scrapeGallery(q mappedQuery) (*models.ScrapedGallery, err
 	var ret models.ScrapedGallery
 
 	galleryScraperConfig := s.Gallery
-	galleryMap := galleryScraperConfig.mappedConfig
-	if galleryMap == nil {
+	if galleryScraperConfig == nil || galleryScraperConfig.mappedConfig == nil {
 		return nil, nil
 	}
+	galleryMap := galleryScraperConfig.mappedConfig
 
 	galleryPerformersMap := galleryScraperConfig.Performers
 	galleryTagsMap := galleryScraperConfig.Tags
@@ -916,10 +1017,10 @@ func (s mappedScraper) scrapeMovie(q mappedQuery) (*models.ScrapedMovie, error)
 	var ret models.ScrapedMovie
 
 	movieScraperConfig := s.Movie
-	movieMap := movieScraperConfig.mappedConfig
-	if movieMap == nil {
+	if movieScraperConfig == nil || movieScraperConfig.mappedConfig == nil {
 		return nil, nil
 	}
+	movieMap := movieScraperConfig.mappedConfig
 
 	movieStudioMap := movieScraperConfig.Studio
 
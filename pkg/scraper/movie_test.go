@@ -0,0 +1,108 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const movieHTML = `
+<div class="movie">
+	<h1>Movie Name</h1>
+	<span class="studio">Movie Studio</span>
+	<img class="front" src="/front.jpg"/>
+	<img class="back" src="/back.jpg"/>
+</div>
+`
+
+func TestScrapeMovieURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/front.jpg", "/back.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			fmt.Fprint(w, r.URL.Path)
+		default:
+			fmt.Fprint(w, movieHTML)
+		}
+	}))
+	defer ts.Close()
+
+	cache, txnManager := makeScraperCache(t, `name: Test
+movieByURL:
+  - action: scrapeXPath
+    url:
+      - `+ts.URL+`
+    scraper: movieScraper
+xPathScrapers:
+  movieScraper:
+    movie:
+      Name: //h1
+      Studio:
+        Name: //span[@class="studio"]
+      FrontImage:
+        selector: //img[@class="front"]/@src
+        postProcess:
+          - replace:
+              - regex: ^
+                with: `+ts.URL+`
+      BackImage:
+        selector: //img[@class="back"]/@src
+        postProcess:
+          - replace:
+              - regex: ^
+                with: `+ts.URL+`
+`)
+
+	const studioID = 10
+	studioReaderWriter := txnManager.Studio().(*mocks.StudioReaderWriter)
+	studioReaderWriter.On("FindByName", "Movie Studio", true).Return(&models.Studio{ID: studioID}, nil).Once()
+
+	movie, err := cache.ScrapeMovieURL(ts.URL + "/movie")
+	if !assert.Nil(t, err) || !assert.NotNil(t, movie) {
+		return
+	}
+
+	verifyField(t, "Movie Name", movie.Name, "Name")
+
+	// the studio is matched with the existing studio
+	if assert.NotNil(t, movie.Studio) {
+		assert.Equal(t, "Movie Studio", movie.Studio.Name)
+		verifyField(t, "10", movie.Studio.ID, "Studio.ID")
+	}
+
+	// the images are downloaded
+	verifyField(t, "data:image/jpeg;base64,"+utils.GetBase64StringFromData([]byte("/front.jpg")), movie.FrontImage, "FrontImage")
+	verifyField(t, "data:image/jpeg;base64,"+utils.GetBase64StringFromData([]byte("/back.jpg")), movie.BackImage, "BackImage")
+
+	studioReaderWriter.AssertExpectations(t)
+}
+
+func TestScrapeMovieURLNoMovie(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, movieHTML)
+	}))
+	defer ts.Close()
+
+	// the scraper has no movie configuration
+	cache, _ := makeScraperCache(t, `name: Test
+movieByURL:
+  - action: scrapeXPath
+    url:
+      - `+ts.URL+`
+    scraper: sceneScraper
+xPathScrapers:
+  sceneScraper:
+    scene:
+      Title: //h1
+`)
+
+	movie, err := cache.ScrapeMovieURL(ts.URL + "/movie")
+	assert.Nil(t, err)
+	assert.Nil(t, movie)
+}
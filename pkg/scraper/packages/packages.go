@@ -0,0 +1,530 @@
+// Package packages installs and updates scrapers from package sources.
+//
+// A source is a yaml index file listing the id, name, version, path and
+// optional sha256 checksum of each package it provides, and the ids of the
+// packages each requires. The path of a package is relative to the index URL, and is either a zip
+// archive of the package files or a single scraper yml file. Packages are
+// installed into their own directory of the packages directory of the
+// scrapers path, along with a manifest recording the installed version.
+package packages
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	// Dir is the directory of the scrapers path into which packages are
+	// installed.
+	Dir = "packages"
+
+	// manifestFile is the file in each package directory recording the
+	// installed package. It has no extension so that it is not loaded as a
+	// scraper.
+	manifestFile = "manifest"
+
+	// timeout of each request to a source
+	requestTimeout = time.Minute
+
+	// maximum size of an index or package
+	maxDownloadSize = 50 * 1024 * 1024
+
+	// maximum total size of the files extracted from a package
+	maxExtractedSize = 200 * 1024 * 1024
+)
+
+// Package is a package listed in the index of a source.
+type Package struct {
+	ID       string   `yaml:"id"`
+	Name     string   `yaml:"name"`
+	Version  string   `yaml:"version"`
+	Date     string   `yaml:"date"`
+	Path     string   `yaml:"path"`
+	Sha256   string   `yaml:"sha256"`
+	Requires []string `yaml:"requires"`
+}
+
+// Manifest records an installed package.
+type Manifest struct {
+	ID        string `yaml:"id"`
+	Name      string `yaml:"name"`
+	Version   string `yaml:"version"`
+	Date      string `yaml:"date"`
+	SourceURL string `yaml:"source_url"`
+	// Files are the paths of the installed files, relative to the package
+	// directory.
+	Files []string `yaml:"files"`
+}
+
+// Manager installs packages into the packages directory of the scrapers
+// path.
+type Manager struct {
+	ScrapersPath string
+	Client       *http.Client
+}
+
+// NewManager returns a manager installing packages into scrapersPath.
+func NewManager(scrapersPath string) *Manager {
+	return &Manager{
+		ScrapersPath: scrapersPath,
+		Client: &http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+}
+
+func (m *Manager) packagesPath() string {
+	return filepath.Join(m.ScrapersPath, Dir)
+}
+
+func (m *Manager) packagePath(id string) string {
+	return filepath.Join(m.packagesPath(), id)
+}
+
+func validateID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid package id '%s'", id)
+	}
+
+	return nil
+}
+
+func (m *Manager) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("http error %d getting %s", resp.StatusCode, u)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDownloadSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", u, maxDownloadSize)
+	}
+
+	return body, nil
+}
+
+// ListAvailable returns the packages in the index of the source, sorted by
+// name.
+func (m *Manager) ListAvailable(ctx context.Context, sourceURL string) ([]Package, error) {
+	body, err := m.get(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Package
+	if err := yaml.Unmarshal(body, &ret); err != nil {
+		return nil, fmt.Errorf("error parsing index %s: %s", sourceURL, err.Error())
+	}
+
+	for _, p := range ret {
+		if err := validateID(p.ID); err != nil {
+			return nil, fmt.Errorf("index %s: %s", sourceURL, err.Error())
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return strings.ToLower(ret[i].Name) < strings.ToLower(ret[j].Name)
+	})
+
+	return ret, nil
+}
+
+// ListInstalled returns the manifests of the installed packages, sorted by
+// name.
+func (m *Manager) ListInstalled() ([]Manifest, error) {
+	dirs, err := ioutil.ReadDir(m.packagesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Manifest
+	for _, d := range dirs {
+		// skip the temporary directories of packages being installed
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+
+		manifest, err := m.readManifest(d.Name())
+		if err != nil {
+			logger.Warnf("Error reading scraper package manifest of %s: %s", d.Name(), err.Error())
+			continue
+		}
+		if manifest != nil {
+			ret = append(ret, *manifest)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return strings.ToLower(ret[i].Name) < strings.ToLower(ret[j].Name)
+	})
+
+	return ret, nil
+}
+
+// readManifest returns the manifest of the installed package, or nil if
+// the package is not installed.
+func (m *Manager) readManifest(id string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(m.packagePath(id), manifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret Manifest
+	if err := yaml.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+// resolve returns the packages with the ids and the packages they require,
+// with the required packages before the packages requiring them.
+func resolve(available []Package, ids []string) ([]Package, error) {
+	byID := make(map[string]Package)
+	for _, p := range available {
+		byID[p.ID] = p
+	}
+
+	var ret []Package
+	added := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var add func(id string) error
+	add = func(id string) error {
+		if added[id] {
+			return nil
+		}
+		if visiting[id] {
+			return fmt.Errorf("package '%s' requires itself", id)
+		}
+
+		p, ok := byID[id]
+		if !ok {
+			return fmt.Errorf("package '%s' not found", id)
+		}
+
+		visiting[id] = true
+		for _, r := range p.Requires {
+			if err := add(r); err != nil {
+				return err
+			}
+		}
+		visiting[id] = false
+
+		added[id] = true
+		ret = append(ret, p)
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := add(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// Install installs the packages with the ids from the source, along with
+// the packages they require. Installed packages are replaced. Returns the
+// ids of the installed packages.
+func (m *Manager) Install(ctx context.Context, sourceURL string, ids []string) ([]string, error) {
+	available, err := m.ListAvailable(ctx, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	toInstall, err := resolve(available, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	for _, p := range toInstall {
+		if err := ctx.Err(); err != nil {
+			return ret, err
+		}
+
+		if err := m.install(ctx, sourceURL, p); err != nil {
+			return ret, fmt.Errorf("error installing package '%s': %s", p.ID, err.Error())
+		}
+
+		logger.Infof("Installed scraper package %s version %s", p.ID, p.Version)
+		ret = append(ret, p.ID)
+	}
+
+	return ret, nil
+}
+
+func (m *Manager) install(ctx context.Context, sourceURL string, p Package) error {
+	base, err := url.Parse(sourceURL)
+	if err != nil {
+		return err
+	}
+	ref, err := url.Parse(p.Path)
+	if err != nil {
+		return err
+	}
+	packageURL := base.ResolveReference(ref).String()
+
+	data, err := m.get(ctx, packageURL)
+	if err != nil {
+		return err
+	}
+
+	if p.Sha256 != "" {
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), p.Sha256) {
+			return errors.New("sha256 of the package does not match the index")
+		}
+	}
+
+	// write to a temporary directory first, so that the installed package is
+	// only replaced once the new version has been written
+	if err := os.MkdirAll(m.packagesPath(), 0755); err != nil {
+		return err
+	}
+	tmpDir, err := ioutil.TempDir(m.packagesPath(), "."+p.ID+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var files []string
+	if strings.EqualFold(path.Ext(ref.Path), ".yml") {
+		name := path.Base(ref.Path)
+		if err := ioutil.WriteFile(filepath.Join(tmpDir, name), data, 0644); err != nil {
+			return err
+		}
+		files = []string{name}
+	} else {
+		files, err = extractZip(data, tmpDir, maxExtractedSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	manifest, err := yaml.Marshal(Manifest{
+		ID:        p.ID,
+		Name:      p.Name,
+		Version:   p.Version,
+		Date:      p.Date,
+		SourceURL: sourceURL,
+		Files:     files,
+	})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, manifestFile), manifest, 0644); err != nil {
+		return err
+	}
+
+	dest := m.packagePath(p.ID)
+	if err := os.RemoveAll(dest); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpDir, dest)
+}
+
+// extractZip extracts the files of the zip archive into dir, returning the
+// paths of the extracted files relative to dir. Returns an error if the
+// files total more than maxSize bytes, regardless of the sizes recorded in
+// the archive.
+func extractZip(data []byte, dir string, maxSize int64) ([]string, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	remaining := maxSize
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		name := filepath.FromSlash(f.Name)
+		dest := filepath.Join(dir, name)
+		if !strings.HasPrefix(dest, filepath.Clean(dir)+string(filepath.Separator)) {
+			return nil, fmt.Errorf("invalid file path in package: %s", f.Name)
+		}
+
+		n, err := extractZipFile(f, dest, remaining)
+		if err != nil {
+			return nil, err
+		}
+		if n > remaining {
+			return nil, fmt.Errorf("package is larger than %d bytes when extracted", maxSize)
+		}
+		remaining -= n
+
+		ret = append(ret, filepath.ToSlash(name))
+	}
+
+	return ret, nil
+}
+
+// extractZipFile extracts f to dest, returning the number of bytes written.
+// No more than maxSize+1 bytes are written, so that the caller can tell if f
+// is larger than maxSize.
+func extractZipFile(f *zip.File, dest string, maxSize int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return 0, err
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode()|0600)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	return io.Copy(out, io.LimitReader(src, maxSize+1))
+}
+
+// Update is an installed package with a different version available from
+// its source.
+type Update struct {
+	Installed Manifest
+	Available Package
+}
+
+// Updates returns the installed packages whose source lists a different
+// version. Sources which cannot be read are logged and skipped.
+func (m *Manager) Updates(ctx context.Context) ([]Update, error) {
+	installed, err := m.ListInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string][]Package)
+	var ret []Update
+	for _, i := range installed {
+		available, found := sources[i.SourceURL]
+		if !found {
+			available, err = m.ListAvailable(ctx, i.SourceURL)
+			if err != nil {
+				logger.Warnf("Error listing scraper packages of %s: %s", i.SourceURL, err.Error())
+			}
+			sources[i.SourceURL] = available
+		}
+
+		for _, p := range available {
+			if p.ID == i.ID && p.Version != i.Version {
+				ret = append(ret, Update{
+					Installed: i,
+					Available: p,
+				})
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// Update installs the available version of the installed packages with the
+// ids, or of all installed packages if ids is empty. Packages already at
+// the available version are not reinstalled. Returns the ids of the updated
+// packages.
+func (m *Manager) Update(ctx context.Context, ids []string) ([]string, error) {
+	updates, err := m.Updates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// group by source, preserving the order in which the sources are listed
+	var sourceURLs []string
+	bySource := make(map[string][]string)
+	for _, u := range updates {
+		if len(ids) > 0 && !utils.StrInclude(ids, u.Installed.ID) {
+			continue
+		}
+
+		sourceURL := u.Installed.SourceURL
+		if _, found := bySource[sourceURL]; !found {
+			sourceURLs = append(sourceURLs, sourceURL)
+		}
+		bySource[sourceURL] = append(bySource[sourceURL], u.Installed.ID)
+	}
+
+	var ret []string
+	for _, sourceURL := range sourceURLs {
+		updated, err := m.Install(ctx, sourceURL, bySource[sourceURL])
+		ret = append(ret, updated...)
+		if err != nil {
+			return ret, err
+		}
+	}
+
+	return ret, nil
+}
+
+// Uninstall removes the installed packages with the ids.
+func (m *Manager) Uninstall(ids []string) error {
+	for _, id := range ids {
+		if err := validateID(id); err != nil {
+			return err
+		}
+
+		manifest, err := m.readManifest(id)
+		if err != nil {
+			return err
+		}
+		if manifest == nil {
+			return fmt.Errorf("package '%s' is not installed", id)
+		}
+
+		if err := os.RemoveAll(m.packagePath(id)); err != nil {
+			return err
+		}
+
+		logger.Infof("Uninstalled scraper package %s", id)
+	}
+
+	return nil
+}
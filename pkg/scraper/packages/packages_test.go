@@ -0,0 +1,272 @@
+package packages
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Error creating zip file: %s", err.Error())
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("Error writing zip file: %s", err.Error())
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing zip: %s", err.Error())
+	}
+
+	return buf.Bytes()
+}
+
+func sha(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// testSource serves an index and the package files it lists.
+type testSource struct {
+	index string
+	files map[string][]byte
+}
+
+func (s *testSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/stable/index.yml" {
+		fmt.Fprint(w, s.index)
+		return
+	}
+
+	data, found := s.files[r.URL.Path]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func newTestManager(t *testing.T) (*Manager, func()) {
+	dir, err := ioutil.TempDir("", "scrapers")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err.Error())
+	}
+
+	return NewManager(dir), func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestInstallUpdateUninstall(t *testing.T) {
+	pythonZip := makeZip(t, map[string]string{
+		"py_common/log.py": "log",
+	})
+	scraperZip := makeZip(t, map[string]string{
+		"site.yml": "name: Site",
+		"site.py":  "site",
+	})
+
+	source := &testSource{
+		files: map[string][]byte{
+			"/stable/py_common.zip": pythonZip,
+			"/stable/site.zip":      scraperZip,
+			"/stable/other.yml":     []byte("name: Other"),
+		},
+	}
+	source.index = fmt.Sprintf(`- id: site
+  name: Site
+  version: v1
+  path: site.zip
+  sha256: %s
+  requires:
+    - py_common
+- id: py_common
+  name: Python Common
+  version: v1
+  path: py_common.zip
+  sha256: %s
+- id: other
+  name: Other
+  version: v1
+  path: other.yml
+`, sha(scraperZip), sha(pythonZip))
+
+	ts := httptest.NewServer(source)
+	defer ts.Close()
+	sourceURL := ts.URL + "/stable/index.yml"
+
+	m, cleanup := newTestManager(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	available, err := m.ListAvailable(ctx, sourceURL)
+	if assert.Nil(t, err) && assert.Len(t, available, 3) {
+		// sorted by name
+		assert.Equal(t, "other", available[0].ID)
+		assert.Equal(t, "py_common", available[1].ID)
+		assert.Equal(t, "site", available[2].ID)
+	}
+
+	// required packages are installed first
+	installed, err := m.Install(ctx, sourceURL, []string{"site", "other"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"py_common", "site", "other"}, installed)
+
+	assert.FileExists(t, filepath.Join(m.ScrapersPath, Dir, "site", "site.yml"))
+	assert.FileExists(t, filepath.Join(m.ScrapersPath, Dir, "py_common", "py_common", "log.py"))
+	assert.FileExists(t, filepath.Join(m.ScrapersPath, Dir, "other", "other.yml"))
+
+	manifests, err := m.ListInstalled()
+	if assert.Nil(t, err) && assert.Len(t, manifests, 3) {
+		site := manifests[2]
+		assert.Equal(t, "site", site.ID)
+		assert.Equal(t, "v1", site.Version)
+		assert.Equal(t, sourceURL, site.SourceURL)
+		assert.ElementsMatch(t, []string{"site.yml", "site.py"}, site.Files)
+	}
+
+	updates, err := m.Updates(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, updates, 0)
+
+	// publish a new version of the scraper
+	scraperZip = makeZip(t, map[string]string{
+		"site.yml": "name: Site v2",
+	})
+	source.files["/stable/site.zip"] = scraperZip
+	source.index = fmt.Sprintf(`- id: site
+  name: Site
+  version: v2
+  path: site.zip
+  sha256: %s
+- id: other
+  name: Other
+  version: v1
+  path: other.yml
+`, sha(scraperZip))
+
+	updates, err = m.Updates(ctx)
+	// py_common is no longer listed by the source, and other is unchanged
+	if assert.Nil(t, err) && assert.Len(t, updates, 1) {
+		assert.Equal(t, "site", updates[0].Installed.ID)
+		assert.Equal(t, "v2", updates[0].Available.Version)
+	}
+
+	updated, err := m.Update(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"site"}, updated)
+
+	data, _ := ioutil.ReadFile(filepath.Join(m.ScrapersPath, Dir, "site", "site.yml"))
+	assert.Equal(t, "name: Site v2", string(data))
+	assert.NoFileExists(t, filepath.Join(m.ScrapersPath, Dir, "site", "site.py"))
+
+	assert.Nil(t, m.Uninstall([]string{"site"}))
+	assert.NoDirExists(t, filepath.Join(m.ScrapersPath, Dir, "site"))
+	assert.NotNil(t, m.Uninstall([]string{"site"}))
+
+	manifests, err = m.ListInstalled()
+	assert.Nil(t, err)
+	assert.Len(t, manifests, 2)
+}
+
+func TestInstallErrors(t *testing.T) {
+	scraperZip := makeZip(t, map[string]string{
+		"site.yml": "name: Site",
+	})
+	slipZip := makeZip(t, map[string]string{
+		"../site.yml": "name: Site",
+	})
+
+	source := &testSource{
+		files: map[string][]byte{
+			"/stable/site.zip": scraperZip,
+			"/stable/slip.zip": slipZip,
+		},
+		index: `- id: checksum
+  name: Checksum
+  path: site.zip
+  sha256: 0123
+- id: slip
+  name: Slip
+  path: slip.zip
+- id: missing
+  name: Missing
+  path: missing.zip
+- id: requires
+  name: Requires
+  path: site.zip
+  requires:
+    - unknown
+`,
+	}
+
+	ts := httptest.NewServer(source)
+	defer ts.Close()
+	sourceURL := ts.URL + "/stable/index.yml"
+
+	m, cleanup := newTestManager(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, id := range []string{"checksum", "slip", "missing", "requires", "unknown"} {
+		_, err := m.Install(ctx, sourceURL, []string{id})
+		assert.NotNil(t, err, id)
+	}
+
+	assert.NoFileExists(t, filepath.Join(m.ScrapersPath, "site.yml"))
+
+	manifests, err := m.ListInstalled()
+	assert.Nil(t, err)
+	assert.Len(t, manifests, 0)
+
+	// only the ids of the packages directory can be uninstalled
+	assert.NotNil(t, m.Uninstall([]string{".."}))
+}
+
+func TestListAvailableInvalidID(t *testing.T) {
+	ts := httptest.NewServer(&testSource{
+		index: "- id: ../escape\n  name: Escape\n",
+	})
+	defer ts.Close()
+
+	m, cleanup := newTestManager(t)
+	defer cleanup()
+
+	_, err := m.ListAvailable(context.Background(), ts.URL+"/stable/index.yml")
+	assert.NotNil(t, err)
+}
+
+func TestExtractZipMaxSize(t *testing.T) {
+	data := makeZip(t, map[string]string{
+		"a.yml": "0123456789",
+		"b.yml": "0123456789",
+	})
+
+	dir, err := ioutil.TempDir("", "stash-packages")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = extractZip(data, filepath.Join(dir, "small"), 15)
+	assert.NotNil(t, err)
+
+	files, err := extractZip(data, filepath.Join(dir, "exact"), 20)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+}
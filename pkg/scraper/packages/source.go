@@ -0,0 +1,39 @@
+package packages
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ValidateSources returns an error if the URL of a package source is not an
+// http or https URL, or is configured more than once.
+func ValidateSources(sources []*models.ScraperPackageSourceInput) error {
+	urls := make(map[string]bool)
+	for _, s := range sources {
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid scraper package source URL: %s", s.URL)
+		}
+
+		if urls[s.URL] {
+			return fmt.Errorf("scraper package source %s is configured more than once", s.URL)
+		}
+		urls[s.URL] = true
+	}
+
+	return nil
+}
+
+// FindSource returns the configured source with the URL, or nil if it is not
+// configured.
+func FindSource(sources []*models.ScraperPackageSource, sourceURL string) *models.ScraperPackageSource {
+	for _, s := range sources {
+		if s.URL == sourceURL {
+			return s
+		}
+	}
+
+	return nil
+}
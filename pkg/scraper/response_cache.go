@@ -0,0 +1,216 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// the subdirectory of the cache directory in which responses are stored
+const responseCacheDir = "scraper"
+
+// expired responses are kept for this long, to be used if the source cannot
+// be reached
+const responseCacheRetention = 7 * 24 * time.Hour
+
+// responseCacheMutex serialises writes to and removals from the response
+// cache.
+var responseCacheMutex sync.Mutex
+
+// cachedResponse is the body of a successful response to a GET request of a
+// scraper, stored in the response cache.
+type cachedResponse struct {
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	Fetched     time.Time `json:"fetched"`
+	Body        []byte    `json:"body"`
+}
+
+// responseCache is an on-disk cache of scraper responses, keyed by the URL
+// and the headers and cookies of the request. See responseCacheKey. It is
+// disabled if the cache path or the time to live is not set.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+	// if true, responses are not read from the cache, but are still stored
+	bypass bool
+}
+
+// uncachedConfig wraps the global config so that scraper responses are not
+// read from the response cache.
+type uncachedConfig struct {
+	GlobalConfig
+}
+
+func newResponseCache(globalConfig GlobalConfig) *responseCache {
+	ret := &responseCache{
+		ttl: globalConfig.GetScraperCacheTTL(),
+	}
+
+	if cachePath := globalConfig.GetCachePath(); cachePath != "" {
+		ret.dir = filepath.Join(cachePath, responseCacheDir)
+	}
+
+	_, ret.bypass = globalConfig.(uncachedConfig)
+
+	return ret
+}
+
+// responseCacheKey returns the cache key of the response to a request of the
+// scraper for url. Sites may respond differently depending on the headers
+// and cookies sent, such as those used to log in, so the key includes a hash
+// of the request options of the scraper and its source.
+func responseCacheKey(url string, scraperConfig config, globalConfig GlobalConfig) string {
+	request := struct {
+		Source        *models.ScraperSource
+		DriverOptions *scraperDriverOptions
+		UserAgent     string
+	}{
+		Source:        getSource(globalConfig, scraperConfig.ID),
+		DriverOptions: scraperConfig.DriverOptions,
+		UserAgent:     globalConfig.GetScraperUserAgent(),
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	data, _ := json.Marshal(request)
+	hash := sha256.Sum256(data)
+	return url + " " + hex.EncodeToString(hash[:])
+}
+
+func (c *responseCache) enabled() bool {
+	return c.dir != "" && c.ttl > 0
+}
+
+func (c *responseCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (c *responseCache) read(key string) *cachedResponse {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("[scraper] error reading cached response for %s: %s", key, err.Error())
+		}
+		return nil
+	}
+
+	var ret cachedResponse
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	if err := json.Unmarshal(data, &ret); err != nil || ret.Key != key {
+		// corrupt, or a hash collision
+		return nil
+	}
+
+	return &ret
+}
+
+// get returns the cached response for key if it was fetched within the time
+// to live. Returns nil if there is none, or if the cache is bypassed.
+func (c *responseCache) get(key string) *cachedResponse {
+	if !c.enabled() || c.bypass {
+		return nil
+	}
+
+	ret := c.read(key)
+	if ret == nil || time.Since(ret.Fetched) > c.ttl {
+		return nil
+	}
+
+	return ret
+}
+
+// getStale returns the cached response for key regardless of its age, to be
+// used when the source cannot be reached.
+func (c *responseCache) getStale(key string) *cachedResponse {
+	if !c.enabled() {
+		return nil
+	}
+
+	return c.read(key)
+}
+
+// put stores the response body for key in the cache. Errors are logged.
+func (c *responseCache) put(key string, contentType string, body []byte) {
+	if !c.enabled() {
+		return
+	}
+
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	data, err := json.Marshal(cachedResponse{
+		Key:         key,
+		ContentType: contentType,
+		Fetched:     time.Now(),
+		Body:        body,
+	})
+	if err != nil {
+		logger.Warnf("[scraper] error caching response for %s: %s", key, err.Error())
+		return
+	}
+
+	responseCacheMutex.Lock()
+	defer responseCacheMutex.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		logger.Warnf("[scraper] error creating response cache directory: %s", err.Error())
+		return
+	}
+
+	// write to a temporary file first, so that partly written responses are
+	// not read
+	path := c.path(key)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		logger.Warnf("[scraper] error caching response for %s: %s", key, err.Error())
+		return
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		logger.Warnf("[scraper] error caching response for %s: %s", key, err.Error())
+	}
+}
+
+// purge removes the responses which were cached longer ago than the time to
+// live and the retention period, or all responses if the cache is disabled.
+func (c *responseCache) purge() {
+	if c.dir == "" {
+		return
+	}
+
+	responseCacheMutex.Lock()
+	defer responseCacheMutex.Unlock()
+
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("[scraper] error reading response cache directory: %s", err.Error())
+		}
+		return
+	}
+
+	maxAge := c.ttl + responseCacheRetention
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+
+		if c.enabled() && time.Since(f.ModTime()) <= maxAge {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, f.Name())); err != nil {
+			logger.Warnf("[scraper] error removing cached response: %s", err.Error())
+		}
+	}
+}
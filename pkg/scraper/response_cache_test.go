@@ -0,0 +1,187 @@
+package scraper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type cacheGlobalConfig struct {
+	mockGlobalConfig
+	cachePath string
+	ttl       time.Duration
+	sources   []*models.ScraperSource
+}
+
+func (c cacheGlobalConfig) GetScraperSources() []*models.ScraperSource {
+	return c.sources
+}
+
+func (c cacheGlobalConfig) GetCachePath() string {
+	return c.cachePath
+}
+
+func (c cacheGlobalConfig) GetScraperCacheTTL() time.Duration {
+	return c.ttl
+}
+
+func readURL(t *testing.T, url string, globalConfig GlobalConfig) string {
+	r, err := loadURL(url, config{}, globalConfig)
+	if !assert.Nil(t, err) {
+		return ""
+	}
+
+	body, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	return string(body)
+}
+
+func TestResponseCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-scraper-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, "response %d", requests)
+	}))
+	defer ts.Close()
+
+	globalConfig := cacheGlobalConfig{
+		cachePath: dir,
+		ttl:       time.Hour,
+	}
+	url := ts.URL + "/page"
+
+	assert.Equal(t, "response 1", readURL(t, url, globalConfig))
+	assert.Equal(t, "response 1", readURL(t, url, globalConfig))
+	assert.Equal(t, "response 2", readURL(t, ts.URL+"/other", globalConfig))
+
+	// bypassing the cache loads and stores the page
+	assert.Equal(t, "response 3", readURL(t, url, uncachedConfig{globalConfig}))
+	assert.Equal(t, "response 3", readURL(t, url, globalConfig))
+
+	// expired responses are loaded again
+	expired := globalConfig
+	expired.ttl = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, "response 4", readURL(t, url, expired))
+
+	// expired responses are used if the page cannot be loaded
+	ts.Close()
+	assert.Equal(t, "response 4", readURL(t, url, expired))
+
+	_, err = loadURL(ts.URL+"/missing", config{}, expired)
+	assert.NotNil(t, err)
+
+	// the cache is not used if disabled
+	disabled := globalConfig
+	disabled.ttl = 0
+	_, err = loadURL(url, config{}, disabled)
+	assert.NotNil(t, err)
+}
+
+func TestResponseCacheRequestOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-scraper-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var session string
+		if c, _ := r.Cookie("session"); c != nil {
+			session = c.Value
+		}
+		fmt.Fprintf(w, "%d %s %s", requests, r.Header.Get("X-Test"), session)
+	}))
+	defer ts.Close()
+
+	globalConfig := cacheGlobalConfig{
+		cachePath: dir,
+		ttl:       time.Hour,
+	}
+	url := ts.URL + "/page"
+
+	read := func(scraperConfig config, globalConfig GlobalConfig) string {
+		r, err := loadURL(url, scraperConfig, globalConfig)
+		if !assert.Nil(t, err) {
+			return ""
+		}
+
+		body, _ := ioutil.ReadAll(r)
+		return string(body)
+	}
+
+	withHeader := func(value string) config {
+		return config{
+			ID: "test",
+			DriverOptions: &scraperDriverOptions{
+				Headers: []*header{{Key: "X-Test", Value: value}},
+			},
+		}
+	}
+
+	assert.Equal(t, "1 a ", read(withHeader("a"), globalConfig))
+	assert.Equal(t, "2 b ", read(withHeader("b"), globalConfig))
+	assert.Equal(t, "1 a ", read(withHeader("a"), globalConfig))
+
+	// the cookies of the source are sent with the request
+	loggedIn := globalConfig
+	loggedIn.sources = []*models.ScraperSource{{
+		Scraper: "test",
+		Cookies: []*models.ScraperCookie{{Name: "session", Value: "secret"}},
+	}}
+	assert.Equal(t, "3 a secret", read(withHeader("a"), loggedIn))
+	assert.Equal(t, "3 a secret", read(withHeader("a"), loggedIn))
+	assert.Equal(t, "1 a ", read(withHeader("a"), globalConfig))
+}
+
+func TestResponseCachePurge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-scraper-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := newResponseCache(cacheGlobalConfig{
+		cachePath: dir,
+		ttl:       time.Hour,
+	})
+
+	const (
+		oldURL    = "http://example.com/old"
+		recentURL = "http://example.com/recent"
+	)
+
+	c.put(oldURL, "text/html", []byte("old"))
+	c.put(recentURL, "text/html", []byte("recent"))
+
+	old := time.Now().Add(-(time.Hour + responseCacheRetention + time.Minute))
+	if err := os.Chtimes(c.path(oldURL), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	c.purge()
+	assert.Nil(t, c.getStale(oldURL))
+	assert.NotNil(t, c.getStale(recentURL))
+
+	// all responses are removed if the cache is disabled
+	c.ttl = 0
+	c.purge()
+	c.ttl = time.Hour
+	assert.Nil(t, c.getStale(recentURL))
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -19,6 +20,27 @@ type GlobalConfig interface {
 	GetScrapersPath() string
 	GetScraperCDPPath() string
 	GetScraperCertCheck() bool
+	// GetConfigPath returns the directory chromium is downloaded to, if
+	// chrome is not installed.
+	GetConfigPath() string
+	// GetCachePath returns the directory in which scraper responses are
+	// cached.
+	GetCachePath() string
+	// GetScraperCacheTTL returns how long scraper responses are cached for.
+	// Responses are not cached if it is zero.
+	GetScraperCacheTTL() time.Duration
+	// GetScraperSources returns the proxies, headers and cookies configured
+	// for scrapers.
+	GetScraperSources() []*models.ScraperSource
+	// GetScraperRequestInterval returns the minimum time between the start
+	// of requests to the same domain.
+	GetScraperRequestInterval() time.Duration
+	// GetScraperRequestJitter returns the maximum random delay added to the
+	// request interval.
+	GetScraperRequestJitter() time.Duration
+	// GetScraperMaxConcurrency returns the maximum number of simultaneous
+	// requests to the same domain, or zero if not limited.
+	GetScraperMaxConcurrency() int
 }
 
 func isCDPPathHTTP(c GlobalConfig) bool {
@@ -32,6 +54,8 @@ func isCDPPathWS(c GlobalConfig) bool {
 // Cache stores scraper details.
 type Cache struct {
 	scrapers     []config
+	loadErrors   []*models.ScraperLoadError
+	traces       *traceStore
 	globalConfig GlobalConfig
 	txnManager   models.TransactionManager
 }
@@ -43,19 +67,25 @@ type Cache struct {
 // Scraper configurations are loaded from yml files in the provided scrapers
 // directory and any subdirectories.
 func NewCache(globalConfig GlobalConfig, txnManager models.TransactionManager) (*Cache, error) {
-	scrapers, err := loadScrapers(globalConfig.GetScrapersPath())
+	scrapers, loadErrors, err := loadScrapers(globalConfig.GetScrapersPath())
 	if err != nil {
 		return nil, err
 	}
 
+	newResponseCache(globalConfig).purge()
+
 	return &Cache{
 		globalConfig: globalConfig,
 		scrapers:     scrapers,
+		loadErrors:   loadErrors,
+		traces:       newTraceStore(),
 		txnManager:   txnManager,
 	}, nil
 }
 
-func loadScrapers(path string) ([]config, error) {
+// loadScrapers loads the scraper configurations from the yml files in path.
+// Files which cannot be loaded are skipped, and their errors returned.
+func loadScrapers(path string) ([]config, []*models.ScraperLoadError, error) {
 	scrapers := make([]config, 0)
 
 	logger.Debugf("Reading scraper configs from %s", path)
@@ -69,37 +99,66 @@ func loadScrapers(path string) ([]config, error) {
 
 	if err != nil {
 		logger.Errorf("Error reading scraper configs: %s", err.Error())
-		return nil, err
+		return nil, nil, err
 	}
 
 	// add built-in freeones scraper
 	scrapers = append(scrapers, getFreeonesScraper())
 
+	var loadErrors []*models.ScraperLoadError
 	for _, file := range scraperFiles {
 		scraper, err := loadScraperFromYAMLFile(file)
 		if err != nil {
 			logger.Errorf("Error loading scraper %s: %s", file, err.Error())
+			loadErrors = append(loadErrors, &models.ScraperLoadError{
+				Path:  file,
+				Error: err.Error(),
+			})
 		} else {
 			scrapers = append(scrapers, *scraper)
 		}
 	}
 
-	return scrapers, nil
+	return scrapers, loadErrors, nil
 }
 
 // ReloadScrapers clears the scraper cache and reloads from the scraper path.
 // In the event of an error during loading, the cache will be left empty.
 func (c *Cache) ReloadScrapers() error {
 	c.scrapers = nil
-	scrapers, err := loadScrapers(c.globalConfig.GetScrapersPath())
+	c.loadErrors = nil
+	scrapers, loadErrors, err := loadScrapers(c.globalConfig.GetScrapersPath())
 	if err != nil {
 		return err
 	}
 
 	c.scrapers = scrapers
+	c.loadErrors = loadErrors
 	return nil
 }
 
+// LoadErrors returns the errors of the scraper configuration files which
+// could not be loaded when the scrapers were last loaded.
+func (c Cache) LoadErrors() []*models.ScraperLoadError {
+	return c.loadErrors
+}
+
+// Traces returns the most recent scrapes by the scraper, newest first.
+// Scrapes are only traced for scrapers with debug tracing enabled.
+func (c Cache) Traces(scraperID string) []*models.ScraperTrace {
+	return c.traces.get(scraperID)
+}
+
+// Uncached returns a copy of the cache whose scrapers load pages from their
+// sources rather than the response cache. The loaded pages are still cached.
+func (c Cache) Uncached() *Cache {
+	if _, ok := c.globalConfig.(uncachedConfig); !ok {
+		c.globalConfig = uncachedConfig{c.globalConfig}
+	}
+
+	return &c
+}
+
 // TODO - don't think this is needed
 // UpdateConfig updates the global config for the cache. If the scraper path
 // has changed, ReloadScrapers will need to be called separately.
@@ -180,7 +239,10 @@ func (c Cache) ScrapePerformerList(scraperID string, query string) ([]*models.Sc
 	// find scraper with the provided id
 	s := c.findScraper(scraperID)
 	if s != nil {
-		return s.ScrapePerformerNames(query, c.txnManager, c.globalConfig)
+		traced, trace := c.traces.start(*s, "performerByName", query)
+		ret, err := traced.ScrapePerformerNames(query, c.txnManager, c.globalConfig)
+		c.traces.finish(trace, err)
+		return ret, err
 	}
 
 	return nil, errors.New("Scraper with ID " + scraperID + " not found")
@@ -192,7 +254,14 @@ func (c Cache) ScrapePerformer(scraperID string, scrapedPerformer models.Scraped
 	// find scraper with the provided id
 	s := c.findScraper(scraperID)
 	if s != nil {
-		ret, err := s.ScrapePerformer(scrapedPerformer, c.txnManager, c.globalConfig)
+		var input string
+		if scrapedPerformer.Name != nil {
+			input = *scrapedPerformer.Name
+		}
+
+		traced, trace := c.traces.start(*s, "performerByFragment", input)
+		ret, err := traced.ScrapePerformer(scrapedPerformer, c.txnManager, c.globalConfig)
+		c.traces.finish(trace, err)
 		if err != nil {
 			return nil, err
 		}
@@ -214,7 +283,9 @@ func (c Cache) ScrapePerformer(scraperID string, scrapedPerformer models.Scraped
 func (c Cache) ScrapePerformerURL(url string) (*models.ScrapedPerformer, error) {
 	for _, s := range c.scrapers {
 		if s.matchesPerformerURL(url) {
+			s, trace := c.traces.start(s, "performerByURL", url)
 			ret, err := s.ScrapePerformerURL(url, c.txnManager, c.globalConfig)
+			c.traces.finish(trace, err)
 			if err != nil {
 				return nil, err
 			}
@@ -367,7 +438,9 @@ func (c Cache) ScrapeScene(scraperID string, scene models.SceneUpdateInput) (*mo
 	// find scraper with the provided id
 	s := c.findScraper(scraperID)
 	if s != nil {
-		ret, err := s.ScrapeScene(scene, c.txnManager, c.globalConfig)
+		traced, trace := c.traces.start(*s, "sceneByFragment", scene.ID)
+		ret, err := traced.ScrapeScene(scene, c.txnManager, c.globalConfig)
+		c.traces.finish(trace, err)
 
 		if err != nil {
 			return nil, err
@@ -392,7 +465,9 @@ func (c Cache) ScrapeScene(scraperID string, scene models.SceneUpdateInput) (*mo
 func (c Cache) ScrapeSceneURL(url string) (*models.ScrapedScene, error) {
 	for _, s := range c.scrapers {
 		if s.matchesSceneURL(url) {
+			s, trace := c.traces.start(s, "sceneByURL", url)
 			ret, err := s.ScrapeSceneURL(url, c.txnManager, c.globalConfig)
+			c.traces.finish(trace, err)
 
 			if err != nil {
 				return nil, err
@@ -414,7 +489,9 @@ func (c Cache) ScrapeSceneURL(url string) (*models.ScrapedScene, error) {
 func (c Cache) ScrapeGallery(scraperID string, gallery models.GalleryUpdateInput) (*models.ScrapedGallery, error) {
 	s := c.findScraper(scraperID)
 	if s != nil {
-		ret, err := s.ScrapeGallery(gallery, c.txnManager, c.globalConfig)
+		traced, trace := c.traces.start(*s, "galleryByFragment", gallery.ID)
+		ret, err := traced.ScrapeGallery(gallery, c.txnManager, c.globalConfig)
+		c.traces.finish(trace, err)
 
 		if err != nil {
 			return nil, err
@@ -439,15 +516,19 @@ func (c Cache) ScrapeGallery(scraperID string, gallery models.GalleryUpdateInput
 func (c Cache) ScrapeGalleryURL(url string) (*models.ScrapedGallery, error) {
 	for _, s := range c.scrapers {
 		if s.matchesGalleryURL(url) {
+			s, trace := c.traces.start(s, "galleryByURL", url)
 			ret, err := s.ScrapeGalleryURL(url, c.txnManager, c.globalConfig)
+			c.traces.finish(trace, err)
 
 			if err != nil {
 				return nil, err
 			}
 
-			err = c.postScrapeGallery(ret)
-			if err != nil {
-				return nil, err
+			if ret != nil {
+				err = c.postScrapeGallery(ret)
+				if err != nil {
+					return nil, err
+				}
 			}
 
 			return ret, nil
@@ -480,30 +561,45 @@ func matchMovieStudio(qb models.StudioReader, s *models.ScrapedMovieStudio) erro
 func (c Cache) ScrapeMovieURL(url string) (*models.ScrapedMovie, error) {
 	for _, s := range c.scrapers {
 		if s.matchesMovieURL(url) {
+			s, trace := c.traces.start(s, "movieByURL", url)
 			ret, err := s.ScrapeMovieURL(url, c.txnManager, c.globalConfig)
+			c.traces.finish(trace, err)
 			if err != nil {
 				return nil, err
 			}
 
-			if ret.Studio != nil {
-				if err := c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
-					return matchMovieStudio(r.Studio(), ret.Studio)
-				}); err != nil {
+			if ret != nil {
+				err = c.postScrapeMovie(ret)
+				if err != nil {
 					return nil, err
 				}
 			}
 
-			// post-process - set the image if applicable
-			if err := setMovieFrontImage(ret, c.globalConfig); err != nil {
-				logger.Warnf("Could not set front image using URL %s: %s", *ret.FrontImage, err.Error())
-			}
-			if err := setMovieBackImage(ret, c.globalConfig); err != nil {
-				logger.Warnf("Could not set back image using URL %s: %s", *ret.BackImage, err.Error())
-			}
-
 			return ret, nil
 		}
 	}
 
 	return nil, nil
 }
+
+// postScrapeMovie matches the studio of the scraped movie with the existing
+// studios, and downloads its front and back images.
+func (c Cache) postScrapeMovie(ret *models.ScrapedMovie) error {
+	if ret.Studio != nil {
+		if err := c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+			return matchMovieStudio(r.Studio(), ret.Studio)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// post-process - set the image if applicable
+	if err := setMovieFrontImage(ret, c.globalConfig); err != nil {
+		logger.Warnf("Could not set front image using URL %s: %s", *ret.FrontImage, err.Error())
+	}
+	if err := setMovieBackImage(ret, c.globalConfig); err != nil {
+		logger.Warnf("Could not set back image using URL %s: %s", *ret.BackImage, err.Error())
+	}
+
+	return nil
+}
@@ -1,18 +1,32 @@
 package scraper
 
 import (
-	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
-	"io"
+	"fmt"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
 
+// scriptTimeout is the time a scraper script may run before it is killed,
+// unless the scraper sets a timeout.
+const scriptTimeout = 60 * time.Second
+
+// scriptKillWait is the time to wait for the output of a scraper script to
+// be closed after it is killed. Processes started by the script may keep
+// the output open on Windows, where they are not killed with the script.
+const scriptKillWait = 5 * time.Second
+
+// scriptErrorLines is the number of lines written to stderr by a failed
+// scraper script which are included in the error.
+const scriptErrorLines = 5
+
 type scriptScraper struct {
 	scraper      scraperTypeConfig
 	config       config
@@ -27,6 +41,14 @@ func newScriptScraper(scraper scraperTypeConfig, config config, globalConfig Glo
 	}
 }
 
+func (s *scriptScraper) timeout() time.Duration {
+	if s.scraper.Timeout > 0 {
+		return time.Duration(s.scraper.Timeout) * time.Second
+	}
+
+	return scriptTimeout
+}
+
 func (s *scriptScraper) runScraperScript(inString string, out interface{}) error {
 	command := s.scraper.Script
 
@@ -37,63 +59,128 @@ func (s *scriptScraper) runScraperScript(inString string, out interface{}) error
 		}
 	}
 
+	timeout := s.timeout()
+
 	cmd := exec.Command(command[0], command[1:]...)
 	cmd.Dir = filepath.Dir(s.config.path)
+	cmd.Stdin = strings.NewReader(inString)
+	setScriptProcAttr(cmd)
 
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return err
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	stderr := &scriptStderr{}
+	cmd.Stderr = stderr
+
+	logger.Debugf("Scraper script <%s> started", strings.Join(cmd.Args, " "))
+
+	if err := cmd.Start(); err != nil {
+		logger.Error("Error running scraper script: " + err.Error())
+		return fmt.Errorf("error running scraper script: %s", err.Error())
 	}
 
+	// Wait returns once the output of the script is closed, which may be
+	// after the script exits if it started other processes
+	done := make(chan error, 1)
 	go func() {
-		defer stdin.Close()
-
-		io.WriteString(stdin, inString)
+		done <- cmd.Wait()
 	}()
 
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		logger.Error("Scraper stderr not available: " + err.Error())
-	}
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		if err := killScript(cmd); err != nil {
+			logger.Warnf("Error killing scraper script: %s", err.Error())
+		}
 
-	stdout, err := cmd.StdoutPipe()
-	if nil != err {
-		logger.Error("Scraper stdout not available: " + err.Error())
+		select {
+		case <-done:
+			stderr.flush()
+			return fmt.Errorf("scraper script timed out after %s%s", timeout, stderr.summary())
+		case <-time.After(scriptKillWait):
+			return fmt.Errorf("scraper script timed out after %s", timeout)
+		}
 	}
 
-	if err = cmd.Start(); err != nil {
+	stderr.flush()
+	logger.Debugf("Scraper script finished")
+
+	if err != nil {
 		logger.Error("Error running scraper script: " + err.Error())
-		return errors.New("Error running scraper script")
+		return fmt.Errorf("error running scraper script: %s%s", err.Error(), stderr.summary())
+	}
+
+	if err := json.NewDecoder(&stdout).Decode(out); err != nil {
+		logger.Error("could not unmarshal json: " + err.Error())
+		return errors.New("could not unmarshal json: " + err.Error())
 	}
 
-	scanner := bufio.NewScanner(stderr)
-	go func() { // log errors from stderr pipe
-		for scanner.Scan() {
-			logger.Errorf("scraper: %s", scanner.Text())
+	return nil
+}
+
+// scriptStderr logs the lines written to the stderr of a scraper script,
+// keeping the last lines so that they can be included in errors.
+type scriptStderr struct {
+	buf   []byte
+	lines []string
+}
+
+func (w *scriptStderr) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
 		}
-	}()
 
-	logger.Debugf("Scraper script <%s> started", strings.Join(cmd.Args, " "))
+		w.line(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
 
-	// TODO - add a timeout here
-	decodeErr := json.NewDecoder(stdout).Decode(out)
-	if decodeErr != nil {
-		logger.Error("could not unmarshal json: " + decodeErr.Error())
-		return errors.New("could not unmarshal json: " + decodeErr.Error())
+func (w *scriptStderr) line(l string) {
+	l = strings.TrimRight(l, "\r")
+	if l == "" {
+		return
 	}
 
-	err = cmd.Wait()
-	logger.Debugf("Scraper script finished")
+	logger.Errorf("scraper: %s", l)
 
-	if err != nil {
-		return errors.New("Error running scraper script")
+	w.lines = append(w.lines, l)
+	if len(w.lines) > scriptErrorLines {
+		w.lines = w.lines[1:]
 	}
+}
 
-	return nil
+// flush logs the last line if it was not terminated.
+func (w *scriptStderr) flush() {
+	if len(w.buf) > 0 {
+		w.line(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// summary returns the last lines of stderr to be appended to an error, or
+// an empty string if nothing was written.
+func (w *scriptStderr) summary() string {
+	if len(w.lines) == 0 {
+		return ""
+	}
+
+	return ": " + strings.Join(w.lines, "\n")
+}
+
+// scriptURLInput returns the input of a scrape by URL.
+func scriptURLInput(url string) string {
+	ret, _ := json.Marshal(map[string]string{"url": url})
+	return string(ret)
 }
 
 func (s *scriptScraper) scrapePerformersByName(name string) ([]*models.ScrapedPerformer, error) {
-	inString := `{"name": "` + name + `"}`
+	input, _ := json.Marshal(map[string]string{"name": name})
+	inString := string(input)
 
 	var performers []models.ScrapedPerformer
 
@@ -125,11 +212,11 @@ func (s *scriptScraper) scrapePerformerByFragment(scrapedPerformer models.Scrape
 }
 
 func (s *scriptScraper) scrapePerformerByURL(url string) (*models.ScrapedPerformer, error) {
-	inString := `{"url": "` + url + `"}`
+	inString := scriptURLInput(url)
 
 	var ret models.ScrapedPerformer
 
-	err := s.runScraperScript(string(inString), &ret)
+	err := s.runScraperScript(inString, &ret)
 
 	return &ret, err
 }
@@ -163,31 +250,31 @@ func (s *scriptScraper) scrapeGalleryByFragment(gallery models.GalleryUpdateInpu
 }
 
 func (s *scriptScraper) scrapeSceneByURL(url string) (*models.ScrapedScene, error) {
-	inString := `{"url": "` + url + `"}`
+	inString := scriptURLInput(url)
 
 	var ret models.ScrapedScene
 
-	err := s.runScraperScript(string(inString), &ret)
+	err := s.runScraperScript(inString, &ret)
 
 	return &ret, err
 }
 
 func (s *scriptScraper) scrapeGalleryByURL(url string) (*models.ScrapedGallery, error) {
-	inString := `{"url": "` + url + `"}`
+	inString := scriptURLInput(url)
 
 	var ret models.ScrapedGallery
 
-	err := s.runScraperScript(string(inString), &ret)
+	err := s.runScraperScript(inString, &ret)
 
 	return &ret, err
 }
 
 func (s *scriptScraper) scrapeMovieByURL(url string) (*models.ScrapedMovie, error) {
-	inString := `{"url": "` + url + `"}`
+	inString := scriptURLInput(url)
 
 	var ret models.ScrapedMovie
 
-	err := s.runScraperScript(string(inString), &ret)
+	err := s.runScraperScript(inString, &ret)
 
 	return &ret, err
 }
@@ -0,0 +1,69 @@
+package scraper
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestScriptScraper(t *testing.T, script string, timeout int) *scriptScraper {
+	if runtime.GOOS == "windows" {
+		t.Skip("test scripts require sh")
+	}
+
+	return newScriptScraper(scraperTypeConfig{
+		Action:  scraperActionScript,
+		Script:  []string{"sh", "-c", script},
+		Timeout: timeout,
+	}, config{}, mockGlobalConfig{})
+}
+
+func TestScriptScraperURL(t *testing.T) {
+	// the script returns its input
+	s := newTestScriptScraper(t, "cat", 0)
+
+	const url = `https://example.com/scene?name="quoted"`
+	scene, err := s.scrapeSceneByURL(url)
+	if assert.Nil(t, err) {
+		verifyField(t, url, scene.URL, "URL")
+	}
+}
+
+func TestScriptScraperError(t *testing.T) {
+	s := newTestScriptScraper(t, "echo first >&2; echo second >&2; exit 1", 0)
+
+	_, err := s.scrapeSceneByURL("https://example.com")
+	if assert.NotNil(t, err) {
+		// the output of the script is included in the error
+		assert.True(t, strings.HasSuffix(err.Error(), ": first\nsecond"), err.Error())
+	}
+
+	s = newTestScriptScraper(t, "echo invalid", 0)
+	_, err = s.scrapeSceneByURL("https://example.com")
+	assert.NotNil(t, err)
+}
+
+func TestScriptScraperTimeout(t *testing.T) {
+	s := newTestScriptScraper(t, "exec sleep 10", 1)
+
+	_, err := s.scrapeSceneByURL("https://example.com")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "timed out")
+	}
+}
+
+func TestScriptScraperTimeoutChildProcess(t *testing.T) {
+	// the background process keeps the output of the script open after the
+	// script exits
+	s := newTestScriptScraper(t, "sleep 10 & exec sleep 10", 1)
+
+	start := time.Now()
+	_, err := s.scrapeSceneByURL("https://example.com")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "timed out")
+	}
+	assert.True(t, time.Since(start) < 5*time.Second, "scraper script was not killed")
+}
@@ -0,0 +1,20 @@
+// +build !windows
+
+package scraper
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setScriptProcAttr starts the scraper script in its own process group, so
+// that the processes it starts can be killed with it.
+func setScriptProcAttr(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killScript kills the process group of the scraper script, including any
+// processes it started which still hold its output open.
+func killScript(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
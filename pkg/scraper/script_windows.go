@@ -0,0 +1,13 @@
+package scraper
+
+import "os/exec"
+
+// setScriptProcAttr does nothing on Windows, where processes are not
+// grouped.
+func setScriptProcAttr(cmd *exec.Cmd) {}
+
+// killScript kills the scraper script. Processes started by the script are
+// not killed.
+func killScript(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,123 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ValidateSources returns an error if a scraper source has no scraper, an
+// invalid proxy URL, or a header or cookie without a name.
+func ValidateSources(sources []*models.ScraperSourceInput) error {
+	for _, s := range sources {
+		if s.Scraper == "" {
+			return errors.New("scraper source has no scraper")
+		}
+
+		if s.Proxy != nil && *s.Proxy != "" {
+			if _, err := parseProxyURL(*s.Proxy); err != nil {
+				return fmt.Errorf("scraper %s: %s", s.Scraper, err.Error())
+			}
+		}
+
+		for _, h := range s.Headers {
+			if h.Name == "" {
+				return fmt.Errorf("scraper %s has a header without a name", s.Scraper)
+			}
+		}
+
+		for _, c := range s.Cookies {
+			if c.Name == "" {
+				return fmt.Errorf("scraper %s has a cookie without a name", s.Scraper)
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseProxyURL(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: %s", proxy)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	}
+
+	return nil, fmt.Errorf("unsupported proxy scheme: %s", u.Scheme)
+}
+
+// getSource returns the source settings configured for the scraper with the
+// id, or nil if there are none.
+func getSource(globalConfig GlobalConfig, id string) *models.ScraperSource {
+	for _, s := range globalConfig.GetScraperSources() {
+		if s.Scraper == id {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// sourceProxy returns the function used by the transport to proxy the
+// requests of the source, or nil if it has no proxy.
+func sourceProxy(source *models.ScraperSource) (func(*http.Request) (*url.URL, error), error) {
+	if source == nil || source.Proxy == nil || *source.Proxy == "" {
+		return nil, nil
+	}
+
+	u, err := parseProxyURL(*source.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.ProxyURL(u), nil
+}
+
+// setSourceHeaders sets the headers of the source on the request, replacing
+// any with the same name.
+func setSourceHeaders(req *http.Request, source *models.ScraperSource) {
+	if source == nil {
+		return
+	}
+
+	for _, h := range source.Headers {
+		req.Header.Set(h.Name, h.Value)
+		logger.Debugf("[scraper] adding header <%s:%s>", h.Name, h.Value)
+	}
+}
+
+// setSourceCookies adds the cookies of the source to the jar. Cookies are
+// set for the requested URL, so that cookies with a domain are only sent to
+// hosts within it.
+func setSourceCookies(jar *cookiejar.Jar, u *url.URL, source *models.ScraperSource) {
+	if source == nil || len(source.Cookies) == 0 {
+		return
+	}
+
+	var cookies []*http.Cookie
+	for _, c := range source.Cookies {
+		cookie := &http.Cookie{
+			Name:  c.Name,
+			Value: c.Value,
+		}
+		if c.Domain != nil {
+			cookie.Domain = *c.Domain
+		}
+		if c.Path != nil {
+			cookie.Path = *c.Path
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	jar.SetCookies(u, cookies)
+}
@@ -0,0 +1,163 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type sourceGlobalConfig struct {
+	mockGlobalConfig
+	sources []*models.ScraperSource
+}
+
+func (c sourceGlobalConfig) GetScraperSources() []*models.ScraperSource {
+	return c.sources
+}
+
+func TestLoadURLSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, _ := r.Cookie("session")
+		var sessionValue string
+		if session != nil {
+			sessionValue = session.Value
+		}
+		fmt.Fprintf(w, "%s %s %s", r.Header.Get("X-Test"), r.Header.Get("Accept-Language"), sessionValue)
+	}))
+	defer ts.Close()
+
+	scraperConfig := config{
+		ID: "test",
+		DriverOptions: &scraperDriverOptions{
+			Headers: []*header{
+				{Key: "X-Test", Value: "scraper"},
+				{Key: "Accept-Language", Value: "en"},
+			},
+		},
+	}
+
+	globalConfig := sourceGlobalConfig{
+		sources: []*models.ScraperSource{
+			{
+				Scraper: "other",
+				Headers: []*models.ScraperHeader{{Name: "X-Test", Value: "other"}},
+			},
+			{
+				Scraper: "test",
+				Headers: []*models.ScraperHeader{{Name: "X-Test", Value: "source"}},
+				Cookies: []*models.ScraperCookie{{Name: "session", Value: "abc"}},
+			},
+		},
+	}
+
+	body, _, _, err := loadURLHTTP(ts.URL, scraperConfig, globalConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "source en abc", string(body))
+
+	// cookies outside the domain are not sent
+	otherDomain := "example.com"
+	globalConfig.sources[1].Cookies[0].Domain = &otherDomain
+	body, _, _, err = loadURLHTTP(ts.URL, scraperConfig, globalConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "source en ", string(body))
+
+	scraperConfig.ID = "unconfigured"
+	body, _, _, err = loadURLHTTP(ts.URL, scraperConfig, globalConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "scraper en ", string(body))
+}
+
+func TestLoadURLProxy(t *testing.T) {
+	// the proxy receives the absolute URL of the request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "proxied %s", r.URL.String())
+	}))
+	defer proxy.Close()
+
+	proxyURL := proxy.URL
+	globalConfig := sourceGlobalConfig{
+		sources: []*models.ScraperSource{
+			{
+				Scraper: "test",
+				Proxy:   &proxyURL,
+			},
+		},
+	}
+
+	body, _, _, err := loadURLHTTP("http://scraper.test/page", config{ID: "test"}, globalConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "proxied http://scraper.test/page", string(body))
+
+	invalid := "ftp://localhost"
+	globalConfig.sources[0].Proxy = &invalid
+	_, _, _, err = loadURLHTTP("http://scraper.test/page", config{ID: "test"}, globalConfig)
+	assert.NotNil(t, err)
+}
+
+func TestValidateSources(t *testing.T) {
+	str := func(s string) *string {
+		return &s
+	}
+
+	tests := []struct {
+		name    string
+		source  *models.ScraperSourceInput
+		wantErr bool
+	}{
+		{
+			"valid",
+			&models.ScraperSourceInput{
+				Scraper: "test",
+				Proxy:   str("socks5://localhost:1080"),
+				Headers: []*models.ScraperHeaderInput{{Name: "X-Test", Value: "value"}},
+				Cookies: []*models.ScraperCookieInput{{Name: "session", Value: "abc"}},
+			},
+			false,
+		},
+		{
+			"empty proxy",
+			&models.ScraperSourceInput{Scraper: "test", Proxy: str("")},
+			false,
+		},
+		{
+			"no scraper",
+			&models.ScraperSourceInput{},
+			true,
+		},
+		{
+			"unsupported proxy",
+			&models.ScraperSourceInput{Scraper: "test", Proxy: str("ftp://localhost")},
+			true,
+		},
+		{
+			"proxy without host",
+			&models.ScraperSourceInput{Scraper: "test", Proxy: str("localhost:1080")},
+			true,
+		},
+		{
+			"unnamed header",
+			&models.ScraperSourceInput{
+				Scraper: "test",
+				Headers: []*models.ScraperHeaderInput{{Value: "value"}},
+			},
+			true,
+		},
+		{
+			"unnamed cookie",
+			&models.ScraperSourceInput{
+				Scraper: "test",
+				Cookies: []*models.ScraperCookieInput{{Value: "abc"}},
+			},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		err := ValidateSources([]*models.ScraperSourceInput{tt.source})
+		assert.Equal(t, tt.wantErr, err != nil, "%s: %v", tt.name, err)
+	}
+}
@@ -0,0 +1,317 @@
+package stashbox
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/Yamashou/gqlgenc/client"
+	"github.com/Yamashou/gqlgenc/graphqljson"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+)
+
+// Timeout for submitting a draft, which includes uploading its image.
+const draftSubmitTimeout = imageGetTimeout * 2
+
+// SubmitSceneDraft submits the scene as a draft to the stash-box endpoint,
+// with its studio, performers, tags, fingerprints and cover image.
+// Returns the ID of the draft, which the user completes on the
+// stash-box site.
+func (c Client) SubmitSceneDraft(sceneID int) (*string, error) {
+	var draft graphql.SceneDraftInput
+	var image []byte
+	if err := c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		qb := r.Scene()
+		scene, err := qb.Find(sceneID)
+		if err != nil {
+			return err
+		}
+		if scene == nil {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		draft, err = c.sceneDraft(r, scene)
+		if err != nil {
+			return err
+		}
+
+		image, err = qb.GetCover(sceneID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var res graphql.SubmitSceneDraftPayload
+	if err := c.submitDraft(graphql.SubmitSceneDraftQuery, draft, image, &res); err != nil {
+		return nil, err
+	}
+
+	return res.SubmitSceneDraft.ID, nil
+}
+
+// SubmitPerformerDraft submits the performer as a draft to the stash-box
+// endpoint, with its image. Returns the ID of the draft.
+func (c Client) SubmitPerformerDraft(performerID int) (*string, error) {
+	var draft graphql.PerformerDraftInput
+	var image []byte
+	if err := c.txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		qb := r.Performer()
+		performer, err := qb.Find(performerID)
+		if err != nil {
+			return err
+		}
+		if performer == nil {
+			return fmt.Errorf("performer with id %d not found", performerID)
+		}
+
+		stashIDs, err := qb.GetStashIDs(performerID)
+		if err != nil {
+			return err
+		}
+
+		draft = performerDraft(performer, c.stashID(stashIDs))
+
+		image, err = qb.GetImage(performerID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var res graphql.SubmitPerformerDraftPayload
+	if err := c.submitDraft(graphql.SubmitPerformerDraftQuery, draft, image, &res); err != nil {
+		return nil, err
+	}
+
+	return res.SubmitPerformerDraft.ID, nil
+}
+
+// stashID returns the stash ID for the endpoint of the client, or nil if
+// there is none.
+func (c Client) stashID(stashIDs []*models.StashID) *string {
+	for _, s := range stashIDs {
+		if s.Endpoint == c.box.Endpoint {
+			ret := s.StashID
+			return &ret
+		}
+	}
+
+	return nil
+}
+
+func (c Client) sceneDraft(r models.ReaderRepository, scene *models.Scene) (graphql.SceneDraftInput, error) {
+	ret := graphql.SceneDraftInput{
+		Title:        nullStringPtr(scene.Title),
+		Details:      nullStringPtr(scene.Details),
+		URL:          nullStringPtr(scene.URL),
+		Performers:   []*graphql.DraftEntityInput{},
+		Tags:         []*graphql.DraftEntityInput{},
+		Fingerprints: sceneFingerprints(scene),
+	}
+
+	if scene.Date.Valid {
+		ret.Date = &scene.Date.String
+	}
+
+	stashIDs, err := r.Scene().GetStashIDs(scene.ID)
+	if err != nil {
+		return ret, err
+	}
+	ret.ID = c.stashID(stashIDs)
+
+	if scene.StudioID.Valid {
+		studioID := int(scene.StudioID.Int64)
+		studio, err := r.Studio().Find(studioID)
+		if err != nil {
+			return ret, err
+		}
+
+		if studio != nil {
+			stashIDs, err := r.Studio().GetStashIDs(studioID)
+			if err != nil {
+				return ret, err
+			}
+
+			ret.Studio = &graphql.DraftEntityInput{
+				Name: studio.Name.String,
+				ID:   c.stashID(stashIDs),
+			}
+		}
+	}
+
+	performers, err := r.Performer().FindBySceneID(scene.ID)
+	if err != nil {
+		return ret, err
+	}
+
+	for _, p := range performers {
+		stashIDs, err := r.Performer().GetStashIDs(p.ID)
+		if err != nil {
+			return ret, err
+		}
+
+		ret.Performers = append(ret.Performers, &graphql.DraftEntityInput{
+			Name: p.Name.String,
+			ID:   c.stashID(stashIDs),
+		})
+	}
+
+	tags, err := r.Tag().FindBySceneID(scene.ID)
+	if err != nil {
+		return ret, err
+	}
+
+	for _, t := range tags {
+		ret.Tags = append(ret.Tags, &graphql.DraftEntityInput{
+			Name: t.Name,
+		})
+	}
+
+	return ret, nil
+}
+
+func performerDraft(performer *models.Performer, stashID *string) graphql.PerformerDraftInput {
+	ret := graphql.PerformerDraftInput{
+		ID:           stashID,
+		Name:         performer.Name.String,
+		Aliases:      nullStringPtr(performer.Aliases),
+		Gender:       nullStringPtr(performer.Gender),
+		Ethnicity:    nullStringPtr(performer.Ethnicity),
+		Country:      nullStringPtr(performer.Country),
+		EyeColor:     nullStringPtr(performer.EyeColor),
+		HairColor:    nullStringPtr(performer.HairColor),
+		Measurements: nullStringPtr(performer.Measurements),
+		BreastType:   nullStringPtr(performer.FakeTits),
+		Tattoos:      nullStringPtr(performer.Tattoos),
+		Piercings:    nullStringPtr(performer.Piercings),
+		Urls:         []string{},
+	}
+
+	if performer.Birthdate.Valid {
+		ret.Birthdate = &performer.Birthdate.String
+	}
+
+	if performer.Height.Valid {
+		height := strconv.FormatInt(performer.Height.Int64, 10)
+		ret.Height = &height
+	}
+
+	for _, url := range []sql.NullString{performer.URL, performer.Twitter, performer.Instagram} {
+		if url.Valid && url.String != "" {
+			ret.Urls = append(ret.Urls, url.String)
+		}
+	}
+
+	if performer.CareerLength.Valid {
+		ret.CareerStartYear, ret.CareerEndYear = parseCareerLength(performer.CareerLength.String)
+	}
+
+	return ret
+}
+
+var careerLengthRE = regexp.MustCompile(`^\s*(\d{4})?\s*-?\s*(\d{4})?\s*$`)
+
+// parseCareerLength returns the start and end years of a career length
+// formatted as by formatCareerLength, such as "2010 - 2015" or "2010 -".
+func parseCareerLength(careerLength string) (start *int, end *int) {
+	m := careerLengthRE.FindStringSubmatch(careerLength)
+	if m == nil {
+		return nil, nil
+	}
+
+	year := func(s string) *int {
+		if s == "" {
+			return nil
+		}
+		ret, _ := strconv.Atoi(s)
+		return &ret
+	}
+
+	return year(m[1]), year(m[2])
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+
+	return &s.String
+}
+
+// submitDraft posts the draft mutation to the endpoint, uploading the image
+// as the image field of the input following the graphql multipart request
+// specification. The image is not uploaded if it is empty.
+func (c Client) submitDraft(query string, input interface{}, image []byte, ret interface{}) error {
+	operations, err := json.Marshal(client.Request{
+		Query: query,
+		Variables: map[string]interface{}{
+			"input": input,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("operations", string(operations)); err != nil {
+		return err
+	}
+
+	fileMap := "{}"
+	if len(image) > 0 {
+		fileMap = `{"0": ["variables.input.image"]}`
+	}
+	if err := writer.WriteField("map", fileMap); err != nil {
+		return err
+	}
+
+	if len(image) > 0 {
+		part, err := writer.CreateFormFile("0", "image")
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(image); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), draftSubmitTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.box.Endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json; charset=utf-8")
+	req.Header.Set("ApiKey", c.box.APIKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	if err := graphqljson.Unmarshal(resp.Body, ret); err != nil {
+		return errors.New("error submitting draft: " + err.Error())
+	}
+
+	return nil
+}
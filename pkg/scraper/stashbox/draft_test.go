@@ -0,0 +1,209 @@
+package stashbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	draftSceneID     = 1
+	draftStudioID    = 2
+	draftPerformerID = 3
+	draftID          = "draftID"
+	draftAPIKey      = "apikey"
+)
+
+// draftRequest is a multipart draft submission received by the test server.
+type draftRequest struct {
+	apiKey     string
+	operations struct {
+		Query     string                     `json:"query"`
+		Variables map[string]json.RawMessage `json:"variables"`
+	}
+	fileMap string
+	image   string
+}
+
+func newDraftServer(t *testing.T, mutation string, received *draftRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.apiKey = r.Header.Get("ApiKey")
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("Error parsing multipart form: %s", err.Error())
+			return
+		}
+
+		if err := json.Unmarshal([]byte(r.FormValue("operations")), &received.operations); err != nil {
+			t.Errorf("Error decoding operations: %s", err.Error())
+		}
+		received.fileMap = r.FormValue("map")
+
+		if f, _, err := r.FormFile("0"); err == nil {
+			data, _ := ioutil.ReadAll(f)
+			received.image = string(data)
+		}
+
+		fmt.Fprintf(w, `{"data": {"%s": {"id": "%s"}}}`, mutation, draftID)
+	}))
+}
+
+func TestSubmitSceneDraft(t *testing.T) {
+	var received draftRequest
+	ts := newDraftServer(t, "submitSceneDraft", &received)
+	defer ts.Close()
+
+	txnManager := mocks.NewTransactionManager()
+	client := NewClient(models.StashBox{Endpoint: ts.URL, APIKey: draftAPIKey}, txnManager)
+
+	sceneReaderWriter := txnManager.Scene().(*mocks.SceneReaderWriter)
+	sceneReaderWriter.On("Find", draftSceneID).Return(&models.Scene{
+		ID:       draftSceneID,
+		Title:    sql.NullString{String: "title", Valid: true},
+		Checksum: sql.NullString{String: "checksum", Valid: true},
+		Duration: sql.NullFloat64{Float64: 10.5, Valid: true},
+		StudioID: sql.NullInt64{Int64: draftStudioID, Valid: true},
+	}, nil).Once()
+	sceneReaderWriter.On("GetStashIDs", draftSceneID).Return(nil, nil).Once()
+	sceneReaderWriter.On("GetCover", draftSceneID).Return([]byte("cover"), nil).Once()
+
+	studioReaderWriter := txnManager.Studio().(*mocks.StudioReaderWriter)
+	studioReaderWriter.On("Find", draftStudioID).Return(&models.Studio{
+		ID:   draftStudioID,
+		Name: sql.NullString{String: "studio", Valid: true},
+	}, nil).Once()
+	studioReaderWriter.On("GetStashIDs", draftStudioID).Return([]*models.StashID{
+		{Endpoint: "other", StashID: "otherID"},
+		{Endpoint: ts.URL, StashID: "studioID"},
+	}, nil).Once()
+
+	performerReaderWriter := txnManager.Performer().(*mocks.PerformerReaderWriter)
+	performerReaderWriter.On("FindBySceneID", draftSceneID).Return([]*models.Performer{
+		{ID: draftPerformerID, Name: sql.NullString{String: "performer", Valid: true}},
+	}, nil).Once()
+	performerReaderWriter.On("GetStashIDs", draftPerformerID).Return(nil, nil).Once()
+
+	tagReaderWriter := txnManager.Tag().(*mocks.TagReaderWriter)
+	tagReaderWriter.On("FindBySceneID", draftSceneID).Return([]*models.Tag{
+		{Name: "tag"},
+	}, nil).Once()
+
+	id, err := client.SubmitSceneDraft(draftSceneID)
+	if !assert.Nil(t, err) || !assert.NotNil(t, id) {
+		return
+	}
+	assert.Equal(t, draftID, *id)
+
+	assert.Equal(t, draftAPIKey, received.apiKey)
+	assert.Contains(t, received.operations.Query, "submitSceneDraft")
+	assert.JSONEq(t, `{
+		"id": null,
+		"title": "title",
+		"details": null,
+		"url": null,
+		"date": null,
+		"studio": {"name": "studio", "id": "studioID"},
+		"performers": [{"name": "performer", "id": null}],
+		"tags": [{"name": "tag", "id": null}],
+		"image": null,
+		"fingerprints": [{"hash": "checksum", "algorithm": "MD5", "duration": 10}]
+	}`, string(received.operations.Variables["input"]))
+
+	// the cover is uploaded as the image
+	assert.JSONEq(t, `{"0": ["variables.input.image"]}`, received.fileMap)
+	assert.Equal(t, "cover", received.image)
+
+	sceneReaderWriter.AssertExpectations(t)
+	studioReaderWriter.AssertExpectations(t)
+	performerReaderWriter.AssertExpectations(t)
+	tagReaderWriter.AssertExpectations(t)
+}
+
+func TestSubmitPerformerDraft(t *testing.T) {
+	var received draftRequest
+	ts := newDraftServer(t, "submitPerformerDraft", &received)
+	defer ts.Close()
+
+	txnManager := mocks.NewTransactionManager()
+	client := NewClient(models.StashBox{Endpoint: ts.URL}, txnManager)
+
+	performerReaderWriter := txnManager.Performer().(*mocks.PerformerReaderWriter)
+	performerReaderWriter.On("Find", draftPerformerID).Return(&models.Performer{
+		ID:           draftPerformerID,
+		Name:         sql.NullString{String: "performer", Valid: true},
+		Gender:       sql.NullString{String: "FEMALE", Valid: true},
+		Height:       sql.NullInt64{Int64: 170, Valid: true},
+		URL:          sql.NullString{String: "https://example.com", Valid: true},
+		Twitter:      sql.NullString{String: "", Valid: true},
+		CareerLength: sql.NullString{String: "2010 - 2015", Valid: true},
+	}, nil).Once()
+	performerReaderWriter.On("GetStashIDs", draftPerformerID).Return([]*models.StashID{
+		{Endpoint: ts.URL, StashID: "performerID"},
+	}, nil).Once()
+	performerReaderWriter.On("GetImage", draftPerformerID).Return(nil, nil).Once()
+
+	id, err := client.SubmitPerformerDraft(draftPerformerID)
+	if !assert.Nil(t, err) || !assert.NotNil(t, id) {
+		return
+	}
+	assert.Equal(t, draftID, *id)
+
+	assert.JSONEq(t, `{
+		"id": "performerID",
+		"name": "performer",
+		"aliases": null,
+		"gender": "FEMALE",
+		"birthdate": null,
+		"urls": ["https://example.com"],
+		"ethnicity": null,
+		"country": null,
+		"eye_color": null,
+		"hair_color": null,
+		"height": "170",
+		"measurements": null,
+		"breast_type": null,
+		"tattoos": null,
+		"piercings": null,
+		"career_start_year": 2010,
+		"career_end_year": 2015,
+		"image": null
+	}`, string(received.operations.Variables["input"]))
+
+	// no image is uploaded
+	assert.JSONEq(t, `{}`, received.fileMap)
+	assert.Equal(t, "", received.image)
+
+	performerReaderWriter.AssertExpectations(t)
+}
+
+func TestParseCareerLength(t *testing.T) {
+	intPtr := func(i int) *int {
+		return &i
+	}
+
+	tests := []struct {
+		careerLength string
+		start        *int
+		end          *int
+	}{
+		{"2010 - 2015", intPtr(2010), intPtr(2015)},
+		{"2010 -", intPtr(2010), nil},
+		{"- 2015", nil, intPtr(2015)},
+		{"2010", intPtr(2010), nil},
+		{"ten years", nil, nil},
+	}
+
+	for _, tt := range tests {
+		start, end := parseCareerLength(tt.careerLength)
+		assert.Equal(t, tt.start, start, tt.careerLength)
+		assert.Equal(t, tt.end, end, tt.careerLength)
+	}
+}
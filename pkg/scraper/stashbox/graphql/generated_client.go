@@ -41,44 +41,46 @@ type Query struct {
 }
 
 type Mutation struct {
-	SceneCreate        *Scene       "json:\"sceneCreate\" graphql:\"sceneCreate\""
-	SceneUpdate        *Scene       "json:\"sceneUpdate\" graphql:\"sceneUpdate\""
-	SceneDestroy       bool         "json:\"sceneDestroy\" graphql:\"sceneDestroy\""
-	PerformerCreate    *Performer   "json:\"performerCreate\" graphql:\"performerCreate\""
-	PerformerUpdate    *Performer   "json:\"performerUpdate\" graphql:\"performerUpdate\""
-	PerformerDestroy   bool         "json:\"performerDestroy\" graphql:\"performerDestroy\""
-	StudioCreate       *Studio      "json:\"studioCreate\" graphql:\"studioCreate\""
-	StudioUpdate       *Studio      "json:\"studioUpdate\" graphql:\"studioUpdate\""
-	StudioDestroy      bool         "json:\"studioDestroy\" graphql:\"studioDestroy\""
-	TagCreate          *Tag         "json:\"tagCreate\" graphql:\"tagCreate\""
-	TagUpdate          *Tag         "json:\"tagUpdate\" graphql:\"tagUpdate\""
-	TagDestroy         bool         "json:\"tagDestroy\" graphql:\"tagDestroy\""
-	UserCreate         *User        "json:\"userCreate\" graphql:\"userCreate\""
-	UserUpdate         *User        "json:\"userUpdate\" graphql:\"userUpdate\""
-	UserDestroy        bool         "json:\"userDestroy\" graphql:\"userDestroy\""
-	ImageCreate        *Image       "json:\"imageCreate\" graphql:\"imageCreate\""
-	ImageDestroy       bool         "json:\"imageDestroy\" graphql:\"imageDestroy\""
-	NewUser            *string      "json:\"newUser\" graphql:\"newUser\""
-	ActivateNewUser    *User        "json:\"activateNewUser\" graphql:\"activateNewUser\""
-	GenerateInviteCode string       "json:\"generateInviteCode\" graphql:\"generateInviteCode\""
-	RescindInviteCode  bool         "json:\"rescindInviteCode\" graphql:\"rescindInviteCode\""
-	GrantInvite        int          "json:\"grantInvite\" graphql:\"grantInvite\""
-	RevokeInvite       int          "json:\"revokeInvite\" graphql:\"revokeInvite\""
-	TagCategoryCreate  *TagCategory "json:\"tagCategoryCreate\" graphql:\"tagCategoryCreate\""
-	TagCategoryUpdate  *TagCategory "json:\"tagCategoryUpdate\" graphql:\"tagCategoryUpdate\""
-	TagCategoryDestroy bool         "json:\"tagCategoryDestroy\" graphql:\"tagCategoryDestroy\""
-	RegenerateAPIKey   string       "json:\"regenerateAPIKey\" graphql:\"regenerateAPIKey\""
-	ResetPassword      bool         "json:\"resetPassword\" graphql:\"resetPassword\""
-	ChangePassword     bool         "json:\"changePassword\" graphql:\"changePassword\""
-	SceneEdit          Edit         "json:\"sceneEdit\" graphql:\"sceneEdit\""
-	PerformerEdit      Edit         "json:\"performerEdit\" graphql:\"performerEdit\""
-	StudioEdit         Edit         "json:\"studioEdit\" graphql:\"studioEdit\""
-	TagEdit            Edit         "json:\"tagEdit\" graphql:\"tagEdit\""
-	EditVote           Edit         "json:\"editVote\" graphql:\"editVote\""
-	EditComment        Edit         "json:\"editComment\" graphql:\"editComment\""
-	ApplyEdit          Edit         "json:\"applyEdit\" graphql:\"applyEdit\""
-	CancelEdit         Edit         "json:\"cancelEdit\" graphql:\"cancelEdit\""
-	SubmitFingerprint  bool         "json:\"submitFingerprint\" graphql:\"submitFingerprint\""
+	SceneCreate          *Scene                "json:\"sceneCreate\" graphql:\"sceneCreate\""
+	SceneUpdate          *Scene                "json:\"sceneUpdate\" graphql:\"sceneUpdate\""
+	SceneDestroy         bool                  "json:\"sceneDestroy\" graphql:\"sceneDestroy\""
+	PerformerCreate      *Performer            "json:\"performerCreate\" graphql:\"performerCreate\""
+	PerformerUpdate      *Performer            "json:\"performerUpdate\" graphql:\"performerUpdate\""
+	PerformerDestroy     bool                  "json:\"performerDestroy\" graphql:\"performerDestroy\""
+	StudioCreate         *Studio               "json:\"studioCreate\" graphql:\"studioCreate\""
+	StudioUpdate         *Studio               "json:\"studioUpdate\" graphql:\"studioUpdate\""
+	StudioDestroy        bool                  "json:\"studioDestroy\" graphql:\"studioDestroy\""
+	TagCreate            *Tag                  "json:\"tagCreate\" graphql:\"tagCreate\""
+	TagUpdate            *Tag                  "json:\"tagUpdate\" graphql:\"tagUpdate\""
+	TagDestroy           bool                  "json:\"tagDestroy\" graphql:\"tagDestroy\""
+	UserCreate           *User                 "json:\"userCreate\" graphql:\"userCreate\""
+	UserUpdate           *User                 "json:\"userUpdate\" graphql:\"userUpdate\""
+	UserDestroy          bool                  "json:\"userDestroy\" graphql:\"userDestroy\""
+	ImageCreate          *Image                "json:\"imageCreate\" graphql:\"imageCreate\""
+	ImageDestroy         bool                  "json:\"imageDestroy\" graphql:\"imageDestroy\""
+	NewUser              *string               "json:\"newUser\" graphql:\"newUser\""
+	ActivateNewUser      *User                 "json:\"activateNewUser\" graphql:\"activateNewUser\""
+	GenerateInviteCode   string                "json:\"generateInviteCode\" graphql:\"generateInviteCode\""
+	RescindInviteCode    bool                  "json:\"rescindInviteCode\" graphql:\"rescindInviteCode\""
+	GrantInvite          int                   "json:\"grantInvite\" graphql:\"grantInvite\""
+	RevokeInvite         int                   "json:\"revokeInvite\" graphql:\"revokeInvite\""
+	TagCategoryCreate    *TagCategory          "json:\"tagCategoryCreate\" graphql:\"tagCategoryCreate\""
+	TagCategoryUpdate    *TagCategory          "json:\"tagCategoryUpdate\" graphql:\"tagCategoryUpdate\""
+	TagCategoryDestroy   bool                  "json:\"tagCategoryDestroy\" graphql:\"tagCategoryDestroy\""
+	RegenerateAPIKey     string                "json:\"regenerateAPIKey\" graphql:\"regenerateAPIKey\""
+	ResetPassword        bool                  "json:\"resetPassword\" graphql:\"resetPassword\""
+	ChangePassword       bool                  "json:\"changePassword\" graphql:\"changePassword\""
+	SceneEdit            Edit                  "json:\"sceneEdit\" graphql:\"sceneEdit\""
+	PerformerEdit        Edit                  "json:\"performerEdit\" graphql:\"performerEdit\""
+	StudioEdit           Edit                  "json:\"studioEdit\" graphql:\"studioEdit\""
+	TagEdit              Edit                  "json:\"tagEdit\" graphql:\"tagEdit\""
+	EditVote             Edit                  "json:\"editVote\" graphql:\"editVote\""
+	EditComment          Edit                  "json:\"editComment\" graphql:\"editComment\""
+	ApplyEdit            Edit                  "json:\"applyEdit\" graphql:\"applyEdit\""
+	CancelEdit           Edit                  "json:\"cancelEdit\" graphql:\"cancelEdit\""
+	SubmitFingerprint    bool                  "json:\"submitFingerprint\" graphql:\"submitFingerprint\""
+	SubmitSceneDraft     DraftSubmissionStatus "json:\"submitSceneDraft\" graphql:\"submitSceneDraft\""
+	SubmitPerformerDraft DraftSubmissionStatus "json:\"submitPerformerDraft\" graphql:\"submitPerformerDraft\""
 }
 type URLFragment struct {
 	URL  string "json:\"url\" graphql:\"url\""
@@ -175,6 +177,16 @@ type FindPerformerByID struct {
 type SubmitFingerprintPayload struct {
 	SubmitFingerprint bool "json:\"submitFingerprint\" graphql:\"submitFingerprint\""
 }
+type SubmitSceneDraftPayload struct {
+	SubmitSceneDraft struct {
+		ID *string "json:\"id\" graphql:\"id\""
+	} "json:\"submitSceneDraft\" graphql:\"submitSceneDraft\""
+}
+type SubmitPerformerDraftPayload struct {
+	SubmitPerformerDraft struct {
+		ID *string "json:\"id\" graphql:\"id\""
+	} "json:\"submitPerformerDraft\" graphql:\"submitPerformerDraft\""
+}
 
 const FindSceneByFingerprintQuery = `query FindSceneByFingerprint ($fingerprint: FingerprintQueryInput!) {
 	findSceneByFingerprint(fingerprint: $fingerprint) {
@@ -728,3 +740,43 @@ func (c *Client) SubmitFingerprint(ctx context.Context, input FingerprintSubmiss
 
 	return &res, nil
 }
+
+const SubmitSceneDraftQuery = `mutation SubmitSceneDraft ($input: SceneDraftInput!) {
+	submitSceneDraft(input: $input) {
+		id
+	}
+}
+`
+
+func (c *Client) SubmitSceneDraft(ctx context.Context, input SceneDraftInput, httpRequestOptions ...client.HTTPRequestOption) (*SubmitSceneDraftPayload, error) {
+	vars := map[string]interface{}{
+		"input": input,
+	}
+
+	var res SubmitSceneDraftPayload
+	if err := c.Client.Post(ctx, SubmitSceneDraftQuery, &res, vars, httpRequestOptions...); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+const SubmitPerformerDraftQuery = `mutation SubmitPerformerDraft ($input: PerformerDraftInput!) {
+	submitPerformerDraft(input: $input) {
+		id
+	}
+}
+`
+
+func (c *Client) SubmitPerformerDraft(ctx context.Context, input PerformerDraftInput, httpRequestOptions ...client.HTTPRequestOption) (*SubmitPerformerDraftPayload, error) {
+	vars := map[string]interface{}{
+		"input": input,
+	}
+
+	var res SubmitPerformerDraftPayload
+	if err := c.Client.Post(ctx, SubmitPerformerDraftQuery, &res, vars, httpRequestOptions...); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
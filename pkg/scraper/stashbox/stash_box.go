@@ -25,6 +25,7 @@ const imageGetTimeout = time.Second * 30
 // Client represents the client interface to a stash-box server instance.
 type Client struct {
 	client     *graphql.Client
+	box        models.StashBox
 	txnManager models.TransactionManager
 }
 
@@ -40,6 +41,7 @@ func NewClient(box models.StashBox, txnManager models.TransactionManager) *Clien
 
 	return &Client{
 		client:     client,
+		box:        box,
 		txnManager: txnManager,
 	}
 }
@@ -170,39 +172,10 @@ func (c Client) SubmitStashBoxFingerprints(sceneIDs []string, endpoint string) (
 			}
 
 			if sceneStashID != "" {
-				if scene.Checksum.Valid && scene.Duration.Valid {
-					fingerprint := graphql.FingerprintInput{
-						Hash:      scene.Checksum.String,
-						Algorithm: graphql.FingerprintAlgorithmMd5,
-						Duration:  int(scene.Duration.Float64),
-					}
+				for _, fingerprint := range sceneFingerprints(scene) {
 					fingerprints = append(fingerprints, graphql.FingerprintSubmission{
 						SceneID:     sceneStashID,
-						Fingerprint: &fingerprint,
-					})
-				}
-
-				if scene.OSHash.Valid && scene.Duration.Valid {
-					fingerprint := graphql.FingerprintInput{
-						Hash:      scene.OSHash.String,
-						Algorithm: graphql.FingerprintAlgorithmOshash,
-						Duration:  int(scene.Duration.Float64),
-					}
-					fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-						SceneID:     sceneStashID,
-						Fingerprint: &fingerprint,
-					})
-				}
-
-				if scene.Phash.Valid && scene.Duration.Valid {
-					fingerprint := graphql.FingerprintInput{
-						Hash:      utils.PhashToString(scene.Phash.Int64),
-						Algorithm: graphql.FingerprintAlgorithmPhash,
-						Duration:  int(scene.Duration.Float64),
-					}
-					fingerprints = append(fingerprints, graphql.FingerprintSubmission{
-						SceneID:     sceneStashID,
-						Fingerprint: &fingerprint,
+						Fingerprint: fingerprint,
 					})
 				}
 			}
@@ -216,6 +189,42 @@ func (c Client) SubmitStashBoxFingerprints(sceneIDs []string, endpoint string) (
 	return c.submitStashBoxFingerprints(fingerprints)
 }
 
+// sceneFingerprints returns the fingerprints of the scene. Scenes without a
+// duration have no fingerprints.
+func sceneFingerprints(scene *models.Scene) []*graphql.FingerprintInput {
+	ret := []*graphql.FingerprintInput{}
+	if !scene.Duration.Valid {
+		return ret
+	}
+
+	duration := int(scene.Duration.Float64)
+	if scene.Checksum.Valid {
+		ret = append(ret, &graphql.FingerprintInput{
+			Hash:      scene.Checksum.String,
+			Algorithm: graphql.FingerprintAlgorithmMd5,
+			Duration:  duration,
+		})
+	}
+
+	if scene.OSHash.Valid {
+		ret = append(ret, &graphql.FingerprintInput{
+			Hash:      scene.OSHash.String,
+			Algorithm: graphql.FingerprintAlgorithmOshash,
+			Duration:  duration,
+		})
+	}
+
+	if scene.Phash.Valid {
+		ret = append(ret, &graphql.FingerprintInput{
+			Hash:      utils.PhashToString(scene.Phash.Int64),
+			Algorithm: graphql.FingerprintAlgorithmPhash,
+			Duration:  duration,
+		})
+	}
+
+	return ret
+}
+
 func (c Client) submitStashBoxFingerprints(fingerprints []graphql.FingerprintSubmission) (bool, error) {
 	for _, fingerprint := range fingerprints {
 		_, err := c.client.SubmitFingerprint(context.TODO(), fingerprint)
@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"math/rand"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// requestThrottle throttles the requests of all scrapers.
+var requestThrottle = newThrottle()
+
+// throttle limits the rate and number of simultaneous requests to each
+// domain.
+type throttle struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	domains map[string]*throttleDomain
+}
+
+type throttleDomain struct {
+	// the time at which the next request may start
+	next   time.Time
+	active int
+}
+
+func newThrottle() *throttle {
+	ret := &throttle{
+		domains: make(map[string]*throttleDomain),
+	}
+	ret.cond = sync.NewCond(&ret.mutex)
+	return ret
+}
+
+// throttleKey returns the domain for which requests to u are throttled. All
+// subdomains of a registered domain share the same key.
+func throttleKey(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+
+	return host
+}
+
+func (t *throttle) domain(key string) *throttleDomain {
+	ret := t.domains[key]
+	if ret == nil {
+		ret = &throttleDomain{}
+		t.domains[key] = ret
+	}
+
+	return ret
+}
+
+// wait blocks until a request to the url may be made, and returns the
+// function to call once the request is complete. Requests to the same domain
+// start at least interval apart, plus a random delay of up to jitter, and no
+// more than maxConcurrent are made at once. maxConcurrent is not limited if
+// it is zero.
+func (t *throttle) wait(u string, interval, jitter time.Duration, maxConcurrent int) func() {
+	if interval <= 0 && jitter <= 0 && maxConcurrent <= 0 {
+		return func() {}
+	}
+
+	key := throttleKey(u)
+
+	t.mutex.Lock()
+	d := t.domain(key)
+	for maxConcurrent > 0 && d.active >= maxConcurrent {
+		t.cond.Wait()
+		// the domain is removed once idle
+		d = t.domain(key)
+	}
+	d.active++
+
+	// reserve the start time of this request
+	now := time.Now()
+	start := d.next
+	if start.Before(now) {
+		start = now
+	}
+
+	delay := interval
+	if jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(jitter) + 1))
+	}
+	d.next = start.Add(delay)
+	t.mutex.Unlock()
+
+	time.Sleep(start.Sub(now))
+
+	return func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+
+		d.active--
+		if d.active == 0 && !d.next.After(time.Now()) {
+			delete(t.domains, key)
+		}
+		t.cond.Broadcast()
+	}
+}
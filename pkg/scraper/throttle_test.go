@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottleKey(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.example.com/page", "example.com"},
+		{"https://images.Example.com/image.jpg", "example.com"},
+		{"http://www.example.co.uk:8080/", "example.co.uk"},
+		{"http://localhost:9999/", "localhost"},
+		{"http://127.0.0.1/", "127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, throttleKey(tt.url), tt.url)
+	}
+}
+
+func TestThrottleInterval(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	th := newThrottle()
+
+	start := time.Now()
+	th.wait("https://www.example.com/1", interval, 0, 0)()
+	th.wait("https://example.com/2", interval, 0, 0)()
+	th.wait("https://example.com/3", interval, 0, 0)()
+	assert.True(t, time.Since(start) >= 2*interval, time.Since(start))
+
+	// other domains are not delayed
+	start = time.Now()
+	th.wait("https://example.org/1", interval, 0, 0)()
+	assert.True(t, time.Since(start) < interval, time.Since(start))
+
+	// the jitter is added to the interval
+	th = newThrottle()
+	start = time.Now()
+	th.wait("https://example.com/1", 0, interval, 0)()
+	th.wait("https://example.com/2", 0, interval, 0)()
+	assert.True(t, time.Since(start) <= interval+25*time.Millisecond, time.Since(start))
+}
+
+func TestThrottleConcurrency(t *testing.T) {
+	const maxConcurrent = 2
+	th := newThrottle()
+
+	var mutex sync.Mutex
+	active := 0
+	maxActive := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done := th.wait("https://example.com/", 0, 0, maxConcurrent)
+
+			mutex.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mutex.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mutex.Lock()
+			active--
+			mutex.Unlock()
+			done()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxConcurrent, maxActive)
+	assert.Len(t, th.domains, 0)
+}
@@ -0,0 +1,124 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// scraperTraceLimit is the number of traces kept for each scraper.
+const scraperTraceLimit = 10
+
+// scrapeTrace records the requests made and the selectors run during a
+// scrape. Scrapes are only traced for scrapers with debug tracing enabled.
+// The methods of a nil trace do nothing, so that they can be called whether
+// or not the scrape is traced.
+type scrapeTrace struct {
+	ret models.ScraperTrace
+}
+
+func (t *scrapeTrace) request(url string, status int, cached bool, err error) {
+	if t == nil {
+		return
+	}
+
+	r := &models.ScraperTraceRequest{
+		URL:    url,
+		Cached: cached,
+		Error:  traceError(err),
+	}
+	if status != 0 {
+		r.Status = &status
+	}
+
+	t.ret.Requests = append(t.ret.Requests, r)
+}
+
+func (t *scrapeTrace) selector(selector string, results int, err error) {
+	if t == nil {
+		return
+	}
+
+	t.ret.Selectors = append(t.ret.Selectors, &models.ScraperTraceSelector{
+		Selector: selector,
+		Results:  results,
+		Error:    traceError(err),
+	})
+}
+
+func traceError(err error) *string {
+	if err == nil {
+		return nil
+	}
+
+	ret := err.Error()
+	return &ret
+}
+
+// traceStore holds the most recent traces of each scraper.
+type traceStore struct {
+	mutex  sync.Mutex
+	traces map[string][]*models.ScraperTrace
+}
+
+func newTraceStore() *traceStore {
+	return &traceStore{
+		traces: make(map[string][]*models.ScraperTrace),
+	}
+}
+
+// start returns a copy of the scraper configuration which records a new
+// trace, if tracing is enabled for the scraper. Otherwise the configuration
+// is returned unchanged, with a nil trace.
+func (s *traceStore) start(c config, action string, input string) (config, *scrapeTrace) {
+	if s == nil || c.DebugOptions == nil || !c.DebugOptions.Trace {
+		return c, nil
+	}
+
+	c.trace = &scrapeTrace{
+		ret: models.ScraperTrace{
+			ScraperID: c.ID,
+			Action:    action,
+			Input:     input,
+			StartedAt: time.Now(),
+			Requests:  []*models.ScraperTraceRequest{},
+			Selectors: []*models.ScraperTraceSelector{},
+		},
+	}
+
+	return c, c.trace
+}
+
+// finish stores the trace with the error of the scrape, discarding the
+// oldest trace of the scraper if it has more than scraperTraceLimit.
+func (s *traceStore) finish(t *scrapeTrace, err error) {
+	if s == nil || t == nil {
+		return
+	}
+
+	t.ret.FinishedAt = time.Now()
+	t.ret.Error = traceError(err)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	traces := append([]*models.ScraperTrace{&t.ret}, s.traces[t.ret.ScraperID]...)
+	if len(traces) > scraperTraceLimit {
+		traces = traces[:scraperTraceLimit]
+	}
+	s.traces[t.ret.ScraperID] = traces
+}
+
+// get returns the traces of the scraper, newest first.
+func (s *traceStore) get(scraperID string) []*models.ScraperTrace {
+	ret := []*models.ScraperTrace{}
+	if s == nil {
+		return ret
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append(ret, s.traces[scraperID]...)
+}
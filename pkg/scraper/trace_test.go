@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrapeTrace(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, galleryHTML)
+	}))
+	defer ts.Close()
+
+	cache, _ := makeScraperCache(t, `name: Test
+debug:
+  trace: true
+galleryByURL:
+  - action: scrapeXPath
+    url:
+      - `+ts.URL+`
+    scraper: galleryScraper
+xPathScrapers:
+  galleryScraper:
+    gallery:
+      Title: //h1
+      Details: //div[@class="details"]
+`)
+	cache.traces = newTraceStore()
+
+	_, err := cache.ScrapeGalleryURL(ts.URL + "/gallery")
+	assert.Nil(t, err)
+	_, err = cache.ScrapeGalleryURL(ts.URL + "/missing")
+	assert.NotNil(t, err)
+
+	traces := cache.Traces("test")
+	if !assert.Len(t, traces, 2) {
+		return
+	}
+
+	// the newest trace is first
+	missing := traces[0]
+	assert.Equal(t, "galleryByURL", missing.Action)
+	assert.Equal(t, ts.URL+"/missing", missing.Input)
+	assert.NotNil(t, missing.Error)
+	if assert.Len(t, missing.Requests, 1) {
+		assert.Equal(t, ts.URL+"/missing", missing.Requests[0].URL)
+		if assert.NotNil(t, missing.Requests[0].Status) {
+			assert.Equal(t, http.StatusNotFound, *missing.Requests[0].Status)
+		}
+		assert.NotNil(t, missing.Requests[0].Error)
+	}
+	assert.Len(t, missing.Selectors, 0)
+
+	found := traces[1]
+	assert.Nil(t, found.Error)
+	if assert.Len(t, found.Requests, 1) && assert.NotNil(t, found.Requests[0].Status) {
+		assert.Equal(t, http.StatusOK, *found.Requests[0].Status)
+	}
+
+	results := make(map[string]int)
+	for _, s := range found.Selectors {
+		results[s.Selector] = s.Results
+	}
+	assert.Equal(t, map[string]int{
+		"//h1":                    1,
+		`//div[@class="details"]`: 0,
+	}, results)
+}
+
+func TestScrapeTraceDisabled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, galleryHTML)
+	}))
+	defer ts.Close()
+
+	cache, _ := makeScraperCache(t, `name: Test
+galleryByURL:
+  - action: scrapeXPath
+    url:
+      - `+ts.URL+`
+    scraper: galleryScraper
+xPathScrapers:
+  galleryScraper:
+    gallery:
+      Title: //h1
+`)
+	cache.traces = newTraceStore()
+
+	_, err := cache.ScrapeGalleryURL(ts.URL + "/gallery")
+	assert.Nil(t, err)
+	assert.Len(t, cache.Traces("test"), 0)
+}
+
+func TestTraceStoreLimit(t *testing.T) {
+	store := newTraceStore()
+	c := config{
+		ID:           "test",
+		DebugOptions: &scraperDebugOptions{Trace: true},
+	}
+
+	for i := 0; i < scraperTraceLimit+1; i++ {
+		_, trace := store.start(c, "sceneByURL", fmt.Sprintf("%d", i))
+		store.finish(trace, nil)
+	}
+
+	traces := store.get("test")
+	if assert.Len(t, traces, scraperTraceLimit) {
+		assert.Equal(t, fmt.Sprintf("%d", scraperTraceLimit), traces[0].Input)
+		assert.Equal(t, "1", traces[scraperTraceLimit-1].Input)
+	}
+
+	assert.Equal(t, []*models.ScraperTrace{}, store.get("other"))
+}
@@ -10,8 +10,6 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/chromedp/cdproto/cdp"
@@ -30,28 +28,94 @@ import (
 const scrapeGetTimeout = time.Second * 60
 const scrapeDefaultSleep = time.Second * 2
 
+// the content type of pages loaded by chrome, which are serialised as UTF-8
+const cdpContentType = "text/html; charset=utf-8"
+
+// loadURL loads the page at url, using the response cache if it is enabled.
+// If the page cannot be loaded, an expired cached response is used if there
+// is one.
 func loadURL(url string, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
+	responses := newResponseCache(globalConfig)
+	cacheKey := responseCacheKey(url, scraperConfig, globalConfig)
+	if cached := responses.get(cacheKey); cached != nil {
+		logger.Debugf("[scraper] using cached response for %s", url)
+		scraperConfig.trace.request(url, 0, true, nil)
+		return charset.NewReader(bytes.NewReader(cached.Body), cached.ContentType)
+	}
+
+	// throttle requests to the same site, but not cached responses
+	done := requestThrottle.wait(url,
+		globalConfig.GetScraperRequestInterval(),
+		globalConfig.GetScraperRequestJitter(),
+		globalConfig.GetScraperMaxConcurrency(),
+	)
+
+	var body []byte
+	var contentType string
+	var status int
+	var err error
+
 	driverOptions := scraperConfig.DriverOptions
 	if driverOptions != nil && driverOptions.UseCDP {
 		// get the page using chrome dp
-		return urlFromCDP(url, *driverOptions, globalConfig)
+		var res string
+		res, err = urlFromCDP(url, *driverOptions, globalConfig)
+		body = []byte(res)
+		contentType = cdpContentType
+	} else {
+		body, contentType, status, err = loadURLHTTP(url, scraperConfig, globalConfig)
+	}
+	done()
+
+	if err != nil {
+		if stale := responses.getStale(cacheKey); stale != nil {
+			logger.Warnf("[scraper] error loading %s, using response cached at %s: %s", url, stale.Fetched.Format(time.RFC3339), err.Error())
+			scraperConfig.trace.request(url, status, true, err)
+			return charset.NewReader(bytes.NewReader(stale.Body), stale.ContentType)
+		}
+
+		scraperConfig.trace.request(url, status, false, err)
+		return nil, err
 	}
 
-	// get the page using http.Client
+	scraperConfig.trace.request(url, status, false, nil)
+	responses.put(cacheKey, contentType, body)
+
+	return charset.NewReader(bytes.NewReader(body), contentType)
+}
+
+// loadURLHTTP gets the page at url using http.Client, returning the body,
+// content type and status of the response. The status is zero if there was
+// no response.
+func loadURLHTTP(url string, scraperConfig config, globalConfig GlobalConfig) ([]byte, string, int, error) {
+	driverOptions := scraperConfig.DriverOptions
 	options := cookiejar.Options{
 		PublicSuffixList: publicsuffix.List,
 	}
 	jar, er := cookiejar.New(&options)
 	if er != nil {
-		return nil, er
+		return nil, "", 0, er
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	source := getSource(globalConfig, scraperConfig.ID)
+	proxy, err := sourceProxy(source)
+	if err != nil {
+		return nil, "", 0, err
 	}
 
 	setCookies(jar, scraperConfig)
+	setSourceCookies(jar, req.URL, source)
 	printCookies(jar, scraperConfig, "Jar cookies set from scraper")
 
 	client := &http.Client{
 		Transport: &http.Transport{ // ignore insecure certificates
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: !globalConfig.GetScraperCertCheck()},
+			Proxy:           proxy,
 		},
 		Timeout: scrapeGetTimeout,
 		// defaultCheckRedirect code with max changed from 10 to 20
@@ -64,11 +128,6 @@ func loadURL(url string, scraperConfig config, globalConfig GlobalConfig) (io.Re
 		Jar: jar,
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	userAgent := globalConfig.GetScraperUserAgent()
 	if userAgent != "" {
 		req.Header.Set("User-Agent", userAgent)
@@ -83,34 +142,37 @@ func loadURL(url string, scraperConfig config, globalConfig GlobalConfig) (io.Re
 		}
 	}
 
+	// the headers configured for the source override those of the scraper
+	setSourceHeaders(req, source)
+
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", 0, err
 	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		return nil, "", resp.StatusCode, fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
 
-	defer resp.Body.Close()
-
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", resp.StatusCode, err
 	}
 
-	bodyReader := bytes.NewReader(body)
 	printCookies(jar, scraperConfig, "Jar cookies found for scraper urls")
 
-	return charset.NewReader(bodyReader, resp.Header.Get("Content-Type"))
+	return body, resp.Header.Get("Content-Type"), resp.StatusCode, nil
 }
 
 // func urlFromCDP uses chrome cdp and DOM to load and process the url
-// if remote is set as true in the scraperConfig  it will try to use localhost:9222
-// else it will look for google-chrome in path
-func urlFromCDP(url string, driverOptions scraperDriverOptions, globalConfig GlobalConfig) (io.Reader, error) {
+// the page is loaded by the browser pool, which uses the remote instance if
+// the CDP path is an address, else the executable at the CDP path, an
+// installed chrome, or a downloaded chromium
+func urlFromCDP(url string, driverOptions scraperDriverOptions, globalConfig GlobalConfig) (res string, err error) {
 
 	if !driverOptions.UseCDP {
-		return nil, fmt.Errorf("Url shouldn't be feetched through CDP")
+		return "", fmt.Errorf("Url shouldn't be feetched through CDP")
 	}
 
 	sleepDuration := scrapeDefaultSleep
@@ -119,55 +181,21 @@ func urlFromCDP(url string, driverOptions scraperDriverOptions, globalConfig Glo
 		sleepDuration = time.Duration(driverOptions.Sleep) * time.Second
 	}
 
-	act := context.Background()
-
-	// if scraperCDPPath is a remote address, then allocate accordingly
-	cdpPath := globalConfig.GetScraperCDPPath()
-	if cdpPath != "" {
-		var cancelAct context.CancelFunc
-
-		if isCDPPathHTTP(globalConfig) || isCDPPathWS(globalConfig) {
-			remote := cdpPath
-
-			// if CDPPath is http(s) then we need to get the websocket URL
-			if isCDPPathHTTP(globalConfig) {
-				var err error
-				remote, err = getRemoteCDPWSAddress(remote)
-				if err != nil {
-					return nil, err
-				}
-			}
-
-			act, cancelAct = chromedp.NewRemoteAllocator(context.Background(), remote)
-		} else {
-			// use a temporary user directory for chrome
-			dir, err := ioutil.TempDir("", "stash-chromedp")
-			if err != nil {
-				return nil, err
-			}
-			defer os.RemoveAll(dir)
-
-			opts := append(chromedp.DefaultExecAllocatorOptions[:],
-				chromedp.UserDataDir(dir),
-				chromedp.ExecPath(cdpPath),
-			)
-			act, cancelAct = chromedp.NewExecAllocator(act, opts...)
-		}
-
-		defer cancelAct()
+	pg, err := browsers.get(globalConfig)
+	if err != nil {
+		return "", err
 	}
-
-	ctx, cancel := chromedp.NewContext(act)
-	defer cancel()
+	defer func() {
+		browsers.put(pg, err)
+	}()
 
 	// add a fixed timeout for the http request
-	ctx, cancel = context.WithTimeout(ctx, scrapeGetTimeout)
+	ctx, cancel := context.WithTimeout(pg.ctx, scrapeGetTimeout)
 	defer cancel()
 
-	var res string
 	headers := cdpHeaders(driverOptions)
 
-	err := chromedp.Run(ctx,
+	err = chromedp.Run(ctx,
 		network.Enable(),
 		setCDPCookies(driverOptions),
 		printCDPCookies(driverOptions, "Cookies found"),
@@ -187,10 +215,10 @@ func urlFromCDP(url string, driverOptions scraperDriverOptions, globalConfig Glo
 	)
 
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return strings.NewReader(res), nil
+	return res, nil
 }
 
 // click all xpaths listed in the scraper config
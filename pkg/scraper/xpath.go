@@ -164,7 +164,7 @@ func (s *xpathScraper) scrapeGalleryByFragment(gallery models.GalleryUpdateInput
 	}
 
 	if storedGallery == nil {
-		return nil, errors.New("no scene found")
+		return nil, errors.New("no gallery found")
 	}
 
 	// construct the URL
@@ -219,10 +219,27 @@ type xpathQuery struct {
 	scraper *xpathScraper
 }
 
+// validateXPathSelector returns an error if the selector is not a valid
+// xpath expression.
+func validateXPathSelector(selector string) error {
+	_, err := htmlquery.QueryAll(&html.Node{Type: html.DocumentNode}, selector)
+	return err
+}
+
+// trace returns the trace of the current scrape, or nil if it is not traced.
+func (q *xpathQuery) trace() *scrapeTrace {
+	if q.scraper == nil {
+		return nil
+	}
+
+	return q.scraper.config.trace
+}
+
 func (q *xpathQuery) runQuery(selector string) []string {
 	found, err := htmlquery.QueryAll(q.doc, selector)
 	if err != nil {
 		logger.Warnf("Error parsing xpath expression '%s': %s", selector, err.Error())
+		q.trace().selector(selector, 0, err)
 		return nil
 	}
 
@@ -235,6 +252,7 @@ func (q *xpathQuery) runQuery(selector string) []string {
 		}
 	}
 
+	q.trace().selector(selector, len(ret), nil)
 	return ret
 }
 
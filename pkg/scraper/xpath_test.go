@@ -6,9 +6,11 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/antchfx/htmlquery"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 )
@@ -809,6 +811,22 @@ func TestLoadInvalidXPath(t *testing.T) {
 
 type mockGlobalConfig struct{}
 
+// makeScraperCache returns a cache containing the scraper loaded from
+// yamlStr, using mock transactions.
+func makeScraperCache(t *testing.T, yamlStr string) (Cache, *mocks.TransactionManager) {
+	c := config{ID: "test"}
+	if err := yaml.Unmarshal([]byte(yamlStr), &c); err != nil {
+		t.Fatalf("Error loading yaml: %s", err.Error())
+	}
+
+	txnManager := mocks.NewTransactionManager()
+	return Cache{
+		scrapers:     []config{c},
+		globalConfig: mockGlobalConfig{},
+		txnManager:   txnManager,
+	}, txnManager
+}
+
 func (mockGlobalConfig) GetScraperUserAgent() string {
 	return ""
 }
@@ -825,6 +843,34 @@ func (mockGlobalConfig) GetScraperCertCheck() bool {
 	return false
 }
 
+func (mockGlobalConfig) GetConfigPath() string {
+	return ""
+}
+
+func (mockGlobalConfig) GetCachePath() string {
+	return ""
+}
+
+func (mockGlobalConfig) GetScraperCacheTTL() time.Duration {
+	return 0
+}
+
+func (mockGlobalConfig) GetScraperSources() []*models.ScraperSource {
+	return nil
+}
+
+func (mockGlobalConfig) GetScraperRequestInterval() time.Duration {
+	return 0
+}
+
+func (mockGlobalConfig) GetScraperRequestJitter() time.Duration {
+	return 0
+}
+
+func (mockGlobalConfig) GetScraperMaxConcurrency() int {
+	return 0
+}
+
 func TestSubScrape(t *testing.T) {
 	retHTML := `
 	<div>
@@ -0,0 +1,44 @@
+// +build integration
+
+package sqlite_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlineBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stash-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backupPath := filepath.Join(dir, "backup.sqlite")
+	if err := database.OnlineBackup(database.DB, backupPath); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sqlx.Connect("sqlite3", "file:"+backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var want, got int
+	if err := database.DB.Get(&want, "SELECT COUNT(*) FROM scenes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Get(&got, "SELECT COUNT(*) FROM scenes"); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotZero(t, want)
+	assert.Equal(t, want, got)
+}
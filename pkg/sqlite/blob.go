@@ -0,0 +1,496 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const blobTable = "blobs"
+
+// BlobStoreOptions configures where images are stored.
+type BlobStoreOptions struct {
+	// UseFilesystem stores new images as files in Path rather than in the
+	// database.
+	UseFilesystem bool
+
+	// Path is the directory of the images stored in the filesystem. Images
+	// stored in the filesystem are read from it regardless of UseFilesystem.
+	Path string
+}
+
+// BlobStorage returns the options of the blob store. It is set by the
+// manager, since the options depend on the configuration.
+var BlobStorage = func() BlobStoreOptions {
+	return BlobStoreOptions{}
+}
+
+type blobReference struct {
+	table  string
+	column string
+}
+
+// blobReferences are the columns which reference blobs. Blobs are deleted
+// once they are not referenced by any of them.
+var blobReferences = []blobReference{
+	{"performers_image", "image_blob"},
+	{"studios_image", "image_blob"},
+	{"tags_image", "image_blob"},
+	{"movies_images", "front_image_blob"},
+	{"movies_images", "back_image_blob"},
+}
+
+// fileRemover is implemented by database handles which remove files once
+// the transaction they are used in is committed.
+type fileRemover interface {
+	removeOnCommit(path string)
+	// keepOnCommit cancels the removal of a file which was written again
+	// within the transaction.
+	keepOnCommit(path string)
+}
+
+// blobStore stores images by their checksum, so that identical images are
+// stored once. Images are stored in the blobs table, or in the filesystem
+// in which case their blob is null.
+type blobStore struct {
+	tx      dbi
+	options BlobStoreOptions
+}
+
+func newBlobStore(tx dbi) *blobStore {
+	return &blobStore{
+		tx:      tx,
+		options: BlobStorage(),
+	}
+}
+
+// blobPath returns the path of the file of the blob with the checksum in
+// dir. Files are spread across directories named after the start of the
+// checksum.
+func blobPath(dir string, checksum string) string {
+	if len(checksum) < 4 {
+		return filepath.Join(dir, checksum)
+	}
+
+	return filepath.Join(dir, checksum[0:2], checksum[2:4], checksum)
+}
+
+func (s *blobStore) path(checksum string) (string, error) {
+	if s.options.Path == "" {
+		return "", errors.New("blobs path is not set")
+	}
+
+	return blobPath(s.options.Path, checksum), nil
+}
+
+// write stores the data if it is not already stored, and returns its
+// checksum.
+func (s *blobStore) write(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("image is empty")
+	}
+
+	checksum := utils.MD5FromBytes(data)
+
+	var count int
+	if err := s.tx.Get(&count, "SELECT COUNT(*) FROM "+blobTable+" WHERE checksum = ?", checksum); err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return checksum, nil
+	}
+
+	blob := data
+	if s.options.UseFilesystem {
+		path, err := s.path(checksum)
+		if err != nil {
+			return "", err
+		}
+
+		// a file left by a transaction which was rolled back is overwritten
+		if err := utils.WriteFile(path, data); err != nil {
+			return "", err
+		}
+		blob = nil
+
+		// the blob may have been deleted earlier in the transaction
+		if r, ok := s.tx.(fileRemover); ok {
+			r.keepOnCommit(path)
+		}
+	}
+
+	if _, err := s.tx.Exec("INSERT INTO "+blobTable+" (checksum, blob) VALUES (?, ?)", checksum, blob); err != nil {
+		return "", err
+	}
+
+	return checksum, nil
+}
+
+// read returns the data of the blob with the checksum, reading it from the
+// filesystem if blob is nil.
+func (s *blobStore) read(checksum string, blob []byte) ([]byte, error) {
+	if blob != nil {
+		return blob, nil
+	}
+
+	path, err := s.path(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading image %s: %s", checksum, err.Error())
+	}
+
+	return ret, nil
+}
+
+// get returns the data of the blob of which the query selects the checksum
+// and blob, or nil if the query selects no blob.
+func (s *blobStore) get(query string, args ...interface{}) ([]byte, error) {
+	rows, err := s.tx.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checksum sql.NullString
+	var blob []byte
+	if rows.Next() {
+		if err := rows.Scan(&checksum, &blob); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !checksum.Valid {
+		return nil, nil
+	}
+
+	return s.read(checksum.String, blob)
+}
+
+// deleteUnreferenced deletes the blobs with the checksums which are no
+// longer referenced. Files are removed once the transaction is committed,
+// so that they remain if it is rolled back.
+func (s *blobStore) deleteUnreferenced(checksums ...string) error {
+	for _, checksum := range checksums {
+		if checksum == "" {
+			continue
+		}
+
+		where := []string{"checksum = ?"}
+		args := []interface{}{checksum}
+		for _, r := range blobReferences {
+			where = append(where, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s WHERE %s = ?)", r.table, r.column))
+			args = append(args, checksum)
+		}
+
+		var inFilesystem []bool
+		query := fmt.Sprintf("SELECT blob IS NULL FROM %s WHERE %s", blobTable, strings.Join(where, " AND "))
+		if err := s.tx.Select(&inFilesystem, query, args...); err != nil {
+			return err
+		}
+		if len(inFilesystem) == 0 {
+			continue
+		}
+
+		stmt := fmt.Sprintf("DELETE FROM %s WHERE checksum = ?", blobTable)
+		if _, err := s.tx.Exec(stmt, checksum); err != nil {
+			return err
+		}
+
+		if inFilesystem[0] && s.options.Path != "" {
+			if r, ok := s.tx.(fileRemover); ok {
+				r.removeOnCommit(blobPath(s.options.Path, checksum))
+			}
+		}
+	}
+
+	return nil
+}
+
+// imageBlobRepository stores the image of each object in the blob store,
+// as a reference to the blob in blobColumn.
+type imageBlobRepository struct {
+	repository
+	blobColumn string
+}
+
+func (r *imageBlobRepository) store() *blobStore {
+	return newBlobStore(r.tx)
+}
+
+func (r *imageBlobRepository) checksum(id int) (string, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", r.blobColumn, r.tableName, r.idColumn)
+	var ret sql.NullString
+	err := r.querySimple(query, []interface{}{id}, &ret)
+	return ret.String, err
+}
+
+func (r *imageBlobRepository) get(id int) ([]byte, error) {
+	query := fmt.Sprintf("SELECT %[1]s.%[2]s, %[3]s.blob FROM %[1]s LEFT JOIN %[3]s ON %[3]s.checksum = %[1]s.%[2]s WHERE %[1]s.%[4]s = ?", r.tableName, r.blobColumn, blobTable, r.idColumn)
+	return r.store().get(query, id)
+}
+
+func (r *imageBlobRepository) replace(id int, image []byte) error {
+	old, err := r.checksum(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.repository.destroy([]int{id}); err != nil {
+		return err
+	}
+
+	store := r.store()
+	checksum, err := store.write(image)
+	if err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)", r.tableName, r.idColumn, r.blobColumn)
+	if _, err := r.tx.Exec(stmt, id, checksum); err != nil {
+		return err
+	}
+
+	return store.deleteUnreferenced(old)
+}
+
+func (r *imageBlobRepository) destroy(id int) error {
+	old, err := r.checksum(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.repository.destroy([]int{id}); err != nil {
+		return err
+	}
+
+	return r.store().deleteUnreferenced(old)
+}
+
+// migrate moves the blob with the checksum to where new blobs are stored.
+func (s *blobStore) migrate(checksum string) error {
+	path, err := s.path(checksum)
+	if err != nil {
+		return err
+	}
+
+	if s.options.UseFilesystem {
+		var blob []byte
+		if err := s.tx.Get(&blob, "SELECT blob FROM "+blobTable+" WHERE checksum = ?", checksum); err != nil {
+			return err
+		}
+		if blob == nil {
+			return nil
+		}
+
+		if err := utils.WriteFile(path, blob); err != nil {
+			return err
+		}
+
+		_, err := s.tx.Exec("UPDATE "+blobTable+" SET blob = NULL WHERE checksum = ?", checksum)
+		return err
+	}
+
+	data, err := s.read(checksum, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.tx.Exec("UPDATE "+blobTable+" SET blob = ? WHERE checksum = ?", data, checksum); err != nil {
+		return err
+	}
+
+	if r, ok := s.tx.(fileRemover); ok {
+		r.removeOnCommit(path)
+	}
+
+	return nil
+}
+
+// isBlobFile returns true if the path relative to the blobs path is of a
+// file named by blobPath, so that other files in the blobs path are left
+// alone.
+func isBlobFile(rel string) bool {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) != 3 {
+		return false
+	}
+
+	checksum := parts[2]
+	if len(checksum) != 32 || strings.Trim(checksum, "0123456789abcdef") != "" {
+		return false
+	}
+
+	return parts[0] == checksum[0:2] && parts[1] == checksum[2:4]
+}
+
+// isBlobDir returns true if the directory relative to the blobs path may
+// contain files named by blobPath.
+func isBlobDir(rel string) bool {
+	if rel == "." {
+		return true
+	}
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) > 2 {
+		return false
+	}
+
+	for _, p := range parts {
+		if len(p) != 2 || strings.Trim(p, "0123456789abcdef") != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clean deletes the blobs which are not referenced, and removes the files in
+// the blobs path which are named as blobs but are not of blobs stored in the
+// filesystem. Returns the number of files removed.
+func (s *blobStore) clean() (int, error) {
+	var where []string
+	for _, r := range blobReferences {
+		where = append(where, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s WHERE %s = %s.checksum)", r.table, r.column, blobTable))
+	}
+	if _, err := s.tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", blobTable, strings.Join(where, " AND "))); err != nil {
+		return 0, err
+	}
+
+	if s.options.Path == "" {
+		return 0, nil
+	}
+
+	var checksums []string
+	if err := s.tx.Select(&checksums, "SELECT checksum FROM "+blobTable+" WHERE blob IS NULL"); err != nil {
+		return 0, err
+	}
+	stored := make(map[string]bool)
+	for _, c := range checksums {
+		stored[c] = true
+	}
+
+	removed := 0
+	err := filepath.Walk(s.options.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(s.options.Path, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if !isBlobDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() || !isBlobFile(rel) || stored[info.Name()] {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			logger.Warnf("error removing %s: %s", path, err.Error())
+			return nil
+		}
+		removed++
+		return nil
+	})
+
+	return removed, err
+}
+
+// blobMigrateBatch is the number of blobs moved in each transaction.
+const blobMigrateBatch = 100
+
+// withBlobTxn calls fn with a blob store using a new write transaction.
+func withBlobTxn(ctx context.Context, options BlobStoreOptions, fn func(s *blobStore) error) error {
+	database.WriteMu.Lock()
+	defer database.WriteMu.Unlock()
+
+	txn := &transaction{Ctx: ctx}
+	return models.WithTxn(txn, func(r models.Repository) error {
+		return fn(&blobStore{tx: txn.db(), options: options})
+	})
+}
+
+// MigrateBlobs moves the stored images to where BlobStorage stores new
+// images, then deletes the images which are no longer used. progress is
+// called with the number of images moved and the total number to move.
+// Images which cannot be moved are logged and skipped.
+func MigrateBlobs(ctx context.Context, progress func(done int, total int)) error {
+	if err := database.Ready(); err != nil {
+		return err
+	}
+
+	options := BlobStorage()
+	if options.Path == "" {
+		return errors.New("blobs path is not set")
+	}
+
+	query := "SELECT checksum FROM " + blobTable + " WHERE blob IS NULL"
+	if options.UseFilesystem {
+		query = "SELECT checksum FROM " + blobTable + " WHERE blob IS NOT NULL"
+	}
+
+	var checksums []string
+	if err := database.DB.Select(&checksums, query); err != nil {
+		return err
+	}
+
+	total := len(checksums)
+	for i := 0; i < total; i += blobMigrateBatch {
+		progress(i, total)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := i + blobMigrateBatch
+		if end > total {
+			end = total
+		}
+
+		if err := withBlobTxn(ctx, options, func(s *blobStore) error {
+			for _, checksum := range checksums[i:end] {
+				if err := s.migrate(checksum); err != nil {
+					logger.Warnf("error moving image %s: %s", checksum, err.Error())
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	progress(total, total)
+
+	return withBlobTxn(ctx, options, func(s *blobStore) error {
+		removed, err := s.clean()
+		if removed > 0 {
+			logger.Infof("Removed %d unused image files", removed)
+		}
+		return err
+	})
+}
@@ -0,0 +1,279 @@
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// withBlobStorage stores images in the filesystem in a temporary directory
+// while f runs. Images are moved back into the database afterwards.
+func withBlobStorage(t *testing.T, f func(dir string, setUseFilesystem func(bool))) {
+	dir, err := ioutil.TempDir("", "blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	old := sqlite.BlobStorage
+	options := sqlite.BlobStoreOptions{
+		UseFilesystem: true,
+		Path:          dir,
+	}
+	sqlite.BlobStorage = func() sqlite.BlobStoreOptions {
+		return options
+	}
+	defer func() {
+		sqlite.BlobStorage = old
+	}()
+
+	f(dir, func(v bool) {
+		options.UseFilesystem = v
+	})
+
+	options.UseFilesystem = false
+	if err := sqlite.MigrateBlobs(context.TODO(), func(int, int) {}); err != nil {
+		t.Errorf("Error moving images into the database: %s", err.Error())
+	}
+}
+
+func blobFile(dir string, data []byte) string {
+	checksum := utils.MD5FromBytes(data)
+	return filepath.Join(dir, checksum[0:2], checksum[2:4], checksum)
+}
+
+func createBlobTestPerformer(r models.Repository, name string) (*models.Performer, error) {
+	return r.Performer().Create(models.Performer{
+		Name:     sql.NullString{String: name, Valid: true},
+		Checksum: utils.MD5FromString(name),
+		Favorite: sql.NullBool{Bool: false, Valid: true},
+	})
+}
+
+func TestBlobStoreFilesystem(t *testing.T) {
+	withBlobStorage(t, func(dir string, setUseFilesystem func(bool)) {
+		image := []byte("TestBlobStoreFilesystem")
+		path := blobFile(dir, image)
+
+		var performer *models.Performer
+		var tag *models.Tag
+		if err := withTxn(func(r models.Repository) error {
+			var err error
+			performer, err = createBlobTestPerformer(r, "TestBlobStoreFilesystem")
+			if err != nil {
+				return err
+			}
+
+			tag, err = r.Tag().Create(models.Tag{Name: "TestBlobStoreFilesystem"})
+			if err != nil {
+				return err
+			}
+
+			// the same image is stored once
+			if err := r.Performer().UpdateImage(performer.ID, image); err != nil {
+				return err
+			}
+			return r.Tag().UpdateImage(tag.ID, image)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		data, err := ioutil.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, image, data)
+
+		if err := withTxn(func(r models.Repository) error {
+			stored, err := r.Tag().GetImage(tag.ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, image, stored)
+
+			return r.Performer().Destroy(performer.ID)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		// the image is still used by the tag
+		_, err = os.Stat(path)
+		assert.Nil(t, err)
+
+		// the file remains if the transaction is rolled back
+		err = withTxn(func(r models.Repository) error {
+			if err := r.Tag().DestroyImage(tag.ID); err != nil {
+				return err
+			}
+			return fmt.Errorf("rollback")
+		})
+		assert.NotNil(t, err)
+		_, err = os.Stat(path)
+		assert.Nil(t, err)
+
+		if err := withTxn(func(r models.Repository) error {
+			return r.Tag().Destroy(tag.ID)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		_, err = os.Stat(path)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestBlobStoreDeleteRewrite(t *testing.T) {
+	withBlobStorage(t, func(dir string, setUseFilesystem func(bool)) {
+		image := []byte("TestBlobStoreDeleteRewrite")
+		other := []byte("TestBlobStoreDeleteRewrite other")
+		path := blobFile(dir, image)
+
+		var performer *models.Performer
+		if err := withTxn(func(r models.Repository) error {
+			var err error
+			performer, err = createBlobTestPerformer(r, "TestBlobStoreDeleteRewrite")
+			if err != nil {
+				return err
+			}
+
+			return r.Performer().UpdateImage(performer.ID, image)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		// the image is replaced and then restored in the same transaction
+		if err := withTxn(func(r models.Repository) error {
+			if err := r.Performer().UpdateImage(performer.ID, other); err != nil {
+				return err
+			}
+			return r.Performer().UpdateImage(performer.ID, image)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		data, err := ioutil.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, image, data)
+
+		// the replacement is removed, since it is no longer used
+		_, err = os.Stat(blobFile(dir, other))
+		assert.True(t, os.IsNotExist(err))
+
+		if err := withTxn(func(r models.Repository) error {
+			return r.Performer().Destroy(performer.ID)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+	})
+}
+
+func TestMigrateBlobs(t *testing.T) {
+	withBlobStorage(t, func(dir string, setUseFilesystem func(bool)) {
+		setUseFilesystem(false)
+
+		front := []byte("TestMigrateBlobs front")
+		back := []byte("TestMigrateBlobs back")
+
+		var movie *models.Movie
+		if err := withTxn(func(r models.Repository) error {
+			var err error
+			movie, err = r.Movie().Create(models.Movie{
+				Name:     sql.NullString{String: "TestMigrateBlobs", Valid: true},
+				Checksum: utils.MD5FromString("TestMigrateBlobs"),
+			})
+			if err != nil {
+				return err
+			}
+
+			return r.Movie().UpdateImages(movie.ID, front, back)
+		}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		_, err := os.Stat(blobFile(dir, front))
+		assert.True(t, os.IsNotExist(err))
+
+		// files which are not of stored images are removed
+		stray := blobFile(dir, []byte("stray"))
+		if err := utils.WriteFile(stray, []byte("stray")); err != nil {
+			t.Fatal(err)
+		}
+
+		// files which are not named as images are left alone
+		foreign := []string{
+			filepath.Join(dir, "foreign.txt"),
+			filepath.Join(dir, "other", "foreign.txt"),
+			filepath.Join(filepath.Dir(stray), "foreign.txt"),
+		}
+		for _, f := range foreign {
+			if err := utils.WriteFile(f, []byte("foreign")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		setUseFilesystem(true)
+		if err := sqlite.MigrateBlobs(context.TODO(), func(int, int) {}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		for _, f := range foreign {
+			_, err := os.Stat(f)
+			assert.Nil(t, err)
+		}
+
+		for _, image := range [][]byte{front, back} {
+			data, err := ioutil.ReadFile(blobFile(dir, image))
+			assert.Nil(t, err)
+			assert.Equal(t, image, data)
+		}
+		_, err = os.Stat(stray)
+		assert.True(t, os.IsNotExist(err))
+
+		if err := withTxn(func(r models.Repository) error {
+			stored, err := r.Movie().GetBackImage(movie.ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, back, stored)
+			return nil
+		}); err != nil {
+			t.Error(err.Error())
+		}
+
+		// the files are removed once the images are moved back
+		setUseFilesystem(false)
+		if err := sqlite.MigrateBlobs(context.TODO(), func(int, int) {}); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		_, err = os.Stat(blobFile(dir, front))
+		assert.True(t, os.IsNotExist(err))
+
+		for _, f := range foreign {
+			_, err := os.Stat(f)
+			assert.Nil(t, err)
+		}
+
+		if err := withTxn(func(r models.Repository) error {
+			stored, err := r.Movie().GetFrontImage(movie.ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, front, stored)
+
+			return r.Movie().Destroy(movie.ID)
+		}); err != nil {
+			t.Error(err.Error())
+		}
+	})
+}
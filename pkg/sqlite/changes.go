@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// entityTables maps the table of each entity to the type reported in change
+// events.
+var entityTables = map[string]models.EntityType{
+	sceneTable:       models.EntityTypeScene,
+	sceneMarkerTable: models.EntityTypeSceneMarker,
+	imageTable:       models.EntityTypeImage,
+	galleryTable:     models.EntityTypeGallery,
+	performerTable:   models.EntityTypePerformer,
+	studioTable:      models.EntityTypeStudio,
+	tagTable:         models.EntityTypeTag,
+	movieTable:       models.EntityTypeMovie,
+	playlistTable:    models.EntityTypePlaylist,
+	groupTable:       models.EntityTypeGroup,
+}
+
+// entityIDColumns maps the columns referencing each entity, so that changes
+// to join, image and stash id tables are reported as updates to the owning
+// entity.
+var entityIDColumns = map[string]models.EntityType{
+	sceneIDColumn:     models.EntityTypeScene,
+	"scene_marker_id": models.EntityTypeSceneMarker,
+	imageIDColumn:     models.EntityTypeImage,
+	galleryIDColumn:   models.EntityTypeGallery,
+	performerIDColumn: models.EntityTypePerformer,
+	studioIDColumn:    models.EntityTypeStudio,
+	tagIDColumn:       models.EntityTypeTag,
+	"movie_id":        models.EntityTypeMovie,
+	playlistIDColumn:  models.EntityTypePlaylist,
+	groupIDColumn:     models.EntityTypeGroup,
+}
+
+// changeRecorder is implemented by database handles which track the objects
+// changed within a transaction.
+type changeRecorder interface {
+	recordChange(tableName string, idColumn string, change models.EntityChangeType, ids []int)
+}
+
+type entityChangeKey struct {
+	entityType models.EntityType
+	change     models.EntityChangeType
+}
+
+// changeSet accumulates the objects changed within a transaction.
+type changeSet struct {
+	keys []entityChangeKey
+	ids  map[entityChangeKey][]int
+	seen map[entityChangeKey]map[int]bool
+
+	// removedFiles are the files to remove once the transaction is
+	// committed
+	removedFiles []string
+}
+
+func (c *changeSet) recordChange(tableName string, idColumn string, change models.EntityChangeType, ids []int) {
+	entityType, found := entityTables[tableName]
+	if !found {
+		entityType, found = entityIDColumns[idColumn]
+		if !found {
+			return
+		}
+
+		// changes to related tables are updates to the owning object
+		change = models.EntityChangeTypeUpdated
+	}
+
+	if c.ids == nil {
+		c.ids = make(map[entityChangeKey][]int)
+		c.seen = make(map[entityChangeKey]map[int]bool)
+	}
+
+	key := entityChangeKey{entityType, change}
+	if c.seen[key] == nil {
+		c.keys = append(c.keys, key)
+		c.seen[key] = make(map[int]bool)
+	}
+
+	for _, id := range ids {
+		if !c.seen[key][id] {
+			c.seen[key][id] = true
+			c.ids[key] = append(c.ids[key], id)
+		}
+	}
+}
+
+// events returns the recorded changes in the order they were first made.
+// Updates to objects which were created or destroyed in the same
+// transaction are omitted.
+func (c *changeSet) events() []*models.EntityChangedEvent {
+	var ret []*models.EntityChangedEvent
+	for _, key := range c.keys {
+		var ids []string
+		for _, id := range c.ids[key] {
+			if key.change == models.EntityChangeTypeUpdated {
+				created := c.seen[entityChangeKey{key.entityType, models.EntityChangeTypeCreated}]
+				destroyed := c.seen[entityChangeKey{key.entityType, models.EntityChangeTypeDestroyed}]
+				if created[id] || destroyed[id] {
+					continue
+				}
+			}
+
+			ids = append(ids, strconv.Itoa(id))
+		}
+
+		if len(ids) > 0 {
+			ret = append(ret, &models.EntityChangedEvent{
+				Type:   key.entityType,
+				Change: key.change,
+				Ids:    ids,
+			})
+		}
+	}
+
+	return ret
+}
+
+// trackedTx records the changes made using it to a changeSet.
+type trackedTx struct {
+	dbi
+	changes *changeSet
+}
+
+func (t *trackedTx) recordChange(tableName string, idColumn string, change models.EntityChangeType, ids []int) {
+	t.changes.recordChange(tableName, idColumn, change, ids)
+}
+
+func (t *trackedTx) removeOnCommit(path string) {
+	t.changes.removedFiles = append(t.changes.removedFiles, path)
+}
+
+func (t *trackedTx) keepOnCommit(path string) {
+	var kept []string
+	for _, p := range t.changes.removedFiles {
+		if p != path {
+			kept = append(kept, p)
+		}
+	}
+	t.changes.removedFiles = kept
+}
@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const deletedObjectTable = "deleted_objects"
+
+// deletedObjectQueryBuilder reads the deleted objects table, which is
+// populated by triggers when objects are deleted.
+type deletedObjectQueryBuilder struct {
+	repository
+}
+
+func NewDeletedObjectReader(tx dbi) *deletedObjectQueryBuilder {
+	return &deletedObjectQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: deletedObjectTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *deletedObjectQueryBuilder) FindSince(since time.Time) ([]*models.DeletedObject, error) {
+	// compare as julian days since the timestamps may be in different time
+	// zones
+	query := selectAll(deletedObjectTable) + "WHERE julianday(deleted_at) > julianday(?) ORDER BY id ASC"
+
+	var ret models.DeletedObjects
+	if err := qb.query(query, []interface{}{since.Format(time.RFC3339Nano)}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.DeletedObject(ret), nil
+}
@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletedObjectFindSince(t *testing.T) {
+	const name = "TestDeletedObjectFindSince"
+
+	// deletion times are recorded to the second
+	since := time.Now().Add(-2 * time.Second)
+
+	var performerChecksum string
+	if err := withTxn(func(r models.Repository) error {
+		tag, err := r.Tag().Create(models.Tag{
+			Name: name,
+		})
+		if err != nil {
+			return err
+		}
+
+		performer, err := r.Performer().Create(models.Performer{
+			Name:     sql.NullString{String: name, Valid: true},
+			Checksum: name,
+			Favorite: sql.NullBool{Bool: false, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		performerChecksum = performer.Checksum
+
+		if err := r.Tag().Destroy(tag.ID); err != nil {
+			return err
+		}
+
+		return r.Performer().Destroy(performer.ID)
+	}); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	var found []*models.DeletedObject
+	var later []*models.DeletedObject
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.DeletedObject()
+
+		deleted, err := qb.FindSince(since)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range deleted {
+			if o.Name.String == name {
+				found = append(found, o)
+			}
+		}
+
+		later, err = qb.FindSince(time.Now().Add(time.Hour))
+		return err
+	}); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, models.DeletedObjectTypeTag, found[0].Type)
+		assert.False(t, found[0].Checksum.Valid)
+
+		assert.Equal(t, models.DeletedObjectTypePerformer, found[1].Type)
+		assert.Equal(t, performerChecksum, found[1].Checksum.String)
+		assert.False(t, found[1].DeletedAt.Timestamp.Before(since.Truncate(time.Second)))
+	}
+
+	assert.Len(t, later, 0)
+}
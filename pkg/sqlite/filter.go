@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/stashapp/stash/pkg/models"
 )
@@ -353,6 +354,100 @@ func intCriterionHandler(c *models.IntCriterionInput, column string) criterionHa
 	}
 }
 
+// the layout of the values of date criteria, and of datetime() in sqlite
+const (
+	dateCriterionLayout  = "2006-01-02"
+	sqliteDateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// dateCriterionHandler filters a datetime column by the day of its value in
+// the local time zone. The column may have been stored in any offset, so it
+// is compared in UTC.
+func dateCriterionHandler(c *models.DateCriterionInput, column string) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		switch c.Modifier {
+		case models.CriterionModifierIsNull:
+			f.addWhere(column + " IS NULL")
+			return
+		case models.CriterionModifierNotNull:
+			f.addWhere(column + " IS NOT NULL")
+			return
+		}
+
+		// returns the start of the day of v, and the start of the next day
+		parseDay := func(v string) (string, string, error) {
+			t, err := time.ParseInLocation(dateCriterionLayout, v, time.Local)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid date %q: expected YYYY-MM-DD", v)
+			}
+
+			return t.UTC().Format(sqliteDateTimeLayout), t.AddDate(0, 0, 1).UTC().Format(sqliteDateTimeLayout), nil
+		}
+
+		start, end, err := parseDay(c.Value)
+		if err != nil {
+			f.setError(err)
+			return
+		}
+
+		value := fmt.Sprintf("datetime(%s)", column)
+
+		switch c.Modifier {
+		case models.CriterionModifierEquals:
+			f.addWhere(fmt.Sprintf("(%s >= ? AND %[1]s < ?)", value), start, end)
+		case models.CriterionModifierNotEquals:
+			f.addWhere(fmt.Sprintf("(%s IS NULL OR %s < ? OR %s >= ?)", column, value, value), start, end)
+		case models.CriterionModifierGreaterThan:
+			f.addWhere(value+" >= ?", end)
+		case models.CriterionModifierLessThan:
+			f.addWhere(value+" < ?", start)
+		case models.CriterionModifierBetween, models.CriterionModifierNotBetween:
+			upperStart, upperEnd := start, end
+			if c.Value2 != nil {
+				upperStart, upperEnd, err = parseDay(*c.Value2)
+				if err != nil {
+					f.setError(err)
+					return
+				}
+			}
+
+			// dates in this format compare in order
+			if upperStart < start {
+				start, upperEnd = upperStart, end
+			}
+
+			if c.Modifier == models.CriterionModifierBetween {
+				f.addWhere(fmt.Sprintf("(%s >= ? AND %[1]s < ?)", value), start, upperEnd)
+			} else {
+				f.addWhere(fmt.Sprintf("(%s IS NULL OR %s < ? OR %s >= ?)", column, value, value), start, upperEnd)
+			}
+		default:
+			f.setError(fmt.Errorf("unsupported modifier for date criterion: %s", c.Modifier))
+		}
+	}
+}
+
+func geoRadiusCriterionHandler(c *models.GeoRadiusCriterionInput, latColumn, lonColumn string) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if c != nil {
+			if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+				f.setError(fmt.Errorf("invalid coordinates: %f, %f", c.Latitude, c.Longitude))
+				return
+			}
+			if c.RadiusKm < 0 {
+				f.setError(fmt.Errorf("invalid radius: %f", c.RadiusKm))
+				return
+			}
+
+			f.addWhere(fmt.Sprintf("(%s IS NOT NULL AND %s IS NOT NULL AND distanceKm(%[1]s, %[2]s, ?, ?) <= ?)", latColumn, lonColumn), c.Latitude, c.Longitude, c.RadiusKm)
+		}
+	}
+}
+
 func boolCriterionHandler(c *bool, column string) criterionHandlerFunc {
 	return func(f *filterBuilder) {
 		if c != nil {
@@ -479,3 +574,73 @@ func (m *countCriterionHandlerBuilder) handler(criterion *models.IntCriterionInp
 		}
 	}
 }
+
+// performerAgeCriterionHandlerBuilder filters objects by the age of their
+// performers on the date of the object.
+type performerAgeCriterionHandlerBuilder struct {
+	primaryTable string
+	joinTable    string
+	primaryFK    string
+	dateColumn   string
+}
+
+func (m *performerAgeCriterionHandlerBuilder) handler(criterion *models.IntCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if criterion != nil {
+			age := fmt.Sprintf("cast(strftime('%%Y.%%m%%d', %s.%s) - strftime('%%Y.%%m%%d', performers.birthdate) as int)", m.primaryTable, m.dateColumn)
+			clause, count := getIntCriterionWhereClause(age, *criterion)
+
+			clause = fmt.Sprintf(`exists (select 1 from %[1]s
+				inner join performers on performers.id = %[1]s.performer_id
+				where %[1]s.%[2]s = %[3]s.id AND %[3]s.%[4]s IS NOT NULL AND performers.birthdate IS NOT NULL AND %[5]s)`,
+				m.joinTable, m.primaryFK, m.primaryTable, m.dateColumn, clause)
+
+			if count == 1 {
+				f.addWhere(clause, criterion.Value)
+			} else {
+				f.addWhere(clause)
+			}
+		}
+	}
+}
+
+// appearsWithCriterionHandlerBuilder filters objects by whether they have
+// performers who have appeared in a scene or gallery with the provided
+// performers.
+type appearsWithCriterionHandlerBuilder struct {
+	primaryTable string
+	joinTable    string
+	primaryFK    string
+}
+
+func (m *appearsWithCriterionHandlerBuilder) handler(criterion *models.MultiCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if criterion != nil && len(criterion.Value) > 0 {
+			in := getInBinding(len(criterion.Value))
+
+			var args []interface{}
+			for i := 0; i < 4; i++ {
+				for _, performerID := range criterion.Value {
+					args = append(args, performerID)
+				}
+			}
+
+			coPerformers := fmt.Sprintf(`select o.performer_id from %[1]s s
+				inner join %[1]s o on o.scene_id = s.scene_id
+				where s.performer_id in %[3]s and o.performer_id not in %[3]s
+				union
+				select o.performer_id from %[2]s s
+				inner join %[2]s o on o.gallery_id = s.gallery_id
+				where s.performer_id in %[3]s and o.performer_id not in %[3]s`,
+				performersScenesTable, performersGalleriesTable, in)
+
+			not := ""
+			if criterion.Modifier == models.CriterionModifierExcludes {
+				not = "not "
+			}
+
+			f.addWhere(fmt.Sprintf("%s.id %sin (select %s from %s where performer_id in (%s))",
+				m.primaryTable, not, m.primaryFK, m.joinTable, coPerformers), args...)
+		}
+	}
+}
@@ -85,21 +85,28 @@ func (qb *galleryQueryBuilder) Find(id int) (*models.Gallery, error) {
 }
 
 func (qb *galleryQueryBuilder) FindMany(ids []int) ([]*models.Gallery, error) {
-	var galleries []*models.Gallery
-	for _, id := range ids {
-		gallery, err := qb.Find(id)
-		if err != nil {
-			return nil, err
-		}
+	var galleries models.Galleries
+	if err := qb.queryByIDs(ids, &galleries); err != nil {
+		return nil, err
+	}
 
-		if gallery == nil {
+	byID := make(map[int]*models.Gallery)
+	for _, o := range galleries {
+		byID[o.ID] = o
+	}
+
+	// return in the same order as the provided ids
+	var ret []*models.Gallery
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
 			return nil, fmt.Errorf("gallery with id %d not found", id)
 		}
 
-		galleries = append(galleries, gallery)
+		ret = append(ret, o)
 	}
 
-	return galleries, nil
+	return ret, nil
 }
 
 func (qb *galleryQueryBuilder) FindByChecksum(checksum string) (*models.Gallery, error) {
@@ -213,6 +220,8 @@ func (qb *galleryQueryBuilder) makeFilter(galleryFilter *models.GalleryFilterTyp
 	query.handleCriterionFunc(galleryTagCountCriterionHandler(qb, galleryFilter.TagCount))
 	query.handleCriterionFunc(galleryPerformersCriterionHandler(qb, galleryFilter.Performers))
 	query.handleCriterionFunc(galleryPerformerCountCriterionHandler(qb, galleryFilter.PerformerCount))
+	query.handleCriterionFunc(galleryPerformerAgeCriterionHandler(galleryFilter.PerformerAge))
+	query.handleCriterionFunc(galleryAppearsWithCriterionHandler(galleryFilter.AppearsWith))
 	query.handleCriterionFunc(galleryStudioCriterionHandler(qb, galleryFilter.Studios))
 	query.handleCriterionFunc(galleryPerformerTagsCriterionHandler(qb, galleryFilter.PerformerTags))
 	query.handleCriterionFunc(galleryAverageResolutionCriterionHandler(qb, galleryFilter.AverageResolution))
@@ -253,27 +262,28 @@ func (qb *galleryQueryBuilder) makeQuery(galleryFilter *models.GalleryFilterType
 }
 
 func (qb *galleryQueryBuilder) Query(galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType) ([]*models.Gallery, int, error) {
-	query, err := qb.makeQuery(galleryFilter, findFilter)
+	idsResult, countResult, err := qb.QueryIDs(galleryFilter, findFilter)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	idsResult, countResult, err := query.executeFind()
+	galleries, err := qb.FindMany(idsResult)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	var galleries []*models.Gallery
-	for _, id := range idsResult {
-		gallery, err := qb.Find(id)
-		if err != nil {
-			return nil, 0, err
-		}
+	return galleries, countResult, nil
+}
 
-		galleries = append(galleries, gallery)
+// QueryIDs returns the ids of the galleries matching the provided filters,
+// along with the total count, without fetching the galleries themselves.
+func (qb *galleryQueryBuilder) QueryIDs(galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType) ([]int, int, error) {
+	query, err := qb.makeQuery(galleryFilter, findFilter)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return galleries, countResult, nil
+	return query.executeFind()
 }
 
 func (qb *galleryQueryBuilder) QueryCount(galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType) (int, error) {
@@ -372,6 +382,27 @@ func galleryPerformerCountCriterionHandler(qb *galleryQueryBuilder, performerCou
 	return h.handler(performerCount)
 }
 
+func galleryPerformerAgeCriterionHandler(performerAge *models.IntCriterionInput) criterionHandlerFunc {
+	h := performerAgeCriterionHandlerBuilder{
+		primaryTable: galleryTable,
+		joinTable:    performersGalleriesTable,
+		primaryFK:    galleryIDColumn,
+		dateColumn:   "date",
+	}
+
+	return h.handler(performerAge)
+}
+
+func galleryAppearsWithCriterionHandler(appearsWith *models.MultiCriterionInput) criterionHandlerFunc {
+	h := appearsWithCriterionHandlerBuilder{
+		primaryTable: galleryTable,
+		joinTable:    performersGalleriesTable,
+		primaryFK:    galleryIDColumn,
+	}
+
+	return h.handler(appearsWith)
+}
+
 func galleryImageCountCriterionHandler(qb *galleryQueryBuilder, imageCount *models.IntCriterionInput) criterionHandlerFunc {
 	h := countCriterionHandlerBuilder{
 		primaryTable: galleryTable,
@@ -542,6 +573,13 @@ func (qb *galleryQueryBuilder) UpdateImages(galleryID int, imageIDs []int) error
 	return qb.imagesRepository().replace(galleryID, imageIDs)
 }
 
+// RemoveMissingImages removes the images which no longer exist from
+// galleries, so that they are not included in the image counts. Returns the
+// number of galleries changed.
+func (qb *galleryQueryBuilder) RemoveMissingImages() (int, error) {
+	return qb.imagesRepository().destroyMissing(galleryTable, imageTable)
+}
+
 func (qb *galleryQueryBuilder) scenesRepository() *joinRepository {
 	return &joinRepository{
 		repository: repository{
@@ -3,6 +3,7 @@
 package sqlite_test
 
 import (
+	"fmt"
 	"strconv"
 	"testing"
 
@@ -63,6 +64,28 @@ func TestGalleryFindByChecksum(t *testing.T) {
 	})
 }
 
+func TestGalleryFindMany(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Gallery()
+
+		ids := []int{galleryIDs[2], galleryIDs[0], galleryIDs[1]}
+		galleries, err := qb.FindMany(ids)
+		if err != nil {
+			t.Errorf("Error finding galleries: %s", err.Error())
+		}
+
+		assert.Len(t, galleries, len(ids))
+		for i, o := range galleries {
+			assert.Equal(t, ids[i], o.ID)
+		}
+
+		_, err = qb.FindMany([]int{galleryIDs[0], 0})
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
 func TestGalleryFindByPath(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		gqb := r.Gallery()
@@ -617,6 +640,62 @@ func TestGalleryQueryPerformers(t *testing.T) {
 	})
 }
 
+func TestGalleryQueryPerformerAge(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Gallery()
+
+		// performer ages are 18 + performer index
+		ageCriterion := models.IntCriterionInput{
+			Value:    18 + performerIdxWithGallery,
+			Modifier: models.CriterionModifierEquals,
+		}
+
+		galleryFilter := models.GalleryFilterType{
+			PerformerAge: &ageCriterion,
+		}
+
+		galleries := queryGallery(t, sqb, &galleryFilter, nil)
+
+		assert.Len(t, galleries, 1)
+		assert.Equal(t, galleryIDs[galleryIdxWithPerformer], galleries[0].ID)
+
+		return nil
+	})
+}
+
+func TestGalleryQueryAppearsWith(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Gallery()
+		appearsWithCriterion := models.MultiCriterionInput{
+			Value: []string{
+				strconv.Itoa(performerIDs[performerIdx1WithGallery]),
+			},
+			Modifier: models.CriterionModifierIncludes,
+		}
+
+		galleryFilter := models.GalleryFilterType{
+			AppearsWith: &appearsWithCriterion,
+		}
+
+		galleries := queryGallery(t, sqb, &galleryFilter, nil)
+
+		assert.Len(t, galleries, 1)
+		assert.Equal(t, galleryIDs[galleryIdxWithTwoPerformers], galleries[0].ID)
+
+		appearsWithCriterion.Modifier = models.CriterionModifierExcludes
+
+		q := getGalleryStringValue(galleryIdxWithTwoPerformers, titleField)
+		findFilter := models.FindFilterType{
+			Q: &q,
+		}
+
+		galleries = queryGallery(t, sqb, &galleryFilter, &findFilter)
+		assert.Len(t, galleries, 0)
+
+		return nil
+	})
+}
+
 func TestGalleryQueryTags(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Gallery()
@@ -920,3 +999,46 @@ func verifyGalleriesImageCount(t *testing.T, imageCountCriterion models.IntCrite
 // TODO Query
 // TODO Update
 // TODO Destroy
+
+func TestGalleryRemoveMissingImages(t *testing.T) {
+	const missingID = 999999
+	galleryID := galleryIDs[galleryIdxWithImage]
+
+	if err := execWithoutForeignKeys(
+		fmt.Sprintf("INSERT INTO galleries_images (gallery_id, image_id) VALUES (%d, %d)", galleryID, missingID),
+		fmt.Sprintf("INSERT INTO galleries_images (gallery_id, image_id) VALUES (%d, %d)", missingID, imageIDs[imageIdxWithGallery]),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := withTxn(func(r models.Repository) error {
+		iqb := r.Image()
+		count, err := iqb.CountByGalleryID(galleryID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 2, count)
+
+		updated, err := r.Gallery().RemoveMissingImages()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, updated)
+
+		count, err = iqb.CountByGalleryID(galleryID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, count)
+
+		count, err = iqb.CountByGalleryID(missingID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 0, count)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
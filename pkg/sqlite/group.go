@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const groupTable = "groups"
+const groupsScenesTable = "groups_scenes"
+const groupIDColumn = "group_id"
+
+// groupSubScenesQuery selects the scenes of each group and of all groups
+// nested in it, as scene_id and group_id.
+const groupSubScenesQuery = `WITH RECURSIVE group_ancestors(group_id, ancestor_id) AS (
+	SELECT id, id FROM groups
+	UNION
+	SELECT groups.id, group_ancestors.ancestor_id FROM groups
+	INNER JOIN group_ancestors ON groups.parent_id = group_ancestors.group_id
+)
+SELECT groups_scenes.scene_id, group_ancestors.ancestor_id AS group_id FROM groups_scenes
+INNER JOIN group_ancestors ON group_ancestors.group_id = groups_scenes.group_id`
+
+type groupQueryBuilder struct {
+	repository
+}
+
+func NewGroupReaderWriter(tx dbi) *groupQueryBuilder {
+	return &groupQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: groupTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *groupQueryBuilder) Create(newObject models.Group) (*models.Group, error) {
+	var ret models.Group
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *groupQueryBuilder) Update(updatedObject models.Group) (*models.Group, error) {
+	const partial = false
+	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	return qb.Find(updatedObject.ID)
+}
+
+func (qb *groupQueryBuilder) Destroy(id int) error {
+	// the scenes of the group are deleted by a delete cascade, and the
+	// groups nested in it are moved to the top level
+	return qb.destroyExisting([]int{id})
+}
+
+func (qb *groupQueryBuilder) Find(id int) (*models.Group, error) {
+	var ret models.Group
+	if err := qb.get(id, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *groupQueryBuilder) FindMany(ids []int) ([]*models.Group, error) {
+	var groups models.Groups
+	if err := qb.queryByIDs(ids, &groups); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Group)
+	for _, o := range groups {
+		byID[o.ID] = o
+	}
+
+	// return in the same order as the provided ids
+	var ret []*models.Group
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
+			return nil, fmt.Errorf("group with id %d not found", id)
+		}
+
+		ret = append(ret, o)
+	}
+
+	return ret, nil
+}
+
+func (qb *groupQueryBuilder) FindChildren(id int) ([]*models.Group, error) {
+	query := selectAll(groupTable) + "WHERE groups.parent_id = ? ORDER BY groups.name ASC, groups.id ASC"
+	return qb.queryGroups(query, []interface{}{id})
+}
+
+func (qb *groupQueryBuilder) FindBySceneID(sceneID int) ([]*models.Group, error) {
+	query := selectAll(groupTable) + `
+		INNER JOIN groups_scenes as scenes_join on scenes_join.group_id = groups.id
+		WHERE scenes_join.scene_id = ?
+		ORDER BY groups.name ASC, groups.id ASC
+	`
+	return qb.queryGroups(query, []interface{}{sceneID})
+}
+
+func (qb *groupQueryBuilder) All() ([]*models.Group, error) {
+	return qb.queryGroups(selectAll(groupTable)+"ORDER BY groups.name ASC, groups.id ASC", nil)
+}
+
+func (qb *groupQueryBuilder) Count() (int, error) {
+	return qb.runCountQuery(qb.buildCountQuery("SELECT groups.id FROM groups"), nil)
+}
+
+func (qb *groupQueryBuilder) queryGroups(query string, args []interface{}) ([]*models.Group, error) {
+	var ret models.Groups
+	if err := qb.query(query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.Group(ret), nil
+}
+
+func (qb *groupQueryBuilder) scenesRepository() *repository {
+	return &repository{
+		tx:        qb.tx,
+		tableName: groupsScenesTable,
+		idColumn:  groupIDColumn,
+	}
+}
+
+func (qb *groupQueryBuilder) GetSceneIDs(groupID int) ([]int, error) {
+	query := `SELECT scene_id as id FROM groups_scenes WHERE group_id = ? ORDER BY scene_index ASC`
+	return qb.runIdsQuery(query, []interface{}{groupID})
+}
+
+func (qb *groupQueryBuilder) UpdateScenes(groupID int, sceneIDs []int) error {
+	// destroy existing joins
+	r := qb.scenesRepository()
+	if err := r.destroy([]int{groupID}); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, scene_index) VALUES (?, ?, ?)", groupsScenesTable, groupIDColumn, sceneIDColumn)
+	for i, sceneID := range sceneIDs {
+		if _, err := r.tx.Exec(stmt, groupID, sceneID, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupCRUD(t *testing.T) {
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.Group()
+		parent, err := qb.Create(models.Group{
+			Name: "TestGroupCRUD parent",
+		})
+		if err != nil {
+			return err
+		}
+
+		child, err := qb.Create(models.Group{
+			Name:     "TestGroupCRUD child",
+			ParentID: models.NullInt64(int64(parent.ID)),
+		})
+		if err != nil {
+			return err
+		}
+
+		child.Description = models.NullString("description")
+		updated, err := qb.Update(*child)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "description", updated.Description.String)
+
+		children, err := qb.FindChildren(parent.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, []*models.Group{updated}, children)
+
+		ids := []int{sceneIDs[sceneIdxWithMovie], sceneIDs[sceneIdxWithGallery]}
+		if err := qb.UpdateScenes(child.ID, ids); err != nil {
+			return err
+		}
+
+		// scenes are returned in the order of the group
+		got, err := qb.GetSceneIDs(child.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, ids, got)
+
+		groups, err := qb.FindBySceneID(ids[1])
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, []*models.Group{updated}, groups)
+
+		found, err := qb.FindMany([]int{child.ID, parent.ID})
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, []*models.Group{updated, parent}, found)
+
+		// nested groups are moved to the top level when their parent is
+		// destroyed
+		if err := qb.Destroy(parent.ID); err != nil {
+			return err
+		}
+
+		child, err = qb.Find(child.ID)
+		if err != nil {
+			return err
+		}
+		assert.False(t, child.ParentID.Valid)
+
+		if err := qb.Destroy(child.ID); err != nil {
+			return err
+		}
+
+		// the scenes of the group are removed with it
+		got, err = qb.GetSceneIDs(child.ID)
+		if err != nil {
+			return err
+		}
+		assert.Empty(t, got)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneQueryGroups(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Group()
+		series, err := qb.Create(models.Group{Name: "TestSceneQueryGroups series"})
+		if err != nil {
+			return err
+		}
+		season, err := qb.Create(models.Group{
+			Name:     "TestSceneQueryGroups season",
+			ParentID: models.NullInt64(int64(series.ID)),
+		})
+		if err != nil {
+			return err
+		}
+		other, err := qb.Create(models.Group{Name: "TestSceneQueryGroups other"})
+		if err != nil {
+			return err
+		}
+
+		seriesScene := sceneIDs[sceneIdxWithMovie]
+		seasonScene := sceneIDs[sceneIdxWithGallery]
+		sharedScene := sceneIDs[sceneIdx1WithPerformer]
+
+		if err := qb.UpdateScenes(series.ID, []int{seriesScene}); err != nil {
+			return err
+		}
+		if err := qb.UpdateScenes(season.ID, []int{seasonScene, sharedScene}); err != nil {
+			return err
+		}
+		if err := qb.UpdateScenes(other.ID, []int{sharedScene}); err != nil {
+			return err
+		}
+
+		defer func() {
+			for _, id := range []int{series.ID, season.ID, other.ID} {
+				if err := qb.Destroy(id); err != nil {
+					t.Error(err.Error())
+				}
+			}
+		}()
+
+		sqb := r.Scene()
+		perPage := -1
+		queryIDs := func(modifier models.CriterionModifier, groupIDs ...int) []int {
+			var values []string
+			for _, id := range groupIDs {
+				values = append(values, strconv.Itoa(id))
+			}
+
+			scenes := queryScene(t, sqb, &models.SceneFilterType{
+				Groups: &models.MultiCriterionInput{
+					Value:    values,
+					Modifier: modifier,
+				},
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+
+			var ret []int
+			for _, s := range scenes {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		// the scenes of nested groups are included
+		assert.ElementsMatch(t, []int{seriesScene, seasonScene, sharedScene}, queryIDs(models.CriterionModifierIncludes, series.ID))
+		assert.ElementsMatch(t, []int{seasonScene, sharedScene}, queryIDs(models.CriterionModifierIncludes, season.ID))
+		assert.ElementsMatch(t, []int{sharedScene}, queryIDs(models.CriterionModifierIncludesAll, series.ID, other.ID))
+
+		excluded := queryIDs(models.CriterionModifierExcludes, series.ID)
+		assert.NotContains(t, excluded, seriesScene)
+		assert.NotContains(t, excluded, seasonScene)
+		assert.NotContains(t, excluded, sharedScene)
+		assert.Contains(t, excluded, sceneIDs[sceneIdxInteractive])
+
+		return nil
+	})
+}
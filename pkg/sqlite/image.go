@@ -74,6 +74,8 @@ func (qb *imageQueryBuilder) IncrementOCounter(id int) (int, error) {
 		return 0, err
 	}
 
+	qb.recordChange(models.EntityChangeTypeUpdated, id)
+
 	image, err := qb.find(id)
 	if err != nil {
 		return 0, err
@@ -91,6 +93,8 @@ func (qb *imageQueryBuilder) DecrementOCounter(id int) (int, error) {
 		return 0, err
 	}
 
+	qb.recordChange(models.EntityChangeTypeUpdated, id)
+
 	image, err := qb.find(id)
 	if err != nil {
 		return 0, err
@@ -108,6 +112,8 @@ func (qb *imageQueryBuilder) ResetOCounter(id int) (int, error) {
 		return 0, err
 	}
 
+	qb.recordChange(models.EntityChangeTypeUpdated, id)
+
 	image, err := qb.find(id)
 	if err != nil {
 		return 0, err
@@ -125,21 +131,28 @@ func (qb *imageQueryBuilder) Find(id int) (*models.Image, error) {
 }
 
 func (qb *imageQueryBuilder) FindMany(ids []int) ([]*models.Image, error) {
-	var images []*models.Image
-	for _, id := range ids {
-		image, err := qb.Find(id)
-		if err != nil {
-			return nil, err
-		}
+	var images models.Images
+	if err := qb.queryByIDs(ids, &images); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Image)
+	for _, o := range images {
+		byID[o.ID] = o
+	}
 
-		if image == nil {
+	// return in the same order as the provided ids
+	var ret []*models.Image
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
 			return nil, fmt.Errorf("image with id %d not found", id)
 		}
 
-		images = append(images, image)
+		ret = append(ret, o)
 	}
 
-	return images, nil
+	return ret, nil
 }
 
 func (qb *imageQueryBuilder) find(id int) (*models.Image, error) {
@@ -232,6 +245,11 @@ func (qb *imageQueryBuilder) makeFilter(imageFilter *models.ImageFilterType) *fi
 	}
 
 	query.handleCriterionFunc(stringCriterionHandler(imageFilter.Path, "images.path"))
+	query.handleCriterionFunc(stringCriterionHandler(imageFilter.Location, "images.location"))
+	query.handleCriterionFunc(geoRadiusCriterionHandler(imageFilter.Nearby, "images.latitude", "images.longitude"))
+	query.handleCriterionFunc(dateCriterionHandler(imageFilter.TakenAt, "images.taken_at"))
+	query.handleCriterionFunc(stringCriterionHandler(imageFilter.CameraMake, "images.camera_make"))
+	query.handleCriterionFunc(stringCriterionHandler(imageFilter.CameraModel, "images.camera_model"))
 	query.handleCriterionFunc(intCriterionHandler(imageFilter.Rating, "images.rating"))
 	query.handleCriterionFunc(intCriterionHandler(imageFilter.OCounter, "images.o_counter"))
 	query.handleCriterionFunc(boolCriterionHandler(imageFilter.Organized, "images.organized"))
@@ -281,27 +299,28 @@ func (qb *imageQueryBuilder) makeQuery(imageFilter *models.ImageFilterType, find
 }
 
 func (qb *imageQueryBuilder) Query(imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) ([]*models.Image, int, error) {
-	query, err := qb.makeQuery(imageFilter, findFilter)
+	idsResult, countResult, err := qb.QueryIDs(imageFilter, findFilter)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	idsResult, countResult, err := query.executeFind()
+	images, err := qb.FindMany(idsResult)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	var images []*models.Image
-	for _, id := range idsResult {
-		image, err := qb.Find(id)
-		if err != nil {
-			return nil, 0, err
-		}
+	return images, countResult, nil
+}
 
-		images = append(images, image)
+// QueryIDs returns the ids of the images matching the provided filters,
+// along with the total count, without fetching the images themselves.
+func (qb *imageQueryBuilder) QueryIDs(imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) ([]int, int, error) {
+	query, err := qb.makeQuery(imageFilter, findFilter)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return images, countResult, nil
+	return query.executeFind()
 }
 
 func (qb *imageQueryBuilder) QueryCount(imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) (int, error) {
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -40,6 +41,28 @@ func TestImageFind(t *testing.T) {
 	})
 }
 
+func TestImageFindMany(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Image()
+
+		ids := []int{imageIDs[2], imageIDs[0], imageIDs[1]}
+		images, err := qb.FindMany(ids)
+		if err != nil {
+			t.Errorf("Error finding images: %s", err.Error())
+		}
+
+		assert.Len(t, images, len(ids))
+		for i, o := range images {
+			assert.Equal(t, ids[i], o.ID)
+		}
+
+		_, err = qb.FindMany([]int{imageIDs[0], 0})
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
 func TestImageFindByPath(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Image()
@@ -292,6 +315,147 @@ func TestImageIllegalQuery(t *testing.T) {
 	})
 }
 
+func TestImageQueryNearby(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Image()
+
+		// images with index%3 == 2 are located at 20, 20
+		imageFilter := models.ImageFilterType{
+			Nearby: &models.GeoRadiusCriterionInput{
+				Latitude:  20,
+				Longitude: 20,
+				RadiusKm:  1,
+			},
+		}
+
+		images := queryImages(t, sqb, &imageFilter, nil)
+		assert.Greater(t, len(images), 0)
+
+		for _, image := range images {
+			assert.Equal(t, float64(20), image.Latitude.Float64)
+			assert.Equal(t, float64(20), image.Longitude.Float64)
+		}
+
+		return nil
+	})
+}
+
+func TestImageQueryTakenAt(t *testing.T) {
+	day := "2001-02-03"
+	later := "2005-06-07"
+
+	isDay := func(image *models.Image, date string) bool {
+		return image.TakenAt.Valid && image.TakenAt.Timestamp.In(time.Local).Format("2006-01-02") == date
+	}
+
+	tests := []struct {
+		name      string
+		criterion models.DateCriterionInput
+		want      func(image *models.Image) bool
+	}{
+		{
+			"equals",
+			models.DateCriterionInput{Value: day, Modifier: models.CriterionModifierEquals},
+			func(image *models.Image) bool { return isDay(image, day) },
+		},
+		{
+			"not equals",
+			models.DateCriterionInput{Value: day, Modifier: models.CriterionModifierNotEquals},
+			func(image *models.Image) bool { return !isDay(image, day) },
+		},
+		{
+			"greater than",
+			models.DateCriterionInput{Value: day, Modifier: models.CriterionModifierGreaterThan},
+			func(image *models.Image) bool { return isDay(image, later) },
+		},
+		{
+			"less than",
+			models.DateCriterionInput{Value: later, Modifier: models.CriterionModifierLessThan},
+			func(image *models.Image) bool { return isDay(image, day) },
+		},
+		{
+			"between",
+			models.DateCriterionInput{Value: "2005-12-31", Value2: &day, Modifier: models.CriterionModifierBetween},
+			func(image *models.Image) bool { return image.TakenAt.Valid },
+		},
+		{
+			"not between",
+			models.DateCriterionInput{Value: "2001-01-01", Value2: &day, Modifier: models.CriterionModifierNotBetween},
+			func(image *models.Image) bool { return !isDay(image, day) },
+		},
+		{
+			"is null",
+			models.DateCriterionInput{Modifier: models.CriterionModifierIsNull},
+			func(image *models.Image) bool { return !image.TakenAt.Valid },
+		},
+	}
+
+	withTxn(func(r models.Repository) error {
+		sqb := r.Image()
+
+		for _, tt := range tests {
+			criterion := tt.criterion
+			imageFilter := models.ImageFilterType{
+				TakenAt: &criterion,
+			}
+
+			images := queryImages(t, sqb, &imageFilter, nil)
+			assert.Greater(t, len(images), 0, tt.name)
+
+			for _, image := range images {
+				assert.True(t, tt.want(image), "%s: %v", tt.name, image.TakenAt)
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestImageQueryTakenAtInvalid(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Image()
+
+		imageFilter := models.ImageFilterType{
+			TakenAt: &models.DateCriterionInput{
+				Value:    "03/02/2001",
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+
+		_, _, err := sqb.Query(&imageFilter, nil)
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
+func TestImageQueryCamera(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Image()
+
+		const imageIdx = 1
+		cameraModel := getPrefixedStringValue("image", imageIdx, cameraModelField)
+		imageFilter := models.ImageFilterType{
+			CameraMake: &models.StringCriterionInput{
+				Value:    "image_",
+				Modifier: models.CriterionModifierIncludes,
+			},
+			CameraModel: &models.StringCriterionInput{
+				Value:    cameraModel,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+
+		images := queryImages(t, sqb, &imageFilter, nil)
+		if assert.Len(t, images, 1) {
+			assert.Equal(t, imageIDs[imageIdx], images[0].ID)
+			assert.Equal(t, cameraModel, images[0].CameraModel.String)
+		}
+
+		return nil
+	})
+}
+
 func TestImageQueryRating(t *testing.T) {
 	const rating = 3
 	ratingCriterion := models.IntCriterionInput{
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const jobHistoryTable = "job_history"
+
+type jobHistoryQueryBuilder struct {
+	repository
+}
+
+func NewJobHistoryReaderWriter(tx dbi) *jobHistoryQueryBuilder {
+	return &jobHistoryQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: jobHistoryTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *jobHistoryQueryBuilder) Create(newObject models.JobHistoryEntry) (*models.JobHistoryEntry, error) {
+	var ret models.JobHistoryEntry
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *jobHistoryQueryBuilder) Trim(keep int) error {
+	_, err := qb.tx.Exec(
+		"DELETE FROM "+jobHistoryTable+" WHERE id NOT IN (SELECT id FROM "+jobHistoryTable+" ORDER BY finished_at DESC, id DESC LIMIT ?)",
+		keep,
+	)
+	return err
+}
+
+func (qb *jobHistoryQueryBuilder) Recent(limit int) ([]*models.JobHistoryEntry, error) {
+	var ret models.JobHistoryEntries
+	query := selectAll(jobHistoryTable) + "ORDER BY finished_at DESC, id DESC LIMIT ?"
+	if err := qb.query(query, []interface{}{limit}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.JobHistoryEntry(ret), nil
+}
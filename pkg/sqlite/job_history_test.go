@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobHistory(t *testing.T) {
+	// timestamps are stored to the second
+	now := time.Now().Truncate(time.Second)
+
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.JobHistory()
+
+		var ids []int
+		for i := 0; i < 3; i++ {
+			finishedAt := now.Add(time.Duration(i) * time.Minute)
+			created, err := qb.Create(models.JobHistoryEntry{
+				Type:       "Scan",
+				Priority:   models.JobPriorityNormal,
+				State:      models.JobStateFailed,
+				Error:      sql.NullString{String: "failed", Valid: true},
+				AddedAt:    models.SQLiteTimestamp{Timestamp: now},
+				StartedAt:  models.NullSQLiteTimestamp{Timestamp: now, Valid: true},
+				FinishedAt: models.SQLiteTimestamp{Timestamp: finishedAt},
+			})
+			if err != nil {
+				return err
+			}
+			ids = append(ids, created.ID)
+		}
+
+		recent, err := qb.Recent(2)
+		if err != nil {
+			return err
+		}
+		if assert.Len(t, recent, 2) {
+			// most recently finished first
+			assert.Equal(t, ids[2], recent[0].ID)
+			assert.Equal(t, ids[1], recent[1].ID)
+			assert.Equal(t, models.JobStateFailed, recent[0].State)
+			assert.Equal(t, "failed", recent[0].Error.String)
+		}
+
+		if err := qb.Trim(1); err != nil {
+			return err
+		}
+
+		recent, err = qb.Recent(10)
+		if err != nil {
+			return err
+		}
+		if assert.Len(t, recent, 1) {
+			assert.Equal(t, ids[2], recent[0].ID)
+		}
+
+		return qb.Trim(0)
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
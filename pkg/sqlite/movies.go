@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -51,6 +52,11 @@ func (qb *movieQueryBuilder) UpdateFull(updatedObject models.Movie) (*models.Mov
 }
 
 func (qb *movieQueryBuilder) Destroy(id int) error {
+	// delete the image blobs if no longer used
+	if err := qb.DestroyImages(id); err != nil {
+		return err
+	}
+
 	return qb.destroyExisting([]int{id})
 }
 
@@ -148,16 +154,18 @@ func (qb *movieQueryBuilder) Query(movieFilter *models.MovieFilterType, findFilt
 		query.addHaving(havingClause)
 	}
 
+	handleMoviePerformersCriterion(&query, movieFilter.Performers)
+
 	if isMissingFilter := movieFilter.IsMissing; isMissingFilter != nil && *isMissingFilter != "" {
 		switch *isMissingFilter {
 		case "front_image":
 			query.body += `left join movies_images on movies_images.movie_id = movies.id
 			`
-			query.addWhere("movies_images.front_image IS NULL")
+			query.addWhere("movies_images.front_image_blob IS NULL")
 		case "back_image":
 			query.body += `left join movies_images on movies_images.movie_id = movies.id
 			`
-			query.addWhere("movies_images.back_image IS NULL")
+			query.addWhere("movies_images.back_image_blob IS NULL")
 		case "scenes":
 			query.body += `left join movies_scenes on movies_scenes.movie_id = movies.id
 			`
@@ -188,6 +196,32 @@ func (qb *movieQueryBuilder) Query(movieFilter *models.MovieFilterType, findFilt
 	return movies, countResult, nil
 }
 
+func handleMoviePerformersCriterion(query *queryBuilder, performersFilter *models.MultiCriterionInput) {
+	if performersFilter != nil && len(performersFilter.Value) > 0 {
+		for _, performerID := range performersFilter.Value {
+			query.addArg(performerID)
+		}
+
+		query.body += `left join performers_scenes as performers_join on performers_join.scene_id = scenes.id
+		`
+
+		if performersFilter.Modifier == models.CriterionModifierIncludes {
+			// includes any of the provided ids
+			query.addWhere("performers_join.performer_id IN " + getInBinding(len(performersFilter.Value)))
+		} else if performersFilter.Modifier == models.CriterionModifierIncludesAll {
+			// includes all of the provided ids
+			query.addWhere("performers_join.performer_id IN " + getInBinding(len(performersFilter.Value)))
+			query.addHaving(fmt.Sprintf("count(distinct performers_join.performer_id) IS %d", len(performersFilter.Value)))
+		} else if performersFilter.Modifier == models.CriterionModifierExcludes {
+			query.addWhere(fmt.Sprintf(`not exists
+				(select movies_scenes.movie_id from movies_scenes
+					left join performers_scenes on performers_scenes.scene_id = movies_scenes.scene_id where
+					movies_scenes.movie_id = movies.id AND
+					performers_scenes.performer_id in %s)`, getInBinding(len(performersFilter.Value))))
+		}
+	}
+}
+
 func (qb *movieQueryBuilder) getMovieSort(findFilter *models.FindFilterType) string {
 	var sort string
 	var direction string
@@ -199,14 +233,25 @@ func (qb *movieQueryBuilder) getMovieSort(findFilter *models.FindFilterType) str
 		direction = findFilter.GetDirection()
 	}
 
-	// #943 - override name sorting to use natural sort
-	if sort == "name" {
+	switch sort {
+	case "name":
+		// #943 - override name sorting to use natural sort
 		return " ORDER BY " + getColumn("movies", sort) + " COLLATE NATURAL_CS " + direction
+	case "performer_count":
+		return getMoviePerformerCountSort(direction)
 	}
 
 	return getSort(sort, direction, "movies")
 }
 
+// getMoviePerformerCountSort sorts by the number of distinct performers
+// appearing in the scenes of the movie.
+func getMoviePerformerCountSort(direction string) string {
+	return fmt.Sprintf(` ORDER BY (SELECT COUNT(DISTINCT performers_scenes.performer_id) FROM movies_scenes
+	INNER JOIN performers_scenes ON performers_scenes.scene_id = movies_scenes.scene_id
+	WHERE movies_scenes.movie_id = movies.id) %s`, getSortDirection(direction))
+}
+
 func (qb *movieQueryBuilder) queryMovie(query string, args []interface{}) (*models.Movie, error) {
 	results, err := qb.queryMovies(query, args)
 	if err != nil || len(results) < 1 {
@@ -224,37 +269,84 @@ func (qb *movieQueryBuilder) queryMovies(query string, args []interface{}) ([]*m
 	return []*models.Movie(ret), nil
 }
 
+// imageChecksums returns the checksums of the front and back image blobs
+// of the movie.
+func (qb *movieQueryBuilder) imageChecksums(movieID int) ([]string, error) {
+	var ret []string
+	query := `SELECT front_image_blob, back_image_blob from movies_images WHERE movie_id = ?`
+	err := qb.queryFunc(query, []interface{}{movieID}, func(rows *sqlx.Rows) error {
+		var front, back sql.NullString
+		if err := rows.Scan(&front, &back); err != nil {
+			return err
+		}
+
+		ret = append(ret, front.String, back.String)
+		return nil
+	})
+
+	return ret, err
+}
+
 func (qb *movieQueryBuilder) UpdateImages(movieID int, frontImage []byte, backImage []byte) error {
+	old, err := qb.imageChecksums(movieID)
+	if err != nil {
+		return err
+	}
+
 	// Delete the existing cover and then create new
-	if err := qb.DestroyImages(movieID); err != nil {
+	if _, err := qb.tx.Exec("DELETE FROM movies_images WHERE movie_id = ?", movieID); err != nil {
+		return err
+	}
+
+	store := newBlobStore(qb.tx)
+	front, err := store.write(frontImage)
+	if err != nil {
 		return err
 	}
 
-	_, err := qb.tx.Exec(
-		`INSERT INTO movies_images (movie_id, front_image, back_image) VALUES (?, ?, ?)`,
+	var back sql.NullString
+	if len(backImage) > 0 {
+		back.String, err = store.write(backImage)
+		if err != nil {
+			return err
+		}
+		back.Valid = true
+	}
+
+	_, err = qb.tx.Exec(
+		`INSERT INTO movies_images (movie_id, front_image_blob, back_image_blob) VALUES (?, ?, ?)`,
 		movieID,
-		frontImage,
-		backImage,
+		front,
+		back,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	qb.recordChange(models.EntityChangeTypeUpdated, movieID)
+	return store.deleteUnreferenced(old...)
 }
 
 func (qb *movieQueryBuilder) DestroyImages(movieID int) error {
-	// Delete the existing joins
-	_, err := qb.tx.Exec("DELETE FROM movies_images WHERE movie_id = ?", movieID)
+	old, err := qb.imageChecksums(movieID)
 	if err != nil {
 		return err
 	}
-	return err
+
+	// Delete the existing joins
+	if _, err := qb.tx.Exec("DELETE FROM movies_images WHERE movie_id = ?", movieID); err != nil {
+		return err
+	}
+
+	return newBlobStore(qb.tx).deleteUnreferenced(old...)
 }
 
 func (qb *movieQueryBuilder) GetFrontImage(movieID int) ([]byte, error) {
-	query := `SELECT front_image from movies_images WHERE movie_id = ?`
-	return getImage(qb.tx, query, movieID)
+	query := `SELECT movies_images.front_image_blob, blobs.blob from movies_images LEFT JOIN blobs ON blobs.checksum = movies_images.front_image_blob WHERE movie_id = ?`
+	return newBlobStore(qb.tx).get(query, movieID)
 }
 
 func (qb *movieQueryBuilder) GetBackImage(movieID int) ([]byte, error) {
-	query := `SELECT back_image from movies_images WHERE movie_id = ?`
-	return getImage(qb.tx, query, movieID)
+	query := `SELECT movies_images.back_image_blob, blobs.blob from movies_images LEFT JOIN blobs ON blobs.checksum = movies_images.back_image_blob WHERE movie_id = ?`
+	return newBlobStore(qb.tx).get(query, movieID)
 }
@@ -119,6 +119,72 @@ func TestMovieQueryStudio(t *testing.T) {
 	})
 }
 
+func TestMovieQueryPerformers(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		mqb := r.Movie()
+		performerCriterion := models.MultiCriterionInput{
+			Value: []string{
+				strconv.Itoa(performerIDs[performerIdx1WithScene]),
+				strconv.Itoa(performerIDs[performerIdx2WithScene]),
+			},
+			Modifier: models.CriterionModifierIncludesAll,
+		}
+
+		movieFilter := models.MovieFilterType{
+			Performers: &performerCriterion,
+		}
+
+		movies, _, err := mqb.Query(&movieFilter, nil)
+		if err != nil {
+			t.Errorf("Error querying movie: %s", err.Error())
+		}
+
+		assert.Len(t, movies, 1)
+		assert.Equal(t, movieIDs[movieIdxWithPerformer], movies[0].ID)
+
+		performerCriterion = models.MultiCriterionInput{
+			Value: []string{
+				strconv.Itoa(performerIDs[performerIdx1WithScene]),
+			},
+			Modifier: models.CriterionModifierExcludes,
+		}
+
+		q := getMovieStringValue(movieIdxWithPerformer, titleField)
+		findFilter := models.FindFilterType{
+			Q: &q,
+		}
+
+		movies, _, err = mqb.Query(&movieFilter, &findFilter)
+		if err != nil {
+			t.Errorf("Error querying movie: %s", err.Error())
+		}
+		assert.Len(t, movies, 0)
+
+		return nil
+	})
+}
+
+func TestMovieQuerySortPerformerCount(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sort := "performer_count"
+		direction := models.SortDirectionEnumDesc
+		findFilter := models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		}
+
+		movies, _, err := r.Movie().Query(nil, &findFilter)
+		if err != nil {
+			t.Errorf("Error querying movie: %s", err.Error())
+		}
+
+		assert.Greater(t, len(movies), 0)
+		assert.Equal(t, movieIDs[movieIdxWithPerformer], movies[0].ID)
+
+		return nil
+	})
+}
+
 func TestMovieQueryURL(t *testing.T) {
 	const sceneIdx = 1
 	movieURL := getMovieStringValue(sceneIdx, urlField)
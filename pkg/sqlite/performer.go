@@ -7,11 +7,13 @@ import (
 	"strings"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 const performerTable = "performers"
 const performerIDColumn = "performer_id"
 const performersTagsTable = "performers_tags"
+const performersRelationshipsTable = "performers_relationships"
 
 var countPerformersForTagQuery = `
 SELECT tag_id AS id FROM performers_tags
@@ -34,6 +36,8 @@ func NewPerformerReaderWriter(tx dbi) *performerQueryBuilder {
 }
 
 func (qb *performerQueryBuilder) Create(newObject models.Performer) (*models.Performer, error) {
+	newObject.Bust, newObject.Waist, newObject.Hips = parseMeasurements(newObject.Measurements)
+
 	var ret models.Performer
 	if err := qb.insertObject(newObject, &ret); err != nil {
 		return nil, err
@@ -43,6 +47,13 @@ func (qb *performerQueryBuilder) Create(newObject models.Performer) (*models.Per
 }
 
 func (qb *performerQueryBuilder) Update(updatedObject models.PerformerPartial) (*models.Performer, error) {
+	if updatedObject.Measurements != nil {
+		bust, waist, hips := parseMeasurements(*updatedObject.Measurements)
+		updatedObject.Bust = &bust
+		updatedObject.Waist = &waist
+		updatedObject.Hips = &hips
+	}
+
 	const partial = true
 	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
 		return nil, err
@@ -57,6 +68,8 @@ func (qb *performerQueryBuilder) Update(updatedObject models.PerformerPartial) (
 }
 
 func (qb *performerQueryBuilder) UpdateFull(updatedObject models.Performer) (*models.Performer, error) {
+	updatedObject.Bust, updatedObject.Waist, updatedObject.Hips = parseMeasurements(updatedObject.Measurements)
+
 	const partial = false
 	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
 		return nil, err
@@ -70,6 +83,24 @@ func (qb *performerQueryBuilder) UpdateFull(updatedObject models.Performer) (*mo
 	return &ret, nil
 }
 
+// parseMeasurements returns the bust, waist and hips measurements stored
+// alongside the measurements string for filtering. They are null if the
+// string cannot be parsed.
+func parseMeasurements(measurements sql.NullString) (bust, waist, hips sql.NullInt64) {
+	if !measurements.Valid {
+		return
+	}
+
+	b, w, h, err := utils.ParseMeasurements(measurements.String)
+	if err != nil {
+		return
+	}
+
+	return sql.NullInt64{Int64: int64(b), Valid: true},
+		sql.NullInt64{Int64: int64(w), Valid: true},
+		sql.NullInt64{Int64: int64(h), Valid: true}
+}
+
 func (qb *performerQueryBuilder) Destroy(id int) error {
 	// TODO - add on delete cascade to performers_scenes
 	_, err := qb.tx.Exec("DELETE FROM performers_scenes WHERE performer_id = ?", id)
@@ -77,6 +108,11 @@ func (qb *performerQueryBuilder) Destroy(id int) error {
 		return err
 	}
 
+	// delete the image blob if no longer used
+	if err := qb.DestroyImage(id); err != nil {
+		return err
+	}
+
 	return qb.destroyExisting([]int{id})
 }
 
@@ -92,21 +128,28 @@ func (qb *performerQueryBuilder) Find(id int) (*models.Performer, error) {
 }
 
 func (qb *performerQueryBuilder) FindMany(ids []int) ([]*models.Performer, error) {
-	var performers []*models.Performer
-	for _, id := range ids {
-		performer, err := qb.Find(id)
-		if err != nil {
-			return nil, err
-		}
+	var performers models.Performers
+	if err := qb.queryByIDs(ids, &performers); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Performer)
+	for _, o := range performers {
+		byID[o.ID] = o
+	}
 
-		if performer == nil {
+	// return in the same order as the provided ids
+	var ret []*models.Performer
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
 			return nil, fmt.Errorf("performer with id %d not found", id)
 		}
 
-		performers = append(performers, performer)
+		ret = append(ret, o)
 	}
 
-	return performers, nil
+	return ret, nil
 }
 
 func (qb *performerQueryBuilder) FindBySceneID(sceneID int) ([]*models.Performer, error) {
@@ -169,6 +212,11 @@ func (qb *performerQueryBuilder) Count() (int, error) {
 	return qb.runCountQuery(qb.buildCountQuery("SELECT performers.id FROM performers"), nil)
 }
 
+func (qb *performerQueryBuilder) SceneStats(limit int) ([]*models.StatsAggregate, error) {
+	return qb.querySceneStats(`INNER JOIN performers_scenes ON performers_scenes.performer_id = performers.id
+INNER JOIN scenes ON scenes.id = performers_scenes.scene_id`, limit)
+}
+
 func (qb *performerQueryBuilder) All() ([]*models.Performer, error) {
 	return qb.queryPerformers(selectAll("performers")+qb.getPerformerSort(nil), nil)
 }
@@ -186,8 +234,16 @@ func (qb *performerQueryBuilder) QueryForAutoTag(words []string) ([]*models.Perf
 		args = append(args, "%"+w+"%")
 		whereClauses = append(whereClauses, "aliases like ?")
 		args = append(args, "%"+w+"%")
+		// names may be joined in scene-release style paths
+		whereClauses = append(whereClauses, "replace(name, ' ', '') like ?")
+		args = append(args, "%"+w+"%")
+		whereClauses = append(whereClauses, "replace(aliases, ' ', '') like ?")
+		args = append(args, "%"+w+"%")
 	}
 
+	// custom regexes are matched by the caller
+	whereClauses = append(whereClauses, autoTagRegexClause)
+
 	where := strings.Join(whereClauses, " OR ")
 	return qb.queryPerformers(query+" WHERE "+where, args)
 }
@@ -246,8 +302,11 @@ func (qb *performerQueryBuilder) Query(performerFilter *models.PerformerFilterTy
 	}
 
 	if gender := performerFilter.Gender; gender != nil {
-		query.addWhere("performers.gender = ?")
-		query.addArg(gender.Value.String())
+		clause, thisArgs := getGenderFilterClause(*gender)
+		if clause != "" {
+			query.addWhere(clause)
+			query.addArg(thisArgs...)
+		}
 	}
 
 	if isMissingFilter := performerFilter.IsMissing; isMissingFilter != nil && *isMissingFilter != "" {
@@ -265,9 +324,14 @@ func (qb *performerQueryBuilder) Query(performerFilter *models.PerformerFilterTy
 
 	query.handleStringCriterionInput(performerFilter.Ethnicity, tableName+".ethnicity")
 	query.handleStringCriterionInput(performerFilter.Country, tableName+".country")
+	query.handleCountryCriterionInput(performerFilter.Countries, tableName+".country")
 	query.handleStringCriterionInput(performerFilter.EyeColor, tableName+".eye_color")
-	query.handleStringCriterionInput(performerFilter.Height, tableName+".height")
+	query.handleStringCriterionInput(performerFilter.Height, "CAST("+tableName+".height AS TEXT)")
+	query.handleIntCriterionInput(performerFilter.HeightCm, tableName+".height")
 	query.handleStringCriterionInput(performerFilter.Measurements, tableName+".measurements")
+	query.handleIntCriterionInput(performerFilter.Bust, tableName+".bust")
+	query.handleIntCriterionInput(performerFilter.Waist, tableName+".waist")
+	query.handleIntCriterionInput(performerFilter.Hips, tableName+".hips")
 	query.handleStringCriterionInput(performerFilter.FakeTits, tableName+".fake_tits")
 	query.handleStringCriterionInput(performerFilter.CareerLength, tableName+".career_length")
 	query.handleStringCriterionInput(performerFilter.Tattoos, tableName+".tattoos")
@@ -278,6 +342,16 @@ func (qb *performerQueryBuilder) Query(performerFilter *models.PerformerFilterTy
 	query.handleIntCriterionInput(performerFilter.Weight, tableName+".weight")
 	query.handleStringCriterionInput(performerFilter.StashID, "performer_stash_ids.stash_id")
 
+	if endpoint := performerFilter.StashIDEndpoint; endpoint != nil {
+		clause, args, err := getStashIDEndpointCriterionClause(performerTable, "performer_stash_ids", performerIDColumn, *endpoint)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		query.addWhere(clause)
+		query.addArg(args...)
+	}
+
 	// TODO - need better handling of aliases
 	query.handleStringCriterionInput(performerFilter.Aliases, tableName+".aliases")
 
@@ -377,6 +451,35 @@ func getAgeFilterClause(criterionModifier models.CriterionModifier, value int) (
 	return clauses, args
 }
 
+func getGenderFilterClause(criterion models.GenderCriterionInput) (string, []interface{}) {
+	var args []interface{}
+	if criterion.Value != nil {
+		args = append(args, criterion.Value.String())
+	}
+	for _, g := range criterion.ValueList {
+		args = append(args, g.String())
+	}
+
+	switch criterion.Modifier {
+	case models.CriterionModifierIsNull:
+		return "performers.gender IS NULL", nil
+	case models.CriterionModifierNotNull:
+		return "performers.gender IS NOT NULL", nil
+	}
+
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	switch criterion.Modifier {
+	case models.CriterionModifierNotEquals, models.CriterionModifierExcludes:
+		return "(performers.gender IS NULL OR performers.gender NOT IN " + getInBinding(len(args)) + ")", args
+	default:
+		// equals and includes
+		return "performers.gender IN " + getInBinding(len(args)), args
+	}
+}
+
 func (qb *performerQueryBuilder) getPerformerSort(findFilter *models.FindFilterType) string {
 	var sort string
 	var direction string
@@ -424,14 +527,32 @@ func (qb *performerQueryBuilder) UpdateTags(id int, tagIDs []int) error {
 	return qb.tagsRepository().replace(id, tagIDs)
 }
 
-func (qb *performerQueryBuilder) imageRepository() *imageRepository {
-	return &imageRepository{
+func (qb *performerQueryBuilder) scenesRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: performersScenesTable,
+			idColumn:  performerIDColumn,
+		},
+		fkColumn: sceneIDColumn,
+	}
+}
+
+// RemoveMissingScenes removes the scenes which no longer exist from
+// performers, so that they are not included in the scene counts. Returns the
+// number of performers changed.
+func (qb *performerQueryBuilder) RemoveMissingScenes() (int, error) {
+	return qb.scenesRepository().destroyMissing(performerTable, sceneTable)
+}
+
+func (qb *performerQueryBuilder) imageRepository() *imageBlobRepository {
+	return &imageBlobRepository{
 		repository: repository{
 			tx:        qb.tx,
 			tableName: "performers_image",
 			idColumn:  performerIDColumn,
 		},
-		imageColumn: "image",
+		blobColumn: "image_blob",
 	}
 }
 
@@ -444,7 +565,7 @@ func (qb *performerQueryBuilder) UpdateImage(performerID int, image []byte) erro
 }
 
 func (qb *performerQueryBuilder) DestroyImage(performerID int) error {
-	return qb.imageRepository().destroy([]int{performerID})
+	return qb.imageRepository().destroy(performerID)
 }
 
 func (qb *performerQueryBuilder) stashIDRepository() *stashIDRepository {
@@ -465,6 +586,16 @@ func (qb *performerQueryBuilder) UpdateStashIDs(performerID int, stashIDs []mode
 	return qb.stashIDRepository().replace(performerID, stashIDs)
 }
 
+func (qb *performerQueryBuilder) FindByStashID(stashID models.StashID) ([]*models.Performer, error) {
+	query := selectAll("performers") + `
+		LEFT JOIN performer_stash_ids on performer_stash_ids.performer_id = performers.id
+		WHERE performer_stash_ids.stash_id = ?
+		AND performer_stash_ids.endpoint = ?
+	`
+	args := []interface{}{stashID.StashID, stashID.Endpoint}
+	return qb.queryPerformers(query, args)
+}
+
 func (qb *performerQueryBuilder) FindByStashIDStatus(hasStashID bool, stashboxEndpoint string) ([]*models.Performer, error) {
 	query := selectAll("performers") + `
 		LEFT JOIN performer_stash_ids on performer_stash_ids.performer_id = performers.id
@@ -484,3 +615,99 @@ func (qb *performerQueryBuilder) FindByStashIDStatus(hasStashID bool, stashboxEn
 	args := []interface{}{stashboxEndpoint}
 	return qb.queryPerformers(query, args)
 }
+
+// normalizeRelationship returns the relationship as it is stored, with the
+// lower performer id first if the type is symmetric.
+func normalizeRelationship(relationship models.PerformerRelationship) models.PerformerRelationship {
+	if models.PerformerRelationshipType(relationship.Type).IsSymmetric() && relationship.RelatedID < relationship.PerformerID {
+		relationship.PerformerID, relationship.RelatedID = relationship.RelatedID, relationship.PerformerID
+	}
+	return relationship
+}
+
+func (qb *performerQueryBuilder) GetRelationships(performerID int) ([]*models.PerformerRelationship, error) {
+	query := `SELECT * FROM performers_relationships
+		WHERE performer_id = ? OR related_id = ?
+		ORDER BY type ASC, performer_id ASC, related_id ASC`
+
+	var ret models.PerformerRelationships
+	if err := qb.query(query, []interface{}{performerID, performerID}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.PerformerRelationship(ret), nil
+}
+
+func (qb *performerQueryBuilder) AddRelationship(relationship models.PerformerRelationship) error {
+	relationship = normalizeRelationship(relationship)
+	stmt := fmt.Sprintf("INSERT OR IGNORE INTO %s (performer_id, related_id, type) VALUES (?, ?, ?)", performersRelationshipsTable)
+	_, err := qb.tx.Exec(stmt, relationship.PerformerID, relationship.RelatedID, relationship.Type)
+	return err
+}
+
+func (qb *performerQueryBuilder) RemoveRelationship(relationship models.PerformerRelationship) error {
+	relationship = normalizeRelationship(relationship)
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE performer_id = ? AND related_id = ? AND type = ?", performersRelationshipsTable)
+	_, err := qb.tx.Exec(stmt, relationship.PerformerID, relationship.RelatedID, relationship.Type)
+	return err
+}
+
+// FindRelated returns the performers linked to the performer by up to depth
+// relationships of the provided types, following relationships in both
+// directions. Each performer is only visited once, so that cycles of
+// relationships are not followed repeatedly.
+func (qb *performerQueryBuilder) FindRelated(performerID int, types []models.PerformerRelationshipType, depth int) ([]*models.Performer, error) {
+	typeClause := ""
+	var typeArgs []interface{}
+	if len(types) > 0 {
+		typeClause = " AND type IN " + getInBinding(len(types))
+		for _, t := range types {
+			typeArgs = append(typeArgs, t.String())
+		}
+	}
+
+	visited := map[int]bool{performerID: true}
+	var relatedIDs []int
+	frontier := []int{performerID}
+	for i := 0; i < depth && len(frontier) > 0; i++ {
+		inBinding := getInBinding(len(frontier))
+		query := "SELECT * FROM " + performersRelationshipsTable + " WHERE (performer_id IN " + inBinding + " OR related_id IN " + inBinding + ")" + typeClause
+
+		var args []interface{}
+		for _, id := range frontier {
+			args = append(args, id)
+		}
+		args = append(args, args...)
+		args = append(args, typeArgs...)
+
+		var relationships models.PerformerRelationships
+		if err := qb.query(query, args, &relationships); err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, r := range relationships {
+			for _, id := range []int{r.PerformerID, r.RelatedID} {
+				if !visited[id] {
+					visited[id] = true
+					frontier = append(frontier, id)
+					relatedIDs = append(relatedIDs, id)
+				}
+			}
+		}
+	}
+
+	if len(relatedIDs) == 0 {
+		return nil, nil
+	}
+
+	var args []interface{}
+	for _, id := range relatedIDs {
+		args = append(args, id)
+	}
+
+	query := selectAll(performerTable) + "WHERE performers.id IN " + getInBinding(len(relatedIDs)) + `
+	ORDER BY performers.name ASC, performers.id ASC`
+
+	return qb.queryPerformers(query, args)
+}
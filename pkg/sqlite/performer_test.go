@@ -12,6 +12,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -44,6 +46,33 @@ func TestPerformerFindBySceneID(t *testing.T) {
 	})
 }
 
+func TestPerformerFindMany(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Performer()
+
+		// request in reverse order to ensure that the order is preserved
+		var ids []int
+		for i := len(performerIDs) - 1; i >= 0; i-- {
+			ids = append(ids, performerIDs[i])
+		}
+
+		performers, err := qb.FindMany(ids)
+		if err != nil {
+			t.Errorf("Error finding performers: %s", err.Error())
+		}
+
+		assert.Len(t, performers, len(ids))
+		for i, o := range performers {
+			assert.Equal(t, ids[i], o.ID)
+		}
+
+		_, err = qb.FindMany([]int{performerIDs[0], 0})
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
 func TestPerformerFindByNames(t *testing.T) {
 	getNames := func(p []*models.Performer) []string {
 		var ret []string
@@ -611,12 +640,80 @@ func TestPerformerStashIDs(t *testing.T) {
 			return fmt.Errorf("Error creating performer: %s", err.Error())
 		}
 
-		testStashIDReaderWriter(t, qb, created.ID)
+		testStashIDReaderWriter(t, qb, created.ID, func(stashID models.StashID) ([]int, error) {
+			performers, err := qb.FindByStashID(stashID)
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, o := range performers {
+				ids = append(ids, o.ID)
+			}
+			return ids, nil
+		})
+
+		testStashIDEndpointCriterion(t, qb, created.ID, func(c models.StringCriterionInput) ([]int, error) {
+			perPage := -1
+			performers, _, err := qb.Query(&models.PerformerFilterType{
+				StashIDEndpoint: &c,
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, o := range performers {
+				ids = append(ids, o.ID)
+			}
+			return ids, nil
+		})
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+func TestPerformerCreateDuplicateName(t *testing.T) {
+	c := config.GetInstance()
+	c.Set(config.DuplicateNamePolicy, models.DuplicateNamePolicyAllow.String())
+	defer c.Set(config.DuplicateNamePolicy, "")
+
+	const name = "TestPerformerCreateDuplicateName"
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Performer()
+
+		var ids []int
+		for i := 0; i < 2; i++ {
+			if err := manager.ValidatePerformerName(qb, name); err != nil {
+				return err
+			}
+
+			created, err := qb.Create(models.Performer{
+				Name:     sql.NullString{String: name, Valid: true},
+				Checksum: utils.MD5FromString(name),
+				Favorite: sql.NullBool{Bool: false, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("Error creating performer: %s", err.Error())
+			}
+			ids = append(ids, created.ID)
+		}
+
+		performers, err := qb.FindByNames([]string{name}, false)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, performers, 2)
+		assert.NotEqual(t, ids[0], ids[1])
+
 		return nil
 	}); err != nil {
 		t.Error(err.Error())
 	}
 }
+
 func TestPerformerQueryRating(t *testing.T) {
 	const rating = 3
 	ratingCriterion := models.IntCriterionInput{
@@ -659,6 +756,243 @@ func verifyPerformersRating(t *testing.T, ratingCriterion models.IntCriterionInp
 	})
 }
 
+func TestPerformerQueryHeight(t *testing.T) {
+	const height = 170
+	heightCriterion := models.IntCriterionInput{
+		Value:    height,
+		Modifier: models.CriterionModifierEquals,
+	}
+
+	verifyPerformersHeight(t, heightCriterion)
+
+	heightCriterion.Modifier = models.CriterionModifierGreaterThan
+	verifyPerformersHeight(t, heightCriterion)
+
+	heightCriterion.Modifier = models.CriterionModifierLessThan
+	verifyPerformersHeight(t, heightCriterion)
+
+	heightCriterion.Modifier = models.CriterionModifierIsNull
+	verifyPerformersHeight(t, heightCriterion)
+
+	upper := 180
+	heightCriterion.Value2 = &upper
+	heightCriterion.Modifier = models.CriterionModifierBetween
+	verifyPerformersHeight(t, heightCriterion)
+
+	heightCriterion.Modifier = models.CriterionModifierNotBetween
+	verifyPerformersHeight(t, heightCriterion)
+}
+
+func verifyPerformersHeight(t *testing.T, heightCriterion models.IntCriterionInput) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Performer()
+		performerFilter := models.PerformerFilterType{
+			HeightCm: &heightCriterion,
+		}
+
+		performers := queryPerformers(t, sqb, &performerFilter, nil)
+
+		// assume it should find at least one
+		assert.Greater(t, len(performers), 0)
+
+		for _, performer := range performers {
+			verifyInt64(t, performer.Height, heightCriterion)
+		}
+
+		return nil
+	})
+}
+
+func TestPerformerQueryMeasurements(t *testing.T) {
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Performer()
+
+		const name = "TestPerformerQueryMeasurements"
+		create := func(measurements string) (*models.Performer, error) {
+			return qb.Create(models.Performer{
+				Name:         sql.NullString{String: name, Valid: true},
+				Checksum:     utils.MD5FromString(name + measurements),
+				Favorite:     sql.NullBool{Bool: false, Valid: true},
+				Measurements: sql.NullString{String: measurements, Valid: true},
+			})
+		}
+
+		small, err := create("32B-24-34")
+		if err != nil {
+			return fmt.Errorf("Error creating performer: %s", err.Error())
+		}
+		large, err := create("38DD-26-40")
+		if err != nil {
+			return fmt.Errorf("Error creating performer: %s", err.Error())
+		}
+		invalid, err := create("natural")
+		if err != nil {
+			return fmt.Errorf("Error creating performer: %s", err.Error())
+		}
+
+		assert.Equal(t, sql.NullInt64{Int64: 38, Valid: true}, large.Bust)
+		assert.Equal(t, sql.NullInt64{Int64: 26, Valid: true}, large.Waist)
+		assert.Equal(t, sql.NullInt64{Int64: 40, Valid: true}, large.Hips)
+		assert.False(t, invalid.Bust.Valid)
+
+		find := func(filter models.PerformerFilterType) []int {
+			q := name
+			findFilter := models.FindFilterType{
+				Q: &q,
+			}
+
+			var ids []int
+			for _, p := range queryPerformers(t, qb, &filter, &findFilter) {
+				ids = append(ids, p.ID)
+			}
+			return ids
+		}
+
+		upper := 36
+		assert.Equal(t, []int{small.ID}, find(models.PerformerFilterType{
+			Bust: &models.IntCriterionInput{Value: 30, Value2: &upper, Modifier: models.CriterionModifierBetween},
+		}))
+		assert.Equal(t, []int{large.ID}, find(models.PerformerFilterType{
+			Waist: &models.IntCriterionInput{Value: 25, Modifier: models.CriterionModifierGreaterThan},
+		}))
+		assert.Equal(t, []int{invalid.ID}, find(models.PerformerFilterType{
+			Hips: &models.IntCriterionInput{Modifier: models.CriterionModifierIsNull},
+		}))
+
+		// the measurements are updated with the string
+		measurements := sql.NullString{String: "34C-25-36", Valid: true}
+		updated, err := qb.Update(models.PerformerPartial{
+			ID:           invalid.ID,
+			Measurements: &measurements,
+		})
+		if err != nil {
+			return fmt.Errorf("Error updating performer: %s", err.Error())
+		}
+		assert.Equal(t, sql.NullInt64{Int64: 36, Valid: true}, updated.Hips)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestPerformerQueryGender(t *testing.T) {
+	female := models.GenderEnumFemale
+	genderCriterion := models.GenderCriterionInput{
+		Value:    &female,
+		Modifier: models.CriterionModifierEquals,
+	}
+
+	verifyPerformersGender(t, genderCriterion)
+
+	genderCriterion.Modifier = models.CriterionModifierNotEquals
+	verifyPerformersGender(t, genderCriterion)
+
+	genderCriterion = models.GenderCriterionInput{
+		ValueList: []models.GenderEnum{
+			models.GenderEnumFemale,
+			models.GenderEnumTransgenderFemale,
+		},
+		Modifier: models.CriterionModifierIncludes,
+	}
+
+	verifyPerformersGender(t, genderCriterion)
+
+	genderCriterion.Modifier = models.CriterionModifierExcludes
+	verifyPerformersGender(t, genderCriterion)
+
+	genderCriterion.Modifier = models.CriterionModifierIsNull
+	verifyPerformersGender(t, genderCriterion)
+}
+
+func verifyPerformersGender(t *testing.T, genderCriterion models.GenderCriterionInput) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Performer()
+		performerFilter := models.PerformerFilterType{
+			Gender: &genderCriterion,
+		}
+
+		performers := queryPerformers(t, sqb, &performerFilter, nil)
+
+		// assume it should find at least one
+		assert.Greater(t, len(performers), 0)
+
+		var values []string
+		if genderCriterion.Value != nil {
+			values = append(values, genderCriterion.Value.String())
+		}
+		for _, g := range genderCriterion.ValueList {
+			values = append(values, g.String())
+		}
+
+		for _, performer := range performers {
+			switch genderCriterion.Modifier {
+			case models.CriterionModifierEquals, models.CriterionModifierIncludes:
+				assert.Contains(t, values, performer.Gender.String)
+			case models.CriterionModifierNotEquals, models.CriterionModifierExcludes:
+				assert.NotContains(t, values, performer.Gender.String)
+			case models.CriterionModifierIsNull:
+				assert.False(t, performer.Gender.Valid)
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestPerformerQueryCountries(t *testing.T) {
+	countriesCriterion := models.MultiCriterionInput{
+		Value:    []string{"USA"},
+		Modifier: models.CriterionModifierIncludes,
+	}
+
+	verifyPerformersCountries(t, countriesCriterion)
+
+	countriesCriterion.Value = []string{"United States", "united kingdom"}
+	verifyPerformersCountries(t, countriesCriterion)
+
+	countriesCriterion.Modifier = models.CriterionModifierExcludes
+	verifyPerformersCountries(t, countriesCriterion)
+
+	countriesCriterion.Value = []string{"US"}
+	verifyPerformersCountries(t, countriesCriterion)
+
+	countriesCriterion.Modifier = models.CriterionModifierIsNull
+	verifyPerformersCountries(t, countriesCriterion)
+}
+
+func verifyPerformersCountries(t *testing.T, countriesCriterion models.MultiCriterionInput) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Performer()
+		performerFilter := models.PerformerFilterType{
+			Countries: &countriesCriterion,
+		}
+
+		performers := queryPerformers(t, sqb, &performerFilter, nil)
+
+		// assume it should find at least one
+		assert.Greater(t, len(performers), 0)
+
+		var values []string
+		for _, v := range countriesCriterion.Value {
+			values = append(values, utils.NormaliseCountry(v))
+		}
+
+		for _, performer := range performers {
+			switch countriesCriterion.Modifier {
+			case models.CriterionModifierIncludes:
+				assert.Contains(t, values, performer.Country.String)
+			case models.CriterionModifierExcludes:
+				assert.NotContains(t, values, performer.Country.String)
+			case models.CriterionModifierIsNull:
+				assert.False(t, performer.Country.Valid)
+			}
+		}
+
+		return nil
+	})
+}
+
 func TestPerformerQueryIsMissingRating(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Performer()
@@ -679,6 +1013,110 @@ func TestPerformerQueryIsMissingRating(t *testing.T) {
 	})
 }
 
+func TestPerformerRelationships(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Performer()
+		aliasID := performerIDs[performerIdxWithScene]
+		performer1ID := performerIDs[performerIdx1WithScene]
+		performer2ID := performerIDs[performerIdx2WithScene]
+
+		alias := models.PerformerRelationship{
+			PerformerID: aliasID,
+			RelatedID:   performer1ID,
+			Type:        models.PerformerRelationshipTypeAliasOf.String(),
+		}
+		sibling := models.PerformerRelationship{
+			PerformerID: performer2ID,
+			RelatedID:   performer1ID,
+			Type:        models.PerformerRelationshipTypeSibling.String(),
+		}
+
+		for _, relationship := range []models.PerformerRelationship{alias, sibling, sibling} {
+			if err := qb.AddRelationship(relationship); err != nil {
+				return err
+			}
+		}
+
+		defer func() {
+			for _, relationship := range []models.PerformerRelationship{alias, sibling} {
+				if err := qb.RemoveRelationship(relationship); err != nil {
+					t.Error(err.Error())
+				}
+			}
+
+			relationships, err := qb.GetRelationships(performer1ID)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			assert.Empty(t, relationships)
+		}()
+
+		// symmetric relationships are stored with the lower id first
+		relationships, err := qb.GetRelationships(performer1ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, []*models.PerformerRelationship{
+			&alias,
+			{
+				PerformerID: performer1ID,
+				RelatedID:   performer2ID,
+				Type:        sibling.Type,
+			},
+		}, relationships)
+
+		relatedIDs := func(id int, depth int, types ...models.PerformerRelationshipType) []int {
+			performers, err := qb.FindRelated(id, types, depth)
+			if err != nil {
+				t.Error(err.Error())
+			}
+
+			var ret []int
+			for _, p := range performers {
+				ret = append(ret, p.ID)
+			}
+			return ret
+		}
+
+		assert.ElementsMatch(t, []int{performer1ID}, relatedIDs(performer2ID, 1))
+		assert.ElementsMatch(t, []int{performer1ID, aliasID}, relatedIDs(performer2ID, 2))
+		assert.ElementsMatch(t, []int{performer1ID}, relatedIDs(performer2ID, 2, models.PerformerRelationshipTypeSibling))
+		assert.Empty(t, relatedIDs(performer2ID, 2, models.PerformerRelationshipTypePairedWith))
+
+		// each performer is returned once, however often it is reached
+		assert.ElementsMatch(t, []int{performer1ID, aliasID}, relatedIDs(performer2ID, 50))
+
+		// only scenes featuring both related performers match
+		sqb := r.Scene()
+		sceneIDsRelated := func(performerID *string, types ...models.PerformerRelationshipType) []int {
+			scenes := queryScene(t, sqb, &models.SceneFilterType{
+				RelatedPerformers: &models.RelatedPerformersCriterionInput{
+					Types:       types,
+					PerformerID: performerID,
+				},
+			}, nil)
+
+			var ret []int
+			for _, s := range scenes {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		twoPerformersID := sceneIDs[sceneIdxWithTwoPerformers]
+		assert.Equal(t, []int{twoPerformersID}, sceneIDsRelated(nil))
+		assert.Equal(t, []int{twoPerformersID}, sceneIDsRelated(nil, models.PerformerRelationshipTypeSibling))
+		assert.Empty(t, sceneIDsRelated(nil, models.PerformerRelationshipTypeAliasOf))
+
+		performer2 := strconv.Itoa(performer2ID)
+		assert.Equal(t, []int{twoPerformersID}, sceneIDsRelated(&performer2))
+		alias1 := strconv.Itoa(aliasID)
+		assert.Empty(t, sceneIDsRelated(&alias1))
+
+		return nil
+	})
+}
+
 // TODO Update
 // TODO Destroy
 // TODO Find
@@ -686,3 +1124,46 @@ func TestPerformerQueryIsMissingRating(t *testing.T) {
 // TODO All
 // TODO AllSlim
 // TODO Query
+
+func TestPerformerRemoveMissingScenes(t *testing.T) {
+	const missingID = 999999
+	performerID := performerIDs[performerIdxWithScene]
+
+	if err := execWithoutForeignKeys(
+		fmt.Sprintf("INSERT INTO performers_scenes (performer_id, scene_id) VALUES (%d, %d)", performerID, missingID),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		count, err := sqb.CountByPerformerID(performerID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 2, count)
+
+		updated, err := r.Performer().RemoveMissingScenes()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, updated)
+
+		count, err = sqb.CountByPerformerID(performerID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, count)
+
+		// nothing is left to remove
+		updated, err = r.Performer().RemoveMissingScenes()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 0, updated)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const playlistTable = "playlists"
+const playlistsScenesTable = "playlists_scenes"
+const playlistIDColumn = "playlist_id"
+
+type playlistQueryBuilder struct {
+	repository
+}
+
+func NewPlaylistReaderWriter(tx dbi) *playlistQueryBuilder {
+	return &playlistQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: playlistTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *playlistQueryBuilder) Create(newObject models.Playlist) (*models.Playlist, error) {
+	var ret models.Playlist
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *playlistQueryBuilder) Update(updatedObject models.Playlist) (*models.Playlist, error) {
+	const partial = false
+	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	return qb.Find(updatedObject.ID)
+}
+
+func (qb *playlistQueryBuilder) Destroy(id int) error {
+	// the scenes of the playlist are deleted by a delete cascade
+	return qb.destroyExisting([]int{id})
+}
+
+func (qb *playlistQueryBuilder) Find(id int) (*models.Playlist, error) {
+	var ret models.Playlist
+	if err := qb.get(id, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *playlistQueryBuilder) All() ([]*models.Playlist, error) {
+	var ret models.Playlists
+	if err := qb.query(selectAll(playlistTable)+"ORDER BY name ASC, id ASC", nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.Playlist(ret), nil
+}
+
+func (qb *playlistQueryBuilder) Count() (int, error) {
+	return qb.runCountQuery(qb.buildCountQuery("SELECT playlists.id FROM playlists"), nil)
+}
+
+func (qb *playlistQueryBuilder) scenesRepository() *repository {
+	return &repository{
+		tx:        qb.tx,
+		tableName: playlistsScenesTable,
+		idColumn:  playlistIDColumn,
+	}
+}
+
+func (qb *playlistQueryBuilder) GetSceneIDs(playlistID int) ([]int, error) {
+	query := `SELECT scene_id as id FROM playlists_scenes WHERE playlist_id = ? ORDER BY position ASC`
+	return qb.runIdsQuery(query, []interface{}{playlistID})
+}
+
+func (qb *playlistQueryBuilder) UpdateScenes(playlistID int, sceneIDs []int) error {
+	// destroy existing joins
+	r := qb.scenesRepository()
+	if err := r.destroy([]int{playlistID}); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, position) VALUES (?, ?, ?)", playlistsScenesTable, playlistIDColumn, sceneIDColumn)
+	for i, sceneID := range sceneIDs {
+		if _, err := r.tx.Exec(stmt, playlistID, sceneID, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaylistCRUD(t *testing.T) {
+	const name = "TestPlaylistCRUD"
+
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.Playlist()
+		created, err := qb.Create(models.Playlist{
+			Name: name,
+		})
+		if err != nil {
+			return err
+		}
+
+		created.Shuffle = true
+		created.Repeat = true
+		updated, err := qb.Update(*created)
+		if err != nil {
+			return err
+		}
+		assert.True(t, updated.Shuffle)
+		assert.True(t, updated.Repeat)
+
+		ids := []int{sceneIDs[sceneIdxWithGallery], sceneIDs[sceneIdxWithMovie], sceneIDs[sceneIdx1WithPerformer]}
+		if err := qb.UpdateScenes(created.ID, ids); err != nil {
+			return err
+		}
+
+		// scenes are returned in the order of the playlist
+		got, err := qb.GetSceneIDs(created.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, ids, got)
+
+		reordered := []int{ids[2], ids[0]}
+		if err := qb.UpdateScenes(created.ID, reordered); err != nil {
+			return err
+		}
+
+		got, err = qb.GetSceneIDs(created.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, reordered, got)
+
+		// a scene can only be in a playlist once
+		assert.NotNil(t, qb.UpdateScenes(created.ID, []int{ids[0], ids[0]}))
+
+		all, err := qb.All()
+		if err != nil {
+			return err
+		}
+		assert.Contains(t, all, updated)
+
+		count, err := qb.Count()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, len(all), count)
+
+		if err := qb.Destroy(created.ID); err != nil {
+			return err
+		}
+
+		found, err := qb.Find(created.ID)
+		if err != nil {
+			return err
+		}
+		assert.Nil(t, found)
+
+		// the scenes of the playlist are removed with it
+		got, err = qb.GetSceneIDs(created.ID)
+		if err != nil {
+			return err
+		}
+		assert.Empty(t, got)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"database/sql"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// QueryStats contains aggregate timing information for a single normalised
+// SQL statement.
+type QueryStats struct {
+	Query         string
+	Count         int
+	SlowCount     int
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+}
+
+type queryProfiler struct {
+	mutex     sync.RWMutex
+	enabled   bool
+	threshold time.Duration
+	stats     map[string]*QueryStats
+}
+
+var profiler = &queryProfiler{
+	stats: make(map[string]*QueryStats),
+}
+
+var (
+	whitespaceRE   = regexp.MustCompile(`\s+`)
+	numberRE       = regexp.MustCompile(`\b\d+\b`)
+	placeholdersRE = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+)
+
+// ConfigureQueryProfiler enables or disables the query profiler. When
+// enabled, aggregate statistics are collected for all statements, and
+// statements taking longer than threshold are logged along with their
+// arguments. A threshold of zero disables slow query logging.
+func ConfigureQueryProfiler(enabled bool, threshold time.Duration) {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	profiler.enabled = enabled
+	profiler.threshold = threshold
+}
+
+// GetQueryProfile returns the collected query statistics, ordered by total
+// duration descending.
+func GetQueryProfile() []QueryStats {
+	profiler.mutex.RLock()
+	defer profiler.mutex.RUnlock()
+
+	ret := make([]QueryStats, 0, len(profiler.stats))
+	for _, s := range profiler.stats {
+		ret = append(ret, *s)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].TotalDuration > ret[j].TotalDuration
+	})
+
+	return ret
+}
+
+// ResetQueryProfile clears the collected query statistics.
+func ResetQueryProfile() {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+
+	profiler.stats = make(map[string]*QueryStats)
+}
+
+func (p *queryProfiler) isEnabled() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.enabled
+}
+
+// normaliseQuery collapses whitespace and literal values so that
+// statements differing only in pagination or the number of bound values
+// are aggregated together.
+func normaliseQuery(query string) string {
+	ret := strings.TrimSpace(whitespaceRE.ReplaceAllString(query, " "))
+	ret = numberRE.ReplaceAllString(ret, "N")
+	ret = placeholdersRE.ReplaceAllString(ret, "?...")
+	return ret
+}
+
+func (p *queryProfiler) record(query string, args interface{}, start time.Time) {
+	d := time.Since(start)
+	key := normaliseQuery(query)
+
+	p.mutex.Lock()
+	s := p.stats[key]
+	if s == nil {
+		s = &QueryStats{
+			Query: key,
+		}
+		p.stats[key] = s
+	}
+
+	s.Count++
+	s.TotalDuration += d
+	if d > s.MaxDuration {
+		s.MaxDuration = d
+	}
+
+	slow := p.threshold > 0 && d >= p.threshold
+	if slow {
+		s.SlowCount++
+	}
+	p.mutex.Unlock()
+
+	if slow {
+		logger.Warnf("[sql] slow query (%s): %s, args: %+v", d, query, args)
+	}
+}
+
+// profiledDB wraps a dbi, recording the duration of each statement.
+type profiledDB struct {
+	db dbi
+}
+
+// profile returns db wrapped with the query profiler if profiling is
+// enabled. Otherwise db is returned unchanged.
+func profile(db dbi) dbi {
+	if !profiler.isEnabled() {
+		return db
+	}
+
+	return &profiledDB{db: db}
+}
+
+func (p *profiledDB) Get(dest interface{}, query string, args ...interface{}) error {
+	defer profiler.record(query, args, time.Now())
+	return p.db.Get(dest, query, args...)
+}
+
+func (p *profiledDB) Select(dest interface{}, query string, args ...interface{}) error {
+	defer profiler.record(query, args, time.Now())
+	return p.db.Select(dest, query, args...)
+}
+
+// Queryx only measures the time taken to execute the statement, not the
+// time taken to iterate over the returned rows.
+func (p *profiledDB) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	defer profiler.record(query, args, time.Now())
+	return p.db.Queryx(query, args...)
+}
+
+func (p *profiledDB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	defer profiler.record(query, arg, time.Now())
+	return p.db.NamedExec(query, arg)
+}
+
+func (p *profiledDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	defer profiler.record(query, args, time.Now())
+	return p.db.Exec(query, args...)
+}
+
+func (p *profiledDB) recordChange(tableName string, idColumn string, change models.EntityChangeType, ids []int) {
+	if c, ok := p.db.(changeRecorder); ok {
+		c.recordChange(tableName, idColumn, change, ids)
+	}
+}
+
+func (p *profiledDB) removeOnCommit(path string) {
+	if r, ok := p.db.(fileRemover); ok {
+		r.removeOnCommit(path)
+	}
+}
+
+func (p *profiledDB) keepOnCommit(path string) {
+	if r, ok := p.db.(fileRemover); ok {
+		r.keepOnCommit(path)
+	}
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(
+		"SELECT * FROM scenes WHERE id IN (?...) LIMIT N OFFSET N",
+		normaliseQuery("SELECT *  FROM scenes\n\tWHERE id IN (?, ?,?) LIMIT 40 OFFSET 80 "),
+	)
+	assert.Equal(
+		"SELECT * FROM scenes WHERE id = ?",
+		normaliseQuery("SELECT * FROM scenes WHERE id = ?"),
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 type queryBuilder struct {
@@ -38,12 +39,17 @@ func (qb queryBuilder) executeCount() (int, error) {
 		return 0, qb.err
 	}
 
+	countQuery := qb.repository.buildCountQuery(qb.toSQL())
+	return qb.repository.runCountQuery(countQuery, qb.args)
+}
+
+// toSQL returns the query without sorting or pagination, for use as a
+// subquery.
+func (qb queryBuilder) toSQL() string {
 	body := qb.body
 	body += qb.joins.toSQL()
 
-	body = qb.repository.buildQueryBody(body, qb.whereClauses, qb.havingClauses)
-	countQuery := qb.repository.buildCountQuery(body)
-	return qb.repository.runCountQuery(countQuery, qb.args)
+	return qb.repository.buildQueryBody(body, qb.whereClauses, qb.havingClauses)
 }
 
 func (qb *queryBuilder) addWhere(clauses ...string) {
@@ -165,6 +171,53 @@ func (qb *queryBuilder) handleStringCriterionInput(c *models.StringCriterionInpu
 	}
 }
 
+// handleCountryCriterionInput filters a country column by the provided
+// values, which are normalised to ISO codes before comparing.
+func (qb *queryBuilder) handleCountryCriterionInput(c *models.MultiCriterionInput, column string) {
+	if c == nil {
+		return
+	}
+
+	switch c.Modifier {
+	case models.CriterionModifierIsNull:
+		qb.addWhere("(" + column + " IS NULL OR TRIM(" + column + ") = '')")
+		return
+	case models.CriterionModifierNotNull:
+		qb.addWhere("(" + column + " IS NOT NULL AND TRIM(" + column + ") != '')")
+		return
+	}
+
+	var args []interface{}
+	seen := make(map[string]bool)
+	for _, v := range c.Value {
+		country := utils.NormaliseCountry(v)
+		if country != "" && !seen[country] {
+			seen[country] = true
+			args = append(args, country)
+		}
+	}
+
+	if len(args) == 0 {
+		return
+	}
+
+	switch c.Modifier {
+	case models.CriterionModifierExcludes:
+		qb.addWhere("(" + column + " IS NULL OR " + column + " NOT IN " + getInBinding(len(args)) + ")")
+	case models.CriterionModifierIncludesAll:
+		// a single country cannot match more than one value
+		if len(args) > 1 {
+			qb.addWhere("0")
+			return
+		}
+		fallthrough
+	default:
+		qb.addWhere(column + " IN " + getInBinding(len(args)))
+	}
+
+	qb.addArg(args...)
+}
+
 func (qb *queryBuilder) handleCountCriterion(countFilter *models.IntCriterionInput, primaryTable, joinTable, primaryFK string) {
 	if countFilter != nil {
 		clause, count := getCountCriterionClause(primaryTable, joinTable, primaryFK, *countFilter)
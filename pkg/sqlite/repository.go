@@ -49,6 +49,7 @@ func (r *repository) insertObject(obj interface{}, out interface{}) error {
 	if err != nil {
 		return err
 	}
+	r.recordChange(models.EntityChangeTypeCreated, int(id))
 	return r.get(int(id), out)
 }
 
@@ -63,9 +64,12 @@ func (r *repository) update(id int, obj interface{}, partial bool) error {
 	}
 
 	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s.%s = :id", r.tableName, updateSet(obj, partial), r.tableName, r.idColumn)
-	_, err = r.tx.NamedExec(stmt, obj)
+	if _, err := r.tx.NamedExec(stmt, obj); err != nil {
+		return err
+	}
 
-	return err
+	r.recordChange(models.EntityChangeTypeUpdated, id)
+	return nil
 }
 
 func (r *repository) updateMap(id int, m map[string]interface{}) error {
@@ -79,9 +83,12 @@ func (r *repository) updateMap(id int, m map[string]interface{}) error {
 	}
 
 	stmt := fmt.Sprintf("UPDATE %s SET %s WHERE %s.%s = :id", r.tableName, updateSetMap(m), r.tableName, r.idColumn)
-	_, err = r.tx.NamedExec(stmt, m)
+	if _, err := r.tx.NamedExec(stmt, m); err != nil {
+		return err
+	}
 
-	return err
+	r.recordChange(models.EntityChangeTypeUpdated, id)
+	return nil
 }
 
 func (r *repository) destroyExisting(ids []int) error {
@@ -107,9 +114,18 @@ func (r *repository) destroy(ids []int) error {
 		}
 	}
 
+	r.recordChange(models.EntityChangeTypeDestroyed, ids...)
 	return nil
 }
 
+// recordChange records the change to the objects with the provided ids, if
+// changes are being tracked by the current transaction.
+func (r *repository) recordChange(change models.EntityChangeType, ids ...int) {
+	if c, ok := r.tx.(changeRecorder); ok {
+		c.recordChange(r.tableName, r.idColumn, change, ids)
+	}
+}
+
 func (r *repository) exists(id int) (bool, error) {
 	stmt := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? LIMIT 1", r.idColumn, r.tableName, r.idColumn)
 	stmt = r.buildCountQuery(stmt)
@@ -190,6 +206,43 @@ func (r *repository) queryFunc(query string, args []interface{}, f func(rows *sq
 	return nil
 }
 
+// findManyBatchSize is the maximum number of ids queried at once by
+// queryByIDs and getManyIDs, to stay well within the sqlite host parameter
+// limit.
+const findManyBatchSize = 500
+
+// forEachIDBatch calls fn with the provided ids split into batches of at
+// most findManyBatchSize, as query arguments.
+func forEachIDBatch(ids []int, fn func(args []interface{}) error) error {
+	for start := 0; start < len(ids); start += findManyBatchSize {
+		end := start + findManyBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		batch := ids[start:end]
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+
+		if err := fn(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queryByIDs populates out with the rows matching the provided ids. Rows are
+// returned in no particular order, and missing ids are silently skipped.
+func (r *repository) queryByIDs(ids []int, out objectList) error {
+	return forEachIDBatch(ids, func(args []interface{}) error {
+		query := selectAll(r.tableName) + "WHERE " + getColumn(r.tableName, r.idColumn) + " IN " + getInBinding(len(args))
+		return r.query(query, args, out)
+	})
+}
+
 func (r *repository) query(query string, args []interface{}, out objectList) error {
 	rows, err := r.tx.Queryx(query, args...)
 
@@ -301,9 +354,42 @@ func (r *joinRepository) getIDs(id int) ([]int, error) {
 	return r.runIdsQuery(query, []interface{}{id})
 }
 
+// getManyIDs returns the foreign ids joined to each of the provided ids, in
+// the same order as ids.
+func (r *joinRepository) getManyIDs(ids []int) ([][]int, error) {
+	byID := make(map[int][]int)
+	if err := forEachIDBatch(ids, func(args []interface{}) error {
+		query := fmt.Sprintf(`SELECT %s, %s from %s WHERE %s IN %s`, r.idColumn, r.fkColumn, r.tableName, r.idColumn, getInBinding(len(args)))
+		return r.queryFunc(query, args, func(rows *sqlx.Rows) error {
+			var id, foreignID int
+			if err := rows.Scan(&id, &foreignID); err != nil {
+				return err
+			}
+
+			byID[id] = append(byID[id], foreignID)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := make([][]int, len(ids))
+	for i, id := range ids {
+		ret[i] = byID[id]
+	}
+
+	return ret, nil
+}
+
 func (r *joinRepository) insert(id, foreignID int) (sql.Result, error) {
 	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)", r.tableName, r.idColumn, r.fkColumn)
-	return r.tx.Exec(stmt, id, foreignID)
+	ret, err := r.tx.Exec(stmt, id, foreignID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.recordChange(models.EntityChangeTypeUpdated, id)
+	return ret, nil
 }
 
 func (r *joinRepository) replace(id int, foreignIDs []int) error {
@@ -320,6 +406,31 @@ func (r *joinRepository) replace(id int, foreignIDs []int) error {
 	return nil
 }
 
+// destroyMissing removes the joins to objects which no longer exist in table
+// or foreignTable, which may be left behind by changes made to the database
+// without foreign key checks. Returns the number of objects in table which
+// lost joins.
+func (r *joinRepository) destroyMissing(table string, foreignTable string) (int, error) {
+	query := fmt.Sprintf(`SELECT DISTINCT %[1]s as id FROM %[2]s
+WHERE %[3]s NOT IN (SELECT id FROM %[4]s) AND %[1]s IN (SELECT id FROM %[5]s)`, r.idColumn, r.tableName, r.fkColumn, foreignTable, table)
+	ids, err := r.runIdsQuery(query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	stmt := fmt.Sprintf(`DELETE FROM %[1]s
+WHERE %[2]s NOT IN (SELECT id FROM %[3]s) OR %[4]s NOT IN (SELECT id FROM %[5]s)`, r.tableName, r.idColumn, table, r.fkColumn, foreignTable)
+	if _, err := r.tx.Exec(stmt); err != nil {
+		return 0, err
+	}
+
+	if len(ids) > 0 {
+		r.recordChange(models.EntityChangeTypeUpdated, ids...)
+	}
+
+	return len(ids), nil
+}
+
 type imageRepository struct {
 	repository
 	imageColumn string
@@ -379,6 +490,42 @@ func (r *stashIDRepository) replace(id int, newIDs []models.StashID) error {
 	return nil
 }
 
+type captionRepository struct {
+	repository
+}
+
+type sceneCaptions []*models.SceneCaption
+
+func (s *sceneCaptions) Append(o interface{}) {
+	*s = append(*s, o.(*models.SceneCaption))
+}
+
+func (s *sceneCaptions) New() interface{} {
+	return &models.SceneCaption{}
+}
+
+func (r *captionRepository) get(id int) ([]*models.SceneCaption, error) {
+	query := fmt.Sprintf("SELECT * from %s WHERE %s = ? ORDER BY id", r.tableName, r.idColumn)
+	var ret sceneCaptions
+	err := r.query(query, []interface{}{id}, &ret)
+	return []*models.SceneCaption(ret), err
+}
+
+func (r *captionRepository) replace(id int, captions []*models.SceneCaption) error {
+	if err := r.destroy([]int{id}); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s, stream_index, path, language_code, title, codec) VALUES (?, ?, ?, ?, ?, ?)", r.tableName, r.idColumn)
+	for _, c := range captions {
+		_, err := r.tx.Exec(query, id, c.StreamIndex, c.Path, c.LanguageCode, c.Title, c.Codec)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func listKeys(i interface{}, addPrefix bool) string {
 	var query []string
 	v := reflect.ValueOf(i)
@@ -124,6 +124,24 @@ func (qb *sceneQueryBuilder) UpdateFileModTime(id int, modTime models.NullSQLite
 	})
 }
 
+// ClearMissingStudios unsets the studio of scenes whose studio no longer
+// exists, which may be left behind by changes made to the database without
+// foreign key checks. Returns the number of scenes changed.
+func (qb *sceneQueryBuilder) ClearMissingStudios() (int, error) {
+	query := `SELECT id FROM scenes WHERE studio_id IS NOT NULL AND studio_id NOT IN (SELECT id FROM studios)`
+	ids, err := qb.runIdsQuery(query, nil)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	if _, err := qb.tx.Exec(`UPDATE scenes SET studio_id = NULL WHERE studio_id IS NOT NULL AND studio_id NOT IN (SELECT id FROM studios)`); err != nil {
+		return 0, err
+	}
+
+	qb.recordChange(models.EntityChangeTypeUpdated, ids...)
+	return len(ids), nil
+}
+
 func (qb *sceneQueryBuilder) IncrementOCounter(id int) (int, error) {
 	_, err := qb.tx.Exec(
 		`UPDATE scenes SET o_counter = o_counter + 1 WHERE scenes.id = ?`,
@@ -133,6 +151,8 @@ func (qb *sceneQueryBuilder) IncrementOCounter(id int) (int, error) {
 		return 0, err
 	}
 
+	qb.recordChange(models.EntityChangeTypeUpdated, id)
+
 	scene, err := qb.find(id)
 	if err != nil {
 		return 0, err
@@ -150,6 +170,8 @@ func (qb *sceneQueryBuilder) DecrementOCounter(id int) (int, error) {
 		return 0, err
 	}
 
+	qb.recordChange(models.EntityChangeTypeUpdated, id)
+
 	scene, err := qb.find(id)
 	if err != nil {
 		return 0, err
@@ -167,6 +189,8 @@ func (qb *sceneQueryBuilder) ResetOCounter(id int) (int, error) {
 		return 0, err
 	}
 
+	qb.recordChange(models.EntityChangeTypeUpdated, id)
+
 	scene, err := qb.find(id)
 	if err != nil {
 		return 0, err
@@ -193,21 +217,28 @@ func (qb *sceneQueryBuilder) Find(id int) (*models.Scene, error) {
 }
 
 func (qb *sceneQueryBuilder) FindMany(ids []int) ([]*models.Scene, error) {
-	var scenes []*models.Scene
-	for _, id := range ids {
-		scene, err := qb.Find(id)
-		if err != nil {
-			return nil, err
-		}
+	var scenes models.Scenes
+	if err := qb.queryByIDs(ids, &scenes); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Scene)
+	for _, o := range scenes {
+		byID[o.ID] = o
+	}
 
-		if scene == nil {
+	// return in the same order as the provided ids
+	var ret []*models.Scene
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
 			return nil, fmt.Errorf("scene with id %d not found", id)
 		}
 
-		scenes = append(scenes, scene)
+		ret = append(ret, o)
 	}
 
-	return scenes, nil
+	return ret, nil
 }
 
 func (qb *sceneQueryBuilder) find(id int) (*models.Scene, error) {
@@ -272,6 +303,10 @@ func (qb *sceneQueryBuilder) Size() (float64, error) {
 	return qb.runSumQuery("SELECT SUM(cast(size as double)) as sum FROM scenes", nil)
 }
 
+func (qb *sceneQueryBuilder) Duration() (float64, error) {
+	return qb.runSumQuery("SELECT COALESCE(SUM(duration), 0) as sum FROM scenes", nil)
+}
+
 func (qb *sceneQueryBuilder) CountByStudioID(studioID int) (int, error) {
 	args := []interface{}{studioID}
 	return qb.runCountQuery(qb.buildCountQuery(scenesForStudioQuery), args)
@@ -357,25 +392,59 @@ func (qb *sceneQueryBuilder) makeFilter(sceneFilter *models.SceneFilterType) *fi
 	query.handleCriterionFunc(intCriterionHandler(sceneFilter.Rating, "scenes.rating"))
 	query.handleCriterionFunc(intCriterionHandler(sceneFilter.OCounter, "scenes.o_counter"))
 	query.handleCriterionFunc(boolCriterionHandler(sceneFilter.Organized, "scenes.organized"))
+	query.handleCriterionFunc(boolCriterionHandler(sceneFilter.AudioOnly, "scenes.audio_only"))
+	query.handleCriterionFunc(boolCriterionHandler(sceneFilter.Interactive, "scenes.interactive"))
+	query.handleCriterionFunc(intCriterionHandler(sceneFilter.InteractiveSpeed, "scenes.interactive_speed"))
 	query.handleCriterionFunc(durationCriterionHandler(sceneFilter.Duration, "scenes.duration"))
 	query.handleCriterionFunc(resolutionCriterionHandler(sceneFilter.Resolution, "scenes.height", "scenes.width"))
 	query.handleCriterionFunc(hasMarkersCriterionHandler(sceneFilter.HasMarkers))
 	query.handleCriterionFunc(sceneIsMissingCriterionHandler(qb, sceneFilter.IsMissing))
 	query.handleCriterionFunc(stringCriterionHandler(sceneFilter.URL, "scenes.url"))
+	query.handleCriterionFunc(stringCriterionHandler(sceneFilter.Location, "scenes.location"))
+	query.handleCriterionFunc(stringCriterionHandler(sceneFilter.DecodeError, "scenes.decode_error"))
+	query.handleCriterionFunc(geoRadiusCriterionHandler(sceneFilter.Nearby, "scenes.latitude", "scenes.longitude"))
 	query.handleCriterionFunc(stringCriterionHandler(sceneFilter.StashID, "scene_stash_ids.stash_id"))
+	query.handleCriterionFunc(sceneStashIDEndpointCriterionHandler(sceneFilter.StashIDEndpoint))
 
 	query.handleCriterionFunc(sceneTagsCriterionHandler(qb, sceneFilter.Tags))
 	query.handleCriterionFunc(sceneTagCountCriterionHandler(qb, sceneFilter.TagCount))
 	query.handleCriterionFunc(scenePerformersCriterionHandler(qb, sceneFilter.Performers))
 	query.handleCriterionFunc(scenePerformerCountCriterionHandler(qb, sceneFilter.PerformerCount))
+	query.handleCriterionFunc(scenePerformerAgeCriterionHandler(sceneFilter.PerformerAge))
+	query.handleCriterionFunc(sceneAppearsWithCriterionHandler(sceneFilter.AppearsWith))
 	query.handleCriterionFunc(sceneStudioCriterionHandler(qb, sceneFilter.Studios))
 	query.handleCriterionFunc(sceneMoviesCriterionHandler(qb, sceneFilter.Movies))
+	query.handleCriterionFunc(sceneGroupsCriterionHandler(sceneFilter.Groups))
+	query.handleCriterionFunc(sceneRelatedPerformersCriterionHandler(sceneFilter.RelatedPerformers))
 	query.handleCriterionFunc(scenePerformerTagsCriterionHandler(qb, sceneFilter.PerformerTags))
 
 	return query
 }
 
-func (qb *sceneQueryBuilder) Query(sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType) ([]*models.Scene, int, error) {
+// subquery returns a query selecting the ids of the scenes matching the
+// provided filter, along with its arguments.
+func (qb *sceneQueryBuilder) subquery(sceneFilter *models.SceneFilterType) (string, []interface{}, error) {
+	if err := qb.validateFilter(sceneFilter); err != nil {
+		return "", nil, err
+	}
+
+	query := qb.newQuery()
+	query.body = selectDistinctIDs(sceneTable)
+
+	filter := qb.makeFilter(sceneFilter)
+	if sceneFilter.StashID != nil {
+		qb.stashIDRepository().join(filter, "scene_stash_ids", "scenes.id")
+	}
+
+	query.addFilter(filter)
+	if query.err != nil {
+		return "", nil, query.err
+	}
+
+	return query.toSQL(), query.args, nil
+}
+
+func (qb *sceneQueryBuilder) makeQuery(sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
 	if sceneFilter == nil {
 		sceneFilter = &models.SceneFilterType{}
 	}
@@ -396,7 +465,7 @@ func (qb *sceneQueryBuilder) Query(sceneFilter *models.SceneFilterType, findFilt
 	}
 
 	if err := qb.validateFilter(sceneFilter); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	filter := qb.makeFilter(sceneFilter)
 
@@ -409,23 +478,34 @@ func (qb *sceneQueryBuilder) Query(sceneFilter *models.SceneFilterType, findFilt
 	qb.setSceneSort(&query, findFilter)
 	query.sortAndPagination += getPagination(findFilter)
 
-	idsResult, countResult, err := query.executeFind()
+	return &query, nil
+}
+
+func (qb *sceneQueryBuilder) Query(sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType) ([]*models.Scene, int, error) {
+	idsResult, countResult, err := qb.QueryIDs(sceneFilter, findFilter)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	var scenes []*models.Scene
-	for _, id := range idsResult {
-		scene, err := qb.Find(id)
-		if err != nil {
-			return nil, 0, err
-		}
-		scenes = append(scenes, scene)
+	scenes, err := qb.FindMany(idsResult)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return scenes, countResult, nil
 }
 
+// QueryIDs returns the ids of the scenes matching the provided filters,
+// along with the total count, without fetching the scenes themselves.
+func (qb *sceneQueryBuilder) QueryIDs(sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType) ([]int, int, error) {
+	query, err := qb.makeQuery(sceneFilter, findFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return query.executeFind()
+}
+
 func appendClause(clauses []string, clause string) []string {
 	if clause != "" {
 		return append(clauses, clause)
@@ -503,6 +583,20 @@ func hasMarkersCriterionHandler(hasMarkers *string) criterionHandlerFunc {
 	}
 }
 
+func sceneStashIDEndpointCriterionHandler(endpoint *models.StringCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if endpoint != nil {
+			clause, args, err := getStashIDEndpointCriterionClause(sceneTable, "scene_stash_ids", sceneIDColumn, *endpoint)
+			if err != nil {
+				f.setError(err)
+				return
+			}
+
+			f.addWhere(clause, args...)
+		}
+	}
+}
+
 func sceneIsMissingCriterionHandler(qb *sceneQueryBuilder, isMissing *string) criterionHandlerFunc {
 	return func(f *filterBuilder) {
 		if isMissing != nil && *isMissing != "" {
@@ -523,6 +617,17 @@ func sceneIsMissingCriterionHandler(qb *sceneQueryBuilder, isMissing *string) cr
 			case "tags":
 				qb.tagsRepository().join(f, "tags_join", "scenes.id")
 				f.addWhere("tags_join.scene_id IS NULL")
+			case "phash":
+				f.addWhere("scenes.phash IS NULL")
+			case "cover":
+				f.addWhere("NOT EXISTS (SELECT 1 FROM scenes_cover WHERE scenes_cover.scene_id = scenes.id)")
+			case "preview", "sprite":
+				// generated files are checked on the filesystem, so this is
+				// slower than the other criteria
+				f.addWhere("NOT generatedFileExists(?, COALESCE(scenes.checksum, ''), COALESCE(scenes.oshash, ''))", *isMissing)
+			case "heatmap":
+				// only interactive scenes have a heatmap
+				f.addWhere("scenes.interactive = 1 AND NOT generatedFileExists('heatmap', COALESCE(scenes.checksum, ''), COALESCE(scenes.oshash, ''))")
 			default:
 				f.addWhere("(scenes." + *isMissing + " IS NULL OR TRIM(scenes." + *isMissing + ") = '')")
 			}
@@ -587,6 +692,27 @@ func scenePerformerCountCriterionHandler(qb *sceneQueryBuilder, performerCount *
 	return h.handler(performerCount)
 }
 
+func scenePerformerAgeCriterionHandler(performerAge *models.IntCriterionInput) criterionHandlerFunc {
+	h := performerAgeCriterionHandlerBuilder{
+		primaryTable: sceneTable,
+		joinTable:    performersScenesTable,
+		primaryFK:    sceneIDColumn,
+		dateColumn:   "date",
+	}
+
+	return h.handler(performerAge)
+}
+
+func sceneAppearsWithCriterionHandler(appearsWith *models.MultiCriterionInput) criterionHandlerFunc {
+	h := appearsWithCriterionHandlerBuilder{
+		primaryTable: sceneTable,
+		joinTable:    performersScenesTable,
+		primaryFK:    sceneIDColumn,
+	}
+
+	return h.handler(appearsWith)
+}
+
 func sceneStudioCriterionHandler(qb *sceneQueryBuilder, studios *models.MultiCriterionInput) criterionHandlerFunc {
 	addJoinsFunc := func(f *filterBuilder) {
 		f.addJoin("studios", "studio", "studio.id = scenes.studio_id")
@@ -605,6 +731,64 @@ func sceneMoviesCriterionHandler(qb *sceneQueryBuilder, movies *models.MultiCrit
 	return h.handler(movies)
 }
 
+// sceneGroupsCriterionHandler matches the scenes of the provided groups,
+// including the scenes of the groups nested in them.
+func sceneGroupsCriterionHandler(groups *models.MultiCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if groups != nil && len(groups.Value) > 0 {
+			var args []interface{}
+			for _, groupID := range groups.Value {
+				args = append(args, groupID)
+			}
+
+			subScenes := fmt.Sprintf("SELECT scene_id FROM (%s) WHERE group_id IN %s", groupSubScenesQuery, getInBinding(len(groups.Value)))
+
+			if groups.Modifier == models.CriterionModifierIncludes {
+				// includes any of the provided ids
+				f.addWhere("scenes.id IN ("+subScenes+")", args...)
+			} else if groups.Modifier == models.CriterionModifierIncludesAll {
+				// includes all of the provided ids
+				f.addWhere(fmt.Sprintf("scenes.id IN (%s GROUP BY scene_id HAVING count(distinct group_id) IS %d)", subScenes, len(groups.Value)), args...)
+			} else if groups.Modifier == models.CriterionModifierExcludes {
+				f.addWhere("scenes.id NOT IN ("+subScenes+")", args...)
+			}
+		}
+	}
+}
+
+// sceneRelatedPerformersCriterionHandler matches the scenes featuring two
+// performers linked by a relationship of the provided types.
+func sceneRelatedPerformersCriterionHandler(related *models.RelatedPerformersCriterionInput) criterionHandlerFunc {
+	return func(f *filterBuilder) {
+		if related == nil {
+			return
+		}
+
+		var clauses []string
+		var args []interface{}
+		if len(related.Types) > 0 {
+			clauses = append(clauses, "r.type IN "+getInBinding(len(related.Types)))
+			for _, t := range related.Types {
+				args = append(args, t.String())
+			}
+		}
+		if related.PerformerID != nil {
+			clauses = append(clauses, "(r.performer_id = ? OR r.related_id = ?)")
+			args = append(args, *related.PerformerID, *related.PerformerID)
+		}
+
+		where := ""
+		if len(clauses) > 0 {
+			where = " WHERE " + strings.Join(clauses, " AND ")
+		}
+
+		f.addWhere(fmt.Sprintf(`scenes.id IN (SELECT s.scene_id FROM %[1]s r
+			INNER JOIN %[2]s s ON s.performer_id = r.performer_id
+			INNER JOIN %[2]s o ON o.scene_id = s.scene_id AND o.performer_id = r.related_id%[3]s)`,
+			performersRelationshipsTable, performersScenesTable, where), args...)
+	}
+}
+
 func scenePerformerTagsCriterionHandler(qb *sceneQueryBuilder, performerTagsFilter *models.MultiCriterionInput) criterionHandlerFunc {
 	return func(f *filterBuilder) {
 		if performerTagsFilter != nil && len(performerTagsFilter.Value) > 0 {
@@ -779,6 +963,10 @@ func (qb *sceneQueryBuilder) GetPerformerIDs(id int) ([]int, error) {
 	return qb.performersRepository().getIDs(id)
 }
 
+func (qb *sceneQueryBuilder) GetManyPerformerIDs(ids []int) ([][]int, error) {
+	return qb.performersRepository().getManyIDs(ids)
+}
+
 func (qb *sceneQueryBuilder) UpdatePerformers(id int, performerIDs []int) error {
 	// Delete the existing joins and then create new ones
 	return qb.performersRepository().replace(id, performerIDs)
@@ -799,6 +987,10 @@ func (qb *sceneQueryBuilder) GetTagIDs(id int) ([]int, error) {
 	return qb.tagsRepository().getIDs(id)
 }
 
+func (qb *sceneQueryBuilder) GetManyTagIDs(ids []int) ([][]int, error) {
+	return qb.tagsRepository().getManyIDs(ids)
+}
+
 func (qb *sceneQueryBuilder) UpdateTags(id int, tagIDs []int) error {
 	// Delete the existing joins and then create new ones
 	return qb.tagsRepository().replace(id, tagIDs)
@@ -842,6 +1034,24 @@ func (qb *sceneQueryBuilder) UpdateStashIDs(sceneID int, stashIDs []models.Stash
 	return qb.stashIDRepository().replace(sceneID, stashIDs)
 }
 
+func (qb *sceneQueryBuilder) captionRepository() *captionRepository {
+	return &captionRepository{
+		repository{
+			tx:        qb.tx,
+			tableName: "scene_captions",
+			idColumn:  sceneIDColumn,
+		},
+	}
+}
+
+func (qb *sceneQueryBuilder) GetCaptions(sceneID int) ([]*models.SceneCaption, error) {
+	return qb.captionRepository().get(sceneID)
+}
+
+func (qb *sceneQueryBuilder) UpdateCaptions(sceneID int, captions []*models.SceneCaption) error {
+	return qb.captionRepository().replace(sceneID, captions)
+}
+
 func (qb *sceneQueryBuilder) FindDuplicates(distance int) ([][]*models.Scene, error) {
 	var dupeIds [][]int
 	if distance == 0 {
@@ -248,6 +248,25 @@ func (qb *sceneMarkerQueryBuilder) Query(sceneMarkerFilter *models.SceneMarkerFi
 		whereClauses = append(whereClauses, "(scene_markers.primary_tag_id = "+*tagID+" OR tags.id = "+*tagID+")")
 	}
 
+	if tagCount := sceneMarkerFilter.TagCount; tagCount != nil {
+		clause, count := getCountCriterionClause(sceneMarkerTable, "scene_markers_tags", "scene_marker_id", *tagCount)
+		whereClauses = append(whereClauses, clause)
+		if count == 1 {
+			args = append(args, tagCount.Value)
+		}
+	}
+
+	if sceneFilter := sceneMarkerFilter.SceneFilter; sceneFilter != nil {
+		sqb := NewSceneReaderWriter(qb.tx)
+		subquery, thisArgs, err := sqb.subquery(sceneFilter)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		whereClauses = append(whereClauses, "scene_markers.scene_id IN ("+subquery+")")
+		args = append(args, thisArgs...)
+	}
+
 	sortAndPagination := qb.getSceneMarkerSort(findFilter) + getPagination(findFilter)
 	idsResult, countResult, err := qb.executeFindQuery(body, args, sortAndPagination, whereClauses, havingClauses)
 	if err != nil {
@@ -271,9 +290,13 @@ func (qb *sceneMarkerQueryBuilder) getSceneMarkerSort(findFilter *models.FindFil
 	sort := findFilter.GetSort("title")
 	direction := findFilter.GetDirection()
 	tableName := "scene_markers"
-	if sort == "scenes_updated_at" {
+	switch sort {
+	case "scenes_updated_at":
 		sort = "updated_at"
 		tableName = "scene"
+	case "scenes_created_at":
+		sort = "created_at"
+		tableName = "scene"
 	}
 	return getSort(sort, direction, tableName)
 }
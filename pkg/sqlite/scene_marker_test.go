@@ -67,9 +67,109 @@ func TestMarkerCountByTagID(t *testing.T) {
 	})
 }
 
+func queryMarkers(t *testing.T, sqb models.SceneMarkerReader, markerFilter *models.SceneMarkerFilterType, findFilter *models.FindFilterType) []*models.SceneMarker {
+	t.Helper()
+	result, _, err := sqb.Query(markerFilter, findFilter)
+	if err != nil {
+		t.Errorf("Error querying markers: %v", err)
+	}
+
+	return result
+}
+
+func TestMarkerQueryTagCount(t *testing.T) {
+	const tagCount = 1
+	tagCountCriterion := models.IntCriterionInput{
+		Value:    tagCount,
+		Modifier: models.CriterionModifierEquals,
+	}
+
+	verifyMarkersTagCount(t, tagCountCriterion)
+
+	tagCountCriterion.Value = 0
+	tagCountCriterion.Modifier = models.CriterionModifierGreaterThan
+	verifyMarkersTagCount(t, tagCountCriterion)
+}
+
+func verifyMarkersTagCount(t *testing.T, tagCountCriterion models.IntCriterionInput) {
+	withTxn(func(r models.Repository) error {
+		mqb := r.SceneMarker()
+		markerFilter := models.SceneMarkerFilterType{
+			TagCount: &tagCountCriterion,
+		}
+
+		markers := queryMarkers(t, mqb, &markerFilter, nil)
+		assert.Greater(t, len(markers), 0)
+
+		for _, marker := range markers {
+			ids, err := mqb.GetTagIDs(marker.ID)
+			if err != nil {
+				return err
+			}
+			verifyInt(t, len(ids), tagCountCriterion)
+		}
+
+		return nil
+	})
+}
+
+func TestMarkerQuerySceneFilter(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		mqb := r.SceneMarker()
+
+		sceneFilter := models.SceneFilterType{
+			Path: &models.StringCriterionInput{
+				Value:    getSceneStringValue(sceneIdxWithMarker, "Path"),
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+		markerFilter := models.SceneMarkerFilterType{
+			SceneFilter: &sceneFilter,
+		}
+
+		markers := queryMarkers(t, mqb, &markerFilter, nil)
+		assert.Len(t, markers, 1)
+		if len(markers) > 0 {
+			assert.Equal(t, markerIDs[markerIdxWithScene], markers[0].ID)
+		}
+
+		sceneFilter.Path.Modifier = models.CriterionModifierNotEquals
+		markers = queryMarkers(t, mqb, &markerFilter, nil)
+		assert.Len(t, markers, 0)
+
+		// invalid nested filters should return an error
+		sceneFilter.Path.Modifier = models.CriterionModifierEquals
+		sceneFilter.And = &models.SceneFilterType{}
+		sceneFilter.Or = &models.SceneFilterType{}
+		_, _, err := mqb.Query(&markerFilter, nil)
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
+func TestMarkerQuerySorting(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		mqb := r.SceneMarker()
+
+		for _, sort := range []string{"created_at", "updated_at", "scenes_created_at", "scenes_updated_at"} {
+			s := sort
+			direction := models.SortDirectionEnumDesc
+			findFilter := models.FindFilterType{
+				Sort:      &s,
+				Direction: &direction,
+			}
+
+			markers := queryMarkers(t, mqb, nil, &findFilter)
+			assert.Len(t, markers, 1, "sort = %s", sort)
+		}
+
+		return nil
+	})
+}
+
 // TODO Update
 // TODO Destroy
 // TODO Find
 // TODO GetMarkerStrings
 // TODO Wall
-// TODO Query
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stashapp/stash/pkg/database"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -43,6 +44,124 @@ func TestSceneFind(t *testing.T) {
 	})
 }
 
+func TestSceneFindMany(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		// request in reverse order to ensure that the order is preserved
+		var ids []int
+		for i := len(sceneIDs) - 1; i >= 0; i-- {
+			ids = append(ids, sceneIDs[i])
+		}
+
+		scenes, err := sqb.FindMany(ids)
+		if err != nil {
+			t.Errorf("Error finding scenes: %s", err.Error())
+		}
+
+		assert.Len(t, scenes, len(ids))
+		for i, scene := range scenes {
+			assert.Equal(t, ids[i], scene.ID)
+		}
+
+		// ensure that requests larger than the query batch size are handled
+		var manyIDs []int
+		for len(manyIDs) <= 500 {
+			manyIDs = append(manyIDs, ids...)
+		}
+
+		scenes, err = sqb.FindMany(manyIDs)
+		if err != nil {
+			t.Errorf("Error finding scenes: %s", err.Error())
+		}
+
+		assert.Len(t, scenes, len(manyIDs))
+
+		_, err = sqb.FindMany([]int{sceneIDs[0], 0})
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
+func TestSceneGetManyPerformerIDs(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		ids := []int{
+			sceneIDs[sceneIdxWithTwoPerformers],
+			sceneIDs[sceneIdxWithPerformer],
+			sceneIDs[sceneIdxWithTag],
+		}
+
+		joined, err := r.Scene().GetManyPerformerIDs(ids)
+		if err != nil {
+			t.Errorf("Error getting performer ids: %s", err.Error())
+		}
+
+		assert.Len(t, joined, len(ids))
+		assert.ElementsMatch(t, []int{performerIDs[performerIdx1WithScene], performerIDs[performerIdx2WithScene]}, joined[0])
+		assert.Equal(t, []int{performerIDs[performerIdxWithScene]}, joined[1])
+		assert.Len(t, joined[2], 0)
+
+		return nil
+	})
+}
+
+func TestSceneGetManyTagIDs(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		ids := []int{
+			sceneIDs[sceneIdxWithTwoTags],
+			sceneIDs[sceneIdxWithTag],
+			sceneIDs[sceneIdxWithPerformer],
+		}
+
+		joined, err := r.Scene().GetManyTagIDs(ids)
+		if err != nil {
+			t.Errorf("Error getting tag ids: %s", err.Error())
+		}
+
+		assert.Len(t, joined, len(ids))
+		assert.ElementsMatch(t, []int{tagIDs[tagIdx1WithScene], tagIDs[tagIdx2WithScene]}, joined[0])
+		assert.Equal(t, []int{tagIDs[tagIdxWithScene]}, joined[1])
+		assert.Len(t, joined[2], 0)
+
+		return nil
+	})
+}
+
+func TestSceneQueryIDs(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		studioCriterion := models.MultiCriterionInput{
+			Value: []string{
+				strconv.Itoa(studioIDs[studioIdxWithTwoScenes]),
+			},
+			Modifier: models.CriterionModifierIncludes,
+		}
+		sceneFilter := models.SceneFilterType{
+			Studios: &studioCriterion,
+		}
+
+		scenes, count, err := sqb.Query(&sceneFilter, nil)
+		if err != nil {
+			t.Errorf("Error querying scene: %s", err.Error())
+		}
+
+		ids, idCount, err := sqb.QueryIDs(&sceneFilter, nil)
+		if err != nil {
+			t.Errorf("Error querying scene ids: %s", err.Error())
+		}
+
+		assert.Equal(t, count, idCount)
+		assert.Len(t, ids, len(scenes))
+		for i, scene := range scenes {
+			assert.Equal(t, scene.ID, ids[i])
+		}
+
+		return nil
+	})
+}
+
 func TestSceneFindByPath(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Scene()
@@ -225,6 +344,185 @@ func TestSceneQueryURL(t *testing.T) {
 	verifySceneQuery(t, filter, verifyFn)
 }
 
+func TestSceneQueryLocation(t *testing.T) {
+	const sceneIdx = 1
+	location := getSceneStringValue(sceneIdx, locationField)
+
+	locationCriterion := models.StringCriterionInput{
+		Value:    location,
+		Modifier: models.CriterionModifierEquals,
+	}
+
+	filter := models.SceneFilterType{
+		Location: &locationCriterion,
+	}
+
+	verifyFn := func(s *models.Scene) {
+		t.Helper()
+		verifyNullString(t, s.Location, locationCriterion)
+	}
+
+	verifySceneQuery(t, filter, verifyFn)
+
+	locationCriterion.Modifier = models.CriterionModifierNotEquals
+	verifySceneQuery(t, filter, verifyFn)
+
+	locationCriterion.Modifier = models.CriterionModifierIsNull
+	locationCriterion.Value = ""
+	verifySceneQuery(t, filter, verifyFn)
+
+	locationCriterion.Modifier = models.CriterionModifierNotNull
+	verifySceneQuery(t, filter, verifyFn)
+}
+
+func TestSceneQueryDecodeError(t *testing.T) {
+	const sceneIdx = 1
+	decodeError := getSceneStringValue(sceneIdx, decodeErrorField)
+
+	decodeErrorCriterion := models.StringCriterionInput{
+		Value:    decodeError,
+		Modifier: models.CriterionModifierEquals,
+	}
+
+	filter := models.SceneFilterType{
+		DecodeError: &decodeErrorCriterion,
+	}
+
+	verifyFn := func(s *models.Scene) {
+		t.Helper()
+		verifyNullString(t, s.DecodeError, decodeErrorCriterion)
+	}
+
+	verifySceneQuery(t, filter, verifyFn)
+
+	decodeErrorCriterion.Modifier = models.CriterionModifierIsNull
+	decodeErrorCriterion.Value = ""
+	verifySceneQuery(t, filter, verifyFn)
+
+	decodeErrorCriterion.Modifier = models.CriterionModifierNotNull
+	verifySceneQuery(t, filter, verifyFn)
+}
+
+func TestSceneQueryAudioOnly(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		audioOnly := true
+		sceneFilter := models.SceneFilterType{
+			AudioOnly: &audioOnly,
+		}
+
+		scenes := queryScene(t, sqb, &sceneFilter, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, sceneIDs[sceneIdxAudioOnly], scenes[0].ID)
+			assert.True(t, scenes[0].AudioOnly)
+		}
+
+		audioOnly = false
+		scenes = queryScene(t, sqb, &sceneFilter, nil)
+		assert.Len(t, scenes, totalScenes-1)
+		for _, s := range scenes {
+			assert.False(t, s.AudioOnly)
+		}
+
+		return nil
+	})
+}
+
+func TestSceneQueryInteractive(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		interactive := true
+		sceneFilter := models.SceneFilterType{
+			Interactive: &interactive,
+		}
+
+		scenes := queryScene(t, sqb, &sceneFilter, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, sceneIDs[sceneIdxInteractive], scenes[0].ID)
+			assert.True(t, scenes[0].Interactive)
+		}
+
+		interactive = false
+		scenes = queryScene(t, sqb, &sceneFilter, nil)
+		assert.Len(t, scenes, totalScenes-1)
+		for _, s := range scenes {
+			assert.False(t, s.Interactive)
+		}
+
+		return nil
+	})
+}
+
+func TestSceneQueryInteractiveSpeed(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		sceneFilter := models.SceneFilterType{
+			InteractiveSpeed: &models.IntCriterionInput{
+				Value:    interactiveSpeed - 1,
+				Modifier: models.CriterionModifierGreaterThan,
+			},
+		}
+
+		scenes := queryScene(t, sqb, &sceneFilter, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, sceneIDs[sceneIdxInteractive], scenes[0].ID)
+			assert.Equal(t, int64(interactiveSpeed), scenes[0].InteractiveSpeed.Int64)
+		}
+
+		sceneFilter.InteractiveSpeed.Modifier = models.CriterionModifierIsNull
+		scenes = queryScene(t, sqb, &sceneFilter, nil)
+		assert.Len(t, scenes, totalScenes-1)
+
+		// scenes are sorted by interactive speed
+		sort := "interactive_speed"
+		direction := models.SortDirectionEnumDesc
+		scenes = queryScene(t, sqb, nil, &models.FindFilterType{Sort: &sort, Direction: &direction})
+		if assert.NotEmpty(t, scenes) {
+			assert.Equal(t, sceneIDs[sceneIdxInteractive], scenes[0].ID)
+		}
+
+		return nil
+	})
+}
+
+func TestSceneQueryNearby(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		// scenes with index%3 == 1 are located at 10, 10
+		sceneFilter := models.SceneFilterType{
+			Nearby: &models.GeoRadiusCriterionInput{
+				Latitude:  10.5,
+				Longitude: 10.5,
+				RadiusKm:  100,
+			},
+		}
+
+		scenes := queryScene(t, sqb, &sceneFilter, nil)
+		assert.Greater(t, len(scenes), 0)
+
+		for _, scene := range scenes {
+			assert.Equal(t, float64(10), scene.Latitude.Float64)
+			assert.Equal(t, float64(10), scene.Longitude.Float64)
+		}
+
+		// no scenes within 10km
+		sceneFilter.Nearby.RadiusKm = 10
+		scenes = queryScene(t, sqb, &sceneFilter, nil)
+		assert.Len(t, scenes, 0)
+
+		// invalid coordinates
+		sceneFilter.Nearby.Latitude = 91
+		_, _, err := sqb.Query(&sceneFilter, nil)
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
 func TestSceneQueryPathOr(t *testing.T) {
 	const scene1Idx = 1
 	const scene2Idx = 2
@@ -510,6 +808,12 @@ func verifyInt64(t *testing.T, value sql.NullInt64, criterion models.IntCriterio
 	if criterion.Modifier == models.CriterionModifierLessThan {
 		assert.True(value.Int64 < int64(criterion.Value))
 	}
+	if criterion.Modifier == models.CriterionModifierBetween {
+		assert.True(value.Int64 >= int64(criterion.Value) && value.Int64 <= int64(*criterion.Value2))
+	}
+	if criterion.Modifier == models.CriterionModifierNotBetween {
+		assert.True(value.Int64 < int64(criterion.Value) || value.Int64 > int64(*criterion.Value2))
+	}
 }
 
 func TestSceneQueryOCounter(t *testing.T) {
@@ -563,6 +867,13 @@ func verifyInt(t *testing.T, value int, criterion models.IntCriterionInput) {
 	if criterion.Modifier == models.CriterionModifierLessThan {
 		assert.Less(value, criterion.Value)
 	}
+	if criterion.Modifier == models.CriterionModifierBetween {
+		assert.GreaterOrEqual(value, criterion.Value)
+		assert.LessOrEqual(value, *criterion.Value2)
+	}
+	if criterion.Modifier == models.CriterionModifierNotBetween {
+		assert.True(value < criterion.Value || value > *criterion.Value2)
+	}
 }
 
 func TestSceneQueryDuration(t *testing.T) {
@@ -880,6 +1191,125 @@ func TestSceneQueryIsMissingTags(t *testing.T) {
 	})
 }
 
+func verifySceneIsMissing(t *testing.T, sqb models.SceneReader, isMissing string, sceneIdx int, expected bool) {
+	t.Helper()
+	sceneFilter := models.SceneFilterType{
+		IsMissing: &isMissing,
+	}
+
+	q := getSceneStringValue(sceneIdx, titleField)
+	findFilter := models.FindFilterType{
+		Q: &q,
+	}
+
+	scenes := queryScene(t, sqb, &sceneFilter, &findFilter)
+
+	var ids []int
+	for _, scene := range scenes {
+		ids = append(ids, scene.ID)
+	}
+
+	if expected {
+		assert.Contains(t, ids, sceneIDs[sceneIdx])
+	} else {
+		assert.NotContains(t, ids, sceneIDs[sceneIdx])
+	}
+}
+
+func TestSceneQueryIsMissingPhash(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		const sceneIdx = sceneIdxWithTwoTags
+		sceneID := sceneIDs[sceneIdx]
+
+		verifySceneIsMissing(t, sqb, "phash", sceneIdx, true)
+
+		phash := sql.NullInt64{Int64: 1, Valid: true}
+		if _, err := sqb.Update(models.ScenePartial{
+			ID:    sceneID,
+			Phash: &phash,
+		}); err != nil {
+			return err
+		}
+
+		verifySceneIsMissing(t, sqb, "phash", sceneIdx, false)
+
+		// reset the phash
+		phash = sql.NullInt64{}
+		_, err := sqb.Update(models.ScenePartial{
+			ID:    sceneID,
+			Phash: &phash,
+		})
+		return err
+	})
+}
+
+func TestSceneQueryIsMissingCover(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		const sceneIdx = sceneIdxWithTwoTags
+		sceneID := sceneIDs[sceneIdx]
+
+		verifySceneIsMissing(t, sqb, "cover", sceneIdx, true)
+
+		if err := sqb.UpdateCover(sceneID, []byte("cover")); err != nil {
+			return err
+		}
+
+		verifySceneIsMissing(t, sqb, "cover", sceneIdx, false)
+
+		return sqb.DestroyCover(sceneID)
+	})
+}
+
+func TestSceneQueryIsMissingGeneratedFiles(t *testing.T) {
+	const sceneIdx = sceneIdxWithTwoTags
+	checksum := getSceneStringValue(sceneIdx, checksumField)
+
+	database.GeneratedFileExistsFunc = func(fileType string, c string, oshash string) bool {
+		return fileType == "preview" && c == checksum
+	}
+	defer func() {
+		database.GeneratedFileExistsFunc = nil
+	}()
+
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		verifySceneIsMissing(t, sqb, "preview", sceneIdx, false)
+		verifySceneIsMissing(t, sqb, "preview", sceneIdxWithGallery, true)
+		verifySceneIsMissing(t, sqb, "sprite", sceneIdx, true)
+
+		return nil
+	})
+}
+
+func TestSceneQueryIsMissingHeatmap(t *testing.T) {
+	hasHeatmap := false
+	checksum := getSceneStringValue(sceneIdxInteractive, checksumField)
+
+	database.GeneratedFileExistsFunc = func(fileType string, c string, oshash string) bool {
+		return hasHeatmap && fileType == "heatmap" && c == checksum
+	}
+	defer func() {
+		database.GeneratedFileExistsFunc = nil
+	}()
+
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+
+		verifySceneIsMissing(t, sqb, "heatmap", sceneIdxInteractive, true)
+
+		// scenes which are not interactive have no heatmap to generate
+		verifySceneIsMissing(t, sqb, "heatmap", sceneIdxWithGallery, false)
+
+		hasHeatmap = true
+		verifySceneIsMissing(t, sqb, "heatmap", sceneIdxInteractive, false)
+
+		return nil
+	})
+}
+
 func TestSceneQueryIsMissingRating(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Scene()
@@ -901,6 +1331,29 @@ func TestSceneQueryIsMissingRating(t *testing.T) {
 	})
 }
 
+func TestSceneQueryAppearsWith(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		appearsWithCriterion := models.MultiCriterionInput{
+			Value: []string{
+				strconv.Itoa(performerIDs[performerIdx1WithScene]),
+			},
+			Modifier: models.CriterionModifierIncludes,
+		}
+
+		sceneFilter := models.SceneFilterType{
+			AppearsWith: &appearsWithCriterion,
+		}
+
+		scenes := queryScene(t, sqb, &sceneFilter, nil)
+
+		assert.Len(t, scenes, 1)
+		assert.Equal(t, sceneIDs[sceneIdxWithTwoPerformers], scenes[0].ID)
+
+		return nil
+	})
+}
+
 func TestSceneQueryPerformers(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Scene()
@@ -1368,6 +1821,43 @@ func TestSceneCountByStudioID(t *testing.T) {
 	})
 }
 
+func TestSceneClearMissingStudios(t *testing.T) {
+	const missingID = 999999
+	sceneID := sceneIDs[sceneIdxWithGallery]
+
+	if err := execWithoutForeignKeys(
+		fmt.Sprintf("UPDATE scenes SET studio_id = %d WHERE id = %d", missingID, sceneID),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		updated, err := sqb.ClearMissingStudios()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, updated)
+
+		s, err := sqb.Find(sceneID)
+		if err != nil {
+			return err
+		}
+		assert.False(t, s.StudioID.Valid)
+
+		// scenes of existing studios are unchanged
+		sceneCount, err := sqb.CountByStudioID(studioIDs[studioIdxWithScene])
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, 1, sceneCount)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestFindByMovieID(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		sqb := r.Scene()
@@ -1512,7 +2002,112 @@ func TestSceneStashIDs(t *testing.T) {
 			return fmt.Errorf("Error creating scene: %s", err.Error())
 		}
 
-		testStashIDReaderWriter(t, qb, created.ID)
+		testStashIDReaderWriter(t, qb, created.ID, func(stashID models.StashID) ([]int, error) {
+			perPage := -1
+			scenes, _, err := qb.Query(&models.SceneFilterType{
+				StashID: &models.StringCriterionInput{
+					Value:    stashID.StashID,
+					Modifier: models.CriterionModifierEquals,
+				},
+				StashIDEndpoint: &models.StringCriterionInput{
+					Value:    stashID.Endpoint,
+					Modifier: models.CriterionModifierEquals,
+				},
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, o := range scenes {
+				ids = append(ids, o.ID)
+			}
+			return ids, nil
+		})
+
+		testStashIDEndpointCriterion(t, qb, created.ID, func(c models.StringCriterionInput) ([]int, error) {
+			perPage := -1
+			scenes, _, err := qb.Query(&models.SceneFilterType{
+				StashIDEndpoint: &c,
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, o := range scenes {
+				ids = append(ids, o.ID)
+			}
+			return ids, nil
+		})
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneCaptions(t *testing.T) {
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.Scene()
+
+		// create scene to test against
+		const name = "TestSceneCaptions"
+		scene := models.Scene{
+			Path:     name,
+			Checksum: sql.NullString{String: utils.MD5FromString(name), Valid: true},
+		}
+		created, err := qb.Create(scene)
+		if err != nil {
+			return fmt.Errorf("Error creating scene: %s", err.Error())
+		}
+
+		captions := []*models.SceneCaption{
+			{
+				StreamIndex:  sql.NullInt64{Int64: 2, Valid: true},
+				LanguageCode: "eng",
+				Title:        "English",
+				Codec:        "subrip",
+			},
+			{
+				Path:  name + ".vtt",
+				Codec: "webvtt",
+			},
+		}
+		if err := qb.UpdateCaptions(created.ID, captions); err != nil {
+			return fmt.Errorf("Error updating scene captions: %s", err.Error())
+		}
+
+		stored, err := qb.GetCaptions(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene captions: %s", err.Error())
+		}
+
+		if assert.Len(t, stored, 2) {
+			for i, c := range stored {
+				assert.Equal(t, created.ID, c.SceneID)
+				assert.NotZero(t, c.ID)
+
+				c.ID = 0
+				c.SceneID = 0
+				assert.Equal(t, captions[i], c)
+			}
+		}
+
+		// captions are removed with the scene
+		if err := qb.Destroy(created.ID); err != nil {
+			return fmt.Errorf("Error destroying scene: %s", err.Error())
+		}
+
+		stored, err = qb.GetCaptions(created.ID)
+		if err != nil {
+			return fmt.Errorf("Error getting scene captions: %s", err.Error())
+		}
+		assert.Len(t, stored, 0)
+
 		return nil
 	}); err != nil {
 		t.Error(err.Error())
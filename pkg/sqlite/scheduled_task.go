@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const scheduledTaskTable = "scheduled_tasks"
+
+type scheduledTaskQueryBuilder struct {
+	repository
+}
+
+func NewScheduledTaskReaderWriter(tx dbi) *scheduledTaskQueryBuilder {
+	return &scheduledTaskQueryBuilder{
+		repository{
+			tx:        tx,
+			tableName: scheduledTaskTable,
+			idColumn:  idColumn,
+		},
+	}
+}
+
+func (qb *scheduledTaskQueryBuilder) Create(newObject models.ScheduledTask) (*models.ScheduledTask, error) {
+	var ret models.ScheduledTask
+	if err := qb.insertObject(newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *scheduledTaskQueryBuilder) Update(updatedObject models.ScheduledTask) (*models.ScheduledTask, error) {
+	const partial = false
+	if err := qb.update(updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	return qb.Find(updatedObject.ID)
+}
+
+func (qb *scheduledTaskQueryBuilder) UpdateLastRunAt(id int, lastRunAt time.Time) error {
+	return qb.updateMap(id, map[string]interface{}{
+		"id":          id,
+		"last_run_at": models.SQLiteTimestamp{Timestamp: lastRunAt},
+	})
+}
+
+func (qb *scheduledTaskQueryBuilder) Destroy(id int) error {
+	return qb.destroyExisting([]int{id})
+}
+
+func (qb *scheduledTaskQueryBuilder) Find(id int) (*models.ScheduledTask, error) {
+	var ret models.ScheduledTask
+	if err := qb.get(id, &ret); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *scheduledTaskQueryBuilder) All() ([]*models.ScheduledTask, error) {
+	var ret models.ScheduledTasks
+	if err := qb.query(selectAll(scheduledTaskTable)+"ORDER BY name ASC, id ASC", nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.ScheduledTask(ret), nil
+}
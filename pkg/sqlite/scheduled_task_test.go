@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledTaskCRUD(t *testing.T) {
+	const name = "TestScheduledTaskCRUD"
+
+	// timestamps are stored to the second
+	lastRunAt := time.Now().Truncate(time.Second)
+
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.ScheduledTask()
+		created, err := qb.Create(models.ScheduledTask{
+			Name:     name,
+			Task:     models.ScheduledTaskTypeScan,
+			Schedule: "@daily",
+			Enabled:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		created.Schedule = "0 3 * * *"
+		created.Enabled = false
+		updated, err := qb.Update(*created)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "0 3 * * *", updated.Schedule)
+		assert.False(t, updated.Enabled)
+		assert.False(t, updated.LastRunAt.Valid)
+
+		if err := qb.UpdateLastRunAt(created.ID, lastRunAt); err != nil {
+			return err
+		}
+
+		found, err := qb.Find(created.ID)
+		if err != nil {
+			return err
+		}
+		assert.True(t, found.LastRunAt.Valid)
+		assert.True(t, lastRunAt.Equal(found.LastRunAt.Timestamp))
+
+		all, err := qb.All()
+		if err != nil {
+			return err
+		}
+		assert.Contains(t, all, found)
+
+		if err := qb.Destroy(created.ID); err != nil {
+			return err
+		}
+
+		found, err = qb.Find(created.ID)
+		if err != nil {
+			return err
+		}
+		assert.Nil(t, found)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
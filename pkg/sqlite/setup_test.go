@@ -5,6 +5,7 @@ package sqlite_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -22,6 +23,7 @@ import (
 
 const (
 	spacedSceneTitle = "zzz yyy xxx"
+	interactiveSpeed = 150
 )
 
 const (
@@ -40,6 +42,8 @@ const (
 	sceneIdxWithPerformerTag
 	sceneIdxWithPerformerTwoTags
 	sceneIdxWithSpacedName
+	sceneIdxAudioOnly
+	sceneIdxInteractive
 	// new indexes above
 	lastSceneIdx
 
@@ -94,9 +98,10 @@ const (
 const (
 	movieIdxWithScene = iota
 	movieIdxWithStudio
+	movieIdxWithPerformer
 	// movies with dup names start from the end
 	// create 10 more basic movies (can remove this if we add more indexes)
-	movieIdxWithDupName = movieIdxWithStudio + 10
+	movieIdxWithDupName = movieIdxWithPerformer + 10
 
 	moviesNameCase   = movieIdxWithDupName
 	moviesNameNoCase = 1
@@ -177,7 +182,12 @@ const (
 	checksumField = "Checksum"
 	titleField    = "Title"
 	urlField      = "URL"
-	zipPath       = "zipPath.zip"
+	locationField = "Location"
+
+	decodeErrorField = "DecodeError"
+	cameraMakeField  = "CameraMake"
+	cameraModelField = "CameraModel"
+	zipPath          = "zipPath.zip"
 )
 
 var (
@@ -224,6 +234,7 @@ var (
 
 	sceneMovieLinks = [][2]int{
 		{sceneIdxWithMovie, movieIdxWithScene},
+		{sceneIdxWithTwoPerformers, movieIdxWithPerformer},
 	}
 
 	sceneStudioLinks = [][2]int{
@@ -312,10 +323,53 @@ func TestMain(m *testing.M) {
 }
 
 func withTxn(f func(r models.Repository) error) error {
-	t := sqlite.NewTransactionManager()
+	t := sqlite.NewTransactionManager(false)
 	return t.WithTxn(context.TODO(), f)
 }
 
+var errRollback = errors.New("rollback")
+
+// withRollbackTxn calls f in a transaction which is rolled back, so that
+// the objects it creates do not affect other tests.
+func withRollbackTxn(f func(r models.Repository) error) error {
+	err := withTxn(func(r models.Repository) error {
+		if err := f(r); err != nil {
+			return err
+		}
+		return errRollback
+	})
+
+	if err == errRollback {
+		return nil
+	}
+	return err
+}
+
+// execWithoutForeignKeys runs the statements with foreign key checks
+// disabled, leaving behind the broken references that manual changes to the
+// database may cause.
+func execWithoutForeignKeys(stmts ...string) error {
+	ctx := context.TODO()
+	conn, err := database.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func testTeardown(databaseFile string) {
 	err := database.DB.Close()
 
@@ -504,6 +558,13 @@ func getOCounter(index int) int {
 	return index % 3
 }
 
+func getCoordinates(index int) (sql.NullFloat64, sql.NullFloat64) {
+	v := float64(index%3) * 10
+	valid := index%3 > 0
+
+	return sql.NullFloat64{Float64: v, Valid: valid}, sql.NullFloat64{Float64: v, Valid: valid}
+}
+
 func getSceneDuration(index int) sql.NullFloat64 {
 	duration := index % 4
 	duration = duration * 100
@@ -542,17 +603,28 @@ func getSceneDate(index int) models.SQLiteDate {
 
 func createScenes(sqb models.SceneReaderWriter, n int) error {
 	for i := 0; i < n; i++ {
+		latitude, longitude := getCoordinates(i)
 		scene := models.Scene{
-			Path:     getSceneStringValue(i, pathField),
-			Title:    sql.NullString{String: getSceneTitle(i), Valid: true},
-			Checksum: sql.NullString{String: getSceneStringValue(i, checksumField), Valid: true},
-			Details:  sql.NullString{String: getSceneStringValue(i, "Details"), Valid: true},
-			URL:      getSceneNullStringValue(i, urlField),
-			Rating:   getRating(i),
-			OCounter: getOCounter(i),
-			Duration: getSceneDuration(i),
-			Height:   getHeight(i),
-			Date:     getSceneDate(i),
+			Path:      getSceneStringValue(i, pathField),
+			Title:     sql.NullString{String: getSceneTitle(i), Valid: true},
+			Checksum:  sql.NullString{String: getSceneStringValue(i, checksumField), Valid: true},
+			Details:   sql.NullString{String: getSceneStringValue(i, "Details"), Valid: true},
+			URL:       getSceneNullStringValue(i, urlField),
+			Rating:    getRating(i),
+			OCounter:  getOCounter(i),
+			Duration:  getSceneDuration(i),
+			Height:    getHeight(i),
+			Date:      getSceneDate(i),
+			Location:  getSceneNullStringValue(i, locationField),
+			Latitude:  latitude,
+			Longitude: longitude,
+
+			DecodeError: getSceneNullStringValue(i, decodeErrorField),
+			AudioOnly:   i == sceneIdxAudioOnly,
+			Interactive: i == sceneIdxInteractive,
+		}
+		if i == sceneIdxInteractive {
+			scene.InteractiveSpeed = sql.NullInt64{Int64: interactiveSpeed, Valid: true}
 		}
 
 		created, err := sqb.Create(scene)
@@ -580,16 +652,41 @@ func getImagePath(index int) string {
 	return getImageStringValue(index, pathField)
 }
 
+func getImageTakenAt(index int) models.NullSQLiteTimestamp {
+	var t time.Time
+	switch index % 4 {
+	case 1:
+		t = time.Date(2001, 2, 3, 0, 30, 0, 0, time.Local)
+	case 2:
+		// stored in another offset
+		t = time.Date(2001, 2, 3, 23, 30, 0, 0, time.Local).In(time.FixedZone("", 10*60*60))
+	case 3:
+		t = time.Date(2005, 6, 7, 12, 0, 0, 0, time.Local)
+	}
+
+	return models.NullSQLiteTimestamp{
+		Timestamp: t,
+		Valid:     !t.IsZero(),
+	}
+}
+
 func createImages(qb models.ImageReaderWriter, n int) error {
 	for i := 0; i < n; i++ {
+		latitude, longitude := getCoordinates(i)
 		image := models.Image{
-			Path:     getImagePath(i),
-			Title:    sql.NullString{String: getImageStringValue(i, titleField), Valid: true},
-			Checksum: getImageStringValue(i, checksumField),
-			Rating:   getRating(i),
-			OCounter: getOCounter(i),
-			Height:   getHeight(i),
-			Width:    getWidth(i),
+			Path:      getImagePath(i),
+			Title:     sql.NullString{String: getImageStringValue(i, titleField), Valid: true},
+			Checksum:  getImageStringValue(i, checksumField),
+			Rating:    getRating(i),
+			OCounter:  getOCounter(i),
+			Height:    getHeight(i),
+			Width:     getWidth(i),
+			Latitude:  latitude,
+			Longitude: longitude,
+
+			TakenAt:     getImageTakenAt(i),
+			CameraMake:  getPrefixedNullStringValue("image", i, cameraMakeField),
+			CameraModel: getPrefixedNullStringValue("image", i, cameraModelField),
 		}
 
 		created, err := qb.Create(image)
@@ -612,6 +709,14 @@ func getGalleryNullStringValue(index int, field string) sql.NullString {
 	return getPrefixedNullStringValue("gallery", index, field)
 }
 
+func getGalleryDate() models.SQLiteDate {
+	// performer ages are relative to the current date
+	return models.SQLiteDate{
+		String: time.Now().Format("2006-01-02"),
+		Valid:  true,
+	}
+}
+
 func createGalleries(gqb models.GalleryReaderWriter, n int) error {
 	for i := 0; i < n; i++ {
 		gallery := models.Gallery{
@@ -619,6 +724,7 @@ func createGalleries(gqb models.GalleryReaderWriter, n int) error {
 			URL:      getGalleryNullStringValue(i, urlField),
 			Checksum: getGalleryStringValue(i, checksumField),
 			Rating:   getRating(i),
+			Date:     getGalleryDate(),
 		}
 
 		created, err := gqb.Create(gallery)
@@ -718,6 +824,42 @@ func getPerformerCareerLength(index int) *string {
 	return &ret
 }
 
+func getPerformerHeight(index int) sql.NullInt64 {
+	height := index % 5
+	if height == 0 {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(150 + height*10), Valid: true}
+}
+
+func getPerformerGender(index int) sql.NullString {
+	genders := []models.GenderEnum{
+		models.GenderEnumMale,
+		models.GenderEnumFemale,
+		models.GenderEnumTransgenderFemale,
+	}
+
+	// every fourth performer has no gender
+	if index%4 == 3 {
+		return sql.NullString{}
+	}
+
+	return models.NullString(genders[index%4].String())
+}
+
+// getCountry returns the country for the performer or studio index. Every
+// third object has no country.
+func getCountry(index int) sql.NullString {
+	countries := []string{"US", "GB"}
+
+	if index%3 == 2 {
+		return sql.NullString{}
+	}
+
+	return models.NullString(countries[index%3])
+}
+
 // createPerformers creates n performers with plain Name and o performers with camel cased NaMe included
 func createPerformers(pqb models.PerformerReaderWriter, n int, o int) error {
 	const namePlain = "Name"
@@ -745,6 +887,9 @@ func createPerformers(pqb models.PerformerReaderWriter, n int, o int) error {
 			},
 			DeathDate: getPerformerDeathDate(i),
 			Details:   sql.NullString{String: getPerformerStringValue(i, "Details"), Valid: true},
+			Height:    getPerformerHeight(i),
+			Gender:    getPerformerGender(i),
+			Country:   getCountry(i),
 		}
 
 		careerLength := getPerformerCareerLength(i)
@@ -809,7 +954,7 @@ func getTagPerformerCount(id int) int {
 	return 0
 }
 
-//createTags creates n tags with plain Name and o tags with camel cased NaMe included
+// createTags creates n tags with plain Name and o tags with camel cased NaMe included
 func createTags(tqb models.TagReaderWriter, n int, o int) error {
 	const namePlain = "Name"
 	const nameNoCase = "NaMe"
@@ -893,6 +1038,7 @@ func createStudios(sqb models.StudioReaderWriter, n int, o int) error {
 			Name:     sql.NullString{String: name, Valid: true},
 			Checksum: utils.MD5FromString(name),
 			URL:      getStudioNullStringValue(index, urlField),
+			Country:  getCountry(i),
 		}
 		created, err := createStudioFromModel(sqb, studio)
 
@@ -1,7 +1,6 @@
 package sqlite
 
 import (
-	"database/sql"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -15,6 +14,10 @@ import (
 
 var randomSortFloat = rand.Float64()
 
+// autoTagRegexClause matches the objects with a custom auto-tag regex, which
+// are always auto-tag candidates.
+const autoTagRegexClause = "(auto_tag_regex IS NOT NULL AND auto_tag_regex != '')"
+
 func selectAll(tableName string) string {
 	idColumn := getColumn(tableName, "*")
 	return "SELECT " + idColumn + " FROM " + tableName + " "
@@ -199,6 +202,26 @@ func getSimpleCriterionClause(criterionModifier models.CriterionModifier, rhs st
 }
 
 func getIntCriterionWhereClause(column string, input models.IntCriterionInput) (string, int) {
+	if input.Modifier == models.CriterionModifierBetween || input.Modifier == models.CriterionModifierNotBetween {
+		// values are integers, so they are safe to include in the clause
+		// directly. This keeps the single argument contract for callers.
+		lower := input.Value
+		upper := input.Value
+		if input.Value2 != nil {
+			upper = *input.Value2
+		}
+		if lower > upper {
+			lower, upper = upper, lower
+		}
+
+		not := ""
+		if input.Modifier == models.CriterionModifierNotBetween {
+			not = "NOT "
+		}
+
+		return fmt.Sprintf("%s %sBETWEEN %d AND %d", column, not, lower, upper), 0
+	}
+
 	binding, count := getCriterionModifierBinding(input.Modifier, input.Value)
 	return column + " " + binding, count
 }
@@ -226,6 +249,27 @@ func getMultiCriterionClause(primaryTable, foreignTable, joinTable, primaryFK, f
 	return whereClause, havingClause
 }
 
+// getStashIDEndpointCriterionClause returns a clause filtering the primary
+// table by the endpoints of its stash IDs. Equals and not equals match
+// objects with and without a stash ID for the endpoint, while is null and
+// not null match objects without any and with any stash ID.
+func getStashIDEndpointCriterionClause(primaryTable, joinTable, primaryFK string, criterion models.StringCriterionInput) (string, []interface{}, error) {
+	exists := fmt.Sprintf("EXISTS (SELECT 1 FROM %s s WHERE s.%s = %s.id", joinTable, primaryFK, primaryTable)
+
+	switch criterion.Modifier {
+	case models.CriterionModifierEquals:
+		return exists + " AND s.endpoint = ?)", []interface{}{criterion.Value}, nil
+	case models.CriterionModifierNotEquals:
+		return "NOT " + exists + " AND s.endpoint = ?)", []interface{}{criterion.Value}, nil
+	case models.CriterionModifierIsNull:
+		return "NOT " + exists + ")", nil, nil
+	case models.CriterionModifierNotNull:
+		return exists + ")", nil, nil
+	}
+
+	return "", nil, fmt.Errorf("unsupported modifier %s for stash id endpoint", criterion.Modifier)
+}
+
 func getCountCriterionClause(primaryTable, joinTable, primaryFK string, criterion models.IntCriterionInput) (string, int) {
 	lhs := fmt.Sprintf("(SELECT COUNT(*) FROM %s s WHERE s.%s = %s.id)", joinTable, primaryFK, primaryTable)
 	return getIntCriterionWhereClause(lhs, criterion)
@@ -236,25 +280,3 @@ func ensureTx(tx *sqlx.Tx) {
 		panic("must use a transaction")
 	}
 }
-
-func getImage(tx dbi, query string, args ...interface{}) ([]byte, error) {
-	rows, err := tx.Queryx(query, args...)
-
-	if err != nil && err != sql.ErrNoRows {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var ret []byte
-	if rows.Next() {
-		if err := rows.Scan(&ret); err != nil {
-			return nil, err
-		}
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return ret, nil
-}
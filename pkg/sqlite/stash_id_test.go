@@ -12,7 +12,10 @@ type stashIDReaderWriter interface {
 	UpdateStashIDs(performerID int, stashIDs []models.StashID) error
 }
 
-func testStashIDReaderWriter(t *testing.T, r stashIDReaderWriter, id int) {
+// findByStashIDFunc returns the IDs of the objects with the stash ID
+type findByStashIDFunc func(stashID models.StashID) ([]int, error)
+
+func testStashIDReaderWriter(t *testing.T, r stashIDReaderWriter, id int, findByStashID findByStashIDFunc) {
 	// ensure no stash IDs to begin with
 	testNoStashIDs(t, r, id)
 
@@ -33,6 +36,13 @@ func testStashIDReaderWriter(t *testing.T, r stashIDReaderWriter, id int) {
 	}
 
 	testStashIDs(t, r, id, []*models.StashID{&stashID})
+	testFindByStashID(t, findByStashID, stashID, []int{id})
+
+	// stash id for a different endpoint should not match
+	testFindByStashID(t, findByStashID, models.StashID{
+		StashID:  stashIDStr,
+		Endpoint: "otherEndpoint",
+	}, nil)
 
 	// update non-existing id - should return error
 	if err := r.UpdateStashIDs(-1, []models.StashID{stashID}); err == nil {
@@ -45,6 +55,82 @@ func testStashIDReaderWriter(t *testing.T, r stashIDReaderWriter, id int) {
 	}
 
 	testNoStashIDs(t, r, id)
+	testFindByStashID(t, findByStashID, stashID, nil)
+}
+
+// queryByStashIDEndpointFunc returns the IDs of the objects matching the
+// stash id endpoint criterion
+type queryByStashIDEndpointFunc func(c models.StringCriterionInput) ([]int, error)
+
+func testStashIDEndpointCriterion(t *testing.T, r stashIDReaderWriter, id int, query queryByStashIDEndpointFunc) {
+	const endpoint = "endpoint"
+	const otherEndpoint = "otherEndpoint"
+
+	verify := func(modifier models.CriterionModifier, value string, expected bool) {
+		t.Helper()
+		ids, err := query(models.StringCriterionInput{
+			Value:    value,
+			Modifier: modifier,
+		})
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		if expected {
+			assert.Contains(t, ids, id, "%s %s", modifier, value)
+		} else {
+			assert.NotContains(t, ids, id, "%s %s", modifier, value)
+		}
+	}
+
+	// ensure no stash IDs to begin with
+	testNoStashIDs(t, r, id)
+
+	verify(models.CriterionModifierIsNull, "", true)
+	verify(models.CriterionModifierNotNull, "", false)
+	verify(models.CriterionModifierEquals, endpoint, false)
+	verify(models.CriterionModifierNotEquals, endpoint, true)
+
+	if err := r.UpdateStashIDs(id, []models.StashID{
+		{
+			StashID:  "stashID",
+			Endpoint: endpoint,
+		},
+	}); err != nil {
+		t.Error(err.Error())
+	}
+
+	verify(models.CriterionModifierIsNull, "", false)
+	verify(models.CriterionModifierNotNull, "", true)
+	verify(models.CriterionModifierEquals, endpoint, true)
+	verify(models.CriterionModifierNotEquals, endpoint, false)
+	verify(models.CriterionModifierEquals, otherEndpoint, false)
+	verify(models.CriterionModifierNotEquals, otherEndpoint, true)
+
+	// unsupported modifiers should return an error
+	if _, err := query(models.StringCriterionInput{
+		Value:    endpoint,
+		Modifier: models.CriterionModifierIncludes,
+	}); err == nil {
+		t.Error("expected error for unsupported modifier")
+	}
+
+	// remove stash ids
+	if err := r.UpdateStashIDs(id, []models.StashID{}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func testFindByStashID(t *testing.T, findByStashID findByStashIDFunc, stashID models.StashID, expected []int) {
+	t.Helper()
+	ids, err := findByStashID(stashID)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	assert.Equal(t, expected, ids)
 }
 
 func testNoStashIDs(t *testing.T, r stashIDReaderWriter, id int) {
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// querySceneStats returns the scene totals for each object in the
+// repository's table with at least one scene, ordered by scene count. The
+// joins must join the table to the scenes table. All objects are returned
+// if limit is negative.
+func (r *repository) querySceneStats(joins string, limit int) ([]*models.StatsAggregate, error) {
+	query := fmt.Sprintf(`SELECT %[1]s.id, %[1]s.name,
+	COUNT(DISTINCT scenes.id) as scene_count,
+	COALESCE(SUM(cast(scenes.size as double)), 0) as scenes_size,
+	COALESCE(SUM(scenes.duration), 0) as scenes_duration
+FROM %[1]s
+%[2]s
+GROUP BY %[1]s.id
+ORDER BY scene_count DESC, %[1]s.name ASC`, r.tableName, joins)
+
+	var args []interface{}
+	if limit >= 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	var ret []*models.StatsAggregate
+	if err := r.queryFunc(query, args, func(rows *sqlx.Rows) error {
+		var id int
+		var name sql.NullString
+		var stats models.StatsAggregate
+		if err := rows.Scan(&id, &name, &stats.SceneCount, &stats.ScenesSize, &stats.ScenesDuration); err != nil {
+			return err
+		}
+
+		stats.ID = strconv.Itoa(id)
+		stats.Name = name.String
+		ret = append(ret, &stats)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
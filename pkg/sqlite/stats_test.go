@@ -0,0 +1,95 @@
+// +build integration
+
+package sqlite_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// verifySceneStats ensures that the stats are ordered by scene count and that
+// the scene count of each object matches countFn.
+func verifySceneStats(t *testing.T, statsFn func(limit int) ([]*models.StatsAggregate, error), countFn func(id int) (int, error)) {
+	t.Helper()
+	stats, err := statsFn(-1)
+	if err != nil {
+		t.Errorf("Error getting scene stats: %s", err.Error())
+		return
+	}
+
+	assert.Greater(t, len(stats), 0)
+
+	for i, s := range stats {
+		id, _ := strconv.Atoi(s.ID)
+		count, err := countFn(id)
+		if err != nil {
+			t.Errorf("Error counting scenes: %s", err.Error())
+			return
+		}
+
+		assert.Equal(t, count, s.SceneCount)
+		assert.Greater(t, s.SceneCount, 0)
+		assert.GreaterOrEqual(t, s.ScenesSize, float64(0))
+		assert.GreaterOrEqual(t, s.ScenesDuration, float64(0))
+
+		if i > 0 {
+			assert.LessOrEqual(t, s.SceneCount, stats[i-1].SceneCount)
+		}
+	}
+
+	limited, err := statsFn(1)
+	if err != nil {
+		t.Errorf("Error getting scene stats: %s", err.Error())
+		return
+	}
+
+	assert.Len(t, limited, 1)
+	assert.Equal(t, stats[0], limited[0])
+}
+
+func TestStudioSceneStats(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		verifySceneStats(t, r.Studio().SceneStats, r.Scene().CountByStudioID)
+		return nil
+	})
+}
+
+func TestTagSceneStats(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		verifySceneStats(t, r.Tag().SceneStats, r.Scene().CountByTagID)
+		return nil
+	})
+}
+
+func TestPerformerSceneStats(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		verifySceneStats(t, r.Performer().SceneStats, r.Scene().CountByPerformerID)
+		return nil
+	})
+}
+
+func TestSceneDuration(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		sqb := r.Scene()
+		duration, err := sqb.Duration()
+		if err != nil {
+			t.Errorf("Error getting scene duration: %s", err.Error())
+		}
+
+		scenes, err := sqb.All()
+		if err != nil {
+			t.Errorf("Error getting scenes: %s", err.Error())
+		}
+
+		var expected float64
+		for _, s := range scenes {
+			expected += s.Duration.Float64
+		}
+
+		assert.InDelta(t, expected, duration, 0.001)
+		return nil
+	})
+}
@@ -60,6 +60,11 @@ func (qb *studioQueryBuilder) Destroy(id int) error {
 		return err
 	}
 
+	// delete the image blob if no longer used
+	if err := qb.DestroyImage(id); err != nil {
+		return err
+	}
+
 	return qb.destroyExisting([]int{id})
 }
 
@@ -75,21 +80,28 @@ func (qb *studioQueryBuilder) Find(id int) (*models.Studio, error) {
 }
 
 func (qb *studioQueryBuilder) FindMany(ids []int) ([]*models.Studio, error) {
-	var studios []*models.Studio
-	for _, id := range ids {
-		studio, err := qb.Find(id)
-		if err != nil {
-			return nil, err
-		}
+	var studios models.Studios
+	if err := qb.queryByIDs(ids, &studios); err != nil {
+		return nil, err
+	}
 
-		if studio == nil {
+	byID := make(map[int]*models.Studio)
+	for _, o := range studios {
+		byID[o.ID] = o
+	}
+
+	// return in the same order as the provided ids
+	var ret []*models.Studio
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
 			return nil, fmt.Errorf("studio with id %d not found", id)
 		}
 
-		studios = append(studios, studio)
+		ret = append(ret, o)
 	}
 
-	return studios, nil
+	return ret, nil
 }
 
 func (qb *studioQueryBuilder) FindChildren(id int) ([]*models.Studio, error) {
@@ -114,10 +126,28 @@ func (qb *studioQueryBuilder) FindByName(name string, nocase bool) (*models.Stud
 	return qb.queryStudio(query, args)
 }
 
+func (qb *studioQueryBuilder) FindByNames(names []string, nocase bool) ([]*models.Studio, error) {
+	query := "SELECT * FROM studios WHERE name"
+	if nocase {
+		query += " COLLATE NOCASE"
+	}
+	query += " IN " + getInBinding(len(names))
+
+	var args []interface{}
+	for _, name := range names {
+		args = append(args, name)
+	}
+	return qb.queryStudios(query, args)
+}
+
 func (qb *studioQueryBuilder) Count() (int, error) {
 	return qb.runCountQuery(qb.buildCountQuery("SELECT studios.id FROM studios"), nil)
 }
 
+func (qb *studioQueryBuilder) SceneStats(limit int) ([]*models.StatsAggregate, error) {
+	return qb.querySceneStats("INNER JOIN scenes ON scenes.studio_id = studios.id", limit)
+}
+
 func (qb *studioQueryBuilder) All() ([]*models.Studio, error) {
 	return qb.queryStudios(selectAll("studios")+qb.getStudioSort(nil), nil)
 }
@@ -133,6 +163,13 @@ func (qb *studioQueryBuilder) QueryForAutoTag(words []string) ([]*models.Studio,
 	for _, w := range words {
 		whereClauses = append(whereClauses, "name like ?")
 		args = append(args, "%"+w+"%")
+		// names may be joined in scene-release style paths
+		whereClauses = append(whereClauses, "replace(name, ' ', '') like ?")
+		args = append(args, "%"+w+"%")
+	}
+
+	if len(whereClauses) == 0 {
+		return nil, nil
 	}
 
 	where := strings.Join(whereClauses, " OR ")
@@ -185,8 +222,19 @@ func (qb *studioQueryBuilder) Query(studioFilter *models.StudioFilterType, findF
 	query.handleCountCriterion(studioFilter.ImageCount, studioTable, imageTable, studioIDColumn)
 	query.handleCountCriterion(studioFilter.GalleryCount, studioTable, galleryTable, studioIDColumn)
 	query.handleStringCriterionInput(studioFilter.URL, "studios.url")
+	query.handleCountryCriterionInput(studioFilter.Countries, "studios.country")
 	query.handleStringCriterionInput(studioFilter.StashID, "studio_stash_ids.stash_id")
 
+	if endpoint := studioFilter.StashIDEndpoint; endpoint != nil {
+		clause, args, err := getStashIDEndpointCriterionClause(studioTable, "studio_stash_ids", studioIDColumn, *endpoint)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		query.addWhere(clause)
+		query.addArg(args...)
+	}
+
 	if isMissingFilter := studioFilter.IsMissing; isMissingFilter != nil && *isMissingFilter != "" {
 		switch *isMissingFilter {
 		case "image":
@@ -257,14 +305,14 @@ func (qb *studioQueryBuilder) queryStudios(query string, args []interface{}) ([]
 	return []*models.Studio(ret), nil
 }
 
-func (qb *studioQueryBuilder) imageRepository() *imageRepository {
-	return &imageRepository{
+func (qb *studioQueryBuilder) imageRepository() *imageBlobRepository {
+	return &imageBlobRepository{
 		repository: repository{
 			tx:        qb.tx,
 			tableName: "studios_image",
 			idColumn:  studioIDColumn,
 		},
-		imageColumn: "image",
+		blobColumn: "image_blob",
 	}
 }
 
@@ -281,7 +329,7 @@ func (qb *studioQueryBuilder) UpdateImage(studioID int, image []byte) error {
 }
 
 func (qb *studioQueryBuilder) DestroyImage(studioID int) error {
-	return qb.imageRepository().destroy([]int{studioID})
+	return qb.imageRepository().destroy(studioID)
 }
 
 func (qb *studioQueryBuilder) stashIDRepository() *stashIDRepository {
@@ -301,3 +349,13 @@ func (qb *studioQueryBuilder) GetStashIDs(studioID int) ([]*models.StashID, erro
 func (qb *studioQueryBuilder) UpdateStashIDs(studioID int, stashIDs []models.StashID) error {
 	return qb.stashIDRepository().replace(studioID, stashIDs)
 }
+
+func (qb *studioQueryBuilder) FindByStashID(stashID models.StashID) ([]*models.Studio, error) {
+	query := selectAll("studios") + `
+		LEFT JOIN studio_stash_ids on studio_stash_ids.studio_id = studios.id
+		WHERE studio_stash_ids.stash_id = ?
+		AND studio_stash_ids.endpoint = ?
+	`
+	args := []interface{}{stashID.StashID, stashID.Endpoint}
+	return qb.queryStudios(query, args)
+}
@@ -10,7 +10,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stashapp/stash/pkg/manager"
+	"github.com/stashapp/stash/pkg/manager/config"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/studio"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,6 +48,33 @@ func TestStudioFindByName(t *testing.T) {
 	})
 }
 
+func TestStudioFindMany(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Studio()
+
+		// request in reverse order to ensure that the order is preserved
+		var ids []int
+		for i := len(studioIDs) - 1; i >= 0; i-- {
+			ids = append(ids, studioIDs[i])
+		}
+
+		studios, err := qb.FindMany(ids)
+		if err != nil {
+			t.Errorf("Error finding studios: %s", err.Error())
+		}
+
+		assert.Len(t, studios, len(ids))
+		for i, o := range studios {
+			assert.Equal(t, ids[i], o.ID)
+		}
+
+		_, err = qb.FindMany([]int{studioIDs[0], 0})
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
 func TestStudioQueryForAutoTag(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		tqb := r.Studio()
@@ -214,6 +244,67 @@ func TestStudioUpdateClearParent(t *testing.T) {
 	}
 }
 
+func TestStudioCreateDuplicateName(t *testing.T) {
+	c := config.GetInstance()
+	c.Set(config.DuplicateNamePolicy, models.DuplicateNamePolicyAllow.String())
+	defer c.Set(config.DuplicateNamePolicy, "")
+
+	const name = "TestStudioCreateDuplicateName"
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Studio()
+
+		var ids []int
+		for i := 0; i < 2; i++ {
+			if err := manager.ValidateStudioName(qb, name); err != nil {
+				return err
+			}
+
+			created, err := createStudio(qb, name, nil)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, created.ID)
+		}
+
+		assert.NotEqual(t, ids[0], ids[1])
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestStudioFindDuplicates(t *testing.T) {
+	c := config.GetInstance()
+	c.Set(config.DuplicateNamePolicy, models.DuplicateNamePolicyAllow.String())
+	defer c.Set(config.DuplicateNamePolicy, "")
+
+	const name = "TestStudioFindDuplicates"
+	if err := withRollbackTxn(func(r models.Repository) error {
+		qb := r.Studio()
+
+		var ids []int
+		for _, n := range []string{name, strings.ToUpper(name), name} {
+			created, err := createStudio(qb, n, nil)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, created.ID)
+		}
+
+		found, err := studio.FindDuplicates(qb, " "+name+" ")
+		if err != nil {
+			return err
+		}
+
+		assert.ElementsMatch(t, ids, found)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestStudioUpdateStudioImage(t *testing.T) {
 	if err := withTxn(func(r models.Repository) error {
 		qb := r.Studio()
@@ -437,7 +528,36 @@ func TestStudioStashIDs(t *testing.T) {
 			return fmt.Errorf("Error creating studio: %s", err.Error())
 		}
 
-		testStashIDReaderWriter(t, qb, created.ID)
+		testStashIDReaderWriter(t, qb, created.ID, func(stashID models.StashID) ([]int, error) {
+			studios, err := qb.FindByStashID(stashID)
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, o := range studios {
+				ids = append(ids, o.ID)
+			}
+			return ids, nil
+		})
+
+		testStashIDEndpointCriterion(t, qb, created.ID, func(c models.StringCriterionInput) ([]int, error) {
+			perPage := -1
+			studios, _, err := qb.Query(&models.StudioFilterType{
+				StashIDEndpoint: &c,
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, o := range studios {
+				ids = append(ids, o.ID)
+			}
+			return ids, nil
+		})
 		return nil
 	}); err != nil {
 		t.Error(err.Error())
@@ -482,6 +602,28 @@ func TestStudioQueryURL(t *testing.T) {
 	verifyStudioQuery(t, filter, verifyFn)
 }
 
+func TestStudioQueryCountries(t *testing.T) {
+	countriesCriterion := models.MultiCriterionInput{
+		Value:    []string{"United States of America"},
+		Modifier: models.CriterionModifierIncludes,
+	}
+
+	filter := models.StudioFilterType{
+		Countries: &countriesCriterion,
+	}
+
+	verifyStudioQuery(t, filter, func(s *models.Studio) {
+		t.Helper()
+		assert.Equal(t, "US", s.Country.String)
+	})
+
+	countriesCriterion.Modifier = models.CriterionModifierExcludes
+	verifyStudioQuery(t, filter, func(s *models.Studio) {
+		t.Helper()
+		assert.NotEqual(t, "US", s.Country.String)
+	})
+}
+
 func TestStudioQueryRating(t *testing.T) {
 	const rating = 3
 	ratingCriterion := models.IntCriterionInput{
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -70,6 +72,11 @@ func (qb *tagQueryBuilder) Destroy(id int) error {
 		return errors.New("Cannot delete tag used as a primary tag in scene markers")
 	}
 
+	// delete the image blob if no longer used
+	if err := qb.DestroyImage(id); err != nil {
+		return err
+	}
+
 	return qb.destroyExisting([]int{id})
 }
 
@@ -85,21 +92,28 @@ func (qb *tagQueryBuilder) Find(id int) (*models.Tag, error) {
 }
 
 func (qb *tagQueryBuilder) FindMany(ids []int) ([]*models.Tag, error) {
-	var tags []*models.Tag
-	for _, id := range ids {
-		tag, err := qb.Find(id)
-		if err != nil {
-			return nil, err
-		}
+	var tags models.Tags
+	if err := qb.queryByIDs(ids, &tags); err != nil {
+		return nil, err
+	}
 
-		if tag == nil {
+	byID := make(map[int]*models.Tag)
+	for _, o := range tags {
+		byID[o.ID] = o
+	}
+
+	// return in the same order as the provided ids
+	var ret []*models.Tag
+	for _, id := range ids {
+		o, found := byID[id]
+		if !found {
 			return nil, fmt.Errorf("tag with id %d not found", id)
 		}
 
-		tags = append(tags, tag)
+		ret = append(ret, o)
 	}
 
-	return tags, nil
+	return ret, nil
 }
 
 func (qb *tagQueryBuilder) FindBySceneID(sceneID int) ([]*models.Tag, error) {
@@ -189,6 +203,11 @@ func (qb *tagQueryBuilder) Count() (int, error) {
 	return qb.runCountQuery(qb.buildCountQuery("SELECT tags.id FROM tags"), nil)
 }
 
+func (qb *tagQueryBuilder) SceneStats(limit int) ([]*models.StatsAggregate, error) {
+	return qb.querySceneStats(`INNER JOIN scenes_tags ON scenes_tags.tag_id = tags.id
+INNER JOIN scenes ON scenes.id = scenes_tags.scene_id`, limit)
+}
+
 func (qb *tagQueryBuilder) All() ([]*models.Tag, error) {
 	return qb.queryTags(selectAll("tags")+qb.getDefaultTagSort(), nil)
 }
@@ -204,8 +223,14 @@ func (qb *tagQueryBuilder) QueryForAutoTag(words []string) ([]*models.Tag, error
 	for _, w := range words {
 		whereClauses = append(whereClauses, "name like ?")
 		args = append(args, "%"+w+"%")
+		// names may be joined in scene-release style paths
+		whereClauses = append(whereClauses, "replace(name, ' ', '') like ?")
+		args = append(args, "%"+w+"%")
 	}
 
+	// custom regexes are matched by the caller
+	whereClauses = append(whereClauses, autoTagRegexClause)
+
 	where := strings.Join(whereClauses, " OR ")
 	return qb.queryTags(query+" WHERE "+where, args)
 }
@@ -406,6 +431,114 @@ func tagPerformerCountCriterionHandler(qb *tagQueryBuilder, performerCount *mode
 	}
 }
 
+// tagUsagePeriods returns the expression for the start of the interval
+// containing the date in the provided column.
+var tagUsagePeriods = map[models.TagUsageInterval]string{
+	models.TagUsageIntervalDay:   "date(%s)",
+	models.TagUsageIntervalWeek:  "date(%s, 'weekday 0', '-6 days')",
+	models.TagUsageIntervalMonth: "date(%s, 'start of month')",
+	models.TagUsageIntervalYear:  "date(%s, 'start of year')",
+}
+
+var tagUsageDateColumns = map[models.TagUsageDateField]string{
+	models.TagUsageDateFieldDate:      "scenes.date",
+	models.TagUsageDateFieldCreatedAt: "scenes.created_at",
+}
+
+// UsageTimeline returns the number of scenes with the tag, grouped by
+// interval of the date field. Scenes without a valid date are excluded.
+func (qb *tagQueryBuilder) UsageTimeline(tagID int, interval models.TagUsageInterval, dateField models.TagUsageDateField) ([]*models.TagUsagePoint, error) {
+	period, ok := tagUsagePeriods[interval]
+	if !ok {
+		return nil, fmt.Errorf("invalid interval: %s", interval)
+	}
+
+	column, ok := tagUsageDateColumns[dateField]
+	if !ok {
+		return nil, fmt.Errorf("invalid date field: %s", dateField)
+	}
+
+	period = fmt.Sprintf(period, column)
+	query := `SELECT ` + period + ` as period, COUNT(DISTINCT scenes.id) as scene_count
+FROM scenes_tags
+INNER JOIN scenes ON scenes.id = scenes_tags.scene_id
+WHERE scenes_tags.tag_id = ? AND date(` + column + `) > '0001-01-01'
+GROUP BY period
+ORDER BY period ASC`
+
+	ret := []*models.TagUsagePoint{}
+	if err := qb.queryFunc(query, []interface{}{tagID}, func(rows *sqlx.Rows) error {
+		var point models.TagUsagePoint
+		if err := rows.Scan(&point.Period, &point.Count); err != nil {
+			return err
+		}
+
+		ret = append(ret, &point)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// Trending returns the tags with the greatest increase in the number of
+// scenes added in the last number of days, compared to the number added in
+// the same number of days before that. Only tags with an increase are
+// returned.
+func (qb *tagQueryBuilder) Trending(days int, limit int) ([]*models.TrendingTag, error) {
+	now := time.Now()
+	current := models.SQLiteTimestamp{Timestamp: now.AddDate(0, 0, -days)}
+	previous := models.SQLiteTimestamp{Timestamp: now.AddDate(0, 0, -2*days)}
+
+	query := `SELECT scenes_tags.tag_id,
+  COUNT(DISTINCT CASE WHEN datetime(scenes.created_at) >= datetime(?) THEN scenes.id END) as current_count,
+  COUNT(DISTINCT CASE WHEN datetime(scenes.created_at) < datetime(?) THEN scenes.id END) as previous_count
+FROM scenes_tags
+INNER JOIN scenes ON scenes.id = scenes_tags.scene_id
+WHERE datetime(scenes.created_at) >= datetime(?)
+GROUP BY scenes_tags.tag_id
+HAVING current_count > previous_count
+ORDER BY current_count - previous_count DESC, current_count DESC, scenes_tags.tag_id ASC
+LIMIT ?`
+
+	args := []interface{}{current, current, previous, limit}
+
+	type trend struct {
+		tagID         int
+		count         int
+		previousCount int
+	}
+	var trends []trend
+	if err := qb.queryFunc(query, args, func(rows *sqlx.Rows) error {
+		var t trend
+		if err := rows.Scan(&t.tagID, &t.count, &t.previousCount); err != nil {
+			return err
+		}
+
+		trends = append(trends, t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := []*models.TrendingTag{}
+	for _, t := range trends {
+		tag, err := qb.Find(t.tagID)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, &models.TrendingTag{
+			Tag:           tag,
+			Count:         t.count,
+			PreviousCount: t.previousCount,
+		})
+	}
+
+	return ret, nil
+}
+
 func (qb *tagQueryBuilder) getDefaultTagSort() string {
 	return getSort("name", "ASC", "tags")
 }
@@ -458,14 +591,14 @@ func (qb *tagQueryBuilder) queryTags(query string, args []interface{}) ([]*model
 	return []*models.Tag(ret), nil
 }
 
-func (qb *tagQueryBuilder) imageRepository() *imageRepository {
-	return &imageRepository{
+func (qb *tagQueryBuilder) imageRepository() *imageBlobRepository {
+	return &imageBlobRepository{
 		repository: repository{
 			tx:        qb.tx,
 			tableName: "tags_image",
 			idColumn:  tagIDColumn,
 		},
-		imageColumn: "image",
+		blobColumn: "image_blob",
 	}
 }
 
@@ -482,5 +615,5 @@ func (qb *tagQueryBuilder) UpdateImage(tagID int, image []byte) error {
 }
 
 func (qb *tagQueryBuilder) DestroyImage(tagID int) error {
-	return qb.imageRepository().destroy([]int{tagID})
+	return qb.imageRepository().destroy(tagID)
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,33 @@ func TestMarkerFindBySceneMarkerID(t *testing.T) {
 	})
 }
 
+func TestTagFindMany(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		qb := r.Tag()
+
+		// request in reverse order to ensure that the order is preserved
+		var ids []int
+		for i := len(tagIDs) - 1; i >= 0; i-- {
+			ids = append(ids, tagIDs[i])
+		}
+
+		tags, err := qb.FindMany(ids)
+		if err != nil {
+			t.Errorf("Error finding tags: %s", err.Error())
+		}
+
+		assert.Len(t, tags, len(ids))
+		for i, o := range tags {
+			assert.Equal(t, ids[i], o.ID)
+		}
+
+		_, err = qb.FindMany([]int{tagIDs[0], 0})
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
 func TestTagFindByName(t *testing.T) {
 	withTxn(func(r models.Repository) error {
 		tqb := r.Tag()
@@ -90,6 +118,42 @@ func TestTagQueryForAutoTag(t *testing.T) {
 	})
 }
 
+func TestTagQueryForAutoTagJoinedAndRegex(t *testing.T) {
+	withTxn(func(r models.Repository) error {
+		tqb := r.Tag()
+
+		joined, err := tqb.Create(models.Tag{Name: "Joined Tag Name"})
+		if err != nil {
+			return fmt.Errorf("Error creating tag: %s", err.Error())
+		}
+		defer tqb.Destroy(joined.ID)
+
+		regex, err := tqb.Create(models.Tag{
+			Name:         "Regex Tag Name",
+			AutoTagRegex: sql.NullString{String: `\bjtn\b`, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating tag: %s", err.Error())
+		}
+		defer tqb.Destroy(regex.ID)
+
+		// names with the spaces removed match, and tags with a regex are
+		// always candidates
+		tags, err := tqb.QueryForAutoTag([]string{"JoinedTagName"})
+		if err != nil {
+			t.Errorf("Error finding tags: %s", err.Error())
+		}
+
+		var names []string
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		assert.ElementsMatch(t, []string{joined.Name, regex.Name}, names)
+
+		return nil
+	})
+}
+
 func TestTagFindByNames(t *testing.T) {
 	var names []string
 
@@ -384,6 +448,126 @@ func verifyTagPerformerCount(t *testing.T, imageCountCriterion models.IntCriteri
 	})
 }
 
+func TestTagUsageTimelineAndTrending(t *testing.T) {
+	if err := withTxn(func(r models.Repository) error {
+		qb := r.Tag()
+		sqb := r.Scene()
+
+		created, err := qb.Create(models.Tag{
+			Name: "TestTagUsageTimelineAndTrending",
+		})
+		if err != nil {
+			return fmt.Errorf("Error creating tag: %s", err.Error())
+		}
+
+		now := time.Now()
+		tenDaysAgo := now.AddDate(0, 0, -10)
+
+		scenes := []struct {
+			date      string
+			createdAt time.Time
+		}{
+			{"2020-01-05", now},
+			{"2020-01-20", now},
+			{"2021-03-01", now},
+			{"", tenDaysAgo},
+		}
+
+		var sceneIDs []int
+		for i, s := range scenes {
+			name := fmt.Sprintf("TestTagUsageTimelineAndTrending_%d", i)
+			scene, err := sqb.Create(models.Scene{
+				Path:      name,
+				Checksum:  sql.NullString{String: name, Valid: true},
+				Date:      models.SQLiteDate{String: s.date, Valid: s.date != ""},
+				CreatedAt: models.SQLiteTimestamp{Timestamp: s.createdAt},
+				UpdatedAt: models.SQLiteTimestamp{Timestamp: s.createdAt},
+			})
+			if err != nil {
+				return fmt.Errorf("Error creating scene: %s", err.Error())
+			}
+
+			if err := sqb.UpdateTags(scene.ID, []int{created.ID}); err != nil {
+				return fmt.Errorf("Error setting scene tags: %s", err.Error())
+			}
+
+			sceneIDs = append(sceneIDs, scene.ID)
+		}
+
+		verifyTimeline := func(interval models.TagUsageInterval, dateField models.TagUsageDateField, expected []*models.TagUsagePoint) {
+			t.Helper()
+			points, err := qb.UsageTimeline(created.ID, interval, dateField)
+			if err != nil {
+				t.Errorf("Error getting usage timeline: %s", err.Error())
+				return
+			}
+
+			assert.Equal(t, expected, points)
+		}
+
+		verifyTimeline(models.TagUsageIntervalDay, models.TagUsageDateFieldDate, []*models.TagUsagePoint{
+			{Period: "2020-01-05", Count: 1},
+			{Period: "2020-01-20", Count: 1},
+			{Period: "2021-03-01", Count: 1},
+		})
+		verifyTimeline(models.TagUsageIntervalWeek, models.TagUsageDateFieldDate, []*models.TagUsagePoint{
+			{Period: "2019-12-30", Count: 1},
+			{Period: "2020-01-20", Count: 1},
+			{Period: "2021-03-01", Count: 1},
+		})
+		verifyTimeline(models.TagUsageIntervalMonth, models.TagUsageDateFieldDate, []*models.TagUsagePoint{
+			{Period: "2020-01-01", Count: 2},
+			{Period: "2021-03-01", Count: 1},
+		})
+		verifyTimeline(models.TagUsageIntervalYear, models.TagUsageDateFieldDate, []*models.TagUsagePoint{
+			{Period: "2020-01-01", Count: 2},
+			{Period: "2021-01-01", Count: 1},
+		})
+
+		// created_at is stored with the local offset and grouped in UTC
+		verifyTimeline(models.TagUsageIntervalDay, models.TagUsageDateFieldCreatedAt, []*models.TagUsagePoint{
+			{Period: tenDaysAgo.UTC().Format("2006-01-02"), Count: 1},
+			{Period: now.UTC().Format("2006-01-02"), Count: 3},
+		})
+
+		trending, err := qb.Trending(7, 10)
+		if err != nil {
+			return fmt.Errorf("Error getting trending tags: %s", err.Error())
+		}
+
+		if assert.Len(t, trending, 1) {
+			assert.Equal(t, created.ID, trending[0].Tag.ID)
+			assert.Equal(t, 3, trending[0].Count)
+			assert.Equal(t, 1, trending[0].PreviousCount)
+		}
+
+		// the scene added ten days ago is outside of both periods
+		trending, err = qb.Trending(1, 10)
+		if err != nil {
+			return fmt.Errorf("Error getting trending tags: %s", err.Error())
+		}
+
+		if assert.Len(t, trending, 1) {
+			assert.Equal(t, 3, trending[0].Count)
+			assert.Equal(t, 0, trending[0].PreviousCount)
+		}
+
+		// remove the scenes so that they don't affect other tests
+		if err := qb.Destroy(created.ID); err != nil {
+			return fmt.Errorf("Error destroying tag: %s", err.Error())
+		}
+		for _, id := range sceneIDs {
+			if err := sqb.Destroy(id); err != nil {
+				return fmt.Errorf("Error destroying scene: %s", err.Error())
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestTagUpdateTagImage(t *testing.T) {
 	if err := withTxn(func(r models.Repository) error {
 		qb := r.Tag()
@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
+	"sync"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/database"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -22,6 +25,9 @@ type dbi interface {
 type transaction struct {
 	Ctx context.Context
 	tx  *sqlx.Tx
+
+	changes  changeSet
+	onCommit func(changes []*models.EntityChangedEvent)
 }
 
 func (t *transaction) Begin() error {
@@ -52,6 +58,7 @@ func (t *transaction) Rollback() error {
 		return fmt.Errorf("error rolling back transaction: %s", err.Error())
 	}
 	t.tx = nil
+	t.changes = changeSet{}
 
 	return nil
 }
@@ -67,6 +74,17 @@ func (t *transaction) Commit() error {
 	}
 	t.tx = nil
 
+	for _, path := range t.changes.removedFiles {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logger.Warnf("error removing %s: %s", path, err.Error())
+		}
+	}
+
+	if events := t.changes.events(); len(events) > 0 && t.onCommit != nil {
+		t.onCommit(events)
+	}
+	t.changes = changeSet{}
+
 	return nil
 }
 
@@ -74,55 +92,89 @@ func (t *transaction) Repository() models.Repository {
 	return t
 }
 
+// db returns the handle used by the repositories, which records the changes
+// made within the transaction.
+func (t *transaction) db() dbi {
+	return &trackedTx{
+		dbi:     t.tx,
+		changes: &t.changes,
+	}
+}
+
 func (t *transaction) ensureTx() {
 	if t.tx == nil {
 		panic("tx is nil")
 	}
 }
 
+func (t *transaction) DeletedObject() models.DeletedObjectReader {
+	t.ensureTx()
+	return NewDeletedObjectReader(profile(t.db()))
+}
+
 func (t *transaction) Gallery() models.GalleryReaderWriter {
 	t.ensureTx()
-	return NewGalleryReaderWriter(t.tx)
+	return NewGalleryReaderWriter(profile(t.db()))
+}
+
+func (t *transaction) Group() models.GroupReaderWriter {
+	t.ensureTx()
+	return NewGroupReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) Image() models.ImageReaderWriter {
 	t.ensureTx()
-	return NewImageReaderWriter(t.tx)
+	return NewImageReaderWriter(profile(t.db()))
+}
+
+func (t *transaction) JobHistory() models.JobHistoryReaderWriter {
+	t.ensureTx()
+	return NewJobHistoryReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) Movie() models.MovieReaderWriter {
 	t.ensureTx()
-	return NewMovieReaderWriter(t.tx)
+	return NewMovieReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) Performer() models.PerformerReaderWriter {
 	t.ensureTx()
-	return NewPerformerReaderWriter(t.tx)
+	return NewPerformerReaderWriter(profile(t.db()))
+}
+
+func (t *transaction) Playlist() models.PlaylistReaderWriter {
+	t.ensureTx()
+	return NewPlaylistReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) SceneMarker() models.SceneMarkerReaderWriter {
 	t.ensureTx()
-	return NewSceneMarkerReaderWriter(t.tx)
+	return NewSceneMarkerReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) Scene() models.SceneReaderWriter {
 	t.ensureTx()
-	return NewSceneReaderWriter(t.tx)
+	return NewSceneReaderWriter(profile(t.db()))
+}
+
+func (t *transaction) ScheduledTask() models.ScheduledTaskReaderWriter {
+	t.ensureTx()
+	return NewScheduledTaskReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) ScrapedItem() models.ScrapedItemReaderWriter {
 	t.ensureTx()
-	return NewScrapedItemReaderWriter(t.tx)
+	return NewScrapedItemReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) Studio() models.StudioReaderWriter {
 	t.ensureTx()
-	return NewStudioReaderWriter(t.tx)
+	return NewStudioReaderWriter(profile(t.db()))
 }
 
 func (t *transaction) Tag() models.TagReaderWriter {
 	t.ensureTx()
-	return NewTagReaderWriter(t.tx)
+	return NewTagReaderWriter(profile(t.db()))
 }
 
 type ReadTransaction struct{}
@@ -147,55 +199,220 @@ func (t *ReadTransaction) Repository() models.ReaderRepository {
 	return t
 }
 
+func (t *ReadTransaction) DeletedObject() models.DeletedObjectReader {
+	return NewDeletedObjectReader(profile(database.DB))
+}
+
 func (t *ReadTransaction) Gallery() models.GalleryReader {
-	return NewGalleryReaderWriter(database.DB)
+	return NewGalleryReaderWriter(profile(database.DB))
+}
+
+func (t *ReadTransaction) Group() models.GroupReader {
+	return NewGroupReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) Image() models.ImageReader {
-	return NewImageReaderWriter(database.DB)
+	return NewImageReaderWriter(profile(database.DB))
+}
+
+func (t *ReadTransaction) JobHistory() models.JobHistoryReader {
+	return NewJobHistoryReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) Movie() models.MovieReader {
-	return NewMovieReaderWriter(database.DB)
+	return NewMovieReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) Performer() models.PerformerReader {
-	return NewPerformerReaderWriter(database.DB)
+	return NewPerformerReaderWriter(profile(database.DB))
+}
+
+func (t *ReadTransaction) Playlist() models.PlaylistReader {
+	return NewPlaylistReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) SceneMarker() models.SceneMarkerReader {
-	return NewSceneMarkerReaderWriter(database.DB)
+	return NewSceneMarkerReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) Scene() models.SceneReader {
-	return NewSceneReaderWriter(database.DB)
+	return NewSceneReaderWriter(profile(database.DB))
+}
+
+func (t *ReadTransaction) ScheduledTask() models.ScheduledTaskReader {
+	return NewScheduledTaskReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) ScrapedItem() models.ScrapedItemReader {
-	return NewScrapedItemReaderWriter(database.DB)
+	return NewScrapedItemReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) Studio() models.StudioReader {
-	return NewStudioReaderWriter(database.DB)
+	return NewStudioReaderWriter(profile(database.DB))
 }
 
 func (t *ReadTransaction) Tag() models.TagReader {
-	return NewTagReaderWriter(database.DB)
+	return NewTagReaderWriter(profile(database.DB))
 }
 
 type TransactionManager struct {
+	// OnCommit, if set, is called with the objects changed by each write
+	// transaction after it has been committed.
+	OnCommit func(changes []*models.EntityChangedEvent)
+
+	readOnly bool
 }
 
-func NewTransactionManager() *TransactionManager {
-	return &TransactionManager{}
+// NewTransactionManager returns a new TransactionManager. If readOnly is
+// true, then WithTxn returns models.ErrReadOnly without calling fn.
+func NewTransactionManager(readOnly bool) *TransactionManager {
+	return &TransactionManager{
+		readOnly: readOnly,
+	}
 }
 
 func (t *TransactionManager) WithTxn(ctx context.Context, fn func(r models.Repository) error) error {
+	if t.readOnly {
+		return models.ErrReadOnly
+	}
+
 	database.WriteMu.Lock()
 	defer database.WriteMu.Unlock()
-	return models.WithTxn(&transaction{Ctx: ctx}, fn)
+	return models.WithTxn(&transaction{Ctx: ctx, onCommit: t.OnCommit}, fn)
 }
 
 func (t *TransactionManager) WithReadTxn(ctx context.Context, fn func(r models.ReaderRepository) error) error {
 	return models.WithROTxn(&ReadTransaction{}, fn)
 }
+
+// DryRun calls fn with a transaction manager whose transactions are all made
+// within a single database transaction, which is rolled back once fn
+// returns. Each of its transactions is made within a savepoint, so that a
+// failed transaction only rolls back its own changes. Other writes wait
+// until fn returns.
+func (t *TransactionManager) DryRun(ctx context.Context, fn func(txnManager models.TransactionManager) error) error {
+	if t.readOnly {
+		return models.ErrReadOnly
+	}
+
+	database.WriteMu.Lock()
+	defer database.WriteMu.Unlock()
+
+	txn := &transaction{Ctx: ctx}
+	if err := txn.Begin(); err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	return fn(&dryRunTransactionManager{txn: txn})
+}
+
+const dryRunSavepoint = "dry_run"
+
+// dryRunTransactionManager makes transactions within the savepoints of an
+// existing transaction. The changes are never published. Transactions are
+// made one at a time, since the savepoints share the same transaction.
+type dryRunTransactionManager struct {
+	txn   *transaction
+	mutex sync.Mutex
+}
+
+func (t *dryRunTransactionManager) WithTxn(ctx context.Context, fn func(r models.Repository) error) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return models.WithTxn(&savepoint{transaction: t.txn}, fn)
+}
+
+func (t *dryRunTransactionManager) WithReadTxn(ctx context.Context, fn func(r models.ReaderRepository) error) error {
+	return t.WithTxn(ctx, func(r models.Repository) error {
+		return fn(&savepointReader{r: r})
+	})
+}
+
+func (t *dryRunTransactionManager) DryRun(ctx context.Context, fn func(txnManager models.TransactionManager) error) error {
+	return fn(t)
+}
+
+type savepoint struct {
+	*transaction
+}
+
+func (s *savepoint) Begin() error {
+	_, err := s.tx.Exec("SAVEPOINT " + dryRunSavepoint)
+	return err
+}
+
+func (s *savepoint) Rollback() error {
+	if _, err := s.tx.Exec("ROLLBACK TO " + dryRunSavepoint); err != nil {
+		return fmt.Errorf("error rolling back savepoint: %s", err.Error())
+	}
+
+	return s.Commit()
+}
+
+func (s *savepoint) Commit() error {
+	_, err := s.tx.Exec("RELEASE " + dryRunSavepoint)
+	return err
+}
+
+// savepointReader reads using the savepoint, so that the changes made
+// within the dry run are visible.
+type savepointReader struct {
+	r models.Repository
+}
+
+func (r *savepointReader) DeletedObject() models.DeletedObjectReader {
+	return r.r.DeletedObject()
+}
+
+func (r *savepointReader) Gallery() models.GalleryReader {
+	return r.r.Gallery()
+}
+
+func (r *savepointReader) Group() models.GroupReader {
+	return r.r.Group()
+}
+
+func (r *savepointReader) Image() models.ImageReader {
+	return r.r.Image()
+}
+
+func (r *savepointReader) JobHistory() models.JobHistoryReader {
+	return r.r.JobHistory()
+}
+
+func (r *savepointReader) Movie() models.MovieReader {
+	return r.r.Movie()
+}
+
+func (r *savepointReader) Performer() models.PerformerReader {
+	return r.r.Performer()
+}
+
+func (r *savepointReader) Playlist() models.PlaylistReader {
+	return r.r.Playlist()
+}
+
+func (r *savepointReader) SceneMarker() models.SceneMarkerReader {
+	return r.r.SceneMarker()
+}
+
+func (r *savepointReader) Scene() models.SceneReader {
+	return r.r.Scene()
+}
+
+func (r *savepointReader) ScheduledTask() models.ScheduledTaskReader {
+	return r.r.ScheduledTask()
+}
+
+func (r *savepointReader) ScrapedItem() models.ScrapedItemReader {
+	return r.r.ScrapedItem()
+}
+
+func (r *savepointReader) Studio() models.StudioReader {
+	return r.r.Studio()
+}
+
+func (r *savepointReader) Tag() models.TagReader {
+	return r.r.Tag()
+}
@@ -0,0 +1,171 @@
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyTransactionManager(t *testing.T) {
+	txnManager := sqlite.NewTransactionManager(true)
+
+	called := false
+	err := txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		called = true
+		return nil
+	})
+
+	assert.Equal(t, models.ErrReadOnly, err)
+	assert.False(t, called)
+
+	err = txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+		_, err := r.Scene().Count()
+		return err
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestTransactionManagerOnCommit(t *testing.T) {
+	txnManager := sqlite.NewTransactionManager(false)
+
+	var events []*models.EntityChangedEvent
+	txnManager.OnCommit = func(changes []*models.EntityChangedEvent) {
+		events = append(events, changes...)
+	}
+
+	var createdID int
+	if err := txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		qb := r.Tag()
+		created, err := qb.Create(models.Tag{
+			Name: "TestTransactionManagerOnCommit",
+		})
+		if err != nil {
+			return err
+		}
+		createdID = created.ID
+
+		// updates to objects created in the same transaction are omitted
+		if err := qb.UpdateImage(created.ID, []byte("image")); err != nil {
+			return err
+		}
+
+		sceneID := sceneIDs[sceneIdxWithTag]
+		tagIDs, err := r.Scene().GetTagIDs(sceneID)
+		if err != nil {
+			return err
+		}
+
+		return r.Scene().UpdateTags(sceneID, tagIDs)
+	}); err != nil {
+		t.Error(err.Error())
+	}
+
+	assert.Equal(t, []*models.EntityChangedEvent{
+		{
+			Type:   models.EntityTypeTag,
+			Change: models.EntityChangeTypeCreated,
+			Ids:    []string{strconv.Itoa(createdID)},
+		},
+		{
+			Type:   models.EntityTypeScene,
+			Change: models.EntityChangeTypeUpdated,
+			Ids:    []string{strconv.Itoa(sceneIDs[sceneIdxWithTag])},
+		},
+	}, events)
+
+	// rolled back changes are not published
+	events = nil
+	_ = txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		if err := r.Tag().Destroy(createdID); err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+
+	assert.Len(t, events, 0)
+
+	// clean up
+	if err := txnManager.WithTxn(context.TODO(), func(r models.Repository) error {
+		return r.Tag().Destroy(createdID)
+	}); err != nil {
+		t.Error(err.Error())
+	}
+
+	assert.Equal(t, []*models.EntityChangedEvent{
+		{
+			Type:   models.EntityTypeTag,
+			Change: models.EntityChangeTypeDestroyed,
+			Ids:    []string{strconv.Itoa(createdID)},
+		},
+	}, events)
+}
+
+func TestTransactionManagerDryRun(t *testing.T) {
+	txnManager := sqlite.NewTransactionManager(false)
+
+	published := false
+	txnManager.OnCommit = func(changes []*models.EntityChangedEvent) {
+		published = true
+	}
+
+	const (
+		createdName    = "TestTransactionManagerDryRun"
+		rolledBackName = "TestTransactionManagerDryRunRolledBack"
+	)
+
+	findTag := func(txnManager models.TransactionManager, name string) *models.Tag {
+		var ret *models.Tag
+		if err := txnManager.WithReadTxn(context.TODO(), func(r models.ReaderRepository) error {
+			var err error
+			ret, err = r.Tag().FindByName(name, false)
+			return err
+		}); err != nil {
+			t.Error(err.Error())
+		}
+
+		return ret
+	}
+
+	if err := txnManager.DryRun(context.TODO(), func(dryRun models.TransactionManager) error {
+		if err := dryRun.WithTxn(context.TODO(), func(r models.Repository) error {
+			_, err := r.Tag().Create(models.Tag{Name: createdName})
+			return err
+		}); err != nil {
+			return err
+		}
+
+		// failed transactions only roll back their own changes
+		_ = dryRun.WithTxn(context.TODO(), func(r models.Repository) error {
+			if _, err := r.Tag().Create(models.Tag{Name: rolledBackName}); err != nil {
+				return err
+			}
+
+			return errors.New("rollback")
+		})
+
+		assert.NotNil(t, findTag(dryRun, createdName))
+		assert.Nil(t, findTag(dryRun, rolledBackName))
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+
+	// the changes are discarded after the dry run
+	assert.Nil(t, findTag(txnManager, createdName))
+	assert.False(t, published)
+
+	readOnly := sqlite.NewTransactionManager(true)
+	assert.Equal(t, models.ErrReadOnly, readOnly.DryRun(context.TODO(), func(models.TransactionManager) error {
+		return nil
+	}))
+}
@@ -0,0 +1,28 @@
+package studio
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// FindDuplicates returns the IDs of the studios with a name matching the
+// provided name, ignoring case and surrounding whitespace.
+func FindDuplicates(r models.StudioReader, name string) ([]int, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return nil, nil
+	}
+
+	existing, err := r.FindByNames([]string{name}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	for _, s := range existing {
+		ret = append(ret, s.ID)
+	}
+
+	return ret, nil
+}
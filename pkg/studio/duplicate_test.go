@@ -0,0 +1,41 @@
+package studio
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	duplicateName        = "Studio Name"
+	duplicateNameErrName = "error"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	mockStudioReader := &mocks.StudioReaderWriter{}
+
+	mockStudioReader.On("FindByNames", []string{duplicateName}, true).Return([]*models.Studio{
+		{ID: 1},
+		{ID: 2},
+		{ID: 3},
+	}, nil).Once()
+
+	ids, err := FindDuplicates(mockStudioReader, " Studio  Name ")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	// empty names never match
+	ids, err = FindDuplicates(mockStudioReader, " ")
+	assert.Nil(t, err)
+	assert.Len(t, ids, 0)
+
+	mockStudioReader.On("FindByNames", []string{duplicateNameErrName}, true).Return(nil, errors.New("FindByNames error")).Once()
+
+	_, err = FindDuplicates(mockStudioReader, duplicateNameErrName)
+	assert.NotNil(t, err)
+
+	mockStudioReader.AssertExpectations(t)
+}
@@ -27,6 +27,10 @@ func ToJSON(reader models.StudioReader, studio *models.Studio) (*jsonschema.Stud
 		newStudioJSON.Details = studio.Details.String
 	}
 
+	if studio.Country.Valid {
+		newStudioJSON.Country = studio.Country.String
+	}
+
 	if studio.ParentID.Valid {
 		parent, err := reader.Find(int(studio.ParentID.Int64))
 		if err != nil {
@@ -51,5 +55,14 @@ func ToJSON(reader models.StudioReader, studio *models.Studio) (*jsonschema.Stud
 		newStudioJSON.Image = utils.GetBase64StringFromData(image)
 	}
 
+	stashIDs, err := reader.GetStashIDs(studio.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting studio stash ids: %s", err.Error())
+	}
+
+	for _, stashID := range stashIDs {
+		newStudioJSON.StashIDs = append(newStudioJSON.StashIDs, *stashID)
+	}
+
 	return &newStudioJSON, nil
 }
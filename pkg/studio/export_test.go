@@ -38,6 +38,11 @@ var parentStudio models.Studio = models.Studio{
 
 var imageBytes = []byte("imageBytes")
 
+var stashID = models.StashID{
+	StashID:  "StashID",
+	Endpoint: "Endpoint",
+}
+
 const image = "aW1hZ2VCeXRlcw=="
 
 var createTime time.Time = time.Date(2001, 01, 01, 0, 0, 0, 0, time.Local)
@@ -91,6 +96,9 @@ func createFullJSONStudio(parentStudio, image string) *jsonschema.Studio {
 		ParentStudio: parentStudio,
 		Image:        image,
 		Rating:       rating,
+		StashIDs: []models.StashID{
+			stashID,
+		},
 	}
 }
 
@@ -156,6 +164,10 @@ func TestToJSON(t *testing.T) {
 	mockStudioReader.On("GetImage", missingParentStudioID).Return(imageBytes, nil).Maybe()
 	mockStudioReader.On("GetImage", errStudioID).Return(imageBytes, nil).Maybe()
 
+	mockStudioReader.On("GetStashIDs", studioID).Return([]*models.StashID{&stashID}, nil).Once()
+	mockStudioReader.On("GetStashIDs", noImageID).Return(nil, nil).Once()
+	mockStudioReader.On("GetStashIDs", missingParentStudioID).Return([]*models.StashID{&stashID}, nil).Once()
+
 	parentStudioErr := errors.New("error getting parent studio")
 
 	mockStudioReader.On("Find", parentStudioID).Return(&parentStudio, nil)
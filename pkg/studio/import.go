@@ -34,6 +34,10 @@ func (i *Importer) PreImport() error {
 		Rating:    sql.NullInt64{Int64: int64(i.Input.Rating), Valid: true},
 	}
 
+	if i.Input.Country != "" {
+		i.studio.Country = sql.NullString{String: utils.NormaliseCountry(i.Input.Country), Valid: true}
+	}
+
 	if err := i.populateParentStudio(); err != nil {
 		return err
 	}
@@ -101,6 +105,28 @@ func (i *Importer) PostImport(id int) error {
 		}
 	}
 
+	if len(i.Input.StashIDs) > 0 {
+		if err := i.updateStashIDs(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateStashIDs merges the stash IDs from the input with the existing stash
+// IDs of the studio, so that stash IDs for other endpoints are retained when
+// overwriting.
+func (i *Importer) updateStashIDs(id int) error {
+	existing, err := i.ReaderWriter.GetStashIDs(id)
+	if err != nil {
+		return fmt.Errorf("error getting studio stash ids: %s", err.Error())
+	}
+
+	if err := i.ReaderWriter.UpdateStashIDs(id, models.MergeStashIDs(existing, i.Input.StashIDs)); err != nil {
+		return fmt.Errorf("error setting studio stash ids: %s", err.Error())
+	}
+
 	return nil
 }
 
@@ -120,6 +146,25 @@ func (i *Importer) FindExistingID() (*int, error) {
 		return &id, nil
 	}
 
+	// fall back to stash IDs, in case the studio has a different name
+	return i.findExistingByStashIDs()
+}
+
+// findExistingByStashIDs returns the ID of the first existing studio with
+// any of the stash IDs in the input.
+func (i *Importer) findExistingByStashIDs() (*int, error) {
+	for _, stashID := range i.Input.StashIDs {
+		existing, err := i.ReaderWriter.FindByStashID(stashID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(existing) > 0 {
+			id := existing[0].ID
+			return &id, nil
+		}
+	}
+
 	return nil, nil
 }
 
@@ -143,3 +188,38 @@ func (i *Importer) Update(id int) error {
 
 	return nil
 }
+
+// Merge sets the missing fields and image of the existing studio.
+func (i *Importer) Merge(id int) error {
+	existing, err := i.ReaderWriter.Find(id)
+	if err != nil {
+		return fmt.Errorf("error finding existing studio: %s", err.Error())
+	}
+	if existing == nil {
+		return fmt.Errorf("existing studio with id %d not found", id)
+	}
+
+	studio := *existing
+	models.FillNullString(&studio.URL, i.studio.URL)
+	models.FillNullInt64(&studio.ParentID, i.studio.ParentID)
+	models.FillNullInt64(&studio.Rating, i.studio.Rating)
+	models.FillNullString(&studio.Details, i.studio.Details)
+	models.FillNullString(&studio.Country, i.studio.Country)
+
+	if _, err := i.ReaderWriter.UpdateFull(studio); err != nil {
+		return fmt.Errorf("error updating existing studio: %s", err.Error())
+	}
+
+	if len(i.imageData) > 0 {
+		hasImage, err := i.ReaderWriter.HasImage(id)
+		if err != nil {
+			return fmt.Errorf("error getting existing studio image: %s", err.Error())
+		}
+
+		if hasImage {
+			i.imageData = nil
+		}
+	}
+
+	return nil
+}
@@ -202,6 +202,33 @@ func TestImporterFindExistingID(t *testing.T) {
 	readerWriter.AssertExpectations(t)
 }
 
+func TestImporterFindExistingIDByStashID(t *testing.T) {
+	readerWriter := &mocks.StudioReaderWriter{}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		Input: jsonschema.Studio{
+			Name: studioName,
+			StashIDs: []models.StashID{
+				stashID,
+			},
+		},
+	}
+
+	readerWriter.On("FindByName", studioName, false).Return(nil, nil).Once()
+	readerWriter.On("FindByStashID", stashID).Return([]*models.Studio{
+		{
+			ID: existingStudioID,
+		},
+	}, nil).Once()
+
+	id, err := i.FindExistingID()
+	assert.Equal(t, existingStudioID, *id)
+	assert.Nil(t, err)
+
+	readerWriter.AssertExpectations(t)
+}
+
 func TestCreate(t *testing.T) {
 	readerWriter := &mocks.StudioReaderWriter{}
 
@@ -16,6 +16,10 @@ func ToJSON(reader models.TagReader, tag *models.Tag) (*jsonschema.Tag, error) {
 		UpdatedAt: models.JSONTime{Time: tag.UpdatedAt.Timestamp},
 	}
 
+	if tag.AutoTagRegex.Valid {
+		newTagJSON.AutoTagRegex = tag.AutoTagRegex.String
+	}
+
 	image, err := reader.GetImage(tag.ID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting tag image: %s", err.Error())
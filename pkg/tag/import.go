@@ -1,6 +1,7 @@
 package tag
 
 import (
+	"database/sql"
 	"fmt"
 
 	"github.com/stashapp/stash/pkg/manager/jsonschema"
@@ -23,6 +24,10 @@ func (i *Importer) PreImport() error {
 		UpdatedAt: models.SQLiteTimestamp{Timestamp: i.Input.UpdatedAt.GetTime()},
 	}
 
+	if i.Input.AutoTagRegex != "" {
+		i.tag.AutoTagRegex = sql.NullString{String: i.Input.AutoTagRegex, Valid: true}
+	}
+
 	var err error
 	if len(i.Input.Image) > 0 {
 		_, i.imageData, err = utils.ProcessBase64Image(i.Input.Image)
@@ -83,3 +88,21 @@ func (i *Importer) Update(id int) error {
 
 	return nil
 }
+
+// Merge sets the image of the existing tag if it has no image.
+func (i *Importer) Merge(id int) error {
+	if len(i.imageData) == 0 {
+		return nil
+	}
+
+	existingImage, err := i.ReaderWriter.GetImage(id)
+	if err != nil {
+		return fmt.Errorf("error getting existing tag image: %s", err.Error())
+	}
+
+	if len(existingImage) > 0 {
+		i.imageData = nil
+	}
+
+	return nil
+}
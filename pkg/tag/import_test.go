@@ -177,3 +177,26 @@ func TestUpdate(t *testing.T) {
 
 	readerWriter.AssertExpectations(t)
 }
+
+func TestMerge(t *testing.T) {
+	readerWriter := &mocks.TagReaderWriter{}
+
+	i := Importer{
+		ReaderWriter: readerWriter,
+		imageData:    imageBytes,
+	}
+
+	readerWriter.On("GetImage", tagID).Return(nil, nil).Once()
+	readerWriter.On("GetImage", errImageID).Return(imageBytes, nil).Once()
+
+	// the imported image is set if the existing tag has no image
+	err := i.Merge(tagID)
+	assert.Nil(t, err)
+	assert.Equal(t, imageBytes, i.imageData)
+
+	err = i.Merge(errImageID)
+	assert.Nil(t, err)
+	assert.Nil(t, i.imageData)
+
+	readerWriter.AssertExpectations(t)
+}
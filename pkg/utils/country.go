@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"strings"
+)
+
+type isoCountry struct {
+	alpha2 string
+	alpha3 string
+	names  []string
+}
+
+// commonly used names which are not included in the ISO names
+var countryAliases = map[string]string{
+	"america":          "US",
+	"england":          "GB",
+	"great britain":    "GB",
+	"scotland":         "GB",
+	"wales":            "GB",
+	"northern ireland": "GB",
+	"uk":               "GB",
+	"holland":          "NL",
+	"russia":           "RU",
+	"korea":            "KR",
+	"macedonia":        "MK",
+	"brunei":           "BN",
+	"ivory coast":      "CI",
+	"cape verde":       "CV",
+	"swaziland":        "SZ",
+	"burma":            "MM",
+	"turkey":           "TR",
+	"vatican":          "VA",
+	"palestine":        "PS",
+}
+
+var countryLookup = buildCountryLookup()
+
+func normaliseCountryKey(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, ".", "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func buildCountryLookup() map[string]string {
+	ret := make(map[string]string)
+	for _, c := range isoCountries {
+		ret[normaliseCountryKey(c.alpha2)] = c.alpha2
+		ret[normaliseCountryKey(c.alpha3)] = c.alpha2
+		for _, n := range c.names {
+			ret[normaliseCountryKey(n)] = c.alpha2
+		}
+	}
+
+	for k, v := range countryAliases {
+		ret[k] = v
+	}
+
+	return ret
+}
+
+// GetCountryCode returns the ISO 3166-1 alpha-2 code for the provided
+// country code or name. Returns false if the country is not recognised.
+func GetCountryCode(country string) (string, bool) {
+	code, found := countryLookup[normaliseCountryKey(country)]
+	return code, found
+}
+
+// NormaliseCountry returns the ISO 3166-1 alpha-2 code for the provided
+// country code or name. Unrecognised values are returned trimmed but
+// otherwise unchanged.
+func NormaliseCountry(country string) string {
+	if code, found := GetCountryCode(country); found {
+		return code
+	}
+
+	return strings.TrimSpace(country)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNormaliseCountry(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"US", "US"},
+		{"us", "US"},
+		{"USA", "US"},
+		{"U.S.A.", "US"},
+		{"United States", "US"},
+		{" united   states ", "US"},
+		{"United States of America", "US"},
+		{"UK", "GB"},
+		{"United Kingdom", "GB"},
+		{"England", "GB"},
+		{"Czechia", "CZ"},
+		{"Czech Republic", "CZ"},
+		{"Russia", "RU"},
+		{"Atlantis", "Atlantis"},
+		{" Atlantis ", "Atlantis"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := NormaliseCountry(tc.input); got != tc.want {
+			t.Errorf("NormaliseCountry(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
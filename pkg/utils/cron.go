@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMaxLookahead is how far ahead CronSchedule.Next looks for the next
+// matching time, so that expressions which never match, such as
+// "0 0 30 2 *", do not loop forever.
+const cronMaxLookahead = 5
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is also Sunday
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// CronSchedule is a parsed cron expression. See ParseCron.
+type CronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// if both days are restricted, days matching either are matched
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseCron parses a standard five field cron expression: minute, hour, day
+// of month, month and day of week. Fields may be *, values, ranges such as
+// 1-5, lists such as 1,15 and steps such as */15. Months and days of the
+// week may be given by their three letter names. The @yearly, @monthly,
+// @weekly, @daily and @hourly macros are also accepted.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, found := cronMacros[strings.ToLower(expr)]; found {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected %d fields, found %d", expr, len(cronFields), len(fields))
+	}
+
+	var bits [5]uint64
+	for i, f := range cronFields {
+		var err error
+		bits[i], err = f.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s", expr, err.Error())
+		}
+	}
+
+	ret := &CronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dayOfMonth:    bits[2],
+		month:         bits[3],
+		dayOfWeek:     bits[4],
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	// Sunday may be 0 or 7
+	if ret.dayOfWeek&(1<<7) != 0 {
+		ret.dayOfWeek |= 1
+	}
+
+	return ret, nil
+}
+
+func (f cronField) parse(s string) (uint64, error) {
+	var ret uint64
+	for _, part := range strings.Split(s, ",") {
+		bits, err := f.parsePart(part)
+		if err != nil {
+			return 0, err
+		}
+		ret |= bits
+	}
+
+	return ret, nil
+}
+
+func (f cronField) parsePart(part string) (uint64, error) {
+	rangePart := part
+	step := 1
+	hasStep := false
+	if i := strings.Index(part, "/"); i != -1 {
+		var err error
+		rangePart = part[:i]
+		step, err = strconv.Atoi(part[i+1:])
+		if err != nil || step < 1 {
+			return 0, fmt.Errorf("invalid %s step in %q", f.name, part)
+		}
+		hasStep = true
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*":
+		start, end = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		bounds := strings.SplitN(rangePart, "-", 2)
+		var err error
+		if start, err = f.value(bounds[0]); err != nil {
+			return 0, err
+		}
+		if end, err = f.value(bounds[1]); err != nil {
+			return 0, err
+		}
+		if end < start {
+			return 0, fmt.Errorf("invalid %s range %q", f.name, rangePart)
+		}
+	default:
+		var err error
+		if start, err = f.value(rangePart); err != nil {
+			return 0, err
+		}
+		end = start
+		// a value with a step is the start of a range to the maximum
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	var ret uint64
+	for v := start; v <= end; v += step {
+		ret |= 1 << uint(v)
+	}
+
+	return ret, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	// names start at the minimum value: Sunday is 0 and January is 1
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+
+	return v, nil
+}
+
+func hasBit(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dom := hasBit(s.dayOfMonth, t.Day())
+	dow := hasBit(s.dayOfWeek, int(t.Weekday()))
+
+	switch {
+	case s.anyDayOfMonth:
+		return dow
+	case s.anyDayOfWeek:
+		return dom
+	}
+
+	return dom || dow
+}
+
+// Next returns the first time after t which matches the schedule, in the
+// location of t. Returns the zero time if no time matches within five
+// years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	cur := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := cur.AddDate(cronMaxLookahead, 0, 0)
+
+	for cur.Before(limit) {
+		var next time.Time
+		switch {
+		case !hasBit(s.month, int(cur.Month())):
+			next = time.Date(cur.Year(), cur.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(cur):
+			next = time.Date(cur.Year(), cur.Month(), cur.Day()+1, 0, 0, 0, 0, loc)
+		case !hasBit(s.hour, cur.Hour()):
+			next = time.Date(cur.Year(), cur.Month(), cur.Day(), cur.Hour()+1, 0, 0, 0, loc)
+		case !hasBit(s.minute, cur.Minute()):
+			next = time.Date(cur.Year(), cur.Month(), cur.Day(), cur.Hour(), cur.Minute()+1, 0, 0, loc)
+		default:
+			return cur
+		}
+
+		// times may be ambiguous when the clocks go back
+		if !next.After(cur) {
+			next = cur.Add(time.Minute)
+		}
+		cur = next
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@every",
+	}
+
+	for _, expr := range invalid {
+		_, err := ParseCron(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2021, time.March, 17, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2021, time.March, 17, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, time.March, 17, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2021, time.March, 18, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2021, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 4 * * sun", time.Date(2021, time.March, 21, 4, 0, 0, 0, time.UTC)},
+		{"0 4 * * 7", time.Date(2021, time.March, 21, 4, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 */3 *", time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 12 * Jan-Feb *", time.Date(2022, time.January, 1, 12, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * mon-fri", time.Date(2021, time.March, 17, 13, 0, 0, 0, time.UTC)},
+		// restricted days of month and week match either
+		{"0 0 20 * mon", time.Date(2021, time.March, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// never matches
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tc := range tests {
+		s, err := ParseCron(tc.expr)
+		if assert.Nil(t, err, tc.expr) {
+			assert.Equal(t, tc.want, s.Next(from), tc.expr)
+		}
+	}
+}
+
+func TestCronScheduleNextDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+
+	// the clocks go forward from 1:00 to 2:00 on 2021-03-28
+	s, _ := ParseCron("30 1 * * *")
+	from := time.Date(2021, time.March, 27, 2, 0, 0, 0, loc)
+	assert.Equal(t, time.Date(2021, time.March, 29, 1, 30, 0, 0, loc), s.Next(from))
+
+	// the clocks go back from 2:00 to 1:00 on 2021-10-31
+	s, _ = ParseCron("15 * * * *")
+	from = time.Date(2021, time.October, 31, 0, 20, 0, 0, loc)
+	next := s.Next(from)
+	assert.True(t, next.After(from))
+	assert.Equal(t, 15, next.Minute())
+}
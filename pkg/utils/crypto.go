@@ -3,7 +3,9 @@ package utils
 import (
 	"crypto/md5"
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"hash/fnv"
 	"io"
 	"os"
@@ -38,6 +40,27 @@ func MD5FromReader(src io.Reader) (string, error) {
 	return fmt.Sprintf("%x", checksum), nil
 }
 
+// VerifyChecksum returns an error if the hex encoded checksum of the file
+// computed with h is not checksum.
+func VerifyChecksum(fn string, checksum string, h hash.Hash) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != checksum {
+		return fmt.Errorf("checksum of %s is %s, expected %s", fn, actual, checksum)
+	}
+
+	return nil
+}
+
 func GenerateRandomKey(l int) string {
 	b := make([]byte, l)
 	rand.Read(b)
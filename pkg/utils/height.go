@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	metricHeightRE   = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(cm|m)?$`)
+	imperialHeightRE = regexp.MustCompile(`^(\d+)\s*(?:'|’|ft|feet|foot)\s*(?:(\d+(?:\.\d+)?)\s*(?:"|''|”|in|inches|inch)?)?$`)
+	inchesHeightRE   = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:"|''|”|in|inches|inch)$`)
+)
+
+const cmPerInch = 2.54
+
+// ParseHeight parses a height string and returns the height in centimetres.
+// Plain numbers are treated as centimetres, unless suffixed with m. Imperial
+// heights may be written as feet and inches (5'7", 5ft 7in) or as inches
+// only (67in).
+func ParseHeight(height string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(height))
+
+	if m := metricHeightRE.FindStringSubmatch(s); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		if m[2] == "m" {
+			v *= 100
+		}
+		return int(math.Round(v)), nil
+	}
+
+	if m := imperialHeightRE.FindStringSubmatch(s); m != nil {
+		feet, _ := strconv.Atoi(m[1])
+		var inches float64
+		if m[2] != "" {
+			inches, _ = strconv.ParseFloat(m[2], 64)
+		}
+		return int(math.Round((float64(feet)*12 + inches) * cmPerInch)), nil
+	}
+
+	if m := inchesHeightRE.FindStringSubmatch(s); m != nil {
+		inches, _ := strconv.ParseFloat(m[1], 64)
+		return int(math.Round(inches * cmPerInch)), nil
+	}
+
+	return 0, fmt.Errorf("invalid height: %s", height)
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseHeight(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"170", 170, false},
+		{"170cm", 170, false},
+		{" 170 CM ", 170, false},
+		{"1.7m", 170, false},
+		{"1.72 m", 172, false},
+		{"5'7\"", 170, false},
+		{"5' 7''", 170, false},
+		{"5ft 7in", 170, false},
+		{"5 ft", 152, false},
+		{"67in", 170, false},
+		{"", 0, true},
+		{"tall", 0, true},
+		{"170-175", 0, true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseHeight(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseHeight(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseHeight(%q) = %d, want %d", tc.input, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var measurementsRE = regexp.MustCompile(`^(\d+)\s*[a-z]*\s*[-/]\s*(\d+)\s*[-/]\s*(\d+)$`)
+
+// ParseMeasurements parses a bust-waist-hips measurements string, such as
+// 34C-24-34, and returns the three measurements in the units they were
+// written in. The cup size of the bust is ignored.
+func ParseMeasurements(measurements string) (bust int, waist int, hips int, err error) {
+	s := strings.ToLower(strings.TrimSpace(measurements))
+
+	m := measurementsRE.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, 0, fmt.Errorf("invalid measurements: %s", measurements)
+	}
+
+	bust, _ = strconv.Atoi(m[1])
+	waist, _ = strconv.Atoi(m[2])
+	hips, _ = strconv.Atoi(m[3])
+	return bust, waist, hips, nil
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseMeasurements(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    [3]int
+		wantErr bool
+	}{
+		{"34C-24-34", [3]int{34, 24, 34}, false},
+		{"34-24-34", [3]int{34, 24, 34}, false},
+		{" 32dd - 25 - 35 ", [3]int{32, 25, 35}, false},
+		{"86/61/89", [3]int{86, 61, 89}, false},
+		{"", [3]int{}, true},
+		{"34C", [3]int{}, true},
+		{"34C-24", [3]int{}, true},
+		{"natural", [3]int{}, true},
+	}
+
+	for _, tc := range testCases {
+		bust, waist, hips, err := ParseMeasurements(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseMeasurements(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			continue
+		}
+		if got := [3]int{bust, waist, hips}; got != tc.want {
+			t.Errorf("ParseMeasurements(%q) = %v, want %v", tc.input, got, tc.want)
+		}
+	}
+}
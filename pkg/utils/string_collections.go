@@ -80,6 +80,22 @@ func StrDelete(vs []string, toDel string) []string {
 	return ret
 }
 
+// StrSliceEquals returns true if the slices have the same values in the
+// same order.
+func StrSliceEquals(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, v := range a {
+		if b[i] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
 // StringSliceToIntSlice converts a slice of strings to a slice of ints.
 // Returns an error if any values cannot be parsed.
 func StringSliceToIntSlice(ss []string) ([]int, error) {
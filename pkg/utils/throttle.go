@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// throttleChunkSize is the maximum number of bytes written in a single
+// throttled write. Larger writes are split so that the output is paced
+// smoothly rather than in bursts.
+const throttleChunkSize = 32 * 1024
+
+// RateLimiter is a token bucket limiting throughput to a number of bytes
+// per second. A rate of zero or less means unlimited. A RateLimiter may be
+// shared between multiple writers.
+type RateLimiter struct {
+	mutex  sync.Mutex
+	rate   int64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter limited to rate bytes per second.
+func NewRateLimiter(rate int64) *RateLimiter {
+	return &RateLimiter{
+		rate:   rate,
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the current rate of the limiter in bytes per second.
+func (l *RateLimiter) Rate() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.rate
+}
+
+// SetRate changes the rate of the limiter. Existing writers pick up the
+// new rate on their next write.
+func (l *RateLimiter) SetRate(rate int64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.rate == rate {
+		return
+	}
+
+	l.rate = rate
+	l.tokens = float64(rate)
+	l.last = time.Now()
+}
+
+// reserve takes n bytes from the bucket and returns the time the caller
+// must wait before sending them.
+func (l *RateLimiter) reserve(n int) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	rate := float64(l.rate)
+
+	// allow bursts of up to one second of data
+	l.tokens += now.Sub(l.last).Seconds() * rate
+	if l.tokens > rate {
+		l.tokens = rate
+	}
+	l.last = now
+
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / rate * float64(time.Second))
+}
+
+// ThrottledWriter is an io.Writer that paces writes to the underlying
+// writer according to one or more RateLimiters. The slowest limiter
+// determines the effective rate.
+type ThrottledWriter struct {
+	ctx      context.Context
+	w        io.Writer
+	limiters []*RateLimiter
+}
+
+// NewThrottledWriter returns a writer that writes to w, limited by the
+// provided limiters. Nil limiters are ignored. Pending writes are abandoned
+// when ctx is done.
+func NewThrottledWriter(ctx context.Context, w io.Writer, limiters ...*RateLimiter) *ThrottledWriter {
+	ret := &ThrottledWriter{
+		ctx: ctx,
+		w:   w,
+	}
+
+	for _, l := range limiters {
+		if l != nil {
+			ret.limiters = append(ret.limiters, l)
+		}
+	}
+
+	return ret
+}
+
+func (t *ThrottledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > throttleChunkSize {
+			chunk = chunk[:throttleChunkSize]
+		}
+
+		if err := t.wait(len(chunk)); err != nil {
+			return written, err
+		}
+
+		n, err := t.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		p = p[len(chunk):]
+	}
+
+	return written, nil
+}
+
+func (t *ThrottledWriter) wait(n int) error {
+	var delay time.Duration
+	for _, l := range t.limiters {
+		if d := l.reserve(n); d > delay {
+			delay = d
+		}
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	}
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	assert := assert.New(t)
+
+	const rate = 1000
+
+	l := NewRateLimiter(rate)
+
+	// initial burst is allowed
+	assert.Equal(time.Duration(0), l.reserve(rate))
+
+	// exceeding the burst requires waiting
+	d := l.reserve(rate / 2)
+	assert.True(d > 400*time.Millisecond && d <= 500*time.Millisecond)
+
+	// unlimited never waits
+	l.SetRate(0)
+	assert.Equal(time.Duration(0), l.reserve(rate*100))
+}
+
+func TestThrottledWriterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	l := NewRateLimiter(1)
+	w := NewThrottledWriter(ctx, &buf, l, nil)
+
+	n, err := w.Write([]byte("abc"))
+	assert.Equal(t, 0, n)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -71,12 +71,44 @@ export const ImageFileInfoPanel: React.FC<IImageFileInfoPanelProps> = (
     }
   }
 
+  function renderTakenAt() {
+    if (props.image.taken_at) {
+      return (
+        <div className="row">
+          <span className="col-4">Taken</span>
+          <span className="col-8 text-truncate">
+            {new Date(props.image.taken_at).toLocaleString()}
+          </span>
+        </div>
+      );
+    }
+  }
+
+  function renderCamera() {
+    const { camera_make: make, camera_model: model } = props.image;
+    // the model often includes the make
+    const camera =
+      make && model && !model.startsWith(make)
+        ? `${make} ${model}`
+        : model || make;
+    if (camera) {
+      return (
+        <div className="row">
+          <span className="col-4">Camera</span>
+          <TruncatedText className="col-8" text={camera} />
+        </div>
+      );
+    }
+  }
+
   return (
     <div className="container image-file-info">
       {renderChecksum()}
       {renderPath()}
       {renderFileSize()}
       {renderDimensions()}
+      {renderTakenAt()}
+      {renderCamera()}
     </div>
   );
 };
@@ -155,7 +155,8 @@ export const AddFilter: React.FC<IAddFilterProps> = (
           criterion.type !== "tags" &&
           criterion.type !== "sceneTags" &&
           criterion.type !== "performerTags" &&
-          criterion.type !== "movies"
+          criterion.type !== "movies" &&
+          criterion.type !== "groups"
         )
           return;
 
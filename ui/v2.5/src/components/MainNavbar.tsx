@@ -15,6 +15,7 @@ import { SessionUtils } from "src/utils";
 import { Icon } from "src/components/Shared";
 import { Manual } from "./Help/Manual";
 import { useConfiguration } from "../core/StashService";
+import { getBasePath } from "../core/createClient";
 
 interface IMenuItem {
   name: string;
@@ -232,7 +233,10 @@ export const MainNavbar: React.FC = () => {
   function maybeRenderLogout() {
     if (SessionUtils.isLoggedIn()) {
       return (
-        <Button className="minimal logout-button" href="/logout">
+        <Button
+          className="minimal logout-button"
+          href={`${getBasePath()}logout`}
+        >
           <Icon icon="sign-out-alt" />
         </Button>
       );
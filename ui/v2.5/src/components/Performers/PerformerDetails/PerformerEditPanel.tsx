@@ -122,6 +122,7 @@ export const PerformerEditPanel: React.FC<IPerformerDetails> = ({
     death_date: yup.string().optional(),
     hair_color: yup.string().optional(),
     weight: yup.number().optional(),
+    auto_tag_regex: yup.string().optional(),
   });
 
   const initialValues = {
@@ -149,6 +150,7 @@ export const PerformerEditPanel: React.FC<IPerformerDetails> = ({
     death_date: performer.death_date ?? "",
     hair_color: performer.hair_color ?? "",
     weight: performer.weight ?? undefined,
+    auto_tag_regex: performer.auto_tag_regex ?? "",
   };
 
   type InputValues = typeof initialValues;
@@ -893,6 +895,8 @@ export const PerformerEditPanel: React.FC<IPerformerDetails> = ({
           </Col>
         </Form.Group>
 
+        {renderTextField("auto_tag_regex", "Auto Tag Regex")}
+
         <Form.Group as={Row}>
           <Form.Label column xs={labelXS} xl={labelXL}>
             Gender
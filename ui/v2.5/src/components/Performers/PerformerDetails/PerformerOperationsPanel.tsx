@@ -1,7 +1,12 @@
 import { Button } from "react-bootstrap";
 import React from "react";
 import * as GQL from "src/core/generated-graphql";
-import { mutateMetadataAutoTag } from "src/core/StashService";
+import {
+  mutateMetadataAutoTag,
+  mutateSubmitStashBoxPerformerDraft,
+  stashBoxDraftURL,
+  useConfiguration,
+} from "src/core/StashService";
 import { useToast } from "src/hooks";
 
 interface IPerformerOperationsProps {
@@ -12,6 +17,8 @@ export const PerformerOperationsPanel: React.FC<IPerformerOperationsProps> = ({
   performer,
 }) => {
   const Toast = useToast();
+  const stashConfig = useConfiguration();
+  const stashBoxes = stashConfig.data?.configuration.general.stashBoxes ?? [];
 
   async function onAutoTag() {
     if (!performer?.id) {
@@ -25,5 +32,38 @@ export const PerformerOperationsPanel: React.FC<IPerformerOperationsProps> = ({
     }
   }
 
-  return <Button onClick={onAutoTag}>Auto Tag</Button>;
+  async function onSubmitDraft(index: number) {
+    const box = stashBoxes[index];
+    if (!performer?.id || !box) {
+      return;
+    }
+    try {
+      const result = await mutateSubmitStashBoxPerformerDraft({
+        id: performer.id,
+        stash_box_index: index,
+      });
+      const draftID = result.data?.submitStashBoxPerformerDraft;
+      if (draftID) {
+        window.open(stashBoxDraftURL(box.endpoint, draftID), "_blank");
+      }
+      Toast.success({ content: "Submitted draft" });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  return (
+    <>
+      <Button onClick={onAutoTag}>Auto Tag</Button>
+      {stashBoxes.map((box, index) => (
+        <Button
+          key={box.endpoint}
+          className="ml-2"
+          onClick={() => onSubmitDraft(index)}
+        >
+          Submit draft to {box.name || "Stash-Box"}
+        </Button>
+      ))}
+    </>
+  );
 };
@@ -208,6 +208,13 @@ export class ScenePlayerImpl extends React.Component<
           file: scene.paths.chapters_vtt,
           kind: "chapters",
         },
+        ...scene.captions.map((c, i) => {
+          return {
+            file: c.url,
+            kind: "captions",
+            label: c.title || c.language_code || `Caption ${i + 1}`,
+          };
+        }),
       ],
       sources: this.props.sceneStreams.map((s) => {
         return {
@@ -48,6 +48,9 @@ export const PrimaryTags: React.FC<IPrimaryTags> = ({
             >
               Edit
             </Button>
+            <Button variant="link" href={marker.clip} download>
+              Clip
+            </Button>
           </div>
           <div>{TextUtils.secondsToTimestamp(marker.seconds)}</div>
           <div className="card-section centered">{tags}</div>
@@ -5,12 +5,16 @@ import { useParams, useLocation, useHistory, Link } from "react-router-dom";
 import * as GQL from "src/core/generated-graphql";
 import {
   mutateMetadataScan,
+  mutateSubmitStashBoxSceneDraft,
+  stashBoxDraftURL,
+  useConfiguration,
   useFindScene,
   useSceneIncrementO,
   useSceneDecrementO,
   useSceneResetO,
   useSceneStreams,
   useSceneGenerateScreenshot,
+  useSceneSetCoverFromFrame,
   useSceneUpdate,
   queryFindScenes,
   queryFindScenesByID,
@@ -34,6 +38,7 @@ import { SceneMoviePanel } from "./SceneMoviePanel";
 import { SceneGalleriesPanel } from "./SceneGalleriesPanel";
 import { DeleteScenesDialog } from "../DeleteScenesDialog";
 import { SceneGenerateDialog } from "../SceneGenerateDialog";
+import { SceneCastDialog } from "./SceneCastDialog";
 import { SceneVideoFilterPanel } from "./SceneVideoFilterPanel";
 import { OrganizedButton } from "./OrganizedButton";
 
@@ -46,8 +51,10 @@ export const Scene: React.FC = () => {
   const location = useLocation();
   const history = useHistory();
   const Toast = useToast();
+  const stashConfig = useConfiguration();
   const [updateScene] = useSceneUpdate();
   const [generateScreenshot] = useSceneGenerateScreenshot();
+  const [setCoverFromFrame] = useSceneSetCoverFromFrame();
   const [timestamp, setTimestamp] = useState<number>(getInitialTimestamp());
   const [collapsed, setCollapsed] = useState(false);
 
@@ -70,6 +77,7 @@ export const Scene: React.FC = () => {
 
   const [isDeleteAlertOpen, setIsDeleteAlertOpen] = useState<boolean>(false);
   const [isGenerateDialogOpen, setIsGenerateDialogOpen] = useState(false);
+  const [castStartTime, setCastStartTime] = useState<number | undefined>();
 
   const [sceneQueue, setSceneQueue] = useState<SceneQueue>(new SceneQueue());
   const [queueScenes, setQueueScenes] = useState<GQL.SlimSceneDataFragment[]>(
@@ -215,6 +223,45 @@ export const Scene: React.FC = () => {
     Toast.success({ content: "Generating screenshot" });
   }
 
+  async function onSetCoverFromFrame(at: number) {
+    if (!scene) {
+      return;
+    }
+
+    try {
+      await setCoverFromFrame({
+        variables: {
+          id: scene.id,
+          at,
+        },
+      });
+      Toast.success({ content: "Set cover from current frame" });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onSubmitDraft(index: number) {
+    const box = stashConfig.data?.configuration.general.stashBoxes[index];
+    if (!scene || !box) {
+      return;
+    }
+
+    try {
+      const result = await mutateSubmitStashBoxSceneDraft({
+        id: scene.id,
+        stash_box_index: index,
+      });
+      const draftID = result.data?.submitStashBoxSceneDraft;
+      if (draftID) {
+        window.open(stashBoxDraftURL(box.endpoint, draftID), "_blank");
+      }
+      Toast.success({ content: "Submitted draft" });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
   async function onQueueLessScenes() {
     if (!sceneQueue.query || queueStart <= 1) {
       return;
@@ -336,6 +383,25 @@ export const Scene: React.FC = () => {
     }
   }
 
+  function maybeRenderSceneCastDialog() {
+    if (castStartTime !== undefined && scene) {
+      return (
+        <SceneCastDialog
+          sceneId={scene.id}
+          startTime={castStartTime}
+          onClose={() => setCastStartTime(undefined)}
+        />
+      );
+    }
+  }
+
+  function onCast() {
+    const player = JWUtils.getPlayer();
+    // pause the local player while the scene plays on the device
+    player?.pause();
+    setCastStartTime(player?.getPosition() ?? 0);
+  }
+
   function renderOperations() {
     return (
       <Dropdown>
@@ -366,10 +432,10 @@ export const Scene: React.FC = () => {
             key="generate-screenshot"
             className="bg-secondary text-white"
             onClick={() =>
-              onGenerateScreenshot(JWUtils.getPlayer().getPosition())
+              onSetCoverFromFrame(JWUtils.getPlayer().getPosition())
             }
           >
-            Generate thumbnail from current
+            Set cover from current frame
           </Dropdown.Item>
           <Dropdown.Item
             key="generate-default"
@@ -378,6 +444,24 @@ export const Scene: React.FC = () => {
           >
             Generate default thumbnail
           </Dropdown.Item>
+          <Dropdown.Item
+            key="cast"
+            className="bg-secondary text-white"
+            onClick={() => onCast()}
+          >
+            Cast...
+          </Dropdown.Item>
+          {(stashConfig.data?.configuration.general.stashBoxes ?? []).map(
+            (box, index) => (
+              <Dropdown.Item
+                key={`submit-draft-${box.endpoint}`}
+                className="bg-secondary text-white"
+                onClick={() => onSubmitDraft(index)}
+              >
+                Submit draft to {box.name || "Stash-Box"}
+              </Dropdown.Item>
+            )
+          )}
           <Dropdown.Item
             key="delete-scene"
             className="bg-secondary text-white"
@@ -551,6 +635,7 @@ export const Scene: React.FC = () => {
   return (
     <div className="row">
       {maybeRenderSceneGenerateDialog()}
+      {maybeRenderSceneCastDialog()}
       {maybeRenderDeleteDialog()}
       <div
         className={`scene-tabs order-xl-first order-last ${
@@ -595,6 +680,13 @@ export const Scene: React.FC = () => {
             onComplete={onComplete}
           />
         ) : undefined}
+        {scene.paths.interactive_heatmap ? (
+          <img
+            className="scene-interactive-heatmap"
+            src={scene.paths.interactive_heatmap}
+            alt="Interactive heatmap"
+          />
+        ) : undefined}
       </div>
     </div>
   );
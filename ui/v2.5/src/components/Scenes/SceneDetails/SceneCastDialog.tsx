@@ -0,0 +1,103 @@
+import React, { useState } from "react";
+import { Button, Form } from "react-bootstrap";
+import { mutateCastScene, useCastDevices } from "src/core/StashService";
+import { Modal, Icon, LoadingIndicator } from "src/components/Shared";
+import { useToast } from "src/hooks";
+
+interface ISceneCastDialogProps {
+  sceneId: string;
+  // position in seconds from which playback starts on the device
+  startTime?: number;
+  onClose: () => void;
+}
+
+export const SceneCastDialog: React.FC<ISceneCastDialogProps> = ({
+  sceneId,
+  startTime,
+  onClose,
+}) => {
+  const Toast = useToast();
+  const { data, loading, refetch } = useCastDevices();
+  const [host, setHost] = useState("");
+  const [isRunning, setIsRunning] = useState(false);
+
+  const devices = data?.castDevices ?? [];
+
+  async function onCast() {
+    const device = devices.find((d) => d.host === host);
+
+    try {
+      setIsRunning(true);
+      await mutateCastScene({
+        scene_id: sceneId,
+        host,
+        port: device?.port,
+        start_time: startTime,
+      });
+      Toast.success({ content: `Playing on ${device?.name ?? host}` });
+      onClose();
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsRunning(false);
+    }
+  }
+
+  function renderDevices() {
+    if (loading) {
+      return <LoadingIndicator inline message="Searching for devices..." />;
+    }
+
+    if (devices.length === 0) {
+      return <p>No cast devices were found on the network.</p>;
+    }
+
+    return devices.map((d) => (
+      <Form.Check
+        type="radio"
+        key={d.id}
+        id={`cast-device-${d.id}`}
+        checked={host === d.host}
+        onChange={() => setHost(d.host)}
+        label={d.model ? `${d.name} (${d.model})` : d.name}
+      />
+    ));
+  }
+
+  return (
+    <Modal
+      show
+      icon="tv"
+      header="Cast"
+      accept={{ onClick: onCast, text: "Cast" }}
+      cancel={{ onClick: onClose, text: "Cancel", variant: "secondary" }}
+      disabled={!host}
+      isRunning={isRunning}
+    >
+      <Form>
+        <Form.Group>{renderDevices()}</Form.Group>
+        <Form.Group>
+          <Form.Label>Device address</Form.Label>
+          <Form.Control
+            className="text-input"
+            placeholder="Address of a device which was not found"
+            value={host}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setHost(e.currentTarget.value.trim())
+            }
+          />
+        </Form.Group>
+        <Button
+          variant="secondary"
+          disabled={loading}
+          onClick={() => refetch()}
+        >
+          <span className="fa-icon">
+            <Icon icon="sync-alt" />
+          </span>
+          <span>Search again</span>
+        </Button>
+      </Form>
+    </Modal>
+  );
+};
@@ -58,6 +58,19 @@ export const SceneFileInfoPanel: React.FC<ISceneFileInfoPanelProps> = (
     );
   }
 
+  function renderFunscript() {
+    if (props.scene.paths.funscript) {
+      return (
+        <div className="row">
+          <span className="col-4">Funscript</span>
+          <a href={props.scene.paths.funscript} className="col-8">
+            <TruncatedText text={props.scene.paths.funscript} />
+          </a>{" "}
+        </div>
+      );
+    }
+  }
+
   function renderFileSize() {
     if (props.scene.file.size === undefined) {
       return;
@@ -232,6 +245,20 @@ export const SceneFileInfoPanel: React.FC<ISceneFileInfoPanelProps> = (
     }
   }
 
+  function renderDecodeError() {
+    if (props.scene.decode_error) {
+      return (
+        <div className="row">
+          <span className="col-4">Decode Error</span>
+          <TruncatedText
+            className="col-8 text-danger"
+            text={props.scene.decode_error}
+          />
+        </div>
+      );
+    }
+  }
+
   return (
     <div className="container scene-file-info">
       {renderOSHash()}
@@ -239,6 +266,7 @@ export const SceneFileInfoPanel: React.FC<ISceneFileInfoPanelProps> = (
       {renderPhash()}
       {renderPath()}
       {renderStream()}
+      {renderFunscript()}
       {renderFileSize()}
       {renderDuration()}
       {renderDimensions()}
@@ -246,6 +274,7 @@ export const SceneFileInfoPanel: React.FC<ISceneFileInfoPanelProps> = (
       {renderbitrate()}
       {renderVideoCodec()}
       {renderAudioCodec()}
+      {renderDecodeError()}
       {renderUrl()}
       {renderStashIDs()}
     </div>
@@ -4,12 +4,19 @@ import {
   mutateMetadataGenerate,
   useConfiguration,
 } from "src/core/StashService";
-import { Modal, Icon } from "src/components/Shared";
+import {
+  Modal,
+  Icon,
+  GenerateOverwriteOptions,
+  IGenerateOverwrite,
+} from "src/components/Shared";
 import { useToast } from "src/hooks";
 import * as GQL from "src/core/generated-graphql";
 
 interface ISceneGenerateDialogProps {
-  selectedIds: string[];
+  selectedIds?: string[];
+  // generate for the scenes matching the filter instead of the selected ids
+  sceneFilter?: GQL.SceneFilterType;
   onClose: () => void;
 }
 
@@ -23,7 +30,17 @@ export const SceneGenerateDialog: React.FC<ISceneGenerateDialogProps> = (
   const [previews, setPreviews] = useState(true);
   const [markers, setMarkers] = useState(true);
   const [transcodes, setTranscodes] = useState(false);
-  const [overwrite, setOverwrite] = useState(true);
+  const [interactiveHeatmapsSpeeds, setInteractiveHeatmapsSpeeds] = useState(
+    false
+  );
+  const [overwrite, setOverwrite] = useState<IGenerateOverwrite>({
+    sprites: true,
+    previews: true,
+    markers: true,
+    transcodes: true,
+    phashes: false,
+    interactiveHeatmapsSpeeds: true,
+  });
   const [imagePreviews, setImagePreviews] = useState(false);
 
   const [previewSegments, setPreviewSegments] = useState<number>(0);
@@ -66,8 +83,16 @@ export const SceneGenerateDialog: React.FC<ISceneGenerateDialogProps> = (
         imagePreviews: previews && imagePreviews,
         markers,
         transcodes,
-        overwrite,
+        overwriteSprites: sprites && overwrite.sprites,
+        overwritePreviews: previews && overwrite.previews,
+        overwriteMarkers: markers && overwrite.markers,
+        overwriteTranscodes: transcodes && overwrite.transcodes,
+        overwritePhashes: phashes && overwrite.phashes,
+        interactiveHeatmapsSpeeds,
+        overwriteInteractiveHeatmapsSpeeds:
+          interactiveHeatmapsSpeeds && overwrite.interactiveHeatmapsSpeeds,
         sceneIDs: props.selectedIds,
+        sceneFilter: props.sceneFilter,
         previewOptions: {
           previewPreset: (previewPreset as GQL.PreviewPreset) ?? undefined,
           previewSegments,
@@ -251,15 +276,30 @@ export const SceneGenerateDialog: React.FC<ISceneGenerateDialogProps> = (
             label="Perceptual hashes (for deduplication)"
             onChange={() => setPhashes(!phashes)}
           />
-
-          <hr />
           <Form.Check
-            id="overwrite"
-            checked={overwrite}
-            label="Overwrite existing generated files"
-            onChange={() => setOverwrite(!overwrite)}
+            id="interactive-heatmap-speed-task"
+            checked={interactiveHeatmapsSpeeds}
+            label="Heatmaps and speeds (for the funscripts of interactive scenes)"
+            onChange={() =>
+              setInteractiveHeatmapsSpeeds(!interactiveHeatmapsSpeeds)
+            }
           />
         </Form.Group>
+
+        <hr />
+        <GenerateOverwriteOptions
+          id="scene-generate-overwrite"
+          generate={{
+            sprites,
+            previews,
+            markers,
+            transcodes,
+            phashes,
+            interactiveHeatmapsSpeeds,
+          }}
+          overwrite={overwrite}
+          onChange={setOverwrite}
+        />
       </Form>
     </Modal>
   );
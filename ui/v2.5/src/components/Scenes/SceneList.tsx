@@ -4,6 +4,7 @@ import { useHistory } from "react-router-dom";
 import Mousetrap from "mousetrap";
 import {
   FindScenesQueryResult,
+  SceneFilterType,
   SlimSceneDataFragment,
 } from "src/core/generated-graphql";
 import { queryFindScenes } from "src/core/StashService";
@@ -34,6 +35,9 @@ export const SceneList: React.FC<ISceneList> = ({
 }) => {
   const history = useHistory();
   const [isGenerateDialogOpen, setIsGenerateDialogOpen] = useState(false);
+  const [generateFilter, setGenerateFilter] = useState<
+    SceneFilterType | undefined
+  >(undefined);
   const [isExportDialogOpen, setIsExportDialogOpen] = useState(false);
   const [isExportAll, setIsExportAll] = useState(false);
 
@@ -52,6 +56,11 @@ export const SceneList: React.FC<ISceneList> = ({
       onClick: generate,
       isDisplayed: showWhenSelected,
     },
+    {
+      text: "Generate all...",
+      onClick: generateAll,
+      isDisplayed: showGenerateAll,
+    },
     {
       text: "Export...",
       onClick: onExport,
@@ -130,9 +139,27 @@ export const SceneList: React.FC<ISceneList> = ({
   }
 
   async function generate() {
+    setGenerateFilter(undefined);
+    setIsGenerateDialogOpen(true);
+  }
+
+  async function generateAll(
+    _result: FindScenesQueryResult,
+    filter: ListFilterModel
+  ) {
+    setGenerateFilter(filter.makeSceneFilter());
     setIsGenerateDialogOpen(true);
   }
 
+  // the scene filter does not include the search term
+  function showGenerateAll(
+    _result: FindScenesQueryResult,
+    filter: ListFilterModel,
+    selectedIds: Set<string>
+  ) {
+    return selectedIds.size === 0 && !filter.searchTerm;
+  }
+
   async function onExport() {
     setIsExportAll(false);
     setIsExportDialogOpen(true);
@@ -148,7 +175,10 @@ export const SceneList: React.FC<ISceneList> = ({
       return (
         <>
           <SceneGenerateDialog
-            selectedIds={Array.from(selectedIds.values())}
+            selectedIds={
+              generateFilter ? undefined : Array.from(selectedIds.values())
+            }
+            sceneFilter={generateFilter}
             onClose={() => {
               setIsGenerateDialogOpen(false);
             }}
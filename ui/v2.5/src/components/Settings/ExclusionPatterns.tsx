@@ -0,0 +1,58 @@
+import React from "react";
+import { Button, Form, InputGroup } from "react-bootstrap";
+import { Icon } from "src/components/Shared";
+
+interface IExclusionPatternsProps {
+  excludes: string[];
+  setExcludes: (value: string[]) => void;
+}
+
+export const ExclusionPatterns: React.FC<IExclusionPatternsProps> = (props) => {
+  function excludeRegexChanged(idx: number, value: string) {
+    const newExcludes = props.excludes.map((regex, i) => {
+      const ret = idx !== i ? regex : value;
+      return ret;
+    });
+    props.setExcludes(newExcludes);
+  }
+
+  function excludeRemoveRegex(idx: number) {
+    const newExcludes = props.excludes.filter((_regex, i) => i !== idx);
+
+    props.setExcludes(newExcludes);
+  }
+
+  function excludeAddRegex() {
+    const demo = "sample\\.mp4$";
+    const newExcludes = props.excludes.concat(demo);
+
+    props.setExcludes(newExcludes);
+  }
+
+  return (
+    <>
+      <Form.Group>
+        {props.excludes &&
+          props.excludes.map((regexp, i) => (
+            <InputGroup>
+              <Form.Control
+                className="col col-sm-6 text-input"
+                value={regexp}
+                onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                  excludeRegexChanged(i, e.currentTarget.value)
+                }
+              />
+              <InputGroup.Append>
+                <Button variant="danger" onClick={() => excludeRemoveRegex(i)}>
+                  <Icon icon="minus" />
+                </Button>
+              </InputGroup.Append>
+            </InputGroup>
+          ))}
+      </Form.Group>
+      <Button className="minimal" onClick={() => excludeAddRegex()}>
+        <Icon icon="plus" />
+      </Button>
+    </>
+  );
+};
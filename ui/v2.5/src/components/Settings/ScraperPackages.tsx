@@ -0,0 +1,221 @@
+import React, { useState } from "react";
+import { Button, Form } from "react-bootstrap";
+import {
+  mutateInstallScraperPackages,
+  mutateUninstallScraperPackages,
+  mutateUpdateScraperPackages,
+  useAvailableScraperPackages,
+  useConfiguration,
+  useInstalledScraperPackages,
+} from "src/core/StashService";
+import { useToast } from "src/hooks";
+import { Icon, LoadingIndicator } from "src/components/Shared";
+
+export const ScraperPackages: React.FC = () => {
+  const Toast = useToast();
+  const { data: config } = useConfiguration();
+  const [checkUpdates, setCheckUpdates] = useState(false);
+  const [sourceURL, setSourceURL] = useState("");
+
+  const {
+    data: installedData,
+    loading: loadingInstalled,
+    refetch: refetchInstalled,
+  } = useInstalledScraperPackages(checkUpdates);
+  const {
+    data: availableData,
+    loading: loadingAvailable,
+  } = useAvailableScraperPackages(sourceURL);
+
+  const sources = config?.configuration.general.scraperPackageSources ?? [];
+  const installed = installedData?.installedScraperPackages ?? [];
+  const available = availableData?.availableScraperPackages ?? [];
+
+  async function onInstall(ids: string[]) {
+    try {
+      await mutateInstallScraperPackages({ source_url: sourceURL, ids });
+      Toast.success({ content: "Started installing scraper packages" });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onUpdate(ids?: string[]) {
+    try {
+      await mutateUpdateScraperPackages(ids);
+      Toast.success({ content: "Started updating scraper packages" });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onUninstall(id: string) {
+    try {
+      await mutateUninstallScraperPackages([id]);
+      Toast.success({ content: "Uninstalled scraper package" });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  function onCheckUpdates() {
+    if (checkUpdates) {
+      refetchInstalled();
+    } else {
+      setCheckUpdates(true);
+    }
+  }
+
+  function renderInstalled() {
+    if (loadingInstalled) {
+      return <LoadingIndicator inline />;
+    }
+
+    if (installed.length === 0) {
+      return <p>No scraper packages are installed.</p>;
+    }
+
+    return (
+      <table className="scraper-table">
+        <thead>
+          <tr>
+            <th>Name</th>
+            <th>Version</th>
+            <th>Available version</th>
+            <th />
+          </tr>
+        </thead>
+        <tbody>
+          {installed.map((p) => (
+            <tr key={p.id}>
+              <td>{p.name}</td>
+              <td>
+                {p.version}
+                {p.date && <div className="text-muted small">{p.date}</div>}
+              </td>
+              <td>{p.available_version}</td>
+              <td>
+                {p.available_version && (
+                  <Button
+                    size="sm"
+                    className="mr-2"
+                    onClick={() => onUpdate([p.id])}
+                  >
+                    Update
+                  </Button>
+                )}
+                <Button
+                  size="sm"
+                  variant="danger"
+                  onClick={() => onUninstall(p.id)}
+                >
+                  Uninstall
+                </Button>
+              </td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    );
+  }
+
+  function renderAvailable() {
+    if (!sourceURL) {
+      return;
+    }
+
+    if (loadingAvailable) {
+      return <LoadingIndicator inline />;
+    }
+
+    const installedVersions = new Map(
+      installed
+        .filter((p) => p.source_url === sourceURL)
+        .map((p) => [p.id, p.version])
+    );
+
+    return (
+      <table className="scraper-table">
+        <thead>
+          <tr>
+            <th>Name</th>
+            <th>Version</th>
+            <th>Requires</th>
+            <th />
+          </tr>
+        </thead>
+        <tbody>
+          {available.map((p) => (
+            <tr key={p.id}>
+              <td>{p.name}</td>
+              <td>
+                {p.version}
+                {p.date && <div className="text-muted small">{p.date}</div>}
+              </td>
+              <td>{p.requires.join(", ")}</td>
+              <td>
+                {installedVersions.has(p.id) ? (
+                  <span className="text-muted">
+                    Installed {installedVersions.get(p.id)}
+                  </span>
+                ) : (
+                  <Button size="sm" onClick={() => onInstall([p.id])}>
+                    Install
+                  </Button>
+                )}
+              </td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    );
+  }
+
+  return (
+    <div className="mb-3">
+      <h5>Installed scraper packages</h5>
+      <div className="mb-2">
+        <Button className="mr-2" onClick={() => onCheckUpdates()}>
+          <span className="fa-icon">
+            <Icon icon="sync-alt" />
+          </span>
+          <span>Check for updates</span>
+        </Button>
+        <Button
+          disabled={!installed.some((p) => p.available_version)}
+          onClick={() => onUpdate()}
+        >
+          Update all
+        </Button>
+      </div>
+      {renderInstalled()}
+
+      <h5 className="mt-3">Available scraper packages</h5>
+      {sources.length === 0 ? (
+        <p>
+          No scraper package sources are configured. Add sources to{" "}
+          <code>scraper_package_sources</code> in the configuration file.
+        </p>
+      ) : (
+        <>
+          <Form.Control
+            as="select"
+            className="col-md-4 col-6 input-control mb-2"
+            value={sourceURL}
+            onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+              setSourceURL(e.currentTarget.value)
+            }
+          >
+            <option value="">Select a source</option>
+            {sources.map((s) => (
+              <option key={s.url} value={s.url}>
+                {s.name || s.url}
+              </option>
+            ))}
+          </Form.Control>
+          {renderAvailable()}
+        </>
+      )}
+    </div>
+  );
+};
@@ -2,6 +2,7 @@ import React from "react";
 import queryString from "query-string";
 import { Card, Tab, Nav, Row, Col } from "react-bootstrap";
 import { useHistory, useLocation } from "react-router-dom";
+import { useConfiguration } from "src/core/StashService";
 import { SettingsAboutPanel } from "./SettingsAboutPanel";
 import { SettingsConfigurationPanel } from "./SettingsConfigurationPanel";
 import { SettingsInterfacePanel } from "./SettingsInterfacePanel/SettingsInterfacePanel";
@@ -14,7 +15,12 @@ import { SettingsToolsPanel } from "./SettingsToolsPanel";
 export const Settings: React.FC = () => {
   const location = useLocation();
   const history = useHistory();
-  const defaultTab = queryString.parse(location.search).tab ?? "tasks";
+  const { data: config } = useConfiguration();
+  // tasks cannot be run when the server is in read-only mode
+  const readOnly = config?.configuration.general.readOnly ?? false;
+  const defaultTab =
+    queryString.parse(location.search).tab ??
+    (readOnly ? "interface" : "tasks");
 
   const onSelect = (val: string) => history.push(`?tab=${val}`);
 
@@ -34,9 +40,11 @@ export const Settings: React.FC = () => {
               <Nav.Item>
                 <Nav.Link eventKey="interface">Interface</Nav.Link>
               </Nav.Item>
-              <Nav.Item>
-                <Nav.Link eventKey="tasks">Tasks</Nav.Link>
-              </Nav.Item>
+              {!readOnly && (
+                <Nav.Item>
+                  <Nav.Link eventKey="tasks">Tasks</Nav.Link>
+                </Nav.Item>
+              )}
               <Nav.Item>
                 <Nav.Link eventKey="tools">Tools</Nav.Link>
               </Nav.Item>
@@ -63,9 +71,11 @@ export const Settings: React.FC = () => {
               <Tab.Pane eventKey="interface">
                 <SettingsInterfacePanel />
               </Tab.Pane>
-              <Tab.Pane eventKey="tasks">
-                <SettingsTasksPanel />
-              </Tab.Pane>
+              {!readOnly && (
+                <Tab.Pane eventKey="tasks">
+                  <SettingsTasksPanel />
+                </Tab.Pane>
+              )}
               <Tab.Pane eventKey="tools" unmountOnExit>
                 <SettingsToolsPanel />
               </Tab.Pane>
@@ -12,60 +12,15 @@ import StashBoxConfiguration, {
   IStashBoxInstance,
 } from "./StashBoxConfiguration";
 import StashConfiguration from "./StashConfiguration";
-
-interface IExclusionPatternsProps {
-  excludes: string[];
-  setExcludes: (value: string[]) => void;
-}
-
-const ExclusionPatterns: React.FC<IExclusionPatternsProps> = (props) => {
-  function excludeRegexChanged(idx: number, value: string) {
-    const newExcludes = props.excludes.map((regex, i) => {
-      const ret = idx !== i ? regex : value;
-      return ret;
-    });
-    props.setExcludes(newExcludes);
-  }
-
-  function excludeRemoveRegex(idx: number) {
-    const newExcludes = props.excludes.filter((_regex, i) => i !== idx);
-
-    props.setExcludes(newExcludes);
-  }
-
-  function excludeAddRegex() {
-    const demo = "sample\\.mp4$";
-    const newExcludes = props.excludes.concat(demo);
-
-    props.setExcludes(newExcludes);
-  }
-
-  return (
-    <>
-      <Form.Group>
-        {props.excludes &&
-          props.excludes.map((regexp, i) => (
-            <InputGroup>
-              <Form.Control
-                className="col col-sm-6 text-input"
-                value={regexp}
-                onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
-                  excludeRegexChanged(i, e.currentTarget.value)
-                }
-              />
-              <InputGroup.Append>
-                <Button variant="danger" onClick={() => excludeRemoveRegex(i)}>
-                  <Icon icon="minus" />
-                </Button>
-              </InputGroup.Append>
-            </InputGroup>
-          ))}
-      </Form.Group>
-      <Button className="minimal" onClick={() => excludeAddRegex()}>
-        <Icon icon="plus" />
-      </Button>
-    </>
-  );
+import { ExclusionPatterns } from "./ExclusionPatterns";
+
+const hardwareAccelerationLabels: Record<GQL.HardwareAcceleration, string> = {
+  [GQL.HardwareAcceleration.None]: "None",
+  [GQL.HardwareAcceleration.Auto]: "Auto",
+  [GQL.HardwareAcceleration.Nvenc]: "NVIDIA NVENC",
+  [GQL.HardwareAcceleration.Qsv]: "Intel Quick Sync Video",
+  [GQL.HardwareAcceleration.Vaapi]: "VAAPI",
+  [GQL.HardwareAcceleration.Videotoolbox]: "Apple VideoToolbox",
 };
 
 export const SettingsConfigurationPanel: React.FC = () => {
@@ -79,12 +34,41 @@ export const SettingsConfigurationPanel: React.FC = () => {
     undefined
   );
   const [cachePath, setCachePath] = useState<string | undefined>(undefined);
+  const [blobsStorage, setBlobsStorage] = useState<GQL.BlobsStorageType>(
+    GQL.BlobsStorageType.Database
+  );
+  const [blobsPath, setBlobsPath] = useState<string | undefined>(undefined);
   const [calculateMD5, setCalculateMD5] = useState<boolean>(false);
   const [videoFileNamingAlgorithm, setVideoFileNamingAlgorithm] = useState<
     GQL.HashAlgorithm | undefined
   >(undefined);
   const [parallelTasks, setParallelTasks] = useState<number>(0);
+  const [hashWorkers, setHashWorkers] = useState<number>(0);
+  const [probeWorkers, setProbeWorkers] = useState<number>(0);
+  const [generateWorkers, setGenerateWorkers] = useState<number>(0);
+  const [thumbnailWorkers, setThumbnailWorkers] = useState<number>(0);
+  const [thumbnailSizes, setThumbnailSizes] = useState<string>("");
+  const [thumbnailCacheSize, setThumbnailCacheSize] = useState<number>(0);
+  const [objectStorage, setObjectStorage] = useState<GQL.ObjectStorageInput>({
+    endpoint: "",
+    bucket: "",
+    accessKey: "",
+    secretKey: "",
+  });
+  const [importWorkers, setImportWorkers] = useState<number>(0);
+  const [backupInterval, setBackupInterval] = useState<number>(0);
+  const [backupPath, setBackupPath] = useState<string | undefined>(undefined);
+  const [backupCount, setBackupCount] = useState<number>(0);
+  const [backupIncludeExport, setBackupIncludeExport] = useState<boolean>(
+    false
+  );
   const [previewSegments, setPreviewSegments] = useState<number>(0);
+  const [previewImageQuality, setPreviewImageQuality] = useState<number>(100);
+  const [previewImageFps, setPreviewImageFps] = useState<number>(12);
+  const [spriteRows, setSpriteRows] = useState<number>(9);
+  const [spriteColumns, setSpriteColumns] = useState<number>(9);
+  const [spriteWidth, setSpriteWidth] = useState<number>(160);
+  const [spriteInterval, setSpriteInterval] = useState<number>(0);
   const [previewSegmentDuration, setPreviewSegmentDuration] = useState<number>(
     0
   );
@@ -103,6 +87,22 @@ export const SettingsConfigurationPanel: React.FC = () => {
   const [maxStreamingTranscodeSize, setMaxStreamingTranscodeSize] = useState<
     GQL.StreamingResolutionEnum | undefined
   >(undefined);
+  const [hardwareAcceleration, setHardwareAcceleration] = useState<
+    GQL.HardwareAcceleration
+  >(GQL.HardwareAcceleration.None);
+  const [
+    availableHardwareAcceleration,
+    setAvailableHardwareAcceleration,
+  ] = useState<GQL.HardwareAcceleration[]>([]);
+  const [maxTranscodeProcesses, setMaxTranscodeProcesses] = useState<number>(
+    0
+  );
+  const [maxGenerateProcesses, setMaxGenerateProcesses] = useState<number>(0);
+  const [transcodeProfiles, setTranscodeProfiles] = useState<string[]>([]);
+  const [liveTranscodeProfile, setLiveTranscodeProfile] = useState<string>("");
+  const [previewTranscodeProfile, setPreviewTranscodeProfile] = useState<
+    string
+  >("");
   const [username, setUsername] = useState<string | undefined>(undefined);
   const [password, setPassword] = useState<string | undefined>(undefined);
   const [maxSessionAge, setMaxSessionAge] = useState<number>(0);
@@ -112,6 +112,7 @@ export const SettingsConfigurationPanel: React.FC = () => {
   const [logAccess, setLogAccess] = useState<boolean>(true);
 
   const [videoExtensions, setVideoExtensions] = useState<string | undefined>();
+  const [audioExtensions, setAudioExtensions] = useState<string | undefined>();
   const [imageExtensions, setImageExtensions] = useState<string | undefined>();
   const [galleryExtensions, setGalleryExtensions] = useState<
     string | undefined
@@ -123,6 +124,7 @@ export const SettingsConfigurationPanel: React.FC = () => {
 
   const [excludes, setExcludes] = useState<string[]>([]);
   const [imageExcludes, setImageExcludes] = useState<string[]>([]);
+  const [autoTagSeparators, setAutoTagSeparators] = useState<string>("");
   const [scraperUserAgent, setScraperUserAgent] = useState<string | undefined>(
     undefined
   );
@@ -130,6 +132,12 @@ export const SettingsConfigurationPanel: React.FC = () => {
     undefined
   );
   const [scraperCertCheck, setScraperCertCheck] = useState<boolean>(true);
+  const [scraperCacheTTL, setScraperCacheTTL] = useState<number>(60);
+  const [scraperRequestInterval, setScraperRequestInterval] = useState<number>(
+    1000
+  );
+  const [scraperRequestJitter, setScraperRequestJitter] = useState<number>(500);
+  const [scraperMaxConcurrency, setScraperMaxConcurrency] = useState<number>(2);
   const [stashBoxes, setStashBoxes] = useState<IStashBoxInstance[]>([]);
 
   const { data, error, loading } = useConfiguration();
@@ -141,21 +149,54 @@ export const SettingsConfigurationPanel: React.FC = () => {
       path: s.path,
       excludeVideo: s.excludeVideo,
       excludeImage: s.excludeImage,
+      watch: s.watch,
+      excludes: s.excludes,
+      imageExcludes: s.imageExcludes,
+      generatePreviews: s.generatePreviews,
+      generatePhashes: s.generatePhashes,
+      removable: s.removable,
     })),
     databasePath,
     generatedPath,
     cachePath,
+    blobsStorage,
+    blobsPath,
     calculateMD5,
     videoFileNamingAlgorithm:
       (videoFileNamingAlgorithm as GQL.HashAlgorithm) ?? undefined,
     parallelTasks,
+    hashWorkers,
+    probeWorkers,
+    generateWorkers,
+    thumbnailWorkers,
+    thumbnailSizes: commaDelimitedToList(thumbnailSizes)
+      ?.filter((s) => s !== "")
+      .map((s) => Number.parseInt(s, 10)),
+    thumbnailCacheSize,
+    objectStorage,
+    importWorkers,
+    backupInterval,
+    backupPath,
+    backupCount,
+    backupIncludeExport,
     previewSegments,
     previewSegmentDuration,
     previewExcludeStart,
     previewExcludeEnd,
     previewPreset: (previewPreset as GQL.PreviewPreset) ?? undefined,
+    previewImageQuality,
+    previewImageFps,
+    spriteRows,
+    spriteColumns,
+    spriteWidth,
+    spriteInterval,
     maxTranscodeSize,
     maxStreamingTranscodeSize,
+    hardwareAcceleration,
+    maxTranscodeProcesses,
+    maxGenerateProcesses,
+    liveTranscodeProfile,
+    previewTranscodeProfile,
     username,
     password,
     maxSessionAge,
@@ -165,13 +206,19 @@ export const SettingsConfigurationPanel: React.FC = () => {
     logAccess,
     createGalleriesFromFolders,
     videoExtensions: commaDelimitedToList(videoExtensions),
+    audioExtensions: commaDelimitedToList(audioExtensions),
     imageExtensions: commaDelimitedToList(imageExtensions),
     galleryExtensions: commaDelimitedToList(galleryExtensions),
     excludes,
     imageExcludes,
+    autoTagSeparators,
     scraperUserAgent,
     scraperCDPPath,
     scraperCertCheck,
+    scraperCacheTTL,
+    scraperRequestInterval,
+    scraperRequestJitter,
+    scraperMaxConcurrency,
     stashBoxes: stashBoxes.map(
       (b) =>
         ({
@@ -191,18 +238,56 @@ export const SettingsConfigurationPanel: React.FC = () => {
       setDatabasePath(conf.general.databasePath);
       setGeneratedPath(conf.general.generatedPath);
       setCachePath(conf.general.cachePath);
+      setBlobsStorage(conf.general.blobsStorage);
+      setBlobsPath(conf.general.blobsPath);
       setVideoFileNamingAlgorithm(conf.general.videoFileNamingAlgorithm);
       setCalculateMD5(conf.general.calculateMD5);
       setParallelTasks(conf.general.parallelTasks);
+      setHashWorkers(conf.general.hashWorkers);
+      setProbeWorkers(conf.general.probeWorkers);
+      setGenerateWorkers(conf.general.generateWorkers);
+      setThumbnailWorkers(conf.general.thumbnailWorkers);
+      setThumbnailSizes(conf.general.thumbnailSizes.join(", "));
+      setThumbnailCacheSize(conf.general.thumbnailCacheSize);
+      setObjectStorage({
+        endpoint: conf.general.objectStorage.endpoint,
+        bucket: conf.general.objectStorage.bucket,
+        region: conf.general.objectStorage.region,
+        accessKey: conf.general.objectStorage.accessKey,
+        secretKey: conf.general.objectStorage.secretKey,
+        prefix: conf.general.objectStorage.prefix,
+        pathStyle: conf.general.objectStorage.pathStyle,
+        keepLocal: conf.general.objectStorage.keepLocal,
+      });
+      setImportWorkers(conf.general.importWorkers);
+      setBackupInterval(conf.general.backupInterval);
+      setBackupPath(conf.general.backupPath);
+      setBackupCount(conf.general.backupCount);
+      setBackupIncludeExport(conf.general.backupIncludeExport);
       setPreviewSegments(conf.general.previewSegments);
       setPreviewSegmentDuration(conf.general.previewSegmentDuration);
       setPreviewExcludeStart(conf.general.previewExcludeStart);
       setPreviewExcludeEnd(conf.general.previewExcludeEnd);
       setPreviewPreset(conf.general.previewPreset);
+      setPreviewImageQuality(conf.general.previewImageQuality);
+      setPreviewImageFps(conf.general.previewImageFps);
+      setSpriteRows(conf.general.spriteRows);
+      setSpriteColumns(conf.general.spriteColumns);
+      setSpriteWidth(conf.general.spriteWidth);
+      setSpriteInterval(conf.general.spriteInterval);
       setMaxTranscodeSize(conf.general.maxTranscodeSize ?? undefined);
       setMaxStreamingTranscodeSize(
         conf.general.maxStreamingTranscodeSize ?? undefined
       );
+      setHardwareAcceleration(conf.general.hardwareAcceleration);
+      setAvailableHardwareAcceleration(
+        conf.general.availableHardwareAcceleration
+      );
+      setMaxTranscodeProcesses(conf.general.maxTranscodeProcesses);
+      setMaxGenerateProcesses(conf.general.maxGenerateProcesses);
+      setTranscodeProfiles(conf.general.transcodeProfiles.map((p) => p.name));
+      setLiveTranscodeProfile(conf.general.liveTranscodeProfile ?? "");
+      setPreviewTranscodeProfile(conf.general.previewTranscodeProfile ?? "");
       setUsername(conf.general.username);
       setPassword(conf.general.password);
       setMaxSessionAge(conf.general.maxSessionAge);
@@ -212,15 +297,21 @@ export const SettingsConfigurationPanel: React.FC = () => {
       setLogAccess(conf.general.logAccess);
       setCreateGalleriesFromFolders(conf.general.createGalleriesFromFolders);
       setVideoExtensions(listToCommaDelimited(conf.general.videoExtensions));
+      setAudioExtensions(listToCommaDelimited(conf.general.audioExtensions));
       setImageExtensions(listToCommaDelimited(conf.general.imageExtensions));
       setGalleryExtensions(
         listToCommaDelimited(conf.general.galleryExtensions)
       );
       setExcludes(conf.general.excludes);
       setImageExcludes(conf.general.imageExcludes);
+      setAutoTagSeparators(conf.general.autoTagSeparators);
       setScraperUserAgent(conf.general.scraperUserAgent ?? undefined);
       setScraperCDPPath(conf.general.scraperCDPPath ?? undefined);
       setScraperCertCheck(conf.general.scraperCertCheck);
+      setScraperCacheTTL(conf.general.scraperCacheTTL);
+      setScraperRequestInterval(conf.general.scraperRequestInterval);
+      setScraperRequestJitter(conf.general.scraperRequestJitter);
+      setScraperMaxConcurrency(conf.general.scraperMaxConcurrency);
       setStashBoxes(
         conf.general.stashBoxes.map((box, i) => ({
           name: box?.name ?? undefined,
@@ -355,6 +446,30 @@ export const SettingsConfigurationPanel: React.FC = () => {
     return GQL.HashAlgorithm.Md5;
   }
 
+  function renderWorkersInput(
+    id: string,
+    label: string,
+    value: number,
+    setValue: (v: number) => void
+  ) {
+    return (
+      <Form.Group id={id}>
+        <h6>{label}</h6>
+        <Form.Control
+          className="col col-sm-6 text-input"
+          type="number"
+          value={value}
+          onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+            setValue(Number.parseInt(e.currentTarget.value || "0", 10))
+          }
+        />
+        <Form.Text className="text-muted">
+          Set to 0 to use the number of parallel tasks.
+        </Form.Text>
+      </Form.Group>
+    );
+  }
+
   if (error) return <h1>{error.message}</h1>;
   if (!data?.configuration || loading) return <LoadingIndicator />;
 
@@ -416,6 +531,39 @@ export const SettingsConfigurationPanel: React.FC = () => {
           </Form.Text>
         </Form.Group>
 
+        <Form.Group id="blobs-storage">
+          <h6>Image Storage</h6>
+          <Form.Control
+            className="w-auto input-control"
+            as="select"
+            value={blobsStorage}
+            onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+              setBlobsStorage(e.currentTarget.value as GQL.BlobsStorageType)
+            }
+          >
+            <option value={GQL.BlobsStorageType.Database}>Database</option>
+            <option value={GQL.BlobsStorageType.Filesystem}>Filesystem</option>
+          </Form.Control>
+          <Form.Text className="text-muted">
+            Where new performer, studio, tag and movie images are stored. Run
+            the Move images task to move the existing images.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="blobs-path">
+          <h6>Image Storage Path</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            defaultValue={blobsPath}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setBlobsPath(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            Directory location of the images stored in the filesystem
+          </Form.Text>
+        </Form.Group>
+
         <Form.Group id="video-extensions">
           <h6>Video Extensions</h6>
           <Form.Control
@@ -431,6 +579,21 @@ export const SettingsConfigurationPanel: React.FC = () => {
           </Form.Text>
         </Form.Group>
 
+        <Form.Group id="audio-extensions">
+          <h6>Audio Extensions</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            defaultValue={audioExtensions}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setAudioExtensions(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            Comma-delimited list of file extensions that will be identified as
+            audio files. Audio files are added as scenes.
+          </Form.Text>
+        </Form.Group>
+
         <Form.Group id="image-extensions">
           <h6>Image Extensions</h6>
           <Form.Control
@@ -447,7 +610,7 @@ export const SettingsConfigurationPanel: React.FC = () => {
         </Form.Group>
 
         <Form.Group id="gallery-extensions">
-          <h6>Gallery zip Extensions</h6>
+          <h6>Gallery Archive Extensions</h6>
           <Form.Control
             className="col col-sm-6 text-input"
             defaultValue={galleryExtensions}
@@ -457,7 +620,7 @@ export const SettingsConfigurationPanel: React.FC = () => {
           />
           <Form.Text className="text-muted">
             Comma-delimited list of file extensions that will be identified as
-            gallery zip files.
+            gallery archives. Rar and 7z archives require 7-Zip.
           </Form.Text>
         </Form.Group>
 
@@ -505,7 +668,26 @@ export const SettingsConfigurationPanel: React.FC = () => {
             }
           />
           <Form.Text className="text-muted">
-            If true, creates galleries from folders containing images.
+            If true, creates galleries from folders containing images. Scans add
+            images to the gallery of their folder, and move them between
+            galleries when they are moved. Clean removes the galleries of
+            folders which are missing or have no images.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="autotag-separators">
+          <h6>Auto Tag Separators</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={autoTagSeparators}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setAutoTagSeparators(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            Characters which may separate the words of a name in a path, or be
+            omitted between them, when auto-tagging. Space is always a
+            separator. Defaults to &quot;.-_ &quot;.
           </Form.Text>
         </Form.Group>
       </Form.Group>
@@ -602,12 +784,84 @@ export const SettingsConfigurationPanel: React.FC = () => {
             Maximum size for transcoded streams
           </Form.Text>
         </Form.Group>
+        <Form.Group id="hardware-acceleration">
+          <h6>Hardware acceleration</h6>
+          <Form.Control
+            className="w-auto input-control"
+            as="select"
+            onChange={(event: React.ChangeEvent<HTMLSelectElement>) =>
+              setHardwareAcceleration(
+                event.currentTarget.value as GQL.HardwareAcceleration
+              )
+            }
+            value={hardwareAcceleration}
+          >
+            {Object.values(GQL.HardwareAcceleration).map((hw) => (
+              <option key={hw} value={hw}>
+                {hardwareAccelerationLabels[hw]}
+              </option>
+            ))}
+          </Form.Control>
+          <Form.Text className="text-muted">
+            Hardware encoder used for transcoded streams and preview
+            generation. Falls back to software encoding if the encoder is not
+            available or fails. Available encoders:{" "}
+            {availableHardwareAcceleration.length > 0
+              ? availableHardwareAcceleration
+                  .map((hw) => hardwareAccelerationLabels[hw])
+                  .join(", ")
+              : "none"}
+          </Form.Text>
+        </Form.Group>
+        <Form.Group id="live-transcode-profile">
+          <h6>Live transcode profile</h6>
+          <Form.Control
+            className="w-auto input-control"
+            as="select"
+            onChange={(event: React.ChangeEvent<HTMLSelectElement>) =>
+              setLiveTranscodeProfile(event.currentTarget.value)
+            }
+            value={liveTranscodeProfile}
+          >
+            <option value="">Default</option>
+            {transcodeProfiles.map((p) => (
+              <option key={p} value={p}>
+                {p}
+              </option>
+            ))}
+          </Form.Control>
+          <Form.Text className="text-muted">
+            Encoder settings used for transcoded H.264 streams. Profiles are
+            defined in transcode_profiles in the config file.
+          </Form.Text>
+        </Form.Group>
+        <Form.Group id="preview-transcode-profile">
+          <h6>Preview transcode profile</h6>
+          <Form.Control
+            className="w-auto input-control"
+            as="select"
+            onChange={(event: React.ChangeEvent<HTMLSelectElement>) =>
+              setPreviewTranscodeProfile(event.currentTarget.value)
+            }
+            value={previewTranscodeProfile}
+          >
+            <option value="">Default</option>
+            {transcodeProfiles.map((p) => (
+              <option key={p} value={p}>
+                {p}
+              </option>
+            ))}
+          </Form.Control>
+          <Form.Text className="text-muted">
+            Encoder settings used when generating scene previews.
+          </Form.Text>
+        </Form.Group>
       </Form.Group>
 
       <hr />
 
       <Form.Group>
-        <h4>Parallel Scan/Generation</h4>
+        <h4>Parallel Tasks</h4>
 
         <Form.Group id="parallel-tasks">
           <h6>Number of parallel task for scan/generation</h6>
@@ -627,6 +881,151 @@ export const SettingsConfigurationPanel: React.FC = () => {
             and potentially cause other issues.
           </Form.Text>
         </Form.Group>
+
+        {renderWorkersInput(
+          "hash-workers",
+          "Number of files to hash concurrently",
+          hashWorkers,
+          setHashWorkers
+        )}
+        {renderWorkersInput(
+          "probe-workers",
+          "Number of files to probe concurrently",
+          probeWorkers,
+          setProbeWorkers
+        )}
+        {renderWorkersInput(
+          "generate-workers",
+          "Number of generated files to make concurrently",
+          generateWorkers,
+          setGenerateWorkers
+        )}
+        {renderWorkersInput(
+          "thumbnail-workers",
+          "Number of image thumbnails to make concurrently",
+          thumbnailWorkers,
+          setThumbnailWorkers
+        )}
+
+        <Form.Group id="import-workers">
+          <h6>Number of objects to import concurrently</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={importWorkers}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setImportWorkers(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Set to 0 to use the number of CPUs.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="max-transcode-processes">
+          <h6>Maximum concurrent live transcodes</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={maxTranscodeProcesses}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setMaxTranscodeProcesses(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Set to 0 for no limit. Further streams wait until a transcode
+            finishes.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="max-generate-processes">
+          <h6>Maximum concurrent ffmpeg processes for tasks</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={maxGenerateProcesses}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setMaxGenerateProcesses(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Set to 0 for no limit. Live transcodes count towards this limit,
+            so that scan and generate tasks use fewer processes while scenes
+            are being played.
+          </Form.Text>
+        </Form.Group>
+      </Form.Group>
+
+      <hr />
+
+      <Form.Group>
+        <h4>Backups</h4>
+
+        <Form.Group id="backup-interval">
+          <h6>Backup interval (hours)</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={backupInterval}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setBackupInterval(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Hours between automatic backups of the database. Set to 0 to
+            disable automatic backups.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="backup-path">
+          <h6>Backup directory</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            defaultValue={backupPath}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setBackupPath(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            Directory where backups are written. Defaults to the backups
+            directory next to the database.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="backup-count">
+          <h6>Number of backups to keep</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={backupCount}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setBackupCount(Number.parseInt(e.currentTarget.value || "0", 10))
+            }
+          />
+          <Form.Text className="text-muted">
+            Older backups are deleted once this number is reached.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group>
+          <Form.Check
+            id="backup-include-export"
+            checked={backupIncludeExport}
+            label="Include metadata export"
+            onChange={() => setBackupIncludeExport(!backupIncludeExport)}
+          />
+          <Form.Text className="text-muted">
+            Also write a metadata export alongside each database backup.
+          </Form.Text>
+        </Form.Group>
       </Form.Group>
 
       <hr />
@@ -673,6 +1072,44 @@ export const SettingsConfigurationPanel: React.FC = () => {
           </Form.Text>
         </Form.Group>
 
+        <Form.Group id="preview-image-quality">
+          <h6>Preview image quality</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={0}
+            max={100}
+            value={previewImageQuality.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setPreviewImageQuality(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Quality of animated WebP preview images, from 0 to 100. 100 encodes
+            losslessly. Lower values produce much smaller files.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="preview-image-fps">
+          <h6>Preview image frame rate</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={1}
+            value={previewImageFps.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setPreviewImageFps(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Frames per second of animated WebP preview images.
+          </Form.Text>
+        </Form.Group>
+
         <Form.Group id="preview-segment-duration">
           <h6>Preview segment duration</h6>
           <Form.Control
@@ -721,6 +1158,263 @@ export const SettingsConfigurationPanel: React.FC = () => {
         </Form.Group>
       </Form.Group>
 
+      <hr />
+
+      <Form.Group>
+        <h4>Sprite Generation</h4>
+
+        <Form.Group id="sprite-interval">
+          <h6>Thumbnail interval</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={0}
+            value={spriteInterval.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setSpriteInterval(
+                Number.parseFloat(e.currentTarget.value || "0")
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Seconds between sprite thumbnails. Longer videos get more rows of
+            thumbnails. Set to 0 to use a fixed grid spread evenly through the
+            video.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="sprite-rows">
+          <h6>Rows</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={1}
+            value={spriteRows.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setSpriteRows(Number.parseInt(e.currentTarget.value || "0", 10))
+            }
+          />
+          <Form.Text className="text-muted">
+            Number of rows in the sprite grid, if the thumbnail interval is 0.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="sprite-columns">
+          <h6>Columns</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={1}
+            value={spriteColumns.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setSpriteColumns(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Number of columns in the sprite grid.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="sprite-width">
+          <h6>Thumbnail width</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={1}
+            value={spriteWidth.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setSpriteWidth(Number.parseInt(e.currentTarget.value || "0", 10))
+            }
+          />
+          <Form.Text className="text-muted">
+            Width of sprite thumbnails, in pixels. Existing sprites are
+            regenerated with the new layout when generating with overwrite.
+          </Form.Text>
+        </Form.Group>
+      </Form.Group>
+
+      <hr />
+
+      <Form.Group>
+        <h4>Image Thumbnails</h4>
+
+        <Form.Group id="thumbnail-sizes">
+          <h6>Thumbnail sizes</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={thumbnailSizes}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setThumbnailSizes(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            Comma separated sizes of the largest dimension of image thumbnails,
+            in pixels. Thumbnails are made of the smallest size which is at
+            least the requested size, when they are first shown.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="thumbnail-cache-size">
+          <h6>Maximum size of thumbnails (MB)</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            min={0}
+            value={thumbnailCacheSize.toString()}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setThumbnailCacheSize(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            The least recently shown thumbnails are deleted when the total size
+            of the thumbnails exceeds this. Set to 0 for no limit.
+          </Form.Text>
+        </Form.Group>
+      </Form.Group>
+
+      <hr />
+
+      <Form.Group>
+        <h4>Object Storage</h4>
+
+        <Form.Group id="object-storage-endpoint">
+          <h6>Endpoint</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={objectStorage.endpoint}
+            placeholder="http://localhost:9000"
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setObjectStorage({
+                ...objectStorage,
+                endpoint: e.currentTarget.value,
+              })
+            }
+          />
+          <Form.Text className="text-muted">
+            URL of S3-compatible storage, such as Amazon S3 or MinIO, where
+            generated screenshots, previews, sprites, markers and transcodes
+            are stored. Leave empty to store generated files locally only.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="object-storage-bucket">
+          <h6>Bucket</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={objectStorage.bucket}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setObjectStorage({
+                ...objectStorage,
+                bucket: e.currentTarget.value,
+              })
+            }
+          />
+        </Form.Group>
+
+        <Form.Group id="object-storage-region">
+          <h6>Region</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={objectStorage.region ?? ""}
+            placeholder="us-east-1"
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setObjectStorage({
+                ...objectStorage,
+                region: e.currentTarget.value,
+              })
+            }
+          />
+        </Form.Group>
+
+        <Form.Group id="object-storage-access-key">
+          <h6>Access key</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={objectStorage.accessKey}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setObjectStorage({
+                ...objectStorage,
+                accessKey: e.currentTarget.value,
+              })
+            }
+          />
+        </Form.Group>
+
+        <Form.Group id="object-storage-secret-key">
+          <h6>Secret key</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="password"
+            value={objectStorage.secretKey}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setObjectStorage({
+                ...objectStorage,
+                secretKey: e.currentTarget.value,
+              })
+            }
+          />
+        </Form.Group>
+
+        <Form.Group id="object-storage-prefix">
+          <h6>Key prefix</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={objectStorage.prefix ?? ""}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setObjectStorage({
+                ...objectStorage,
+                prefix: e.currentTarget.value,
+              })
+            }
+          />
+          <Form.Text className="text-muted">
+            Generated files are stored under this prefix in the bucket.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group>
+          <Form.Check
+            id="object-storage-path-style"
+            checked={objectStorage.pathStyle ?? false}
+            label="Use path style addressing"
+            onChange={() =>
+              setObjectStorage({
+                ...objectStorage,
+                pathStyle: !objectStorage.pathStyle,
+              })
+            }
+          />
+          <Form.Text className="text-muted">
+            Address the bucket in the URL path rather than the host name. This
+            is usually required by MinIO.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group>
+          <Form.Check
+            id="object-storage-keep-local"
+            checked={objectStorage.keepLocal ?? false}
+            label="Keep local copies"
+            onChange={() =>
+              setObjectStorage({
+                ...objectStorage,
+                keepLocal: !objectStorage.keepLocal,
+              })
+            }
+          />
+          <Form.Text className="text-muted">
+            Keep generated files in the generated directory after they are
+            uploaded. Local copies are served in preference to stored files.
+          </Form.Text>
+        </Form.Group>
+      </Form.Group>
+
+      <hr />
+
       <Form.Group>
         <h4>Scraping</h4>
         <Form.Group id="scraperUserAgent">
@@ -766,6 +1460,79 @@ export const SettingsConfigurationPanel: React.FC = () => {
             sites. If you get a certificate error when scraping untick this.
           </Form.Text>
         </Form.Group>
+
+        <Form.Group id="scraper-cache-ttl">
+          <h6>Scraper cache time (minutes)</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={scraperCacheTTL}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setScraperCacheTTL(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            How long pages loaded by scrapers are cached for. Cached pages are
+            also used when a site cannot be reached. Set to 0 to disable the
+            cache.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="scraper-request-interval">
+          <h6>Scraper request interval (milliseconds)</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={scraperRequestInterval}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setScraperRequestInterval(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Minimum time between the start of requests to the same site.
+            Throttling requests avoids being blocked by sites when scraping
+            many scenes or performers.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="scraper-request-jitter">
+          <h6>Scraper request jitter (milliseconds)</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={scraperRequestJitter}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setScraperRequestJitter(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Maximum random delay added to the request interval.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="scraper-max-concurrency">
+          <h6>Maximum simultaneous scraper requests</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={scraperMaxConcurrency}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setScraperMaxConcurrency(
+                Number.parseInt(e.currentTarget.value || "0", 10)
+              )
+            }
+          />
+          <Form.Text className="text-muted">
+            Maximum number of requests made to the same site at once. Set to 0
+            for no limit.
+          </Form.Text>
+        </Form.Group>
       </Form.Group>
 
       <hr />
@@ -2,6 +2,7 @@ import React, { useEffect, useState } from "react";
 import { Button, Form } from "react-bootstrap";
 import { DurationInput, LoadingIndicator } from "src/components/Shared";
 import { useConfiguration, useConfigureInterface } from "src/core/StashService";
+import { getPlatformURL } from "src/core/createClient";
 import { useToast } from "src/hooks";
 import { CheckboxGroup } from "./CheckboxGroup";
 
@@ -34,6 +35,8 @@ export const SettingsInterfacePanel: React.FC = () => {
   const [css, setCSS] = useState<string>();
   const [cssEnabled, setCSSEnabled] = useState<boolean>(false);
   const [language, setLanguage] = useState<string>("en");
+  const [funscriptOffset, setFunscriptOffset] = useState<number>(0);
+  const [handyKey, setHandyKey] = useState<string>("");
 
   const [updateInterfaceConfig] = useConfigureInterface({
     menuItems: menuItemIds,
@@ -47,6 +50,8 @@ export const SettingsInterfacePanel: React.FC = () => {
     cssEnabled,
     language,
     slideshowDelay,
+    funscriptOffset,
+    handyKey,
   });
 
   useEffect(() => {
@@ -62,6 +67,8 @@ export const SettingsInterfacePanel: React.FC = () => {
     setCSSEnabled(iCfg?.cssEnabled ?? false);
     setLanguage(iCfg?.language ?? "en-US");
     setSlideshowDelay(iCfg?.slideshowDelay ?? 5000);
+    setFunscriptOffset(iCfg?.funscriptOffset ?? 0);
+    setHandyKey(iCfg?.handyKey ?? "");
   }, [config]);
 
   async function onSave() {
@@ -77,7 +84,7 @@ export const SettingsInterfacePanel: React.FC = () => {
         prevCSS !== result.data?.configureInterface.css ||
         prevCSSenabled !== result.data?.configureInterface.cssEnabled
       ) {
-        await fetch("/css", { cache: "reload" });
+        await fetch(`${getPlatformURL()}css`, { cache: "reload" });
         window.location.reload();
       }
 
@@ -210,6 +217,43 @@ export const SettingsInterfacePanel: React.FC = () => {
         </Form.Text>
       </Form.Group>
 
+      <Form.Group>
+        <h5>Interactive</h5>
+        <Form.Group id="funscript-offset">
+          <h6>Funscript Offset (ms)</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            type="number"
+            value={funscriptOffset}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) => {
+              setFunscriptOffset(
+                Number.parseInt(e.currentTarget.value, 10) || 0
+              );
+            }}
+          />
+          <Form.Text className="text-muted">
+            Time in milliseconds added to the actions of funscripts, to sync
+            interactive devices with the video. Negative values play the
+            actions earlier.
+          </Form.Text>
+        </Form.Group>
+
+        <Form.Group id="handy-key">
+          <h6>Handy Connection Key</h6>
+          <Form.Control
+            className="col col-sm-6 text-input"
+            value={handyKey}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setHandyKey(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            Connection key of the Handy device that plays the funscripts of
+            interactive scenes.
+          </Form.Text>
+        </Form.Group>
+      </Form.Group>
+
       <Form.Group>
         <h5>Custom CSS</h5>
         <Form.Check
@@ -1,7 +1,7 @@
 import React, { useState } from "react";
 import { Button } from "react-bootstrap";
 import {
-  mutateReloadScrapers,
+  mutateValidateScrapers,
   useListMovieScrapers,
   useListPerformerScrapers,
   useListSceneScrapers,
@@ -10,7 +10,8 @@ import {
 import { useToast } from "src/hooks";
 import { TextUtils } from "src/utils";
 import { Icon, LoadingIndicator } from "src/components/Shared";
-import { ScrapeType } from "src/core/generated-graphql";
+import { ScrapeType, ScraperLoadError } from "src/core/generated-graphql";
+import { ScraperPackages } from "./ScraperPackages";
 
 interface IURLList {
   urls: string[];
@@ -85,8 +86,34 @@ export const SettingsScrapersPanel: React.FC = () => {
     loading: loadingMovies,
   } = useListMovieScrapers();
 
+  const [loadErrors, setLoadErrors] = useState<ScraperLoadError[]>([]);
+
   async function onReloadScrapers() {
-    await mutateReloadScrapers().catch((e) => Toast.error(e));
+    try {
+      const result = await mutateValidateScrapers();
+      setLoadErrors(result.data?.validateScrapers ?? []);
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  function renderLoadErrors() {
+    if (loadErrors.length === 0) {
+      return;
+    }
+
+    return (
+      <div className="mb-3 text-danger">
+        <h6>Scrapers which could not be loaded</h6>
+        <ul>
+          {loadErrors.map((e) => (
+            <li key={e.path}>
+              <code>{e.path}</code>: {e.error}
+            </li>
+          ))}
+        </ul>
+      </div>
+    );
   }
 
   function renderPerformerScrapeTypes(types: ScrapeType[]) {
@@ -267,6 +294,9 @@ export const SettingsScrapersPanel: React.FC = () => {
           <span>Reload scrapers</span>
         </Button>
       </div>
+      {renderLoadErrors()}
+
+      <ScraperPackages />
 
       <div>
         {renderSceneScrapers()}
@@ -2,6 +2,10 @@ import React, { useState } from "react";
 import { Button, Form } from "react-bootstrap";
 import { mutateMetadataGenerate } from "src/core/StashService";
 import { useToast } from "src/hooks";
+import {
+  GenerateOverwriteOptions,
+  noGenerateOverwrite,
+} from "src/components/Shared";
 
 export const GenerateButton: React.FC = () => {
   const Toast = useToast();
@@ -10,7 +14,11 @@ export const GenerateButton: React.FC = () => {
   const [previews, setPreviews] = useState(true);
   const [markers, setMarkers] = useState(true);
   const [transcodes, setTranscodes] = useState(false);
+  const [interactiveHeatmapsSpeeds, setInteractiveHeatmapsSpeeds] = useState(
+    false
+  );
   const [imagePreviews, setImagePreviews] = useState(false);
+  const [overwrite, setOverwrite] = useState(noGenerateOverwrite);
 
   async function onGenerate() {
     try {
@@ -21,6 +29,14 @@ export const GenerateButton: React.FC = () => {
         imagePreviews: previews && imagePreviews,
         markers,
         transcodes,
+        overwriteSprites: sprites && overwrite.sprites,
+        overwritePreviews: previews && overwrite.previews,
+        overwriteMarkers: markers && overwrite.markers,
+        overwriteTranscodes: transcodes && overwrite.transcodes,
+        overwritePhashes: phashes && overwrite.phashes,
+        interactiveHeatmapsSpeeds,
+        overwriteInteractiveHeatmapsSpeeds:
+          interactiveHeatmapsSpeeds && overwrite.interactiveHeatmapsSpeeds,
       });
       Toast.success({ content: "Started generating" });
     } catch (e) {
@@ -72,7 +88,28 @@ export const GenerateButton: React.FC = () => {
           label="Phashes (for deduplication and scene identification)"
           onChange={() => setPhashes(!phashes)}
         />
+        <Form.Check
+          id="interactive-heatmap-speed-task"
+          checked={interactiveHeatmapsSpeeds}
+          label="Heatmaps and speeds (for the funscripts of interactive scenes)"
+          onChange={() =>
+            setInteractiveHeatmapsSpeeds(!interactiveHeatmapsSpeeds)
+          }
+        />
       </Form.Group>
+      <GenerateOverwriteOptions
+        id="generate-overwrite"
+        generate={{
+          sprites,
+          previews,
+          markers,
+          transcodes,
+          phashes,
+          interactiveHeatmapsSpeeds,
+        }}
+        overwrite={overwrite}
+        onChange={setOverwrite}
+      />
       <Form.Group>
         <Button
           id="generate"
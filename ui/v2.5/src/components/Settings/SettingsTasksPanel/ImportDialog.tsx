@@ -1,6 +1,9 @@
 import React, { useState } from "react";
 import { Form } from "react-bootstrap";
-import { mutateImportObjects } from "src/core/StashService";
+import {
+  mutateImportObjects,
+  mutateImportObjectsDryRun,
+} from "src/core/StashService";
 import { Modal } from "src/components/Shared";
 import * as GQL from "src/core/generated-graphql";
 import { useToast } from "src/hooks";
@@ -21,6 +24,12 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
   );
 
   const [file, setFile] = useState<File | undefined>();
+  const [dryRun, setDryRun] = useState(false);
+  const [pathMappings, setPathMappings] = useState<string>("");
+  const [imagesPath, setImagesPath] = useState<string>("");
+  const [objectTypes, setObjectTypes] = useState<GQL.ImportObjectType[]>(
+    Object.values(GQL.ImportObjectType)
+  );
 
   // Network state
   const [isRunning, setIsRunning] = useState(false);
@@ -37,6 +46,8 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
         return "Ignore";
       case GQL.ImportDuplicateEnum.Overwrite:
         return "Overwrite";
+      case GQL.ImportDuplicateEnum.Merge:
+        return "Merge";
     }
     return "Ignore";
   }
@@ -49,6 +60,8 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
         return GQL.ImportDuplicateEnum.Ignore;
       case "Overwrite":
         return GQL.ImportDuplicateEnum.Overwrite;
+      case "Merge":
+        return GQL.ImportDuplicateEnum.Merge;
     }
 
     return GQL.ImportDuplicateEnum.Ignore;
@@ -81,6 +94,44 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
     return GQL.ImportMissingRefEnum.Fail;
   }
 
+  function objectTypeToString(value: GQL.ImportObjectType) {
+    switch (value) {
+      case GQL.ImportObjectType.Tag:
+        return "Tags";
+      case GQL.ImportObjectType.Performer:
+        return "Performers";
+      case GQL.ImportObjectType.Studio:
+        return "Studios";
+      case GQL.ImportObjectType.Movie:
+        return "Movies";
+      case GQL.ImportObjectType.Gallery:
+        return "Galleries";
+      case GQL.ImportObjectType.ScrapedItem:
+        return "Scraped items";
+      case GQL.ImportObjectType.Scene:
+        return "Scenes";
+      case GQL.ImportObjectType.Image:
+        return "Images";
+    }
+  }
+
+  function toggleObjectType(value: GQL.ImportObjectType) {
+    if (objectTypes.includes(value)) {
+      setObjectTypes(objectTypes.filter((t) => t !== value));
+    } else {
+      setObjectTypes([...objectTypes, value]);
+    }
+  }
+
+  // each line of the path mappings is of the form: from => to
+  function parsePathMappings(): GQL.ImportPathMappingInput[] {
+    return pathMappings
+      .split("\n")
+      .map((line) => line.split("=>"))
+      .filter((parts) => parts.length === 2 && parts[0].trim())
+      .map((parts) => ({ from: parts[0].trim(), to: parts[1].trim() }));
+  }
+
   function onFileChange(event: React.ChangeEvent<HTMLInputElement>) {
     if (
       event.target.validity.valid &&
@@ -91,16 +142,46 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
     }
   }
 
+  function summariseReport(
+    report: GQL.ImportObjectsDryRunMutation["importObjectsDryRun"]
+  ) {
+    const counts = new Map<GQL.ImportObjectResult, number>();
+    report.objects.forEach((o) => {
+      counts.set(o.result, (counts.get(o.result) ?? 0) + 1);
+    });
+
+    const summary = Object.values(GQL.ImportObjectResult)
+      .filter((r) => counts.has(r))
+      .map((r) => `${r.toLowerCase()}: ${counts.get(r)}`)
+      .join(", ");
+
+    return `Dry run complete. ${summary || "No objects to import"}`;
+  }
+
   async function onImport() {
+    const input = {
+      duplicateBehaviour: translateDuplicateHandling(duplicateBehaviour),
+      missingRefBehaviour: translateMissingRefHandling(missingRefBehaviour),
+      file,
+      types: objectTypes,
+      pathMappings: parsePathMappings(),
+      imagesPath: imagesPath || undefined,
+    };
+
     try {
       setIsRunning(true);
-      await mutateImportObjects({
-        duplicateBehaviour: translateDuplicateHandling(duplicateBehaviour),
-        missingRefBehaviour: translateMissingRefHandling(missingRefBehaviour),
-        file,
-      });
+      if (dryRun) {
+        const result = await mutateImportObjectsDryRun(input);
+        if (result.data?.importObjectsDryRun) {
+          Toast.success({
+            content: summariseReport(result.data.importObjectsDryRun),
+          });
+        }
+      } else {
+        await mutateImportObjects(input);
+        Toast.success({ content: "Started importing" });
+      }
       setIsRunning(false);
-      Toast.success({ content: "Started importing" });
     } catch (e) {
       Toast.error(e);
     } finally {
@@ -124,14 +205,14 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
         text: "Cancel",
         variant: "secondary",
       }}
-      disabled={!file}
+      disabled={!file || objectTypes.length === 0}
       isRunning={isRunning}
     >
       <div className="dialog-container">
         <Form>
           <Form.Group id="import-file">
-            <h6>Import zip file</h6>
-            <Form.File onChange={onFileChange} accept=".zip" />
+            <h6>Import zip or JSON lines file</h6>
+            <Form.File onChange={onFileChange} accept=".zip,.jsonl,.gz" />
           </Form.Group>
           <Form.Group id="duplicate-handling">
             <h6>Duplicate object handling</h6>
@@ -164,6 +245,61 @@ export const ImportDialog: React.FC<IImportDialogProps> = (
               ))}
             </Form.Control>
           </Form.Group>
+
+          <Form.Group id="object-types">
+            <h6>Objects to import</h6>
+            {Object.values(GQL.ImportObjectType).map((t) => (
+              <Form.Check
+                key={t}
+                id={`object-type-${t}`}
+                checked={objectTypes.includes(t)}
+                label={objectTypeToString(t)}
+                onChange={() => toggleObjectType(t)}
+              />
+            ))}
+          </Form.Group>
+
+          <Form.Group id="path-mappings">
+            <h6>Path mappings</h6>
+            <Form.Control
+              as="textarea"
+              className="text-input"
+              rows={3}
+              placeholder={"C:\\Videos => /data/videos"}
+              value={pathMappings}
+              onChange={(e: React.ChangeEvent<HTMLTextAreaElement>) =>
+                setPathMappings(e.currentTarget.value)
+              }
+            />
+            <Form.Text className="text-muted">
+              Replaces path prefixes of scenes, images and galleries. One
+              mapping per line.
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="images-path">
+            <h6>Images directory</h6>
+            <Form.Control
+              className="text-input"
+              value={imagesPath}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setImagesPath(e.currentTarget.value)
+              }
+            />
+            <Form.Text className="text-muted">
+              Directory on the server containing performer, studio and movie
+              images exported as files. Used for objects without images.
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="dry-run">
+            <Form.Check
+              id="dry-run-checkbox"
+              checked={dryRun}
+              label="Dry run (report what would be imported without saving)"
+              onChange={() => setDryRun(!dryRun)}
+            />
+          </Form.Group>
         </Form>
       </div>
     </Modal>
@@ -0,0 +1,174 @@
+import React, { useEffect } from "react";
+import { Button, ProgressBar, Table } from "react-bootstrap";
+import {
+  mutateStopJob,
+  useJobHistory,
+  useJobQueue,
+  useJobsUpdate,
+} from "src/core/StashService";
+import { useToast } from "src/hooks";
+import * as GQL from "src/core/generated-graphql";
+import { Icon } from "src/components/Shared";
+
+function formatTime(t?: string | null) {
+  return t ? new Date(t).toLocaleString() : "";
+}
+
+function formatDuration(seconds?: number | null) {
+  if (seconds === undefined || seconds === null) {
+    return "";
+  }
+
+  const s = Math.round(seconds);
+  const h = Math.floor(s / 3600);
+  const m = Math.floor((s % 3600) / 60);
+
+  if (h > 0) {
+    return `${h}h ${m}m`;
+  }
+  if (m > 0) {
+    return `${m}m ${s % 60}s`;
+  }
+  return `${s}s`;
+}
+
+function stateToText(s: GQL.JobState) {
+  switch (s) {
+    case GQL.JobState.Queued:
+      return "Queued";
+    case GQL.JobState.Running:
+      return "Running";
+    case GQL.JobState.Stopping:
+      return "Stopping";
+    case GQL.JobState.Finished:
+      return "Finished";
+    case GQL.JobState.Cancelled:
+      return "Cancelled";
+    case GQL.JobState.Failed:
+      return "Failed";
+  }
+}
+
+export const JobQueue: React.FC = () => {
+  const Toast = useToast();
+  const queue = useJobQueue();
+  const jobsUpdate = useJobsUpdate();
+  const history = useJobHistory();
+
+  const jobs = jobsUpdate.data?.jobsUpdate ?? queue.data?.jobQueue ?? [];
+  const { refetch: refetchHistory } = history;
+
+  // jobs leave the queue when they finish
+  useEffect(() => {
+    refetchHistory();
+  }, [jobs.length, refetchHistory]);
+
+  async function onStop(job: GQL.JobDataFragment) {
+    try {
+      await mutateStopJob(job.id);
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  function renderProgress(job: GQL.JobDataFragment) {
+    if (job.state === GQL.JobState.Queued) {
+      return "";
+    }
+
+    return (
+      <ProgressBar
+        animated
+        now={job.progress > -1 ? job.progress * 100 : 100}
+        label={job.progress > -1 ? `${(job.progress * 100).toFixed(0)}%` : ""}
+      />
+    );
+  }
+
+  function renderQueue() {
+    if (jobs.length === 0) {
+      return;
+    }
+
+    return (
+      <Table size="sm">
+        <thead>
+          <tr>
+            <th>Job</th>
+            <th>State</th>
+            <th>Priority</th>
+            <th>Progress</th>
+            <th />
+          </tr>
+        </thead>
+        <tbody>
+          {jobs.map((j) => (
+            <tr key={j.id}>
+              <td>
+                {j.type}
+                {j.phase ? (
+                  <div className="text-muted">{j.phase}</div>
+                ) : undefined}
+              </td>
+              <td>{stateToText(j.state)}</td>
+              <td>{j.priority}</td>
+              <td className="w-25">{renderProgress(j)}</td>
+              <td>
+                <Button
+                  size="sm"
+                  variant="danger"
+                  disabled={j.state === GQL.JobState.Stopping}
+                  onClick={() => onStop(j)}
+                >
+                  <Icon icon="times" />
+                </Button>
+              </td>
+            </tr>
+          ))}
+        </tbody>
+      </Table>
+    );
+  }
+
+  function renderHistory() {
+    const entries = history.data?.jobHistory ?? [];
+    if (entries.length === 0) {
+      return;
+    }
+
+    return (
+      <>
+        <h6>Recent Jobs</h6>
+        <Table size="sm">
+          <thead>
+            <tr>
+              <th>Job</th>
+              <th>State</th>
+              <th>Duration</th>
+              <th>Finished</th>
+              <th>Error</th>
+            </tr>
+          </thead>
+          <tbody>
+            {entries.map((e) => (
+              <tr key={e.id}>
+                <td>{e.type}</td>
+                <td>{stateToText(e.state)}</td>
+                <td>{formatDuration(e.duration)}</td>
+                <td>{formatTime(e.finishedAt)}</td>
+                <td className="text-danger">{e.error}</td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      </>
+    );
+  }
+
+  return (
+    <>
+      {renderQueue()}
+      {renderHistory()}
+    </>
+  );
+};
@@ -0,0 +1,90 @@
+import React, { useEffect, useState } from "react";
+import { Button, Form, InputGroup } from "react-bootstrap";
+import {
+  mutateSetZipPassword,
+  useJobsUpdate,
+  useLockedZipFiles,
+} from "src/core/StashService";
+import { useToast } from "src/hooks";
+
+interface ILockedZipFileProps {
+  path: string;
+  onUnlocked: () => void;
+}
+
+const LockedZipFile: React.FC<ILockedZipFileProps> = ({
+  path,
+  onUnlocked,
+}) => {
+  const Toast = useToast();
+  const [password, setPassword] = useState("");
+  const [saving, setSaving] = useState(false);
+
+  async function onSave() {
+    setSaving(true);
+    try {
+      await mutateSetZipPassword(path, password);
+      Toast.success({ content: "Password set. Scanning gallery..." });
+      onUnlocked();
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setSaving(false);
+    }
+  }
+
+  return (
+    <Form.Group>
+      <Form.Label>{path}</Form.Label>
+      <InputGroup>
+        <Form.Control
+          type="password"
+          className="text-input"
+          placeholder="Password"
+          value={password}
+          onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+            setPassword(e.currentTarget.value)
+          }
+        />
+        <InputGroup.Append>
+          <Button
+            variant="secondary"
+            disabled={saving || !password}
+            onClick={() => onSave()}
+          >
+            Unlock
+          </Button>
+        </InputGroup.Append>
+      </InputGroup>
+    </Form.Group>
+  );
+};
+
+export const LockedZipFiles: React.FC = () => {
+  const { data, refetch } = useLockedZipFiles();
+  const jobsUpdate = useJobsUpdate();
+
+  // scans find locked zip files
+  const jobs = jobsUpdate.data?.jobsUpdate.length;
+  useEffect(() => {
+    refetch();
+  }, [jobs, refetch]);
+
+  const paths = data?.lockedZipFiles ?? [];
+  if (paths.length === 0) {
+    return <></>;
+  }
+
+  return (
+    <>
+      <h6>Locked Zip Galleries</h6>
+      <Form.Text className="text-muted">
+        These zip galleries are encrypted with an unknown password. Their
+        images are added once the password is set.
+      </Form.Text>
+      {paths.map((p) => (
+        <LockedZipFile key={p} path={p} onUnlocked={() => refetch()} />
+      ))}
+    </>
+  );
+};
@@ -0,0 +1,153 @@
+import React, { useState } from "react";
+import { Form } from "react-bootstrap";
+import { mutateImportFromStash } from "src/core/StashService";
+import { Modal } from "src/components/Shared";
+import * as GQL from "src/core/generated-graphql";
+import { useToast } from "src/hooks";
+
+interface IRemoteImportDialogProps {
+  onClose: () => void;
+}
+
+export const RemoteImportDialog: React.FC<IRemoteImportDialogProps> = (
+  props: IRemoteImportDialogProps
+) => {
+  const [url, setURL] = useState<string>("");
+  const [apiKey, setAPIKey] = useState<string>("");
+  const [includeScenes, setIncludeScenes] = useState(false);
+  const [duplicateBehaviour, setDuplicateBehaviour] = useState<
+    GQL.ImportDuplicateEnum
+  >(GQL.ImportDuplicateEnum.Ignore);
+  const [missingRefBehaviour, setMissingRefBehaviour] = useState<
+    GQL.ImportMissingRefEnum
+  >(GQL.ImportMissingRefEnum.Create);
+
+  // Network state
+  const [isRunning, setIsRunning] = useState(false);
+
+  const Toast = useToast();
+
+  async function onImport() {
+    try {
+      setIsRunning(true);
+      await mutateImportFromStash({
+        url,
+        apiKey: apiKey || undefined,
+        includeScenes,
+        duplicateBehaviour,
+        missingRefBehaviour,
+      });
+      setIsRunning(false);
+      Toast.success({ content: "Started importing" });
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      props.onClose();
+    }
+  }
+
+  return (
+    <Modal
+      show
+      icon="pencil-alt"
+      header="Import from stash"
+      accept={{
+        onClick: () => {
+          onImport();
+        },
+        text: "Import",
+      }}
+      cancel={{
+        onClick: () => props.onClose(),
+        text: "Cancel",
+        variant: "secondary",
+      }}
+      disabled={!url}
+      isRunning={isRunning}
+    >
+      <div className="dialog-container">
+        <Form>
+          <Form.Group id="remote-url">
+            <h6>Stash URL</h6>
+            <Form.Control
+              className="text-input"
+              placeholder="http://localhost:9999"
+              value={url}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setURL(e.currentTarget.value)
+              }
+            />
+          </Form.Group>
+
+          <Form.Group id="remote-api-key">
+            <h6>API key</h6>
+            <Form.Control
+              className="text-input"
+              value={apiKey}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setAPIKey(e.currentTarget.value)
+              }
+            />
+            <Form.Text className="text-muted">
+              Required if the other stash requires authentication.
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="duplicate-handling">
+            <h6>Duplicate object handling</h6>
+            <Form.Control
+              className="w-auto input-control"
+              as="select"
+              value={duplicateBehaviour}
+              onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+                setDuplicateBehaviour(
+                  e.currentTarget.value as GQL.ImportDuplicateEnum
+                )
+              }
+            >
+              {Object.values(GQL.ImportDuplicateEnum).map((p) => (
+                <option key={p} value={p}>
+                  {p.charAt(0) + p.slice(1).toLowerCase()}
+                </option>
+              ))}
+            </Form.Control>
+          </Form.Group>
+
+          <Form.Group id="missing-ref-handling">
+            <h6>Missing reference handling</h6>
+            <Form.Control
+              className="w-auto input-control"
+              as="select"
+              value={missingRefBehaviour}
+              onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+                setMissingRefBehaviour(
+                  e.currentTarget.value as GQL.ImportMissingRefEnum
+                )
+              }
+            >
+              {Object.values(GQL.ImportMissingRefEnum).map((p) => (
+                <option key={p} value={p}>
+                  {p.charAt(0) + p.slice(1).toLowerCase()}
+                </option>
+              ))}
+            </Form.Control>
+          </Form.Group>
+
+          <Form.Group id="include-scenes">
+            <Form.Check
+              id="include-scenes-checkbox"
+              checked={includeScenes}
+              label="Include scene metadata"
+              onChange={() => setIncludeScenes(!includeScenes)}
+            />
+            <Form.Text className="text-muted">
+              Imports the metadata of remote scenes with the same checksum or
+              oshash as local scenes. Only applied when overwriting or
+              merging duplicates.
+            </Form.Text>
+          </Form.Group>
+        </Form>
+      </div>
+    </Modal>
+  );
+};
@@ -0,0 +1,174 @@
+import React, { useState } from "react";
+import { Button, Form, InputGroup, Table } from "react-bootstrap";
+import {
+  useAllScheduledTasks,
+  useScheduledTaskCreate,
+  useScheduledTaskUpdate,
+  useScheduledTaskDestroy,
+} from "src/core/StashService";
+import { useToast } from "src/hooks";
+import * as GQL from "src/core/generated-graphql";
+import { Icon, LoadingIndicator } from "src/components/Shared";
+
+const taskTypes: { value: GQL.ScheduledTaskType; label: string }[] = [
+  { value: GQL.ScheduledTaskType.Scan, label: "Scan" },
+  { value: GQL.ScheduledTaskType.AutoTag, label: "Auto Tag" },
+  { value: GQL.ScheduledTaskType.Clean, label: "Clean" },
+  { value: GQL.ScheduledTaskType.Backup, label: "Backup" },
+  { value: GQL.ScheduledTaskType.Optimize, label: "Optimize Database" },
+];
+
+function taskTypeLabel(t: GQL.ScheduledTaskType) {
+  return taskTypes.find((tt) => tt.value === t)?.label ?? t;
+}
+
+function formatTime(t?: string | null) {
+  return t ? new Date(t).toLocaleString() : "";
+}
+
+export const ScheduledTasks: React.FC = () => {
+  const Toast = useToast();
+  const { data, loading } = useAllScheduledTasks();
+  const [createTask] = useScheduledTaskCreate();
+  const [updateTask] = useScheduledTaskUpdate();
+  const [destroyTask] = useScheduledTaskDestroy();
+
+  const [name, setName] = useState("");
+  const [task, setTask] = useState<GQL.ScheduledTaskType>(
+    GQL.ScheduledTaskType.Scan
+  );
+  const [schedule, setSchedule] = useState("");
+
+  async function onCreate() {
+    try {
+      await createTask({ variables: { input: { name, task, schedule } } });
+      setName("");
+      setSchedule("");
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onToggle(t: GQL.ScheduledTaskDataFragment) {
+    try {
+      await updateTask({
+        variables: { input: { id: t.id, enabled: !t.enabled } },
+      });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onDestroy(t: GQL.ScheduledTaskDataFragment) {
+    try {
+      await destroyTask({ variables: { id: t.id } });
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  if (loading) return <LoadingIndicator />;
+
+  const tasks = data?.allScheduledTasks ?? [];
+
+  return (
+    <>
+      {tasks.length > 0 && (
+        <Table size="sm">
+          <thead>
+            <tr>
+              <th>Enabled</th>
+              <th>Name</th>
+              <th>Task</th>
+              <th>Schedule</th>
+              <th>Last run</th>
+              <th>Next run</th>
+              <th />
+            </tr>
+          </thead>
+          <tbody>
+            {tasks.map((t) => (
+              <tr key={t.id}>
+                <td>
+                  <Form.Check
+                    id={`scheduled-task-enabled-${t.id}`}
+                    checked={t.enabled}
+                    onChange={() => onToggle(t)}
+                  />
+                </td>
+                <td>{t.name}</td>
+                <td>{taskTypeLabel(t.task)}</td>
+                <td>
+                  <code>{t.schedule}</code>
+                </td>
+                <td>{formatTime(t.last_run_at)}</td>
+                <td>{formatTime(t.next_run_at)}</td>
+                <td>
+                  <Button
+                    size="sm"
+                    variant="danger"
+                    onClick={() => onDestroy(t)}
+                  >
+                    <Icon icon="trash" />
+                  </Button>
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      )}
+
+      <Form.Group>
+        <InputGroup>
+          <Form.Control
+            className="text-input"
+            placeholder="Name"
+            value={name}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setName(e.currentTarget.value)
+            }
+          />
+          <Form.Control
+            as="select"
+            className="input-control"
+            value={task}
+            onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+              setTask(e.currentTarget.value as GQL.ScheduledTaskType)
+            }
+          >
+            {taskTypes.map((tt) => (
+              <option key={tt.value} value={tt.value}>
+                {tt.label}
+              </option>
+            ))}
+          </Form.Control>
+          <Form.Control
+            className="text-input"
+            placeholder="Schedule, e.g. 0 3 * * *"
+            value={schedule}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setSchedule(e.currentTarget.value)
+            }
+          />
+          <InputGroup.Append>
+            <Button
+              variant="secondary"
+              disabled={!name || !schedule}
+              onClick={() => onCreate()}
+            >
+              <Icon icon="plus" />
+            </Button>
+          </InputGroup.Append>
+        </InputGroup>
+        <Form.Text className="text-muted">
+          Runs tasks on a cron schedule of minute, hour, day of month, month
+          and day of week, such as <code>0 3 * * *</code> for 3am every day,
+          or <code>@weekly</code>, <code>@monthly</code> and{" "}
+          <code>@daily</code>. Scheduled tasks are added to the job queue when
+          they are due, and those missed while stash was not running are run
+          once when it starts.
+        </Form.Text>
+      </Form.Group>
+    </>
+  );
+};
@@ -5,22 +5,33 @@ import {
   useMetadataUpdate,
   mutateMetadataImport,
   mutateMetadataClean,
+  mutateMetadataCheckMedia,
+  mutateMetadataOrganize,
   mutateMetadataScan,
   mutateMetadataAutoTag,
   mutateMetadataExport,
+  mutateExportNfo,
   mutateMigrateHashNaming,
+  mutateMigrateBlobs,
+  mutateUploadGeneratedFiles,
   mutateStopJob,
   usePlugins,
   mutateRunPluginTask,
   mutateBackupDatabase,
+  queryLastImportReport,
+  queryLastCleanReport,
 } from "src/core/StashService";
 import { useToast } from "src/hooks";
 import * as GQL from "src/core/generated-graphql";
 import { LoadingIndicator, Modal } from "src/components/Shared";
-import { downloadFile } from "src/utils";
+import { downloadFile, TextUtils } from "src/utils";
 import { GenerateButton } from "./GenerateButton";
 import { ImportDialog } from "./ImportDialog";
+import { RemoteImportDialog } from "./RemoteImportDialog";
 import { DirectorySelectionDialog } from "./DirectorySelectionDialog";
+import { ScheduledTasks } from "./ScheduledTasks";
+import { JobQueue } from "./JobQueue";
+import { LockedZipFiles } from "./LockedZipFiles";
 
 type Plugin = Pick<GQL.Plugin, "id">;
 type PluginTask = Pick<GQL.PluginTask, "name" | "description">;
@@ -29,13 +40,22 @@ export const SettingsTasksPanel: React.FC = () => {
   const Toast = useToast();
   const [isImportAlertOpen, setIsImportAlertOpen] = useState<boolean>(false);
   const [isCleanAlertOpen, setIsCleanAlertOpen] = useState<boolean>(false);
+  const [isOrganizeAlertOpen, setIsOrganizeAlertOpen] = useState<boolean>(
+    false
+  );
   const [isImportDialogOpen, setIsImportDialogOpen] = useState<boolean>(false);
+  const [isRemoteImportDialogOpen, setIsRemoteImportDialogOpen] = useState<
+    boolean
+  >(false);
   const [isScanDialogOpen, setIsScanDialogOpen] = useState<boolean>(false);
   const [isAutoTagDialogOpen, setIsAutoTagDialogOpen] = useState<boolean>(
     false
   );
   const [isBackupRunning, setIsBackupRunning] = useState<boolean>(false);
   const [useFileMetadata, setUseFileMetadata] = useState<boolean>(false);
+  const [useSidecarMetadata, setUseSidecarMetadata] = useState<boolean>(
+    false
+  );
   const [stripFileExtension, setStripFileExtension] = useState<boolean>(false);
   const [scanGeneratePreviews, setScanGeneratePreviews] = useState<boolean>(
     false
@@ -47,6 +67,13 @@ export const SettingsTasksPanel: React.FC = () => {
     false
   );
   const [cleanDryRun, setCleanDryRun] = useState<boolean>(false);
+  const [checkFullDecode, setCheckFullDecode] = useState<boolean>(false);
+  const [organizeTemplate, setOrganizeTemplate] = useState<string>(
+    "{studio}/{date} {title}"
+  );
+  const [organizeDryRun, setOrganizeDryRun] = useState<boolean>(true);
+  const [nfoImages, setNfoImages] = useState<boolean>(true);
+  const [nfoOverwrite, setNfoOverwrite] = useState<boolean>(false);
   const [
     scanGenerateImagePreviews,
     setScanGenerateImagePreviews,
@@ -54,6 +81,7 @@ export const SettingsTasksPanel: React.FC = () => {
 
   const [status, setStatus] = useState<string>("");
   const [progress, setProgress] = useState<number>(0);
+  const [statusDetail, setStatusDetail] = useState<string>("");
 
   const [autoTagPerformers, setAutoTagPerformers] = useState<boolean>(true);
   const [autoTagStudios, setAutoTagStudios] = useState<boolean>(true);
@@ -76,6 +104,10 @@ export const SettingsTasksPanel: React.FC = () => {
         return "Cleaning the database";
       case "Export":
         return "Exporting to JSON";
+      case "Export NFO":
+        return "Exporting NFO files";
+      case "Organize Files":
+        return "Organizing scene files";
       case "Import":
         return "Importing from JSON";
       case "Auto Tag":
@@ -86,14 +118,38 @@ export const SettingsTasksPanel: React.FC = () => {
         return "Migrating";
       case "Stash-Box Performer Batch Operation":
         return "Tagging performers from Stash-Box instance";
+      case "Optimize Database":
+        return "Optimizing the database";
+      case "Install Scrapers":
+        return "Installing scraper packages";
+      case "Move Images":
+        return "Moving images to the image storage";
+      case "Upload Generated Files":
+        return "Uploading generated files to object storage";
       default:
         return "Idle";
     }
   }
 
+  function statusToDetail(s: GQL.MetadataUpdateStatus) {
+    const detail = [];
+    if (s.phase) {
+      detail.push(s.phase);
+    }
+    if (s.objectsTotal) {
+      detail.push(`${s.objectsDone ?? 0} of ${s.objectsTotal} objects`);
+    }
+    if (s.eta) {
+      detail.push(`${TextUtils.secondsToTimestamp(s.eta)} remaining`);
+    }
+
+    return detail.join(" - ");
+  }
+
   useEffect(() => {
     if (jobStatus?.data?.jobStatus) {
       setStatus(statusToText(jobStatus.data.jobStatus.status));
+      setStatusDetail(statusToDetail(jobStatus.data.jobStatus));
       const newProgress = jobStatus.data.jobStatus.progress;
       if (newProgress < 0) {
         setProgress(-1);
@@ -106,6 +162,7 @@ export const SettingsTasksPanel: React.FC = () => {
   useEffect(() => {
     if (metadataUpdate?.data?.metadataUpdate) {
       setStatus(statusToText(metadataUpdate.data.metadataUpdate.status));
+      setStatusDetail(statusToDetail(metadataUpdate.data.metadataUpdate));
       const newProgress = metadataUpdate.data.metadataUpdate.progress;
       if (newProgress < 0) {
         setProgress(-1);
@@ -181,6 +238,48 @@ export const SettingsTasksPanel: React.FC = () => {
     );
   }
 
+  function onOrganize() {
+    setIsOrganizeAlertOpen(false);
+    mutateMetadataOrganize({
+      template: organizeTemplate,
+      dryRun: organizeDryRun,
+    })
+      .then(() => {
+        jobStatus.refetch();
+      })
+      .catch((e) => Toast.error(e));
+  }
+
+  function renderOrganizeAlert() {
+    let msg;
+    if (organizeDryRun) {
+      msg = (
+        <p>
+          Dry Mode selected. No files will be moved, only logging where they
+          would be moved.
+        </p>
+      );
+    } else {
+      msg = (
+        <p>
+          Are you sure you want to organize the scene files? This will rename
+          and move the files of all scenes in the filesystem.
+        </p>
+      );
+    }
+
+    return (
+      <Modal
+        show={isOrganizeAlertOpen}
+        icon="folder"
+        accept={{ text: "Organize", variant: "danger", onClick: onOrganize }}
+        cancel={{ onClick: () => setIsOrganizeAlertOpen(false) }}
+      >
+        {msg}
+      </Modal>
+    );
+  }
+
   function renderImportDialog() {
     if (!isImportDialogOpen) {
       return;
@@ -189,6 +288,16 @@ export const SettingsTasksPanel: React.FC = () => {
     return <ImportDialog onClose={() => setIsImportDialogOpen(false)} />;
   }
 
+  function renderRemoteImportDialog() {
+    if (!isRemoteImportDialogOpen) {
+      return;
+    }
+
+    return (
+      <RemoteImportDialog onClose={() => setIsRemoteImportDialogOpen(false)} />
+    );
+  }
+
   function renderScanDialog() {
     if (!isScanDialogOpen) {
       return;
@@ -210,6 +319,7 @@ export const SettingsTasksPanel: React.FC = () => {
       await mutateMetadataScan({
         paths,
         useFileMetadata,
+        useSidecarMetadata,
         stripFileExtension,
         scanGeneratePreviews,
         scanGenerateImagePreviews,
@@ -271,7 +381,7 @@ export const SettingsTasksPanel: React.FC = () => {
           variant="danger"
           onClick={() => mutateStopJob().then(() => jobStatus.refetch())}
         >
-          Stop
+          Stop All
         </Button>
       </Form.Group>
     );
@@ -282,6 +392,11 @@ export const SettingsTasksPanel: React.FC = () => {
       <>
         <Form.Group>
           <h5>Status: {status}</h5>
+          {!!status && status !== "Idle" && statusDetail ? (
+            <div className="text-muted">{statusDetail}</div>
+          ) : (
+            ""
+          )}
           {!!status && status !== "Idle" ? (
             <ProgressBar
               animated
@@ -344,6 +459,47 @@ export const SettingsTasksPanel: React.FC = () => {
     }
   }
 
+  function downloadReport(report: object, filename: string) {
+    const blob = new Blob([JSON.stringify(report, null, 2)], {
+      type: "application/json",
+    });
+    const a = document.createElement("a");
+    a.href = URL.createObjectURL(blob);
+    a.download = filename;
+    a.click();
+    URL.revokeObjectURL(a.href);
+  }
+
+  async function onDownloadImportReport() {
+    try {
+      const ret = await queryLastImportReport();
+      const report = ret.data?.lastImportReport;
+      if (!report) {
+        Toast.success({ content: "No import has finished" });
+        return;
+      }
+
+      downloadReport(report, "import_report.json");
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onDownloadCleanReport() {
+    try {
+      const ret = await queryLastCleanReport();
+      const report = ret.data?.lastCleanReport;
+      if (!report) {
+        Toast.success({ content: "No clean has finished" });
+        return;
+      }
+
+      downloadReport(report, "clean_report.json");
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
   function renderPlugins() {
     if (!plugins.data || !plugins.data.plugins) {
       return;
@@ -374,7 +530,9 @@ export const SettingsTasksPanel: React.FC = () => {
     <>
       {renderImportAlert()}
       {renderCleanAlert()}
+      {renderOrganizeAlert()}
       {renderImportDialog()}
+      {renderRemoteImportDialog()}
       {renderScanDialog()}
       {renderAutoTagDialog()}
 
@@ -382,6 +540,10 @@ export const SettingsTasksPanel: React.FC = () => {
 
       {renderJobStatus()}
 
+      <JobQueue />
+
+      <LockedZipFiles />
+
       <hr />
 
       <h5>Library</h5>
@@ -389,9 +551,19 @@ export const SettingsTasksPanel: React.FC = () => {
         <Form.Check
           id="use-file-metadata"
           checked={useFileMetadata}
-          label="Set name, date, details from metadata (if present)"
+          label="Set name, date, details from metadata, and artists, album and genres from audio tags (if present)"
           onChange={() => setUseFileMetadata(!useFileMetadata)}
         />
+        <Form.Text className="text-muted">
+          Missing names, dates and details of existing scenes which are not
+          organized are also set from metadata.
+        </Form.Text>
+        <Form.Check
+          id="use-sidecar-metadata"
+          checked={useSidecarMetadata}
+          label="Set metadata of new scenes from .nfo/.json sidecar files (if present)"
+          onChange={() => setUseSidecarMetadata(!useSidecarMetadata)}
+        />
         <Form.Check
           id="strip-file-extension"
           checked={stripFileExtension}
@@ -524,6 +696,83 @@ export const SettingsTasksPanel: React.FC = () => {
           destructive action.
         </Form.Text>
       </Form.Group>
+      <Form.Group>
+        <Button
+          id="clean-report"
+          variant="secondary"
+          onClick={() => onDownloadCleanReport()}
+        >
+          Download clean report
+        </Button>
+        <Form.Text className="text-muted">
+          Downloads what the last finished clean removed, or what a dry run
+          would remove, with the reason for each object, as a JSON file.
+        </Form.Text>
+      </Form.Group>
+      <Form.Group>
+        <Form.Label htmlFor="organize-template">Organize template</Form.Label>
+        <Form.Control
+          id="organize-template"
+          className="col col-sm-6 text-input"
+          value={organizeTemplate}
+          onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+            setOrganizeTemplate(e.currentTarget.value)
+          }
+        />
+        <Form.Text className="text-muted">
+          Path of the scene files within their library path, without the file
+          extension. Use / to separate directories. Fields are {"{title}"},{" "}
+          {"{studio}"}, {"{date}"}, {"{yyyy}"}, {"{mm}"}, {"{dd}"},{" "}
+          {"{performer}"} and {"{performers}"}.
+        </Form.Text>
+        <Form.Check
+          id="organize-dryrun"
+          checked={organizeDryRun}
+          label="Only perform a dry run. Don't move any files"
+          onChange={() => setOrganizeDryRun(!organizeDryRun)}
+        />
+      </Form.Group>
+      <Form.Group>
+        <Button
+          id="organize"
+          variant="danger"
+          onClick={() => setIsOrganizeAlertOpen(true)}
+        >
+          Organize Files
+        </Button>
+        <Form.Text className="text-muted">
+          Rename and move scene files according to the template, keeping their
+          metadata. Files are numbered if their path is taken.
+        </Form.Text>
+      </Form.Group>
+      <Form.Group>
+        <Form.Check
+          id="check-full-decode"
+          checked={checkFullDecode}
+          label="Decode every frame. Much slower, but finds errors within frames"
+          onChange={() => setCheckFullDecode(!checkFullDecode)}
+        />
+      </Form.Group>
+      <Form.Group>
+        <Button
+          id="check-media"
+          variant="secondary"
+          type="submit"
+          onClick={() =>
+            mutateMetadataCheckMedia({ fullDecode: checkFullDecode })
+              .then(() => {
+                jobStatus.refetch();
+              })
+              .catch((e) => Toast.error(e))
+          }
+        >
+          Check Media
+        </Button>
+        <Form.Text className="text-muted">
+          Decode scene files to find corrupted files. Scenes which failed to
+          decode can be found with the Decode Error filter.
+        </Form.Text>
+      </Form.Group>
 
       <hr />
 
@@ -549,6 +798,40 @@ export const SettingsTasksPanel: React.FC = () => {
         </Form.Text>
       </Form.Group>
 
+      <Form.Group>
+        <Form.Check
+          id="nfo-images"
+          checked={nfoImages}
+          label="Include poster and fanart images"
+          onChange={() => setNfoImages(!nfoImages)}
+        />
+        <Form.Check
+          id="nfo-overwrite"
+          checked={nfoOverwrite}
+          label="Overwrite existing files"
+          onChange={() => setNfoOverwrite(!nfoOverwrite)}
+        />
+      </Form.Group>
+      <Form.Group>
+        <Button
+          id="export-nfo"
+          variant="secondary"
+          type="submit"
+          onClick={() =>
+            mutateExportNfo({ images: nfoImages, overwrite: nfoOverwrite })
+              .then(() => {
+                jobStatus.refetch();
+              })
+              .catch((e) => Toast.error(e))
+          }
+        >
+          Export NFO Files
+        </Button>
+        <Form.Text className="text-muted">
+          Writes Kodi/Jellyfin NFO files next to the scene files.
+        </Form.Text>
+      </Form.Group>
+
       <Form.Group>
         <Button
           id="import"
@@ -576,6 +859,34 @@ export const SettingsTasksPanel: React.FC = () => {
         </Form.Text>
       </Form.Group>
 
+      <Form.Group>
+        <Button
+          id="remote-import"
+          variant="danger"
+          onClick={() => setIsRemoteImportDialogOpen(true)}
+        >
+          Import from stash
+        </Button>
+        <Form.Text className="text-muted">
+          Incremental import of tags, performers and studios from another
+          stash instance.
+        </Form.Text>
+      </Form.Group>
+
+      <Form.Group>
+        <Button
+          id="import-report"
+          variant="secondary"
+          onClick={() => onDownloadImportReport()}
+        >
+          Download import report
+        </Button>
+        <Form.Text className="text-muted">
+          Downloads what the last finished import did to each object, as a
+          JSON file.
+        </Form.Text>
+      </Form.Group>
+
       <hr />
 
       <h5>Backup</h5>
@@ -609,6 +920,11 @@ export const SettingsTasksPanel: React.FC = () => {
         </Form.Text>
       </Form.Group>
 
+      <hr />
+
+      <h5>Scheduled Tasks</h5>
+      <ScheduledTasks />
+
       {renderPlugins()}
 
       <hr />
@@ -632,6 +948,47 @@ export const SettingsTasksPanel: React.FC = () => {
           generated files to the new hash format.
         </Form.Text>
       </Form.Group>
+
+      <Form.Group>
+        <Button
+          id="migrateBlobs"
+          variant="danger"
+          onClick={() =>
+            mutateMigrateBlobs().then(() => {
+              jobStatus.refetch();
+            })
+          }
+        >
+          Move images
+        </Button>
+        <Form.Text className="text-muted">
+          Used after changing the Image Storage to move the existing performer,
+          studio, tag and movie images to the new storage. The space used by
+          images moved to the filesystem is reclaimed when the database is
+          optimized.
+        </Form.Text>
+      </Form.Group>
+
+      <Form.Group>
+        <Button
+          id="uploadGeneratedFiles"
+          variant="secondary"
+          onClick={() =>
+            mutateUploadGeneratedFiles()
+              .then(() => {
+                jobStatus.refetch();
+              })
+              .catch((e) => Toast.error(e))
+          }
+        >
+          Upload generated files
+        </Button>
+        <Form.Text className="text-muted">
+          Uploads the generated files which are only stored locally to the
+          configured object storage. Local copies are removed unless they are
+          kept.
+        </Form.Text>
+      </Form.Group>
     </>
   );
 };
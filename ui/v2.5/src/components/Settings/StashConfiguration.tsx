@@ -1,8 +1,15 @@
 import React, { useState } from "react";
 import { Button, Form, Row, Col } from "react-bootstrap";
-import { Icon } from "src/components/Shared";
+import { CollapseButton, Icon } from "src/components/Shared";
 import * as GQL from "src/core/generated-graphql";
 import { FolderSelectDialog } from "../Shared/FolderSelect/FolderSelectDialog";
+import { ExclusionPatterns } from "./ExclusionPatterns";
+
+const optionValue = (value?: boolean | null) =>
+  value === undefined || value === null ? "" : String(value);
+
+const parseOption = (value: string) =>
+  value === "" ? null : value === "true";
 
 interface IStashProps {
   index: number;
@@ -23,12 +30,79 @@ const Stash: React.FC<IStashProps> = ({ index, stash, onSave, onDelete }) => {
 
   const classAdd = index % 2 === 1 ? "bg-dark" : "";
 
+  function renderOptionSelect(
+    id: string,
+    label: string,
+    value?: boolean | null
+  ) {
+    return (
+      <Form.Group>
+        <Form.Label htmlFor={`stash-${id}`}>{label}</Form.Label>
+        <Form.Control
+          as="select"
+          id={`stash-${id}`}
+          className="col-4 input-control"
+          value={optionValue(value)}
+          onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+            handleInput(id, parseOption(e.currentTarget.value))
+          }
+        >
+          <option value="">Use scan options</option>
+          <option value="true">Always</option>
+          <option value="false">Never</option>
+        </Form.Control>
+      </Form.Group>
+    );
+  }
+
+  function renderOptions() {
+    return (
+      <CollapseButton text="Options">
+        <Form.Group>
+          <Form.Check
+            id="stash-removable"
+            checked={stash.removable}
+            label="Removable or network storage"
+            onChange={() => handleInput("removable", !stash.removable)}
+          />
+          <Form.Text className="text-muted">
+            Files are not cleaned while the path is missing or empty.
+          </Form.Text>
+        </Form.Group>
+        {renderOptionSelect(
+          "generatePreviews",
+          "Generate previews during scan",
+          stash.generatePreviews
+        )}
+        {renderOptionSelect(
+          "generatePhashes",
+          "Generate phashes during scan",
+          stash.generatePhashes
+        )}
+        <Form.Group>
+          <h6>Excluded Video Patterns</h6>
+          <ExclusionPatterns
+            excludes={stash.excludes}
+            setExcludes={(v) => handleInput("excludes", v)}
+          />
+        </Form.Group>
+        <Form.Group>
+          <h6>Excluded Image/Gallery Patterns</h6>
+          <ExclusionPatterns
+            excludes={stash.imageExcludes}
+            setExcludes={(v) => handleInput("imageExcludes", v)}
+          />
+        </Form.Group>
+      </CollapseButton>
+    );
+  }
+
   return (
     <Row className={`align-items-center ${classAdd}`}>
       <Form.Label column xs={4}>
         {stash.path}
       </Form.Label>
-      <Col xs={3}>
+      <Col xs={2}>
         <Form.Check
           id="stash-exclude-video"
           checked={stash.excludeVideo}
@@ -36,13 +110,21 @@ const Stash: React.FC<IStashProps> = ({ index, stash, onSave, onDelete }) => {
         />
       </Col>
 
-      <Col xs={3}>
+      <Col xs={2}>
         <Form.Check
           id="stash-exclude-image"
           checked={stash.excludeImage}
           onChange={() => handleInput("excludeImage", !stash.excludeImage)}
         />
       </Col>
+
+      <Col xs={2}>
+        <Form.Check
+          id="stash-watch"
+          checked={stash.watch}
+          onChange={() => handleInput("watch", !stash.watch)}
+        />
+      </Col>
       <Col xs={2}>
         <Button
           size="sm"
@@ -53,6 +135,7 @@ const Stash: React.FC<IStashProps> = ({ index, stash, onSave, onDelete }) => {
           <Icon icon="minus" />
         </Button>
       </Col>
+      <Col xs={12}>{renderOptions()}</Col>
     </Row>
   );
 };
@@ -85,6 +168,10 @@ export const StashConfiguration: React.FC<IStashConfigurationProps> = ({
         path: folder,
         excludeImage: false,
         excludeVideo: false,
+        watch: false,
+        excludes: [],
+        imageExcludes: [],
+        removable: false,
       },
     ]);
   };
@@ -104,8 +191,9 @@ export const StashConfiguration: React.FC<IStashConfigurationProps> = ({
         {stashes.length > 0 && (
           <Row>
             <h6 className="col-4">Path</h6>
-            <h6 className="col-3">Exclude Video</h6>
-            <h6 className="col-3">Exclude Image</h6>
+            <h6 className="col-2">Exclude Video</h6>
+            <h6 className="col-2">Exclude Image</h6>
+            <h6 className="col-2">Watch</h6>
           </Row>
         )}
         {stashes.map((stash, index) => (
@@ -4,7 +4,11 @@ import { mutateExportObjects } from "src/core/StashService";
 import { Modal } from "src/components/Shared";
 import { useToast } from "src/hooks";
 import { downloadFile } from "src/utils";
-import { ExportObjectsInput } from "src/core/generated-graphql";
+import {
+  ExportFormat,
+  ExportImageMode,
+  ExportObjectsInput,
+} from "src/core/generated-graphql";
 
 interface IExportDialogProps {
   exportInput: ExportObjectsInput;
@@ -15,6 +19,17 @@ export const ExportDialog: React.FC<IExportDialogProps> = (
   props: IExportDialogProps
 ) => {
   const [includeDependencies, setIncludeDependencies] = useState(true);
+  const [format, setFormat] = useState<ExportFormat>(ExportFormat.Files);
+  const [stream, setStream] = useState(false);
+  const [imageMode, setImageMode] = useState<ExportImageMode>(
+    ExportImageMode.Inline
+  );
+
+  // images can only be exported as files in zip exports
+  const effectiveImageMode =
+    format !== ExportFormat.Files && imageMode === ExportImageMode.Files
+      ? ExportImageMode.Inline
+      : imageMode;
 
   // Network state
   const [isRunning, setIsRunning] = useState(false);
@@ -27,6 +42,9 @@ export const ExportDialog: React.FC<IExportDialogProps> = (
       const ret = await mutateExportObjects({
         ...props.exportInput,
         includeDependencies,
+        format,
+        stream,
+        imageMode: effectiveImageMode,
       });
 
       // download the result
@@ -64,6 +82,50 @@ export const ExportDialog: React.FC<IExportDialogProps> = (
             onChange={() => setIncludeDependencies(!includeDependencies)}
           />
         </Form.Group>
+        <Form.Group id="export-format">
+          <h6>Format</h6>
+          <Form.Control
+            as="select"
+            className="w-auto input-control"
+            value={format}
+            onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+              setFormat(e.currentTarget.value as ExportFormat)
+            }
+          >
+            <option value={ExportFormat.Files}>Zip of JSON files</option>
+            <option value={ExportFormat.JsonLines}>JSON lines file</option>
+            <option value={ExportFormat.JsonLinesGzip}>
+              Gzipped JSON lines file
+            </option>
+          </Form.Control>
+        </Form.Group>
+        <Form.Group id="export-image-mode">
+          <h6>Performer, studio and movie images</h6>
+          <Form.Control
+            as="select"
+            className="w-auto input-control"
+            value={effectiveImageMode}
+            onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+              setImageMode(e.currentTarget.value as ExportImageMode)
+            }
+          >
+            <option value={ExportImageMode.Inline}>
+              Include in exported objects
+            </option>
+            {format === ExportFormat.Files && (
+              <option value={ExportImageMode.Files}>Separate files</option>
+            )}
+            <option value={ExportImageMode.None}>Do not export</option>
+          </Form.Control>
+        </Form.Group>
+        <Form.Group>
+          <Form.Check
+            id="stream"
+            checked={stream}
+            label="Generate the export while it is downloaded"
+            onChange={() => setStream(!stream)}
+          />
+        </Form.Group>
       </Form>
     </Modal>
   );
@@ -0,0 +1,66 @@
+import React from "react";
+import { Form } from "react-bootstrap";
+
+export interface IGenerateOverwrite {
+  sprites: boolean;
+  previews: boolean;
+  markers: boolean;
+  transcodes: boolean;
+  phashes: boolean;
+  interactiveHeatmapsSpeeds: boolean;
+}
+
+export const noGenerateOverwrite: IGenerateOverwrite = {
+  sprites: false,
+  previews: false,
+  markers: false,
+  transcodes: false,
+  phashes: false,
+  interactiveHeatmapsSpeeds: false,
+};
+
+interface IGenerateOverwriteOptionsProps {
+  id: string;
+  // the types of file being generated
+  generate: IGenerateOverwrite;
+  overwrite: IGenerateOverwrite;
+  onChange: (overwrite: IGenerateOverwrite) => void;
+}
+
+const types: { key: keyof IGenerateOverwrite; label: string }[] = [
+  { key: "previews", label: "Previews" },
+  { key: "sprites", label: "Sprites" },
+  { key: "markers", label: "Markers" },
+  { key: "transcodes", label: "Transcodes" },
+  { key: "phashes", label: "Phashes" },
+  { key: "interactiveHeatmapsSpeeds", label: "Heatmaps" },
+];
+
+export const GenerateOverwriteOptions: React.FC<IGenerateOverwriteOptionsProps> = (
+  props: IGenerateOverwriteOptionsProps
+) => {
+  const { id, generate, overwrite, onChange } = props;
+
+  return (
+    <Form.Group>
+      <h6>Overwrite existing</h6>
+      {types.map((t) => (
+        <Form.Check
+          inline
+          key={t.key}
+          id={`${id}-${t.key}`}
+          checked={generate[t.key] && overwrite[t.key]}
+          disabled={!generate[t.key]}
+          label={t.label}
+          onChange={() =>
+            onChange({ ...overwrite, [t.key]: !overwrite[t.key] })
+          }
+        />
+      ))}
+      <Form.Text className="text-muted">
+        Regenerates the selected types of file even if they exist, such as after
+        changing the preview settings.
+      </Form.Text>
+    </Form.Group>
+  );
+};
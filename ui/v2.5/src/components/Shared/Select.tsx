@@ -7,6 +7,7 @@ import * as GQL from "src/core/generated-graphql";
 import {
   useAllTagsForFilter,
   useAllMoviesForFilter,
+  useAllGroups,
   useAllStudiosForFilter,
   useAllPerformersForFilter,
   useMarkerStrings,
@@ -21,7 +22,8 @@ export type ValidTypes =
   | GQL.SlimPerformerDataFragment
   | GQL.Tag
   | GQL.SlimStudioDataFragment
-  | GQL.SlimMovieDataFragment;
+  | GQL.SlimMovieDataFragment
+  | GQL.SlimGroupDataFragment;
 type Option = { value: string; label: string };
 
 interface ITypeProps {
@@ -32,7 +34,8 @@ interface ITypeProps {
     | "tags"
     | "sceneTags"
     | "performerTags"
-    | "movies";
+    | "movies"
+    | "groups";
 }
 interface IFilterProps {
   ids?: string[];
@@ -453,6 +456,22 @@ export const MovieSelect: React.FC<IFilterProps> = (props) => {
   );
 };
 
+export const GroupSelect: React.FC<IFilterProps> = (props) => {
+  const { data, loading } = useAllGroups();
+  const items = data?.allGroups ?? [];
+
+  return (
+    <FilterSelectComponent
+      {...props}
+      isMulti={props.isMulti ?? false}
+      type="groups"
+      isLoading={loading}
+      items={items}
+      placeholder={props.noSelectionString ?? "Select group..."}
+    />
+  );
+};
+
 export const TagSelect: React.FC<IFilterProps> = (props) => {
   const { data, loading } = useAllTagsForFilter();
   const [createTag] = useTagCreate({ name: "" });
@@ -489,6 +508,8 @@ export const FilterSelect: React.FC<IFilterProps & ITypeProps> = (props) =>
     <StudioSelect {...props} creatable={false} />
   ) : props.type === "movies" ? (
     <MovieSelect {...props} creatable={false} />
+  ) : props.type === "groups" ? (
+    <GroupSelect {...props} creatable={false} />
   ) : (
     <TagSelect {...props} creatable={false} />
   );
@@ -16,4 +16,5 @@ export { default as TruncatedText } from "./TruncatedText";
 export { BasicCard } from "./BasicCard";
 export { RatingStars } from "./RatingStars";
 export { ExportDialog } from "./ExportDialog";
+export * from "./GenerateOverwriteOptions";
 export { default as DeleteEntityDialog } from "./DeleteEntityDialog";
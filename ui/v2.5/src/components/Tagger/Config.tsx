@@ -191,7 +191,7 @@ const Config: React.FC<IConfigProps> = ({ show, config, setConfig }) => {
                 {!stashBoxes.length && <option>No instances found</option>}
                 {stashConfig.data?.configuration.general.stashBoxes.map((i) => (
                   <option value={i.endpoint} key={i.endpoint}>
-                    {i.endpoint}
+                    {i.name || i.endpoint}
                   </option>
                 ))}
               </Form.Control>
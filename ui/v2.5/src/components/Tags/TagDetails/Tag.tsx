@@ -43,6 +43,7 @@ export const Tag: React.FC = () => {
   // Editing tag state
   const [image, setImage] = useState<string | null>();
   const [name, setName] = useState<string>();
+  const [autoTagRegex, setAutoTagRegex] = useState<string>();
 
   // Tag state
   const [tag, setTag] = useState<GQL.TagDataFragment | undefined>();
@@ -88,6 +89,7 @@ export const Tag: React.FC = () => {
 
   function updateTagEditState(state: GQL.TagDataFragment) {
     setName(state.name);
+    setAutoTagRegex(state.auto_tag_regex ?? undefined);
   }
 
   function updateTagData(tagData: GQL.TagDataFragment) {
@@ -123,11 +125,13 @@ export const Tag: React.FC = () => {
       return {
         id,
         name,
+        auto_tag_regex: autoTagRegex,
         image,
       };
     }
     return {
       name,
+      auto_tag_regex: autoTagRegex,
       image,
     };
   }
@@ -232,6 +236,12 @@ export const Tag: React.FC = () => {
               isEditing: !!isEditing,
               onChange: setName,
             })}
+            {TableUtils.renderInputGroup({
+              title: "Auto Tag Regex",
+              value: autoTagRegex ?? "",
+              isEditing: !!isEditing,
+              onChange: setAutoTagRegex,
+            })}
           </tbody>
         </Table>
         <DetailsEditNavbar
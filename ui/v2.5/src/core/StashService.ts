@@ -203,6 +203,9 @@ export const useFindPerformer = (id: string) => {
   const skip = id === "new";
   return GQL.useFindPerformerQuery({ variables: { id }, skip });
 };
+export const useFindRelatedPerformers = (
+  variables: GQL.FindRelatedPerformersQueryVariables
+) => GQL.useFindRelatedPerformersQuery({ variables });
 export const useFindStudio = (id: string) => {
   const skip = id === "new";
   return GQL.useFindStudioQuery({ variables: { id }, skip });
@@ -351,6 +354,21 @@ export const usePerformersDestroy = (
     update: deleteCache(performerMutationImpactedQueries),
   });
 
+const performerRelationshipMutationImpactedQueries = [
+  GQL.FindPerformerDocument,
+  GQL.FindRelatedPerformersDocument,
+  GQL.FindScenesDocument,
+];
+
+export const usePerformerRelationshipAdd = () =>
+  GQL.usePerformerRelationshipAddMutation({
+    update: deleteCache(performerRelationshipMutationImpactedQueries),
+  });
+export const usePerformerRelationshipRemove = () =>
+  GQL.usePerformerRelationshipRemoveMutation({
+    update: deleteCache(performerRelationshipMutationImpactedQueries),
+  });
+
 const sceneMutationImpactedQueries = [
   GQL.FindPerformerDocument,
   GQL.FindPerformersDocument,
@@ -449,6 +467,22 @@ export const useSceneGenerateScreenshot = () =>
     update: deleteCache([GQL.FindScenesDocument]),
   });
 
+export const useSceneSetCoverFromFrame = () =>
+  GQL.useSceneSetCoverFromFrameMutation({
+    update: deleteCache([GQL.FindScenesDocument]),
+  });
+
+export const useCastDevices = () =>
+  GQL.useCastDevicesQuery({
+    fetchPolicy: "no-cache",
+  });
+
+export const mutateCastScene = (input: GQL.CastSceneInput) =>
+  client.mutate<GQL.CastSceneMutation>({
+    mutation: GQL.CastSceneDocument,
+    variables: { input },
+  });
+
 const imageMutationImpactedQueries = [
   GQL.FindPerformerDocument,
   GQL.FindPerformersDocument,
@@ -681,6 +715,82 @@ export const useTagsDestroy = (input: GQL.TagsDestroyMutationVariables) =>
     update: deleteCache(tagMutationImpactedQueries),
   });
 
+export const useAllScheduledTasks = () => GQL.useAllScheduledTasksQuery();
+
+export const useScheduledTaskCreate = () =>
+  GQL.useScheduledTaskCreateMutation({
+    refetchQueries: getQueryNames([GQL.AllScheduledTasksDocument]),
+  });
+export const useScheduledTaskUpdate = () =>
+  GQL.useScheduledTaskUpdateMutation({
+    refetchQueries: getQueryNames([GQL.AllScheduledTasksDocument]),
+  });
+export const useScheduledTaskDestroy = () =>
+  GQL.useScheduledTaskDestroyMutation({
+    refetchQueries: getQueryNames([GQL.AllScheduledTasksDocument]),
+  });
+
+export const useAllPlaylists = () => GQL.useAllPlaylistsQuery();
+export const useFindPlaylist = (id: string) =>
+  GQL.useFindPlaylistQuery({ variables: { id } });
+
+export const queryPlaylistNext = (input: GQL.PlaylistNextInput) =>
+  client.query<GQL.PlaylistNextQuery>({
+    query: GQL.PlaylistNextDocument,
+    variables: { input },
+    fetchPolicy: "no-cache",
+  });
+
+const playlistMutationImpactedQueries = [
+  GQL.AllPlaylistsDocument,
+  GQL.FindPlaylistDocument,
+];
+
+export const usePlaylistCreate = () =>
+  GQL.usePlaylistCreateMutation({
+    refetchQueries: getQueryNames([GQL.AllPlaylistsDocument]),
+  });
+export const usePlaylistUpdate = () =>
+  GQL.usePlaylistUpdateMutation({
+    update: deleteCache(playlistMutationImpactedQueries),
+  });
+export const usePlaylistAddScenes = () =>
+  GQL.usePlaylistAddScenesMutation({
+    update: deleteCache(playlistMutationImpactedQueries),
+  });
+export const usePlaylistDestroy = () =>
+  GQL.usePlaylistDestroyMutation({
+    update: deleteCache(playlistMutationImpactedQueries),
+  });
+
+export const useAllGroups = () => GQL.useAllGroupsQuery();
+export const useFindGroup = (id: string) =>
+  GQL.useFindGroupQuery({ variables: { id } });
+
+const groupMutationImpactedQueries = [
+  GQL.AllGroupsDocument,
+  GQL.FindGroupDocument,
+  GQL.FindSceneDocument,
+  GQL.FindScenesDocument,
+];
+
+export const useGroupCreate = () =>
+  GQL.useGroupCreateMutation({
+    refetchQueries: getQueryNames([GQL.AllGroupsDocument]),
+  });
+export const useGroupUpdate = () =>
+  GQL.useGroupUpdateMutation({
+    update: deleteCache(groupMutationImpactedQueries),
+  });
+export const useGroupAddScenes = () =>
+  GQL.useGroupAddScenesMutation({
+    update: deleteCache(groupMutationImpactedQueries),
+  });
+export const useGroupDestroy = () =>
+  GQL.useGroupDestroyMutation({
+    update: deleteCache(groupMutationImpactedQueries),
+  });
+
 export const useConfigureGeneral = (input: GQL.ConfigGeneralInput) =>
   GQL.useConfigureGeneralMutation({
     variables: { input },
@@ -711,6 +821,18 @@ export const querySystemStatus = () =>
     fetchPolicy: "no-cache",
   });
 
+export const queryLastImportReport = () =>
+  client.query<GQL.LastImportReportQuery>({
+    query: GQL.LastImportReportDocument,
+    fetchPolicy: "no-cache",
+  });
+
+export const queryLastCleanReport = () =>
+  client.query<GQL.LastCleanReportQuery>({
+    query: GQL.LastCleanReportDocument,
+    fetchPolicy: "no-cache",
+  });
+
 export const useSystemStatus = () =>
   GQL.useSystemStatusQuery({
     fetchPolicy: "no-cache",
@@ -726,9 +848,23 @@ export const useJobStatus = () =>
     fetchPolicy: "no-cache",
   });
 
-export const mutateStopJob = () =>
+export const mutateStopJob = (jobId?: string) =>
   client.mutate<GQL.StopJobMutation>({
     mutation: GQL.StopJobDocument,
+    variables: { job_id: jobId },
+  });
+
+export const useJobQueue = () =>
+  GQL.useJobQueueQuery({
+    fetchPolicy: "no-cache",
+  });
+
+export const useJobsUpdate = () => GQL.useJobsUpdateSubscription();
+
+export const useJobHistory = (limit?: number) =>
+  GQL.useJobHistoryQuery({
+    variables: { limit },
+    fetchPolicy: "no-cache",
   });
 
 export const queryScrapeFreeones = (performerName: string) =>
@@ -850,6 +986,55 @@ export const mutateReloadScrapers = () =>
     ],
   });
 
+export const mutateValidateScrapers = () =>
+  client.mutate<GQL.ValidateScrapersMutation>({
+    mutation: GQL.ValidateScrapersDocument,
+    refetchQueries: [
+      GQL.refetchListMovieScrapersQuery(),
+      GQL.refetchListPerformerScrapersQuery(),
+      GQL.refetchListSceneScrapersQuery(),
+      GQL.refetchListGalleryScrapersQuery(),
+    ],
+  });
+
+export const useInstalledScraperPackages = (checkUpdates: boolean) =>
+  GQL.useInstalledScraperPackagesQuery({
+    variables: { check_updates: checkUpdates },
+  });
+
+export const useAvailableScraperPackages = (sourceURL: string) =>
+  GQL.useAvailableScraperPackagesQuery({
+    variables: { source_url: sourceURL },
+    skip: sourceURL === "",
+  });
+
+export const mutateInstallScraperPackages = (
+  input: GQL.InstallScraperPackagesInput
+) =>
+  client.mutate<GQL.InstallScraperPackagesMutation>({
+    mutation: GQL.InstallScraperPackagesDocument,
+    variables: { input },
+  });
+
+export const mutateUpdateScraperPackages = (ids?: string[]) =>
+  client.mutate<GQL.UpdateScraperPackagesMutation>({
+    mutation: GQL.UpdateScraperPackagesDocument,
+    variables: { ids },
+  });
+
+export const mutateUninstallScraperPackages = (ids: string[]) =>
+  client.mutate<GQL.UninstallScraperPackagesMutation>({
+    mutation: GQL.UninstallScraperPackagesDocument,
+    variables: { ids },
+    refetchQueries: [
+      GQL.refetchInstalledScraperPackagesQuery({ check_updates: false }),
+      GQL.refetchListMovieScrapersQuery(),
+      GQL.refetchListPerformerScrapersQuery(),
+      GQL.refetchListSceneScrapersQuery(),
+      GQL.refetchListGalleryScrapersQuery(),
+    ],
+  });
+
 export const mutateReloadPlugins = () =>
   client.mutate<GQL.ReloadPluginsMutation>({
     mutation: GQL.ReloadPluginsDocument,
@@ -866,6 +1051,26 @@ export const mutateRunPluginTask = (
     variables: { plugin_id: pluginId, task_name: taskName, args },
   });
 
+export const mutateSubmitStashBoxSceneDraft = (
+  input: GQL.StashBoxDraftSubmissionInput
+) =>
+  client.mutate<GQL.SubmitStashBoxSceneDraftMutation>({
+    mutation: GQL.SubmitStashBoxSceneDraftDocument,
+    variables: { input },
+  });
+
+export const mutateSubmitStashBoxPerformerDraft = (
+  input: GQL.StashBoxDraftSubmissionInput
+) =>
+  client.mutate<GQL.SubmitStashBoxPerformerDraftMutation>({
+    mutation: GQL.SubmitStashBoxPerformerDraftDocument,
+    variables: { input },
+  });
+
+// stashBoxDraftURL returns the page of the draft on the stash-box site.
+export const stashBoxDraftURL = (endpoint: string, draftID: string) =>
+  `${endpoint.replace(/\/graphql\/?$/, "")}/drafts/${draftID}`;
+
 export const mutateMetadataScan = (input: GQL.ScanMetadataInput) =>
   client.mutate<GQL.MetadataScanMutation>({
     mutation: GQL.MetadataScanDocument,
@@ -890,11 +1095,33 @@ export const mutateMetadataClean = (input: GQL.CleanMetadataInput) =>
     variables: { input },
   });
 
+export const mutateMetadataCheckMedia = (input: GQL.CheckMediaInput) =>
+  client.mutate<GQL.MetadataCheckMediaMutation>({
+    mutation: GQL.MetadataCheckMediaDocument,
+    variables: { input },
+  });
+
+export const mutateMetadataOrganize = (input: GQL.OrganizeFilesInput) =>
+  client.mutate<GQL.MetadataOrganizeMutation>({
+    mutation: GQL.MetadataOrganizeDocument,
+    variables: { input },
+  });
+
 export const mutateMigrateHashNaming = () =>
   client.mutate<GQL.MigrateHashNamingMutation>({
     mutation: GQL.MigrateHashNamingDocument,
   });
 
+export const mutateMigrateBlobs = () =>
+  client.mutate<GQL.MigrateBlobsMutation>({
+    mutation: GQL.MigrateBlobsDocument,
+  });
+
+export const mutateUploadGeneratedFiles = () =>
+  client.mutate<GQL.UploadGeneratedFilesMutation>({
+    mutation: GQL.UploadGeneratedFilesDocument,
+  });
+
 export const mutateMetadataExport = () =>
   client.mutate<GQL.MetadataExportMutation>({
     mutation: GQL.MetadataExportDocument,
@@ -917,6 +1144,35 @@ export const mutateImportObjects = (input: GQL.ImportObjectsInput) =>
     variables: { input },
   });
 
+export const mutateImportObjectsDryRun = (input: GQL.ImportObjectsInput) =>
+  client.mutate<GQL.ImportObjectsDryRunMutation>({
+    mutation: GQL.ImportObjectsDryRunDocument,
+    variables: { input },
+  });
+
+export const mutateExportNfo = (input: GQL.ExportNfoInput) =>
+  client.mutate<GQL.ExportNfoMutation>({
+    mutation: GQL.ExportNfoDocument,
+    variables: { input },
+  });
+
+export const mutateImportFromStash = (input: GQL.RemoteImportInput) =>
+  client.mutate<GQL.ImportFromStashMutation>({
+    mutation: GQL.ImportFromStashDocument,
+    variables: { input },
+  });
+
+export const useLockedZipFiles = () =>
+  GQL.useLockedZipFilesQuery({
+    fetchPolicy: "no-cache",
+  });
+
+export const mutateSetZipPassword = (path: string, password: string) =>
+  client.mutate<GQL.SetZipPasswordMutation>({
+    mutation: GQL.SetZipPasswordDocument,
+    variables: { path, password },
+  });
+
 export const mutateBackupDatabase = (input: GQL.BackupDatabaseInput) =>
   client.mutate<GQL.BackupDatabaseMutation>({
     mutation: GQL.BackupDatabaseDocument,
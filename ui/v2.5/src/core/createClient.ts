@@ -70,8 +70,12 @@ const typePolicies: TypePolicies = {
   },
 };
 
+// returns the path under which stash is served, with a trailing slash
+export const getBasePath = () =>
+  document.querySelector("base")?.getAttribute("href") ?? "/";
+
 export const getPlatformURL = (ws?: boolean) => {
-  const platformUrl = new URL(window.location.origin);
+  const platformUrl = new URL(getBasePath(), window.location.origin);
 
   if (!process.env.NODE_ENV || process.env.NODE_ENV === "development") {
     platformUrl.port = "9999"; // TODO: Hack. Development expects port 9999
@@ -114,7 +118,10 @@ export const createClient = () => {
     // handle unauthorized error by redirecting to the login page
     if (networkError && (networkError as ServerError).statusCode === 401) {
       // redirect to login page
-      const newURL = new URL("/login", window.location.toString());
+      const newURL = new URL(
+        `${getBasePath()}login`,
+        window.location.toString()
+      );
       newURL.searchParams.append("returnURL", window.location.href);
       window.location.href = newURL.toString();
     }
@@ -4,14 +4,14 @@ import ReactDOM from "react-dom";
 import { BrowserRouter } from "react-router-dom";
 import { App } from "./App";
 import { getClient } from "./core/StashService";
-import { getPlatformURL } from "./core/createClient";
+import { getBasePath, getPlatformURL } from "./core/createClient";
 import "./index.scss";
 import * as serviceWorker from "./serviceWorker";
 
 ReactDOM.render(
   <>
     <link rel="stylesheet" type="text/css" href={`${getPlatformURL()}css`} />
-    <BrowserRouter>
+    <BrowserRouter basename={getBasePath()}>
       <ApolloProvider client={getClient()}>
         <App />
       </ApolloProvider>
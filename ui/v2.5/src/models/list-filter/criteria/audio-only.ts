@@ -0,0 +1,16 @@
+import { CriterionModifier } from "src/core/generated-graphql";
+import { Criterion, CriterionType, ICriterionOption } from "./criterion";
+
+export class AudioOnlyCriterion extends Criterion {
+  public type: CriterionType = "audio_only";
+  public parameterName: string = "audio_only";
+  public modifier = CriterionModifier.Equals;
+  public modifierOptions = [];
+  public options: string[] = [true.toString(), false.toString()];
+  public value: string = "";
+}
+
+export class AudioOnlyCriterionOption implements ICriterionOption {
+  public label: string = Criterion.getLabel("audio_only");
+  public value: CriterionType = "audio_only";
+}
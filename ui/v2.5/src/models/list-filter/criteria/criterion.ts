@@ -9,6 +9,9 @@ export type CriterionType =
   | "path"
   | "rating"
   | "organized"
+  | "audio_only"
+  | "interactive"
+  | "interactive_speed"
   | "o_counter"
   | "resolution"
   | "average_resolution"
@@ -29,6 +32,7 @@ export type CriterionType =
   | "performers"
   | "studios"
   | "movies"
+  | "groups"
   | "galleries"
   | "birth_year"
   | "age"
@@ -53,6 +57,9 @@ export type CriterionType =
   | "performer_count"
   | "death_year"
   | "url"
+  | "decode_error"
+  | "camera_make"
+  | "camera_model"
   | "stash_id";
 
 type Option = string | number | IOptionType;
@@ -69,6 +76,12 @@ export abstract class Criterion {
         return "Rating";
       case "organized":
         return "Organized";
+      case "audio_only":
+        return "Audio Only";
+      case "interactive":
+        return "Interactive";
+      case "interactive_speed":
+        return "Interactive Speed";
       case "o_counter":
         return "O-Counter";
       case "resolution":
@@ -103,6 +116,8 @@ export abstract class Criterion {
         return "Studios";
       case "movies":
         return "Movies";
+      case "groups":
+        return "Groups";
       case "galleries":
         return "Galleries";
       case "birth_year":
@@ -151,6 +166,12 @@ export abstract class Criterion {
         return "Performer Count";
       case "url":
         return "URL";
+      case "decode_error":
+        return "Decode Error";
+      case "camera_make":
+        return "Camera Make";
+      case "camera_model":
+        return "Camera Model";
       case "stash_id":
         return "StashID";
     }
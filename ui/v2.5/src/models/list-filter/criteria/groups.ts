@@ -0,0 +1,32 @@
+import { CriterionModifier } from "src/core/generated-graphql";
+import { ILabeledId, encodeILabeledId } from "../types";
+import { Criterion, CriterionType, ICriterionOption } from "./criterion";
+
+interface IOptionType {
+  id: string;
+  name?: string;
+}
+
+export class GroupsCriterion extends Criterion {
+  public type: CriterionType = "groups";
+  public parameterName: string = "groups";
+  public modifier = CriterionModifier.Includes;
+  public modifierOptions = [
+    Criterion.getModifierOption(CriterionModifier.Includes),
+    Criterion.getModifierOption(CriterionModifier.IncludesAll),
+    Criterion.getModifierOption(CriterionModifier.Excludes),
+  ];
+  public options: IOptionType[] = [];
+  public value: ILabeledId[] = [];
+
+  public encodeValue() {
+    return this.value.map((o) => {
+      return encodeILabeledId(o);
+    });
+  }
+}
+
+export class GroupsCriterionOption implements ICriterionOption {
+  public label: string = Criterion.getLabel("groups");
+  public value: CriterionType = "groups";
+}
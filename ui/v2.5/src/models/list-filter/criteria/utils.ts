@@ -8,6 +8,8 @@ import {
   MandatoryNumberCriterion,
 } from "./criterion";
 import { OrganizedCriterion } from "./organized";
+import { AudioOnlyCriterion } from "./audio-only";
+import { InteractiveCriterion } from "./interactive";
 import { FavoriteCriterion } from "./favorite";
 import { HasMarkersCriterion } from "./has-markers";
 import {
@@ -27,6 +29,7 @@ import { StudiosCriterion, ParentStudiosCriterion } from "./studios";
 import { TagsCriterion } from "./tags";
 import { GenderCriterion } from "./gender";
 import { MoviesCriterion } from "./movies";
+import { GroupsCriterion } from "./groups";
 import { GalleriesCriterion } from "./galleries";
 
 export function makeCriteria(type: CriterionType = "none") {
@@ -39,6 +42,10 @@ export function makeCriteria(type: CriterionType = "none") {
       return new RatingCriterion();
     case "organized":
       return new OrganizedCriterion();
+    case "audio_only":
+      return new AudioOnlyCriterion();
+    case "interactive":
+      return new InteractiveCriterion();
     case "o_counter":
     case "scene_count":
     case "marker_count":
@@ -85,11 +92,14 @@ export function makeCriteria(type: CriterionType = "none") {
       return new ParentStudiosCriterion();
     case "movies":
       return new MoviesCriterion();
+    case "groups":
+      return new GroupsCriterion();
     case "galleries":
       return new GalleriesCriterion();
     case "birth_year":
     case "death_year":
     case "weight":
+    case "interactive_speed":
       return new NumberCriterion(type, type);
     case "age":
       return new MandatoryNumberCriterion(type, type);
@@ -107,6 +117,9 @@ export function makeCriteria(type: CriterionType = "none") {
     case "piercings":
     case "aliases":
     case "url":
+    case "decode_error":
+    case "camera_make":
+    case "camera_model":
     case "stash_id":
       return new StringCriterion(type, type);
   }
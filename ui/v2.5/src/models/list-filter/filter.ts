@@ -31,6 +31,14 @@ import {
   OrganizedCriterion,
   OrganizedCriterionOption,
 } from "./criteria/organized";
+import {
+  AudioOnlyCriterion,
+  AudioOnlyCriterionOption,
+} from "./criteria/audio-only";
+import {
+  InteractiveCriterion,
+  InteractiveCriterionOption,
+} from "./criteria/interactive";
 import {
   HasMarkersCriterion,
   HasMarkersCriterionOption,
@@ -73,6 +81,7 @@ import { makeCriteria } from "./criteria/utils";
 import { DisplayMode, FilterMode } from "./types";
 import { GenderCriterionOption, GenderCriterion } from "./criteria/gender";
 import { MoviesCriterionOption, MoviesCriterion } from "./criteria/movies";
+import { GroupsCriterionOption, GroupsCriterion } from "./criteria/groups";
 import { GalleriesCriterion } from "./criteria/galleries";
 
 interface IQueryParameters {
@@ -136,6 +145,7 @@ export class ListFilterModel {
           "performer_count",
           "random",
           "movie_scene_number",
+          "interactive_speed",
         ];
         this.displayModeOptions = [
           DisplayMode.Grid,
@@ -160,8 +170,13 @@ export class ListFilterModel {
           ListFilterModel.createCriterionOption("performer_count"),
           new StudiosCriterionOption(),
           new MoviesCriterionOption(),
+          new GroupsCriterionOption(),
           ListFilterModel.createCriterionOption("url"),
           ListFilterModel.createCriterionOption("stash_id"),
+          ListFilterModel.createCriterionOption("decode_error"),
+          new AudioOnlyCriterionOption(),
+          new InteractiveCriterionOption(),
+          ListFilterModel.createCriterionOption("interactive_speed"),
         ];
         break;
       case FilterMode.Images:
@@ -173,6 +188,7 @@ export class ListFilterModel {
           "o_counter",
           "filesize",
           "file_mod_time",
+          "taken_at",
           "tag_count",
           "performer_count",
           "random",
@@ -192,6 +208,8 @@ export class ListFilterModel {
           new PerformersCriterionOption(),
           ListFilterModel.createCriterionOption("performer_count"),
           new StudiosCriterionOption(),
+          ListFilterModel.createCriterionOption("camera_make"),
+          ListFilterModel.createCriterionOption("camera_model"),
         ];
         break;
       case FilterMode.Performers: {
@@ -531,6 +549,24 @@ export class ListFilterModel {
           result.organized = (criterion as OrganizedCriterion).value === "true";
           break;
         }
+        case "audio_only": {
+          result.audio_only =
+            (criterion as AudioOnlyCriterion).value === "true";
+          break;
+        }
+        case "interactive": {
+          result.interactive =
+            (criterion as InteractiveCriterion).value === "true";
+          break;
+        }
+        case "interactive_speed": {
+          const speedCrit = criterion as NumberCriterion;
+          result.interactive_speed = {
+            value: speedCrit.value,
+            modifier: speedCrit.modifier,
+          };
+          break;
+        }
         case "o_counter": {
           const oCounterCrit = criterion as NumberCriterion;
           result.o_counter = {
@@ -654,6 +690,14 @@ export class ListFilterModel {
           };
           break;
         }
+        case "groups": {
+          const groupCrit = criterion as GroupsCriterion;
+          result.groups = {
+            value: groupCrit.value.map((group) => group.id),
+            modifier: groupCrit.modifier,
+          };
+          break;
+        }
         case "url": {
           const urlCrit = criterion as StringCriterion;
           result.url = {
@@ -670,6 +714,14 @@ export class ListFilterModel {
           };
           break;
         }
+        case "decode_error": {
+          const decodeErrorCrit = criterion as StringCriterion;
+          result.decode_error = {
+            value: decodeErrorCrit.value,
+            modifier: decodeErrorCrit.modifier,
+          };
+          break;
+        }
         // no default
       }
     });
@@ -1032,6 +1084,22 @@ export class ListFilterModel {
           };
           break;
         }
+        case "camera_make": {
+          const cameraCrit = criterion as StringCriterion;
+          result.camera_make = {
+            value: cameraCrit.value,
+            modifier: cameraCrit.modifier,
+          };
+          break;
+        }
+        case "camera_model": {
+          const cameraCrit = criterion as StringCriterion;
+          result.camera_model = {
+            value: cameraCrit.value,
+            modifier: cameraCrit.modifier,
+          };
+          break;
+        }
         // no default
       }
     });